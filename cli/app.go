@@ -9,6 +9,9 @@ import (
 	"strings"
 
 	"github.com/urfave/cli/v2"
+
+	"go.viam.com/rdk/referenceframe/urdf"
+	"go.viam.com/rdk/robot"
 )
 
 // CLI flags.
@@ -132,10 +135,16 @@ const (
 
 	cpFlagRecursive = "recursive"
 	cpFlagPreserve  = "preserve"
+	cpFlagResumable = "resumable"
 
 	tunnelFlagLocalPort       = "local-port"
 	tunnelFlagDestinationPort = "destination-port"
 
+	exportFrameSystemFlagFormat = "format"
+
+	diagnosticsFlagFTDCWindow = "ftdc-window"
+	diagnosticsFlagSkipFTDC   = "skip-ftdc"
+
 	organizationFlagSupportEmail = "support-email"
 	organizationBillingAddress   = "address"
 	organizationFlagLogoPath     = "logo-path"
@@ -2236,6 +2245,11 @@ Copy multiple files from the machine to a local destination with recursion and k
 									// Note(erd): maybe support access time in the future if needed
 									Usage: "preserve modification times and file mode bits from the source files",
 								},
+								&cli.BoolFlag{
+									Name: cpFlagResumable,
+									Usage: "copy files in checksummed chunks, resuming an earlier interrupted copy of the same files " +
+										"and skipping files that are already identical. Does not support directories",
+								},
 							},
 							Action: createCommandWithT[machinesPartCopyFilesArgs](MachinesPartCopyFilesAction),
 						},
@@ -2282,6 +2296,105 @@ Copy multiple files from the machine to a local destination with recursion and k
 							},
 							Action: createCommandWithT[robotsPartTunnelArgs](RobotsPartTunnelAction),
 						},
+						{
+							Name:  "export-frame-system",
+							Usage: "export the frame system of a machine part as URDF or SDF",
+							UsageText: createUsageText("machines part export-frame-system", []string{
+								generalFlagPart,
+							}, true, false),
+							Description: `Export the frame system of a machine part, with the links, joints and geometries of its frames,
+so that the machine can be loaded into tools like RViz or Gazebo.
+
+Frames added to the frame system at runtime are included.`,
+							Flags: []cli.Flag{
+								&AliasStringFlag{
+									cli.StringFlag{
+										Name:     generalFlagPart,
+										Aliases:  []string{generalFlagPartID, generalFlagPartName},
+										Required: true,
+									},
+								},
+								&AliasStringFlag{
+									cli.StringFlag{
+										Name:    generalFlagOrganization,
+										Aliases: []string{generalFlagAliasOrg, generalFlagOrgID, generalFlagAliasOrgName},
+									},
+								},
+								&AliasStringFlag{
+									cli.StringFlag{
+										Name:    generalFlagLocation,
+										Aliases: []string{generalFlagLocationID, generalFlagAliasLocationName},
+									},
+								},
+								&AliasStringFlag{
+									cli.StringFlag{
+										Name:    generalFlagMachine,
+										Aliases: []string{generalFlagAliasRobot, generalFlagMachineID, generalFlagMachineName},
+									},
+								},
+								&cli.StringFlag{
+									Name:  exportFrameSystemFlagFormat,
+									Usage: "file format (urdf or sdf)",
+									Value: urdf.Extension,
+								},
+								&cli.PathFlag{
+									Name:  generalFlagDestination,
+									Usage: "file to write to, defaults to stdout",
+								},
+							},
+							Action: createCommandWithT[robotsPartExportFrameSystemArgs](RobotsPartExportFrameSystemAction),
+						},
+						{
+							Name:  "diagnostics",
+							Usage: "download a diagnostics bundle from a machine part",
+							UsageText: createUsageText("machines part diagnostics", []string{
+								generalFlagPart,
+							}, true, false),
+							Description: `Generate a diagnostics bundle on a machine part and download it over the connection to the part.
+The bundle is a gzipped tarball for support holding the recent logs and FTDC files of the part, its config
+with secrets redacted, the statuses of its resources and its version.`,
+							Flags: []cli.Flag{
+								&AliasStringFlag{
+									cli.StringFlag{
+										Name:     generalFlagPart,
+										Aliases:  []string{generalFlagPartID, generalFlagPartName},
+										Required: true,
+									},
+								},
+								&AliasStringFlag{
+									cli.StringFlag{
+										Name:    generalFlagOrganization,
+										Aliases: []string{generalFlagAliasOrg, generalFlagOrgID, generalFlagAliasOrgName},
+									},
+								},
+								&AliasStringFlag{
+									cli.StringFlag{
+										Name:    generalFlagLocation,
+										Aliases: []string{generalFlagLocationID, generalFlagAliasLocationName},
+									},
+								},
+								&AliasStringFlag{
+									cli.StringFlag{
+										Name:    generalFlagMachine,
+										Aliases: []string{generalFlagAliasRobot, generalFlagMachineID, generalFlagMachineName},
+									},
+								},
+								&cli.PathFlag{
+									Name:  generalFlagDestination,
+									Usage: "file to write the bundle to, defaults to diagnostics-<part>-<time>.tar.gz",
+								},
+								&cli.DurationFlag{
+									Name:  diagnosticsFlagFTDCWindow,
+									Usage: "how far back to include FTDC files",
+									Value: robot.DefaultDiagnosticsFTDCWindow,
+								},
+								&cli.BoolFlag{
+									Name:  diagnosticsFlagSkipFTDC,
+									Usage: "leave FTDC files out of the bundle",
+								},
+							},
+							Action: createCommandWithT[robotsPartDiagnosticsArgs](RobotsPartDiagnosticsAction),
+						},
 					},
 				},
 			},
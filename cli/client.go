@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"net"
@@ -13,6 +14,7 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"path"
 	"path/filepath"
 	"runtime/debug"
 	"strconv"
@@ -46,10 +48,15 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 
 	rconfig "go.viam.com/rdk/config"
+	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/referenceframe/urdf"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/framesystem"
 	"go.viam.com/rdk/services/shell"
 )
 
@@ -1236,6 +1243,7 @@ type machinesPartCopyFilesArgs struct {
 	Part         string
 	Recursive    bool
 	Preserve     bool
+	Resumable    bool
 }
 
 // MachinesPartCopyFilesAction is the corresponding Action for 'machines part cp'.
@@ -1320,6 +1328,9 @@ func (c *viamClient) machinesPartCopyFilesAction(
 	}
 
 	doCopy := func() error {
+		if flagArgs.Resumable {
+			return c.copyFilesResumable(flagArgs, globalArgs.Debug, isFrom, paths, destination, logger)
+		}
 		if isFrom {
 			return c.copyFilesFromMachine(
 				flagArgs.Organization,
@@ -1462,6 +1473,172 @@ func (c *viamClient) robotPartTunnel(cCtx *cli.Context, args robotsPartTunnelArg
 	return tunnelTraffic(cCtx, robotClient, args.LocalPort, args.DestinationPort)
 }
 
+type robotsPartExportFrameSystemArgs struct {
+	Organization string
+	Location     string
+	Machine      string
+	Part         string
+	Format       string
+	Destination  string
+}
+
+// RobotsPartExportFrameSystemAction is the corresponding Action for 'machines part export-frame-system'.
+func RobotsPartExportFrameSystemAction(c *cli.Context, args robotsPartExportFrameSystemArgs) error {
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+
+	return client.robotPartExportFrameSystem(c, args)
+}
+
+func (c *viamClient) robotPartExportFrameSystem(cCtx *cli.Context, args robotsPartExportFrameSystemArgs) error {
+	if args.Format != urdf.Extension && args.Format != urdf.SDFExtension {
+		return errors.Errorf("unsupported format %q, must be %q or %q", args.Format, urdf.Extension, urdf.SDFExtension)
+	}
+
+	logger := logging.FromZapCompatible(zap.NewNop().Sugar())
+	globalArgs, err := getGlobalArgs(cCtx)
+	if err != nil {
+		return err
+	}
+	if globalArgs.Debug {
+		logger = logging.NewDebugLogger("cli")
+	}
+
+	dialCtx, fqdn, rpcOpts, err := c.prepareDial(args.Organization, args.Location, args.Machine, args.Part, globalArgs.Debug)
+	if err != nil {
+		return err
+	}
+	robotClient, err := c.connectToRobot(dialCtx, fqdn, rpcOpts, globalArgs.Debug, logger)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		utils.UncheckedError(robotClient.Close(cCtx.Context))
+	}()
+
+	fsCfg, err := robotClient.FrameSystemConfig(cCtx.Context)
+	if err != nil {
+		return err
+	}
+	data, err := marshalFrameSystem(fsCfg, args.Part, args.Format)
+	if err != nil {
+		return err
+	}
+
+	if args.Destination == "" {
+		_, err = cCtx.App.Writer.Write(data)
+		return err
+	}
+	//nolint:gosec
+	if err := os.WriteFile(args.Destination, data, 0o644); err != nil {
+		return err
+	}
+	printf(cCtx.App.Writer, "Wrote frame system to %s", args.Destination)
+	return nil
+}
+
+type robotsPartDiagnosticsArgs struct {
+	Organization string
+	Location     string
+	Machine      string
+	Part         string
+	Destination  string
+	FtdcWindow   time.Duration
+	SkipFtdc     bool
+}
+
+// RobotsPartDiagnosticsAction is the corresponding Action for 'machines part diagnostics'.
+func RobotsPartDiagnosticsAction(c *cli.Context, args robotsPartDiagnosticsArgs) error {
+	client, err := newViamClient(c)
+	if err != nil {
+		return err
+	}
+
+	return client.robotPartDiagnostics(c, args)
+}
+
+func (c *viamClient) robotPartDiagnostics(cCtx *cli.Context, args robotsPartDiagnosticsArgs) (err error) {
+	logger := logging.FromZapCompatible(zap.NewNop().Sugar())
+	globalArgs, err := getGlobalArgs(cCtx)
+	if err != nil {
+		return err
+	}
+	if globalArgs.Debug {
+		logger = logging.NewDebugLogger("cli")
+	}
+
+	dialCtx, fqdn, rpcOpts, err := c.prepareDial(args.Organization, args.Location, args.Machine, args.Part, globalArgs.Debug)
+	if err != nil {
+		return err
+	}
+	robotClient, err := c.connectToRobot(dialCtx, fqdn, rpcOpts, globalArgs.Debug, logger)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		utils.UncheckedError(robotClient.Close(cCtx.Context))
+	}()
+
+	destination := args.Destination
+	if destination == "" {
+		destination = diagnosticsFilename(args.Part, time.Now())
+	}
+	//nolint:gosec
+	f, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Combine(err, f.Close())
+		if err != nil {
+			utils.UncheckedError(os.Remove(destination))
+		}
+	}()
+
+	opts := robot.DiagnosticsOptions{FTDCWindow: args.FtdcWindow, SkipFTDC: args.SkipFtdc}
+	if err := robotClient.DiagnosticsBundle(cCtx.Context, opts, f); err != nil {
+		if errors.Is(err, robot.ErrDiagnosticsUnsupported) {
+			return errors.New("the machine part does not support diagnostics bundles, update its viam-server")
+		}
+		return err
+	}
+	printf(cCtx.App.Writer, "Wrote diagnostics bundle to %s", destination)
+	return nil
+}
+
+// diagnosticsFilename returns the default name of the diagnostics bundle of a part.
+func diagnosticsFilename(part string, now time.Time) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ' ' {
+			return '-'
+		}
+		return r
+	}, part)
+	return fmt.Sprintf("diagnostics-%s-%s.tar.gz", name, now.UTC().Format("20060102T150405Z"))
+}
+
+// marshalFrameSystem returns the frame system as a model named `name`, in the URDF or SDF format.
+func marshalFrameSystem(fsCfg *framesystem.Config, name, format string) ([]byte, error) {
+	parts := fsCfg.Parts
+	for _, transform := range fsCfg.AdditionalTransforms {
+		parts = append(parts, &referenceframe.FrameSystemPart{FrameConfig: transform})
+	}
+	model, err := urdf.NewModelFromFrameSystemParts(parts, name)
+	if err != nil {
+		return nil, err
+	}
+	if format == urdf.SDFExtension {
+		return urdf.MarshalSDF(model)
+	}
+	data, err := xml.MarshalIndent(model, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
 // checkUpdateResponse holds the values used to hold release information.
 type getLatestReleaseResponse struct {
 	Name       string `json:"name"`
@@ -2505,6 +2682,82 @@ func (c *viamClient) copyFilesFromMachine(
 	return shellSvc.CopyFilesFromMachine(c.c.Context, paths, allowRecursion, preserve, factory, nil)
 }
 
+// copyFilesResumable copies files to or from a machine in checksummed chunks with the shell
+// service's resumable file transfers, printing the progress of each file.
+func (c *viamClient) copyFilesResumable(
+	flagArgs machinesPartCopyFilesArgs,
+	debug bool,
+	isFrom bool,
+	paths []string,
+	destination string,
+	logger logging.Logger,
+) error {
+	if flagArgs.Recursive {
+		return errors.New("resumable copies do not support directories, copy them without --resumable")
+	}
+	shellSvc, closeClient, err := c.connectToShellService(
+		flagArgs.Organization, flagArgs.Location, flagArgs.Machine, flagArgs.Part, debug, logger)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		utils.UncheckedError(closeClient(c.c.Context))
+	}()
+	transferer, ok := shellSvc.(shell.ResumableFileTransferer)
+	if !ok {
+		return errors.New("the machine does not support resumable copies, copy without --resumable")
+	}
+
+	// copying into a directory keeps the file names, like cp
+	intoDir := len(paths) > 1 || strings.HasSuffix(destination, "/") || destination == ""
+	if !intoDir {
+		if isFrom {
+			info, err := os.Stat(destination)
+			intoDir = err == nil && info.IsDir()
+		} else {
+			stat, err := transferer.StatFileTransfer(c.c.Context, destination, false)
+			if err != nil {
+				return err
+			}
+			intoDir = stat.IsDir
+		}
+	}
+
+	for _, p := range paths {
+		target := destination
+		if intoDir {
+			if isFrom {
+				target = filepath.Join(destination, path.Base(filepath.ToSlash(p)))
+			} else if destination == "" {
+				target = filepath.Base(p)
+			} else {
+				target = strings.TrimSuffix(destination, "/") + "/" + filepath.Base(p)
+			}
+		}
+		opts := shell.FileTransferOptions{
+			Preserve: flagArgs.Preserve,
+			Progress: func(transferred, total int64) {
+				percent := int64(100)
+				if total > 0 {
+					percent = transferred * 100 / total
+				}
+				fmt.Fprintf(c.c.App.ErrWriter, "\r%s %3d%% (%s / %s)", //nolint:errcheck
+					p, percent, data.FormatBytesI64(transferred), data.FormatBytesI64(total))
+			},
+		}
+		if isFrom {
+			err = shell.DownloadFile(c.c.Context, transferer, p, target, opts)
+		} else {
+			err = shell.UploadFile(c.c.Context, transferer, p, target, opts)
+		}
+		fmt.Fprintln(c.c.App.ErrWriter) //nolint:errcheck
+		if err != nil {
+			return errors.Wrapf(err, "failed to copy %q, running the same copy again resumes it", p)
+		}
+	}
+	return nil
+}
+
 func logEntryFieldsToString(fields []*structpb.Struct) (string, error) {
 	// if there are no fields, don't return anything, otherwise we add lots of {}'s
 	// to the logs
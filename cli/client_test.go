@@ -18,6 +18,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/geo/r3"
 	"github.com/google/uuid"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/zap/zapcore"
@@ -32,11 +33,15 @@ import (
 
 	robotconfig "go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/referenceframe/urdf"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/framesystem"
 	robotimpl "go.viam.com/rdk/robot/impl"
 	"go.viam.com/rdk/services/shell"
 	_ "go.viam.com/rdk/services/shell/register"
 	shelltestutils "go.viam.com/rdk/services/shell/testutils"
+	"go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/testutils/inject"
 	"go.viam.com/rdk/testutils/robottestutils"
 	"go.viam.com/rdk/utils"
@@ -1173,6 +1178,42 @@ func TestShellFileCopy(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("resumable", func(t *testing.T) {
+		partFlagsCopy := make(map[string]any, len(partFlags))
+		maps.Copy(partFlagsCopy, partFlags)
+		partFlagsCopy[cpFlagResumable] = true
+
+		tempDir := t.TempDir()
+		args := []string{fmt.Sprintf("machine:%s", tfs.SingleFileNested), tempDir}
+		cCtx, viamClient, _, errOut := setupWithRunningPart(
+			t, asc, nil, nil, partFlagsCopy, "token", partFqdn, args...)
+		test.That(t,
+			viamClient.machinesPartCopyFilesAction(cCtx, parseStructFromCtx[machinesPartCopyFilesArgs](cCtx), logger),
+			test.ShouldBeNil)
+		rd, err := os.ReadFile(filepath.Join(tempDir, filepath.Base(tfs.SingleFileNested)))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, rd, test.ShouldResemble, tfs.SingleFileNestedData)
+		test.That(t, strings.Join(errOut.messages, ""), test.ShouldContainSubstring, "100%")
+
+		uploadPath := filepath.Join(t.TempDir(), "uploaded")
+		args = []string{tfs.SingleFileNested, fmt.Sprintf("machine:%s", uploadPath)}
+		cCtx, viamClient, _, _ = setupWithRunningPart(
+			t, asc, nil, nil, partFlagsCopy, "token", partFqdn, args...)
+		test.That(t,
+			viamClient.machinesPartCopyFilesAction(cCtx, parseStructFromCtx[machinesPartCopyFilesArgs](cCtx), logger),
+			test.ShouldBeNil)
+		rd, err = os.ReadFile(uploadPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, rd, test.ShouldResemble, tfs.SingleFileNestedData)
+
+		partFlagsCopy["recursive"] = true
+		cCtx, viamClient, _, _ = setupWithRunningPart(
+			t, asc, nil, nil, partFlagsCopy, "token", partFqdn, args...)
+		err = viamClient.machinesPartCopyFilesAction(cCtx, parseStructFromCtx[machinesPartCopyFilesArgs](cCtx), logger)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "do not support directories")
+	})
 }
 
 func TestCreateOAuthAppAction(t *testing.T) {
@@ -1442,3 +1483,29 @@ func TestTunnelE2ECLI(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestMarshalFrameSystem(t *testing.T) {
+	box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	fsCfg := &framesystem.Config{
+		Parts: []*referenceframe.FrameSystemPart{{
+			FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, spatialmath.NewZeroPose(), "base", box),
+		}},
+		AdditionalTransforms: []*referenceframe.LinkInFrame{
+			referenceframe.NewLinkInFrame("base", spatialmath.NewPoseFromPoint(r3.Vector{Z: 100}), "camera", nil),
+		},
+	}
+
+	data, err := marshalFrameSystem(fsCfg, "machine", urdf.Extension)
+	test.That(t, err, test.ShouldBeNil)
+	model, err := urdf.UnmarshalModelXML(data, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, model.Name, test.ShouldEqual, "machine")
+	// imported joints are links too
+	test.That(t, model.Links, test.ShouldHaveLength, 4)
+
+	data, err = marshalFrameSystem(fsCfg, "machine", urdf.SDFExtension)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(data), test.ShouldContainSubstring, `<sdf version="1.7">`)
+	test.That(t, string(data), test.ShouldContainSubstring, `<pose relative_to="base">`)
+}
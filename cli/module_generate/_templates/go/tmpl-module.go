@@ -39,8 +39,11 @@ type Config struct {
 // The path is the JSON path in your robot's config (not the `Config` struct) to the
 // resource being validated; e.g. "components.0".
 func (cfg *Config) Validate(path string) ([]string, error) {
-	// Add config validation code here
-	 return nil, nil
+	// Add config validation code here. For example, to require the "pin" attribute:
+	//	if cfg.Pin == "" {
+	//		return nil, resource.NewConfigValidationFieldRequiredError(path, "pin")
+	//	}
+	return nil, nil
 }
 
 type {{.ModuleCamel}}{{.ModelPascal}} struct {
@@ -0,0 +1,38 @@
+package {{.ModuleLowercase}}
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/rdk/{{.ResourceType}}s/{{.ResourceSubtype}}"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func TestValidate(t *testing.T) {
+	cfg := &Config{}
+	// Add a case here for each config attribute that Validate checks
+	if _, err := cfg.Validate("components.0"); err != nil {
+		t.Fatalf("expected config to be valid: %v", err)
+	}
+}
+
+func TestNew{{.ModelPascal}}(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	name := {{.ResourceSubtype}}.Named("test")
+	res, err := New{{.ModelPascal}}(ctx, resource.Dependencies{}, name, &Config{}, logger)
+	if err != nil {
+		t.Fatalf("failed to construct {{.ModelName}}: %v", err)
+	}
+	if res.Name() != name {
+		t.Fatalf("expected name %v but got %v", name, res.Name())
+	}
+
+	// Test the methods of your model here as you implement them
+
+	if err := res.Close(ctx); err != nil {
+		t.Fatalf("failed to close {{.ModelName}}: %v", err)
+	}
+}
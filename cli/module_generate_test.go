@@ -146,6 +146,26 @@ func TestGenerateModuleAction(t *testing.T) {
 		test.That(t, err, test.ShouldBeNil)
 	})
 
+	t.Run("test render go template", func(t *testing.T) {
+		testModule.Language = "go"
+		setupDirectories(cCtx, testModule.ModuleName, globalArgs)
+		err := copyLanguageTemplate(cCtx, "go", testModule.ModuleName, globalArgs)
+		test.That(t, err, test.ShouldBeNil)
+
+		err = renderTemplate(cCtx, testModule, globalArgs)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = os.Stat(filepath.Join(modulePath, "Makefile"))
+		test.That(t, err, test.ShouldBeNil)
+		_, err = os.Stat(filepath.Join(modulePath, "cmd", "module", "main.go"))
+		test.That(t, err, test.ShouldBeNil)
+
+		moduleTest, err := os.ReadFile(filepath.Join(modulePath, "module_test.go"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, string(moduleTest), test.ShouldContainSubstring, "package "+testModule.ModuleLowercase)
+		test.That(t, string(moduleTest), test.ShouldContainSubstring, "func TestNew"+testModule.ModelPascal)
+		test.That(t, string(moduleTest), test.ShouldContainSubstring, "go.viam.com/rdk/components/arm")
+	})
+
 	t.Run("test generate python stubs", func(t *testing.T) {
 		testModule.Language = "python"
 		setupDirectories(cCtx, testModule.ModuleName, globalArgs)
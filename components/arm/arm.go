@@ -37,6 +37,7 @@ func init() {
 		API:        API,
 		MethodName: jointPositions.String(),
 	}, newJointPositionsCollector)
+	robot.RegisterResourceReader(API, readArm)
 }
 
 // SubtypeName is a constant that identifies the component resource API string "arm".
@@ -189,3 +190,32 @@ func CheckDesiredJointPositions(ctx context.Context, a Arm, desiredInputs []refe
 	}
 	return nil
 }
+
+// readArm reads the joint positions, in degrees for revolute joints, and end position of an arm for a bulk read.
+func readArm(ctx context.Context, res resource.Resource, extra map[string]interface{}) (map[string]interface{}, error) {
+	a, err := resource.AsType[Arm](res)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := a.JointPositions(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	jp, err := referenceframe.JointPositionsFromInputs(a.ModelFrame(), inputs)
+	if err != nil {
+		return nil, err
+	}
+	jointPositions := make([]interface{}, 0, len(jp.Values))
+	for _, v := range jp.Values {
+		jointPositions = append(jointPositions, v)
+	}
+	pose, err := a.EndPosition(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	endPosition, err := spatialmath.PoseMap(pose)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"joint_positions": jointPositions, "end_position": endPosition}, nil
+}
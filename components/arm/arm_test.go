@@ -3,6 +3,7 @@ package arm_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/golang/geo/r3"
 	"go.viam.com/test"
@@ -317,3 +318,22 @@ func TestFromRobot(t *testing.T) {
 	_, err = arm.FromRobot(r, "g")
 	test.That(t, err, test.ShouldNotBeNil)
 }
+
+func TestTimeParameterize(t *testing.T) {
+	positions := [][]referenceframe.Input{
+		referenceframe.FloatsToInputs([]float64{0, 0}),
+		referenceframe.FloatsToInputs([]float64{1, -2}),
+		referenceframe.FloatsToInputs([]float64{1, -2}),
+		referenceframe.FloatsToInputs([]float64{0.5, -2}),
+	}
+	points := arm.TimeParameterize(positions, 2)
+	test.That(t, len(points), test.ShouldEqual, len(positions))
+	test.That(t, points[0].TimeFromStart, test.ShouldEqual, 0)
+	test.That(t, points[1].TimeFromStart, test.ShouldEqual, time.Second)
+	test.That(t, points[2].TimeFromStart, test.ShouldEqual, time.Second)
+	test.That(t, points[3].TimeFromStart, test.ShouldEqual, 1250*time.Millisecond)
+	test.That(t, points[3].Positions, test.ShouldResemble, positions[3])
+
+	points = arm.TimeParameterize(positions[:2], 0)
+	test.That(t, points[1].TimeFromStart, test.ShouldEqual, 2*time.Second)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/golang/geo/r3"
 	robotpb "go.viam.com/api/robot/v1"
@@ -217,3 +218,149 @@ func TestClient(t *testing.T) {
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
 }
+
+func TestClientJointVelocities(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	var (
+		capVelocities []float64
+		capSegments   []arm.JointVelocitySegment
+		extraOptions  map[string]interface{}
+	)
+	injectArm := &inject.Arm{}
+	injectArm.SetJointVelocitiesFunc = func(ctx context.Context, velocities []float64, extra map[string]interface{}) error {
+		capVelocities = velocities
+		extraOptions = extra
+		return nil
+	}
+	injectArm.MoveThroughJointVelocitiesFunc = func(
+		ctx context.Context,
+		segments []arm.JointVelocitySegment,
+		extra map[string]interface{},
+	) error {
+		capSegments = segments
+		extraOptions = extra
+		return nil
+	}
+	injectArm.DoFunc = testutils.EchoFunc
+
+	armSvc, err := resource.NewAPIResourceCollection(arm.API, map[resource.Name]arm.Arm{
+		arm.Named(testArmName): injectArm,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[arm.Arm](arm.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, armSvc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	armClient, err := arm.NewClientFromConn(context.Background(), conn, "", arm.Named(testArmName), logger)
+	test.That(t, err, test.ShouldBeNil)
+	velArm, ok := armClient.(arm.JointVelocityController)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	err = velArm.SetJointVelocities(context.Background(), []float64{0.1, -0.2, 0}, map[string]interface{}{"foo": "SetJointVelocities"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, capVelocities, test.ShouldResemble, []float64{0.1, -0.2, 0})
+	test.That(t, extraOptions, test.ShouldResemble, map[string]interface{}{"foo": "SetJointVelocities"})
+
+	segments := []arm.JointVelocitySegment{
+		{Velocities: []float64{1, 2, 3}, Duration: 500 * time.Millisecond},
+		{Velocities: []float64{0, 0, -1}, Duration: 2 * time.Second},
+	}
+	err = velArm.MoveThroughJointVelocities(context.Background(), segments, map[string]interface{}{"foo": "MoveThroughJointVelocities"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, capSegments, test.ShouldResemble, segments)
+	test.That(t, extraOptions, test.ShouldResemble, map[string]interface{}{"foo": "MoveThroughJointVelocities"})
+
+	// Other commands still reach the arm's DoCommand.
+	resp, err := armClient.DoCommand(context.Background(), testutils.TestCommand)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["command"], test.ShouldEqual, testutils.TestCommand["command"])
+
+	test.That(t, armClient.Close(context.Background()), test.ShouldBeNil)
+}
+
+func TestClientStreamTrajectory(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	var (
+		capPoints    []arm.TrajectoryPoint
+		extraOptions map[string]interface{}
+	)
+	injectArm := &inject.Arm{}
+	injectArm.StreamTrajectoryFunc = func(
+		ctx context.Context,
+		points <-chan arm.TrajectoryPoint,
+		feedback func(arm.TrajectoryFeedback),
+		extra map[string]interface{},
+	) error {
+		extraOptions = extra
+		idx := 0
+		for pt := range points {
+			capPoints = append(capPoints, pt)
+			feedback(arm.TrajectoryFeedback{PointIndex: idx, TimeFromStart: pt.TimeFromStart, Positions: pt.Positions})
+			idx++
+		}
+		return nil
+	}
+
+	armSvc, err := resource.NewAPIResourceCollection(arm.API, map[resource.Name]arm.Arm{
+		arm.Named(testArmName): injectArm,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[arm.Arm](arm.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, armSvc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	armClient, err := arm.NewClientFromConn(context.Background(), conn, "", arm.Named(testArmName), logger)
+	test.That(t, err, test.ShouldBeNil)
+	streamer, ok := armClient.(arm.TrajectoryStreamer)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	trajectory := arm.TimeParameterize([][]referenceframe.Input{
+		referenceframe.FloatsToInputs([]float64{0, 0, 0}),
+		referenceframe.FloatsToInputs([]float64{1, 0, 0}),
+		referenceframe.FloatsToInputs([]float64{1, 1, 0}),
+	}, 1)
+	points := make(chan arm.TrajectoryPoint, len(trajectory))
+	for _, pt := range trajectory {
+		points <- pt
+	}
+	close(points)
+
+	var feedback []arm.TrajectoryFeedback
+	err = streamer.StreamTrajectory(context.Background(), points, func(fb arm.TrajectoryFeedback) {
+		feedback = append(feedback, fb)
+	}, map[string]interface{}{"foo": "StreamTrajectory"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, capPoints, test.ShouldResemble, trajectory)
+	test.That(t, extraOptions, test.ShouldResemble, map[string]interface{}{"foo": "StreamTrajectory"})
+	test.That(t, len(feedback), test.ShouldEqual, len(trajectory))
+	for i, fb := range feedback {
+		test.That(t, fb.PointIndex, test.ShouldEqual, i)
+		test.That(t, fb.Positions, test.ShouldResemble, trajectory[i].Positions)
+	}
+
+	test.That(t, armClient.Close(context.Background()), test.ShouldBeNil)
+}
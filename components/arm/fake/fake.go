@@ -4,9 +4,12 @@ package fake
 import (
 	"context"
 	_ "embed"
+	"math"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/components/arm"
@@ -17,6 +20,7 @@ import (
 	"go.viam.com/rdk/referenceframe/urdf"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/spatialmath"
+	rutils "go.viam.com/rdk/utils"
 )
 
 // errAttrCfgPopulation is the returned error if the Config's fields are fully populated.
@@ -37,20 +41,50 @@ var dofbotjson []byte
 type Config struct {
 	ArmModel      string `json:"arm-model,omitempty"`
 	ModelFilePath string `json:"model-path,omitempty"`
+
+	// MaxJointSpeedDegsPerSec and MaxJointAccelDegsPerSec2 make the fake arm simulate moving its
+	// joints instead of jumping to their goals: moves take time, during which the arm reports
+	// intermediate joint positions and is moving, and can be stopped. Zero means no limit, and the
+	// arm jumps to its goals if both are zero. Prismatic joints are limited to the same number of
+	// mm per second.
+	MaxJointSpeedDegsPerSec  float64 `json:"max-joint-speed-degs-per-sec,omitempty"`
+	MaxJointAccelDegsPerSec2 float64 `json:"max-joint-accel-degs-per-sec-per-sec,omitempty"`
+
+	// KinematicsOverrides narrow the joint limits of the arm's kinematics and set the home position the arm starts at.
+	KinematicsOverrides *referenceframe.KinematicsOverrides `json:"kinematics-overrides,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
 func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.MaxJointSpeedDegsPerSec < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("max-joint-speed-degs-per-sec cannot be negative"))
+	}
+	if conf.MaxJointAccelDegsPerSec2 < 0 {
+		return nil, resource.NewConfigValidationError(path,
+			errors.New("max-joint-accel-degs-per-sec-per-sec cannot be negative"))
+	}
+	if err := conf.KinematicsOverrides.Validate(); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	var model referenceframe.Model
 	var err error
 	switch {
 	case conf.ArmModel != "" && conf.ModelFilePath != "":
 		err = errAttrCfgPopulation
 	case conf.ArmModel != "" && conf.ModelFilePath == "":
-		_, err = modelFromName(conf.ArmModel, "")
+		model, err = modelFromName(conf.ArmModel, "")
 	case conf.ArmModel == "" && conf.ModelFilePath != "":
-		_, err = modelFromPath(conf.ModelFilePath, "")
+		model, err = urdf.ParseModelFile(conf.ModelFilePath, "")
+	default:
+		model, err = modelFromName(Model.Name, "")
 	}
-	return nil, err
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conf.KinematicsOverrides.Apply(model); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	return nil, nil
 }
 
 func init() {
@@ -64,6 +98,7 @@ func NewArm(ctx context.Context, deps resource.Dependencies, conf resource.Confi
 	a := &Arm{
 		Named:  conf.ResourceName().AsNamed(),
 		logger: logger,
+		clk:    rutils.ClockFromContext(ctx),
 	}
 	if err := a.Reconfigure(ctx, deps, conf); err != nil {
 		return nil, err
@@ -85,13 +120,16 @@ func buildModel(cfg resource.Config, newConf *Config) (referenceframe.Model, err
 	case armModel != "":
 		model, err = modelFromName(armModel, cfg.Name)
 	case modelPath != "":
-		model, err = modelFromPath(modelPath, cfg.Name)
+		model, err = urdf.ParseModelFile(modelPath, cfg.Name)
 	default:
 		// if no arm model is specified, we return a fake arm with 1 dof and 0 spatial transformation
 		model, err = modelFromName(Model.Name, cfg.Name)
 	}
+	if err != nil {
+		return nil, err
+	}
 
-	return model, err
+	return newConf.KinematicsOverrides.Apply(model)
 }
 
 // Arm is a fake arm that can simply read and set properties.
@@ -103,6 +141,31 @@ type Arm struct {
 	mu     sync.RWMutex
 	joints []referenceframe.Input
 	model  referenceframe.Model
+
+	// velocities are the joint velocities set by SetJointVelocities. They are integrated into
+	// `joints` whenever the joint positions are read. velocitiesSetAt is the time `joints` was last
+	// brought up to date with `velocities`.
+	velocities      []float64
+	velocitiesSetAt time.Time
+
+	// maxSpeed and maxAccel are the joint limits of simulated moves, in radians. move is the
+	// simulated move in progress, if any.
+	maxSpeed float64
+	maxAccel float64
+	move     *jointMove
+
+	// clk times simulated moves and velocities. The wall clock is used if it is nil.
+	clk clock.Clock
+}
+
+// jointMove is a simulated move of all joints from one set of positions to another, timed such
+// that all joints arrive at once by the profile of the joint that has the furthest to go.
+type jointMove struct {
+	from, to  []float64
+	profile   *rutils.TrapezoidalProfile
+	startedAt time.Time
+	// done is closed when the move is stopped or replaced by another command.
+	done chan struct{}
 }
 
 // Reconfigure atomically reconfigures this arm in place based on the new config.
@@ -126,8 +189,15 @@ func (a *Arm) Reconfigure(ctx context.Context, deps resource.Dependencies, conf
 
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	a.joints = referenceframe.FloatsToInputs(make([]float64, dof))
+	a.joints = newConf.KinematicsOverrides.HomeInputs(model)
+	if a.joints == nil {
+		a.joints = referenceframe.FloatsToInputs(make([]float64, dof))
+	}
 	a.model = model
+	a.velocities = nil
+	a.stopMove()
+	a.maxSpeed = rutils.DegToRad(newConf.MaxJointSpeedDegsPerSec)
+	a.maxAccel = rutils.DegToRad(newConf.MaxJointAccelDegsPerSec2)
 
 	return nil
 }
@@ -152,21 +222,36 @@ func (a *Arm) EndPosition(ctx context.Context, extra map[string]interface{}) (sp
 
 // MoveToPosition sets the position.
 func (a *Arm) MoveToPosition(ctx context.Context, pose spatialmath.Pose, extra map[string]interface{}) error {
-	a.mu.RLock()
-	defer a.mu.RUnlock()
-
+	a.mu.Lock()
+	a.advance()
+	a.velocities = nil
+	a.stopMove()
 	model := a.model
-	_, err := model.Transform(a.joints)
+	joints := append([]referenceframe.Input(nil), a.joints...)
+	simulated := a.simulated()
+	a.mu.Unlock()
+
+	_, err := model.Transform(joints)
 	if err != nil && strings.Contains(err.Error(), referenceframe.OOBErrString) {
 		return errors.New("cannot move arm: " + err.Error())
 	} else if err != nil {
 		return err
 	}
 
-	plan, err := motionplan.PlanFrameMotion(ctx, a.logger, pose, model, a.joints, nil, nil)
+	plan, err := motionplan.PlanFrameMotion(ctx, a.logger, pose, model, joints, nil, nil)
 	if err != nil {
 		return err
 	}
+	if simulated {
+		for _, step := range plan {
+			if err := a.simulateMove(ctx, step); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	copy(a.joints, plan[len(plan)-1])
 	return nil
 }
@@ -176,16 +261,111 @@ func (a *Arm) MoveToJointPositions(ctx context.Context, joints []referenceframe.
 	if err := arm.CheckDesiredJointPositions(ctx, a, joints); err != nil {
 		return err
 	}
-	a.mu.RLock()
-	defer a.mu.RUnlock()
+	a.mu.Lock()
 	_, err := a.model.Transform(joints)
 	if err != nil {
+		a.mu.Unlock()
 		return err
 	}
+	if a.simulated() {
+		a.mu.Unlock()
+		return a.simulateMove(ctx, joints)
+	}
+	defer a.mu.Unlock()
+	a.velocities = nil
+	a.stopMove()
 	copy(a.joints, joints)
 	return nil
 }
 
+// simulated returns whether moves are simulated. Must be called with `mu` held.
+func (a *Arm) simulated() bool {
+	return a.maxSpeed > 0 || a.maxAccel > 0
+}
+
+// simulateMove moves the joints to goal within the speed and acceleration limits, returning once
+// they get there. It returns an error if the move is stopped or replaced first.
+func (a *Arm) simulateMove(ctx context.Context, goal []referenceframe.Input) error {
+	a.mu.Lock()
+	a.advance()
+	a.velocities = nil
+	a.stopMove()
+	move := &jointMove{
+		from:      referenceframe.InputsToFloats(a.joints),
+		to:        referenceframe.InputsToFloats(goal),
+		startedAt: a.clock().Now(),
+		done:      make(chan struct{}),
+	}
+	for idx := range move.from {
+		profile := rutils.NewTrapezoidalProfile(move.to[idx]-move.from[idx], a.maxSpeed, a.maxAccel)
+		if move.profile == nil || profile.Duration() > move.profile.Duration() {
+			move.profile = profile
+		}
+	}
+	a.move = move
+	a.mu.Unlock()
+
+	timer := a.clock().Timer(move.profile.Duration())
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-move.done:
+		return errors.New("fake arm move was interrupted")
+	case <-ctx.Done():
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if a.move == move {
+			a.advance()
+			a.stopMove()
+		}
+		return ctx.Err()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.move != move {
+		return errors.New("fake arm move was interrupted")
+	}
+	a.advance()
+	return nil
+}
+
+// stopMove stops the simulated move in progress, leaving the joints where they are. Must be called
+// with `mu` held for writing.
+func (a *Arm) stopMove() {
+	if a.move != nil {
+		close(a.move.done)
+		a.move = nil
+	}
+}
+
+// advance brings the joints up to date with the simulated move or velocities in progress. Must be
+// called with `mu` held for writing.
+func (a *Arm) advance() {
+	a.integrateVelocities()
+	if a.move == nil {
+		return
+	}
+	elapsed := a.clock().Since(a.move.startedAt)
+	fraction := a.move.profile.Fraction(elapsed)
+	for idx := range a.joints {
+		from, to := a.move.from[idx], a.move.to[idx]
+		a.joints[idx] = referenceframe.Input{Value: from + (to-from)*fraction}
+	}
+	if elapsed >= a.move.profile.Duration() {
+		// the move is over, but it is not stopped short
+		a.move = nil
+	}
+}
+
+// clock returns the clock simulated moves are timed by.
+func (a *Arm) clock() clock.Clock {
+	if a.clk == nil {
+		return clock.New()
+	}
+	return a.clk
+}
+
 // MoveThroughJointPositions moves the fake arm through the given inputs.
 func (a *Arm) MoveThroughJointPositions(
 	ctx context.Context,
@@ -201,28 +381,116 @@ func (a *Arm) MoveThroughJointPositions(
 	return nil
 }
 
+// StreamTrajectory moves the fake arm to each point's positions once its time has come.
+func (a *Arm) StreamTrajectory(
+	ctx context.Context,
+	points <-chan arm.TrajectoryPoint,
+	feedback func(arm.TrajectoryFeedback),
+	extra map[string]interface{},
+) error {
+	return arm.StreamTrajectoryWithMoves(ctx, points, feedback,
+		func(ctx context.Context, positions []referenceframe.Input) error {
+			return a.MoveToJointPositions(ctx, positions, extra)
+		},
+		a.CurrentInputs)
+}
+
 // JointPositions returns joints.
 func (a *Arm) JointPositions(ctx context.Context, extra map[string]interface{}) ([]referenceframe.Input, error) {
-	a.mu.RLock()
-	defer a.mu.RUnlock()
-	return a.joints, nil
+	return a.CurrentInputs(ctx)
+}
+
+// SetJointVelocities moves the fake arm's joints at the given velocities until they reach their
+// limits, or a new command is given.
+func (a *Arm) SetJointVelocities(ctx context.Context, velocities []float64, extra map[string]interface{}) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(velocities) != len(a.joints) {
+		return errors.Errorf("expected %d joint velocities, got %d", len(a.joints), len(velocities))
+	}
+	a.advance()
+	a.stopMove()
+	a.velocities = append([]float64(nil), velocities...)
+	if a.maxSpeed > 0 {
+		for idx, velocity := range a.velocities {
+			a.velocities[idx] = math.Max(-a.maxSpeed, math.Min(a.maxSpeed, velocity))
+		}
+	}
+	return nil
 }
 
-// Stop doesn't do anything for a fake arm.
+// MoveThroughJointVelocities holds each segment's velocities for its duration, then stops.
+func (a *Arm) MoveThroughJointVelocities(
+	ctx context.Context,
+	segments []arm.JointVelocitySegment,
+	extra map[string]interface{},
+) error {
+	return arm.MoveThroughJointVelocitiesWithSetter(ctx, segments,
+		func(ctx context.Context, velocities []float64) error {
+			return a.SetJointVelocities(ctx, velocities, extra)
+		},
+		func(ctx context.Context) error {
+			return a.Stop(ctx, extra)
+		})
+}
+
+// integrateVelocities advances `joints` by the commanded velocities since they were last
+// integrated, clamping each joint to its limits. Must be called with `mu` held for writing.
+func (a *Arm) integrateVelocities() {
+	now := a.clock().Now()
+	defer func() {
+		a.velocitiesSetAt = now
+	}()
+	if a.velocities == nil {
+		return
+	}
+
+	elapsed := now.Sub(a.velocitiesSetAt).Seconds()
+	limits := a.model.DoF()
+	joints := make([]referenceframe.Input, len(a.joints))
+	for idx, joint := range a.joints {
+		value := joint.Value + a.velocities[idx]*elapsed
+		if idx < len(limits) {
+			value = math.Max(limits[idx].Min, math.Min(limits[idx].Max, value))
+		}
+		joints[idx] = referenceframe.Input{Value: value}
+	}
+	a.joints = joints
+}
+
+// Stop stops any joint velocities set on the fake arm, and any simulated move in progress.
 func (a *Arm) Stop(ctx context.Context, extra map[string]interface{}) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.advance()
+	a.velocities = nil
+	a.stopMove()
 	return nil
 }
 
-// IsMoving is always false for a fake arm.
+// IsMoving returns whether the fake arm has non-zero joint velocities set, or a simulated move in
+// progress.
 func (a *Arm) IsMoving(ctx context.Context) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.advance()
+	if a.move != nil {
+		return true, nil
+	}
+	for _, velocity := range a.velocities {
+		if velocity != 0 {
+			return true, nil
+		}
+	}
 	return false, nil
 }
 
 // CurrentInputs returns the current inputs of the fake arm.
 func (a *Arm) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
-	a.mu.RLock()
-	defer a.mu.RUnlock()
-	return a.joints, nil
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.advance()
+	return append([]referenceframe.Input(nil), a.joints...), nil
 }
 
 // GoToInputs moves the fake arm to the given inputs.
@@ -264,14 +532,3 @@ func modelFromName(model, name string) (referenceframe.Model, error) {
 		return nil, errors.Errorf("fake arm cannot be created, unsupported arm-model: %s", model)
 	}
 }
-
-func modelFromPath(modelPath, name string) (referenceframe.Model, error) {
-	switch {
-	case strings.HasSuffix(modelPath, ".urdf"):
-		return urdf.ParseModelXMLFile(modelPath, name)
-	case strings.HasSuffix(modelPath, ".json"):
-		return referenceframe.ParseModelJSONFile(modelPath, name)
-	default:
-		return nil, errors.New("only files with .json and .urdf file extensions are supported")
-	}
-}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"math"
 	"testing"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
 
+	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
+	rutils "go.viam.com/rdk/utils"
 )
 
 func TestReconfigure(t *testing.T) {
@@ -102,6 +107,37 @@ func TestReconfigure(t *testing.T) {
 	test.That(t, fakeArm.model, test.ShouldResemble, model)
 }
 
+func TestKinematicsOverrides(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	overrides := &referenceframe.KinematicsOverrides{
+		JointLimits: map[string]referenceframe.JointLimitsConfig{"shoulder_pan_joint": {Min: -90, Max: 90}},
+		Home:        []float64{45, -90, 90, -90, -90, 0},
+	}
+	conf := &Config{ArmModel: "ur5e", KinematicsOverrides: overrides}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	a, err := NewArm(context.Background(), nil, resource.Config{Name: "testArm", ConvertedAttributes: conf}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, a.ModelFrame().DoF()[0], test.ShouldResemble, referenceframe.Limit{Min: -math.Pi / 2, Max: math.Pi / 2})
+
+	// the arm starts at its home position
+	joints, err := a.JointPositions(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, joints, test.ShouldResemble, overrides.HomeInputs(a.ModelFrame()))
+
+	// the arm cannot be moved outside of the narrowed limits
+	err = a.MoveToJointPositions(context.Background(), referenceframe.FloatsToInputs([]float64{math.Pi, 0, 0, 0, 0, 0}), nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	conf.KinematicsOverrides = &referenceframe.KinematicsOverrides{
+		JointLimits: map[string]referenceframe.JointLimitsConfig{"shoulder_pan_joint": {Min: -400, Max: 90}},
+	}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must be within its limits")
+}
+
 func TestJointPositions(t *testing.T) {
 	ctx := context.Background()
 	logger := logging.NewTestLogger(t)
@@ -131,3 +167,169 @@ func TestJointPositions(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, sampleInputs, test.ShouldResemble, inputs)
 }
+
+func TestJointVelocities(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	cfg := resource.Config{
+		Name: "testArm",
+		ConvertedAttributes: &Config{
+			ArmModel: "ur5e",
+		},
+	}
+
+	a, err := NewArm(ctx, nil, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	fakeArm := a.(*Arm)
+
+	err = fakeArm.SetJointVelocities(ctx, []float64{1}, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "expected 6 joint velocities")
+
+	velocities := []float64{1, 0, 0, 0, 0, -1}
+	test.That(t, fakeArm.SetJointVelocities(ctx, velocities, nil), test.ShouldBeNil)
+	moving, err := fakeArm.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeTrue)
+
+	time.Sleep(50 * time.Millisecond)
+	inputs, err := fakeArm.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inputs[0].Value, test.ShouldBeGreaterThan, 0)
+	test.That(t, inputs[1].Value, test.ShouldEqual, 0)
+	test.That(t, inputs[5].Value, test.ShouldBeLessThan, 0)
+
+	test.That(t, fakeArm.Stop(ctx, nil), test.ShouldBeNil)
+	moving, err = fakeArm.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+	stopped, err := fakeArm.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	time.Sleep(10 * time.Millisecond)
+	stillStopped, err := fakeArm.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stillStopped, test.ShouldResemble, stopped)
+
+	// Joints stop at their limits.
+	limits := fakeArm.ModelFrame().DoF()
+	test.That(t, fakeArm.SetJointVelocities(ctx, []float64{1000, 0, 0, 0, 0, 0}, nil), test.ShouldBeNil)
+	time.Sleep(50 * time.Millisecond)
+	inputs, err = fakeArm.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inputs[0].Value, test.ShouldEqual, limits[0].Max)
+
+	segments := []arm.JointVelocitySegment{{Velocities: []float64{0, -1, 0, 0, 0, 0}, Duration: 20 * time.Millisecond}}
+	test.That(t, fakeArm.MoveThroughJointVelocities(ctx, segments, nil), test.ShouldBeNil)
+	moving, err = fakeArm.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+	inputs, err = fakeArm.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inputs[1].Value, test.ShouldBeLessThan, 0)
+}
+
+func TestSimulatedMove(t *testing.T) {
+	mockClock := clock.NewMock()
+	ctx := rutils.WithClock(context.Background(), mockClock)
+	logger := logging.NewTestLogger(t)
+	cfg := resource.Config{
+		Name: "testArm",
+		ConvertedAttributes: &Config{
+			ArmModel:                "ur5e",
+			MaxJointSpeedDegsPerSec: 90,
+		},
+	}
+
+	a, err := NewArm(ctx, nil, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	fakeArm := a.(*Arm)
+
+	goal := []referenceframe.Input{{math.Pi / 2}, {0}, {0}, {0}, {0}, {0}}
+	moveErr := make(chan error, 1)
+	go func() {
+		moveErr <- fakeArm.MoveToJointPositions(ctx, goal, nil)
+	}()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		moving, err := fakeArm.IsMoving(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, moving, test.ShouldBeTrue)
+	})
+
+	// halfway through the second the move takes at 90 degrees per second
+	mockClock.Add(500 * time.Millisecond)
+	inputs, err := fakeArm.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inputs[0].Value, test.ShouldAlmostEqual, math.Pi/4)
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		mockClock.Add(100 * time.Millisecond)
+		test.That(tb, len(moveErr), test.ShouldEqual, 1)
+	})
+	test.That(t, <-moveErr, test.ShouldBeNil)
+	inputs, err = fakeArm.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inputs, test.ShouldResemble, goal)
+	moving, err := fakeArm.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+
+	// stopping a move leaves the joints where they are
+	go func() {
+		moveErr <- fakeArm.MoveToJointPositions(ctx, make([]referenceframe.Input, 6), nil)
+	}()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		moving, err := fakeArm.IsMoving(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, moving, test.ShouldBeTrue)
+	})
+	mockClock.Add(500 * time.Millisecond)
+	test.That(t, fakeArm.Stop(ctx, nil), test.ShouldBeNil)
+	test.That(t, <-moveErr, test.ShouldNotBeNil)
+	inputs, err = fakeArm.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inputs[0].Value, test.ShouldAlmostEqual, math.Pi/4)
+}
+
+func TestStreamTrajectory(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	cfg := resource.Config{
+		Name: "testArm",
+		ConvertedAttributes: &Config{
+			ArmModel: "ur5e",
+		},
+	}
+
+	a, err := NewArm(ctx, nil, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	fakeArm := a.(*Arm)
+
+	goal := referenceframe.FloatsToInputs([]float64{0.02, 0, 0, 0, 0, 0.01})
+	trajectory := arm.TimeParameterize([][]referenceframe.Input{
+		make([]referenceframe.Input, 6),
+		goal,
+	}, 1)
+	points := make(chan arm.TrajectoryPoint, len(trajectory))
+	for _, pt := range trajectory {
+		points <- pt
+	}
+	close(points)
+
+	var feedback []arm.TrajectoryFeedback
+	start := time.Now()
+	err = fakeArm.StreamTrajectory(ctx, points, func(fb arm.TrajectoryFeedback) {
+		feedback = append(feedback, fb)
+	}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, trajectory[1].TimeFromStart)
+	test.That(t, len(feedback), test.ShouldEqual, 2)
+	test.That(t, feedback[1].PointIndex, test.ShouldEqual, 1)
+	test.That(t, feedback[1].Positions, test.ShouldResemble, goal)
+
+	inputs, err := fakeArm.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inputs, test.ShouldResemble, goal)
+}
@@ -219,5 +219,11 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := doJointVelocityCommand(ctx, arm, req.GetName(), req); handled {
+		return resp, err
+	}
+	if resp, handled, err := doStreamTrajectoryCommand(ctx, arm, req.GetName(), req); handled {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, arm, req)
 }
@@ -0,0 +1,324 @@
+//go:build !no_cgo
+
+package arm
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// The arm proto has no trajectory streaming RPC. Trajectories are carried over DoCommand in batches
+// using the following reserved keys, each batch returning feedback for the points it executed.
+const (
+	streamTrajectoryKey  = "stream_trajectory"
+	pointsKey            = "points"
+	positionsKey         = "positions"
+	timeFromStartSecsKey = "time_from_start_secs"
+	feedbackKey          = "feedback"
+	pointIndexKey        = "point_index"
+
+	// trajectoryBatchSize is the maximum number of points the client sends in one request.
+	trajectoryBatchSize = 100
+)
+
+// DefaultTrajectoryMaxVelRads is the joint speed used by TimeParameterize when no maximum velocity
+// is given.
+const DefaultTrajectoryMaxVelRads = 1.0
+
+// TrajectoryPoint is a set of joint positions the arm should reach at a time relative to the start
+// of the trajectory.
+type TrajectoryPoint struct {
+	Positions     []referenceframe.Input
+	TimeFromStart time.Duration
+}
+
+// TrajectoryFeedback reports the progress of a streamed trajectory after a point has been executed.
+type TrajectoryFeedback struct {
+	// PointIndex is the index of the executed point in the order points were streamed.
+	PointIndex int
+	// TimeFromStart is the time since execution of the trajectory began.
+	TimeFromStart time.Duration
+	// Positions are the arm's joint positions after executing the point.
+	Positions []referenceframe.Input
+}
+
+// TrajectoryStreamer is implemented by arms that can execute a time-parameterized joint trajectory
+// whose points are supplied while it runs, allowing smooth execution of long trajectories.
+//
+// StreamTrajectory example:
+//
+//	myArm, err := arm.FromRobot(machine, "my_arm")
+//	if streamer, ok := myArm.(arm.TrajectoryStreamer); ok {
+//		points := make(chan arm.TrajectoryPoint, len(trajectory))
+//		for _, pt := range trajectory {
+//			points <- pt
+//		}
+//		close(points)
+//		err = streamer.StreamTrajectory(context.Background(), points, func(fb arm.TrajectoryFeedback) {
+//			logger.Infof("reached point %d at %v", fb.PointIndex, fb.TimeFromStart)
+//		}, nil)
+//	}
+type TrajectoryStreamer interface {
+	// StreamTrajectory executes points as they are received until `points` is closed. Each point is
+	// reached no earlier than its TimeFromStart, and `feedback`, if non-nil, is called after each
+	// point is executed.
+	// This will block until done or a new operation cancels this one.
+	StreamTrajectory(
+		ctx context.Context,
+		points <-chan TrajectoryPoint,
+		feedback func(TrajectoryFeedback),
+		extra map[string]interface{},
+	) error
+}
+
+// TimeParameterize assigns times to a sequence of joint positions such that no joint moves faster
+// than `maxVelRads` between consecutive positions. The first position is reached at time zero. If
+// `maxVelRads` is not positive, DefaultTrajectoryMaxVelRads is used.
+func TimeParameterize(positions [][]referenceframe.Input, maxVelRads float64) []TrajectoryPoint {
+	if maxVelRads <= 0 {
+		maxVelRads = DefaultTrajectoryMaxVelRads
+	}
+	points := make([]TrajectoryPoint, 0, len(positions))
+	var elapsed time.Duration
+	for idx, pos := range positions {
+		if idx > 0 {
+			var maxDelta float64
+			for j := range pos {
+				if j < len(positions[idx-1]) {
+					maxDelta = math.Max(maxDelta, math.Abs(pos[j].Value-positions[idx-1][j].Value))
+				}
+			}
+			elapsed += time.Duration(maxDelta / maxVelRads * float64(time.Second))
+		}
+		points = append(points, TrajectoryPoint{Positions: pos, TimeFromStart: elapsed})
+	}
+	return points
+}
+
+// StreamTrajectoryWithMoves implements StreamTrajectory for arms that can only move to joint
+// positions. Each point is executed with `move` once its time has come, and feedback reports the
+// positions returned by `current`.
+func StreamTrajectoryWithMoves(
+	ctx context.Context,
+	points <-chan TrajectoryPoint,
+	feedback func(TrajectoryFeedback),
+	move func(ctx context.Context, positions []referenceframe.Input) error,
+	current func(ctx context.Context) ([]referenceframe.Input, error),
+) error {
+	start := time.Now()
+	idx := 0
+	for {
+		var point TrajectoryPoint
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case pt, ok := <-points:
+			if !ok {
+				return nil
+			}
+			point = pt
+		}
+		if wait := point.TimeFromStart - time.Since(start); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+		if err := move(ctx, point.Positions); err != nil {
+			return errors.Wrapf(err, "trajectory point %d", idx)
+		}
+		if feedback != nil {
+			positions, err := current(ctx)
+			if err != nil {
+				return err
+			}
+			feedback(TrajectoryFeedback{PointIndex: idx, TimeFromStart: time.Since(start), Positions: positions})
+		}
+		idx++
+	}
+}
+
+// StreamTrajectory sends points to the server in batches as they become available. Each batch's
+// times are made relative to the end of the previous batch.
+func (c *client) StreamTrajectory(
+	ctx context.Context,
+	points <-chan TrajectoryPoint,
+	feedback func(TrajectoryFeedback),
+	extra map[string]interface{},
+) error {
+	var (
+		sent       int
+		batchStart time.Duration
+		elapsed    time.Duration
+	)
+	for {
+		batch, open, err := nextTrajectoryBatch(ctx, points)
+		if err != nil {
+			return err
+		}
+		if len(batch) > 0 {
+			encoded := make([]interface{}, 0, len(batch))
+			for _, pt := range batch {
+				encoded = append(encoded, map[string]interface{}{
+					positionsKey:         referenceframe.InputsToFloats(pt.Positions),
+					timeFromStartSecsKey: (pt.TimeFromStart - batchStart).Seconds(),
+				})
+			}
+			resp, err := c.DoCommand(ctx, map[string]interface{}{
+				streamTrajectoryKey: map[string]interface{}{
+					pointsKey: encoded,
+					extraKey:  extra,
+				},
+			})
+			if err != nil {
+				return err
+			}
+			fbs, err := trajectoryFeedbackFromInterface(resp[feedbackKey])
+			if err != nil {
+				return err
+			}
+			for _, fb := range fbs {
+				if feedback != nil {
+					fb.PointIndex += sent
+					fb.TimeFromStart += elapsed
+					feedback(fb)
+				}
+			}
+			if len(fbs) > 0 {
+				elapsed += fbs[len(fbs)-1].TimeFromStart
+			}
+			sent += len(batch)
+			batchStart = batch[len(batch)-1].TimeFromStart
+		}
+		if !open {
+			return nil
+		}
+	}
+}
+
+// nextTrajectoryBatch blocks for the next point and then gathers any others that are immediately
+// available, up to trajectoryBatchSize. It returns false once `points` is closed.
+func nextTrajectoryBatch(ctx context.Context, points <-chan TrajectoryPoint) ([]TrajectoryPoint, bool, error) {
+	var batch []TrajectoryPoint
+	select {
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	case pt, ok := <-points:
+		if !ok {
+			return nil, false, nil
+		}
+		batch = append(batch, pt)
+	}
+	for len(batch) < trajectoryBatchSize {
+		select {
+		case pt, ok := <-points:
+			if !ok {
+				return batch, false, nil
+			}
+			batch = append(batch, pt)
+		default:
+			return batch, true, nil
+		}
+	}
+	return batch, true, nil
+}
+
+// doStreamTrajectoryCommand handles the reserved trajectory DoCommand key. It returns false if `cmd`
+// is not a trajectory command or the arm does not implement TrajectoryStreamer.
+func doStreamTrajectoryCommand(
+	ctx context.Context,
+	a Arm,
+	name string,
+	req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	streamer, ok := a.(TrajectoryStreamer)
+	if !ok {
+		return nil, false, nil
+	}
+	payload, ok := req.GetCommand().AsMap()[streamTrajectoryKey]
+	if !ok {
+		return nil, false, nil
+	}
+	operation.CancelOtherWithLabel(ctx, name)
+	args, ok := payload.(map[string]interface{})
+	if !ok {
+		return nil, true, errors.Errorf("%q must be an object", streamTrajectoryKey)
+	}
+	rawPoints, ok := args[pointsKey].([]interface{})
+	if !ok {
+		return nil, true, errors.Errorf("%q must be a list of points", pointsKey)
+	}
+	points := make(chan TrajectoryPoint, len(rawPoints))
+	for idx, rawPoint := range rawPoints {
+		point, ok := rawPoint.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("point %d must be an object", idx)
+		}
+		positions, err := floatsFromInterface(point[positionsKey], positionsKey)
+		if err != nil {
+			return nil, true, errors.Wrapf(err, "point %d", idx)
+		}
+		secs, ok := point[timeFromStartSecsKey].(float64)
+		if !ok || secs < 0 {
+			return nil, true, errors.Errorf("point %d must have a non-negative %q", idx, timeFromStartSecsKey)
+		}
+		points <- TrajectoryPoint{
+			Positions:     referenceframe.FloatsToInputs(positions),
+			TimeFromStart: time.Duration(secs * float64(time.Second)),
+		}
+	}
+	close(points)
+
+	feedback := []interface{}{}
+	extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+	err := streamer.StreamTrajectory(ctx, points, func(fb TrajectoryFeedback) {
+		feedback = append(feedback, map[string]interface{}{
+			pointIndexKey:        fb.PointIndex,
+			timeFromStartSecsKey: fb.TimeFromStart.Seconds(),
+			positionsKey:         referenceframe.InputsToFloats(fb.Positions),
+		})
+	}, extra)
+	if err != nil {
+		return nil, true, err
+	}
+	res, err := protoutils.StructToStructPb(map[string]interface{}{feedbackKey: feedback})
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
+
+func trajectoryFeedbackFromInterface(raw interface{}) ([]TrajectoryFeedback, error) {
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("%q must be a list", feedbackKey)
+	}
+	ret := make([]TrajectoryFeedback, 0, len(list))
+	for _, rawFb := range list {
+		fb, ok := rawFb.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%q entries must be objects", feedbackKey)
+		}
+		idx, _ := fb[pointIndexKey].(float64)         //nolint:errcheck
+		secs, _ := fb[timeFromStartSecsKey].(float64) //nolint:errcheck
+		positions, err := floatsFromInterface(fb[positionsKey], positionsKey)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, TrajectoryFeedback{
+			PointIndex:    int(idx),
+			TimeFromStart: time.Duration(secs * float64(time.Second)),
+			Positions:     referenceframe.FloatsToInputs(positions),
+		})
+	}
+	return ret, nil
+}
@@ -44,6 +44,9 @@ type Config struct {
 	SpeedDegsPerSec     float64 `json:"speed_degs_per_sec"`
 	Host                string  `json:"host"`
 	ArmHostedKinematics bool    `json:"arm_hosted_kinematics,omitempty"`
+
+	// KinematicsOverrides narrow the joint limits of the arm's kinematics. Motion planned for the arm stays within them.
+	KinematicsOverrides *referenceframe.KinematicsOverrides `json:"kinematics_overrides,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -54,6 +57,18 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 	if cfg.SpeedDegsPerSec > 180 || cfg.SpeedDegsPerSec < 3 {
 		return nil, errors.New("speed for universalrobots has to be between 3 and 180 degrees per second")
 	}
+	if cfg.KinematicsOverrides != nil {
+		if len(cfg.KinematicsOverrides.Home) > 0 {
+			return nil, resource.NewConfigValidationError(path, errors.New("universalrobots arms do not have a home position"))
+		}
+		model, err := MakeModelFrame("")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := cfg.KinematicsOverrides.Apply(model); err != nil {
+			return nil, resource.NewConfigValidationError(path, err)
+		}
+	}
 	return []string{}, nil
 }
 
@@ -174,6 +189,9 @@ func urArmConnect(ctx context.Context, conf resource.Config, logger logging.Logg
 	if err != nil {
 		return nil, err
 	}
+	if model, err = newConf.KinematicsOverrides.Apply(model); err != nil {
+		return nil, err
+	}
 
 	var d net.Dialer
 
@@ -0,0 +1,205 @@
+//go:build !no_cgo
+
+package arm
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+
+	"go.viam.com/rdk/operation"
+)
+
+// The arm proto has no velocity RPCs. Joint velocity commands are carried over DoCommand using the
+// following reserved keys. The server dispatches them to arms implementing JointVelocityController
+// and passes them through to the arm's own DoCommand otherwise.
+const (
+	setJointVelocitiesKey         = "set_joint_velocities"
+	moveThroughJointVelocitiesKey = "move_through_joint_velocities"
+	velocitiesKey                 = "velocities"
+	durationSecsKey               = "duration_secs"
+	segmentsKey                   = "segments"
+	extraKey                      = "extra"
+)
+
+// JointVelocitySegment is a set of joint velocities to hold for a duration.
+type JointVelocitySegment struct {
+	Velocities []float64
+	Duration   time.Duration
+}
+
+// JointVelocityController is implemented by arms that can command joint velocities directly, such as
+// arms driven by velocity-mode controllers or teleoperation. Velocities are given per joint in the
+// units of the arm's model per second: radians per second for revolute joints and millimeters per
+// second for prismatic joints.
+//
+// SetJointVelocities example:
+//
+//	myArm, err := arm.FromRobot(machine, "my_arm")
+//	if velArm, ok := myArm.(arm.JointVelocityController); ok {
+//		// Rotate the first joint at 0.1 radians per second.
+//		err = velArm.SetJointVelocities(context.Background(), []float64{0.1, 0, 0, 0, 0, 0}, nil)
+//	}
+type JointVelocityController interface {
+	// SetJointVelocities commands each joint to move at the given velocity. The arm keeps moving
+	// until a new velocity or position is commanded, or Stop is called.
+	SetJointVelocities(ctx context.Context, velocities []float64, extra map[string]interface{}) error
+
+	// MoveThroughJointVelocities holds each segment's velocities for the segment's duration, in order,
+	// and stops the arm afterwards.
+	// This will block until done or a new operation cancels this one.
+	MoveThroughJointVelocities(ctx context.Context, segments []JointVelocitySegment, extra map[string]interface{}) error
+}
+
+// MoveThroughJointVelocitiesWithSetter implements MoveThroughJointVelocities for arms that can only
+// set velocities. It calls `setVelocities` for each segment, waits out the segment's duration and
+// calls `stop` once all segments have run or the context is cancelled.
+func MoveThroughJointVelocitiesWithSetter(
+	ctx context.Context,
+	segments []JointVelocitySegment,
+	setVelocities func(ctx context.Context, velocities []float64) error,
+	stop func(ctx context.Context) error,
+) (err error) {
+	defer func() {
+		// Use a fresh context so the arm is stopped even when the move was cancelled.
+		if stopErr := stop(context.Background()); err == nil {
+			err = stopErr
+		}
+	}()
+	for _, segment := range segments {
+		if err := setVelocities(ctx, segment.Velocities); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(segment.Duration):
+		}
+	}
+	return nil
+}
+
+func (c *client) SetJointVelocities(ctx context.Context, velocities []float64, extra map[string]interface{}) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		setJointVelocitiesKey: map[string]interface{}{
+			velocitiesKey: velocities,
+			extraKey:      extra,
+		},
+	})
+	return err
+}
+
+func (c *client) MoveThroughJointVelocities(
+	ctx context.Context,
+	segments []JointVelocitySegment,
+	extra map[string]interface{},
+) error {
+	segs := make([]interface{}, 0, len(segments))
+	for _, segment := range segments {
+		segs = append(segs, map[string]interface{}{
+			velocitiesKey:   segment.Velocities,
+			durationSecsKey: segment.Duration.Seconds(),
+		})
+	}
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		moveThroughJointVelocitiesKey: map[string]interface{}{
+			segmentsKey: segs,
+			extraKey:    extra,
+		},
+	})
+	return err
+}
+
+// doJointVelocityCommand handles the reserved velocity DoCommand keys. It returns false if `cmd` is
+// not a velocity command or the arm does not implement JointVelocityController.
+func doJointVelocityCommand(
+	ctx context.Context,
+	a Arm,
+	name string,
+	req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	velArm, ok := a.(JointVelocityController)
+	if !ok {
+		return nil, false, nil
+	}
+	cmd := req.GetCommand().AsMap()
+	if payload, ok := cmd[setJointVelocitiesKey]; ok {
+		operation.CancelOtherWithLabel(ctx, name)
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", setJointVelocitiesKey)
+		}
+		velocities, err := floatsFromInterface(args[velocitiesKey], velocitiesKey)
+		if err != nil {
+			return nil, true, err
+		}
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		if err := velArm.SetJointVelocities(ctx, velocities, extra); err != nil {
+			return nil, true, err
+		}
+		return emptyDoCommandResponse(), true, nil
+	}
+	if payload, ok := cmd[moveThroughJointVelocitiesKey]; ok {
+		operation.CancelOtherWithLabel(ctx, name)
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", moveThroughJointVelocitiesKey)
+		}
+		rawSegments, ok := args[segmentsKey].([]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be a list of segments", segmentsKey)
+		}
+		segments := make([]JointVelocitySegment, 0, len(rawSegments))
+		for idx, rawSegment := range rawSegments {
+			segment, ok := rawSegment.(map[string]interface{})
+			if !ok {
+				return nil, true, errors.Errorf("segment %d must be an object", idx)
+			}
+			velocities, err := floatsFromInterface(segment[velocitiesKey], velocitiesKey)
+			if err != nil {
+				return nil, true, errors.Wrapf(err, "segment %d", idx)
+			}
+			secs, ok := segment[durationSecsKey].(float64)
+			if !ok || secs < 0 {
+				return nil, true, errors.Errorf("segment %d must have a non-negative %q", idx, durationSecsKey)
+			}
+			segments = append(segments, JointVelocitySegment{
+				Velocities: velocities,
+				Duration:   time.Duration(secs * float64(time.Second)),
+			})
+		}
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		if err := velArm.MoveThroughJointVelocities(ctx, segments, extra); err != nil {
+			return nil, true, err
+		}
+		return emptyDoCommandResponse(), true, nil
+	}
+	return nil, false, nil
+}
+
+func floatsFromInterface(raw interface{}, key string) ([]float64, error) {
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("%q must be a list of numbers", key)
+	}
+	ret := make([]float64, 0, len(list))
+	for _, val := range list {
+		f, ok := val.(float64)
+		if !ok {
+			return nil, errors.Errorf("%q must be a list of numbers, got %v", key, val)
+		}
+		ret = append(ret, f)
+	}
+	return ret, nil
+}
+
+func emptyDoCommandResponse() *commonpb.DoCommandResponse {
+	res, err := protoutils.StructToStructPb(map[string]interface{}{})
+	if err != nil {
+		return &commonpb.DoCommandResponse{}
+	}
+	return &commonpb.DoCommandResponse{Result: res}
+}
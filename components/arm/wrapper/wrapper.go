@@ -3,8 +3,6 @@ package wrapper
 
 import (
 	"context"
-	"errors"
-	"strings"
 	"sync"
 
 	"go.viam.com/rdk/components/arm"
@@ -31,7 +29,7 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 	if cfg.ArmName == "" {
 		return nil, resource.NewConfigValidationFieldRequiredError(path, "arm-name")
 	}
-	if _, err := modelFromPath(cfg.ModelFilePath, ""); err != nil {
+	if _, err := urdf.ParseModelFile(cfg.ModelFilePath, ""); err != nil {
 		return nil, err
 	}
 	deps = append(deps, cfg.ArmName)
@@ -77,7 +75,7 @@ func (wrapper *Arm) Reconfigure(ctx context.Context, deps resource.Dependencies,
 	if err != nil {
 		return err
 	}
-	model, err := modelFromPath(newConf.ModelFilePath, conf.Name)
+	model, err := urdf.ParseModelFile(newConf.ModelFilePath, conf.Name)
 	if err != nil {
 		return err
 	}
@@ -205,15 +203,3 @@ func (wrapper *Arm) Geometries(ctx context.Context, extra map[string]interface{}
 	}
 	return gif.Geometries(), nil
 }
-
-// modelFromPath returns a Model from a given path.
-func modelFromPath(modelPath, name string) (referenceframe.Model, error) {
-	switch {
-	case strings.HasSuffix(modelPath, ".urdf"):
-		return urdf.ParseModelXMLFile(modelPath, name)
-	case strings.HasSuffix(modelPath, ".json"):
-		return referenceframe.ParseModelJSONFile(modelPath, name)
-	default:
-		return nil, errors.New("only files with .json and .urdf file extensions are supported")
-	}
-}
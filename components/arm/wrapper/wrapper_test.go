@@ -8,6 +8,7 @@ import (
 
 	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe/urdf"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/testutils/inject"
 )
@@ -51,7 +52,7 @@ func TestReconfigure(t *testing.T) {
 	conf, err := resource.NativeConfig[*Config](cfg)
 	test.That(t, err, test.ShouldBeNil)
 
-	model, err := modelFromPath(conf.ModelFilePath, cfg.Name)
+	model, err := urdf.ParseModelFile(conf.ModelFilePath, cfg.Name)
 	test.That(t, err, test.ShouldBeNil)
 
 	actualArm := &inject.Arm{}
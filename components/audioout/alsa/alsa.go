@@ -0,0 +1,19 @@
+// Package alsa implements an audio output playing through an ALSA device on linux.
+package alsa
+
+import (
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("alsa_audio_out")
+
+// Config is used for converting config attributes.
+type Config struct {
+	// Device is the ALSA PCM device to play on, such as "hw:1,0". It defaults to "default".
+	Device string `json:"device,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,115 @@
+//go:build linux
+
+package alsa
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/audioout"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// aplay is the ALSA utility audio is played with, which takes care of negotiating the hardware
+// parameters and resampling with the device.
+var aplay = "aplay"
+
+func init() {
+	resource.RegisterComponent(audioout.API, model, resource.Registration[resource.Resource, *Config]{
+		Constructor: newAudioOut,
+	})
+}
+
+type audioOut struct {
+	resource.Named
+	resource.AlwaysRebuild
+	*audioout.Player
+}
+
+func newAudioOut(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (resource.Resource, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := exec.LookPath(aplay); err != nil {
+		return nil, errors.Wrap(err, "ALSA audio output requires aplay, which is part of alsa-utils")
+	}
+	device := newConf.Device
+	if device == "" {
+		device = "default"
+	}
+	return &audioOut{
+		Named: conf.ResourceName().AsNamed(),
+		Player: audioout.NewPlayer(func(ctx context.Context, format audioout.Format) (io.WriteCloser, error) {
+			return openDevice(ctx, device, format)
+		}, logger),
+	}, nil
+}
+
+// DoCommand handles the audio output commands.
+func (a *audioOut) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, handled, err := audioout.HandleDoCommand(ctx, a, cmd); handled {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+// Close stops playback.
+func (a *audioOut) Close(ctx context.Context) error {
+	a.Player.Close()
+	return nil
+}
+
+// device is an aplay process playing the raw samples written to it. Cancelling the context it was
+// opened with kills the process, stopping playback at once.
+type device struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr bytes.Buffer
+}
+
+func openDevice(ctx context.Context, name string, format audioout.Format) (*device, error) {
+	//nolint:gosec
+	cmd := exec.CommandContext(ctx, aplay,
+		"-q",
+		"-D", name,
+		"-t", "raw",
+		"-f", "S16_LE",
+		"-r", strconv.Itoa(format.SampleRate),
+		"-c", strconv.Itoa(format.Channels),
+	)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	d := &device{cmd: cmd, stdin: stdin}
+	cmd.Stderr = &d.stderr
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "starting %s", aplay)
+	}
+	return d, nil
+}
+
+func (d *device) Write(data []byte) (int, error) {
+	return d.stdin.Write(data)
+}
+
+// Close waits for the written audio to play.
+func (d *device) Close() error {
+	closeErr := d.stdin.Close()
+	if err := d.cmd.Wait(); err != nil {
+		if d.cmd.ProcessState != nil && !d.cmd.ProcessState.Exited() {
+			// killed by stopping playback
+			return nil
+		}
+		return errors.Wrapf(err, "%s: %s", aplay, strings.TrimSpace(d.stderr.String()))
+	}
+	return closeErr
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package alsa
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/audioout"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func init() {
+	resource.RegisterComponent(audioout.API, model, resource.Registration[resource.Resource, *Config]{
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (resource.Resource, error) {
+			return nil, errors.New("ALSA audio output is only supported on linux")
+		},
+	})
+}
@@ -0,0 +1,146 @@
+// Package audioout defines an audio output device, such as a speaker, that plays PCM audio.
+//
+// There is no audio output proto, so audio outputs are generic components whose typed methods
+// are carried over DoCommand. Models implement AudioOut and answer the reserved commands by
+// calling HandleDoCommand from their DoCommand.
+package audioout
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+)
+
+// API is the resource API audio outputs are served under.
+var API = generic.API
+
+// Named is a helper for getting the named audio output's typed resource name.
+func Named(name string) resource.Name {
+	return resource.NewName(API, name)
+}
+
+// Format describes interleaved signed 16-bit PCM samples.
+type Format struct {
+	SampleRate int
+	Channels   int
+}
+
+// Validate ensures the format can be played.
+func (f Format) Validate() error {
+	if f.SampleRate <= 0 {
+		return errors.New("sample rate must be positive")
+	}
+	if f.Channels <= 0 {
+		return errors.New("channels must be positive")
+	}
+	return nil
+}
+
+// A Clip is a piece of audio to play.
+type Clip struct {
+	Format Format
+	// Samples are interleaved signed 16-bit PCM samples, one per channel per frame.
+	Samples []int16
+}
+
+// Validate ensures the clip can be played.
+func (c Clip) Validate() error {
+	if err := c.Format.Validate(); err != nil {
+		return err
+	}
+	if len(c.Samples)%c.Format.Channels != 0 {
+		return errors.Errorf("%d samples is not a whole number of %d channel frames", len(c.Samples), c.Format.Channels)
+	}
+	return nil
+}
+
+// Duration returns how long the clip takes to play.
+func (c Clip) Duration() time.Duration {
+	if c.Format.SampleRate <= 0 || c.Format.Channels <= 0 {
+		return 0
+	}
+	frames := len(c.Samples) / c.Format.Channels
+	return time.Duration(frames) * time.Second / time.Duration(c.Format.SampleRate)
+}
+
+// Status is the playback state of an audio output.
+type Status struct {
+	// Playing is whether audio is being played.
+	Playing bool
+	// Queued is how much audio remains to be played, including the clip being played.
+	Queued time.Duration
+	// Volume is the playback volume, from 0 to 1.
+	Volume float64
+}
+
+// An AudioOut is a device that plays audio.
+//
+// Play example:
+//
+//	mySpeaker, err := audioout.FromRobot(machine, "my_speaker")
+//	f, err := os.Open("alert.wav")
+//	clip, err := audioout.DecodeWAV(f)
+//	err = mySpeaker.Play(context.Background(), clip, nil)
+//
+// Streaming audio is played by calling Play with consecutive chunks, which are queued:
+//
+//	for chunk := range speech {
+//		err = mySpeaker.Play(context.Background(), audioout.Clip{Format: format, Samples: chunk}, nil)
+//	}
+//
+// SetVolume example:
+//
+//	err = mySpeaker.SetVolume(context.Background(), 0.5, nil)
+//
+// Stop example:
+//
+//	err = mySpeaker.Stop(context.Background(), nil)
+type AudioOut interface {
+	resource.Resource
+
+	// Play queues a clip to be played after any audio already queued. It returns once the clip is
+	// queued, not once it has been played.
+	Play(ctx context.Context, clip Clip, extra map[string]interface{}) error
+
+	// Stop stops playback and discards the queued audio.
+	Stop(ctx context.Context, extra map[string]interface{}) error
+
+	// SetVolume sets the playback volume, from 0 to 1.
+	SetVolume(ctx context.Context, volume float64, extra map[string]interface{}) error
+
+	// Status returns the playback state.
+	Status(ctx context.Context, extra map[string]interface{}) (Status, error)
+}
+
+// FromResource returns `res` as an AudioOut. Resources that do not implement AudioOut, such as the
+// clients of remote audio outputs, are wrapped in a client that calls them over DoCommand.
+func FromResource(res resource.Resource) AudioOut {
+	if ao, ok := res.(AudioOut); ok {
+		return ao
+	}
+	return NewClientFromResource(res)
+}
+
+// FromDependencies is a helper for getting the named audio output from a collection of
+// dependencies.
+func FromDependencies(deps resource.Dependencies, name string) (AudioOut, error) {
+	res, err := generic.FromDependencies(deps, name)
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
+
+// FromRobot is a helper for getting the named audio output from the given Robot.
+func FromRobot(r robot.Robot, name string) (AudioOut, error) {
+	res, err := generic.FromRobot(r, name)
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
@@ -0,0 +1,159 @@
+package audioout_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/audioout"
+	"go.viam.com/rdk/components/audioout/fake"
+	"go.viam.com/rdk/components/generic"
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func makeWAV(channels, sampleRate, bitsPerSample int, data []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(4+8+16+8+8+len(data)+len(data)%2))
+	buf.WriteString("WAVE")
+	// an unknown chunk to skip, with an odd size to check the padding is skipped too
+	buf.WriteString("LIST")
+	binary.Write(&buf, binary.LittleEndian, uint32(3))
+	buf.Write([]byte{1, 2, 3, 0})
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*channels*bitsPerSample/8))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels*bitsPerSample/8))
+	binary.Write(&buf, binary.LittleEndian, uint16(bitsPerSample))
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func TestDecodeWAV(t *testing.T) {
+	t.Run("16-bit stereo", func(t *testing.T) {
+		wav := makeWAV(2, 44100, 16, []byte{0x01, 0x00, 0xff, 0xff, 0x00, 0x80, 0xff, 0x7f})
+		clip, err := audioout.DecodeWAV(bytes.NewReader(wav))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, clip.Format, test.ShouldResemble, audioout.Format{SampleRate: 44100, Channels: 2})
+		test.That(t, clip.Samples, test.ShouldResemble, []int16{1, -1, -32768, 32767})
+	})
+
+	t.Run("8-bit mono", func(t *testing.T) {
+		wav := makeWAV(1, 8000, 8, []byte{128, 0, 255})
+		clip, err := audioout.DecodeWAV(bytes.NewReader(wav))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, clip.Format, test.ShouldResemble, audioout.Format{SampleRate: 8000, Channels: 1})
+		test.That(t, clip.Samples, test.ShouldResemble, []int16{0, -32768, 127 << 8})
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := audioout.DecodeWAV(bytes.NewReader([]byte("RIFF\x00\x00\x00\x00AVI ")))
+		test.That(t, err, test.ShouldBeError, "not a WAV file")
+
+		_, err = audioout.DecodeWAV(bytes.NewReader(makeWAV(1, 8000, 24, []byte{0, 0, 0})))
+		test.That(t, err, test.ShouldBeError, "unsupported WAV sample size of 24 bits")
+
+		_, err = audioout.DecodeWAV(bytes.NewReader(makeWAV(2, 8000, 16, []byte{0, 0})))
+		test.That(t, err, test.ShouldBeError, "1 samples is not a whole number of 2 channel frames")
+	})
+}
+
+func TestClip(t *testing.T) {
+	clip := audioout.Clip{Format: audioout.Format{SampleRate: 8000, Channels: 2}, Samples: make([]int16, 8000)}
+	test.That(t, clip.Validate(), test.ShouldBeNil)
+	test.That(t, clip.Duration(), test.ShouldEqual, 500*time.Millisecond)
+
+	clip.Format.SampleRate = 0
+	test.That(t, clip.Validate(), test.ShouldBeError, "sample rate must be positive")
+}
+
+func TestClient(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	speaker := fake.NewAudioOut(audioout.Named("speaker"), logger)
+	defer speaker.Close(context.Background())
+	coll, err := resource.NewAPIResourceCollection(generic.API, map[resource.Name]resource.Resource{
+		audioout.Named("speaker"): speaker,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[resource.Resource](generic.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, coll), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	res, err := generic.NewClientFromConn(context.Background(), conn, "", audioout.Named("speaker"), logger)
+	test.That(t, err, test.ShouldBeNil)
+	client := audioout.FromResource(res)
+
+	t.Run("play", func(t *testing.T) {
+		test.That(t, client.SetVolume(context.Background(), 0.5, nil), test.ShouldBeNil)
+		test.That(t, client.SetVolume(context.Background(), 2, nil), test.ShouldBeError, "volume must be between 0 and 1")
+
+		samples := make([]int16, 1600)
+		for i := range samples {
+			samples[i] = int16(i * 10)
+		}
+		format := audioout.Format{SampleRate: 8000, Channels: 2}
+		test.That(t, client.Play(context.Background(), audioout.Clip{Format: format, Samples: samples[:800]}, nil), test.ShouldBeNil)
+		test.That(t, client.Play(context.Background(), audioout.Clip{Format: format, Samples: samples[800:]}, nil), test.ShouldBeNil)
+
+		status, err := client.Status(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, status.Playing, test.ShouldBeTrue)
+		test.That(t, status.Queued, test.ShouldBeGreaterThan, 0)
+		test.That(t, status.Queued, test.ShouldBeLessThanOrEqualTo, 100*time.Millisecond)
+		test.That(t, status.Volume, test.ShouldEqual, 0.5)
+
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			status, err := client.Status(context.Background(), nil)
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, status.Playing, test.ShouldBeFalse)
+			test.That(tb, len(speaker.Played()), test.ShouldEqual, len(samples))
+		})
+		played := speaker.Played()
+		for i, s := range samples {
+			test.That(t, played[i], test.ShouldEqual, s/2)
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		long := audioout.Clip{Format: audioout.Format{SampleRate: 8000, Channels: 1}, Samples: make([]int16, 80000)}
+		test.That(t, client.Play(context.Background(), long, nil), test.ShouldBeNil)
+		test.That(t, client.Stop(context.Background(), nil), test.ShouldBeNil)
+		status, err := client.Status(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, status.Playing, test.ShouldBeFalse)
+		test.That(t, status.Queued, test.ShouldEqual, 0)
+	})
+
+	t.Run("invalid clip", func(t *testing.T) {
+		clip := audioout.Clip{Format: audioout.Format{SampleRate: 8000, Channels: 2}, Samples: make([]int16, 3)}
+		test.That(t, client.Play(context.Background(), clip, nil), test.ShouldBeError,
+			"3 samples is not a whole number of 2 channel frames")
+	})
+}
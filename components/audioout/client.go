@@ -0,0 +1,160 @@
+package audioout
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The audio output commands are carried over DoCommand using the following reserved keys.
+const (
+	playKey       = "play"
+	sampleRateKey = "sample_rate"
+	channelsKey   = "channels"
+	pcmKey        = "pcm"
+	stopKey       = "stop"
+	setVolumeKey  = "set_volume"
+	volumeKey     = "volume"
+	getStatusKey  = "get_status"
+	playingKey    = "playing"
+	queuedSecsKey = "queued_secs"
+	extraKey      = "extra"
+)
+
+// client implements AudioOut over the DoCommand of a resource that does not implement it, such as
+// the generic client of a remote audio output.
+type client struct {
+	resource.Resource
+}
+
+// NewClientFromResource returns an AudioOut calling `res` over DoCommand.
+func NewClientFromResource(res resource.Resource) AudioOut {
+	return &client{Resource: res}
+}
+
+func (c *client) Play(ctx context.Context, clip Clip, extra map[string]interface{}) error {
+	if err := clip.Validate(); err != nil {
+		return err
+	}
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		playKey: map[string]interface{}{
+			sampleRateKey: float64(clip.Format.SampleRate),
+			channelsKey:   float64(clip.Format.Channels),
+			pcmKey:        base64.StdEncoding.EncodeToString(encodeSamples(clip.Samples)),
+			extraKey:      extra,
+		},
+	})
+	return err
+}
+
+func (c *client) Stop(ctx context.Context, extra map[string]interface{}) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		stopKey: map[string]interface{}{extraKey: extra},
+	})
+	return err
+}
+
+func (c *client) SetVolume(ctx context.Context, volume float64, extra map[string]interface{}) error {
+	if err := validateVolume(volume); err != nil {
+		return err
+	}
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		setVolumeKey: map[string]interface{}{volumeKey: volume, extraKey: extra},
+	})
+	return err
+}
+
+func (c *client) Status(ctx context.Context, extra map[string]interface{}) (Status, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		getStatusKey: map[string]interface{}{extraKey: extra},
+	})
+	if err != nil {
+		return Status{}, err
+	}
+	playing, ok := resp[playingKey].(bool)
+	if !ok {
+		return Status{}, errors.Errorf("expected %q in response, got %v", playingKey, resp)
+	}
+	queued, _ := resp[queuedSecsKey].(float64) //nolint:errcheck
+	volume, _ := resp[volumeKey].(float64)     //nolint:errcheck
+	return Status{
+		Playing: playing,
+		Queued:  time.Duration(queued * float64(time.Second)),
+		Volume:  volume,
+	}, nil
+}
+
+// HandleDoCommand handles the reserved audio output DoCommand keys. Models call it first from their
+// DoCommand, and handle `cmd` themselves if it returns false.
+func HandleDoCommand(ctx context.Context, ao AudioOut, cmd map[string]interface{}) (map[string]interface{}, bool, error) {
+	if payload, ok := cmd[playKey]; ok {
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", playKey)
+		}
+		rate, _ := args[sampleRateKey].(float64)            //nolint:errcheck
+		channels, _ := args[channelsKey].(float64)          //nolint:errcheck
+		encoded, _ := args[pcmKey].(string)                 //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		pcm, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, true, errors.Wrapf(err, "decoding %q", pcmKey)
+		}
+		if len(pcm)%2 != 0 {
+			return nil, true, errors.Errorf("%q must hold 16-bit samples", pcmKey)
+		}
+		clip := Clip{
+			Format:  Format{SampleRate: int(rate), Channels: int(channels)},
+			Samples: decodeSamples(pcm),
+		}
+		if err := clip.Validate(); err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{}, true, ao.Play(ctx, clip, extra)
+	}
+	if payload, ok := cmd[stopKey]; ok {
+		args, _ := payload.(map[string]interface{})         //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		return map[string]interface{}{}, true, ao.Stop(ctx, extra)
+	}
+	if payload, ok := cmd[setVolumeKey]; ok {
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", setVolumeKey)
+		}
+		volume, ok := args[volumeKey].(float64)
+		if !ok {
+			return nil, true, errors.Errorf("%q must be a number", volumeKey)
+		}
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		if err := validateVolume(volume); err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{}, true, ao.SetVolume(ctx, volume, extra)
+	}
+	if payload, ok := cmd[getStatusKey]; ok {
+		args, _ := payload.(map[string]interface{})         //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		status, err := ao.Status(ctx, extra)
+		if err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{
+			playingKey:    status.Playing,
+			queuedSecsKey: status.Queued.Seconds(),
+			volumeKey:     status.Volume,
+		}, true, nil
+	}
+	return nil, false, nil
+}
+
+func validateVolume(volume float64) error {
+	if volume < 0 || volume > 1 {
+		return errors.New("volume must be between 0 and 1")
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Package fake implements a fake audio output that plays audio in real time without a sound device.
+package fake
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/components/audioout"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("fake_audio_out")
+
+func init() {
+	resource.RegisterComponent(
+		audioout.API,
+		model,
+		resource.Registration[resource.Resource, resource.NoNativeConfig]{
+			Constructor: func(
+				ctx context.Context,
+				_ resource.Dependencies,
+				conf resource.Config,
+				logger logging.Logger,
+			) (resource.Resource, error) {
+				return NewAudioOut(conf.ResourceName(), logger), nil
+			},
+		})
+}
+
+// AudioOut is a fake audio output that takes as long as real playback and records what it played.
+type AudioOut struct {
+	resource.Named
+	resource.AlwaysRebuild
+	*audioout.Player
+
+	mu     sync.Mutex
+	played []int16
+}
+
+// NewAudioOut returns a fake audio output.
+func NewAudioOut(name resource.Name, logger logging.Logger) *AudioOut {
+	a := &AudioOut{Named: name.AsNamed()}
+	a.Player = audioout.NewPlayer(func(ctx context.Context, format audioout.Format) (io.WriteCloser, error) {
+		return &output{audioOut: a, format: format}, nil
+	}, logger)
+	return a
+}
+
+// Played returns the samples played so far, scaled by the volume.
+func (a *AudioOut) Played() []int16 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]int16(nil), a.played...)
+}
+
+// DoCommand handles the audio output commands.
+func (a *AudioOut) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, handled, err := audioout.HandleDoCommand(ctx, a, cmd); handled {
+		return resp, err
+	}
+	return map[string]interface{}{}, nil
+}
+
+// Close stops playback.
+func (a *AudioOut) Close(ctx context.Context) error {
+	a.Player.Close()
+	return nil
+}
+
+// output takes as long to write samples as playing them would.
+type output struct {
+	audioOut *AudioOut
+	format   audioout.Format
+}
+
+func (o *output) Write(data []byte) (int, error) {
+	samples := len(data) / 2
+	frames := samples / o.format.Channels
+	time.Sleep(time.Duration(frames) * time.Second / time.Duration(o.format.SampleRate))
+	o.audioOut.mu.Lock()
+	defer o.audioOut.mu.Unlock()
+	for i := 0; i+1 < len(data); i += 2 {
+		o.audioOut.played = append(o.audioOut.played, int16(uint16(data[i])|uint16(data[i+1])<<8))
+	}
+	return len(data), nil
+}
+
+func (o *output) Close() error {
+	return nil
+}
@@ -0,0 +1,204 @@
+package audioout
+
+import (
+	"context"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/logging"
+)
+
+// playChunk is how much audio the player writes at once, bounding how long Stop takes.
+const playChunk = 50 * time.Millisecond
+
+// OpenFunc opens an output that plays samples of the given format as they are written. Writes
+// should block while the output's buffer is full, and Close should return once the written audio
+// has played. The output must stop playing at once when `ctx` is cancelled, which happens when
+// playback is stopped.
+type OpenFunc func(ctx context.Context, format Format) (io.WriteCloser, error)
+
+// A Player plays queued clips on an output. Audio output models embed it to implement Play, Stop,
+// SetVolume and Status.
+type Player struct {
+	open   OpenFunc
+	logger logging.Logger
+
+	mu      sync.Mutex
+	queue   []Clip
+	current Clip
+	pos     int
+	volume  float64
+	output  io.WriteCloser
+	format  Format
+	wake    chan struct{}
+	// stopped cancels the writes to the output when Stop is called.
+	stopped    context.Context
+	stopOutput context.CancelFunc
+
+	workers *goutils.StoppableWorkers
+}
+
+// NewPlayer returns a player at full volume writing to outputs opened by `open`. Close must be
+// called when the audio output is closed.
+func NewPlayer(open OpenFunc, logger logging.Logger) *Player {
+	p := &Player{
+		open:   open,
+		logger: logger,
+		volume: 1,
+		wake:   make(chan struct{}, 1),
+	}
+	p.stopped, p.stopOutput = context.WithCancel(context.Background())
+	p.workers = goutils.NewBackgroundStoppableWorkers(p.run)
+	return p
+}
+
+// Play queues a clip to be played after any audio already queued.
+func (p *Player) Play(ctx context.Context, clip Clip, extra map[string]interface{}) error {
+	if err := clip.Validate(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.queue = append(p.queue, clip)
+	p.mu.Unlock()
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Stop stops playback and discards the queued audio.
+func (p *Player) Stop(ctx context.Context, extra map[string]interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queue = nil
+	p.current = Clip{}
+	p.pos = 0
+	p.stopOutput()
+	p.stopped, p.stopOutput = context.WithCancel(context.Background())
+	p.closeOutput()
+	return nil
+}
+
+// SetVolume sets the playback volume, from 0 to 1.
+func (p *Player) SetVolume(ctx context.Context, volume float64, extra map[string]interface{}) error {
+	if err := validateVolume(volume); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.volume = volume
+	return nil
+}
+
+// Status returns the playback state.
+func (p *Player) Status(ctx context.Context, extra map[string]interface{}) (Status, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	queued := Clip{Format: p.current.Format, Samples: p.current.Samples[p.pos:]}.Duration()
+	for _, clip := range p.queue {
+		queued += clip.Duration()
+	}
+	return Status{
+		Playing: len(p.current.Samples) > p.pos || len(p.queue) > 0,
+		Queued:  queued,
+		Volume:  p.volume,
+	}, nil
+}
+
+// Close stops playback and closes the output.
+func (p *Player) Close() {
+	p.mu.Lock()
+	p.stopOutput()
+	p.mu.Unlock()
+	p.workers.Stop()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closeOutput()
+}
+
+func (p *Player) run(ctx context.Context) {
+	for {
+		chunk, output, stopped, ok := p.next(ctx)
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.wake:
+			}
+			continue
+		}
+		if err := writeSamples(stopped, output, chunk); err != nil && stopped.Err() == nil && ctx.Err() == nil {
+			p.logger.CWarnw(ctx, "error playing audio, discarding the queued audio", "error", err)
+			goutils.UncheckedError(p.Stop(ctx, nil))
+		}
+	}
+}
+
+// next returns the next chunk of samples to play, scaled by the volume, and the output to play it
+// on. It returns false if nothing is queued.
+func (p *Player) next(ctx context.Context) ([]int16, io.Writer, context.Context, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for p.pos >= len(p.current.Samples) {
+		if len(p.queue) == 0 {
+			// let the output finish playing rather than keep it open while idle
+			p.current, p.pos = Clip{}, 0
+			p.closeOutput()
+			return nil, nil, nil, false
+		}
+		p.current, p.pos = p.queue[0], 0
+		p.queue = p.queue[1:]
+	}
+	format := p.current.Format
+	if p.output == nil || p.format != format {
+		p.closeOutput()
+		output, err := p.open(p.stopped, format)
+		if err != nil {
+			p.logger.CWarnw(ctx, "error opening audio output, discarding the queued audio", "error", err)
+			p.queue, p.current, p.pos = nil, Clip{}, 0
+			return nil, nil, nil, false
+		}
+		p.output, p.format = output, format
+	}
+
+	n := int(playChunk.Seconds()*float64(format.SampleRate)) * format.Channels
+	end := min(p.pos+max(n, format.Channels), len(p.current.Samples))
+	chunk := make([]int16, end-p.pos)
+	for i, s := range p.current.Samples[p.pos:end] {
+		chunk[i] = int16(math.Round(float64(s) * p.volume))
+	}
+	p.pos = end
+	return chunk, p.output, p.stopped, true
+}
+
+// closeOutput must be called with p.mu held.
+func (p *Player) closeOutput() {
+	if p.output == nil {
+		return
+	}
+	if err := p.output.Close(); err != nil {
+		p.logger.Debugw("error closing audio output", "error", err)
+	}
+	p.output = nil
+}
+
+// writeSamples writes the samples to the output unless `ctx` is done first, in which case the
+// write is abandoned; stopping closes the output, which unblocks it.
+func writeSamples(ctx context.Context, output io.Writer, samples []int16) error {
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := output.Write(encodeSamples(samples))
+		errCh <- err
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errCh:
+		return err
+	}
+}
@@ -0,0 +1,8 @@
+// Package register registers all relevant audio outputs.
+package register
+
+import (
+	// register audio outputs.
+	_ "go.viam.com/rdk/components/audioout/alsa"
+	_ "go.viam.com/rdk/components/audioout/fake"
+)
@@ -0,0 +1,102 @@
+package audioout
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const wavFormatPCM = 1
+
+// DecodeWAV decodes an uncompressed 8 or 16-bit PCM WAV file into a clip.
+func DecodeWAV(r io.Reader) (Clip, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return Clip{}, errors.Wrap(err, "reading WAV header")
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return Clip{}, errors.New("not a WAV file")
+	}
+
+	var clip Clip
+	var bitsPerSample uint16
+	haveFormat := false
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return Clip{}, errors.New("WAV file has no data chunk")
+			}
+			return Clip{}, errors.Wrap(err, "reading WAV chunk")
+		}
+		id := string(chunk[0:4])
+		size := binary.LittleEndian.Uint32(chunk[4:8])
+		// chunks are padded to an even size
+		padded := int64(size) + int64(size%2)
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return Clip{}, errors.New("WAV format chunk is too short")
+			}
+			fmtChunk := make([]byte, padded)
+			if _, err := io.ReadFull(r, fmtChunk); err != nil {
+				return Clip{}, errors.Wrap(err, "reading WAV format")
+			}
+			if audioFormat := binary.LittleEndian.Uint16(fmtChunk[0:2]); audioFormat != wavFormatPCM {
+				return Clip{}, errors.Errorf("unsupported WAV encoding %d, only PCM is supported", audioFormat)
+			}
+			clip.Format.Channels = int(binary.LittleEndian.Uint16(fmtChunk[2:4]))
+			clip.Format.SampleRate = int(binary.LittleEndian.Uint32(fmtChunk[4:8]))
+			bitsPerSample = binary.LittleEndian.Uint16(fmtChunk[14:16])
+			if bitsPerSample != 8 && bitsPerSample != 16 {
+				return Clip{}, errors.Errorf("unsupported WAV sample size of %d bits", bitsPerSample)
+			}
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return Clip{}, errors.New("WAV data chunk precedes its format chunk")
+			}
+			data := make([]byte, size)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return Clip{}, errors.Wrap(err, "reading WAV data")
+			}
+			if bitsPerSample == 8 {
+				// 8-bit samples are unsigned
+				clip.Samples = make([]int16, len(data))
+				for i, b := range data {
+					clip.Samples[i] = (int16(b) - 128) << 8
+				}
+			} else {
+				clip.Samples = decodeSamples(data)
+			}
+			if err := clip.Validate(); err != nil {
+				return Clip{}, err
+			}
+			return clip, nil
+		default:
+			if _, err := io.CopyN(io.Discard, r, padded); err != nil {
+				return Clip{}, errors.Wrapf(err, "skipping WAV %q chunk", id)
+			}
+		}
+	}
+}
+
+// encodeSamples encodes samples as little endian bytes.
+func encodeSamples(samples []int16) []byte {
+	data := make([]byte, 2*len(samples))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(data[2*i:], uint16(s))
+	}
+	return data
+}
+
+// decodeSamples decodes little endian bytes into samples, ignoring a trailing odd byte.
+func decodeSamples(data []byte) []int16 {
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[2*i:]))
+	}
+	return samples
+}
@@ -3,20 +3,27 @@ package fake
 
 import (
 	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/spatialmath"
+	rutils "go.viam.com/rdk/utils"
 )
 
 func init() {
 	resource.RegisterComponent(
 		base.API,
 		resource.DefaultModelFamily.WithModel("fake"),
-		resource.Registration[base.Base, resource.NoNativeConfig]{Constructor: NewBase},
+		resource.Registration[base.Base, *Config]{Constructor: NewBase},
 	)
 }
 
@@ -24,22 +31,66 @@ const (
 	defaultWidthMm               = 600
 	defaultMinimumTurningRadiusM = 0
 	defaultWheelCircumferenceM   = 3
+
+	defaultMaxSpeedMmPerSec       = 300
+	defaultMaxAngularDegsPerSec   = 90
+	simulationIntegrationInterval = 10 * time.Millisecond
 )
 
+// Config is used for converting config attributes.
+type Config struct {
+	// Simulate makes the fake base simulate its motion instead of doing nothing: moves take time,
+	// during which the base is moving, and the base tracks its odometry, such that it can be
+	// localized by a base-odometry movement sensor.
+	Simulate bool `json:"simulate,omitempty"`
+	// MaxSpeedMmPerSec and MaxAngularDegsPerSec are the velocities of full power.
+	MaxSpeedMmPerSec     float64 `json:"max-speed-mm-per-sec,omitempty"`
+	MaxAngularDegsPerSec float64 `json:"max-angular-degs-per-sec,omitempty"`
+	// MaxLinearAccelMmPerSec2 and MaxAngularAccelDegsPerSec2 limit how fast the base speeds up and
+	// slows down. Zero means no limit.
+	MaxLinearAccelMmPerSec2    float64 `json:"max-linear-accel-mm-per-sec-per-sec,omitempty"`
+	MaxAngularAccelDegsPerSec2 float64 `json:"max-angular-accel-degs-per-sec-per-sec,omitempty"`
+	// WheelSlipNoise is the standard deviation of the fraction of each commanded motion the base
+	// does not actually make, as if its wheels slipped. Odometry reports the motion the base
+	// actually makes.
+	WheelSlipNoise float64 `json:"wheel-slip-noise,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	for name, value := range map[string]float64{
+		"max-speed-mm-per-sec":                   conf.MaxSpeedMmPerSec,
+		"max-angular-degs-per-sec":               conf.MaxAngularDegsPerSec,
+		"max-linear-accel-mm-per-sec-per-sec":    conf.MaxLinearAccelMmPerSec2,
+		"max-angular-accel-degs-per-sec-per-sec": conf.MaxAngularAccelDegsPerSec2,
+	} {
+		if value < 0 {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf("%s cannot be negative", name))
+		}
+	}
+	if conf.WheelSlipNoise < 0 || conf.WheelSlipNoise >= 1 {
+		return nil, resource.NewConfigValidationError(path, errors.New("wheel-slip-noise must be at least 0 and less than 1"))
+	}
+	return nil, nil
+}
+
 // Base is a fake base that returns what it was provided in each method.
 type Base struct {
 	resource.Named
-	resource.TriviallyReconfigurable
+	resource.AlwaysRebuild
 	CloseCount               int
 	WidthMeters              float64
 	TurningRadius            float64
 	WheelCircumferenceMeters float64
 	Geometry                 []spatialmath.Geometry
 	logger                   logging.Logger
+
+	// sim is the simulation of the base's motion, if it simulates it.
+	sim *simulation
 }
 
 // NewBase instantiates a new base of the fake model type.
-func NewBase(_ context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (base.Base, error) {
+func NewBase(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (base.Base, error) {
 	b := &Base{
 		Named:    conf.ResourceName().AsNamed(),
 		Geometry: []spatialmath.Geometry{},
@@ -54,42 +105,102 @@ func NewBase(_ context.Context, _ resource.Dependencies, conf resource.Config, l
 	}
 	b.WidthMeters = defaultWidthMm * 0.001
 	b.TurningRadius = defaultMinimumTurningRadiusM
+	if conf.ConvertedAttributes != nil {
+		newConf, err := resource.NativeConfig[*Config](conf)
+		if err != nil {
+			return nil, err
+		}
+		if newConf.Simulate {
+			b.sim = newSimulation(newConf, rutils.ClockFromContext(ctx))
+		}
+	}
 	return b, nil
 }
 
-// MoveStraight does nothing.
+// MoveStraight does nothing, or moves the simulated base straight, returning once it has moved.
 func (b *Base) MoveStraight(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
-	return nil
+	if b.sim == nil {
+		return nil
+	}
+	if mmPerSec == 0 {
+		return errors.New("cannot move straight at 0 mm/s")
+	}
+	// moving backwards at a negative speed moves forwards
+	distance := float64(distanceMm) * math.Copysign(1, mmPerSec)
+	return b.sim.makeMove(ctx, distance, 0, math.Abs(mmPerSec), b.sim.linearAccel)
 }
 
-// Spin does nothing.
+// Spin does nothing, or spins the simulated base, returning once it has spun.
 func (b *Base) Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
-	return nil
+	if b.sim == nil {
+		return nil
+	}
+	if degsPerSec == 0 {
+		return errors.New("cannot spin at 0 degs/s")
+	}
+	angle := rutils.DegToRad(angleDeg) * math.Copysign(1, degsPerSec)
+	return b.sim.makeMove(ctx, 0, angle, rutils.DegToRad(math.Abs(degsPerSec)), b.sim.angularAccel)
 }
 
-// SetPower does nothing.
+// SetPower does nothing, or drives the simulated base at the given fractions of its maximum
+// velocities.
 func (b *Base) SetPower(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	if b.sim == nil {
+		return nil
+	}
+	b.sim.setVelocity(linear.Y*b.sim.maxSpeed, angular.Z*b.sim.maxAngular)
 	return nil
 }
 
-// SetVelocity does nothing.
+// SetVelocity does nothing, or drives the simulated base at the given velocities.
 func (b *Base) SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	if b.sim == nil {
+		return nil
+	}
+	b.sim.setVelocity(linear.Y, rutils.DegToRad(angular.Z))
 	return nil
 }
 
-// Stop does nothing.
+// Stop does nothing, or stops the simulated base where it is.
 func (b *Base) Stop(ctx context.Context, extra map[string]interface{}) error {
+	if b.sim == nil {
+		return nil
+	}
+	b.sim.stop()
 	return nil
 }
 
-// IsMoving always returns false.
+// IsMoving returns whether the simulated base is moving, and otherwise false.
 func (b *Base) IsMoving(ctx context.Context) (bool, error) {
-	return false, nil
+	if b.sim == nil {
+		return false, nil
+	}
+	return b.sim.isMoving(), nil
+}
+
+// Odometry returns how the simulated base has moved since its odometry was last reset.
+func (b *Base) Odometry(ctx context.Context, extra map[string]interface{}) (base.Odometry, error) {
+	if b.sim == nil {
+		return base.Odometry{}, errors.New("fake base only tracks its odometry when it is simulated")
+	}
+	return b.sim.odometry(), nil
+}
+
+// ResetOdometry makes the current pose of the simulated base the origin of its odometry.
+func (b *Base) ResetOdometry(ctx context.Context, extra map[string]interface{}) error {
+	if b.sim == nil {
+		return errors.New("fake base only tracks its odometry when it is simulated")
+	}
+	b.sim.resetOdometry()
+	return nil
 }
 
-// Close does nothing.
+// Close stops the simulated base.
 func (b *Base) Close(ctx context.Context) error {
 	b.CloseCount++
+	if b.sim != nil {
+		b.sim.stop()
+	}
 	return nil
 }
 
@@ -106,3 +217,222 @@ func (b *Base) Properties(ctx context.Context, extra map[string]interface{}) (ba
 func (b *Base) Geometries(ctx context.Context, extra map[string]interface{}) ([]spatialmath.Geometry, error) {
 	return b.Geometry, nil
 }
+
+// simulation simulates the motion of a base on a plane. The base either makes a move of a given
+// distance or angle, or drives at commanded velocities, within its acceleration limits.
+type simulation struct {
+	clk          clock.Clock
+	maxSpeed     float64 // mm/s
+	maxAngular   float64 // rad/s
+	linearAccel  float64 // mm/s^2
+	angularAccel float64 // rad/s^2
+	slipNoise    float64
+
+	mu sync.Mutex
+	// x, y and theta are in mm and radians, with +Y forward and theta counterclockwise, and the
+	// origin is where the odometry was last reset.
+	x, y, theta                   float64
+	originX, originY, originTheta float64
+	linear, angular               float64
+	updated                       time.Time
+	move                          *baseMove
+	// targetLinear and targetAngular are the commanded velocities, and slip the fraction of them
+	// the base makes.
+	targetLinear, targetAngular float64
+	slip                        float64
+}
+
+// baseMove is a straight move or spin of the simulated base, following a trapezoidal profile.
+type baseMove struct {
+	fromX, fromY, fromTheta float64
+	distance, angle         float64
+	profile                 *rutils.TrapezoidalProfile
+	startedAt               time.Time
+	// done is closed when the move is stopped or replaced by another command.
+	done chan struct{}
+}
+
+func newSimulation(conf *Config, clk clock.Clock) *simulation {
+	s := &simulation{
+		clk:          clk,
+		maxSpeed:     conf.MaxSpeedMmPerSec,
+		maxAngular:   rutils.DegToRad(conf.MaxAngularDegsPerSec),
+		linearAccel:  conf.MaxLinearAccelMmPerSec2,
+		angularAccel: rutils.DegToRad(conf.MaxAngularAccelDegsPerSec2),
+		slipNoise:    conf.WheelSlipNoise,
+		updated:      clk.Now(),
+	}
+	if s.maxSpeed == 0 {
+		s.maxSpeed = defaultMaxSpeedMmPerSec
+	}
+	if s.maxAngular == 0 {
+		s.maxAngular = rutils.DegToRad(defaultMaxAngularDegsPerSec)
+	}
+	return s
+}
+
+// sampleSlip returns the fraction of a commanded motion the base makes.
+func (s *simulation) sampleSlip() float64 {
+	if s.slipNoise == 0 {
+		return 1
+	}
+	//nolint:gosec
+	return math.Max(0, 1-math.Abs(rand.NormFloat64()*s.slipNoise))
+}
+
+// makeMove makes a straight move or a spin at speed, returning once it is over. It returns an error
+// if it is stopped or replaced first.
+func (s *simulation) makeMove(ctx context.Context, distance, angle, speed, accel float64) error {
+	s.mu.Lock()
+	s.advance()
+	s.stopMove()
+	s.targetLinear, s.targetAngular, s.linear, s.angular = 0, 0, 0, 0
+	m := &baseMove{
+		fromX:     s.x,
+		fromY:     s.y,
+		fromTheta: s.theta,
+		profile:   rutils.NewTrapezoidalProfile(distance+angle, speed, accel),
+		startedAt: s.clk.Now(),
+		done:      make(chan struct{}),
+	}
+	slip := s.sampleSlip()
+	m.distance, m.angle = distance*slip, angle*slip
+	s.move = m
+	s.mu.Unlock()
+
+	timer := s.clk.Timer(m.profile.Duration())
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-m.done:
+		return errors.New("fake base move was interrupted")
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.move == m {
+			s.advance()
+			s.stopMove()
+		}
+		return ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.move != m {
+		return errors.New("fake base move was interrupted")
+	}
+	s.advance()
+	return nil
+}
+
+func (s *simulation) setVelocity(linear, angular float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance()
+	s.stopMove()
+	s.targetLinear, s.targetAngular = linear, angular
+	s.slip = s.sampleSlip()
+}
+
+func (s *simulation) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance()
+	s.stopMove()
+	s.targetLinear, s.targetAngular, s.linear, s.angular = 0, 0, 0, 0
+}
+
+func (s *simulation) isMoving() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance()
+	return s.move != nil || s.linear != 0 || s.angular != 0 || s.targetLinear != 0 || s.targetAngular != 0
+}
+
+func (s *simulation) odometry() base.Odometry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance()
+	// the pose relative to the origin of the odometry
+	dx, dy := s.x-s.originX, s.y-s.originY
+	sin, cos := math.Sincos(-s.originTheta)
+	return base.Odometry{
+		Pose: spatialmath.NewPose(
+			r3.Vector{X: dx*cos - dy*sin, Y: dx*sin + dy*cos},
+			&spatialmath.OrientationVector{OZ: 1, Theta: math.Remainder(s.theta-s.originTheta, 2*math.Pi)},
+		),
+		LinearVelocity:  r3.Vector{Y: s.linear},
+		AngularVelocity: spatialmath.AngularVelocity{Z: rutils.RadToDeg(s.angular)},
+		Time:            s.updated,
+	}
+}
+
+func (s *simulation) resetOdometry() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance()
+	s.originX, s.originY, s.originTheta = s.x, s.y, s.theta
+}
+
+// stopMove stops the move in progress, leaving the base where it is. Must be called with `mu`
+// held.
+func (s *simulation) stopMove() {
+	if s.move != nil {
+		close(s.move.done)
+		s.move = nil
+	}
+}
+
+// advance brings the pose and velocities up to date with the move or velocities in progress. Must
+// be called with `mu` held.
+func (s *simulation) advance() {
+	now := s.clk.Now()
+	defer func() {
+		s.updated = now
+	}()
+
+	if m := s.move; m != nil {
+		elapsed := now.Sub(m.startedAt)
+		fraction := m.profile.Fraction(elapsed)
+		s.theta = m.fromTheta + m.angle*fraction
+		s.x = m.fromX - m.distance*fraction*math.Sin(m.fromTheta)
+		s.y = m.fromY + m.distance*fraction*math.Cos(m.fromTheta)
+		if elapsed >= m.profile.Duration() {
+			s.move = nil
+			s.linear, s.angular = 0, 0
+		} else {
+			speed := m.profile.Speed(elapsed)
+			if m.distance != 0 {
+				s.linear, s.angular = math.Copysign(speed, m.distance), 0
+			} else {
+				s.linear, s.angular = 0, math.Copysign(speed, m.angle)
+			}
+		}
+		return
+	}
+
+	// Velocities are integrated in small steps, as they ramp up and down to their targets.
+	for t := s.updated; t.Before(now); {
+		dt := simulationIntegrationInterval
+		if remaining := now.Sub(t); remaining < dt {
+			dt = remaining
+		}
+		seconds := dt.Seconds()
+		s.linear = rampToward(s.linear, s.targetLinear*s.slip, s.linearAccel*seconds)
+		s.angular = rampToward(s.angular, s.targetAngular*s.slip, s.angularAccel*seconds)
+		heading := s.theta + s.angular*seconds/2
+		s.x -= s.linear * seconds * math.Sin(heading)
+		s.y += s.linear * seconds * math.Cos(heading)
+		s.theta = math.Remainder(s.theta+s.angular*seconds, 2*math.Pi)
+		t = t.Add(dt)
+	}
+}
+
+// rampToward returns the velocity after changing from current toward target by at most maxChange,
+// or target if maxChange is zero, meaning there is no acceleration limit.
+func rampToward(current, target, maxChange float64) float64 {
+	if maxChange == 0 || math.Abs(target-current) <= maxChange {
+		return target
+	}
+	return current + math.Copysign(maxChange, target-current)
+}
@@ -0,0 +1,107 @@
+package fake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	rutils "go.viam.com/rdk/utils"
+)
+
+func newSimulatedBase(t *testing.T, conf *Config) (*Base, *clock.Mock) {
+	t.Helper()
+	mockClock := clock.NewMock()
+	ctx := rutils.WithClock(context.Background(), mockClock)
+	conf.Simulate = true
+	b, err := NewBase(ctx, nil, resource.Config{Name: "base", API: base.API, ConvertedAttributes: conf}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	return b.(*Base), mockClock
+}
+
+func TestNotSimulated(t *testing.T) {
+	b, err := NewBase(context.Background(), nil, resource.Config{Name: "base", API: base.API}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, b.MoveStraight(context.Background(), 1000, 100, nil), test.ShouldBeNil)
+	moving, err := b.IsMoving(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+	_, err = b.(base.Odometer).Odometry(context.Background(), nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestSimulatedMoves(t *testing.T) {
+	ctx := context.Background()
+	b, mockClock := newSimulatedBase(t, &Config{MaxLinearAccelMmPerSec2: 100})
+
+	moveErr := make(chan error, 1)
+	go func() {
+		moveErr <- b.MoveStraight(ctx, 1000, 100, nil)
+	}()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		moving, err := b.IsMoving(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, moving, test.ShouldBeTrue)
+	})
+
+	// halfway through the 11 seconds the move takes, accelerating for 1 second at each end
+	mockClock.Add(5500 * time.Millisecond)
+	odometry, err := b.Odometry(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, odometry.Pose.Point().Y, test.ShouldAlmostEqual, 500)
+	test.That(t, odometry.LinearVelocity.Y, test.ShouldAlmostEqual, 100)
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		mockClock.Add(time.Second)
+		test.That(tb, len(moveErr), test.ShouldEqual, 1)
+	})
+	test.That(t, <-moveErr, test.ShouldBeNil)
+	odometry, err = b.Odometry(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, odometry.Pose.Point().Y, test.ShouldAlmostEqual, 1000)
+	test.That(t, odometry.LinearVelocity.Y, test.ShouldEqual, 0)
+
+	// spin left by 90 degrees, then the base moves along -X
+	go func() {
+		moveErr <- b.Spin(ctx, 90, 45, nil)
+	}()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		mockClock.Add(time.Second)
+		test.That(tb, len(moveErr), test.ShouldEqual, 1)
+	})
+	test.That(t, <-moveErr, test.ShouldBeNil)
+	test.That(t, b.ResetOdometry(ctx, nil), test.ShouldBeNil)
+	test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 100}, r3.Vector{}, nil), test.ShouldBeNil)
+	mockClock.Add(2 * time.Second)
+	test.That(t, b.Stop(ctx, nil), test.ShouldBeNil)
+	odometry, err = b.Odometry(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	// accelerating for the first second
+	test.That(t, odometry.Pose.Point().Y, test.ShouldAlmostEqual, 150, 1)
+	test.That(t, odometry.Pose.Point().X, test.ShouldAlmostEqual, 0, 1e-6)
+	moving, err := b.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+}
+
+func TestSimulatedWheelSlip(t *testing.T) {
+	ctx := context.Background()
+	b, mockClock := newSimulatedBase(t, &Config{WheelSlipNoise: 0.2})
+	test.That(t, b.SetPower(ctx, r3.Vector{Y: 1}, r3.Vector{}, nil), test.ShouldBeNil)
+	mockClock.Add(time.Second)
+	odometry, err := b.Odometry(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	// the base makes no more than the commanded motion
+	test.That(t, odometry.Pose.Point().Y, test.ShouldBeLessThanOrEqualTo, defaultMaxSpeedMmPerSec)
+	test.That(t, odometry.Pose.Point().Y, test.ShouldBeGreaterThanOrEqualTo, 0)
+}
@@ -0,0 +1,203 @@
+package base
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// The base proto has no odometry RPCs. Odometry is carried over DoCommand using the following
+// reserved keys.
+const (
+	getOdometryKey       = "get_odometry"
+	resetOdometryKey     = "reset_odometry"
+	xMmKey               = "x_mm"
+	yMmKey               = "y_mm"
+	thetaDegKey          = "theta_deg"
+	linearMmPerSecKey    = "linear_mm_per_sec"
+	angularDegsPerSecKey = "angular_degs_per_sec"
+	odometryTimeKey      = "time_unix_nanos"
+	odometryExtraKey     = "extra"
+)
+
+// Odometry is a base's estimate of how it has moved since its odometry was last reset, integrated
+// from its wheels or sensors.
+type Odometry struct {
+	// Pose is relative to where the base was when its odometry was reset, with +Y forward, +X to
+	// the right and the heading as a rotation about +Z, counterclockwise.
+	Pose spatialmath.Pose
+	// LinearVelocity is in mm/s, in the base's own frame.
+	LinearVelocity r3.Vector
+	// AngularVelocity is in degrees/s.
+	AngularVelocity spatialmath.AngularVelocity
+	// Time is when the odometry was last updated.
+	Time time.Time
+}
+
+// An Odometer is a base that tracks its own odometry. The base-odometry movement sensor exposes
+// the odometry of such bases through the movement sensor API.
+//
+// Odometry example:
+//
+//	myBase, err := base.FromRobot(machine, "my_base")
+//	if odometer, ok := myBase.(base.Odometer); ok {
+//		odometry, err := odometer.Odometry(context.Background(), nil)
+//	}
+type Odometer interface {
+	// Odometry returns how the base has moved since its odometry was last reset.
+	Odometry(ctx context.Context, extra map[string]interface{}) (Odometry, error)
+
+	// ResetOdometry makes the current pose of the base the origin of its odometry.
+	ResetOdometry(ctx context.Context, extra map[string]interface{}) error
+}
+
+// A DifferentialOdometer integrates the distances traveled by the wheels on each side of a
+// differential drive base into odometry. It is safe for concurrent use.
+type DifferentialOdometer struct {
+	widthMm float64
+
+	mu              sync.Mutex
+	x, y, theta     float64
+	linear, angular float64
+	last            time.Time
+}
+
+// NewDifferentialOdometer returns an odometer for a base whose left and right wheels are widthMm
+// apart.
+func NewDifferentialOdometer(widthMm float64) *DifferentialOdometer {
+	return &DifferentialOdometer{widthMm: widthMm}
+}
+
+// Update integrates the distances in mm the left and right wheels have traveled since the last
+// update, at time `at`. The first update only sets the time velocities are measured from.
+func (o *DifferentialOdometer) Update(at time.Time, leftMm, rightMm float64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	dist := (leftMm + rightMm) / 2
+	dTheta := (rightMm - leftMm) / o.widthMm
+	// integrate along the heading halfway through the turn
+	heading := o.theta + dTheta/2
+	o.x -= dist * math.Sin(heading)
+	o.y += dist * math.Cos(heading)
+	o.theta = math.Remainder(o.theta+dTheta, 2*math.Pi)
+	if !o.last.IsZero() {
+		if dt := at.Sub(o.last).Seconds(); dt > 0 {
+			o.linear = dist / dt
+			o.angular = utils.RadToDeg(dTheta) / dt
+		}
+	}
+	o.last = at
+}
+
+// Odometry returns the integrated odometry.
+func (o *DifferentialOdometer) Odometry() Odometry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return Odometry{
+		Pose: spatialmath.NewPose(
+			r3.Vector{X: o.x, Y: o.y},
+			&spatialmath.OrientationVector{OZ: 1, Theta: o.theta},
+		),
+		LinearVelocity:  r3.Vector{Y: o.linear},
+		AngularVelocity: spatialmath.AngularVelocity{Z: o.angular},
+		Time:            o.last,
+	}
+}
+
+// Reset makes the current pose the origin of the odometry.
+func (o *DifferentialOdometer) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.x, o.y, o.theta = 0, 0, 0
+}
+
+func (c *client) Odometry(ctx context.Context, extra map[string]interface{}) (Odometry, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		getOdometryKey: map[string]interface{}{odometryExtraKey: extra},
+	})
+	if err != nil {
+		return Odometry{}, err
+	}
+	x, okX := resp[xMmKey].(float64)
+	y, okY := resp[yMmKey].(float64)
+	theta, okTheta := resp[thetaDegKey].(float64)
+	if !okX || !okY || !okTheta {
+		return Odometry{}, errors.Errorf("expected odometry in response, got %v", resp)
+	}
+	linear, _ := resp[linearMmPerSecKey].(float64)     //nolint:errcheck
+	angular, _ := resp[angularDegsPerSecKey].(float64) //nolint:errcheck
+	nanos, _ := resp[odometryTimeKey].(float64)        //nolint:errcheck
+	var updated time.Time
+	if nanos != 0 {
+		updated = time.Unix(0, int64(nanos))
+	}
+	return Odometry{
+		Pose:            spatialmath.NewPose(r3.Vector{X: x, Y: y}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: theta}),
+		LinearVelocity:  r3.Vector{Y: linear},
+		AngularVelocity: spatialmath.AngularVelocity{Z: angular},
+		Time:            updated,
+	}, nil
+}
+
+func (c *client) ResetOdometry(ctx context.Context, extra map[string]interface{}) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		resetOdometryKey: map[string]interface{}{odometryExtraKey: extra},
+	})
+	return err
+}
+
+// doOdometryCommand handles the reserved odometry DoCommand keys. It returns false if `req` is not
+// an odometry command or the base does not implement Odometer.
+func doOdometryCommand(ctx context.Context, b Base, req *commonpb.DoCommandRequest) (*commonpb.DoCommandResponse, bool, error) {
+	odometer, ok := b.(Odometer)
+	if !ok {
+		return nil, false, nil
+	}
+	cmd := req.GetCommand().AsMap()
+	var result map[string]interface{}
+	switch {
+	case cmd[getOdometryKey] != nil:
+		args, _ := cmd[getOdometryKey].(map[string]interface{})     //nolint:errcheck
+		extra, _ := args[odometryExtraKey].(map[string]interface{}) //nolint:errcheck
+		odometry, err := odometer.Odometry(ctx, extra)
+		if err != nil {
+			return nil, true, err
+		}
+		pt := odometry.Pose.Point()
+		var nanos float64
+		if !odometry.Time.IsZero() {
+			nanos = float64(odometry.Time.UnixNano())
+		}
+		result = map[string]interface{}{
+			xMmKey:               pt.X,
+			yMmKey:               pt.Y,
+			thetaDegKey:          odometry.Pose.Orientation().OrientationVectorDegrees().Theta,
+			linearMmPerSecKey:    odometry.LinearVelocity.Y,
+			angularDegsPerSecKey: odometry.AngularVelocity.Z,
+			odometryTimeKey:      nanos,
+		}
+	case cmd[resetOdometryKey] != nil:
+		args, _ := cmd[resetOdometryKey].(map[string]interface{})   //nolint:errcheck
+		extra, _ := args[odometryExtraKey].(map[string]interface{}) //nolint:errcheck
+		if err := odometer.ResetOdometry(ctx, extra); err != nil {
+			return nil, true, err
+		}
+		result = map[string]interface{}{}
+	default:
+		return nil, false, nil
+	}
+	res, err := protoutils.StructToStructPb(result)
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
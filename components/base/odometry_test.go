@@ -0,0 +1,143 @@
+package base_test
+
+import (
+	"context"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/components/base"
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// odometerBase is a base tracking its odometry with a DifferentialOdometer.
+type odometerBase struct {
+	*inject.Base
+	odometer   *base.DifferentialOdometer
+	extraCalls []map[string]interface{}
+}
+
+func (b *odometerBase) Odometry(ctx context.Context, extra map[string]interface{}) (base.Odometry, error) {
+	b.extraCalls = append(b.extraCalls, extra)
+	return b.odometer.Odometry(), nil
+}
+
+func (b *odometerBase) ResetOdometry(ctx context.Context, extra map[string]interface{}) error {
+	b.extraCalls = append(b.extraCalls, extra)
+	b.odometer.Reset()
+	return nil
+}
+
+func TestDifferentialOdometer(t *testing.T) {
+	start := time.Now()
+	odometer := base.NewDifferentialOdometer(100)
+
+	odometer.Update(start, 0, 0)
+	odometry := odometer.Odometry()
+	test.That(t, odometry.Pose.Point().Norm(), test.ShouldAlmostEqual, 0)
+	test.That(t, odometry.LinearVelocity.Y, test.ShouldAlmostEqual, 0)
+	test.That(t, odometry.Time, test.ShouldEqual, start)
+
+	// straight ahead
+	odometer.Update(start.Add(time.Second), 200, 200)
+	odometry = odometer.Odometry()
+	test.That(t, odometry.Pose.Point().X, test.ShouldAlmostEqual, 0)
+	test.That(t, odometry.Pose.Point().Y, test.ShouldAlmostEqual, 200)
+	test.That(t, odometry.Pose.Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, 0)
+	test.That(t, odometry.LinearVelocity.Y, test.ShouldAlmostEqual, 200)
+	test.That(t, odometry.AngularVelocity.Z, test.ShouldAlmostEqual, 0)
+
+	// a quarter turn to the left, about the middle of the base
+	quarter := 100 * math.Pi / 4
+	odometer.Update(start.Add(2*time.Second), -quarter, quarter)
+	odometry = odometer.Odometry()
+	test.That(t, odometry.Pose.Point().X, test.ShouldAlmostEqual, 0)
+	test.That(t, odometry.Pose.Point().Y, test.ShouldAlmostEqual, 200)
+	test.That(t, odometry.Pose.Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, 90)
+	test.That(t, odometry.LinearVelocity.Y, test.ShouldAlmostEqual, 0)
+	test.That(t, odometry.AngularVelocity.Z, test.ShouldAlmostEqual, 90)
+
+	// forward is now -X
+	odometer.Update(start.Add(3*time.Second), 100, 100)
+	odometry = odometer.Odometry()
+	test.That(t, odometry.Pose.Point().X, test.ShouldAlmostEqual, -100)
+	test.That(t, odometry.Pose.Point().Y, test.ShouldAlmostEqual, 200)
+
+	odometer.Reset()
+	odometry = odometer.Odometry()
+	test.That(t, odometry.Pose.Point().Norm(), test.ShouldAlmostEqual, 0)
+	test.That(t, odometry.Pose.Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, 0)
+}
+
+func TestClientOdometry(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	start := time.Unix(0, 1000)
+	odometer := base.NewDifferentialOdometer(100)
+	odometer.Update(start, 0, 0)
+	odometer.Update(start.Add(time.Second), 150, 150)
+	workingBase := &odometerBase{Base: inject.NewBase(testBaseName), odometer: odometer}
+	plainBase := inject.NewBase(failBaseName)
+	plainBase.DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		return nil, resource.ErrDoUnimplemented
+	}
+
+	baseSvc, err := resource.NewAPIResourceCollection(base.API, map[resource.Name]base.Base{
+		base.Named(testBaseName): workingBase,
+		base.Named(failBaseName): plainBase,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[base.Base](base.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, baseSvc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}()
+
+	t.Run("base tracking odometry", func(t *testing.T) {
+		client, err := base.NewClientFromConn(context.Background(), conn, "", base.Named(testBaseName), logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer client.Close(context.Background())
+
+		clientOdometer, ok := client.(base.Odometer)
+		test.That(t, ok, test.ShouldBeTrue)
+		odometry, err := clientOdometer.Odometry(context.Background(), map[string]interface{}{"foo": "bar"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, odometry.Pose.Point().Y, test.ShouldAlmostEqual, 150)
+		test.That(t, odometry.LinearVelocity.Y, test.ShouldAlmostEqual, 150)
+		test.That(t, odometry.Time.Equal(start.Add(time.Second)), test.ShouldBeTrue)
+		test.That(t, workingBase.extraCalls[0], test.ShouldResemble, map[string]interface{}{"foo": "bar"})
+
+		test.That(t, clientOdometer.ResetOdometry(context.Background(), nil), test.ShouldBeNil)
+		odometry, err = clientOdometer.Odometry(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, odometry.Pose.Point().Norm(), test.ShouldAlmostEqual, 0)
+	})
+
+	t.Run("base without odometry", func(t *testing.T) {
+		client, err := base.NewClientFromConn(context.Background(), conn, "", base.Named(failBaseName), logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer client.Close(context.Background())
+
+		_, err = client.(base.Odometer).Odometry(context.Background(), nil)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,45 @@
+package sensorcontrolled
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/base"
+)
+
+// Odometry returns the odometry tracked by the controlled base, with its velocities measured by the
+// velocity movement sensor if there is one.
+func (sb *sensorBase) Odometry(ctx context.Context, extra map[string]interface{}) (base.Odometry, error) {
+	odometer, ok := sb.controlledBase.(base.Odometer)
+	if !ok {
+		return base.Odometry{}, errors.Errorf("base %s does not track its odometry", sb.controlledBase.Name().ShortName())
+	}
+	odometry, err := odometer.Odometry(ctx, extra)
+	if err != nil {
+		return base.Odometry{}, err
+	}
+	if sb.velocities != nil {
+		linear, err := sb.velocities.LinearVelocity(ctx, nil)
+		if err != nil {
+			return base.Odometry{}, err
+		}
+		angular, err := sb.velocities.AngularVelocity(ctx, nil)
+		if err != nil {
+			return base.Odometry{}, err
+		}
+		// the movement sensor measures m/s
+		odometry.LinearVelocity = linear.Mul(1000)
+		odometry.AngularVelocity = angular
+	}
+	return odometry, nil
+}
+
+// ResetOdometry resets the odometry of the controlled base.
+func (sb *sensorBase) ResetOdometry(ctx context.Context, extra map[string]interface{}) error {
+	odometer, ok := sb.controlledBase.(base.Odometer)
+	if !ok {
+		return errors.Errorf("base %s does not track its odometry", sb.controlledBase.Name().ShortName())
+	}
+	return odometer.ResetOdometry(ctx, extra)
+}
@@ -182,5 +182,8 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := doOdometryCommand(ctx, base, req); handled {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, base, req)
 }
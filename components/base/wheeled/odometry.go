@@ -0,0 +1,104 @@
+package wheeled
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/motor"
+)
+
+// odometryInterval is how often the motors' positions are read to track the base's odometry.
+const odometryInterval = 50 * time.Millisecond
+
+// startOdometry starts tracking the base's odometry from its motors' positions, if they all
+// report them. It must be called with `mu` held and the previous tracking stopped.
+func (wb *wheeledBase) startOdometry(ctx context.Context) {
+	wb.odometryMu.Lock()
+	defer wb.odometryMu.Unlock()
+	wb.odometer = nil
+	wb.odometryErr = nil
+	left := append([]motor.Motor(nil), wb.left...)
+	right := append([]motor.Motor(nil), wb.right...)
+	for _, m := range append(append([]motor.Motor(nil), left...), right...) {
+		props, err := m.Properties(ctx, nil)
+		if err != nil {
+			wb.odometryErr = errors.Wrapf(err, "cannot track odometry of base %s", wb.Name().ShortName())
+			return
+		}
+		if !props.PositionReporting {
+			wb.odometryErr = errors.Errorf("cannot track odometry of base %s since motor %s does not report position",
+				wb.Name().ShortName(), m.Name().ShortName())
+			return
+		}
+	}
+
+	odometer := base.NewDifferentialOdometer(float64(wb.widthMm))
+	wb.odometer = odometer
+	circumference := float64(wb.wheelCircumferenceMm)
+	wb.odometryWorkers = goutils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
+		ticker := time.NewTicker(odometryInterval)
+		defer ticker.Stop()
+		var lastLeft, lastRight float64
+		started := false
+		for {
+			leftPos, errLeft := averagePosition(ctx, left)
+			rightPos, errRight := averagePosition(ctx, right)
+			switch {
+			case errLeft != nil || errRight != nil:
+				if ctx.Err() == nil {
+					wb.logger.CDebugw(ctx, "error reading motor positions for odometry", "left", errLeft, "right", errRight)
+				}
+			case !started:
+				odometer.Update(time.Now(), 0, 0)
+				started = true
+			default:
+				odometer.Update(time.Now(), (leftPos-lastLeft)*circumference, (rightPos-lastRight)*circumference)
+			}
+			if errLeft == nil && errRight == nil {
+				lastLeft, lastRight = leftPos, rightPos
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// stopOdometry stops tracking the base's odometry.
+func (wb *wheeledBase) stopOdometry() {
+	wb.odometryMu.Lock()
+	workers := wb.odometryWorkers
+	wb.odometryWorkers = nil
+	wb.odometryMu.Unlock()
+	if workers != nil {
+		workers.Stop()
+	}
+}
+
+// Odometry returns how the base has moved since it was configured or its odometry was last reset,
+// as measured by its motors' encoders.
+func (wb *wheeledBase) Odometry(ctx context.Context, extra map[string]interface{}) (base.Odometry, error) {
+	wb.odometryMu.Lock()
+	defer wb.odometryMu.Unlock()
+	if wb.odometer == nil {
+		return base.Odometry{}, wb.odometryErr
+	}
+	return wb.odometer.Odometry(), nil
+}
+
+// ResetOdometry makes the current pose of the base the origin of its odometry.
+func (wb *wheeledBase) ResetOdometry(ctx context.Context, extra map[string]interface{}) error {
+	wb.odometryMu.Lock()
+	defer wb.odometryMu.Unlock()
+	if wb.odometer == nil {
+		return wb.odometryErr
+	}
+	wb.odometer.Reset()
+	return nil
+}
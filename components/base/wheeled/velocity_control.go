@@ -0,0 +1,268 @@
+package wheeled
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/control"
+	"go.viam.com/rdk/resource"
+)
+
+const (
+	typeLinVel         = "linear_velocity"
+	typeAngVel         = "angular_velocity"
+	defaultControlFreq = 10 // Hz
+)
+
+// velocityFeedback estimates the base's linear (m/s) and angular (deg/s) velocities.
+type velocityFeedback func(ctx context.Context) (linear, angular float64, err error)
+
+// setupVelocityControl prepares closed-loop velocity control from the config. The control loop
+// itself is started on the first SetVelocity. Must be called with `mu` held and the loop stopped.
+func (wb *wheeledBase) setupVelocityControl(ctx context.Context, deps resource.Dependencies, conf *Config) error {
+	wb.controlMu.Lock()
+	defer wb.controlMu.Unlock()
+
+	wb.controlLoopConfig = nil
+	wb.blockNames = nil
+	wb.feedback = nil
+	if len(conf.ControlParameters) == 0 {
+		return nil
+	}
+
+	if conf.MovementSensor != "" {
+		ms, err := movementsensor.FromDependencies(deps, conf.MovementSensor)
+		if err != nil {
+			return errors.Wrapf(err, "no movement sensor named (%s)", conf.MovementSensor)
+		}
+		props, err := ms.Properties(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if !props.LinearVelocitySupported || !props.AngularVelocitySupported {
+			return errors.Errorf("movement sensor %s must support linear and angular velocity", conf.MovementSensor)
+		}
+		wb.feedback = movementSensorFeedback(ms)
+	} else {
+		for _, m := range wb.allMotors {
+			props, err := m.Properties(ctx, nil)
+			if err != nil {
+				return err
+			}
+			if !props.PositionReporting {
+				return errors.Errorf(
+					"motor %s does not report position; configure a movement_sensor for velocity control",
+					m.Name().ShortName())
+			}
+		}
+		wb.feedback = wb.encoderFeedback()
+	}
+
+	pidVals := []control.PIDConfig{{}, {}}
+	for _, pidConf := range conf.ControlParameters {
+		if pidConf.Type == typeLinVel {
+			// linear is first so that it is used by the linear blocks of the control loop
+			pidVals[0] = pidConf
+		} else {
+			pidVals[1] = pidConf
+		}
+	}
+
+	freq := float64(defaultControlFreq)
+	if conf.ControlFreq != 0 {
+		freq = conf.ControlFreq
+	}
+	pl, err := control.SetupPIDControlConfig(pidVals, wb.Name().ShortName(), control.Options{
+		SensorFeedback2DVelocityControl: true,
+		LoopFrequency:                   freq,
+		ControllableType:                "base_name",
+	}, wb, wb.logger)
+	if err != nil {
+		return err
+	}
+	wb.controlLoopConfig = pl.ControlConf
+	wb.blockNames = pl.BlockNames
+	return nil
+}
+
+// setControlledVelocity updates the control loop's setpoints, starting the loop if needed. It
+// returns false if closed-loop velocity control is not configured.
+func (wb *wheeledBase) setControlledVelocity(ctx context.Context, linear, angular r3.Vector) (bool, error) {
+	wb.controlMu.Lock()
+	if wb.controlLoopConfig == nil {
+		wb.controlMu.Unlock()
+		return false, nil
+	}
+	if wb.loop == nil {
+		loop, err := control.NewLoop(wb.logger, *wb.controlLoopConfig, wb)
+		if err != nil {
+			wb.controlMu.Unlock()
+			return true, err
+		}
+		if err := loop.Start(); err != nil {
+			wb.controlMu.Unlock()
+			return true, err
+		}
+		wb.loop = loop
+	}
+	loop, blockNames := wb.loop, wb.blockNames
+	wb.controlMu.Unlock()
+
+	// convert linear.Y mmPerSec to mPerSec, angular.Z is degPerSec
+	if err := updateControlConfig(ctx, loop, blockNames, linear.Y/1000.0, angular.Z); err != nil {
+		return true, err
+	}
+	loop.Resume()
+	return true, nil
+}
+
+// pauseVelocityControl stops the control loop from commanding the motors, and resets its setpoints
+// to rest if `reset` is true.
+func (wb *wheeledBase) pauseVelocityControl(ctx context.Context, reset bool) error {
+	wb.controlMu.Lock()
+	loop, blockNames := wb.loop, wb.blockNames
+	wb.controlMu.Unlock()
+	if loop == nil {
+		return nil
+	}
+	loop.Pause()
+	if reset {
+		return updateControlConfig(ctx, loop, blockNames, 0, 0)
+	}
+	return nil
+}
+
+// stopVelocityControl shuts down the control loop. It must not be called with `mu` held since the
+// loop may be waiting on it.
+func (wb *wheeledBase) stopVelocityControl() {
+	wb.controlMu.Lock()
+	loop := wb.loop
+	wb.loop = nil
+	wb.controlMu.Unlock()
+	if loop != nil {
+		loop.Stop()
+	}
+}
+
+// updateControlConfig sets the linear and angular setpoints of the loop.
+func updateControlConfig(
+	ctx context.Context, loop *control.Loop, blockNames map[string][]string, linearValue, angularValue float64,
+) error {
+	if err := control.UpdateConstantBlock(ctx, blockNames[control.BlockNameConstant][0], linearValue, loop); err != nil {
+		return err
+	}
+	return control.UpdateConstantBlock(ctx, blockNames[control.BlockNameConstant][1], angularValue, loop)
+}
+
+// SetState is called by the control loop with the linear and angular powers to apply.
+func (wb *wheeledBase) SetState(ctx context.Context, state []*control.Signal) error {
+	if loop := wb.currentLoop(); loop == nil || !loop.Running() {
+		return nil
+	}
+	linear := state[0].GetSignalValueAt(0)
+	// multiply by the direction of the linear velocity so that angular direction
+	// (cw/ccw) doesn't switch when the base is moving backwards
+	angular := state[1].GetSignalValueAt(0)
+	if math.Signbit(linear) {
+		angular = -angular
+	}
+	return wb.setMotorPowers(ctx, linear, angular, nil)
+}
+
+// State is called by the control loop to get the base's current linear (m/s) and angular (deg/s)
+// velocities.
+func (wb *wheeledBase) State(ctx context.Context) ([]float64, error) {
+	wb.controlMu.Lock()
+	feedback := wb.feedback
+	wb.controlMu.Unlock()
+	if feedback == nil {
+		return []float64{}, errors.New("no velocity feedback configured")
+	}
+	linear, angular, err := feedback(ctx)
+	if err != nil {
+		return []float64{}, err
+	}
+	return []float64{linear, angular}, nil
+}
+
+func (wb *wheeledBase) currentLoop() *control.Loop {
+	wb.controlMu.Lock()
+	defer wb.controlMu.Unlock()
+	return wb.loop
+}
+
+func movementSensorFeedback(ms movementsensor.MovementSensor) velocityFeedback {
+	return func(ctx context.Context) (float64, float64, error) {
+		linvel, err := ms.LinearVelocity(ctx, nil)
+		if err != nil {
+			return 0, 0, err
+		}
+		angvel, err := ms.AngularVelocity(ctx, nil)
+		if err != nil {
+			return 0, 0, err
+		}
+		return linvel.Y, angvel.Z, nil
+	}
+}
+
+// encoderFeedback estimates the base's velocities from the change in its motors' positions since
+// the previous call. The first call reports zero velocity.
+func (wb *wheeledBase) encoderFeedback() velocityFeedback {
+	var (
+		lastLeft, lastRight float64
+		lastTime            time.Time
+	)
+	return func(ctx context.Context) (float64, float64, error) {
+		wb.mu.Lock()
+		left, right := wb.left, wb.right
+		wb.mu.Unlock()
+
+		leftPos, err := averagePosition(ctx, left)
+		if err != nil {
+			return 0, 0, err
+		}
+		rightPos, err := averagePosition(ctx, right)
+		if err != nil {
+			return 0, 0, err
+		}
+		now := time.Now()
+		defer func() {
+			lastLeft, lastRight, lastTime = leftPos, rightPos, now
+		}()
+		if lastTime.IsZero() {
+			return 0, 0, nil
+		}
+		dt := now.Sub(lastTime).Seconds()
+		if dt <= 0 {
+			return 0, 0, nil
+		}
+		// wheel surface speeds in mm/s
+		leftSpeed := (leftPos - lastLeft) / dt * float64(wb.wheelCircumferenceMm)
+		rightSpeed := (rightPos - lastRight) / dt * float64(wb.wheelCircumferenceMm)
+
+		linear := (leftSpeed + rightSpeed) / 2 / 1000.0
+		angular := (rightSpeed - leftSpeed) / float64(wb.widthMm) * 180 / math.Pi
+		return linear, angular, nil
+	}
+}
+
+func averagePosition(ctx context.Context, motors []motor.Motor) (float64, error) {
+	if len(motors) == 0 {
+		return 0, nil
+	}
+	var sum float64
+	for _, m := range motors {
+		pos, err := m.Position(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		sum += pos
+	}
+	return sum / float64(len(motors)), nil
+}
@@ -13,6 +13,13 @@ package wheeled
    Adding a movementsensor that supports Orientation provides feedback to a Spin command to correct the heading. As of
    June 2023, this feature is experimental.
 
+   If all the motors report their positions, the base tracks its odometry from them, which the base-odometry movement
+   sensor exposes for SLAM and navigation.
+
+   Adding control_parameters makes SetVelocity closed-loop: a PID control loop corrects the error between the commanded
+   and measured linear and angular velocities. Velocities are measured with the configured movement_sensor, or from the
+   motors' encoders if no movement sensor is given.
+
    Configuring a base with a frame will create a kinematic base that can be used by Viam's motion service to plan paths
    when a SLAM service is also present. As of June 2023 This feature is experimental.
    Example Config:
@@ -26,6 +33,10 @@ package wheeled
        "spin_slip_factor": 1.76,
        "wheel_circumference_mm": 217,
        "width_mm": 260,
+       "control_parameters": [
+         {"type": "linear_velocity", "p": 0.5, "i": 0.2, "d": 0},
+         {"type": "angular_velocity", "p": 0.5, "i": 0.2, "d": 0}
+       ]
      },
      "depends_on": ["left1", "left2", "right1", "right2", "local"],
    },
@@ -40,9 +51,11 @@ import (
 	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
+	goutils "go.viam.com/utils"
 
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/control"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
@@ -60,6 +73,11 @@ type Config struct {
 	SpinSlipFactor       float64  `json:"spin_slip_factor,omitempty"`
 	Left                 []string `json:"left"`
 	Right                []string `json:"right"`
+
+	// MovementSensor optionally provides velocity feedback for closed-loop velocity control.
+	MovementSensor    string              `json:"movement_sensor,omitempty"`
+	ControlParameters []control.PIDConfig `json:"control_parameters,omitempty"`
+	ControlFreq       float64             `json:"control_frequency_hz,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -90,6 +108,31 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 	deps = append(deps, cfg.Left...)
 	deps = append(deps, cfg.Right...)
 
+	for _, pidConf := range cfg.ControlParameters {
+		if pidConf.Type != typeLinVel && pidConf.Type != typeAngVel {
+			return nil, resource.NewConfigValidationError(path,
+				errors.New("control_parameters type must be 'linear_velocity' or 'angular_velocity'"))
+		}
+		if pidConf.NeedsAutoTuning() {
+			return nil, resource.NewConfigValidationError(path,
+				fmt.Errorf("control_parameters for %s must have a non-zero p, i, or d", pidConf.Type))
+		}
+	}
+	if len(cfg.ControlParameters) != 0 && len(cfg.ControlParameters) != 2 {
+		return nil, resource.NewConfigValidationError(path,
+			errors.New("control_parameters must have one linear_velocity and one angular_velocity entry"))
+	}
+	if len(cfg.ControlParameters) == 2 && cfg.ControlParameters[0].Type == cfg.ControlParameters[1].Type {
+		return nil, resource.NewConfigValidationError(path,
+			errors.New("control_parameters must have one linear_velocity and one angular_velocity entry"))
+	}
+	if cfg.ControlFreq < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("control_frequency_hz cannot be negative"))
+	}
+	if cfg.MovementSensor != "" {
+		deps = append(deps, cfg.MovementSensor)
+	}
+
 	return deps, nil
 }
 
@@ -113,10 +156,27 @@ type wheeledBase struct {
 
 	mu   sync.Mutex
 	name string
+
+	// controlMu guards the closed-loop velocity control state below.
+	controlMu         sync.Mutex
+	controlLoopConfig *control.Config
+	blockNames        map[string][]string
+	loop              *control.Loop
+	feedback          velocityFeedback
+
+	// odometryMu guards the odometry tracked from the motors' positions.
+	odometryMu      sync.Mutex
+	odometer        *base.DifferentialOdometer
+	odometryErr     error
+	odometryWorkers *goutils.StoppableWorkers
 }
 
 // Reconfigure reconfigures the base atomically and in place.
 func (wb *wheeledBase) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	// stop the control loop and odometry before locking since they use the motors
+	wb.stopVelocityControl()
+	wb.stopOdometry()
+
 	wb.mu.Lock()
 	defer wb.mu.Unlock()
 
@@ -202,7 +262,8 @@ func (wb *wheeledBase) Reconfigure(ctx context.Context, deps resource.Dependenci
 		wb.wheelCircumferenceMm = newConf.WheelCircumferenceMM
 	}
 
-	return nil
+	wb.startOdometry(ctx)
+	return wb.setupVelocityControl(ctx, deps, newConf)
 }
 
 // createWheeledBase returns a new wheeled base defined by the given config.
@@ -419,11 +480,15 @@ func (wb *wheeledBase) SetVelocity(ctx context.Context, linear, angular r3.Vecto
 		return wb.Stop(ctx, nil)
 	}
 
-	leftRPM, rightRPM := wb.velocityMath(linear.Y, angular.Z)
-
 	// start new operation after all calculations are made
 	ctx, done := wb.opMgr.New(ctx)
 	defer done()
+
+	if controlled, err := wb.setControlledVelocity(ctx, linear, angular); controlled {
+		return err
+	}
+
+	leftRPM, rightRPM := wb.velocityMath(linear.Y, angular.Z)
 	return wb.runAllSetRPM(ctx, leftRPM, rightRPM)
 }
 
@@ -443,7 +508,15 @@ func (wb *wheeledBase) SetPower(ctx context.Context, linear, angular r3.Vector,
 		return wb.Stop(ctx, nil)
 	}
 
-	lPower, rPower := wb.differentialDrive(linear.Y, angular.Z)
+	if err := wb.pauseVelocityControl(ctx, false); err != nil {
+		return err
+	}
+	return wb.setMotorPowers(ctx, linear.Y, angular.Z, extra)
+}
+
+// setMotorPowers sets the motor powers for the given linear and angular powers.
+func (wb *wheeledBase) setMotorPowers(ctx context.Context, linear, angular float64, extra map[string]interface{}) error {
+	lPower, rPower := wb.differentialDrive(linear, angular)
 
 	// Send motor commands
 	setPowerFuncs := func() []rdkutils.SimpleFunc {
@@ -513,6 +586,10 @@ func (wb *wheeledBase) straightDistanceToMotorInputs(distanceMm int, mmPerSec fl
 
 // Stop commands the base to stop moving.
 func (wb *wheeledBase) Stop(ctx context.Context, extra map[string]interface{}) error {
+	if err := wb.pauseVelocityControl(ctx, true); err != nil {
+		return err
+	}
+
 	stopFuncs := func() []rdkutils.SimpleFunc {
 		ret := []rdkutils.SimpleFunc{}
 
@@ -551,7 +628,10 @@ func (wb *wheeledBase) IsMoving(ctx context.Context) (bool, error) {
 
 // Close is called from the client to close the instance of the wheeledBase.
 func (wb *wheeledBase) Close(ctx context.Context) error {
-	return wb.Stop(ctx, nil)
+	err := wb.Stop(ctx, nil)
+	wb.stopVelocityControl()
+	wb.stopOdometry()
+	return err
 }
 
 func (wb *wheeledBase) Properties(ctx context.Context, extra map[string]interface{}) (base.Properties, error) {
@@ -4,25 +4,33 @@ import (
 	"context"
 	"errors"
 	"math"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/golang/geo/r3"
 	"go.viam.com/test"
 	"go.viam.com/utils"
+	"go.viam.com/utils/testutils"
 
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/components/motor"
 	"go.viam.com/rdk/components/motor/fake"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/control"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/testutils/inject"
 )
 
 func createFakeMotor() motor.Motor {
 	return &inject.Motor{
 		StopFunc: func(ctx context.Context, extra map[string]interface{}) error { return errors.New("stop error") },
+		PropertiesFunc: func(ctx context.Context, extra map[string]interface{}) (motor.Properties, error) {
+			return motor.Properties{}, nil
+		},
 		// SetRPMFunc: func(ctx context.Context, rpm float64, extra map[string]interface{}) error {
 
 		// },
@@ -502,3 +510,201 @@ func waitForMotorsToStop(ctx context.Context, wb *wheeledBase) error {
 		}
 	}
 }
+
+func TestValidateControlParameters(t *testing.T) {
+	cfg := &Config{
+		WidthMM:              100,
+		WheelCircumferenceMM: 1000,
+		Left:                 []string{"fl-m"},
+		Right:                []string{"fr-m"},
+		MovementSensor:       "ms",
+		ControlParameters: []control.PIDConfig{
+			{Type: typeLinVel, P: 1},
+			{Type: typeAngVel, P: 1},
+		},
+	}
+	deps, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"fl-m", "fr-m", "ms"})
+
+	cfg.ControlParameters[1].P = 0
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must have a non-zero p, i, or d")
+
+	cfg.ControlParameters[1] = control.PIDConfig{Type: typeLinVel, P: 1}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "one linear_velocity and one angular_velocity")
+
+	cfg.ControlParameters[1] = control.PIDConfig{Type: "position", P: 1}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must be 'linear_velocity' or 'angular_velocity'")
+}
+
+func TestClosedLoopSetVelocity(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	motorDeps := fakeMotorDependencies(t, []string{"fl-m", "fr-m"})
+	ms := inject.NewMovementSensor("ms")
+	ms.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+		return &movementsensor.Properties{LinearVelocitySupported: true, AngularVelocitySupported: true}, nil
+	}
+	ms.LinearVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+		return r3.Vector{}, nil
+	}
+	ms.AngularVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+		return spatialmath.AngularVelocity{}, nil
+	}
+	motorDeps[movementsensor.Named("ms")] = ms
+
+	cfg := resource.Config{
+		Name:  "test",
+		API:   base.API,
+		Model: resource.Model{Name: "wheeled_base"},
+		ConvertedAttributes: &Config{
+			WidthMM:              100,
+			WheelCircumferenceMM: 1000,
+			Left:                 []string{"fl-m"},
+			Right:                []string{"fr-m"},
+			MovementSensor:       "ms",
+			ControlParameters: []control.PIDConfig{
+				{Type: typeLinVel, P: 1000},
+				{Type: typeAngVel, P: 1},
+			},
+			ControlFreq: 100,
+		},
+	}
+	b, err := createWheeledBase(ctx, motorDeps, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	wb := b.(*wheeledBase)
+	defer wb.Close(ctx)
+
+	// the sensor reports no motion, so the loop drives both motors forward
+	test.That(t, wb.SetVelocity(ctx, r3.Vector{Y: 100}, r3.Vector{}, nil), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		for _, m := range wb.allMotors {
+			on, powerPct, err := m.IsPowered(ctx, nil)
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, on, test.ShouldBeTrue)
+			test.That(tb, powerPct, test.ShouldBeGreaterThan, 0)
+		}
+	})
+
+	test.That(t, wb.Stop(ctx, nil), test.ShouldBeNil)
+	time.Sleep(50 * time.Millisecond)
+	moving, err := wb.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+}
+
+func TestClosedLoopRequiresEncoders(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	motorDeps := fakeMotorDependencies(t, []string{"fl-m", "fr-m"})
+	cfg := resource.Config{
+		Name:  "test",
+		API:   base.API,
+		Model: resource.Model{Name: "wheeled_base"},
+		ConvertedAttributes: &Config{
+			WidthMM:              100,
+			WheelCircumferenceMM: 1000,
+			Left:                 []string{"fl-m"},
+			Right:                []string{"fr-m"},
+			ControlParameters: []control.PIDConfig{
+				{Type: typeLinVel, P: 1},
+				{Type: typeAngVel, P: 1},
+			},
+		},
+	}
+	_, err := createWheeledBase(context.Background(), motorDeps, cfg, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "does not report position")
+}
+
+func TestOdometry(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	t.Run("requires encoders", func(t *testing.T) {
+		motorDeps := fakeMotorDependencies(t, []string{"fl-m", "fr-m"})
+		cfg := resource.Config{
+			Name:  "test",
+			API:   base.API,
+			Model: resource.Model{Name: "wheeled_base"},
+			ConvertedAttributes: &Config{
+				WidthMM:              100,
+				WheelCircumferenceMM: 1000,
+				Left:                 []string{"fl-m"},
+				Right:                []string{"fr-m"},
+			},
+		}
+		wb, err := createWheeledBase(ctx, motorDeps, cfg, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer wb.Close(ctx)
+
+		odometer, ok := wb.(base.Odometer)
+		test.That(t, ok, test.ShouldBeTrue)
+		_, err = odometer.Odometry(ctx, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "does not report position")
+	})
+
+	t.Run("tracks encoded motors", func(t *testing.T) {
+		var mu sync.Mutex
+		positions := map[string]float64{}
+		encodedMotor := func(name string) motor.Motor {
+			m := inject.NewMotor(name)
+			m.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (motor.Properties, error) {
+				return motor.Properties{PositionReporting: true}, nil
+			}
+			m.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				return positions[name], nil
+			}
+			m.StopFunc = func(ctx context.Context, extra map[string]interface{}) error { return nil }
+			return m
+		}
+		deps := resource.Dependencies{
+			motor.Named("left"):  encodedMotor("left"),
+			motor.Named("right"): encodedMotor("right"),
+		}
+		cfg := resource.Config{
+			Name: "base",
+			ConvertedAttributes: &Config{
+				WidthMM:              100,
+				WheelCircumferenceMM: 100,
+				Left:                 []string{"left"},
+				Right:                []string{"right"},
+			},
+		}
+		wb, err := createWheeledBase(ctx, deps, cfg, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer wb.Close(ctx)
+		odometer := wb.(base.Odometer)
+
+		// wait for the starting positions to be read before moving
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			odometry, err := odometer.Odometry(ctx, nil)
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, odometry.Time.IsZero(), test.ShouldBeFalse)
+		})
+
+		mu.Lock()
+		positions["left"], positions["right"] = 2, 2
+		mu.Unlock()
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			odometry, err := odometer.Odometry(ctx, nil)
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, odometry.Pose.Point().X, test.ShouldAlmostEqual, 0)
+			test.That(tb, odometry.Pose.Point().Y, test.ShouldAlmostEqual, 200)
+		})
+
+		test.That(t, odometer.ResetOdometry(ctx, nil), test.ShouldBeNil)
+		odometry, err := odometer.Odometry(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, odometry.Pose.Point().Norm(), test.ShouldAlmostEqual, 0)
+	})
+}
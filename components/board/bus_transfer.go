@@ -0,0 +1,283 @@
+package board
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+)
+
+// The board proto has no I2C or SPI RPCs. Bus transfers are made over DoCommand using the
+// following reserved keys. Bytes are sent as base64 strings.
+const (
+	i2cTransferKey        = "i2c_transfer"
+	spiTransferKey        = "spi_transfer"
+	busTransferBusKey     = "bus"
+	busTransferWriteKey   = "write"
+	busTransferReadKey    = "read"
+	busTransferExtraKey   = "extra"
+	i2cTransferAddressKey = "address"
+	i2cTransferSpeedKey   = "speed_hz"
+	i2cTransferReadLenKey = "read_len"
+	spiTransferCSKey      = "chip_select"
+	spiTransferBaudKey    = "baud_hz"
+	spiTransferModeKey    = "mode"
+)
+
+// maxI2CAddress is the largest 7-bit I2C address.
+const maxI2CAddress = 0x7f
+
+// An I2CTransfer is a single transaction with a device on an I2C bus. Write is written to the
+// device and then ReadLen bytes are read from it after a repeated start, so that no other
+// transaction can happen in between, such as when selecting and then reading a register. Either
+// may be empty for a plain read or write.
+type I2CTransfer struct {
+	// Bus is the name of the bus, such as "1" for /dev/i2c-1.
+	Bus string
+	// Address is the 7-bit address of the device.
+	Address byte
+	// SpeedHz is the clock speed of the bus. Zero keeps the current speed.
+	SpeedHz uint
+	Write   []byte
+	ReadLen int
+}
+
+// Validate ensures the transfer is consistent.
+func (t I2CTransfer) Validate() error {
+	if t.Bus == "" {
+		return errors.New("an I2C transfer needs a bus")
+	}
+	if t.Address > maxI2CAddress {
+		return errors.Errorf("I2C address %#x is not a 7-bit address", t.Address)
+	}
+	if t.ReadLen < 0 {
+		return errors.Errorf("I2C read length %d can't be negative", t.ReadLen)
+	}
+	if len(t.Write) == 0 && t.ReadLen == 0 {
+		return errors.New("an I2C transfer needs bytes to write or read")
+	}
+	return nil
+}
+
+// An SPITransfer is a single transaction with a device on an SPI bus, from asserting its chip
+// select to releasing it. SPI is full duplex, so as many bytes are read as are written; devices
+// are usually read by writing a command followed by zeros.
+type SPITransfer struct {
+	// Bus is the name of the bus, such as "0" for /dev/spidev0.*.
+	Bus string
+	// ChipSelect is the chip select of the device, such as "1" for /dev/spidev0.1.
+	ChipSelect string
+	// BaudHz is the clock speed of the transfer.
+	BaudHz uint
+	// Mode is the SPI mode, between 0 and 3.
+	Mode  uint
+	Write []byte
+}
+
+// Validate ensures the transfer is consistent.
+func (t SPITransfer) Validate() error {
+	if t.Bus == "" {
+		return errors.New("an SPI transfer needs a bus")
+	}
+	if t.ChipSelect == "" {
+		return errors.New("an SPI transfer needs a chip select")
+	}
+	if t.BaudHz == 0 {
+		return errors.New("an SPI transfer needs a baud rate")
+	}
+	if t.Mode > 3 {
+		return errors.Errorf("SPI mode %d must be between 0 and 3", t.Mode)
+	}
+	if len(t.Write) == 0 {
+		return errors.New("an SPI transfer needs bytes to write")
+	}
+	return nil
+}
+
+// BusTransactor is implemented by boards which give direct access to their I2C and SPI buses, so
+// that modules and other code can drive peripherals without opening the buses themselves. Each
+// transfer holds the bus for its whole duration, so transfers to the same bus never interleave.
+//
+// I2CTransfer example:
+//
+//	myBoard, err := board.FromRobot(machine, "my_board")
+//	if transactor, ok := myBoard.(board.BusTransactor); ok {
+//		// Read the two bytes of register 0x05 of the device at address 0x48 on /dev/i2c-1.
+//		data, err := transactor.I2CTransfer(context.Background(), board.I2CTransfer{
+//			Bus:     "1",
+//			Address: 0x48,
+//			Write:   []byte{0x05},
+//			ReadLen: 2,
+//		}, nil)
+//	}
+//
+// SPITransfer example:
+//
+//	myBoard, err := board.FromRobot(machine, "my_board")
+//	if transactor, ok := myBoard.(board.BusTransactor); ok {
+//		// Read channel 0 of an MCP3008 on /dev/spidev0.0.
+//		data, err := transactor.SPITransfer(context.Background(), board.SPITransfer{
+//			Bus:        "0",
+//			ChipSelect: "0",
+//			BaudHz:     1000000,
+//			Write:      []byte{1, 0x80, 0},
+//		}, nil)
+//	}
+type BusTransactor interface {
+	// I2CTransfer writes transfer.Write to the device and then reads transfer.ReadLen bytes from
+	// it, returning the bytes read.
+	I2CTransfer(ctx context.Context, transfer I2CTransfer, extra map[string]interface{}) ([]byte, error)
+
+	// SPITransfer writes transfer.Write to the device, returning the bytes read while writing.
+	SPITransfer(ctx context.Context, transfer SPITransfer, extra map[string]interface{}) ([]byte, error)
+}
+
+func (c *client) I2CTransfer(ctx context.Context, transfer I2CTransfer, extra map[string]interface{}) ([]byte, error) {
+	if err := transfer.Validate(); err != nil {
+		return nil, err
+	}
+	resp, err := c.DoCommand(ctx, map[string]interface{}{i2cTransferKey: map[string]interface{}{
+		busTransferBusKey:     transfer.Bus,
+		i2cTransferAddressKey: float64(transfer.Address),
+		i2cTransferSpeedKey:   float64(transfer.SpeedHz),
+		busTransferWriteKey:   base64.StdEncoding.EncodeToString(transfer.Write),
+		i2cTransferReadLenKey: float64(transfer.ReadLen),
+		busTransferExtraKey:   extra,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	return bytesFromInterface(resp[busTransferReadKey], busTransferReadKey)
+}
+
+func (c *client) SPITransfer(ctx context.Context, transfer SPITransfer, extra map[string]interface{}) ([]byte, error) {
+	if err := transfer.Validate(); err != nil {
+		return nil, err
+	}
+	resp, err := c.DoCommand(ctx, map[string]interface{}{spiTransferKey: map[string]interface{}{
+		busTransferBusKey:   transfer.Bus,
+		spiTransferCSKey:    transfer.ChipSelect,
+		spiTransferBaudKey:  float64(transfer.BaudHz),
+		spiTransferModeKey:  float64(transfer.Mode),
+		busTransferWriteKey: base64.StdEncoding.EncodeToString(transfer.Write),
+		busTransferExtraKey: extra,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	return bytesFromInterface(resp[busTransferReadKey], busTransferReadKey)
+}
+
+// doBusTransferCommand handles the reserved bus transfer DoCommand keys. It returns false if `req`
+// is not a bus transfer command or the board does not implement BusTransactor.
+func doBusTransferCommand(
+	ctx context.Context, b Board, req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	transactor, ok := b.(BusTransactor)
+	if !ok {
+		return nil, false, nil
+	}
+	cmd := req.GetCommand().AsMap()
+	var read []byte
+	if payload, ok := cmd[i2cTransferKey]; ok {
+		transfer, extra, err := i2cTransferFromArgs(payload)
+		if err != nil {
+			return nil, true, err
+		}
+		if read, err = transactor.I2CTransfer(ctx, transfer, extra); err != nil {
+			return nil, true, err
+		}
+	} else if payload, ok := cmd[spiTransferKey]; ok {
+		transfer, extra, err := spiTransferFromArgs(payload)
+		if err != nil {
+			return nil, true, err
+		}
+		if read, err = transactor.SPITransfer(ctx, transfer, extra); err != nil {
+			return nil, true, err
+		}
+	} else {
+		return nil, false, nil
+	}
+	res, err := protoutils.StructToStructPb(map[string]interface{}{
+		busTransferReadKey: base64.StdEncoding.EncodeToString(read),
+	})
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
+
+func i2cTransferFromArgs(payload interface{}) (I2CTransfer, map[string]interface{}, error) {
+	args, ok := payload.(map[string]interface{})
+	if !ok {
+		return I2CTransfer{}, nil, errors.Errorf("%q must be an object", i2cTransferKey)
+	}
+	address := uintFromInterface(args[i2cTransferAddressKey])
+	if address > maxI2CAddress {
+		return I2CTransfer{}, nil, errors.Errorf("I2C address %#x is not a 7-bit address", address)
+	}
+	transfer := I2CTransfer{
+		Bus:     stringFromInterface(args[busTransferBusKey]),
+		Address: byte(address),
+		SpeedHz: uintFromInterface(args[i2cTransferSpeedKey]),
+		ReadLen: int(uintFromInterface(args[i2cTransferReadLenKey])),
+	}
+	var err error
+	if transfer.Write, err = bytesFromInterface(args[busTransferWriteKey], busTransferWriteKey); err != nil {
+		return I2CTransfer{}, nil, err
+	}
+	if err := transfer.Validate(); err != nil {
+		return I2CTransfer{}, nil, err
+	}
+	extra, _ := args[busTransferExtraKey].(map[string]interface{}) //nolint:errcheck
+	return transfer, extra, nil
+}
+
+func spiTransferFromArgs(payload interface{}) (SPITransfer, map[string]interface{}, error) {
+	args, ok := payload.(map[string]interface{})
+	if !ok {
+		return SPITransfer{}, nil, errors.Errorf("%q must be an object", spiTransferKey)
+	}
+	transfer := SPITransfer{
+		Bus:        stringFromInterface(args[busTransferBusKey]),
+		ChipSelect: stringFromInterface(args[spiTransferCSKey]),
+		BaudHz:     uintFromInterface(args[spiTransferBaudKey]),
+		Mode:       uintFromInterface(args[spiTransferModeKey]),
+	}
+	var err error
+	if transfer.Write, err = bytesFromInterface(args[busTransferWriteKey], busTransferWriteKey); err != nil {
+		return SPITransfer{}, nil, err
+	}
+	if err := transfer.Validate(); err != nil {
+		return SPITransfer{}, nil, err
+	}
+	extra, _ := args[busTransferExtraKey].(map[string]interface{}) //nolint:errcheck
+	return transfer, extra, nil
+}
+
+func stringFromInterface(raw interface{}) string {
+	s, _ := raw.(string) //nolint:errcheck
+	return s
+}
+
+func uintFromInterface(raw interface{}) uint {
+	f, _ := raw.(float64) //nolint:errcheck
+	if f < 0 {
+		return 0
+	}
+	return uint(f)
+}
+
+func bytesFromInterface(raw interface{}, key string) ([]byte, error) {
+	encoded, ok := raw.(string)
+	if !ok {
+		return nil, errors.Errorf("%q must be a base64 string", key)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%q must be a base64 string", key)
+	}
+	return decoded, nil
+}
@@ -2,6 +2,7 @@ package board_test
 
 import (
 	"context"
+	"errors"
 	"net"
 	"testing"
 	"time"
@@ -210,6 +211,72 @@ func TestWorkingClient(t *testing.T) {
 		test.That(t, err, test.ShouldNotBeNil)
 		test.That(t, err, test.ShouldHaveSameTypeAs, viamgrpc.UnimplementedError)
 
+		// SetPWMGroup
+		var actualGroup board.PWMGroup
+		injectBoard.SetPWMGroupFunc = func(ctx context.Context, group board.PWMGroup, extra map[string]interface{}) error {
+			actualGroup = group
+			actualExtra = extra
+			return nil
+		}
+		grouper, ok := client.(board.PWMGrouper)
+		test.That(t, ok, test.ShouldBeTrue)
+		group := board.PWMGroup{
+			Pins:            []string{"one", "two"},
+			FreqHz:          1000,
+			DutyCyclePcts:   []float64{0.25, 0.75},
+			PhaseOffsetPcts: []float64{0, 0.5},
+		}
+		err = grouper.SetPWMGroup(context.Background(), group, expectedExtra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, actualGroup, test.ShouldResemble, group)
+		test.That(t, actualExtra, test.ShouldResemble, expectedExtra)
+		actualExtra = nil
+		err = grouper.SetPWMGroup(context.Background(), board.PWMGroup{Pins: []string{"one"}}, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+
+		// I2CTransfer
+		var actualI2C board.I2CTransfer
+		injectBoard.I2CTransferFunc = func(
+			ctx context.Context, transfer board.I2CTransfer, extra map[string]interface{},
+		) ([]byte, error) {
+			actualI2C = transfer
+			actualExtra = extra
+			return []byte{0x12, 0x34}, nil
+		}
+		transactor, ok := client.(board.BusTransactor)
+		test.That(t, ok, test.ShouldBeTrue)
+		i2cTransfer := board.I2CTransfer{Bus: "1", Address: 0x48, SpeedHz: 400000, Write: []byte{0x05}, ReadLen: 2}
+		read, err := transactor.I2CTransfer(context.Background(), i2cTransfer, expectedExtra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, read, test.ShouldResemble, []byte{0x12, 0x34})
+		test.That(t, actualI2C, test.ShouldResemble, i2cTransfer)
+		test.That(t, actualExtra, test.ShouldResemble, expectedExtra)
+		actualExtra = nil
+		_, err = transactor.I2CTransfer(context.Background(), board.I2CTransfer{Bus: "1", Address: 0x80, ReadLen: 1}, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+
+		// SPITransfer
+		var actualSPI board.SPITransfer
+		injectBoard.SPITransferFunc = func(
+			ctx context.Context, transfer board.SPITransfer, extra map[string]interface{},
+		) ([]byte, error) {
+			actualSPI = transfer
+			return []byte{0, 0x03, 0xff}, nil
+		}
+		spiTransfer := board.SPITransfer{Bus: "0", ChipSelect: "1", BaudHz: 1000000, Mode: 3, Write: []byte{1, 0x80, 0}}
+		read, err = transactor.SPITransfer(context.Background(), spiTransfer, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, read, test.ShouldResemble, []byte{0, 0x03, 0xff})
+		test.That(t, actualSPI, test.ShouldResemble, spiTransfer)
+		injectBoard.SPITransferFunc = func(
+			ctx context.Context, transfer board.SPITransfer, extra map[string]interface{},
+		) ([]byte, error) {
+			return nil, errors.New("bus busy")
+		}
+		_, err = transactor.SPITransfer(context.Background(), spiTransfer, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "bus busy")
+
 		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
 	}
 
@@ -9,7 +9,8 @@ import (
 // wraparound in timestamp values past 4294967295000 nanoseconds (~72 minutes) if the value
 // was originally in microseconds as a 32-bit integer. The timestamp in nanoseconds of the
 // tick SHOULD ONLY BE USED FOR CALCULATING THE TIME ELAPSED BETWEEN CONSECUTIVE TICKS AND NOT
-// AS AN ABSOLUTE TIMESTAMP.
+// AS AN ABSOLUTE TIMESTAMP. Boards use hardware or kernel timestamps when available and
+// MonotonicTickTimestamp otherwise.
 type Tick struct {
 	Name             string
 	High             bool
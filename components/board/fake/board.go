@@ -244,7 +244,7 @@ func (b *Board) StreamTicks(ctx context.Context, interrupts []board.DigitalInter
 				//nolint:gosec
 				randBool := rand.Int()%2 == 0
 				select {
-				case ch <- board.Tick{Name: di.Name(), High: randBool, TimestampNanosec: uint64(time.Now().Unix())}:
+				case ch <- board.Tick{Name: di.Name(), High: randBool, TimestampNanosec: board.MonotonicTickTimestamp()}:
 				default:
 					// if nothing is listening to the channel just do nothing.
 				}
@@ -254,6 +254,37 @@ func (b *Board) StreamTicks(ctx context.Context, interrupts []board.DigitalInter
 	return nil
 }
 
+// SetPWMGroup sets the frequency, phases and duty cycles of the group's pins at once.
+func (b *Board) SetPWMGroup(ctx context.Context, group board.PWMGroup, extra map[string]interface{}) error {
+	if err := group.Validate(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	pins := make([]*GPIOPin, 0, len(group.Pins))
+	for _, name := range group.Pins {
+		pin, ok := b.GPIOPins[name]
+		if !ok {
+			pin = &GPIOPin{}
+			b.GPIOPins[name] = pin
+		}
+		pin.mu.Lock()
+		defer pin.mu.Unlock()
+		pins = append(pins, pin)
+	}
+	for i, pin := range pins {
+		if group.FreqHz != 0 {
+			pin.pwmFreq = group.FreqHz
+		}
+		pin.pwm = group.DutyCyclePcts[i]
+		pin.phase = 0
+		if len(group.PhaseOffsetPcts) > 0 {
+			pin.phase = group.PhaseOffsetPcts[i]
+		}
+	}
+	return nil
+}
+
 // Close attempts to cleanly close each part of the board.
 func (b *Board) Close(ctx context.Context) error {
 	b.mu.Lock()
@@ -312,6 +343,7 @@ type GPIOPin struct {
 	high    bool
 	pwm     float64
 	pwmFreq uint
+	phase   float64
 
 	mu sync.Mutex
 }
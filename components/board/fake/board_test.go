@@ -71,3 +71,41 @@ func TestConfigValidate(t *testing.T) {
 	_, err = validConfig.Validate("path")
 	test.That(t, err, test.ShouldBeNil)
 }
+
+func TestSetPWMGroup(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	cfg := resource.Config{Name: "board1", ConvertedAttributes: &Config{}}
+	b, err := NewBoard(context.Background(), cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	err = b.SetPWMGroup(context.Background(), board.PWMGroup{
+		Pins:            []string{"1", "2", "3"},
+		FreqHz:          20000,
+		DutyCyclePcts:   []float64{0.1, 0.2, 0.3},
+		PhaseOffsetPcts: []float64{0, 0.25, 0.5},
+	}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	for i, name := range []string{"1", "2", "3"} {
+		pin, err := b.GPIOPinByName(name)
+		test.That(t, err, test.ShouldBeNil)
+		duty, err := pin.PWM(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, duty, test.ShouldAlmostEqual, 0.1*float64(i+1))
+		freq, err := pin.PWMFreq(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, freq, test.ShouldEqual, 20000)
+		test.That(t, pin.(*GPIOPin).phase, test.ShouldAlmostEqual, 0.25*float64(i))
+	}
+
+	// invalid groups leave every pin untouched
+	err = b.SetPWMGroup(context.Background(), board.PWMGroup{
+		Pins:          []string{"1", "2"},
+		DutyCyclePcts: []float64{0.5, 2},
+	}, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	pin, err := b.GPIOPinByName("1")
+	test.That(t, err, test.ShouldBeNil)
+	duty, err := pin.PWM(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, duty, test.ShouldAlmostEqual, 0.1)
+}
@@ -1,8 +1,10 @@
 //go:build linux
 
-// Package genericlinux implements a Linux-based board making heavy use of sysfs
-// (https://en.wikipedia.org/wiki/Sysfs). This does not provide a board model itself but provides
-// the underlying logic for any Linux/sysfs based board.
+// Package genericlinux implements a Linux-based board. GPIO pins and digital interrupts (including
+// edge events) use the GPIO character devices (/dev/gpiochipN) rather than the deprecated sysfs
+// GPIO interface, while hardware PWM still uses sysfs (https://en.wikipedia.org/wiki/Sysfs). This
+// does not provide a board model itself but provides the underlying logic for any Linux based
+// board.
 package genericlinux
 
 import (
@@ -26,7 +28,7 @@ import (
 	"go.viam.com/rdk/resource"
 )
 
-// RegisterBoard registers a sysfs based board of the given model.
+// RegisterBoard registers a Linux based board of the given model.
 func RegisterBoard(modelName string, gpioMappings map[string]GPIOBoardMapping) {
 	resource.RegisterComponent(
 		board.API,
@@ -60,6 +62,8 @@ func NewBoard(
 		analogReaders: map[string]*wrappedAnalogReader{},
 		gpios:         map[string]*gpioPin{},
 		interrupts:    map[string]*digitalInterrupt{},
+		i2cBuses:      map[string]buses.I2C{},
+		spiBuses:      map[string]buses.SPI{},
 	}
 
 	if err := b.Reconfigure(ctx, nil, conf); err != nil {
@@ -201,7 +205,8 @@ func (b *Board) reconfigureAnalogReaders(ctx context.Context, newConf *LinuxBoar
 			return errors.Errorf("bad analog pin (%s)", c.Channel)
 		}
 
-		bus := buses.NewSpiBus(c.SPIBus)
+		// Share the bus with SPI transfers so that they do not interleave with reads.
+		bus := b.spiBus(c.SPIBus)
 
 		stillExists[c.Name] = struct{}{}
 		if curr, ok := b.analogReaders[c.Name]; ok {
@@ -363,6 +368,11 @@ type Board struct {
 	gpios      map[string]*gpioPin
 	interrupts map[string]*digitalInterrupt
 
+	// busMu guards the I2C and SPI buses used for bus transfers, which are opened when first used.
+	busMu    sync.Mutex
+	i2cBuses map[string]buses.I2C
+	spiBuses map[string]buses.SPI
+
 	workers *utils.StoppableWorkers
 }
 
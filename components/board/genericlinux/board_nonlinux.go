@@ -14,7 +14,7 @@ import (
 	"go.viam.com/rdk/resource"
 )
 
-// RegisterBoard would register a sysfs based board of the given model. However, this one never
+// RegisterBoard would register a Linux based board of the given model. However, this one never
 // creates a board, and instead returns errors about making a Linux board on a non-Linux OS.
 func RegisterBoard(modelName string, gpioMappings map[string]GPIOBoardMapping) {
 	resource.RegisterComponent(
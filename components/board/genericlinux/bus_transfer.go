@@ -0,0 +1,76 @@
+//go:build linux
+
+package genericlinux
+
+import (
+	"context"
+
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+)
+
+func (b *Board) i2cBus(name string) (buses.I2C, error) {
+	b.busMu.Lock()
+	defer b.busMu.Unlock()
+	if bus, ok := b.i2cBuses[name]; ok {
+		return bus, nil
+	}
+	bus, err := buses.NewI2cBus(name)
+	if err != nil {
+		return nil, err
+	}
+	b.i2cBuses[name] = bus
+	return bus, nil
+}
+
+func (b *Board) spiBus(name string) buses.SPI {
+	b.busMu.Lock()
+	defer b.busMu.Unlock()
+	if bus, ok := b.spiBuses[name]; ok {
+		return bus
+	}
+	bus := buses.NewSpiBus(name)
+	b.spiBuses[name] = bus
+	return bus
+}
+
+// I2CTransfer writes to and then reads from a device on an I2C bus, holding the bus throughout.
+func (b *Board) I2CTransfer(ctx context.Context, transfer board.I2CTransfer, extra map[string]interface{}) (rx []byte, err error) {
+	if err := transfer.Validate(); err != nil {
+		return nil, err
+	}
+	bus, err := b.i2cBus(transfer.Bus)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := bus.OpenHandle(transfer.Address)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = multierr.Combine(err, handle.Close())
+	}()
+	if transfer.SpeedHz != 0 {
+		if err := handle.SetSpeed(ctx, transfer.SpeedHz); err != nil {
+			return nil, err
+		}
+	}
+	return handle.Transfer(ctx, transfer.Write, transfer.ReadLen)
+}
+
+// SPITransfer makes a transfer with a device on an SPI bus, holding the bus throughout.
+func (b *Board) SPITransfer(ctx context.Context, transfer board.SPITransfer, extra map[string]interface{}) (rx []byte, err error) {
+	if err := transfer.Validate(); err != nil {
+		return nil, err
+	}
+	handle, err := b.spiBus(transfer.Bus).OpenHandle()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = multierr.Combine(err, handle.Close())
+	}()
+	return handle.Xfer(ctx, transfer.BaudHz, transfer.ChipSelect, transfer.Mode, transfer.Write)
+}
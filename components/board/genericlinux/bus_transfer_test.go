@@ -0,0 +1,129 @@
+//go:build linux
+
+package genericlinux
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+)
+
+type fakeI2C struct {
+	buses.I2C
+	handle *fakeI2CHandle
+}
+
+func (bus *fakeI2C) OpenHandle(addr byte) (buses.I2CHandle, error) {
+	bus.handle.addr = addr
+	bus.handle.open = true
+	return bus.handle, nil
+}
+
+type fakeI2CHandle struct {
+	buses.I2CHandle
+	addr    byte
+	speedHz uint
+	written []byte
+	open    bool
+}
+
+func (h *fakeI2CHandle) Transfer(ctx context.Context, tx []byte, count int) ([]byte, error) {
+	h.written = tx
+	rx := make([]byte, count)
+	for i := range rx {
+		rx[i] = byte(i + 1)
+	}
+	return rx, nil
+}
+
+func (h *fakeI2CHandle) SetSpeed(ctx context.Context, speedHz uint) error {
+	h.speedHz = speedHz
+	return nil
+}
+
+func (h *fakeI2CHandle) Close() error {
+	h.open = false
+	return nil
+}
+
+type fakeSPI struct {
+	buses.SPI
+	handle *fakeSPIHandle
+}
+
+func (bus *fakeSPI) OpenHandle() (buses.SPIHandle, error) {
+	bus.handle.open = true
+	return bus.handle, nil
+}
+
+type fakeSPIHandle struct {
+	buses.SPIHandle
+	baud       uint
+	chipSelect string
+	mode       uint
+	open       bool
+}
+
+func (h *fakeSPIHandle) Xfer(ctx context.Context, baud uint, chipSelect string, mode uint, tx []byte) ([]byte, error) {
+	h.baud, h.chipSelect, h.mode = baud, chipSelect, mode
+	// Loop the written bytes back, as if MOSI were wired to MISO.
+	return append([]byte{}, tx...), nil
+}
+
+func (h *fakeSPIHandle) Close() error {
+	h.open = false
+	return nil
+}
+
+func TestBusTransfers(t *testing.T) {
+	ctx := context.Background()
+	i2cHandle := &fakeI2CHandle{}
+	spiHandle := &fakeSPIHandle{}
+	b := &Board{
+		Named:    board.Named("foo").AsNamed(),
+		i2cBuses: map[string]buses.I2C{"1": &fakeI2C{handle: i2cHandle}},
+		spiBuses: map[string]buses.SPI{"0": &fakeSPI{handle: spiHandle}},
+	}
+
+	t.Run("I2CTransfer", func(t *testing.T) {
+		rx, err := b.I2CTransfer(ctx, board.I2CTransfer{
+			Bus:     "1",
+			Address: 0x48,
+			SpeedHz: 400000,
+			Write:   []byte{0x05},
+			ReadLen: 2,
+		}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, rx, test.ShouldResemble, []byte{1, 2})
+		test.That(t, i2cHandle.addr, test.ShouldEqual, 0x48)
+		test.That(t, i2cHandle.speedHz, test.ShouldEqual, 400000)
+		test.That(t, i2cHandle.written, test.ShouldResemble, []byte{0x05})
+		test.That(t, i2cHandle.open, test.ShouldBeFalse)
+
+		_, err = b.I2CTransfer(ctx, board.I2CTransfer{Bus: "1", Address: 0x48}, nil)
+		test.That(t, err, test.ShouldBeError, "an I2C transfer needs bytes to write or read")
+	})
+
+	t.Run("SPITransfer", func(t *testing.T) {
+		rx, err := b.SPITransfer(ctx, board.SPITransfer{
+			Bus:        "0",
+			ChipSelect: "1",
+			BaudHz:     1000000,
+			Mode:       3,
+			Write:      []byte{1, 0x80, 0},
+		}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, rx, test.ShouldResemble, []byte{1, 0x80, 0})
+		test.That(t, spiHandle.baud, test.ShouldEqual, 1000000)
+		test.That(t, spiHandle.chipSelect, test.ShouldEqual, "1")
+		test.That(t, spiHandle.mode, test.ShouldEqual, 3)
+		test.That(t, spiHandle.open, test.ShouldBeFalse)
+
+		_, err = b.SPITransfer(ctx, board.SPITransfer{Bus: "0", ChipSelect: "1", BaudHz: 1000000, Mode: 4, Write: []byte{1}}, nil)
+		test.That(t, err, test.ShouldBeError, "SPI mode 4 must be between 0 and 3")
+	})
+}
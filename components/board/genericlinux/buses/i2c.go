@@ -10,6 +10,7 @@ import (
 
 	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/conn/v3/physic"
 	"periph.io/x/host/v3"
 
 	"go.viam.com/rdk/logging"
@@ -101,6 +102,21 @@ func (h *I2cHandle) Read(ctx context.Context, count int) ([]byte, error) {
 	return buffer, nil
 }
 
+// Transfer writes the given bytes to the handle and then reads the given number of bytes from it,
+// with a repeated start in between.
+func (h *I2cHandle) Transfer(ctx context.Context, tx []byte, count int) ([]byte, error) {
+	buffer := make([]byte, count)
+	if err := h.device.Tx(tx, buffer); err != nil {
+		return nil, err
+	}
+	return buffer, nil
+}
+
+// SetSpeed sets the clock speed of the I2C bus. It stays set for every handle on the bus.
+func (h *I2cHandle) SetSpeed(ctx context.Context, speedHz uint) error {
+	return h.parentBus.closeableBus.SetSpeed(physic.Frequency(speedHz) * physic.Hertz)
+}
+
 // This is a private helper function, used to implement the rest of the I2CHandle interface.
 func (h *I2cHandle) transactAtRegister(register byte, w, r []byte) error {
 	if w == nil {
@@ -23,6 +23,13 @@ type I2CHandle interface {
 	ReadBlockData(ctx context.Context, register byte, numBytes uint8) ([]byte, error)
 	WriteBlockData(ctx context.Context, register byte, data []byte) error
 
+	// Transfer writes tx and then reads count bytes in a single transaction, with a repeated start
+	// in between, so that no other controller can use the bus between the write and the read.
+	Transfer(ctx context.Context, tx []byte, count int) ([]byte, error)
+
+	// SetSpeed sets the clock speed of the bus the handle is on.
+	SetSpeed(ctx context.Context, speedHz uint) error
+
 	// Close closes the handle and releases the lock on the bus.
 	Close() error
 }
@@ -9,8 +9,8 @@ import (
 	"go.viam.com/rdk/resource"
 )
 
-// GPIOBoardMapping represents a GPIO pin's location locally within a GPIO chip
-// and globally within sysfs.
+// GPIOBoardMapping represents a GPIO pin's location as a line of a GPIO character device, and its
+// hardware PWM's location within sysfs.
 type GPIOBoardMapping struct {
 	GPIOChipDev    string
 	GPIO           int
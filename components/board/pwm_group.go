@@ -0,0 +1,183 @@
+package board
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+)
+
+// The board proto has no PWM group RPCs. PWM groups are set over DoCommand using the following
+// reserved keys.
+const (
+	setPWMGroupKey    = "set_pwm_group"
+	pwmGroupPinsKey   = "pins"
+	pwmGroupFreqKey   = "freq_hz"
+	pwmGroupDutiesKey = "duty_cycle_pcts"
+	pwmGroupPhasesKey = "phase_offset_pcts"
+	pwmGroupExtraKey  = "extra"
+)
+
+// A PWMGroup is a set of PWM pins that share a frequency and are updated together.
+type PWMGroup struct {
+	// Pins are the names of the pins of the group.
+	Pins []string
+	// FreqHz is the PWM frequency shared by every pin. Zero keeps the current frequency.
+	FreqHz uint
+	// DutyCyclePcts are the duty cycles of each pin, between 0 and 1.
+	DutyCyclePcts []float64
+	// PhaseOffsetPcts optionally delay the start of each pin's period by a fraction of the period,
+	// between 0 and 1. The pins' periods are aligned if unset.
+	PhaseOffsetPcts []float64
+}
+
+// Validate ensures the group is consistent.
+func (g PWMGroup) Validate() error {
+	if len(g.Pins) == 0 {
+		return errors.New("a PWM group needs at least one pin")
+	}
+	seen := map[string]bool{}
+	for _, pin := range g.Pins {
+		if seen[pin] {
+			return errors.Errorf("pin %q appears more than once in the PWM group", pin)
+		}
+		seen[pin] = true
+	}
+	if len(g.DutyCyclePcts) != len(g.Pins) {
+		return errors.Errorf("PWM group has %d pins but %d duty cycles", len(g.Pins), len(g.DutyCyclePcts))
+	}
+	if len(g.PhaseOffsetPcts) != 0 && len(g.PhaseOffsetPcts) != len(g.Pins) {
+		return errors.Errorf("PWM group has %d pins but %d phase offsets", len(g.Pins), len(g.PhaseOffsetPcts))
+	}
+	for _, duty := range g.DutyCyclePcts {
+		if duty < 0 || duty > 1 {
+			return errors.Errorf("duty cycle %v must be between 0 and 1", duty)
+		}
+	}
+	for _, phase := range g.PhaseOffsetPcts {
+		if phase < 0 || phase >= 1 {
+			return errors.Errorf("phase offset %v must be in [0, 1)", phase)
+		}
+	}
+	return nil
+}
+
+// PWMGrouper is implemented by boards whose PWM hardware can drive several pins from a shared
+// timer, such as for multi-phase motor drivers or LED arrays. Setting the pins one at a time with
+// GPIOPin.SetPWM leaves them briefly inconsistent and does not align their periods.
+//
+// SetPWMGroup example:
+//
+//	myBoard, err := board.FromRobot(machine, "my_board")
+//	if grouper, ok := myBoard.(board.PWMGrouper); ok {
+//		// Drive three phases at 20kHz, offset by a third of a period each.
+//		err = grouper.SetPWMGroup(context.Background(), board.PWMGroup{
+//			Pins:            []string{"32", "33", "35"},
+//			FreqHz:          20000,
+//			DutyCyclePcts:   []float64{0.5, 0.5, 0.5},
+//			PhaseOffsetPcts: []float64{0, 1. / 3, 2. / 3},
+//		}, nil)
+//	}
+type PWMGrouper interface {
+	// SetPWMGroup sets the frequency, phases and duty cycles of all pins of the group at once.
+	// Either every pin is updated or none is.
+	SetPWMGroup(ctx context.Context, group PWMGroup, extra map[string]interface{}) error
+}
+
+func (c *client) SetPWMGroup(ctx context.Context, group PWMGroup, extra map[string]interface{}) error {
+	if err := group.Validate(); err != nil {
+		return err
+	}
+	pins := make([]interface{}, 0, len(group.Pins))
+	for _, pin := range group.Pins {
+		pins = append(pins, pin)
+	}
+	args := map[string]interface{}{
+		pwmGroupPinsKey:   pins,
+		pwmGroupFreqKey:   float64(group.FreqHz),
+		pwmGroupDutiesKey: floatsToInterfaces(group.DutyCyclePcts),
+		pwmGroupExtraKey:  extra,
+	}
+	if len(group.PhaseOffsetPcts) > 0 {
+		args[pwmGroupPhasesKey] = floatsToInterfaces(group.PhaseOffsetPcts)
+	}
+	_, err := c.DoCommand(ctx, map[string]interface{}{setPWMGroupKey: args})
+	return err
+}
+
+// doPWMGroupCommand handles the reserved PWM group DoCommand key. It returns false if `req` is not
+// a PWM group command or the board does not implement PWMGrouper.
+func doPWMGroupCommand(ctx context.Context, b Board, req *commonpb.DoCommandRequest) (*commonpb.DoCommandResponse, bool, error) {
+	grouper, ok := b.(PWMGrouper)
+	if !ok {
+		return nil, false, nil
+	}
+	payload, ok := req.GetCommand().AsMap()[setPWMGroupKey]
+	if !ok {
+		return nil, false, nil
+	}
+	args, ok := payload.(map[string]interface{})
+	if !ok {
+		return nil, true, errors.Errorf("%q must be an object", setPWMGroupKey)
+	}
+	var group PWMGroup
+	rawPins, ok := args[pwmGroupPinsKey].([]interface{})
+	if !ok {
+		return nil, true, errors.Errorf("%q must be a list of pin names", pwmGroupPinsKey)
+	}
+	for _, rawPin := range rawPins {
+		pin, ok := rawPin.(string)
+		if !ok {
+			return nil, true, errors.Errorf("%q must be a list of pin names", pwmGroupPinsKey)
+		}
+		group.Pins = append(group.Pins, pin)
+	}
+	freq, _ := args[pwmGroupFreqKey].(float64) //nolint:errcheck
+	group.FreqHz = uint(freq)
+	var err error
+	if group.DutyCyclePcts, err = floatsFromInterface(args[pwmGroupDutiesKey], pwmGroupDutiesKey); err != nil {
+		return nil, true, err
+	}
+	if rawPhases, ok := args[pwmGroupPhasesKey]; ok {
+		if group.PhaseOffsetPcts, err = floatsFromInterface(rawPhases, pwmGroupPhasesKey); err != nil {
+			return nil, true, err
+		}
+	}
+	if err := group.Validate(); err != nil {
+		return nil, true, err
+	}
+	extra, _ := args[pwmGroupExtraKey].(map[string]interface{}) //nolint:errcheck
+	if err := grouper.SetPWMGroup(ctx, group, extra); err != nil {
+		return nil, true, err
+	}
+	res, err := protoutils.StructToStructPb(map[string]interface{}{})
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
+
+func floatsToInterfaces(vals []float64) []interface{} {
+	ret := make([]interface{}, 0, len(vals))
+	for _, val := range vals {
+		ret = append(ret, val)
+	}
+	return ret
+}
+
+func floatsFromInterface(raw interface{}, key string) ([]float64, error) {
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("%q must be a list of numbers", key)
+	}
+	ret := make([]float64, 0, len(list))
+	for _, val := range list {
+		f, ok := val.(float64)
+		if !ok {
+			return nil, errors.Errorf("%q must be a list of numbers, got %v", key, val)
+		}
+		ret = append(ret, f)
+	}
+	return ret, nil
+}
@@ -244,6 +244,9 @@ func (s *serviceServer) GetDigitalInterruptValue(
 	return &pb.GetDigitalInterruptValueResponse{Value: val}, nil
 }
 
+// streamTicksBufferSize is the number of ticks StreamTicks buffers before sending them.
+const streamTicksBufferSize = 1024
+
 func (s *serviceServer) StreamTicks(
 	req *pb.StreamTicksRequest,
 	server pb.BoardService_StreamTicksServer,
@@ -253,7 +256,8 @@ func (s *serviceServer) StreamTicks(
 		return err
 	}
 
-	ticksChan := make(chan Tick)
+	// Buffer ticks so that bursts of interrupts are not held up by the network.
+	ticksChan := make(chan Tick, streamTicksBufferSize)
 	interrupts := []DigitalInterrupt{}
 
 	for _, name := range req.PinNames {
@@ -302,6 +306,12 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := doPWMGroupCommand(ctx, b, req); handled {
+		return resp, err
+	}
+	if resp, handled, err := doBusTransferCommand(ctx, b, req); handled {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, b, req)
 }
 
@@ -0,0 +1,112 @@
+package board
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+)
+
+// processStart anchors MonotonicTickTimestamp.
+var processStart = time.Now()
+
+// MonotonicTickTimestamp returns a Tick timestamp read from the monotonic clock, for boards whose
+// interrupts are not timestamped by hardware. It is unaffected by wall clock changes, so
+// differences between timestamps are accurate up to the latency of the interrupt handler.
+func MonotonicTickTimestamp() uint64 {
+	return uint64(time.Since(processStart).Nanoseconds())
+}
+
+// Defaults for TickBatchOptions.
+const (
+	defaultTickBatchSize    = 256
+	defaultTickBatchLatency = 20 * time.Millisecond
+)
+
+// TickBatchOptions configure how ticks are grouped by StreamTickBatches.
+type TickBatchOptions struct {
+	// MaxSize is the most ticks in a batch. Defaults to 256.
+	MaxSize int
+	// MaxLatency is the longest a tick waits before its batch is delivered. Defaults to 20ms.
+	MaxLatency time.Duration
+}
+
+// StreamTickBatches starts a stream of digital interrupt ticks like Board.StreamTicks, but
+// delivers them in batches, in the order they were received. Consumers of high frequency
+// interrupts, such as encoders, can keep up with a batch per wakeup where they could not with a
+// tick per wakeup. Batches stop being delivered when ctx is done.
+//
+// StreamTickBatches example:
+//
+//	myBoard, err := board.FromRobot(machine, "my_board")
+//	di8, err := myBoard.DigitalInterruptByName("8")
+//	batches := make(chan []board.Tick)
+//	err = board.StreamTickBatches(ctx, myBoard, []board.DigitalInterrupt{di8}, batches, board.TickBatchOptions{}, nil)
+//	for batch := range batches {
+//		for _, tick := range batch {
+//			// Ticks of a batch are in order, so time between them can be computed directly.
+//		}
+//	}
+func StreamTickBatches(
+	ctx context.Context,
+	b Board,
+	interrupts []DigitalInterrupt,
+	ch chan []Tick,
+	opts TickBatchOptions,
+	extra map[string]interface{},
+) error {
+	if opts.MaxSize < 0 || opts.MaxLatency < 0 {
+		return errors.New("tick batch options cannot be negative")
+	}
+	if opts.MaxSize == 0 {
+		opts.MaxSize = defaultTickBatchSize
+	}
+	if opts.MaxLatency == 0 {
+		opts.MaxLatency = defaultTickBatchLatency
+	}
+	ticks := make(chan Tick, opts.MaxSize)
+	if err := b.StreamTicks(ctx, interrupts, ticks, extra); err != nil {
+		return err
+	}
+	utils.PanicCapturingGo(func() {
+		batchTicks(ctx, ticks, ch, opts)
+	})
+	return nil
+}
+
+// batchTicks groups the ticks received on in into batches sent to out, until ctx is done.
+func batchTicks(ctx context.Context, in <-chan Tick, out chan<- []Tick, opts TickBatchOptions) {
+	var (
+		batch    []Tick
+		deadline <-chan time.Time
+	)
+	flush := func() bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case out <- batch:
+		}
+		batch = nil
+		deadline = nil
+		return true
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tick := <-in:
+			batch = append(batch, tick)
+			if len(batch) == 1 {
+				deadline = time.After(opts.MaxLatency)
+			}
+			if len(batch) >= opts.MaxSize && !flush() {
+				return
+			}
+		case <-deadline:
+			if !flush() {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package board_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestStreamTickBatches(t *testing.T) {
+	var ticks chan board.Tick
+	injectBoard := &inject.Board{}
+	injectBoard.StreamTicksFunc = func(
+		ctx context.Context, interrupts []board.DigitalInterrupt, ch chan board.Tick, extra map[string]interface{},
+	) error {
+		ticks = ch
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	batches := make(chan []board.Tick)
+	opts := board.TickBatchOptions{MaxSize: 3, MaxLatency: 50 * time.Millisecond}
+	test.That(t, board.StreamTickBatches(ctx, injectBoard, nil, batches, opts, nil), test.ShouldBeNil)
+
+	// a full batch is delivered right away
+	for i := 0; i < 4; i++ {
+		ticks <- board.Tick{Name: "a", TimestampNanosec: uint64(i)}
+	}
+	batch := <-batches
+	test.That(t, batch, test.ShouldHaveLength, 3)
+	for i, tick := range batch {
+		test.That(t, tick.TimestampNanosec, test.ShouldEqual, i)
+	}
+
+	// a partial batch is delivered after the max latency
+	start := time.Now()
+	batch = <-batches
+	test.That(t, batch, test.ShouldResemble, []board.Tick{{Name: "a", TimestampNanosec: 3}})
+	test.That(t, time.Since(start), test.ShouldBeLessThan, time.Second)
+
+	err := board.StreamTickBatches(ctx, injectBoard, nil, batches, board.TickBatchOptions{MaxSize: -1}, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestMonotonicTickTimestamp(t *testing.T) {
+	first := board.MonotonicTickTimestamp()
+	time.Sleep(time.Millisecond)
+	test.That(t, board.MonotonicTickTimestamp()-first, test.ShouldBeGreaterThanOrEqualTo, uint64(time.Millisecond))
+}
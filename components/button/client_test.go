@@ -4,7 +4,9 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"go.viam.com/test"
 	"go.viam.com/utils/rpc"
 
@@ -56,6 +58,22 @@ func TestClient(t *testing.T) {
 	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, buttonSvc), test.ShouldBeNil)
 
 	injectButton.DoFunc = testutils.EchoFunc
+	pressedAt := time.Unix(1700000000, 0)
+	injectButton.StreamPressesFunc = func(
+		ctx context.Context, extra map[string]interface{}, handle func(button.PressEvent) error,
+	) error {
+		extraOptions = extra
+		for _, press := range []button.PressEvent{
+			{Type: button.PressShort, Time: pressedAt, Duration: 100 * time.Millisecond},
+			{Type: button.PressLong, Time: pressedAt.Add(time.Second), Duration: time.Second},
+		} {
+			if err := handle(press); err != nil {
+				return err
+			}
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}
 
 	go rpcServer.Serve(listener1)
 	defer rpcServer.Stop()
@@ -88,6 +106,28 @@ func TestClient(t *testing.T) {
 		test.That(t, extraOptions, test.ShouldResemble, extra)
 		test.That(t, buttonPushed, test.ShouldEqual, testButtonName)
 
+		// StreamPresses
+		streamer, ok := button1Client.(button.PressStreamer)
+		test.That(t, ok, test.ShouldBeTrue)
+		errDone := errors.New("done")
+		var presses []button.PressEvent
+		extra = map[string]interface{}{"foo": "StreamPresses"}
+		err = streamer.StreamPresses(context.Background(), extra, func(press button.PressEvent) error {
+			presses = append(presses, press)
+			if len(presses) == 2 {
+				return errDone
+			}
+			return nil
+		})
+		test.That(t, err, test.ShouldEqual, errDone)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+		test.That(t, len(presses), test.ShouldEqual, 2)
+		test.That(t, presses[0].Type, test.ShouldEqual, button.PressShort)
+		test.That(t, presses[0].Time.Equal(pressedAt), test.ShouldBeTrue)
+		test.That(t, presses[0].Duration, test.ShouldEqual, 100*time.Millisecond)
+		test.That(t, presses[1].Type, test.ShouldEqual, button.PressLong)
+		test.That(t, presses[1].Duration, test.ShouldEqual, time.Second)
+
 		test.That(t, button1Client.Close(context.Background()), test.ShouldBeNil)
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
@@ -3,6 +3,7 @@ package fake
 
 import (
 	"context"
+	"time"
 
 	"go.viam.com/rdk/components/button"
 	"go.viam.com/rdk/logging"
@@ -20,6 +21,7 @@ type Button struct {
 	resource.Named
 	resource.TriviallyCloseable
 	resource.AlwaysRebuild
+	button.PressBroadcaster
 	logger logging.Logger
 }
 
@@ -34,8 +36,13 @@ func NewButton(
 	return b, nil
 }
 
-// Push logs the push.
+// Push logs the push and reports a short press, or the kind of press given by "press" in `extra`.
 func (b *Button) Push(ctx context.Context, extra map[string]interface{}) error {
 	b.logger.Info("pushed button")
+	pressType := button.PressShort
+	if t, ok := extra["press"].(string); ok {
+		pressType = button.PressType(t)
+	}
+	b.Publish(button.PressEvent{Type: pressType, Time: time.Now()})
 	return nil
 }
@@ -0,0 +1,125 @@
+// Package gpio implements a button wired to a GPIO pin of a board, with debouncing and press
+// classification.
+package gpio
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/button"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("gpio")
+
+const (
+	defaultPollHz = 100
+	maxPollHz     = 1000
+)
+
+// Config is used for converting config attributes.
+type Config struct {
+	Board string `json:"board"`
+	Pin   string `json:"pin"`
+	// ActiveLow is whether the pin reads low while the button is down, as with a pull-up resistor.
+	ActiveLow bool `json:"active_low,omitempty"`
+	// PollHz is how often the pin is read. Defaults to 100Hz.
+	PollHz float64 `json:"poll_hz,omitempty"`
+	button.PressConfig
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.Board == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "board")
+	}
+	if conf.Pin == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "pin")
+	}
+	if conf.PollHz < 0 || conf.PollHz > maxPollHz {
+		return nil, resource.NewConfigValidationError(path, errors.Errorf("poll_hz must be between 0 and %d", maxPollHz))
+	}
+	if err := conf.PressConfig.Validate(path); err != nil {
+		return nil, err
+	}
+	return []string{conf.Board}, nil
+}
+
+func init() {
+	resource.RegisterComponent(button.API, model, resource.Registration[button.Button, *Config]{Constructor: newButton})
+}
+
+// gpioButton reads a button from a GPIO pin and reports its presses.
+type gpioButton struct {
+	resource.Named
+	resource.AlwaysRebuild
+	button.PressBroadcaster
+
+	logger  logging.Logger
+	workers *goutils.StoppableWorkers
+}
+
+func newButton(
+	ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger,
+) (button.Button, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	b, err := board.FromDependencies(deps, newConf.Board)
+	if err != nil {
+		return nil, err
+	}
+	pin, err := b.GPIOPinByName(newConf.Pin)
+	if err != nil {
+		return nil, err
+	}
+	rate := newConf.PollHz
+	if rate == 0 {
+		rate = defaultPollHz
+	}
+
+	btn := &gpioButton{Named: conf.ResourceName().AsNamed(), logger: logger}
+	classifier := button.NewPressClassifier(newConf.PressConfig)
+	btn.workers = goutils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+		for {
+			high, err := pin.Get(ctx, nil)
+			if err == nil {
+				for _, press := range classifier.Update(time.Now(), high != newConf.ActiveLow) {
+					logger.CDebugw(ctx, "button pressed", "type", press.Type, "duration", press.Duration)
+					btn.Publish(press)
+				}
+			} else if ctx.Err() == nil {
+				logger.CWarnw(ctx, "error reading button pin", "pin", newConf.Pin, "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+	return btn, nil
+}
+
+// Push reports a short press, as though the button had been pressed.
+func (b *gpioButton) Push(ctx context.Context, extra map[string]interface{}) error {
+	b.Publish(button.PressEvent{Type: button.PressShort, Time: time.Now()})
+	return nil
+}
+
+func (b *gpioButton) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return nil, resource.ErrDoUnimplemented
+}
+
+func (b *gpioButton) Close(ctx context.Context) error {
+	b.workers.Stop()
+	return nil
+}
@@ -0,0 +1,99 @@
+package gpio
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/button"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestGPIOButton(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	var down atomic.Bool
+	pin := &inject.GPIOPin{}
+	pin.GetFunc = func(ctx context.Context, extra map[string]interface{}) (bool, error) {
+		// active low, so the pin reads low while the button is down
+		return !down.Load(), nil
+	}
+	b := inject.NewBoard("board")
+	b.GPIOPinByNameFunc = func(name string) (board.GPIOPin, error) {
+		test.That(t, name, test.ShouldEqual, "7")
+		return pin, nil
+	}
+	deps := resource.Dependencies{board.Named("board"): b}
+
+	conf := &Config{
+		Board:       "board",
+		Pin:         "7",
+		ActiveLow:   true,
+		PollHz:      1000,
+		PressConfig: button.PressConfig{DebounceMs: -1, LongPressMs: 200, DoublePressWindowMs: -1},
+	}
+	deps2, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps2, test.ShouldResemble, []string{"board"})
+
+	btn, err := newButton(ctx, deps, resource.Config{Name: "button", ConvertedAttributes: conf}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer btn.Close(ctx)
+
+	presses := make(chan button.PressEvent, 10)
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go btn.(button.PressStreamer).StreamPresses(streamCtx, nil, func(press button.PressEvent) error {
+		presses <- press
+		return nil
+	})
+	// wait for the stream to subscribe
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, btn.Push(ctx, nil), test.ShouldBeNil)
+		select {
+		case press := <-presses:
+			test.That(tb, press.Type, test.ShouldEqual, button.PressShort)
+		default:
+			tb.Fatal("no press")
+		}
+	})
+	// drop any presses pushed while waiting
+	time.Sleep(50 * time.Millisecond)
+	for len(presses) > 0 {
+		<-presses
+	}
+
+	down.Store(true)
+	time.Sleep(50 * time.Millisecond)
+	down.Store(false)
+	select {
+	case press := <-presses:
+		test.That(t, press.Type, test.ShouldEqual, button.PressShort)
+		test.That(t, press.Duration, test.ShouldBeGreaterThan, 0)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no short press")
+	}
+
+	down.Store(true)
+	select {
+	case press := <-presses:
+		test.That(t, press.Type, test.ShouldEqual, button.PressLong)
+		test.That(t, press.Duration, test.ShouldEqual, 200*time.Millisecond)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no long press")
+	}
+	down.Store(false)
+
+	conf.Pin = ""
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,485 @@
+package button
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	goutils "go.viam.com/utils"
+	"go.viam.com/utils/protoutils"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The button proto has no press events. Press streams are carried over DoCommand using the
+// following reserved keys. The first request of a stream subscribes, and each request returns the
+// presses since the previous one, waiting a while for one if there are none.
+const (
+	streamPressesKey  = "stream_presses"
+	stopPressesKey    = "stop_presses"
+	subscriptionIDKey = "subscription_id"
+	pressesKey        = "presses"
+	typeKey           = "type"
+	timeUnixNanosKey  = "time_unix_nanos"
+	durationSecsKey   = "duration_secs"
+	extraKey          = "extra"
+)
+
+const (
+	defaultDebounceMs          = 20
+	defaultLongPressMs         = 1000
+	defaultDoublePressWindowMs = 300
+
+	// pressPollTimeout is how long a press stream request waits for a press before returning none.
+	pressPollTimeout = 5 * time.Second
+	// subscriptionIdleTimeout is how long a subscription is kept without its client asking for
+	// presses, after which it is assumed the client went away.
+	subscriptionIdleTimeout = 3 * pressPollTimeout
+	// subscriptionBufferedPresses is the number of presses a subscription holds for its client.
+	subscriptionBufferedPresses = 64
+)
+
+// PressType classifies a press of a button.
+type PressType string
+
+// The kinds of presses.
+const (
+	// PressShort is a press released before it became long and not followed by another press.
+	PressShort = PressType("short")
+	// PressLong is a press held down for the long press time. It is reported while still held.
+	PressLong = PressType("long")
+	// PressDouble is two short presses in quick succession.
+	PressDouble = PressType("double")
+)
+
+// A PressEvent is a classified press of a button.
+type PressEvent struct {
+	Type PressType
+	// Time is when the press began.
+	Time time.Time
+	// Duration is how long the press took, from the button first going down to it last going up,
+	// or, for a long press, to it becoming long.
+	Duration time.Duration
+}
+
+// PressConfig configures how the presses of a button are debounced and classified.
+type PressConfig struct {
+	// DebounceMs is how long the button must stay up or down for the change to count. Defaults to
+	// 20ms; -1 disables debouncing.
+	DebounceMs int `json:"debounce_ms,omitempty"`
+	// LongPressMs is how long the button must be held for a long press. Defaults to 1000ms; -1
+	// disables long presses.
+	LongPressMs int `json:"long_press_ms,omitempty"`
+	// DoublePressWindowMs is how soon after being released the button must be pressed again for a
+	// double press. Defaults to 300ms; -1 disables double presses, so that short presses are
+	// reported without waiting to see whether a second press follows.
+	DoublePressWindowMs int `json:"double_press_window_ms,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf PressConfig) Validate(path string) error {
+	for field, ms := range map[string]int{
+		"debounce_ms":            conf.DebounceMs,
+		"long_press_ms":          conf.LongPressMs,
+		"double_press_window_ms": conf.DoublePressWindowMs,
+	} {
+		if ms < -1 {
+			return resource.NewConfigValidationError(path, errors.Errorf("%s must be -1 to disable or a duration", field))
+		}
+	}
+	return nil
+}
+
+func msOrDefault(ms, def int) time.Duration {
+	switch ms {
+	case 0:
+		return time.Duration(def) * time.Millisecond
+	case -1:
+		return -1
+	default:
+		return time.Duration(ms) * time.Millisecond
+	}
+}
+
+type pressState int
+
+const (
+	pressIdle pressState = iota
+	pressDown
+	pressAwaitingSecond
+	pressSecondDown
+)
+
+// A PressClassifier debounces the raw up and down states of a button and classifies its presses.
+// It is not safe for concurrent use.
+type PressClassifier struct {
+	debounce, long, doubleWindow time.Duration
+
+	raw      bool
+	rawSince time.Time
+	stable   bool
+
+	state      pressState
+	start      time.Time
+	released   time.Time
+	longFired  bool
+	firstPress time.Duration
+}
+
+// NewPressClassifier returns a classifier for a button that starts up.
+func NewPressClassifier(conf PressConfig) *PressClassifier {
+	return &PressClassifier{
+		debounce:     max(msOrDefault(conf.DebounceMs, defaultDebounceMs), 0),
+		long:         msOrDefault(conf.LongPressMs, defaultLongPressMs),
+		doubleWindow: msOrDefault(conf.DoublePressWindowMs, defaultDoublePressWindowMs),
+	}
+}
+
+// Update takes whether the button is down at time `t` and returns the presses completed by then.
+// It should be called regularly even when the state does not change, since long and short presses
+// are recognized by time passing. Times must not go backwards.
+func (pc *PressClassifier) Update(t time.Time, down bool) []PressEvent {
+	if down != pc.raw || pc.rawSince.IsZero() {
+		pc.raw, pc.rawSince = down, t
+	}
+	var events []PressEvent
+	if pc.raw != pc.stable && t.Sub(pc.rawSince) >= pc.debounce {
+		// the change happened when the button first moved, not when it settled
+		pc.stable = pc.raw
+		events = append(events, pc.edge(pc.rawSince, pc.stable)...)
+	}
+	return append(events, pc.elapse(t)...)
+}
+
+// edge handles the button going down or up at `t`.
+func (pc *PressClassifier) edge(t time.Time, down bool) []PressEvent {
+	switch {
+	case down && pc.state == pressIdle:
+		pc.state, pc.start, pc.longFired = pressDown, t, false
+	case down && pc.state == pressAwaitingSecond:
+		pc.state = pressSecondDown
+	case !down && pc.state == pressDown:
+		if pc.longFired {
+			pc.state = pressIdle
+			return nil
+		}
+		if pc.doubleWindow < 0 {
+			pc.state = pressIdle
+			return []PressEvent{{Type: PressShort, Time: pc.start, Duration: t.Sub(pc.start)}}
+		}
+		pc.state, pc.released, pc.firstPress = pressAwaitingSecond, t, t.Sub(pc.start)
+	case !down && pc.state == pressSecondDown:
+		pc.state = pressIdle
+		return []PressEvent{{Type: PressDouble, Time: pc.start, Duration: t.Sub(pc.start)}}
+	}
+	return nil
+}
+
+// elapse handles time passing up to `t`.
+func (pc *PressClassifier) elapse(t time.Time) []PressEvent {
+	switch pc.state {
+	case pressDown:
+		if pc.long >= 0 && !pc.longFired && t.Sub(pc.start) >= pc.long {
+			pc.longFired = true
+			return []PressEvent{{Type: PressLong, Time: pc.start, Duration: pc.long}}
+		}
+	case pressAwaitingSecond:
+		if t.Sub(pc.released) > pc.doubleWindow {
+			pc.state = pressIdle
+			return []PressEvent{{Type: PressShort, Time: pc.start, Duration: pc.firstPress}}
+		}
+	case pressIdle, pressSecondDown:
+	}
+	return nil
+}
+
+// PressStreamer is implemented by buttons that report their presses.
+//
+// StreamPresses example:
+//
+//	myButton, err := button.FromRobot(machine, "my_button")
+//	if streamer, ok := myButton.(button.PressStreamer); ok {
+//		err = streamer.StreamPresses(ctx, nil, func(press button.PressEvent) error {
+//			if press.Type == button.PressLong {
+//				logger.Info("shutting down")
+//			}
+//			return nil
+//		})
+//	}
+type PressStreamer interface {
+	// StreamPresses calls `handle` with each press until ctx is done or `handle` fails.
+	StreamPresses(ctx context.Context, extra map[string]interface{}, handle func(PressEvent) error) error
+}
+
+// A PressBroadcaster delivers the presses of a button to its streams. Button models embed it to
+// implement PressStreamer. The zero value is ready to use.
+type PressBroadcaster struct {
+	mu   sync.Mutex
+	subs map[chan PressEvent]struct{}
+}
+
+// Publish delivers a press to the current streams, dropping it for streams that are not keeping up.
+func (pb *PressBroadcaster) Publish(press PressEvent) {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	for ch := range pb.subs {
+		select {
+		case ch <- press:
+		default:
+		}
+	}
+}
+
+// StreamPresses calls `handle` with each press published until ctx is done or `handle` fails.
+func (pb *PressBroadcaster) StreamPresses(ctx context.Context, extra map[string]interface{}, handle func(PressEvent) error) error {
+	ch := make(chan PressEvent, subscriptionBufferedPresses)
+	pb.mu.Lock()
+	if pb.subs == nil {
+		pb.subs = map[chan PressEvent]struct{}{}
+	}
+	pb.subs[ch] = struct{}{}
+	pb.mu.Unlock()
+	defer func() {
+		pb.mu.Lock()
+		delete(pb.subs, ch)
+		pb.mu.Unlock()
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case press := <-ch:
+			if err := handle(press); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// pressSubscription streams a button's presses on behalf of a client.
+type pressSubscription struct {
+	cancel  context.CancelFunc
+	presses chan PressEvent
+	done    chan struct{}
+	err     error
+
+	mu       sync.Mutex
+	lastPoll time.Time
+}
+
+func (sub *pressSubscription) poll() {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	sub.lastPoll = time.Now()
+}
+
+func (sub *pressSubscription) idle() bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return time.Since(sub.lastPoll) > subscriptionIdleTimeout
+}
+
+// next returns the presses since the previous call, waiting up to pressPollTimeout for one.
+func (sub *pressSubscription) next(ctx context.Context) ([]PressEvent, error) {
+	sub.poll()
+	defer sub.poll()
+	timer := time.NewTimer(pressPollTimeout)
+	defer timer.Stop()
+	var presses []PressEvent
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, nil
+	case press := <-sub.presses:
+		presses = append(presses, press)
+	case <-sub.done:
+		if sub.err != nil && len(sub.presses) == 0 {
+			return nil, sub.err
+		}
+	}
+	for {
+		select {
+		case press := <-sub.presses:
+			presses = append(presses, press)
+		default:
+			return presses, nil
+		}
+	}
+}
+
+// pressStreams are the press subscriptions of the button server.
+type pressStreams struct {
+	mu   sync.Mutex
+	subs map[string]*pressSubscription
+}
+
+func newPressStreams() *pressStreams {
+	return &pressStreams{subs: map[string]*pressSubscription{}}
+}
+
+func (ps *pressStreams) subscribe(streamer PressStreamer, extra map[string]interface{}) string {
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := &pressSubscription{
+		cancel:   cancel,
+		presses:  make(chan PressEvent, subscriptionBufferedPresses),
+		done:     make(chan struct{}),
+		lastPoll: time.Now(),
+	}
+	id := uuid.NewString()
+	ps.mu.Lock()
+	ps.subs[id] = sub
+	ps.mu.Unlock()
+
+	goutils.PanicCapturingGo(func() {
+		defer close(sub.done)
+		defer ps.remove(id)
+		// expire the subscription even if the button is never pressed
+		goutils.PanicCapturingGo(func() {
+			ticker := time.NewTicker(pressPollTimeout)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if sub.idle() {
+						cancel()
+						return
+					}
+				}
+			}
+		})
+		sub.err = streamer.StreamPresses(ctx, extra, func(press PressEvent) error {
+			select {
+			case sub.presses <- press:
+			default:
+				// the client is not keeping up, so drop the oldest press
+				select {
+				case <-sub.presses:
+				default:
+				}
+				sub.presses <- press
+			}
+			return nil
+		})
+	})
+	return id
+}
+
+func (ps *pressStreams) get(id string) (*pressSubscription, bool) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	sub, ok := ps.subs[id]
+	return sub, ok
+}
+
+func (ps *pressStreams) remove(id string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if sub, ok := ps.subs[id]; ok {
+		sub.cancel()
+		delete(ps.subs, id)
+	}
+}
+
+// doPressCommand handles the reserved press stream DoCommand keys. It returns false if `req` is not
+// a press stream command.
+func doPressCommand(
+	ctx context.Context, ps *pressStreams, b Button, req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	cmd := req.GetCommand().AsMap()
+	if payload, ok := cmd[stopPressesKey]; ok {
+		args, _ := payload.(map[string]interface{}) //nolint:errcheck
+		id, _ := args[subscriptionIDKey].(string)   //nolint:errcheck
+		ps.remove(id)
+		return doCommandResponse(map[string]interface{}{})
+	}
+	payload, ok := cmd[streamPressesKey]
+	if !ok {
+		return nil, false, nil
+	}
+	streamer, ok := b.(PressStreamer)
+	if !ok {
+		return nil, true, errors.New("button does not report presses")
+	}
+	args, _ := payload.(map[string]interface{}) //nolint:errcheck
+	id, _ := args[subscriptionIDKey].(string)   //nolint:errcheck
+	if id == "" {
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		id = ps.subscribe(streamer, extra)
+	}
+	sub, ok := ps.get(id)
+	if !ok {
+		return nil, true, errors.Errorf("no press subscription %q", id)
+	}
+	presses, err := sub.next(ctx)
+	if err != nil {
+		return nil, true, err
+	}
+	encoded := make([]interface{}, 0, len(presses))
+	for _, press := range presses {
+		encoded = append(encoded, map[string]interface{}{
+			typeKey:          string(press.Type),
+			timeUnixNanosKey: float64(press.Time.UnixNano()),
+			durationSecsKey:  press.Duration.Seconds(),
+		})
+	}
+	return doCommandResponse(map[string]interface{}{subscriptionIDKey: id, pressesKey: encoded})
+}
+
+func (c *client) StreamPresses(ctx context.Context, extra map[string]interface{}, handle func(PressEvent) error) error {
+	var id string
+	defer func() {
+		if id == "" {
+			return
+		}
+		// let the server stop streaming now rather than when the subscription expires
+		stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		//nolint:errcheck
+		c.DoCommand(stopCtx, map[string]interface{}{stopPressesKey: map[string]interface{}{subscriptionIDKey: id}})
+	}()
+	for {
+		args := map[string]interface{}{subscriptionIDKey: id}
+		if id == "" {
+			args = map[string]interface{}{extraKey: extra}
+		}
+		resp, err := c.DoCommand(ctx, map[string]interface{}{streamPressesKey: args})
+		if err != nil {
+			return err
+		}
+		if id, _ = resp[subscriptionIDKey].(string); id == "" { //nolint:errcheck
+			return errors.Errorf("expected %q in response, got %v", subscriptionIDKey, resp)
+		}
+		presses, _ := resp[pressesKey].([]interface{}) //nolint:errcheck
+		for _, raw := range presses {
+			entry, ok := raw.(map[string]interface{})
+			if !ok {
+				return errors.Errorf("%q entries must be objects", pressesKey)
+			}
+			pressType, _ := entry[typeKey].(string)       //nolint:errcheck
+			nanos, _ := entry[timeUnixNanosKey].(float64) //nolint:errcheck
+			secs, _ := entry[durationSecsKey].(float64)   //nolint:errcheck
+			press := PressEvent{
+				Type:     PressType(pressType),
+				Time:     time.Unix(0, int64(nanos)),
+				Duration: time.Duration(secs * float64(time.Second)),
+			}
+			if err := handle(press); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func doCommandResponse(result map[string]interface{}) (*commonpb.DoCommandResponse, bool, error) {
+	res, err := protoutils.StructToStructPb(result)
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
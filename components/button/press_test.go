@@ -0,0 +1,92 @@
+package button_test
+
+import (
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/button"
+)
+
+// feed runs the classifier over samples every millisecond, where `down` says whether the button is
+// down at each millisecond, and returns the presses with their times in milliseconds.
+func feed(pc *button.PressClassifier, start time.Time, fromMs, toMs int, down func(ms int) bool) []button.PressEvent {
+	var presses []button.PressEvent
+	for ms := fromMs; ms < toMs; ms++ {
+		presses = append(presses, pc.Update(start.Add(time.Duration(ms)*time.Millisecond), down(ms))...)
+	}
+	return presses
+}
+
+func between(from, to int) func(int) bool {
+	return func(ms int) bool { return ms >= from && ms < to }
+}
+
+func TestPressClassifier(t *testing.T) {
+	start := time.Unix(1000, 0)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+
+	t.Run("short press with bounces", func(t *testing.T) {
+		pc := button.NewPressClassifier(button.PressConfig{DebounceMs: 10})
+		presses := feed(pc, start, 0, 1000, func(ms int) bool {
+			// contact bounces for 5ms on press and release
+			if ms >= 100 && ms < 105 || ms >= 200 && ms < 205 {
+				return ms%2 == 0
+			}
+			return ms >= 100 && ms < 200
+		})
+		test.That(t, presses, test.ShouldResemble, []button.PressEvent{
+			{Type: button.PressShort, Time: at(104), Duration: 101 * time.Millisecond},
+		})
+	})
+
+	t.Run("glitch shorter than the debounce is ignored", func(t *testing.T) {
+		pc := button.NewPressClassifier(button.PressConfig{DebounceMs: 10})
+		test.That(t, feed(pc, start, 0, 1000, between(100, 105)), test.ShouldBeEmpty)
+	})
+
+	t.Run("long press is reported while held", func(t *testing.T) {
+		pc := button.NewPressClassifier(button.PressConfig{DebounceMs: -1, LongPressMs: 500})
+		presses := feed(pc, start, 0, 700, between(100, 2000))
+		test.That(t, presses, test.ShouldResemble, []button.PressEvent{
+			{Type: button.PressLong, Time: at(100), Duration: 500 * time.Millisecond},
+		})
+		// releasing does not report another press
+		test.That(t, feed(pc, start, 700, 3000, between(100, 2000)), test.ShouldBeEmpty)
+	})
+
+	t.Run("double press", func(t *testing.T) {
+		pc := button.NewPressClassifier(button.PressConfig{DebounceMs: -1})
+		presses := feed(pc, start, 0, 1000, func(ms int) bool {
+			return ms >= 100 && ms < 150 || ms >= 300 && ms < 350
+		})
+		test.That(t, presses, test.ShouldResemble, []button.PressEvent{
+			{Type: button.PressDouble, Time: at(100), Duration: 250 * time.Millisecond},
+		})
+	})
+
+	t.Run("presses too far apart are two short presses", func(t *testing.T) {
+		pc := button.NewPressClassifier(button.PressConfig{DebounceMs: -1, DoublePressWindowMs: 100})
+		presses := feed(pc, start, 0, 1000, func(ms int) bool {
+			return ms >= 100 && ms < 150 || ms >= 300 && ms < 350
+		})
+		test.That(t, presses, test.ShouldResemble, []button.PressEvent{
+			{Type: button.PressShort, Time: at(100), Duration: 50 * time.Millisecond},
+			{Type: button.PressShort, Time: at(300), Duration: 50 * time.Millisecond},
+		})
+	})
+
+	t.Run("disabled double press reports short presses on release", func(t *testing.T) {
+		pc := button.NewPressClassifier(button.PressConfig{DebounceMs: -1, DoublePressWindowMs: -1})
+		presses := feed(pc, start, 0, 151, between(100, 150))
+		test.That(t, presses, test.ShouldResemble, []button.PressEvent{
+			{Type: button.PressShort, Time: at(100), Duration: 50 * time.Millisecond},
+		})
+	})
+
+	t.Run("config", func(t *testing.T) {
+		test.That(t, button.PressConfig{LongPressMs: -1}.Validate("path"), test.ShouldBeNil)
+		test.That(t, button.PressConfig{DebounceMs: -2}.Validate("path"), test.ShouldNotBeNil)
+	})
+}
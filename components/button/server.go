@@ -14,13 +14,14 @@ import (
 // serviceServer implements the ButtonService from button.proto.
 type serviceServer struct {
 	pb.UnimplementedButtonServiceServer
-	coll resource.APIResourceCollection[Button]
+	coll    resource.APIResourceCollection[Button]
+	presses *pressStreams
 }
 
 // NewRPCServiceServer constructs an gripper gRPC service server.
 // It is intentionally untyped to prevent use outside of tests.
 func NewRPCServiceServer(coll resource.APIResourceCollection[Button]) interface{} {
-	return &serviceServer{coll: coll}
+	return &serviceServer{coll: coll, presses: newPressStreams()}
 }
 
 // Pushes a button.
@@ -40,5 +41,8 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := doPressCommand(ctx, s.presses, button, req); handled {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, button, req)
 }
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"image"
+	"time"
 
 	"github.com/pkg/errors"
 	pb "go.viam.com/api/component/camera/v1"
@@ -42,6 +43,7 @@ func init() {
 		API:        API,
 		MethodName: getImages.String(),
 	}, newGetImagesCollector)
+	robot.RegisterResourceReader(API, readCamera)
 }
 
 // SubtypeName is a constant that identifies the camera resource subtype string.
@@ -76,6 +78,9 @@ type NamedImage struct {
 // ImageMetadata contains useful information about returned image bytes such as its mimetype.
 type ImageMetadata struct {
 	MimeType string
+	// HintApplied is set by cameras that already cropped or downscaled the image according to the
+	// ImageHint in the request's extra, so that it is not applied again.
+	HintApplied bool
 }
 
 // A Camera is a resource that can capture frames.
@@ -201,3 +206,29 @@ func FromRobot(r robot.Robot, name string) (Camera, error) {
 func NamesFromRobot(r robot.Robot) []string {
 	return robot.NamesByAPI(r, API)
 }
+
+// readCamera reads the metadata of the images of a camera for a bulk read, rather than the images
+// themselves, so that dashboards can tell whether cameras are producing images without streaming them.
+func readCamera(ctx context.Context, res resource.Resource, _ map[string]interface{}) (map[string]interface{}, error) {
+	c, err := resource.AsType[Camera](res)
+	if err != nil {
+		return nil, err
+	}
+	imgs, metadata, err := c.Images(ctx)
+	if err != nil {
+		return nil, err
+	}
+	images := make([]interface{}, 0, len(imgs))
+	for _, img := range imgs {
+		bounds := img.Image.Bounds()
+		images = append(images, map[string]interface{}{
+			"source_name": img.SourceName,
+			"width":       bounds.Dx(),
+			"height":      bounds.Dy(),
+		})
+	}
+	return map[string]interface{}{
+		"images":      images,
+		"captured_at": metadata.CapturedAt.Format(time.RFC3339Nano),
+	}, nil
+}
@@ -43,6 +43,9 @@ var (
 	// ErrUnknownSubscriptionID indicates that a SubscriptionID is unknown.
 	ErrUnknownSubscriptionID = errors.New("subscriptionID Unknown")
 	readRTPTimeout           = time.Millisecond * 200
+	// resumeTrackTimeout is how long to wait for a track lost with its connection to be added back
+	// once the connection is re-established.
+	resumeTrackTimeout = 10 * time.Second
 )
 
 type bufAndCB struct {
@@ -251,6 +254,9 @@ func (c *client) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, err
 	if ctx.Value(data.FromDMContextKey{}) == true {
 		extra[data.FromDMString] = true
 	}
+	if opts, ok := PointCloudFilterFromContext(ctx); ok {
+		addPointCloudFilterToExtra(extra, opts)
+	}
 	extraStructPb, err := goprotoutils.StructToStructPb(extra)
 	if err != nil {
 		return nil, err
@@ -521,6 +527,13 @@ func (c *client) addOnTrackFunc(
 	return func(tr *webrtc.TrackRemote, r *webrtc.RTPReceiver) {
 		// Our `OnTrack` was called. Inform `SubscribeRTP` that getting video data was successful.
 		close(trackReceived)
+		// Remember the connection the track was received over, such that the track can be resumed
+		// over the next one if this one is lost.
+		pc := c.conn.PeerConn()
+		var replaced <-chan struct{}
+		if rConn, ok := c.conn.(grpc.ReplaceableConn); ok {
+			replaced = rConn.Replaced()
+		}
 		c.activeBackgroundWorkers.Add(1)
 		goutils.ManagedGo(func() {
 			var count atomic.Uint64
@@ -555,6 +568,10 @@ func (c *client) addOnTrackFunc(
 					} else {
 						c.logger.Warnw("ReadRTP error", "generationId", generationID, "err", err)
 					}
+					if c.resumeTrack(healthyClientCh, generationID, pc, replaced) {
+						c.logger.Infow("ReadRTP resumed track after reconnecting", "generationId", generationID)
+						return
+					}
 					// NOTE: (Nick S) We need to remember which subscriptions are consuming packets
 					// from to which tr *webrtc.TrackRemote so that we can terminate the child subscriptions
 					// when their track terminate.
@@ -583,6 +600,79 @@ func (c *client) addOnTrackFunc(
 	}
 }
 
+// resumeTrack re-adds the stream of a track that ended because its connection was lost, once the
+// connection is re-established, such that the subscriptions of the track's generation keep
+// receiving packets rather than being terminated. It returns false if the track ended for any other
+// reason, or could not be resumed.
+func (c *client) resumeTrack(
+	healthyClientCh chan struct{},
+	generationID int,
+	pc *webrtc.PeerConnection,
+	replaced <-chan struct{},
+) bool {
+	if pc == nil || replaced == nil || pc.ConnectionState() == webrtc.PeerConnectionStateConnected {
+		return false
+	}
+	c.logger.Infow("track ended with its connection, waiting to reconnect", "generationId", generationID)
+	ticker := time.NewTicker(readRTPTimeout)
+	defer ticker.Stop()
+	for waiting := true; waiting; {
+		select {
+		case <-healthyClientCh:
+			return false
+		case <-replaced:
+			waiting = false
+		case <-ticker.C:
+			// Stop waiting once every subscription to the track has unsubscribed.
+			if !c.hasRunningSubs(generationID) {
+				return false
+			}
+		}
+	}
+
+	c.rtpPassthroughMu.Lock()
+	defer c.rtpPassthroughMu.Unlock()
+	tracker, ok := c.conn.(grpc.Tracker)
+	if !ok || !c.hasRunningSubsLocked(generationID) {
+		return false
+	}
+	trackReceived, trackClosed := make(chan struct{}), make(chan struct{})
+	tracker.AddOnTrackSub(c.trackName(), c.addOnTrackFunc(healthyClientCh, trackReceived, trackClosed, generationID))
+	defer tracker.RemoveOnTrackSub(c.trackName())
+
+	ctx, cancel := context.WithTimeout(context.Background(), resumeTrackTimeout)
+	defer cancel()
+	if _, err := c.streamClient.AddStream(ctx, &streampb.AddStreamRequest{Name: c.trackName()}); err != nil {
+		c.logger.Warnw("failed to resume track after reconnecting", "generationId", generationID, "err", err)
+		return false
+	}
+	select {
+	case <-ctx.Done():
+		c.logger.Warnw("track not received after reconnecting", "generationId", generationID, "err", ctx.Err())
+		return false
+	case <-healthyClientCh:
+		return false
+	case <-trackReceived:
+	}
+	c.trackClosed = trackClosed
+	return true
+}
+
+func (c *client) hasRunningSubs(generationID int) bool {
+	c.rtpPassthroughMu.Lock()
+	defer c.rtpPassthroughMu.Unlock()
+	return c.hasRunningSubsLocked(generationID)
+}
+
+func (c *client) hasRunningSubsLocked(generationID int) bool {
+	for _, subID := range c.associatedSubs[generationID] {
+		if _, ok := c.runningStreams[subID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Unsubscribe terminates a subscription to receive RTP packets.
 //
 // It is strongly recommended to set a timeout on ctx as the underlying WebRTC peer connection's
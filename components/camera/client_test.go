@@ -193,6 +193,12 @@ func TestClient(t *testing.T) {
 		_, got := pcB.At(5, 5, 5)
 		test.That(t, got, test.ShouldBeTrue)
 
+		// the point is ~8.7mm from the origin, so clipping to 5mm filters it out on the server
+		filterCtx := camera.WithPointCloudFilter(context.Background(), pointcloud.FilterOptions{MaxRangeMM: 5})
+		pcB, err = camera1Client.NextPointCloud(filterCtx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pcB.Size(), test.ShouldEqual, 0)
+
 		propsB, err := camera1Client.Properties(context.Background())
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, propsB.SupportsPCD, test.ShouldBeTrue)
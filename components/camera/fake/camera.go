@@ -73,6 +73,10 @@ func NewCamera(
 	if newConf.Height > 0 {
 		height = newConf.Height
 	}
+	processor, err := camera.NewImageProcessor(newConf.Pipeline)
+	if err != nil {
+		return nil, err
+	}
 	var resModel *transform.PinholeCameraModel
 	if newConf.Model {
 		resModel = fakeModel(width, height)
@@ -89,6 +93,7 @@ func NewCamera(
 		Height:         height,
 		Animated:       newConf.Animated,
 		RTPPassthrough: newConf.RTPPassthrough,
+		processor:      processor,
 		bufAndCBByID:   make(map[rtppassthrough.SubscriptionID]bufAndCB),
 		logger:         logger,
 	}
@@ -121,6 +126,8 @@ type Config struct {
 	Animated       bool `json:"animated,omitempty"`
 	RTPPassthrough bool `json:"rtp_passthrough,omitempty"`
 	Model          bool `json:"model,omitempty"`
+
+	Pipeline camera.ProcessingPipeline `json:"pipeline,omitempty"`
 }
 
 // Validate checks that the config attributes are valid for a fake camera.
@@ -141,6 +148,10 @@ func (conf *Config) Validate(path string) ([]string, error) {
 		return nil, fmt.Errorf("odd-number resolutions cannot be rendered, cannot use a width of %d", conf.Width)
 	}
 
+	if err := conf.Pipeline.Validate(path); err != nil {
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -187,13 +198,23 @@ type Camera struct {
 	bufAndCBByID            map[rtppassthrough.SubscriptionID]bufAndCB
 	cacheImage              image.Image
 	cachePointCloud         pointcloud.PointCloud
+	processor               *camera.ImageProcessor
 	logger                  logging.Logger
 }
 
-// Read always returns the same image of a yellow to blue gradient.
+// Read always returns the same image of a yellow to blue gradient, passed through the camera's
+// processing pipeline.
 func (c *Camera) Read(ctx context.Context) (image.Image, func(), error) {
+	img, err := c.processor.Process(ctx, c.gradient())
+	if err != nil {
+		return nil, nil, err
+	}
+	return img, func() {}, nil
+}
+
+func (c *Camera) gradient() image.Image {
 	if c.cacheImage != nil {
-		return c.cacheImage, func() {}, nil
+		return c.cacheImage
 	}
 	width := float64(c.Width)
 	height := float64(c.Height)
@@ -222,7 +243,7 @@ func (c *Camera) Read(ctx context.Context) (image.Image, func(), error) {
 	if !c.Animated {
 		c.cacheImage = img
 	}
-	return rimage.ConvertImage(img), func() {}, nil
+	return rimage.ConvertImage(img)
 }
 
 // NextPointCloud always returns a pointcloud of a yellow to blue gradient, with the depth determined by the intensity of blue.
@@ -76,6 +76,36 @@ func TestCameraValidationAndCreation(t *testing.T) {
 	test.That(t, camera.Close(context.Background()), test.ShouldBeNil)
 }
 
+func TestProcessingPipeline(t *testing.T) {
+	cfg := resource.Config{
+		Name:  "test1",
+		API:   camera.API,
+		Model: Model,
+		ConvertedAttributes: &Config{
+			Width:  100,
+			Height: 50,
+			Pipeline: camera.ProcessingPipeline{
+				{Type: camera.ProcessingRotate, Attributes: utils.AttributeMap{"angle_degs": 90}},
+				{Type: camera.ProcessingResize, Attributes: utils.AttributeMap{"width_px": 10, "height_px": 20}},
+			},
+		},
+	}
+	_, err := cfg.Validate("", camera.API.SubtypeName)
+	test.That(t, err, test.ShouldBeNil)
+
+	cam, err := NewCamera(context.Background(), nil, cfg, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	defer cam.Close(context.Background())
+
+	img, err := camera.DecodeImageFromCamera(context.Background(), utils.MimeTypePNG, nil, cam)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, img.Bounds(), test.ShouldResemble, image.Rect(0, 0, 10, 20))
+
+	badCfg := &Config{Pipeline: camera.ProcessingPipeline{{Type: "blur"}}}
+	_, err = badCfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
 func TestRTPPassthrough(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 
@@ -51,10 +51,11 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 	return []string{}, nil
 }
 
-var model = resource.DefaultModelFamily.WithModel("ffmpeg")
+// Model is the model of the ffmpeg camera.
+var Model = resource.DefaultModelFamily.WithModel("ffmpeg")
 
 func init() {
-	resource.RegisterComponent(camera.API, model, resource.Registration[camera.Camera, *Config]{
+	resource.RegisterComponent(camera.API, Model, resource.Registration[camera.Camera, *Config]{
 		Constructor: func(
 			ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger,
 		) (camera.Camera, error) {
@@ -0,0 +1,175 @@
+package camera
+
+import (
+	"context"
+	"image"
+
+	"github.com/pkg/errors"
+	"golang.org/x/image/draw"
+
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/utils"
+)
+
+// The camera proto has no region of interest or resolution fields on GetImage. Image hints are
+// carried in the request's extra using the following reserved keys.
+const (
+	roiKey       = "roi"
+	roiXMinKey   = "x_min_px"
+	roiYMinKey   = "y_min_px"
+	roiXMaxKey   = "x_max_px"
+	roiYMaxKey   = "y_max_px"
+	maxWidthKey  = "max_width_px"
+	maxHeightKey = "max_height_px"
+)
+
+// ImageHint asks a camera for part of its frame or for a smaller image, letting clients such as
+// vision pipelines that only need part of the frame save bandwidth. The server applies the hint to
+// the camera's image unless the camera reports that it already did so in ImageMetadata.
+type ImageHint struct {
+	// ROI is the region of the frame to return, in pixels. An empty ROI returns the whole frame.
+	ROI image.Rectangle
+	// MaxWidth and MaxHeight bound the size of the returned image. The image is downscaled,
+	// preserving its aspect ratio, to fit within them. Zero means no bound.
+	MaxWidth  int
+	MaxHeight int
+}
+
+// IsZero returns true if the hint does not ask for any change to the image.
+func (h ImageHint) IsZero() bool {
+	return h.ROI.Empty() && h.MaxWidth == 0 && h.MaxHeight == 0
+}
+
+// ExtraWithImageHint returns a copy of extra containing the hint, to be passed to Image.
+//
+// ExtraWithImageHint example:
+//
+//	myCamera, err := camera.FromRobot(machine, "my_camera")
+//	// Get the top left 100x100 pixels of the frame.
+//	extra := camera.ExtraWithImageHint(nil, camera.ImageHint{ROI: image.Rect(0, 0, 100, 100)})
+//	imgBytes, metadata, err := myCamera.Image(context.Background(), utils.MimeTypeJPEG, extra)
+func ExtraWithImageHint(extra map[string]interface{}, hint ImageHint) map[string]interface{} {
+	out := make(map[string]interface{}, len(extra)+3)
+	for k, v := range extra {
+		out[k] = v
+	}
+	if !hint.ROI.Empty() {
+		out[roiKey] = map[string]interface{}{
+			roiXMinKey: hint.ROI.Min.X,
+			roiYMinKey: hint.ROI.Min.Y,
+			roiXMaxKey: hint.ROI.Max.X,
+			roiYMaxKey: hint.ROI.Max.Y,
+		}
+	}
+	if hint.MaxWidth > 0 {
+		out[maxWidthKey] = hint.MaxWidth
+	}
+	if hint.MaxHeight > 0 {
+		out[maxHeightKey] = hint.MaxHeight
+	}
+	return out
+}
+
+// ImageHintFromExtra reads the image hint from an Image request's extra. A camera that applies
+// hints itself, for example by configuring its sensor, should use this and set HintApplied in its
+// ImageMetadata.
+func ImageHintFromExtra(extra map[string]interface{}) (ImageHint, error) {
+	var hint ImageHint
+	if rawROI, ok := extra[roiKey]; ok {
+		roi, ok := rawROI.(map[string]interface{})
+		if !ok {
+			return ImageHint{}, errors.Errorf("%q must be an object", roiKey)
+		}
+		var coords [4]int
+		for idx, key := range []string{roiXMinKey, roiYMinKey, roiXMaxKey, roiYMaxKey} {
+			val, err := intFromExtra(roi, key)
+			if err != nil {
+				return ImageHint{}, errors.Wrap(err, roiKey)
+			}
+			coords[idx] = val
+		}
+		hint.ROI = image.Rect(coords[0], coords[1], coords[2], coords[3])
+		if hint.ROI.Empty() {
+			return ImageHint{}, errors.Errorf("%q must have a positive width and height", roiKey)
+		}
+	}
+	var err error
+	if _, ok := extra[maxWidthKey]; ok {
+		if hint.MaxWidth, err = intFromExtra(extra, maxWidthKey); err != nil {
+			return ImageHint{}, err
+		}
+	}
+	if _, ok := extra[maxHeightKey]; ok {
+		if hint.MaxHeight, err = intFromExtra(extra, maxHeightKey); err != nil {
+			return ImageHint{}, err
+		}
+	}
+	if hint.MaxWidth < 0 || hint.MaxHeight < 0 {
+		return ImageHint{}, errors.Errorf("%q and %q cannot be negative", maxWidthKey, maxHeightKey)
+	}
+	return hint, nil
+}
+
+// intFromExtra reads an integer from a map that may have come from JSON or protobuf, where numbers
+// are float64s.
+func intFromExtra(m map[string]interface{}, key string) (int, error) {
+	switch val := m[key].(type) {
+	case int:
+		return val, nil
+	case float64:
+		return int(val), nil
+	default:
+		return 0, errors.Errorf("%q must be a number", key)
+	}
+}
+
+// ApplyImageHint crops and downscales encoded image bytes according to the hint, returning them
+// re-encoded with the same MIME type.
+func ApplyImageHint(ctx context.Context, imgBytes []byte, mimeType string, hint ImageHint) ([]byte, error) {
+	if hint.IsZero() {
+		return imgBytes, nil
+	}
+	mimeType, _ = utils.CheckLazyMIMEType(mimeType)
+	img, err := rimage.DecodeImage(ctx, imgBytes, mimeType)
+	if err != nil {
+		return nil, err
+	}
+	if mimeType == utils.MimeTypeRawDepth {
+		if img, err = rimage.ConvertImageToGray16(img); err != nil {
+			return nil, err
+		}
+	}
+
+	bounds := img.Bounds()
+	src := bounds
+	if !hint.ROI.Empty() {
+		src = hint.ROI.Add(bounds.Min).Intersect(bounds)
+		if src.Empty() {
+			return nil, errors.Errorf("region of interest %v is outside of the image bounds %v", hint.ROI, bounds)
+		}
+	}
+
+	width, height := src.Dx(), src.Dy()
+	scale := 1.0
+	if hint.MaxWidth > 0 && width > hint.MaxWidth {
+		scale = float64(hint.MaxWidth) / float64(width)
+	}
+	if hint.MaxHeight > 0 && height > hint.MaxHeight {
+		if s := float64(hint.MaxHeight) / float64(height); s < scale {
+			scale = s
+		}
+	}
+	dstRect := image.Rect(0, 0, max(1, int(float64(width)*scale)), max(1, int(float64(height)*scale)))
+
+	var dst draw.Image
+	if _, ok := img.(*image.Gray16); ok {
+		dst = image.NewGray16(dstRect)
+	} else {
+		// The scaled image is only encoded, so its pixels are reused for the next frame.
+		rgba, release := rimage.NewPooledRGBA(dstRect)
+		defer release()
+		dst = rgba
+	}
+	draw.NearestNeighbor.Scale(dst, dstRect, img, src, draw.Src, nil)
+	return rimage.EncodeImage(ctx, dst, mimeType)
+}
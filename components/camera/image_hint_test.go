@@ -0,0 +1,88 @@
+package camera_test
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/utils"
+)
+
+func TestImageHintExtra(t *testing.T) {
+	hint := camera.ImageHint{ROI: image.Rect(10, 20, 30, 40), MaxWidth: 5, MaxHeight: 6}
+	extra := camera.ExtraWithImageHint(map[string]interface{}{"foo": "bar"}, hint)
+	test.That(t, extra["foo"], test.ShouldEqual, "bar")
+
+	parsed, err := camera.ImageHintFromExtra(extra)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, parsed, test.ShouldResemble, hint)
+
+	parsed, err = camera.ImageHintFromExtra(nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, parsed.IsZero(), test.ShouldBeTrue)
+
+	// numbers decoded from protobuf are float64s
+	parsed, err = camera.ImageHintFromExtra(map[string]interface{}{"max_height_px": 480.0})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, parsed.MaxHeight, test.ShouldEqual, 480)
+
+	_, err = camera.ImageHintFromExtra(map[string]interface{}{
+		"roi": map[string]interface{}{"x_min_px": 5, "y_min_px": 5, "x_max_px": 5, "y_max_px": 10},
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "positive width and height")
+
+	_, err = camera.ImageHintFromExtra(map[string]interface{}{"max_width_px": -1})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestApplyImageHint(t *testing.T) {
+	ctx := context.Background()
+	img := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	imgBytes, err := rimage.EncodeImage(ctx, img, utils.MimeTypePNG)
+	test.That(t, err, test.ShouldBeNil)
+
+	t.Run("no hint", func(t *testing.T) {
+		out, err := camera.ApplyImageHint(ctx, imgBytes, utils.MimeTypePNG, camera.ImageHint{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, out, test.ShouldResemble, imgBytes)
+	})
+
+	t.Run("downscale keeps aspect ratio", func(t *testing.T) {
+		out, err := camera.ApplyImageHint(ctx, imgBytes, utils.MimeTypePNG, camera.ImageHint{MaxWidth: 50, MaxHeight: 50})
+		test.That(t, err, test.ShouldBeNil)
+		decoded, err := rimage.DecodeImage(ctx, out, utils.MimeTypePNG)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, decoded.Bounds(), test.ShouldResemble, image.Rect(0, 0, 50, 25))
+	})
+
+	t.Run("roi is clipped to the image", func(t *testing.T) {
+		out, err := camera.ApplyImageHint(ctx, imgBytes, utils.MimeTypePNG, camera.ImageHint{ROI: image.Rect(90, 40, 200, 200)})
+		test.That(t, err, test.ShouldBeNil)
+		decoded, err := rimage.DecodeImage(ctx, out, utils.MimeTypePNG)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, decoded.Bounds(), test.ShouldResemble, image.Rect(0, 0, 10, 10))
+
+		_, err = camera.ApplyImageHint(ctx, imgBytes, utils.MimeTypePNG, camera.ImageHint{ROI: image.Rect(200, 200, 300, 300)})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		dm := rimage.NewEmptyDepthMap(20, 10)
+		dm.Set(5, 5, 1000)
+		depthBytes, err := rimage.EncodeImage(ctx, dm, utils.MimeTypeRawDepth)
+		test.That(t, err, test.ShouldBeNil)
+		out, err := camera.ApplyImageHint(ctx, depthBytes, utils.MimeTypeRawDepth, camera.ImageHint{ROI: image.Rect(5, 5, 10, 10)})
+		test.That(t, err, test.ShouldBeNil)
+		decoded, err := rimage.DecodeImage(ctx, out, utils.MimeTypeRawDepth)
+		test.That(t, err, test.ShouldBeNil)
+		cropped, err := rimage.ConvertImageToDepthMap(ctx, decoded)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, cropped.Bounds(), test.ShouldResemble, image.Rect(0, 0, 5, 5))
+		test.That(t, cropped.GetDepth(0, 0), test.ShouldEqual, rimage.Depth(1000))
+	})
+}
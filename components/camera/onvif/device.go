@@ -0,0 +1,232 @@
+package onvif
+
+import (
+	"context"
+	"encoding/xml"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultDevicePath is where ONVIF devices serve their device service.
+const defaultDevicePath = "/onvif/device_service"
+
+// Profile is a media profile of an ONVIF device, which selects the stream, encoding and PTZ
+// configuration to use.
+type Profile struct {
+	Token    string `xml:"token,attr"`
+	Name     string `xml:"Name"`
+	Encoding string `xml:"VideoEncoderConfiguration>Encoding"`
+	Width    int    `xml:"VideoEncoderConfiguration>Resolution>Width"`
+	Height   int    `xml:"VideoEncoderConfiguration>Resolution>Height"`
+	PTZ      *struct {
+		Token string `xml:"token,attr"`
+	} `xml:"PTZConfiguration"`
+}
+
+// DeviceInformation identifies an ONVIF device.
+type DeviceInformation struct {
+	Manufacturer    string `xml:"Manufacturer"`
+	Model           string `xml:"Model"`
+	FirmwareVersion string `xml:"FirmwareVersion"`
+	SerialNumber    string `xml:"SerialNumber"`
+	HardwareID      string `xml:"HardwareId"`
+}
+
+// A Device is a connection to the services of an ONVIF device.
+type Device struct {
+	soap      *soapClient
+	deviceURL string
+	mediaURL  string
+	ptzURL    string
+}
+
+// DeviceURL returns the URL of the device service of an ONVIF device at `address`, which is
+// either such a URL or the host, and optionally port, of the device.
+func DeviceURL(address string) (string, error) {
+	if address == "" {
+		return "", errors.New("ONVIF device address is empty")
+	}
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid ONVIF device address %q", address)
+	}
+	if u.Host == "" {
+		return "", errors.Errorf("invalid ONVIF device address %q", address)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = defaultDevicePath
+	}
+	return u.String(), nil
+}
+
+// Connect connects to the ONVIF device at `address` (see DeviceURL) and finds its services.
+func Connect(ctx context.Context, address, username, password string) (*Device, error) {
+	deviceURL, err := DeviceURL(address)
+	if err != nil {
+		return nil, err
+	}
+	d := &Device{
+		soap: &soapClient{
+			httpClient: &http.Client{Timeout: 10 * time.Second},
+			username:   username,
+			password:   password,
+		},
+		deviceURL: deviceURL,
+	}
+	d.syncClock(ctx)
+
+	var caps struct {
+		Media string `xml:"Capabilities>Media>XAddr"`
+		PTZ   string `xml:"Capabilities>PTZ>XAddr"`
+	}
+	if err := d.soap.call(ctx, deviceURL, &struct {
+		XMLName  xml.Name `xml:"http://www.onvif.org/ver10/device/wsdl GetCapabilities"`
+		Category string   `xml:"Category"`
+	}{Category: "All"}, &caps); err != nil {
+		return nil, errors.Wrap(err, "failed to get capabilities of ONVIF device")
+	}
+	if caps.Media == "" {
+		return nil, errors.Errorf("ONVIF device %s has no media service", deviceURL)
+	}
+	d.mediaURL = d.reachable(caps.Media)
+	if caps.PTZ != "" {
+		d.ptzURL = d.reachable(caps.PTZ)
+	}
+	return d, nil
+}
+
+// syncClock measures how far the device's clock is from ours, since the timestamps of
+// authenticated requests must be within a few seconds of the device's clock. Devices that do not
+// report their time are assumed to be in sync.
+func (d *Device) syncClock(ctx context.Context) {
+	var resp struct {
+		Year   int `xml:"SystemDateAndTime>UTCDateTime>Date>Year"`
+		Month  int `xml:"SystemDateAndTime>UTCDateTime>Date>Month"`
+		Day    int `xml:"SystemDateAndTime>UTCDateTime>Date>Day"`
+		Hour   int `xml:"SystemDateAndTime>UTCDateTime>Time>Hour"`
+		Minute int `xml:"SystemDateAndTime>UTCDateTime>Time>Minute"`
+		Second int `xml:"SystemDateAndTime>UTCDateTime>Time>Second"`
+	}
+	// the device's time can be read without authenticating
+	unauthenticated := &soapClient{httpClient: d.soap.httpClient}
+	if err := unauthenticated.call(ctx, d.deviceURL, &struct {
+		XMLName xml.Name `xml:"http://www.onvif.org/ver10/device/wsdl GetSystemDateAndTime"`
+	}{}, &resp); err != nil || resp.Year == 0 {
+		return
+	}
+	deviceTime := time.Date(resp.Year, time.Month(resp.Month), resp.Day, resp.Hour, resp.Minute, resp.Second, 0, time.UTC)
+	d.soap.clockOffset = time.Until(deviceTime)
+}
+
+// reachable replaces the host of a service address reported by the device with the host the
+// device was reached at, as devices behind NAT or with several interfaces often report addresses
+// that cannot be reached.
+func (d *Device) reachable(serviceURL string) string {
+	service, err := url.Parse(serviceURL)
+	if err != nil {
+		return serviceURL
+	}
+	device, err := url.Parse(d.deviceURL)
+	if err != nil {
+		return serviceURL
+	}
+	switch {
+	case service.Scheme == device.Scheme && (service.Port() == "" || service.Port() == device.Port()):
+		// services on the device's own port follow it through port forwarding
+		service.Host = device.Host
+	case service.Port() != "":
+		service.Host = net.JoinHostPort(device.Hostname(), service.Port())
+	case strings.Contains(device.Hostname(), ":"):
+		service.Host = "[" + device.Hostname() + "]"
+	default:
+		service.Host = device.Hostname()
+	}
+	return service.String()
+}
+
+// URL returns the URL of the device's device service.
+func (d *Device) URL() string {
+	return d.deviceURL
+}
+
+// SupportsPTZ returns whether the device has a PTZ service.
+func (d *Device) SupportsPTZ() bool {
+	return d.ptzURL != ""
+}
+
+// Information returns the manufacturer, model and serial number of the device.
+func (d *Device) Information(ctx context.Context) (DeviceInformation, error) {
+	var info DeviceInformation
+	err := d.soap.call(ctx, d.deviceURL, &struct {
+		XMLName xml.Name `xml:"http://www.onvif.org/ver10/device/wsdl GetDeviceInformation"`
+	}{}, &info)
+	return info, err
+}
+
+// Profiles returns the media profiles of the device.
+func (d *Device) Profiles(ctx context.Context) ([]Profile, error) {
+	var resp struct {
+		Profiles []Profile `xml:"Profiles"`
+	}
+	if err := d.soap.call(ctx, d.mediaURL, &struct {
+		XMLName xml.Name `xml:"http://www.onvif.org/ver10/media/wsdl GetProfiles"`
+	}{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Profiles, nil
+}
+
+type streamSetup struct {
+	Stream    string `xml:"http://www.onvif.org/ver10/schema Stream"`
+	Transport struct {
+		Protocol string `xml:"Protocol"`
+	} `xml:"http://www.onvif.org/ver10/schema Transport"`
+}
+
+// StreamURI returns the RTSP URI of the stream of a media profile.
+func (d *Device) StreamURI(ctx context.Context, profileToken string) (string, error) {
+	setup := streamSetup{Stream: "RTP-Unicast"}
+	setup.Transport.Protocol = "RTSP"
+	var resp struct {
+		URI string `xml:"MediaUri>Uri"`
+	}
+	if err := d.soap.call(ctx, d.mediaURL, &struct {
+		XMLName      xml.Name    `xml:"http://www.onvif.org/ver10/media/wsdl GetStreamUri"`
+		StreamSetup  streamSetup `xml:"StreamSetup"`
+		ProfileToken string      `xml:"ProfileToken"`
+	}{
+		StreamSetup:  setup,
+		ProfileToken: profileToken,
+	}, &resp); err != nil {
+		return "", err
+	}
+	if resp.URI == "" {
+		return "", errors.Errorf("ONVIF device returned no stream URI for profile %q", profileToken)
+	}
+	return d.reachable(resp.URI), nil
+}
+
+// SnapshotURI returns the HTTP URI of JPEG snapshots of a media profile.
+func (d *Device) SnapshotURI(ctx context.Context, profileToken string) (string, error) {
+	var resp struct {
+		URI string `xml:"MediaUri>Uri"`
+	}
+	if err := d.soap.call(ctx, d.mediaURL, &struct {
+		XMLName      xml.Name `xml:"http://www.onvif.org/ver10/media/wsdl GetSnapshotUri"`
+		ProfileToken string   `xml:"ProfileToken"`
+	}{ProfileToken: profileToken}, &resp); err != nil {
+		return "", err
+	}
+	if resp.URI == "" {
+		return "", errors.Errorf("ONVIF device returned no snapshot URI for profile %q", profileToken)
+	}
+	return d.reachable(resp.URI), nil
+}
@@ -0,0 +1,137 @@
+package onvif
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// wsDiscoveryAddress is the multicast group WS-Discovery probes are sent to.
+const wsDiscoveryAddress = "239.255.255.250:3702"
+
+const probeTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" ` +
+	`xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing" ` +
+	`xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery" ` +
+	`xmlns:dn="http://www.onvif.org/ver10/network/wsdl">
+<s:Header>
+<a:MessageID>uuid:%s</a:MessageID>
+<a:To s:mustUnderstand="true">urn:schemas-xmlsoap-org:ws:2005:04:discovery</a:To>
+<a:Action s:mustUnderstand="true">http://schemas.xmlsoap.org/ws/2005/04/discovery/Probe</a:Action>
+</s:Header>
+<s:Body><d:Probe><d:Types>dn:NetworkVideoTransmitter</d:Types></d:Probe></s:Body>
+</s:Envelope>`
+
+// A DiscoveredDevice is an ONVIF device that answered a WS-Discovery probe.
+type DiscoveredDevice struct {
+	// Endpoint uniquely identifies the device, usually as a "urn:uuid:" URN.
+	Endpoint string
+	// Addresses are the URLs of the device's device service.
+	Addresses []string
+	// Name, Hardware and Location are read from the device's ONVIF scopes, if it has them.
+	Name     string
+	Hardware string
+	Location string
+}
+
+// Discover sends a WS-Discovery probe for ONVIF network video transmitters and returns the
+// devices that answer before `timeout` elapses.
+func Discover(ctx context.Context, timeout time.Duration) ([]DiscoveredDevice, error) {
+	group, err := net.ResolveUDPAddr("udp4", wsDiscoveryAddress)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open socket for ONVIF discovery")
+	}
+	defer func() {
+		//nolint:errcheck
+		conn.Close()
+	}()
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteTo([]byte(fmt.Sprintf(probeTemplate, uuid.NewString())), group); err != nil {
+		return nil, errors.Wrap(err, "failed to send ONVIF discovery probe")
+	}
+
+	var devices []DiscoveredDevice
+	seen := map[string]bool{}
+	buf := make([]byte, 64*1024)
+	for ctx.Err() == nil {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return devices, err
+		}
+		for _, device := range parseProbeMatches(buf[:n]) {
+			if !seen[device.Endpoint] {
+				seen[device.Endpoint] = true
+				devices = append(devices, device)
+			}
+		}
+	}
+	return devices, nil
+}
+
+// parseProbeMatches returns the devices of a WS-Discovery ProbeMatches message, or none if the
+// message is not one.
+func parseProbeMatches(data []byte) []DiscoveredDevice {
+	var envelope struct {
+		Matches []struct {
+			Endpoint string `xml:"EndpointReference>Address"`
+			Scopes   string `xml:"Scopes"`
+			XAddrs   string `xml:"XAddrs"`
+		} `xml:"Body>ProbeMatches>ProbeMatch"`
+	}
+	if err := xml.Unmarshal(data, &envelope); err != nil {
+		return nil
+	}
+	devices := make([]DiscoveredDevice, 0, len(envelope.Matches))
+	for _, match := range envelope.Matches {
+		addresses := strings.Fields(match.XAddrs)
+		if len(addresses) == 0 {
+			continue
+		}
+		device := DiscoveredDevice{Endpoint: strings.TrimSpace(match.Endpoint), Addresses: addresses}
+		if device.Endpoint == "" {
+			device.Endpoint = addresses[0]
+		}
+		for _, scope := range strings.Fields(match.Scopes) {
+			rest, ok := strings.CutPrefix(scope, "onvif://www.onvif.org/")
+			if !ok {
+				continue
+			}
+			key, value, _ := strings.Cut(rest, "/")
+			if unescaped, err := url.PathUnescape(value); err == nil {
+				value = unescaped
+			}
+			switch key {
+			case "name":
+				device.Name = value
+			case "hardware":
+				device.Hardware = value
+			case "location":
+				device.Location = value
+			}
+		}
+		devices = append(devices, device)
+	}
+	return devices
+}
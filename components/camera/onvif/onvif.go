@@ -0,0 +1,279 @@
+// Package onvif implements cameras for ONVIF network cameras, including discovering them on the
+// local network, finding their stream URIs and controlling their pan, tilt and zoom.
+package onvif
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/utils"
+)
+
+// Model is the model of ONVIF cameras.
+var Model = resource.DefaultModelFamily.WithModel("onvif")
+
+// DoCommand keys of ONVIF cameras.
+const (
+	getStreamURIKey  = "get_stream_uri"
+	getProfilesKey   = "get_profiles"
+	ptzMoveKey       = "ptz_move"
+	ptzStopKey       = "ptz_stop"
+	ptzStatusKey     = "ptz_status"
+	ptzPresetsKey    = "ptz_presets"
+	ptzGotoPresetKey = "ptz_goto_preset"
+)
+
+// PTZ move modes of the ptz_move DoCommand.
+const (
+	moveContinuous = "continuous"
+	moveRelative   = "relative"
+	moveAbsolute   = "absolute"
+)
+
+// Config is the config of an ONVIF camera.
+type Config struct {
+	// Address is the URL of the camera's device service, or its host and optionally port.
+	Address  string `json:"address"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// Profile is the token or name of the media profile to use. The first profile is used if unset.
+	Profile string `json:"profile,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.Address == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "address")
+	}
+	if _, err := DeviceURL(cfg.Address); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	return []string{}, nil
+}
+
+func init() {
+	resource.RegisterComponent(camera.API, Model, resource.Registration[camera.Camera, *Config]{
+		Constructor: newCamera,
+	})
+}
+
+type onvifCamera struct {
+	resource.Named
+	resource.AlwaysRebuild
+	resource.TriviallyCloseable
+
+	device  *Device
+	profile Profile
+	// streamURI is the RTSP URI of the profile's stream, for other cameras and viewers to use.
+	streamURI   string
+	snapshotURI string
+	snapshotErr error
+	logger      logging.Logger
+}
+
+func newCamera(
+	ctx context.Context,
+	_ resource.Dependencies,
+	conf resource.Config,
+	logger logging.Logger,
+) (camera.Camera, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	device, err := Connect(ctx, newConf.Address, newConf.Username, newConf.Password)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := device.Profiles(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get media profiles of ONVIF camera")
+	}
+	profile, err := selectProfile(profiles, newConf.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	cam := &onvifCamera{
+		Named:   conf.ResourceName().AsNamed(),
+		device:  device,
+		profile: profile,
+		logger:  logger,
+	}
+	if cam.streamURI, err = device.StreamURI(ctx, profile.Token); err != nil {
+		logger.CWarnw(ctx, "failed to get stream URI of ONVIF camera", "profile", profile.Token, "error", err)
+	} else {
+		logger.CInfow(ctx, "found ONVIF camera stream", "profile", profile.Token, "uri", cam.streamURI)
+	}
+	if cam.snapshotURI, cam.snapshotErr = device.SnapshotURI(ctx, profile.Token); cam.snapshotErr != nil {
+		cam.snapshotErr = errors.Wrapf(cam.snapshotErr,
+			"ONVIF camera does not provide snapshots; its stream %q can be read with an ffmpeg camera instead", cam.streamURI)
+		logger.CWarn(ctx, cam.snapshotErr)
+	}
+	return cam, nil
+}
+
+// selectProfile returns the profile with the token or name `want`, or the first profile if
+// `want` is empty.
+func selectProfile(profiles []Profile, want string) (Profile, error) {
+	if len(profiles) == 0 {
+		return Profile{}, errors.New("ONVIF camera has no media profiles")
+	}
+	if want == "" {
+		return profiles[0], nil
+	}
+	for _, profile := range profiles {
+		if profile.Token == want || profile.Name == want {
+			return profile, nil
+		}
+	}
+	return Profile{}, errors.Errorf("ONVIF camera has no media profile %q", want)
+}
+
+func (c *onvifCamera) snapshot(ctx context.Context) ([]byte, error) {
+	if c.snapshotErr != nil {
+		return nil, c.snapshotErr
+	}
+	return c.device.Snapshot(ctx, c.snapshotURI)
+}
+
+func (c *onvifCamera) Image(ctx context.Context, mimeType string, extra map[string]interface{}) ([]byte, camera.ImageMetadata, error) {
+	data, err := c.snapshot(ctx)
+	if err != nil {
+		return nil, camera.ImageMetadata{}, err
+	}
+	if actual, _ := utils.CheckLazyMIMEType(mimeType); actual == "" || actual == utils.MimeTypeJPEG {
+		return data, camera.ImageMetadata{MimeType: utils.MimeTypeJPEG}, nil
+	}
+	img, err := rimage.DecodeImage(ctx, data, utils.MimeTypeJPEG)
+	if err != nil {
+		return nil, camera.ImageMetadata{}, err
+	}
+	encoded, err := rimage.EncodeImage(ctx, img, mimeType)
+	if err != nil {
+		return nil, camera.ImageMetadata{}, err
+	}
+	return encoded, camera.ImageMetadata{MimeType: mimeType}, nil
+}
+
+func (c *onvifCamera) Images(ctx context.Context) ([]camera.NamedImage, resource.ResponseMetadata, error) {
+	data, err := c.snapshot(ctx)
+	if err != nil {
+		return nil, resource.ResponseMetadata{}, err
+	}
+	captured := time.Now()
+	img, err := rimage.DecodeImage(ctx, data, utils.MimeTypeJPEG)
+	if err != nil {
+		return nil, resource.ResponseMetadata{}, err
+	}
+	return []camera.NamedImage{{Image: img, SourceName: c.Name().Name}}, resource.ResponseMetadata{CapturedAt: captured}, nil
+}
+
+func (c *onvifCamera) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
+	return nil, errors.New("ONVIF cameras do not support point clouds")
+}
+
+func (c *onvifCamera) Properties(ctx context.Context) (camera.Properties, error) {
+	return camera.Properties{
+		ImageType: camera.ColorStream,
+		MimeTypes: []string{utils.MimeTypeJPEG},
+	}, nil
+}
+
+// DoCommand reports the camera's stream URI and profiles and moves its PTZ unit. ptz_move takes
+// "pan", "tilt" and "zoom" and a "mode" of "continuous" (the default, with an optional
+// "timeout_ms"), "relative" or "absolute".
+func (c *onvifCamera) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	token := c.profile.Token
+	switch {
+	case cmd[getStreamURIKey] != nil:
+		if c.streamURI == "" {
+			return nil, errors.New("ONVIF camera did not report a stream URI")
+		}
+		return map[string]interface{}{"stream_uri": c.streamURI, "profile": token}, nil
+	case cmd[getProfilesKey] != nil:
+		profiles, err := c.device.Profiles(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]interface{}, 0, len(profiles))
+		for _, profile := range profiles {
+			result = append(result, map[string]interface{}{
+				"token":    profile.Token,
+				"name":     profile.Name,
+				"encoding": profile.Encoding,
+				"width":    profile.Width,
+				"height":   profile.Height,
+				"ptz":      profile.PTZ != nil,
+			})
+		}
+		return map[string]interface{}{"profiles": result}, nil
+	case cmd[ptzMoveKey] != nil:
+		args, _ := cmd[ptzMoveKey].(map[string]interface{}) //nolint:errcheck
+		pan, _ := args["pan"].(float64)                     //nolint:errcheck
+		tilt, _ := args["tilt"].(float64)                   //nolint:errcheck
+		zoom, _ := args["zoom"].(float64)                   //nolint:errcheck
+		vector := PTZVector{Pan: pan, Tilt: tilt, Zoom: zoom}
+		mode, _ := args["mode"].(string) //nolint:errcheck
+		var err error
+		switch mode {
+		case "", moveContinuous:
+			timeoutMs, _ := args["timeout_ms"].(float64) //nolint:errcheck
+			err = c.device.ContinuousMove(ctx, token, vector, time.Duration(timeoutMs*float64(time.Millisecond)))
+		case moveRelative:
+			err = c.device.RelativeMove(ctx, token, vector)
+		case moveAbsolute:
+			err = c.device.AbsoluteMove(ctx, token, vector)
+		default:
+			return nil, errors.Errorf("unknown PTZ move mode %q, expected %q, %q or %q", mode, moveContinuous, moveRelative, moveAbsolute)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{}, nil
+	case cmd[ptzStopKey] != nil:
+		if err := c.device.StopPTZ(ctx, token); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{}, nil
+	case cmd[ptzStatusKey] != nil:
+		status, err := c.device.PTZStatus(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"pan":    status.Position.Pan,
+			"tilt":   status.Position.Tilt,
+			"zoom":   status.Position.Zoom,
+			"moving": status.Moving,
+		}, nil
+	case cmd[ptzPresetsKey] != nil:
+		presets, err := c.device.PTZPresets(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]interface{}, 0, len(presets))
+		for _, preset := range presets {
+			result = append(result, map[string]interface{}{"token": preset.Token, "name": preset.Name})
+		}
+		return map[string]interface{}{"presets": result}, nil
+	case cmd[ptzGotoPresetKey] != nil:
+		preset, ok := cmd[ptzGotoPresetKey].(string)
+		if !ok {
+			return nil, errors.New("ptz_goto_preset must be the token of a preset")
+		}
+		if err := c.device.GotoPreset(ctx, token, preset); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{}, nil
+	default:
+		return nil, resource.ErrDoUnimplemented
+	}
+}
@@ -0,0 +1,279 @@
+package onvif
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+const (
+	testUsername = "admin"
+	testPassword = "secret"
+	testRealm    = "camera"
+	testNonce    = "abc123"
+)
+
+// fakeDevice serves the ONVIF calls used by cameras, replying to the body of each request by the
+// name of the call in it.
+type fakeDevice struct {
+	mu       sync.Mutex
+	server   *httptest.Server
+	requests []string
+	snapshot []byte
+}
+
+func newFakeDevice(t *testing.T) *fakeDevice {
+	t.Helper()
+	var buf bytes.Buffer
+	test.That(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 3)), nil), test.ShouldBeNil)
+	d := &fakeDevice{snapshot: buf.Bytes()}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/onvif/", d.serveSOAP)
+	mux.HandleFunc("/snapshot.jpg", d.serveSnapshot)
+	d.server = httptest.NewServer(mux)
+	t.Cleanup(d.server.Close)
+	return d
+}
+
+func (d *fakeDevice) lastRequest() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.requests[len(d.requests)-1]
+}
+
+func (d *fakeDevice) serveSOAP(w http.ResponseWriter, r *http.Request) {
+	data, _ := io.ReadAll(r.Body) //nolint:errcheck
+	body := string(data)
+	d.mu.Lock()
+	d.requests = append(d.requests, body)
+	d.mu.Unlock()
+
+	var reply string
+	switch {
+	case strings.Contains(body, "GetSystemDateAndTime"):
+		reply = `<tds:GetSystemDateAndTimeResponse><tds:SystemDateAndTime><tt:UTCDateTime>
+			<tt:Date><tt:Year>2024</tt:Year><tt:Month>1</tt:Month><tt:Day>2</tt:Day></tt:Date>
+			<tt:Time><tt:Hour>3</tt:Hour><tt:Minute>4</tt:Minute><tt:Second>5</tt:Second></tt:Time>
+			</tt:UTCDateTime></tds:SystemDateAndTime></tds:GetSystemDateAndTimeResponse>`
+	case !strings.Contains(body, "<Username>"+testUsername+"</Username>"):
+		w.WriteHeader(http.StatusBadRequest)
+		reply = `<s:Fault><s:Code><s:Value>s:Sender</s:Value><s:Subcode><s:Value>ter:NotAuthorized</s:Value></s:Subcode></s:Code>
+			<s:Reason><s:Text>Sender not authorized</s:Text></s:Reason></s:Fault>`
+	case strings.Contains(body, "GetCapabilities"):
+		// the device reports an address it cannot be reached at
+		reply = `<tds:GetCapabilitiesResponse><tds:Capabilities>
+			<tt:Media><tt:XAddr>http://10.0.0.1/onvif/media</tt:XAddr></tt:Media>
+			<tt:PTZ><tt:XAddr>http://10.0.0.1/onvif/ptz</tt:XAddr></tt:PTZ>
+			</tds:Capabilities></tds:GetCapabilitiesResponse>`
+	case strings.Contains(body, "GetProfiles"):
+		reply = `<trt:GetProfilesResponse>
+			<trt:Profiles token="main" fixed="true"><tt:Name>Main</tt:Name>
+			<tt:VideoEncoderConfiguration><tt:Encoding>H264</tt:Encoding>
+			<tt:Resolution><tt:Width>1920</tt:Width><tt:Height>1080</tt:Height></tt:Resolution></tt:VideoEncoderConfiguration>
+			<tt:PTZConfiguration token="ptz0"/></trt:Profiles>
+			<trt:Profiles token="sub"><tt:Name>Sub</tt:Name></trt:Profiles>
+			</trt:GetProfilesResponse>`
+	case strings.Contains(body, "GetStreamUri"):
+		reply = `<trt:GetStreamUriResponse><trt:MediaUri><tt:Uri>rtsp://10.0.0.1:554/stream1</tt:Uri></trt:MediaUri></trt:GetStreamUriResponse>`
+	case strings.Contains(body, "GetSnapshotUri"):
+		reply = `<trt:GetSnapshotUriResponse><trt:MediaUri><tt:Uri>http://10.0.0.1/snapshot.jpg</tt:Uri></trt:MediaUri></trt:GetSnapshotUriResponse>`
+	case strings.Contains(body, "GetStatus"):
+		reply = `<tptz:GetStatusResponse><tptz:PTZStatus><tt:Position>
+			<tt:PanTilt x="0.5" y="-0.25"/><tt:Zoom x="0.1"/></tt:Position>
+			<tt:MoveStatus><tt:PanTilt>MOVING</tt:PanTilt><tt:Zoom>IDLE</tt:Zoom></tt:MoveStatus>
+			</tptz:PTZStatus></tptz:GetStatusResponse>`
+	case strings.Contains(body, "GetPresets"):
+		reply = `<tptz:GetPresetsResponse><tptz:Preset token="1"><tt:Name>door</tt:Name></tptz:Preset></tptz:GetPresetsResponse>`
+	default:
+		reply = `<Response/>`
+	}
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:tt="http://www.onvif.org/ver10/schema"><s:Body>%s</s:Body></s:Envelope>`,
+		reply)
+}
+
+func (d *fakeDevice) serveSnapshot(w http.ResponseWriter, r *http.Request) {
+	params := parseAuthParams(strings.TrimPrefix(r.Header.Get("Authorization"), "Digest "))
+	md5Hex := func(s string) string {
+		//nolint:gosec
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+	ha1 := md5Hex(testUsername + ":" + testRealm + ":" + testPassword)
+	ha2 := md5Hex(r.Method + ":" + params["uri"])
+	expected := md5Hex(ha1 + ":" + testNonce + ":" + params["nc"] + ":" + params["cnonce"] + ":" + params["qop"] + ":" + ha2)
+	if params["username"] != testUsername || params["response"] != expected {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Digest realm="%s", qop="auth", nonce="%s", opaque="xyz"`, testRealm, testNonce))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", utils.MimeTypeJPEG)
+	//nolint:errcheck
+	w.Write(d.snapshot)
+}
+
+func TestDeviceURL(t *testing.T) {
+	for address, expected := range map[string]string{
+		"192.168.1.10":                          "http://192.168.1.10/onvif/device_service",
+		"192.168.1.10:8080":                     "http://192.168.1.10:8080/onvif/device_service",
+		"https://cam.local/":                    "https://cam.local/onvif/device_service",
+		"http://cam.local/onvif/device_service": "http://cam.local/onvif/device_service",
+	} {
+		deviceURL, err := DeviceURL(address)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, deviceURL, test.ShouldEqual, expected)
+	}
+	_, err := DeviceURL("")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestDevice(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeDevice(t)
+
+	_, err := Connect(ctx, fake.server.URL, "", "")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "ter:NotAuthorized")
+
+	device, err := Connect(ctx, fake.server.URL, testUsername, testPassword)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, device.SupportsPTZ(), test.ShouldBeTrue)
+	test.That(t, fake.lastRequest(), test.ShouldContainSubstring, "PasswordDigest")
+
+	profiles, err := device.Profiles(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(profiles), test.ShouldEqual, 2)
+	test.That(t, profiles[0].Token, test.ShouldEqual, "main")
+	test.That(t, profiles[0].Encoding, test.ShouldEqual, "H264")
+	test.That(t, profiles[0].Width, test.ShouldEqual, 1920)
+	test.That(t, profiles[0].PTZ, test.ShouldNotBeNil)
+	test.That(t, profiles[1].PTZ, test.ShouldBeNil)
+
+	// reported addresses are rewritten to the host the device was reached at
+	host := strings.TrimPrefix(fake.server.URL, "http://")
+	hostname := strings.Split(host, ":")[0]
+	streamURI, err := device.StreamURI(ctx, "main")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, streamURI, test.ShouldEqual, "rtsp://"+hostname+":554/stream1")
+	snapshotURI, err := device.SnapshotURI(ctx, "main")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, snapshotURI, test.ShouldEqual, fake.server.URL+"/snapshot.jpg")
+
+	snapshot, err := device.Snapshot(ctx, snapshotURI)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, snapshot, test.ShouldResemble, fake.snapshot)
+}
+
+func TestCamera(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	fake := newFakeDevice(t)
+
+	conf := resource.Config{
+		Name: "cam",
+		ConvertedAttributes: &Config{
+			Address:  fake.server.URL,
+			Username: testUsername,
+			Password: testPassword,
+			Profile:  "Main",
+		},
+	}
+	cam, err := newCamera(ctx, nil, conf, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	data, metadata, err := cam.Image(ctx, utils.MimeTypeJPEG, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, metadata.MimeType, test.ShouldEqual, utils.MimeTypeJPEG)
+	test.That(t, data, test.ShouldResemble, fake.snapshot)
+
+	images, _, err := cam.Images(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(images), test.ShouldEqual, 1)
+	test.That(t, images[0].Image.Bounds().Dx(), test.ShouldEqual, 4)
+
+	resp, err := cam.DoCommand(ctx, map[string]interface{}{getStreamURIKey: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["stream_uri"], test.ShouldEndWith, ":554/stream1")
+	test.That(t, resp["profile"], test.ShouldEqual, "main")
+
+	_, err = cam.DoCommand(ctx, map[string]interface{}{
+		ptzMoveKey: map[string]interface{}{"pan": 0.5, "tilt": -0.5, "timeout_ms": 1500.},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	request := fake.lastRequest()
+	test.That(t, request, test.ShouldContainSubstring, `<ContinuousMove xmlns="http://www.onvif.org/ver20/ptz/wsdl">`)
+	test.That(t, request, test.ShouldContainSubstring, `<ProfileToken>main</ProfileToken>`)
+	test.That(t, request, test.ShouldContainSubstring, `x="0.5" y="-0.5"`)
+	test.That(t, request, test.ShouldContainSubstring, `<Timeout>PT1.500S</Timeout>`)
+
+	_, err = cam.DoCommand(ctx, map[string]interface{}{ptzMoveKey: map[string]interface{}{"mode": "absolute", "zoom": 1.}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fake.lastRequest(), test.ShouldContainSubstring, "<AbsoluteMove")
+
+	_, err = cam.DoCommand(ctx, map[string]interface{}{ptzMoveKey: map[string]interface{}{"mode": "sideways"}})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	status, err := cam.DoCommand(ctx, map[string]interface{}{ptzStatusKey: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, status, test.ShouldResemble, map[string]interface{}{"pan": 0.5, "tilt": -0.25, "zoom": 0.1, "moving": true})
+
+	presets, err := cam.DoCommand(ctx, map[string]interface{}{ptzPresetsKey: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, presets["presets"], test.ShouldResemble, []interface{}{map[string]interface{}{"token": "1", "name": "door"}})
+
+	_, err = cam.DoCommand(ctx, map[string]interface{}{ptzGotoPresetKey: "1"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fake.lastRequest(), test.ShouldContainSubstring, "<PresetToken>1</PresetToken>")
+
+	conf.ConvertedAttributes = &Config{Address: fake.server.URL, Username: testUsername, Password: testPassword, Profile: "missing"}
+	_, err = newCamera(ctx, nil, conf, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "no media profile")
+}
+
+func TestParseProbeMatches(t *testing.T) {
+	reply := `<?xml version="1.0" encoding="UTF-8"?>
+<SOAP-ENV:Envelope xmlns:SOAP-ENV="http://www.w3.org/2003/05/soap-envelope"
+	xmlns:wsa="http://schemas.xmlsoap.org/ws/2004/08/addressing" xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery">
+<SOAP-ENV:Body><d:ProbeMatches><d:ProbeMatch>
+<wsa:EndpointReference><wsa:Address>urn:uuid:1234</wsa:Address></wsa:EndpointReference>
+<d:Types>dn:NetworkVideoTransmitter</d:Types>
+<d:Scopes>onvif://www.onvif.org/type/video_encoder onvif://www.onvif.org/name/Front%20Door onvif://www.onvif.org/hardware/IPC-123</d:Scopes>
+<d:XAddrs>http://192.168.1.10/onvif/device_service http://[fe80::1]/onvif/device_service</d:XAddrs>
+</d:ProbeMatch></d:ProbeMatches></SOAP-ENV:Body></SOAP-ENV:Envelope>`
+
+	devices := parseProbeMatches([]byte(reply))
+	test.That(t, devices, test.ShouldResemble, []DiscoveredDevice{{
+		Endpoint:  "urn:uuid:1234",
+		Addresses: []string{"http://192.168.1.10/onvif/device_service", "http://[fe80::1]/onvif/device_service"},
+		Name:      "Front Door",
+		Hardware:  "IPC-123",
+	}})
+	test.That(t, parseProbeMatches([]byte("not xml")), test.ShouldBeEmpty)
+}
+
+func TestParseAuthParams(t *testing.T) {
+	params := parseAuthParams(`realm="a, b", qop="auth,auth-int", nonce=xyz, opaque="o"`)
+	test.That(t, params, test.ShouldResemble, map[string]string{
+		"realm":  "a, b",
+		"qop":    "auth,auth-int",
+		"nonce":  "xyz",
+		"opaque": "o",
+	})
+}
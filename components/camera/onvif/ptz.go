@@ -0,0 +1,183 @@
+package onvif
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// PTZVector is a pan, tilt and zoom position or velocity. Positions and velocities are in the
+// device's generic spaces, where pan and tilt range over [-1, 1] and zoom over [0, 1] for
+// positions and [-1, 1] for velocities.
+type PTZVector struct {
+	Pan  float64
+	Tilt float64
+	Zoom float64
+}
+
+// PTZStatus is the position and movement of the PTZ unit of a profile.
+type PTZStatus struct {
+	Position PTZVector
+	// Moving is true if pan, tilt or zoom are not idle.
+	Moving bool
+}
+
+// PTZPreset is a stored PTZ position of a profile.
+type PTZPreset struct {
+	Token string
+	Name  string
+}
+
+type panTilt struct {
+	XMLName xml.Name `xml:"http://www.onvif.org/ver10/schema PanTilt"`
+	X       float64  `xml:"x,attr"`
+	Y       float64  `xml:"y,attr"`
+}
+
+type zoom struct {
+	XMLName xml.Name `xml:"http://www.onvif.org/ver10/schema Zoom"`
+	X       float64  `xml:"x,attr"`
+}
+
+type ptzVectorXML struct {
+	PanTilt panTilt
+	Zoom    zoom
+}
+
+func newPTZVectorXML(v PTZVector) ptzVectorXML {
+	return ptzVectorXML{PanTilt: panTilt{X: v.Pan, Y: v.Tilt}, Zoom: zoom{X: v.Zoom}}
+}
+
+// xsDuration formats a duration as an xs:duration, as PTZ timeouts are given.
+func xsDuration(d time.Duration) string {
+	return fmt.Sprintf("PT%.3fS", d.Seconds())
+}
+
+func (d *Device) checkPTZ() error {
+	if d.ptzURL == "" {
+		return errors.Errorf("ONVIF device %s does not support PTZ", d.deviceURL)
+	}
+	return nil
+}
+
+// ContinuousMove moves the PTZ unit of a profile at `velocity` until stopped or, if timeout is
+// positive, until the timeout elapses.
+func (d *Device) ContinuousMove(ctx context.Context, profileToken string, velocity PTZVector, timeout time.Duration) error {
+	if err := d.checkPTZ(); err != nil {
+		return err
+	}
+	var timeoutStr string
+	if timeout > 0 {
+		timeoutStr = xsDuration(timeout)
+	}
+	return d.soap.call(ctx, d.ptzURL, &struct {
+		XMLName      xml.Name     `xml:"http://www.onvif.org/ver20/ptz/wsdl ContinuousMove"`
+		ProfileToken string       `xml:"ProfileToken"`
+		Velocity     ptzVectorXML `xml:"Velocity"`
+		Timeout      string       `xml:"Timeout,omitempty"`
+	}{ProfileToken: profileToken, Velocity: newPTZVectorXML(velocity), Timeout: timeoutStr}, nil)
+}
+
+// RelativeMove moves the PTZ unit of a profile by `translation` from its current position.
+func (d *Device) RelativeMove(ctx context.Context, profileToken string, translation PTZVector) error {
+	if err := d.checkPTZ(); err != nil {
+		return err
+	}
+	return d.soap.call(ctx, d.ptzURL, &struct {
+		XMLName      xml.Name     `xml:"http://www.onvif.org/ver20/ptz/wsdl RelativeMove"`
+		ProfileToken string       `xml:"ProfileToken"`
+		Translation  ptzVectorXML `xml:"Translation"`
+	}{ProfileToken: profileToken, Translation: newPTZVectorXML(translation)}, nil)
+}
+
+// AbsoluteMove moves the PTZ unit of a profile to `position`.
+func (d *Device) AbsoluteMove(ctx context.Context, profileToken string, position PTZVector) error {
+	if err := d.checkPTZ(); err != nil {
+		return err
+	}
+	return d.soap.call(ctx, d.ptzURL, &struct {
+		XMLName      xml.Name     `xml:"http://www.onvif.org/ver20/ptz/wsdl AbsoluteMove"`
+		ProfileToken string       `xml:"ProfileToken"`
+		Position     ptzVectorXML `xml:"Position"`
+	}{ProfileToken: profileToken, Position: newPTZVectorXML(position)}, nil)
+}
+
+// StopPTZ stops any movement of the PTZ unit of a profile.
+func (d *Device) StopPTZ(ctx context.Context, profileToken string) error {
+	if err := d.checkPTZ(); err != nil {
+		return err
+	}
+	return d.soap.call(ctx, d.ptzURL, &struct {
+		XMLName      xml.Name `xml:"http://www.onvif.org/ver20/ptz/wsdl Stop"`
+		ProfileToken string   `xml:"ProfileToken"`
+		PanTilt      bool     `xml:"PanTilt"`
+		Zoom         bool     `xml:"Zoom"`
+	}{ProfileToken: profileToken, PanTilt: true, Zoom: true}, nil)
+}
+
+// PTZStatus returns the position and movement of the PTZ unit of a profile.
+func (d *Device) PTZStatus(ctx context.Context, profileToken string) (PTZStatus, error) {
+	if err := d.checkPTZ(); err != nil {
+		return PTZStatus{}, err
+	}
+	var resp struct {
+		PanTilt struct {
+			X float64 `xml:"x,attr"`
+			Y float64 `xml:"y,attr"`
+		} `xml:"PTZStatus>Position>PanTilt"`
+		Zoom struct {
+			X float64 `xml:"x,attr"`
+		} `xml:"PTZStatus>Position>Zoom"`
+		PanTiltStatus string `xml:"PTZStatus>MoveStatus>PanTilt"`
+		ZoomStatus    string `xml:"PTZStatus>MoveStatus>Zoom"`
+	}
+	if err := d.soap.call(ctx, d.ptzURL, &struct {
+		XMLName      xml.Name `xml:"http://www.onvif.org/ver20/ptz/wsdl GetStatus"`
+		ProfileToken string   `xml:"ProfileToken"`
+	}{ProfileToken: profileToken}, &resp); err != nil {
+		return PTZStatus{}, err
+	}
+	return PTZStatus{
+		Position: PTZVector{Pan: resp.PanTilt.X, Tilt: resp.PanTilt.Y, Zoom: resp.Zoom.X},
+		Moving:   resp.PanTiltStatus == "MOVING" || resp.ZoomStatus == "MOVING",
+	}, nil
+}
+
+// PTZPresets returns the stored PTZ positions of a profile.
+func (d *Device) PTZPresets(ctx context.Context, profileToken string) ([]PTZPreset, error) {
+	if err := d.checkPTZ(); err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Presets []struct {
+			Token string `xml:"token,attr"`
+			Name  string `xml:"Name"`
+		} `xml:"Preset"`
+	}
+	if err := d.soap.call(ctx, d.ptzURL, &struct {
+		XMLName      xml.Name `xml:"http://www.onvif.org/ver20/ptz/wsdl GetPresets"`
+		ProfileToken string   `xml:"ProfileToken"`
+	}{ProfileToken: profileToken}, &resp); err != nil {
+		return nil, err
+	}
+	presets := make([]PTZPreset, 0, len(resp.Presets))
+	for _, preset := range resp.Presets {
+		presets = append(presets, PTZPreset{Token: preset.Token, Name: preset.Name})
+	}
+	return presets, nil
+}
+
+// GotoPreset moves the PTZ unit of a profile to a stored position.
+func (d *Device) GotoPreset(ctx context.Context, profileToken, presetToken string) error {
+	if err := d.checkPTZ(); err != nil {
+		return err
+	}
+	return d.soap.call(ctx, d.ptzURL, &struct {
+		XMLName      xml.Name `xml:"http://www.onvif.org/ver20/ptz/wsdl GotoPreset"`
+		ProfileToken string   `xml:"ProfileToken"`
+		PresetToken  string   `xml:"PresetToken"`
+	}{ProfileToken: profileToken, PresetToken: presetToken}, nil)
+}
@@ -0,0 +1,144 @@
+package onvif
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // HTTP digest authentication is defined with MD5
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Snapshot returns the JPEG at a snapshot URI of the device, authenticating with HTTP basic or
+// digest authentication as the device asks.
+func (d *Device) Snapshot(ctx context.Context, snapshotURI string) ([]byte, error) {
+	resp, err := d.getSnapshot(ctx, snapshotURI, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && d.soap.username != "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		//nolint:errcheck
+		resp.Body.Close()
+		authorization, err := d.authorization(challenge, snapshotURI)
+		if err != nil {
+			return nil, err
+		}
+		if resp, err = d.getSnapshot(ctx, snapshotURI, authorization); err != nil {
+			return nil, err
+		}
+	}
+	defer func() {
+		//nolint:errcheck
+		resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get snapshot from ONVIF device: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (d *Device) getSnapshot(ctx context.Context, snapshotURI, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, snapshotURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := d.soap.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get snapshot from ONVIF device")
+	}
+	return resp, nil
+}
+
+// authorization answers a WWW-Authenticate challenge for a GET of `uri`.
+func (d *Device) authorization(challenge, uri string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		req, err := http.NewRequest(http.MethodGet, uri, nil)
+		if err != nil {
+			return "", err
+		}
+		req.SetBasicAuth(d.soap.username, d.soap.password)
+		return req.Header.Get("Authorization"), nil
+	case "digest":
+		return digestAuthorization(parseAuthParams(params), d.soap.username, d.soap.password, uri)
+	default:
+		return "", errors.Errorf("unsupported snapshot authentication %q", challenge)
+	}
+}
+
+// parseAuthParams parses the comma separated key=value parameters of an authentication challenge.
+func parseAuthParams(params string) map[string]string {
+	parsed := map[string]string{}
+	for len(params) > 0 {
+		params = strings.TrimLeft(params, " ,")
+		key, rest, ok := strings.Cut(params, "=")
+		if !ok {
+			break
+		}
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		parsed[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		params = rest
+	}
+	return parsed
+}
+
+// digestAuthorization computes an RFC 2617 MD5 digest authorization for a GET of `uri`.
+func digestAuthorization(challenge map[string]string, username, password, uri string) (string, error) {
+	if algorithm := challenge["algorithm"]; algorithm != "" && !strings.EqualFold(algorithm, "MD5") {
+		return "", errors.Errorf("unsupported digest algorithm %q", algorithm)
+	}
+	path := uri
+	if idx := strings.Index(uri, "://"); idx >= 0 {
+		path = "/"
+		if slash := strings.Index(uri[idx+3:], "/"); slash >= 0 {
+			path = uri[idx+3+slash:]
+		}
+	}
+	md5Hex := func(s string) string {
+		//nolint:gosec
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+	realm, nonce := challenge["realm"], challenge["nonce"]
+	ha1 := md5Hex(username + ":" + realm + ":" + password)
+	ha2 := md5Hex(http.MethodGet + ":" + path)
+	authorization := fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s"`, username, realm, nonce, path)
+	if qop := challenge["qop"]; qop != "" {
+		if !strings.Contains(qop, "auth") {
+			return "", errors.Errorf("unsupported digest qop %q", qop)
+		}
+		cnonceBytes := make([]byte, 8)
+		if _, err := rand.Read(cnonceBytes); err != nil {
+			return "", err
+		}
+		cnonce := hex.EncodeToString(cnonceBytes)
+		const nc = "00000001"
+		response := md5Hex(ha1 + ":" + nonce + ":" + nc + ":" + cnonce + ":auth:" + ha2)
+		authorization += fmt.Sprintf(`, qop=auth, nc=%s, cnonce="%s", response="%s"`, nc, cnonce, response)
+	} else {
+		authorization += fmt.Sprintf(`, response="%s"`, md5Hex(ha1+":"+nonce+":"+ha2))
+	}
+	if opaque := challenge["opaque"]; opaque != "" {
+		authorization += fmt.Sprintf(`, opaque="%s"`, opaque)
+	}
+	return authorization + ", algorithm=MD5", nil
+}
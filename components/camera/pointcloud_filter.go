@@ -0,0 +1,87 @@
+package camera
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/pointcloud"
+)
+
+// The camera proto has no filtering fields on GetPointCloud. Filter options are carried in the
+// request's extra using the following reserved keys.
+const (
+	voxelSizeMMKey = "voxel_size_mm"
+	minRangeMMKey  = "min_range_mm"
+	maxRangeMMKey  = "max_range_mm"
+	maxPointsKey   = "max_points"
+)
+
+type pointCloudFilterContextKey struct{}
+
+// WithPointCloudFilter returns a context that makes NextPointCloud calls on camera clients ask the
+// server to filter the point cloud before sending it, so that consumers on slow links do not pull
+// multi-megabyte clouds when a much smaller one is enough.
+//
+// WithPointCloudFilter example:
+//
+//	myCamera, err := camera.FromRobot(machine, "my_camera")
+//	// Keep points within 2 meters, at most one per 1cm voxel.
+//	ctx := camera.WithPointCloudFilter(context.Background(), pointcloud.FilterOptions{
+//		MaxRangeMM:  2000,
+//		VoxelSizeMM: 10,
+//	})
+//	pc, err := myCamera.NextPointCloud(ctx)
+func WithPointCloudFilter(ctx context.Context, opts pointcloud.FilterOptions) context.Context {
+	return context.WithValue(ctx, pointCloudFilterContextKey{}, opts)
+}
+
+// PointCloudFilterFromContext returns the filter options set by WithPointCloudFilter, if any.
+func PointCloudFilterFromContext(ctx context.Context) (pointcloud.FilterOptions, bool) {
+	opts, ok := ctx.Value(pointCloudFilterContextKey{}).(pointcloud.FilterOptions)
+	return opts, ok
+}
+
+// addPointCloudFilterToExtra sets the reserved filter keys of a GetPointCloud request's extra.
+func addPointCloudFilterToExtra(extra map[string]interface{}, opts pointcloud.FilterOptions) {
+	if opts.VoxelSizeMM > 0 {
+		extra[voxelSizeMMKey] = opts.VoxelSizeMM
+	}
+	if opts.MinRangeMM > 0 {
+		extra[minRangeMMKey] = opts.MinRangeMM
+	}
+	if opts.MaxRangeMM > 0 {
+		extra[maxRangeMMKey] = opts.MaxRangeMM
+	}
+	if opts.MaxPoints > 0 {
+		extra[maxPointsKey] = opts.MaxPoints
+	}
+}
+
+// pointCloudFilterFromExtra reads the filter options from a GetPointCloud request's extra.
+func pointCloudFilterFromExtra(extra map[string]interface{}) (pointcloud.FilterOptions, error) {
+	var opts pointcloud.FilterOptions
+	for key, dst := range map[string]*float64{
+		voxelSizeMMKey: &opts.VoxelSizeMM,
+		minRangeMMKey:  &opts.MinRangeMM,
+		maxRangeMMKey:  &opts.MaxRangeMM,
+	} {
+		raw, ok := extra[key]
+		if !ok {
+			continue
+		}
+		val, ok := raw.(float64)
+		if !ok {
+			return pointcloud.FilterOptions{}, errors.Errorf("%q must be a number", key)
+		}
+		*dst = val
+	}
+	if _, ok := extra[maxPointsKey]; ok {
+		maxPoints, err := intFromExtra(extra, maxPointsKey)
+		if err != nil {
+			return pointcloud.FilterOptions{}, err
+		}
+		opts.MaxPoints = maxPoints
+	}
+	return opts, opts.Validate()
+}
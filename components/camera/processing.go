@@ -0,0 +1,231 @@
+package camera
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/fogleman/gg"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	"golang.org/x/image/draw"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/utils"
+)
+
+// The types of steps a camera processing pipeline can contain.
+const (
+	ProcessingResize     = "resize"
+	ProcessingRotate     = "rotate"
+	ProcessingCrop       = "crop"
+	ProcessingColorspace = "colorspace"
+	ProcessingTimestamp  = "overlay_timestamp"
+)
+
+// The colorspaces a colorspace processing step can convert to.
+const (
+	ColorspaceGray = "gray"
+	ColorspaceRGB  = "rgb"
+)
+
+const (
+	defaultTimestampFormat   = time.RFC3339
+	defaultTimestampFontSize = 20
+	timestampMarginPx        = 10
+)
+
+// ProcessingStep is one image transformation in a camera's processing pipeline. Attributes depend
+// on the type of the step:
+//   - resize: width_px and height_px
+//   - rotate: angle_degs, clockwise, defaulting to 180
+//   - crop: x_min_px, y_min_px, x_max_px and y_max_px, in pixels
+//   - colorspace: colorspace, either "gray" or "rgb"
+//   - overlay_timestamp: optional format (a Go time layout) and font_size
+type ProcessingStep struct {
+	Type       string             `json:"type"`
+	Attributes utils.AttributeMap `json:"attributes,omitempty"`
+}
+
+// ProcessingPipeline is an ordered list of processing steps applied to a camera's color images
+// before they are encoded. It lets a camera resize, rotate, crop, convert or annotate its own
+// images instead of requiring a chain of transform cameras.
+type ProcessingPipeline []ProcessingStep
+
+type (
+	processingResizeConfig struct {
+		Width  int `json:"width_px"`
+		Height int `json:"height_px"`
+	}
+	processingRotateConfig struct {
+		Angle *float64 `json:"angle_degs"`
+	}
+	processingCropConfig struct {
+		XMin int `json:"x_min_px"`
+		YMin int `json:"y_min_px"`
+		XMax int `json:"x_max_px"`
+		YMax int `json:"y_max_px"`
+	}
+	processingColorspaceConfig struct {
+		Colorspace string `json:"colorspace"`
+	}
+	processingTimestampConfig struct {
+		Format   string  `json:"format"`
+		FontSize float64 `json:"font_size"`
+	}
+)
+
+// processingFunc transforms an image as part of a processing pipeline.
+type processingFunc func(img image.Image) (image.Image, error)
+
+// ImageProcessor applies a ProcessingPipeline to images. A nil or empty ImageProcessor returns
+// images unchanged.
+type ImageProcessor struct {
+	steps []processingFunc
+	now   func() time.Time
+}
+
+// Validate ensures every step of the pipeline is well formed.
+func (p ProcessingPipeline) Validate(path string) error {
+	if _, err := NewImageProcessor(p); err != nil {
+		return resource.NewConfigValidationError(path, err)
+	}
+	return nil
+}
+
+// NewImageProcessor builds an ImageProcessor from a pipeline.
+func NewImageProcessor(pipeline ProcessingPipeline) (*ImageProcessor, error) {
+	ip := &ImageProcessor{now: time.Now}
+	for idx, step := range pipeline {
+		fn, err := ip.newProcessingFunc(step)
+		if err != nil {
+			return nil, errors.Wrapf(err, "processing step %d (%s)", idx, step.Type)
+		}
+		ip.steps = append(ip.steps, fn)
+	}
+	return ip, nil
+}
+
+func (ip *ImageProcessor) newProcessingFunc(step ProcessingStep) (processingFunc, error) {
+	switch step.Type {
+	case ProcessingResize:
+		conf, err := resource.TransformAttributeMap[*processingResizeConfig](step.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		if conf.Width <= 0 || conf.Height <= 0 {
+			return nil, errors.New("width_px and height_px must be positive")
+		}
+		return func(img image.Image) (image.Image, error) {
+			dst := image.NewRGBA(image.Rect(0, 0, conf.Width, conf.Height))
+			draw.NearestNeighbor.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Over, nil)
+			return dst, nil
+		}, nil
+	case ProcessingRotate:
+		conf, err := resource.TransformAttributeMap[*processingRotateConfig](step.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		angle := 180.0
+		if conf.Angle != nil {
+			angle = *conf.Angle
+		}
+		return func(img image.Image) (image.Image, error) {
+			// imaging.Rotate rotates counter-clockwise, the pipeline rotates clockwise
+			return imaging.Rotate(img, -angle, color.Black), nil
+		}, nil
+	case ProcessingCrop:
+		conf, err := resource.TransformAttributeMap[*processingCropConfig](step.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		if conf.XMin < 0 || conf.YMin < 0 {
+			return nil, errors.New("x_min_px and y_min_px cannot be negative")
+		}
+		if conf.XMin >= conf.XMax || conf.YMin >= conf.YMax {
+			return nil, errors.New("crop window must have a positive width and height")
+		}
+		window := image.Rect(conf.XMin, conf.YMin, conf.XMax, conf.YMax)
+		return func(img image.Image) (image.Image, error) {
+			bounds := img.Bounds()
+			rect := window.Add(bounds.Min).Intersect(bounds)
+			if rect.Empty() {
+				return nil, errors.Errorf("crop window %v is outside of the image bounds %v", window, bounds)
+			}
+			return imaging.Crop(img, rect), nil
+		}, nil
+	case ProcessingColorspace:
+		conf, err := resource.TransformAttributeMap[*processingColorspaceConfig](step.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		switch conf.Colorspace {
+		case ColorspaceGray:
+			return func(img image.Image) (image.Image, error) {
+				dst := image.NewGray(img.Bounds())
+				draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Src)
+				return dst, nil
+			}, nil
+		case ColorspaceRGB:
+			return func(img image.Image) (image.Image, error) {
+				dst := image.NewRGBA(img.Bounds())
+				draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Src)
+				return dst, nil
+			}, nil
+		default:
+			return nil, errors.Errorf("colorspace must be %q or %q, got %q", ColorspaceGray, ColorspaceRGB, conf.Colorspace)
+		}
+	case ProcessingTimestamp:
+		conf, err := resource.TransformAttributeMap[*processingTimestampConfig](step.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		if conf.FontSize < 0 {
+			return nil, errors.New("font_size cannot be negative")
+		}
+		format := conf.Format
+		if format == "" {
+			format = defaultTimestampFormat
+		}
+		size := conf.FontSize
+		if size == 0 {
+			size = defaultTimestampFontSize
+		}
+		return func(img image.Image) (image.Image, error) {
+			dc := gg.NewContextForImage(img)
+			rimage.DrawString(dc, ip.now().Format(format),
+				image.Point{timestampMarginPx, timestampMarginPx}, color.NRGBA{255, 255, 255, 255}, size)
+			return dc.Image(), nil
+		}, nil
+	default:
+		return nil, errors.Errorf("unknown processing step type %q", step.Type)
+	}
+}
+
+// Process applies each step of the pipeline to the image in order. Lazily encoded images are
+// decoded first.
+func (ip *ImageProcessor) Process(ctx context.Context, img image.Image) (image.Image, error) {
+	if ip == nil || len(ip.steps) == 0 {
+		return img, nil
+	}
+	_, span := trace.StartSpan(ctx, "camera::ImageProcessor::Process")
+	defer span.End()
+
+	if lazy, ok := img.(*rimage.LazyEncodedImage); ok {
+		decoded, err := lazy.DecodedImage()
+		if err != nil {
+			return nil, err
+		}
+		img = decoded
+	}
+	var err error
+	for _, step := range ip.steps {
+		if img, err = step(img); err != nil {
+			return nil, err
+		}
+	}
+	return img, nil
+}
@@ -0,0 +1,117 @@
+package camera_test
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/utils"
+)
+
+func TestImageProcessor(t *testing.T) {
+	ctx := context.Background()
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+
+	t.Run("empty pipeline", func(t *testing.T) {
+		ip, err := camera.NewImageProcessor(nil)
+		test.That(t, err, test.ShouldBeNil)
+		out, err := ip.Process(ctx, img)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, out, test.ShouldEqual, img)
+
+		var nilProcessor *camera.ImageProcessor
+		out, err = nilProcessor.Process(ctx, img)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, out, test.ShouldEqual, img)
+	})
+
+	t.Run("ordered steps", func(t *testing.T) {
+		ip, err := camera.NewImageProcessor(camera.ProcessingPipeline{
+			{Type: camera.ProcessingCrop, Attributes: utils.AttributeMap{
+				"x_min_px": 0, "y_min_px": 0, "x_max_px": 20, "y_max_px": 10,
+			}},
+			{Type: camera.ProcessingRotate, Attributes: utils.AttributeMap{"angle_degs": 90}},
+			{Type: camera.ProcessingResize, Attributes: utils.AttributeMap{"width_px": 5, "height_px": 10}},
+			{Type: camera.ProcessingColorspace, Attributes: utils.AttributeMap{"colorspace": camera.ColorspaceGray}},
+			{Type: camera.ProcessingTimestamp},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		out, err := ip.Process(ctx, img)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, out.Bounds().Dx(), test.ShouldEqual, 5)
+		test.That(t, out.Bounds().Dy(), test.ShouldEqual, 10)
+	})
+
+	t.Run("colorspace", func(t *testing.T) {
+		ip, err := camera.NewImageProcessor(camera.ProcessingPipeline{
+			{Type: camera.ProcessingColorspace, Attributes: utils.AttributeMap{"colorspace": camera.ColorspaceGray}},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		out, err := ip.Process(ctx, img)
+		test.That(t, err, test.ShouldBeNil)
+		_, ok := out.(*image.Gray)
+		test.That(t, ok, test.ShouldBeTrue)
+	})
+
+	t.Run("lazy encoded input", func(t *testing.T) {
+		encoded, err := rimage.EncodeImage(ctx, img, utils.MimeTypePNG)
+		test.That(t, err, test.ShouldBeNil)
+		ip, err := camera.NewImageProcessor(camera.ProcessingPipeline{
+			{Type: camera.ProcessingResize, Attributes: utils.AttributeMap{"width_px": 4, "height_px": 2}},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		out, err := ip.Process(ctx, rimage.NewLazyEncodedImage(encoded, utils.MimeTypePNG))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, out.Bounds(), test.ShouldResemble, image.Rect(0, 0, 4, 2))
+	})
+
+	t.Run("crop outside of image", func(t *testing.T) {
+		ip, err := camera.NewImageProcessor(camera.ProcessingPipeline{
+			{Type: camera.ProcessingCrop, Attributes: utils.AttributeMap{
+				"x_min_px": 100, "y_min_px": 100, "x_max_px": 200, "y_max_px": 200,
+			}},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		_, err = ip.Process(ctx, img)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "outside of the image bounds")
+	})
+}
+
+func TestProcessingPipelineValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		pipeline camera.ProcessingPipeline
+		errMsg   string
+	}{
+		{"unknown type", camera.ProcessingPipeline{{Type: "sharpen"}}, "unknown processing step type"},
+		{
+			"zero resize",
+			camera.ProcessingPipeline{{Type: camera.ProcessingResize, Attributes: utils.AttributeMap{"width_px": 10}}},
+			"must be positive",
+		},
+		{
+			"empty crop",
+			camera.ProcessingPipeline{{Type: camera.ProcessingCrop, Attributes: utils.AttributeMap{"x_max_px": 10}}},
+			"positive width and height",
+		},
+		{
+			"bad colorspace",
+			camera.ProcessingPipeline{{Type: camera.ProcessingColorspace, Attributes: utils.AttributeMap{"colorspace": "hsv"}}},
+			"colorspace must be",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.pipeline.Validate("path")
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errMsg)
+		})
+	}
+	test.That(t, camera.ProcessingPipeline{}.Validate("path"), test.ShouldBeNil)
+}
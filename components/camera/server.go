@@ -83,7 +83,12 @@ func (s *serviceServer) GetImage(
 	}
 	req.MimeType = utils.WithLazyMIMEType(req.MimeType)
 
-	resBytes, resMetadata, err := cam.Image(ctx, req.MimeType, req.Extra.AsMap())
+	extra := req.Extra.AsMap()
+	hint, err := ImageHintFromExtra(extra)
+	if err != nil {
+		return nil, err
+	}
+	resBytes, resMetadata, err := cam.Image(ctx, req.MimeType, extra)
 	if err != nil {
 		return nil, err
 	}
@@ -91,6 +96,11 @@ func (s *serviceServer) GetImage(
 		return nil, fmt.Errorf("received empty bytes from Image method of %s", req.Name)
 	}
 	actualMIME, _ := utils.CheckLazyMIMEType(resMetadata.MimeType)
+	if !resMetadata.HintApplied {
+		if resBytes, err = ApplyImageHint(ctx, resBytes, actualMIME, hint); err != nil {
+			return nil, err
+		}
+	}
 	return &pb.GetImageResponse{MimeType: actualMIME, Image: resBytes}, nil
 }
 
@@ -208,10 +218,20 @@ func (s *serviceServer) GetPointCloud(
 		return nil, err
 	}
 
+	opts, err := pointCloudFilterFromExtra(req.Extra.AsMap())
+	if err != nil {
+		return nil, err
+	}
+
 	pc, err := camera.NextPointCloud(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if !opts.IsZero() {
+		if pc, err = pointcloud.Filter(pc, opts); err != nil {
+			return nil, err
+		}
+	}
 
 	bytes, err := pointcloud.ToBytes(pc)
 	if err != nil {
@@ -292,6 +292,34 @@ func TestServer(t *testing.T) {
 		test.That(t, err.Error(), test.ShouldContainSubstring, errInvalidMimeType.Error())
 	})
 
+	t.Run("GetImage with image hint", func(t *testing.T) {
+		extra, err := goprotoutils.StructToStructPb(camera.ExtraWithImageHint(nil, camera.ImageHint{
+			ROI:      image.Rect(1, 1, 3, 4),
+			MaxWidth: 1,
+		}))
+		test.That(t, err, test.ShouldBeNil)
+		resp, err := cameraServer.GetImage(context.Background(), &pb.GetImageRequest{
+			Name:     testCameraName,
+			MimeType: utils.MimeTypePNG,
+			Extra:    extra,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.MimeType, test.ShouldEqual, utils.MimeTypePNG)
+		decoded, err := png.Decode(bytes.NewReader(resp.Image))
+		test.That(t, err, test.ShouldBeNil)
+		// the 2x3 region is downscaled to fit within a width of 1
+		test.That(t, decoded.Bounds(), test.ShouldResemble, image.Rect(0, 0, 1, 1))
+
+		badExtra, err := goprotoutils.StructToStructPb(map[string]interface{}{"roi": "everything"})
+		test.That(t, err, test.ShouldBeNil)
+		_, err = cameraServer.GetImage(context.Background(), &pb.GetImageRequest{
+			Name:     testCameraName,
+			MimeType: utils.MimeTypePNG,
+			Extra:    badExtra,
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
 	t.Run("GetImage with +lazy default", func(t *testing.T) {
 		for _, mimeType := range []string{
 			utils.MimeTypePNG,
@@ -71,6 +71,7 @@ type WebcamConfig struct {
 	Width                int                                `json:"width_px,omitempty"`
 	Height               int                                `json:"height_px,omitempty"`
 	FrameRate            float32                            `json:"frame_rate,omitempty"`
+	Pipeline             camera.ProcessingPipeline          `json:"pipeline,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -85,6 +86,9 @@ func (c WebcamConfig) Validate(path string) ([]string, error) {
 			"got illegal non-positive dimension for frame rate (%.2f) field set for webcam camera",
 			c.FrameRate)
 	}
+	if err := c.Pipeline.Validate(path); err != nil {
+		return nil, err
+	}
 
 	return []string{}, nil
 }
@@ -194,6 +198,7 @@ type webcam struct {
 	hasLoggedIntrinsicsInfo bool
 
 	cameraModel transform.PinholeCameraModel
+	processor   *camera.ImageProcessor
 
 	reader video.Reader
 	driver driverutils.Driver
@@ -244,10 +249,16 @@ func (c *webcam) Reconfigure(
 		return err
 	}
 
+	processor, err := camera.NewImageProcessor(newConf.Pipeline)
+	if err != nil {
+		return err
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.cameraModel = camera.NewPinholeModelWithBrownConradyDistortion(newConf.CameraParameters, newConf.DistortionParameters)
+	c.processor = processor
 
 	driverReinitNotNeeded := c.conf.Format == newConf.Format &&
 		c.conf.Path == newConf.Path &&
@@ -389,6 +400,10 @@ func (c *webcam) Images(ctx context.Context) ([]camera.NamedImage, resource.Resp
 			release()
 		}
 	}()
+	img, err = c.processor.Process(ctx, img)
+	if err != nil {
+		return nil, resource.ResponseMetadata{}, err
+	}
 	return []camera.NamedImage{{img, c.Name().Name}}, resource.ResponseMetadata{time.Now()}, nil
 }
 
@@ -418,6 +433,10 @@ func (c *webcam) Image(ctx context.Context, mimeType string, extra map[string]in
 		return nil, camera.ImageMetadata{}, err
 	}
 	defer release()
+	img, err = c.processor.Process(ctx, img)
+	if err != nil {
+		return nil, camera.ImageMetadata{}, err
+	}
 
 	if mimeType == "" {
 		mimeType = utils.MimeTypeJPEG
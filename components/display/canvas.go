@@ -0,0 +1,96 @@
+package display
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// FlushFunc shows the whole canvas on the display's hardware.
+type FlushFunc func(ctx context.Context, img *image.RGBA) error
+
+// A Canvas is the image shown on a display, which it draws on and flushes to the hardware after
+// each change. Display models embed it to implement DrawImage, WriteText, Clear and Properties.
+type Canvas struct {
+	flush      FlushFunc
+	monochrome bool
+
+	mu  sync.Mutex
+	img *image.RGBA
+}
+
+// NewCanvas returns a black canvas of the given size. It does not flush it, so models should clear
+// the display when they start.
+func NewCanvas(width, height int, monochrome bool, flush FlushFunc) (*Canvas, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errors.Errorf("display size of %dx%d must be positive", width, height)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.Black, image.Point{}, draw.Src)
+	return &Canvas{flush: flush, monochrome: monochrome, img: img}, nil
+}
+
+// DrawImage draws an image with its top left corner at `at`.
+func (c *Canvas) DrawImage(ctx context.Context, img image.Image, at image.Point, extra map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	bounds := img.Bounds()
+	draw.Draw(c.img, bounds.Sub(bounds.Min).Add(at), img, bounds.Min, draw.Over)
+	return c.flush(ctx, c.img)
+}
+
+// WriteText writes white text on black with the top left corner of its first line at `at`.
+func (c *Canvas) WriteText(ctx context.Context, text string, at image.Point, extra map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	face := basicfont.Face7x13
+	for i, line := range strings.Split(text, "\n") {
+		top := at.Y + i*face.Height
+		width := font.MeasureString(face, line).Ceil()
+		draw.Draw(c.img, image.Rect(at.X, top, at.X+width, top+face.Height), image.Black, image.Point{}, draw.Src)
+		drawer := font.Drawer{
+			Dst:  c.img,
+			Src:  image.White,
+			Face: face,
+			Dot:  fixed.P(at.X, top+face.Ascent),
+		}
+		drawer.DrawString(line)
+	}
+	return c.flush(ctx, c.img)
+}
+
+// Clear turns every pixel of the display off.
+func (c *Canvas) Clear(ctx context.Context, extra map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	draw.Draw(c.img, c.img.Bounds(), image.Black, image.Point{}, draw.Src)
+	return c.flush(ctx, c.img)
+}
+
+// Properties returns the size and kind of the display.
+func (c *Canvas) Properties(ctx context.Context, extra map[string]interface{}) (Properties, error) {
+	return Properties{Width: c.img.Bounds().Dx(), Height: c.img.Bounds().Dy(), Monochrome: c.monochrome}, nil
+}
+
+// Image returns a copy of what the canvas shows.
+func (c *Canvas) Image() *image.RGBA {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	img := image.NewRGBA(c.img.Bounds())
+	copy(img.Pix, c.img.Pix)
+	return img
+}
+
+// Lit returns whether a monochrome display lights a pixel of the color, which is when it is
+// brighter than half.
+func Lit(col color.Color) bool {
+	return color.GrayModel.Convert(col).(color.Gray).Y >= 0x80
+}
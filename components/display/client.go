@@ -0,0 +1,150 @@
+package display
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/png"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The display commands are carried over DoCommand using the following reserved keys.
+const (
+	drawImageKey     = "draw_image"
+	pngKey           = "png"
+	xKey             = "x"
+	yKey             = "y"
+	writeTextKey     = "write_text"
+	textKey          = "text"
+	clearKey         = "clear"
+	getPropertiesKey = "get_properties"
+	widthKey         = "width"
+	heightKey        = "height"
+	monochromeKey    = "monochrome"
+	extraKey         = "extra"
+)
+
+// client implements Display over the DoCommand of a resource that does not implement it, such as
+// the generic client of a remote display.
+type client struct {
+	resource.Resource
+}
+
+// NewClientFromResource returns a Display calling `res` over DoCommand.
+func NewClientFromResource(res resource.Resource) Display {
+	return &client{Resource: res}
+}
+
+func (c *client) DrawImage(ctx context.Context, img image.Image, at image.Point, extra map[string]interface{}) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		drawImageKey: map[string]interface{}{
+			pngKey:   base64.StdEncoding.EncodeToString(buf.Bytes()),
+			xKey:     float64(at.X),
+			yKey:     float64(at.Y),
+			extraKey: extra,
+		},
+	})
+	return err
+}
+
+func (c *client) WriteText(ctx context.Context, text string, at image.Point, extra map[string]interface{}) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		writeTextKey: map[string]interface{}{
+			textKey:  text,
+			xKey:     float64(at.X),
+			yKey:     float64(at.Y),
+			extraKey: extra,
+		},
+	})
+	return err
+}
+
+func (c *client) Clear(ctx context.Context, extra map[string]interface{}) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		clearKey: map[string]interface{}{extraKey: extra},
+	})
+	return err
+}
+
+func (c *client) Properties(ctx context.Context, extra map[string]interface{}) (Properties, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		getPropertiesKey: map[string]interface{}{extraKey: extra},
+	})
+	if err != nil {
+		return Properties{}, err
+	}
+	width, ok := resp[widthKey].(float64)
+	if !ok {
+		return Properties{}, errors.Errorf("expected %q in response, got %v", widthKey, resp)
+	}
+	height, _ := resp[heightKey].(float64)      //nolint:errcheck
+	monochrome, _ := resp[monochromeKey].(bool) //nolint:errcheck
+	return Properties{Width: int(width), Height: int(height), Monochrome: monochrome}, nil
+}
+
+// HandleDoCommand handles the reserved display DoCommand keys. Models call it first from their
+// DoCommand, and handle `cmd` themselves if it returns false.
+func HandleDoCommand(ctx context.Context, d Display, cmd map[string]interface{}) (map[string]interface{}, bool, error) {
+	if payload, ok := cmd[drawImageKey]; ok {
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", drawImageKey)
+		}
+		encoded, _ := args[pngKey].(string)                 //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, true, errors.Wrapf(err, "decoding %q", pngKey)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, true, errors.Wrapf(err, "decoding %q", pngKey)
+		}
+		return map[string]interface{}{}, true, d.DrawImage(ctx, img, pointFromArgs(args), extra)
+	}
+	if payload, ok := cmd[writeTextKey]; ok {
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", writeTextKey)
+		}
+		text, ok := args[textKey].(string)
+		if !ok {
+			return nil, true, errors.Errorf("%q must be a string", textKey)
+		}
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		return map[string]interface{}{}, true, d.WriteText(ctx, text, pointFromArgs(args), extra)
+	}
+	if payload, ok := cmd[clearKey]; ok {
+		args, _ := payload.(map[string]interface{})         //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		return map[string]interface{}{}, true, d.Clear(ctx, extra)
+	}
+	if payload, ok := cmd[getPropertiesKey]; ok {
+		args, _ := payload.(map[string]interface{})         //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		props, err := d.Properties(ctx, extra)
+		if err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{
+			widthKey:      float64(props.Width),
+			heightKey:     float64(props.Height),
+			monochromeKey: props.Monochrome,
+		}, true, nil
+	}
+	return nil, false, nil
+}
+
+func pointFromArgs(args map[string]interface{}) image.Point {
+	x, _ := args[xKey].(float64) //nolint:errcheck
+	y, _ := args[yKey].(float64) //nolint:errcheck
+	return image.Pt(int(x), int(y))
+}
@@ -0,0 +1,97 @@
+// Package display defines a display, such as a small onboard OLED or LCD screen, that shows images
+// and text.
+//
+// There is no display proto, so displays are generic components whose typed methods are carried
+// over DoCommand. Models implement Display and answer the reserved commands by calling
+// HandleDoCommand from their DoCommand.
+package display
+
+import (
+	"context"
+	"image"
+
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+)
+
+// API is the resource API displays are served under.
+var API = generic.API
+
+// Named is a helper for getting the named display's typed resource name.
+func Named(name string) resource.Name {
+	return resource.NewName(API, name)
+}
+
+// Properties describes a display.
+type Properties struct {
+	// Width and Height are the size of the display in pixels.
+	Width  int
+	Height int
+	// Monochrome is whether the display can only turn pixels on or off. Monochrome displays light
+	// the pixels of images that are brighter than half.
+	Monochrome bool
+}
+
+// A Display is a screen that shows images and text. Points are in pixels from the top left
+// corner, and anything drawn outside the screen is cut off.
+//
+// DrawImage example:
+//
+//	myDisplay, err := display.FromRobot(machine, "my_display")
+//	f, err := os.Open("logo.png")
+//	logo, err := png.Decode(f)
+//	err = myDisplay.DrawImage(context.Background(), logo, image.Point{}, nil)
+//
+// WriteText example:
+//
+//	err = myDisplay.Clear(context.Background(), nil)
+//	err = myDisplay.WriteText(context.Background(), "ip: 10.1.2.3\nbattery: 87%", image.Pt(0, 0), nil)
+//
+// Properties example:
+//
+//	props, err := myDisplay.Properties(context.Background(), nil)
+type Display interface {
+	resource.Resource
+
+	// DrawImage draws an image with its top left corner at `at`.
+	DrawImage(ctx context.Context, img image.Image, at image.Point, extra map[string]interface{}) error
+
+	// WriteText writes text with the top left corner of its first line at `at`, in a fixed width
+	// font 7 pixels wide with lines 13 pixels tall. The text is written in white on black, so that
+	// text written over older text replaces it. Newlines start new lines.
+	WriteText(ctx context.Context, text string, at image.Point, extra map[string]interface{}) error
+
+	// Clear turns every pixel of the display off.
+	Clear(ctx context.Context, extra map[string]interface{}) error
+
+	// Properties returns the size and kind of the display.
+	Properties(ctx context.Context, extra map[string]interface{}) (Properties, error)
+}
+
+// FromResource returns `res` as a Display. Resources that do not implement Display, such as the
+// clients of remote displays, are wrapped in a client that calls them over DoCommand.
+func FromResource(res resource.Resource) Display {
+	if d, ok := res.(Display); ok {
+		return d
+	}
+	return NewClientFromResource(res)
+}
+
+// FromDependencies is a helper for getting the named display from a collection of dependencies.
+func FromDependencies(deps resource.Dependencies, name string) (Display, error) {
+	res, err := generic.FromDependencies(deps, name)
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
+
+// FromRobot is a helper for getting the named display from the given Robot.
+func FromRobot(r robot.Robot, name string) (Display, error) {
+	res, err := generic.FromRobot(r, name)
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
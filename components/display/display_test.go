@@ -0,0 +1,113 @@
+package display_test
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"net"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/components/display"
+	"go.viam.com/rdk/components/display/fake"
+	"go.viam.com/rdk/components/generic"
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// litPixels counts the lit pixels of the image within `r`.
+func litPixels(img image.Image, r image.Rectangle) int {
+	n := 0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if display.Lit(img.At(x, y)) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestCanvas(t *testing.T) {
+	flushes := 0
+	canvas, err := display.NewCanvas(64, 32, false, func(ctx context.Context, img *image.RGBA) error {
+		flushes++
+		return nil
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	square := image.NewRGBA(image.Rect(10, 10, 14, 14))
+	for i := range square.Pix {
+		square.Pix[i] = 0xff
+	}
+	test.That(t, canvas.DrawImage(context.Background(), square, image.Pt(60, 30), nil), test.ShouldBeNil)
+	// the square is moved to the point and cut off at the edges
+	test.That(t, litPixels(canvas.Image(), canvas.Image().Bounds()), test.ShouldEqual, 8)
+	test.That(t, canvas.Image().At(63, 31), test.ShouldResemble, color.RGBA{0xff, 0xff, 0xff, 0xff})
+
+	test.That(t, canvas.WriteText(context.Background(), "ok\nhi", image.Pt(0, 0), nil), test.ShouldBeNil)
+	test.That(t, litPixels(canvas.Image(), image.Rect(0, 0, 14, 13)), test.ShouldBeGreaterThan, 0)
+	test.That(t, litPixels(canvas.Image(), image.Rect(0, 13, 14, 26)), test.ShouldBeGreaterThan, 0)
+	test.That(t, litPixels(canvas.Image(), image.Rect(14, 0, 60, 30)), test.ShouldEqual, 0)
+
+	// text written over older text replaces it
+	before := litPixels(canvas.Image(), image.Rect(0, 0, 14, 13))
+	test.That(t, canvas.WriteText(context.Background(), "..", image.Pt(0, 0), nil), test.ShouldBeNil)
+	test.That(t, litPixels(canvas.Image(), image.Rect(0, 0, 14, 13)), test.ShouldBeLessThan, before)
+
+	test.That(t, canvas.Clear(context.Background(), nil), test.ShouldBeNil)
+	test.That(t, litPixels(canvas.Image(), canvas.Image().Bounds()), test.ShouldEqual, 0)
+	test.That(t, flushes, test.ShouldEqual, 4)
+
+	_, err = display.NewCanvas(0, 32, false, nil)
+	test.That(t, err, test.ShouldBeError, "display size of 0x32 must be positive")
+}
+
+func TestClient(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	screen, err := fake.NewDisplay(display.Named("screen"), &fake.Config{Width: 32, Height: 16, Monochrome: true})
+	test.That(t, err, test.ShouldBeNil)
+	coll, err := resource.NewAPIResourceCollection(generic.API, map[resource.Name]resource.Resource{
+		display.Named("screen"): screen,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[resource.Resource](generic.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, coll), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	res, err := generic.NewClientFromConn(context.Background(), conn, "", display.Named("screen"), logger)
+	test.That(t, err, test.ShouldBeNil)
+	client := display.FromResource(res)
+
+	props, err := client.Properties(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, props, test.ShouldResemble, display.Properties{Width: 32, Height: 16, Monochrome: true})
+
+	dot := image.NewGray(image.Rect(0, 0, 2, 2))
+	for i := range dot.Pix {
+		dot.Pix[i] = 0xff
+	}
+	test.That(t, client.DrawImage(context.Background(), dot, image.Pt(30, 14), nil), test.ShouldBeNil)
+	test.That(t, litPixels(screen.Image(), image.Rect(30, 14, 32, 16)), test.ShouldEqual, 4)
+
+	test.That(t, client.WriteText(context.Background(), "ip", image.Pt(1, 1), nil), test.ShouldBeNil)
+	test.That(t, litPixels(screen.Image(), image.Rect(0, 0, 16, 14)), test.ShouldBeGreaterThan, 0)
+
+	test.That(t, client.Clear(context.Background(), nil), test.ShouldBeNil)
+	test.That(t, litPixels(screen.Image(), screen.Image().Bounds()), test.ShouldEqual, 0)
+}
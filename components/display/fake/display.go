@@ -0,0 +1,87 @@
+// Package fake implements a fake display that keeps what it is shown in memory.
+package fake
+
+import (
+	"context"
+	"image"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/display"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("fake_display")
+
+const (
+	defaultWidth  = 128
+	defaultHeight = 64
+)
+
+func init() {
+	resource.RegisterComponent(display.API, model, resource.Registration[resource.Resource, *Config]{
+		Constructor: func(
+			ctx context.Context,
+			_ resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			newConf, err := resource.NativeConfig[*Config](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewDisplay(conf.ResourceName(), newConf)
+		},
+	})
+}
+
+// Config is used for converting config attributes.
+type Config struct {
+	// Width and Height are the size of the display. They default to 128x64.
+	Width      int  `json:"width,omitempty"`
+	Height     int  `json:"height,omitempty"`
+	Monochrome bool `json:"monochrome,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.Width < 0 || conf.Height < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("width and height must not be negative"))
+	}
+	return nil, nil
+}
+
+// Display is a fake display.
+type Display struct {
+	resource.Named
+	resource.AlwaysRebuild
+	resource.TriviallyCloseable
+	*display.Canvas
+}
+
+// NewDisplay returns a fake display.
+func NewDisplay(name resource.Name, conf *Config) (*Display, error) {
+	width, height := conf.Width, conf.Height
+	if width == 0 {
+		width = defaultWidth
+	}
+	if height == 0 {
+		height = defaultHeight
+	}
+	canvas, err := display.NewCanvas(width, height, conf.Monochrome, func(ctx context.Context, img *image.RGBA) error {
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Display{Named: name.AsNamed(), Canvas: canvas}, nil
+}
+
+// DoCommand handles the display commands.
+func (d *Display) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, handled, err := display.HandleDoCommand(ctx, d, cmd); handled {
+		return resp, err
+	}
+	return map[string]interface{}{}, nil
+}
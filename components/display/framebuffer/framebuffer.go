@@ -0,0 +1,188 @@
+// Package framebuffer implements a display drawn through a linux framebuffer device, such as an
+// SPI LCD with an fbtft driver or an HDMI screen.
+package framebuffer
+
+import (
+	"context"
+	"image"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/display"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("framebuffer")
+
+// sysfsRoot is where the framebuffers' properties are read from.
+var sysfsRoot = "/sys/class/graphics"
+
+func init() {
+	resource.RegisterComponent(display.API, model, resource.Registration[resource.Resource, *Config]{
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (resource.Resource, error) {
+			newConf, err := resource.NativeConfig[*Config](conf)
+			if err != nil {
+				return nil, err
+			}
+			return newDisplay(ctx, conf.ResourceName(), newConf)
+		},
+	})
+}
+
+// Config is used for converting config attributes.
+type Config struct {
+	// Device is the framebuffer device. It defaults to "/dev/fb0".
+	Device string `json:"device,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	return nil, nil
+}
+
+// info is the layout of a framebuffer's memory.
+type info struct {
+	width, height int
+	bitsPerPixel  int
+	stride        int
+}
+
+func readInfo(device string) (info, error) {
+	dir := filepath.Join(sysfsRoot, filepath.Base(device))
+	readInt := func(name string) (int, error) {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return 0, errors.Wrapf(err, "reading framebuffer %s", name)
+		}
+		return parseInt(name, string(data))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "virtual_size"))
+	if err != nil {
+		return info{}, errors.Wrap(err, "reading framebuffer virtual_size")
+	}
+	width, height, ok := strings.Cut(strings.TrimSpace(string(data)), ",")
+	if !ok {
+		return info{}, errors.Errorf("unexpected framebuffer virtual_size %q", data)
+	}
+	var fb info
+	if fb.width, err = parseInt("width", width); err != nil {
+		return info{}, err
+	}
+	if fb.height, err = parseInt("height", height); err != nil {
+		return info{}, err
+	}
+	if fb.bitsPerPixel, err = readInt("bits_per_pixel"); err != nil {
+		return info{}, err
+	}
+	if fb.stride, err = readInt("stride"); err != nil {
+		return info{}, err
+	}
+	switch fb.bitsPerPixel {
+	case 16, 24, 32:
+	default:
+		return info{}, errors.Errorf("unsupported framebuffer depth of %d bits per pixel", fb.bitsPerPixel)
+	}
+	return fb, nil
+}
+
+func parseInt(name, s string) (int, error) {
+	v, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, errors.Wrapf(err, "parsing framebuffer %s", name)
+	}
+	return v, nil
+}
+
+type framebuffer struct {
+	resource.Named
+	resource.AlwaysRebuild
+	*display.Canvas
+
+	info info
+	mu   sync.Mutex
+	file *os.File
+}
+
+func newDisplay(ctx context.Context, name resource.Name, conf *Config) (display.Display, error) {
+	device := conf.Device
+	if device == "" {
+		device = "/dev/fb0"
+	}
+	fb, err := readInfo(device)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(device, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	d := &framebuffer{Named: name.AsNamed(), info: fb, file: file}
+	canvas, err := display.NewCanvas(fb.width, fb.height, false, d.flush)
+	if err != nil {
+		return nil, multierr.Combine(err, file.Close())
+	}
+	d.Canvas = canvas
+	if err := d.Clear(ctx, nil); err != nil {
+		return nil, multierr.Combine(err, file.Close())
+	}
+	return d, nil
+}
+
+func (d *framebuffer) flush(ctx context.Context, img *image.RGBA) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.file == nil {
+		return errors.New("framebuffer is closed")
+	}
+	_, err := d.file.WriteAt(encode(img, d.info), 0)
+	return err
+}
+
+// encode returns the framebuffer memory showing `img`, in the little endian RGB565, BGR or BGRX
+// layouts of 16, 24 and 32 bit framebuffers.
+func encode(img *image.RGBA, fb info) []byte {
+	data := make([]byte, fb.stride*fb.height)
+	bytesPerPixel := fb.bitsPerPixel / 8
+	for y := 0; y < fb.height; y++ {
+		for x := 0; x < fb.width; x++ {
+			i := img.PixOffset(x, y)
+			r, g, b := img.Pix[i], img.Pix[i+1], img.Pix[i+2]
+			o := y*fb.stride + x*bytesPerPixel
+			switch bytesPerPixel {
+			case 2:
+				v := uint16(r>>3)<<11 | uint16(g>>2)<<5 | uint16(b>>3)
+				data[o], data[o+1] = byte(v), byte(v>>8)
+			default:
+				data[o], data[o+1], data[o+2] = b, g, r
+			}
+		}
+	}
+	return data
+}
+
+// DoCommand handles the display commands.
+func (d *framebuffer) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, handled, err := display.HandleDoCommand(ctx, d, cmd); handled {
+		return resp, err
+	}
+	return map[string]interface{}{}, nil
+}
+
+// Close closes the framebuffer device, leaving the image shown.
+func (d *framebuffer) Close(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.file == nil {
+		return nil
+	}
+	err := d.file.Close()
+	d.file = nil
+	return err
+}
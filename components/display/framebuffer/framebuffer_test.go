@@ -0,0 +1,60 @@
+package framebuffer
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/display"
+)
+
+func TestEncode(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{R: 0xff, A: 0xff})
+	img.Set(1, 0, color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xff})
+
+	test.That(t, encode(img, info{width: 2, height: 1, bitsPerPixel: 16, stride: 6}), test.ShouldResemble,
+		[]byte{0x00, 0xf8, 0x06, 0x11, 0, 0})
+	test.That(t, encode(img, info{width: 2, height: 1, bitsPerPixel: 32, stride: 8}), test.ShouldResemble,
+		[]byte{0, 0, 0xff, 0, 0x30, 0x20, 0x10, 0})
+}
+
+func TestDisplay(t *testing.T) {
+	dir := t.TempDir()
+	sysfsRoot = filepath.Join(dir, "sys")
+	defer func() { sysfsRoot = "/sys/class/graphics" }()
+	fbDir := filepath.Join(sysfsRoot, "fb1")
+	test.That(t, os.MkdirAll(fbDir, 0o755), test.ShouldBeNil)
+	for name, value := range map[string]string{"virtual_size": "4,2\n", "bits_per_pixel": "24\n", "stride": "12\n"} {
+		test.That(t, os.WriteFile(filepath.Join(fbDir, name), []byte(value), 0o644), test.ShouldBeNil)
+	}
+	device := filepath.Join(dir, "fb1")
+	test.That(t, os.WriteFile(device, nil, 0o644), test.ShouldBeNil)
+
+	d, err := newDisplay(context.Background(), display.Named("screen"), &Config{Device: device})
+	test.That(t, err, test.ShouldBeNil)
+	props, err := d.Properties(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, props, test.ShouldResemble, display.Properties{Width: 4, Height: 2})
+
+	dot := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	dot.Set(0, 0, color.RGBA{R: 1, G: 2, B: 3, A: 0xff})
+	test.That(t, d.DrawImage(context.Background(), dot, image.Pt(3, 1), nil), test.ShouldBeNil)
+	data, err := os.ReadFile(device)
+	test.That(t, err, test.ShouldBeNil)
+	expected := make([]byte, 24)
+	copy(expected[21:], []byte{3, 2, 1})
+	test.That(t, data, test.ShouldResemble, expected)
+
+	test.That(t, d.Close(context.Background()), test.ShouldBeNil)
+	test.That(t, d.Clear(context.Background(), nil), test.ShouldBeError, "framebuffer is closed")
+
+	test.That(t, os.WriteFile(filepath.Join(fbDir, "bits_per_pixel"), []byte("8\n"), 0o644), test.ShouldBeNil)
+	_, err = newDisplay(context.Background(), display.Named("screen"), &Config{Device: device})
+	test.That(t, err, test.ShouldBeError, "unsupported framebuffer depth of 8 bits per pixel")
+}
@@ -0,0 +1,9 @@
+// Package register registers all relevant displays.
+package register
+
+import (
+	// register displays.
+	_ "go.viam.com/rdk/components/display/fake"
+	_ "go.viam.com/rdk/components/display/framebuffer"
+	_ "go.viam.com/rdk/components/display/ssd1306"
+)
@@ -0,0 +1,188 @@
+// Package ssd1306 implements a monochrome OLED display driven by an SSD1306 controller over I2C,
+// such as the common 128x64 and 128x32 modules.
+package ssd1306
+
+import (
+	"context"
+	"image"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+	"go.viam.com/rdk/components/display"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("ssd1306")
+
+const (
+	defaultAddr   = 0x3c
+	defaultHeight = 64
+	width         = 128
+
+	// controlCommand and controlData start writes of commands and of display data.
+	controlCommand = 0x00
+	controlData    = 0x40
+	// dataChunk is how many bytes of display data are written at once.
+	dataChunk = 32
+
+	cmdDisplayOff = 0xae
+	cmdDisplayOn  = 0xaf
+	cmdColumnAddr = 0x21
+	cmdPageAddr   = 0x22
+)
+
+// Config is used for converting config attributes.
+type Config struct {
+	// I2CBus is the I2C bus the display is on, such as "1".
+	I2CBus string `json:"i2c_bus"`
+	// I2CAddr is the address of the display. It defaults to 0x3c.
+	I2CAddr int `json:"i2c_addr,omitempty"`
+	// Height is 64 or 32 pixels. It defaults to 64.
+	Height int `json:"height,omitempty"`
+	// Rotate180 turns the image upside down, for displays mounted that way.
+	Rotate180 bool `json:"rotate_180,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.I2CBus == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "i2c_bus")
+	}
+	if conf.I2CAddr < 0 || conf.I2CAddr > 0x7f {
+		return nil, resource.NewConfigValidationError(path, errors.New("i2c_addr must be a 7-bit address"))
+	}
+	if conf.Height != 0 && conf.Height != 32 && conf.Height != 64 {
+		return nil, resource.NewConfigValidationError(path, errors.New("height must be 32 or 64"))
+	}
+	return nil, nil
+}
+
+type oled struct {
+	resource.Named
+	resource.AlwaysRebuild
+	*display.Canvas
+
+	bus  buses.I2C
+	addr byte
+}
+
+func newDisplay(ctx context.Context, name resource.Name, conf *Config, bus buses.I2C) (display.Display, error) {
+	d := &oled{Named: name.AsNamed(), bus: bus, addr: defaultAddr}
+	if conf.I2CAddr != 0 {
+		d.addr = byte(conf.I2CAddr)
+	}
+	height := conf.Height
+	if height == 0 {
+		height = defaultHeight
+	}
+	canvas, err := display.NewCanvas(width, height, true, d.flush)
+	if err != nil {
+		return nil, err
+	}
+	d.Canvas = canvas
+	if err := d.command(ctx, initCommands(height, conf.Rotate180)...); err != nil {
+		return nil, errors.Wrap(err, "initializing SSD1306")
+	}
+	if err := d.Clear(ctx, nil); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// initCommands returns the commands that set the display up, with the charge pump on, to be
+// written to in horizontal addressing mode.
+func initCommands(height int, rotate180 bool) []byte {
+	comPins := byte(0x12)
+	if height == 32 {
+		comPins = 0x02
+	}
+	segmentRemap, comScan := byte(0xa1), byte(0xc8)
+	if rotate180 {
+		segmentRemap, comScan = 0xa0, 0xc0
+	}
+	return []byte{
+		cmdDisplayOff,
+		0xd5, 0x80, // clock divide ratio and oscillator frequency
+		0xa8, byte(height - 1), // multiplex ratio
+		0xd3, 0x00, // display offset
+		0x40,       // start line 0
+		0x8d, 0x14, // enable the charge pump
+		0x20, 0x00, // horizontal addressing mode
+		segmentRemap,
+		comScan,
+		0xda, comPins,
+		0x81, 0xcf, // contrast
+		0xd9, 0xf1, // pre-charge period
+		0xdb, 0x40, // VCOMH deselect level
+		0xa4, // show the display memory
+		0xa6, // not inverted
+		cmdDisplayOn,
+	}
+}
+
+// flush writes the whole image to the display memory.
+func (d *oled) flush(ctx context.Context, img *image.RGBA) error {
+	bounds := img.Bounds()
+	pages := bounds.Dy() / 8
+	if err := d.command(ctx, cmdColumnAddr, 0, byte(bounds.Dx()-1), cmdPageAddr, 0, byte(pages-1)); err != nil {
+		return err
+	}
+	data := encode(img)
+	return d.withHandle(func(handle buses.I2CHandle) error {
+		for len(data) > 0 {
+			n := min(dataChunk, len(data))
+			if err := handle.Write(ctx, append([]byte{controlData}, data[:n]...)); err != nil {
+				return err
+			}
+			data = data[n:]
+		}
+		return nil
+	})
+}
+
+// encode returns the display memory showing `img`. Each byte is a column of 8 pixels of a page of
+// rows, with the top pixel in the lowest bit.
+func encode(img *image.RGBA) []byte {
+	bounds := img.Bounds()
+	data := make([]byte, bounds.Dx()*bounds.Dy()/8)
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			if display.Lit(img.At(bounds.Min.X+x, bounds.Min.Y+y)) {
+				data[(y/8)*bounds.Dx()+x] |= 1 << (y % 8)
+			}
+		}
+	}
+	return data
+}
+
+func (d *oled) command(ctx context.Context, cmds ...byte) error {
+	return d.withHandle(func(handle buses.I2CHandle) error {
+		return handle.Write(ctx, append([]byte{controlCommand}, cmds...))
+	})
+}
+
+func (d *oled) withHandle(f func(handle buses.I2CHandle) error) (err error) {
+	handle, err := d.bus.OpenHandle(d.addr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Combine(err, handle.Close())
+	}()
+	return f(handle)
+}
+
+// DoCommand handles the display commands.
+func (d *oled) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, handled, err := display.HandleDoCommand(ctx, d, cmd); handled {
+		return resp, err
+	}
+	return map[string]interface{}{}, nil
+}
+
+// Close turns the display off.
+func (d *oled) Close(ctx context.Context) error {
+	return d.command(ctx, cmdDisplayOff)
+}
@@ -0,0 +1,28 @@
+//go:build linux
+
+package ssd1306
+
+import (
+	"context"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+	"go.viam.com/rdk/components/display"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func init() {
+	resource.RegisterComponent(display.API, model, resource.Registration[resource.Resource, *Config]{
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (resource.Resource, error) {
+			newConf, err := resource.NativeConfig[*Config](conf)
+			if err != nil {
+				return nil, err
+			}
+			bus, err := buses.NewI2cBus(newConf.I2CBus)
+			if err != nil {
+				return nil, err
+			}
+			return newDisplay(ctx, conf.ResourceName(), newConf, bus)
+		},
+	})
+}
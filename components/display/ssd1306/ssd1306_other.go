@@ -0,0 +1,21 @@
+//go:build !linux
+
+package ssd1306
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/display"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func init() {
+	resource.RegisterComponent(display.API, model, resource.Registration[resource.Resource, *Config]{
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (resource.Resource, error) {
+			return nil, errors.New("SSD1306 displays are only supported on linux")
+		},
+	})
+}
@@ -0,0 +1,70 @@
+package ssd1306
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+	"go.viam.com/rdk/components/display"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestEncode(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, width, 16))
+	img.Set(0, 0, color.White)
+	img.Set(0, 9, color.White)
+	img.Set(5, 7, color.Gray{Y: 0x90})
+	img.Set(6, 7, color.Gray{Y: 0x70})
+	data := encode(img)
+	test.That(t, len(data), test.ShouldEqual, 2*width)
+	test.That(t, data[0], test.ShouldEqual, 0x01)
+	test.That(t, data[5], test.ShouldEqual, 0x80)
+	test.That(t, data[6], test.ShouldEqual, 0)
+	test.That(t, data[width], test.ShouldEqual, 0x02)
+}
+
+func TestDisplay(t *testing.T) {
+	var writes [][]byte
+	handle := &inject.I2CHandle{
+		WriteFunc: func(ctx context.Context, tx []byte) error {
+			writes = append(writes, append([]byte(nil), tx...))
+			return nil
+		},
+		CloseFunc: func() error { return nil },
+	}
+	bus := &inject.I2C{OpenHandleFunc: func(addr byte) (buses.I2CHandle, error) {
+		test.That(t, addr, test.ShouldEqual, defaultAddr)
+		return handle, nil
+	}}
+
+	conf := &Config{I2CBus: "1", Height: 32}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	d, err := newDisplay(context.Background(), display.Named("oled"), conf, bus)
+	test.That(t, err, test.ShouldBeNil)
+
+	test.That(t, writes[0], test.ShouldResemble, append([]byte{controlCommand}, initCommands(32, false)...))
+	// clearing sets the address window then writes the 512 bytes of memory in chunks
+	test.That(t, writes[1], test.ShouldResemble, []byte{controlCommand, cmdColumnAddr, 0, 127, cmdPageAddr, 0, 3})
+	test.That(t, writes, test.ShouldHaveLength, 2+512/dataChunk)
+	for _, w := range writes[2:] {
+		test.That(t, w[0], test.ShouldEqual, controlData)
+		test.That(t, w[1:], test.ShouldResemble, make([]byte, dataChunk))
+	}
+
+	props, err := d.Properties(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, props, test.ShouldResemble, display.Properties{Width: 128, Height: 32, Monochrome: true})
+
+	writes = nil
+	test.That(t, d.Close(context.Background()), test.ShouldBeNil)
+	test.That(t, writes, test.ShouldResemble, [][]byte{{controlCommand, cmdDisplayOff}})
+
+	_, err = (&Config{I2CBus: "1", Height: 48}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "height must be 32 or 64")
+}
@@ -0,0 +1,256 @@
+/*
+Package multiturn implements an encoder that extends an absolute single-turn encoder, which
+reports an angle between 0 and 360 degrees, into a continuous multi-turn position.
+
+The underlying encoder is polled and a full turn is counted whenever its angle wraps around. The
+turn count is saved to a state file so that the position survives restarts. This assumes the axis
+does not move by more than half a turn between polls, including while the machine is off.
+
+Positions are reported in degrees. They are also reported as ticks, with one tick per degree, so
+that motors using this encoder should be configured with "ticks_per_rotation": 360.
+
+Sample configuration:
+
+	{
+		"encoder": "my-absolute-encoder",
+		"state_file": "/var/lib/viam/gantry-x-turns.json"
+	}
+*/
+package multiturn
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/components/encoder"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	rutils "go.viam.com/rdk/utils"
+)
+
+var model = resource.DefaultModelFamily.WithModel("multi-turn")
+
+const defaultPollIntervalMs = 10
+
+func init() {
+	resource.RegisterComponent(
+		encoder.API,
+		model,
+		resource.Registration[encoder.Encoder, *Config]{
+			Constructor: NewEncoder,
+		})
+}
+
+// Config describes the configuration of a multi-turn encoder.
+type Config struct {
+	// Encoder is the name of the absolute single-turn encoder to extend.
+	Encoder string `json:"encoder"`
+	// StateFile is where the turn count is saved. Defaults to a file named after the encoder in
+	// the ~/.viam directory.
+	StateFile string `json:"state_file,omitempty"`
+	// PollIntervalMs is how often the underlying encoder is read. Defaults to 10ms.
+	PollIntervalMs int `json:"poll_interval_ms,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.Encoder == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "encoder")
+	}
+	if conf.PollIntervalMs < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("poll_interval_ms cannot be negative"))
+	}
+	return []string{conf.Encoder}, nil
+}
+
+// state is what is saved to the state file.
+type state struct {
+	Turns int64 `json:"turns"`
+	// LastAngleDegs is the last angle read, used to detect wraps that happened while stopped.
+	LastAngleDegs float64 `json:"last_angle_degs"`
+	// ZeroDegs is the continuous position that ResetPosition made the zero.
+	ZeroDegs float64 `json:"zero_degs"`
+}
+
+// Encoder tracks the continuous position of an absolute single-turn encoder.
+type Encoder struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	absolute  encoder.Encoder
+	stateFile string
+	logger    logging.Logger
+
+	mu    sync.Mutex
+	state state
+	err   error
+
+	workers *utils.StoppableWorkers
+}
+
+// NewEncoder creates a new multi-turn encoder.
+func NewEncoder(
+	ctx context.Context,
+	deps resource.Dependencies,
+	conf resource.Config,
+	logger logging.Logger,
+) (encoder.Encoder, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	absolute, err := encoder.FromDependencies(deps, newConf.Encoder)
+	if err != nil {
+		return nil, err
+	}
+	props, err := absolute.Properties(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !props.AngleDegreesSupported {
+		return nil, errors.Errorf("encoder %q must report absolute angles in degrees", newConf.Encoder)
+	}
+
+	e := &Encoder{
+		Named:     conf.ResourceName().AsNamed(),
+		absolute:  absolute,
+		stateFile: newConf.StateFile,
+		logger:    logger,
+	}
+	if e.stateFile == "" {
+		e.stateFile = filepath.Join(rutils.ViamDotDir, "encoder-state", conf.ResourceName().ShortName()+".json")
+	}
+	if err := e.loadState(); err != nil {
+		return nil, err
+	}
+	// Account for movement since the state was saved before reporting any position.
+	if err := e.poll(ctx); err != nil {
+		return nil, err
+	}
+
+	pollInterval := time.Duration(newConf.PollIntervalMs) * time.Millisecond
+	if pollInterval == 0 {
+		pollInterval = defaultPollIntervalMs * time.Millisecond
+	}
+	e.workers = utils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
+		for utils.SelectContextOrWait(ctx, pollInterval) {
+			err := e.poll(ctx)
+			e.mu.Lock()
+			if err != nil && e.err == nil {
+				e.logger.CWarnw(ctx, "error reading absolute encoder", "error", err)
+			}
+			e.err = err
+			e.mu.Unlock()
+		}
+	})
+	return e, nil
+}
+
+func (e *Encoder) loadState() error {
+	data, err := os.ReadFile(e.stateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		e.logger.Infof("no multi-turn state at %s, starting from turn 0", e.stateFile)
+		e.state = state{LastAngleDegs: math.NaN()}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &e.state); err != nil {
+		return errors.Wrapf(err, "invalid multi-turn state file %s", e.stateFile)
+	}
+	return nil
+}
+
+// saveState writes the state atomically, so a power loss leaves either the old or the new state.
+func (e *Encoder) saveState() error {
+	data, err := json.Marshal(e.state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(e.stateFile), 0o700); err != nil {
+		return err
+	}
+	tmp := e.stateFile + ".tmp"
+	//nolint:gosec
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, e.stateFile)
+}
+
+// poll reads the absolute encoder and counts a turn if its angle wrapped since the last read.
+func (e *Encoder) poll(ctx context.Context) error {
+	angle, _, err := e.absolute.Position(ctx, encoder.PositionTypeDegrees, nil)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	last := e.state.LastAngleDegs
+	e.state.LastAngleDegs = angle
+	if math.IsNaN(last) {
+		return e.saveState()
+	}
+	switch delta := angle - last; {
+	case delta < -180:
+		e.state.Turns++
+	case delta > 180:
+		e.state.Turns--
+	default:
+		return nil
+	}
+	return e.saveState()
+}
+
+// continuous returns the position in degrees since the zero. It must be called with mu held.
+func (e *Encoder) continuous() float64 {
+	return float64(e.state.Turns)*360 + e.state.LastAngleDegs - e.state.ZeroDegs
+}
+
+// Position returns the continuous position of the encoder in degrees, or in ticks of one degree.
+func (e *Encoder) Position(
+	ctx context.Context,
+	positionType encoder.PositionType,
+	extra map[string]interface{},
+) (float64, encoder.PositionType, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return math.NaN(), encoder.PositionTypeUnspecified, e.err
+	}
+	if positionType == encoder.PositionTypeUnspecified {
+		positionType = encoder.PositionTypeDegrees
+	}
+	return e.continuous(), positionType, nil
+}
+
+// ResetPosition makes the current position the zero.
+func (e *Encoder) ResetPosition(ctx context.Context, extra map[string]interface{}) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.state.ZeroDegs += e.continuous()
+	return e.saveState()
+}
+
+// Properties returns the position types supported by the encoder.
+func (e *Encoder) Properties(ctx context.Context, extra map[string]interface{}) (encoder.Properties, error) {
+	return encoder.Properties{
+		TicksCountSupported:   true,
+		AngleDegreesSupported: true,
+	}, nil
+}
+
+// Close stops polling the absolute encoder.
+func (e *Encoder) Close(ctx context.Context) error {
+	e.workers.Stop()
+	return nil
+}
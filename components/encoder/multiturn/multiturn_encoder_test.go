@@ -0,0 +1,106 @@
+package multiturn
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/encoder"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestMultiTurnEncoder(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	var (
+		mu    sync.Mutex
+		angle = 350.0
+	)
+	setAngle := func(a float64) {
+		mu.Lock()
+		defer mu.Unlock()
+		angle = a
+	}
+	absolute := inject.NewEncoder("abs")
+	absolute.PositionFunc = func(
+		ctx context.Context, positionType encoder.PositionType, extra map[string]interface{},
+	) (float64, encoder.PositionType, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return angle, encoder.PositionTypeDegrees, nil
+	}
+	absolute.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (encoder.Properties, error) {
+		return encoder.Properties{AngleDegreesSupported: true}, nil
+	}
+	deps := resource.Dependencies{encoder.Named("abs"): absolute}
+	conf := &Config{
+		Encoder:   "abs",
+		StateFile: filepath.Join(t.TempDir(), "state.json"),
+		// polls are done by hand
+		PollIntervalMs: 1000000,
+	}
+	cfg := resource.Config{Name: "multi", ConvertedAttributes: conf}
+
+	newEncoder := func() *Encoder {
+		enc, err := NewEncoder(ctx, deps, cfg, logger)
+		test.That(t, err, test.ShouldBeNil)
+		return enc.(*Encoder)
+	}
+	position := func(e *Encoder) float64 {
+		pos, _, err := e.Position(ctx, encoder.PositionTypeDegrees, nil)
+		test.That(t, err, test.ShouldBeNil)
+		return pos
+	}
+
+	e := newEncoder()
+	test.That(t, position(e), test.ShouldEqual, 350)
+
+	// wrapping forward counts a turn
+	setAngle(10)
+	test.That(t, e.poll(ctx), test.ShouldBeNil)
+	test.That(t, position(e), test.ShouldEqual, 370)
+	setAngle(200)
+	test.That(t, e.poll(ctx), test.ShouldBeNil)
+	setAngle(5)
+	test.That(t, e.poll(ctx), test.ShouldBeNil)
+	test.That(t, position(e), test.ShouldEqual, 365)
+	test.That(t, e.Close(ctx), test.ShouldBeNil)
+
+	// turns persist across restarts, including a wrap while stopped
+	setAngle(355)
+	e = newEncoder()
+	test.That(t, position(e), test.ShouldEqual, 355)
+
+	test.That(t, e.ResetPosition(ctx, nil), test.ShouldBeNil)
+	test.That(t, position(e), test.ShouldEqual, 0)
+	setAngle(15)
+	test.That(t, e.poll(ctx), test.ShouldBeNil)
+	test.That(t, position(e), test.ShouldEqual, 20)
+	test.That(t, e.Close(ctx), test.ShouldBeNil)
+
+	e = newEncoder()
+	test.That(t, position(e), test.ShouldEqual, 20)
+	test.That(t, e.Close(ctx), test.ShouldBeNil)
+
+	t.Run("validate", func(t *testing.T) {
+		deps, err := conf.Validate("path")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, deps, test.ShouldResemble, []string{"abs"})
+		_, err = (&Config{}).Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("relative encoders are rejected", func(t *testing.T) {
+		absolute.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (encoder.Properties, error) {
+			return encoder.Properties{TicksCountSupported: true}, nil
+		}
+		_, err := NewEncoder(ctx, deps, cfg, logger)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
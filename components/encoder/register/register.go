@@ -4,5 +4,6 @@ package register
 import (
 	// Load all encoders.
 	_ "go.viam.com/rdk/components/encoder/incremental"
+	_ "go.viam.com/rdk/components/encoder/multiturn"
 	_ "go.viam.com/rdk/components/encoder/single"
 )
@@ -52,6 +52,15 @@ func TestClient(t *testing.T) {
 		extra1 = extra
 		return true, nil
 	}
+	var linearOpts gantry.LinearMoveOptions
+	injectGantry.MoveLinearFunc = func(
+		ctx context.Context, pos []float64, opts gantry.LinearMoveOptions, extra map[string]interface{},
+	) error {
+		gantryPos = pos
+		linearOpts = opts
+		extra1 = extra
+		return nil
+	}
 
 	pos2 := []float64{4.0, 5.0, 6.0}
 	speed2 := []float64{100.0, 80.0, 120.0}
@@ -129,6 +138,15 @@ func TestClient(t *testing.T) {
 		test.That(t, gantrySpeed, test.ShouldResemble, speed2)
 		test.That(t, extra1, test.ShouldResemble, map[string]interface{}{"foo": 234., "bar": "345"})
 
+		mover, ok := gantry1Client.(gantry.LinearMover)
+		test.That(t, ok, test.ShouldBeTrue)
+		opts := gantry.LinearMoveOptions{FeedRateMmPerSec: 20, AccelMmPerSecSec: 100}
+		err = mover.MoveLinear(context.Background(), pos1, opts, map[string]interface{}{"foo": "MoveLinear"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, gantryPos, test.ShouldResemble, pos1)
+		test.That(t, linearOpts, test.ShouldResemble, opts)
+		test.That(t, extra1, test.ShouldResemble, map[string]interface{}{"foo": "MoveLinear"})
+
 		lens, err := gantry1Client.Lengths(context.Background(), map[string]interface{}{"foo": 345, "bar": "456"})
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, lens, test.ShouldResemble, len1)
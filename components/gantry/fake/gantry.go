@@ -10,22 +10,73 @@ import (
 	"go.viam.com/rdk/components/gantry"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/referenceframe/urdf"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/testutils"
 )
 
+// Config is used for converting config attributes.
+type Config struct {
+	// ModelFilePath is an optional URDF or JSON kinematics file of the gantry. Without it, the gantry has a single axis.
+	ModelFilePath string `json:"model-path,omitempty"`
+
+	// KinematicsOverrides narrow the limits of the gantry's axes and set the home position it starts at and returns to when
+	// homed. Without a kinematics file, the single axis is named after the gantry.
+	KinematicsOverrides *referenceframe.KinematicsOverrides `json:"kinematics-overrides,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if err := conf.KinematicsOverrides.Validate(); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	if conf.ModelFilePath != "" {
+		model, err := urdf.ParseModelFile(conf.ModelFilePath, "")
+		if err != nil {
+			return nil, resource.NewConfigValidationError(path, err)
+		}
+		if _, err := conf.KinematicsOverrides.Apply(model); err != nil {
+			return nil, resource.NewConfigValidationError(path, err)
+		}
+	}
+	return nil, nil
+}
+
 func init() {
 	resource.RegisterComponent(
 		gantry.API,
 		resource.DefaultModelFamily.WithModel("fake"),
-		resource.Registration[gantry.Gantry, resource.NoNativeConfig]{
+		resource.Registration[gantry.Gantry, *Config]{
 			Constructor: func(
 				ctx context.Context,
 				_ resource.Dependencies,
 				conf resource.Config,
 				logger logging.Logger,
 			) (gantry.Gantry, error) {
-				return NewGantry(conf.ResourceName(), logger), nil
+				newConf, err := resource.NativeConfig[*Config](conf)
+				if err != nil {
+					return nil, err
+				}
+				var model referenceframe.Model
+				switch {
+				case newConf.ModelFilePath != "":
+					if model, err = urdf.ParseModelFile(newConf.ModelFilePath, conf.Name); err != nil {
+						return nil, err
+					}
+				case newConf.KinematicsOverrides != nil:
+					model = NewGantry(conf.ResourceName(), logger).ModelFrame()
+				default:
+					return NewGantry(conf.ResourceName(), logger), nil
+				}
+				if model, err = newConf.KinematicsOverrides.Apply(model); err != nil {
+					return nil, err
+				}
+				g := NewGantryFromModel(conf.ResourceName(), model, logger).(*Gantry)
+				if home := newConf.KinematicsOverrides.HomeInputs(model); home != nil {
+					g.homeMm = referenceframe.InputsToFloats(home)
+					g.positionsMm = append([]float64(nil), g.homeMm...)
+				}
+				return g, nil
 			},
 		})
 }
@@ -33,16 +84,34 @@ func init() {
 // NewGantry returns a new fake gantry.
 func NewGantry(name resource.Name, logger logging.Logger) gantry.Gantry {
 	return &Gantry{
-		testutils.NewUnimplementedResource(name),
-		resource.TriviallyReconfigurable{},
-		resource.TriviallyCloseable{},
-		[]float64{1.2},
-		[]float64{120},
-		[]float64{5},
-		2,
-		r3.Vector{X: 1, Y: 0, Z: 0},
-		logger,
+		Named:          testutils.NewUnimplementedResource(name),
+		positionsMm:    []float64{1.2},
+		speedsMmPerSec: []float64{120},
+		lengths:        []float64{5},
+		lengthMeters:   2,
+		frame:          r3.Vector{X: 1, Y: 0, Z: 0},
+		logger:         logger,
+	}
+}
+
+// NewGantryFromModel returns a new fake gantry with the axes of a kinematics model, such as one read from a URDF.
+// Its axes start at their lower limits.
+func NewGantryFromModel(name resource.Name, model referenceframe.Model, logger logging.Logger) gantry.Gantry {
+	limits := model.DoF()
+	g := &Gantry{
+		Named:          testutils.NewUnimplementedResource(name),
+		positionsMm:    make([]float64, len(limits)),
+		speedsMmPerSec: make([]float64, len(limits)),
+		lengths:        make([]float64, len(limits)),
+		model:          model,
+		logger:         logger,
 	}
+	for i, limit := range limits {
+		g.positionsMm[i] = limit.Min
+		g.speedsMmPerSec[i] = 120
+		g.lengths[i] = limit.Max - limit.Min
+	}
+	return g
 }
 
 // Gantry is a fake gantry that can simply read and set properties.
@@ -55,7 +124,11 @@ type Gantry struct {
 	lengths        []float64
 	lengthMeters   float64
 	frame          r3.Vector
+	model          referenceframe.Model
 	logger         logging.Logger
+
+	// homeMm is where the axes return to when homed, if set
+	homeMm []float64
 }
 
 // Position returns the position in meters.
@@ -71,6 +144,9 @@ func (g *Gantry) Lengths(ctx context.Context, extra map[string]interface{}) ([]f
 // Home runs the homing sequence of the gantry and returns true once completed.
 func (g *Gantry) Home(ctx context.Context, extra map[string]interface{}) (bool, error) {
 	g.logger.CInfo(ctx, "homing")
+	if g.homeMm != nil {
+		g.positionsMm = append([]float64(nil), g.homeMm...)
+	}
 	return true, nil
 }
 
@@ -93,6 +169,9 @@ func (g *Gantry) IsMoving(ctx context.Context) (bool, error) {
 
 // ModelFrame returns a Gantry frame.
 func (g *Gantry) ModelFrame() referenceframe.Model {
+	if g.model != nil {
+		return g.model
+	}
 	m := referenceframe.NewSimpleModel("")
 	f, err := referenceframe.NewTranslationalFrame(g.Name().ShortName(), g.frame, referenceframe.Limit{0, g.lengthMeters})
 	if err != nil {
@@ -0,0 +1,91 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/gantry"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/referenceframe/urdf"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+func TestGantryFromModel(t *testing.T) {
+	ctx := context.Background()
+	modelPath := utils.ResolveFile("referenceframe/urdf/testfiles/example_gantry.xml")
+	conf := &Config{ModelFilePath: modelPath}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	model, err := urdf.ParseModelFile(modelPath, "gantry")
+	test.That(t, err, test.ShouldBeNil)
+	g := NewGantryFromModel(gantry.Named("gantry"), model, logging.NewTestLogger(t))
+	test.That(t, g.ModelFrame(), test.ShouldEqual, model)
+
+	lengths, err := g.Lengths(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, lengths, test.ShouldHaveLength, 2)
+	inputs, err := g.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inputs, test.ShouldHaveLength, 2)
+
+	goal := []referenceframe.Input{{Value: 10}, {Value: 20}}
+	test.That(t, g.GoToInputs(ctx, goal), test.ShouldBeNil)
+	inputs, err = g.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inputs, test.ShouldResemble, goal)
+
+	conf.ModelFilePath = "gantry.yaml"
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestKinematicsOverrides(t *testing.T) {
+	ctx := context.Background()
+	conf := &Config{
+		ModelFilePath: utils.ResolveFile("referenceframe/urdf/testfiles/example_gantry.xml"),
+		KinematicsOverrides: &referenceframe.KinematicsOverrides{
+			JointLimits: map[string]referenceframe.JointLimitsConfig{"gantry_y_joint": {Min: 0, Max: 1000}},
+			Home:        []float64{500, 0},
+		},
+	}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	reg, ok := resource.LookupRegistration(gantry.API, resource.DefaultModelFamily.WithModel("fake"))
+	test.That(t, ok, test.ShouldBeTrue)
+	res, err := reg.Constructor(ctx, nil, resource.Config{Name: "gantry", ConvertedAttributes: conf}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	g, ok := res.(gantry.Gantry)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, g.ModelFrame().DoF()[0], test.ShouldResemble, referenceframe.Limit{Min: 0, Max: 1000})
+
+	// the gantry starts at, and returns to, its home position
+	positions, err := g.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, positions, test.ShouldResemble, []float64{500, 0})
+	test.That(t, g.GoToInputs(ctx, []referenceframe.Input{{Value: 10}, {Value: 20}}), test.ShouldBeNil)
+	_, err = g.Home(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	positions, err = g.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, positions, test.ShouldResemble, []float64{500, 0})
+
+	// without a kinematics file, the gantry's single axis is named after it
+	conf = &Config{KinematicsOverrides: &referenceframe.KinematicsOverrides{
+		JointLimits: map[string]referenceframe.JointLimitsConfig{"gantry": {Min: 0.5, Max: 1}},
+	}}
+	res, err = reg.Constructor(ctx, nil, resource.Config{Name: "gantry", ConvertedAttributes: conf}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res.(gantry.Gantry).ModelFrame().DoF(), test.ShouldResemble, []referenceframe.Limit{{Min: 0.5, Max: 1}})
+
+	conf.KinematicsOverrides.JointLimits = map[string]referenceframe.JointLimitsConfig{"gantry_y_joint": {Min: -1000, Max: 0}}
+	conf.ModelFilePath = utils.ResolveFile("referenceframe/urdf/testfiles/example_gantry.xml")
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must be within its limits")
+}
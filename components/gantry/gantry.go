@@ -7,6 +7,7 @@ package gantry
 import (
 	"context"
 
+	"github.com/pkg/errors"
 	pb "go.viam.com/api/component/gantry/v1"
 
 	"go.viam.com/rdk/data"
@@ -31,6 +32,13 @@ func init() {
 		API:        API,
 		MethodName: lengths.String(),
 	}, newLengthsCollector)
+	robot.RegisterResourceReader(API, readGantry)
+	robot.RegisterStateSnapshotter(API, robot.StateSnapshotter{
+		Capture: func(ctx context.Context, res resource.Resource) (map[string]interface{}, error) {
+			return readGantry(ctx, res, nil)
+		},
+		Restore: restoreGantry,
+	})
 }
 
 // SubtypeName is a constant that identifies the component resource API string "gantry".
@@ -128,3 +136,41 @@ func FromRobot(r robot.Robot, name string) (Gantry, error) {
 func NamesFromRobot(r robot.Robot) []string {
 	return robot.NamesByAPI(r, API)
 }
+
+// readGantry reads the positions of the axes of a gantry, in millimeters, for a bulk read.
+func readGantry(ctx context.Context, res resource.Resource, extra map[string]interface{}) (map[string]interface{}, error) {
+	g, err := resource.AsType[Gantry](res)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := g.Position(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	positionsMm := make([]interface{}, 0, len(positions))
+	for _, p := range positions {
+		positionsMm = append(positionsMm, p)
+	}
+	return map[string]interface{}{"positions_mm": positionsMm}, nil
+}
+
+// restoreGantry moves a gantry back to positions captured by readGantry, at its default speeds.
+func restoreGantry(ctx context.Context, res resource.Resource, state map[string]interface{}) error {
+	g, err := resource.AsType[Gantry](res)
+	if err != nil {
+		return err
+	}
+	positionsMm, ok := state["positions_mm"].([]interface{})
+	if !ok {
+		return errors.Errorf("invalid gantry state %v", state)
+	}
+	positions := make([]float64, 0, len(positionsMm))
+	for _, p := range positionsMm {
+		position, ok := p.(float64)
+		if !ok {
+			return errors.Errorf("invalid gantry state %v", state)
+		}
+		positions = append(positions, position)
+	}
+	return g.MoveToPosition(ctx, positions, []float64{}, nil)
+}
@@ -0,0 +1,172 @@
+package gantry
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+
+	"go.viam.com/rdk/operation"
+	rutils "go.viam.com/rdk/utils"
+)
+
+// The gantry proto has no coordinated move RPC. Linear moves are carried over DoCommand using the
+// following reserved keys.
+const (
+	moveLinearKey   = "move_linear"
+	positionsMmKey  = "positions_mm"
+	feedRateKey     = "feed_rate_mm_per_sec"
+	accelerationKey = "accel_mm_per_sec_per_sec"
+	extraKey        = "extra"
+)
+
+// linearMoveStep is the time between the setpoints of an interpolated linear move.
+const linearMoveStep = 50 * time.Millisecond
+
+// LinearMoveOptions bound the speed of a coordinated move along the straight line to its target.
+type LinearMoveOptions struct {
+	// FeedRateMmPerSec is the speed of the tool along the line.
+	FeedRateMmPerSec float64
+	// AccelMmPerSecSec is the acceleration along the line. Zero means the feed rate is reached
+	// immediately.
+	AccelMmPerSecSec float64
+}
+
+// Validate ensures the options describe a move.
+func (opts LinearMoveOptions) Validate() error {
+	if opts.FeedRateMmPerSec <= 0 {
+		return errors.New("feed rate must be positive")
+	}
+	if opts.AccelMmPerSecSec < 0 {
+		return errors.New("acceleration cannot be negative")
+	}
+	return nil
+}
+
+// LinearMover is implemented by gantries that can move all of their axes together along a
+// straight line with a trapezoidal velocity profile, as needed for CNC-like motion. MoveToPosition
+// instead moves each axis at its own speed, so the tool does not follow a line.
+//
+// MoveLinear example:
+//
+//	myGantry, err := gantry.FromRobot(machine, "my_gantry")
+//	if mover, ok := myGantry.(gantry.LinearMover); ok {
+//		// Cut a line to (100, 50) at 20mm/s, accelerating at 100mm/s².
+//		err = mover.MoveLinear(context.Background(), []float64{100, 50},
+//			gantry.LinearMoveOptions{FeedRateMmPerSec: 20, AccelMmPerSecSec: 100}, nil)
+//	}
+type LinearMover interface {
+	// MoveLinear moves the gantry to the given positions in millimeters along a straight line.
+	// This will block until done or a new operation cancels this one.
+	MoveLinear(ctx context.Context, positionsMm []float64, opts LinearMoveOptions, extra map[string]interface{}) error
+}
+
+// InterpolateLinearMove emulates a linear move on axes that can only move to setpoints. It plans a
+// trapezoidal profile along the line from `from` to `to`, and calls move with the setpoint
+// reached every 50ms along with the per-axis speeds that reach it on time. move must block until
+// the setpoint is reached.
+func InterpolateLinearMove(
+	ctx context.Context,
+	from, to []float64,
+	opts LinearMoveOptions,
+	move func(ctx context.Context, positionsMm, speedsMmPerSec []float64) error,
+) error {
+	if len(from) != len(to) {
+		return errors.Errorf("have %d current positions but %d target positions", len(from), len(to))
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	var sq float64
+	for i := range from {
+		sq += (to[i] - from[i]) * (to[i] - from[i])
+	}
+	profile := rutils.NewTrapezoidalProfile(math.Sqrt(sq), opts.FeedRateMmPerSec, opts.AccelMmPerSecSec)
+
+	last := from
+	for elapsed := linearMoveStep; ; elapsed += linearMoveStep {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fraction := profile.Fraction(elapsed)
+		positions := make([]float64, len(from))
+		speeds := make([]float64, len(from))
+		for i := range from {
+			positions[i] = from[i] + (to[i]-from[i])*fraction
+			speeds[i] = math.Abs(positions[i]-last[i]) / linearMoveStep.Seconds()
+		}
+		if err := move(ctx, positions, speeds); err != nil {
+			return err
+		}
+		if fraction >= 1 {
+			return nil
+		}
+		last = positions
+	}
+}
+
+func (c *client) MoveLinear(ctx context.Context, positionsMm []float64, opts LinearMoveOptions, extra map[string]interface{}) error {
+	positions := make([]interface{}, 0, len(positionsMm))
+	for _, pos := range positionsMm {
+		positions = append(positions, pos)
+	}
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		moveLinearKey: map[string]interface{}{
+			positionsMmKey:  positions,
+			feedRateKey:     opts.FeedRateMmPerSec,
+			accelerationKey: opts.AccelMmPerSecSec,
+			extraKey:        extra,
+		},
+	})
+	return err
+}
+
+// doLinearCommand handles the reserved linear move DoCommand key. It returns false if `req` is not
+// a linear move or the gantry does not implement LinearMover.
+func doLinearCommand(
+	ctx context.Context,
+	g Gantry,
+	name string,
+	req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	mover, ok := g.(LinearMover)
+	if !ok {
+		return nil, false, nil
+	}
+	payload, ok := req.GetCommand().AsMap()[moveLinearKey]
+	if !ok {
+		return nil, false, nil
+	}
+	operation.CancelOtherWithLabel(ctx, name)
+	args, ok := payload.(map[string]interface{})
+	if !ok {
+		return nil, true, errors.Errorf("%q must be an object", moveLinearKey)
+	}
+	rawPositions, ok := args[positionsMmKey].([]interface{})
+	if !ok {
+		return nil, true, errors.Errorf("%q must be a list of numbers", positionsMmKey)
+	}
+	positions := make([]float64, 0, len(rawPositions))
+	for _, raw := range rawPositions {
+		pos, ok := raw.(float64)
+		if !ok {
+			return nil, true, errors.Errorf("%q must be a list of numbers", positionsMmKey)
+		}
+		positions = append(positions, pos)
+	}
+	var opts LinearMoveOptions
+	opts.FeedRateMmPerSec, _ = args[feedRateKey].(float64)     //nolint:errcheck
+	opts.AccelMmPerSecSec, _ = args[accelerationKey].(float64) //nolint:errcheck
+	extra, _ := args[extraKey].(map[string]interface{})        //nolint:errcheck
+	if err := mover.MoveLinear(ctx, positions, opts, extra); err != nil {
+		return nil, true, err
+	}
+	res, err := protoutils.StructToStructPb(map[string]interface{}{})
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
@@ -0,0 +1,42 @@
+package gantry_test
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/gantry"
+)
+
+func TestInterpolateLinearMove(t *testing.T) {
+	var setpoints [][]float64
+	move := func(ctx context.Context, positions, speeds []float64) error {
+		setpoints = append(setpoints, positions)
+		// the axis speeds keep the ratio of the move's extents to stay on the line
+		test.That(t, 4*speeds[0], test.ShouldAlmostEqual, 3*speeds[1])
+		return nil
+	}
+	// 50mm at 500mm/s without acceleration takes 100ms, so two steps
+	opts := gantry.LinearMoveOptions{FeedRateMmPerSec: 500}
+	err := gantry.InterpolateLinearMove(context.Background(), []float64{0, 0}, []float64{30, 40}, opts, move)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, setpoints, test.ShouldHaveLength, 2)
+	test.That(t, setpoints[0][0], test.ShouldAlmostEqual, 15)
+	test.That(t, setpoints[0][1], test.ShouldAlmostEqual, 20)
+	test.That(t, setpoints[1], test.ShouldResemble, []float64{30, 40})
+
+	// accelerating takes more steps, and the first ones are shorter
+	setpoints = nil
+	opts.AccelMmPerSecSec = 2000
+	err = gantry.InterpolateLinearMove(context.Background(), []float64{0, 0}, []float64{30, 40}, opts, move)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(setpoints), test.ShouldBeGreaterThan, 2)
+	test.That(t, setpoints[0][0], test.ShouldBeLessThan, setpoints[1][0]-setpoints[0][0])
+	test.That(t, setpoints[len(setpoints)-1], test.ShouldResemble, []float64{30, 40})
+
+	err = gantry.InterpolateLinearMove(context.Background(), []float64{0}, []float64{40, 30}, opts, move)
+	test.That(t, err, test.ShouldNotBeNil)
+	err = gantry.InterpolateLinearMove(context.Background(), []float64{0}, []float64{40}, gantry.LinearMoveOptions{}, move)
+	test.That(t, err, test.ShouldNotBeNil)
+}
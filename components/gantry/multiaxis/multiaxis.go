@@ -160,6 +160,64 @@ func (g *multiAxis) MoveToPosition(ctx context.Context, positions, speeds []floa
 	return nil
 }
 
+// MoveLinear moves all subaxes together so the gantry follows a straight line to the positions,
+// regardless of move_simultaneously.
+func (g *multiAxis) MoveLinear(
+	ctx context.Context,
+	positions []float64,
+	opts gantry.LinearMoveOptions,
+	extra map[string]interface{},
+) error {
+	ctx, done := g.opMgr.New(ctx)
+	defer done()
+
+	if len(positions) != len(g.lengthsMm) {
+		return errors.Errorf(
+			"number of input positions %v does not match total gantry axes count %v",
+			len(positions), len(g.lengthsMm),
+		)
+	}
+	current, err := g.Position(ctx, extra)
+	if err != nil {
+		return err
+	}
+	err = gantry.InterpolateLinearMove(ctx, current, positions, opts, g.moveSubAxesTogether)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return multierr.Combine(err, g.Stop(ctx, nil))
+	}
+	return err
+}
+
+// minLinearSpeedMmPerSec is the speed under which a subaxis is not moved during a linear move step,
+// since single-axis gantries reject such slow speeds.
+const minLinearSpeedMmPerSec = 0.1
+
+// moveSubAxesTogether moves every subaxis to its part of positions in parallel.
+func (g *multiAxis) moveSubAxesTogether(ctx context.Context, positions, speeds []float64) error {
+	fs := []rdkutils.SimpleFunc{}
+	idx := 0
+	for _, subAx := range g.subAxes {
+		subAxNum, err := subAx.Lengths(ctx, nil)
+		if err != nil {
+			return err
+		}
+		pos := positions[idx : idx+len(subAxNum)]
+		speed := speeds[idx : idx+len(subAxNum)]
+		idx += len(subAxNum)
+		moving := false
+		for _, sp := range speed {
+			moving = moving || sp >= minLinearSpeedMmPerSec
+		}
+		if !moving {
+			continue
+		}
+		singleGantry := subAx
+		fs = append(fs, func(ctx context.Context) error { return singleGantry.MoveToPosition(ctx, pos, speed, nil) })
+	}
+	_, err := rdkutils.RunInParallel(ctx, fs)
+	return err
+}
+
 // GoToInputs moves the gantry to a goal position in the Gantry frame.
 func (g *multiAxis) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
 	for _, goal := range inputSteps {
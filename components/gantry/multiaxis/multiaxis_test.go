@@ -434,3 +434,39 @@ func TestComplexMultiAxis(t *testing.T) {
 			}
 		})
 }
+
+func TestMoveLinear(t *testing.T) {
+	ctx := context.Background()
+	positions := []float64{0, 0}
+	var steps int
+	makeAxis := func(idx int) *inject.Gantry {
+		axis := createFakeOneaAxis(100, nil)
+		axis.PositionFunc = func(ctx context.Context, extra map[string]interface{}) ([]float64, error) {
+			return []float64{positions[idx]}, nil
+		}
+		axis.MoveToPositionFunc = func(ctx context.Context, pos, speed []float64, extra map[string]interface{}) error {
+			test.That(t, speed[0], test.ShouldBeGreaterThan, 0)
+			positions[idx] = pos[0]
+			if idx == 0 {
+				steps++
+			}
+			return nil
+		}
+		return axis
+	}
+
+	fakemultiaxis := &multiAxis{
+		subAxes:   []gantry.Gantry{makeAxis(0), makeAxis(1)},
+		lengthsMm: []float64{100, 100},
+		opMgr:     operation.NewSingleOperationManager(),
+	}
+	opts := gantry.LinearMoveOptions{FeedRateMmPerSec: 400}
+	err := fakemultiaxis.MoveLinear(ctx, []float64{30, 40}, opts, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, positions, test.ShouldResemble, []float64{30, 40})
+	// 50mm at 400mm/s in 50ms steps
+	test.That(t, steps, test.ShouldEqual, 3)
+
+	err = fakemultiaxis.MoveLinear(ctx, []float64{30}, opts, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -127,5 +127,8 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := doLinearCommand(ctx, gantry, req.GetName(), req); handled {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, gantry, req)
 }
@@ -55,3 +55,8 @@ func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map
 	}
 	return resp.Result.AsMap(), nil
 }
+
+// DoCommandStream serves a command stream opened with `cmd` on the server.
+func (c *client) DoCommandStream(ctx context.Context, cmd map[string]interface{}, conn CommandStreamConn) error {
+	return DoCommandStreamWithDoCommand(ctx, c.DoCommand, cmd, conn)
+}
@@ -2,6 +2,7 @@ package fake
 
 import (
 	"context"
+	"io"
 	"testing"
 
 	"go.viam.com/test"
@@ -20,3 +21,27 @@ func TestDoCommand(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, resp, test.ShouldResemble, cmd)
 }
+
+func TestDoCommandStream(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	gen := newGeneric(generic.Named("foo"), logger)
+	cmd := map[string]interface{}{"bar": "baz"}
+	stream, err := generic.OpenCommandStream(ctx, gen, cmd)
+	test.That(t, err, test.ShouldBeNil)
+	defer stream.Close()
+
+	msg, err := stream.Recv()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, msg, test.ShouldResemble, cmd)
+
+	test.That(t, stream.Send(map[string]interface{}{"n": 1}), test.ShouldBeNil)
+	msg, err = stream.Recv()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, msg, test.ShouldResemble, map[string]interface{}{"n": 1})
+
+	test.That(t, stream.CloseSend(), test.ShouldBeNil)
+	_, err = stream.Recv()
+	test.That(t, err, test.ShouldBeError, io.EOF)
+}
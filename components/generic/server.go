@@ -14,12 +14,13 @@ import (
 // serviceServer implements the resource.Generic service.
 type serviceServer struct {
 	genericpb.UnimplementedGenericServiceServer
-	coll resource.APIResourceCollection[resource.Resource]
+	coll    resource.APIResourceCollection[resource.Resource]
+	streams *CommandStreams
 }
 
 // NewRPCServiceServer constructs an generic gRPC service serviceServer.
 func NewRPCServiceServer(coll resource.APIResourceCollection[resource.Resource]) interface{} {
-	return &serviceServer{coll: coll}
+	return &serviceServer{coll: coll, streams: NewCommandStreams()}
 }
 
 // DoCommand returns an arbitrary command and returns arbitrary results.
@@ -28,6 +29,9 @@ func (s *serviceServer) DoCommand(ctx context.Context, req *commonpb.DoCommandRe
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := DoCommandStreamCommand(ctx, s.streams, genericDevice, req); handled {
+		return resp, err
+	}
 	result, err := genericDevice.DoCommand(ctx, req.Command.AsMap())
 	if err != nil {
 		return nil, err
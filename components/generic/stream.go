@@ -0,0 +1,499 @@
+package generic
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	goutils "go.viam.com/utils"
+	"go.viam.com/utils/protoutils"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The generic protos have no streaming DoCommand RPC. Command streams are carried over DoCommand
+// using the following reserved keys. The first request opens a stream, after which the client
+// sends messages to the resource and long polls for the messages the resource sends back.
+const (
+	openStreamKey  = "open_command_stream"
+	sendStreamKey  = "send_command_stream"
+	recvStreamKey  = "recv_command_stream"
+	closeStreamKey = "close_command_stream"
+	streamIDKey    = "stream_id"
+	commandKey     = "command"
+	messagesKey    = "messages"
+	closeSendKey   = "close_send"
+	doneKey        = "done"
+)
+
+const (
+	// streamBufferedMessages is the number of messages buffered in each direction of a stream
+	// before senders block.
+	streamBufferedMessages = 64
+	// maxRecvBatch is the most messages returned by a single receive request.
+	maxRecvBatch = 64
+	// streamIdleTimeout is how long a stream is kept without its client making a request, after
+	// which it is assumed the client went away.
+	streamIdleTimeout = 10 * time.Second
+	// streamRecvPollTimeout is how long a receive request waits for a message before returning
+	// none, so clients periodically show they are still there.
+	streamRecvPollTimeout = 5 * time.Second
+)
+
+// ErrSendClosed is returned when sending on a command stream whose send side is closed.
+var ErrSendClosed = errors.New("command stream send side is closed")
+
+// CommandStreamConn is a resource's side of a command stream.
+type CommandStreamConn interface {
+	// Recv returns the next message from the client, or io.EOF once the client closed its send
+	// side.
+	Recv() (map[string]interface{}, error)
+	// Send sends a message to the client, waiting if the client is not keeping up.
+	Send(msg map[string]interface{}) error
+}
+
+// CommandStreamer is implemented by resources that support command streams, which are a streaming
+// variant of DoCommand for long running or high rate custom interactions. Both the client and the
+// resource may send any number of messages, and the stream ends when DoCommandStream returns.
+type CommandStreamer interface {
+	// DoCommandStream serves a stream opened with `cmd`. ctx is done when the client closes the
+	// stream. `conn` must not be used after DoCommandStream returns.
+	DoCommandStream(ctx context.Context, cmd map[string]interface{}, conn CommandStreamConn) error
+}
+
+// CommandStream is a client's side of a command stream, opened with OpenCommandStream.
+type CommandStream struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	toResource   chan map[string]interface{}
+	fromResource chan map[string]interface{}
+	done         chan struct{}
+	err          error
+
+	sendMu     sync.Mutex
+	sendClosed bool
+}
+
+// OpenCommandStream opens a command stream on `res` with `cmd`. Resources on other machines serve
+// the stream on their server, so modules can expose streaming interactions over their existing
+// connection.
+//
+// OpenCommandStream example:
+//
+//	myGeneric, err := generic.FromRobot(machine, "my_generic")
+//	stream, err := generic.OpenCommandStream(ctx, myGeneric, map[string]interface{}{"subscribe": "telemetry"})
+//	defer stream.Close()
+//	for {
+//		msg, err := stream.Recv()
+//		if errors.Is(err, io.EOF) {
+//			break
+//		}
+//		logger.Info(msg)
+//	}
+func OpenCommandStream(ctx context.Context, res resource.Resource, cmd map[string]interface{}) (*CommandStream, error) {
+	streamer, ok := res.(CommandStreamer)
+	if !ok {
+		return nil, errors.Errorf("%q does not support command streams", res.Name())
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream := &CommandStream{
+		ctx:          streamCtx,
+		cancel:       cancel,
+		toResource:   make(chan map[string]interface{}, streamBufferedMessages),
+		fromResource: make(chan map[string]interface{}, streamBufferedMessages),
+		done:         make(chan struct{}),
+	}
+	goutils.PanicCapturingGo(func() {
+		defer cancel()
+		defer close(stream.done)
+		stream.err = streamer.DoCommandStream(streamCtx, cmd, &streamConn{stream: stream})
+	})
+	return stream, nil
+}
+
+// Send sends a message to the resource, waiting if the resource is not keeping up. If the stream
+// ended, the error it ended with, or io.EOF, is returned.
+func (s *CommandStream) Send(msg map[string]interface{}) error {
+	return s.send(s.ctx, msg)
+}
+
+func (s *CommandStream) send(ctx context.Context, msg map[string]interface{}) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	if s.sendClosed {
+		return ErrSendClosed
+	}
+	select {
+	case s.toResource <- msg:
+		return nil
+	case <-s.done:
+		return s.endErr()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CloseSend tells the resource no more messages will be sent.
+func (s *CommandStream) CloseSend() error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	if !s.sendClosed {
+		s.sendClosed = true
+		close(s.toResource)
+	}
+	return nil
+}
+
+// Recv returns the next message from the resource. Once the stream ended and all messages were
+// received, the error it ended with, or io.EOF, is returned.
+func (s *CommandStream) Recv() (map[string]interface{}, error) {
+	msgs, err := s.recv(s.ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	return msgs[0], nil
+}
+
+// recv returns at least one and at most `limit` messages from the resource, waiting for the first.
+func (s *CommandStream) recv(ctx context.Context, limit int) ([]map[string]interface{}, error) {
+	var msg map[string]interface{}
+	received := false
+	select {
+	case msg = <-s.fromResource:
+		received = true
+	case <-s.done:
+	case <-ctx.Done():
+		// the stream's own context is canceled as it ends
+		if ctx != s.ctx {
+			return nil, ctx.Err()
+		}
+		<-s.done
+	}
+	if !received {
+		// deliver messages sent before the stream ended
+		select {
+		case msg = <-s.fromResource:
+		default:
+			return nil, s.endErr()
+		}
+	}
+	msgs := []map[string]interface{}{msg}
+	for len(msgs) < limit {
+		select {
+		case msg := <-s.fromResource:
+			msgs = append(msgs, msg)
+		default:
+			return msgs, nil
+		}
+	}
+	return msgs, nil
+}
+
+// Done returns a channel that is closed when the stream ended.
+func (s *CommandStream) Done() <-chan struct{} {
+	return s.done
+}
+
+// endErr returns the error the stream ended with, or io.EOF if it ended cleanly.
+func (s *CommandStream) endErr() error {
+	if s.err != nil {
+		return s.err
+	}
+	return io.EOF
+}
+
+// Close ends the stream and waits for the resource to stop serving it.
+func (s *CommandStream) Close() error {
+	s.cancel()
+	<-s.done
+	return nil
+}
+
+// streamConn is the resource's side of a CommandStream.
+type streamConn struct {
+	stream *CommandStream
+}
+
+func (c *streamConn) Recv() (map[string]interface{}, error) {
+	select {
+	case msg, ok := <-c.stream.toResource:
+		if !ok {
+			return nil, io.EOF
+		}
+		return msg, nil
+	case <-c.stream.ctx.Done():
+		return nil, c.stream.ctx.Err()
+	}
+}
+
+func (c *streamConn) Send(msg map[string]interface{}) error {
+	select {
+	case c.stream.fromResource <- msg:
+		return nil
+	case <-c.stream.ctx.Done():
+		return c.stream.ctx.Err()
+	}
+}
+
+// commandStreamSession is a command stream served on behalf of a client.
+type commandStreamSession struct {
+	stream *CommandStream
+
+	mu       sync.Mutex
+	inFlight int
+	idle     *time.Timer
+}
+
+// begin marks the start of a client request, so the session does not expire during it.
+func (sess *commandStreamSession) begin() {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.inFlight++
+	sess.idle.Stop()
+}
+
+func (sess *commandStreamSession) end() {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.inFlight--
+	if sess.inFlight == 0 {
+		sess.idle.Reset(streamIdleTimeout)
+	}
+}
+
+// CommandStreams are the command streams of an API's server. Servers of generic APIs dispatch
+// DoCommand requests to DoCommandStreamCommand so their clients can open command streams.
+type CommandStreams struct {
+	mu       sync.Mutex
+	sessions map[string]*commandStreamSession
+}
+
+// NewCommandStreams returns an empty set of command streams.
+func NewCommandStreams() *CommandStreams {
+	return &CommandStreams{sessions: map[string]*commandStreamSession{}}
+}
+
+func (cs *CommandStreams) open(res resource.Resource, cmd map[string]interface{}) (string, error) {
+	// the stream outlives the request that opened it
+	stream, err := OpenCommandStream(context.Background(), res, cmd)
+	if err != nil {
+		return "", err
+	}
+	id := uuid.NewString()
+	sess := &commandStreamSession{stream: stream}
+	sess.idle = time.AfterFunc(streamIdleTimeout, func() { cs.remove(id) })
+	cs.mu.Lock()
+	cs.sessions[id] = sess
+	cs.mu.Unlock()
+	return id, nil
+}
+
+// get returns the session `id` after marking the start of a request on it.
+func (cs *CommandStreams) get(id string) (*commandStreamSession, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	sess, ok := cs.sessions[id]
+	if !ok {
+		return nil, errors.Errorf("no command stream %q", id)
+	}
+	sess.begin()
+	return sess, nil
+}
+
+func (cs *CommandStreams) remove(id string) {
+	cs.mu.Lock()
+	sess, ok := cs.sessions[id]
+	delete(cs.sessions, id)
+	cs.mu.Unlock()
+	if ok {
+		sess.idle.Stop()
+		//nolint:errcheck
+		sess.stream.Close()
+	}
+}
+
+// DoCommandStreamCommand handles the reserved command stream DoCommand keys for `res`. It returns
+// false if `req` is not a command stream command.
+func DoCommandStreamCommand(
+	ctx context.Context,
+	cs *CommandStreams,
+	res resource.Resource,
+	req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	cmd := req.GetCommand().AsMap()
+	switch {
+	case cmd[openStreamKey] != nil:
+		args, _ := cmd[openStreamKey].(map[string]interface{})    //nolint:errcheck
+		streamCmd, _ := args[commandKey].(map[string]interface{}) //nolint:errcheck
+		id, err := cs.open(res, streamCmd)
+		if err != nil {
+			return nil, true, err
+		}
+		return doCommandResponse(map[string]interface{}{streamIDKey: id})
+	case cmd[sendStreamKey] != nil:
+		args, _ := cmd[sendStreamKey].(map[string]interface{}) //nolint:errcheck
+		id, _ := args[streamIDKey].(string)                    //nolint:errcheck
+		sess, err := cs.get(id)
+		if err != nil {
+			return nil, true, err
+		}
+		defer sess.end()
+		msgs, _ := args[messagesKey].([]interface{}) //nolint:errcheck
+		for _, raw := range msgs {
+			msg, ok := raw.(map[string]interface{})
+			if !ok {
+				return nil, true, errors.Errorf("%q entries must be objects", messagesKey)
+			}
+			if err := sess.stream.send(ctx, msg); err != nil {
+				return nil, true, err
+			}
+		}
+		if closeSend, _ := args[closeSendKey].(bool); closeSend { //nolint:errcheck
+			//nolint:errcheck
+			sess.stream.CloseSend()
+		}
+		return doCommandResponse(map[string]interface{}{})
+	case cmd[recvStreamKey] != nil:
+		args, _ := cmd[recvStreamKey].(map[string]interface{}) //nolint:errcheck
+		id, _ := args[streamIDKey].(string)                    //nolint:errcheck
+		sess, err := cs.get(id)
+		if err != nil {
+			return nil, true, err
+		}
+		pollCtx, cancel := context.WithTimeout(ctx, streamRecvPollTimeout)
+		defer cancel()
+		msgs, err := sess.stream.recv(pollCtx, maxRecvBatch)
+		sess.end()
+		switch {
+		case err == nil:
+		case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+			// nothing was sent within the poll timeout
+		case errors.Is(err, io.EOF):
+			cs.remove(id)
+			return doCommandResponse(map[string]interface{}{messagesKey: []interface{}{}, doneKey: true})
+		default:
+			if ctx.Err() == nil {
+				cs.remove(id)
+			}
+			return nil, true, err
+		}
+		encoded := make([]interface{}, 0, len(msgs))
+		for _, msg := range msgs {
+			encoded = append(encoded, msg)
+		}
+		return doCommandResponse(map[string]interface{}{messagesKey: encoded, doneKey: false})
+	case cmd[closeStreamKey] != nil:
+		args, _ := cmd[closeStreamKey].(map[string]interface{}) //nolint:errcheck
+		id, _ := args[streamIDKey].(string)                     //nolint:errcheck
+		cs.remove(id)
+		return doCommandResponse(map[string]interface{}{})
+	default:
+		return nil, false, nil
+	}
+}
+
+// DoCommandStreamWithDoCommand implements CommandStreamer for the client of a generic API whose
+// server dispatches to DoCommandStreamCommand, relaying the messages of `conn` to the server and
+// the server's messages back to `conn`.
+func DoCommandStreamWithDoCommand(
+	ctx context.Context,
+	doCommand func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error),
+	cmd map[string]interface{},
+	conn CommandStreamConn,
+) error {
+	resp, err := doCommand(ctx, map[string]interface{}{openStreamKey: map[string]interface{}{commandKey: cmd}})
+	if err != nil {
+		return err
+	}
+	id, _ := resp[streamIDKey].(string) //nolint:errcheck
+	if id == "" {
+		return errors.Errorf("expected %q in response, got %v", streamIDKey, resp)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		// let the server stop the stream now rather than when it expires
+		closeCtx, closeCancel := context.WithTimeout(context.Background(), time.Second)
+		defer closeCancel()
+		//nolint:errcheck
+		doCommand(closeCtx, map[string]interface{}{closeStreamKey: map[string]interface{}{streamIDKey: id}})
+	}()
+
+	sendErr := make(chan error, 1)
+	goutils.PanicCapturingGo(func() {
+		sendErr <- relayToServer(ctx, doCommand, id, conn)
+	})
+	for {
+		select {
+		case err := <-sendErr:
+			if err != nil {
+				return err
+			}
+			sendErr = nil
+		default:
+		}
+		resp, err := doCommand(ctx, map[string]interface{}{recvStreamKey: map[string]interface{}{streamIDKey: id}})
+		if err != nil {
+			return err
+		}
+		msgs, _ := resp[messagesKey].([]interface{}) //nolint:errcheck
+		for _, raw := range msgs {
+			msg, ok := raw.(map[string]interface{})
+			if !ok {
+				return errors.Errorf("%q entries must be objects", messagesKey)
+			}
+			if err := conn.Send(msg); err != nil {
+				return err
+			}
+		}
+		if done, _ := resp[doneKey].(bool); done { //nolint:errcheck
+			return nil
+		}
+	}
+}
+
+// relayToServer sends the messages of `conn` to the server until its send side is closed.
+func relayToServer(
+	ctx context.Context,
+	doCommand func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error),
+	id string,
+	conn CommandStreamConn,
+) error {
+	for {
+		msg, err := conn.Recv()
+		args := map[string]interface{}{streamIDKey: id}
+		switch {
+		case err == nil:
+			args[messagesKey] = []interface{}{msg}
+		case errors.Is(err, io.EOF):
+			args[closeSendKey] = true
+		default:
+			return nil
+		}
+		if _, sendErr := doCommand(ctx, map[string]interface{}{sendStreamKey: args}); sendErr != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return sendErr
+		}
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+func doCommandResponse(result map[string]interface{}) (*commonpb.DoCommandResponse, bool, error) {
+	res, err := protoutils.StructToStructPb(result)
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
@@ -0,0 +1,126 @@
+package generic_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/components/generic"
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// streamingGeneric sums the "n" of each message it receives, sending the running total back, and
+// fails if it receives a message without one.
+type streamingGeneric struct {
+	*inject.GenericComponent
+}
+
+func (g *streamingGeneric) DoCommandStream(ctx context.Context, cmd map[string]interface{}, conn generic.CommandStreamConn) error {
+	total, _ := cmd["start"].(float64)
+	for {
+		msg, err := conn.Recv()
+		if errors.Is(err, io.EOF) {
+			return conn.Send(map[string]interface{}{"final": total})
+		}
+		if err != nil {
+			return err
+		}
+		n, ok := msg["n"].(float64)
+		if !ok {
+			return errors.New("missing n")
+		}
+		total += n
+		if err := conn.Send(map[string]interface{}{"total": total}); err != nil {
+			return err
+		}
+	}
+}
+
+func TestCommandStream(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	resourceMap := map[resource.Name]resource.Resource{
+		generic.Named(testGenericName): &streamingGeneric{inject.NewGenericComponent(testGenericName)},
+		generic.Named(failGenericName): inject.NewGenericComponent(failGenericName),
+	}
+	genericSvc, err := resource.NewAPIResourceCollection(generic.API, resourceMap)
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[resource.Resource](generic.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, genericSvc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+
+	t.Run("stream over client", func(t *testing.T) {
+		client, err := generic.NewClientFromConn(context.Background(), conn, "", generic.Named(testGenericName), logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		stream, err := generic.OpenCommandStream(context.Background(), client, map[string]interface{}{"start": 10})
+		test.That(t, err, test.ShouldBeNil)
+		defer stream.Close()
+
+		for i := 1; i <= 3; i++ {
+			test.That(t, stream.Send(map[string]interface{}{"n": i}), test.ShouldBeNil)
+		}
+		for _, want := range []float64{11, 13, 16} {
+			msg, err := stream.Recv()
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, msg["total"], test.ShouldEqual, want)
+		}
+		test.That(t, stream.CloseSend(), test.ShouldBeNil)
+		test.That(t, stream.Send(map[string]interface{}{"n": 1}), test.ShouldBeError, generic.ErrSendClosed)
+
+		msg, err := stream.Recv()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, msg["final"], test.ShouldEqual, 16)
+		_, err = stream.Recv()
+		test.That(t, err, test.ShouldBeError, io.EOF)
+	})
+
+	t.Run("resource error ends stream", func(t *testing.T) {
+		client, err := generic.NewClientFromConn(context.Background(), conn, "", generic.Named(testGenericName), logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		stream, err := generic.OpenCommandStream(context.Background(), client, nil)
+		test.That(t, err, test.ShouldBeNil)
+		defer stream.Close()
+
+		test.That(t, stream.Send(map[string]interface{}{"m": 1}), test.ShouldBeNil)
+		_, err = stream.Recv()
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "missing n")
+	})
+
+	t.Run("resource without streams", func(t *testing.T) {
+		client, err := generic.NewClientFromConn(context.Background(), conn, "", generic.Named(failGenericName), logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		stream, err := generic.OpenCommandStream(context.Background(), client, nil)
+		test.That(t, err, test.ShouldBeNil)
+		defer stream.Close()
+		_, err = stream.Recv()
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "does not support command streams")
+
+		_, err = generic.OpenCommandStream(context.Background(), inject.NewGenericComponent(failGenericName), nil)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
@@ -47,6 +47,10 @@ func TestClient(t *testing.T) {
 	injectGripper.GeometriesFunc = func(ctx context.Context) ([]spatialmath.Geometry, error) {
 		return expectedGeometries, nil
 	}
+	injectGripper.IsHoldingSomethingFunc = func(ctx context.Context, extra map[string]interface{}) (gripper.HoldingStatus, error) {
+		extraOptions = extra
+		return gripper.HoldingStatus{IsHoldingSomething: true, WidthMM: 25, HasWidth: true}, nil
+	}
 
 	injectGripper2 := &inject.Gripper{}
 	injectGripper2.OpenFunc = func(ctx context.Context, extra map[string]interface{}) error {
@@ -111,6 +115,14 @@ func TestClient(t *testing.T) {
 		test.That(t, extraOptions, test.ShouldResemble, extra)
 		test.That(t, grabbed, test.ShouldEqual, grabbed1)
 
+		extra = map[string]interface{}{"foo": "IsHoldingSomething"}
+		sensor, ok := gripper1Client.(gripper.HoldingSensor)
+		test.That(t, ok, test.ShouldBeTrue)
+		status, err := sensor.IsHoldingSomething(context.Background(), extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+		test.That(t, status, test.ShouldResemble, gripper.HoldingStatus{IsHoldingSomething: true, WidthMM: 25, HasWidth: true})
+
 		extra = map[string]interface{}{"foo": "Stop"}
 		test.That(t, gripper1Client.Stop(context.Background(), extra), test.ShouldBeNil)
 		test.That(t, extraOptions, test.ShouldResemble, extra)
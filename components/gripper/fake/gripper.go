@@ -14,9 +14,17 @@ import (
 
 var model = resource.DefaultModelFamily.WithModel("fake")
 
+// openWidthMM is the finger width of the fake gripper when open.
+const openWidthMM = 100.0
+
 // Config is the config for a trossen gripper.
 type Config struct {
 	resource.TriviallyValidateConfig
+	// ObjectWidthMM simulates an object of this width between the fingers, which Grab then holds.
+	// No object is simulated if zero.
+	ObjectWidthMM float64 `json:"object_width_mm,omitempty"`
+	// GripForceN is the force reported while holding the simulated object.
+	GripForceN float64 `json:"grip_force_n,omitempty"`
 }
 
 func init() {
@@ -30,6 +38,11 @@ type Gripper struct {
 	geometries []spatialmath.Geometry
 	mu         sync.Mutex
 	logger     logging.Logger
+
+	objectWidthMM float64
+	gripForceN    float64
+	widthMM       float64
+	holding       bool
 }
 
 // NewGripper instantiates a new gripper of the fake model type.
@@ -40,6 +53,7 @@ func NewGripper(
 		Named:      conf.ResourceName().AsNamed(),
 		geometries: []spatialmath.Geometry{},
 		logger:     logger,
+		widthMM:    openWidthMM,
 	}
 	if err := g.Reconfigure(ctx, deps, conf); err != nil {
 		return nil, err
@@ -59,6 +73,10 @@ func (g *Gripper) Reconfigure(_ context.Context, _ resource.Dependencies, conf r
 		}
 		g.geometries = []spatialmath.Geometry{geometry}
 	}
+	if newConf, ok := conf.ConvertedAttributes.(*Config); ok {
+		g.objectWidthMM = newConf.ObjectWidthMM
+		g.gripForceN = newConf.GripForceN
+	}
 	return nil
 }
 
@@ -67,14 +85,41 @@ func (g *Gripper) ModelFrame() referenceframe.Model {
 	return nil
 }
 
-// Open does nothing.
+// Open releases the simulated object, if any.
 func (g *Gripper) Open(ctx context.Context, extra map[string]interface{}) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.widthMM = openWidthMM
+	g.holding = false
 	return nil
 }
 
-// Grab does nothing.
+// Grab closes on the simulated object, if any, and returns whether it was grabbed.
 func (g *Gripper) Grab(ctx context.Context, extra map[string]interface{}) (bool, error) {
-	return false, nil
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.holding = g.objectWidthMM > 0
+	g.widthMM = g.objectWidthMM
+	return g.holding, nil
+}
+
+// IsHoldingSomething returns whether the simulated object is held, with the finger width and the
+// configured grip force.
+func (g *Gripper) IsHoldingSomething(ctx context.Context, extra map[string]interface{}) (gripper.HoldingStatus, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	status := gripper.HoldingStatus{
+		IsHoldingSomething: g.holding,
+		WidthMM:            g.widthMM,
+		HasWidth:           true,
+	}
+	if g.gripForceN > 0 {
+		status.HasForce = true
+		if g.holding {
+			status.ForceN = g.gripForceN
+		}
+	}
+	return status, nil
 }
 
 // Stop doesn't do anything for a fake gripper.
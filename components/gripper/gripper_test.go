@@ -35,3 +35,31 @@ func TestGetGeometries(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, geometries, test.ShouldResemble, []spatialmath.Geometry{expected})
 }
+
+func TestFakeIsHoldingSomething(t *testing.T) {
+	ctx := context.Background()
+	cfg := resource.Config{Name: "fakeGripper", API: gripper.API, ConvertedAttributes: &fake.Config{ObjectWidthMM: 30, GripForceN: 12}}
+	g, err := fake.NewGripper(ctx, nil, cfg, nil)
+	test.That(t, err, test.ShouldBeNil)
+	sensor, ok := g.(gripper.HoldingSensor)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	status, err := sensor.IsHoldingSomething(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, status.IsHoldingSomething, test.ShouldBeFalse)
+	test.That(t, status.WidthMM, test.ShouldEqual, 100)
+
+	grabbed, err := g.Grab(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, grabbed, test.ShouldBeTrue)
+	status, err = sensor.IsHoldingSomething(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, status, test.ShouldResemble, gripper.HoldingStatus{
+		IsHoldingSomething: true, ForceN: 12, HasForce: true, WidthMM: 30, HasWidth: true,
+	})
+
+	test.That(t, g.Open(ctx, nil), test.ShouldBeNil)
+	status, err = sensor.IsHoldingSomething(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, status.IsHoldingSomething, test.ShouldBeFalse)
+}
@@ -0,0 +1,97 @@
+package gripper
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+)
+
+// The gripper proto has no grasp state RPC. It is carried over DoCommand using the following
+// reserved keys.
+const (
+	isHoldingSomethingKey = "is_holding_something"
+	holdingKey            = "holding"
+	forceNKey             = "force_n"
+	widthMMKey            = "width_mm"
+	extraKey              = "extra"
+)
+
+// HoldingStatus describes what a gripper is holding.
+type HoldingStatus struct {
+	IsHoldingSomething bool
+	// ForceN is the grip force in newtons, if HasForce.
+	ForceN   float64
+	HasForce bool
+	// WidthMM is the distance between the gripper's fingers in millimeters, if HasWidth.
+	WidthMM  float64
+	HasWidth bool
+}
+
+// HoldingSensor is implemented by grippers that can sense whether they hold an object, for example
+// from finger position or grip force feedback, so pick-and-place logic can verify grasps.
+//
+// IsHoldingSomething example:
+//
+//	myGripper, err := gripper.FromRobot(machine, "my_gripper")
+//	grabbed, err := myGripper.Grab(context.Background(), nil)
+//	if sensor, ok := myGripper.(gripper.HoldingSensor); ok {
+//		status, err := sensor.IsHoldingSomething(context.Background(), nil)
+//		if !status.IsHoldingSomething {
+//			// The object slipped, try again.
+//		}
+//	}
+type HoldingSensor interface {
+	// IsHoldingSomething returns whether the gripper holds an object, along with the force and
+	// width readings it measured, if any.
+	IsHoldingSomething(ctx context.Context, extra map[string]interface{}) (HoldingStatus, error)
+}
+
+func (c *client) IsHoldingSomething(ctx context.Context, extra map[string]interface{}) (HoldingStatus, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		isHoldingSomethingKey: map[string]interface{}{extraKey: extra},
+	})
+	if err != nil {
+		return HoldingStatus{}, err
+	}
+	holding, ok := resp[holdingKey].(bool)
+	if !ok {
+		return HoldingStatus{}, errors.Errorf("expected %q in response, got %v", holdingKey, resp)
+	}
+	status := HoldingStatus{IsHoldingSomething: holding}
+	status.ForceN, status.HasForce = resp[forceNKey].(float64)
+	status.WidthMM, status.HasWidth = resp[widthMMKey].(float64)
+	return status, nil
+}
+
+// doHoldingCommand handles the reserved grasp state DoCommand key. It returns false if `req` is not
+// a grasp state command or the gripper does not implement HoldingSensor.
+func doHoldingCommand(ctx context.Context, g Gripper, req *commonpb.DoCommandRequest) (*commonpb.DoCommandResponse, bool, error) {
+	sensor, ok := g.(HoldingSensor)
+	if !ok {
+		return nil, false, nil
+	}
+	payload, ok := req.GetCommand().AsMap()[isHoldingSomethingKey]
+	if !ok {
+		return nil, false, nil
+	}
+	args, _ := payload.(map[string]interface{})         //nolint:errcheck
+	extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+	status, err := sensor.IsHoldingSomething(ctx, extra)
+	if err != nil {
+		return nil, true, err
+	}
+	result := map[string]interface{}{holdingKey: status.IsHoldingSomething}
+	if status.HasForce {
+		result[forceNKey] = status.ForceN
+	}
+	if status.HasWidth {
+		result[widthMMKey] = status.WidthMM
+	}
+	res, err := protoutils.StructToStructPb(result)
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
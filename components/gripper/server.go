@@ -84,6 +84,9 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := doHoldingCommand(ctx, gripper, req); handled {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, gripper, req)
 }
 
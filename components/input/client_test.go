@@ -2,10 +2,12 @@ package input_test
 
 import (
 	"context"
+	"image/color"
 	"net"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"go.viam.com/test"
 	"go.viam.com/utils/rpc"
 
@@ -79,6 +81,18 @@ func TestClient(t *testing.T) {
 	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, inputControllerSvc), test.ShouldBeNil)
 
 	injectInputController.DoFunc = testutils.EchoFunc
+	var gotRumble input.Rumble
+	injectInputController.RumbleFunc = func(ctx context.Context, rumble input.Rumble, extra map[string]interface{}) error {
+		extraOptions = extra
+		gotRumble = rumble
+		return nil
+	}
+	var gotColor color.Color
+	injectInputController.SetLEDColorFunc = func(ctx context.Context, c color.Color, extra map[string]interface{}) error {
+		extraOptions = extra
+		gotColor = c
+		return nil
+	}
 
 	go rpcServer.Serve(listener1)
 	defer rpcServer.Stop()
@@ -108,7 +122,23 @@ func TestClient(t *testing.T) {
 		test.That(t, resp["command"], test.ShouldEqual, testutils.TestCommand["command"])
 		test.That(t, resp["data"], test.ShouldEqual, testutils.TestCommand["data"])
 
-		extra := map[string]interface{}{"foo": "Controls"}
+		// Feedback
+		fb, ok := inputController1Client.(input.FeedbackController)
+		test.That(t, ok, test.ShouldBeTrue)
+		extra := map[string]interface{}{"foo": "Rumble"}
+		rumble := input.Rumble{StrongMagnitude: 1, WeakMagnitude: 0.5, Duration: 250 * time.Millisecond}
+		test.That(t, fb.Rumble(context.Background(), rumble, extra), test.ShouldBeNil)
+		test.That(t, gotRumble, test.ShouldResemble, rumble)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+		err = fb.Rumble(context.Background(), input.Rumble{StrongMagnitude: 2}, nil)
+		test.That(t, err, test.ShouldBeError, errors.New("rumble magnitudes must be between 0 and 1"))
+
+		extra = map[string]interface{}{"foo": "SetLEDColor"}
+		test.That(t, fb.SetLEDColor(context.Background(), color.RGBA{R: 255, B: 128, A: 255}, extra), test.ShouldBeNil)
+		test.That(t, gotColor, test.ShouldResemble, color.NRGBA{R: 255, B: 128, A: 255})
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+
+		extra = map[string]interface{}{"foo": "Controls"}
 		controlList, err := inputController1Client.Controls(context.Background(), extra)
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, controlList, test.ShouldResemble, []input.Control{input.AbsoluteX, input.ButtonStart})
@@ -3,6 +3,7 @@ package fake
 
 import (
 	"context"
+	"image/color"
 	"math/rand"
 	"sync"
 	"time"
@@ -89,6 +90,10 @@ type InputController struct {
 	callbackDelay *time.Duration
 	callbacks     []callback
 	logger        logging.Logger
+
+	rumble      input.Rumble
+	rumbleUntil time.Time
+	ledColor    color.NRGBA
 }
 
 // Reconfigure updates the config of the controller.
@@ -198,6 +203,26 @@ func (c *InputController) TriggerEvent(ctx context.Context, event input.Event, e
 	return errors.New("unsupported")
 }
 
+// Rumble records the rumble, which plays until its duration has passed.
+func (c *InputController) Rumble(ctx context.Context, rumble input.Rumble, extra map[string]interface{}) error {
+	if err := rumble.Validate(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rumble = rumble
+	c.rumbleUntil = time.Now().Add(rumble.Duration)
+	return nil
+}
+
+// SetLEDColor records the LED color.
+func (c *InputController) SetLEDColor(ctx context.Context, col color.Color, extra map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ledColor = color.NRGBAModel.Convert(col).(color.NRGBA)
+	return nil
+}
+
 // Close attempts to cleanly close the input controller.
 func (c *InputController) Close(ctx context.Context) error {
 	c.mu.Lock()
@@ -2,6 +2,7 @@ package fake
 
 import (
 	"context"
+	"image/color"
 	"testing"
 	"time"
 
@@ -153,3 +154,20 @@ func TestTriggerEvent(t *testing.T) {
 	err := i.TriggerEvent(context.Background(), input.Event{}, nil)
 	test.That(t, err, test.ShouldBeError, errors.New("unsupported"))
 }
+
+func TestFeedback(t *testing.T) {
+	c := setupDefaultInput(t)
+	defer c.Close(context.Background())
+
+	var fb input.FeedbackController = c
+	rumble := input.Rumble{StrongMagnitude: 1, WeakMagnitude: 0.5, Duration: time.Second}
+	test.That(t, fb.Rumble(context.Background(), rumble, nil), test.ShouldBeNil)
+	test.That(t, c.rumble, test.ShouldResemble, rumble)
+	test.That(t, c.rumbleUntil.After(time.Now()), test.ShouldBeTrue)
+
+	err := fb.Rumble(context.Background(), input.Rumble{StrongMagnitude: 2}, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	test.That(t, fb.SetLEDColor(context.Background(), color.RGBA{R: 128, A: 255}, nil), test.ShouldBeNil)
+	test.That(t, c.ledColor, test.ShouldResemble, color.NRGBA{R: 128, A: 255})
+}
@@ -0,0 +1,153 @@
+package input
+
+import (
+	"context"
+	"image/color"
+	"time"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+)
+
+// The input controller proto has no output RPCs. Feedback commands are carried over DoCommand using
+// the following reserved keys.
+const (
+	rumbleKey          = "rumble"
+	strongMagnitudeKey = "strong_magnitude"
+	weakMagnitudeKey   = "weak_magnitude"
+	durationSecsKey    = "duration_secs"
+	setLEDColorKey     = "set_led_color"
+	redKey             = "red"
+	greenKey           = "green"
+	blueKey            = "blue"
+	extraKey           = "extra"
+)
+
+// Rumble is a vibration of a controller's force feedback motors.
+type Rumble struct {
+	// StrongMagnitude and WeakMagnitude are the strengths, from 0 to 1, of the low frequency
+	// (strong) and high frequency (weak) motors.
+	StrongMagnitude float64
+	WeakMagnitude   float64
+	// Duration is how long the controller vibrates.
+	Duration time.Duration
+}
+
+// Validate ensures the rumble's values are within range.
+func (r Rumble) Validate() error {
+	if r.StrongMagnitude < 0 || r.StrongMagnitude > 1 || r.WeakMagnitude < 0 || r.WeakMagnitude > 1 {
+		return errors.New("rumble magnitudes must be between 0 and 1")
+	}
+	if r.Duration < 0 {
+		return errors.New("rumble duration cannot be negative")
+	}
+	return nil
+}
+
+// FeedbackController is implemented by input controllers with outputs, such as force feedback
+// motors or color LEDs, so that a teleoperation station can signal state, such as an error, an
+// emergency stop or recording, back through the controller.
+//
+// Feedback example:
+//
+//	myController, err := input.FromRobot(machine, "my_controller")
+//	if fb, ok := myController.(input.FeedbackController); ok {
+//		// Signal an emergency stop with a red light and a long vibration.
+//		err = fb.SetLEDColor(context.Background(), color.NRGBA{R: 255, A: 255}, nil)
+//		err = fb.Rumble(context.Background(), input.Rumble{StrongMagnitude: 1, Duration: time.Second}, nil)
+//	}
+type FeedbackController interface {
+	// Rumble vibrates the controller. A rumble replaces any rumble that is still playing, so a zero
+	// rumble stops it.
+	Rumble(ctx context.Context, rumble Rumble, extra map[string]interface{}) error
+
+	// SetLEDColor sets the color of the controller's LEDs. Controllers with single color LEDs use
+	// the color's brightness.
+	SetLEDColor(ctx context.Context, c color.Color, extra map[string]interface{}) error
+}
+
+func (c *client) Rumble(ctx context.Context, rumble Rumble, extra map[string]interface{}) error {
+	if err := rumble.Validate(); err != nil {
+		return err
+	}
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		rumbleKey: map[string]interface{}{
+			strongMagnitudeKey: rumble.StrongMagnitude,
+			weakMagnitudeKey:   rumble.WeakMagnitude,
+			durationSecsKey:    rumble.Duration.Seconds(),
+			extraKey:           extra,
+		},
+	})
+	return err
+}
+
+func (c *client) SetLEDColor(ctx context.Context, col color.Color, extra map[string]interface{}) error {
+	rgb := color.NRGBAModel.Convert(col).(color.NRGBA)
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		setLEDColorKey: map[string]interface{}{
+			redKey:   float64(rgb.R),
+			greenKey: float64(rgb.G),
+			blueKey:  float64(rgb.B),
+			extraKey: extra,
+		},
+	})
+	return err
+}
+
+// doFeedbackCommand handles the reserved feedback DoCommand keys. It returns false if `req` is not
+// a feedback command or the controller does not implement FeedbackController.
+func doFeedbackCommand(ctx context.Context, c Controller, req *commonpb.DoCommandRequest) (*commonpb.DoCommandResponse, bool, error) {
+	fb, ok := c.(FeedbackController)
+	if !ok {
+		return nil, false, nil
+	}
+	cmd := req.GetCommand().AsMap()
+	if payload, ok := cmd[rumbleKey]; ok {
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", rumbleKey)
+		}
+		strong, _ := args[strongMagnitudeKey].(float64)     //nolint:errcheck
+		weak, _ := args[weakMagnitudeKey].(float64)         //nolint:errcheck
+		secs, _ := args[durationSecsKey].(float64)          //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		rumble := Rumble{
+			StrongMagnitude: strong,
+			WeakMagnitude:   weak,
+			Duration:        time.Duration(secs * float64(time.Second)),
+		}
+		if err := rumble.Validate(); err != nil {
+			return nil, true, err
+		}
+		return feedbackResponse(fb.Rumble(ctx, rumble, extra))
+	}
+	if payload, ok := cmd[setLEDColorKey]; ok {
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", setLEDColorKey)
+		}
+		var rgb [3]uint8
+		for i, key := range []string{redKey, greenKey, blueKey} {
+			val, ok := args[key].(float64)
+			if !ok || val < 0 || val > 255 {
+				return nil, true, errors.Errorf("%q must be a number between 0 and 255", key)
+			}
+			rgb[i] = uint8(val)
+		}
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		return feedbackResponse(fb.SetLEDColor(ctx, color.NRGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 255}, extra))
+	}
+	return nil, false, nil
+}
+
+func feedbackResponse(err error) (*commonpb.DoCommandResponse, bool, error) {
+	if err != nil {
+		return nil, true, err
+	}
+	res, err := protoutils.StructToStructPb(map[string]interface{}{})
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
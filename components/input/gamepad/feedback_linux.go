@@ -0,0 +1,194 @@
+//go:build linux
+// +build linux
+
+package gamepad
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+
+	"go.viam.com/rdk/components/input"
+)
+
+const (
+	evFF     = 0x15
+	ffRumble = 0x50
+)
+
+// sysClassInput is where the kernel exposes input devices, including the LEDs of gamepads.
+var sysClassInput = "/sys/class/input"
+
+// ffEffect mirrors the kernel's struct ff_effect holding a rumble effect. The effect parameters
+// are a union sized for its largest member, a periodic effect ending in a pointer.
+type ffEffect struct {
+	effectType      uint16
+	id              int16
+	direction       uint16
+	triggerButton   uint16
+	triggerInterval uint16
+	replayLength    uint16
+	replayDelay     uint16
+	_               uint16
+	strongMagnitude uint16
+	weakMagnitude   uint16
+	_               [20 + unsafe.Sizeof(uintptr(0))]byte
+}
+
+// evIOCSFF is the EVIOCSFF ioctl, which uploads a force feedback effect.
+var evIOCSFF = uintptr(1<<30 | unsafe.Sizeof(ffEffect{})<<16 | 'E'<<8 | 0x80)
+
+// inputEvent mirrors the kernel's struct input_event.
+type inputEvent struct {
+	time  unix.Timeval
+	typ   uint16
+	code  uint16
+	value int32
+}
+
+// feedbackFile returns a file for uploading and playing force feedback effects on the connected
+// gamepad. Effects belong to the file they were uploaded with, so it is kept open. It must be
+// called with g.mu held.
+func (g *gamepad) feedbackFile() (*os.File, error) {
+	if g.devPath == "" {
+		return nil, errors.New("no gamepad connected")
+	}
+	if g.ff != nil && g.ff.Name() == g.devPath {
+		return g.ff, nil
+	}
+	g.closeFeedbackFile()
+	f, err := os.OpenFile(g.devPath, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	g.ff = f
+	g.rumbleID = -1
+	return f, nil
+}
+
+// closeFeedbackFile closes the force feedback file, which also removes its effects. It must be
+// called with g.mu held.
+func (g *gamepad) closeFeedbackFile() {
+	if g.ff == nil {
+		return
+	}
+	if err := g.ff.Close(); err != nil {
+		g.logger.Debugw("error closing gamepad force feedback file", "error", err)
+	}
+	g.ff = nil
+}
+
+// Rumble plays a rumble effect on the gamepad's force feedback motors.
+func (g *gamepad) Rumble(ctx context.Context, rumble input.Rumble, extra map[string]interface{}) error {
+	if err := rumble.Validate(); err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	f, err := g.feedbackFile()
+	if err != nil {
+		return err
+	}
+
+	effect := ffEffect{
+		effectType:      ffRumble,
+		id:              g.rumbleID,
+		replayLength:    uint16(math.Min(float64(rumble.Duration.Milliseconds()), math.MaxUint16)),
+		strongMagnitude: uint16(rumble.StrongMagnitude * math.MaxUint16),
+		weakMagnitude:   uint16(rumble.WeakMagnitude * math.MaxUint16),
+	}
+	// uploading with the id of a previous upload replaces that effect
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), evIOCSFF, uintptr(unsafe.Pointer(&effect))); errno != 0 {
+		return errors.Wrap(errno, "gamepad does not support rumble")
+	}
+	g.rumbleID = effect.id
+
+	play := int32(1)
+	if rumble.Duration == 0 || (rumble.StrongMagnitude == 0 && rumble.WeakMagnitude == 0) {
+		play = 0
+	}
+	ev := inputEvent{typ: evFF, code: uint16(effect.id), value: play}
+	if _, err := f.Write(unsafe.Slice((*byte)(unsafe.Pointer(&ev)), unsafe.Sizeof(ev))); err != nil {
+		g.closeFeedbackFile()
+		return err
+	}
+	return nil
+}
+
+// SetLEDColor sets the gamepad's LEDs, as exposed by its kernel driver. Red, green and blue LEDs,
+// such as a light bar's, and multicolor LEDs display the color, and other LEDs are lit by its
+// brightness.
+func (g *gamepad) SetLEDColor(ctx context.Context, col color.Color, extra map[string]interface{}) error {
+	g.mu.RLock()
+	devPath := g.devPath
+	g.mu.RUnlock()
+	if devPath == "" {
+		return errors.New("no gamepad connected")
+	}
+	rgb := color.NRGBAModel.Convert(col).(color.NRGBA)
+	channels := map[string]uint8{"red": rgb.R, "green": rgb.G, "blue": rgb.B}
+
+	leds, err := filepath.Glob(filepath.Join(sysClassInput, filepath.Base(devPath), "device", "device", "leds", "*"))
+	if err != nil {
+		return err
+	}
+	if len(leds) == 0 {
+		return errors.New("gamepad has no LEDs")
+	}
+	for _, led := range leds {
+		maxBrightness, err := readSysfsInt(filepath.Join(led, "max_brightness"))
+		if err != nil {
+			return err
+		}
+		level := float64(max(rgb.R, rgb.G, rgb.B)) / 255
+		name := filepath.Base(led)
+		if idx := strings.LastIndex(name, ":"); idx >= 0 {
+			if channel, ok := channels[name[idx+1:]]; ok {
+				level = float64(channel) / 255
+			}
+		}
+		if index, err := os.ReadFile(filepath.Join(led, "multi_index")); err == nil {
+			// a multicolor LED sets the intensity of each of its channels, in the order of its index
+			var intensities []string
+			for _, channel := range strings.Fields(string(index)) {
+				intensities = append(intensities, strconv.Itoa(int(channels[channel])))
+			}
+			if err := writeSysfs(filepath.Join(led, "multi_intensity"), strings.Join(intensities, " ")); err != nil {
+				return err
+			}
+			level = 1
+			if rgb.R == 0 && rgb.G == 0 && rgb.B == 0 {
+				level = 0
+			}
+		}
+		if err := writeSysfs(filepath.Join(led, "brightness"), strconv.Itoa(int(math.Round(level*float64(maxBrightness))))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readSysfsInt(path string) (int, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+func writeSysfs(path, value string) error {
+	//nolint:gosec
+	if err := os.WriteFile(path, []byte(value), 0o644); err != nil {
+		return fmt.Errorf("setting gamepad LED: %w", err)
+	}
+	return nil
+}
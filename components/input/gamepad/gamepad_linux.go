@@ -7,6 +7,7 @@ package gamepad
 import (
 	"context"
 	"math"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -104,6 +105,11 @@ type gamepad struct {
 	callbacks               map[input.Control]map[input.EventType]input.ControlFunction
 	devFile                 string
 	reconnect               bool
+	// devPath is the device file of the connected gamepad, and ff the file its force feedback
+	// effects were uploaded with.
+	devPath  string
+	ff       *os.File
+	rumbleID int16
 }
 
 // Mapping represents the evdev code to input.Control mapping for a given gamepad model.
@@ -151,6 +157,7 @@ func (g *gamepad) eventDispatcher(ctx context.Context) {
 						g.logger.CError(ctx, err)
 					}
 					g.dev = nil
+					g.devPath = ""
 					return
 				}
 				g.logger.CDebugf(ctx, "unhandled event: %+v", eventIn)
@@ -306,6 +313,7 @@ func (g *gamepad) connectDev(ctx context.Context) error {
 		if ok {
 			g.logger.CInfof(ctx, "found known gamepad: '%s' at %s", name, n)
 			g.dev = dev
+			g.devPath = n
 			g.Model = g.dev.Name()
 			g.Mapping = mapping
 			break
@@ -327,6 +335,7 @@ func (g *gamepad) connectDev(ctx context.Context) error {
 				g.logger.CInfof(ctx, "found gamepad: '%s' at %s", name, n)
 				g.logger.CInfof(ctx, "no button mapping for '%s', using default: '%s'", name, defaultMapping)
 				g.dev = dev
+				g.devPath = n
 				g.Model = g.dev.Name()
 				g.Mapping, _ = MappingForModel(defaultMapping)
 				break
@@ -364,6 +373,9 @@ func (g *gamepad) Close(ctx context.Context) error {
 			g.logger.CError(ctx, err)
 		}
 	}
+	g.mu.Lock()
+	g.closeFeedbackFile()
+	g.mu.Unlock()
 	return nil
 }
 
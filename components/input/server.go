@@ -194,5 +194,8 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := doFeedbackCommand(ctx, controller, req); handled {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, controller, req)
 }
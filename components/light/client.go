@@ -0,0 +1,214 @@
+package light
+
+import (
+	"context"
+	"image/color"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The light commands are carried over DoCommand using the following reserved keys.
+const (
+	setColorKey      = "set_color"
+	segmentKey       = "segment"
+	colorKey         = "color"
+	setBrightnessKey = "set_brightness"
+	brightnessKey    = "brightness"
+	animateKey       = "animate"
+	typeKey          = "type"
+	periodSecsKey    = "period_secs"
+	getStateKey      = "get_state"
+	pixelsKey        = "pixels"
+	segmentsKey      = "segments"
+	startKey         = "start"
+	countKey         = "count"
+	animationsKey    = "animations"
+	extraKey         = "extra"
+)
+
+// client implements Light over the DoCommand of a resource that does not implement it, such as the
+// generic client of a remote light.
+type client struct {
+	resource.Resource
+}
+
+// NewClientFromResource returns a Light calling `res` over DoCommand.
+func NewClientFromResource(res resource.Resource) Light {
+	return &client{Resource: res}
+}
+
+func (c *client) SetColor(ctx context.Context, segment string, col color.RGBA, extra map[string]interface{}) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		setColorKey: map[string]interface{}{segmentKey: segment, colorKey: Hex(col), extraKey: extra},
+	})
+	return err
+}
+
+func (c *client) SetBrightness(ctx context.Context, brightness float64, extra map[string]interface{}) error {
+	if err := validateBrightness(brightness); err != nil {
+		return err
+	}
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		setBrightnessKey: map[string]interface{}{brightnessKey: brightness, extraKey: extra},
+	})
+	return err
+}
+
+func (c *client) Animate(ctx context.Context, segment string, animation Animation, extra map[string]interface{}) error {
+	if err := animation.Validate(); err != nil {
+		return err
+	}
+	args := animationToMap(animation)
+	args[segmentKey] = segment
+	args[extraKey] = extra
+	_, err := c.DoCommand(ctx, map[string]interface{}{animateKey: args})
+	return err
+}
+
+func (c *client) State(ctx context.Context, extra map[string]interface{}) (State, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		getStateKey: map[string]interface{}{extraKey: extra},
+	})
+	if err != nil {
+		return State{}, err
+	}
+	brightness, ok := resp[brightnessKey].(float64)
+	if !ok {
+		return State{}, errors.Errorf("expected %q in response, got %v", brightnessKey, resp)
+	}
+	pixels, _ := resp[pixelsKey].([]interface{})                  //nolint:errcheck
+	segments, _ := resp[segmentsKey].(map[string]interface{})     //nolint:errcheck
+	animations, _ := resp[animationsKey].(map[string]interface{}) //nolint:errcheck
+	state := State{
+		Brightness: brightness,
+		Pixels:     make([]color.RGBA, 0, len(pixels)),
+		Segments:   make(map[string]Segment, len(segments)),
+		Animations: make(map[string]Animation, len(animations)),
+	}
+	for _, p := range pixels {
+		hex, _ := p.(string) //nolint:errcheck
+		pixel, err := ParseHex(hex)
+		if err != nil {
+			return State{}, err
+		}
+		state.Pixels = append(state.Pixels, pixel)
+	}
+	for name, s := range segments {
+		segment, _ := s.(map[string]interface{}) //nolint:errcheck
+		start, _ := segment[startKey].(float64)  //nolint:errcheck
+		count, _ := segment[countKey].(float64)  //nolint:errcheck
+		state.Segments[name] = Segment{Start: int(start), Count: int(count)}
+	}
+	for name, a := range animations {
+		args, _ := a.(map[string]interface{}) //nolint:errcheck
+		animation, err := animationFromMap(args)
+		if err != nil {
+			return State{}, err
+		}
+		state.Animations[name] = animation
+	}
+	return state, nil
+}
+
+// HandleDoCommand handles the reserved light DoCommand keys. Models call it first from their
+// DoCommand, and handle `cmd` themselves if it returns false.
+func HandleDoCommand(ctx context.Context, l Light, cmd map[string]interface{}) (map[string]interface{}, bool, error) {
+	if payload, ok := cmd[setColorKey]; ok {
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", setColorKey)
+		}
+		segment, _ := args[segmentKey].(string)             //nolint:errcheck
+		hex, _ := args[colorKey].(string)                   //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		col, err := ParseHex(hex)
+		if err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{}, true, l.SetColor(ctx, segment, col, extra)
+	}
+	if payload, ok := cmd[setBrightnessKey]; ok {
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", setBrightnessKey)
+		}
+		brightness, ok := args[brightnessKey].(float64)
+		if !ok {
+			return nil, true, errors.Errorf("%q must be a number", brightnessKey)
+		}
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		if err := validateBrightness(brightness); err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{}, true, l.SetBrightness(ctx, brightness, extra)
+	}
+	if payload, ok := cmd[animateKey]; ok {
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", animateKey)
+		}
+		segment, _ := args[segmentKey].(string)             //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		animation, err := animationFromMap(args)
+		if err != nil {
+			return nil, true, err
+		}
+		if err := animation.Validate(); err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{}, true, l.Animate(ctx, segment, animation, extra)
+	}
+	if payload, ok := cmd[getStateKey]; ok {
+		args, _ := payload.(map[string]interface{})         //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		state, err := l.State(ctx, extra)
+		if err != nil {
+			return nil, true, err
+		}
+		pixels := make([]interface{}, 0, len(state.Pixels))
+		for _, pixel := range state.Pixels {
+			pixels = append(pixels, Hex(pixel))
+		}
+		segments := make(map[string]interface{}, len(state.Segments))
+		for name, segment := range state.Segments {
+			segments[name] = map[string]interface{}{startKey: float64(segment.Start), countKey: float64(segment.Count)}
+		}
+		animations := make(map[string]interface{}, len(state.Animations))
+		for name, animation := range state.Animations {
+			animations[name] = animationToMap(animation)
+		}
+		return map[string]interface{}{
+			brightnessKey: state.Brightness,
+			pixelsKey:     pixels,
+			segmentsKey:   segments,
+			animationsKey: animations,
+		}, true, nil
+	}
+	return nil, false, nil
+}
+
+func animationToMap(animation Animation) map[string]interface{} {
+	return map[string]interface{}{
+		typeKey:       string(animation.Type),
+		colorKey:      Hex(animation.Color),
+		periodSecsKey: animation.Period.Seconds(),
+	}
+}
+
+func animationFromMap(args map[string]interface{}) (Animation, error) {
+	typ, _ := args[typeKey].(string)           //nolint:errcheck
+	hex, _ := args[colorKey].(string)          //nolint:errcheck
+	period, _ := args[periodSecsKey].(float64) //nolint:errcheck
+	col, err := ParseHex(hex)
+	if err != nil {
+		return Animation{}, err
+	}
+	return Animation{
+		Type:   AnimationType(typ),
+		Color:  col,
+		Period: time.Duration(period * float64(time.Second)),
+	}, nil
+}
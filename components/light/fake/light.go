@@ -0,0 +1,99 @@
+// Package fake implements a fake light that records the frames it is shown.
+package fake
+
+import (
+	"context"
+	"image/color"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/light"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("fake_light")
+
+// defaultLength is the number of pixels of a fake light when the config does not say.
+const defaultLength = 30
+
+func init() {
+	resource.RegisterComponent(light.API, model, resource.Registration[resource.Resource, *Config]{
+		Constructor: func(
+			ctx context.Context,
+			_ resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			newConf, err := resource.NativeConfig[*Config](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewLight(conf.ResourceName(), newConf, logger)
+		},
+	})
+}
+
+// Config is used for converting config attributes.
+type Config struct {
+	light.Config
+	// Length is the number of pixels. It defaults to 30.
+	Length int `json:"length,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.Length < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("length must not be negative"))
+	}
+	return nil, conf.Config.Validate(path, conf.length())
+}
+
+func (conf *Config) length() int {
+	if conf.Length == 0 {
+		return defaultLength
+	}
+	return conf.Length
+}
+
+// Light is a fake light that records the last frame it was shown.
+type Light struct {
+	resource.Named
+	resource.AlwaysRebuild
+	*light.Strip
+
+	mu    sync.Mutex
+	frame []color.RGBA
+}
+
+// NewLight returns a fake light.
+func NewLight(name resource.Name, conf *Config, logger logging.Logger) (*Light, error) {
+	l := &Light{Named: name.AsNamed()}
+	strip, err := light.NewStrip(conf.length(), conf.Segments, func(ctx context.Context, pixels []color.RGBA) error {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.frame = pixels
+		return nil
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	l.Strip = strip
+	return l, nil
+}
+
+// Frame returns the last frame shown, with the brightness applied.
+func (l *Light) Frame() []color.RGBA {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]color.RGBA(nil), l.frame...)
+}
+
+// DoCommand handles the light commands.
+func (l *Light) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, handled, err := light.HandleDoCommand(ctx, l, cmd); handled {
+		return resp, err
+	}
+	return map[string]interface{}{}, nil
+}
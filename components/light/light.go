@@ -0,0 +1,201 @@
+// Package light defines a light, such as an addressable LED strip or an RGB LED, whose pixels can
+// be set to colors and animated.
+//
+// There is no light proto, so lights are generic components whose typed methods are carried over
+// DoCommand. Models implement Light and answer the reserved commands by calling HandleDoCommand
+// from their DoCommand.
+package light
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+)
+
+// API is the resource API lights are served under.
+var API = generic.API
+
+// Named is a helper for getting the named light's typed resource name.
+func Named(name string) resource.Name {
+	return resource.NewName(API, name)
+}
+
+// AllPixels is the segment name that refers to every pixel of a light.
+const AllPixels = ""
+
+// A Segment is a run of consecutive pixels of a light that can be colored and animated on its own.
+type Segment struct {
+	Start int `json:"start"`
+	Count int `json:"count"`
+}
+
+// Validate ensures the segment fits a light of `length` pixels.
+func (s Segment) Validate(length int) error {
+	if s.Start < 0 || s.Count <= 0 || s.Start+s.Count > length {
+		return errors.Errorf("segment of %d pixels from pixel %d does not fit %d pixels", s.Count, s.Start, length)
+	}
+	return nil
+}
+
+func (s Segment) overlaps(other Segment) bool {
+	return s.Start < other.Start+other.Count && other.Start < s.Start+s.Count
+}
+
+// AnimationType is the kind of an animation.
+type AnimationType string
+
+// The supported animations.
+const (
+	// AnimationBlink turns the pixels on for the first half of each period and off for the rest.
+	AnimationBlink AnimationType = "blink"
+	// AnimationBreathe fades the pixels in and out once each period.
+	AnimationBreathe AnimationType = "breathe"
+	// AnimationRainbow cycles the pixels through the hues once each period, spread along the
+	// segment. It ignores the animation's color.
+	AnimationRainbow AnimationType = "rainbow"
+	// AnimationChase moves a single lit pixel along the segment once each period.
+	AnimationChase AnimationType = "chase"
+)
+
+// An Animation changes the colors of a segment over time, repeating every period.
+type Animation struct {
+	Type   AnimationType
+	Color  color.RGBA
+	Period time.Duration
+}
+
+// Validate ensures the animation can be played.
+func (a Animation) Validate() error {
+	switch a.Type {
+	case AnimationBlink, AnimationBreathe, AnimationRainbow, AnimationChase:
+	default:
+		return errors.Errorf("unknown animation type %q", a.Type)
+	}
+	if a.Period <= 0 {
+		return errors.New("animation period must be positive")
+	}
+	return nil
+}
+
+// State is what a light is showing.
+type State struct {
+	// Brightness scales all the pixels, from 0 to 1.
+	Brightness float64
+	// Pixels are the colors currently shown, before the brightness is applied.
+	Pixels []color.RGBA
+	// Segments are the named segments of the light.
+	Segments map[string]Segment
+	// Animations are the animations playing, by segment name.
+	Animations map[string]Animation
+}
+
+// A Light is a set of pixels that can be colored and animated, such as an addressable LED strip.
+// Pixels can be addressed all at once, or through the named segments of the light's config. The
+// alpha of colors is ignored.
+//
+// SetColor example:
+//
+//	myLight, err := light.FromRobot(machine, "my_light")
+//	// Turn the whole light red.
+//	err = myLight.SetColor(context.Background(), light.AllPixels, color.RGBA{R: 255, A: 255}, nil)
+//	// Turn the "status" segment off.
+//	err = myLight.SetColor(context.Background(), "status", color.RGBA{}, nil)
+//
+// SetBrightness example:
+//
+//	err = myLight.SetBrightness(context.Background(), 0.25, nil)
+//
+// Animate example:
+//
+//	err = myLight.Animate(context.Background(), "status", light.Animation{
+//		Type:   light.AnimationBreathe,
+//		Color:  color.RGBA{B: 255, A: 255},
+//		Period: 2 * time.Second,
+//	}, nil)
+//
+// State example:
+//
+//	state, err := myLight.State(context.Background(), nil)
+type Light interface {
+	resource.Resource
+
+	// SetColor sets every pixel of the segment to a color, stopping any animation playing on them.
+	SetColor(ctx context.Context, segment string, c color.RGBA, extra map[string]interface{}) error
+
+	// SetBrightness sets the brightness of the whole light, from 0 to 1.
+	SetBrightness(ctx context.Context, brightness float64, extra map[string]interface{}) error
+
+	// Animate plays an animation on the segment until it is colored or animated again. Animations
+	// on segments that overlap it are stopped.
+	Animate(ctx context.Context, segment string, animation Animation, extra map[string]interface{}) error
+
+	// State returns what the light is showing.
+	State(ctx context.Context, extra map[string]interface{}) (State, error)
+}
+
+// FromResource returns `res` as a Light. Resources that do not implement Light, such as the clients
+// of remote lights, are wrapped in a client that calls them over DoCommand.
+func FromResource(res resource.Resource) Light {
+	if l, ok := res.(Light); ok {
+		return l
+	}
+	return NewClientFromResource(res)
+}
+
+// FromDependencies is a helper for getting the named light from a collection of dependencies.
+func FromDependencies(deps resource.Dependencies, name string) (Light, error) {
+	res, err := generic.FromDependencies(deps, name)
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
+
+// FromRobot is a helper for getting the named light from the given Robot.
+func FromRobot(r robot.Robot, name string) (Light, error) {
+	res, err := generic.FromRobot(r, name)
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
+
+// Config is the configuration shared by lights: the named segments their pixels are split into.
+// Models embed it in their configs.
+type Config struct {
+	Segments map[string]Segment `json:"segments,omitempty"`
+}
+
+// Validate ensures the segments fit a light of `length` pixels.
+func (conf *Config) Validate(path string, length int) error {
+	for name, segment := range conf.Segments {
+		if name == AllPixels {
+			return resource.NewConfigValidationError(path, errors.New("segment names must not be empty"))
+		}
+		if err := segment.Validate(length); err != nil {
+			return resource.NewConfigValidationError(path, errors.Wrapf(err, "segment %q", name))
+		}
+	}
+	return nil
+}
+
+// ParseHex parses a color in the "#rrggbb" form.
+func ParseHex(hex string) (color.RGBA, error) {
+	c := color.RGBA{A: 0xff}
+	if n, err := fmt.Sscanf(hex, "#%02x%02x%02x", &c.R, &c.G, &c.B); n != 3 || err != nil {
+		return color.RGBA{}, errors.Errorf("color %q must be in the #rrggbb form", hex)
+	}
+	return c, nil
+}
+
+// Hex formats a color in the "#rrggbb" form.
+func Hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,88 @@
+package light_test
+
+import (
+	"context"
+	"image/color"
+	"net"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/components/light"
+	"go.viam.com/rdk/components/light/fake"
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func TestClient(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	strip, err := fake.NewLight(light.Named("strip"), &fake.Config{
+		Config: light.Config{Segments: map[string]light.Segment{"status": {Start: 2, Count: 2}}},
+		Length: 4,
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer strip.Close(context.Background())
+	coll, err := resource.NewAPIResourceCollection(generic.API, map[resource.Name]resource.Resource{
+		light.Named("strip"): strip,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[resource.Resource](generic.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, coll), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	res, err := generic.NewClientFromConn(context.Background(), conn, "", light.Named("strip"), logger)
+	test.That(t, err, test.ShouldBeNil)
+	client := light.FromResource(res)
+
+	green := color.RGBA{G: 0xc8, A: 0xff}
+	off := color.RGBA{A: 0xff}
+
+	t.Run("color and brightness", func(t *testing.T) {
+		test.That(t, client.SetColor(context.Background(), "status", green, nil), test.ShouldBeNil)
+		test.That(t, client.SetBrightness(context.Background(), 0.5, nil), test.ShouldBeNil)
+		test.That(t, strip.Frame(), test.ShouldResemble, []color.RGBA{off, off, {G: 0x64, A: 0xff}, {G: 0x64, A: 0xff}})
+
+		state, err := client.State(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, state.Brightness, test.ShouldEqual, 0.5)
+		test.That(t, state.Pixels, test.ShouldResemble, []color.RGBA{off, off, green, green})
+		test.That(t, state.Segments, test.ShouldResemble, map[string]light.Segment{"status": {Start: 2, Count: 2}})
+
+		test.That(t, client.SetBrightness(context.Background(), -1, nil), test.ShouldBeError, "brightness must be between 0 and 1")
+		test.That(t, client.SetColor(context.Background(), "missing", green, nil), test.ShouldNotBeNil)
+	})
+
+	t.Run("animate", func(t *testing.T) {
+		blink := light.Animation{Type: light.AnimationBlink, Color: green, Period: 100 * time.Millisecond}
+		test.That(t, client.Animate(context.Background(), "status", blink, nil), test.ShouldBeNil)
+		state, err := client.State(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, state.Animations, test.ShouldResemble, map[string]light.Animation{"status": blink})
+
+		// the animation keeps running in the background
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, strip.Frame(), test.ShouldResemble, []color.RGBA{off, off, off, off})
+		})
+
+		test.That(t, client.Animate(context.Background(), "status", light.Animation{Type: "spin", Period: time.Second}, nil),
+			test.ShouldBeError, `unknown animation type "spin"`)
+	})
+}
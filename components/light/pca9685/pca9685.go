@@ -0,0 +1,222 @@
+// Package pca9685 implements a light of RGB LEDs driven by the PWM channels of a PCA9685 over I2C.
+package pca9685
+
+import (
+	"context"
+	"image/color"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+	"go.viam.com/rdk/components/light"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("pca9685")
+
+const (
+	defaultAddr   = 0x40
+	defaultFreqHz = 1000
+	numChannels   = 16
+	// oscillatorHz is the frequency of the PCA9685's internal oscillator.
+	oscillatorHz = 25000000
+	maxDuty      = 4095
+
+	regMode1    = 0x00
+	regMode2    = 0x01
+	regLED0OnL  = 0x06
+	regPrescale = 0xFE
+
+	mode1Restart = 0x80
+	mode1AutoInc = 0x20
+	mode1Sleep   = 0x10
+	mode2OutDrv  = 0x04
+	// fullBit in the high byte of a channel's on or off time turns the channel fully on or off.
+	fullBit = 0x10
+)
+
+// Pixel is the channels of the red, green and blue parts of an RGB LED.
+type Pixel struct {
+	Red   int `json:"red"`
+	Green int `json:"green"`
+	Blue  int `json:"blue"`
+}
+
+// Config is used for converting config attributes.
+type Config struct {
+	light.Config
+	// I2CBus is the I2C bus the PCA9685 is on, such as "1".
+	I2CBus string `json:"i2c_bus"`
+	// I2CAddr is the address of the PCA9685. It defaults to 0x40.
+	I2CAddr int `json:"i2c_addr,omitempty"`
+	// FreqHz is the PWM frequency, from 24 to 1526. It defaults to 1000.
+	FreqHz int `json:"pwm_freq_hz,omitempty"`
+	// CommonAnode is whether the LEDs are lit by pulling their channels low.
+	CommonAnode bool `json:"common_anode,omitempty"`
+	// Pixels are the LEDs of the light, in order.
+	Pixels []Pixel `json:"pixels"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.I2CBus == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "i2c_bus")
+	}
+	if conf.I2CAddr < 0 || conf.I2CAddr > 0x7f {
+		return nil, resource.NewConfigValidationError(path, errors.New("i2c_addr must be a 7-bit address"))
+	}
+	if conf.FreqHz != 0 && (conf.FreqHz < 24 || conf.FreqHz > 1526) {
+		return nil, resource.NewConfigValidationError(path, errors.New("pwm_freq_hz must be between 24 and 1526"))
+	}
+	if len(conf.Pixels) == 0 {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "pixels")
+	}
+	used := map[int]bool{}
+	for _, pixel := range conf.Pixels {
+		for _, channel := range []int{pixel.Red, pixel.Green, pixel.Blue} {
+			if channel < 0 || channel >= numChannels {
+				return nil, resource.NewConfigValidationError(path, errors.Errorf("channel %d must be from 0 to 15", channel))
+			}
+			if used[channel] {
+				return nil, resource.NewConfigValidationError(path, errors.Errorf("channel %d is used more than once", channel))
+			}
+			used[channel] = true
+		}
+	}
+	return nil, conf.Config.Validate(path, len(conf.Pixels))
+}
+
+type pwmLight struct {
+	resource.Named
+	resource.AlwaysRebuild
+	*light.Strip
+
+	bus         buses.I2C
+	addr        byte
+	pixels      []Pixel
+	commonAnode bool
+
+	mu sync.Mutex
+	// duties are the last duties written, so that only changed channels are written.
+	duties map[int]uint16
+}
+
+func newLight(ctx context.Context, name resource.Name, conf *Config, bus buses.I2C, logger logging.Logger) (light.Light, error) {
+	l := &pwmLight{
+		Named:       name.AsNamed(),
+		bus:         bus,
+		addr:        defaultAddr,
+		pixels:      conf.Pixels,
+		commonAnode: conf.CommonAnode,
+		duties:      map[int]uint16{},
+	}
+	if conf.I2CAddr != 0 {
+		l.addr = byte(conf.I2CAddr)
+	}
+	freqHz := conf.FreqHz
+	if freqHz == 0 {
+		freqHz = defaultFreqHz
+	}
+	if err := l.init(ctx, freqHz); err != nil {
+		return nil, errors.Wrap(err, "initializing PCA9685")
+	}
+	s, err := light.NewStrip(len(conf.Pixels), conf.Segments, l.write, logger)
+	if err != nil {
+		return nil, err
+	}
+	l.Strip = s
+	return l, nil
+}
+
+// init sets the PWM frequency, which can only be changed while the oscillator sleeps, and turns on
+// register auto-increment so a channel's four registers are written at once.
+func (l *pwmLight) init(ctx context.Context, freqHz int) (err error) {
+	handle, err := l.bus.OpenHandle(l.addr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Combine(err, handle.Close())
+	}()
+	if err := handle.WriteByteData(ctx, regMode1, mode1Sleep|mode1AutoInc); err != nil {
+		return err
+	}
+	if err := handle.WriteByteData(ctx, regPrescale, prescale(freqHz)); err != nil {
+		return err
+	}
+	if err := handle.WriteByteData(ctx, regMode1, mode1AutoInc); err != nil {
+		return err
+	}
+	// the oscillator takes up to 500us to start again
+	time.Sleep(500 * time.Microsecond)
+	if err := handle.WriteByteData(ctx, regMode1, mode1Restart|mode1AutoInc); err != nil {
+		return err
+	}
+	return handle.WriteByteData(ctx, regMode2, mode2OutDrv)
+}
+
+func (l *pwmLight) write(ctx context.Context, pixels []color.RGBA) (err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	handle, err := l.bus.OpenHandle(l.addr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Combine(err, handle.Close())
+	}()
+	for i, p := range pixels {
+		for _, part := range []struct {
+			channel int
+			value   uint8
+		}{
+			{l.pixels[i].Red, p.R},
+			{l.pixels[i].Green, p.G},
+			{l.pixels[i].Blue, p.B},
+		} {
+			duty := uint16(math.Round(float64(part.value) * maxDuty / 255))
+			if l.commonAnode {
+				duty = maxDuty - duty
+			}
+			if last, ok := l.duties[part.channel]; ok && last == duty {
+				continue
+			}
+			if err := handle.WriteBlockData(ctx, byte(regLED0OnL+4*part.channel), channelRegisters(duty)); err != nil {
+				return err
+			}
+			l.duties[part.channel] = duty
+		}
+	}
+	return nil
+}
+
+// DoCommand handles the light commands.
+func (l *pwmLight) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, handled, err := light.HandleDoCommand(ctx, l, cmd); handled {
+		return resp, err
+	}
+	return map[string]interface{}{}, nil
+}
+
+// prescale returns the prescaler value giving a PWM frequency of `freqHz`.
+func prescale(freqHz int) byte {
+	return byte(math.Round(oscillatorHz/(4096*float64(freqHz))) - 1)
+}
+
+// channelRegisters returns the on and off times of a channel with a duty cycle of `duty` out of
+// 4095, using the full on and full off bits at the ends so that the LED is steady.
+func channelRegisters(duty uint16) []byte {
+	switch {
+	case duty == 0:
+		return []byte{0, 0, 0, fullBit}
+	case duty >= maxDuty:
+		return []byte{0, fullBit, 0, 0}
+	default:
+		return []byte{0, 0, byte(duty), byte(duty >> 8)}
+	}
+}
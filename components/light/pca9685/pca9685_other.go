@@ -0,0 +1,21 @@
+//go:build !linux
+
+package pca9685
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/light"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func init() {
+	resource.RegisterComponent(light.API, model, resource.Registration[resource.Resource, *Config]{
+		Constructor: func(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (resource.Resource, error) {
+			return nil, errors.New("PCA9685 lights are only supported on linux")
+		},
+	})
+}
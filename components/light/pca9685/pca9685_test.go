@@ -0,0 +1,92 @@
+package pca9685
+
+import (
+	"context"
+	"image/color"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+	"go.viam.com/rdk/components/light"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestChannelRegisters(t *testing.T) {
+	test.That(t, channelRegisters(0), test.ShouldResemble, []byte{0, 0, 0, fullBit})
+	test.That(t, channelRegisters(maxDuty), test.ShouldResemble, []byte{0, fullBit, 0, 0})
+	test.That(t, channelRegisters(0x123), test.ShouldResemble, []byte{0, 0, 0x23, 0x01})
+	test.That(t, prescale(1000), test.ShouldEqual, 5)
+	test.That(t, prescale(200), test.ShouldEqual, 30)
+}
+
+func TestLight(t *testing.T) {
+	var byteWrites [][2]byte
+	blockWrites := map[byte][]byte{}
+	handle := &inject.I2CHandle{
+		WriteByteDataFunc: func(ctx context.Context, register, data byte) error {
+			byteWrites = append(byteWrites, [2]byte{register, data})
+			return nil
+		},
+		WriteBlockDataFunc: func(ctx context.Context, register byte, data []byte) error {
+			blockWrites[register] = data
+			return nil
+		},
+		CloseFunc: func() error { return nil },
+	}
+	bus := &inject.I2C{OpenHandleFunc: func(addr byte) (buses.I2CHandle, error) {
+		test.That(t, addr, test.ShouldEqual, 0x41)
+		return handle, nil
+	}}
+
+	conf := &Config{
+		I2CBus:      "1",
+		I2CAddr:     0x41,
+		FreqHz:      200,
+		CommonAnode: true,
+		Pixels:      []Pixel{{Red: 0, Green: 1, Blue: 2}, {Red: 8, Green: 9, Blue: 10}},
+	}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	l, err := newLight(context.Background(), light.Named("rgb"), conf, bus, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	defer l.Close(context.Background())
+
+	test.That(t, byteWrites, test.ShouldResemble, [][2]byte{
+		{regMode1, mode1Sleep | mode1AutoInc},
+		{regPrescale, 30},
+		{regMode1, mode1AutoInc},
+		{regMode1, mode1Restart | mode1AutoInc},
+		{regMode2, mode2OutDrv},
+	})
+
+	test.That(t, l.SetColor(context.Background(), light.AllPixels, color.RGBA{R: 0xff, G: 0x80}, nil), test.ShouldBeNil)
+	// the LEDs are common anode, so full red is a channel that is fully off
+	test.That(t, blockWrites[regLED0OnL+4*0], test.ShouldResemble, channelRegisters(0))
+	test.That(t, blockWrites[regLED0OnL+4*1], test.ShouldResemble, channelRegisters(maxDuty-2056))
+	test.That(t, blockWrites[regLED0OnL+4*10], test.ShouldResemble, channelRegisters(maxDuty))
+
+	// unchanged channels are not written again
+	blockWrites = map[byte][]byte{}
+	test.That(t, l.SetColor(context.Background(), light.AllPixels, color.RGBA{R: 0xff, G: 0x80, B: 0xff}, nil), test.ShouldBeNil)
+	test.That(t, len(blockWrites), test.ShouldEqual, 2)
+	test.That(t, blockWrites[regLED0OnL+4*2], test.ShouldResemble, channelRegisters(0))
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		conf     Config
+		expected string
+	}{
+		{Config{Pixels: []Pixel{{0, 1, 2}}}, "i2c_bus"},
+		{Config{I2CBus: "1"}, "pixels"},
+		{Config{I2CBus: "1", FreqHz: 2000, Pixels: []Pixel{{0, 1, 2}}}, "pwm_freq_hz must be between 24 and 1526"},
+		{Config{I2CBus: "1", Pixels: []Pixel{{0, 1, 16}}}, "channel 16 must be from 0 to 15"},
+		{Config{I2CBus: "1", Pixels: []Pixel{{0, 1, 2}, {2, 3, 4}}}, "channel 2 is used more than once"},
+	} {
+		_, err := tc.conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.expected)
+	}
+}
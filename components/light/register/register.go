@@ -0,0 +1,9 @@
+// Package register registers all relevant lights.
+package register
+
+import (
+	// register lights.
+	_ "go.viam.com/rdk/components/light/fake"
+	_ "go.viam.com/rdk/components/light/pca9685"
+	_ "go.viam.com/rdk/components/light/ws2812"
+)
@@ -0,0 +1,280 @@
+package light
+
+import (
+	"context"
+	"image/color"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/logging"
+)
+
+// frameInterval is how often the frames of animations are shown.
+const frameInterval = 20 * time.Millisecond
+
+// off is the color of an unlit pixel.
+var off = color.RGBA{A: 0xff}
+
+// WriteFunc shows a frame of pixels, with the brightness already applied, on the light's hardware.
+type WriteFunc func(ctx context.Context, pixels []color.RGBA) error
+
+// A Strip keeps the colors and animations of a light's pixels and shows them on its hardware.
+// Light models embed it to implement SetColor, SetBrightness, Animate and State.
+type Strip struct {
+	write    WriteFunc
+	segments map[string]Segment
+	logger   logging.Logger
+	now      func() time.Time
+
+	mu         sync.Mutex
+	colors     []color.RGBA
+	brightness float64
+	animations map[string]playingAnimation
+	pixels     []color.RGBA
+	wake       chan struct{}
+
+	workers *goutils.StoppableWorkers
+}
+
+type playingAnimation struct {
+	Animation
+	segment Segment
+	start   time.Time
+}
+
+// NewStrip returns a strip of `length` pixels, all off, at full brightness and split into the
+// given segments. Close must be called when the light is closed.
+func NewStrip(length int, segments map[string]Segment, write WriteFunc, logger logging.Logger) (*Strip, error) {
+	if length <= 0 {
+		return nil, errors.New("a light must have at least one pixel")
+	}
+	for name, segment := range segments {
+		if name == AllPixels {
+			return nil, errors.New("segment names must not be empty")
+		}
+		if err := segment.Validate(length); err != nil {
+			return nil, errors.Wrapf(err, "segment %q", name)
+		}
+	}
+	s := &Strip{
+		write:      write,
+		segments:   segments,
+		logger:     logger,
+		now:        time.Now,
+		colors:     make([]color.RGBA, length),
+		brightness: 1,
+		animations: map[string]playingAnimation{},
+		wake:       make(chan struct{}, 1),
+	}
+	for i := range s.colors {
+		s.colors[i] = off
+	}
+	s.pixels = append([]color.RGBA(nil), s.colors...)
+	s.workers = goutils.NewBackgroundStoppableWorkers(s.animate)
+	return s, nil
+}
+
+// SetColor sets every pixel of the segment to a color, stopping any animation playing on them.
+func (s *Strip) SetColor(ctx context.Context, segment string, c color.RGBA, extra map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seg, err := s.segment(segment)
+	if err != nil {
+		return err
+	}
+	s.stopAnimations(seg)
+	for i := seg.Start; i < seg.Start+seg.Count; i++ {
+		s.colors[i] = opaque(c)
+	}
+	return s.show(ctx)
+}
+
+// SetBrightness sets the brightness of the whole light, from 0 to 1.
+func (s *Strip) SetBrightness(ctx context.Context, brightness float64, extra map[string]interface{}) error {
+	if err := validateBrightness(brightness); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.brightness = brightness
+	return s.show(ctx)
+}
+
+// Animate plays an animation on the segment until it is colored or animated again.
+func (s *Strip) Animate(ctx context.Context, segment string, animation Animation, extra map[string]interface{}) error {
+	if err := animation.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seg, err := s.segment(segment)
+	if err != nil {
+		return err
+	}
+	s.stopAnimations(seg)
+	animation.Color = opaque(animation.Color)
+	s.animations[segment] = playingAnimation{Animation: animation, segment: seg, start: s.now()}
+	if err := s.show(ctx); err != nil {
+		return err
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// State returns what the light is showing.
+func (s *Strip) State(ctx context.Context, extra map[string]interface{}) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := State{
+		Brightness: s.brightness,
+		Pixels:     append([]color.RGBA(nil), s.pixels...),
+		Segments:   make(map[string]Segment, len(s.segments)),
+		Animations: make(map[string]Animation, len(s.animations)),
+	}
+	for name, segment := range s.segments {
+		state.Segments[name] = segment
+	}
+	for name, animation := range s.animations {
+		state.Animations[name] = animation.Animation
+	}
+	return state, nil
+}
+
+// Close stops the animations and turns the light off.
+func (s *Strip) Close(ctx context.Context) error {
+	s.workers.Stop()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.animations = map[string]playingAnimation{}
+	for i := range s.colors {
+		s.colors[i] = off
+	}
+	return s.show(ctx)
+}
+
+func (s *Strip) animate(ctx context.Context) {
+	ticker := time.NewTicker(frameInterval)
+	defer ticker.Stop()
+	for {
+		s.mu.Lock()
+		animating := len(s.animations) > 0
+		s.mu.Unlock()
+		if !animating {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.wake:
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.mu.Lock()
+		if err := s.show(ctx); err != nil && ctx.Err() == nil {
+			s.logger.CWarnw(ctx, "error showing light animation, stopping the animations", "error", err)
+			s.animations = map[string]playingAnimation{}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// segment returns the named segment. It must be called with s.mu held.
+func (s *Strip) segment(name string) (Segment, error) {
+	if name == AllPixels {
+		return Segment{Count: len(s.colors)}, nil
+	}
+	segment, ok := s.segments[name]
+	if !ok {
+		return Segment{}, errors.Errorf("no segment named %q", name)
+	}
+	return segment, nil
+}
+
+// stopAnimations stops the animations overlapping the segment. It must be called with s.mu held.
+func (s *Strip) stopAnimations(segment Segment) {
+	for name, animation := range s.animations {
+		if animation.segment.overlaps(segment) {
+			delete(s.animations, name)
+		}
+	}
+}
+
+// show renders the current frame and writes it to the hardware. It must be called with s.mu held.
+func (s *Strip) show(ctx context.Context) error {
+	s.pixels = s.render(s.now())
+	frame := make([]color.RGBA, len(s.pixels))
+	for i, c := range s.pixels {
+		frame[i] = scale(c, s.brightness)
+	}
+	return s.write(ctx, frame)
+}
+
+// render returns the colors of the pixels at `at`. It must be called with s.mu held.
+func (s *Strip) render(at time.Time) []color.RGBA {
+	pixels := append([]color.RGBA(nil), s.colors...)
+	for _, animation := range s.animations {
+		phase := math.Mod(float64(at.Sub(animation.start)), float64(animation.Period)) / float64(animation.Period)
+		seg := animation.segment
+		for i := 0; i < seg.Count; i++ {
+			pixels[seg.Start+i] = animation.pixel(phase, i, seg.Count)
+		}
+	}
+	return pixels
+}
+
+// pixel returns the color of the i-th of `n` pixels `phase` of the way through a period.
+func (a Animation) pixel(phase float64, i, n int) color.RGBA {
+	switch a.Type {
+	case AnimationBlink:
+		if phase < 0.5 {
+			return a.Color
+		}
+		return off
+	case AnimationBreathe:
+		return scale(a.Color, (1-math.Cos(2*math.Pi*phase))/2)
+	case AnimationRainbow:
+		hue := math.Mod(phase+float64(i)/float64(n), 1)
+		r, g, b := colorful.Hsv(hue*360, 1, 1).RGB255()
+		return color.RGBA{R: r, G: g, B: b, A: 0xff}
+	case AnimationChase:
+		if i == int(phase*float64(n)) {
+			return a.Color
+		}
+		return off
+	default:
+		return off
+	}
+}
+
+func scale(c color.RGBA, by float64) color.RGBA {
+	return color.RGBA{
+		R: uint8(math.Round(float64(c.R) * by)),
+		G: uint8(math.Round(float64(c.G) * by)),
+		B: uint8(math.Round(float64(c.B) * by)),
+		A: c.A,
+	}
+}
+
+// opaque returns the color with its alpha, which lights ignore, set to opaque.
+func opaque(c color.RGBA) color.RGBA {
+	c.A = 0xff
+	return c
+}
+
+func validateBrightness(brightness float64) error {
+	if brightness < 0 || brightness > 1 {
+		return errors.New("brightness must be between 0 and 1")
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package light
+
+import (
+	"context"
+	"image/color"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestStrip(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	var frame []color.RGBA
+	s, err := NewStrip(6, map[string]Segment{
+		"left":  {Start: 0, Count: 3},
+		"right": {Start: 3, Count: 3},
+	}, func(ctx context.Context, pixels []color.RGBA) error {
+		frame = pixels
+		return nil
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	// stop animating in the background so that frames are only rendered by the test
+	s.workers.Stop()
+	start := time.Unix(100, 0)
+	s.now = func() time.Time { return start }
+
+	red := color.RGBA{R: 200, A: 0xff}
+	blue := color.RGBA{B: 100, A: 0xff}
+
+	t.Run("color and brightness", func(t *testing.T) {
+		test.That(t, s.SetColor(context.Background(), AllPixels, red, nil), test.ShouldBeNil)
+		test.That(t, s.SetColor(context.Background(), "right", blue, nil), test.ShouldBeNil)
+		test.That(t, frame, test.ShouldResemble, []color.RGBA{red, red, red, blue, blue, blue})
+
+		test.That(t, s.SetBrightness(context.Background(), 0.5, nil), test.ShouldBeNil)
+		dimRed, dimBlue := color.RGBA{R: 100, A: 0xff}, color.RGBA{B: 50, A: 0xff}
+		test.That(t, frame, test.ShouldResemble, []color.RGBA{dimRed, dimRed, dimRed, dimBlue, dimBlue, dimBlue})
+
+		state, err := s.State(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, state.Brightness, test.ShouldEqual, 0.5)
+		test.That(t, state.Pixels, test.ShouldResemble, []color.RGBA{red, red, red, blue, blue, blue})
+		test.That(t, state.Segments["right"], test.ShouldResemble, Segment{Start: 3, Count: 3})
+
+		test.That(t, s.SetBrightness(context.Background(), 1.5, nil), test.ShouldBeError, "brightness must be between 0 and 1")
+		test.That(t, s.SetColor(context.Background(), "middle", red, nil), test.ShouldBeError, `no segment named "middle"`)
+		test.That(t, s.SetBrightness(context.Background(), 1, nil), test.ShouldBeNil)
+	})
+
+	t.Run("animations", func(t *testing.T) {
+		period := time.Second
+		test.That(t, s.Animate(context.Background(), "left", Animation{Type: AnimationBlink, Color: blue, Period: period}, nil),
+			test.ShouldBeNil)
+		test.That(t, s.Animate(context.Background(), "right", Animation{Type: AnimationChase, Color: red, Period: period}, nil),
+			test.ShouldBeNil)
+		test.That(t, s.render(start.Add(100*time.Millisecond)), test.ShouldResemble, []color.RGBA{blue, blue, blue, red, off, off})
+		test.That(t, s.render(start.Add(600*time.Millisecond)), test.ShouldResemble, []color.RGBA{off, off, off, off, red, off})
+		test.That(t, s.render(start.Add(2900*time.Millisecond)), test.ShouldResemble, []color.RGBA{off, off, off, off, off, red})
+
+		// coloring the whole strip stops both animations
+		test.That(t, s.SetColor(context.Background(), AllPixels, red, nil), test.ShouldBeNil)
+		state, err := s.State(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, state.Animations, test.ShouldBeEmpty)
+
+		test.That(t, s.Animate(context.Background(), AllPixels, Animation{Type: AnimationBreathe, Color: red, Period: period}, nil),
+			test.ShouldBeNil)
+		test.That(t, s.render(start)[0], test.ShouldResemble, off)
+		test.That(t, s.render(start.Add(500 * time.Millisecond))[0], test.ShouldResemble, red)
+		test.That(t, s.render(start.Add(250 * time.Millisecond))[0], test.ShouldResemble, color.RGBA{R: 100, A: 0xff})
+
+		test.That(t, s.Animate(context.Background(), AllPixels, Animation{Type: AnimationRainbow, Period: period}, nil),
+			test.ShouldBeNil)
+		pixels := s.render(start)
+		test.That(t, pixels[0], test.ShouldResemble, color.RGBA{R: 255, A: 0xff})
+		test.That(t, pixels[2], test.ShouldResemble, color.RGBA{G: 255, A: 0xff})
+		test.That(t, pixels[4], test.ShouldResemble, color.RGBA{B: 255, A: 0xff})
+	})
+
+	t.Run("invalid animation", func(t *testing.T) {
+		test.That(t, s.Animate(context.Background(), "left", Animation{Type: "spin", Period: time.Second}, nil),
+			test.ShouldBeError, `unknown animation type "spin"`)
+		test.That(t, s.Animate(context.Background(), "left", Animation{Type: AnimationBlink}, nil),
+			test.ShouldBeError, "animation period must be positive")
+	})
+
+	test.That(t, s.Close(context.Background()), test.ShouldBeNil)
+	test.That(t, frame, test.ShouldResemble, []color.RGBA{off, off, off, off, off, off})
+}
+
+func TestNewStrip(t *testing.T) {
+	write := func(ctx context.Context, pixels []color.RGBA) error { return nil }
+	_, err := NewStrip(0, nil, write, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeError, "a light must have at least one pixel")
+	_, err = NewStrip(4, map[string]Segment{"tail": {Start: 2, Count: 3}}, write, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeError, `segment "tail": segment of 3 pixels from pixel 2 does not fit 4 pixels`)
+}
+
+func TestHex(t *testing.T) {
+	c, err := ParseHex("#ff8000")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, c, test.ShouldResemble, color.RGBA{R: 0xff, G: 0x80, A: 0xff})
+	test.That(t, Hex(c), test.ShouldEqual, "#ff8000")
+	_, err = ParseHex("orange")
+	test.That(t, err, test.ShouldBeError, `color "orange" must be in the #rrggbb form`)
+}
@@ -0,0 +1,117 @@
+// Package ws2812 implements a strip of WS2812 (NeoPixel) addressable LEDs driven over an SPI bus.
+//
+// The WS2812 data line is driven by the SPI bus's MOSI pin. Each bit of pixel data is sent as three
+// SPI bits at 2.4MHz, 110 for a one and 100 for a zero, which gives the pulse widths the LEDs
+// expect without bit-banging.
+package ws2812
+
+import (
+	"context"
+	"image/color"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+	"go.viam.com/rdk/components/light"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("ws2812")
+
+const (
+	// baud is the SPI clock rate at which three SPI bits take one 1.25us WS2812 bit.
+	baud = 2400000
+	// bytesPerPixel is the SPI bytes of the 24 bits of a pixel.
+	bytesPerPixel = 9
+	// resetBytes hold the data line low for 300us after the pixels, which latches them.
+	resetBytes = 90
+	// maxLength keeps a frame within the 4096 byte transfers spidev allows by default.
+	maxLength = (4096 - resetBytes) / bytesPerPixel
+)
+
+// Config is used for converting config attributes.
+type Config struct {
+	light.Config
+	// SPIBus is the SPI bus the strip's data line is on, such as "0".
+	SPIBus string `json:"spi_bus"`
+	// ChipSelect is the chip select line of the bus to use. It defaults to "0". The strip does not
+	// use it, but the bus still drives it.
+	ChipSelect string `json:"chip_select,omitempty"`
+	// Length is the number of LEDs in the strip.
+	Length int `json:"length"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.SPIBus == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "spi_bus")
+	}
+	if conf.Length <= 0 {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "length")
+	}
+	if conf.Length > maxLength {
+		return nil, resource.NewConfigValidationError(path, errors.Errorf("length must be at most %d", maxLength))
+	}
+	return nil, conf.Config.Validate(path, conf.Length)
+}
+
+type strip struct {
+	resource.Named
+	resource.AlwaysRebuild
+	*light.Strip
+}
+
+func newStrip(name resource.Name, conf *Config, bus buses.SPI, logger logging.Logger) (light.Light, error) {
+	chipSelect := conf.ChipSelect
+	if chipSelect == "" {
+		chipSelect = "0"
+	}
+	s, err := light.NewStrip(conf.Length, conf.Segments, func(ctx context.Context, pixels []color.RGBA) error {
+		handle, err := bus.OpenHandle()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := handle.Close(); err != nil {
+				logger.Debugw("error closing SPI handle", "error", err)
+			}
+		}()
+		_, err = handle.Xfer(ctx, baud, chipSelect, 0, encode(pixels))
+		return err
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &strip{Named: name.AsNamed(), Strip: s}, nil
+}
+
+// DoCommand handles the light commands.
+func (s *strip) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, handled, err := light.HandleDoCommand(ctx, s, cmd); handled {
+		return resp, err
+	}
+	return map[string]interface{}{}, nil
+}
+
+// encode returns the SPI bytes that show the pixels, in the green, red, blue order the LEDs take
+// them, followed by the reset.
+func encode(pixels []color.RGBA) []byte {
+	data := make([]byte, 0, len(pixels)*bytesPerPixel+resetBytes)
+	for _, p := range pixels {
+		for _, c := range []uint8{p.G, p.R, p.B} {
+			// each of the 8 bits becomes 3, making 24 bits
+			var bits uint32
+			for i := 7; i >= 0; i-- {
+				bits <<= 3
+				if c&(1<<i) != 0 {
+					bits |= 0b110
+				} else {
+					bits |= 0b100
+				}
+			}
+			data = append(data, byte(bits>>16), byte(bits>>8), byte(bits))
+		}
+	}
+	return append(data, make([]byte, resetBytes)...)
+}
@@ -0,0 +1,66 @@
+package ws2812
+
+import (
+	"context"
+	"image/color"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+	"go.viam.com/rdk/components/light"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestEncode(t *testing.T) {
+	data := encode([]color.RGBA{{R: 0xff, G: 0x00, B: 0x81}})
+	test.That(t, len(data), test.ShouldEqual, bytesPerPixel+resetBytes)
+	// green 0x00 is eight 100s, red 0xff is eight 110s, blue 0x81 is 110, six 100s and 110
+	test.That(t, data[:bytesPerPixel], test.ShouldResemble, []byte{
+		0x92, 0x49, 0x24,
+		0xdb, 0x6d, 0xb6,
+		0xd2, 0x49, 0x26,
+	})
+	test.That(t, data[bytesPerPixel:], test.ShouldResemble, make([]byte, resetBytes))
+}
+
+func TestStrip(t *testing.T) {
+	var sent []byte
+	handle := &inject.SPIHandle{
+		XferFunc: func(ctx context.Context, rate uint, chipSelect string, mode uint, tx []byte) ([]byte, error) {
+			test.That(t, rate, test.ShouldEqual, baud)
+			test.That(t, chipSelect, test.ShouldEqual, "0")
+			sent = tx
+			return make([]byte, len(tx)), nil
+		},
+		CloseFunc: func() error { return nil },
+	}
+	bus := &inject.SPI{OpenHandleFunc: func() (buses.SPIHandle, error) { return handle, nil }}
+
+	conf := &Config{SPIBus: "0", Length: 2}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	s, err := newStrip(light.Named("strip"), conf, bus, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	defer s.Close(context.Background())
+
+	test.That(t, s.SetColor(context.Background(), light.AllPixels, color.RGBA{R: 0xff, B: 0x81}, nil), test.ShouldBeNil)
+	test.That(t, sent, test.ShouldResemble, encode([]color.RGBA{{R: 0xff, B: 0x81}, {R: 0xff, B: 0x81}}))
+}
+
+func TestValidate(t *testing.T) {
+	_, err := (&Config{Length: 2}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "spi_bus")
+	_, err = (&Config{SPIBus: "0", Length: maxLength + 1}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "length must be at most 445")
+	_, err = (&Config{
+		Config: light.Config{Segments: map[string]light.Segment{"tail": {Start: 1, Count: 2}}},
+		SPIBus: "0",
+		Length: 2,
+	}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `segment "tail"`)
+}
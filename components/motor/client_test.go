@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
 	"go.viam.com/test"
 	"go.viam.com/utils/rpc"
@@ -284,3 +285,107 @@ func TestClient(t *testing.T) {
 	})
 	test.That(t, conn.Close(), test.ShouldBeNil)
 }
+
+func TestClientCurrent(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	var (
+		capAmps      float64
+		extraOptions map[string]interface{}
+	)
+	injectMotor := &inject.Motor{}
+	injectMotor.SetCurrentFunc = func(ctx context.Context, amps float64, extra map[string]interface{}) error {
+		capAmps = amps
+		extraOptions = extra
+		return nil
+	}
+	injectMotor.CurrentFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		extraOptions = extra
+		return 1.25, nil
+	}
+	injectMotor.DoFunc = testutils.EchoFunc
+
+	motorSvc, err := resource.NewAPIResourceCollection(motor.API, map[resource.Name]motor.Motor{
+		motor.Named(testMotorName): injectMotor,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[motor.Motor](motor.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, motorSvc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	motorClient, err := motor.NewClientFromConn(context.Background(), conn, "", motor.Named(testMotorName), logger)
+	test.That(t, err, test.ShouldBeNil)
+	currentMotor, ok := motorClient.(motor.CurrentController)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	err = currentMotor.SetCurrent(context.Background(), -0.75, map[string]interface{}{"foo": "SetCurrent"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, capAmps, test.ShouldEqual, -0.75)
+	test.That(t, extraOptions, test.ShouldResemble, map[string]interface{}{"foo": "SetCurrent"})
+
+	amps, err := currentMotor.Current(context.Background(), map[string]interface{}{"foo": "Current"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, amps, test.ShouldEqual, 1.25)
+	test.That(t, extraOptions, test.ShouldResemble, map[string]interface{}{"foo": "Current"})
+
+	// other commands still reach the motor's DoCommand
+	resp, err := motorClient.DoCommand(context.Background(), testutils.TestCommand)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["command"], test.ShouldEqual, testutils.TestCommand["command"])
+}
+
+func TestClientFaults(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	faultTime := time.Unix(1700000000, 123456000)
+	var capSince time.Time
+	injectMotor := &inject.Motor{}
+	injectMotor.FaultsFunc = func(ctx context.Context, since time.Time, extra map[string]interface{}) ([]motor.Fault, error) {
+		capSince = since
+		return []motor.Fault{{Type: motor.FaultStall, Time: faultTime, Message: "stuck"}}, nil
+	}
+
+	motorSvc, err := resource.NewAPIResourceCollection(motor.API, map[resource.Name]motor.Motor{
+		motor.Named(testMotorName): injectMotor,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[motor.Motor](motor.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, motorSvc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	motorClient, err := motor.NewClientFromConn(context.Background(), conn, "", motor.Named(testMotorName), logger)
+	test.That(t, err, test.ShouldBeNil)
+	reporter, ok := motorClient.(motor.FaultReporter)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	since := time.Unix(1600000000, 0)
+	faults, err := reporter.Faults(context.Background(), since, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, capSince.Equal(since), test.ShouldBeTrue)
+	test.That(t, faults, test.ShouldHaveLength, 1)
+	test.That(t, faults[0].Type, test.ShouldEqual, motor.FaultStall)
+	test.That(t, faults[0].Time.Equal(faultTime), test.ShouldBeTrue)
+	test.That(t, faults[0].Message, test.ShouldEqual, "stuck")
+}
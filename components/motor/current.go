@@ -0,0 +1,120 @@
+package motor
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+
+	"go.viam.com/rdk/operation"
+)
+
+// The motor proto has no current RPCs. Current commands are carried over DoCommand using the
+// following reserved keys. The server dispatches them to motors implementing CurrentController and
+// passes them through to the motor's own DoCommand otherwise.
+const (
+	setCurrentKey = "set_current"
+	getCurrentKey = "get_current"
+	ampsKey       = "amps"
+	extraKey      = "extra"
+)
+
+// CurrentController is implemented by motors whose drivers can regulate and measure winding
+// current. Since a motor's torque is proportional to its current, this allows force-sensitive
+// applications such as grippers and compliant joints to be built on standard motor models.
+//
+// SetCurrent example:
+//
+//	myMotor, err := motor.FromRobot(machine, "my_motor")
+//	if currentMotor, ok := myMotor.(motor.CurrentController); ok {
+//		// Hold 0.5 amps, which pushes with a constant torque until stopped.
+//		err = currentMotor.SetCurrent(context.Background(), 0.5, nil)
+//
+//		// Read the measured current.
+//		amps, err := currentMotor.Current(context.Background(), nil)
+//	}
+type CurrentController interface {
+	// SetCurrent commands the motor to hold the given current in amps, with the sign giving the
+	// direction. The motor keeps regulating current until another command is given or Stop is
+	// called.
+	SetCurrent(ctx context.Context, amps float64, extra map[string]interface{}) error
+
+	// Current returns the measured current through the motor in amps.
+	Current(ctx context.Context, extra map[string]interface{}) (float64, error)
+}
+
+func (c *client) SetCurrent(ctx context.Context, amps float64, extra map[string]interface{}) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		setCurrentKey: map[string]interface{}{
+			ampsKey:  amps,
+			extraKey: extra,
+		},
+	})
+	return err
+}
+
+func (c *client) Current(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		getCurrentKey: map[string]interface{}{
+			extraKey: extra,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	amps, ok := resp[ampsKey].(float64)
+	if !ok {
+		return 0, errors.Errorf("expected %q in response, got %v", ampsKey, resp)
+	}
+	return amps, nil
+}
+
+// doCurrentCommand handles the reserved current DoCommand keys. It returns false if `req` is not a
+// current command or the motor does not implement CurrentController.
+func doCurrentCommand(
+	ctx context.Context,
+	m Motor,
+	name string,
+	req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	currentMotor, ok := m.(CurrentController)
+	if !ok {
+		return nil, false, nil
+	}
+	cmd := req.GetCommand().AsMap()
+	if payload, ok := cmd[setCurrentKey]; ok {
+		operation.CancelOtherWithLabel(ctx, name)
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", setCurrentKey)
+		}
+		amps, ok := args[ampsKey].(float64)
+		if !ok {
+			return nil, true, errors.Errorf("%q must be a number", ampsKey)
+		}
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		if err := currentMotor.SetCurrent(ctx, amps, extra); err != nil {
+			return nil, true, err
+		}
+		return doCommandResponse(map[string]interface{}{})
+	}
+	if payload, ok := cmd[getCurrentKey]; ok {
+		args, _ := payload.(map[string]interface{})         //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		amps, err := currentMotor.Current(ctx, extra)
+		if err != nil {
+			return nil, true, err
+		}
+		return doCommandResponse(map[string]interface{}{ampsKey: amps})
+	}
+	return nil, false, nil
+}
+
+func doCommandResponse(result map[string]interface{}) (*commonpb.DoCommandResponse, bool, error) {
+	res, err := protoutils.StructToStructPb(result)
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
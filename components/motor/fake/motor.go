@@ -32,7 +32,8 @@ var (
 )
 
 const (
-	defaultMaxRpm = 100
+	defaultMaxRpm         = 100
+	defaultMaxCurrentAmps = 2.0
 )
 
 // PinConfig defines the mapping of where motor are wired.
@@ -52,6 +53,7 @@ type Config struct {
 	MaxRPM           float64   `json:"max_rpm,omitempty"`
 	TicksPerRotation int       `json:"ticks_per_rotation,omitempty"`
 	DirectionFlip    bool      `json:"direction_flip,omitempty"`
+	MaxCurrentAmps   float64   `json:"max_current_amps,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -60,6 +62,9 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 	if cfg.BoardName != "" {
 		deps = append(deps, cfg.BoardName)
 	}
+	if cfg.MaxCurrentAmps < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("max_current_amps cannot be negative"))
+	}
 	if cfg.Encoder != "" {
 		if cfg.TicksPerRotation <= 0 {
 			return nil, resource.NewConfigValidationError(path, errors.New("need nonzero TicksPerRotation for encoded motor"))
@@ -88,6 +93,7 @@ type Motor struct {
 	PositionReporting bool
 	Encoder           fake.Encoder
 	MaxRPM            float64
+	MaxCurrentAmps    float64
 	DirFlip           bool
 	TicksPerRotation  int
 
@@ -152,6 +158,11 @@ func (m *Motor) Reconfigure(ctx context.Context, deps resource.Dependencies, con
 		m.MaxRPM = defaultMaxRpm
 	}
 
+	m.MaxCurrentAmps = newConf.MaxCurrentAmps
+	if m.MaxCurrentAmps == 0 {
+		m.MaxCurrentAmps = defaultMaxCurrentAmps
+	}
+
 	if newConf.Encoder != "" {
 		m.TicksPerRotation = newConf.TicksPerRotation
 
@@ -431,3 +442,22 @@ func (m *Motor) IsMoving(ctx context.Context) (bool, error) {
 	defer m.mu.Unlock()
 	return math.Abs(m.powerPct) >= 0.005, nil
 }
+
+// SetCurrent pretends to regulate the given current by setting the power in proportion to the
+// motor's max current.
+func (m *Motor) SetCurrent(ctx context.Context, amps float64, extra map[string]interface{}) error {
+	m.mu.Lock()
+	maxCurrent := m.MaxCurrentAmps
+	m.mu.Unlock()
+	if math.Abs(amps) > maxCurrent {
+		return errors.Errorf("current %.2fA exceeds the max current of %.2fA", amps, maxCurrent)
+	}
+	return m.SetPower(ctx, amps/maxCurrent, extra)
+}
+
+// Current returns a current proportional to the motor's power.
+func (m *Motor) Current(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.powerPct * m.MaxCurrentAmps, nil
+}
@@ -223,6 +223,36 @@ func TestPower(t *testing.T) {
 	test.That(t, powerPct, test.ShouldEqual, 0.0)
 }
 
+func TestCurrent(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	m := &Motor{
+		Logger:         logger,
+		MaxRPM:         60,
+		MaxCurrentAmps: 2,
+		OpMgr:          operation.NewSingleOperationManager(),
+	}
+	var _ motor.CurrentController = m
+
+	err := m.SetCurrent(ctx, -1, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, m.PowerPct(), test.ShouldEqual, -0.5)
+
+	amps, err := m.Current(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, amps, test.ShouldEqual, -1)
+
+	err = m.SetCurrent(ctx, 3, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "exceeds the max current")
+
+	test.That(t, m.Stop(ctx, nil), test.ShouldBeNil)
+	amps, err = m.Current(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, amps, test.ShouldEqual, 0)
+}
+
 func TestGoForMath(t *testing.T) {
 	maxRPM := 100.0
 
@@ -0,0 +1,277 @@
+package motor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The motor proto has no fault RPCs. Faults are carried over DoCommand using the following
+// reserved keys.
+const (
+	getFaultsKey      = "get_faults"
+	sinceUnixNanosKey = "since_unix_nanos"
+	faultsKey         = "faults"
+	faultTypeKey      = "type"
+	faultTimeKey      = "time_unix_nanos"
+	faultMessageKey   = "message"
+)
+
+const (
+	defaultStallMinPowerPct = 0.1
+	// maxRecentFaults is the number of faults a FaultDetector remembers.
+	maxRecentFaults = 100
+)
+
+// FaultType is the kind of a motor fault.
+type FaultType string
+
+// The faults a motor can report.
+const (
+	// FaultStall is reported when the motor is powered but its position does not change.
+	FaultStall = FaultType("stall")
+	// FaultOvercurrent is reported when the measured current exceeds its configured limit.
+	FaultOvercurrent = FaultType("overcurrent")
+)
+
+// Fault is an abnormal condition detected by a motor.
+type Fault struct {
+	Type    FaultType
+	Time    time.Time
+	Message string
+}
+
+// FaultReporter is implemented by motors that can detect faults, such as a stall detected from
+// encoder feedback or an overcurrent detected from current feedback, so jams can be handled
+// programmatically.
+//
+// Faults example:
+//
+//	myMotor, err := motor.FromRobot(machine, "my_motor")
+//	if reporter, ok := myMotor.(motor.FaultReporter); ok {
+//		faults, err := reporter.Faults(context.Background(), time.Now().Add(-time.Minute), nil)
+//		for _, fault := range faults {
+//			logger.Warnf("motor %s at %v: %s", fault.Type, fault.Time, fault.Message)
+//		}
+//	}
+type FaultReporter interface {
+	// Faults returns the faults detected after `since`, oldest first. Only recent faults are kept.
+	Faults(ctx context.Context, since time.Time, extra map[string]interface{}) ([]Fault, error)
+}
+
+// FaultDetectionConfig configures fault detection for motors with position or current feedback.
+type FaultDetectionConfig struct {
+	// StallTimeoutSecs is how long the motor may be powered without its position changing before a
+	// stall is reported. Zero disables stall detection.
+	StallTimeoutSecs float64 `json:"stall_timeout_secs,omitempty"`
+	// StallMinPowerPct is the power below which the motor is not expected to move. Defaults to 0.1.
+	StallMinPowerPct float64 `json:"stall_min_power_pct,omitempty"`
+	// OvercurrentAmps is the current above which an overcurrent is reported. Zero disables
+	// overcurrent detection.
+	OvercurrentAmps float64 `json:"overcurrent_amps,omitempty"`
+	// StopOnFault stops the motor when a fault is detected.
+	StopOnFault bool `json:"stop_on_fault,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *FaultDetectionConfig) Validate(path string) error {
+	if conf.StallTimeoutSecs < 0 || conf.StallMinPowerPct < 0 || conf.OvercurrentAmps < 0 {
+		return resource.NewConfigValidationError(path, errors.New("fault detection values cannot be negative"))
+	}
+	if conf.StallMinPowerPct > 1 {
+		return resource.NewConfigValidationError(path, errors.New("stall_min_power_pct must be at most 1"))
+	}
+	return nil
+}
+
+// FaultSample is a reading of a motor's state used to detect faults.
+type FaultSample struct {
+	Time        time.Time
+	PowerPct    float64
+	Position    float64
+	HasPosition bool
+	CurrentAmps float64
+	HasCurrent  bool
+}
+
+// FaultStats are the fault counters of a FaultDetector, suitable for FTDC.
+type FaultStats struct {
+	StallCount       int
+	OvercurrentCount int
+	// Stalled and Overcurrent are 1 while the condition persists and 0 otherwise.
+	Stalled     int
+	Overcurrent int
+}
+
+// FaultDetector detects stalls and overcurrents from periodic samples of a motor's state. A fault
+// is reported once when its condition starts, and again only after the condition has cleared.
+type FaultDetector struct {
+	conf FaultDetectionConfig
+
+	mu               sync.Mutex
+	lastPosition     float64
+	hasLastPosition  bool
+	stuckSince       time.Time
+	stalled          bool
+	overcurrent      bool
+	stallCount       int
+	overcurrentCount int
+	recent           []Fault
+}
+
+// NewFaultDetector returns a FaultDetector for the given config.
+func NewFaultDetector(conf FaultDetectionConfig) *FaultDetector {
+	if conf.StallMinPowerPct == 0 {
+		conf.StallMinPowerPct = defaultStallMinPowerPct
+	}
+	return &FaultDetector{conf: conf}
+}
+
+// Config returns the detector's config, with defaults applied.
+func (d *FaultDetector) Config() FaultDetectionConfig {
+	return d.conf
+}
+
+// Update feeds a sample to the detector and returns the faults it newly detected.
+func (d *FaultDetector) Update(sample FaultSample) []Fault {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var detected []Fault
+	if d.conf.StallTimeoutSecs > 0 && sample.HasPosition {
+		moved := !d.hasLastPosition || sample.Position != d.lastPosition
+		powered := math.Abs(sample.PowerPct) >= d.conf.StallMinPowerPct
+		switch {
+		case !powered || moved:
+			d.stuckSince = time.Time{}
+			d.stalled = false
+		case d.stuckSince.IsZero():
+			d.stuckSince = sample.Time
+		case !d.stalled && sample.Time.Sub(d.stuckSince).Seconds() >= d.conf.StallTimeoutSecs:
+			d.stalled = true
+			d.stallCount++
+			detected = append(detected, Fault{
+				Type: FaultStall,
+				Time: sample.Time,
+				Message: fmt.Sprintf("position stuck at %.3f for %v at %.0f%% power",
+					sample.Position, sample.Time.Sub(d.stuckSince), sample.PowerPct*100),
+			})
+		}
+		d.lastPosition = sample.Position
+		d.hasLastPosition = true
+	}
+	if d.conf.OvercurrentAmps > 0 && sample.HasCurrent {
+		over := math.Abs(sample.CurrentAmps) > d.conf.OvercurrentAmps
+		if over && !d.overcurrent {
+			d.overcurrentCount++
+			detected = append(detected, Fault{
+				Type: FaultOvercurrent,
+				Time: sample.Time,
+				Message: fmt.Sprintf("current %.2fA exceeds limit of %.2fA",
+					sample.CurrentAmps, d.conf.OvercurrentAmps),
+			})
+		}
+		d.overcurrent = over
+	}
+
+	d.recent = append(d.recent, detected...)
+	if len(d.recent) > maxRecentFaults {
+		d.recent = d.recent[len(d.recent)-maxRecentFaults:]
+	}
+	return detected
+}
+
+// Faults returns the remembered faults detected after `since`, oldest first.
+func (d *FaultDetector) Faults(since time.Time) []Fault {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var faults []Fault
+	for _, fault := range d.recent {
+		if fault.Time.After(since) {
+			faults = append(faults, fault)
+		}
+	}
+	return faults
+}
+
+// Stats returns the detector's fault counters.
+func (d *FaultDetector) Stats() FaultStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	stats := FaultStats{StallCount: d.stallCount, OvercurrentCount: d.overcurrentCount}
+	if d.stalled {
+		stats.Stalled = 1
+	}
+	if d.overcurrent {
+		stats.Overcurrent = 1
+	}
+	return stats
+}
+
+func (c *client) Faults(ctx context.Context, since time.Time, extra map[string]interface{}) ([]Fault, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		getFaultsKey: map[string]interface{}{
+			sinceUnixNanosKey: float64(since.UnixNano()),
+			extraKey:          extra,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	rawFaults, ok := resp[faultsKey].([]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected %q in response, got %v", faultsKey, resp)
+	}
+	faults := make([]Fault, 0, len(rawFaults))
+	for _, rawFault := range rawFaults {
+		fault, ok := rawFault.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%q entries must be objects", faultsKey)
+		}
+		faultType, _ := fault[faultTypeKey].(string)  //nolint:errcheck
+		nanos, _ := fault[faultTimeKey].(float64)     //nolint:errcheck
+		message, _ := fault[faultMessageKey].(string) //nolint:errcheck
+		faults = append(faults, Fault{
+			Type:    FaultType(faultType),
+			Time:    time.Unix(0, int64(nanos)),
+			Message: message,
+		})
+	}
+	return faults, nil
+}
+
+// doFaultsCommand handles the reserved fault DoCommand key. It returns false if `req` is not a
+// fault command or the motor does not implement FaultReporter.
+func doFaultsCommand(ctx context.Context, m Motor, req *commonpb.DoCommandRequest) (*commonpb.DoCommandResponse, bool, error) {
+	reporter, ok := m.(FaultReporter)
+	if !ok {
+		return nil, false, nil
+	}
+	payload, ok := req.GetCommand().AsMap()[getFaultsKey]
+	if !ok {
+		return nil, false, nil
+	}
+	args, _ := payload.(map[string]interface{})         //nolint:errcheck
+	nanos, _ := args[sinceUnixNanosKey].(float64)       //nolint:errcheck
+	extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+	faults, err := reporter.Faults(ctx, time.Unix(0, int64(nanos)), extra)
+	if err != nil {
+		return nil, true, err
+	}
+	encoded := make([]interface{}, 0, len(faults))
+	for _, fault := range faults {
+		encoded = append(encoded, map[string]interface{}{
+			faultTypeKey:    string(fault.Type),
+			faultTimeKey:    float64(fault.Time.UnixNano()),
+			faultMessageKey: fault.Message,
+		})
+	}
+	return doCommandResponse(map[string]interface{}{faultsKey: encoded})
+}
@@ -0,0 +1,63 @@
+package motor_test
+
+import (
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/motor"
+)
+
+func TestFaultDetector(t *testing.T) {
+	start := time.Now()
+	at := func(secs float64) time.Time {
+		return start.Add(time.Duration(secs * float64(time.Second)))
+	}
+
+	t.Run("stall", func(t *testing.T) {
+		d := motor.NewFaultDetector(motor.FaultDetectionConfig{StallTimeoutSecs: 1})
+		test.That(t, d.Update(motor.FaultSample{Time: at(0), PowerPct: 0.5, Position: 3, HasPosition: true}), test.ShouldBeEmpty)
+		test.That(t, d.Update(motor.FaultSample{Time: at(0.5), PowerPct: 0.5, Position: 3, HasPosition: true}), test.ShouldBeEmpty)
+		faults := d.Update(motor.FaultSample{Time: at(1.6), PowerPct: 0.5, Position: 3, HasPosition: true})
+		test.That(t, faults, test.ShouldHaveLength, 1)
+		test.That(t, faults[0].Type, test.ShouldEqual, motor.FaultStall)
+		test.That(t, d.Stats(), test.ShouldResemble, motor.FaultStats{StallCount: 1, Stalled: 1})
+
+		// reported once while the condition persists
+		test.That(t, d.Update(motor.FaultSample{Time: at(3), PowerPct: 0.5, Position: 3, HasPosition: true}), test.ShouldBeEmpty)
+
+		// moving clears the stall
+		test.That(t, d.Update(motor.FaultSample{Time: at(3.5), PowerPct: 0.5, Position: 4, HasPosition: true}), test.ShouldBeEmpty)
+		test.That(t, d.Stats(), test.ShouldResemble, motor.FaultStats{StallCount: 1})
+
+		// an unpowered motor is not stalled
+		for i := 4; i < 10; i++ {
+			test.That(t, d.Update(motor.FaultSample{Time: at(float64(i)), Position: 4, HasPosition: true}), test.ShouldBeEmpty)
+		}
+
+		test.That(t, d.Faults(start), test.ShouldHaveLength, 1)
+		test.That(t, d.Faults(at(2)), test.ShouldBeEmpty)
+	})
+
+	t.Run("overcurrent", func(t *testing.T) {
+		d := motor.NewFaultDetector(motor.FaultDetectionConfig{OvercurrentAmps: 2})
+		test.That(t, d.Update(motor.FaultSample{Time: at(0), CurrentAmps: 1.5, HasCurrent: true}), test.ShouldBeEmpty)
+		faults := d.Update(motor.FaultSample{Time: at(1), CurrentAmps: -2.5, HasCurrent: true})
+		test.That(t, faults, test.ShouldHaveLength, 1)
+		test.That(t, faults[0].Type, test.ShouldEqual, motor.FaultOvercurrent)
+		test.That(t, d.Update(motor.FaultSample{Time: at(2), CurrentAmps: 3, HasCurrent: true}), test.ShouldBeEmpty)
+		test.That(t, d.Update(motor.FaultSample{Time: at(3), CurrentAmps: 1, HasCurrent: true}), test.ShouldBeEmpty)
+		test.That(t, d.Update(motor.FaultSample{Time: at(4), CurrentAmps: 3, HasCurrent: true}), test.ShouldHaveLength, 1)
+		test.That(t, d.Stats(), test.ShouldResemble, motor.FaultStats{OvercurrentCount: 2, Overcurrent: 1})
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		conf := motor.FaultDetectionConfig{StallTimeoutSecs: 1, OvercurrentAmps: 2}
+		test.That(t, conf.Validate("path"), test.ShouldBeNil)
+		conf.StallMinPowerPct = 2
+		test.That(t, conf.Validate("path"), test.ShouldNotBeNil)
+		conf = motor.FaultDetectionConfig{OvercurrentAmps: -1}
+		test.That(t, conf.Validate("path"), test.ShouldNotBeNil)
+	})
+}
@@ -128,9 +128,13 @@ func setupMotorWithControls(
 		tunedVals:        &[]control.PIDConfig{{}},
 		ticksPerRotation: tpr,
 		maxRPM:           maxRPM,
+		positionDeadband: conf.PositionDeadbandRevolutions,
 		real:             m,
 		enc:              enc,
 	}
+	if cm.positionDeadband == 0 {
+		cm.positionDeadband = defaultPositionDeadband
+	}
 
 	// setup control loop
 	if conf.ControlParameters == nil {
@@ -153,6 +157,7 @@ type controlledMotor struct {
 	offsetInTicks    float64
 	ticksPerRotation float64
 	maxRPM           float64
+	positionDeadband float64
 
 	mu   sync.RWMutex
 	real motor.Motor
@@ -273,7 +278,7 @@ func (cm *controlledMotor) GoTo(ctx context.Context, rpm, targetPosition float64
 
 	// if you call GoFor with 0 revolutions, the motor will spin forever. If we are at the target,
 	// we must avoid this by not calling GoFor.
-	if rdkutils.Float64AlmostEqual(rotations, 0, 0.1) {
+	if rdkutils.Float64AlmostEqual(rotations, 0, cm.positionDeadband) {
 		cm.logger.CDebug(ctx, "GoTo distance nearly zero, not moving")
 		return nil
 	}
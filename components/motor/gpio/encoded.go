@@ -20,6 +20,12 @@ import (
 	rdkutils "go.viam.com/rdk/utils"
 )
 
+// faultSampleInterval is how often motor state is sampled for fault detection.
+const faultSampleInterval = 50 * time.Millisecond
+
+// defaultPositionDeadband is how close, in revolutions, GoTo must be to its target to not move.
+const defaultPositionDeadband = 0.1
+
 // WrapMotorWithEncoder takes a motor and adds an encoder onto it in order to understand its odometry.
 func WrapMotorWithEncoder(
 	ctx context.Context,
@@ -73,9 +79,14 @@ func newEncodedMotor(
 		real:             localReal,
 		rampRate:         motorConfig.RampRate,
 		maxPowerPct:      motorConfig.MaxPowerPct,
+		backlashTicks:    motorConfig.BacklashRevolutions * float64(motorConfig.TicksPerRotation),
+		positionDeadband: motorConfig.PositionDeadbandRevolutions,
 		logger:           logger,
 		opMgr:            operation.NewSingleOperationManager(),
 	}
+	if em.positionDeadband == 0 {
+		em.positionDeadband = defaultPositionDeadband
+	}
 
 	em.encoder = realEncoder
 
@@ -99,6 +110,16 @@ func newEncodedMotor(
 		em.maxPowerPct = 1.0
 	}
 
+	if motorConfig.FaultDetection != nil {
+		em.faults = motor.NewFaultDetector(*motorConfig.FaultDetection)
+		var monitorCtx context.Context
+		monitorCtx, em.monitorFaultsDone = context.WithCancel(context.Background())
+		em.activeBackgroundWorkers.Add(1)
+		utils.ManagedGo(func() {
+			em.monitorFaults(monitorCtx)
+		}, em.activeBackgroundWorkers.Done)
+	}
+
 	return em, nil
 }
 
@@ -123,10 +144,87 @@ type EncodedMotor struct {
 	maxPowerPct      float64
 	ticksPerRotation float64
 
+	// backlashTicks of slack are taken up whenever the motor changes direction, which lastDirection
+	// (guarded by mu) tracks.
+	backlashTicks    float64
+	lastDirection    float64
+	positionDeadband float64
+
+	faults            *motor.FaultDetector
+	monitorFaultsDone func()
+
 	logger logging.Logger
 	opMgr  *operation.SingleOperationManager
 }
 
+// monitorFaults samples the motor's power, position and current, if the real motor can measure it,
+// until ctx is done, and stops the motor on faults if configured to.
+func (m *EncodedMotor) monitorFaults(ctx context.Context) {
+	currentMotor, hasCurrent := m.real.(motor.CurrentController)
+	hasCurrent = hasCurrent && m.faults.Config().OvercurrentAmps > 0
+	for utils.SelectContextOrWait(ctx, faultSampleInterval) {
+		sample := motor.FaultSample{Time: time.Now()}
+		var err error
+		if _, sample.PowerPct, err = m.real.IsPowered(ctx, nil); err != nil {
+			m.logger.CDebugw(ctx, "error getting power for fault detection", "error", err)
+			continue
+		}
+		ticks, _, err := m.encoder.Position(ctx, encoder.PositionTypeTicks, nil)
+		if err == nil {
+			sample.Position = ticks
+			sample.HasPosition = true
+		}
+		if hasCurrent {
+			if sample.CurrentAmps, err = currentMotor.Current(ctx, nil); err == nil {
+				sample.HasCurrent = true
+			}
+		}
+		for _, fault := range m.faults.Update(sample) {
+			m.logger.CWarnf(ctx, "motor %s fault detected: %s", fault.Type, fault.Message)
+			if m.faults.Config().StopOnFault {
+				if err := m.Stop(ctx, nil); err != nil {
+					m.logger.CErrorw(ctx, "error stopping motor after fault", "error", err)
+				}
+			}
+		}
+	}
+}
+
+// Faults returns the stalls and overcurrents detected after `since`, if fault detection is
+// configured.
+func (m *EncodedMotor) Faults(ctx context.Context, since time.Time, extra map[string]interface{}) ([]motor.Fault, error) {
+	if m.faults == nil {
+		return nil, errors.New("fault_detection is not configured")
+	}
+	return m.faults.Faults(since), nil
+}
+
+// Stats returns the fault counters of the motor, which are recorded by FTDC.
+func (m *EncodedMotor) Stats() any {
+	if m.faults == nil {
+		return motor.FaultStats{}
+	}
+	return m.faults.Stats()
+}
+
+// takeUpBacklash records that the motor is about to turn in `direction` and returns how many ticks
+// of slack it turns through first without moving its output. Those ticks are excluded from the
+// motor's position. As the slack is unknown before the motor's first move, none is taken up then.
+func (m *EncodedMotor) takeUpBacklash(direction float64) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if direction == 0 {
+		return 0
+	}
+	last := m.lastDirection
+	m.lastDirection = direction
+	if m.backlashTicks == 0 || last == 0 || last == direction {
+		return 0
+	}
+	m.offsetInTicks -= direction * m.backlashTicks
+	return m.backlashTicks
+}
+
 // makeAdjustments keeps track of the desired RPM and position.
 func (m *EncodedMotor) makeAdjustments(ctx context.Context, goalRPM, goalPos, direction float64) error {
 	lastTicks, _, err := m.encoder.Position(ctx, encoder.PositionTypeTicks, nil)
@@ -238,6 +336,7 @@ func (m *EncodedMotor) SetPower(ctx context.Context, powerPct float64, extra map
 		m.makeAdjustmentsDone()
 	}
 	powerPct = fixPowerPct(powerPct, m.maxPowerPct)
+	m.takeUpBacklash(sign(powerPct))
 	return m.real.SetPower(ctx, powerPct, nil)
 }
 
@@ -270,6 +369,7 @@ func (m *EncodedMotor) GoFor(ctx context.Context, rpm, revolutions float64, extr
 	}
 
 	goalPos, goalRPM, direction := encodedGoForMath(rpm, revolutions, currentTicks, m.ticksPerRotation)
+	goalPos += direction * m.takeUpBacklash(direction)
 
 	if err := m.goForInternal(goalRPM, goalPos, direction); err != nil {
 		return err
@@ -357,7 +457,7 @@ func (m *EncodedMotor) GoTo(ctx context.Context, rpm, targetPosition float64, ex
 
 	// if you call GoFor with 0 revolutions, the motor will spin forever. If we are at the target,
 	// we must avoid this by not calling GoFor.
-	if rdkutils.Float64AlmostEqual(rotations, 0, 0.1) {
+	if rdkutils.Float64AlmostEqual(rotations, 0, m.positionDeadband) {
 		m.logger.CDebug(ctx, "GoTo distance nearly zero, not moving")
 		return nil
 	}
@@ -379,6 +479,7 @@ func (m *EncodedMotor) SetRPM(ctx context.Context, rpm float64, extra map[string
 
 	goalPos := math.Inf(int(rpm))
 	direction := sign(rpm)
+	m.takeUpBacklash(direction)
 	if err := m.goForInternal(rpm, goalPos, direction); err != nil {
 		return err
 	}
@@ -446,6 +547,9 @@ func (m *EncodedMotor) Stop(ctx context.Context, extra map[string]interface{}) e
 
 // Close cleanly shuts down the motor.
 func (m *EncodedMotor) Close(ctx context.Context) error {
+	if m.monitorFaultsDone != nil {
+		m.monitorFaultsDone()
+	}
 	if err := m.Stop(ctx, nil); err != nil {
 		return err
 	}
@@ -299,3 +299,110 @@ func TestEncodedMotor(t *testing.T) {
 		cancel()
 	})
 }
+
+func TestEncodedMotorBacklash(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	vals := newState()
+	conf := resource.Config{
+		Name:                motorName,
+		ConvertedAttributes: &Config{},
+	}
+	motorConf := Config{
+		TicksPerRotation:            1,
+		BacklashRevolutions:         2,
+		PositionDeadbandRevolutions: 3,
+	}
+	wrappedMotor, err := WrapMotorWithEncoder(ctx, injectEncoder(vals), conf, motorConf, injectMotor(vals), logger)
+	test.That(t, err, test.ShouldBeNil)
+	m, ok := wrappedMotor.(*EncodedMotor)
+	test.That(t, ok, test.ShouldBeTrue)
+	defer func() {
+		test.That(t, m.Close(ctx), test.ShouldBeNil)
+	}()
+
+	ticks := func() float64 {
+		vals.mu.Lock()
+		defer vals.mu.Unlock()
+		return vals.position
+	}
+
+	t.Run("slack is taken up on direction changes", func(t *testing.T) {
+		m.lastDirection = 0
+		// nothing is known of the slack before the first move
+		test.That(t, m.takeUpBacklash(1), test.ShouldEqual, 0)
+		test.That(t, m.takeUpBacklash(1), test.ShouldEqual, 0)
+		test.That(t, m.takeUpBacklash(0), test.ShouldEqual, 0)
+
+		before, err := m.Position(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, m.takeUpBacklash(-1), test.ShouldEqual, 2)
+		after, err := m.Position(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		// the slack the motor turns through going backwards is not part of its position
+		test.That(t, after-before, test.ShouldEqual, 2)
+
+		test.That(t, m.takeUpBacklash(-1), test.ShouldEqual, 0)
+		test.That(t, m.takeUpBacklash(1), test.ShouldEqual, 2)
+	})
+
+	t.Run("GoFor is lengthened when changing direction", func(t *testing.T) {
+		test.That(t, m.GoFor(ctx, 10, 1, nil), test.ShouldBeNil)
+		startTicks := ticks()
+		startPos, err := m.Position(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+
+		test.That(t, m.GoFor(ctx, 10, -1, nil), test.ShouldBeNil)
+		test.That(t, startTicks-ticks(), test.ShouldBeGreaterThanOrEqualTo, 3)
+		endPos, err := m.Position(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, startPos-endPos, test.ShouldBeGreaterThanOrEqualTo, 1)
+		test.That(t, startPos-endPos, test.ShouldBeLessThan, startTicks-ticks())
+	})
+
+	t.Run("GoTo does not move within the deadband", func(t *testing.T) {
+		pos, err := m.Position(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		startTicks := ticks()
+		test.That(t, m.GoTo(ctx, 10, pos+2.5, nil), test.ShouldBeNil)
+		test.That(t, ticks(), test.ShouldEqual, startTicks)
+	})
+}
+
+func TestEncodedMotorFaults(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	vals := newState()
+	conf := resource.Config{
+		Name:                motorName,
+		ConvertedAttributes: &Config{},
+	}
+	motorConf := Config{
+		TicksPerRotation: 1,
+		FaultDetection:   &motor.FaultDetectionConfig{StallTimeoutSecs: 0.2, StopOnFault: true},
+	}
+	wrappedMotor, err := WrapMotorWithEncoder(context.Background(), injectEncoder(vals), conf, motorConf, injectMotor(vals), logger)
+	test.That(t, err, test.ShouldBeNil)
+	m, ok := wrappedMotor.(*EncodedMotor)
+	test.That(t, ok, test.ShouldBeTrue)
+	defer func() {
+		test.That(t, m.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	// the injected motor only moves when its power is set, so it stalls right after
+	start := time.Now()
+	test.That(t, m.SetPower(context.Background(), 0.5, nil), test.ShouldBeNil)
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		faults, err := m.Faults(context.Background(), start, nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, faults, test.ShouldHaveLength, 1)
+	})
+	faults, err := m.Faults(context.Background(), start, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, faults[0].Type, test.ShouldEqual, motor.FaultStall)
+	on, _, err := m.IsPowered(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, on, test.ShouldBeFalse)
+	test.That(t, m.Stats().(motor.FaultStats).StallCount, test.ShouldEqual, 1)
+}
@@ -124,6 +124,15 @@ type Config struct {
 	MaxRPM            float64         `json:"max_rpm,omitempty"`
 	TicksPerRotation  int             `json:"ticks_per_rotation,omitempty"`
 	ControlParameters *motorPIDConfig `json:"control_parameters,omitempty"`
+	// FaultDetection enables stall and overcurrent detection on motors with an encoder.
+	FaultDetection *motor.FaultDetectionConfig `json:"fault_detection,omitempty"`
+	// BacklashRevolutions is the gear slop, in revolutions, that a motor with an encoder turns
+	// through without moving its output when it changes direction. Moves are lengthened by it when
+	// they change direction, and it is excluded from the motor's position.
+	BacklashRevolutions float64 `json:"backlash_revolutions,omitempty"`
+	// PositionDeadbandRevolutions is how close, in revolutions, a motor with an encoder must be to
+	// the target of a GoTo to not move at all. Defaults to 0.1.
+	PositionDeadbandRevolutions float64 `json:"position_deadband_revolutions,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -147,9 +156,28 @@ func (conf *Config) Validate(path string) ([]string, error) {
 			return nil, resource.NewConfigValidationError(path, errors.New("ticks_per_rotation should be positive or zero"))
 		}
 		deps = append(deps, conf.Encoder)
+	} else if conf.FaultDetection != nil {
+		return nil, resource.NewConfigValidationError(path, errors.New("fault_detection requires an encoder"))
+	} else if conf.BacklashRevolutions != 0 || conf.PositionDeadbandRevolutions != 0 {
+		return nil, resource.NewConfigValidationError(path,
+			errors.New("backlash_revolutions and position_deadband_revolutions require an encoder"))
 	} else if conf.MaxRPM <= 0 {
 		return nil, resource.NewConfigValidationFieldRequiredError(path, "max_rpm")
 	}
+	if conf.BacklashRevolutions < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("backlash_revolutions cannot be negative"))
+	}
+	if conf.BacklashRevolutions > 0 && conf.ControlParameters != nil {
+		return nil, resource.NewConfigValidationError(path, errors.New("backlash_revolutions is not supported with control_parameters"))
+	}
+	if conf.PositionDeadbandRevolutions < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("position_deadband_revolutions cannot be negative"))
+	}
+	if conf.FaultDetection != nil {
+		if err := conf.FaultDetection.Validate(path + ".fault_detection"); err != nil {
+			return nil, err
+		}
+	}
 	return deps, nil
 }
 
@@ -150,6 +150,39 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErrText: resource.NewConfigValidationError("test/path", errors.New("ticks_per_rotation should be positive or zero")).Error(),
 		},
+		{
+			name: "backlash without encoder",
+			config: Config{
+				BoardName:           "board1",
+				Pins:                PinConfig{A: "pin1", B: "pin2", PWM: "pwm1"},
+				MaxRPM:              100,
+				BacklashRevolutions: 0.1,
+			},
+			wantErrText: "require an encoder",
+		},
+		{
+			name: "negative backlash",
+			config: Config{
+				BoardName:           "board1",
+				Pins:                PinConfig{A: "pin1", B: "pin2", PWM: "pwm1"},
+				Encoder:             "encoder1",
+				TicksPerRotation:    100,
+				BacklashRevolutions: -0.1,
+			},
+			wantErrText: "backlash_revolutions cannot be negative",
+		},
+		{
+			name: "backlash with control parameters",
+			config: Config{
+				BoardName:           "board1",
+				Pins:                PinConfig{A: "pin1", B: "pin2", PWM: "pwm1"},
+				Encoder:             "encoder1",
+				TicksPerRotation:    100,
+				BacklashRevolutions: 0.1,
+				ControlParameters:   &motorPIDConfig{P: 1},
+			},
+			wantErrText: "not supported with control_parameters",
+		},
 	}
 
 	for _, tt := range tests {
@@ -31,6 +31,7 @@ func init() {
 		API:        API,
 		MethodName: isPowered.String(),
 	}, newIsPoweredCollector)
+	robot.RegisterResourceReader(API, readMotor)
 }
 
 // SubtypeName is a constant that identifies the component resource API string "motor".
@@ -232,3 +233,20 @@ func ClampPower(pwr float64) float64 {
 	pwr = math.Max(pwr, -1.0)
 	return pwr
 }
+
+// readMotor reads the position and power of a motor for a bulk read.
+func readMotor(ctx context.Context, res resource.Resource, extra map[string]interface{}) (map[string]interface{}, error) {
+	m, err := resource.AsType[Motor](res)
+	if err != nil {
+		return nil, err
+	}
+	position, err := m.Position(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	isPowered, powerPct, err := m.IsPowered(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"position": position, "is_powered": isPowered, "power_pct": powerPct}, nil
+}
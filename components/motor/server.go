@@ -188,5 +188,11 @@ func (server *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := doCurrentCommand(ctx, motor, req.GetName(), req); handled {
+		return resp, err
+	}
+	if resp, handled, err := doFaultsCommand(ctx, motor, req); handled {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, motor, req)
 }
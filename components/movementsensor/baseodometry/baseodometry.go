@@ -0,0 +1,177 @@
+// Package baseodometry implements a movement sensor reporting the odometry a base tracks itself,
+// such as a wheeled base with encoded motors, so SLAM and navigation can use it.
+package baseodometry
+
+import (
+	"context"
+	"math"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// Model is the name of the base odometry model of a movementsensor component.
+var Model = resource.DefaultModelFamily.WithModel("base-odometry")
+
+const (
+	returnRelative = "return_relative_pos_m"
+	resetOdometry  = "reset"
+)
+
+// Config is used for converting config attributes.
+type Config struct {
+	Base string `json:"base"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.Base == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "base")
+	}
+	return []string{cfg.Base}, nil
+}
+
+func init() {
+	resource.RegisterComponent(
+		movementsensor.API,
+		Model,
+		resource.Registration[movementsensor.MovementSensor, *Config]{Constructor: newBaseOdometry})
+}
+
+type baseOdometry struct {
+	resource.Named
+	resource.AlwaysRebuild
+	resource.TriviallyCloseable
+
+	odometer base.Odometer
+	// origin is the geographic position odometry is reported relative to.
+	origin *geo.Point
+	logger logging.Logger
+}
+
+func newBaseOdometry(
+	ctx context.Context,
+	deps resource.Dependencies,
+	conf resource.Config,
+	logger logging.Logger,
+) (movementsensor.MovementSensor, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	b, err := base.FromDependencies(deps, newConf.Base)
+	if err != nil {
+		return nil, err
+	}
+	odometer, ok := b.(base.Odometer)
+	if !ok {
+		return nil, errors.Errorf("base %s does not track its odometry", newConf.Base)
+	}
+	// fail early for bases that cannot track odometry, such as wheeled bases without encoders
+	if _, err := odometer.Odometry(ctx, nil); err != nil {
+		return nil, err
+	}
+	return &baseOdometry{
+		Named:    conf.ResourceName().AsNamed(),
+		odometer: odometer,
+		origin:   geo.NewPoint(0, 0),
+		logger:   logger,
+	}, nil
+}
+
+// Position returns the position the base has moved to from the origin at (0, 0), with +Y of the
+// base's starting pose facing north. If `return_relative_pos_m` is set in extra, the position is
+// instead returned as (y, x) in meters.
+func (o *baseOdometry) Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+	odometry, err := o.odometer.Odometry(ctx, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	pt := odometry.Pose.Point().Mul(0.001)
+	if relative, _ := extra[returnRelative].(bool); relative { //nolint:errcheck
+		return geo.NewPoint(pt.Y, pt.X), 0, nil
+	}
+	distanceKm := math.Hypot(pt.X, pt.Y) / 1000
+	bearing := utils.RadToDeg(math.Atan2(pt.X, pt.Y))
+	return o.origin.PointAtDistanceAndBearing(distanceKm, bearing), 0, nil
+}
+
+// LinearVelocity returns the base's velocity in m/s.
+func (o *baseOdometry) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	odometry, err := o.odometer.Odometry(ctx, nil)
+	if err != nil {
+		return r3.Vector{}, err
+	}
+	return odometry.LinearVelocity.Mul(0.001), nil
+}
+
+func (o *baseOdometry) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+	odometry, err := o.odometer.Odometry(ctx, nil)
+	if err != nil {
+		return spatialmath.AngularVelocity{}, err
+	}
+	return odometry.AngularVelocity, nil
+}
+
+func (o *baseOdometry) LinearAcceleration(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	return r3.Vector{}, movementsensor.ErrMethodUnimplementedLinearAcceleration
+}
+
+func (o *baseOdometry) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	return 0, movementsensor.ErrMethodUnimplementedCompassHeading
+}
+
+func (o *baseOdometry) Orientation(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+	odometry, err := o.odometer.Odometry(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return odometry.Pose.Orientation(), nil
+}
+
+func (o *baseOdometry) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	readings, err := movementsensor.DefaultAPIReadings(ctx, o, extra)
+	if err != nil {
+		return nil, err
+	}
+	odometry, err := o.odometer.Odometry(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	pt := odometry.Pose.Point().Mul(0.001)
+	readings["position_meters_X"] = pt.X
+	readings["position_meters_Y"] = pt.Y
+	return readings, nil
+}
+
+func (o *baseOdometry) Accuracy(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+	return movementsensor.UnimplementedOptionalAccuracies(), nil
+}
+
+func (o *baseOdometry) Properties(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+	return &movementsensor.Properties{
+		LinearVelocitySupported:  true,
+		AngularVelocitySupported: true,
+		OrientationSupported:     true,
+		PositionSupported:        true,
+	}, nil
+}
+
+// DoCommand resets the base's odometry given {"reset": true}.
+func (o *baseOdometry) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if reset, _ := cmd[resetOdometry].(bool); reset { //nolint:errcheck
+		if err := o.odometer.ResetOdometry(ctx, nil); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{resetOdometry: "odometry reset"}, nil
+	}
+	return nil, resource.ErrDoUnimplemented
+}
@@ -0,0 +1,102 @@
+package baseodometry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+const (
+	baseName       = "base"
+	testSensorName = "name"
+)
+
+type odometerBase struct {
+	*inject.Base
+	odometer *base.DifferentialOdometer
+}
+
+func (b *odometerBase) Odometry(ctx context.Context, extra map[string]interface{}) (base.Odometry, error) {
+	return b.odometer.Odometry(), nil
+}
+
+func (b *odometerBase) ResetOdometry(ctx context.Context, extra map[string]interface{}) error {
+	b.odometer.Reset()
+	return nil
+}
+
+func TestValidateConfig(t *testing.T) {
+	cfg := Config{}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeError, resource.NewConfigValidationFieldRequiredError("path", "base"))
+
+	cfg.Base = baseName
+	deps, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{baseName})
+}
+
+func TestBaseOdometry(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	conf := resource.Config{
+		Name:                testSensorName,
+		ConvertedAttributes: &Config{Base: baseName},
+	}
+
+	t.Run("base without odometry", func(t *testing.T) {
+		deps := resource.Dependencies{base.Named(baseName): inject.NewBase(baseName)}
+		_, err := newBaseOdometry(ctx, deps, conf, logger)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "does not track its odometry")
+	})
+
+	start := time.Now()
+	odometer := base.NewDifferentialOdometer(100)
+	odometer.Update(start, 0, 0)
+	deps := resource.Dependencies{
+		base.Named(baseName): &odometerBase{Base: inject.NewBase(baseName), odometer: odometer},
+	}
+	ms, err := newBaseOdometry(ctx, deps, conf, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	// drive 1m forward in 2s
+	odometer.Update(start.Add(2*time.Second), 1000, 1000)
+
+	pos, _, err := ms.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	expected := geo.NewPoint(0, 0).PointAtDistanceAndBearing(0.001, 0)
+	test.That(t, pos.Lat(), test.ShouldAlmostEqual, expected.Lat())
+	test.That(t, pos.Lng(), test.ShouldAlmostEqual, 0)
+
+	pos, _, err = ms.Position(ctx, map[string]interface{}{returnRelative: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos.Lat(), test.ShouldAlmostEqual, 1)
+	test.That(t, pos.Lng(), test.ShouldAlmostEqual, 0)
+
+	linVel, err := ms.LinearVelocity(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, linVel.Y, test.ShouldAlmostEqual, 0.5)
+
+	orientation, err := ms.Orientation(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, orientation.OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, 0)
+
+	readings, err := ms.Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings["position_meters_Y"], test.ShouldAlmostEqual, 1)
+
+	_, err = ms.DoCommand(ctx, map[string]interface{}{resetOdometry: true})
+	test.That(t, err, test.ShouldBeNil)
+	pos, _, err = ms.Position(ctx, map[string]interface{}{returnRelative: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos.Lat(), test.ShouldAlmostEqual, 0)
+}
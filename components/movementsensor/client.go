@@ -10,6 +10,8 @@ import (
 	"go.viam.com/utils/rpc"
 	"google.golang.org/protobuf/types/known/structpb"
 
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
@@ -22,6 +24,7 @@ type client struct {
 	resource.TriviallyReconfigurable
 	resource.TriviallyCloseable
 	name   string
+	conn   rpc.ClientConn
 	client pb.MovementSensorServiceClient
 	logger logging.Logger
 }
@@ -38,6 +41,7 @@ func NewClientFromConn(
 	return &client{
 		Named:  name.PrependRemote(remoteName).AsNamed(),
 		name:   name.ShortName(),
+		conn:   conn,
 		client: c,
 		logger: logger,
 	}, nil
@@ -185,3 +189,16 @@ func (c *client) Properties(ctx context.Context, extra map[string]interface{}) (
 func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	return protoutils.DoFromResourceClient(ctx, c.client, c.name, cmd)
 }
+
+// StreamReadings has the server sample the movement sensor and receives its readings in batches.
+func (c *client) StreamReadings(
+	ctx context.Context,
+	opts sensor.ReadingsStreamOptions,
+	extra map[string]interface{},
+	handle func([]sensor.TimedReadings) error,
+) error {
+	// A stream lost with the connection is resubscribed to once the connection is re-established.
+	return grpc.ResumeStream(ctx, c.conn, func(ctx context.Context) error {
+		return sensor.StreamReadingsWithDoCommand(ctx, c.DoCommand, opts, extra, handle)
+	})
+}
@@ -0,0 +1,166 @@
+package fusion
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/utils"
+)
+
+// unknownVariance is the variance of a state that has not been measured yet.
+const unknownVariance = 1e6
+
+// noise are the standard deviations of the measurements and of the motion model.
+type noise struct {
+	positionM          float64
+	velocityMPerSec    float64
+	headingDegs        float64
+	gyroDegsPerSec     float64
+	accelMPerSecPerSec float64
+}
+
+// axisFilter is a Kalman filter of the position and velocity along one horizontal axis, using a
+// constant velocity model.
+type axisFilter struct {
+	pos, vel float64
+	// cov is the covariance of [pos, vel].
+	cov [2][2]float64
+}
+
+func newAxisFilter(posVariance float64) axisFilter {
+	return axisFilter{cov: [2][2]float64{{posVariance, 0}, {0, unknownVariance}}}
+}
+
+// predict advances the state by dt seconds, with accelVariance the variance of the unmodelled
+// acceleration.
+func (f *axisFilter) predict(dt, accelVariance float64) {
+	f.pos += f.vel * dt
+	p := f.cov
+	dt2 := dt * dt
+	f.cov = [2][2]float64{
+		{
+			p[0][0] + dt*(p[0][1]+p[1][0]) + dt2*p[1][1] + accelVariance*dt2*dt2/4,
+			p[0][1] + dt*p[1][1] + accelVariance*dt2*dt/2,
+		},
+		{
+			p[1][0] + dt*p[1][1] + accelVariance*dt2*dt/2,
+			p[1][1] + accelVariance*dt2,
+		},
+	}
+}
+
+// update corrects the state with a measurement z of the position (idx 0) or velocity (idx 1)
+// whose variance is r.
+func (f *axisFilter) update(idx int, z, r float64) {
+	innovation := z - f.pos
+	if idx == 1 {
+		innovation = z - f.vel
+	}
+	s := f.cov[idx][idx] + r
+	k0 := f.cov[0][idx] / s
+	k1 := f.cov[1][idx] / s
+	f.pos += k0 * innovation
+	f.vel += k1 * innovation
+	row := f.cov[idx]
+	f.cov[0][0] -= k0 * row[0]
+	f.cov[0][1] -= k0 * row[1]
+	f.cov[1][0] -= k1 * row[0]
+	f.cov[1][1] -= k1 * row[1]
+}
+
+// headingFilter is a Kalman filter of the compass heading, integrating the yaw rate between
+// absolute heading measurements.
+type headingFilter struct {
+	degs     float64
+	variance float64
+	known    bool
+}
+
+func (f *headingFilter) predict(rateDegsPerSec, dt, rateVariance float64) {
+	f.degs = wrap360(f.degs + rateDegsPerSec*dt)
+	f.variance += rateVariance * dt * dt
+}
+
+func (f *headingFilter) update(z, r float64) {
+	if !f.known {
+		f.degs, f.variance, f.known = wrap360(z), r, true
+		return
+	}
+	k := f.variance / (f.variance + r)
+	f.degs = wrap360(f.degs + k*wrap180(z-f.degs))
+	f.variance *= 1 - k
+}
+
+// estimator fuses position, body velocity, heading and yaw rate measurements into a position and
+// velocity estimate in meters east and north of an origin, and a compass heading.
+type estimator struct {
+	noise       noise
+	east, north axisFilter
+	heading     headingFilter
+}
+
+// newEstimator returns an estimator. If absolute is true the position is unknown until the first
+// position measurement, otherwise the estimate starts at the origin.
+func newEstimator(n noise, absolute bool) *estimator {
+	posVariance := 0.0
+	if absolute {
+		posVariance = unknownVariance
+	}
+	// without heading measurements, the heading is relative to the initial one, which is north
+	return &estimator{
+		noise: n,
+		east:  newAxisFilter(posVariance),
+		north: newAxisFilter(posVariance),
+	}
+}
+
+// predict advances the estimate by dt seconds at the given yaw rate, which is counterclockwise
+// positive like a movement sensor's angular velocity.
+func (e *estimator) predict(dt, yawRateDegsPerSec float64) {
+	accelVariance := e.noise.accelMPerSecPerSec * e.noise.accelMPerSecPerSec
+	e.east.predict(dt, accelVariance)
+	e.north.predict(dt, accelVariance)
+	// compass headings increase clockwise
+	e.heading.predict(-yawRateDegsPerSec, dt, e.noise.gyroDegsPerSec*e.noise.gyroDegsPerSec)
+}
+
+func (e *estimator) updatePosition(east, north float64) {
+	r := e.noise.positionM * e.noise.positionM
+	e.east.update(0, east, r)
+	e.north.update(0, north, r)
+}
+
+// updateVelocity corrects the estimate with a velocity in the sensor's frame, with +Y forward and
+// +X to the right, which is rotated into the world frame using the current heading.
+func (e *estimator) updateVelocity(v r3.Vector) {
+	h := utils.DegToRad(e.heading.degs)
+	r := e.noise.velocityMPerSec * e.noise.velocityMPerSec
+	e.east.update(1, v.Y*math.Sin(h)+v.X*math.Cos(h), r)
+	e.north.update(1, v.Y*math.Cos(h)-v.X*math.Sin(h), r)
+}
+
+func (e *estimator) updateHeading(degs float64) {
+	e.heading.update(degs, e.noise.headingDegs*e.noise.headingDegs)
+}
+
+// bodyVelocity returns the estimated velocity rotated into the frame of updateVelocity.
+func (e *estimator) bodyVelocity() r3.Vector {
+	h := utils.DegToRad(e.heading.degs)
+	return r3.Vector{
+		X: e.east.vel*math.Cos(h) - e.north.vel*math.Sin(h),
+		Y: e.east.vel*math.Sin(h) + e.north.vel*math.Cos(h),
+	}
+}
+
+func wrap360(degs float64) float64 {
+	return math.Mod(math.Mod(degs, 360)+360, 360)
+}
+
+func wrap180(degs float64) float64 {
+	degs = wrap360(degs)
+	if degs > 180 {
+		degs -= 360
+	}
+	return degs
+}
@@ -0,0 +1,66 @@
+package fusion
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+var testNoise = noise{
+	positionM:          2,
+	velocityMPerSec:    0.1,
+	headingDegs:        5,
+	gyroDegsPerSec:     1,
+	accelMPerSecPerSec: 0.5,
+}
+
+func TestEstimatorPosition(t *testing.T) {
+	est := newEstimator(testNoise, true)
+	for i := 0; i < 100; i++ {
+		est.predict(0.1, 0)
+		est.updatePosition(10, -5)
+	}
+	test.That(t, est.east.pos, test.ShouldAlmostEqual, 10, 0.01)
+	test.That(t, est.north.pos, test.ShouldAlmostEqual, -5, 0.01)
+	test.That(t, est.east.vel, test.ShouldAlmostEqual, 0, 0.01)
+	// fusing many measurements is more accurate than any one of them
+	test.That(t, est.east.cov[0][0], test.ShouldBeLessThan, testNoise.positionM*testNoise.positionM)
+	test.That(t, est.north.cov[0][0], test.ShouldEqual, est.east.cov[0][0])
+}
+
+func TestEstimatorDeadReckoning(t *testing.T) {
+	est := newEstimator(testNoise, false)
+	est.updateHeading(90)
+	for i := 0; i < 10; i++ {
+		est.updateVelocity(r3.Vector{Y: 2})
+		est.predict(0.1, 0)
+	}
+	// 2m/s east for a second
+	test.That(t, est.east.vel, test.ShouldAlmostEqual, 2, 0.01)
+	test.That(t, est.north.vel, test.ShouldAlmostEqual, 0, 0.01)
+	test.That(t, est.east.pos, test.ShouldAlmostEqual, 2, 0.05)
+	test.That(t, est.bodyVelocity().Y, test.ShouldAlmostEqual, 2, 0.01)
+	test.That(t, est.bodyVelocity().X, test.ShouldAlmostEqual, 0, 0.01)
+	// the position is uncertain since it was never measured
+	test.That(t, est.east.cov[0][0], test.ShouldBeGreaterThan, 0)
+}
+
+func TestEstimatorHeading(t *testing.T) {
+	est := newEstimator(testNoise, false)
+	est.updateHeading(350)
+	test.That(t, est.heading.degs, test.ShouldEqual, 350)
+	test.That(t, est.heading.variance, test.ShouldEqual, 25)
+
+	// fusing across north moves the heading towards it rather than through south
+	est.updateHeading(10)
+	test.That(t, est.heading.degs, test.ShouldAlmostEqual, 0)
+	test.That(t, est.heading.variance, test.ShouldAlmostEqual, 12.5)
+
+	// counterclockwise yaw rates decrease the heading
+	for i := 0; i < 10; i++ {
+		est.predict(0.1, 10)
+	}
+	test.That(t, est.heading.degs, test.ShouldAlmostEqual, 350)
+	test.That(t, est.heading.variance, test.ShouldBeGreaterThan, 12.5)
+}
@@ -0,0 +1,424 @@
+// Package fusion implements a movementsensor that fuses the readings of other movement sensors,
+// such as a GPS, an IMU and wheeled odometry, into a single pose and velocity estimate.
+//
+// Position and velocity are estimated with a constant velocity Kalman filter along the east and
+// north axes, and the compass heading with a Kalman filter integrating the yaw rate of angular
+// velocity sensors between heading measurements. The covariances of the estimates are part of the
+// sensor's readings.
+package fusion
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// Model is the name of the fusion model of a movementsensor component.
+var Model = resource.DefaultModelFamily.WithModel("fusion")
+
+const (
+	defaultUpdateRateHz          = 20
+	defaultPositionStdDevM       = 2.5
+	defaultVelocityStdDevMPerSec = 0.1
+	defaultHeadingStdDevDegs     = 5
+	defaultGyroStdDevDegsPerSec  = 1
+	defaultAccelStdDevMPerSecSq  = 0.5
+	mToKm                        = 1e-3
+)
+
+// Reading keys of the estimate covariances.
+const (
+	positionCovarianceKey = "position_covariance_m2"
+	velocityCovarianceKey = "velocity_covariance_m2_per_sec2"
+	headingVarianceKey    = "heading_variance_degs2"
+)
+
+// Config is the config of the fusion movement_sensor model.
+type Config struct {
+	// Position sensors report absolute positions, such as GPS receivers.
+	Position []string `json:"position,omitempty"`
+	// LinearVelocity sensors report velocities in their own frame, with +Y forward, such as
+	// wheeled odometry.
+	LinearVelocity []string `json:"linear_velocity,omitempty"`
+	// CompassHeading sensors report absolute headings.
+	CompassHeading []string `json:"compass_heading,omitempty"`
+	// AngularVelocity sensors report yaw rates, such as IMUs.
+	AngularVelocity []string `json:"angular_velocity,omitempty"`
+	// Orientation is an optional sensor whose orientation is reported as is. Otherwise the
+	// orientation is the fused heading.
+	Orientation string `json:"orientation,omitempty"`
+
+	UpdateRateHz          float64 `json:"update_rate_hz,omitempty"`
+	PositionStdDevM       float64 `json:"position_std_dev_m,omitempty"`
+	VelocityStdDevMPerSec float64 `json:"velocity_std_dev_m_per_sec,omitempty"`
+	HeadingStdDevDegs     float64 `json:"heading_std_dev_degs,omitempty"`
+	GyroStdDevDegsPerSec  float64 `json:"gyro_std_dev_degs_per_sec,omitempty"`
+	AccelStdDevMPerSecSq  float64 `json:"accel_std_dev_m_per_sec_sq,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if len(cfg.Position) == 0 && len(cfg.LinearVelocity) == 0 {
+		return nil, resource.NewConfigValidationError(path,
+			errors.New("at least one position or linear_velocity sensor is required"))
+	}
+	for _, val := range []float64{
+		cfg.UpdateRateHz, cfg.PositionStdDevM, cfg.VelocityStdDevMPerSec,
+		cfg.HeadingStdDevDegs, cfg.GyroStdDevDegsPerSec, cfg.AccelStdDevMPerSecSq,
+	} {
+		if val < 0 {
+			return nil, resource.NewConfigValidationError(path, errors.New("rates and standard deviations cannot be negative"))
+		}
+	}
+
+	seen := map[string]bool{}
+	var deps []string
+	for _, names := range [][]string{
+		cfg.Position, cfg.LinearVelocity, cfg.CompassHeading, cfg.AngularVelocity, {cfg.Orientation},
+	} {
+		for _, name := range names {
+			if name != "" && !seen[name] {
+				seen[name] = true
+				deps = append(deps, name)
+			}
+		}
+	}
+	return deps, nil
+}
+
+func (cfg *Config) noise() noise {
+	orDefault := func(val, def float64) float64 {
+		if val == 0 {
+			return def
+		}
+		return val
+	}
+	return noise{
+		positionM:          orDefault(cfg.PositionStdDevM, defaultPositionStdDevM),
+		velocityMPerSec:    orDefault(cfg.VelocityStdDevMPerSec, defaultVelocityStdDevMPerSec),
+		headingDegs:        orDefault(cfg.HeadingStdDevDegs, defaultHeadingStdDevDegs),
+		gyroDegsPerSec:     orDefault(cfg.GyroStdDevDegsPerSec, defaultGyroStdDevDegsPerSec),
+		accelMPerSecPerSec: orDefault(cfg.AccelStdDevMPerSecSq, defaultAccelStdDevMPerSecSq),
+	}
+}
+
+type fusion struct {
+	resource.Named
+	resource.AlwaysRebuild
+	logger logging.Logger
+
+	position        []movementsensor.MovementSensor
+	linearVelocity  []movementsensor.MovementSensor
+	compassHeading  []movementsensor.MovementSensor
+	angularVelocity []movementsensor.MovementSensor
+	orientation     movementsensor.MovementSensor
+	interval        time.Duration
+
+	mu         sync.Mutex
+	est        *estimator
+	origin     *geo.Point
+	altitude   float64
+	angVel     spatialmath.AngularVelocity
+	lastUpdate time.Time
+
+	workers *goutils.StoppableWorkers
+}
+
+func init() {
+	resource.RegisterComponent(
+		movementsensor.API,
+		Model,
+		resource.Registration[movementsensor.MovementSensor, *Config]{Constructor: newFusion})
+}
+
+func newFusion(
+	ctx context.Context,
+	deps resource.Dependencies,
+	conf resource.Config,
+	logger logging.Logger,
+) (movementsensor.MovementSensor, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	f := &fusion{
+		Named:  conf.ResourceName().AsNamed(),
+		logger: logger,
+		est:    newEstimator(newConf.noise(), len(newConf.Position) > 0),
+	}
+
+	sensorsWith := func(names []string, want func(*movementsensor.Properties) bool, propname string,
+	) ([]movementsensor.MovementSensor, error) {
+		sensors := make([]movementsensor.MovementSensor, 0, len(names))
+		for _, name := range names {
+			ms, err := movementsensor.FromDependencies(deps, name)
+			if err != nil {
+				return nil, err
+			}
+			props, err := ms.Properties(ctx, nil)
+			if err != nil {
+				return nil, err
+			}
+			if !want(props) {
+				return nil, errors.Errorf("movement sensor %q does not support %s", name, propname)
+			}
+			sensors = append(sensors, ms)
+		}
+		return sensors, nil
+	}
+	if f.position, err = sensorsWith(newConf.Position,
+		func(p *movementsensor.Properties) bool { return p.PositionSupported }, "position"); err != nil {
+		return nil, err
+	}
+	if f.linearVelocity, err = sensorsWith(newConf.LinearVelocity,
+		func(p *movementsensor.Properties) bool { return p.LinearVelocitySupported }, "linear_velocity"); err != nil {
+		return nil, err
+	}
+	if f.compassHeading, err = sensorsWith(newConf.CompassHeading,
+		func(p *movementsensor.Properties) bool { return p.CompassHeadingSupported }, "compass_heading"); err != nil {
+		return nil, err
+	}
+	if f.angularVelocity, err = sensorsWith(newConf.AngularVelocity,
+		func(p *movementsensor.Properties) bool { return p.AngularVelocitySupported }, "angular_velocity"); err != nil {
+		return nil, err
+	}
+	if newConf.Orientation != "" {
+		ori, err := sensorsWith([]string{newConf.Orientation},
+			func(p *movementsensor.Properties) bool { return p.OrientationSupported }, "orientation")
+		if err != nil {
+			return nil, err
+		}
+		f.orientation = ori[0]
+	}
+	if len(f.position) == 0 {
+		// without absolute positions, positions are relative to a start at (0, 0)
+		f.origin = geo.NewPoint(0, 0)
+	}
+
+	rate := newConf.UpdateRateHz
+	if rate == 0 {
+		rate = defaultUpdateRateHz
+	}
+	f.interval = time.Duration(float64(time.Second) / rate)
+	f.workers = goutils.NewBackgroundStoppableWorkers(f.run)
+	return f, nil
+}
+
+func (f *fusion) run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			f.update(ctx, now)
+		}
+	}
+}
+
+// update reads all sensors and feeds their measurements to the filters. Sensors that fail are
+// skipped for this update.
+func (f *fusion) update(ctx context.Context, now time.Time) {
+	var (
+		yawRate    float64
+		yawRates   int
+		angVel     spatialmath.AngularVelocity
+		hasAngVel  bool
+		headings   []float64
+		velocities []r3.Vector
+		positions  []*geo.Point
+		altitude   float64
+	)
+	for _, ms := range f.angularVelocity {
+		av, err := ms.AngularVelocity(ctx, nil)
+		if err != nil {
+			f.logger.CDebugw(ctx, "error reading angular velocity", "sensor", ms.Name().ShortName(), "error", err)
+			continue
+		}
+		if !hasAngVel {
+			angVel, hasAngVel = av, true
+		}
+		yawRate += av.Z
+		yawRates++
+	}
+	for _, ms := range f.compassHeading {
+		heading, err := ms.CompassHeading(ctx, nil)
+		if err != nil || math.IsNaN(heading) {
+			f.logger.CDebugw(ctx, "error reading compass heading", "sensor", ms.Name().ShortName(), "error", err)
+			continue
+		}
+		headings = append(headings, heading)
+	}
+	for _, ms := range f.linearVelocity {
+		vel, err := ms.LinearVelocity(ctx, nil)
+		if err != nil {
+			f.logger.CDebugw(ctx, "error reading linear velocity", "sensor", ms.Name().ShortName(), "error", err)
+			continue
+		}
+		velocities = append(velocities, vel)
+	}
+	for _, ms := range f.position {
+		pos, alt, err := ms.Position(ctx, nil)
+		if err != nil || pos == nil || math.IsNaN(pos.Lat()) || math.IsNaN(pos.Lng()) {
+			f.logger.CDebugw(ctx, "error reading position", "sensor", ms.Name().ShortName(), "error", err)
+			continue
+		}
+		if len(positions) == 0 {
+			altitude = alt
+		}
+		positions = append(positions, pos)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.lastUpdate.IsZero() {
+		if yawRates > 0 {
+			yawRate /= float64(yawRates)
+		}
+		f.est.predict(now.Sub(f.lastUpdate).Seconds(), yawRate)
+	}
+	f.lastUpdate = now
+	if hasAngVel {
+		f.angVel = angVel
+	}
+	for _, heading := range headings {
+		f.est.updateHeading(heading)
+	}
+	for _, vel := range velocities {
+		f.est.updateVelocity(vel)
+	}
+	for _, pos := range positions {
+		if f.origin == nil {
+			f.origin = pos
+		}
+		east, north := f.toLocal(pos)
+		f.est.updatePosition(east, north)
+		f.altitude = altitude
+	}
+}
+
+// toLocal returns the position of p in meters east and north of the origin.
+func (f *fusion) toLocal(p *geo.Point) (float64, float64) {
+	dist := f.origin.GreatCircleDistance(p) / mToKm
+	bearing := utils.DegToRad(f.origin.BearingTo(p))
+	return dist * math.Sin(bearing), dist * math.Cos(bearing)
+}
+
+// Position returns the fused position. Before the first position measurement, the position is
+// NaN.
+func (f *fusion) Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.origin == nil {
+		return geo.NewPoint(math.NaN(), math.NaN()), math.NaN(), nil
+	}
+	east, north := f.est.east.pos, f.est.north.pos
+	bearing := utils.RadToDeg(math.Atan2(east, north))
+	return f.origin.PointAtDistanceAndBearing(math.Hypot(east, north)*mToKm, bearing), f.altitude, nil
+}
+
+// LinearVelocity returns the fused velocity in the frame of the linear velocity sensors, with +Y
+// along the fused heading.
+func (f *fusion) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.est.bodyVelocity(), nil
+}
+
+func (f *fusion) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+	if len(f.angularVelocity) == 0 {
+		return spatialmath.AngularVelocity{}, movementsensor.ErrMethodUnimplementedAngularVelocity
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.angVel, nil
+}
+
+func (f *fusion) LinearAcceleration(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	return r3.Vector{}, movementsensor.ErrMethodUnimplementedLinearAcceleration
+}
+
+func (f *fusion) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	if len(f.compassHeading) == 0 {
+		return 0, movementsensor.ErrMethodUnimplementedCompassHeading
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.est.heading.known {
+		return math.NaN(), nil
+	}
+	return f.est.heading.degs, nil
+}
+
+// Orientation returns the orientation of the orientation sensor if one is configured, and
+// otherwise a rotation about +Z by the fused heading.
+func (f *fusion) Orientation(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+	if f.orientation != nil {
+		return f.orientation.Orientation(ctx, extra)
+	}
+	if len(f.compassHeading) == 0 && len(f.angularVelocity) == 0 {
+		return nil, movementsensor.ErrMethodUnimplementedOrientation
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &spatialmath.OrientationVector{OZ: 1, Theta: -utils.DegToRad(f.est.heading.degs)}, nil
+}
+
+func (f *fusion) Properties(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+	return &movementsensor.Properties{
+		PositionSupported:        true,
+		LinearVelocitySupported:  true,
+		AngularVelocitySupported: len(f.angularVelocity) > 0,
+		CompassHeadingSupported:  len(f.compassHeading) > 0,
+		OrientationSupported:     f.orientation != nil || len(f.compassHeading) > 0 || len(f.angularVelocity) > 0,
+	}, nil
+}
+
+// Accuracy reports the standard deviations of the fused position and heading.
+func (f *fusion) Accuracy(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+	acc := movementsensor.UnimplementedOptionalAccuracies()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	posStdDev := math.Sqrt(math.Max(f.est.east.cov[0][0], f.est.north.cov[0][0]))
+	acc.AccuracyMap = map[string]float32{"position_std_dev_m": float32(posStdDev)}
+	if len(f.compassHeading) > 0 && f.est.heading.known {
+		acc.CompassDegreeError = float32(math.Sqrt(f.est.heading.variance))
+	}
+	return acc, nil
+}
+
+// Readings returns the readings of all supported APIs, along with the covariances of the fused
+// position and velocity in the east and north axes, as row-major 2x2 matrices, and the variance of
+// the fused heading.
+func (f *fusion) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	readings, err := movementsensor.DefaultAPIReadings(ctx, f, extra)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// the axes are filtered independently, so they are uncorrelated
+	readings[positionCovarianceKey] = []interface{}{f.est.east.cov[0][0], 0.0, 0.0, f.est.north.cov[0][0]}
+	readings[velocityCovarianceKey] = []interface{}{f.est.east.cov[1][1], 0.0, 0.0, f.est.north.cov[1][1]}
+	readings[headingVarianceKey] = f.est.heading.variance
+	return readings, nil
+}
+
+func (f *fusion) Close(ctx context.Context) error {
+	// we do not try to Close the movement sensors that this driver depends on
+	f.workers.Stop()
+	return nil
+}
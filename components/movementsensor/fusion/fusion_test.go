@@ -0,0 +1,156 @@
+package fusion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func newInjectSensor(name string, props movementsensor.Properties) *inject.MovementSensor {
+	ms := inject.NewMovementSensor(name)
+	ms.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+		return &props, nil
+	}
+	return ms
+}
+
+func newTestFusion(t *testing.T, conf *Config, sensors ...movementsensor.MovementSensor) movementsensor.MovementSensor {
+	t.Helper()
+	deps := resource.Dependencies{}
+	for _, ms := range sensors {
+		deps[ms.Name()] = ms
+	}
+	ms, err := newFusion(context.Background(), deps, resource.Config{
+		Name:                "fused",
+		API:                 movementsensor.API,
+		Model:               Model,
+		ConvertedAttributes: conf,
+	}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() { test.That(t, ms.Close(context.Background()), test.ShouldBeNil) })
+	return ms
+}
+
+func TestValidate(t *testing.T) {
+	_, err := (&Config{}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	_, err = (&Config{Position: []string{"gps"}, PositionStdDevM: -1}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	deps, err := (&Config{
+		Position:        []string{"gps"},
+		LinearVelocity:  []string{"odometry"},
+		AngularVelocity: []string{"imu"},
+		CompassHeading:  []string{"gps", "imu"},
+		Orientation:     "imu",
+	}).Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"gps", "odometry", "imu"})
+}
+
+func TestFusionGPS(t *testing.T) {
+	ctx := context.Background()
+	gps := newInjectSensor("gps", movementsensor.Properties{PositionSupported: true, CompassHeadingSupported: true})
+	gps.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+		return geo.NewPoint(40.7, -74), 12, nil
+	}
+	gps.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		return 45, nil
+	}
+	ms := newTestFusion(t, &Config{
+		Position:       []string{"gps"},
+		CompassHeading: []string{"gps"},
+		UpdateRateHz:   100,
+	}, gps)
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		pos, alt, err := ms.Position(ctx, nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, pos.Lat(), test.ShouldAlmostEqual, 40.7, 1e-6)
+		test.That(tb, pos.Lng(), test.ShouldAlmostEqual, -74, 1e-6)
+		test.That(tb, alt, test.ShouldEqual, 12)
+	})
+
+	heading, err := ms.CompassHeading(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, heading, test.ShouldAlmostEqual, 45)
+
+	_, err = ms.AngularVelocity(ctx, nil)
+	test.That(t, err, test.ShouldBeError, movementsensor.ErrMethodUnimplementedAngularVelocity)
+
+	props, err := ms.Properties(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, props.PositionSupported, test.ShouldBeTrue)
+	test.That(t, props.OrientationSupported, test.ShouldBeTrue)
+	test.That(t, props.AngularVelocitySupported, test.ShouldBeFalse)
+
+	readings, err := ms.Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings["compass"], test.ShouldAlmostEqual, 45)
+	cov, ok := readings[positionCovarianceKey].([]interface{})
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, cov, test.ShouldHaveLength, 4)
+	test.That(t, cov[0], test.ShouldBeLessThan, defaultPositionStdDevM*defaultPositionStdDevM)
+	test.That(t, readings, test.ShouldContainKey, velocityCovarianceKey)
+	test.That(t, readings, test.ShouldContainKey, headingVarianceKey)
+
+	acc, err := ms.Accuracy(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, acc.CompassDegreeError, test.ShouldBeLessThan, defaultHeadingStdDevDegs)
+}
+
+func TestFusionDeadReckoning(t *testing.T) {
+	ctx := context.Background()
+	odometry := newInjectSensor("odometry", movementsensor.Properties{LinearVelocitySupported: true})
+	odometry.LinearVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+		return r3.Vector{Y: 1}, nil
+	}
+	imu := newInjectSensor("imu", movementsensor.Properties{AngularVelocitySupported: true})
+	imu.AngularVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+		return spatialmath.AngularVelocity{}, nil
+	}
+	ms := newTestFusion(t, &Config{
+		LinearVelocity:  []string{"odometry"},
+		AngularVelocity: []string{"imu"},
+		UpdateRateHz:    100,
+	}, odometry, imu)
+
+	// without a heading sensor the initial heading is north
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		pos, _, err := ms.Position(ctx, nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, pos.Lat(), test.ShouldBeGreaterThan, 0)
+		vel, err := ms.LinearVelocity(ctx, nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, vel.Y, test.ShouldAlmostEqual, 1, 0.01)
+	})
+	pos, _, err := ms.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos.Lng(), test.ShouldAlmostEqual, 0)
+
+	_, err = ms.CompassHeading(ctx, nil)
+	test.That(t, err, test.ShouldBeError, movementsensor.ErrMethodUnimplementedCompassHeading)
+}
+
+func TestFusionUnsupportedSensor(t *testing.T) {
+	gps := newInjectSensor("gps", movementsensor.Properties{})
+	_, err := newFusion(context.Background(), resource.Dependencies{gps.Name(): gps}, resource.Config{
+		Name:                "fused",
+		API:                 movementsensor.API,
+		Model:               Model,
+		ConvertedAttributes: &Config{Position: []string{"gps"}},
+	}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "does not support position")
+}
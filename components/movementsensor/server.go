@@ -8,18 +8,20 @@ import (
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/movementsensor/v1"
 
+	"go.viam.com/rdk/components/sensor"
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
 )
 
 type serviceServer struct {
 	pb.UnimplementedMovementSensorServiceServer
-	coll resource.APIResourceCollection[MovementSensor]
+	coll    resource.APIResourceCollection[MovementSensor]
+	streams *sensor.ReadingsStreams
 }
 
 // NewRPCServiceServer constructs an MovementSensor gRPC service serviceServer.
 func NewRPCServiceServer(coll resource.APIResourceCollection[MovementSensor]) interface{} {
-	return &serviceServer{coll: coll}
+	return &serviceServer{coll: coll, streams: sensor.NewReadingsStreams()}
 }
 
 // GetReadings returns the most recent readings from the given Sensor.
@@ -196,5 +198,8 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := sensor.DoStreamReadingsCommand(ctx, s.streams, msDevice, req); handled {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, msDevice, req)
 }
@@ -5,6 +5,7 @@ import (
 	"math"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/golang/geo/r3"
 	"go.viam.com/test"
@@ -118,6 +119,39 @@ func TestClient(t *testing.T) {
 		poseTester(t, bodyToPoseInFrame, nonZeroPoseBody2)
 	})
 
+	t.Run("tracked poses", func(t *testing.T) {
+		tracker, ok := workingPTClient.(posetracker.TrackedPoser)
+		test.That(t, ok, test.ShouldBeTrue)
+
+		// without TrackedPoses, the poses are reported as observed now
+		tracked, err := tracker.TrackedPoses(context.Background(), []string{nonZeroPoseBody}, map[string]interface{}{"foo": "Tracked"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, extraOptions, test.ShouldResemble, map[string]interface{}{"foo": "Tracked"})
+		test.That(t, tracked[nonZeroPoseBody].Confidence, test.ShouldEqual, 1)
+		test.That(t, time.Since(tracked[nonZeroPoseBody].Time), test.ShouldBeLessThan, time.Second)
+		poseTester(t, referenceframe.FrameSystemPoses{nonZeroPoseBody: tracked[nonZeroPoseBody].Pose}, nonZeroPoseBody)
+
+		observedAt := time.Unix(1700000000, 0)
+		workingPT.TrackedPosesFunc = func(ctx context.Context, bodyNames []string, extra map[string]interface{}) (
+			map[string]posetracker.TrackedPose, error,
+		) {
+			test.That(t, bodyNames, test.ShouldResemble, []string{nonZeroPoseBody2})
+			return map[string]posetracker.TrackedPose{nonZeroPoseBody2: {
+				Pose:       allBodiesToPoseInFrames[nonZeroPoseBody2],
+				Time:       observedAt,
+				Confidence: 0.25,
+				Predicted:  true,
+			}}, nil
+		}
+		defer func() { workingPT.TrackedPosesFunc = nil }()
+		tracked, err = tracker.TrackedPoses(context.Background(), []string{nonZeroPoseBody2}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, tracked[nonZeroPoseBody2].Time.Equal(observedAt), test.ShouldBeTrue)
+		test.That(t, tracked[nonZeroPoseBody2].Confidence, test.ShouldEqual, 0.25)
+		test.That(t, tracked[nonZeroPoseBody2].Predicted, test.ShouldBeTrue)
+		poseTester(t, referenceframe.FrameSystemPoses{nonZeroPoseBody2: tracked[nonZeroPoseBody2].Pose}, nonZeroPoseBody2)
+	})
+
 	t.Run("dialed client tests for working pose tracker", func(t *testing.T) {
 		conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
 		test.That(t, err, test.ShouldBeNil)
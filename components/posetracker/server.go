@@ -52,5 +52,8 @@ func (server *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := doTrackingCommand(ctx, poseTracker, req); handled {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, poseTracker, req)
 }
@@ -0,0 +1,312 @@
+package posetracker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	vprotoutils "go.viam.com/utils/protoutils"
+	"gonum.org/v1/gonum/num/quat"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// The pose tracker proto only returns poses. Tracked poses are carried over DoCommand using the
+// following reserved keys.
+const (
+	getTrackedPosesKey = "get_tracked_poses"
+	bodyNamesKey       = "body_names"
+	bodiesKey          = "bodies"
+	frameKey           = "frame"
+	poseKey            = "pose"
+	timeUnixNanosKey   = "time_unix_nanos"
+	confidenceKey      = "confidence"
+	predictedKey       = "predicted"
+	extraKey           = "extra"
+)
+
+// TrackedPose is the pose of a body along with when it was observed and how certain the tracker is
+// of it.
+type TrackedPose struct {
+	Pose *referenceframe.PoseInFrame
+	// Time is when the pose was observed, or predicted for.
+	Time time.Time
+	// Confidence is how certain the tracker is of the pose, from 0 to 1.
+	Confidence float64
+	// Predicted is whether the pose was extrapolated rather than observed.
+	Predicted bool
+}
+
+// A TrackedPoser is a pose tracker that reports when each body was last observed and its
+// confidence, so that consumers can tell stale poses from fresh ones.
+//
+// TrackedPoses example:
+//
+//	myPoseTracker, err := posetracker.FromRobot(machine, "my_pose_tracker")
+//	if tracker, ok := myPoseTracker.(posetracker.TrackedPoser); ok {
+//		bodies, err := tracker.TrackedPoses(context.Background(), []string{"body1"}, nil)
+//		if time.Since(bodies["body1"].Time) > time.Second {
+//			logger.Warn("lost track of body1")
+//		}
+//	}
+type TrackedPoser interface {
+	// TrackedPoses returns the tracked poses of the named bodies, or of all bodies if no names
+	// are given.
+	TrackedPoses(ctx context.Context, bodyNames []string, extra map[string]interface{}) (map[string]TrackedPose, error)
+}
+
+// GetTrackedPoses returns the tracked poses of `pt`. Pose trackers that do not implement
+// TrackedPoser report their poses as observed now with full confidence.
+func GetTrackedPoses(
+	ctx context.Context, pt PoseTracker, bodyNames []string, extra map[string]interface{},
+) (map[string]TrackedPose, error) {
+	if tracker, ok := pt.(TrackedPoser); ok {
+		return tracker.TrackedPoses(ctx, bodyNames, extra)
+	}
+	poses, err := pt.Poses(ctx, bodyNames, extra)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tracked := make(map[string]TrackedPose, len(poses))
+	for name, pose := range poses {
+		tracked[name] = TrackedPose{Pose: pose, Time: now, Confidence: 1}
+	}
+	return tracked, nil
+}
+
+// WithPrediction returns `pt` extended to predict the poses of bodies it loses track of, for up to
+// `horizon` after they were last observed. A body whose timestamp has not advanced since the last
+// call, or that is missing, is extrapolated from its last two observations assuming constant linear
+// and angular velocity, with its confidence falling to zero over the horizon. Bodies observed only
+// once are held in place. Past the horizon, missing bodies are dropped.
+func WithPrediction(pt PoseTracker, horizon time.Duration) PoseTracker {
+	return &predictingPoseTracker{
+		PoseTracker: pt,
+		horizon:     horizon,
+		history:     map[string]*bodyHistory{},
+		now:         time.Now,
+	}
+}
+
+type bodyHistory struct {
+	previous, latest TrackedPose
+	hasPrevious      bool
+}
+
+type predictingPoseTracker struct {
+	PoseTracker
+	horizon time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	history map[string]*bodyHistory
+}
+
+func (p *predictingPoseTracker) Poses(
+	ctx context.Context, bodyNames []string, extra map[string]interface{},
+) (referenceframe.FrameSystemPoses, error) {
+	tracked, err := p.TrackedPoses(ctx, bodyNames, extra)
+	if err != nil {
+		return nil, err
+	}
+	poses := referenceframe.FrameSystemPoses{}
+	for name, body := range tracked {
+		poses[name] = body.Pose
+	}
+	return poses, nil
+}
+
+func (p *predictingPoseTracker) TrackedPoses(
+	ctx context.Context, bodyNames []string, extra map[string]interface{},
+) (map[string]TrackedPose, error) {
+	observed, err := GetTrackedPoses(ctx, p.PoseTracker, bodyNames, extra)
+	if err != nil {
+		return nil, err
+	}
+	now := p.now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	wanted := map[string]bool{}
+	for _, name := range bodyNames {
+		wanted[name] = true
+	}
+	isWanted := func(name string) bool {
+		return len(bodyNames) == 0 || wanted[name]
+	}
+
+	tracked := make(map[string]TrackedPose, len(observed))
+	for name, body := range observed {
+		h, ok := p.history[name]
+		if !ok {
+			h = &bodyHistory{latest: body}
+			p.history[name] = h
+		} else if body.Time.After(h.latest.Time) {
+			h.previous, h.latest, h.hasPrevious = h.latest, body, true
+		} else {
+			continue
+		}
+		if isWanted(name) {
+			tracked[name] = body
+		}
+	}
+
+	for name, h := range p.history {
+		if _, ok := tracked[name]; ok || !isWanted(name) {
+			continue
+		}
+		if now.Sub(h.latest.Time) > p.horizon {
+			// past the horizon, report the stale pose as the tracker does, or drop a missing body
+			if body, ok := observed[name]; ok {
+				tracked[name] = body
+			} else {
+				delete(p.history, name)
+			}
+			continue
+		}
+		tracked[name] = h.predict(now, p.horizon)
+	}
+	return tracked, nil
+}
+
+// predict extrapolates the body's pose to `at`.
+func (h *bodyHistory) predict(at time.Time, horizon time.Duration) TrackedPose {
+	age := at.Sub(h.latest.Time)
+	predicted := TrackedPose{
+		Pose:       h.latest.Pose,
+		Time:       at,
+		Confidence: h.latest.Confidence,
+		Predicted:  true,
+	}
+	if horizon > 0 {
+		predicted.Confidence *= 1 - float64(age)/float64(horizon)
+	}
+	dt := h.latest.Time.Sub(h.previous.Time)
+	if !h.hasPrevious || dt <= 0 || h.previous.Pose.Parent() != h.latest.Pose.Parent() {
+		return predicted
+	}
+	predicted.Pose = referenceframe.NewPoseInFrame(
+		h.latest.Pose.Parent(),
+		extrapolate(h.previous.Pose.Pose(), h.latest.Pose.Pose(), float64(age)/float64(dt)),
+	)
+	return predicted
+}
+
+// extrapolate continues the motion from `p1` to `p2` past `p2` by `by` times that motion, keeping
+// its linear and angular velocity constant.
+func extrapolate(p1, p2 spatialmath.Pose, by float64) spatialmath.Pose {
+	point := p2.Point().Add(p2.Point().Sub(p1.Point()).Mul(by))
+	q1, q2 := p1.Orientation().Quaternion(), p2.Orientation().Quaternion()
+	delta := quat.Mul(q2, quat.Conj(q1))
+	if delta.Real < 0 {
+		// take the shorter way around
+		delta = quat.Scale(-1, delta)
+	}
+	rotation := spatialmath.QuatToR4AA(delta)
+	rotation.Theta *= by
+	orientation := spatialmath.Quaternion(quat.Mul(rotation.ToQuat(), q2))
+	return spatialmath.NewPose(point, &orientation)
+}
+
+// TrackedPoses returns the tracked poses of the remote pose tracker, or its poses as observed now
+// if it does not implement TrackedPoser.
+func (c *client) TrackedPoses(
+	ctx context.Context, bodyNames []string, extra map[string]interface{},
+) (map[string]TrackedPose, error) {
+	names := make([]interface{}, 0, len(bodyNames))
+	for _, name := range bodyNames {
+		names = append(names, name)
+	}
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		getTrackedPosesKey: map[string]interface{}{bodyNamesKey: names, extraKey: extra},
+	})
+	if err != nil {
+		return nil, err
+	}
+	bodies, ok := resp[bodiesKey].(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected %q in response, got %v", bodiesKey, resp)
+	}
+	tracked := make(map[string]TrackedPose, len(bodies))
+	for name, b := range bodies {
+		body, ok := b.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("body %q must be an object", name)
+		}
+		frame, _ := body[frameKey].(string)               //nolint:errcheck
+		pose, _ := body[poseKey].(map[string]interface{}) //nolint:errcheck
+		nanos, _ := body[timeUnixNanosKey].(float64)      //nolint:errcheck
+		confidence, _ := body[confidenceKey].(float64)    //nolint:errcheck
+		predicted, _ := body[predictedKey].(bool)         //nolint:errcheck
+		tracked[name] = TrackedPose{
+			Pose:       referenceframe.NewPoseInFrame(frame, poseFromMap(pose)),
+			Time:       time.Unix(0, int64(nanos)),
+			Confidence: confidence,
+			Predicted:  predicted,
+		}
+	}
+	return tracked, nil
+}
+
+// doTrackingCommand handles the reserved tracked pose DoCommand keys. It returns false if `req` is
+// not a tracked pose command.
+func doTrackingCommand(
+	ctx context.Context, pt PoseTracker, req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	payload, ok := req.GetCommand().AsMap()[getTrackedPosesKey]
+	if !ok {
+		return nil, false, nil
+	}
+	args, _ := payload.(map[string]interface{})         //nolint:errcheck
+	names, _ := args[bodyNamesKey].([]interface{})      //nolint:errcheck
+	extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+	bodyNames := make([]string, 0, len(names))
+	for _, name := range names {
+		if s, ok := name.(string); ok {
+			bodyNames = append(bodyNames, s)
+		}
+	}
+	tracked, err := GetTrackedPoses(ctx, pt, bodyNames, extra)
+	if err != nil {
+		return nil, true, err
+	}
+	bodies := make(map[string]interface{}, len(tracked))
+	for name, body := range tracked {
+		bodies[name] = map[string]interface{}{
+			frameKey:         body.Pose.Parent(),
+			poseKey:          poseToMap(body.Pose.Pose()),
+			timeUnixNanosKey: float64(body.Time.UnixNano()),
+			confidenceKey:    body.Confidence,
+			predictedKey:     body.Predicted,
+		}
+	}
+	res, err := vprotoutils.StructToStructPb(map[string]interface{}{bodiesKey: bodies})
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
+
+func poseToMap(pose spatialmath.Pose) map[string]interface{} {
+	p := spatialmath.PoseToProtobuf(pose)
+	return map[string]interface{}{
+		"x": p.X, "y": p.Y, "z": p.Z,
+		"o_x": p.OX, "o_y": p.OY, "o_z": p.OZ, "theta": p.Theta,
+	}
+}
+
+func poseFromMap(m map[string]interface{}) spatialmath.Pose {
+	get := func(key string) float64 {
+		v, _ := m[key].(float64) //nolint:errcheck
+		return v
+	}
+	return spatialmath.NewPose(
+		r3.Vector{X: get("x"), Y: get("y"), Z: get("z")},
+		&spatialmath.OrientationVectorDegrees{OX: get("o_x"), OY: get("o_y"), OZ: get("o_z"), Theta: get("theta")},
+	)
+}
@@ -0,0 +1,109 @@
+package posetracker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+type fakeTracker struct {
+	resource.Named
+	resource.TriviallyReconfigurable
+	resource.TriviallyCloseable
+	bodies map[string]TrackedPose
+}
+
+func (f *fakeTracker) Poses(
+	ctx context.Context, bodyNames []string, extra map[string]interface{},
+) (referenceframe.FrameSystemPoses, error) {
+	return nil, nil
+}
+
+func (f *fakeTracker) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (f *fakeTracker) TrackedPoses(
+	ctx context.Context, bodyNames []string, extra map[string]interface{},
+) (map[string]TrackedPose, error) {
+	out := map[string]TrackedPose{}
+	for name, body := range f.bodies {
+		out[name] = body
+	}
+	return out, nil
+}
+
+func TestPrediction(t *testing.T) {
+	start := time.Unix(1000, 0)
+	now := start
+	observe := func(x float64, yawDeg float64, at time.Time) TrackedPose {
+		return TrackedPose{
+			Pose: referenceframe.NewPoseInFrame("world", spatialmath.NewPose(
+				r3.Vector{X: x},
+				&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: yawDeg},
+			)),
+			Time:       at,
+			Confidence: 0.8,
+		}
+	}
+	tracker := &fakeTracker{bodies: map[string]TrackedPose{"ball": observe(0, 0, start)}}
+	predictor := WithPrediction(tracker, time.Second).(*predictingPoseTracker)
+	predictor.now = func() time.Time { return now }
+
+	tracked, err := predictor.TrackedPoses(context.Background(), nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tracked["ball"].Predicted, test.ShouldBeFalse)
+
+	// moving at 100mm/s and 10deg/s
+	now = start.Add(100 * time.Millisecond)
+	tracker.bodies["ball"] = observe(10, 1, now)
+	tracked, err = predictor.TrackedPoses(context.Background(), nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tracked["ball"].Predicted, test.ShouldBeFalse)
+	test.That(t, tracked["ball"].Pose.Pose().Point().X, test.ShouldAlmostEqual, 10)
+
+	// the body's timestamp stops advancing, so it is extrapolated
+	now = start.Add(600 * time.Millisecond)
+	tracked, err = predictor.TrackedPoses(context.Background(), nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	ball := tracked["ball"]
+	test.That(t, ball.Predicted, test.ShouldBeTrue)
+	test.That(t, ball.Time, test.ShouldEqual, now)
+	test.That(t, ball.Confidence, test.ShouldAlmostEqual, 0.8*0.5)
+	test.That(t, ball.Pose.Parent(), test.ShouldEqual, "world")
+	test.That(t, ball.Pose.Pose().Point().X, test.ShouldAlmostEqual, 60, 1e-6)
+	test.That(t, ball.Pose.Pose().Orientation().OrientationVectorDegrees().Theta, test.ShouldAlmostEqual, 6, 1e-6)
+
+	// a missing body is extrapolated too, and only the requested bodies are returned
+	delete(tracker.bodies, "ball")
+	tracker.bodies["cup"] = observe(0, 0, now)
+	now = start.Add(800 * time.Millisecond)
+	tracked, err = predictor.TrackedPoses(context.Background(), []string{"ball"}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tracked["ball"].Pose.Pose().Point().X, test.ShouldAlmostEqual, 80, 1e-6)
+	_, ok := tracked["cup"]
+	test.That(t, ok, test.ShouldBeFalse)
+
+	// past the horizon, the missing body is dropped and the cup, seen once, is held in place
+	now = start.Add(1200 * time.Millisecond)
+	tracked, err = predictor.TrackedPoses(context.Background(), nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	_, ok = tracked["ball"]
+	test.That(t, ok, test.ShouldBeFalse)
+	test.That(t, tracked["cup"].Predicted, test.ShouldBeTrue)
+	test.That(t, tracked["cup"].Pose.Pose().Point().X, test.ShouldAlmostEqual, 0)
+
+	// past the horizon, a stale body still reported by the tracker is returned as it was observed
+	now = start.Add(2 * time.Second)
+	tracked, err = predictor.TrackedPoses(context.Background(), nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tracked["cup"].Predicted, test.ShouldBeFalse)
+	test.That(t, tracked["cup"].Time, test.ShouldEqual, start.Add(600*time.Millisecond))
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
 	"go.viam.com/test"
 	"go.viam.com/utils/rpc"
@@ -46,6 +47,21 @@ func TestClient(t *testing.T) {
 		return testWatts, nil
 	}
 
+	testEnergy := powersensor.EnergyStats{
+		EnergyWh:     1.5,
+		PeakWatts:    12,
+		AverageWatts: 3,
+		Since:        time.Unix(1700000000, 0),
+	}
+	var energyReset bool
+	workingPowerSensor.EnergyFunc = func(ctx context.Context, extra map[string]interface{}) (powersensor.EnergyStats, error) {
+		return testEnergy, nil
+	}
+	workingPowerSensor.ResetEnergyFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		energyReset = true
+		return nil
+	}
+
 	failingPowerSensor.VoltageFunc = func(ctx context.Context, extra map[string]interface{}) (float64, bool, error) {
 		return 0, false, errVoltageFailed
 	}
@@ -112,6 +128,17 @@ func TestClient(t *testing.T) {
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, watts, test.ShouldEqual, testWatts)
 
+		meter, ok := client.(powersensor.EnergyMeter)
+		test.That(t, ok, test.ShouldBeTrue)
+		energy, err := meter.Energy(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, energy.EnergyWh, test.ShouldEqual, testEnergy.EnergyWh)
+		test.That(t, energy.PeakWatts, test.ShouldEqual, testEnergy.PeakWatts)
+		test.That(t, energy.AverageWatts, test.ShouldEqual, testEnergy.AverageWatts)
+		test.That(t, energy.Since.Equal(testEnergy.Since), test.ShouldBeTrue)
+		test.That(t, meter.ResetEnergy(context.Background(), nil), test.ShouldBeNil)
+		test.That(t, energyReset, test.ShouldBeTrue)
+
 		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
@@ -0,0 +1,257 @@
+package powersensor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	goutils "go.viam.com/utils"
+	"go.viam.com/utils/protoutils"
+
+	"go.viam.com/rdk/logging"
+)
+
+// The power sensor proto has no energy RPCs. Energy commands are carried over DoCommand using the
+// following reserved keys.
+const (
+	getEnergyKey      = "get_energy"
+	resetEnergyKey    = "reset_energy"
+	energyWhKey       = "energy_wh"
+	peakWattsKey      = "peak_watts"
+	averageWattsKey   = "average_watts"
+	sinceUnixNanosKey = "since_unix_nanos"
+	extraKey          = "extra"
+)
+
+const defaultEnergyRateHz = 10
+
+// EnergyStats are the statistics of the power measured since the last reset.
+type EnergyStats struct {
+	// EnergyWh is the energy consumed in watt-hours.
+	EnergyWh float64
+	// PeakWatts is the highest power measured.
+	PeakWatts float64
+	// AverageWatts is the energy divided by the time elapsed.
+	AverageWatts float64
+	// Since is when the statistics were last reset, or when measuring started.
+	Since time.Time
+}
+
+// EnergyMeter is implemented by power sensors that integrate their power readings over time, so
+// that the energy drawn from a battery can be budgeted.
+//
+// Energy example:
+//
+//	myPowerSensor, err := powersensor.FromRobot(machine, "my_power_sensor")
+//	if meter, ok := myPowerSensor.(powersensor.EnergyMeter); ok {
+//		stats, err := meter.Energy(context.Background(), nil)
+//		logger.Infof("used %.2f Wh since %v, peaking at %.1f W", stats.EnergyWh, stats.Since, stats.PeakWatts)
+//
+//		// Start measuring from zero again, for example after swapping batteries.
+//		err = meter.ResetEnergy(context.Background(), nil)
+//	}
+type EnergyMeter interface {
+	// Energy returns the energy and power statistics since the last reset.
+	Energy(ctx context.Context, extra map[string]interface{}) (EnergyStats, error)
+
+	// ResetEnergy restarts the statistics from zero.
+	ResetEnergy(ctx context.Context, extra map[string]interface{}) error
+}
+
+// EnergyAccumulator integrates power samples into energy statistics using the trapezoidal rule.
+type EnergyAccumulator struct {
+	mu        sync.Mutex
+	since     time.Time
+	last      time.Time
+	lastWatts float64
+	hasLast   bool
+	joules    float64
+	peakWatts float64
+}
+
+// NewEnergyAccumulator returns an EnergyAccumulator measuring from `start`.
+func NewEnergyAccumulator(start time.Time) *EnergyAccumulator {
+	return &EnergyAccumulator{since: start}
+}
+
+// Add adds a power sample in watts taken at time `t`. Samples must be added in time order.
+func (a *EnergyAccumulator) Add(t time.Time, watts float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.hasLast && t.After(a.last) {
+		a.joules += (watts + a.lastWatts) / 2 * t.Sub(a.last).Seconds()
+	}
+	if !a.hasLast || watts > a.peakWatts {
+		a.peakWatts = watts
+	}
+	a.last, a.lastWatts, a.hasLast = t, watts, true
+}
+
+// Reset restarts the statistics from zero at time `t`.
+func (a *EnergyAccumulator) Reset(t time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.since = t
+	a.joules = 0
+	a.peakWatts = 0
+	a.hasLast = false
+}
+
+// Stats returns the statistics up to the last sample.
+func (a *EnergyAccumulator) Stats() EnergyStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stats := EnergyStats{
+		EnergyWh:  a.joules / 3600,
+		PeakWatts: a.peakWatts,
+		Since:     a.since,
+	}
+	if elapsed := a.last.Sub(a.since).Seconds(); a.hasLast && elapsed > 0 {
+		stats.AverageWatts = a.joules / elapsed
+	}
+	return stats
+}
+
+// energyFTDCStats are the energy statistics recorded by FTDC.
+type energyFTDCStats struct {
+	EnergyWh     float64
+	PeakWatts    float64
+	AverageWatts float64
+}
+
+// EnergyMonitor samples a power sensor's power in the background and accumulates its energy
+// statistics. Power sensor models embed it to implement EnergyMeter and to have the statistics
+// recorded by FTDC.
+type EnergyMonitor struct {
+	acc     *EnergyAccumulator
+	workers *goutils.StoppableWorkers
+}
+
+// NewEnergyMonitor starts sampling `power` every `interval`, or at 10Hz if zero. Stop must be called
+// when the power sensor is closed.
+func NewEnergyMonitor(
+	power func(ctx context.Context) (float64, error),
+	interval time.Duration,
+	logger logging.Logger,
+) *EnergyMonitor {
+	if interval == 0 {
+		interval = time.Second / defaultEnergyRateHz
+	}
+	m := &EnergyMonitor{acc: NewEnergyAccumulator(time.Now())}
+	m.workers = goutils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			watts, err := power(ctx)
+			if err == nil {
+				m.acc.Add(time.Now(), watts)
+			} else if ctx.Err() == nil {
+				logger.CDebugw(ctx, "error sampling power for energy statistics", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+	return m
+}
+
+// Energy returns the energy statistics since the last reset.
+func (m *EnergyMonitor) Energy(ctx context.Context, extra map[string]interface{}) (EnergyStats, error) {
+	return m.acc.Stats(), nil
+}
+
+// ResetEnergy restarts the energy statistics from zero.
+func (m *EnergyMonitor) ResetEnergy(ctx context.Context, extra map[string]interface{}) error {
+	m.acc.Reset(time.Now())
+	return nil
+}
+
+// Stats returns the energy statistics for FTDC.
+func (m *EnergyMonitor) Stats() any {
+	stats := m.acc.Stats()
+	return energyFTDCStats{
+		EnergyWh:     stats.EnergyWh,
+		PeakWatts:    stats.PeakWatts,
+		AverageWatts: stats.AverageWatts,
+	}
+}
+
+// Stop stops sampling.
+func (m *EnergyMonitor) Stop() {
+	m.workers.Stop()
+}
+
+func (c *client) Energy(ctx context.Context, extra map[string]interface{}) (EnergyStats, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		getEnergyKey: map[string]interface{}{extraKey: extra},
+	})
+	if err != nil {
+		return EnergyStats{}, err
+	}
+	energy, ok := resp[energyWhKey].(float64)
+	if !ok {
+		return EnergyStats{}, errors.Errorf("expected %q in response, got %v", energyWhKey, resp)
+	}
+	peak, _ := resp[peakWattsKey].(float64)       //nolint:errcheck
+	average, _ := resp[averageWattsKey].(float64) //nolint:errcheck
+	nanos, _ := resp[sinceUnixNanosKey].(float64) //nolint:errcheck
+	return EnergyStats{
+		EnergyWh:     energy,
+		PeakWatts:    peak,
+		AverageWatts: average,
+		Since:        time.Unix(0, int64(nanos)),
+	}, nil
+}
+
+func (c *client) ResetEnergy(ctx context.Context, extra map[string]interface{}) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		resetEnergyKey: map[string]interface{}{extraKey: extra},
+	})
+	return err
+}
+
+// doEnergyCommand handles the reserved energy DoCommand keys. It returns false if `req` is not an
+// energy command or the power sensor does not implement EnergyMeter.
+func doEnergyCommand(ctx context.Context, ps PowerSensor, req *commonpb.DoCommandRequest) (*commonpb.DoCommandResponse, bool, error) {
+	meter, ok := ps.(EnergyMeter)
+	if !ok {
+		return nil, false, nil
+	}
+	cmd := req.GetCommand().AsMap()
+	if payload, ok := cmd[getEnergyKey]; ok {
+		args, _ := payload.(map[string]interface{})         //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		stats, err := meter.Energy(ctx, extra)
+		if err != nil {
+			return nil, true, err
+		}
+		return doCommandResponse(map[string]interface{}{
+			energyWhKey:       stats.EnergyWh,
+			peakWattsKey:      stats.PeakWatts,
+			averageWattsKey:   stats.AverageWatts,
+			sinceUnixNanosKey: float64(stats.Since.UnixNano()),
+		})
+	}
+	if payload, ok := cmd[resetEnergyKey]; ok {
+		args, _ := payload.(map[string]interface{})         //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		if err := meter.ResetEnergy(ctx, extra); err != nil {
+			return nil, true, err
+		}
+		return doCommandResponse(map[string]interface{}{})
+	}
+	return nil, false, nil
+}
+
+func doCommandResponse(result map[string]interface{}) (*commonpb.DoCommandResponse, bool, error) {
+	res, err := protoutils.StructToStructPb(result)
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
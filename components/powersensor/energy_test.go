@@ -0,0 +1,62 @@
+package powersensor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/powersensor"
+	"go.viam.com/rdk/logging"
+)
+
+func TestEnergyAccumulator(t *testing.T) {
+	start := time.Now()
+	acc := powersensor.NewEnergyAccumulator(start)
+	stats := acc.Stats()
+	test.That(t, stats.EnergyWh, test.ShouldEqual, 0)
+	test.That(t, stats.AverageWatts, test.ShouldEqual, 0)
+	test.That(t, stats.Since, test.ShouldEqual, start)
+
+	// ramping from 0W to 20W over an hour, then holding 20W for an hour
+	acc.Add(start, 0)
+	acc.Add(start.Add(time.Hour), 20)
+	acc.Add(start.Add(2*time.Hour), 20)
+	stats = acc.Stats()
+	test.That(t, stats.EnergyWh, test.ShouldAlmostEqual, 30)
+	test.That(t, stats.PeakWatts, test.ShouldEqual, 20)
+	test.That(t, stats.AverageWatts, test.ShouldAlmostEqual, 15)
+
+	resetAt := start.Add(2 * time.Hour)
+	acc.Reset(resetAt)
+	acc.Add(resetAt.Add(time.Minute), 5)
+	acc.Add(resetAt.Add(2*time.Minute), 5)
+	stats = acc.Stats()
+	test.That(t, stats.EnergyWh, test.ShouldAlmostEqual, 5.0/60)
+	test.That(t, stats.PeakWatts, test.ShouldEqual, 5)
+	test.That(t, stats.Since, test.ShouldEqual, resetAt)
+}
+
+func TestEnergyMonitor(t *testing.T) {
+	monitor := powersensor.NewEnergyMonitor(func(ctx context.Context) (float64, error) {
+		return 3600, nil
+	}, time.Millisecond, logging.NewTestLogger(t))
+	defer monitor.Stop()
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		stats, err := monitor.Energy(context.Background(), nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, stats.EnergyWh, test.ShouldBeGreaterThan, 0)
+		test.That(tb, stats.PeakWatts, test.ShouldEqual, 3600)
+	})
+	test.That(t, monitor.Stats(), test.ShouldNotBeNil)
+
+	before, err := monitor.Energy(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, monitor.ResetEnergy(context.Background(), nil), test.ShouldBeNil)
+	after, err := monitor.Energy(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, after.Since.After(before.Since), test.ShouldBeTrue)
+}
@@ -27,16 +27,21 @@ func init() {
 
 func newFakePowerSensorModel(_ context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger,
 ) (powersensor.PowerSensor, error) {
-	return powersensor.PowerSensor(&PowerSensor{
+	f := &PowerSensor{
 		Named:  conf.ResourceName().AsNamed(),
 		logger: logger,
-	}), nil
+	}
+	f.EnergyMonitor = powersensor.NewEnergyMonitor(func(ctx context.Context) (float64, error) {
+		return f.Power(ctx, nil)
+	}, 0, logger)
+	return f, nil
 }
 
 // PowerSensor implements a fake PowerSensor interface.
 type PowerSensor struct {
 	resource.Named
 	resource.AlwaysRebuild
+	*powersensor.EnergyMonitor
 	logger logging.Logger
 }
 
@@ -76,15 +81,22 @@ func (f *PowerSensor) Readings(ctx context.Context, extra map[string]interface{}
 	if err != nil {
 		f.logger.CErrorf(ctx, "failed to get power reading: %s", err.Error())
 	}
+
+	energy, err := f.Energy(ctx, nil)
+	if err != nil {
+		f.logger.CErrorf(ctx, "failed to get energy reading: %s", err.Error())
+	}
 	return map[string]interface{}{
-		"volts": volts,
-		"amps":  amps,
-		"is_ac": isAC,
-		"watts": watts,
+		"volts":     volts,
+		"amps":      amps,
+		"is_ac":     isAC,
+		"watts":     watts,
+		"energy_wh": energy.EnergyWh,
 	}, nil
 }
 
 // Close closes the fake powersensor.
 func (f *PowerSensor) Close(ctx context.Context) error {
+	f.Stop()
 	return nil
 }
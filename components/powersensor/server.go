@@ -105,5 +105,8 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := doEnergyCommand(ctx, psDevice, req); handled {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, psDevice, req)
 }
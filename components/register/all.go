@@ -3,20 +3,24 @@ package register
 
 import (
 	// register components.
+	_ "go.viam.com/rdk/components/audioout/register"
 	_ "go.viam.com/rdk/components/base/register"
 	_ "go.viam.com/rdk/components/board/register"
 	_ "go.viam.com/rdk/components/button/register"
 	_ "go.viam.com/rdk/components/camera/register"
+	_ "go.viam.com/rdk/components/display/register"
 	_ "go.viam.com/rdk/components/encoder/register"
 	_ "go.viam.com/rdk/components/gantry/register"
 	_ "go.viam.com/rdk/components/generic/register"
 	_ "go.viam.com/rdk/components/gripper/register"
 	_ "go.viam.com/rdk/components/input/register"
+	_ "go.viam.com/rdk/components/light/register"
 	_ "go.viam.com/rdk/components/motor/register"
 	_ "go.viam.com/rdk/components/movementsensor/register"
 	// register APIs without implementations directly.
 	_ "go.viam.com/rdk/components/posetracker"
 	_ "go.viam.com/rdk/components/powersensor/register"
+	_ "go.viam.com/rdk/components/relay/register"
 	_ "go.viam.com/rdk/components/sensor/register"
 	_ "go.viam.com/rdk/components/servo/register"
 	_ "go.viam.com/rdk/components/switch/register"
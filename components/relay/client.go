@@ -0,0 +1,118 @@
+package relay
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The relay commands are carried over DoCommand using the following reserved keys.
+const (
+	onKey           = "on"
+	offKey          = "off"
+	toggleKey       = "toggle"
+	pulseKey        = "pulse"
+	durationSecsKey = "duration_secs"
+	getStateKey     = "get_state"
+	isOnKey         = "is_on"
+	extraKey        = "extra"
+)
+
+// client implements Relay over the DoCommand of a resource that does not implement it, such as the
+// generic client of a remote relay.
+type client struct {
+	resource.Resource
+}
+
+// NewClientFromResource returns a Relay calling `res` over DoCommand.
+func NewClientFromResource(res resource.Resource) Relay {
+	return &client{Resource: res}
+}
+
+func (c *client) On(ctx context.Context, extra map[string]interface{}) error {
+	return c.command(ctx, onKey, extra)
+}
+
+func (c *client) Off(ctx context.Context, extra map[string]interface{}) error {
+	return c.command(ctx, offKey, extra)
+}
+
+func (c *client) Toggle(ctx context.Context, extra map[string]interface{}) error {
+	return c.command(ctx, toggleKey, extra)
+}
+
+func (c *client) command(ctx context.Context, key string, extra map[string]interface{}) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		key: map[string]interface{}{extraKey: extra},
+	})
+	return err
+}
+
+func (c *client) IsOn(ctx context.Context, extra map[string]interface{}) (bool, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		getStateKey: map[string]interface{}{extraKey: extra},
+	})
+	if err != nil {
+		return false, err
+	}
+	on, ok := resp[isOnKey].(bool)
+	if !ok {
+		return false, errors.Errorf("expected %q in response, got %v", isOnKey, resp)
+	}
+	return on, nil
+}
+
+func (c *client) Pulse(ctx context.Context, duration time.Duration, extra map[string]interface{}) error {
+	if err := validateDuration(duration); err != nil {
+		return err
+	}
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		pulseKey: map[string]interface{}{durationSecsKey: duration.Seconds(), extraKey: extra},
+	})
+	return err
+}
+
+// HandleDoCommand handles the reserved relay DoCommand keys. Models call it first from their
+// DoCommand, and handle `cmd` themselves if it returns false.
+func HandleDoCommand(ctx context.Context, r Relay, cmd map[string]interface{}) (map[string]interface{}, bool, error) {
+	for key, command := range map[string]func(context.Context, map[string]interface{}) error{
+		onKey:     r.On,
+		offKey:    r.Off,
+		toggleKey: r.Toggle,
+	} {
+		if payload, ok := cmd[key]; ok {
+			args, _ := payload.(map[string]interface{})         //nolint:errcheck
+			extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+			return map[string]interface{}{}, true, command(ctx, extra)
+		}
+	}
+	if payload, ok := cmd[pulseKey]; ok {
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", pulseKey)
+		}
+		secs, ok := args[durationSecsKey].(float64)
+		if !ok {
+			return nil, true, errors.Errorf("%q must be a number", durationSecsKey)
+		}
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		duration := time.Duration(secs * float64(time.Second))
+		if err := validateDuration(duration); err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{}, true, r.Pulse(ctx, duration, extra)
+	}
+	if payload, ok := cmd[getStateKey]; ok {
+		args, _ := payload.(map[string]interface{})         //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		on, err := r.IsOn(ctx, extra)
+		if err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{isOnKey: on}, true, nil
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,133 @@
+package relay
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+)
+
+// pulseEndTimeout bounds how long switching off the load at the end of a pulse may take.
+const pulseEndTimeout = 5 * time.Second
+
+// SetFunc switches the hardware's load on or off.
+type SetFunc func(ctx context.Context, on bool) error
+
+// GetFunc reads whether the hardware's load is on.
+type GetFunc func(ctx context.Context) (bool, error)
+
+// A Coil drives a relay's hardware and times its pulses. Relay models embed it to implement On,
+// Off, Toggle, IsOn and Pulse.
+type Coil struct {
+	set    SetFunc
+	get    GetFunc
+	logger logging.Logger
+
+	mu sync.Mutex
+	// pulse switches the load off at the end of the pulse in progress, if any.
+	pulse *time.Timer
+}
+
+// NewCoil returns a coil switching the load with `set` and reading it with `get`. Close must be
+// called when the relay is closed.
+func NewCoil(set SetFunc, get GetFunc, logger logging.Logger) *Coil {
+	return &Coil{set: set, get: get, logger: logger}
+}
+
+// Init switches the load to the configured initial state, if any.
+func (c *Coil) Init(ctx context.Context, conf Config) error {
+	switch conf.InitialState {
+	case InitialStateOn:
+		return c.On(ctx, nil)
+	case InitialStateOff:
+		return c.Off(ctx, nil)
+	default:
+		return nil
+	}
+}
+
+// On switches the load on, ending any pulse.
+func (c *Coil) On(ctx context.Context, extra map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.endPulse()
+	return c.set(ctx, true)
+}
+
+// Off switches the load off, ending any pulse.
+func (c *Coil) Off(ctx context.Context, extra map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.endPulse()
+	return c.set(ctx, false)
+}
+
+// Toggle switches the load off if it is on and on if it is off, ending any pulse.
+func (c *Coil) Toggle(ctx context.Context, extra map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.endPulse()
+	on, err := c.get(ctx)
+	if err != nil {
+		return err
+	}
+	return c.set(ctx, !on)
+}
+
+// IsOn returns whether the load is on.
+func (c *Coil) IsOn(ctx context.Context, extra map[string]interface{}) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.get(ctx)
+}
+
+// Pulse switches the load on, switching it off again after `duration` unless the coil is commanded
+// again first.
+func (c *Coil) Pulse(ctx context.Context, duration time.Duration, extra map[string]interface{}) error {
+	if err := validateDuration(duration); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.endPulse()
+	if err := c.set(ctx, true); err != nil {
+		return err
+	}
+	var pulse *time.Timer
+	pulse = time.AfterFunc(duration, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.pulse != pulse {
+			// the pulse was ended by another command
+			return
+		}
+		c.pulse = nil
+		ctx, cancel := context.WithTimeout(context.Background(), pulseEndTimeout)
+		defer cancel()
+		if err := c.set(ctx, false); err != nil {
+			c.logger.Errorw("error switching off relay at the end of a pulse", "error", err)
+		}
+	})
+	c.pulse = pulse
+	return nil
+}
+
+// Close ends any pulse in progress early, switching the load off, so that it is not left on.
+func (c *Coil) Close(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pulse == nil {
+		return nil
+	}
+	c.endPulse()
+	return c.set(ctx, false)
+}
+
+// endPulse cancels the end of the pulse in progress. It must be called with c.mu held.
+func (c *Coil) endPulse() {
+	if c.pulse != nil {
+		c.pulse.Stop()
+		c.pulse = nil
+	}
+}
@@ -0,0 +1,78 @@
+// Package fake implements a fake relay that keeps its state in memory.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"go.viam.com/rdk/components/relay"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("fake_relay")
+
+func init() {
+	resource.RegisterComponent(relay.API, model, resource.Registration[resource.Resource, *Config]{
+		Constructor: func(
+			ctx context.Context,
+			_ resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			newConf, err := resource.NativeConfig[*Config](conf)
+			if err != nil {
+				return nil, err
+			}
+			r := NewRelay(conf.ResourceName(), logger)
+			if err := r.Init(ctx, newConf.Config); err != nil {
+				return nil, err
+			}
+			return r, nil
+		},
+	})
+}
+
+// Config is used for converting config attributes.
+type Config struct {
+	relay.Config
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	return nil, conf.Config.Validate(path)
+}
+
+// Relay is a fake relay.
+type Relay struct {
+	resource.Named
+	resource.AlwaysRebuild
+	*relay.Coil
+
+	mu sync.Mutex
+	on bool
+}
+
+// NewRelay returns a fake relay that is off.
+func NewRelay(name resource.Name, logger logging.Logger) *Relay {
+	r := &Relay{Named: name.AsNamed()}
+	r.Coil = relay.NewCoil(func(ctx context.Context, on bool) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.on = on
+		return nil
+	}, func(ctx context.Context) (bool, error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.on, nil
+	}, logger)
+	return r
+}
+
+// DoCommand handles the relay commands.
+func (r *Relay) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, handled, err := relay.HandleDoCommand(ctx, r, cmd); handled {
+		return resp, err
+	}
+	return map[string]interface{}{}, nil
+}
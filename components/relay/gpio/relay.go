@@ -0,0 +1,83 @@
+// Package gpio implements a relay driven by a GPIO pin of a board, such as a relay module or a
+// transistor driving a contactor's coil.
+package gpio
+
+import (
+	"context"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/relay"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("gpio_relay")
+
+// Config is used for converting config attributes.
+type Config struct {
+	relay.Config
+	Board string `json:"board"`
+	Pin   string `json:"pin"`
+	// ActiveLow is whether the load is on while the pin is low, as with many relay modules.
+	ActiveLow bool `json:"active_low,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.Board == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "board")
+	}
+	if conf.Pin == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "pin")
+	}
+	if err := conf.Config.Validate(path); err != nil {
+		return nil, err
+	}
+	return []string{conf.Board}, nil
+}
+
+func init() {
+	resource.RegisterComponent(relay.API, model, resource.Registration[resource.Resource, *Config]{Constructor: newRelay})
+}
+
+type gpioRelay struct {
+	resource.Named
+	resource.AlwaysRebuild
+	*relay.Coil
+}
+
+func newRelay(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (resource.Resource, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	b, err := board.FromDependencies(deps, newConf.Board)
+	if err != nil {
+		return nil, err
+	}
+	pin, err := b.GPIOPinByName(newConf.Pin)
+	if err != nil {
+		return nil, err
+	}
+	r := &gpioRelay{
+		Named: conf.ResourceName().AsNamed(),
+		Coil: relay.NewCoil(func(ctx context.Context, on bool) error {
+			return pin.Set(ctx, on != newConf.ActiveLow, nil)
+		}, func(ctx context.Context) (bool, error) {
+			high, err := pin.Get(ctx, nil)
+			return high != newConf.ActiveLow, err
+		}, logger),
+	}
+	if err := r.Init(ctx, newConf.Config); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DoCommand handles the relay commands.
+func (r *gpioRelay) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, handled, err := relay.HandleDoCommand(ctx, r, cmd); handled {
+		return resp, err
+	}
+	return map[string]interface{}{}, nil
+}
@@ -0,0 +1,57 @@
+package gpio
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/relay"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestGPIORelay(t *testing.T) {
+	ctx := context.Background()
+	high := true
+	pin := &inject.GPIOPin{}
+	pin.SetFunc = func(ctx context.Context, h bool, extra map[string]interface{}) error {
+		high = h
+		return nil
+	}
+	pin.GetFunc = func(ctx context.Context, extra map[string]interface{}) (bool, error) {
+		return high, nil
+	}
+	b := inject.NewBoard("board")
+	b.GPIOPinByNameFunc = func(name string) (board.GPIOPin, error) {
+		test.That(t, name, test.ShouldEqual, "11")
+		return pin, nil
+	}
+	deps := resource.Dependencies{board.Named("board"): b}
+
+	conf := &Config{Config: relay.Config{InitialState: relay.InitialStateOff}, Board: "board", Pin: "11", ActiveLow: true}
+	deps2, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps2, test.ShouldResemble, []string{"board"})
+
+	res, err := newRelay(ctx, deps, resource.Config{Name: "pump", ConvertedAttributes: conf}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	r := res.(relay.Relay)
+	defer r.Close(ctx)
+	// active low, so off drives the pin high
+	test.That(t, high, test.ShouldBeTrue)
+
+	test.That(t, r.On(ctx, nil), test.ShouldBeNil)
+	test.That(t, high, test.ShouldBeFalse)
+	on, err := r.IsOn(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, on, test.ShouldBeTrue)
+
+	// the state is read back from the pin
+	high = true
+	on, err = r.IsOn(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, on, test.ShouldBeFalse)
+}
@@ -0,0 +1,163 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The Modbus function codes used by relays.
+const (
+	funcReadCoils       = 0x01
+	funcWriteSingleCoil = 0x05
+	exceptionBit        = 0x80
+)
+
+// exceptionNames are the standard Modbus exception codes.
+var exceptionNames = map[byte]string{
+	0x01: "illegal function",
+	0x02: "illegal data address",
+	0x03: "illegal data value",
+	0x04: "server device failure",
+	0x06: "server device busy",
+	0x0b: "gateway target device failed to respond",
+}
+
+// tcpClient is a Modbus TCP client for a single unit. It connects when first used, and reconnects
+// after any error.
+type tcpClient struct {
+	address string
+	unitID  byte
+	timeout time.Duration
+
+	mu            sync.Mutex
+	conn          net.Conn
+	transactionID uint16
+}
+
+// readCoil returns the state of a coil.
+func (c *tcpClient) readCoil(ctx context.Context, coil uint16) (bool, error) {
+	resp, err := c.request(ctx, []byte{funcReadCoils, byte(coil >> 8), byte(coil), 0, 1})
+	if err != nil {
+		return false, err
+	}
+	if len(resp) != 3 || resp[1] != 1 {
+		return false, errors.Errorf("unexpected read coils response % x", resp)
+	}
+	return resp[2]&1 == 1, nil
+}
+
+// writeCoil sets the state of a coil.
+func (c *tcpClient) writeCoil(ctx context.Context, coil uint16, on bool) error {
+	value := byte(0x00)
+	if on {
+		value = 0xff
+	}
+	req := []byte{funcWriteSingleCoil, byte(coil >> 8), byte(coil), value, 0}
+	resp, err := c.request(ctx, req)
+	if err != nil {
+		return err
+	}
+	if string(resp) != string(req) {
+		return errors.Errorf("unexpected write coil response % x", resp)
+	}
+	return nil
+}
+
+// request sends the protocol data unit of a request and returns that of the response.
+func (c *tcpClient) request(ctx context.Context, pdu []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp, err := c.exchange(ctx, pdu)
+	if err != nil {
+		// the connection may be out of step with the server, so start again
+		c.closeConn()
+		return nil, err
+	}
+	if resp[0] == pdu[0]|exceptionBit {
+		if len(resp) < 2 {
+			return nil, errors.New("truncated Modbus exception response")
+		}
+		name, ok := exceptionNames[resp[1]]
+		if !ok {
+			name = "unknown exception"
+		}
+		return nil, errors.Errorf("Modbus exception %#02x: %s", resp[1], name)
+	}
+	if resp[0] != pdu[0] {
+		return nil, errors.Errorf("Modbus response is for function %#02x, not %#02x", resp[0], pdu[0])
+	}
+	return resp, nil
+}
+
+// exchange must be called with c.mu held.
+func (c *tcpClient) exchange(ctx context.Context, pdu []byte) ([]byte, error) {
+	if c.conn == nil {
+		dialer := net.Dialer{Timeout: c.timeout}
+		conn, err := dialer.DialContext(ctx, "tcp", c.address)
+		if err != nil {
+			return nil, err
+		}
+		c.conn = conn
+	}
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	c.transactionID++
+	// the MBAP header is the transaction ID, the protocol ID of 0, the length of the rest of the
+	// frame and the unit ID
+	frame := make([]byte, 7, 7+len(pdu))
+	binary.BigEndian.PutUint16(frame[0:], c.transactionID)
+	binary.BigEndian.PutUint16(frame[4:], uint16(1+len(pdu)))
+	frame[6] = c.unitID
+	if _, err := c.conn.Write(append(frame, pdu...)); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(c.conn, header); err != nil {
+		return nil, err
+	}
+	length := int(binary.BigEndian.Uint16(header[4:]))
+	if length < 2 || length > 254 {
+		return nil, errors.Errorf("invalid Modbus frame length %d", length)
+	}
+	resp := make([]byte, length-1)
+	if _, err := io.ReadFull(c.conn, resp); err != nil {
+		return nil, err
+	}
+	if id := binary.BigEndian.Uint16(header[0:]); id != c.transactionID {
+		return nil, errors.Errorf("Modbus response is for transaction %d, not %d", id, c.transactionID)
+	}
+	if header[6] != c.unitID {
+		return nil, errors.Errorf("Modbus response is from unit %d, not %d", header[6], c.unitID)
+	}
+	return resp, nil
+}
+
+// close closes the connection.
+func (c *tcpClient) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeConn()
+}
+
+// closeConn must be called with c.mu held.
+func (c *tcpClient) closeConn() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
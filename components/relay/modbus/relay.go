@@ -0,0 +1,111 @@
+// Package modbus implements a relay that is a coil of a Modbus TCP device, such as a networked
+// relay board or a PLC output.
+package modbus
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/relay"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("modbus_relay")
+
+const (
+	defaultPort    = "502"
+	defaultUnitID  = 1
+	defaultTimeout = time.Second
+)
+
+// Config is used for converting config attributes.
+type Config struct {
+	relay.Config
+	// Host is the address of the Modbus TCP server, with a port that defaults to 502.
+	Host string `json:"host"`
+	// UnitID is the unit of the server the coil belongs to, from 1 to 247. It defaults to 1.
+	UnitID int `json:"unit_id,omitempty"`
+	// Coil is the zero-based address of the coil.
+	Coil int `json:"coil"`
+	// TimeoutMs bounds each request. It defaults to 1000.
+	TimeoutMs int `json:"timeout_ms,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.Host == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "host")
+	}
+	if conf.UnitID < 0 || conf.UnitID > 247 {
+		return nil, resource.NewConfigValidationError(path, errors.New("unit_id must be between 1 and 247"))
+	}
+	if conf.Coil < 0 || conf.Coil > 0xffff {
+		return nil, resource.NewConfigValidationError(path, errors.New("coil must be between 0 and 65535"))
+	}
+	if conf.TimeoutMs < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("timeout_ms must not be negative"))
+	}
+	return nil, conf.Config.Validate(path)
+}
+
+func init() {
+	resource.RegisterComponent(relay.API, model, resource.Registration[resource.Resource, *Config]{Constructor: newRelay})
+}
+
+type modbusRelay struct {
+	resource.Named
+	resource.AlwaysRebuild
+	*relay.Coil
+
+	client *tcpClient
+}
+
+func newRelay(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (resource.Resource, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	address := newConf.Host
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, defaultPort)
+	}
+	client := &tcpClient{address: address, unitID: defaultUnitID, timeout: defaultTimeout}
+	if newConf.UnitID != 0 {
+		client.unitID = byte(newConf.UnitID)
+	}
+	if newConf.TimeoutMs != 0 {
+		client.timeout = time.Duration(newConf.TimeoutMs) * time.Millisecond
+	}
+	coil := uint16(newConf.Coil)
+	r := &modbusRelay{
+		Named: conf.ResourceName().AsNamed(),
+		Coil: relay.NewCoil(func(ctx context.Context, on bool) error {
+			return client.writeCoil(ctx, coil, on)
+		}, func(ctx context.Context) (bool, error) {
+			return client.readCoil(ctx, coil)
+		}, logger),
+		client: client,
+	}
+	if err := r.Init(ctx, newConf.Config); err != nil {
+		return nil, multierr.Combine(err, client.close())
+	}
+	return r, nil
+}
+
+// DoCommand handles the relay commands.
+func (r *modbusRelay) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if resp, handled, err := relay.HandleDoCommand(ctx, r, cmd); handled {
+		return resp, err
+	}
+	return map[string]interface{}{}, nil
+}
+
+// Close ends any pulse in progress and disconnects from the server.
+func (r *modbusRelay) Close(ctx context.Context) error {
+	return multierr.Combine(r.Coil.Close(ctx), r.client.close())
+}
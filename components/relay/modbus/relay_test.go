@@ -0,0 +1,131 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/relay"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// fakeServer is a Modbus TCP server of unit 3 with 16 coils.
+type fakeServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	coils    [16]bool
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	s := &fakeServer{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		header := make([]byte, 7)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		pdu := make([]byte, binary.BigEndian.Uint16(header[4:])-1)
+		if _, err := io.ReadFull(conn, pdu); err != nil {
+			return
+		}
+		resp := s.handle(header[6], pdu)
+		binary.BigEndian.PutUint16(header[4:], uint16(1+len(resp)))
+		if _, err := conn.Write(append(header, resp...)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeServer) handle(unit byte, pdu []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	coil := int(binary.BigEndian.Uint16(pdu[1:]))
+	if unit != 3 {
+		return []byte{pdu[0] | exceptionBit, 0x0b}
+	}
+	if coil >= len(s.coils) {
+		return []byte{pdu[0] | exceptionBit, 0x02}
+	}
+	switch pdu[0] {
+	case funcReadCoils:
+		var status byte
+		if s.coils[coil] {
+			status = 1
+		}
+		return []byte{funcReadCoils, 1, status}
+	case funcWriteSingleCoil:
+		s.coils[coil] = pdu[3] == 0xff
+		return pdu
+	default:
+		return []byte{pdu[0] | exceptionBit, 0x01}
+	}
+}
+
+func TestModbusRelay(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	server := newFakeServer(t)
+
+	conf := &Config{
+		Config: relay.Config{InitialState: relay.InitialStateOn},
+		Host:   server.listener.Addr().String(),
+		UnitID: 3,
+		Coil:   5,
+	}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	res, err := newRelay(ctx, nil, resource.Config{Name: "charger", ConvertedAttributes: conf}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	r := res.(relay.Relay)
+	defer r.Close(ctx)
+
+	server.mu.Lock()
+	test.That(t, server.coils[5], test.ShouldBeTrue)
+	server.mu.Unlock()
+
+	test.That(t, r.Toggle(ctx, nil), test.ShouldBeNil)
+	on, err := r.IsOn(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, on, test.ShouldBeFalse)
+
+	// the state is read back from the device
+	server.mu.Lock()
+	server.coils[5] = true
+	server.mu.Unlock()
+	on, err = r.IsOn(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, on, test.ShouldBeTrue)
+
+	t.Run("exceptions", func(t *testing.T) {
+		conf := &Config{Host: server.listener.Addr().String(), UnitID: 3, Coil: 20}
+		res, err := newRelay(ctx, nil, resource.Config{Name: "missing", ConvertedAttributes: conf}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer res.Close(ctx)
+		test.That(t, res.(relay.Relay).On(ctx, nil), test.ShouldBeError, "Modbus exception 0x02: illegal data address")
+	})
+
+	_, err = (&Config{Host: "relay.local", UnitID: 300}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,9 @@
+// Package register registers all relevant relays.
+package register
+
+import (
+	// register relays.
+	_ "go.viam.com/rdk/components/relay/fake"
+	_ "go.viam.com/rdk/components/relay/gpio"
+	_ "go.viam.com/rdk/components/relay/modbus"
+)
@@ -0,0 +1,120 @@
+// Package relay defines a relay or contactor, an on/off switch for a load such as a pump, solenoid
+// or charger that can be pulsed and read back.
+//
+// Unlike a switch, which selects one of several positions, a relay only switches a load on and
+// off, and reports whether its load is actually on. There is no relay proto, so relays are generic
+// components whose typed methods are carried over DoCommand. Models implement Relay and answer the
+// reserved commands by calling HandleDoCommand from their DoCommand.
+package relay
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+)
+
+// API is the resource API relays are served under.
+var API = generic.API
+
+// Named is a helper for getting the named relay's typed resource name.
+func Named(name string) resource.Name {
+	return resource.NewName(API, name)
+}
+
+// A Relay switches a load on and off.
+//
+// On and Off example:
+//
+//	myRelay, err := relay.FromRobot(machine, "pump")
+//	err = myRelay.On(context.Background(), nil)
+//	err = myRelay.Off(context.Background(), nil)
+//
+// Pulse example:
+//
+//	// Open the solenoid valve for half a second.
+//	err = myRelay.Pulse(context.Background(), 500*time.Millisecond, nil)
+//
+// IsOn example:
+//
+//	on, err := myRelay.IsOn(context.Background(), nil)
+type Relay interface {
+	resource.Resource
+
+	// On switches the load on, ending any pulse.
+	On(ctx context.Context, extra map[string]interface{}) error
+
+	// Off switches the load off, ending any pulse.
+	Off(ctx context.Context, extra map[string]interface{}) error
+
+	// Toggle switches the load off if it is on and on if it is off, ending any pulse.
+	Toggle(ctx context.Context, extra map[string]interface{}) error
+
+	// IsOn returns whether the load is on, as read back from the hardware.
+	IsOn(ctx context.Context, extra map[string]interface{}) (bool, error)
+
+	// Pulse switches the load on and returns, switching it off again after `duration` unless the
+	// relay is commanded again first.
+	Pulse(ctx context.Context, duration time.Duration, extra map[string]interface{}) error
+}
+
+// FromResource returns `res` as a Relay. Resources that do not implement Relay, such as the clients
+// of remote relays, are wrapped in a client that calls them over DoCommand.
+func FromResource(res resource.Resource) Relay {
+	if r, ok := res.(Relay); ok {
+		return r
+	}
+	return NewClientFromResource(res)
+}
+
+// FromDependencies is a helper for getting the named relay from a collection of dependencies.
+func FromDependencies(deps resource.Dependencies, name string) (Relay, error) {
+	res, err := generic.FromDependencies(deps, name)
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
+
+// FromRobot is a helper for getting the named relay from the given Robot.
+func FromRobot(r robot.Robot, name string) (Relay, error) {
+	res, err := generic.FromRobot(r, name)
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
+
+// The initial states a relay can be configured with.
+const (
+	InitialStateOn  = "on"
+	InitialStateOff = "off"
+)
+
+// Config is the configuration shared by relays. Models embed it in their configs.
+type Config struct {
+	// InitialState is "on" or "off" to switch the relay when it is built, or empty to leave it as
+	// it is, so that reconfiguring does not interrupt its load.
+	InitialState string `json:"initial_state,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) error {
+	switch conf.InitialState {
+	case "", InitialStateOn, InitialStateOff:
+		return nil
+	default:
+		return resource.NewConfigValidationError(path, errors.Errorf("initial_state must be %q or %q", InitialStateOn, InitialStateOff))
+	}
+}
+
+func validateDuration(duration time.Duration) error {
+	if duration <= 0 {
+		return errors.New("pulse duration must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package relay_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/components/relay"
+	"go.viam.com/rdk/components/relay/fake"
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func TestCoil(t *testing.T) {
+	ctx := context.Background()
+	r := fake.NewRelay(relay.Named("pump"), logging.NewTestLogger(t))
+	isOn := func() bool {
+		on, err := r.IsOn(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		return on
+	}
+
+	test.That(t, r.Init(ctx, relay.Config{InitialState: relay.InitialStateOn}), test.ShouldBeNil)
+	test.That(t, isOn(), test.ShouldBeTrue)
+	test.That(t, r.Toggle(ctx, nil), test.ShouldBeNil)
+	test.That(t, isOn(), test.ShouldBeFalse)
+	test.That(t, r.Toggle(ctx, nil), test.ShouldBeNil)
+	test.That(t, isOn(), test.ShouldBeTrue)
+	test.That(t, r.Off(ctx, nil), test.ShouldBeNil)
+	test.That(t, isOn(), test.ShouldBeFalse)
+
+	t.Run("pulse", func(t *testing.T) {
+		test.That(t, r.Pulse(ctx, 50*time.Millisecond, nil), test.ShouldBeNil)
+		test.That(t, isOn(), test.ShouldBeTrue)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			on, err := r.IsOn(ctx, nil)
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, on, test.ShouldBeFalse)
+		})
+
+		test.That(t, r.Pulse(ctx, 0, nil), test.ShouldBeError, "pulse duration must be positive")
+	})
+
+	t.Run("commands end pulses", func(t *testing.T) {
+		test.That(t, r.Pulse(ctx, 50*time.Millisecond, nil), test.ShouldBeNil)
+		test.That(t, r.On(ctx, nil), test.ShouldBeNil)
+		time.Sleep(100 * time.Millisecond)
+		test.That(t, isOn(), test.ShouldBeTrue)
+	})
+
+	t.Run("close ends pulses", func(t *testing.T) {
+		test.That(t, r.Pulse(ctx, time.Hour, nil), test.ShouldBeNil)
+		test.That(t, r.Close(ctx), test.ShouldBeNil)
+		test.That(t, isOn(), test.ShouldBeFalse)
+
+		// closing without a pulse leaves the load as it is
+		test.That(t, r.On(ctx, nil), test.ShouldBeNil)
+		test.That(t, r.Close(ctx), test.ShouldBeNil)
+		test.That(t, isOn(), test.ShouldBeTrue)
+	})
+
+	conf := relay.Config{InitialState: "open"}
+	test.That(t, conf.Validate("path"), test.ShouldNotBeNil)
+}
+
+func TestClient(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	pump := fake.NewRelay(relay.Named("pump"), logger)
+	defer pump.Close(context.Background())
+	coll, err := resource.NewAPIResourceCollection(generic.API, map[resource.Name]resource.Resource{
+		relay.Named("pump"): pump,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[resource.Resource](generic.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, coll), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	res, err := generic.NewClientFromConn(context.Background(), conn, "", relay.Named("pump"), logger)
+	test.That(t, err, test.ShouldBeNil)
+	client := relay.FromResource(res)
+
+	isOn := func() bool {
+		on, err := client.IsOn(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		return on
+	}
+	test.That(t, isOn(), test.ShouldBeFalse)
+	test.That(t, client.On(context.Background(), nil), test.ShouldBeNil)
+	test.That(t, isOn(), test.ShouldBeTrue)
+	test.That(t, client.Toggle(context.Background(), nil), test.ShouldBeNil)
+	test.That(t, isOn(), test.ShouldBeFalse)
+	test.That(t, client.Pulse(context.Background(), time.Hour, nil), test.ShouldBeNil)
+	test.That(t, isOn(), test.ShouldBeTrue)
+	test.That(t, client.Off(context.Background(), nil), test.ShouldBeNil)
+	test.That(t, isOn(), test.ShouldBeFalse)
+	test.That(t, client.Pulse(context.Background(), -time.Second, nil), test.ShouldBeError, "pulse duration must be positive")
+}
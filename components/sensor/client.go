@@ -9,6 +9,7 @@ import (
 	"go.viam.com/utils/rpc"
 	"google.golang.org/protobuf/types/known/structpb"
 
+	"go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
@@ -20,6 +21,7 @@ type client struct {
 	resource.TriviallyReconfigurable
 	resource.TriviallyCloseable
 	name   string
+	conn   rpc.ClientConn
 	client pb.SensorServiceClient
 	logger logging.Logger
 }
@@ -36,6 +38,7 @@ func NewClientFromConn(
 	return &client{
 		Named:  name.PrependRemote(remoteName).AsNamed(),
 		name:   name.ShortName(),
+		conn:   conn,
 		client: c,
 		logger: logger,
 	}, nil
@@ -60,3 +63,16 @@ func (c *client) Readings(ctx context.Context, extra map[string]interface{}) (ma
 func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	return protoutils.DoFromResourceClient(ctx, c.client, c.name, cmd)
 }
+
+// StreamReadings has the server sample the sensor and receives its readings in batches.
+func (c *client) StreamReadings(
+	ctx context.Context,
+	opts ReadingsStreamOptions,
+	extra map[string]interface{},
+	handle func([]TimedReadings) error,
+) error {
+	// A stream lost with the connection is resubscribed to once the connection is re-established.
+	return grpc.ResumeStream(ctx, c.conn, func(ctx context.Context) error {
+		return StreamReadingsWithDoCommand(ctx, c.DoCommand, opts, extra, handle)
+	})
+}
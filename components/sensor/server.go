@@ -20,12 +20,13 @@ var ErrReadingsNil = func(sensorType, sensorName string) error {
 // serviceServer implements the SensorService from sensor.proto.
 type serviceServer struct {
 	pb.UnimplementedSensorServiceServer
-	coll resource.APIResourceCollection[Sensor]
+	coll    resource.APIResourceCollection[Sensor]
+	streams *ReadingsStreams
 }
 
 // NewRPCServiceServer constructs an sensor gRPC service serviceServer.
 func NewRPCServiceServer(coll resource.APIResourceCollection[Sensor]) interface{} {
-	return &serviceServer{coll: coll}
+	return &serviceServer{coll: coll, streams: NewReadingsStreams()}
 }
 
 // GetReadings returns the most recent readings from the given Sensor.
@@ -59,5 +60,8 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := DoStreamReadingsCommand(ctx, s.streams, sensorDevice, req); handled {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, sensorDevice, req)
 }
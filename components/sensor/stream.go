@@ -0,0 +1,412 @@
+package sensor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	goutils "go.viam.com/utils"
+	vprotoutils "go.viam.com/utils/protoutils"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/protoutils"
+	"go.viam.com/rdk/resource"
+)
+
+// The sensor protos have no streaming readings RPC. Readings streams are carried over DoCommand
+// using the following reserved keys. The first request of a stream subscribes, after which the
+// server samples the sensor at the requested rate, and each request returns the next batch of
+// readings.
+const (
+	streamReadingsKey = "stream_readings"
+	stopReadingsKey   = "stop_readings"
+	subscriptionIDKey = "subscription_id"
+	rateHzKey         = "rate_hz"
+	batchSizeKey      = "batch_size"
+	batchKey          = "batch"
+	timeUnixNanosKey  = "time_unix_nanos"
+	readingsKey       = "readings"
+	extraKey          = "extra"
+)
+
+const (
+	defaultStreamRateHz = 10
+	maxStreamRateHz     = 1000
+	// subscriptionIdleTimeout is how long a subscription is kept without its client asking for a
+	// batch, after which it is assumed the client went away.
+	subscriptionIdleTimeout = 10 * time.Second
+	// subscriptionBufferedBatches is the number of batches a subscription holds for its client.
+	subscriptionBufferedBatches = 16
+)
+
+// TimedReadings are the readings of a sensor and the time they were taken.
+type TimedReadings struct {
+	Time     time.Time
+	Readings map[string]interface{}
+}
+
+// ReadingsStreamOptions configure a readings stream.
+type ReadingsStreamOptions struct {
+	// RateHz is how often the sensor is read. Defaults to 10Hz, and is at most 1000Hz.
+	RateHz float64
+	// BatchSize is the number of readings delivered at once. Defaults to 1.
+	BatchSize int
+}
+
+func (opts ReadingsStreamOptions) withDefaults() (ReadingsStreamOptions, error) {
+	if opts.RateHz < 0 || opts.BatchSize < 0 {
+		return opts, errors.New("readings stream options cannot be negative")
+	}
+	if opts.RateHz > maxStreamRateHz {
+		return opts, errors.Errorf("readings stream rate %.0fHz is above the maximum of %dHz", opts.RateHz, maxStreamRateHz)
+	}
+	if opts.RateHz == 0 {
+		opts.RateHz = defaultStreamRateHz
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = 1
+	}
+	return opts, nil
+}
+
+// ReadingsStreamer is implemented by sensor clients, which have the server sample the sensor and
+// receive its readings in batches, rather than making a request per reading.
+type ReadingsStreamer interface {
+	// StreamReadings reads the sensor at opts.RateHz and calls `handle` with each batch of
+	// opts.BatchSize readings, until ctx is done or reading or `handle` fails.
+	StreamReadings(
+		ctx context.Context,
+		opts ReadingsStreamOptions,
+		extra map[string]interface{},
+		handle func([]TimedReadings) error,
+	) error
+}
+
+// StreamReadings reads `s` at opts.RateHz and calls `handle` with each batch of opts.BatchSize
+// readings, until ctx is done or reading or `handle` fails. Sensors on other machines are sampled
+// by their server, which sends each batch in a single response, so high rate sensors such as IMUs
+// can be read efficiently.
+//
+// StreamReadings example:
+//
+//	mySensor, err := sensor.FromRobot(machine, "my_sensor")
+//	// Read at 100Hz, receiving 10 readings at a time.
+//	err = sensor.StreamReadings(ctx, mySensor, sensor.ReadingsStreamOptions{RateHz: 100, BatchSize: 10}, nil,
+//		func(batch []sensor.TimedReadings) error {
+//			for _, r := range batch {
+//				logger.Infof("%v: %v", r.Time, r.Readings)
+//			}
+//			return nil
+//		})
+func StreamReadings(
+	ctx context.Context,
+	s resource.Sensor,
+	opts ReadingsStreamOptions,
+	extra map[string]interface{},
+	handle func([]TimedReadings) error,
+) error {
+	if streamer, ok := s.(ReadingsStreamer); ok {
+		return streamer.StreamReadings(ctx, opts, extra, handle)
+	}
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return err
+	}
+	return sampleReadings(ctx, s, opts, extra, handle)
+}
+
+// sampleReadings reads `s` on a ticker and calls `handle` with each full batch.
+func sampleReadings(
+	ctx context.Context,
+	s resource.Sensor,
+	opts ReadingsStreamOptions,
+	extra map[string]interface{},
+	handle func([]TimedReadings) error,
+) error {
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.RateHz))
+	defer ticker.Stop()
+	batch := make([]TimedReadings, 0, opts.BatchSize)
+	for {
+		readings, err := s.Readings(ctx, extra)
+		if err != nil {
+			return err
+		}
+		batch = append(batch, TimedReadings{Time: time.Now(), Readings: readings})
+		if len(batch) == opts.BatchSize {
+			if err := handle(batch); err != nil {
+				return err
+			}
+			batch = make([]TimedReadings, 0, opts.BatchSize)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// readingsSubscription samples a sensor on behalf of a client.
+type readingsSubscription struct {
+	cancel  context.CancelFunc
+	batches chan []TimedReadings
+	done    chan struct{}
+	err     error
+
+	mu       sync.Mutex
+	lastPoll time.Time
+}
+
+func (sub *readingsSubscription) poll() {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	sub.lastPoll = time.Now()
+}
+
+func (sub *readingsSubscription) idle() bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return time.Since(sub.lastPoll) > subscriptionIdleTimeout
+}
+
+// ReadingsStreams are the readings subscriptions of an API's server. Servers of sensor APIs
+// dispatch DoCommand requests to DoStreamReadingsCommand so their clients can stream readings.
+type ReadingsStreams struct {
+	mu   sync.Mutex
+	subs map[string]*readingsSubscription
+}
+
+// NewReadingsStreams returns an empty set of readings subscriptions.
+func NewReadingsStreams() *ReadingsStreams {
+	return &ReadingsStreams{subs: map[string]*readingsSubscription{}}
+}
+
+func (rs *ReadingsStreams) subscribe(s resource.Sensor, opts ReadingsStreamOptions, extra map[string]interface{}) string {
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := &readingsSubscription{
+		cancel:   cancel,
+		batches:  make(chan []TimedReadings, subscriptionBufferedBatches),
+		done:     make(chan struct{}),
+		lastPoll: time.Now(),
+	}
+	id := uuid.NewString()
+	rs.mu.Lock()
+	rs.subs[id] = sub
+	rs.mu.Unlock()
+
+	goutils.PanicCapturingGo(func() {
+		defer close(sub.done)
+		defer rs.remove(id)
+		sub.err = sampleReadings(ctx, s, opts, extra, func(batch []TimedReadings) error {
+			if sub.idle() {
+				return errors.New("readings subscription expired")
+			}
+			select {
+			case sub.batches <- batch:
+			default:
+				// the client is not keeping up, so drop the oldest batch
+				select {
+				case <-sub.batches:
+				default:
+				}
+				sub.batches <- batch
+			}
+			return nil
+		})
+	})
+	return id
+}
+
+func (rs *ReadingsStreams) get(id string) (*readingsSubscription, bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	sub, ok := rs.subs[id]
+	return sub, ok
+}
+
+func (rs *ReadingsStreams) remove(id string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if sub, ok := rs.subs[id]; ok {
+		sub.cancel()
+		delete(rs.subs, id)
+	}
+}
+
+// next returns the subscription's next batch, waiting for it if needed.
+func (sub *readingsSubscription) next(ctx context.Context) ([]TimedReadings, error) {
+	sub.poll()
+	select {
+	case batch := <-sub.batches:
+		return batch, nil
+	default:
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case batch := <-sub.batches:
+		return batch, nil
+	case <-sub.done:
+		// deliver batches sampled before the subscription ended
+		select {
+		case batch := <-sub.batches:
+			return batch, nil
+		default:
+		}
+		if sub.err != nil {
+			return nil, sub.err
+		}
+		return nil, errors.New("readings subscription ended")
+	}
+}
+
+// DoStreamReadingsCommand handles the reserved readings stream DoCommand keys for `s`. It returns
+// false if `req` is not a readings stream command.
+func DoStreamReadingsCommand(
+	ctx context.Context,
+	rs *ReadingsStreams,
+	s resource.Sensor,
+	req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	cmd := req.GetCommand().AsMap()
+	if payload, ok := cmd[stopReadingsKey]; ok {
+		args, _ := payload.(map[string]interface{}) //nolint:errcheck
+		id, _ := args[subscriptionIDKey].(string)   //nolint:errcheck
+		rs.remove(id)
+		return doCommandResponse(map[string]interface{}{})
+	}
+	payload, ok := cmd[streamReadingsKey]
+	if !ok {
+		return nil, false, nil
+	}
+	args, ok := payload.(map[string]interface{})
+	if !ok {
+		return nil, true, errors.Errorf("%q must be an object", streamReadingsKey)
+	}
+	id, _ := args[subscriptionIDKey].(string) //nolint:errcheck
+	if id == "" {
+		rate, _ := args[rateHzKey].(float64)                //nolint:errcheck
+		batchSize, _ := args[batchSizeKey].(float64)        //nolint:errcheck
+		extra, _ := args[extraKey].(map[string]interface{}) //nolint:errcheck
+		opts, err := ReadingsStreamOptions{RateHz: rate, BatchSize: int(batchSize)}.withDefaults()
+		if err != nil {
+			return nil, true, err
+		}
+		id = rs.subscribe(s, opts, extra)
+	}
+	sub, ok := rs.get(id)
+	if !ok {
+		return nil, true, errors.Errorf("no readings subscription %q", id)
+	}
+	batch, err := sub.next(ctx)
+	if err != nil {
+		return nil, true, err
+	}
+	encoded := make([]interface{}, 0, len(batch))
+	for _, r := range batch {
+		readings, err := protoutils.ReadingGoToProto(r.Readings)
+		if err != nil {
+			return nil, true, err
+		}
+		values := make(map[string]interface{}, len(readings))
+		for k, v := range readings {
+			values[k] = v.AsInterface()
+		}
+		encoded = append(encoded, map[string]interface{}{
+			timeUnixNanosKey: float64(r.Time.UnixNano()),
+			readingsKey:      values,
+		})
+	}
+	return doCommandResponse(map[string]interface{}{subscriptionIDKey: id, batchKey: encoded})
+}
+
+// StreamReadingsWithDoCommand implements ReadingsStreamer for the client of a sensor API whose
+// server dispatches to DoStreamReadingsCommand.
+func StreamReadingsWithDoCommand(
+	ctx context.Context,
+	doCommand func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error),
+	opts ReadingsStreamOptions,
+	extra map[string]interface{},
+	handle func([]TimedReadings) error,
+) error {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return err
+	}
+	var id string
+	defer func() {
+		if id == "" {
+			return
+		}
+		// let the server stop sampling now rather than when the subscription expires
+		stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		//nolint:errcheck
+		doCommand(stopCtx, map[string]interface{}{stopReadingsKey: map[string]interface{}{subscriptionIDKey: id}})
+	}()
+	for {
+		args := map[string]interface{}{subscriptionIDKey: id}
+		if id == "" {
+			args = map[string]interface{}{
+				rateHzKey:    opts.RateHz,
+				batchSizeKey: opts.BatchSize,
+				extraKey:     extra,
+			}
+		}
+		resp, err := doCommand(ctx, map[string]interface{}{streamReadingsKey: args})
+		if err != nil {
+			return err
+		}
+		if id, _ = resp[subscriptionIDKey].(string); id == "" { //nolint:errcheck
+			return errors.Errorf("expected %q in response, got %v", subscriptionIDKey, resp)
+		}
+		batch, err := timedReadingsFromInterface(resp[batchKey])
+		if err != nil {
+			return err
+		}
+		if err := handle(batch); err != nil {
+			return err
+		}
+	}
+}
+
+func timedReadingsFromInterface(raw interface{}) ([]TimedReadings, error) {
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("%q must be a list", batchKey)
+	}
+	batch := make([]TimedReadings, 0, len(list))
+	for _, rawEntry := range list {
+		entry, ok := rawEntry.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%q entries must be objects", batchKey)
+		}
+		nanos, _ := entry[timeUnixNanosKey].(float64)            //nolint:errcheck
+		values, _ := entry[readingsKey].(map[string]interface{}) //nolint:errcheck
+		readings := make(map[string]*structpb.Value, len(values))
+		for k, v := range values {
+			value, err := structpb.NewValue(v)
+			if err != nil {
+				return nil, err
+			}
+			readings[k] = value
+		}
+		goReadings, err := protoutils.ReadingProtoToGo(readings)
+		if err != nil {
+			return nil, err
+		}
+		batch = append(batch, TimedReadings{Time: time.Unix(0, int64(nanos)), Readings: goReadings})
+	}
+	return batch, nil
+}
+
+func doCommandResponse(result map[string]interface{}) (*commonpb.DoCommandResponse, bool, error) {
+	res, err := vprotoutils.StructToStructPb(result)
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
@@ -0,0 +1,115 @@
+package sensor_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/components/sensor"
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+var errEnoughBatches = errors.New("enough batches")
+
+func newCountingSensor() (*inject.Sensor, *atomic.Int64) {
+	var count atomic.Int64
+	s := &inject.Sensor{}
+	s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"count": float64(count.Add(1)), "extra": extra["foo"]}, nil
+	}
+	return s, &count
+}
+
+func TestStreamReadingsLocal(t *testing.T) {
+	s, _ := newCountingSensor()
+	var batches [][]sensor.TimedReadings
+	err := sensor.StreamReadings(context.Background(), s, sensor.ReadingsStreamOptions{RateHz: 1000, BatchSize: 3},
+		map[string]interface{}{"foo": "bar"},
+		func(batch []sensor.TimedReadings) error {
+			batches = append(batches, batch)
+			if len(batches) == 2 {
+				return errEnoughBatches
+			}
+			return nil
+		})
+	test.That(t, err, test.ShouldBeError, errEnoughBatches)
+	test.That(t, batches, test.ShouldHaveLength, 2)
+	test.That(t, batches[0], test.ShouldHaveLength, 3)
+	test.That(t, batches[1][2].Readings["count"], test.ShouldEqual, 6.)
+	test.That(t, batches[1][2].Readings["extra"], test.ShouldEqual, "bar")
+	test.That(t, batches[1][2].Time.After(batches[0][0].Time), test.ShouldBeTrue)
+
+	err = sensor.StreamReadings(context.Background(), s, sensor.ReadingsStreamOptions{RateHz: 5000}, nil,
+		func(batch []sensor.TimedReadings) error { return nil })
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestStreamReadingsClient(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	working, count := newCountingSensor()
+	failing := &inject.Sensor{}
+	failing.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return nil, errReadingsFailed
+	}
+	sensorSvc, err := resource.NewAPIResourceCollection(sensor.API, map[resource.Name]sensor.Sensor{
+		sensor.Named(testSensorName): working,
+		sensor.Named(failSensorName): failing,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[sensor.Sensor](sensor.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, sensorSvc), test.ShouldBeNil)
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() { test.That(t, conn.Close(), test.ShouldBeNil) }()
+
+	client, err := sensor.NewClientFromConn(context.Background(), conn, "", sensor.Named(testSensorName), logger)
+	test.That(t, err, test.ShouldBeNil)
+	_, ok = client.(sensor.ReadingsStreamer)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	var received []sensor.TimedReadings
+	err = sensor.StreamReadings(context.Background(), client, sensor.ReadingsStreamOptions{RateHz: 500, BatchSize: 5},
+		map[string]interface{}{"foo": "bar"},
+		func(batch []sensor.TimedReadings) error {
+			test.That(t, batch, test.ShouldHaveLength, 5)
+			received = append(received, batch...)
+			if len(received) >= 15 {
+				return errEnoughBatches
+			}
+			return nil
+		})
+	test.That(t, err, test.ShouldBeError, errEnoughBatches)
+	test.That(t, received, test.ShouldHaveLength, 15)
+	// readings arrive in order and were all taken on the server
+	for i := 1; i < len(received); i++ {
+		test.That(t, received[i].Readings["count"], test.ShouldBeGreaterThan, received[i-1].Readings["count"])
+		test.That(t, received[i].Time.Before(received[i-1].Time), test.ShouldBeFalse)
+	}
+	test.That(t, received[0].Readings["extra"], test.ShouldEqual, "bar")
+	test.That(t, count.Load(), test.ShouldBeGreaterThanOrEqualTo, 15)
+
+	failingClient, err := sensor.NewClientFromConn(context.Background(), conn, "", sensor.Named(failSensorName), logger)
+	test.That(t, err, test.ShouldBeNil)
+	err = sensor.StreamReadings(context.Background(), failingClient, sensor.ReadingsStreamOptions{}, nil,
+		func(batch []sensor.TimedReadings) error { return nil })
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, errReadingsFailed.Error())
+}
@@ -47,6 +47,11 @@ type servoConfig struct {
 	MinWidthUs *uint `json:"min_width_us,omitempty"`
 	// MaxWidthUs overrides the safe maximum PWM width in microseconds.
 	MaxWidthUs *uint `json:"max_width_us,omitempty"`
+	// SpeedDegsPerSec is the default speed limit of Move. If omitted, the servo moves at its
+	// maximum rate. It can be overridden per move with servo.ExtraWithMotionLimits.
+	SpeedDegsPerSec float64 `json:"speed_degs_per_sec,omitempty"`
+	// AccelDegsPerSecSec is the default acceleration limit of Move.
+	AccelDegsPerSecSec float64 `json:"accel_degs_per_sec_per_sec,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -84,9 +89,16 @@ func (config *servoConfig) Validate(path string) ([]string, error) {
 	if config.MaxWidthUs != nil && *config.MaxWidthUs > maxWidthUs {
 		return nil, resource.NewConfigValidationError(path, errors.Errorf("max_width_us cannot be higher than %d", maxWidthUs))
 	}
+	if err := config.motionLimits().Validate(); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
 	return deps, nil
 }
 
+func (config *servoConfig) motionLimits() servo.MotionLimits {
+	return servo.MotionLimits{SpeedDegsPerSec: config.SpeedDegsPerSec, AccelDegsPerSecSec: config.AccelDegsPerSecSec}
+}
+
 var model = resource.DefaultModelFamily.WithModel("gpio")
 
 func init() {
@@ -109,7 +121,11 @@ type servoGPIO struct {
 	maxUs     uint
 	pwmRes    uint
 	currPct   float64
-	mu        sync.Mutex
+	limits    servo.MotionLimits
+	// currDeg is the last angle moved to, if hasDeg. Limited moves start from it.
+	currDeg float64
+	hasDeg  bool
+	mu      sync.Mutex
 }
 
 func newGPIOServo(
@@ -175,6 +191,10 @@ func (s *servoGPIO) Reconfigure(ctx context.Context, deps resource.Dependencies,
 		s.maxUs = *newConf.MaxWidthUs
 	}
 
+	// Moves to the start position below are not limited, since the servo's angle is unknown.
+	s.limits = servo.MotionLimits{}
+	s.hasDeg = false
+
 	// If the frequency isn't specified in the config, we'll use whatever it's currently set to
 	// instead. If it's currently set to 0, we'll default to using 300 Hz.
 	s.frequency, err = s.pin.PWMFreq(ctx, nil)
@@ -221,6 +241,7 @@ func (s *servoGPIO) Reconfigure(ctx context.Context, deps resource.Dependencies,
 	if err := s.Move(ctx, uint32(startPos), nil); err != nil {
 		return errors.Wrap(err, "couldn't move servo back to start position")
 	}
+	s.limits = newConf.motionLimits()
 
 	return nil
 }
@@ -323,12 +344,18 @@ func (s *servoGPIO) findPWMResolution(ctx context.Context) error {
 	return nil
 }
 
-// Move moves the servo to the given angle (0-180 degrees)
+// Move moves the servo to the given angle (0-180 degrees), within the configured speed and
+// acceleration limits or those set in extra by servo.ExtraWithMotionLimits.
 // This will block until done or a new operation cancels this one.
 func (s *servoGPIO) Move(ctx context.Context, ang uint32, extra map[string]interface{}) error {
 	ctx, done := s.opMgr.New(ctx)
 	defer done()
 
+	limits, err := servo.MotionLimitsFromExtra(extra, s.limits)
+	if err != nil {
+		return err
+	}
+
 	angle := float64(ang)
 
 	if angle < s.minDeg {
@@ -338,6 +365,16 @@ func (s *servoGPIO) Move(ctx context.Context, ang uint32, extra map[string]inter
 		angle = s.maxDeg
 	}
 
+	if limits.IsZero() || !s.hasDeg {
+		return s.setAngle(ctx, angle)
+	}
+	return servo.InterpolateMove(ctx, s.currDeg, angle, limits, func(angle float64) error {
+		return s.setAngle(ctx, angle)
+	})
+}
+
+// setAngle sets the PWM duty cycle of the servo's pin for the given angle.
+func (s *servoGPIO) setAngle(ctx context.Context, angle float64) error {
 	pct := mapDegToDutyCylePct(s.minUs, s.maxUs, s.minDeg, s.maxDeg, angle, s.frequency)
 	if s.pwmRes != 0 {
 		realTick := math.Round(pct * float64(s.pwmRes))
@@ -349,6 +386,8 @@ func (s *servoGPIO) Move(ctx context.Context, ang uint32, extra map[string]inter
 	}
 
 	s.currPct = pct
+	s.currDeg = angle
+	s.hasDeg = true
 	return nil
 }
 
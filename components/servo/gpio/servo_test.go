@@ -4,11 +4,13 @@ package gpio
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.viam.com/test"
 
 	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/servo"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/testutils/inject"
@@ -205,3 +207,45 @@ func TestServoMove(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, pos, test.ShouldEqual, 63)
 }
+
+func TestServoMoveWithLimits(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	deps := setupDependencies(t)
+	ctx := context.Background()
+
+	conf := servoConfig{
+		Pin:             "1",
+		Board:           "mock",
+		StartPos:        ptr(0.0),
+		SpeedDegsPerSec: 900,
+	}
+	s, err := newGPIOServo(ctx, deps, resource.Config{ConvertedAttributes: &conf}, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	// 90 degrees at 900 degrees per second takes 100ms
+	start := time.Now()
+	test.That(t, s.Move(ctx, 90, nil), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 100*time.Millisecond)
+	pos, err := s.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldAlmostEqual, 90, 1)
+
+	// limits in extra override the configured ones
+	start = time.Now()
+	extra := servo.ExtraWithMotionLimits(nil, servo.MotionLimits{SpeedDegsPerSec: 300})
+	test.That(t, s.Move(ctx, 60, extra), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 100*time.Millisecond)
+
+	// a canceled move stops partway
+	cancelCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	extra = servo.ExtraWithMotionLimits(nil, servo.MotionLimits{SpeedDegsPerSec: 100})
+	test.That(t, s.Move(cancelCtx, 0, extra), test.ShouldNotBeNil)
+	pos, err = s.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldBeBetween, 50, 60)
+
+	conf.SpeedDegsPerSec = -1
+	_, err = conf.Validate("test")
+	test.That(t, err, test.ShouldNotBeNil)
+}
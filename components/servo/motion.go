@@ -0,0 +1,132 @@
+package servo
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	rutils "go.viam.com/rdk/utils"
+)
+
+// The servo proto has no speed or acceleration fields on Move. Motion limits are carried in the
+// request's extra using the following reserved keys.
+const (
+	speedKey = "speed_degs_per_sec"
+	accelKey = "accel_degs_per_sec_per_sec"
+)
+
+// interpolationStep is how often an emulated move updates the servo's target angle. Hobby servos
+// are typically refreshed every 20ms, so updating faster has no effect.
+const interpolationStep = 20 * time.Millisecond
+
+// MotionLimits bound how fast a servo moves to its target. Zero values mean no limit.
+type MotionLimits struct {
+	SpeedDegsPerSec    float64
+	AccelDegsPerSecSec float64
+}
+
+// IsZero returns true if no limits are set, meaning the servo moves at its maximum rate.
+func (l MotionLimits) IsZero() bool {
+	return l == MotionLimits{}
+}
+
+// Validate ensures the limits are not negative.
+func (l MotionLimits) Validate() error {
+	if l.SpeedDegsPerSec < 0 || l.AccelDegsPerSecSec < 0 {
+		return errors.New("servo speed and acceleration limits cannot be negative")
+	}
+	return nil
+}
+
+// ExtraWithMotionLimits returns a copy of extra with the reserved keys set so that Move honors the
+// limits, on servos that support them.
+//
+// ExtraWithMotionLimits example:
+//
+//	myServo, err := servo.FromRobot(machine, "my_servo")
+//	// Sweep to 90 degrees at no more than 30 degrees per second.
+//	extra := servo.ExtraWithMotionLimits(nil, servo.MotionLimits{SpeedDegsPerSec: 30})
+//	err = myServo.Move(context.Background(), 90, extra)
+func ExtraWithMotionLimits(extra map[string]interface{}, limits MotionLimits) map[string]interface{} {
+	ret := make(map[string]interface{}, len(extra)+2)
+	for k, v := range extra {
+		ret[k] = v
+	}
+	if limits.SpeedDegsPerSec > 0 {
+		ret[speedKey] = limits.SpeedDegsPerSec
+	}
+	if limits.AccelDegsPerSecSec > 0 {
+		ret[accelKey] = limits.AccelDegsPerSecSec
+	}
+	return ret
+}
+
+// MotionLimitsFromExtra reads the limits set by ExtraWithMotionLimits. Limits that are absent
+// from extra are taken from defaults.
+func MotionLimitsFromExtra(extra map[string]interface{}, defaults MotionLimits) (MotionLimits, error) {
+	limits := defaults
+	for key, dst := range map[string]*float64{
+		speedKey: &limits.SpeedDegsPerSec,
+		accelKey: &limits.AccelDegsPerSecSec,
+	} {
+		raw, ok := extra[key]
+		if !ok {
+			continue
+		}
+		val, ok := raw.(float64)
+		if !ok {
+			return MotionLimits{}, errors.Errorf("%q must be a number", key)
+		}
+		*dst = val
+	}
+	return limits, limits.Validate()
+}
+
+// TrapezoidalProfile is a move that accelerates, cruises and decelerates within MotionLimits.
+type TrapezoidalProfile struct {
+	from, to float64
+	profile  *rutils.TrapezoidalProfile
+}
+
+// NewTrapezoidalProfile plans a move from one angle to another. Without a speed limit the move
+// accelerates through its first half and decelerates through its second. Without an acceleration
+// limit it moves at constant speed. Without either it completes immediately.
+func NewTrapezoidalProfile(from, to float64, limits MotionLimits) *TrapezoidalProfile {
+	return &TrapezoidalProfile{
+		from:    from,
+		to:      to,
+		profile: rutils.NewTrapezoidalProfile(to-from, limits.SpeedDegsPerSec, limits.AccelDegsPerSecSec),
+	}
+}
+
+// Duration returns how long the move takes.
+func (p *TrapezoidalProfile) Duration() time.Duration {
+	return p.profile.Duration()
+}
+
+// At returns the angle the move reaches after elapsed time.
+func (p *TrapezoidalProfile) At(elapsed time.Duration) float64 {
+	return p.from + (p.to-p.from)*p.profile.Fraction(elapsed)
+}
+
+// InterpolateMove emulates a limited move on servos that can only jump to a target angle, by
+// repeatedly calling set with intermediate angles along a TrapezoidalProfile. It returns early
+// with the context's error if ctx is done.
+func InterpolateMove(ctx context.Context, from, to float64, limits MotionLimits, set func(angle float64) error) error {
+	profile := NewTrapezoidalProfile(from, to, limits)
+	start := time.Now()
+	for {
+		elapsed := time.Since(start)
+		if err := set(profile.At(elapsed)); err != nil {
+			return err
+		}
+		if elapsed >= profile.Duration() {
+			return nil
+		}
+		if !utils.SelectContextOrWait(ctx, interpolationStep) {
+			return ctx.Err()
+		}
+	}
+}
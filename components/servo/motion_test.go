@@ -0,0 +1,70 @@
+package servo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/servo"
+)
+
+func TestTrapezoidalProfile(t *testing.T) {
+	// accelerates for 1s over 5 degrees, cruises for 1s over 10 degrees, decelerates for 1s
+	p := servo.NewTrapezoidalProfile(10, 30, servo.MotionLimits{SpeedDegsPerSec: 10, AccelDegsPerSecSec: 10})
+	test.That(t, p.Duration(), test.ShouldEqual, 3*time.Second)
+	test.That(t, p.At(0), test.ShouldEqual, 10)
+	test.That(t, p.At(time.Second), test.ShouldAlmostEqual, 15)
+	test.That(t, p.At(1500*time.Millisecond), test.ShouldAlmostEqual, 20)
+	test.That(t, p.At(2*time.Second), test.ShouldAlmostEqual, 25)
+	test.That(t, p.At(3*time.Second), test.ShouldEqual, 30)
+
+	// triangular, backwards
+	p = servo.NewTrapezoidalProfile(20, 0, servo.MotionLimits{AccelDegsPerSecSec: 20})
+	test.That(t, p.Duration(), test.ShouldEqual, 2*time.Second)
+	test.That(t, p.At(time.Second), test.ShouldAlmostEqual, 10)
+	test.That(t, p.At(500*time.Millisecond), test.ShouldAlmostEqual, 17.5)
+
+	// constant speed
+	p = servo.NewTrapezoidalProfile(0, 90, servo.MotionLimits{SpeedDegsPerSec: 45})
+	test.That(t, p.Duration(), test.ShouldEqual, 2*time.Second)
+	test.That(t, p.At(time.Second), test.ShouldAlmostEqual, 45)
+
+	// no limits
+	p = servo.NewTrapezoidalProfile(0, 90, servo.MotionLimits{})
+	test.That(t, p.Duration(), test.ShouldEqual, 0)
+	test.That(t, p.At(0), test.ShouldEqual, 90)
+}
+
+func TestInterpolateMove(t *testing.T) {
+	var angles []float64
+	set := func(angle float64) error {
+		angles = append(angles, angle)
+		return nil
+	}
+	err := servo.InterpolateMove(context.Background(), 0, 10, servo.MotionLimits{SpeedDegsPerSec: 100}, set)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(angles), test.ShouldBeGreaterThan, 2)
+	test.That(t, angles[len(angles)-1], test.ShouldEqual, 10)
+	for i := 1; i < len(angles); i++ {
+		test.That(t, angles[i], test.ShouldBeGreaterThanOrEqualTo, angles[i-1])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = servo.InterpolateMove(ctx, 0, 10, servo.MotionLimits{SpeedDegsPerSec: 1}, set)
+	test.That(t, err, test.ShouldBeError, context.Canceled)
+}
+
+func TestMotionLimitsExtra(t *testing.T) {
+	extra := servo.ExtraWithMotionLimits(map[string]interface{}{"foo": "bar"}, servo.MotionLimits{SpeedDegsPerSec: 30})
+	limits, err := servo.MotionLimitsFromExtra(extra, servo.MotionLimits{SpeedDegsPerSec: 10, AccelDegsPerSecSec: 5})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, limits, test.ShouldResemble, servo.MotionLimits{SpeedDegsPerSec: 30, AccelDegsPerSecSec: 5})
+
+	_, err = servo.MotionLimitsFromExtra(map[string]interface{}{"speed_degs_per_sec": "fast"}, servo.MotionLimits{})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = servo.MotionLimitsFromExtra(map[string]interface{}{"speed_degs_per_sec": -1.}, servo.MotionLimits{})
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -7,6 +7,7 @@ package servo
 import (
 	"context"
 
+	"github.com/pkg/errors"
 	pb "go.viam.com/api/component/servo/v1"
 
 	"go.viam.com/rdk/data"
@@ -25,6 +26,13 @@ func init() {
 		API:        API,
 		MethodName: position.String(),
 	}, newPositionCollector)
+	robot.RegisterResourceReader(API, readServo)
+	robot.RegisterStateSnapshotter(API, robot.StateSnapshotter{
+		Capture: func(ctx context.Context, res resource.Resource) (map[string]interface{}, error) {
+			return readServo(ctx, res, nil)
+		},
+		Restore: restoreServo,
+	})
 }
 
 // SubtypeName is a constant that identifies the component resource API string "servo".
@@ -88,3 +96,35 @@ func FromRobot(r robot.Robot, name string) (Servo, error) {
 func NamesFromRobot(r robot.Robot) []string {
 	return robot.NamesByAPI(r, API)
 }
+
+// readServo reads the position of a servo, in degrees, for a bulk read.
+func readServo(ctx context.Context, res resource.Resource, extra map[string]interface{}) (map[string]interface{}, error) {
+	s, err := resource.AsType[Servo](res)
+	if err != nil {
+		return nil, err
+	}
+	position, err := s.Position(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"position_deg": position}, nil
+}
+
+// restoreServo moves a servo back to a position captured by readServo.
+func restoreServo(ctx context.Context, res resource.Resource, state map[string]interface{}) error {
+	s, err := resource.AsType[Servo](res)
+	if err != nil {
+		return err
+	}
+	var position uint32
+	switch p := state["position_deg"].(type) {
+	case uint32:
+		position = p
+	case float64:
+		// the state was restored from JSON
+		position = uint32(p)
+	default:
+		return errors.Errorf("invalid servo state %v", state)
+	}
+	return s.Move(ctx, position, nil)
+}
@@ -20,6 +20,7 @@ import (
 	"go.viam.com/utils/pexec"
 	"go.viam.com/utils/rpc"
 
+	rgrpc "go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
@@ -39,6 +40,8 @@ type Config struct {
 	Auth              AuthConfig
 	Debug             bool
 	LogConfig         []logging.LoggerPatternConfig
+	LogSinks          []logging.SinkConfig
+	LogFormat         string
 	MaintenanceConfig *MaintenanceConfig
 
 	ConfigFilePath string
@@ -81,6 +84,15 @@ type Config struct {
 	// should be turned off. Defaults to false.
 	DisableLogDeduplication bool
 
+	// DisableLogDeduplicationFor are the patterns of the loggers whose noisy logs are never
+	// deduplicated, such as "rdk.resource_manager.rdk:component:motor/*".
+	DisableLogDeduplicationFor []string
+
+	// CachedAt is set when this config was loaded from the local cache because the cloud could
+	// not be reached. It holds the time the cached config was last written. It is the zero time
+	// when the config was fetched from the cloud or read from a local file.
+	CachedAt time.Time
+
 	// toCache stores the JSON marshalled version of the config to be cached. It should be a copy of
 	// the config pulled from cloud with minor changes.
 	// This version is kept because the config is changed as it moves through the system.
@@ -109,10 +121,14 @@ type configData struct {
 	DisablePartialStart     bool                          `json:"disable_partial_start"`
 	EnableWebProfile        bool                          `json:"enable_web_profile"`
 	LogConfig               []logging.LoggerPatternConfig `json:"log,omitempty"`
+	LogSinks                []logging.SinkConfig          `json:"log_sinks,omitempty"`
+	LogFormat               string                        `json:"log_format,omitempty"`
 	Revision                string                        `json:"revision,omitempty"`
 	MaintenanceConfig       *MaintenanceConfig            `json:"maintenance,omitempty"`
 	PackagePath             string                        `json:"package_path,omitempty"`
 	DisableLogDeduplication bool                          `json:"disable_log_deduplication"`
+	// Only local config files can opt loggers out, as cloud configs have no field for it.
+	DisableLogDeduplicationFor []string `json:"disable_log_deduplication_for,omitempty"`
 }
 
 // AppValidationStatus refers to the.
@@ -154,6 +170,25 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 		return err
 	}
 
+	if err := logging.ValidateLogFormat(c.LogFormat); err != nil {
+		fullErr := resource.NewConfigValidationError("log_format", err)
+		if c.DisablePartialStart {
+			return fullErr
+		}
+		logger.Errorw("log format config error; keeping the current log format", "error", fullErr)
+	}
+
+	// A sink which fails to validate is left out when the sinks are created.
+	for idx, sink := range c.LogSinks {
+		if err := sink.Validate(); err != nil {
+			fullErr := resource.NewConfigValidationError(fmt.Sprintf("%s.%d", "log_sinks", idx), err)
+			if c.DisablePartialStart {
+				return fullErr
+			}
+			logger.Errorw("log sink config error; starting robot without log sink", "error", fullErr)
+		}
+	}
+
 	for idx := 0; idx < len(c.Modules); idx++ {
 		if err := c.Modules[idx].Validate(fmt.Sprintf("%s.%d", "modules", idx)); err != nil {
 			if c.DisablePartialStart {
@@ -318,10 +353,13 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 	c.DisablePartialStart = conf.DisablePartialStart
 	c.EnableWebProfile = conf.EnableWebProfile
 	c.LogConfig = conf.LogConfig
+	c.LogSinks = conf.LogSinks
+	c.LogFormat = conf.LogFormat
 	c.Revision = conf.Revision
 	c.MaintenanceConfig = conf.MaintenanceConfig
 	c.PackagePath = conf.PackagePath
 	c.DisableLogDeduplication = conf.DisableLogDeduplication
+	c.DisableLogDeduplicationFor = conf.DisableLogDeduplicationFor
 
 	return nil
 }
@@ -339,23 +377,26 @@ func (c Config) MarshalJSON() ([]byte, error) {
 	}
 
 	return json.Marshal(configData{
-		Cloud:                   c.Cloud,
-		Modules:                 c.Modules,
-		Remotes:                 c.Remotes,
-		Components:              c.Components,
-		Processes:               c.Processes,
-		Services:                c.Services,
-		Packages:                c.Packages,
-		Network:                 c.Network,
-		Auth:                    c.Auth,
-		Debug:                   c.Debug,
-		DisablePartialStart:     c.DisablePartialStart,
-		EnableWebProfile:        c.EnableWebProfile,
-		LogConfig:               c.LogConfig,
-		Revision:                c.Revision,
-		MaintenanceConfig:       c.MaintenanceConfig,
-		PackagePath:             c.PackagePath,
-		DisableLogDeduplication: c.DisableLogDeduplication,
+		Cloud:                      c.Cloud,
+		Modules:                    c.Modules,
+		Remotes:                    c.Remotes,
+		Components:                 c.Components,
+		Processes:                  c.Processes,
+		Services:                   c.Services,
+		Packages:                   c.Packages,
+		Network:                    c.Network,
+		Auth:                       c.Auth,
+		Debug:                      c.Debug,
+		DisablePartialStart:        c.DisablePartialStart,
+		EnableWebProfile:           c.EnableWebProfile,
+		LogConfig:                  c.LogConfig,
+		LogSinks:                   c.LogSinks,
+		LogFormat:                  c.LogFormat,
+		Revision:                   c.Revision,
+		MaintenanceConfig:          c.MaintenanceConfig,
+		PackagePath:                c.PackagePath,
+		DisableLogDeduplication:    c.DisableLogDeduplication,
+		DisableLogDeduplicationFor: c.DisableLogDeduplicationFor,
 	})
 }
 
@@ -391,6 +432,16 @@ type Remote struct {
 	ReconnectInterval         time.Duration
 	AssociatedResourceConfigs []resource.AssociatedResourceConfig
 
+	// Prefix is prepended to the names of the resources of the remote, so that remotes owned by
+	// different teams can use the same resource names without their resources colliding.
+	Prefix string
+	// AllowedAPIs restricts the resources of the remote which are integrated to those of the
+	// given APIs, such as "rdk:component:camera". All resources are integrated when it is empty.
+	AllowedAPIs []string
+	// AllowedEntities restricts the authenticated entities which may call the resources of the
+	// remote through this robot. Any entity may call them when it is empty.
+	AllowedEntities []string
+
 	// Secret is a helper for a robot location secret.
 	Secret string
 
@@ -409,6 +460,9 @@ type remoteData struct {
 	ConnectionCheckInterval   string                              `json:"connection_check_interval,omitempty"`
 	ReconnectInterval         string                              `json:"reconnect_interval,omitempty"`
 	AssociatedResourceConfigs []resource.AssociatedResourceConfig `json:"service_configs"`
+	Prefix                    string                              `json:"prefix,omitempty"`
+	AllowedAPIs               []string                            `json:"allowed_apis,omitempty"`
+	AllowedEntities           []string                            `json:"allowed_entities,omitempty"`
 
 	// Secret is a helper for a robot location secret.
 	Secret string `json:"secret"`
@@ -438,6 +492,9 @@ func (conf *Remote) UnmarshalJSON(data []byte) error {
 		ManagedBy:                 temp.ManagedBy,
 		Insecure:                  temp.Insecure,
 		AssociatedResourceConfigs: temp.AssociatedResourceConfigs,
+		Prefix:                    temp.Prefix,
+		AllowedAPIs:               temp.AllowedAPIs,
+		AllowedEntities:           temp.AllowedEntities,
 		Secret:                    temp.Secret,
 	}
 	if temp.ConnectionCheckInterval != "" {
@@ -467,6 +524,9 @@ func (conf Remote) MarshalJSON() ([]byte, error) {
 		ManagedBy:                 conf.ManagedBy,
 		Insecure:                  conf.Insecure,
 		AssociatedResourceConfigs: conf.AssociatedResourceConfigs,
+		Prefix:                    conf.Prefix,
+		AllowedAPIs:               conf.AllowedAPIs,
+		AllowedEntities:           conf.AllowedEntities,
 		Secret:                    conf.Secret,
 	}
 	if conf.ConnectionCheckInterval != 0 {
@@ -505,6 +565,19 @@ func (conf *Remote) Validate(path string) ([]string, error) {
 	return nil, conf.cachedErr
 }
 
+// AllowsAPI returns whether resources of the API are integrated from the remote.
+func (conf *Remote) AllowsAPI(api resource.API) bool {
+	if len(conf.AllowedAPIs) == 0 {
+		return true
+	}
+	for _, allowed := range conf.AllowedAPIs {
+		if allowed == api.String() {
+			return true
+		}
+	}
+	return false
+}
+
 // adjustPartialNames assumes this config comes from a place where the associated
 // config type names are partially stored (JSON/Proto/Database) and will
 // fix them up to the builtin values they are intended for.
@@ -531,6 +604,22 @@ func (conf *Remote) validate(path string) error {
 			return resource.NewConfigValidationFieldRequiredError(path, "frame.parent")
 		}
 	}
+	if conf.Prefix != "" {
+		// The prefix must itself start a valid resource name.
+		if err := rutils.ValidateResourceName(conf.Prefix + "a"); err != nil {
+			return resource.NewConfigValidationError(path, errors.Errorf("invalid prefix %q", conf.Prefix))
+		}
+	}
+	for _, api := range conf.AllowedAPIs {
+		if _, err := resource.NewAPIFromString(api); err != nil {
+			return resource.NewConfigValidationError(path, errors.Wrap(err, "invalid allowed_apis"))
+		}
+	}
+	for _, entity := range conf.AllowedEntities {
+		if entity == "" {
+			return resource.NewConfigValidationError(path, errors.New("allowed_entities can't contain an empty entity"))
+		}
+	}
 
 	if conf.Secret != "" {
 		conf.Auth = RemoteAuth{
@@ -564,6 +653,9 @@ type Cloud struct {
 	LogPath           string
 	AppAddress        string
 	RefreshInterval   time.Duration
+	// MaxCacheStaleness bounds how old a cached config may be for the robot to start from it when
+	// the cloud is unreachable. Zero means a cached config of any age is used.
+	MaxCacheStaleness time.Duration
 
 	// cached by us and fetched from a non-config endpoint.
 	TLSCertificate string
@@ -590,6 +682,7 @@ type cloudData struct {
 	Path              string           `json:"path,omitempty"`
 	LogPath           string           `json:"log_path,omitempty"`
 	RefreshInterval   string           `json:"refresh_interval,omitempty"`
+	MaxCacheStaleness string           `json:"max_cache_staleness,omitempty"`
 
 	// cached by us and fetched from a non-config endpoint.
 	TLSCertificate string `json:"tls_certificate"`
@@ -628,6 +721,13 @@ func (config *Cloud) UnmarshalJSON(data []byte) error {
 		}
 		config.RefreshInterval = dur
 	}
+	if temp.MaxCacheStaleness != "" {
+		dur, err := time.ParseDuration(temp.MaxCacheStaleness)
+		if err != nil {
+			return err
+		}
+		config.MaxCacheStaleness = dur
+	}
 	return nil
 }
 
@@ -655,6 +755,9 @@ func (config Cloud) MarshalJSON() ([]byte, error) {
 	if config.RefreshInterval != 0 {
 		temp.RefreshInterval = config.RefreshInterval.String()
 	}
+	if config.MaxCacheStaleness != 0 {
+		temp.MaxCacheStaleness = config.MaxCacheStaleness.String()
+	}
 	return json.Marshal(temp)
 }
 
@@ -676,6 +779,9 @@ func (config *Cloud) Validate(path string, fromCloud bool) error {
 	if config.RefreshInterval == 0 {
 		config.RefreshInterval = 10 * time.Second
 	}
+	if config.MaxCacheStaleness < 0 {
+		return resource.NewConfigValidationError(path, errors.New("max_cache_staleness cannot be negative"))
+	}
 	return nil
 }
 
@@ -735,6 +841,10 @@ type NetworkConfigData struct {
 
 	// TrafficTunnelEndpoints are the allowed ports and options for tunneling.
 	TrafficTunnelEndpoints []TrafficTunnelEndpoint `json:"traffic_tunnel_endpoints"`
+
+	// GRPCCompression is the compressor, "gzip" or "snappy", that the gRPC server compresses
+	// responses with when clients support it. Empty to leave responses uncompressed.
+	GRPCCompression string `json:"grpc_compression,omitempty"`
 }
 
 // MarshalJSON marshals out this config.
@@ -765,6 +875,9 @@ func (nc *NetworkConfig) Validate(path string) error {
 	if (nc.TLSCertFile == "") != (nc.TLSKeyFile == "") {
 		return resource.NewConfigValidationError(path, errors.New("must provide both tls_cert_file and tls_key_file"))
 	}
+	if err := rgrpc.ValidateCompression(nc.GRPCCompression); err != nil {
+		return resource.NewConfigValidationError(path, err)
+	}
 
 	return nc.Sessions.Validate(path + ".sessions")
 }
@@ -1209,6 +1322,15 @@ func (p *PackageConfig) sanitizedVersion() string {
 type Revision struct {
 	Revision    string
 	LastUpdated time.Time
+	// CachedAt is non-zero when the robot is running on a cached cloud config. It is the time that
+	// cached config was last written.
+	CachedAt time.Time
+}
+
+// FromCache returns whether the revision was loaded from the local config cache rather than the
+// cloud.
+func (r Revision) FromCache() bool {
+	return !r.CachedAt.IsZero()
 }
 
 // UpdateLoggerRegistryFromConfig will update the passed in registry with all log patterns
@@ -1256,4 +1378,5 @@ func UpdateLoggerRegistryFromConfig(registry *logging.Registry, cfg *Config, log
 		registry.DeduplicateLogs.Store(!cfg.DisableLogDeduplication)
 		logger.Infof("Noisy log deduplication is now %s", state)
 	}
+	registry.UpdateDeduplicationOptOuts(cfg.DisableLogDeduplicationFor, logger)
 }
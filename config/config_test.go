@@ -184,6 +184,15 @@ func TestConfigWithLogDeclarations(t *testing.T) {
 	// The data manager service is left unconfigured.
 	test.That(t, cfg.Services[1].Name, test.ShouldEqual, "dm")
 	test.That(t, cfg.Services[1].LogConfiguration, test.ShouldBeNil)
+
+	test.That(t, cfg.LogFormat, test.ShouldEqual, logging.LogFormatJSON)
+	test.That(t, cfg.DisableLogDeduplicationFor, test.ShouldResemble, []string{"rdk.resource_manager.rdk:component:motor/*"})
+	test.That(t, cfg.LogSinks, test.ShouldResemble, []logging.SinkConfig{{
+		Type:     logging.SinkTypeRemoteSyslog,
+		Address:  "logs.example.com:6514",
+		Protocol: logging.SyslogProtocolTLS,
+		Level:    "warn",
+	}})
 }
 
 func TestConfigEnsure(t *testing.T) {
@@ -235,6 +244,24 @@ func TestConfigEnsure(t *testing.T) {
 	}
 	test.That(t, invalidRemotes.Ensure(false, logger), test.ShouldBeNil)
 
+	invalidLogFormat := config.Config{DisablePartialStart: true, LogFormat: "xml"}
+	err = invalidLogFormat.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `log_format`)
+	invalidLogFormat.LogFormat = logging.LogFormatJSON
+	test.That(t, invalidLogFormat.Ensure(false, logger), test.ShouldBeNil)
+
+	invalidLogSinks := config.Config{
+		DisablePartialStart: true,
+		LogSinks:            []logging.SinkConfig{{Type: logging.SinkTypeRemoteSyslog}},
+	}
+	err = invalidLogSinks.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `log_sinks.0`)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `address is required`)
+	invalidLogSinks.LogSinks[0].Address = "logs.example.com:514"
+	test.That(t, invalidLogSinks.Ensure(false, logger), test.ShouldBeNil)
+
 	invalidComponents := config.Config{
 		DisablePartialStart: true,
 		Components:          []resource.Config{{}},
@@ -362,6 +389,15 @@ func TestConfigEnsure(t *testing.T) {
 	invalidNetwork.Network.Sessions.HeartbeatWindow = 30 * time.Millisecond
 	test.That(t, invalidNetwork.Ensure(false, logger), test.ShouldBeNil)
 
+	invalidNetwork.Network.GRPCCompression = "zstd"
+	err = invalidNetwork.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `unknown gRPC compression`)
+
+	invalidNetwork.Network.GRPCCompression = "snappy"
+	test.That(t, invalidNetwork.Ensure(false, logger), test.ShouldBeNil)
+	invalidNetwork.Network.GRPCCompression = ""
+
 	invalidNetwork.Network.BindAddress = "woop"
 	err = invalidNetwork.Ensure(false, logger)
 	test.That(t, err, test.ShouldNotBeNil)
@@ -691,6 +727,57 @@ func TestRemoteValidate(t *testing.T) {
 			"must start with a letter or number and must only contain letters, numbers, dashes, and underscores",
 		)
 	})
+
+	t.Run("remote isolation", func(t *testing.T) {
+		validRemote := config.Remote{
+			Name:            "foo",
+			Address:         "address",
+			Prefix:          "team-a_",
+			AllowedAPIs:     []string{"rdk:component:camera", "rdk:service:vision"},
+			AllowedEntities: []string{"team-a@viam.com"},
+		}
+		_, err := validRemote.Validate("path")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, validRemote.AllowsAPI(camera.API), test.ShouldBeTrue)
+		test.That(t, validRemote.AllowsAPI(arm.API), test.ShouldBeFalse)
+		test.That(t, (&config.Remote{}).AllowsAPI(arm.API), test.ShouldBeTrue)
+
+		invalidRemote := config.Remote{Name: "foo", Address: "address", Prefix: "team.a"}
+		_, err = invalidRemote.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `invalid prefix "team.a"`)
+
+		invalidRemote = config.Remote{Name: "foo", Address: "address", AllowedAPIs: []string{"camera"}}
+		_, err = invalidRemote.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "invalid allowed_apis")
+
+		invalidRemote = config.Remote{Name: "foo", Address: "address", AllowedEntities: []string{""}}
+		_, err = invalidRemote.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "allowed_entities can't contain an empty entity")
+	})
+
+	t.Run("remote isolation json", func(t *testing.T) {
+		var remote config.Remote
+		err := json.Unmarshal([]byte(`{
+			"name": "foo",
+			"address": "address",
+			"prefix": "team-a_",
+			"allowed_apis": ["rdk:component:camera"],
+			"allowed_entities": ["team-a@viam.com"]
+		}`), &remote)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, remote.Prefix, test.ShouldEqual, "team-a_")
+		test.That(t, remote.AllowedAPIs, test.ShouldResemble, []string{"rdk:component:camera"})
+		test.That(t, remote.AllowedEntities, test.ShouldResemble, []string{"team-a@viam.com"})
+
+		data, err := json.Marshal(remote)
+		test.That(t, err, test.ShouldBeNil)
+		var roundTripped config.Remote
+		test.That(t, json.Unmarshal(data, &roundTripped), test.ShouldBeNil)
+		test.That(t, roundTripped.Equals(remote), test.ShouldBeTrue)
+	})
 }
 
 func TestCopyOnlyPublicFields(t *testing.T) {
@@ -1287,6 +1374,21 @@ func TestConfigJSONMarshalRoundtrip(t *testing.T) {
 				DisableLogDeduplication: true,
 			},
 		},
+		{
+			name: "cloud max cache staleness",
+			c: config.Config{
+				Cloud: &config.Cloud{
+					ID:                "some_id",
+					MaxCacheStaleness: 72 * time.Hour,
+				},
+			},
+			expected: config.Config{
+				Cloud: &config.Cloud{
+					ID:                "some_id",
+					MaxCacheStaleness: 72 * time.Hour,
+				},
+			},
+		},
 		{
 			name: "package path",
 			c: config.Config{
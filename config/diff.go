@@ -115,49 +115,6 @@ func prettyDiff(left, right Config) (string, error) {
 	left = leftClone
 	right = rightClone
 
-	mask := "******"
-	sanitizeConfig := func(conf *Config) {
-		// Note(erd): keep in mind this will destroy the actual pretty diffing of these which
-		// is fine because we aren't considering pretty diff changes to these fields at this level
-		// of the stack.
-		if conf.Cloud != nil {
-			if conf.Cloud.Secret != "" {
-				conf.Cloud.Secret = mask
-			}
-			if conf.Cloud.LocationSecret != "" {
-				conf.Cloud.LocationSecret = mask
-			}
-			for i := range conf.Cloud.LocationSecrets {
-				if conf.Cloud.LocationSecrets[i].Secret != "" {
-					conf.Cloud.LocationSecrets[i].Secret = mask
-				}
-			}
-			// Not really a secret but annoying to diff
-			if conf.Cloud.TLSCertificate != "" {
-				conf.Cloud.TLSCertificate = mask
-			}
-			if conf.Cloud.TLSPrivateKey != "" {
-				conf.Cloud.TLSPrivateKey = mask
-			}
-		}
-		for _, hdlr := range conf.Auth.Handlers {
-			for key := range hdlr.Config {
-				hdlr.Config[key] = mask
-			}
-		}
-		for i := range conf.Remotes {
-			rem := &conf.Remotes[i]
-			if rem.Secret != "" {
-				rem.Secret = mask
-			}
-			if rem.Auth.Credentials != nil {
-				rem.Auth.Credentials.Payload = mask
-			}
-			if rem.Auth.SignalingCreds != nil {
-				rem.Auth.SignalingCreds.Payload = mask
-			}
-		}
-	}
 	sanitizeConfig(&left)
 	sanitizeConfig(&right)
 
@@ -182,6 +139,53 @@ func prettyDiff(left, right Config) (string, error) {
 	return dmp.DiffPrettyText(filteredDiffs), nil
 }
 
+// redactedMask replaces secrets in configs which are logged or shared.
+const redactedMask = "******"
+
+// sanitizeConfig replaces the secrets of the cloud, auth and remotes configs of `conf` with a mask.
+func sanitizeConfig(conf *Config) {
+	// Note(erd): keep in mind this will destroy the actual pretty diffing of these which
+	// is fine because we aren't considering pretty diff changes to these fields at this level
+	// of the stack.
+	if conf.Cloud != nil {
+		if conf.Cloud.Secret != "" {
+			conf.Cloud.Secret = redactedMask
+		}
+		if conf.Cloud.LocationSecret != "" {
+			conf.Cloud.LocationSecret = redactedMask
+		}
+		for i := range conf.Cloud.LocationSecrets {
+			if conf.Cloud.LocationSecrets[i].Secret != "" {
+				conf.Cloud.LocationSecrets[i].Secret = redactedMask
+			}
+		}
+		// Not really a secret but annoying to diff
+		if conf.Cloud.TLSCertificate != "" {
+			conf.Cloud.TLSCertificate = redactedMask
+		}
+		if conf.Cloud.TLSPrivateKey != "" {
+			conf.Cloud.TLSPrivateKey = redactedMask
+		}
+	}
+	for _, hdlr := range conf.Auth.Handlers {
+		for key := range hdlr.Config {
+			hdlr.Config[key] = redactedMask
+		}
+	}
+	for i := range conf.Remotes {
+		rem := &conf.Remotes[i]
+		if rem.Secret != "" {
+			rem.Secret = redactedMask
+		}
+		if rem.Auth.Credentials != nil {
+			rem.Auth.Credentials.Payload = redactedMask
+		}
+		if rem.Auth.SignalingCreds != nil {
+			rem.Auth.SignalingCreds.Payload = redactedMask
+		}
+	}
+}
+
 // String returns a pretty version of the diff.
 func (diff *Diff) String() string {
 	return diff.PrettyDiff
@@ -524,6 +528,12 @@ func diffLogCfg(left, right *Config, servicesDifferent, componentsDifferent bool
 	if !reflect.DeepEqual(left.LogConfig, right.LogConfig) {
 		return true
 	}
+	if !reflect.DeepEqual(left.LogSinks, right.LogSinks) || left.LogFormat != right.LogFormat {
+		return true
+	}
+	if !reflect.DeepEqual(left.DisableLogDeduplicationFor, right.DisableLogDeduplicationFor) {
+		return true
+	}
 	// If there was any change in services or components; attempt to update logger levels.
 	if servicesDifferent || componentsDifferent {
 		return true
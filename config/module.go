@@ -32,6 +32,8 @@ type Module struct {
 	// Name is an arbitrary name used to identify the module, and is used to name it's socket as well.
 	Name string `json:"name"`
 	// ExePath is the path (either absolute, or relative to the working directory) to the executable module file.
+	// A path ending in ".wasm" is a WebAssembly module, which is run in-process instead of as a module
+	// process; see the module/wasm package.
 	ExePath string `json:"executable_path"`
 	// LogLevel represents the level at which the module should log its messages. It will be passed as a commandline
 	// argument "log-level" (i.e. preceded by "--log-level=") to the module executable. If unset or set to an empty
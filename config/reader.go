@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"sort"
+	"time"
 
 	"github.com/a8m/envsubst"
 	"github.com/pkg/errors"
@@ -107,6 +108,15 @@ func readFromCache(id string) (*Config, error) {
 	return unprocessedConfig, nil
 }
 
+// cacheLastUpdated returns the time the cached config for the given robot part was last written.
+func cacheLastUpdated(id string) (time.Time, error) {
+	fInfo, err := os.Stat(getCloudCacheFilePath(id))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return fInfo.ModTime(), nil
+}
+
 func clearCache(id string) {
 	utils.UncheckedErrorFunc(func() error {
 		return os.Remove(getCloudCacheFilePath(id))
@@ -199,6 +209,11 @@ func readFromCloud(
 	if cfg.Cloud == nil {
 		return nil, errors.New("expected config to have cloud section")
 	}
+	if cached {
+		if cachedAt, err := cacheLastUpdated(cloudCfg.ID); err == nil {
+			cfg.CachedAt = cachedAt
+		}
+	}
 
 	tls := tlsConfig{
 		// both fields are empty if not cached, since its a separate request, which we
@@ -643,9 +658,15 @@ func getFromCloudOrCache(
 			}
 
 			lastUpdated := "unknown"
-			if fInfo, err := os.Stat(getCloudCacheFilePath(cloudCfg.ID)); err == nil {
+			cachedAt, statErr := cacheLastUpdated(cloudCfg.ID)
+			if statErr == nil {
 				// Use logging.DefaultTimeFormatStr since this time will be logged.
-				lastUpdated = fInfo.ModTime().Format(logging.DefaultTimeFormatStr)
+				lastUpdated = cachedAt.Format(logging.DefaultTimeFormatStr)
+			}
+			if staleErr := checkCacheStaleness(cloudCfg.MaxCacheStaleness, cachedAt, statErr); staleErr != nil {
+				logger.Errorw("unable to get cloud config and cached config is too stale to use",
+					"config last updated", lastUpdated, "error", err)
+				return nil, cached, errors.Wrapf(err, "error getting cloud config, cached config cannot be used (%v)", staleErr)
 			}
 			logger.Warnw("unable to get cloud config; using cached version", "config last updated", lastUpdated, "error", err)
 			cached = true
@@ -658,6 +679,22 @@ func getFromCloudOrCache(
 	return cfg, cached, nil
 }
 
+// checkCacheStaleness returns an error if a cached config last written at `cachedAt` is older than
+// `maxStaleness`. A zero `maxStaleness` allows a cached config of any age.
+func checkCacheStaleness(maxStaleness time.Duration, cachedAt time.Time, statErr error) error {
+	if maxStaleness <= 0 {
+		return nil
+	}
+	if statErr != nil {
+		return errors.Wrap(statErr, "cannot determine age of cached config")
+	}
+	if age := time.Since(cachedAt); age > maxStaleness {
+		return errors.Errorf("cached config is %v old, which exceeds max_cache_staleness of %v",
+			age.Round(time.Second), maxStaleness)
+	}
+	return nil
+}
+
 // getFromCloudGRPC actually does the fetching of the robot config from the gRPC endpoint.
 func getFromCloudGRPC(ctx context.Context, cloudCfg *Cloud, logger logging.Logger, conn rpc.ClientConn) (*Config, bool, error) {
 	shouldCheckCacheOnFailure := true
@@ -225,6 +225,9 @@ func TestStoreToCache(t *testing.T) {
 	cloudCfg, err := readFromCloud(ctx, cfg, nil, true, false, logger, appConn)
 	test.That(t, err, test.ShouldBeNil)
 	cloudCfg.toCache = nil
+	// the app is unreachable, so the config must have come from the cache.
+	test.That(t, cloudCfg.CachedAt.IsZero(), test.ShouldBeFalse)
+	cloudCfg.CachedAt = time.Time{}
 	test.That(t, cloudCfg, test.ShouldResemble, cfg)
 
 	// Modify our config
@@ -248,7 +251,36 @@ func TestStoreToCache(t *testing.T) {
 	cloudCfg3, err := readFromCloud(ctx, cfg, nil, true, false, logger, appConn)
 	test.That(t, err, test.ShouldBeNil)
 	cloudCfg3.toCache = nil
+	cloudCfg3.CachedAt = time.Time{}
 	test.That(t, cloudCfg3, test.ShouldResemble, cfg)
+
+	// a cached config older than max_cache_staleness is refused.
+	staleTime := time.Now().Add(-time.Hour)
+	test.That(t, os.Chtimes(getCloudCacheFilePath(cloud.ID), staleTime, staleTime), test.ShouldBeNil)
+	cfg.Cloud.MaxCacheStaleness = time.Minute
+	_, err = readFromCloud(ctx, cfg, nil, true, false, logger, appConn)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "exceeds max_cache_staleness")
+
+	cfg.Cloud.MaxCacheStaleness = 2 * time.Hour
+	cloudCfg4, err := readFromCloud(ctx, cfg, nil, true, false, logger, appConn)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cloudCfg4.CachedAt.Unix(), test.ShouldEqual, staleTime.Unix())
+}
+
+func TestCheckCacheStaleness(t *testing.T) {
+	now := time.Now()
+	test.That(t, checkCacheStaleness(0, now.Add(-time.Hour), nil), test.ShouldBeNil)
+	test.That(t, checkCacheStaleness(0, time.Time{}, os.ErrNotExist), test.ShouldBeNil)
+	test.That(t, checkCacheStaleness(time.Hour, now.Add(-time.Minute), nil), test.ShouldBeNil)
+
+	err := checkCacheStaleness(time.Minute, now.Add(-time.Hour), nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "exceeds max_cache_staleness")
+
+	err = checkCacheStaleness(time.Minute, time.Time{}, os.ErrNotExist)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cannot determine age")
 }
 
 func TestCacheInvalidation(t *testing.T) {
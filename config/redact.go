@@ -0,0 +1,72 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// secretKeyWords are the words which mark the attributes and environment variables redacted by
+// Redacted, such as "api_key", "password" or "AWS_SECRET_ACCESS_KEY".
+var secretKeyWords = []string{"secret", "password", "passwd", "token", "api_key", "apikey", "private_key", "credential"}
+
+// Redacted returns a copy of `conf` which can be shared, such as with support. The cloud, auth and
+// remote secrets are masked like in pretty config diffs, as are the resource attributes and module
+// environment variables whose names look like secrets.
+func Redacted(conf *Config) (*Config, error) {
+	md, err := json.Marshal(conf)
+	if err != nil {
+		return nil, err
+	}
+	var redacted Config
+	if err := json.Unmarshal(md, &redacted); err != nil {
+		return nil, err
+	}
+	sanitizeConfig(&redacted)
+	for i := range redacted.Components {
+		redactSecretValues(redacted.Components[i].Attributes)
+	}
+	for i := range redacted.Services {
+		redactSecretValues(redacted.Services[i].Attributes)
+	}
+	for _, mod := range redacted.Modules {
+		for key := range mod.Environment {
+			if isSecretKey(key) {
+				mod.Environment[key] = redactedMask
+			}
+		}
+	}
+	return &redacted, nil
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range secretKeyWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSecretValues masks the values of secret keys in `attributes`, including in nested objects
+// and lists of objects.
+func redactSecretValues(attributes map[string]interface{}) {
+	for key, value := range attributes {
+		if isSecretKey(key) {
+			attributes[key] = redactedMask
+			continue
+		}
+		redactNested(value)
+	}
+}
+
+func redactNested(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redactSecretValues(v)
+	case []interface{}:
+		for _, elem := range v {
+			redactNested(elem)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package config_test
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+func TestRedacted(t *testing.T) {
+	conf := &config.Config{
+		Cloud: &config.Cloud{ID: "part", Secret: "cloud secret", TLSPrivateKey: "private key"},
+		Components: []resource.Config{{
+			Name:  "arm1",
+			API:   arm.API,
+			Model: fakeModel,
+			Attributes: map[string]interface{}{
+				"host":     "10.0.0.2",
+				"Password": "hunter2",
+				"auth":     map[string]interface{}{"user": "admin", "api_token": "token"},
+				"cameras":  []interface{}{map[string]interface{}{"name": "cam", "secret_key": "key"}},
+			},
+		}},
+		Modules: []config.Module{{
+			Name:        "mod",
+			ExePath:     "/bin/mod",
+			Environment: map[string]string{"LOG_LEVEL": "debug", "AWS_SECRET_ACCESS_KEY": "aws"},
+		}},
+		Remotes: []config.Remote{{Name: "remote", Address: "remote:8080", Secret: "remote secret"}},
+	}
+
+	redacted, err := config.Redacted(conf)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, redacted.Cloud.ID, test.ShouldEqual, "part")
+	test.That(t, redacted.Cloud.Secret, test.ShouldEqual, "******")
+	test.That(t, redacted.Cloud.TLSPrivateKey, test.ShouldEqual, "******")
+	test.That(t, redacted.Remotes[0].Secret, test.ShouldEqual, "******")
+	test.That(t, redacted.Components[0].Attributes, test.ShouldResemble, utils.AttributeMap{
+		"host":     "10.0.0.2",
+		"Password": "******",
+		"auth":     map[string]interface{}{"user": "admin", "api_token": "******"},
+		"cameras":  []interface{}{map[string]interface{}{"name": "cam", "secret_key": "******"}},
+	})
+	test.That(t, redacted.Modules[0].Environment, test.ShouldResemble, map[string]string{
+		"LOG_LEVEL": "debug", "AWS_SECRET_ACCESS_KEY": "******",
+	})
+
+	// the original is untouched
+	test.That(t, conf.Cloud.Secret, test.ShouldEqual, "cloud secret")
+	test.That(t, conf.Components[0].Attributes["Password"], test.ShouldEqual, "hunter2")
+}
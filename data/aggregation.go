@@ -0,0 +1,215 @@
+package data
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The functions an AggregatingCaptureBuffer can reduce the numbers of the readings of a window with.
+const (
+	AggregateMean = "mean"
+	AggregateMin  = "min"
+	AggregateMax  = "max"
+)
+
+// AggregateCountKey is the key of the number of readings reduced into an aggregated reading.
+const AggregateCountKey = "count"
+
+// DefaultAggregateFunctions are the functions readings are reduced with if none are configured.
+var DefaultAggregateFunctions = []string{AggregateMean, AggregateMin, AggregateMax}
+
+// ValidateAggregateFunction returns an error if fn is not a function readings can be reduced with.
+func ValidateAggregateFunction(fn string) error {
+	switch fn {
+	case AggregateMean, AggregateMin, AggregateMax:
+		return nil
+	default:
+		return errors.Errorf("unknown aggregate function %q, must be one of %s", fn, strings.Join(DefaultAggregateFunctions, ", "))
+	}
+}
+
+// AggregatingCaptureBuffer is a CaptureBufferedWriter which reduces the tabular readings of each
+// window of time to a single reading before writing it to another CaptureBufferedWriter, so that
+// high rate captures take less space and cost less to sync. Windows are aligned to multiples of
+// the interval, by the time readings were requested.
+//
+// An aggregated reading has a copy of the last reading of its window for each function, in which
+// every number is replaced by that function of the values of the number over the window, and the
+// number of readings of the window. For example, with the mean and max functions:
+//
+//	{"mean": {"readings": {"temp": 20.5}}, "max": {"readings": {"temp": 22}}, "count": 10}
+//
+// Binary data is written as is.
+type AggregatingCaptureBuffer struct {
+	target    CaptureBufferedWriter
+	interval  time.Duration
+	functions []string
+
+	lock   sync.Mutex
+	window *aggregateWindow
+}
+
+// NewAggregatingCaptureBuffer returns an AggregatingCaptureBuffer writing to target. If functions
+// is empty, DefaultAggregateFunctions are used.
+func NewAggregatingCaptureBuffer(
+	target CaptureBufferedWriter,
+	interval time.Duration,
+	functions []string,
+) (*AggregatingCaptureBuffer, error) {
+	if interval <= 0 {
+		return nil, errors.Errorf("aggregation interval must be positive, got %v", interval)
+	}
+	if len(functions) == 0 {
+		functions = DefaultAggregateFunctions
+	}
+	for _, fn := range functions {
+		if err := ValidateAggregateFunction(fn); err != nil {
+			return nil, err
+		}
+	}
+	return &AggregatingCaptureBuffer{target: target, interval: interval, functions: functions}, nil
+}
+
+// WriteBinary writes the items to the target.
+func (b *AggregatingCaptureBuffer) WriteBinary(items []*v1.SensorData) error {
+	return b.target.WriteBinary(items)
+}
+
+// WriteTabular adds the reading to its window, first writing the aggregated reading of the
+// previous window if the reading is in a new one.
+func (b *AggregatingCaptureBuffer) WriteTabular(item *v1.SensorData) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if IsBinary(item) {
+		return errInvalidTabularSensorData
+	}
+
+	start := item.GetMetadata().GetTimeRequested().AsTime().Truncate(b.interval)
+	if b.window != nil && !b.window.start.Equal(start) {
+		if err := b.writeWindow(); err != nil {
+			return err
+		}
+	}
+	if b.window == nil {
+		b.window = &aggregateWindow{start: start, numbers: map[string]*aggregateNumber{}}
+	}
+	b.window.add(item)
+	return nil
+}
+
+// Flush writes the aggregated reading of the current window, cutting it short, and flushes the
+// target.
+func (b *AggregatingCaptureBuffer) Flush() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.window != nil {
+		if err := b.writeWindow(); err != nil {
+			return err
+		}
+	}
+	return b.target.Flush()
+}
+
+// Path returns the path of the target.
+func (b *AggregatingCaptureBuffer) Path() string {
+	return b.target.Path()
+}
+
+func (b *AggregatingCaptureBuffer) writeWindow() error {
+	window := b.window
+	b.window = nil
+	return b.target.WriteTabular(window.aggregate(b.functions))
+}
+
+// aggregateWindow accumulates the readings of a window.
+type aggregateWindow struct {
+	start         time.Time
+	timeRequested *timestamppb.Timestamp
+	last          *v1.SensorData
+	count         int
+	// numbers are the accumulated values of the numbers of the readings, by the path of their fields
+	numbers map[string]*aggregateNumber
+}
+
+type aggregateNumber struct {
+	sum, min, max float64
+	count         int
+}
+
+func (w *aggregateWindow) add(item *v1.SensorData) {
+	if w.count == 0 {
+		w.timeRequested = item.GetMetadata().GetTimeRequested()
+	}
+	w.last = item
+	w.count++
+
+	var accumulate func(path string, v *structpb.Value)
+	accumulate = func(path string, v *structpb.Value) {
+		switch kind := v.GetKind().(type) {
+		case *structpb.Value_StructValue:
+			for key, field := range kind.StructValue.GetFields() {
+				accumulate(path+"\x00"+key, field)
+			}
+		case *structpb.Value_NumberValue:
+			n, ok := w.numbers[path]
+			if !ok {
+				w.numbers[path] = &aggregateNumber{sum: kind.NumberValue, min: kind.NumberValue, max: kind.NumberValue, count: 1}
+				return
+			}
+			n.sum += kind.NumberValue
+			n.min = min(n.min, kind.NumberValue)
+			n.max = max(n.max, kind.NumberValue)
+			n.count++
+		}
+	}
+	accumulate("", structpb.NewStructValue(item.GetStruct()))
+}
+
+// aggregate returns the aggregated reading of the window. It is timestamped with the time the
+// first reading was requested and the last was received.
+func (w *aggregateWindow) aggregate(functions []string) *v1.SensorData {
+	fields := map[string]*structpb.Value{
+		AggregateCountKey: structpb.NewNumberValue(float64(w.count)),
+	}
+	for _, fn := range functions {
+		var reduce func(path string, v *structpb.Value) *structpb.Value
+		reduce = func(path string, v *structpb.Value) *structpb.Value {
+			switch kind := v.GetKind().(type) {
+			case *structpb.Value_StructValue:
+				reduced := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(kind.StructValue.GetFields()))}
+				for key, field := range kind.StructValue.GetFields() {
+					reduced.Fields[key] = reduce(path+"\x00"+key, field)
+				}
+				return structpb.NewStructValue(reduced)
+			case *structpb.Value_NumberValue:
+				n := w.numbers[path]
+				switch fn {
+				case AggregateMin:
+					return structpb.NewNumberValue(n.min)
+				case AggregateMax:
+					return structpb.NewNumberValue(n.max)
+				default:
+					return structpb.NewNumberValue(n.sum / float64(n.count))
+				}
+			default:
+				// values other than numbers keep their last value
+				return v
+			}
+		}
+		fields[fn] = reduce("", structpb.NewStructValue(w.last.GetStruct()))
+	}
+
+	return &v1.SensorData{
+		Metadata: &v1.SensorMetadata{
+			TimeRequested: w.timeRequested,
+			TimeReceived:  w.last.GetMetadata().GetTimeReceived(),
+		},
+		Data: &v1.SensorData_Struct{Struct: &structpb.Struct{Fields: fields}},
+	}
+}
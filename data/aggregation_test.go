@@ -0,0 +1,113 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// recordingWriter is a CaptureBufferedWriter which keeps what is written to it.
+type recordingWriter struct {
+	tabular []*v1.SensorData
+	binary  [][]*v1.SensorData
+	flushes int
+}
+
+func (w *recordingWriter) WriteBinary(items []*v1.SensorData) error {
+	w.binary = append(w.binary, items)
+	return nil
+}
+
+func (w *recordingWriter) WriteTabular(item *v1.SensorData) error {
+	w.tabular = append(w.tabular, item)
+	return nil
+}
+
+func (w *recordingWriter) Flush() error {
+	w.flushes++
+	return nil
+}
+
+func (w *recordingWriter) Path() string {
+	return "recording"
+}
+
+func TestAggregatingCaptureBuffer(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	reading := func(offset time.Duration, temp float64, state string) *v1.SensorData {
+		s, err := structpb.NewStruct(map[string]interface{}{
+			"readings": map[string]interface{}{"temp": temp, "state": state, "nested": map[string]interface{}{"value": -temp}},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		return &v1.SensorData{
+			Metadata: &v1.SensorMetadata{
+				TimeRequested: timestamppb.New(start.Add(offset)),
+				TimeReceived:  timestamppb.New(start.Add(offset + time.Millisecond)),
+			},
+			Data: &v1.SensorData_Struct{Struct: s},
+		}
+	}
+
+	_, err := NewAggregatingCaptureBuffer(&recordingWriter{}, 0, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewAggregatingCaptureBuffer(&recordingWriter{}, time.Second, []string{"median"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unknown aggregate function")
+
+	target := &recordingWriter{}
+	b, err := NewAggregatingCaptureBuffer(target, time.Second, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, b.Path(), test.ShouldEqual, "recording")
+
+	for i, temp := range []float64{10, 20, 60} {
+		test.That(t, b.WriteTabular(reading(time.Duration(i)*300*time.Millisecond, temp, "ok")), test.ShouldBeNil)
+	}
+	test.That(t, target.tabular, test.ShouldBeEmpty)
+
+	// a reading of the next window writes the aggregate of the previous one
+	test.That(t, b.WriteTabular(reading(1100*time.Millisecond, 5, "hot")), test.ShouldBeNil)
+	test.That(t, target.tabular, test.ShouldHaveLength, 1)
+	aggregated := target.tabular[0]
+	test.That(t, aggregated.GetMetadata().GetTimeRequested().AsTime(), test.ShouldEqual, start)
+	test.That(t, aggregated.GetMetadata().GetTimeReceived().AsTime(), test.ShouldEqual, start.Add(601*time.Millisecond))
+	expected := func(temp, value float64) map[string]interface{} {
+		return map[string]interface{}{
+			"readings": map[string]interface{}{"temp": temp, "state": "ok", "nested": map[string]interface{}{"value": value}},
+		}
+	}
+	test.That(t, aggregated.GetStruct().AsMap(), test.ShouldResemble, map[string]interface{}{
+		"count": 3.,
+		"mean":  expected(30, -30),
+		"min":   expected(10, -60),
+		"max":   expected(60, -10),
+	})
+
+	// flushing writes the current window
+	test.That(t, b.Flush(), test.ShouldBeNil)
+	test.That(t, target.flushes, test.ShouldEqual, 1)
+	test.That(t, target.tabular, test.ShouldHaveLength, 2)
+	test.That(t, target.tabular[1].GetStruct().AsMap()["count"], test.ShouldEqual, 1.)
+	test.That(t, b.Flush(), test.ShouldBeNil)
+	test.That(t, target.tabular, test.ShouldHaveLength, 2)
+
+	test.That(t, b.WriteTabular(binarySensorData), test.ShouldBeError, errInvalidTabularSensorData)
+	test.That(t, b.WriteBinary([]*v1.SensorData{binarySensorData}), test.ShouldBeNil)
+	test.That(t, target.binary, test.ShouldHaveLength, 1)
+
+	t.Run("selected functions", func(t *testing.T) {
+		target := &recordingWriter{}
+		b, err := NewAggregatingCaptureBuffer(target, time.Minute, []string{AggregateMax})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, b.WriteTabular(reading(0, 1, "ok")), test.ShouldBeNil)
+		test.That(t, b.WriteTabular(reading(time.Second, 2, "ok")), test.ShouldBeNil)
+		test.That(t, b.Flush(), test.ShouldBeNil)
+		test.That(t, target.tabular, test.ShouldHaveLength, 1)
+		aggregated := target.tabular[0].GetStruct().AsMap()
+		test.That(t, aggregated, test.ShouldHaveLength, 2)
+		test.That(t, aggregated["max"], test.ShouldResemble, expected(2, -1))
+	})
+}
@@ -20,6 +20,7 @@ import (
 
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
+	rutils "go.viam.com/rdk/utils"
 )
 
 // The cutoff at which if interval < cutoff, a sleep based capture func is used instead of a ticker.
@@ -64,6 +65,7 @@ type collector struct {
 
 	captureResults  chan CaptureResult
 	mongoCollection *mongo.Collection
+	publisher       CapturePublisher
 	componentName   string
 	componentType   string
 	methodName      string
@@ -78,6 +80,7 @@ type collector struct {
 	cancelCtx        context.Context
 	cancel           context.CancelFunc
 	captureFunc      CaptureFunc
+	filter           CaptureFilter
 	target           CaptureBufferedWriter
 	lastLoggedErrors map[string]int64
 	dataType         CaptureType
@@ -163,22 +166,18 @@ func (c *collector) sleepBasedCapture(started chan struct{}) {
 }
 
 func (c *collector) tickerBasedCapture(started chan struct{}) {
-	ticker := c.clock.Ticker(c.interval)
-	defer ticker.Stop()
+	worker := rutils.NewPeriodicWorker(rutils.PeriodicWorkerConfig{
+		Name:     c.componentName + "." + c.methodName,
+		Interval: c.interval,
+		Clock:    c.clock,
+		Logger:   c.logger,
+	}, func(context.Context) {
+		c.getAndPushNextReading()
+	})
+	defer worker.Stop()
 
 	close(started)
-	for {
-		if err := c.cancelCtx.Err(); err != nil {
-			return
-		}
-
-		select {
-		case <-c.cancelCtx.Done():
-			return
-		case <-ticker.C:
-			c.getAndPushNextReading()
-		}
-	}
+	<-c.cancelCtx.Done()
 }
 
 func (c *collector) validateReadingType(t CaptureType) error {
@@ -226,6 +225,21 @@ func (c *collector) getAndPushNextReading() {
 		return
 	}
 
+	if c.filter != nil {
+		keep, err := c.filter(c.cancelCtx, result)
+		if c.cancelCtx.Err() != nil {
+			return
+		}
+		if err != nil {
+			c.captureErrors <- errors.Wrap(err, "error while evaluating capture conditions")
+			return
+		}
+		if !keep {
+			c.logger.Debug("capture filtered out by capture conditions")
+			return
+		}
+	}
+
 	select {
 	// If c.captureResults is full, c.captureResults <- a can block indefinitely.
 	// This additional select block allows cancel to
@@ -254,6 +268,7 @@ func NewCollector(captureFunc CaptureFunc, params CollectorParams) (Collector, e
 		componentType:    params.ComponentType,
 		methodName:       params.MethodName,
 		mongoCollection:  params.MongoCollection,
+		publisher:        params.Publisher,
 		captureResults:   make(chan CaptureResult, params.QueueSize),
 		captureErrors:    make(chan error, params.QueueSize),
 		dataType:         params.DataType,
@@ -263,6 +278,7 @@ func NewCollector(captureFunc CaptureFunc, params CollectorParams) (Collector, e
 		cancelCtx:        cancelCtx,
 		cancel:           cancelFunc,
 		captureFunc:      captureFunc,
+		filter:           params.Filter,
 		target:           params.Target,
 		clock:            c,
 		lastLoggedErrors: make(map[string]int64, 0),
@@ -307,6 +323,9 @@ func (c *collector) writeCaptureResults() {
 			}
 
 			c.maybeWriteToMongo(msg)
+			if c.publisher != nil {
+				c.publisher(c.cancelCtx, msg)
+			}
 		}
 	}
 }
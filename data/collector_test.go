@@ -295,6 +295,59 @@ func TestCtxCancelledNotLoggedAfterClose(t *testing.T) {
 	test.That(t, failedLogs.Len(), test.ShouldEqual, 0)
 }
 
+func TestCollectorFilter(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	tmpDir := t.TempDir()
+	target := NewCaptureBuffer(tmpDir, &v1.DataCaptureMetadata{}, 1000)
+	var mu sync.Mutex
+	count := 0
+	countingCapturer := CaptureFunc(func(ctx context.Context, _ map[string]*anypb.Any) (CaptureResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		count++
+		return NewTabularCaptureResultReadings(
+			Timestamps{TimeRequested: dummyTime, TimeReceived: dummyTime}, map[string]interface{}{"count": count})
+	})
+	filtered := make(chan struct{})
+	evenFilter := CaptureFilter(func(ctx context.Context, result CaptureResult) (bool, error) {
+		select {
+		case filtered <- struct{}{}:
+		case <-ctx.Done():
+		}
+		n := result.TabularData.Payload.AsMap()["readings"].(map[string]interface{})["count"].(float64)
+		return int(n)%2 == 0, nil
+	})
+
+	c, err := NewCollector(countingCapturer, CollectorParams{
+		ComponentName: "testComponent",
+		DataType:      CaptureTypeTabular,
+		Filter:        evenFilter,
+		Interval:      time.Millisecond,
+		Target:        target,
+		QueueSize:     queueSize,
+		BufferSize:    bufferSize,
+		Logger:        logger,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	c.Collect()
+	for i := 0; i < 6; i++ {
+		<-filtered
+	}
+	c.Close()
+
+	var readings []*v1.SensorData
+	for _, file := range getAllFiles(tmpDir) {
+		fileReadings, err := SensorDataFromCaptureFilePath(filepath.Join(tmpDir, file.Name()))
+		test.That(t, err, test.ShouldBeNil)
+		readings = append(readings, fileReadings...)
+	}
+	test.That(t, len(readings), test.ShouldBeGreaterThanOrEqualTo, 2)
+	for _, reading := range readings {
+		n := reading.GetStruct().AsMap()["readings"].(map[string]interface{})["count"].(float64)
+		test.That(t, int(n)%2, test.ShouldEqual, 0)
+	}
+}
+
 func TestLogErrorsOnlyOnce(t *testing.T) {
 	// Set up a collector.
 	logger, logs := logging.NewObservedTestLogger(t)
@@ -0,0 +1,550 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ParquetCaptureFileExt is the file extension of completed parquet capture files. While they are
+// being written, parquet capture files have the InProgressCaptureFileExt extension.
+const ParquetCaptureFileExt = ".parquet"
+
+// The file formats tabular data can be captured to.
+const (
+	// CaptureFileFormatCapture writes tabular data to .capture files, one SensorData message per reading.
+	CaptureFileFormatCapture = "capture"
+	// CaptureFileFormatParquet writes tabular data to columnar .parquet files.
+	CaptureFileFormatParquet = "parquet"
+)
+
+// ParquetCaptureMetadataKey is the key of the parquet file metadata which holds the base64 encoded
+// v1.DataCaptureMetadata of the captured data.
+const ParquetCaptureMetadataKey = "viam.capture_metadata"
+
+const (
+	parquetMagic = "PAR1"
+
+	// The names of the columns holding the timestamps of each reading.
+	parquetTimeRequestedColumn = "time_requested"
+	parquetTimeReceivedColumn  = "time_received"
+)
+
+// parquet physical types, converted types, encodings and codecs.
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetRequired = 0
+
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetGzip = 2
+
+	parquetDataPage = 0
+)
+
+// ParquetColumn is a column of a parquet capture file. Readings are flattened into columns named by
+// the dot separated path of each field, such as "readings.temperature". Numbers are stored as
+// doubles, and lists and other values which aren't numbers, strings or booleans as JSON strings.
+type ParquetColumn struct {
+	Name string
+	// Values are int64 timestamps in microseconds, float64, bool or string.
+	Values []interface{}
+	typ    int32
+}
+
+// ParquetCaptureBuffer is a CaptureBufferedWriter which writes tabular data to parquet files.
+// Readings are buffered in memory until Flush is called, maxFileSize worth of readings have been
+// buffered, or a reading has different fields than the buffered ones. Each of these writes a file
+// with a single row group, which is much smaller and fewer files than the equivalent .capture files.
+type ParquetCaptureBuffer struct {
+	Directory   string
+	MetaData    *v1.DataCaptureMetadata
+	maxFileSize int64
+
+	lock    sync.Mutex
+	columns []*ParquetColumn
+	rows    int
+	size    int64
+}
+
+// NewParquetCaptureBuffer returns a new ParquetCaptureBuffer.
+func NewParquetCaptureBuffer(dir string, md *v1.DataCaptureMetadata, maxFileSize int64) *ParquetCaptureBuffer {
+	return &ParquetCaptureBuffer{Directory: dir, MetaData: md, maxFileSize: maxFileSize}
+}
+
+// WriteBinary returns an error as parquet capture files only hold tabular data.
+func (b *ParquetCaptureBuffer) WriteBinary(items []*v1.SensorData) error {
+	return errors.New("parquet capture files only support tabular data")
+}
+
+// WriteTabular buffers the reading, writing a parquet file first if the buffered readings are too
+// large or have different fields.
+func (b *ParquetCaptureBuffer) WriteTabular(item *v1.SensorData) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if IsBinary(item) {
+		return errInvalidTabularSensorData
+	}
+
+	row := flattenReading(item)
+	if b.rows > 0 && (!b.sameColumns(row) || b.size >= b.maxFileSize) {
+		if err := b.writeFile(); err != nil {
+			return err
+		}
+	}
+	if b.rows == 0 {
+		b.columns = make([]*ParquetColumn, 0, len(row))
+		for _, field := range row {
+			b.columns = append(b.columns, &ParquetColumn{Name: field.name, typ: field.typ})
+		}
+	}
+	for i, field := range row {
+		b.columns[i].Values = append(b.columns[i].Values, field.value)
+		b.size += field.size
+	}
+	b.rows++
+	return nil
+}
+
+// Flush writes the buffered readings to a parquet file.
+func (b *ParquetCaptureBuffer) Flush() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.rows == 0 {
+		return nil
+	}
+	return b.writeFile()
+}
+
+// Path returns the directory the parquet files are written to.
+func (b *ParquetCaptureBuffer) Path() string {
+	return b.Directory
+}
+
+func (b *ParquetCaptureBuffer) sameColumns(row []parquetField) bool {
+	if len(row) != len(b.columns) {
+		return false
+	}
+	for i, field := range row {
+		if field.name != b.columns[i].Name || field.typ != b.columns[i].typ {
+			return false
+		}
+	}
+	return true
+}
+
+// writeFile writes the buffered readings to a .prog file and then renames it to a .parquet file so
+// that sync never sees partial files.
+func (b *ParquetCaptureBuffer) writeFile() error {
+	md, err := proto.Marshal(b.MetaData)
+	if err != nil {
+		return err
+	}
+	name := CaptureFilePathWithReplacedReservedChars(filepath.Join(b.Directory, getFileTimestampName()))
+	//nolint:gosec
+	f, err := os.OpenFile(name+InProgressCaptureFileExt, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	err = writeParquet(w, b.columns, b.rows, map[string]string{ParquetCaptureMetadataKey: base64.StdEncoding.EncodeToString(md)})
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	b.columns, b.rows, b.size = nil, 0, 0
+	return os.Rename(name+InProgressCaptureFileExt, name+ParquetCaptureFileExt)
+}
+
+type parquetField struct {
+	name  string
+	typ   int32
+	value interface{}
+	size  int64
+}
+
+// flattenReading returns the columns of a reading: its timestamps followed by its fields in order
+// of their names.
+func flattenReading(item *v1.SensorData) []parquetField {
+	micros := func(t *timestamppb.Timestamp) int64 {
+		return t.AsTime().UnixMicro()
+	}
+	fields := []parquetField{
+		{parquetTimeRequestedColumn, parquetInt64, micros(item.GetMetadata().GetTimeRequested()), 8},
+		{parquetTimeReceivedColumn, parquetInt64, micros(item.GetMetadata().GetTimeReceived()), 8},
+	}
+	var readings []parquetField
+	var flatten func(prefix string, v interface{})
+	flatten = func(prefix string, v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if len(v) > 0 {
+				for key, value := range v {
+					flatten(prefix+key+".", value)
+				}
+				return
+			}
+		case float64:
+			readings = append(readings, parquetField{strings.TrimSuffix(prefix, "."), parquetDouble, v, 8})
+			return
+		case bool:
+			readings = append(readings, parquetField{strings.TrimSuffix(prefix, "."), parquetBoolean, v, 1})
+			return
+		case string:
+			readings = append(readings, parquetField{strings.TrimSuffix(prefix, "."), parquetByteArray, v, int64(4 + len(v))})
+			return
+		}
+		//nolint:errchkjson
+		encoded, _ := json.Marshal(v)
+		readings = append(readings, parquetField{strings.TrimSuffix(prefix, "."), parquetByteArray, string(encoded), int64(4 + len(encoded))})
+	}
+	flatten("", item.GetStruct().AsMap())
+	sort.Slice(readings, func(i, j int) bool { return readings[i].name < readings[j].name })
+	return append(fields, readings...)
+}
+
+// writeParquet writes a parquet file with a single row group holding the columns, which must all
+// have rows values. Values are plain encoded and gzip compressed.
+func writeParquet(w io.Writer, columns []*ParquetColumn, rows int, keyValueMetadata map[string]string) error {
+	offset := int64(len(parquetMagic))
+	if _, err := io.WriteString(w, parquetMagic); err != nil {
+		return err
+	}
+	type chunk struct {
+		offset, uncompressedSize, compressedSize int64
+	}
+	chunks := make([]chunk, 0, len(columns))
+	var totalSize int64
+	for _, column := range columns {
+		raw := plainEncode(column)
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		if _, err := gz.Write(raw); err != nil {
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return err
+		}
+
+		var header thriftWriter
+		header.structBegin()
+		header.i32Field(1, parquetDataPage)
+		header.i32Field(2, int32(len(raw)))
+		header.i32Field(3, int32(compressed.Len()))
+		header.structField(5)
+		header.i32Field(1, int32(rows))
+		header.i32Field(2, parquetPlain)
+		header.i32Field(3, parquetRLE)
+		header.i32Field(4, parquetRLE)
+		header.structEnd()
+		header.structEnd()
+
+		if _, err := w.Write(header.buf.Bytes()); err != nil {
+			return err
+		}
+		if _, err := w.Write(compressed.Bytes()); err != nil {
+			return err
+		}
+		c := chunk{
+			offset:           offset,
+			uncompressedSize: int64(header.buf.Len() + len(raw)),
+			compressedSize:   int64(header.buf.Len() + compressed.Len()),
+		}
+		chunks = append(chunks, c)
+		offset += c.compressedSize
+		totalSize += c.uncompressedSize
+	}
+
+	var footer thriftWriter
+	footer.structBegin()
+	footer.i32Field(1, 1)
+	footer.listField(2, thriftStruct, len(columns)+1)
+	footer.structBegin()
+	footer.stringField(4, "schema")
+	footer.i32Field(5, int32(len(columns)))
+	footer.structEnd()
+	for _, column := range columns {
+		footer.structBegin()
+		footer.i32Field(1, column.typ)
+		footer.i32Field(3, parquetRequired)
+		footer.stringField(4, column.Name)
+		switch {
+		case column.typ == parquetByteArray:
+			footer.i32Field(6, parquetUTF8)
+		case column.typ == parquetInt64:
+			footer.i32Field(6, parquetTimestampMicros)
+		}
+		footer.structEnd()
+	}
+	footer.i64Field(3, int64(rows))
+	footer.listField(4, thriftStruct, 1)
+	footer.structBegin()
+	footer.listField(1, thriftStruct, len(columns))
+	for i, column := range columns {
+		footer.structBegin()
+		footer.i64Field(2, chunks[i].offset)
+		footer.structField(3)
+		footer.i32Field(1, column.typ)
+		footer.listField(2, thriftI32, 2)
+		footer.varint(parquetPlain)
+		footer.varint(parquetRLE)
+		footer.listField(3, thriftBinary, 1)
+		footer.binary([]byte(column.Name))
+		footer.i32Field(4, parquetGzip)
+		footer.i64Field(5, int64(rows))
+		footer.i64Field(6, chunks[i].uncompressedSize)
+		footer.i64Field(7, chunks[i].compressedSize)
+		footer.i64Field(9, chunks[i].offset)
+		footer.structEnd()
+		footer.structEnd()
+	}
+	footer.i64Field(2, totalSize)
+	footer.i64Field(3, int64(rows))
+	footer.structEnd()
+	footer.listField(5, thriftStruct, len(keyValueMetadata))
+	keys := make([]string, 0, len(keyValueMetadata))
+	for key := range keyValueMetadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		footer.structBegin()
+		footer.stringField(1, key)
+		footer.stringField(2, keyValueMetadata[key])
+		footer.structEnd()
+	}
+	footer.stringField(6, "viam rdk")
+	footer.structEnd()
+
+	if _, err := w.Write(footer.buf.Bytes()); err != nil {
+		return err
+	}
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(footer.buf.Len()))
+	if _, err := w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, parquetMagic)
+	return err
+}
+
+func plainEncode(column *ParquetColumn) []byte {
+	var buf bytes.Buffer
+	var b [8]byte
+	switch column.typ {
+	case parquetBoolean:
+		packed := make([]byte, (len(column.Values)+7)/8)
+		for i, v := range column.Values {
+			if v.(bool) {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		buf.Write(packed)
+	case parquetInt64:
+		for _, v := range column.Values {
+			binary.LittleEndian.PutUint64(b[:], uint64(v.(int64)))
+			buf.Write(b[:])
+		}
+	case parquetDouble:
+		for _, v := range column.Values {
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(v.(float64)))
+			buf.Write(b[:])
+		}
+	case parquetByteArray:
+		for _, v := range column.Values {
+			s := v.(string)
+			binary.LittleEndian.PutUint32(b[:4], uint32(len(s)))
+			buf.Write(b[:4])
+			buf.WriteString(s)
+		}
+	}
+	return buf.Bytes()
+}
+
+// ParquetCaptureFile is a parquet capture file read by ReadParquetCaptureFile.
+type ParquetCaptureFile struct {
+	Metadata *v1.DataCaptureMetadata
+	NumRows  int
+	Columns  []*ParquetColumn
+}
+
+// ReadParquetCaptureMetadata reads the capture metadata from the footer of a parquet capture file
+// without reading its data.
+func ReadParquetCaptureMetadata(f io.ReaderAt, size int64) (*v1.DataCaptureMetadata, error) {
+	footer, err := readParquetFooter(f, size)
+	if err != nil {
+		return nil, err
+	}
+	return parquetCaptureMetadata(footer)
+}
+
+// ReadParquetCaptureFile reads a parquet capture file written by a ParquetCaptureBuffer.
+func ReadParquetCaptureFile(f io.ReaderAt, size int64) (*ParquetCaptureFile, error) {
+	footer, err := readParquetFooter(f, size)
+	if err != nil {
+		return nil, err
+	}
+	md, err := parquetCaptureMetadata(footer)
+	if err != nil {
+		return nil, err
+	}
+	file := &ParquetCaptureFile{Metadata: md, NumRows: int(footer.int(3))}
+	rowGroups := footer.list(4)
+	if len(rowGroups) == 0 {
+		return file, nil
+	}
+	rowGroup, _ := rowGroups[0].(thriftStructValue) //nolint:errcheck
+	for _, c := range rowGroup.list(1) {
+		chunk, _ := c.(thriftStructValue)       //nolint:errcheck
+		meta, _ := chunk[3].(thriftStructValue) //nolint:errcheck
+		path, _ := meta.list(3)[0].([]byte)     //nolint:errcheck
+		column := &ParquetColumn{Name: string(path), typ: int32(meta.int(1))}
+		if err := readParquetColumnChunk(f, meta, column, file.NumRows); err != nil {
+			return nil, errors.Wrapf(err, "failed to read column %q", column.Name)
+		}
+		file.Columns = append(file.Columns, column)
+	}
+	return file, nil
+}
+
+func readParquetFooter(f io.ReaderAt, size int64) (thriftStructValue, error) {
+	var tail [8]byte
+	if size < int64(2*len(parquetMagic)+4) {
+		return nil, errors.New("file is too small to be a parquet file")
+	}
+	if _, err := f.ReadAt(tail[:], size-8); err != nil {
+		return nil, err
+	}
+	if string(tail[4:]) != parquetMagic {
+		return nil, errors.New("not a parquet file")
+	}
+	length := int64(binary.LittleEndian.Uint32(tail[:4]))
+	if length > size-8 {
+		return nil, errors.New("invalid parquet footer length")
+	}
+	footer := make([]byte, length)
+	if _, err := f.ReadAt(footer, size-8-length); err != nil {
+		return nil, err
+	}
+	return readThriftStruct(bytes.NewReader(footer))
+}
+
+func parquetCaptureMetadata(footer thriftStructValue) (*v1.DataCaptureMetadata, error) {
+	for _, kv := range footer.list(5) {
+		kv, _ := kv.(thriftStructValue) //nolint:errcheck
+		if kv.string(1) != ParquetCaptureMetadataKey {
+			continue
+		}
+		encoded, err := base64.StdEncoding.DecodeString(kv.string(2))
+		if err != nil {
+			return nil, err
+		}
+		var md v1.DataCaptureMetadata
+		if err := proto.Unmarshal(encoded, &md); err != nil {
+			return nil, err
+		}
+		return &md, nil
+	}
+	return nil, errors.New("parquet file has no capture metadata")
+}
+
+func readParquetColumnChunk(f io.ReaderAt, meta thriftStructValue, column *ParquetColumn, rows int) error {
+	if meta.int(4) != parquetGzip {
+		return errors.Errorf("unsupported compression codec %d", meta.int(4))
+	}
+	if meta.int(7) < 0 || meta.int(9) < 0 {
+		return errors.New("invalid column chunk")
+	}
+	chunk := make([]byte, meta.int(7))
+	if _, err := f.ReadAt(chunk, meta.int(9)); err != nil {
+		return err
+	}
+	r := bytes.NewReader(chunk)
+	header, err := readThriftStruct(r)
+	if err != nil {
+		return err
+	}
+	if header.int(3) < 0 || header.int(3) > int64(r.Len()) {
+		return errors.New("invalid page header")
+	}
+	compressed := make([]byte, header.int(3))
+	if _, err := io.ReadFull(r, compressed); err != nil {
+		return err
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return err
+	}
+	raw, err := io.ReadAll(gz)
+	if err != nil {
+		return err
+	}
+	switch column.typ {
+	case parquetBoolean:
+		if len(raw) < (rows+7)/8 {
+			return io.ErrUnexpectedEOF
+		}
+	case parquetInt64, parquetDouble:
+		if len(raw) < 8*rows {
+			return io.ErrUnexpectedEOF
+		}
+	}
+	for i := 0; i < rows; i++ {
+		switch column.typ {
+		case parquetBoolean:
+			column.Values = append(column.Values, raw[i/8]&(1<<(i%8)) != 0)
+		case parquetInt64:
+			column.Values = append(column.Values, int64(binary.LittleEndian.Uint64(raw[8*i:])))
+		case parquetDouble:
+			column.Values = append(column.Values, math.Float64frombits(binary.LittleEndian.Uint64(raw[8*i:])))
+		case parquetByteArray:
+			if len(raw) < 4 {
+				return io.ErrUnexpectedEOF
+			}
+			n := int(binary.LittleEndian.Uint32(raw))
+			if len(raw) < 4+n {
+				return io.ErrUnexpectedEOF
+			}
+			column.Values = append(column.Values, string(raw[4:4+n]))
+			raw = raw[4+n:]
+		default:
+			return errors.Errorf("unsupported column type %d", column.typ)
+		}
+	}
+	return nil
+}
+
+// ParquetTime converts a time column value of a parquet capture file to a time.Time.
+func ParquetTime(micros interface{}) time.Time {
+	v, _ := micros.(int64) //nolint:errcheck
+	return time.UnixMicro(v)
+}
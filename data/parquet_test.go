@@ -0,0 +1,142 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/resource"
+)
+
+func parquetReading(t *testing.T, at time.Time, readings map[string]interface{}) *v1.SensorData {
+	t.Helper()
+	s, err := structpb.NewStruct(map[string]interface{}{"readings": readings})
+	test.That(t, err, test.ShouldBeNil)
+	return &v1.SensorData{
+		Metadata: &v1.SensorMetadata{
+			TimeRequested: timestamppb.New(at),
+			TimeReceived:  timestamppb.New(at.Add(time.Millisecond)),
+		},
+		Data: &v1.SensorData_Struct{Struct: s},
+	}
+}
+
+func readParquetFiles(t *testing.T, dir string) []*ParquetCaptureFile {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+ParquetCaptureFileExt))
+	test.That(t, err, test.ShouldBeNil)
+	sort.Strings(paths)
+	var files []*ParquetCaptureFile
+	for _, path := range paths {
+		//nolint:gosec
+		f, err := os.Open(path)
+		test.That(t, err, test.ShouldBeNil)
+		info, err := f.Stat()
+		test.That(t, err, test.ShouldBeNil)
+		file, err := ReadParquetCaptureFile(f, info.Size())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, f.Close(), test.ShouldBeNil)
+		files = append(files, file)
+	}
+	return files
+}
+
+func TestParquetCaptureBuffer(t *testing.T) {
+	dir := t.TempDir()
+	md, _ := BuildCaptureMetadata(resource.APINamespaceRDK.WithComponentType("sensor"), "thermo", "Readings", nil, nil, []string{"tag"})
+	b := NewParquetCaptureBuffer(dir, md, 1024*1024)
+
+	test.That(t, b.WriteBinary(nil), test.ShouldNotBeNil)
+	test.That(t, b.WriteTabular(&v1.SensorData{Data: &v1.SensorData_Binary{}}), test.ShouldBeError, errInvalidTabularSensorData)
+
+	start := time.Date(2024, time.January, 10, 23, 0, 0, 0, time.UTC)
+	for i := 0; i < 20; i++ {
+		test.That(t, b.WriteTabular(parquetReading(t, start.Add(time.Duration(i)*time.Second), map[string]interface{}{
+			"temp":  20 + float64(i)/2,
+			"ok":    i%3 == 0,
+			"unit":  "C",
+			"probe": map[string]interface{}{"id": float64(i)},
+			"list":  []interface{}{1, "a"},
+		})), test.ShouldBeNil)
+	}
+	// nothing is written until flushed
+	test.That(t, readParquetFiles(t, dir), test.ShouldBeEmpty)
+	// a reading with different fields starts a new file
+	test.That(t, b.WriteTabular(parquetReading(t, start, map[string]interface{}{"temp": 1})), test.ShouldBeNil)
+	test.That(t, b.Flush(), test.ShouldBeNil)
+	test.That(t, b.Flush(), test.ShouldBeNil)
+
+	progFiles, err := filepath.Glob(filepath.Join(dir, "*"+InProgressCaptureFileExt))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, progFiles, test.ShouldBeEmpty)
+
+	files := readParquetFiles(t, dir)
+	test.That(t, files, test.ShouldHaveLength, 2)
+	file := files[0]
+	test.That(t, file.Metadata.GetComponentName(), test.ShouldEqual, "thermo")
+	test.That(t, file.Metadata.GetMethodName(), test.ShouldEqual, "Readings")
+	test.That(t, file.Metadata.GetTags(), test.ShouldResemble, []string{"tag"})
+	test.That(t, file.NumRows, test.ShouldEqual, 20)
+	var names []string
+	for _, column := range file.Columns {
+		names = append(names, column.Name)
+		test.That(t, column.Values, test.ShouldHaveLength, 20)
+	}
+	test.That(t, names, test.ShouldResemble, []string{
+		"time_requested", "time_received",
+		"readings.list", "readings.ok", "readings.probe.id", "readings.temp", "readings.unit",
+	})
+	test.That(t, ParquetTime(file.Columns[0].Values[3]).Equal(start.Add(3*time.Second)), test.ShouldBeTrue)
+	test.That(t, ParquetTime(file.Columns[1].Values[3]).Equal(start.Add(3*time.Second+time.Millisecond)), test.ShouldBeTrue)
+	test.That(t, file.Columns[2].Values[0], test.ShouldEqual, `[1,"a"]`)
+	test.That(t, file.Columns[3].Values[:4], test.ShouldResemble, []interface{}{true, false, false, true})
+	test.That(t, file.Columns[4].Values[19], test.ShouldEqual, 19.)
+	test.That(t, file.Columns[5].Values[1], test.ShouldEqual, 20.5)
+	test.That(t, file.Columns[6].Values[7], test.ShouldEqual, "C")
+
+	test.That(t, files[1].NumRows, test.ShouldEqual, 1)
+	test.That(t, files[1].Columns, test.ShouldHaveLength, 3)
+	test.That(t, files[1].Columns[2].Values, test.ShouldResemble, []interface{}{1.})
+
+	// the metadata can be read without the data
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+ParquetCaptureFileExt))
+	test.That(t, err, test.ShouldBeNil)
+	contents, err := os.ReadFile(paths[0])
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(contents[:4]), test.ShouldEqual, "PAR1")
+	//nolint:gosec
+	f, err := os.Open(paths[0])
+	test.That(t, err, test.ShouldBeNil)
+	defer f.Close()
+	readMD, err := ReadParquetCaptureMetadata(f, int64(len(contents)))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readMD.GetComponentType(), test.ShouldEqual, md.GetComponentType())
+	_, err = ReadParquetCaptureMetadata(f, 3)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestParquetCaptureBufferRollsOverAtMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	md, _ := BuildCaptureMetadata(resource.APINamespaceRDK.WithComponentType("sensor"), "thermo", "Readings", nil, nil, nil)
+	// each reading is 24 bytes of timestamps and a double
+	b := NewParquetCaptureBuffer(dir, md, 24*10)
+	start := time.Now()
+	for i := 0; i < 25; i++ {
+		test.That(t, b.WriteTabular(parquetReading(t, start, map[string]interface{}{"temp": float64(i)})), test.ShouldBeNil)
+	}
+	test.That(t, b.Flush(), test.ShouldBeNil)
+	files := readParquetFiles(t, dir)
+	var rows []int
+	for _, f := range files {
+		rows = append(rows, f.NumRows)
+	}
+	sort.Ints(rows)
+	test.That(t, rows, test.ShouldResemble, []int{5, 10, 10})
+}
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"maps"
 	"time"
@@ -24,15 +25,26 @@ type CollectorParams struct {
 	ComponentName   string
 	ComponentType   string
 	DataType        CaptureType
+	Filter          CaptureFilter
 	Interval        time.Duration
 	Logger          logging.Logger
 	MethodName      string
 	MethodParams    map[string]*anypb.Any
 	MongoCollection *mongo.Collection
+	Publisher       CapturePublisher
 	QueueSize       int
 	Target          CaptureBufferedWriter
 }
 
+// CaptureFilter decides whether a capture is saved. Captures it returns false or an error for are
+// dropped.
+type CaptureFilter func(ctx context.Context, result CaptureResult) (bool, error)
+
+// CapturePublisher receives every capture once it has been written to the target, such as to
+// stream it to a message broker. It is called from the collector's writing goroutine, so it
+// should not block.
+type CapturePublisher func(ctx context.Context, result CaptureResult)
+
 // Validate validates that p contains all required parameters.
 func (p CollectorParams) Validate() error {
 	if p.Target == nil {
@@ -0,0 +1,216 @@
+package data
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// The thrift compact protocol is how parquet encodes its page headers and file footer. Only the
+// parts of the protocol parquet uses are implemented.
+
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftByte   = 3
+	thriftI16    = 4
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftDouble = 7
+	thriftBinary = 8
+	thriftList   = 9
+	thriftSet    = 10
+	thriftMap    = 11
+	thriftStruct = 12
+)
+
+// thriftWriter writes thrift compact protocol structs.
+type thriftWriter struct {
+	buf         bytes.Buffer
+	lastFieldID []int16
+}
+
+func (w *thriftWriter) structBegin() {
+	w.lastFieldID = append(w.lastFieldID, 0)
+}
+
+func (w *thriftWriter) structEnd() {
+	w.buf.WriteByte(0)
+	w.lastFieldID = w.lastFieldID[:len(w.lastFieldID)-1]
+}
+
+func (w *thriftWriter) fieldHeader(id int16, typ byte) {
+	last := &w.lastFieldID[len(w.lastFieldID)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.varint(int64(id))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) varint(v int64) {
+	var b [binary.MaxVarintLen64]byte
+	w.buf.Write(b[:binary.PutVarint(b[:], v)])
+}
+
+func (w *thriftWriter) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	w.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func (w *thriftWriter) i32Field(id int16, v int32) {
+	w.fieldHeader(id, thriftI32)
+	w.varint(int64(v))
+}
+
+func (w *thriftWriter) i64Field(id int16, v int64) {
+	w.fieldHeader(id, thriftI64)
+	w.varint(v)
+}
+
+func (w *thriftWriter) binary(b []byte) {
+	w.uvarint(uint64(len(b)))
+	w.buf.Write(b)
+}
+
+func (w *thriftWriter) stringField(id int16, s string) {
+	w.fieldHeader(id, thriftBinary)
+	w.binary([]byte(s))
+}
+
+func (w *thriftWriter) structField(id int16) {
+	w.fieldHeader(id, thriftStruct)
+	w.structBegin()
+}
+
+func (w *thriftWriter) listField(id int16, elemType byte, size int) {
+	w.fieldHeader(id, thriftList)
+	w.listHeader(elemType, size)
+}
+
+func (w *thriftWriter) listHeader(elemType byte, size int) {
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elemType)
+	w.uvarint(uint64(size))
+}
+
+// thriftStructValue is a decoded thrift struct, keyed by field id. Values are bool, int64,
+// float64, []byte, []interface{} or thriftStructValue.
+type thriftStructValue map[int16]interface{}
+
+func (s thriftStructValue) int(id int16) int64 {
+	v, _ := s[id].(int64) //nolint:errcheck
+	return v
+}
+
+func (s thriftStructValue) string(id int16) string {
+	v, _ := s[id].([]byte) //nolint:errcheck
+	return string(v)
+}
+
+func (s thriftStructValue) list(id int16) []interface{} {
+	v, _ := s[id].([]interface{}) //nolint:errcheck
+	return v
+}
+
+// readThriftStruct reads a thrift compact protocol struct from r.
+func readThriftStruct(r io.ByteReader) (thriftStructValue, error) {
+	s := thriftStructValue{}
+	var lastID int16
+	for {
+		header, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if header == 0 {
+			return s, nil
+		}
+		typ := header & 0x0f
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			v, err := binary.ReadVarint(r)
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+		lastID = id
+		switch typ {
+		case thriftTrue, thriftFalse:
+			s[id] = typ == thriftTrue
+		default:
+			v, err := readThriftValue(r, typ)
+			if err != nil {
+				return nil, err
+			}
+			s[id] = v
+		}
+	}
+}
+
+func readThriftValue(r io.ByteReader, typ byte) (interface{}, error) {
+	switch typ {
+	case thriftTrue, thriftFalse:
+		// only in lists, where booleans are a byte each
+		b, err := r.ReadByte()
+		return b == thriftTrue, err
+	case thriftByte:
+		b, err := r.ReadByte()
+		return int64(int8(b)), err
+	case thriftI16, thriftI32, thriftI64:
+		return binary.ReadVarint(r)
+	case thriftDouble:
+		var b [8]byte
+		for i := range b {
+			var err error
+			if b[i], err = r.ReadByte(); err != nil {
+				return nil, err
+			}
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b[:])), nil
+	case thriftBinary:
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, n)
+		for i := range b {
+			if b[i], err = r.ReadByte(); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case thriftList, thriftSet:
+		header, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		size := uint64(header >> 4)
+		if size == 15 {
+			if size, err = binary.ReadUvarint(r); err != nil {
+				return nil, err
+			}
+		}
+		list := make([]interface{}, 0, size)
+		for i := uint64(0); i < size; i++ {
+			v, err := readThriftValue(r, header&0x0f)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case thriftStruct:
+		return readThriftStruct(r)
+	default:
+		return nil, errors.Errorf("unsupported thrift type %d", typ)
+	}
+}
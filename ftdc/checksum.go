@@ -0,0 +1,196 @@
+package ftdc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	// chunkIdentifier is the first byte of a checksummed chunk. Its least significant bit is set,
+	// such that it is never confused with a metric document. See `doc.go`.
+	chunkIdentifier = 0x5
+	// chunkHeaderBytes is the size of a chunk header: the identifier byte, the length of the chunk
+	// and its checksum.
+	chunkHeaderBytes = 1 + 4 + 4
+	// maxChunkBytes bounds the length of a chunk. A chunk header claiming to be larger than this is
+	// assumed to be corrupt rather than allocating for it.
+	maxChunkBytes = 64 << 20
+)
+
+// crcTable is the CRC-32C (Castagnoli) table, which most CPUs compute in hardware.
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// writeChunk writes the FTDC documents in `chunk` prefixed with a header holding their length
+// and checksum. Each call to `FTDC.writeDatum` writes out one chunk, such that a corrupted chunk
+// loses as little data as possible.
+func writeChunk(chunk []byte, output io.Writer) error {
+	header := make([]byte, chunkHeaderBytes)
+	header[0] = chunkIdentifier
+	binary.BigEndian.PutUint32(header[1:5], uint32(len(chunk)))
+	binary.BigEndian.PutUint32(header[5:9], crc32.Checksum(chunk, crcTable))
+	if _, err := output.Write(header); err != nil {
+		return fmt.Errorf("Error writing chunk header: %w", err)
+	}
+	if _, err := output.Write(chunk); err != nil {
+		return fmt.Errorf("Error writing chunk: %w", err)
+	}
+
+	return nil
+}
+
+// CorruptChunk locates a chunk of an FTDC file whose checksum does not match its contents.
+type CorruptChunk struct {
+	// Index is the position of the chunk in the file, starting at 0.
+	Index int
+	// Offset is the position of the chunk's first byte in the (decompressed) file.
+	Offset int64
+	// Length is the size of the chunk in bytes, not including its header.
+	Length int
+}
+
+// VerifyResult describes the integrity of an FTDC file.
+type VerifyResult struct {
+	NumChunks     int
+	CorruptChunks []CorruptChunk
+	// NumSkippedChunks is how many intact chunks could not be recovered because their metrics are
+	// diffed against a corrupt chunk. Data is recovered again starting at the next schema.
+	NumSkippedChunks int
+	// NumDatums is how many datums were recovered.
+	NumDatums int
+	// Unchecksummed is true if some of the file was written without checksums, such as files
+	// written by older versions of viam-server. Corruption cannot be detected in those parts.
+	Unchecksummed bool
+}
+
+// checksumState tracks the checksummed chunks seen while parsing.
+type checksumState struct {
+	numChunks     int
+	corruptChunks []CorruptChunk
+	// offset is the number of bytes read by chunks so far.
+	offset int64
+	// skipUntilSchema is set after a corrupt chunk. Metric documents are skipped until a schema
+	// document resets the state they are diffed against.
+	skipUntilSchema  bool
+	numSkippedChunks int
+	unchecksummed    bool
+}
+
+// unchecksummedDocument records that a document was found outside of any chunk. This is expected
+// of files written without checksums. But once chunks have been seen, it means a chunk header was
+// corrupted and the position of the following documents is unknown.
+func (cs *checksumState) unchecksummedDocument() error {
+	if cs.numChunks > 0 {
+		return fmt.Errorf("corrupt FTDC chunk header at offset %d, cannot read further", cs.offset)
+	}
+	cs.unchecksummed = true
+	return nil
+}
+
+// readChunk reads a checksummed chunk, positioning `parser.chunk` on its first document. A chunk
+// whose checksum does not match is skipped. If the data ends part way through the chunk, what was
+// read is kept and `io.EOF` is returned, such that the chunk can be finished once the rest of it is
+// written.
+func (parser *parser) readChunk() error {
+	cs := &parser.checksums
+	if parser.chunkHeader == nil {
+		parser.chunkHeader = make([]byte, 0, chunkHeaderBytes)
+	}
+	var err error
+	if parser.chunkHeader, err = readFull(parser.reader, parser.chunkHeader); err != nil {
+		if errors.Is(err, io.EOF) {
+			return err
+		}
+		return fmt.Errorf("error reading FTDC chunk header at offset %d: %w", cs.offset, err)
+	}
+	length := binary.BigEndian.Uint32(parser.chunkHeader[1:5])
+	checksum := binary.BigEndian.Uint32(parser.chunkHeader[5:9])
+	if length > maxChunkBytes {
+		return fmt.Errorf("corrupt FTDC chunk header at offset %d, cannot read further", cs.offset)
+	}
+
+	if parser.chunkBody == nil {
+		parser.chunkBody = make([]byte, 0, length)
+	}
+	if parser.chunkBody, err = readFull(parser.reader, parser.chunkBody); err != nil {
+		if errors.Is(err, io.EOF) {
+			return err
+		}
+		return fmt.Errorf("error reading FTDC chunk at offset %d: %w", cs.offset, err)
+	}
+	chunk := parser.chunkBody
+	parser.chunkHeader, parser.chunkBody = nil, nil
+	offset := cs.offset
+	cs.offset += int64(chunkHeaderBytes + len(chunk))
+	cs.numChunks++
+
+	if crc32.Checksum(chunk, crcTable) != checksum {
+		parser.logger.Warnw("Skipping corrupt FTDC chunk", "index", cs.numChunks-1, "offset", offset)
+		cs.corruptChunks = append(cs.corruptChunks, CorruptChunk{
+			Index:  cs.numChunks - 1,
+			Offset: offset,
+			Length: len(chunk),
+		})
+		// The corrupt chunk may have held a new schema or values later metric documents are diffed
+		// against.
+		parser.schema, parser.prevValues, cs.skipUntilSchema = nil, nil, true
+		return nil
+	}
+
+	parser.chunk = bufio.NewReader(bytes.NewReader(chunk))
+	return nil
+}
+
+// truncatedChunkError returns an error if the data ended part way through a chunk.
+func (parser *parser) truncatedChunkError() error {
+	switch {
+	case parser.chunkHeader == nil:
+		return nil
+	case parser.chunkBody == nil:
+		return fmt.Errorf("error reading FTDC chunk header at offset %d: %w", parser.checksums.offset, io.ErrUnexpectedEOF)
+	default:
+		return fmt.Errorf("error reading FTDC chunk at offset %d: %w", parser.checksums.offset, io.ErrUnexpectedEOF)
+	}
+}
+
+// readFull reads from `reader` until `buf` is filled to its capacity, returning `buf` extended with
+// the bytes read. Unlike `io.ReadFull`, the bytes read are kept when the input ends early, along
+// with an `io.EOF`, such that reading can resume once more of the input is written.
+func readFull(reader io.Reader, buf []byte) ([]byte, error) {
+	for len(buf) < cap(buf) {
+		n, err := reader.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if err != nil && len(buf) < cap(buf) {
+			return buf, err
+		}
+	}
+	return buf, nil
+}
+
+// Verify parses the FTDC file, checking the checksum of each of its chunks. It reports which
+// chunks are corrupt, and how much data could still be recovered from the file. As with `Parse`,
+// an error is returned if the file cannot be read to the end, alongside what was verified up until
+// that point.
+func Verify(rawReader io.Reader, logger logging.Logger) (VerifyResult, error) {
+	reader, err := NewReader(rawReader, logger)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	defer reader.Close()
+
+	err = reader.forEach(func(FlatDatum) {})
+	parser := reader.parser
+	return VerifyResult{
+		NumChunks:        parser.checksums.numChunks,
+		CorruptChunks:    parser.checksums.corruptChunks,
+		NumSkippedChunks: parser.checksums.numSkippedChunks,
+		NumDatums:        parser.numDatums,
+		Unchecksummed:    parser.checksums.unchecksummed,
+	}, err
+}
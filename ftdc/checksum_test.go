@@ -0,0 +1,93 @@
+package ftdc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestVerify(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+
+	ftdcData := bytes.NewBuffer(nil)
+	ftdc := NewWithWriter(ftdcData, logger.Sublogger("ftdc"))
+	statser := mockStatser{stats: struct{ X int }{0}}
+	ftdc.Add("mock", &statser)
+	// Chunks 0 through 2 share a schema. Chunk 3 starts a new schema.
+	for x := range 5 {
+		if x < 3 {
+			statser.stats = struct{ X int }{x}
+		} else {
+			statser.stats = struct {
+				X int
+				Y int
+			}{x, 1}
+		}
+		test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+	}
+
+	result, err := Verify(bytes.NewReader(ftdcData.Bytes()), logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, result, test.ShouldResemble, VerifyResult{NumChunks: 5, NumDatums: 5})
+
+	// Find where each chunk starts by walking the chunk headers.
+	var chunkOffsets []int
+	for offset := 0; offset < ftdcData.Len(); {
+		test.That(t, ftdcData.Bytes()[offset], test.ShouldEqual, chunkIdentifier)
+		chunkOffsets = append(chunkOffsets, offset)
+		offset += chunkHeaderBytes + int(binary.BigEndian.Uint32(ftdcData.Bytes()[offset+1:]))
+	}
+	test.That(t, chunkOffsets, test.ShouldHaveLength, 5)
+
+	t.Run("corrupt chunk", func(t *testing.T) {
+		corrupted := bytes.Clone(ftdcData.Bytes())
+		// Flip a bit of the last byte of chunk 1, one of the metric values.
+		corrupted[chunkOffsets[2]-1] ^= 0x10
+
+		result, err := Verify(bytes.NewReader(corrupted), logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, result.NumChunks, test.ShouldEqual, 5)
+		test.That(t, result.CorruptChunks, test.ShouldResemble, []CorruptChunk{{
+			Index:  1,
+			Offset: int64(chunkOffsets[1]),
+			Length: chunkOffsets[2] - chunkOffsets[1] - chunkHeaderBytes,
+		}})
+		// Chunk 2 is intact, but diffed against the corrupt chunk.
+		test.That(t, result.NumSkippedChunks, test.ShouldEqual, 1)
+		test.That(t, result.NumDatums, test.ShouldEqual, 3)
+
+		// Parsing recovers the data from before the corruption and from the next schema on.
+		datums, err := Parse(bytes.NewReader(corrupted))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, datums, test.ShouldHaveLength, 3)
+		test.That(t, datums[0].Readings, test.ShouldResemble, []Reading{{"mock.X", 0}})
+		test.That(t, datums[1].Readings, test.ShouldResemble, []Reading{{"mock.X", 3}, {"mock.Y", 1}})
+		test.That(t, datums[2].Readings, test.ShouldResemble, []Reading{{"mock.X", 4}, {"mock.Y", 1}})
+	})
+
+	t.Run("corrupt chunk header", func(t *testing.T) {
+		corrupted := bytes.Clone(ftdcData.Bytes())
+		corrupted[chunkOffsets[3]] = 0x1
+
+		result, err := Verify(bytes.NewReader(corrupted), logger)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "corrupt FTDC chunk header")
+		test.That(t, result.NumChunks, test.ShouldEqual, 3)
+		test.That(t, result.NumDatums, test.ShouldEqual, 3)
+	})
+
+	t.Run("unchecksummed", func(t *testing.T) {
+		// Files written before checksums were added have bare documents.
+		unchecksummed := bytes.NewBuffer(nil)
+		test.That(t, writeSchema(&schema{fieldOrder: []string{"mock.X"}}, unchecksummed), test.ShouldBeNil)
+		test.That(t, writeDatum(1, nil, []float32{1}, unchecksummed), test.ShouldBeNil)
+
+		result, err := Verify(unchecksummed, logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, result, test.ShouldResemble, VerifyResult{NumDatums: 1, Unchecksummed: true})
+	})
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/ftdc"
+	"go.viam.com/rdk/logging"
+)
+
+// exportBatchSize is how many datums are read from an FTDC file before they are rendered, such that
+// exporting a large file does not need to hold all of its data.
+const exportBatchSize = 1000
+
+// filterReadings returns the datums with only the readings of metrics matching the filter. Datums
+// left without readings are dropped. A nil filter matches every metric.
+func filterReadings(datums []ftdc.FlatDatum, filter *regexp.Regexp) []ftdc.FlatDatum {
+	if filter == nil {
+		return datums
+	}
+	ret := make([]ftdc.FlatDatum, 0, len(datums))
+	for _, datum := range datums {
+		var readings []ftdc.Reading
+		for _, reading := range datum.Readings {
+			if filter.MatchString(reading.MetricName) {
+				readings = append(readings, reading)
+			}
+		}
+		if len(readings) > 0 {
+			ret = append(ret, ftdc.FlatDatum{Time: datum.Time, Readings: readings})
+		}
+	}
+	return ret
+}
+
+// exportFTDCFile streams the datums of the FTDC file within the range of the graph options to the
+// renderer, in batches. It returns how many datums were rendered.
+func exportFTDCFile(
+	ctx context.Context,
+	renderer ftdc.Renderer,
+	input ftdcInput,
+	options graphOptions,
+	logger logging.Logger,
+) (int, error) {
+	ftdcFile, err := os.Open(input.path) //nolint:gosec
+	if err != nil {
+		return 0, err
+	}
+	defer utils.UncheckedErrorFunc(ftdcFile.Close)
+
+	reader, err := ftdc.NewReader(ftdcFile, logger)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	reader.SetTimeRange(options.timeRange())
+
+	numRendered := 0
+	render := func(batch []ftdc.FlatDatum) error {
+		toRender := filterReadings(batch, options.metricFilter)
+		if len(toRender) == 0 {
+			return nil
+		}
+		numRendered += len(toRender)
+		return renderer.Render(ctx, input.label, toRender)
+	}
+
+	batch := make([]ftdc.FlatDatum, 0, exportBatchSize)
+	for {
+		datum, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			// A partial datum at the end of a file that is still being written is ignored.
+			return numRendered, render(batch)
+		}
+		if err != nil {
+			// Export what was read before the error.
+			if renderErr := render(batch); renderErr != nil {
+				return numRendered, renderErr
+			}
+			return numRendered, err
+		}
+
+		batch = append(batch, datum)
+		if len(batch) == exportBatchSize {
+			if err := render(batch); err != nil {
+				return numRendered, err
+			}
+			// Renderers may hold onto the datums they are given.
+			batch = make([]ftdc.FlatDatum, 0, exportBatchSize)
+		}
+	}
+}
+
+// runExport implements `parser export`, which writes FTDC files with a renderer without graphing
+// them. E.g: `parser export --format=csv --metrics=^proc --out=proc.csv viam-server.ftdc`.
+func runExport(args []string) {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", "csv",
+		"the renderer to export with. E.g: csv, json, prometheus or exec")
+	out := flags.String("out", "",
+		"where to export to. A file for csv and json, a remote-write URL for prometheus or a command for exec")
+	metrics := flags.String("metrics", "",
+		"only export metrics whose FTDC names match this regular expression. E.g: ^proc\\.")
+	start := flags.String("start", "", "only export datapoints from this time on. E.g: 2024-09-24T18:00:00")
+	end := flags.String("end", "", "only export datapoints up to this time. E.g: 2024-09-24T19:00:00")
+	var pluginPaths []string
+	flags.Func("plugin", "load the renderers of a Go plugin. May be given multiple times",
+		func(path string) error {
+			pluginPaths = append(pluginPaths, path)
+			return nil
+		})
+	if err := flags.Parse(args); err != nil {
+		// The flag set prints the error and usage.
+		return
+	}
+	if flags.NArg() < 1 {
+		nolintPrintln("Expected an FTDC filename. E.g: " +
+			"go run parser.go export --format=csv --out=<path-to>/ftdc.csv <path-to>/viam-server.ftdc")
+		return
+	}
+
+	for _, path := range pluginPaths {
+		if err := loadRendererPlugin(path); err != nil {
+			nolintPrintln("Error loading plugin. File:", path, "Err:", err)
+			return
+		}
+	}
+
+	options := defaultGraphOptions()
+	if *metrics != "" {
+		metricFilter, err := regexp.Compile(*metrics)
+		if err != nil {
+			nolintPrintln("Error parsing --metrics. Err:", err)
+			return
+		}
+		options.metricFilter = metricFilter
+	}
+	if *start != "" {
+		goTime, err := parseStringAsTime(*start)
+		if err != nil {
+			// parseStringAsTime outputs an error message for us.
+			return
+		}
+		options.minTimeSeconds = goTime.Unix()
+	}
+	if *end != "" {
+		goTime, err := parseStringAsTime(*end)
+		if err != nil {
+			return
+		}
+		options.maxTimeSeconds = goTime.Unix()
+	}
+
+	inputs, err := parseInputs(flags.Args())
+	if err != nil {
+		nolintPrintln("Error parsing arguments. Err:", err)
+		return
+	}
+
+	factory, ok := ftdc.LookupRenderer(*format)
+	if !ok {
+		nolintPrintln("Unknown format:", *format, "Formats:", strings.Join(ftdc.RegisteredRenderers(), ", "))
+		return
+	}
+	logger := logging.NewLogger("parser")
+	renderer, err := factory(*out, logger)
+	if err != nil {
+		nolintPrintln("Error creating renderer. Renderer:", *format, "Err:", err)
+		return
+	}
+	defer func() {
+		if err := renderer.Close(); err != nil {
+			nolintPrintln("Error closing renderer. Renderer:", *format, "Err:", err)
+		}
+	}()
+
+	for _, input := range inputs {
+		numRendered, err := exportFTDCFile(context.Background(), renderer, input, options, logger)
+		nolintPrintln("Exported", numRendered, "datapoints with", *format+". File:", input.path)
+		if err != nil {
+			nolintPrintln("Error exporting. File:", input.path, "Err:", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/ftdc"
+	"go.viam.com/rdk/logging"
+)
+
+// followInterval is how often `follow` checks the FTDC files for new datums.
+const followInterval = time.Second
+
+// reloadInputs re-reads the datums of each FTDC file within the range of the graph options, such
+// as after the range changes. It returns false, after saying why, if a file has nothing to graph.
+func reloadInputs(inputs []ftdcInput, options graphOptions, logger logging.Logger) ([][]ftdc.FlatDatum, bool) {
+	datas := make([][]ftdc.FlatDatum, len(inputs))
+	for idx, input := range inputs {
+		data, _, err := parseFTDCFile(input.path, options, logger)
+		if err != nil {
+			nolintPrintln("Error reading file. File:", input.path, "Err:", err)
+			return nil, false
+		}
+		if len(data) == 0 {
+			nolintPrintln("FTDC file has no data within the range. File:", input.path)
+			return nil, false
+		}
+		datas[idx] = data
+	}
+	return datas, true
+}
+
+// followedFile reads the datums appended to an FTDC file that viam-server is still writing to.
+type followedFile struct {
+	file   *os.File
+	reader *ftdc.Reader
+}
+
+// followFile opens the FTDC file for reading the datums after `lastTime`, within the range of the
+// graph options.
+func followFile(path string, lastTime int64, options graphOptions, logger logging.Logger) (*followedFile, error) {
+	file, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	reader, err := ftdc.NewReader(file, logger)
+	if err != nil {
+		utils.UncheckedError(file.Close())
+		return nil, err
+	}
+	// The datums already graphed are decoded again to catch up, but are not assembled.
+	start, end := options.timeRange()
+	if after := time.Unix(0, lastTime+1); after.After(start) {
+		start = after
+	}
+	reader.SetTimeRange(start, end)
+	return &followedFile{file: file, reader: reader}, nil
+}
+
+// readNew returns the datums written since the last call.
+func (ff *followedFile) readNew() ([]ftdc.FlatDatum, error) {
+	var ret []ftdc.FlatDatum
+	for {
+		datum, err := ff.reader.Next()
+		if errors.Is(err, io.EOF) {
+			// The rest of the file, if any, is still being written.
+			return ret, nil
+		}
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, datum)
+	}
+}
+
+func (ff *followedFile) close() {
+	ff.reader.Close()
+	utils.UncheckedError(ff.file.Close())
+}
+
+// runFollow tails the FTDC files, adding the datums written to them to `datas` and rendering the
+// graphs again whenever there are new ones. It returns when the user presses enter.
+func runFollow(
+	inputs []ftdcInput,
+	datas [][]ftdc.FlatDatum,
+	options graphOptions,
+	stdinReader *bufio.Reader,
+	logger logging.Logger,
+) {
+	followed := make([]*followedFile, len(inputs))
+	for idx, input := range inputs {
+		data := datas[idx]
+		ff, err := followFile(input.path, data[len(data)-1].Time, options, logger)
+		if err != nil {
+			nolintPrintln("Error opening file. File:", input.path, "Err:", err)
+			return
+		}
+		defer ff.close()
+		followed[idx] = ff
+	}
+
+	stop := make(chan struct{})
+	utils.PanicCapturingGo(func() {
+		defer close(stop)
+		//nolint:errcheck
+		stdinReader.ReadString('\n')
+	})
+
+	nolintPrintln("Following", len(inputs), "FTDC file(s). Graphs are rendered again as new data is written.")
+	nolintPrintln("Press enter to stop.")
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		numNew := 0
+		for idx, ff := range followed {
+			if ff == nil {
+				continue
+			}
+			newData, err := ff.readNew()
+			if err != nil {
+				// Keep the datums read before the error, but stop following a corrupt file.
+				nolintPrintln("Error reading file, no longer following it. File:", inputs[idx].path, "Err:", err)
+				followed[idx] = nil
+			}
+			datas[idx] = append(datas[idx], newData...)
+			numNew += len(newData)
+		}
+		if numNew > 0 {
+			buildGraphs(options, inputs, datas, logger).Render()
+		}
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"bufio"
 	"cmp"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 	"time"
@@ -21,14 +23,55 @@ import (
 	"go.viam.com/rdk/logging"
 )
 
-// graphInfo points to an OS file containing the data points for a single graph. We also record the
-// min/max values for scaling purposes when generating plots.
+// graphInfo points to the OS files containing the data points for a single graph. There is one
+// file per input FTDC file, keyed by its label. We also record the min/max values across all of
+// them for scaling purposes when generating plots.
 type graphInfo struct {
-	file   *os.File
+	files  map[string]*os.File
 	minVal int64
 	maxVal int64
 }
 
+// ftdcInput is an FTDC file to graph. When graphing multiple FTDC files, the label (e.g: a robot or
+// part name) is used in the legend to tell apart the same metric from each file.
+type ftdcInput struct {
+	label string
+	path  string
+}
+
+// parseInputs parses the command line arguments into the FTDC files to graph. Each argument is
+// either a path, or a `<label>=<path>` pair. When multiple files are given without labels, the
+// file's base name is used as the label. A single file without a label has the empty label.
+func parseInputs(args []string) ([]ftdcInput, error) {
+	inputs := make([]ftdcInput, 0, len(args))
+	labels := make(map[string]struct{}, len(args))
+	for _, arg := range args {
+		input := ftdcInput{path: arg}
+		if label, path, found := strings.Cut(arg, "="); found {
+			input = ftdcInput{label: label, path: path}
+		} else if len(args) > 1 {
+			input.label = filepath.Base(arg)
+			// Drop the extensions of compressed FTDC files too. E.g: `viam-server.ftdc.gz`.
+			for _, ext := range []string{".gz", ".zst", ".zstd"} {
+				input.label = strings.TrimSuffix(input.label, ext)
+			}
+			input.label = strings.TrimSuffix(input.label, filepath.Ext(input.label))
+		}
+
+		if _, exists := labels[input.label]; exists {
+			return nil, fmt.Errorf("duplicate label %q. Use `<label>=<path>` to name each FTDC file", input.label)
+		}
+		labels[input.label] = struct{}{}
+		inputs = append(inputs, input)
+	}
+
+	return inputs, nil
+}
+
+// overlayLineStyles are the gnuplot line styles used for each FTDC file when overlaying them on one
+// graph. linestyle 6 (blue) is omitted as it is used for the vertical event lines.
+var overlayLineStyles = []int{7, 2, 4, 1, 3, 5, 8}
+
 // gnuplotWriter organizes all of the output for `gnuplot` to create a graph from FTDC
 // data. Notably:
 //   - Each graph consists of all the readings for an individual metric. There is one file per metric
@@ -40,11 +83,18 @@ type graphInfo struct {
 //     graphs at the same horizontal position will show readings as of a common point in time.
 type gnuplotWriter struct {
 	// metricFiles contains the actual data points to be graphed. The map key is a metric name and
-	// the value encapsulates the file with that metrics' datapoints. In addition to other metadata
+	// the value encapsulates the files with that metrics' datapoints. In addition to other metadata
 	// for plotting that data (e.g: min/max values for scaling). A "top level" gnuplot will
 	// reference them.
 	metricFiles map[string]*graphInfo
 
+	// label is the label of the FTDC file whose datapoints are currently being added. When
+	// overlaying multiple FTDC files, each graph will have one line per label.
+	label string
+	// overlay is whether multiple FTDC files are being graphed. If so, each graph is titled with
+	// its metric name and the legend names the FTDC file of each line.
+	overlay bool
+
 	// tempdir is a temporary directory for writing out all of the files that gnuplot will use to
 	// create a graph. This is expected to be of the form `/tmp/ftdc<random digits>`.
 	tempdir string
@@ -105,6 +155,14 @@ type graphOptions struct {
 	// user (where the user would like to find correlations in other metrics.)
 	vertLinesAtSeconds []int64
 
+	// metricFilter, if set, omits graphs for metrics whose names do not match it. This helps users
+	// focus on the metrics of a single subsystem.
+	metricFilter *regexp.Regexp
+
+	// outputFile is the name of the image file that gets rendered. Scripts can render multiple
+	// views of the same data into different files.
+	outputFile string
+
 	// maxPoints is how many data points will actually be graphed for each plot. Too many data
 	// points can be distracting. The algorithm is to divide the min/max time in `maxPoints`
 	// "equally distanced" timestamps. A user needing more fine-grained information is expected to
@@ -118,10 +176,24 @@ func defaultGraphOptions() graphOptions {
 		maxTimeSeconds:     math.MaxInt64,
 		hideAllZeroes:      true,
 		vertLinesAtSeconds: make([]int64, 0),
+		outputFile:         "plot.png",
 		maxPoints:          1000,
 	}
 }
 
+// timeRange returns the range of times to graph, with a zero time for an open side of the range.
+func (options graphOptions) timeRange() (time.Time, time.Time) {
+	var start, end time.Time
+	if options.minTimeSeconds > 0 {
+		start = time.Unix(options.minTimeSeconds, 0)
+	}
+	if options.maxTimeSeconds < math.MaxInt64 {
+		// Include the whole of the last second, as graphs compare times by the second.
+		end = time.Unix(options.maxTimeSeconds, int64(time.Second-1))
+	}
+	return start, end
+}
+
 func nolintPrintln(str ...any) {
 	// This is a CLI. It's acceptable to output to stdout.
 	//nolint:forbidigo
@@ -154,7 +226,7 @@ func writef(toWrite io.Writer, formatStr string, args ...any) {
 	write(toWrite, fmt.Sprintf(formatStr, args...))
 }
 
-func newGnuPlotWriter(graphOptions graphOptions, numDatapoints int, minTime, maxTime int64) *gnuplotWriter {
+func newGnuPlotWriter(graphOptions graphOptions, numDatapoints int, minTime, maxTime int64, overlay bool) *gnuplotWriter {
 	tempdir, err := os.MkdirTemp("", "ftdc_parser")
 	if err != nil {
 		panic(err)
@@ -179,9 +251,17 @@ func newGnuPlotWriter(graphOptions graphOptions, numDatapoints int, minTime, max
 		tempdir:        tempdir,
 		options:        graphOptions,
 		timesToInclude: timesToInclude,
+		overlay:        overlay,
 	}
 }
 
+// setLabel sets the label of the FTDC file whose datapoints are about to be added. Successive
+// calls to `shouldIncludePoint` start over from the first time to include.
+func (gpw *gnuplotWriter) setLabel(label string) {
+	gpw.label = label
+	gpw.shouldIncludePointStorage.nextTimeIdx = 0
+}
+
 // shouldIncludePoint returns one of the input `FlatDatum`s or nil. If a `FlatDatum` is returned,
 // the caller is expected to add it to the output graph. If nil is returned, neither are to be
 // added.
@@ -218,35 +298,41 @@ func (gpw *gnuplotWriter) shouldIncludePoint(this, next *ftdc.FlatDatum) *ftdc.F
 	return this
 }
 
-func (gpw *gnuplotWriter) getGraphInfo(metricName string) *graphInfo {
-	if gi, created := gpw.metricFiles[metricName]; created {
-		return gi
+// getDataFile returns the file to write the current label's datapoints for the metric to, along
+// with the metric's graphInfo.
+func (gpw *gnuplotWriter) getDataFile(metricName string) (*graphInfo, *os.File) {
+	gi, created := gpw.metricFiles[metricName]
+	if !created {
+		gi = &graphInfo{files: make(map[string]*os.File)}
+		gpw.metricFiles[metricName] = gi
+	}
+	if datafile, created := gi.files[gpw.label]; created {
+		return gi, datafile
 	}
 
 	datafile, err := os.CreateTemp(gpw.tempdir, "")
 	if err != nil {
 		panic(err)
 	}
+	gi.files[gpw.label] = datafile
 
-	ret := &graphInfo{
-		file: datafile,
-	}
-	gpw.metricFiles[metricName] = ret
-
-	return ret
+	return gi, datafile
 }
 
 func (gpw *gnuplotWriter) addPoint(timeSeconds int64, metricName string, metricValue float32) {
 	if timeSeconds < gpw.options.minTimeSeconds || timeSeconds > gpw.options.maxTimeSeconds {
 		return
 	}
+	if gpw.options.metricFilter != nil && !gpw.options.metricFilter.MatchString(metricName) {
+		return
+	}
 
 	// While we're adding points, track the min/max values we saw. This can be used to better scale
 	// graphs. As we've found gnuplots auto scaling to be a bit clunky.
-	gi := gpw.getGraphInfo(metricName)
+	gi, datafile := gpw.getDataFile(metricName)
 	gi.minVal = min(gi.minVal, int64(metricValue))
 	gi.maxVal = max(gi.maxVal, int64(metricValue))
-	writelnf(gi.file, "%v %.5f", timeSeconds, metricValue)
+	writelnf(datafile, "%v %.5f", timeSeconds, metricValue)
 }
 
 // ratioMetric describes which two FTDC metrics that should be combined to create a computed
@@ -486,9 +572,9 @@ func (gpw *gnuplotWriter) CompileAndClose() string {
 	// can rerun `gnuplot /<tmpdir>/main<unique value>` to recreate `plot.png` with the new
 	// settings/data.
 	nolintPrintln("Gnuplot dir:", gpw.tempdir)
-	nolintPrintln("Output file: `plot.png`")
+	nolintPrintln(fmt.Sprintf("Output file: `%v`", gpw.options.outputFile))
 	// The output filename
-	writeln(gnuFile, "set output 'plot.png'")
+	writelnf(gnuFile, "set output '%v'", gpw.options.outputFile)
 
 	// We're making separate graphs instead of a single big graph. The graphs will be arranged in a
 	// rectangle with 1 column and X rows. Where X is the number of metrics.  Add some margins for
@@ -514,6 +600,9 @@ func (gpw *gnuplotWriter) CompileAndClose() string {
 	// single graph.
 	for _, nameFilePair := range sorted(gpw.metricFiles) {
 		metricName, graphInfo := nameFilePair.Key, nameFilePair.Val
+		for _, datafile := range graphInfo.files {
+			utils.UncheckedErrorFunc(datafile.Close)
+		}
 		if gpw.options.hideAllZeroes && graphInfo.minVal == 0 && graphInfo.maxVal == 0 {
 			allZeroesHidden++
 			continue
@@ -548,7 +637,24 @@ func (gpw *gnuplotWriter) CompileAndClose() string {
 		//
 		// linestyle 7 is red, 6 is blue, lw is line-width (or weight) -- makes it thicker. The
 		// title is what's used in the legend.
-		writef(gnuFile, "plot '%v' using 1:2 with lines linestyle 7 lw 4 title '%v'", graphInfo.file.Name(), strings.ReplaceAll(metricName, "_", "\\_"))
+		//
+		// When overlaying multiple FTDC files, the graph is instead titled with the metric name and
+		// there is one line per FTDC file, each with its own color and its label in the legend.
+		if !gpw.overlay {
+			writef(gnuFile, "plot '%v' using 1:2 with lines linestyle 7 lw 4 title '%v'",
+				graphInfo.files[""].Name(), escapeTitle(metricName))
+		} else {
+			writelnf(gnuFile, "set title '%v'", escapeTitle(metricName))
+			write(gnuFile, "plot ")
+			for idx, labelFilePair := range sorted(graphInfo.files) {
+				if idx > 0 {
+					writeln(gnuFile, ",\\")
+					write(gnuFile, "\t")
+				}
+				writef(gnuFile, "'%v' using 1:2 with lines linestyle %d lw 4 title '%v'",
+					labelFilePair.Val.Name(), overlayLineStyles[idx%len(overlayLineStyles)], escapeTitle(labelFilePair.Key))
+			}
+		}
 
 		// "vertical lines" for events are rendered as another set of data points for a
 		// `plot`. Because the vertical lines are at the same x-value/time for each graph, we can
@@ -564,8 +670,6 @@ func (gpw *gnuplotWriter) CompileAndClose() string {
 
 		// The trailing newline for the above calls to write out a single plot.
 		writeln(gnuFile, "")
-
-		utils.UncheckedErrorFunc(graphInfo.file.Close)
 	}
 	if allZeroesHidden > 0 {
 		nolintPrintln("Hid metrics that only had 0s for data. Cnt:", allZeroesHidden)
@@ -591,6 +695,46 @@ func (gpw *gnuplotWriter) CompileAndClose() string {
 	return gnuFile.Name()
 }
 
+// buildGraphs writes out the datapoints of every FTDC file to graph, as selected by the graph
+// options.
+func buildGraphs(options graphOptions, inputs []ftdcInput, datas [][]ftdc.FlatDatum, logger logging.Logger) *gnuplotWriter {
+	// All of the graphs share the same X (Time) axis, spanning the data of every input. We select
+	// datapoints to graph based on the input with the most of them.
+	numDatapoints := 0
+	minTime, maxTime := int64(math.MaxInt64), int64(math.MinInt64)
+	for _, data := range datas {
+		numDatapoints = max(numDatapoints, len(data))
+		minTime = min(minTime, data[0].Time)
+		maxTime = max(maxTime, data[len(data)-1].Time)
+	}
+
+	gpw := newGnuPlotWriter(options, numDatapoints, minTime, maxTime, len(inputs) > 1)
+	for inputIdx, data := range datas {
+		gpw.setLabel(inputs[inputIdx].label)
+		deferredValues := make([]map[string]*ratioReading, 0)
+		for idx := 0; idx < len(data)-1; idx++ {
+			thisDatum, nextDatum := data[idx], data[idx+1]
+			if pt := gpw.shouldIncludePoint(&thisDatum, &nextDatum); pt != nil {
+				deferredValues = append(deferredValues, gpw.addFlatDatum(*pt))
+			}
+		}
+		if gpw.timesToInclude == nil {
+			// If we're including all of the data points, don't forget the last one.
+			deferredValues = append(deferredValues, gpw.addFlatDatum(data[len(data)-1]))
+		}
+
+		gpw.writeDeferredValues(deferredValues, logger)
+	}
+
+	return gpw
+}
+
+// escapeTitle escapes underscores in a graph or legend title, which gnuplot otherwise renders as
+// subscripts.
+func escapeTitle(title string) string {
+	return strings.ReplaceAll(title, "_", "\\_")
+}
+
 func parseStringAsTime(inp string) (time.Time, error) {
 	goTime, err := time.Parse("2006-01-02T15:04:05", inp)
 	if err != nil {
@@ -603,58 +747,196 @@ func parseStringAsTime(inp string) (time.Time, error) {
 	return goTime, nil
 }
 
+// verifyFTDCFile prints which chunks of the FTDC file are corrupt and how much data can be recovered.
+func verifyFTDCFile(input ftdcInput, logger logging.Logger) {
+	ftdcFile, err := os.Open(input.path)
+	if err != nil {
+		nolintPrintln("Error opening file. File:", input.path, "Err:", err)
+		return
+	}
+	defer utils.UncheckedErrorFunc(ftdcFile.Close)
+
+	// Corrupt chunks are reported below. Only log errors.
+	logger = logger.Sublogger("verify")
+	logger.SetLevel(logging.ERROR)
+	result, err := ftdc.Verify(ftdcFile, logger)
+
+	var output strings.Builder
+	writelnf(&output, "File: %v Chunks: %d Corrupt: %d Datums recovered: %d",
+		input.path, result.NumChunks, len(result.CorruptChunks), result.NumDatums)
+	for _, chunk := range result.CorruptChunks {
+		writelnf(&output, "  Corrupt chunk: %d Offset: %d Length: %d", chunk.Index, chunk.Offset, chunk.Length)
+	}
+	if result.NumSkippedChunks > 0 {
+		writelnf(&output, "  Intact chunks skipped for following a corrupt chunk: %d", result.NumSkippedChunks)
+	}
+	if result.Unchecksummed {
+		writeln(&output, "  Some of the file has no checksums and cannot be verified.")
+	}
+	if err != nil {
+		writelnf(&output, "  Could not read to the end of the file. Err: %v", err)
+	}
+	write(os.Stdout, output.String())
+}
+
+// printMetadata prints which machine the FTDC file was captured on, such that captures from a
+// fleet can be told apart.
+func printMetadata(input ftdcInput, metadata *ftdc.Metadata) {
+	if metadata == nil {
+		nolintPrintln("File:", input.path, "has no machine metadata")
+		return
+	}
+
+	var output strings.Builder
+	writelnf(&output, "File: %v Label: %v", input.path, input.label)
+	writelnf(&output, "  Part ID: %v Robot: %v Version: %v", metadata.PartID, metadata.RobotName, metadata.Version)
+	writelnf(&output, "  Host: %v OS: %v Arch: %v", metadata.Hostname, metadata.OS, metadata.Arch)
+	write(os.Stdout, output.String())
+}
+
 func main() {
-	if len(os.Args) < 2 {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		runExport(os.Args[2:])
+		return
+	}
+
+	scriptPath := flag.String("script", "",
+		"run the commands in this file, one per line, instead of reading them interactively. "+
+			"Graphs are only rendered by `render` commands")
+	serveAddr := flag.String("serve", "",
+		"serve the graphs as an HTML page at this address, e.g: `localhost:8080`, instead of running gnuplot")
+	logPath := flag.String("log", "",
+		"a viam-server log to show alongside the graphs served with --serve, following the graphs' time cursor")
+	follow := flag.Bool("follow", false,
+		"follow FTDC files that viam-server is still writing to, rendering the graphs again as new data is written")
+	verify := flag.Bool("verify", false,
+		"check the checksums of the FTDC files and report which chunks are corrupt, instead of graphing them")
+	var pluginPaths []string
+	flag.Func("plugin",
+		"load the renderers of a Go plugin, for the `export` command. May be given multiple times",
+		func(path string) error {
+			pluginPaths = append(pluginPaths, path)
+			return nil
+		})
+	flag.Parse()
+	if flag.NArg() < 1 {
 		nolintPrintln("Expected an FTDC filename. E.g: go run parser.go <path-to>/viam-server.ftdc")
+		nolintPrintln("FTDC files may be gzip or zstd compressed. E.g: <path-to>/viam-server.ftdc.gz")
+		nolintPrintln("To compare robots, pass multiple labeled FTDC files. " +
+			"E.g: go run parser.go robot1=<path-to>/viam-server.ftdc robot2=<path-to>/viam-server.ftdc")
+		nolintPrintln("To run a script of commands, pass it before the FTDC files. " +
+			"E.g: go run parser.go --script <path-to>/cmds.txt <path-to>/viam-server.ftdc")
+		nolintPrintln("To check FTDC files for corruption: go run parser.go --verify <path-to>/viam-server.ftdc")
+		nolintPrintln("To graph a running viam-server's FTDC file as it is written: " +
+			"go run parser.go --follow <path-to>/viam-server.ftdc")
+		nolintPrintln("To export FTDC files without graphing them: go run parser.go export " +
+			"--format=csv|json|prometheus --metrics=<regex> --out=<path-or-url> <path-to>/viam-server.ftdc")
+		nolintPrintln("To browse the graphs alongside a viam-server log, serve them as an HTML page. " +
+			"E.g: go run parser.go --serve localhost:8080 --log <path-to>/viam-server.log <path-to>/viam-server.ftdc")
+		return
+	}
+	if *logPath != "" && *serveAddr == "" {
+		nolintPrintln("--log is only supported with --serve.")
 		return
 	}
+	if *follow && (*serveAddr != "" || *scriptPath != "" || *verify) {
+		nolintPrintln("--follow is not supported with --serve, --script or --verify.")
+		return
+	}
+
+	for _, path := range pluginPaths {
+		if err := loadRendererPlugin(path); err != nil {
+			nolintPrintln("Error loading plugin. File:", path, "Err:", err)
+			return
+		}
+	}
 
-	ftdcFile, err := os.Open(os.Args[1])
+	inputs, err := parseInputs(flag.Args())
 	if err != nil {
-		nolintPrintln("Error opening file. File:", os.Args[1], "Err:", err)
-		nolintPrintln("Expected an FTDC filename. E.g: go run parser.go <path-to>/viam-server.ftdc")
+		nolintPrintln("Error parsing arguments. Err:", err)
 		return
 	}
 
 	logger := logging.NewLogger("parser")
-	data, err := ftdc.ParseWithLogger(ftdcFile, logger)
-	if err != nil {
-		panic(err)
+	if *verify {
+		for _, input := range inputs {
+			verifyFTDCFile(input, logger)
+		}
+		return
+	}
+
+	// datas contains the parsed FTDC data of each input, in the same order.
+	datas := make([][]ftdc.FlatDatum, len(inputs))
+	for idx, input := range inputs {
+		if _, err := os.Stat(input.path); err != nil {
+			nolintPrintln("Error opening file. File:", input.path, "Err:", err)
+			nolintPrintln("Expected an FTDC filename. E.g: go run parser.go <path-to>/viam-server.ftdc")
+			return
+		}
+
+		data, metadata, err := parseFTDCFile(input.path, defaultGraphOptions(), logger)
+		if err != nil {
+			panic(err)
+		}
+		if len(data) == 0 {
+			nolintPrintln("FTDC file has no data. File:", input.path)
+			return
+		}
+		datas[idx] = data
+		printMetadata(input, metadata)
+	}
+
+	if *serveAddr != "" {
+		if err := runServer(*serveAddr, *logPath, inputs, defaultGraphOptions(), logger); err != nil {
+			nolintPrintln("Error serving graphs. Err:", err)
+		}
+		return
 	}
 
 	stdinReader := bufio.NewReader(os.Stdin)
-	render := true
+	// cmdReader is where commands are read from. Either the user's input, or the script.
+	cmdReader := stdinReader
+	scripted := *scriptPath != ""
+	if scripted {
+		scriptFile, err := os.Open(*scriptPath)
+		if err != nil {
+			nolintPrintln("Error opening script. File:", *scriptPath, "Err:", err)
+			return
+		}
+		defer utils.UncheckedErrorFunc(scriptFile.Close)
+		cmdReader = bufio.NewReader(scriptFile)
+	}
+
+	// When running a script, graphs are only rendered when the script asks for it. Such that a
+	// script can set up a view with multiple commands before rendering it.
+	render := !scripted
 	graphOptions := defaultGraphOptions()
 	for {
 		if render {
-			deferredValues := make([]map[string]*ratioReading, 0)
-			gpw := newGnuPlotWriter(graphOptions, len(data), data[0].Time, data[len(data)-1].Time)
-			for idx := 0; idx < len(data)-1; idx++ {
-				thisDatum, nextDatum := data[idx], data[idx+1]
-				if pt := gpw.shouldIncludePoint(&thisDatum, &nextDatum); pt != nil {
-					deferredValues = append(deferredValues, gpw.addFlatDatum(*pt))
-				}
-			}
-			if gpw.timesToInclude == nil {
-				// If we're including all of the data points, don't forget the last one.
-				deferredValues = append(deferredValues, gpw.addFlatDatum(data[len(data)-1]))
-			}
-
-			gpw.writeDeferredValues(deferredValues, logger)
-
-			gpw.Render()
+			buildGraphs(graphOptions, inputs, datas, logger).Render()
+		}
+		render = !scripted
+		if *follow {
+			*follow = false
+			runFollow(inputs, datas, graphOptions, stdinReader, logger)
 		}
-		render = true
 
 		// This is a CLI. It's acceptable to output to stdout.
 		//nolint:forbidigo
 		fmt.Print("$ ")
-		cmd, err := stdinReader.ReadString('\n')
+		cmd, err := cmdReader.ReadString('\n')
 		cmd = strings.TrimSpace(cmd)
+		if scripted && len(cmd) > 0 {
+			// Echo the commands of the script, as if they were typed in.
+			nolintPrintln(cmd)
+		}
 		switch {
-		case err != nil && errors.Is(err, io.EOF):
+		case err != nil && errors.Is(err, io.EOF) && (!scripted || len(cmd) == 0):
 			nolintPrintln("\nExiting...")
 			return
+		case strings.HasPrefix(cmd, "#"):
+			// A comment, such as for documenting the views of a script.
+			render = false
 		case cmd == "quit":
 			nolintPrintln("Exiting...")
 			return
@@ -670,11 +952,48 @@ func main() {
 			nolintPrintln("reset range")
 			nolintPrintln("-  Unset any prior range. \"zoom out to full\"")
 			nolintPrintln()
-			nolintPrintln("r, refresh")
+			nolintPrintln("filter <regex>")
+			nolintPrintln("-  Only plot metrics whose names match the regular expression.")
+			nolintPrintln("-  E.g: filter ^proc\\.viam-server\\.")
+			nolintPrintln()
+			nolintPrintln("reset filter")
+			nolintPrintln("-  Unset any prior filter. Plot all metrics.")
+			nolintPrintln()
+			nolintPrintln("output <filename>")
+			nolintPrintln("-  Render future images to the given file instead of plot.png.")
+			nolintPrintln()
+			nolintPrintln("r, refresh, render")
 			nolintPrintln("-  Regenerate the plot.png image. Useful when a current viam-server is running.")
 			nolintPrintln()
+			nolintPrintln("follow")
+			nolintPrintln("-  Follow the FTDC file(s) of a running viam-server, regenerating the plot.png image")
+			nolintPrintln("-  as new data is written. Press enter to stop.")
+			nolintPrintln()
+			nolintPrintln("prometheus <url>")
+			nolintPrintln("-  Push the datapoints within the current range to a prometheus remote-write endpoint,")
+			nolintPrintln("-  with their original timestamps. Each FTDC file's label is added as a `robot` label.")
+			nolintPrintln("-  E.g: prometheus http://localhost:9009/api/v1/push")
+			nolintPrintln()
+			nolintPrintln("export <renderer> <target>")
+			nolintPrintln("-  Render the datapoints within the current range with a renderer. Renderers:",
+				strings.Join(ftdc.RegisteredRenderers(), ", "))
+			nolintPrintln("-  Only the metrics matching the current filter are rendered.")
+			nolintPrintln("-  `csv` and `json` write to the target file, with a row or line per reading. Metric names are")
+			nolintPrintln("-  split into `api`, `resource` and `process` labels.")
+			nolintPrintln("-  `exec` runs the target command, writing each datapoint to its stdin as a line of JSON.")
+			nolintPrintln("-  More renderers can be loaded from Go plugins with --plugin.")
+			nolintPrintln("-  E.g: export csv ftdc.csv")
+			nolintPrintln("-  E.g: export exec python3 dashboard.py")
+			nolintPrintln()
+			nolintPrintln("top [<count>] [change|stddev]")
+			nolintPrintln("-  Follow the FTDC file(s) of a running viam-server, continuously listing the metrics")
+			nolintPrintln("-  with the largest change or standard deviation over the last", topWindowSecs, "seconds.")
+			nolintPrintln("-  Press enter to stop. Defaults to the top", topDefaultCount, "metrics by change.")
+			nolintPrintln("-  E.g: top 10 stddev")
+			nolintPrintln()
 			nolintPrintln("`quit` or Ctrl-d to exit")
 		case strings.HasPrefix(cmd, "range "):
+			prevOptions := graphOptions
 			pieces := strings.SplitN(cmd, " ", 3)
 			// TrimSpace to remove the newline.
 			start, end := pieces[1], pieces[2]
@@ -696,17 +1015,77 @@ func main() {
 					graphOptions.maxTimeSeconds = goTime.Unix()
 				}
 			}
+			// Only the datums within the range are read, such that zooming in on large files
+			// does not need to hold all of their data.
+			if reloaded, ok := reloadInputs(inputs, graphOptions, logger); ok {
+				datas = reloaded
+			} else {
+				graphOptions = prevOptions
+				render = false
+			}
 		case strings.HasPrefix(cmd, "reset range"):
 			graphOptions.minTimeSeconds = 0
 			graphOptions.maxTimeSeconds = math.MaxInt64
+			if reloaded, ok := reloadInputs(inputs, graphOptions, logger); ok {
+				datas = reloaded
+			} else {
+				render = false
+			}
+		case strings.HasPrefix(cmd, "filter "):
+			metricFilter, err := regexp.Compile(strings.TrimSpace(strings.TrimPrefix(cmd, "filter ")))
+			if err != nil {
+				nolintPrintln("Error parsing filter. Err:", err)
+				render = false
+				break
+			}
+			graphOptions.metricFilter = metricFilter
+		case cmd == "reset filter":
+			graphOptions.metricFilter = nil
+		case strings.HasPrefix(cmd, "output "):
+			graphOptions.outputFile = strings.TrimSpace(strings.TrimPrefix(cmd, "output "))
+			render = false
 		case strings.HasPrefix(cmd, "ev ") || strings.HasPrefix(cmd, "event "):
 			pieces := strings.SplitN(cmd, " ", 2)
 			if goTime, err := parseStringAsTime(pieces[1]); err == nil {
 				// parseStringAsTime outputs an error message for us.
 				graphOptions.vertLinesAtSeconds = append(graphOptions.vertLinesAtSeconds, goTime.Unix())
 			}
-		case cmd == "refresh" || cmd == "r":
+		case cmd == "top" || strings.HasPrefix(cmd, "top "):
+			render = false
+			if scripted {
+				nolintPrintln("`top` is interactive and cannot be run from a script.")
+				break
+			}
+			count, sortKey, err := parseTopArgs(strings.Fields(cmd)[1:])
+			if err != nil {
+				nolintPrintln("Error parsing top command. Err:", err)
+				break
+			}
+			runTop(inputs, count, sortKey, stdinReader, logger)
+		case strings.HasPrefix(cmd, "prometheus "):
+			render = false
+			renderData("prometheus", strings.TrimSpace(strings.TrimPrefix(cmd, "prometheus ")), inputs, datas, graphOptions, logger)
+		case strings.HasPrefix(cmd, "export "):
+			render = false
+			pieces := strings.SplitN(cmd, " ", 3)
+			target := ""
+			if len(pieces) == 3 {
+				target = strings.TrimSpace(pieces[2])
+			}
+			renderData(pieces[1], target, inputs, datas, graphOptions, logger)
+		case cmd == "refresh" || cmd == "r" || cmd == "render":
 			nolintPrintln("Refreshing graphs with new data")
+			if reloaded, ok := reloadInputs(inputs, graphOptions, logger); ok {
+				datas = reloaded
+			}
+			render = true
+		case cmd == "follow":
+			render = false
+			if scripted {
+				nolintPrintln("`follow` is interactive and cannot be run from a script.")
+				break
+			}
+			runFollow(inputs, datas, graphOptions, stdinReader, logger)
 		case len(cmd) == 0:
 			render = false
 		default:
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"plugin"
+	"strings"
+
+	"go.viam.com/rdk/ftdc"
+	"go.viam.com/rdk/logging"
+)
+
+// rendererPluginSymbol is the function a renderer plugin exports to register its renderers.
+const rendererPluginSymbol = "RegisterRenderers"
+
+// loadRendererPlugin opens a Go plugin and calls its `RegisterRenderers` function, which is expected
+// to register the plugin's renderers with `ftdc.RegisterRenderer`. This lets teams ship their own
+// output backends without forking the parser. A plugin is a `main` package built with
+// `go build -buildmode=plugin` against the same version of this module as the parser. E.g:
+//
+//	package main
+//
+//	func RegisterRenderers() {
+//		ftdc.RegisterRenderer("dashboard", newDashboardRenderer)
+//	}
+//
+// Renderers that are not written in Go can instead be run with the builtin `exec` renderer.
+func loadRendererPlugin(path string) error {
+	plug, err := plugin.Open(path)
+	if err != nil {
+		return err
+	}
+	sym, err := plug.Lookup(rendererPluginSymbol)
+	if err != nil {
+		return err
+	}
+	register, ok := sym.(func())
+	if !ok {
+		return fmt.Errorf("%v must be a `func()`, not %T", rendererPluginSymbol, sym)
+	}
+	register()
+	return nil
+}
+
+// renderData renders the datapoints of each FTDC file within the range of the graph options with
+// the named renderer, writing to the target. Only the metrics matching the graph options' filter,
+// if any, are rendered.
+func renderData(
+	name, target string,
+	inputs []ftdcInput,
+	datas [][]ftdc.FlatDatum,
+	options graphOptions,
+	logger logging.Logger,
+) {
+	factory, ok := ftdc.LookupRenderer(name)
+	if !ok {
+		nolintPrintln("Unknown renderer:", name, "Renderers:", strings.Join(ftdc.RegisteredRenderers(), ", "))
+		return
+	}
+	renderer, err := factory(target, logger)
+	if err != nil {
+		nolintPrintln("Error creating renderer. Renderer:", name, "Err:", err)
+		return
+	}
+	defer func() {
+		if err := renderer.Close(); err != nil {
+			nolintPrintln("Error closing renderer. Renderer:", name, "Err:", err)
+		}
+	}()
+
+	for idx, input := range inputs {
+		var toRender []ftdc.FlatDatum
+		for _, datum := range datas[idx] {
+			timeSeconds := datum.ConvertedTime().Unix()
+			if timeSeconds >= options.minTimeSeconds && timeSeconds <= options.maxTimeSeconds {
+				toRender = append(toRender, datum)
+			}
+		}
+		toRender = filterReadings(toRender, options.metricFilter)
+
+		if err := renderer.Render(context.Background(), input.label, toRender); err != nil {
+			nolintPrintln("Error rendering. Renderer:", name, "File:", input.path, "Err:", err)
+			return
+		}
+		nolintPrintln("Rendered", len(toRender), "datapoints with", name+". File:", input.path)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/ftdc"
+	"go.viam.com/rdk/logging"
+)
+
+//go:embed serve.html
+var serveHTML []byte
+
+// maxLogLineBytes bounds how long a single viam-server log line may be. Log lines with large
+// structured fields (e.g: configs) can be much longer than bufio.Scanner's default limit.
+const maxLogLineBytes = 1 << 20
+
+// servedGraph is the data for a single graph of the HTML page. There is one line per FTDC file.
+type servedGraph struct {
+	Metric string       `json:"metric"`
+	Lines  []servedLine `json:"lines"`
+}
+
+type servedLine struct {
+	Label string `json:"label"`
+	// Points are (seconds since the epoch, value) pairs, in time order.
+	Points [][2]float64 `json:"points"`
+}
+
+// logLine is a single entry of a viam-server log. Entries spanning multiple lines, such as stack
+// traces, are kept together.
+type logLine struct {
+	// Time is in (fractional) seconds since the epoch.
+	Time float64 `json:"time"`
+	Text string  `json:"text"`
+}
+
+type servedData struct {
+	Graphs []servedGraph `json:"graphs"`
+	Logs   []logLine     `json:"logs"`
+	// Events are the times, in seconds since the epoch, of the vertical lines to draw.
+	Events []int64 `json:"events"`
+}
+
+// series reads back the datapoints written out for gnuplot, for each metric that would be
+// graphed. The underlying files are removed.
+func (gpw *gnuplotWriter) series() ([]servedGraph, error) {
+	defer utils.UncheckedErrorFunc(func() error { return os.RemoveAll(gpw.tempdir) })
+
+	var graphs []servedGraph
+	for _, nameFilePair := range sorted(gpw.metricFiles) {
+		metricName, graphInfo := nameFilePair.Key, nameFilePair.Val
+		for _, datafile := range graphInfo.files {
+			utils.UncheckedErrorFunc(datafile.Close)
+		}
+		if gpw.options.hideAllZeroes && graphInfo.minVal == 0 && graphInfo.maxVal == 0 {
+			continue
+		}
+
+		graph := servedGraph{Metric: metricName}
+		for _, labelFilePair := range sorted(graphInfo.files) {
+			contents, err := os.ReadFile(labelFilePair.Val.Name())
+			if err != nil {
+				return nil, err
+			}
+
+			line := servedLine{Label: labelFilePair.Key, Points: [][2]float64{}}
+			for _, point := range strings.Split(strings.TrimSpace(string(contents)), "\n") {
+				timeStr, valueStr, found := strings.Cut(point, " ")
+				if !found {
+					continue
+				}
+				timeSeconds, err := strconv.ParseFloat(timeStr, 64)
+				if err != nil {
+					return nil, err
+				}
+				value, err := strconv.ParseFloat(valueStr, 64)
+				if err != nil {
+					return nil, err
+				}
+				line.Points = append(line.Points, [2]float64{timeSeconds, value})
+			}
+			graph.Lines = append(graph.Lines, line)
+		}
+		graphs = append(graphs, graph)
+	}
+
+	return graphs, nil
+}
+
+// parseLogFile reads the entries of a viam-server log file. Each entry starts with its RFC3339
+// timestamp, e.g: `2024-09-24T18:00:00.123Z	INFO	rdk	...`. Lines without a timestamp continue
+// the entry before them.
+func parseLogFile(path string) ([]logLine, error) {
+	//nolint:gosec
+	logFile, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.UncheckedErrorFunc(logFile.Close)
+
+	logs := make([]logLine, 0)
+	scanner := bufio.NewScanner(logFile)
+	scanner.Buffer(nil, maxLogLineBytes)
+	for scanner.Scan() {
+		text := scanner.Text()
+		timeStr, _, _ := strings.Cut(strings.TrimSpace(text), "\t")
+		timeStr, _, _ = strings.Cut(timeStr, " ")
+		if logTime, err := time.Parse(time.RFC3339Nano, timeStr); err == nil {
+			logs = append(logs, logLine{Time: float64(logTime.UnixNano()) / 1e9, Text: text})
+			continue
+		}
+
+		if len(logs) > 0 {
+			logs[len(logs)-1].Text += "\n" + text
+		}
+	}
+
+	return logs, scanner.Err()
+}
+
+// runServer serves an HTML page graphing the FTDC files alongside the viam-server log at
+// `logPath`, if any. Moving the time cursor across the graphs scrolls the log to the lines logged
+// at that time, and clicking a log line moves the cursor to it. The FTDC files and log are re-read
+// on every page load, such that refreshing the page picks up new data from a running viam-server.
+func runServer(addr, logPath string, inputs []ftdcInput, options graphOptions, logger logging.Logger) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		//nolint:errcheck
+		w.Write(serveHTML)
+	})
+	mux.HandleFunc("/data", func(w http.ResponseWriter, r *http.Request) {
+		requestOptions := options
+		if filter := r.URL.Query().Get("filter"); filter != "" {
+			metricFilter, err := regexp.Compile(filter)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			requestOptions.metricFilter = metricFilter
+		}
+
+		data, err := loadServedData(logPath, inputs, requestOptions, logger)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			logger.Warnw("Error writing graph data", "err", err)
+		}
+	})
+
+	nolintPrintln("Serving graphs at", addr)
+	//nolint:gosec
+	return http.ListenAndServe(addr, mux)
+}
+
+func loadServedData(logPath string, inputs []ftdcInput, options graphOptions, logger logging.Logger) (*servedData, error) {
+	datas := make([][]ftdc.FlatDatum, len(inputs))
+	for idx, input := range inputs {
+		data, _, err := parseFTDCFile(input.path, options, logger)
+		if err != nil && len(data) == 0 {
+			return nil, err
+		}
+		if len(data) == 0 {
+			return nil, errors.New("FTDC file has no data: " + input.path)
+		}
+		// A file that is still being written to may end with a partial datum. Graph what we have.
+		datas[idx] = data
+	}
+
+	graphs, err := buildGraphs(options, inputs, datas, logger).series()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &servedData{Graphs: graphs, Logs: []logLine{}, Events: options.vertLinesAtSeconds}
+	if logPath != "" {
+		if ret.Logs, err = parseLogFile(logPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/ftdc"
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	// topWindowSecs is how far back, in seconds from the latest reading, `top` looks when computing
+	// how much each metric has recently changed.
+	topWindowSecs = 30
+	// topRefreshInterval is how often `top` re-reads the FTDC files and redraws.
+	topRefreshInterval = time.Second
+	// topDefaultCount is how many metrics `top` shows when the user does not ask for a number.
+	topDefaultCount = 20
+)
+
+// topSortKey is which statistic `top` sorts metrics by.
+type topSortKey string
+
+const (
+	// sortByChange sorts metrics by the absolute difference between their first and last readings in
+	// the window.
+	sortByChange topSortKey = "change"
+	// sortByStddev sorts metrics by the standard deviation of their readings in the window. This
+	// better surfaces metrics that fluctuate without trending in one direction.
+	sortByStddev topSortKey = "stddev"
+)
+
+// metricChange describes how a single metric has changed over the `top` window.
+type metricChange struct {
+	name   string
+	latest float64
+	change float64
+	stddev float64
+}
+
+func (mc metricChange) score(sortKey topSortKey) float64 {
+	if sortKey == sortByStddev {
+		return mc.stddev
+	}
+	return math.Abs(mc.change)
+}
+
+// computeMetricChanges returns how each metric in `data` has changed over the `windowSecs` seconds
+// preceding the latest datum. Metric names are prefixed with `label` when it is not empty, such that
+// metrics from multiple FTDC files can be listed together.
+func computeMetricChanges(data []ftdc.FlatDatum, label string, windowSecs int64) []metricChange {
+	if len(data) == 0 {
+		return nil
+	}
+
+	windowStart := data[len(data)-1].ConvertedTime().Unix() - windowSecs
+	// values contains the readings of each metric within the window, in FTDC reading order.
+	values := make(map[string][]float64)
+	for _, datum := range data {
+		if datum.ConvertedTime().Unix() < windowStart {
+			continue
+		}
+		for _, reading := range datum.Readings {
+			values[reading.MetricName] = append(values[reading.MetricName], float64(reading.Value))
+		}
+	}
+
+	ret := make([]metricChange, 0, len(values))
+	for _, nameValuesPair := range sorted(values) {
+		metricName, metricValues := nameValuesPair.Key, nameValuesPair.Val
+		if label != "" {
+			metricName = fmt.Sprint(label, ": ", metricName)
+		}
+
+		var mean float64
+		for _, value := range metricValues {
+			mean += value
+		}
+		mean /= float64(len(metricValues))
+
+		var variance float64
+		for _, value := range metricValues {
+			variance += (value - mean) * (value - mean)
+		}
+		variance /= float64(len(metricValues))
+
+		ret = append(ret, metricChange{
+			name:   metricName,
+			latest: metricValues[len(metricValues)-1],
+			change: metricValues[len(metricValues)-1] - metricValues[0],
+			stddev: math.Sqrt(variance),
+		})
+	}
+
+	return ret
+}
+
+// topMetrics returns the `count` metrics with the highest score for the `sortKey`. Metrics that
+// did not change at all are omitted.
+func topMetrics(changes []metricChange, count int, sortKey topSortKey) []metricChange {
+	ret := make([]metricChange, 0, len(changes))
+	for _, change := range changes {
+		if change.score(sortKey) > 0 {
+			ret = append(ret, change)
+		}
+	}
+
+	// Sort stably by descending score, such that ties keep metric name order and the output does
+	// not jump around between refreshes.
+	slices.SortStableFunc(ret, func(left, right metricChange) int {
+		return -cmpFloat(left.score(sortKey), right.score(sortKey))
+	})
+	if len(ret) > count {
+		ret = ret[:count]
+	}
+
+	return ret
+}
+
+func cmpFloat(left, right float64) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// parseTopArgs parses the arguments of a `top [<count>] [change|stddev]` command.
+func parseTopArgs(args []string) (int, topSortKey, error) {
+	count, sortKey := topDefaultCount, sortByChange
+	for _, arg := range args {
+		switch {
+		case arg == string(sortByChange) || arg == string(sortByStddev):
+			sortKey = topSortKey(arg)
+		default:
+			parsed, err := strconv.Atoi(arg)
+			if err != nil || parsed < 1 {
+				return 0, "", fmt.Errorf("expected a positive number of metrics or `change`/`stddev`, got %q", arg)
+			}
+			count = parsed
+		}
+	}
+
+	return count, sortKey, nil
+}
+
+// runTop continuously re-reads the FTDC files and prints the metrics that changed the most
+// recently, like `top`. It returns when the user presses enter.
+func runTop(inputs []ftdcInput, count int, sortKey topSortKey, stdinReader *bufio.Reader, logger logging.Logger) {
+	stop := make(chan struct{})
+	utils.PanicCapturingGo(func() {
+		defer close(stop)
+		//nolint:errcheck
+		stdinReader.ReadString('\n')
+	})
+
+	ticker := time.NewTicker(topRefreshInterval)
+	defer ticker.Stop()
+	for {
+		var changes []metricChange
+		for _, input := range inputs {
+			data, _, err := parseFTDCFile(input.path, defaultGraphOptions(), logger)
+			if err != nil {
+				// The FTDC file may be in the middle of being written to. Try again next refresh.
+				logger.Debugw("Error reading FTDC file", "file", input.path, "err", err)
+				continue
+			}
+			changes = append(changes, computeMetricChanges(data, input.label, topWindowSecs)...)
+		}
+
+		// Clear the terminal and move the cursor to the top left before redrawing.
+		var output strings.Builder
+		write(&output, "\033[H\033[2J")
+		writelnf(&output, "Metrics with the largest %s over the last %d seconds. As of: %v",
+			sortKey, topWindowSecs, time.Now().UTC().Format(time.DateTime))
+		writeln(&output, "Press enter to stop.")
+		writeln(&output, "")
+		writelnf(&output, "%14s %14s %14s  %s", "LATEST", "CHANGE", "STDDEV", "METRIC")
+		for _, change := range topMetrics(changes, count, sortKey) {
+			writelnf(&output, "%14.3f %+14.3f %14.3f  %s", change.latest, change.change, change.stddev, change.name)
+		}
+		write(os.Stdout, output.String())
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseFTDCFile opens and parses the datums of the FTDC file at `path` within the time range of
+// the graph options. Datums outside of the range are not decoded, such that a range over a large
+// file only needs memory for that range. The returned metadata is nil for files that do not record
+// it. A file that is still being written to may end with a partial datum, which is ignored.
+func parseFTDCFile(path string, options graphOptions, logger logging.Logger) ([]ftdc.FlatDatum, *ftdc.Metadata, error) {
+	ftdcFile, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return nil, nil, err
+	}
+	defer utils.UncheckedErrorFunc(ftdcFile.Close)
+
+	reader, err := ftdc.NewReader(ftdcFile, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer reader.Close()
+	reader.SetTimeRange(options.timeRange())
+
+	var data []ftdc.FlatDatum
+	for {
+		datum, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return data, reader.Metadata(), nil
+		}
+		if err != nil {
+			return data, reader.Metadata(), err
+		}
+		data = append(data, datum)
+	}
+}
@@ -0,0 +1,44 @@
+package ftdc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	// gzipMagic and zstdMagic are the bytes that gzip and zstd compressed data start with. An FTDC
+	// file starts with a schema document, whose first byte is 0x1, so neither can be mistaken for
+	// an uncompressed FTDC file.
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// decompressReader returns a reader of the decompressed contents of `reader` if it holds gzip or
+// zstd compressed data, as operators often compress FTDC files before copying them off of a
+// robot. Otherwise `reader` is returned as is. The returned function releases the resources of
+// the decompressor and must be called once done reading.
+func decompressReader(reader *bufio.Reader) (*bufio.Reader, func(), error) {
+	// Peek returns fewer bytes along with an error when the input is shorter than the magic
+	// bytes. Such inputs cannot be compressed, so the error is left to the FTDC parser.
+	//nolint:errcheck
+	peek, _ := reader.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(peek, gzipMagic):
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		return bufio.NewReader(gzipReader), func() {}, nil
+	case bytes.HasPrefix(peek, zstdMagic):
+		zstdReader, err := zstd.NewReader(reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		return bufio.NewReader(zstdReader), zstdReader.Close, nil
+	default:
+		return reader, func() {}, nil
+	}
+}
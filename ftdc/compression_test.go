@@ -0,0 +1,59 @@
+package ftdc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestParseCompressed(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+
+	ftdcData := bytes.NewBuffer(nil)
+	ftdc := NewWithWriter(ftdcData, logger.Sublogger("ftdc"))
+	foo1 := &foo{x: 1, y: 2}
+	ftdc.Add("foo1", foo1)
+	for range 3 {
+		test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+		foo1.x++
+	}
+
+	expected, err := ParseWithLogger(bytes.NewReader(ftdcData.Bytes()), logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, expected, test.ShouldHaveLength, 3)
+
+	compress := func(newWriter func(io.Writer) io.WriteCloser) []byte {
+		compressed := bytes.NewBuffer(nil)
+		writer := newWriter(compressed)
+		_, err := writer.Write(ftdcData.Bytes())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, writer.Close(), test.ShouldBeNil)
+		return compressed.Bytes()
+	}
+
+	gzipped := compress(func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })
+	datums, err := ParseWithLogger(bytes.NewReader(gzipped), logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, datums, test.ShouldResemble, expected)
+
+	zstded := compress(func(w io.Writer) io.WriteCloser {
+		writer, err := zstd.NewWriter(w)
+		test.That(t, err, test.ShouldBeNil)
+		return writer
+	})
+	datums, err = ParseWithLogger(bytes.NewReader(zstded), logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, datums, test.ShouldResemble, expected)
+
+	// A compressed file that was cut short, such as one copied while it was still being written,
+	// returns the datums before the cut along with an error.
+	datums, err = ParseWithLogger(bytes.NewReader(zstded[:len(zstded)/2]), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, datums, test.ShouldResemble, expected[:len(datums)])
+}
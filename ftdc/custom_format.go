@@ -362,7 +362,8 @@ func (flatDatum *FlatDatum) asDatum() datum {
 
 // Parse reads the entire contents from `rawReader` and returns a list of `Datum`. If an error
 // occurs, the []Datum parsed up until the place of the error will be returned, in addition to a
-// non-nil error.
+// non-nil error. The contents may be gzip or zstd compressed, in which case they are decompressed
+// on the fly. Use a `Reader` to decode large files without holding all of their datums in memory.
 func Parse(rawReader io.Reader) ([]FlatDatum, error) {
 	logger := logging.NewLogger("")
 	logger.SetLevel(logging.ERROR)
@@ -372,93 +373,291 @@ func Parse(rawReader io.Reader) ([]FlatDatum, error) {
 
 // ParseWithLogger parses with a logger for output.
 func ParseWithLogger(rawReader io.Reader, logger logging.Logger) ([]FlatDatum, error) {
+	ret, _, err := ParseWithMetadata(rawReader, logger)
+	return ret, err
+}
+
+// ParseWithMetadata parses like `ParseWithLogger`, additionally returning the metadata describing
+// the machine the FTDC data was captured on. The metadata is nil for files written before metadata
+// was recorded. If the input has multiple metadata documents, such as for concatenated files, the
+// last one is returned.
+func ParseWithMetadata(rawReader io.Reader, logger logging.Logger) ([]FlatDatum, *Metadata, error) {
+	reader, err := NewReader(rawReader, logger)
+	if err != nil {
+		return make([]FlatDatum, 0), nil, err
+	}
+	defer reader.Close()
+
 	ret := make([]FlatDatum, 0)
+	err = reader.forEach(func(datum FlatDatum) {
+		ret = append(ret, datum)
+	})
+	return ret, reader.Metadata(), err
+}
 
-	// prevValues are the previous values used for producing the diff bits. This is overwritten when
-	// a new metrics reading is made. and nilled out when the schema changes.
-	var prevValues []float32
+// Reader decodes the datums of FTDC data one at a time. Only the current schema and the values the
+// next datum is diffed against are held in memory, such that files of any size can be read.
+//
+// A `Reader` can follow a file that viam-server is still writing to. `Next` returns `io.EOF` when
+// there is no complete datum left to read, and may be called again once more data has been written
+// to pick up from where it left off.
+type Reader struct {
+	parser            *parser
+	closeDecompressor func()
+}
 
+// NewReader returns a `Reader` of the FTDC data in `rawReader`. The data may be gzip or zstd
+// compressed, in which case it is decompressed on the fly. Compressed data cannot be followed.
+func NewReader(rawReader io.Reader, logger logging.Logger) (*Reader, error) {
 	// bufio's Reader allows for peeking and potentially better control over how much data to read
 	// from disk at a time.
-	reader := bufio.NewReader(rawReader)
-	var schema *schema
+	reader, closeDecompressor, err := decompressReader(bufio.NewReader(rawReader))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{parser: newParser(reader, logger), closeDecompressor: closeDecompressor}, nil
+}
+
+// SetTimeRange limits the datums returned by `Next` to those captured within `[start, end]`. A zero
+// `start` or `end` leaves that side of the range open. The values of datums outside of the range are
+// still read, as the datums that follow are diffed against them, but their readings are not
+// assembled.
+func (reader *Reader) SetTimeRange(start, end time.Time) {
+	reader.parser.minTime, reader.parser.maxTime = 0, math.MaxInt64
+	if !start.IsZero() {
+		reader.parser.minTime = start.UnixNano()
+	}
+	if !end.IsZero() {
+		reader.parser.maxTime = end.UnixNano()
+	}
+}
+
+// Next returns the next datum. It returns `io.EOF` when the end of the data is reached, including
+// when the data ends part way through a datum that is still being written.
+func (reader *Reader) Next() (FlatDatum, error) {
+	return reader.parser.next()
+}
+
+// Metadata returns the metadata describing the machine the FTDC data was captured on, as of the
+// datums read so far. It is nil when no metadata has been read.
+func (reader *Reader) Metadata() *Metadata {
+	return reader.parser.metadata
+}
+
+// Close releases the resources of the `Reader`. It does not close the underlying reader.
+func (reader *Reader) Close() {
+	reader.closeDecompressor()
+}
+
+// forEach calls `fn` with each of the remaining datums. Unlike `Next`, data that ends part way
+// through a datum is an error.
+func (reader *Reader) forEach(fn func(FlatDatum)) error {
 	for {
-		peek, err := reader.Peek(1)
+		datum, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return reader.parser.truncatedChunkError()
+		}
 		if err != nil {
-			logger.Debugw("Beginning peek error", "error", err)
+			return err
+		}
+		fn(datum)
+	}
+}
+
+// parser holds the state of parsing an FTDC file that carries over from one document to the next.
+type parser struct {
+	// reader is positioned on the next top-level document. chunk is positioned on the next document
+	// of the checksummed chunk being read, if any.
+	reader *bufio.Reader
+	chunk  *bufio.Reader
+	// chunkHeader and chunkBody hold a chunk read part of the way, until the rest of it is written.
+	// See `readChunk`.
+	chunkHeader []byte
+	chunkBody   []byte
+
+	metadata  *Metadata
+	numDatums int
+	// minTime and maxTime bound the times of the datums returned, in nanoseconds since the epoch.
+	minTime int64
+	maxTime int64
+
+	schema *schema
+	// prevValues are the previous values used for producing the diff bits. This is overwritten when
+	// a new metrics reading is made. and nilled out when the schema changes.
+	prevValues []float32
+
+	// checksums tracks the checksummed chunks read. See `checksum.go`.
+	checksums checksumState
+
+	logger logging.Logger
+}
+
+func newParser(reader *bufio.Reader, logger logging.Logger) *parser {
+	return &parser{reader: reader, maxTime: math.MaxInt64, logger: logger}
+}
+
+// next parses FTDC documents until it has read a datum within the time range, returning `io.EOF`
+// if the data ends first.
+func (parser *parser) next() (FlatDatum, error) {
+	for {
+		if parser.chunk != nil {
+			datum, ok, err := parser.readDocument(&parser.chunk, false)
 			if errors.Is(err, io.EOF) {
-				break
+				// The end of the chunk, continue with the next top-level document.
+				parser.chunk = nil
+				continue
 			}
-
-			return ret, err
+			if err != nil {
+				return FlatDatum{}, err
+			}
+			if ok {
+				return datum, nil
+			}
+			continue
 		}
 
-		// If the first bit of the first byte is `1`, the next block of data is a schema
-		// document. The rest of the bits (for diffing) are irrelevant and will be zero. Thus the
-		// check against `0x1`.
-		if peek[0] == 0x1 {
-			//nolint
-			//
-			// Justifying the nolint: if `Peek(1)` does not return an error, `ReadByte` must not be
-			// able to return an error.
-			//
-			// Consume the 0x1 byte.
-			_, _ = reader.ReadByte()
-
-			// Read json and position the cursor at the next FTDC document. The JSON reader may
-			// "over-read", so `readSchema` assembles a new reader positioned at the right spot. The
-			// schema bytes themselves are expected to be a list of strings, e.g: `["metricName1",
-			// "metricName2"]`.
-			schema, reader = readSchema(reader)
-			logger.Debugw("Schema bit", "parsedSchema", schema)
-
-			// We cannot diff against values from the old schema.
-			prevValues = nil
-			continue
-		} else if schema == nil {
-			return nil, errors.New("first byte of FTDC data must be the magic 0x1 representing a new schema")
+		if parser.chunkHeader == nil {
+			peek, err := parser.reader.Peek(1)
+			if err != nil {
+				parser.logger.Debugw("Beginning peek error", "error", err)
+				return FlatDatum{}, err
+			}
+			if peek[0] != chunkIdentifier {
+				if err := parser.checksums.unchecksummedDocument(); err != nil {
+					return FlatDatum{}, err
+				}
+				datum, ok, err := parser.readDocument(&parser.reader, true)
+				if err != nil {
+					return FlatDatum{}, err
+				}
+				if ok {
+					return datum, nil
+				}
+				continue
+			}
 		}
 
-		// This FTDC document is a metric document. Read the "diff bits" that describe which metrics
-		// have changed since the prior metric document. Note, the reader is positioned on the
-		// "packed byte" where the first bit is not a diff bit. `readDiffBits` must account for
-		// that.
-		diffedFieldsIndexes := readDiffBits(reader, schema)
-		logger.Debugw("Diff bits",
-			"changedFieldIndexes", diffedFieldsIndexes,
-			"changedFieldNames", schema.FieldNamesForIndexes(diffedFieldsIndexes))
-
-		// The next eight bytes after the diff bits is the time in nanoseconds since the 1970 epoch.
-		var dataTime int64
-		if err = binary.Read(reader, binary.BigEndian, &dataTime); err != nil {
-			logger.Debugw("Error reading time", "error", err)
-			return ret, err
+		if err := parser.readChunk(); err != nil {
+			return FlatDatum{}, err
 		}
-		logger.Debugw("Read time", "time", dataTime, "seconds", dataTime/1e9)
+	}
+}
+
+// readDocument parses the FTDC document `reader` is positioned on, returning the datum if it is a
+// metric document within the time range. It returns `io.EOF` only if `reader` has no document left.
+// `topLevel` is false when parsing the contents of a checksummed chunk.
+func (parser *parser) readDocument(reader **bufio.Reader, topLevel bool) (FlatDatum, bool, error) {
+	logger := parser.logger
+	peek, err := (*reader).Peek(1)
+	if err != nil {
+		return FlatDatum{}, false, err
+	}
+
+	if peek[0] == metadataIdentifier {
+		// A metadata document describes the machine the data was captured on. Consume the
+		// identifier byte followed by the JSON object.
+		//
+		//nolint
+		_, _ = (*reader).ReadByte()
 
-		// Read the payload. There will be one float32 value for each diff bit set to `1`, i.e:
-		// `len(diffedFields)`.
-		data, err := readData(reader, schema, diffedFieldsIndexes, prevValues)
+		parsed, nextReader, err := readMetadata(*reader)
 		if err != nil {
-			logger.Debugw("Error reading data", "error", err)
-			return ret, err
+			return FlatDatum{}, false, err
 		}
-		logger.Debugw("Read data", "data", data)
+		parser.metadata, *reader = parsed, nextReader
+		logger.Debugw("Metadata", "metadata", parser.metadata)
+		return FlatDatum{}, false, nil
+	}
 
-		// The old `prevValues` is no longer needed. Set the `prevValues` to the new hydrated
-		// `data`.
-		prevValues = data
+	// If the first bit of the first byte is `1`, the next block of data is a schema
+	// document. The rest of the bits (for diffing) are irrelevant and will be zero. Thus the
+	// check against `0x1`.
+	if peek[0] == 0x1 {
+		//nolint
+		//
+		// Justifying the nolint: if `Peek(1)` does not return an error, `ReadByte` must not be
+		// able to return an error.
+		//
+		// Consume the 0x1 byte.
+		_, _ = (*reader).ReadByte()
+
+		// Read json and position the cursor at the next FTDC document. The JSON reader may
+		// "over-read", so `readSchema` assembles a new reader positioned at the right spot. The
+		// schema bytes themselves are expected to be a list of strings, e.g: `["metricName1",
+		// "metricName2"]`.
+		parser.schema, *reader = readSchema(*reader)
+		logger.Debugw("Schema bit", "parsedSchema", parser.schema)
+
+		// We cannot diff against values from the old schema.
+		parser.prevValues = nil
+		parser.checksums.skipUntilSchema = false
+		return FlatDatum{}, false, nil
+	}
 
-		// Construct a `Datum` that hydrates/merged the full set of float32 metrics with the metric
-		// names as written in the most recent schema document.
-		ret = append(ret, FlatDatum{
-			Time:     dataTime,
-			Readings: schema.Zip(data),
-		})
-		logger.Debugw("Hydrated data", "data", ret[len(ret)-1].Readings)
+	if !topLevel && parser.checksums.skipUntilSchema {
+		// This metric document follows a corrupt chunk. Its values are diffed against values
+		// we do not have, possibly in a schema we do not have. Skip the rest of the chunk.
+		parser.checksums.numSkippedChunks++
+		parser.chunk = nil
+		return FlatDatum{}, false, nil
+	}
+	if parser.schema == nil {
+		return FlatDatum{}, false, errors.New("first byte of FTDC data must be the magic 0x1 representing a new schema")
 	}
 
-	return ret, nil
+	// This FTDC document is a metric document. Read the "diff bits" that describe which metrics
+	// have changed since the prior metric document. Note, the reader is positioned on the
+	// "packed byte" where the first bit is not a diff bit. `readDiffBits` must account for
+	// that.
+	diffedFieldsIndexes := readDiffBits(*reader, parser.schema)
+	logger.Debugw("Diff bits",
+		"changedFieldIndexes", diffedFieldsIndexes,
+		"changedFieldNames", parser.schema.FieldNamesForIndexes(diffedFieldsIndexes))
+
+	// The next eight bytes after the diff bits is the time in nanoseconds since the 1970 epoch.
+	var dataTime int64
+	if err = binary.Read(*reader, binary.BigEndian, &dataTime); err != nil {
+		logger.Debugw("Error reading time", "error", err)
+		return FlatDatum{}, false, unexpectedEOF(err)
+	}
+	logger.Debugw("Read time", "time", dataTime, "seconds", dataTime/1e9)
+
+	// Read the payload. There will be one float32 value for each diff bit set to `1`, i.e:
+	// `len(diffedFields)`.
+	data, err := readData(*reader, parser.schema, diffedFieldsIndexes, parser.prevValues)
+	if err != nil {
+		logger.Debugw("Error reading data", "error", err)
+		return FlatDatum{}, false, unexpectedEOF(err)
+	}
+	logger.Debugw("Read data", "data", data)
+
+	// The old `prevValues` is no longer needed. Set the `prevValues` to the new hydrated
+	// `data`.
+	parser.prevValues = data
+	parser.numDatums++
+
+	if dataTime < parser.minTime || dataTime > parser.maxTime {
+		return FlatDatum{}, false, nil
+	}
+
+	// Construct a `Datum` that hydrates/merged the full set of float32 metrics with the metric
+	// names as written in the most recent schema document.
+	ret := FlatDatum{
+		Time:     dataTime,
+		Readings: parser.schema.Zip(data),
+	}
+	logger.Debugw("Hydrated data", "data", ret.Readings)
+	return ret, true, nil
+}
+
+// unexpectedEOF turns an `io.EOF` part way through a document into an `io.ErrUnexpectedEOF`, such
+// that it is not mistaken for the end of the data.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
 }
 
 func flatDatumsToDatums(inp []FlatDatum) []datum {
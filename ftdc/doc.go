@@ -24,7 +24,19 @@
 // Using a pseudo EBNF notation, an FTDC file is:
 // FTDC = ftdc_doc*
 //
-// ftdc_doc = schema | metric
+// ftdc_doc = chunk | metadata | schema | metric
+//
+// chunk =
+//
+//	chunk_identifier : 0x05 (a full byte of value 5)
+//	length : uint32 <The number of bytes of `docs`.>
+//	checksum : uint32 <The CRC-32C (Castagnoli) of `docs`.>
+//	docs : (metadata | schema | metric)*
+//
+// metadata =
+//
+//	metadata_identifier : 0x03 (a full byte of value 3)
+//	metadata : <object serialized as JSON, including a trailing \n(0xa)>
 //
 // schema =
 //
@@ -100,4 +112,25 @@
 //
 // A parser can read a single byte and look at the least significant bit to determine which path to
 // take.
+//
+// Each file written by FTDC additionally starts with a metadata document describing the machine the
+// data was captured on. E.g:
+//
+// 0000 0011 {"part_id":"<id>","robot_name":"rover","version":"v0.50.0","hostname":"pi","os":"linux","arch":"arm64"}\n
+// 7       0
+//
+// Like the schema identifier, the metadata identifier has its least significant bit set. Parsers
+// distinguish the two by the full byte value.
+//
+// Lastly, to detect corruption, such as bit rot on an SD card, FTDC wraps the documents written for
+// each datum in a checksummed chunk. E.g: the chunk for the first datum of a file holds the
+// metadata, schema and metric documents:
+//
+// 0000 0101 <32bit length> <32bit checksum> <metadata document> <schema document> <metric document>
+// 7       0
+//
+// A parser that finds a chunk whose checksum does not match skips it. Because metric documents are
+// diffed against the prior metric document, metric documents are also skipped until the next schema
+// document, from which point data is recovered again. Files written before checksums were added do
+// not have chunks.
 package ftdc
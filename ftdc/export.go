@@ -0,0 +1,217 @@
+package ftdc
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The labels `LabelMetric` splits out of FTDC metric names.
+const (
+	// MetricLabelAPI is the API of the resource a metric is of. E.g: `rdk:component:arm`.
+	MetricLabelAPI = "api"
+	// MetricLabelResource is the name of the resource a metric is of. E.g: `arm1`.
+	MetricLabelResource = "resource"
+	// MetricLabelProcess is the process a metric is of. Either `viam-server` or a module name.
+	MetricLabelProcess = "process"
+)
+
+// metricLabelNames are the labels of a `LabeledMetric`, in the order they are exported as columns.
+var metricLabelNames = []string{MetricLabelAPI, MetricLabelResource, MetricLabelProcess}
+
+// resourceMetricRegex matches the metrics of a resource, whose statser is named after the
+// resource. E.g: `rdk:component:arm/arm1.MoveToPosition.timeSpent`.
+var resourceMetricRegex = regexp.MustCompile(`^([\w-]+:[\w-]+:[\w-]+)/([^.]+)\.(.+)$`)
+
+// LabeledMetric is an FTDC metric name split into what is measured and what it is measured of, such
+// that the same metric of different resources or processes can be aggregated together.
+type LabeledMetric struct {
+	Name   string
+	Labels map[string]string
+}
+
+// LabelMetric splits the FTDC metric name into a `LabeledMetric`:
+//
+//	rdk:component:arm/arm1.MoveToPosition.timeSpent -> resource.MoveToPosition.timeSpent{api="rdk:component:arm",resource="arm1"}
+//	proc.viam-server.UserCPUSecs                     -> proc.UserCPUSecs{process="viam-server"}
+//	proc.modules.my-module.UserCPUSecs               -> proc.UserCPUSecs{process="my-module"}
+//
+// The custom metrics of a module are labeled with the module as the process, and are otherwise
+// labeled as above:
+//
+//	modules.my-module.rdk:component:motor/motor1.MoveCount -> resource.MoveCount{api="rdk:component:motor",resource="motor1",process="my-module"}
+//	modules.my-module.queue.Depth                          -> module.queue.Depth{process="my-module"}
+//
+// Other metrics, such as those of `net`, are left as is without labels.
+func LabelMetric(metricName string) LabeledMetric {
+	if rest, ok := strings.CutPrefix(metricName, "modules."); ok {
+		if module, field, ok := strings.Cut(rest, "."); ok {
+			labeled := LabelMetric(field)
+			if labeled.Labels == nil {
+				labeled = LabeledMetric{Name: "module." + field, Labels: map[string]string{}}
+			}
+			labeled.Labels[MetricLabelProcess] = module
+			return labeled
+		}
+	}
+	if match := resourceMetricRegex.FindStringSubmatch(metricName); match != nil {
+		return LabeledMetric{
+			Name:   "resource." + match[3],
+			Labels: map[string]string{MetricLabelAPI: match[1], MetricLabelResource: match[2]},
+		}
+	}
+	if field, ok := strings.CutPrefix(metricName, "proc.viam-server."); ok {
+		return LabeledMetric{Name: "proc." + field, Labels: map[string]string{MetricLabelProcess: "viam-server"}}
+	}
+	if rest, ok := strings.CutPrefix(metricName, "proc.modules."); ok {
+		// Module names may not contain periods.
+		if module, field, ok := strings.Cut(rest, "."); ok {
+			return LabeledMetric{Name: "proc." + field, Labels: map[string]string{MetricLabelProcess: module}}
+		}
+	}
+	return LabeledMetric{Name: metricName}
+}
+
+// CSVExporter writes the datums it exports as CSV, with a row per reading. The columns are:
+//
+//	time,<labels...>,ftdc_metric,metric,api,resource,process,value
+//
+// Where `time` is RFC 3339, the labels are those the exporter is created with, and `metric` and the
+// columns after it are the `LabelMetric` of the FTDC metric. A row per reading, rather than a
+// column per metric, keeps the columns the same when metrics come and go mid-file.
+type CSVExporter struct {
+	writer      *csv.Writer
+	labelNames  []string
+	labels      map[string]string
+	writeHeader bool
+	err         error
+}
+
+// NewCSVExporter creates a `CSVExporter` writing to `w`. Closing the exporter does not close `w`.
+func NewCSVExporter(w io.Writer, labels map[string]string) *CSVExporter {
+	return newCSVExporter(w, labels, true)
+}
+
+func newCSVExporter(w io.Writer, labels map[string]string, writeHeader bool) *CSVExporter {
+	return &CSVExporter{
+		writer:      csv.NewWriter(w),
+		labelNames:  slices.Sorted(maps.Keys(labels)),
+		labels:      labels,
+		writeHeader: writeHeader,
+	}
+}
+
+// Export writes a row for each reading of the datum. Errors are returned by `Close`, after which
+// nothing more is written.
+func (ce *CSVExporter) Export(datum FlatDatum) {
+	if ce.err != nil {
+		return
+	}
+	if ce.writeHeader {
+		header := slices.Concat([]string{"time"}, ce.labelNames, []string{prometheusMetricLabel, "metric"},
+			metricLabelNames, []string{"value"})
+		if ce.err = ce.writer.Write(header); ce.err != nil {
+			return
+		}
+		ce.writeHeader = false
+	}
+
+	timestamp := datum.ConvertedTime().UTC().Format(time.RFC3339Nano)
+	for _, reading := range datum.Readings {
+		labeled := LabelMetric(reading.MetricName)
+		row := make([]string, 0, len(ce.labelNames)+len(metricLabelNames)+4)
+		row = append(row, timestamp)
+		for _, name := range ce.labelNames {
+			row = append(row, ce.labels[name])
+		}
+		row = append(row, reading.MetricName, labeled.Name)
+		for _, name := range metricLabelNames {
+			row = append(row, labeled.Labels[name])
+		}
+		row = append(row, strconv.FormatFloat(float64(reading.Value), 'g', -1, 32))
+		if ce.err = ce.writer.Write(row); ce.err != nil {
+			return
+		}
+	}
+	ce.writer.Flush()
+	ce.err = ce.writer.Error()
+}
+
+// Close flushes the rows written and returns the first error writing them, if any.
+func (ce *CSVExporter) Close() error {
+	if ce.err != nil {
+		return ce.err
+	}
+	ce.writer.Flush()
+	return ce.writer.Error()
+}
+
+// jsonReading is a reading as it is written by a `JSONExporter`.
+type jsonReading struct {
+	Time       string            `json:"time"`
+	FTDCMetric string            `json:"ftdc_metric"`
+	Metric     string            `json:"metric"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Value      float32           `json:"value"`
+}
+
+// JSONExporter writes the datums it exports as newline-delimited JSON, with a line per reading.
+// E.g:
+//
+//	{"time":"2025-01-01T00:00:00Z","ftdc_metric":"proc.viam-server.UserCPUSecs","metric":"proc.UserCPUSecs","labels":{"process":"viam-server"},"value":12.5}
+//
+// Where `metric` and `labels` are the `LabelMetric` of the FTDC metric, plus the labels the
+// exporter is created with. Readings that are NaN or infinite are skipped.
+type JSONExporter struct {
+	encoder *json.Encoder
+	labels  map[string]string
+	err     error
+}
+
+// NewJSONExporter creates a `JSONExporter` writing to `w`. Closing the exporter does not close `w`.
+func NewJSONExporter(w io.Writer, labels map[string]string) *JSONExporter {
+	return &JSONExporter{encoder: json.NewEncoder(w), labels: labels}
+}
+
+// Export writes a line for each reading of the datum. Errors are returned by `Close`, after which
+// nothing more is written.
+func (je *JSONExporter) Export(datum FlatDatum) {
+	if je.err != nil {
+		return
+	}
+	timestamp := datum.ConvertedTime().UTC().Format(time.RFC3339Nano)
+	for _, reading := range datum.Readings {
+		value := float64(reading.Value)
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			// JSON has no representation of these.
+			continue
+		}
+		labeled := LabelMetric(reading.MetricName)
+		labels := labeled.Labels
+		if len(je.labels) > 0 {
+			labels = maps.Clone(je.labels)
+			maps.Copy(labels, labeled.Labels)
+		}
+		if je.err = je.encoder.Encode(jsonReading{
+			Time:       timestamp,
+			FTDCMetric: reading.MetricName,
+			Metric:     labeled.Name,
+			Labels:     labels,
+			Value:      reading.Value,
+		}); je.err != nil {
+			return
+		}
+	}
+}
+
+// Close returns the first error writing readings, if any.
+func (je *JSONExporter) Close() error {
+	return je.err
+}
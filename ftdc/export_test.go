@@ -0,0 +1,185 @@
+package ftdc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestLabelMetric(t *testing.T) {
+	for _, tc := range []struct {
+		metricName string
+		expected   LabeledMetric
+	}{
+		{
+			"rdk:component:arm/arm1.MoveToPosition.timeSpent",
+			LabeledMetric{
+				Name:   "resource.MoveToPosition.timeSpent",
+				Labels: map[string]string{"api": "rdk:component:arm", "resource": "arm1"},
+			},
+		},
+		{
+			"acme:service:my-nav/remote1:nav.State",
+			LabeledMetric{
+				Name:   "resource.State",
+				Labels: map[string]string{"api": "acme:service:my-nav", "resource": "remote1:nav"},
+			},
+		},
+		{
+			"proc.viam-server.UserCPUSecs",
+			LabeledMetric{Name: "proc.UserCPUSecs", Labels: map[string]string{"process": "viam-server"}},
+		},
+		{
+			"proc.modules.my-module.ElapsedTimeSecs",
+			LabeledMetric{Name: "proc.ElapsedTimeSecs", Labels: map[string]string{"process": "my-module"}},
+		},
+		{
+			"modules.my-module.rdk:component:motor/motor1.MoveCount",
+			LabeledMetric{
+				Name:   "resource.MoveCount",
+				Labels: map[string]string{"api": "rdk:component:motor", "resource": "motor1", "process": "my-module"},
+			},
+		},
+		{
+			"modules.my-module.queue.Depth",
+			LabeledMetric{Name: "module.queue.Depth", Labels: map[string]string{"process": "my-module"}},
+		},
+		{"net.TxBytes", LabeledMetric{Name: "net.TxBytes"}},
+		{"proc.modules", LabeledMetric{Name: "proc.modules"}},
+	} {
+		test.That(t, LabelMetric(tc.metricName), test.ShouldResemble, tc.expected)
+	}
+}
+
+// exportTestDatums has a second datum that adds a metric, like a schema change mid-file.
+func exportTestDatums() []FlatDatum {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []FlatDatum{
+		{Time: start.UnixNano(), Readings: []Reading{{"proc.viam-server.UserCPUSecs", 1.5}}},
+		{Time: start.Add(time.Second).UnixNano(), Readings: []Reading{
+			{"proc.viam-server.UserCPUSecs", 2},
+			{"rdk:component:arm/arm1.MoveCount", 3},
+		}},
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestCSVExporter(t *testing.T) {
+	var out bytes.Buffer
+	exporter := NewCSVExporter(&out, map[string]string{"robot": "robot1"})
+	for _, datum := range exportTestDatums() {
+		exporter.Export(datum)
+	}
+	test.That(t, exporter.Close(), test.ShouldBeNil)
+	test.That(t, strings.Split(out.String(), "\n"), test.ShouldResemble, []string{
+		"time,robot,ftdc_metric,metric,api,resource,process,value",
+		"2025-01-01T00:00:00Z,robot1,proc.viam-server.UserCPUSecs,proc.UserCPUSecs,,,viam-server,1.5",
+		"2025-01-01T00:00:01Z,robot1,proc.viam-server.UserCPUSecs,proc.UserCPUSecs,,,viam-server,2",
+		"2025-01-01T00:00:01Z,robot1,rdk:component:arm/arm1.MoveCount,resource.MoveCount,rdk:component:arm,arm1,,3",
+		"",
+	})
+
+	exporter = NewCSVExporter(failingWriter{}, nil)
+	exporter.Export(exportTestDatums()[0])
+	test.That(t, exporter.Close(), test.ShouldBeError, "disk full")
+}
+
+func TestJSONExporter(t *testing.T) {
+	var out bytes.Buffer
+	exporter := NewJSONExporter(&out, map[string]string{"robot": "robot1"})
+	datums := exportTestDatums()
+	datums[0].Readings = append(datums[0].Readings, Reading{"net.TxBytes", float32(math.NaN())})
+	for _, datum := range datums {
+		exporter.Export(datum)
+	}
+	test.That(t, exporter.Close(), test.ShouldBeNil)
+
+	var written []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var reading map[string]interface{}
+		test.That(t, json.Unmarshal([]byte(line), &reading), test.ShouldBeNil)
+		written = append(written, reading)
+	}
+	// The NaN reading is skipped.
+	test.That(t, written, test.ShouldResemble, []map[string]interface{}{
+		{
+			"time":        "2025-01-01T00:00:00Z",
+			"ftdc_metric": "proc.viam-server.UserCPUSecs",
+			"metric":      "proc.UserCPUSecs",
+			"labels":      map[string]interface{}{"robot": "robot1", "process": "viam-server"},
+			"value":       1.5,
+		},
+		{
+			"time":        "2025-01-01T00:00:01Z",
+			"ftdc_metric": "proc.viam-server.UserCPUSecs",
+			"metric":      "proc.UserCPUSecs",
+			"labels":      map[string]interface{}{"robot": "robot1", "process": "viam-server"},
+			"value":       2.0,
+		},
+		{
+			"time":        "2025-01-01T00:00:01Z",
+			"ftdc_metric": "rdk:component:arm/arm1.MoveCount",
+			"metric":      "resource.MoveCount",
+			"labels":      map[string]interface{}{"robot": "robot1", "api": "rdk:component:arm", "resource": "arm1"},
+			"value":       3.0,
+		},
+	})
+}
+
+func TestFileRenderers(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	datums := exportTestDatums()
+
+	csvFactory, ok := LookupRenderer("csv")
+	test.That(t, ok, test.ShouldBeTrue)
+	_, err := csvFactory("", logger)
+	test.That(t, err, test.ShouldBeError, "a file to write to is required")
+
+	csvPath := filepath.Join(t.TempDir(), "out.csv")
+	renderer, err := csvFactory(csvPath, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, renderer.Render(context.Background(), "robot1", datums[:1]), test.ShouldBeNil)
+	// Rendering a file in multiple batches, or multiple files, writes the header once.
+	test.That(t, renderer.Render(context.Background(), "robot1", datums[1:]), test.ShouldBeNil)
+	test.That(t, renderer.Render(context.Background(), "robot2", datums[:1]), test.ShouldBeNil)
+	test.That(t, renderer.Close(), test.ShouldBeNil)
+
+	written, err := os.ReadFile(csvPath)
+	test.That(t, err, test.ShouldBeNil)
+	lines := strings.Split(strings.TrimSpace(string(written)), "\n")
+	test.That(t, lines, test.ShouldHaveLength, 5)
+	test.That(t, lines[0], test.ShouldEqual, "time,robot,ftdc_metric,metric,api,resource,process,value")
+	test.That(t, lines[4], test.ShouldStartWith, "2025-01-01T00:00:00Z,robot2,")
+
+	jsonFactory, ok := LookupRenderer("json")
+	test.That(t, ok, test.ShouldBeTrue)
+	jsonPath := filepath.Join(t.TempDir(), "out.jsonl")
+	renderer, err = jsonFactory(jsonPath, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, renderer.Render(context.Background(), "", datums), test.ShouldBeNil)
+	test.That(t, renderer.Close(), test.ShouldBeNil)
+
+	written, err = os.ReadFile(jsonPath)
+	test.That(t, err, test.ShouldBeNil)
+	lines = strings.Split(strings.TrimSpace(string(written)), "\n")
+	test.That(t, lines, test.ShouldHaveLength, 3)
+	test.That(t, lines[0], test.ShouldEqual,
+		`{"time":"2025-01-01T00:00:00Z","ftdc_metric":"proc.viam-server.UserCPUSecs",`+
+			`"metric":"proc.UserCPUSecs","labels":{"process":"viam-server"},"value":1.5}`)
+}
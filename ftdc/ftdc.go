@@ -1,6 +1,7 @@
 package ftdc
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -18,9 +19,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"go.viam.com/utils"
 
 	"go.viam.com/rdk/logging"
+	rutils "go.viam.com/rdk/utils"
 )
 
 // datum combines the `Stats` call to all registered `Statser`s at some "time". The hierarchy of
@@ -88,10 +91,20 @@ type namedStatser struct {
 // FTDC is a tool for storing observability data on disk in a compact binary format for production
 // debugging.
 type FTDC struct {
-	// mu protects the `statser` member. The `statser` member is modified during user calls to `Add`
-	// and `Remove`. Additionally, there's a concurrent background reader of the `statser` member.
+	// mu protects the `statser`, `exporters` and `exportersClosed` members. The `statser` member is
+	// modified during user calls to `Add` and `Remove`. Additionally, there's a concurrent background
+	// reader of the `statser` member.
 	mu       sync.Mutex
 	statsers []namedStatser
+	// exporters are sent every datum that is written.
+	exporters []Exporter
+	// exportersClosed is set when FTDC is stopped and its exporters have been closed.
+	exportersClosed bool
+
+	// metadata is written at the start of each file. `writeMetadataNext` is set when a new file is
+	// started and the metadata has yet to be written to it.
+	metadata          Metadata
+	writeMetadataNext bool
 
 	// The schema used describe how new Datums are serialized.
 	currSchema *schema
@@ -100,11 +113,17 @@ type FTDC struct {
 	// simplicity, all metrics are massaged into a 32-bit float. See `custom_format.go` for a more
 	// detailed description.
 	prevFlatData []float32
+	// chunk is where the documents of a datum are gathered before being written out together with
+	// their checksum. It is reused between datums.
+	chunk bytes.Buffer
 
-	readStatsWorker  *utils.StoppableWorkers
+	readStatsWorker  *rutils.PeriodicWorker
 	datumCh          chan datum
 	outputWorkerDone chan struct{}
 	stopOnce         sync.Once
+	// throttler, if set, slows down how often `statsReader` collects datums while the system is
+	// under pressure. See `SetPressureReader`.
+	throttler *throttler
 
 	// Fields used to manage where serialized FTDC bytes are written.
 	outputWriter io.Writer
@@ -119,6 +138,14 @@ type FTDC struct {
 	// ftdcDir controls where FTDC data files will be written.
 	ftdcDir string
 
+	// latestMu protects `latest`, the most recently written datum, which is read by `Latest`
+	// concurrently with the `statsWriter`.
+	latestMu sync.Mutex
+	latest   *FlatDatum
+
+	// clk times when datums are read and old files are checked for deletion.
+	clk clock.Clock
+
 	logger logging.Logger
 }
 
@@ -148,12 +175,21 @@ func DefaultDirectory(viamHome, partID string) string {
 func newFTDC(logger logging.Logger) *FTDC {
 	return &FTDC{
 		// Allow for some wiggle before blocking producers.
-		datumCh:          make(chan datum, 20),
-		outputWorkerDone: make(chan struct{}),
-		logger:           logger,
+		datumCh:           make(chan datum, 20),
+		outputWorkerDone:  make(chan struct{}),
+		metadata:          hostMetadata(),
+		writeMetadataNext: true,
+		clk:               clock.New(),
+		logger:            logger,
 	}
 }
 
+// SetClock makes FTDC read and timestamp datums, and name and delete files, by clk instead of the
+// wall clock. It must be called before `Start`.
+func (ftdc *FTDC) SetClock(clk clock.Clock) {
+	ftdc.clk = clk
+}
+
 // Add regsiters a new staters that will be recorded in future FTDC loop iterations.
 func (ftdc *FTDC) Add(name string, statser Statser) {
 	ftdc.mu.Lock()
@@ -191,6 +227,14 @@ func (ftdc *FTDC) Remove(name string) {
 	ftdc.logger.Warnw("Did not find statser to remove", "name", name)
 }
 
+// AddExporter registers an exporter that will be sent every datum written in future FTDC loop
+// iterations. The exporter is closed when FTDC is stopped.
+func (ftdc *FTDC) AddExporter(exporter Exporter) {
+	ftdc.mu.Lock()
+	defer ftdc.mu.Unlock()
+	ftdc.exporters = append(ftdc.exporters, exporter)
+}
+
 // Start spins off the background goroutine for collecting + writing FTDC data. It's normal for tests
 // to _not_ call `Start`. Tests can simulate the same functionality by calling `constructDatum` and `writeDatum`.
 func (ftdc *FTDC) Start() {
@@ -199,7 +243,12 @@ func (ftdc *FTDC) Start() {
 		ftdc.logger.Debug("FTDC not implemented on windows, not starting")
 		return
 	}
-	ftdc.readStatsWorker = utils.NewStoppableWorkerWithTicker(time.Second, ftdc.statsReader)
+	ftdc.readStatsWorker = rutils.NewPeriodicWorker(rutils.PeriodicWorkerConfig{
+		Name:     "ftdc.statsReader",
+		Interval: time.Second,
+		Clock:    ftdc.clk,
+		Logger:   ftdc.logger,
+	}, ftdc.statsReader)
 	utils.PanicCapturingGo(ftdc.statsWriter)
 
 	// The `fileDeleter` goroutine mostly aligns with the "stoppable worker with ticker"
@@ -212,6 +261,9 @@ func (ftdc *FTDC) Start() {
 }
 
 func (ftdc *FTDC) statsReader(ctx context.Context) {
+	if ftdc.throttler != nil && !ftdc.throttler.shouldCollect() {
+		return
+	}
 	datum := ftdc.constructDatum()
 
 	select {
@@ -277,12 +329,23 @@ func (ftdc *FTDC) StopAndJoin(ctx context.Context) {
 	case <-ftdc.outputWorkerDone:
 	case <-time.After(10 * time.Second):
 	}
+
+	ftdc.mu.Lock()
+	exporters := ftdc.exporters
+	ftdc.exporters = nil
+	ftdc.exportersClosed = true
+	ftdc.mu.Unlock()
+	for _, exporter := range exporters {
+		if err := exporter.Close(); err != nil {
+			ftdc.logger.Warnw("Error closing FTDC exporter", "err", err)
+		}
+	}
 }
 
 // constructDatum walks all of the registered `statser`s to construct a `datum`.
 func (ftdc *FTDC) constructDatum() datum {
 	datum := datum{
-		Time: time.Now().UnixNano(),
+		Time: ftdc.clk.Now().UnixNano(),
 		Data: map[string]any{},
 	}
 
@@ -418,11 +481,21 @@ func (ftdc *FTDC) writeDatum(datum datum) error {
 		return err
 	}
 
+	// The documents for this datum are gathered into a single chunk, which is written out with a
+	// checksum. See `checksum.go`.
+	ftdc.chunk.Reset()
+	if ftdc.writeMetadataNext {
+		if err = writeMetadata(ftdc.metadata, &ftdc.chunk); err != nil {
+			return err
+		}
+		ftdc.writeMetadataNext = false
+	}
+
 	// In the happy path where the schema hasn't changed, the `walk` function is guaranteed to
 	// return the same schema object.
 	if ftdc.currSchema != newSchema {
 		ftdc.currSchema = newSchema
-		if err = writeSchema(ftdc.currSchema, toWrite); err != nil {
+		if err = writeSchema(ftdc.currSchema, &ftdc.chunk); err != nil {
 			return err
 		}
 
@@ -431,14 +504,72 @@ func (ftdc *FTDC) writeDatum(datum datum) error {
 		ftdc.prevFlatData = nil
 	}
 
-	if err = writeDatum(datum.Time, ftdc.prevFlatData, flatData, toWrite); err != nil {
+	if err = writeDatum(datum.Time, ftdc.prevFlatData, flatData, &ftdc.chunk); err != nil {
+		return err
+	}
+	if err = writeChunk(ftdc.chunk.Bytes(), toWrite); err != nil {
 		return err
 	}
 	ftdc.prevFlatData = flatData
 
+	latest := &FlatDatum{Time: datum.Time, Readings: ftdc.currSchema.Zip(flatData)}
+	ftdc.latestMu.Lock()
+	ftdc.latest = latest
+	ftdc.latestMu.Unlock()
+
+	ftdc.mu.Lock()
+	exporters := ftdc.exporters
+	ftdc.mu.Unlock()
+	for _, exporter := range exporters {
+		exporter.Export(*latest)
+	}
+
 	return nil
 }
 
+// Latest returns the most recently written datum, with the same fully qualified metric names
+// and values as are written to disk. It returns false if no datum has been written yet.
+func (ftdc *FTDC) Latest() (FlatDatum, bool) {
+	ftdc.latestMu.Lock()
+	defer ftdc.latestMu.Unlock()
+	if ftdc.latest == nil {
+		return FlatDatum{}, false
+	}
+	return *ftdc.latest, true
+}
+
+// Files returns the paths of the FTDC data files in the FTDC directory, oldest first. These
+// include the file currently being written to.
+func (ftdc *FTDC) Files() ([]string, error) {
+	entries, err := os.ReadDir(ftdc.ftdcDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var files []fileTime
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".ftdc") {
+			continue
+		}
+		filename := filepath.Join(ftdc.ftdcDir, entry.Name())
+		if parsedTime, err := parseTimeFromFilename(filename); err == nil {
+			files = append(files, fileTime{filename, parsedTime})
+		}
+	}
+	slices.SortFunc(files, func(left, right fileTime) int {
+		return left.time.Compare(right.time)
+	})
+
+	filenames := make([]string, 0, len(files))
+	for _, file := range files {
+		filenames = append(filenames, file.name)
+	}
+	return filenames, nil
+}
+
 // getWriter returns an io.Writer xor error for writing schema/data information. `getWriter` is only
 // expected to be called by `writeDatum`.
 func (ftdc *FTDC) getWriter() (io.Writer, error) {
@@ -483,7 +614,7 @@ func (ftdc *FTDC) getWriter() (io.Writer, error) {
 			return nil, err
 		}
 
-		now := time.Now().UTC()
+		now := ftdc.clk.Now().UTC()
 		// lint wants 0o600 file permissions. We don't expect the unix user someone is ssh'ed in as
 		// to be on the same unix user as is running the viam-server process. Thus the file needs to
 		// be accessible by anyone.
@@ -523,6 +654,8 @@ func (ftdc *FTDC) getWriter() (io.Writer, error) {
 	// `currSchema` value. Such that the caller/`writeDatum` will behave as if this is a "schema
 	// change".
 	ftdc.currSchema = nil
+	// Similarly, each file starts with the metadata describing where it was captured.
+	ftdc.writeMetadataNext = true
 
 	return ftdc.outputWriter, nil
 }
@@ -534,7 +667,7 @@ func (ftdc *FTDC) fileDeleter() {
 		// `readStatsWorker`s context to track that.
 		case <-ftdc.readStatsWorker.Context().Done():
 			return
-		case <-time.After(time.Second):
+		case <-ftdc.clk.After(time.Second):
 		}
 
 		if err := ftdc.checkAndDeleteOldFiles(); err != nil {
@@ -2,6 +2,7 @@ package ftdc
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io/fs"
 	"os"
@@ -11,7 +12,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
 
 	"go.viam.com/rdk/logging"
 )
@@ -81,6 +84,46 @@ func TestCopeWithChangingSchema(t *testing.T) {
 
 // TestCopeWithSubtleSchemaChange is similar to TestCopeWithChangingSchema except that it keeps the
 // number of flattened fields the same. Only the field names changed.
+func TestLatest(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ftdc := NewWithWriter(bytes.NewBuffer(nil), logger.Sublogger("ftdc"))
+	_, ok := ftdc.Latest()
+	test.That(t, ok, test.ShouldBeFalse)
+
+	statser := foo{x: 1, y: 2}
+	ftdc.Add("foo", &statser)
+	datum := ftdc.constructDatum()
+	test.That(t, ftdc.writeDatum(datum), test.ShouldBeNil)
+	statser.x = 3
+	datum = ftdc.constructDatum()
+	test.That(t, ftdc.writeDatum(datum), test.ShouldBeNil)
+
+	latest, ok := ftdc.Latest()
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, latest.Time, test.ShouldEqual, datum.Time)
+	test.That(t, latest.Readings, test.ShouldResemble, []Reading{{"foo.X", 3}, {"foo.Y", 2}})
+}
+
+func TestClock(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ftdc := NewWithWriter(bytes.NewBuffer(nil), logger.Sublogger("ftdc"))
+	mockClock := clock.NewMock()
+	ftdc.SetClock(mockClock)
+	ftdc.Add("foo", &foo{x: 1, y: 2})
+	ftdc.Start()
+	defer ftdc.StopAndJoin(context.Background())
+
+	// Datums are read as the clock advances, with no need to wait for the wall clock.
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		mockClock.Add(time.Second)
+		latest, ok := ftdc.Latest()
+		test.That(tb, ok, test.ShouldBeTrue)
+		test.That(tb, latest.ConvertedTime().After(time.Unix(0, 0)), test.ShouldBeTrue)
+		test.That(tb, latest.ConvertedTime().Before(time.Unix(3600, 0)), test.ShouldBeTrue)
+	})
+}
+
 func TestCopeWithSubtleSchemaChange(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 
@@ -358,6 +401,34 @@ func TestParseTimeFromFile(t *testing.T) {
 	test.That(t, timeVal.Second(), test.ShouldEqual, 1)
 }
 
+func TestFiles(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ftdcFileDir := t.TempDir()
+	ftdc := New(ftdcFileDir, logger)
+
+	// a missing directory has no files yet
+	ftdc.ftdcDir = filepath.Join(ftdcFileDir, "missing")
+	files, err := ftdc.Files()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, files, test.ShouldBeEmpty)
+
+	ftdc.ftdcDir = ftdcFileDir
+	for _, name := range []string{
+		"viam-server-2024-11-18T20-37-01Z.ftdc",
+		"viam-server-2023-01-02T03-04-05Z.ftdc",
+		"notes.txt",
+		"unrelated.ftdc",
+	} {
+		test.That(t, os.WriteFile(filepath.Join(ftdcFileDir, name), nil, 0o600), test.ShouldBeNil)
+	}
+	files, err = ftdc.Files()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, files, test.ShouldResemble, []string{
+		filepath.Join(ftdcFileDir, "viam-server-2023-01-02T03-04-05Z.ftdc"),
+		filepath.Join(ftdcFileDir, "viam-server-2024-11-18T20-37-01Z.ftdc"),
+	})
+}
+
 func TestFileDeletion(t *testing.T) {
 	// This test takes ~10 seconds due to file naming limitations. This test creates FTDC files on
 	// disk whose names include the timestamp with seconds resolution. In this case FTDC has to wait
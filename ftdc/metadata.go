@@ -0,0 +1,92 @@
+package ftdc
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+)
+
+// metadataIdentifier is the first byte of a metadata document. Its least significant bit is set,
+// such that it is never confused with a metric document. See `doc.go`.
+const metadataIdentifier = 0x3
+
+// Metadata identifies the machine an FTDC file was captured on. It is written at the start of each
+// FTDC file such that captures from a fleet of machines can be told apart.
+type Metadata struct {
+	PartID    string `json:"part_id,omitempty"`
+	RobotName string `json:"robot_name,omitempty"`
+	// Version is the viam-server version, e.g: `v0.50.0`.
+	Version  string `json:"version,omitempty"`
+	Hostname string `json:"hostname,omitempty"`
+	OS       string `json:"os,omitempty"`
+	Arch     string `json:"arch,omitempty"`
+}
+
+// hostMetadata returns the metadata describing the host this process is running on.
+func hostMetadata() Metadata {
+	//nolint:errcheck
+	hostname, _ := os.Hostname()
+	return Metadata{
+		Hostname: hostname,
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+	}
+}
+
+// SetMetadata sets the metadata written at the start of each FTDC file. Empty host fields are
+// filled in with the values of the host this process is running on. It must be called before
+// `Start`.
+func (ftdc *FTDC) SetMetadata(metadata Metadata) {
+	host := hostMetadata()
+	if metadata.Hostname == "" {
+		metadata.Hostname = host.Hostname
+	}
+	if metadata.OS == "" {
+		metadata.OS = host.OS
+	}
+	if metadata.Arch == "" {
+		metadata.Arch = host.Arch
+	}
+	ftdc.metadata = metadata
+}
+
+// writeMetadata writes the metadata document: the metadata identifier byte followed by the metadata
+// serialized as a JSON object, including a trailing newline.
+func writeMetadata(metadata Metadata, output io.Writer) error {
+	if _, err := output.Write([]byte{metadataIdentifier}); err != nil {
+		return fmt.Errorf("Error writing metadata byte: %w", err)
+	}
+
+	if err := json.NewEncoder(output).Encode(metadata); err != nil {
+		return fmt.Errorf("Error writing metadata: %w", err)
+	}
+
+	return nil
+}
+
+// readMetadata expects to be positioned on the beginning of a JSON object, following the metadata
+// identifier byte. It returns the metadata and a new reader positioned on the first byte of the next
+// FTDC document.
+func readMetadata(reader *bufio.Reader) (*Metadata, *bufio.Reader, error) {
+	decoder := json.NewDecoder(reader)
+	var metadata Metadata
+	if err := decoder.Decode(&metadata); err != nil {
+		return nil, nil, fmt.Errorf("error reading FTDC metadata: %w", err)
+	}
+
+	// As with `readSchema`, the decoder may have read past the end of the JSON object. And the
+	// trailing newline written by the encoder must be consumed.
+	retReader := bufio.NewReader(io.MultiReader(decoder.Buffered(), reader))
+	ch, err := retReader.ReadByte()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading FTDC metadata: %w", err)
+	}
+	if ch != '\n' {
+		return nil, nil, fmt.Errorf("expected a newline after FTDC metadata, got: %q", ch)
+	}
+
+	return &metadata, retReader, nil
+}
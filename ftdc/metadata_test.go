@@ -0,0 +1,55 @@
+package ftdc
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestMetadata(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+
+	ftdcData := bytes.NewBuffer(nil)
+	ftdc := NewWithWriter(ftdcData, logger.Sublogger("ftdc"))
+	ftdc.SetMetadata(Metadata{PartID: "abc", RobotName: "rover", Version: "v0.50.0", Hostname: "pi"})
+	ftdc.Add("foo", &foo{x: 1, y: 2})
+	for range 2 {
+		test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+	}
+
+	datums, metadata, err := ParseWithMetadata(bytes.NewReader(ftdcData.Bytes()), logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, datums, test.ShouldHaveLength, 2)
+	test.That(t, datums[1].Readings, test.ShouldResemble, []Reading{{"foo.X", 1}, {"foo.Y", 2}})
+	test.That(t, metadata, test.ShouldResemble, &Metadata{
+		PartID:    "abc",
+		RobotName: "rover",
+		Version:   "v0.50.0",
+		Hostname:  "pi",
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	})
+
+	// `Parse` skips over the metadata.
+	datums, err = Parse(bytes.NewReader(ftdcData.Bytes()))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, datums, test.ShouldHaveLength, 2)
+
+	t.Run("without metadata", func(t *testing.T) {
+		// Files written before metadata was recorded start directly with a schema.
+		ftdcData := bytes.NewBuffer(nil)
+		ftdc := NewWithWriter(ftdcData, logger.Sublogger("ftdc"))
+		ftdc.writeMetadataNext = false
+		ftdc.Add("foo", &foo{x: 1, y: 2})
+		test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+
+		datums, metadata, err := ParseWithMetadata(ftdcData, logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, datums, test.ShouldHaveLength, 1)
+		test.That(t, metadata, test.ShouldBeNil)
+	})
+}
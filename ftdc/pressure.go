@@ -0,0 +1,140 @@
+package ftdc
+
+import (
+	"sync"
+)
+
+const (
+	// pressureCheckTicks is how often, in collection ticks (i.e: seconds), system pressure is
+	// checked.
+	pressureCheckTicks = 10
+	// sustainedPressureChecks is how many consecutive pressure checks must agree before the
+	// collection frequency is changed. With `pressureCheckTicks`, that is 30 seconds of sustained
+	// pressure, such that brief spikes do not affect collection.
+	sustainedPressureChecks = 3
+	// highPressure and lowPressure are the busy fractions at or above which the system is under
+	// pressure, and below which it has recovered. The gap avoids flapping between frequencies.
+	highPressure = 0.9
+	lowPressure  = 0.6
+	// maxCollectEvery bounds how far collection is slowed down. At one tick per second, FTDC
+	// collects at least once every 8 seconds.
+	maxCollectEvery = 8
+)
+
+// Pressure is how busy the system has been since the last reading.
+type Pressure struct {
+	// CPU is the fraction of time, between 0 and 1, the CPUs were busy.
+	CPU float64
+	// Disk is the fraction of time, between 0 and 1, the busiest disk had I/O in progress.
+	Disk float64
+}
+
+// PressureReader reads system pressure. Each call to `Pressure` reports on the time since the
+// previous call.
+type PressureReader interface {
+	Pressure() (Pressure, error)
+}
+
+// SetPressureReader makes FTDC adapt its collection frequency to system pressure. When the CPU or
+// a disk is under sustained pressure, FTDC halves how often it collects (and consequently writes
+// and syncs) datums, down to once every `maxCollectEvery` seconds. It returns to collecting every
+// second as pressure subsides. This keeps FTDC from meaningfully contributing to the problem it is
+// measuring.
+//
+// Adjustments are logged and recorded in the "ftdc" metrics. It must be called before `Start`.
+func (ftdc *FTDC) SetPressureReader(reader PressureReader) {
+	ftdc.throttler = &throttler{reader: reader, collectEvery: 1, ftdc: ftdc}
+	ftdc.Add("ftdc", ftdc.throttler)
+}
+
+// throttler decides, on each collection tick, whether FTDC collects a datum.
+type throttler struct {
+	reader PressureReader
+	ftdc   *FTDC
+
+	// mu protects all of the following members, which are read by `Stats` concurrently with
+	// `shouldCollect`.
+	mu           sync.Mutex
+	ticks        int64
+	collectEvery int
+	// highChecks and lowChecks count consecutive pressure checks above `highPressure` and below
+	// `lowPressure`.
+	highChecks   int
+	lowChecks    int
+	lastPressure Pressure
+	numThrottles int64
+}
+
+// throttlerStats are the "ftdc" metrics recording how FTDC adapted to system pressure.
+type throttlerStats struct {
+	// CollectionIntervalSecs is how often FTDC currently collects datums.
+	CollectionIntervalSecs int
+	// NumThrottles counts how many times FTDC slowed down collection.
+	NumThrottles int64
+	CPUPressure  float64
+	DiskPressure float64
+}
+
+// Stats returns the `throttlerStats`.
+func (throttler *throttler) Stats() any {
+	throttler.mu.Lock()
+	defer throttler.mu.Unlock()
+	return throttlerStats{
+		CollectionIntervalSecs: throttler.collectEvery,
+		NumThrottles:           throttler.numThrottles,
+		CPUPressure:            throttler.lastPressure.CPU,
+		DiskPressure:           throttler.lastPressure.Disk,
+	}
+}
+
+// shouldCollect is called on every collection tick. It returns whether a datum should be collected
+// on this tick.
+func (throttler *throttler) shouldCollect() bool {
+	throttler.mu.Lock()
+	defer throttler.mu.Unlock()
+
+	throttler.ticks++
+	collect := throttler.ticks%int64(throttler.collectEvery) == 0
+	if throttler.ticks%pressureCheckTicks == 0 {
+		throttler.checkPressure()
+	}
+
+	return collect
+}
+
+// checkPressure reads the system pressure and changes the collection frequency if pressure has
+// been sustained. It is called with `mu` held.
+func (throttler *throttler) checkPressure() {
+	logger := throttler.ftdc.logger
+	pressure, err := throttler.reader.Pressure()
+	if err != nil {
+		logger.Debugw("Error reading system pressure", "err", err)
+		return
+	}
+	throttler.lastPressure = pressure
+
+	switch {
+	case pressure.CPU >= highPressure || pressure.Disk >= highPressure:
+		throttler.highChecks++
+		throttler.lowChecks = 0
+	case pressure.CPU < lowPressure && pressure.Disk < lowPressure:
+		throttler.lowChecks++
+		throttler.highChecks = 0
+	default:
+		throttler.highChecks, throttler.lowChecks = 0, 0
+	}
+
+	if throttler.highChecks >= sustainedPressureChecks && throttler.collectEvery < maxCollectEvery {
+		throttler.collectEvery *= 2
+		throttler.numThrottles++
+		throttler.highChecks = 0
+		logger.Infow("System is under pressure, reducing FTDC collection frequency",
+			"cpu", pressure.CPU, "disk", pressure.Disk, "intervalSecs", throttler.collectEvery)
+	}
+	if throttler.lowChecks >= sustainedPressureChecks && throttler.collectEvery > 1 {
+		throttler.collectEvery /= 2
+		throttler.lowChecks = 0
+		logger.Infow("System pressure subsided, increasing FTDC collection frequency",
+			"cpu", pressure.CPU, "disk", pressure.Disk, "intervalSecs", throttler.collectEvery)
+	}
+}
@@ -0,0 +1,74 @@
+package ftdc
+
+import (
+	"bytes"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+type fakePressureReader struct {
+	pressure Pressure
+}
+
+func (fpr *fakePressureReader) Pressure() (Pressure, error) {
+	return fpr.pressure, nil
+}
+
+func TestAdaptToPressure(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ftdc := NewWithWriter(bytes.NewBuffer(nil), logger.Sublogger("ftdc"))
+	reader := &fakePressureReader{}
+	ftdc.SetPressureReader(reader)
+
+	// collectSecs runs `secs` collection ticks and returns how many of them collected a datum.
+	collectSecs := func(secs int) int {
+		collected := 0
+		for range secs {
+			if ftdc.throttler.shouldCollect() {
+				collected++
+			}
+		}
+		return collected
+	}
+	intervalSecs := func() int {
+		return ftdc.throttler.Stats().(throttlerStats).CollectionIntervalSecs
+	}
+
+	test.That(t, collectSecs(60), test.ShouldEqual, 60)
+	test.That(t, intervalSecs(), test.ShouldEqual, 1)
+
+	// A spike in pressure shorter than `sustainedPressureChecks` is ignored.
+	reader.pressure = Pressure{CPU: 0.95}
+	collectSecs(20)
+	reader.pressure = Pressure{CPU: 0.7}
+	collectSecs(10)
+	test.That(t, intervalSecs(), test.ShouldEqual, 1)
+
+	// Sustained pressure halves the collection frequency every 30 seconds, down to the minimum.
+	reader.pressure = Pressure{Disk: 1}
+	collectSecs(30)
+	test.That(t, intervalSecs(), test.ShouldEqual, 2)
+	test.That(t, collectSecs(30), test.ShouldEqual, 15)
+	test.That(t, intervalSecs(), test.ShouldEqual, 4)
+	collectSecs(120)
+	test.That(t, intervalSecs(), test.ShouldEqual, maxCollectEvery)
+
+	stats := ftdc.throttler.Stats().(throttlerStats)
+	test.That(t, stats.NumThrottles, test.ShouldEqual, 3)
+	test.That(t, stats.DiskPressure, test.ShouldEqual, 1)
+
+	// Collection speeds back up once pressure subsides.
+	reader.pressure = Pressure{CPU: 0.1, Disk: 0.1}
+	collectSecs(90)
+	test.That(t, intervalSecs(), test.ShouldEqual, 1)
+
+	// The adjustments are recorded as FTDC metrics.
+	test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+	latest, ok := ftdc.Latest()
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, latest.Readings, test.ShouldContain, Reading{"ftdc.NumThrottles", 3})
+	test.That(t, latest.Readings, test.ShouldContain, Reading{"ftdc.CollectionIntervalSecs", 1})
+}
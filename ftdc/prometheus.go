@@ -0,0 +1,343 @@
+package ftdc
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	// defaultPrometheusPushInterval is how often a `PrometheusExporter` pushes the datums it has
+	// accumulated when none is configured.
+	defaultPrometheusPushInterval = 15 * time.Second
+	// maxPrometheusPendingDatums bounds how many datums a `PrometheusExporter` holds onto while the
+	// remote-write endpoint is unreachable. At one datum per second, this is ten minutes of data.
+	// Older datums are dropped first.
+	maxPrometheusPendingDatums = 600
+	// maxPrometheusSamplesPerRequest bounds the size of a single remote-write request. Larger pushes
+	// are split across multiple requests.
+	maxPrometheusSamplesPerRequest = 50_000
+	// prometheusMetricLabel is the label holding the original FTDC metric name, as the Prometheus
+	// metric name has its invalid characters replaced.
+	prometheusMetricLabel = "ftdc_metric"
+)
+
+// Exporter receives each datum as it is written by FTDC, such as to forward it to another system.
+// `Export` is called from the FTDC writer goroutine and must not block. FTDC closes its exporters
+// when it is stopped.
+type Exporter interface {
+	Export(datum FlatDatum)
+	Close() error
+}
+
+// PrometheusConfig describes a Prometheus remote-write endpoint (e.g: Mimir, Thanos or
+// VictoriaMetrics) to push FTDC data to.
+type PrometheusConfig struct {
+	// URL is the remote-write endpoint. E.g: `http://localhost:9009/api/v1/push`.
+	URL string
+	// Labels are added to every time series pushed. E.g: `{"part_id": "<id>"}`, so the data of
+	// many robots can be stored together.
+	Labels map[string]string
+	// Headers are added to every request. E.g: `{"X-Scope-OrgID": "<tenant>"}` for Mimir, or an
+	// `Authorization` header.
+	Headers map[string]string
+	// PushInterval is how often accumulated datums are pushed. Defaults to 15 seconds.
+	PushInterval time.Duration
+}
+
+// PrometheusExporter pushes FTDC datums, with their original timestamps, to a Prometheus
+// remote-write endpoint. Each FTDC metric becomes a time series named after its `LabelMetric`,
+// with characters that are invalid in Prometheus metric names replaced by underscores. E.g:
+// `proc.viam-server.UserCPUSecs` becomes `proc_UserCPUSecs{process="viam-server"}`.
+//
+// It can be used to push datums directly with `Push`, or added to a running FTDC with
+// `FTDC.AddExporter`, in which case datums are pushed in the background every `PushInterval`.
+type PrometheusExporter struct {
+	config PrometheusConfig
+	client *http.Client
+
+	workers  *utils.StoppableWorkers
+	datumCh  chan FlatDatum
+	stopOnce sync.Once
+
+	logger logging.Logger
+}
+
+// NewPrometheusExporter creates a new `PrometheusExporter`. Background pushing of exported datums
+// is not started until `Start` is called.
+func NewPrometheusExporter(config PrometheusConfig, logger logging.Logger) (*PrometheusExporter, error) {
+	if config.URL == "" {
+		return nil, errors.New("prometheus remote-write URL is required")
+	}
+	if config.PushInterval <= 0 {
+		config.PushInterval = defaultPrometheusPushInterval
+	}
+	for name := range config.Labels {
+		if !isValidPrometheusLabelName(name) {
+			return nil, fmt.Errorf("invalid prometheus label name %q", name)
+		}
+	}
+
+	return &PrometheusExporter{
+		config: config,
+		client: &http.Client{Timeout: 30 * time.Second},
+		// Allow for some wiggle before dropping datums.
+		datumCh: make(chan FlatDatum, 2*int(config.PushInterval/time.Second)+20),
+		logger:  logger,
+	}, nil
+}
+
+// Start spins off the background goroutine for pushing exported datums.
+func (pe *PrometheusExporter) Start() {
+	pe.workers = utils.NewBackgroundStoppableWorkers(pe.pushLoop)
+}
+
+// Export queues the datum to be pushed by the background goroutine. If the queue is full, the
+// datum is dropped rather than blocking FTDC.
+func (pe *PrometheusExporter) Export(datum FlatDatum) {
+	select {
+	case pe.datumCh <- datum:
+	default:
+		pe.logger.Debugw("Prometheus exporter queue is full, dropping datum", "time", datum.ConvertedTime())
+	}
+}
+
+// Close stops the background goroutine, after it makes a last attempt to push any remaining
+// datums.
+func (pe *PrometheusExporter) Close() error {
+	pe.stopOnce.Do(func() {
+		if pe.workers != nil {
+			pe.workers.Stop()
+		}
+	})
+	return nil
+}
+
+func (pe *PrometheusExporter) pushLoop(ctx context.Context) {
+	ticker := time.NewTicker(pe.config.PushInterval)
+	defer ticker.Stop()
+
+	var pending []FlatDatum
+	push := func(ctx context.Context) {
+		if len(pending) == 0 {
+			return
+		}
+		if err := pe.Push(ctx, pending); err != nil {
+			// Keep the datums to retry on the next push. The endpoint may only be temporarily
+			// unreachable, such as when the robot is offline.
+			pe.logger.Warnw("Error pushing FTDC data to prometheus", "url", pe.config.URL, "err", err)
+			if len(pending) > maxPrometheusPendingDatums {
+				pending = pending[len(pending)-maxPrometheusPendingDatums:]
+			}
+			return
+		}
+		pending = pending[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Drain whatever was queued and make one last attempt at pushing it.
+		drain:
+			for {
+				select {
+				case datum := <-pe.datumCh:
+					pending = append(pending, datum)
+				default:
+					break drain
+				}
+			}
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			push(flushCtx)
+			cancel()
+			return
+		case datum := <-pe.datumCh:
+			pending = append(pending, datum)
+		case <-ticker.C:
+			push(ctx)
+		}
+	}
+}
+
+// Push sends the datums to the remote-write endpoint, splitting them across multiple requests if
+// there are many.
+func (pe *PrometheusExporter) Push(ctx context.Context, datums []FlatDatum) error {
+	for len(datums) > 0 {
+		numDatums, numSamples := 0, 0
+		for numDatums < len(datums) {
+			numReadings := len(datums[numDatums].Readings)
+			if numDatums > 0 && numSamples+numReadings > maxPrometheusSamplesPerRequest {
+				break
+			}
+			numSamples += numReadings
+			numDatums++
+		}
+
+		if err := pe.send(ctx, encodePrometheusWriteRequest(datums[:numDatums], pe.config.Labels)); err != nil {
+			return err
+		}
+		datums = datums[numDatums:]
+	}
+
+	return nil
+}
+
+func (pe *PrometheusExporter) send(ctx context.Context, writeRequest []byte) error {
+	body := snappy.Encode(nil, writeRequest)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pe.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, value := range pe.config.Headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := pe.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer utils.UncheckedErrorFunc(resp.Body.Close)
+
+	if resp.StatusCode/100 != 2 {
+		// Include the start of the response body, which generally explains why the push failed.
+		//nolint:errcheck
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("remote-write returned status %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	//nolint:errcheck
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// prometheusSample is a single value of a time series.
+type prometheusSample struct {
+	value       float64
+	timestampMs int64
+}
+
+// encodePrometheusWriteRequest serializes the datums into a remote-write `WriteRequest` protobuf
+// message. There is one time series per metric, holding a sample for each datum it appears in:
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label { string name = 1; string value = 2; }
+//	message Sample { double value = 1; int64 timestamp = 2; }
+func encodePrometheusWriteRequest(datums []FlatDatum, labels map[string]string) []byte {
+	// Samples within a time series must be in timestamp order.
+	datums = slices.Clone(datums)
+	slices.SortStableFunc(datums, func(left, right FlatDatum) int {
+		return cmp.Compare(left.Time, right.Time)
+	})
+
+	samples := make(map[string][]prometheusSample)
+	var metricNames []string
+	for _, datum := range datums {
+		for _, reading := range datum.Readings {
+			if _, exists := samples[reading.MetricName]; !exists {
+				metricNames = append(metricNames, reading.MetricName)
+			}
+			samples[reading.MetricName] = append(samples[reading.MetricName], prometheusSample{
+				value:       float64(reading.Value),
+				timestampMs: datum.Time / int64(time.Millisecond),
+			})
+		}
+	}
+	slices.Sort(metricNames)
+
+	var writeRequest []byte
+	for _, metricName := range metricNames {
+		labeled := LabelMetric(metricName)
+		seriesLabels := make([][2]string, 0, len(labels)+len(labeled.Labels)+2)
+		for name, value := range labels {
+			seriesLabels = append(seriesLabels, [2]string{name, value})
+		}
+		for name, value := range labeled.Labels {
+			seriesLabels = append(seriesLabels, [2]string{name, value})
+		}
+		seriesLabels = append(seriesLabels,
+			[2]string{"__name__", prometheusMetricName(labeled.Name)},
+			[2]string{prometheusMetricLabel, metricName})
+		// Labels within a time series must be sorted by name.
+		slices.SortFunc(seriesLabels, func(left, right [2]string) int {
+			return strings.Compare(left[0], right[0])
+		})
+
+		var timeSeries []byte
+		for _, label := range seriesLabels {
+			var encodedLabel []byte
+			encodedLabel = protowire.AppendTag(encodedLabel, 1, protowire.BytesType)
+			encodedLabel = protowire.AppendString(encodedLabel, label[0])
+			encodedLabel = protowire.AppendTag(encodedLabel, 2, protowire.BytesType)
+			encodedLabel = protowire.AppendString(encodedLabel, label[1])
+
+			timeSeries = protowire.AppendTag(timeSeries, 1, protowire.BytesType)
+			timeSeries = protowire.AppendBytes(timeSeries, encodedLabel)
+		}
+		for _, sample := range samples[metricName] {
+			var encodedSample []byte
+			encodedSample = protowire.AppendTag(encodedSample, 1, protowire.Fixed64Type)
+			encodedSample = protowire.AppendFixed64(encodedSample, math.Float64bits(sample.value))
+			encodedSample = protowire.AppendTag(encodedSample, 2, protowire.VarintType)
+			encodedSample = protowire.AppendVarint(encodedSample, uint64(sample.timestampMs))
+
+			timeSeries = protowire.AppendTag(timeSeries, 2, protowire.BytesType)
+			timeSeries = protowire.AppendBytes(timeSeries, encodedSample)
+		}
+
+		writeRequest = protowire.AppendTag(writeRequest, 1, protowire.BytesType)
+		writeRequest = protowire.AppendBytes(writeRequest, timeSeries)
+	}
+
+	return writeRequest
+}
+
+// prometheusMetricName turns an FTDC metric name, such as `resource.MoveToPosition.timeSpent`, into
+// a valid Prometheus metric name, such as `resource_MoveToPosition_timeSpent`.
+func prometheusMetricName(metricName string) string {
+	var ret strings.Builder
+	for idx, char := range metricName {
+		switch {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char == '_':
+			ret.WriteRune(char)
+		case char >= '0' && char <= '9':
+			if idx == 0 {
+				// Metric names may not start with a digit.
+				ret.WriteRune('_')
+			}
+			ret.WriteRune(char)
+		default:
+			ret.WriteRune('_')
+		}
+	}
+	return ret.String()
+}
+
+func isValidPrometheusLabelName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "__") || name == prometheusMetricLabel {
+		// Names starting with `__` are reserved for internal use.
+		return false
+	}
+	if slices.Contains(metricLabelNames, name) {
+		// These are set per time series from the FTDC metric name.
+		return false
+	}
+	return prometheusMetricName(name) == name
+}
@@ -0,0 +1,204 @@
+package ftdc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"go.viam.com/rdk/logging"
+)
+
+type pushedSample struct {
+	value       float64
+	timestampMs int64
+}
+
+type pushedSeries struct {
+	labels  [][2]string
+	samples []pushedSample
+}
+
+// consumeMessage calls `onField` with each field of the protobuf message, returning the value of
+// length-delimited fields as bytes and of other fields as a uint64.
+func consumeMessage(t *testing.T, msg []byte, onField func(num protowire.Number, bytesVal []byte, intVal uint64)) {
+	t.Helper()
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		test.That(t, n, test.ShouldBeGreaterThan, 0)
+		msg = msg[n:]
+		switch typ {
+		case protowire.BytesType:
+			val, n := protowire.ConsumeBytes(msg)
+			test.That(t, n, test.ShouldBeGreaterThan, 0)
+			onField(num, val, 0)
+			msg = msg[n:]
+		case protowire.Fixed64Type:
+			val, n := protowire.ConsumeFixed64(msg)
+			test.That(t, n, test.ShouldBeGreaterThan, 0)
+			onField(num, nil, val)
+			msg = msg[n:]
+		case protowire.VarintType:
+			val, n := protowire.ConsumeVarint(msg)
+			test.That(t, n, test.ShouldBeGreaterThan, 0)
+			onField(num, nil, val)
+			msg = msg[n:]
+		default:
+			t.Fatalf("unexpected wire type %v", typ)
+		}
+	}
+}
+
+func decodeWriteRequest(t *testing.T, writeRequest []byte) []pushedSeries {
+	t.Helper()
+	var ret []pushedSeries
+	consumeMessage(t, writeRequest, func(_ protowire.Number, timeSeries []byte, _ uint64) {
+		var series pushedSeries
+		consumeMessage(t, timeSeries, func(num protowire.Number, field []byte, _ uint64) {
+			if num == 1 {
+				var label [2]string
+				consumeMessage(t, field, func(num protowire.Number, val []byte, _ uint64) {
+					label[num-1] = string(val)
+				})
+				series.labels = append(series.labels, label)
+				return
+			}
+
+			var sample pushedSample
+			consumeMessage(t, field, func(num protowire.Number, _ []byte, val uint64) {
+				if num == 1 {
+					sample.value = math.Float64frombits(val)
+				} else {
+					sample.timestampMs = int64(val)
+				}
+			})
+			series.samples = append(series.samples, sample)
+		})
+		ret = append(ret, series)
+	})
+	return ret
+}
+
+// remoteWriteServer records the time series of each remote-write request it receives. Requests are
+// expected to be for the `orgID` tenant.
+type remoteWriteServer struct {
+	orgID    string
+	mu       sync.Mutex
+	requests [][]pushedSeries
+}
+
+func (rws *remoteWriteServer) start(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		test.That(t, r.Header.Get("Content-Encoding"), test.ShouldEqual, "snappy")
+		test.That(t, r.Header.Get("Content-Type"), test.ShouldEqual, "application/x-protobuf")
+		test.That(t, r.Header.Get("X-Scope-OrgID"), test.ShouldEqual, rws.orgID)
+
+		body, err := io.ReadAll(r.Body)
+		test.That(t, err, test.ShouldBeNil)
+		writeRequest, err := snappy.Decode(nil, body)
+		test.That(t, err, test.ShouldBeNil)
+
+		rws.mu.Lock()
+		defer rws.mu.Unlock()
+		rws.requests = append(rws.requests, decodeWriteRequest(t, writeRequest))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPrometheusPush(t *testing.T) {
+	rws := &remoteWriteServer{orgID: "tenant"}
+	server := rws.start(t)
+
+	exporter, err := NewPrometheusExporter(PrometheusConfig{
+		URL:     server.URL,
+		Labels:  map[string]string{"part_id": "abc"},
+		Headers: map[string]string{"X-Scope-OrgID": "tenant"},
+	}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	datums := []FlatDatum{
+		// Out of order, to assert samples are sorted by time.
+		{Time: start.Add(time.Second).UnixNano(), Readings: []Reading{{"proc.viam-server.UserCPUSecs", 2}}},
+		{Time: start.UnixNano(), Readings: []Reading{{"proc.viam-server.UserCPUSecs", 1}, {"9lives", 5}}},
+	}
+	test.That(t, exporter.Push(context.Background(), datums), test.ShouldBeNil)
+
+	test.That(t, rws.requests, test.ShouldHaveLength, 1)
+	test.That(t, rws.requests[0], test.ShouldResemble, []pushedSeries{
+		{
+			labels:  [][2]string{{"__name__", "_9lives"}, {"ftdc_metric", "9lives"}, {"part_id", "abc"}},
+			samples: []pushedSample{{5, start.UnixMilli()}},
+		},
+		{
+			labels: [][2]string{
+				{"__name__", "proc_UserCPUSecs"},
+				{"ftdc_metric", "proc.viam-server.UserCPUSecs"},
+				{"part_id", "abc"},
+				{"process", "viam-server"},
+			},
+			samples: []pushedSample{{1, start.UnixMilli()}, {2, start.Add(time.Second).UnixMilli()}},
+		},
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewPrometheusExporter(PrometheusConfig{}, logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = NewPrometheusExporter(PrometheusConfig{URL: server.URL, Labels: map[string]string{"__name__": "x"}},
+			logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = NewPrometheusExporter(PrometheusConfig{URL: server.URL, Labels: map[string]string{"resource": "x"}},
+			logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldNotBeNil)
+
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "out of order sample", http.StatusBadRequest)
+		}))
+		defer failing.Close()
+		exporter, err := NewPrometheusExporter(PrometheusConfig{URL: failing.URL}, logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldBeNil)
+		err = exporter.Push(context.Background(), datums)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "out of order sample")
+	})
+}
+
+func TestPrometheusExporter(t *testing.T) {
+	rws := &remoteWriteServer{orgID: "tenant"}
+	server := rws.start(t)
+	logger := logging.NewTestLogger(t)
+
+	exporter, err := NewPrometheusExporter(PrometheusConfig{
+		URL:     server.URL,
+		Headers: map[string]string{"X-Scope-OrgID": "tenant"},
+		// Long enough that datums are only pushed when the exporter is closed.
+		PushInterval: time.Hour,
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	exporter.Start()
+
+	ftdc := NewWithWriter(bytes.NewBuffer(nil), logger.Sublogger("ftdc"))
+	ftdc.AddExporter(exporter)
+	ftdc.Add("foo1", &foo{})
+	for range 3 {
+		test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+	}
+
+	// Closing the exporter, as FTDC does when it is stopped, pushes what it has left.
+	test.That(t, exporter.Close(), test.ShouldBeNil)
+	test.That(t, rws.requests, test.ShouldHaveLength, 1)
+	test.That(t, rws.requests[0], test.ShouldHaveLength, 2)
+	for _, series := range rws.requests[0] {
+		test.That(t, series.samples, test.ShouldHaveLength, 3)
+	}
+}
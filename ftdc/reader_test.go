@@ -0,0 +1,135 @@
+package ftdc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+// writeTestData writes five datums, one second apart starting at the Unix epoch. The third datum
+// starts a new schema.
+func writeTestData(t *testing.T) []byte {
+	t.Helper()
+	logger := logging.NewTestLogger(t)
+
+	ftdcData := bytes.NewBuffer(nil)
+	ftdc := NewWithWriter(ftdcData, logger.Sublogger("ftdc"))
+	mockClock := clock.NewMock()
+	ftdc.SetClock(mockClock)
+	statser := mockStatser{}
+	ftdc.Add("mock", &statser)
+	for x := range 5 {
+		if x < 2 {
+			statser.stats = struct{ X int }{x}
+		} else {
+			statser.stats = struct {
+				X int
+				Y int
+			}{x, 1}
+		}
+		test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+		mockClock.Add(time.Second)
+	}
+	return ftdcData.Bytes()
+}
+
+// readAll reads datums until `Next` returns an error.
+func readAll(reader *Reader) ([]FlatDatum, error) {
+	var ret []FlatDatum
+	for {
+		datum, err := reader.Next()
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, datum)
+	}
+}
+
+func TestReader(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ftdcData := writeTestData(t)
+
+	reader, err := NewReader(bytes.NewReader(ftdcData), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer reader.Close()
+	datums, err := readAll(reader)
+	test.That(t, err, test.ShouldEqual, io.EOF)
+	test.That(t, datums, test.ShouldHaveLength, 5)
+	test.That(t, datums[0].Time, test.ShouldEqual, 0)
+	test.That(t, datums[1].Readings, test.ShouldResemble, []Reading{{"mock.X", 1}})
+	test.That(t, datums[4].Time, test.ShouldEqual, (4 * time.Second).Nanoseconds())
+	test.That(t, datums[4].Readings, test.ShouldResemble, []Reading{{"mock.X", 4}, {"mock.Y", 1}})
+	test.That(t, reader.Metadata(), test.ShouldNotBeNil)
+
+	// The reader returns the same datums as `Parse`.
+	parsed, err := Parse(bytes.NewReader(ftdcData))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, datums, test.ShouldResemble, parsed)
+
+	t.Run("time range", func(t *testing.T) {
+		reader, err := NewReader(bytes.NewReader(ftdcData), logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer reader.Close()
+		reader.SetTimeRange(time.Unix(1, 0), time.Unix(3, 0))
+		datums, err := readAll(reader)
+		test.That(t, err, test.ShouldEqual, io.EOF)
+		// Datums outside of the range are still diffed against.
+		test.That(t, datums, test.ShouldResemble, parsed[1:4])
+
+		reader, err = NewReader(bytes.NewReader(ftdcData), logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer reader.Close()
+		reader.SetTimeRange(time.Unix(3, 0), time.Time{})
+		datums, err = readAll(reader)
+		test.That(t, err, test.ShouldEqual, io.EOF)
+		test.That(t, datums, test.ShouldResemble, parsed[3:])
+	})
+
+	t.Run("follow", func(t *testing.T) {
+		// A bytes.Buffer returns io.EOF once drained, and more data once written to. Like a file
+		// that is still being written.
+		live := bytes.NewBuffer(nil)
+		reader, err := NewReader(live, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer reader.Close()
+
+		_, err = reader.Next()
+		test.That(t, err, test.ShouldEqual, io.EOF)
+
+		// Write the first two chunks, and the third part way through its header and then part way
+		// through its contents.
+		secondChunkEnd := 0
+		for range 2 {
+			secondChunkEnd += chunkHeaderBytes + int(binary.BigEndian.Uint32(ftdcData[secondChunkEnd+1:]))
+		}
+		live.Write(ftdcData[:secondChunkEnd+4])
+		datums, err := readAll(reader)
+		test.That(t, err, test.ShouldEqual, io.EOF)
+		test.That(t, datums, test.ShouldResemble, parsed[:2])
+
+		live.Write(ftdcData[secondChunkEnd+4 : secondChunkEnd+chunkHeaderBytes+10])
+		datums, err = readAll(reader)
+		test.That(t, err, test.ShouldEqual, io.EOF)
+		test.That(t, datums, test.ShouldBeEmpty)
+
+		live.Write(ftdcData[secondChunkEnd+chunkHeaderBytes+10:])
+		datums, err = readAll(reader)
+		test.That(t, err, test.ShouldEqual, io.EOF)
+		test.That(t, datums, test.ShouldResemble, parsed[2:])
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		// Unlike `Next`, parsing a file that ends part way through a chunk is an error.
+		datums, err := Parse(bytes.NewReader(ftdcData[:len(ftdcData)-1]))
+		test.That(t, errors.Is(err, io.ErrUnexpectedEOF), test.ShouldBeTrue)
+		test.That(t, datums, test.ShouldResemble, parsed[:4])
+	})
+}
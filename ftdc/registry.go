@@ -0,0 +1,95 @@
+package ftdc
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Registry is a set of named statsers that may change at runtime. E.g: the custom metrics of a
+// module, which come and go as its resources are reconfigured. A registry is itself a `Statser`,
+// whose stats are those of each of its statsers keyed by name. When a registry added to an `FTDC`
+// gains or loses statsers, a new schema is written and the datums before and after the change are
+// both parsed as usual.
+type Registry struct {
+	mu       sync.Mutex
+	statsers []namedStatser
+}
+
+// NewRegistry creates an empty `Registry`.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Add registers a statser under the name. Its stats are recorded as `<name>.<field>` metrics,
+// relative to wherever the registry is added.
+func (r *Registry) Add(name string, statser Statser) error {
+	if name == "" {
+		return errors.New("statser name cannot be empty")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.statsers {
+		if existing.name == name {
+			return fmt.Errorf("statser %q is already registered", name)
+		}
+	}
+	r.statsers = append(r.statsers, namedStatser{name: name, statser: statser})
+	return nil
+}
+
+// Remove unregisters the statser added with the name. It returns false if there was none.
+func (r *Registry) Remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for idx, existing := range r.statsers {
+		if existing.name == name {
+			r.statsers = slices.Delete(r.statsers, idx, idx+1)
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the names of the registered statsers, in the order they were added.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.statsers))
+	for _, existing := range r.statsers {
+		names = append(names, existing.name)
+	}
+	return names
+}
+
+// Stats returns the stats of each registered statser, keyed by name.
+func (r *Registry) Stats() any {
+	// As with `FTDC.constructDatum`, `Stats` methods are called without holding the mutex.
+	r.mu.Lock()
+	statsers := slices.Clone(r.statsers)
+	r.mu.Unlock()
+
+	ret := make(map[string]any, len(statsers))
+	for _, namedStatser := range statsers {
+		ret[namedStatser.name] = namedStatser.statser.Stats()
+	}
+	return ret
+}
+
+// Flatten returns the readings of a value returned by a `Statser`, named as they would be in FTDC
+// relative to the statser. E.g: `MoveCount` or `Latency.P99`. This is for passing stats along to
+// an FTDC in another process.
+func Flatten(stats any) ([]Reading, error) {
+	fields, values, err := flatten(reflect.ValueOf(stats))
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Reading, len(fields))
+	for idx := range fields {
+		ret[idx] = Reading{MetricName: fields[idx], Value: values[idx]}
+	}
+	return ret, nil
+}
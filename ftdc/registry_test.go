@@ -0,0 +1,58 @@
+package ftdc
+
+import (
+	"bytes"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	test.That(t, registry.Stats(), test.ShouldResemble, map[string]any{})
+
+	motor := &mockStatser{stats: struct{ MoveCount int }{1}}
+	test.That(t, registry.Add("motor", motor), test.ShouldBeNil)
+	test.That(t, registry.Add("motor", motor), test.ShouldBeError, `statser "motor" is already registered`)
+	test.That(t, registry.Add("", motor), test.ShouldNotBeNil)
+	test.That(t, registry.Add("queue", &mockStatser{stats: map[string]float32{"Depth": 2}}), test.ShouldBeNil)
+	test.That(t, registry.Names(), test.ShouldResemble, []string{"motor", "queue"})
+
+	readings, err := Flatten(registry.Stats())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings, test.ShouldResemble, []Reading{{"motor.MoveCount", 1}, {"queue.Depth", 2}})
+
+	test.That(t, registry.Remove("queue"), test.ShouldBeTrue)
+	test.That(t, registry.Remove("queue"), test.ShouldBeFalse)
+	test.That(t, registry.Names(), test.ShouldResemble, []string{"motor"})
+}
+
+// TestRegistrySchemaChange asserts that statsers coming and going from a registry added to FTDC are
+// recorded as schema changes, with the data before and after the change parsed as usual.
+func TestRegistrySchemaChange(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ftdcData := bytes.NewBuffer(nil)
+	ftdc := NewWithWriter(ftdcData, logger.Sublogger("ftdc"))
+
+	registry := NewRegistry()
+	ftdc.Add("custom", registry)
+	test.That(t, registry.Add("motor", &mockStatser{stats: struct{ MoveCount int }{1}}), test.ShouldBeNil)
+	test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+
+	test.That(t, registry.Add("encoder", &mockStatser{stats: struct{ Ticks int }{100}}), test.ShouldBeNil)
+	test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+
+	test.That(t, registry.Remove("motor"), test.ShouldBeTrue)
+	test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+
+	datums, err := Parse(ftdcData)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, datums, test.ShouldHaveLength, 3)
+	test.That(t, datums[0].Readings, test.ShouldResemble, []Reading{{"custom.motor.MoveCount", 1}})
+	test.That(t, datums[1].Readings, test.ShouldResemble, []Reading{
+		{"custom.encoder.Ticks", 100}, {"custom.motor.MoveCount", 1},
+	})
+	test.That(t, datums[2].Readings, test.ShouldResemble, []Reading{{"custom.encoder.Ticks", 100}})
+}
@@ -0,0 +1,241 @@
+package ftdc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+)
+
+// Renderer writes the data of FTDC files to an output backend, such as a dashboard or a file
+// format. Renderers are used by the FTDC parser CLI to output the datums within its current range.
+// Unlike an `Exporter`, a renderer is given all of the datums of a file at once.
+type Renderer interface {
+	// Render writes the datums of an FTDC file. The label tells apart multiple FTDC files rendered
+	// together. E.g: a robot or part name. It is empty when a single file is rendered.
+	Render(ctx context.Context, label string, datums []FlatDatum) error
+	Close() error
+}
+
+// RendererFactory creates a `Renderer` writing to the target. What the target is, is up to the
+// renderer. E.g: a URL or a file path.
+type RendererFactory func(target string, logger logging.Logger) (Renderer, error)
+
+var (
+	renderersMu sync.Mutex
+	renderers   = map[string]RendererFactory{
+		"prometheus": newPrometheusRenderer,
+		"exec":       newSubprocessRenderer,
+		"csv":        newCSVRenderer,
+		"json":       newJSONRenderer,
+	}
+)
+
+// RegisterRenderer makes a renderer available by name to the FTDC parser CLI. It panics if a
+// renderer is already registered with the name. Renderers are generally registered from an
+// `init` function, or from the `RegisterRenderers` function of a Go plugin loaded by the CLI.
+func RegisterRenderer(name string, factory RendererFactory) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	if _, exists := renderers[name]; exists {
+		panic(fmt.Sprintf("FTDC renderer %q is already registered", name))
+	}
+	renderers[name] = factory
+}
+
+// LookupRenderer returns the factory of the renderer registered with the name.
+func LookupRenderer(name string) (RendererFactory, bool) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	factory, ok := renderers[name]
+	return factory, ok
+}
+
+// RegisteredRenderers returns the sorted names of the registered renderers.
+func RegisteredRenderers() []string {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// prometheusRenderer pushes the datums of each FTDC file to a Prometheus remote-write endpoint,
+// adding the file's label as a `robot` label.
+type prometheusRenderer struct {
+	url    string
+	logger logging.Logger
+}
+
+func newPrometheusRenderer(target string, logger logging.Logger) (Renderer, error) {
+	if target == "" {
+		return nil, errors.New("prometheus remote-write URL is required")
+	}
+	return &prometheusRenderer{url: target, logger: logger}, nil
+}
+
+func (pr *prometheusRenderer) Render(ctx context.Context, label string, datums []FlatDatum) error {
+	config := PrometheusConfig{URL: pr.url}
+	if label != "" {
+		config.Labels = map[string]string{"robot": label}
+	}
+	exporter, err := NewPrometheusExporter(config, pr.logger)
+	if err != nil {
+		return err
+	}
+	return exporter.Push(ctx, datums)
+}
+
+func (pr *prometheusRenderer) Close() error {
+	return nil
+}
+
+// fileRenderer writes the datums of each FTDC file to the target file with an `Exporter`, adding
+// the file's label as a `robot` label. An existing file is overwritten.
+type fileRenderer struct {
+	file *os.File
+	out  *bufio.Writer
+	// newExporter creates the exporter for an FTDC file. `first` is true for the first file
+	// rendered, such that a header is only written once.
+	newExporter func(w io.Writer, labels map[string]string, first bool) Exporter
+	rendered    bool
+}
+
+func newFileRenderer(
+	target string,
+	newExporter func(w io.Writer, labels map[string]string, first bool) Exporter,
+) (Renderer, error) {
+	if target == "" {
+		return nil, errors.New("a file to write to is required")
+	}
+	//nolint:gosec
+	file, err := os.Create(target)
+	if err != nil {
+		return nil, err
+	}
+	return &fileRenderer{file: file, out: bufio.NewWriter(file), newExporter: newExporter}, nil
+}
+
+// newCSVRenderer writes the datums to the target file as CSV. See `CSVExporter`.
+func newCSVRenderer(target string, _ logging.Logger) (Renderer, error) {
+	return newFileRenderer(target, func(w io.Writer, labels map[string]string, first bool) Exporter {
+		return newCSVExporter(w, labels, first)
+	})
+}
+
+// newJSONRenderer writes the datums to the target file as newline-delimited JSON. See
+// `JSONExporter`.
+func newJSONRenderer(target string, _ logging.Logger) (Renderer, error) {
+	return newFileRenderer(target, func(w io.Writer, labels map[string]string, _ bool) Exporter {
+		return NewJSONExporter(w, labels)
+	})
+}
+
+func (fr *fileRenderer) Render(ctx context.Context, label string, datums []FlatDatum) error {
+	var labels map[string]string
+	if label != "" {
+		labels = map[string]string{"robot": label}
+	}
+	exporter := fr.newExporter(fr.out, labels, !fr.rendered)
+	fr.rendered = true
+	for _, datum := range datums {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		exporter.Export(datum)
+	}
+	if err := exporter.Close(); err != nil {
+		return err
+	}
+	return fr.out.Flush()
+}
+
+func (fr *fileRenderer) Close() error {
+	flushErr := fr.out.Flush()
+	if err := fr.file.Close(); err != nil && flushErr == nil {
+		flushErr = err
+	}
+	return flushErr
+}
+
+// subprocessDatum is a datum as it is written to the stdin of a subprocess renderer.
+type subprocessDatum struct {
+	Label string `json:"label,omitempty"`
+	// Time is in nanoseconds since the epoch.
+	Time     int64              `json:"time"`
+	Readings map[string]float32 `json:"readings"`
+}
+
+// subprocessRenderer lets renderers be written in any language. The target is a command, which is
+// started with the renderer and is written every datum to its stdin as a line of JSON. E.g:
+//
+//	{"label":"robot1","time":1727200800000000000,"readings":{"proc.viam-server.UserCPUSecs":12.5}}
+//
+// The command's stdin is closed when the renderer is closed, after which the command is expected
+// to finish rendering and exit. Its stdout and stderr are those of the CLI.
+type subprocessRenderer struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	out   *bufio.Writer
+}
+
+func newSubprocessRenderer(target string, _ logging.Logger) (Renderer, error) {
+	args := strings.Fields(target)
+	if len(args) == 0 {
+		return nil, errors.New("a command to run is required")
+	}
+	//nolint:gosec
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "failed to start %q", args[0])
+	}
+	return &subprocessRenderer{cmd: cmd, stdin: stdin, out: bufio.NewWriter(stdin)}, nil
+}
+
+func (sr *subprocessRenderer) Render(ctx context.Context, label string, datums []FlatDatum) error {
+	encoder := json.NewEncoder(sr.out)
+	for _, datum := range datums {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		readings := make(map[string]float32, len(datum.Readings))
+		for _, reading := range datum.Readings {
+			readings[reading.MetricName] = reading.Value
+		}
+		if err := encoder.Encode(subprocessDatum{Label: label, Time: datum.Time, Readings: readings}); err != nil {
+			return err
+		}
+	}
+	return sr.out.Flush()
+}
+
+// Close closes the command's stdin and waits for it to exit.
+func (sr *subprocessRenderer) Close() error {
+	flushErr := sr.out.Flush()
+	if err := sr.stdin.Close(); err != nil && flushErr == nil {
+		flushErr = err
+	}
+	if err := sr.cmd.Wait(); err != nil {
+		return errors.Wrap(err, "renderer command failed")
+	}
+	return flushErr
+}
@@ -0,0 +1,141 @@
+package ftdc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+type recordingRenderer struct {
+	target   string
+	rendered map[string][]FlatDatum
+	closed   bool
+}
+
+func (rr *recordingRenderer) Render(ctx context.Context, label string, datums []FlatDatum) error {
+	rr.rendered[label] = datums
+	return nil
+}
+
+func (rr *recordingRenderer) Close() error {
+	rr.closed = true
+	return nil
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	test.That(t, RegisteredRenderers(), test.ShouldContain, "prometheus")
+	test.That(t, RegisteredRenderers(), test.ShouldContain, "exec")
+
+	var created *recordingRenderer
+	RegisterRenderer("recording", func(target string, logger logging.Logger) (Renderer, error) {
+		created = &recordingRenderer{target: target, rendered: map[string][]FlatDatum{}}
+		return created, nil
+	})
+	test.That(t, RegisteredRenderers(), test.ShouldContain, "recording")
+	test.That(t, func() {
+		RegisterRenderer("recording", func(string, logging.Logger) (Renderer, error) { return nil, nil })
+	}, test.ShouldPanicWith, `FTDC renderer "recording" is already registered`)
+
+	factory, ok := LookupRenderer("recording")
+	test.That(t, ok, test.ShouldBeTrue)
+	renderer, err := factory("dashboard", logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	datums := []FlatDatum{{Time: 1, Readings: []Reading{{"a", 1}}}}
+	test.That(t, renderer.Render(context.Background(), "robot1", datums), test.ShouldBeNil)
+	test.That(t, renderer.Close(), test.ShouldBeNil)
+	test.That(t, created.target, test.ShouldEqual, "dashboard")
+	test.That(t, created.rendered["robot1"], test.ShouldResemble, datums)
+	test.That(t, created.closed, test.ShouldBeTrue)
+
+	_, ok = LookupRenderer("missing")
+	test.That(t, ok, test.ShouldBeFalse)
+}
+
+func TestPrometheusRenderer(t *testing.T) {
+	rws := &remoteWriteServer{}
+	server := rws.start(t)
+
+	factory, ok := LookupRenderer("prometheus")
+	test.That(t, ok, test.ShouldBeTrue)
+	_, err := factory("", logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldNotBeNil)
+	renderer, err := factory(server.URL, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	datums := []FlatDatum{{Time: start.UnixNano(), Readings: []Reading{{"cpu", 1}}}}
+	test.That(t, renderer.Render(context.Background(), "robot1", datums), test.ShouldBeNil)
+	test.That(t, renderer.Render(context.Background(), "", datums), test.ShouldBeNil)
+	test.That(t, renderer.Close(), test.ShouldBeNil)
+
+	// The label of each FTDC file is added as a `robot` label.
+	test.That(t, rws.requests, test.ShouldHaveLength, 2)
+	test.That(t, rws.requests[0][0].labels, test.ShouldResemble, [][2]string{
+		{"__name__", "cpu"}, {"ftdc_metric", "cpu"}, {"robot", "robot1"},
+	})
+	test.That(t, rws.requests[1][0].labels, test.ShouldResemble, [][2]string{{"__name__", "cpu"}, {"ftdc_metric", "cpu"}})
+}
+
+func TestSubprocessRenderer(t *testing.T) {
+	outPath := filepath.Join(t.TempDir(), "out.jsonl")
+	factory, ok := LookupRenderer("exec")
+	test.That(t, ok, test.ShouldBeTrue)
+	// `dd` copies the datums written to stdin to the output file.
+	renderer, err := factory("dd status=none of="+outPath, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	datums := []FlatDatum{
+		{Time: start.UnixNano(), Readings: []Reading{{"proc.viam-server.UserCPUSecs", 1}, {"9lives", 5}}},
+		{Time: start.Add(time.Second).UnixNano(), Readings: []Reading{{"proc.viam-server.UserCPUSecs", 2}}},
+	}
+	test.That(t, renderer.Render(context.Background(), "robot1", datums), test.ShouldBeNil)
+	test.That(t, renderer.Render(context.Background(), "", datums[:1]), test.ShouldBeNil)
+	test.That(t, renderer.Close(), test.ShouldBeNil)
+
+	outFile, err := os.Open(outPath)
+	test.That(t, err, test.ShouldBeNil)
+	defer outFile.Close()
+	var written []map[string]interface{}
+	scanner := bufio.NewScanner(outFile)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		test.That(t, json.Unmarshal(scanner.Bytes(), &line), test.ShouldBeNil)
+		written = append(written, line)
+	}
+	test.That(t, written, test.ShouldResemble, []map[string]interface{}{
+		{
+			"label":    "robot1",
+			"time":     float64(start.UnixNano()),
+			"readings": map[string]interface{}{"proc.viam-server.UserCPUSecs": 1.0, "9lives": 5.0},
+		},
+		{
+			"label":    "robot1",
+			"time":     float64(start.Add(time.Second).UnixNano()),
+			"readings": map[string]interface{}{"proc.viam-server.UserCPUSecs": 2.0},
+		},
+		{
+			"time":     float64(start.UnixNano()),
+			"readings": map[string]interface{}{"proc.viam-server.UserCPUSecs": 1.0, "9lives": 5.0},
+		},
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := factory("", logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldBeError, "a command to run is required")
+		_, err = factory("/nonexistent/renderer", logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldNotBeNil)
+
+		renderer, err := factory("false", logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, renderer.Close(), test.ShouldNotBeNil)
+	})
+}
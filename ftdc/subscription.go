@@ -0,0 +1,135 @@
+package ftdc
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultSubscriptionBufferSize is how many datums a `Subscription` holds onto for a slow consumer
+// when none is requested. At one datum per second, this is a minute of data.
+const defaultSubscriptionBufferSize = 60
+
+// Subscription is a stream of the datums FTDC writes, for in-process consumers such as the web UI
+// or an alerting service. Datums are filtered down to the readings of the subscribed metrics. Create
+// one with `FTDC.Subscribe`.
+type Subscription struct {
+	prefixes []string
+	ftdc     *FTDC
+
+	// mu protects `closed` and sending on `datumCh`, such that a datum being exported concurrently
+	// with `Unsubscribe` is not sent on a closed channel.
+	mu      sync.Mutex
+	closed  bool
+	datumCh chan FlatDatum
+	dropped atomic.Int64
+}
+
+// Subscribe returns a new `Subscription` receiving every datum written from now on. Only readings
+// of metrics starting with one of `metricPrefixes` are included, or all of them if there are none.
+// Datums without any matching readings are skipped. Up to `bufferSize` datums are held for a
+// consumer that falls behind, after which newer datums are dropped rather than blocking FTDC.
+//
+// The subscription's channel is closed when `Unsubscribe` is called or FTDC is stopped.
+func (ftdc *FTDC) Subscribe(bufferSize int, metricPrefixes ...string) *Subscription {
+	if bufferSize <= 0 {
+		bufferSize = defaultSubscriptionBufferSize
+	}
+	sub := &Subscription{
+		prefixes: metricPrefixes,
+		ftdc:     ftdc,
+		datumCh:  make(chan FlatDatum, bufferSize),
+	}
+
+	ftdc.mu.Lock()
+	defer ftdc.mu.Unlock()
+	if ftdc.exportersClosed {
+		// FTDC was already stopped. There is nothing to stream.
+		//nolint:errcheck
+		sub.Close()
+		return sub
+	}
+	ftdc.exporters = append(ftdc.exporters, sub)
+	return sub
+}
+
+// C returns the channel datums are sent on.
+func (sub *Subscription) C() <-chan FlatDatum {
+	return sub.datumCh
+}
+
+// Dropped returns how many datums were dropped because the consumer was not keeping up.
+func (sub *Subscription) Dropped() int64 {
+	return sub.dropped.Load()
+}
+
+// Unsubscribe stops datums from being sent to the subscription and closes its channel. It is safe
+// to call more than once.
+func (sub *Subscription) Unsubscribe() {
+	sub.ftdc.removeExporter(sub)
+	//nolint:errcheck
+	sub.Close()
+}
+
+// Export sends the datum's readings of subscribed metrics to the subscription. It is called by
+// FTDC for every datum written.
+func (sub *Subscription) Export(datum FlatDatum) {
+	if len(sub.prefixes) > 0 {
+		readings := make([]Reading, 0, len(datum.Readings))
+		for _, reading := range datum.Readings {
+			if hasAnyPrefix(reading.MetricName, sub.prefixes) {
+				readings = append(readings, reading)
+			}
+		}
+		if len(readings) == 0 {
+			return
+		}
+		datum = FlatDatum{Time: datum.Time, Readings: readings}
+	}
+
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if sub.closed {
+		return
+	}
+	select {
+	case sub.datumCh <- datum:
+	default:
+		sub.dropped.Add(1)
+	}
+}
+
+// Close closes the subscription's channel. FTDC calls this when it is stopped. Consumers should
+// call `Unsubscribe` instead.
+func (sub *Subscription) Close() error {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if !sub.closed {
+		sub.closed = true
+		close(sub.datumCh)
+	}
+	return nil
+}
+
+func (ftdc *FTDC) removeExporter(toRemove Exporter) {
+	ftdc.mu.Lock()
+	defer ftdc.mu.Unlock()
+	// `writeDatum` iterates over `exporters` without holding the lock. Build a new slice rather than
+	// modifying the shared one in place.
+	exporters := make([]Exporter, 0, len(ftdc.exporters))
+	for _, exporter := range ftdc.exporters {
+		if exporter != toRemove {
+			exporters = append(exporters, exporter)
+		}
+	}
+	ftdc.exporters = exporters
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package ftdc
+
+import (
+	"bytes"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestSubscribe(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ftdc := NewWithWriter(bytes.NewBuffer(nil), logger.Sublogger("ftdc"))
+	statser := foo{x: 1, y: 2}
+	ftdc.Add("foo", &statser)
+
+	all := ftdc.Subscribe(10)
+	// A buffer of two datums, to assert datums are dropped rather than blocking FTDC.
+	onlyX := ftdc.Subscribe(2, "foo.X")
+	none := ftdc.Subscribe(10, "bar")
+	for x := range 3 {
+		statser.x = x
+		test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+	}
+
+	test.That(t, all.C(), test.ShouldHaveLength, 3)
+	test.That(t, all.Dropped(), test.ShouldEqual, 0)
+	datum := <-all.C()
+	test.That(t, datum.Readings, test.ShouldResemble, []Reading{{"foo.X", 0}, {"foo.Y", 2}})
+
+	test.That(t, onlyX.C(), test.ShouldHaveLength, 2)
+	test.That(t, onlyX.Dropped(), test.ShouldEqual, 1)
+	datum = <-onlyX.C()
+	test.That(t, datum.Readings, test.ShouldResemble, []Reading{{"foo.X", 0}})
+	datum = <-onlyX.C()
+	test.That(t, datum.Readings, test.ShouldResemble, []Reading{{"foo.X", 1}})
+
+	test.That(t, none.C(), test.ShouldHaveLength, 0)
+
+	// Unsubscribing closes the channel once the remaining datums are consumed, and no more datums
+	// are sent.
+	all.Unsubscribe()
+	all.Unsubscribe()
+	test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+	var received int
+	for range all.C() {
+		received++
+	}
+	test.That(t, received, test.ShouldEqual, 2)
+	test.That(t, onlyX.C(), test.ShouldHaveLength, 1)
+
+	// Subscribing after FTDC is stopped returns a closed subscription.
+	ftdc.mu.Lock()
+	ftdc.exportersClosed = true
+	ftdc.mu.Unlock()
+	_, open := <-ftdc.Subscribe(10).C()
+	test.That(t, open, test.ShouldBeFalse)
+}
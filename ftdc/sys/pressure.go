@@ -0,0 +1,86 @@
+package sys
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/procfs"
+	"github.com/prometheus/procfs/blockdevice"
+
+	"go.viam.com/rdk/ftdc"
+)
+
+// SystemPressure reads how busy the machine's CPUs and disks are. It implements
+// `ftdc.PressureReader`.
+type SystemPressure struct {
+	procFS  procfs.FS
+	blockFS blockdevice.FS
+
+	prevCPU       procfs.CPUStat
+	prevDiskTicks map[string]uint64
+	prevTime      time.Time
+}
+
+// NewSystemPressure returns a `SystemPressure` reading from `/proc`. The first pressure reading
+// covers the time since it was created.
+func NewSystemPressure() (*SystemPressure, error) {
+	procFS, err := procfs.NewDefaultFS()
+	if err != nil {
+		return nil, err
+	}
+	blockFS, err := blockdevice.NewDefaultFS()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &SystemPressure{procFS: procFS, blockFS: blockFS}
+	if _, err := ret.Pressure(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Pressure returns how busy the CPUs and the busiest disk were since the last call.
+func (sp *SystemPressure) Pressure() (ftdc.Pressure, error) {
+	stat, err := sp.procFS.Stat()
+	if err != nil {
+		return ftdc.Pressure{}, err
+	}
+	diskStats, err := sp.blockFS.ProcDiskstats()
+	if err != nil {
+		return ftdc.Pressure{}, err
+	}
+	now := time.Now()
+
+	var ret ftdc.Pressure
+	// I/O wait is counted as idle CPU time. Time waiting on disks is accounted for by the disk
+	// pressure.
+	busy := cpuBusySecs(stat.CPUTotal) - cpuBusySecs(sp.prevCPU)
+	total := busy + (stat.CPUTotal.Idle + stat.CPUTotal.Iowait) - (sp.prevCPU.Idle + sp.prevCPU.Iowait)
+	if total > 0 {
+		ret.CPU = busy / total
+	}
+
+	diskTicks := make(map[string]uint64, len(diskStats))
+	elapsedMs := float64(now.Sub(sp.prevTime).Milliseconds())
+	for _, disk := range diskStats {
+		// Loop and RAM devices are not backed by a physical disk.
+		if strings.HasPrefix(disk.DeviceName, "loop") || strings.HasPrefix(disk.DeviceName, "ram") ||
+			strings.HasPrefix(disk.DeviceName, "zram") {
+			continue
+		}
+		diskTicks[disk.DeviceName] = disk.IOsTotalTicks
+		prevTicks, exists := sp.prevDiskTicks[disk.DeviceName]
+		if !exists || elapsedMs <= 0 || disk.IOsTotalTicks < prevTicks {
+			continue
+		}
+		ret.Disk = max(ret.Disk, min(1, float64(disk.IOsTotalTicks-prevTicks)/elapsedMs))
+	}
+
+	sp.prevCPU, sp.prevDiskTicks, sp.prevTime = stat.CPUTotal, diskTicks, now
+	return ret, nil
+}
+
+func cpuBusySecs(cpu procfs.CPUStat) float64 {
+	return cpu.User + cpu.Nice + cpu.System + cpu.IRQ + cpu.SoftIRQ + cpu.Steal
+}
@@ -0,0 +1,44 @@
+// Package h265 contains the H.265 video codec. There is no software H.265 encoder; the codec
+// streams frames that were already encoded, such as by a camera's hardware encoder.
+package h265
+
+import (
+	"context"
+	"fmt"
+	"image"
+
+	"go.viam.com/rdk/gostream/codec"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/utils"
+)
+
+type encoder struct {
+	logger logging.Logger
+}
+
+// NewEncoder returns an H.265 passthrough encoder. It only accepts frames that are already H.265
+// encoded and returns them as is.
+func NewEncoder(width, height, keyFrameInterval int, logger logging.Logger) (codec.VideoEncoder, error) {
+	return &encoder{logger: logger}, nil
+}
+
+// Encode returns the encoded bytes of an H.265 frame.
+func (v *encoder) Encode(_ context.Context, img image.Image) ([]byte, error) {
+	switch frame := img.(type) {
+	case rimage.H265:
+		return frame.Bytes, nil
+	case *rimage.LazyEncodedImage:
+		if frame.MIMEType() == utils.MimeTypeH265 {
+			return frame.RawData(), nil
+		}
+		return nil, fmt.Errorf("h265 encoder cannot encode %q frames; the source must produce H.265 frames", frame.MIMEType())
+	default:
+		return nil, fmt.Errorf("h265 encoder cannot encode %T frames; the source must produce H.265 frames", img)
+	}
+}
+
+// Close closes the encoder.
+func (v *encoder) Close() error {
+	return nil
+}
@@ -0,0 +1,36 @@
+package h265
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/utils"
+)
+
+func TestEncoderPassthrough(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	enc, err := NewEncoderFactory().New(640, 480, 30, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer enc.Close()
+
+	frame := []byte{0, 0, 0, 1, 0x26, 0x01, 0xaf}
+	encoded, err := enc.Encode(context.Background(), rimage.NewLazyEncodedImage(frame, utils.MimeTypeH265))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, encoded, test.ShouldResemble, frame)
+
+	encoded, err = enc.Encode(context.Background(), rimage.NewH265Image(frame, 640, 480, 30))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, encoded, test.ShouldResemble, frame)
+
+	_, err = enc.Encode(context.Background(), rimage.NewLazyEncodedImage(frame, utils.MimeTypeH264))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must produce H.265 frames")
+
+	_, err = enc.Encode(context.Background(), image.NewRGBA(image.Rect(0, 0, 2, 2)))
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -26,3 +26,16 @@ type VideoEncoderFactory interface {
 	New(height, width, keyFrameInterval int, logger logging.Logger) (VideoEncoder, error)
 	MIMEType() string
 }
+
+// A BitrateVideoEncoderFactory is a VideoEncoderFactory whose encoders can target a given bitrate.
+// Streams use it to lower the bitrate of their video when peers report congestion.
+type BitrateVideoEncoderFactory interface {
+	VideoEncoderFactory
+
+	// DefaultBitrate returns the bitrate, in bits per second, that encoders made by New target for
+	// frames of the given size.
+	DefaultBitrate(width, height, keyFrameInterval int) int
+
+	// NewWithBitrate returns an encoder that targets the given bitrate in bits per second.
+	NewWithBitrate(width, height, keyFrameInterval, bitrate int, logger logging.Logger) (VideoEncoder, error)
+}
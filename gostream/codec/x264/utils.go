@@ -37,6 +37,14 @@ func (f *factory) New(width, height, keyFrameInterval int, logger logging.Logger
 	return NewEncoder(width, height, keyFrameInterval, logger)
 }
 
+func (f *factory) DefaultBitrate(width, height, keyFrameInterval int) int {
+	return calcBitrateFromResolution(width, height, float32(keyFrameInterval))
+}
+
+func (f *factory) NewWithBitrate(width, height, keyFrameInterval, bitrate int, logger logging.Logger) (codec.VideoEncoder, error) {
+	return NewEncoderWithBitrate(width, height, keyFrameInterval, bitrate, logger)
+}
+
 func (f *factory) MIMEType() string {
 	return "video/H264"
 }
@@ -46,6 +54,11 @@ func calcBitrateFromResolution(width, height int, framerate float32) int {
 	bitrate := float32(width) * float32(height) * framerate * encodeCompressionRatio
 	// Round up to the nearest integer value.
 	bitrate = float32(math.Ceil(float64(bitrate)))
+	return clampBitrate(int(bitrate))
+}
+
+// clampBitrate keeps a bitrate between the minimum and maximum bitrates the encoder is used with.
+func clampBitrate(bitrate int) int {
 	// This accounts for zero bitrates too.
 	if bitrate < minBitrate {
 		return minBitrate
@@ -53,5 +66,5 @@ func calcBitrateFromResolution(width, height int, framerate float32) int {
 	if bitrate > maxBitrate {
 		return maxBitrate
 	}
-	return int(bitrate)
+	return bitrate
 }
@@ -0,0 +1,127 @@
+package gostream
+
+import (
+	"math"
+	"sync"
+
+	"github.com/pion/rtcp"
+)
+
+const (
+	// Peers losing more than congestionLossHigh of their packets are sent less video, and peers
+	// losing less than congestionLossLow are sent more, as in the loss-based controller of Google
+	// Congestion Control (https://datatracker.ietf.org/doc/html/draft-ietf-rmcat-gcc-02#section-6).
+	congestionLossHigh = 0.10
+	congestionLossLow  = 0.02
+	// congestionIncrease is how much a peer's estimate grows per receiver report without loss.
+	congestionIncrease = 1.08
+	// congestionMinBitrate is the lowest bitrate a peer's estimate is lowered to.
+	congestionMinBitrate = 100_000
+)
+
+// peerCongestion is what is known about the bitrate a peer can receive.
+type peerCongestion struct {
+	// lossBitrate is estimated from the packet loss the peer reports.
+	lossBitrate float64
+	// remoteBitrate is the maximum bitrate the peer estimates it can receive itself, or zero if it
+	// has not said.
+	remoteBitrate float64
+}
+
+func (pc peerCongestion) bitrate() float64 {
+	if pc.remoteBitrate != 0 {
+		return math.Min(pc.lossBitrate, pc.remoteBitrate)
+	}
+	return pc.lossBitrate
+}
+
+// A congestionController estimates the bitrate each peer receiving a stream can receive from the
+// RTCP feedback it sends. Peers share the stream's encoder, so the stream is encoded at the bitrate
+// the most congested peer can keep up with.
+type congestionController struct {
+	mu sync.Mutex
+	// maxBitrate is the bitrate the stream is encoded at when no peer is congested.
+	maxBitrate float64
+	peers      map[string]*peerCongestion
+}
+
+func newCongestionController() *congestionController {
+	return &congestionController{peers: map[string]*peerCongestion{}}
+}
+
+// setMaxBitrate sets the bitrate the stream is encoded at without congestion. Estimates above it
+// are lowered to it.
+func (cc *congestionController) setMaxBitrate(bitrate int) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.maxBitrate = float64(bitrate)
+	for _, peer := range cc.peers {
+		peer.lossBitrate = math.Min(peer.lossBitrate, cc.maxBitrate)
+	}
+}
+
+// observe updates the estimate for a peer from the RTCP packets it sent about the stream's video,
+// which is sent to the peer with the given SSRC.
+func (cc *congestionController) observe(peerID string, ssrc uint32, pkts []rtcp.Packet) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.maxBitrate == 0 {
+		return
+	}
+	peer, ok := cc.peers[peerID]
+	if !ok {
+		peer = &peerCongestion{lossBitrate: cc.maxBitrate}
+		cc.peers[peerID] = peer
+	}
+	for _, pkt := range pkts {
+		switch pkt := pkt.(type) {
+		case *rtcp.ReceiverReport:
+			for _, report := range pkt.Reports {
+				if report.SSRC == ssrc {
+					cc.observeLoss(peer, float64(report.FractionLost)/256)
+				}
+			}
+		case *rtcp.SenderReport:
+			for _, report := range pkt.Reports {
+				if report.SSRC == ssrc {
+					cc.observeLoss(peer, float64(report.FractionLost)/256)
+				}
+			}
+		case *rtcp.ReceiverEstimatedMaximumBitrate:
+			for _, rembSSRC := range pkt.SSRCs {
+				if rembSSRC == ssrc {
+					peer.remoteBitrate = float64(pkt.Bitrate)
+				}
+			}
+		}
+	}
+}
+
+func (cc *congestionController) observeLoss(peer *peerCongestion, fractionLost float64) {
+	switch {
+	case fractionLost > congestionLossHigh:
+		peer.lossBitrate *= 1 - 0.5*fractionLost
+	case fractionLost < congestionLossLow:
+		peer.lossBitrate *= congestionIncrease
+	}
+	peer.lossBitrate = math.Max(congestionMinBitrate, math.Min(peer.lossBitrate, cc.maxBitrate))
+}
+
+// removePeer forgets a peer that no longer receives the stream.
+func (cc *congestionController) removePeer(peerID string) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	delete(cc.peers, peerID)
+}
+
+// targetBitrate returns the bitrate every peer can receive, in bits per second. It returns zero if
+// no maximum bitrate has been set.
+func (cc *congestionController) targetBitrate() int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	target := cc.maxBitrate
+	for _, peer := range cc.peers {
+		target = math.Min(target, peer.bitrate())
+	}
+	return int(target)
+}
@@ -0,0 +1,54 @@
+package gostream
+
+import (
+	"testing"
+
+	"github.com/pion/rtcp"
+	"go.viam.com/test"
+)
+
+func TestCongestionController(t *testing.T) {
+	const ssrc = 1234
+	receiverReport := func(ssrc uint32, fractionLost float64) []rtcp.Packet {
+		return []rtcp.Packet{&rtcp.ReceiverReport{Reports: []rtcp.ReceptionReport{
+			{SSRC: ssrc, FractionLost: uint8(fractionLost * 256)},
+		}}}
+	}
+
+	cc := newCongestionController()
+	cc.observe("peer1", ssrc, receiverReport(ssrc, 0.5))
+	test.That(t, cc.targetBitrate(), test.ShouldEqual, 0)
+
+	cc.setMaxBitrate(1_000_000)
+	test.That(t, cc.targetBitrate(), test.ShouldEqual, 1_000_000)
+
+	// Heavy loss lowers the estimate, loss in between holds it, and little loss raises it again.
+	cc.observe("peer1", ssrc, receiverReport(ssrc, 0.5))
+	test.That(t, cc.targetBitrate(), test.ShouldEqual, 750_000)
+	cc.observe("peer1", ssrc, receiverReport(ssrc, 0.05))
+	test.That(t, cc.targetBitrate(), test.ShouldEqual, 750_000)
+	cc.observe("peer1", ssrc, receiverReport(ssrc, 0))
+	test.That(t, cc.targetBitrate(), test.ShouldEqual, 810_000)
+
+	// Reports about other streams are ignored.
+	cc.observe("peer1", ssrc, receiverReport(ssrc+1, 0.9))
+	test.That(t, cc.targetBitrate(), test.ShouldEqual, 810_000)
+
+	// Estimates never go above the maximum or below the minimum.
+	for i := 0; i < 10; i++ {
+		cc.observe("peer1", ssrc, receiverReport(ssrc, 0))
+	}
+	test.That(t, cc.targetBitrate(), test.ShouldEqual, 1_000_000)
+	for i := 0; i < 100; i++ {
+		cc.observe("peer1", ssrc, receiverReport(ssrc, 0.9))
+	}
+	test.That(t, cc.targetBitrate(), test.ShouldEqual, congestionMinBitrate)
+
+	// The most congested peer, including by its own estimate, decides the target.
+	cc.observe("peer2", ssrc, []rtcp.Packet{&rtcp.ReceiverEstimatedMaximumBitrate{Bitrate: 400_000, SSRCs: []uint32{ssrc}}})
+	test.That(t, cc.targetBitrate(), test.ShouldEqual, congestionMinBitrate)
+	cc.removePeer("peer1")
+	test.That(t, cc.targetBitrate(), test.ShouldEqual, 400_000)
+	cc.removePeer("peer2")
+	test.That(t, cc.targetBitrate(), test.ShouldEqual, 1_000_000)
+}
@@ -0,0 +1,92 @@
+package gostream
+
+import "bytes"
+
+// H.265 RTP payload constants from RFC 7798.
+const (
+	h265NALUHeaderSize = 2
+	h265FUHeaderSize   = 1
+	h265NALUTypeFU     = 49
+	h265FUStartBit     = 0x80
+	h265FUEndBit       = 0x40
+)
+
+// h265Payloader payloads Annex B H.265 access units into RTP payloads as described by RFC 7798.
+// NAL units that fit within the MTU are sent as single NAL unit packets and larger ones are split
+// into fragmentation units. Aggregation packets are not produced.
+type h265Payloader struct{}
+
+// Payload splits an Annex B access unit into RTP payloads of at most mtu bytes.
+func (p *h265Payloader) Payload(mtu uint16, payload []byte) [][]byte {
+	var payloads [][]byte
+	if mtu <= h265NALUHeaderSize+h265FUHeaderSize {
+		return payloads
+	}
+	for _, nalu := range splitAnnexB(payload) {
+		if len(nalu) <= h265NALUHeaderSize {
+			continue
+		}
+		if len(nalu) <= int(mtu) {
+			out := make([]byte, len(nalu))
+			copy(out, nalu)
+			payloads = append(payloads, out)
+			continue
+		}
+
+		naluType := (nalu[0] >> 1) & 0x3f
+		// The payload header keeps the F, LayerId and TID fields of the NAL unit header and
+		// replaces its type with the fragmentation unit type.
+		payloadHeader := [h265NALUHeaderSize]byte{
+			(nalu[0] & 0x81) | (h265NALUTypeFU << 1),
+			nalu[1],
+		}
+		data := nalu[h265NALUHeaderSize:]
+		maxFragment := int(mtu) - h265NALUHeaderSize - h265FUHeaderSize
+		for offset := 0; offset < len(data); offset += maxFragment {
+			end := offset + maxFragment
+			if end > len(data) {
+				end = len(data)
+			}
+			fuHeader := naluType
+			if offset == 0 {
+				fuHeader |= h265FUStartBit
+			}
+			if end == len(data) {
+				fuHeader |= h265FUEndBit
+			}
+			out := make([]byte, 0, h265NALUHeaderSize+h265FUHeaderSize+end-offset)
+			out = append(out, payloadHeader[:]...)
+			out = append(out, fuHeader)
+			out = append(out, data[offset:end]...)
+			payloads = append(payloads, out)
+		}
+	}
+	return payloads
+}
+
+// splitAnnexB returns the NAL units of an Annex B byte stream, without their start codes. A
+// payload without start codes is treated as a single NAL unit.
+func splitAnnexB(payload []byte) [][]byte {
+	startCode := []byte{0, 0, 1}
+	first := bytes.Index(payload, startCode)
+	if first == -1 {
+		return [][]byte{payload}
+	}
+
+	var nalus [][]byte
+	rest := payload[first+len(startCode):]
+	for {
+		next := bytes.Index(rest, startCode)
+		if next == -1 {
+			nalus = append(nalus, rest)
+			return nalus
+		}
+		nalu := rest[:next]
+		// a four byte start code leaves a trailing zero on the previous NAL unit
+		nalu = bytes.TrimRight(nalu, "\x00")
+		if len(nalu) > 0 {
+			nalus = append(nalus, nalu)
+		}
+		rest = rest[next+len(startCode):]
+	}
+}
@@ -0,0 +1,47 @@
+package gostream
+
+import (
+	"bytes"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestH265Payloader(t *testing.T) {
+	// VPS (type 32) with a four byte start code followed by a large IDR slice (type 19).
+	vps := []byte{0x40, 0x01, 0x0c, 0x01}
+	idr := append([]byte{0x26, 0x01}, bytes.Repeat([]byte{0xab}, 250)...)
+	var stream []byte
+	stream = append(stream, 0, 0, 0, 1)
+	stream = append(stream, vps...)
+	stream = append(stream, 0, 0, 1)
+	stream = append(stream, idr...)
+
+	p := &h265Payloader{}
+	payloads := p.Payload(100, stream)
+	test.That(t, len(payloads), test.ShouldEqual, 4)
+
+	// the VPS fits in a single NAL unit packet
+	test.That(t, payloads[0], test.ShouldResemble, vps)
+
+	// the IDR slice is split into fragmentation units
+	var reassembled []byte
+	for i, fu := range payloads[1:] {
+		test.That(t, len(fu), test.ShouldBeLessThanOrEqualTo, 100)
+		test.That(t, (fu[0]>>1)&0x3f, test.ShouldEqual, h265NALUTypeFU)
+		test.That(t, fu[1], test.ShouldEqual, idr[1])
+		test.That(t, fu[2]&0x3f, test.ShouldEqual, 19)
+		test.That(t, fu[2]&h265FUStartBit != 0, test.ShouldEqual, i == 0)
+		test.That(t, fu[2]&h265FUEndBit != 0, test.ShouldEqual, i == 2)
+		reassembled = append(reassembled, fu[3:]...)
+	}
+	test.That(t, reassembled, test.ShouldResemble, idr[2:])
+
+	// an MTU too small to hold a fragmentation unit produces nothing
+	test.That(t, p.Payload(3, stream), test.ShouldBeEmpty)
+}
+
+func TestSplitAnnexB(t *testing.T) {
+	test.That(t, splitAnnexB([]byte{1, 2, 3}), test.ShouldResemble, [][]byte{{1, 2, 3}})
+	test.That(t, splitAnnexB([]byte{0, 0, 1, 1, 2, 0, 0, 0, 1, 3, 4}), test.ShouldResemble, [][]byte{{1, 2}, {3, 4}})
+}
@@ -6,9 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"image"
+	"math"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/disintegration/imaging"
 	"github.com/google/uuid"
 	"github.com/pion/mediadevices/pkg/prop"
 	"github.com/pion/mediadevices/pkg/wave"
@@ -24,6 +27,14 @@ import (
 
 const (
 	defaultTargetFrameRate = 20
+	// adaptEncoderInterval is how often the video encoder may be replaced to follow the bitrate
+	// peers can receive. Replacing it sends a key frame, so it should not be done often.
+	adaptEncoderInterval = 2 * time.Second
+	// adaptBitrateThreshold is how much the bitrate peers can receive must change by before the
+	// video encoder is replaced.
+	adaptBitrateThreshold = 0.2
+	// adaptMinWidth is the narrowest video is scaled down to under congestion.
+	adaptMinWidth = 160
 )
 
 // A Stream is sink that accepts any image frames for the purpose
@@ -46,6 +57,11 @@ type Stream interface {
 
 	InputAudioChunks(props prop.Audio) (chan<- MediaReleasePair[wave.Audio], error)
 
+	// AdaptToSender reads the RTCP feedback a peer sends to the given sender of the stream's video
+	// track, until the sender is stopped. When the video encoder supports it, the video is encoded
+	// at a bitrate and resolution that the most congested peer can receive.
+	AdaptToSender(sender *webrtc.RTPSender)
+
 	// Stop stops further processing of frames.
 	Stop()
 }
@@ -110,6 +126,8 @@ func NewStream(config StreamConfig, logger logging.Logger) (Stream, error) {
 		inputAudioChan:  make(chan MediaReleasePair[wave.Audio]),
 		outputAudioChan: make(chan []byte),
 
+		congestion: newCongestionController(),
+
 		logger:            logger,
 		shutdownCtx:       ctx,
 		shutdownCtxCancel: cancelFunc,
@@ -130,6 +148,14 @@ type basicStream struct {
 	outputVideoChan chan []byte
 	videoEncoder    codec.VideoEncoder
 
+	// congestion picks the bitrate video is encoded at from peers' feedback. Frames are scaled to
+	// encodeWidth by encodeHeight before encoding, at encodeBitrate, when the encoder supports
+	// choosing a bitrate.
+	congestion                *congestionController
+	encodeWidth, encodeHeight int
+	encodeBitrate             int
+	lastAdapted               time.Time
+
 	audioTrackLocal *trackLocalStaticSample
 	inputAudioChan  chan MediaReleasePair[wave.Audio]
 	outputAudioChan chan []byte
@@ -229,6 +255,27 @@ func (bs *basicStream) InputAudioChunks(props prop.Audio) (chan<- MediaReleasePa
 	return bs.inputAudioChan, nil
 }
 
+func (bs *basicStream) AdaptToSender(sender *webrtc.RTPSender) {
+	if bs.videoTrackLocal == nil || sender.Track() != bs.videoTrackLocal {
+		return
+	}
+	peerID := fmt.Sprintf("%p", sender)
+	var ssrc uint32
+	if encodings := sender.GetParameters().Encodings; len(encodings) != 0 {
+		ssrc = uint32(encodings[0].SSRC)
+	}
+	utils.PanicCapturingGo(func() {
+		defer bs.congestion.removePeer(peerID)
+		for {
+			pkts, _, err := sender.ReadRTCP()
+			if err != nil {
+				return
+			}
+			bs.congestion.observe(peerID, ssrc, pkts)
+		}
+	})
+}
+
 func (bs *basicStream) VideoTrackLocal() (webrtc.TrackLocal, bool) {
 	return bs.videoTrackLocal, bs.videoTrackLocal != nil
 }
@@ -271,7 +318,7 @@ func (bs *basicStream) processInputFrames() {
 
 			var encodedFrame []byte
 
-			if frame, ok := framePair.Media.(*rimage.LazyEncodedImage); ok && frame.MIMEType() == utils2.MimeTypeH264 {
+			if frame, ok := framePair.Media.(*rimage.LazyEncodedImage); ok && bs.isPreEncoded(frame) {
 				encodedFrame = frame.RawData() // nothing to do; already encoded
 			} else {
 				var bounds image.Rectangle
@@ -309,11 +356,20 @@ func (bs *basicStream) processInputFrames() {
 						initErr = true
 						return
 					}
+				} else if err := bs.adaptVideoCodec(dx, dy); err != nil {
+					bs.logger.Error(err)
+					initErr = true
+					return
+				}
+
+				frame := framePair.Media
+				if bs.encodeWidth != dx || bs.encodeHeight != dy {
+					frame = imaging.Resize(frame, bs.encodeWidth, bs.encodeHeight, imaging.Linear)
 				}
 
 				// thread-safe because the size is static
 				var err error
-				encodedFrame, err = bs.videoEncoder.Encode(bs.shutdownCtx, framePair.Media)
+				encodedFrame, err = bs.videoEncoder.Encode(bs.shutdownCtx, frame)
 				if err != nil {
 					bs.logger.Error(err)
 					return
@@ -429,12 +485,73 @@ func (bs *basicStream) processOutputAudioChunks() {
 	}
 }
 
+// isPreEncoded returns whether the frame is already encoded with the stream's video codec, in which
+// case it can be sent without re-encoding. This is how cameras with hardware H.264 or H.265
+// encoders stream.
+func (bs *basicStream) isPreEncoded(frame *rimage.LazyEncodedImage) bool {
+	mimeType := frame.MIMEType()
+	if mimeType != utils2.MimeTypeH264 && mimeType != utils2.MimeTypeH265 {
+		return false
+	}
+	return strings.EqualFold(mimeType, bs.config.VideoEncoderFactory.MIMEType())
+}
+
 func (bs *basicStream) initVideoCodec(width, height int) error {
 	var err error
 	bs.videoEncoder, err = bs.config.VideoEncoderFactory.New(width, height, bs.config.TargetFrameRate, bs.logger)
+	bs.encodeWidth, bs.encodeHeight = width, height
+	if factory, ok := bs.config.VideoEncoderFactory.(codec.BitrateVideoEncoderFactory); ok {
+		bs.encodeBitrate = factory.DefaultBitrate(width, height, bs.config.TargetFrameRate)
+		bs.congestion.setMaxBitrate(bs.encodeBitrate)
+	}
+	bs.lastAdapted = time.Now()
 	return err
 }
 
+// adaptVideoCodec replaces the video encoder when the bitrate peers can receive has changed
+// enough since it was made. Video is scaled down, halving its size up to twice, when the bitrate
+// is much lower than the encoder would use for frames of their full size.
+func (bs *basicStream) adaptVideoCodec(width, height int) error {
+	factory, ok := bs.config.VideoEncoderFactory.(codec.BitrateVideoEncoderFactory)
+	if !ok || time.Since(bs.lastAdapted) < adaptEncoderInterval {
+		return nil
+	}
+	target := bs.congestion.targetBitrate()
+	if target == 0 {
+		return nil
+	}
+
+	frameRate := bs.config.TargetFrameRate
+	encodeWidth, encodeHeight := width, height
+	for i := 0; i < 2; i++ {
+		if target*2 >= factory.DefaultBitrate(encodeWidth, encodeHeight, frameRate) || encodeWidth/2 < adaptMinWidth {
+			break
+		}
+		// Encoders need even dimensions.
+		encodeWidth, encodeHeight = encodeWidth/4*2, encodeHeight/4*2
+	}
+	bitrate := min(target, factory.DefaultBitrate(encodeWidth, encodeHeight, frameRate))
+
+	sameSize := encodeWidth == bs.encodeWidth && encodeHeight == bs.encodeHeight
+	change := math.Abs(float64(bitrate-bs.encodeBitrate)) / float64(bs.encodeBitrate)
+	if sameSize && change < adaptBitrateThreshold {
+		return nil
+	}
+
+	bs.lastAdapted = time.Now()
+	encoder, err := factory.NewWithBitrate(encodeWidth, encodeHeight, frameRate, bitrate, bs.logger)
+	if err != nil {
+		return err
+	}
+	if err := bs.videoEncoder.Close(); err != nil {
+		bs.logger.Error(err)
+	}
+	bs.logger.Infow("adapting video to congestion", "width", encodeWidth, "height", encodeHeight, "bitrate", bitrate)
+	bs.videoEncoder = encoder
+	bs.encodeWidth, bs.encodeHeight, bs.encodeBitrate = encodeWidth, encodeHeight, bitrate
+	return nil
+}
+
 func (bs *basicStream) initAudioCodec(sampleRate, channelCount int) error {
 	var err error
 	if bs.audioEncoder != nil {
@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/golang/snappy"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// The names of the compressors gRPC messages can be compressed with.
+const (
+	CompressionGzip   = gzip.Name
+	CompressionSnappy = "snappy"
+)
+
+func init() {
+	encoding.RegisterCompressor(snappyCompressor{})
+}
+
+// ValidateCompression returns an error if gRPC messages cannot be compressed with the named
+// compressor. An empty name, for no compression, is valid.
+func ValidateCompression(name string) error {
+	switch name {
+	case "", CompressionGzip, CompressionSnappy:
+		return nil
+	default:
+		return fmt.Errorf("unknown gRPC compression %q, must be one of %q or %q", name, CompressionGzip, CompressionSnappy)
+	}
+}
+
+// CompressionUnaryServerInterceptor compresses responses with the named compressor when the client
+// supports it. Requests are decompressed with whichever compressor the client used regardless.
+func CompressionUnaryServerInterceptor(name string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		setSendCompressor(ctx, name)
+		return handler(ctx, req)
+	}
+}
+
+// CompressionStreamServerInterceptor compresses streamed responses with the named compressor when
+// the client supports it.
+func CompressionStreamServerInterceptor(name string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		setSendCompressor(ss.Context(), name)
+		return handler(srv, ss)
+	}
+}
+
+func setSendCompressor(ctx context.Context, name string) {
+	// Requests over WebRTC are not gRPC transport streams and have no compressors.
+	supported, err := grpc.ClientSupportedCompressors(ctx)
+	if err != nil || !slices.Contains(supported, name) {
+		return
+	}
+	//nolint:errcheck
+	_ = grpc.SetSendCompressor(ctx, name)
+}
+
+// CompressionDialOptions returns the options to compress the requests of a connection with the
+// named compressor. Only requests sent directly over gRPC, rather than over WebRTC, are compressed.
+func CompressionDialOptions(name string) []rpc.DialOption {
+	return []rpc.DialOption{
+		rpc.WithUnaryClientInterceptor(func(
+			ctx context.Context,
+			method string, req, reply interface{},
+			cc *grpc.ClientConn,
+			invoker grpc.UnaryInvoker,
+			opts ...grpc.CallOption,
+		) error {
+			return invoker(ctx, method, req, reply, cc, append(opts, grpc.UseCompressor(name))...)
+		}),
+		rpc.WithStreamClientInterceptor(func(
+			ctx context.Context,
+			desc *grpc.StreamDesc,
+			cc *grpc.ClientConn,
+			method string,
+			streamer grpc.Streamer,
+			opts ...grpc.CallOption,
+		) (grpc.ClientStream, error) {
+			return streamer(ctx, desc, cc, method, append(opts, grpc.UseCompressor(name))...)
+		}),
+	}
+}
+
+// snappyCompressor compresses gRPC messages with the snappy framing format. It is faster than
+// gzip, at the cost of compressing less.
+type snappyCompressor struct{}
+
+func (snappyCompressor) Name() string {
+	return CompressionSnappy
+}
+
+func (snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+func (snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestValidateCompression(t *testing.T) {
+	test.That(t, ValidateCompression(""), test.ShouldBeNil)
+	test.That(t, ValidateCompression(CompressionGzip), test.ShouldBeNil)
+	test.That(t, ValidateCompression(CompressionSnappy), test.ShouldBeNil)
+	test.That(t, ValidateCompression("zstd"), test.ShouldNotBeNil)
+}
+
+func TestCompressorsRegistered(t *testing.T) {
+	payload := bytes.Repeat([]byte("point cloud "), 1000)
+	for _, name := range []string{CompressionGzip, CompressionSnappy} {
+		t.Run(name, func(t *testing.T) {
+			compressor := encoding.GetCompressor(name)
+			test.That(t, compressor, test.ShouldNotBeNil)
+
+			var compressed bytes.Buffer
+			w, err := compressor.Compress(&compressed)
+			test.That(t, err, test.ShouldBeNil)
+			_, err = w.Write(payload)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, w.Close(), test.ShouldBeNil)
+			test.That(t, compressed.Len(), test.ShouldBeLessThan, len(payload))
+
+			r, err := compressor.Decompress(&compressed)
+			test.That(t, err, test.ShouldBeNil)
+			decompressed, err := io.ReadAll(r)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, decompressed, test.ShouldResemble, payload)
+		})
+	}
+}
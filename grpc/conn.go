@@ -18,6 +18,8 @@ import (
 type ReconfigurableClientConn struct {
 	connMu sync.RWMutex
 	conn   rpc.ClientConn
+	// replaced is closed when conn is next replaced.
+	replaced chan struct{}
 
 	onTrackCBByTrackNameMu sync.Mutex
 	onTrackCBByTrackName   map[string]OnTrackCB
@@ -65,6 +67,10 @@ func (c *ReconfigurableClientConn) NewStream(
 func (c *ReconfigurableClientConn) ReplaceConn(conn rpc.ClientConn) {
 	c.connMu.Lock()
 	c.conn = conn
+	if c.replaced != nil {
+		close(c.replaced)
+	}
+	c.replaced = make(chan struct{})
 	// It is safe to access this without a mutex as it is only ever nil once at the beginning of the
 	// ReconfigurableClientConn's lifetime. Before it is shared with clients.
 	if c.onTrackCBByTrackName == nil {
@@ -87,6 +93,17 @@ func (c *ReconfigurableClientConn) ReplaceConn(conn rpc.ClientConn) {
 	c.connMu.Unlock()
 }
 
+// Replaced returns a channel that is closed when the underlying client connection is next
+// replaced, such as when the connection is re-established after being lost.
+func (c *ReconfigurableClientConn) Replaced() <-chan struct{} {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	if c.replaced == nil {
+		c.replaced = make(chan struct{})
+	}
+	return c.replaced
+}
+
 // PeerConn returns the backing PeerConnection object, if applicable. Nil otherwise.
 func (c *ReconfigurableClientConn) PeerConn() *webrtc.PeerConnection {
 	c.connMu.Lock()
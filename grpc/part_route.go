@@ -0,0 +1,229 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/viamrobotics/webrtc/v3"
+	goutils "go.viam.com/utils"
+	"go.viam.com/utils/rpc"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// PartRouteMetadataKey is the gRPC metadata key naming the remote part a call made to a main part
+// is for, such that a client can talk to every part of a machine over its one connection to the
+// main part. Remotes of remotes are named by joining their names with ":", as in resource names.
+const PartRouteMetadataKey = "viam-part-route"
+
+// A PartConnLookup returns the connection a main part has to its remote part with the given name.
+type PartConnLookup func(part string) (rpc.ClientConn, bool)
+
+// partConn makes the calls of a connection to a main part for one of its remote parts.
+type partConn struct {
+	conn rpc.ClientConn
+	part string
+}
+
+// NewPartConn returns a connection to the remote part with the given name of the main part conn is
+// connected to. Calls made over it are routed to the part by the main part. Closing it leaves conn
+// open.
+func NewPartConn(conn rpc.ClientConn, part string) rpc.ClientConn {
+	return &partConn{conn: conn, part: part}
+}
+
+func (pc *partConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...googlegrpc.CallOption,
+) error {
+	return pc.conn.Invoke(pc.withRoute(ctx), method, args, reply, opts...)
+}
+
+func (pc *partConn) NewStream(
+	ctx context.Context,
+	desc *googlegrpc.StreamDesc,
+	method string,
+	opts ...googlegrpc.CallOption,
+) (googlegrpc.ClientStream, error) {
+	return pc.conn.NewStream(pc.withRoute(ctx), desc, method, opts...)
+}
+
+// PeerConn returns nil, as tracks sent over the main part's peer connection are for the main part.
+func (pc *partConn) PeerConn() *webrtc.PeerConnection {
+	return nil
+}
+
+func (pc *partConn) Close() error {
+	return nil
+}
+
+func (pc *partConn) withRoute(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, PartRouteMetadataKey, pc.part)
+}
+
+// PartRouteUnaryServerInterceptor forwards calls routed to a remote part over the connection
+// lookup returns for it. Other calls are handled as usual.
+func PartRouteUnaryServerInterceptor(lookup PartConnLookup) googlegrpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *googlegrpc.UnaryServerInfo,
+		handler googlegrpc.UnaryHandler,
+	) (interface{}, error) {
+		conn, outCtx, err := routeToPart(ctx, lookup)
+		if err != nil {
+			return nil, err
+		}
+		if conn == nil {
+			return handler(ctx, req)
+		}
+		method, err := methodDescriptor(info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		reply := newMessage(method.Output())
+		if err := conn.Invoke(outCtx, info.FullMethod, req, reply); err != nil {
+			return nil, err
+		}
+		return reply, nil
+	}
+}
+
+// PartRouteStreamServerInterceptor forwards streams routed to a remote part over the connection
+// lookup returns for it. Other streams are handled as usual.
+func PartRouteStreamServerInterceptor(lookup PartConnLookup) googlegrpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss googlegrpc.ServerStream,
+		info *googlegrpc.StreamServerInfo,
+		handler googlegrpc.StreamHandler,
+	) error {
+		conn, outCtx, err := routeToPart(ss.Context(), lookup)
+		if err != nil {
+			return err
+		}
+		if conn == nil {
+			return handler(srv, ss)
+		}
+		method, err := methodDescriptor(info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(outCtx)
+		defer cancel()
+		cs, err := conn.NewStream(ctx, &googlegrpc.StreamDesc{
+			StreamName:    string(method.Name()),
+			ServerStreams: info.IsServerStream,
+			ClientStreams: info.IsClientStream,
+		}, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		// Requests are forwarded until the client is done sending them, and responses until the
+		// part is done sending them.
+		goutils.PanicCapturingGo(func() {
+			for {
+				req := newMessage(method.Input())
+				if err := ss.RecvMsg(req); err != nil {
+					if errors.Is(err, io.EOF) {
+						//nolint:errcheck
+						cs.CloseSend()
+					} else {
+						cancel()
+					}
+					return
+				}
+				if err := cs.SendMsg(req); err != nil {
+					cancel()
+					return
+				}
+			}
+		})
+		for {
+			resp := newMessage(method.Output())
+			if err := cs.RecvMsg(resp); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+			if err := ss.SendMsg(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// routeToPart returns the connection to the part a call is routed to and the context to forward
+// the call with, or a nil connection if the call is not routed.
+func routeToPart(ctx context.Context, lookup PartConnLookup) (rpc.ClientConn, context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil, nil
+	}
+	routes := md.Get(PartRouteMetadataKey)
+	if len(routes) == 0 {
+		return nil, nil, nil
+	}
+	part, rest, _ := strings.Cut(routes[0], ":")
+	conn, ok := lookup(part)
+	if !ok {
+		return nil, nil, status.Errorf(codes.NotFound, "no remote part named %q", part)
+	}
+
+	// The part is called with the metadata of the call, apart from what is particular to the
+	// connection to this part.
+	outMD := metadata.MD{}
+	for key, values := range md {
+		switch {
+		case key == PartRouteMetadataKey, key == "authorization", key == "content-type", key == "user-agent",
+			strings.HasPrefix(key, ":"), strings.HasPrefix(key, "grpc-"):
+		default:
+			outMD[key] = values
+		}
+	}
+	if rest != "" {
+		outMD.Set(PartRouteMetadataKey, rest)
+	}
+	return conn, metadata.NewOutgoingContext(ctx, outMD), nil
+}
+
+// methodDescriptor returns the descriptor of a full gRPC method name, such as
+// "/viam.robot.v1.RobotService/GetMachineStatus".
+func methodDescriptor(fullMethod string) (protoreflect.MethodDescriptor, error) {
+	service, name, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid method %q", fullMethod)
+	}
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, status.Errorf(codes.Unimplemented, "cannot route unknown service %q to a part", service)
+	}
+	serviceDesc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "cannot route unknown service %q to a part", service)
+	}
+	method := serviceDesc.Methods().ByName(protoreflect.Name(name))
+	if method == nil {
+		return nil, status.Errorf(codes.Unimplemented, "cannot route unknown method %q to a part", fullMethod)
+	}
+	return method, nil
+}
+
+func newMessage(desc protoreflect.MessageDescriptor) protoreflect.ProtoMessage {
+	if msgType, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName()); err == nil {
+		return msgType.New().Interface()
+	}
+	return dynamicpb.NewMessage(desc)
+}
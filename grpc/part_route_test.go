@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	pb "go.viam.com/api/robot/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// invokeConn records the last call invoked over it.
+type invokeConn struct {
+	rpc.ClientConn
+	method string
+	md     metadata.MD
+}
+
+func (c *invokeConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...googlegrpc.CallOption) error {
+	c.method = method
+	c.md, _ = metadata.FromOutgoingContext(ctx)
+	reply.(*pb.GetVersionResponse).Version = "remote"
+	return nil
+}
+
+func TestPartConn(t *testing.T) {
+	main := &invokeConn{}
+	conn := NewPartConn(main, "remote1")
+	test.That(t, conn.PeerConn(), test.ShouldBeNil)
+	test.That(t, conn.Close(), test.ShouldBeNil)
+
+	reply := &pb.GetVersionResponse{}
+	err := conn.Invoke(context.Background(), "/viam.robot.v1.RobotService/GetVersion", &pb.GetVersionRequest{}, reply)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, main.md.Get(PartRouteMetadataKey), test.ShouldResemble, []string{"remote1"})
+}
+
+func TestPartRouteUnaryServerInterceptor(t *testing.T) {
+	remote := &invokeConn{}
+	lookup := func(part string) (rpc.ClientConn, bool) {
+		if part != "remote1" {
+			return nil, false
+		}
+		return remote, true
+	}
+	interceptor := PartRouteUnaryServerInterceptor(lookup)
+	info := &googlegrpc.UnaryServerInfo{FullMethod: "/viam.robot.v1.RobotService/GetVersion"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.GetVersionResponse{Version: "main"}, nil
+	}
+
+	t.Run("not routed", func(t *testing.T) {
+		resp, err := interceptor(context.Background(), &pb.GetVersionRequest{}, info, handler)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.(*pb.GetVersionResponse).Version, test.ShouldEqual, "main")
+	})
+
+	t.Run("routed", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+			PartRouteMetadataKey, "remote1:remote2",
+			"authorization", "Bearer main",
+			"opid", "op",
+		))
+		resp, err := interceptor(ctx, &pb.GetVersionRequest{}, info, handler)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.(*pb.GetVersionResponse).Version, test.ShouldEqual, "remote")
+		test.That(t, remote.method, test.ShouldEqual, info.FullMethod)
+		// the rest of the route is left for the remote to follow
+		test.That(t, remote.md.Get(PartRouteMetadataKey), test.ShouldResemble, []string{"remote2"})
+		test.That(t, remote.md.Get("authorization"), test.ShouldBeEmpty)
+		test.That(t, remote.md.Get("opid"), test.ShouldResemble, []string{"op"})
+	})
+
+	t.Run("unknown part", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(PartRouteMetadataKey, "remote3"))
+		_, err := interceptor(ctx, &pb.GetVersionRequest{}, info, handler)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+	})
+}
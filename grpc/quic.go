@@ -0,0 +1,442 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/viamrobotics/webrtc/v3"
+	"go.uber.org/multierr"
+	rpcpb "go.viam.com/utils/proto/rpc/v1"
+	"go.viam.com/utils/rpc"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// QUICKeepAlivePeriod is how often QUIC connections to and from robots are kept alive when idle, which
+// also keeps the mappings of NATs between the client and the robot from expiring.
+const QUICKeepAlivePeriod = 10 * time.Second
+
+// QUICDialOptions configure dialing a robot over QUIC.
+type QUICDialOptions struct {
+	// TLSConfig verifies the robot's certificate. QUIC is always encrypted, so a robot serves QUIC
+	// with TLS even where it serves HTTP/2 without.
+	TLSConfig *tls.Config
+	// Entity and Credentials, if set, authenticate the connection with the robot's auth service.
+	Entity      string
+	Credentials *rpc.Credentials
+	// UnaryInterceptors and StreamInterceptors intercept calls, in order from first to last.
+	UnaryInterceptors  []googlegrpc.UnaryClientInterceptor
+	StreamInterceptors []googlegrpc.StreamClientInterceptor
+}
+
+// QUICHandler lets `handler` serve gRPC calls made over HTTP/3 by DialQUIC. gRPC only accepts
+// HTTP/2 requests, but relies on nothing HTTP/3 lacks: full duplex streams, flushing and trailers.
+func QUICHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 3 {
+			r2 := *r
+			r2.Proto, r2.ProtoMajor, r2.ProtoMinor = "HTTP/2.0", 2, 0
+			r = &r2
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// quicConn makes gRPC calls over HTTP/3, such that calls do not block each other when packets are
+// lost, as they do over the single TCP stream of HTTP/2. Messages are encoded with protobuf and not
+// compressed.
+type quicConn struct {
+	transport *http3.Transport
+	baseURL   string
+	opts      QUICDialOptions
+	// accessToken authenticates calls, if the connection was authenticated.
+	accessToken string
+}
+
+// DialQUIC connects to a robot serving gRPC over QUIC at `address`, e.g: `robot.local:8443`. Unlike
+// DialDirectGRPC, it does not fall back to other transports.
+func DialQUIC(ctx context.Context, address string, opts QUICDialOptions) (rpc.ClientConn, error) {
+	tlsConfig := opts.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS13}
+	}
+	conn := &quicConn{
+		transport: &http3.Transport{
+			TLSClientConfig: tlsConfig,
+			QUICConfig:      &quic.Config{KeepAlivePeriod: QUICKeepAlivePeriod},
+		},
+		baseURL: "https://" + address,
+		opts:    opts,
+	}
+	if opts.Credentials != nil {
+		resp, err := rpcpb.NewAuthServiceClient(&quicConn{transport: conn.transport, baseURL: conn.baseURL}).
+			Authenticate(ctx, &rpcpb.AuthenticateRequest{
+				Entity: opts.Entity,
+				Credentials: &rpcpb.Credentials{
+					Type:    string(opts.Credentials.Type),
+					Payload: opts.Credentials.Payload,
+				},
+			})
+		if err != nil {
+			return nil, multierr.Combine(errors.Wrapf(err, "cannot authenticate with %s over QUIC", address), conn.Close())
+		}
+		conn.accessToken = resp.GetAccessToken()
+		return conn, nil
+	}
+	// Check that the robot is reachable, such that dialing fails like dialing other transports does.
+	if err := conn.ping(ctx); err != nil {
+		return nil, multierr.Combine(errors.Wrapf(err, "cannot connect to %s over QUIC", address), conn.Close())
+	}
+	return conn, nil
+}
+
+// ping makes a request that establishes the QUIC connection.
+func (qc *quicConn) ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodOptions, qc.baseURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := qc.transport.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (qc *quicConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...googlegrpc.CallOption,
+) error {
+	invoker := func(ctx context.Context, method string, args, reply interface{}, _ *googlegrpc.ClientConn,
+		opts ...googlegrpc.CallOption,
+	) error {
+		stream, err := qc.newStream(ctx, method, opts)
+		if err != nil {
+			return err
+		}
+		// a send that fails with io.EOF ended the call, whose status RecvMsg returns
+		if err := stream.SendMsg(args); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if err := stream.CloseSend(); err != nil {
+			return err
+		}
+		if err := stream.RecvMsg(reply); err != nil {
+			if errors.Is(err, io.EOF) {
+				return status.Error(codes.Internal, "unary call received no response")
+			}
+			return err
+		}
+		// the call ends at the trailers, which carry its status
+		if err := stream.RecvMsg(reply); !errors.Is(err, io.EOF) {
+			if err == nil {
+				return status.Error(codes.Internal, "unary call received more than one response")
+			}
+			return err
+		}
+		return nil
+	}
+	for i := len(qc.opts.UnaryInterceptors) - 1; i >= 0; i-- {
+		interceptor, next := qc.opts.UnaryInterceptors[i], invoker
+		invoker = func(ctx context.Context, method string, args, reply interface{}, cc *googlegrpc.ClientConn,
+			opts ...googlegrpc.CallOption,
+		) error {
+			return interceptor(ctx, method, args, reply, cc, next, opts...)
+		}
+	}
+	return invoker(ctx, method, args, reply, nil, opts...)
+}
+
+func (qc *quicConn) NewStream(
+	ctx context.Context,
+	desc *googlegrpc.StreamDesc,
+	method string,
+	opts ...googlegrpc.CallOption,
+) (googlegrpc.ClientStream, error) {
+	streamer := func(ctx context.Context, _ *googlegrpc.StreamDesc, _ *googlegrpc.ClientConn, method string,
+		opts ...googlegrpc.CallOption,
+	) (googlegrpc.ClientStream, error) {
+		return qc.newStream(ctx, method, opts)
+	}
+	for i := len(qc.opts.StreamInterceptors) - 1; i >= 0; i-- {
+		interceptor, next := qc.opts.StreamInterceptors[i], streamer
+		streamer = func(ctx context.Context, desc *googlegrpc.StreamDesc, cc *googlegrpc.ClientConn, method string,
+			opts ...googlegrpc.CallOption,
+		) (googlegrpc.ClientStream, error) {
+			return interceptor(ctx, desc, cc, method, next, opts...)
+		}
+	}
+	return streamer(ctx, desc, nil, method, opts...)
+}
+
+// PeerConn returns nil, as QUIC connections have no peer connection to send tracks over.
+func (qc *quicConn) PeerConn() *webrtc.PeerConnection {
+	return nil
+}
+
+func (qc *quicConn) Close() error {
+	return qc.transport.Close()
+}
+
+// newStream starts a call. Its request body is written by SendMsg, and its response is read by
+// RecvMsg once the robot responds.
+func (qc *quicConn) newStream(ctx context.Context, method string, opts []googlegrpc.CallOption) (*quicStream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	body, bodyWriter := io.Pipe()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, qc.baseURL+method, body)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/grpc+proto")
+	req.Header.Set("TE", "trailers")
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set("Grpc-Timeout", strconv.FormatInt(max(time.Until(deadline).Milliseconds(), 1), 10)+"m")
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for key, values := range md {
+		for _, value := range values {
+			if strings.HasSuffix(key, "-bin") {
+				value = base64.RawStdEncoding.EncodeToString([]byte(value))
+			}
+			req.Header.Add(key, value)
+		}
+	}
+	if qc.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+qc.accessToken)
+	}
+
+	stream := &quicStream{ctx: ctx, cancel: cancel, body: bodyWriter, responded: make(chan struct{})}
+	// Once the call ends, sending fails instead of blocking on a body that is no longer read.
+	go func() {
+		<-ctx.Done()
+		//nolint:errcheck
+		body.Close()
+	}()
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case googlegrpc.HeaderCallOption:
+			stream.headerAddr = opt.HeaderAddr
+		case googlegrpc.TrailerCallOption:
+			stream.trailerAddr = opt.TrailerAddr
+		}
+	}
+	go func() {
+		defer close(stream.responded)
+		//nolint:bodyclose
+		resp, err := qc.transport.RoundTrip(req)
+		if err != nil {
+			stream.err = toStatusError(ctx, err)
+			return
+		}
+		stream.resp = resp
+		stream.header = toMetadata(resp.Header)
+		if stream.headerAddr != nil {
+			*stream.headerAddr = stream.header
+		}
+		if resp.StatusCode != http.StatusOK {
+			stream.err = status.Errorf(codes.Unavailable, "unexpected HTTP status %s", resp.Status)
+			return
+		}
+		// A call that fails before responding has its status in its headers.
+		if resp.Header.Get("Grpc-Status") != "" {
+			stream.err = statusFromHeader(resp.Header)
+		}
+	}()
+	return stream, nil
+}
+
+// quicStream is a gRPC call over HTTP/3.
+type quicStream struct {
+	ctx    context.Context
+	cancel func()
+	body   *io.PipeWriter
+
+	// responded is closed once the response or err is set.
+	responded chan struct{}
+	resp      *http.Response
+	header    metadata.MD
+	err       error
+
+	headerAddr  *metadata.MD
+	trailerAddr *metadata.MD
+	trailer     metadata.MD
+}
+
+func (qs *quicStream) Header() (metadata.MD, error) {
+	select {
+	case <-qs.responded:
+	case <-qs.ctx.Done():
+		return nil, toStatusError(qs.ctx, qs.ctx.Err())
+	}
+	if qs.resp == nil {
+		return nil, qs.err
+	}
+	return qs.header, nil
+}
+
+func (qs *quicStream) Trailer() metadata.MD {
+	return qs.trailer
+}
+
+func (qs *quicStream) CloseSend() error {
+	return qs.body.Close()
+}
+
+func (qs *quicStream) Context() context.Context {
+	return qs.ctx
+}
+
+func (qs *quicStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "cannot send %T, which is not a proto message", m)
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	frame := make([]byte, 5, 5+len(data))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(data)))
+	if _, err := qs.body.Write(append(frame, data...)); err != nil {
+		// the call ended, and RecvMsg returns why
+		return io.EOF
+	}
+	return nil
+}
+
+func (qs *quicStream) RecvMsg(m interface{}) error {
+	if _, err := qs.Header(); err != nil {
+		return qs.finish(err)
+	}
+	if qs.err != nil {
+		return qs.finish(qs.err)
+	}
+	var prefix [5]byte
+	if _, err := io.ReadFull(qs.resp.Body, prefix[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return qs.finish(statusFromHeader(qs.resp.Trailer))
+		}
+		return qs.finish(toStatusError(qs.ctx, err))
+	}
+	if prefix[0] != 0 {
+		return qs.finish(status.Error(codes.Internal, "received a compressed message, which is not supported over QUIC"))
+	}
+	data := make([]byte, binary.BigEndian.Uint32(prefix[1:]))
+	if _, err := io.ReadFull(qs.resp.Body, data); err != nil {
+		return qs.finish(toStatusError(qs.ctx, err))
+	}
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return qs.finish(status.Errorf(codes.Internal, "cannot receive %T, which is not a proto message", m))
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return qs.finish(status.Error(codes.Internal, err.Error()))
+	}
+	return nil
+}
+
+// finish ends the call with `err`, which is io.EOF if the call succeeded.
+func (qs *quicStream) finish(err error) error {
+	if qs.resp != nil {
+		qs.trailer = toMetadata(qs.resp.Trailer)
+		if qs.trailerAddr != nil {
+			*qs.trailerAddr = qs.trailer
+		}
+		//nolint:errcheck
+		qs.resp.Body.Close()
+	}
+	qs.cancel()
+	if err == nil {
+		return io.EOF
+	}
+	return err
+}
+
+// statusFromHeader returns the status of a call from the headers or trailers carrying it, or nil if
+// the call succeeded.
+func statusFromHeader(header http.Header) error {
+	if details := header.Get("Grpc-Status-Details-Bin"); details != "" {
+		if data, err := decodeBinaryHeader(details); err == nil {
+			var s spb.Status
+			if err := proto.Unmarshal(data, &s); err == nil {
+				return status.FromProto(&s).Err()
+			}
+		}
+	}
+	codeStr := header.Get("Grpc-Status")
+	if codeStr == "" {
+		return status.Error(codes.Internal, "call ended without a status")
+	}
+	code, err := strconv.ParseUint(codeStr, 10, 32)
+	if err != nil {
+		return status.Errorf(codes.Internal, "invalid status %q", codeStr)
+	}
+	msg := header.Get("Grpc-Message")
+	if unescaped, err := url.PathUnescape(msg); err == nil {
+		msg = unescaped
+	}
+	return status.Error(codes.Code(code), msg)
+}
+
+// toMetadata returns the metadata in HTTP headers, without the headers of the gRPC protocol.
+func toMetadata(header http.Header) metadata.MD {
+	md := metadata.MD{}
+	for key, values := range header {
+		key = strings.ToLower(key)
+		if strings.HasPrefix(key, "grpc-") || key == "content-type" || key == "trailer" {
+			continue
+		}
+		for _, value := range values {
+			if strings.HasSuffix(key, "-bin") {
+				data, err := decodeBinaryHeader(value)
+				if err != nil {
+					continue
+				}
+				value = string(data)
+			}
+			md.Append(key, value)
+		}
+	}
+	return md
+}
+
+func decodeBinaryHeader(value string) ([]byte, error) {
+	if len(value)%4 == 0 {
+		return base64.StdEncoding.DecodeString(value)
+	}
+	return base64.RawStdEncoding.DecodeString(value)
+}
+
+// toStatusError returns the status error of a call that failed with `err`.
+func toStatusError(ctx context.Context, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+	case errors.Is(ctx.Err(), context.Canceled):
+		return status.Error(codes.Canceled, ctx.Err().Error())
+	default:
+		return status.Error(codes.Unavailable, fmt.Sprintf("QUIC transport: %v", err))
+	}
+}
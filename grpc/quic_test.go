@@ -0,0 +1,154 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/quic-go/quic-go/http3"
+	"go.viam.com/test"
+	echopb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	"go.viam.com/utils/rpc"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestQUIC(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	rpcServer, err := rpc.NewServer(
+		logger,
+		rpc.WithAuthHandler(rpc.CredentialsTypeAPIKey, rpc.MakeSimpleAuthHandler([]string{"foo"}, "bar")),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+	}()
+	echo := &echoserver.Server{
+		MustContextAuthEntity: func(ctx context.Context) echoserver.RPCEntityInfo {
+			ent := rpc.MustContextAuthEntity(ctx)
+			return echoserver.RPCEntityInfo{Entity: ent.Entity, Data: ent.Data}
+		},
+	}
+	echo.SetAuthorized(true)
+	echo.SetExpectedAuthEntity("foo")
+	test.That(t, rpcServer.RegisterServiceServer(
+		ctx,
+		&echopb.EchoService_ServiceDesc,
+		echo,
+		echopb.RegisterEchoServiceHandlerFromEndpoint,
+	), test.ShouldBeNil)
+
+	cert, _, _, certPool, err := testutils.GenerateSelfSignedCertificate("localhost")
+	test.That(t, err, test.ShouldBeNil)
+	udpConn, err := net.ListenPacket("udp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	server := &http3.Server{
+		Handler: QUICHandler(rpcServer.GRPCHandler()),
+		TLSConfig: http3.ConfigureTLSConfig(&tls.Config{
+			MinVersion:   tls.VersionTLS13,
+			Certificates: []tls.Certificate{cert},
+		}),
+	}
+	go server.Serve(udpConn)
+	defer func() {
+		test.That(t, server.Close(), test.ShouldBeNil)
+		test.That(t, udpConn.Close(), test.ShouldBeNil)
+	}()
+	address := fmt.Sprintf("localhost:%d", udpConn.LocalAddr().(*net.UDPAddr).Port)
+
+	var intercepted []string
+	conn, err := DialQUIC(ctx, address, QUICDialOptions{
+		TLSConfig:   &tls.Config{MinVersion: tls.VersionTLS13, RootCAs: certPool},
+		Entity:      "foo",
+		Credentials: &rpc.Credentials{Type: rpc.CredentialsTypeAPIKey, Payload: "bar"},
+		UnaryInterceptors: []googlegrpc.UnaryClientInterceptor{
+			func(ctx context.Context, method string, req, reply interface{}, cc *googlegrpc.ClientConn,
+				invoker googlegrpc.UnaryInvoker, opts ...googlegrpc.CallOption,
+			) error {
+				intercepted = append(intercepted, method)
+				return invoker(ctx, method, req, reply, cc, opts...)
+			},
+		},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}()
+	test.That(t, conn.PeerConn(), test.ShouldBeNil)
+	client := echopb.NewEchoServiceClient(conn)
+
+	resp, err := client.Echo(ctx, &echopb.EchoRequest{Message: "hello"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.GetMessage(), test.ShouldEqual, "hello")
+	test.That(t, intercepted, test.ShouldResemble, []string{"/proto.rpc.examples.echo.v1.EchoService/Echo"})
+
+	multiple, err := client.EchoMultiple(ctx, &echopb.EchoMultipleRequest{Message: "abc"})
+	test.That(t, err, test.ShouldBeNil)
+	var received string
+	for {
+		resp, err := multiple.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		test.That(t, err, test.ShouldBeNil)
+		received += resp.GetMessage()
+	}
+	test.That(t, received, test.ShouldEqual, "abc")
+
+	// Messages are received while the stream is still being sent on.
+	bidi, err := client.EchoBiDi(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, bidi.Send(&echopb.EchoBiDiRequest{Message: "x"}), test.ShouldBeNil)
+	biResp, err := bidi.Recv()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, biResp.GetMessage(), test.ShouldEqual, "x")
+	test.That(t, bidi.Send(&echopb.EchoBiDiRequest{Message: "y"}), test.ShouldBeNil)
+	biResp, err = bidi.Recv()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, biResp.GetMessage(), test.ShouldEqual, "y")
+	test.That(t, bidi.CloseSend(), test.ShouldBeNil)
+	_, err = bidi.Recv()
+	test.That(t, err, test.ShouldEqual, io.EOF)
+
+	echo.SetFail(true)
+	_, err = client.Echo(ctx, &echopb.EchoRequest{Message: "hello"})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unknown)
+	test.That(t, status.Convert(err).Message(), test.ShouldEqual, "whoops")
+	echo.SetFail(false)
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		conn, err := DialQUIC(ctx, address, QUICDialOptions{
+			TLSConfig: &tls.Config{MinVersion: tls.VersionTLS13, RootCAs: certPool},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		}()
+		_, err = echopb.NewEchoServiceClient(conn).Echo(ctx, &echopb.EchoRequest{Message: "hello"})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+
+		_, err = DialQUIC(ctx, address, QUICDialOptions{
+			TLSConfig:   &tls.Config{MinVersion: tls.VersionTLS13, RootCAs: certPool},
+			Entity:      "foo",
+			Credentials: &rpc.Credentials{Type: rpc.CredentialsTypeAPIKey, Payload: "wrong"},
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "cannot authenticate")
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		_, err := DialQUIC(ctx, address, QUICDialOptions{})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.viam.com/utils/rpc"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// A RemoteAccessPolicy restricts which authenticated entities may call the resources of each
+// remote, so that a part aggregating the remotes of several teams only lets each team reach its
+// own. Calls are attributed to a remote either by their part route or by the name of the resource
+// they are for.
+type RemoteAccessPolicy struct {
+	mu sync.RWMutex
+	// allowedEntities are the entities allowed to call each remote. Remotes without any allow
+	// every entity.
+	allowedEntities map[string][]string
+	// resourceRemotes are the remotes of resources, by every name a resource can be called by.
+	resourceRemotes map[string]string
+}
+
+// NewRemoteAccessPolicy returns a RemoteAccessPolicy which allows every call until it is updated.
+func NewRemoteAccessPolicy() *RemoteAccessPolicy {
+	return &RemoteAccessPolicy{}
+}
+
+// Update replaces the entities allowed to call each remote and the remotes of resources, keyed by
+// the names they can be called by.
+func (p *RemoteAccessPolicy) Update(allowedEntities map[string][]string, resourceRemotes map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.allowedEntities = allowedEntities
+	p.resourceRemotes = resourceRemotes
+}
+
+// UnaryServerInterceptor denies calls to remotes from entities they don't allow.
+func (p *RemoteAccessPolicy) UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *googlegrpc.UnaryServerInfo,
+	handler googlegrpc.UnaryHandler,
+) (interface{}, error) {
+	if err := p.authorize(ctx, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor denies streams to remotes from entities they don't allow. Streams are
+// checked both when they start and on every request received over them.
+func (p *RemoteAccessPolicy) StreamServerInterceptor(
+	srv interface{},
+	ss googlegrpc.ServerStream,
+	info *googlegrpc.StreamServerInfo,
+	handler googlegrpc.StreamHandler,
+) error {
+	if err := p.authorize(ss.Context(), nil); err != nil {
+		return err
+	}
+	return handler(srv, &remoteAccessServerStream{ServerStream: ss, policy: p})
+}
+
+// authorize returns a PermissionDenied error if the call is for a remote which does not allow the
+// entity making it. req may be nil when only the part route is known.
+func (p *RemoteAccessPolicy) authorize(ctx context.Context, req interface{}) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.allowedEntities) == 0 {
+		return nil
+	}
+
+	var remote string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if routes := md.Get(PartRouteMetadataKey); len(routes) != 0 {
+			remote, _, _ = strings.Cut(routes[0], ":")
+		}
+	}
+	if remote == "" {
+		named, ok := req.(interface{ GetName() string })
+		if !ok {
+			return nil
+		}
+		if remote, ok = p.resourceRemotes[named.GetName()]; !ok {
+			return nil
+		}
+	}
+
+	allowed := p.allowedEntities[remote]
+	if len(allowed) == 0 {
+		return nil
+	}
+	if entity, ok := rpc.ContextAuthEntity(ctx); ok && slices.Contains(allowed, entity.Entity) {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "not allowed to access remote %q", remote)
+}
+
+// remoteAccessServerStream authorizes every request received over a stream.
+type remoteAccessServerStream struct {
+	googlegrpc.ServerStream
+	policy *RemoteAccessPolicy
+}
+
+func (s *remoteAccessServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.policy.authorize(s.Context(), m)
+}
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	camerapb "go.viam.com/api/component/camera/v1"
+	pb "go.viam.com/api/robot/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// recvServerStream receives a single request.
+type recvServerStream struct {
+	googlegrpc.ServerStream
+	ctx context.Context
+	req string
+}
+
+func (s *recvServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *recvServerStream) RecvMsg(m interface{}) error {
+	m.(*camerapb.GetImageRequest).Name = s.req
+	return nil
+}
+
+func TestRemoteAccessPolicy(t *testing.T) {
+	policy := NewRemoteAccessPolicy()
+	info := &googlegrpc.UnaryServerInfo{FullMethod: "/viam.component.camera.v1.CameraService/GetImage"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &camerapb.GetImageResponse{}, nil
+	}
+	asEntity := func(entity string) context.Context {
+		return rpc.ContextWithAuthEntity(context.Background(), rpc.EntityInfo{Entity: entity})
+	}
+
+	// Every call is allowed until the policy is updated.
+	_, err := policy.UnaryServerInterceptor(context.Background(), &camerapb.GetImageRequest{Name: "team-a:cam"}, info, handler)
+	test.That(t, err, test.ShouldBeNil)
+
+	policy.Update(
+		map[string][]string{"team-a": {"a@viam.com"}},
+		map[string]string{"team-a:cam": "team-a", "cam": "team-a", "team-b:cam2": "team-b"},
+	)
+
+	t.Run("unary", func(t *testing.T) {
+		for _, name := range []string{"team-a:cam", "cam"} {
+			_, err := policy.UnaryServerInterceptor(asEntity("a@viam.com"), &camerapb.GetImageRequest{Name: name}, info, handler)
+			test.That(t, err, test.ShouldBeNil)
+
+			_, err = policy.UnaryServerInterceptor(asEntity("b@viam.com"), &camerapb.GetImageRequest{Name: name}, info, handler)
+			test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+
+			_, err = policy.UnaryServerInterceptor(context.Background(), &camerapb.GetImageRequest{Name: name}, info, handler)
+			test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+		}
+
+		// Remotes without allowed entities and local resources are open to every entity.
+		_, err := policy.UnaryServerInterceptor(asEntity("a@viam.com"), &camerapb.GetImageRequest{Name: "team-b:cam2"}, info, handler)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = policy.UnaryServerInterceptor(asEntity("b@viam.com"), &camerapb.GetImageRequest{Name: "local-cam"}, info, handler)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = policy.UnaryServerInterceptor(asEntity("b@viam.com"), &pb.GetVersionRequest{}, info, handler)
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("part route", func(t *testing.T) {
+		routed := func(entity string) context.Context {
+			return metadata.NewIncomingContext(asEntity(entity), metadata.Pairs(PartRouteMetadataKey, "team-a:nested"))
+		}
+		_, err := policy.UnaryServerInterceptor(routed("a@viam.com"), &pb.GetVersionRequest{}, info, handler)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = policy.UnaryServerInterceptor(routed("b@viam.com"), &pb.GetVersionRequest{}, info, handler)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		streamInfo := &googlegrpc.StreamServerInfo{FullMethod: info.FullMethod}
+		streamHandler := func(srv interface{}, ss googlegrpc.ServerStream) error {
+			return ss.RecvMsg(&camerapb.GetImageRequest{})
+		}
+		err := policy.StreamServerInterceptor(nil, &recvServerStream{ctx: asEntity("a@viam.com"), req: "cam"}, streamInfo, streamHandler)
+		test.That(t, err, test.ShouldBeNil)
+		err = policy.StreamServerInterceptor(nil, &recvServerStream{ctx: asEntity("b@viam.com"), req: "cam"}, streamInfo, streamHandler)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	})
+}
@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// resumeRetryInterval is the longest a resumed stream waits for its connection to be replaced
+// before trying again on the connection it has.
+var resumeRetryInterval = 5 * time.Second
+
+// A ReplaceableConn is a client connection whose underlying connection is replaced when it is
+// re-established after being lost.
+type ReplaceableConn interface {
+	rpc.ClientConn
+	// Replaced returns a channel that is closed when the underlying connection is next replaced.
+	Replaced() <-chan struct{}
+}
+
+// IsDisconnectedError returns whether err was caused by the connection to the server being lost,
+// rather than by the server failing the request.
+func IsDisconnectedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, rpc.ErrDisconnected) ||
+		errors.Is(err, ErrNotConnected) ||
+		status.Code(err) == codes.Unavailable ||
+		strings.Contains(err.Error(), io.ErrClosedPipe.Error())
+}
+
+// ResumeStream calls run with ctx until it returns for any reason other than the connection being
+// lost. When it is lost, run is called again once conn is re-established, so that a stream run
+// subscribes to continues where it left off. run must be safe to call again after it returns.
+func ResumeStream(ctx context.Context, conn rpc.ClientConn, run func(ctx context.Context) error) error {
+	for {
+		var replaced <-chan struct{}
+		if rConn, ok := conn.(ReplaceableConn); ok {
+			replaced = rConn.Replaced()
+		}
+		err := run(ctx)
+		if !IsDisconnectedError(err) || ctx.Err() != nil {
+			return err
+		}
+		// Connections that are never replaced, with a nil replaced, are retried after an interval.
+		timer := time.NewTimer(resumeRetryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-replaced:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type replaceableConn struct {
+	rpc.ClientConn
+	replaced chan struct{}
+}
+
+func (c *replaceableConn) Replaced() <-chan struct{} {
+	return c.replaced
+}
+
+func TestIsDisconnectedError(t *testing.T) {
+	test.That(t, IsDisconnectedError(nil), test.ShouldBeFalse)
+	test.That(t, IsDisconnectedError(errors.New("bad request")), test.ShouldBeFalse)
+	test.That(t, IsDisconnectedError(status.Error(codes.InvalidArgument, "bad request")), test.ShouldBeFalse)
+	test.That(t, IsDisconnectedError(rpc.ErrDisconnected), test.ShouldBeTrue)
+	test.That(t, IsDisconnectedError(ErrNotConnected), test.ShouldBeTrue)
+	test.That(t, IsDisconnectedError(status.Error(codes.Unavailable, "transport is closing")), test.ShouldBeTrue)
+}
+
+func TestResumeStream(t *testing.T) {
+	t.Run("returns other errors", func(t *testing.T) {
+		conn := &replaceableConn{replaced: make(chan struct{})}
+		errBad := errors.New("bad request")
+		var calls int
+		err := ResumeStream(context.Background(), conn, func(ctx context.Context) error {
+			calls++
+			return errBad
+		})
+		test.That(t, err, test.ShouldEqual, errBad)
+		test.That(t, calls, test.ShouldEqual, 1)
+	})
+
+	t.Run("resumes once replaced", func(t *testing.T) {
+		conn := &replaceableConn{replaced: make(chan struct{})}
+		var calls int
+		err := ResumeStream(context.Background(), conn, func(ctx context.Context) error {
+			calls++
+			if calls == 1 {
+				close(conn.replaced)
+				return ErrNotConnected
+			}
+			return nil
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, calls, test.ShouldEqual, 2)
+	})
+
+	t.Run("stops waiting when canceled", func(t *testing.T) {
+		conn := &replaceableConn{replaced: make(chan struct{})}
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls int
+		err := ResumeStream(ctx, conn, func(ctx context.Context) error {
+			calls++
+			cancel()
+			return status.Error(codes.Unavailable, "transport is closing")
+		})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+		test.That(t, calls, test.ShouldEqual, 1)
+	})
+}
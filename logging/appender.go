@@ -41,6 +41,15 @@ func NewWriterAppender(writer io.Writer) ConsoleAppender {
 // enabled such that restarts of the viam-server with the same filename will move the old file out
 // of the way. The `io.Closer` can be used to eventually close the opened log file.
 func NewFileAppender(filename string) (Appender, io.Closer) {
+	// We only have `NewFileAppender` return an io.Closer, rather than `NewWriterAppender` because
+	// `NewWriterAppender` accepts stdout from `NewStdoutAppender`. And I'm not certain that it's a
+	// good idea to be calling `stdout.Close`.
+	logFile := NewFileWriter(filename)
+	return NewWriterAppender(logFile), logFile
+}
+
+// NewFileWriter opens a log file the way NewFileAppender does, for appenders of other formats.
+func NewFileWriter(filename string) io.WriteCloser {
 	logger := &lumberjack.Logger{
 		Filename: filename,
 		// 1 Terabyte -- basically infinite. Don't rollover on size. Just restarts.
@@ -52,11 +61,7 @@ func NewFileAppender(filename string) (Appender, io.Closer) {
 	if err := logger.Rotate(); err != nil {
 		Global().Fatal("Error creating log file:", err)
 	}
-
-	// We only have `NewFileAppender` return an io.Closer, rather than `NewWriterAppender` because
-	// `NewWriterAppender` accepts stdout from `NewStdoutAppender`. And I'm not certain that it's a
-	// good idea to be calling `stdout.Close`.
-	return NewWriterAppender(logger), logger
+	return logger
 }
 
 // ZapcoreFieldsToJSON will serialize the Field objects into a JSON map of key/value pairs. It's
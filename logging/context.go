@@ -3,6 +3,7 @@ package logging
 import (
 	"context"
 
+	"go.uber.org/zap"
 	"go.viam.com/utils"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
@@ -14,7 +15,10 @@ const emptyTraceKey = ""
 
 type debugLogKeyType int
 
-const debugLogKeyID = debugLogKeyType(iota)
+const (
+	debugLogKeyID = debugLogKeyType(iota)
+	operationIDKeyID
+)
 
 // EnableDebugMode returns a new context with debug logging state attached with a randomly generated
 // log key.
@@ -48,6 +52,29 @@ func GetName(ctx context.Context) string {
 	return emptyTraceKey
 }
 
+// ContextWithOperationID returns a new context with an operation id attached. Lines logged with the
+// context, or a context derived from it, include the id as the "opid" field such that everything
+// logged on behalf of one operation can be found by its id.
+func ContextWithOperationID(ctx context.Context, opid string) context.Context {
+	return context.WithValue(ctx, operationIDKeyID, opid)
+}
+
+// OperationIDFromContext returns the operation id attached to the context, if any.
+func OperationIDFromContext(ctx context.Context) string {
+	if opid, ok := ctx.Value(operationIDKeyID).(string); ok {
+		return opid
+	}
+	return ""
+}
+
+// withOperationID adds the operation id of the context, if any, to the fields of the log entry.
+func withOperationID(ctx context.Context, entry *LogEntry) *LogEntry {
+	if opid := OperationIDFromContext(ctx); opid != "" {
+		entry.Fields = append(entry.Fields, zap.String("opid", opid))
+	}
+	return entry
+}
+
 const dtNameMetadataKey = "dtName"
 
 // UnaryClientInterceptor adds debug directives from the current context (if any) to the
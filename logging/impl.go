@@ -7,6 +7,7 @@ import (
 	"os"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -29,6 +30,9 @@ type (
 		name             string
 		level            AtomicLevel
 		neverDeduplicate bool
+		// dedupOptOut is set by the registry for loggers whose logs the config opts out of
+		// deduplication.
+		dedupOptOut atomic.Bool
 
 		appenders []Appender
 		registry  *Registry
@@ -138,6 +142,7 @@ func (imp *impl) Sublogger(subname string) Logger {
 		newName,
 		NewAtomicLevelAt(imp.level.Get()),
 		false, // allow log deduplication by default
+		atomic.Bool{},
 		imp.appenders,
 		imp.registry,
 		imp.testHelper,
@@ -159,6 +164,10 @@ func (imp *impl) NeverDeduplicate() {
 	imp.neverDeduplicate = true
 }
 
+func (imp *impl) setDeduplicationOptOut(optOut bool) {
+	imp.dedupOptOut.Store(optOut)
+}
+
 func (imp *impl) Named(name string) *zap.SugaredLogger {
 	return imp.AsZap().Named(name)
 }
@@ -263,7 +272,7 @@ func (imp *impl) shouldLog(logLevel Level) bool {
 }
 
 func (imp *impl) Write(entry *LogEntry) {
-	if imp.registry.DeduplicateLogs.Load() && !imp.neverDeduplicate {
+	if imp.registry.DeduplicateLogs.Load() && !imp.neverDeduplicate && !imp.dedupOptOut.Load() {
 		hashkeyedEntry := entry.HashKey()
 
 		// If we have entered a new recentMessage window, output noisy logs from
@@ -401,7 +410,7 @@ func (imp *impl) CDebug(ctx context.Context, args ...interface{}) {
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(DEBUG) || dbgName != emptyTraceKey {
-		imp.Write(imp.format(DEBUG, dbgName, args...))
+		imp.Write(withOperationID(ctx, imp.format(DEBUG, dbgName, args...)))
 	}
 }
 
@@ -418,7 +427,7 @@ func (imp *impl) CDebugf(ctx context.Context, template string, args ...interface
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(DEBUG) || dbgName != emptyTraceKey {
-		imp.Write(imp.formatf(DEBUG, dbgName, template, args...))
+		imp.Write(withOperationID(ctx, imp.formatf(DEBUG, dbgName, template, args...)))
 	}
 }
 
@@ -435,7 +444,7 @@ func (imp *impl) CDebugw(ctx context.Context, msg string, keysAndValues ...inter
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(DEBUG) || dbgName != emptyTraceKey {
-		imp.Write(imp.formatw(DEBUG, dbgName, msg, keysAndValues...))
+		imp.Write(withOperationID(ctx, imp.formatw(DEBUG, dbgName, msg, keysAndValues...)))
 	}
 }
 
@@ -452,7 +461,7 @@ func (imp *impl) CInfo(ctx context.Context, args ...interface{}) {
 
 	// We log if the logger is configured for info, or if there's a trace key.
 	if imp.shouldLog(INFO) || dbgName != emptyTraceKey {
-		imp.Write(imp.format(INFO, dbgName, args...))
+		imp.Write(withOperationID(ctx, imp.format(INFO, dbgName, args...)))
 	}
 }
 
@@ -469,7 +478,7 @@ func (imp *impl) CInfof(ctx context.Context, template string, args ...interface{
 
 	// We log if the logger is configured for info, or if there's a trace key.
 	if imp.shouldLog(INFO) || dbgName != emptyTraceKey {
-		imp.Write(imp.formatf(INFO, dbgName, template, args...))
+		imp.Write(withOperationID(ctx, imp.formatf(INFO, dbgName, template, args...)))
 	}
 }
 
@@ -486,7 +495,7 @@ func (imp *impl) CInfow(ctx context.Context, msg string, keysAndValues ...interf
 
 	// We log if the logger is configured for info, or if there's a trace key.
 	if imp.shouldLog(INFO) || dbgName != emptyTraceKey {
-		imp.Write(imp.formatw(INFO, dbgName, msg, keysAndValues...))
+		imp.Write(withOperationID(ctx, imp.formatw(INFO, dbgName, msg, keysAndValues...)))
 	}
 }
 
@@ -503,7 +512,7 @@ func (imp *impl) CWarn(ctx context.Context, args ...interface{}) {
 
 	// We log if the logger is configured for warn, or if there's a trace key.
 	if imp.shouldLog(WARN) || dbgName != emptyTraceKey {
-		imp.Write(imp.format(WARN, dbgName, args...))
+		imp.Write(withOperationID(ctx, imp.format(WARN, dbgName, args...)))
 	}
 }
 
@@ -520,7 +529,7 @@ func (imp *impl) CWarnf(ctx context.Context, template string, args ...interface{
 
 	// We log if the logger is configured for warn, or if there's a trace key.
 	if imp.shouldLog(WARN) || dbgName != emptyTraceKey {
-		imp.Write(imp.formatf(WARN, dbgName, template, args...))
+		imp.Write(withOperationID(ctx, imp.formatf(WARN, dbgName, template, args...)))
 	}
 }
 
@@ -537,7 +546,7 @@ func (imp *impl) CWarnw(ctx context.Context, msg string, keysAndValues ...interf
 
 	// We log if the logger is configured for warn, or if there's a trace key.
 	if imp.shouldLog(WARN) || dbgName != emptyTraceKey {
-		imp.Write(imp.formatw(WARN, dbgName, msg, keysAndValues...))
+		imp.Write(withOperationID(ctx, imp.formatw(WARN, dbgName, msg, keysAndValues...)))
 	}
 }
 
@@ -554,7 +563,7 @@ func (imp *impl) CError(ctx context.Context, args ...interface{}) {
 
 	// We log if the logger is configured for error, or if there's a trace key.
 	if imp.shouldLog(ERROR) || dbgName != emptyTraceKey {
-		imp.Write(imp.format(ERROR, dbgName, args...))
+		imp.Write(withOperationID(ctx, imp.format(ERROR, dbgName, args...)))
 	}
 }
 
@@ -571,7 +580,7 @@ func (imp *impl) CErrorf(ctx context.Context, template string, args ...interface
 
 	// We log if the logger is configured for error, or if there's a trace key.
 	if imp.shouldLog(ERROR) || dbgName != emptyTraceKey {
-		imp.Write(imp.formatf(ERROR, dbgName, template, args...))
+		imp.Write(withOperationID(ctx, imp.formatf(ERROR, dbgName, template, args...)))
 	}
 }
 
@@ -588,7 +597,7 @@ func (imp *impl) CErrorw(ctx context.Context, msg string, keysAndValues ...inter
 
 	// We log if the logger is configured for error, or if there's a trace key.
 	if imp.shouldLog(ERROR) || dbgName != emptyTraceKey {
-		imp.Write(imp.formatw(ERROR, dbgName, msg, keysAndValues...))
+		imp.Write(withOperationID(ctx, imp.formatw(ERROR, dbgName, msg, keysAndValues...)))
 	}
 }
 
@@ -649,7 +658,7 @@ func (imp *implWith) CDebug(ctx context.Context, args ...interface{}) {
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(DEBUG) || dbgName != emptyTraceKey {
-		entry := imp.format(DEBUG, dbgName, args...)
+		entry := withOperationID(ctx, imp.format(DEBUG, dbgName, args...))
 		entry.Fields = append(entry.Fields, imp.logFields...)
 		imp.Write(entry)
 	}
@@ -670,7 +679,7 @@ func (imp *implWith) CDebugf(ctx context.Context, template string, args ...inter
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(DEBUG) || dbgName != emptyTraceKey {
-		entry := imp.formatf(DEBUG, dbgName, template, args...)
+		entry := withOperationID(ctx, imp.formatf(DEBUG, dbgName, template, args...))
 		entry.Fields = append(entry.Fields, imp.logFields...)
 		imp.Write(entry)
 	}
@@ -691,7 +700,7 @@ func (imp *implWith) CDebugw(ctx context.Context, msg string, keysAndValues ...i
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(DEBUG) || dbgName != emptyTraceKey {
-		entry := imp.formatw(DEBUG, dbgName, msg, keysAndValues...)
+		entry := withOperationID(ctx, imp.formatw(DEBUG, dbgName, msg, keysAndValues...))
 		entry.Fields = append(entry.Fields, imp.logFields...)
 		imp.Write(entry)
 	}
@@ -712,7 +721,7 @@ func (imp *implWith) CInfo(ctx context.Context, args ...interface{}) {
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(INFO) || dbgName != emptyTraceKey {
-		entry := imp.format(INFO, dbgName, args...)
+		entry := withOperationID(ctx, imp.format(INFO, dbgName, args...))
 		entry.Fields = append(entry.Fields, imp.logFields...)
 		imp.Write(entry)
 	}
@@ -733,7 +742,7 @@ func (imp *implWith) CInfof(ctx context.Context, template string, args ...interf
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(INFO) || dbgName != emptyTraceKey {
-		entry := imp.formatf(INFO, dbgName, template, args...)
+		entry := withOperationID(ctx, imp.formatf(INFO, dbgName, template, args...))
 		entry.Fields = append(entry.Fields, imp.logFields...)
 		imp.Write(entry)
 	}
@@ -754,7 +763,7 @@ func (imp *implWith) CInfow(ctx context.Context, msg string, keysAndValues ...in
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(INFO) || dbgName != emptyTraceKey {
-		entry := imp.formatw(INFO, dbgName, msg, keysAndValues...)
+		entry := withOperationID(ctx, imp.formatw(INFO, dbgName, msg, keysAndValues...))
 		entry.Fields = append(entry.Fields, imp.logFields...)
 		imp.Write(entry)
 	}
@@ -775,7 +784,7 @@ func (imp *implWith) CWarn(ctx context.Context, args ...interface{}) {
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(WARN) || dbgName != emptyTraceKey {
-		entry := imp.format(WARN, dbgName, args...)
+		entry := withOperationID(ctx, imp.format(WARN, dbgName, args...))
 		entry.Fields = append(entry.Fields, imp.logFields...)
 		imp.Write(entry)
 	}
@@ -796,7 +805,7 @@ func (imp *implWith) CWarnf(ctx context.Context, template string, args ...interf
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(WARN) || dbgName != emptyTraceKey {
-		entry := imp.formatf(WARN, dbgName, template, args...)
+		entry := withOperationID(ctx, imp.formatf(WARN, dbgName, template, args...))
 		entry.Fields = append(entry.Fields, imp.logFields...)
 		imp.Write(entry)
 	}
@@ -817,7 +826,7 @@ func (imp *implWith) CWarnw(ctx context.Context, msg string, keysAndValues ...in
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(WARN) || dbgName != emptyTraceKey {
-		entry := imp.formatw(WARN, dbgName, msg, keysAndValues...)
+		entry := withOperationID(ctx, imp.formatw(WARN, dbgName, msg, keysAndValues...))
 		entry.Fields = append(entry.Fields, imp.logFields...)
 		imp.Write(entry)
 	}
@@ -838,7 +847,7 @@ func (imp *implWith) CError(ctx context.Context, args ...interface{}) {
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(ERROR) || dbgName != emptyTraceKey {
-		entry := imp.format(ERROR, dbgName, args...)
+		entry := withOperationID(ctx, imp.format(ERROR, dbgName, args...))
 		entry.Fields = append(entry.Fields, imp.logFields...)
 		imp.Write(entry)
 	}
@@ -859,7 +868,7 @@ func (imp *implWith) CErrorf(ctx context.Context, template string, args ...inter
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(ERROR) || dbgName != emptyTraceKey {
-		entry := imp.formatf(ERROR, dbgName, template, args...)
+		entry := withOperationID(ctx, imp.formatf(ERROR, dbgName, template, args...))
 		entry.Fields = append(entry.Fields, imp.logFields...)
 		imp.Write(entry)
 	}
@@ -880,7 +889,7 @@ func (imp *implWith) CErrorw(ctx context.Context, msg string, keysAndValues ...i
 
 	// We log if the logger is configured for debug, or if there's a trace key.
 	if imp.shouldLog(ERROR) || dbgName != emptyTraceKey {
-		entry := imp.formatw(ERROR, dbgName, msg, keysAndValues...)
+		entry := withOperationID(ctx, imp.formatw(ERROR, dbgName, msg, keysAndValues...))
 		entry.Fields = append(entry.Fields, imp.logFields...)
 		imp.Write(entry)
 	}
@@ -290,6 +290,42 @@ func TestContextLogging(t *testing.T) {
 		`2023-10-30T09:12:09.459Z	ERROR	impl	logging/impl_test.go:200	Errorw log	{"traceKey":"foobar","key":"value"}`)
 }
 
+func TestOperationIDLogging(t *testing.T) {
+	notStdout := &bytes.Buffer{}
+	logger := &impl{
+		name:                     "impl",
+		level:                    NewAtomicLevelAt(INFO),
+		appenders:                []Appender{NewWriterAppender(notStdout)},
+		registry:                 newRegistry(),
+		testHelper:               func() {},
+		recentMessageCounts:      make(map[string]int),
+		recentMessageEntries:     make(map[string]LogEntry),
+		recentMessageWindowStart: time.Now(),
+	}
+
+	ctx := ContextWithOperationID(context.Background(), "1234")
+	test.That(t, OperationIDFromContext(ctx), test.ShouldEqual, "1234")
+	test.That(t, OperationIDFromContext(context.Background()), test.ShouldEqual, "")
+
+	logger.CInfo(ctx, "Info log")
+	assertLogMatches(t, notStdout,
+		`2023-10-30T09:12:09.459Z	INFO	impl	logging/impl_test.go:200	Info log	{"opid":"1234"}`)
+
+	logger.CWarnw(EnableDebugModeWithKey(ctx, "foobar"), "Warnw log", "key", "value")
+	assertLogMatches(t, notStdout,
+		`2023-10-30T09:12:09.459Z	WARN	impl	logging/impl_test.go:200	Warnw log	{"traceKey":"foobar","key":"value","opid":"1234"}`)
+
+	// Operation ids are kept by loggers with fields.
+	logger.WithFields("key", "value").CErrorf(ctx, "Errorf log %v", "Errorf")
+	assertLogMatches(t, notStdout,
+		`2023-10-30T09:12:09.459Z	ERROR	impl	logging/impl_test.go:200	Errorf log Errorf	{"opid":"1234","key":"value"}`)
+
+	// Operation ids are only logged with a context.
+	logger.Info("Info log")
+	assertLogMatches(t, notStdout,
+		`2023-10-30T09:12:09.459Z	INFO	impl	logging/impl_test.go:200	Info log`)
+}
+
 func TestSublogger(t *testing.T) {
 	// A logger object that will write to the `notStdout` buffer.
 	notStdout := &bytes.Buffer{}
@@ -642,3 +678,49 @@ func TestNeverDeduplicate(t *testing.T) {
 			`2023-10-30T13:19:45.806Z	INFO	impl	logging/impl_test.go:132	identical message`)
 	}
 }
+
+func TestDeduplicationOptOut(t *testing.T) {
+	registry := newRegistry()
+	registry.DeduplicateLogs.Store(true)
+
+	notStdout := &bytes.Buffer{}
+	logger := &impl{
+		name:                     "impl",
+		level:                    NewAtomicLevelAt(DEBUG),
+		appenders:                []Appender{NewWriterAppender(notStdout)},
+		registry:                 registry,
+		testHelper:               func() {},
+		recentMessageCounts:      make(map[string]int),
+		recentMessageEntries:     make(map[string]LogEntry),
+		recentMessageWindowStart: time.Now(),
+	}
+	registry.registerLogger(logger.name, logger)
+	motorLogger := logger.Sublogger("motor")
+	otherLogger := logger.Sublogger("other")
+
+	// Loggers registered before and after the update are opted out, and invalid patterns are skipped.
+	registry.UpdateDeduplicationOptOuts([]string{"impl.motor", "impl.*.encoder", "!bad"}, logger)
+	encoderLogger := motorLogger.Sublogger("encoder")
+
+	for _, optedOut := range []Logger{motorLogger, encoderLogger} {
+		for range 5 {
+			optedOut.Info("identical message")
+		}
+	}
+	for range 5 {
+		otherLogger.Info("identical message")
+	}
+	output := notStdout.String()
+	test.That(t, strings.Count(output, "impl.motor\t"), test.ShouldEqual, 5)
+	test.That(t, strings.Count(output, "impl.motor.encoder\t"), test.ShouldEqual, 5)
+	test.That(t, strings.Count(output, "impl.other\t"), test.ShouldEqual, noisyMessageCountThreshold)
+	test.That(t, output, test.ShouldContainSubstring, "failed to validate a log deduplication opt out pattern")
+
+	// Removing the patterns deduplicates the logs again.
+	notStdout.Reset()
+	registry.UpdateDeduplicationOptOuts(nil, logger)
+	for range 5 {
+		encoderLogger.Info("another message")
+	}
+	test.That(t, strings.Count(notStdout.String(), "impl.motor.encoder\t"), test.ShouldEqual, noisyMessageCountThreshold)
+}
@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// journaldSocket is where the systemd journal receives entries in its native protocol.
+var journaldSocket = "/run/systemd/journal/socket"
+
+// journaldWriter sends entries to the systemd journal, keeping the logger and caller of each
+// entry as their own fields such that they can be filtered on with journalctl.
+type journaldWriter struct {
+	conn     reconnectingConn
+	facility int
+	tag      string
+}
+
+func newJournaldWriter(conf SinkConfig) (sinkWriter, error) {
+	facility, err := syslogFacility(conf.Facility)
+	if err != nil {
+		return nil, err
+	}
+	dial := func() (net.Conn, error) {
+		return net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journaldSocket, Net: "unixgram"})
+	}
+	conn, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	return &journaldWriter{
+		conn:     reconnectingConn{dial: dial, conn: conn},
+		facility: facility,
+		tag:      conf.tag(),
+	}, nil
+}
+
+func (w *journaldWriter) write(entry sinkEntry) error {
+	var msg bytes.Buffer
+	message := entry.Message
+	if entry.fieldsJSON != "" {
+		message += "\t" + entry.fieldsJSON
+	}
+	writeJournaldField(&msg, "MESSAGE", message)
+	writeJournaldField(&msg, "PRIORITY", strconv.Itoa(syslogSeverity(entry.Level)))
+	writeJournaldField(&msg, "SYSLOG_IDENTIFIER", w.tag)
+	writeJournaldField(&msg, "SYSLOG_FACILITY", strconv.Itoa(w.facility))
+	writeJournaldField(&msg, "VIAM_LOGGER", entry.LoggerName)
+	if entry.Caller.Defined {
+		writeJournaldField(&msg, "CODE_FILE", entry.Caller.File)
+		writeJournaldField(&msg, "CODE_LINE", strconv.Itoa(entry.Caller.Line))
+		if entry.Caller.Function != "" {
+			writeJournaldField(&msg, "CODE_FUNC", entry.Caller.Function)
+		}
+	}
+	return w.conn.write(msg.Bytes())
+}
+
+// writeJournaldField writes a field in the journal's native protocol, where values holding
+// newlines are written with their length instead of ending at a newline.
+func writeJournaldField(msg *bytes.Buffer, key, value string) {
+	msg.WriteString(key)
+	if !strings.Contains(value, "\n") {
+		msg.WriteByte('=')
+		msg.WriteString(value)
+		msg.WriteByte('\n')
+		return
+	}
+	msg.WriteByte('\n')
+	//nolint:errcheck
+	binary.Write(msg, binary.LittleEndian, uint64(len(value)))
+	msg.WriteString(value)
+	msg.WriteByte('\n')
+}
+
+func (w *journaldWriter) close() error {
+	return w.conn.close()
+}
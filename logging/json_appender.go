@@ -0,0 +1,155 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+// The formats appenders can write entries in.
+const (
+	// LogFormatText writes each entry as a human readable, tab separated line. This is the default.
+	LogFormatText = "text"
+	// LogFormatJSON writes each entry as a line holding a JSON object, for log aggregation.
+	LogFormatJSON = "json"
+)
+
+// ValidateLogFormat returns an error if `format` is not a known log format. The empty format is
+// the default text format.
+func ValidateLogFormat(format string) error {
+	switch format {
+	case "", LogFormatText, LogFormatJSON:
+		return nil
+	default:
+		return errors.Errorf("unknown log format %q, expected %q or %q", format, LogFormatText, LogFormatJSON)
+	}
+}
+
+// JSONAppender writes each log entry as a line holding a JSON object, such that logs can be
+// ingested without parsing human readable lines.
+type JSONAppender struct {
+	io.Writer
+}
+
+// NewJSONWriterAppender creates a new appender that writes JSON lines to the input writer.
+func NewJSONWriterAppender(writer io.Writer) JSONAppender {
+	return JSONAppender{writer}
+}
+
+// JSONLogEntry is the JSON object a log entry is written as by JSONAppender, and streamed to
+// clients as.
+type JSONLogEntry struct {
+	// Time uses the same format as the text format, which is also RFC 3339.
+	Time   string `json:"ts"`
+	Level  string `json:"level"`
+	Logger string `json:"logger"`
+	// Resource is the name of the resource the entry was logged by, if any.
+	Resource   string          `json:"resource,omitempty"`
+	Caller     string          `json:"caller,omitempty"`
+	Message    string          `json:"msg"`
+	Fields     json.RawMessage `json:"fields,omitempty"`
+	Stacktrace string          `json:"stacktrace,omitempty"`
+}
+
+// Write outputs the log entry to the underlying stream as a JSON line.
+func (appender JSONAppender) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	line, err := json.Marshal(newJSONEntry(entry, fields))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(appender.Writer, string(line)) //nolint:errcheck
+	return nil
+}
+
+func newJSONEntry(entry zapcore.Entry, fields []zapcore.Field) JSONLogEntry {
+	jEntry := JSONLogEntry{
+		// We use UTC for the same reason as the text format.
+		Time:       entry.Time.UTC().Format(DefaultTimeFormatStr),
+		Level:      entry.Level.String(),
+		Logger:     entry.LoggerName,
+		Resource:   resourceNameFromLogger(entry.LoggerName),
+		Message:    entry.Message,
+		Stacktrace: entry.Stack,
+	}
+	if entry.Caller.Defined {
+		jEntry.Caller = callerToString(&entry.Caller)
+	}
+	if len(fields) > 0 {
+		if fieldsJSON, err := ZapcoreFieldsToJSON(fields); err == nil {
+			jEntry.Fields = json.RawMessage(fieldsJSON)
+		}
+	}
+	return jEntry
+}
+
+// Sync is a no-op.
+func (appender JSONAppender) Sync() error {
+	return nil
+}
+
+// resourceNameFromLogger returns the resource name a logger is named after, such as
+// "rdk:component:camera/cam1" for "rdk.resource_manager.rdk:component:camera/cam1", or the empty
+// string if it is not the logger of a resource or one of its subloggers.
+func resourceNameFromLogger(loggerName string) string {
+	for _, segment := range strings.Split(loggerName, ".") {
+		api, name, found := strings.Cut(segment, "/")
+		if found && name != "" && strings.Count(api, ":") == 2 {
+			return segment
+		}
+	}
+	return ""
+}
+
+// LogFormatSwitch holds the format of appenders created with it, which can be changed while they
+// are in use, e.g. when the machine config changes.
+type LogFormatSwitch struct {
+	json atomic.Bool
+}
+
+// NewLogFormatSwitch creates a LogFormatSwitch for the default text format.
+func NewLogFormatSwitch() *LogFormatSwitch {
+	return &LogFormatSwitch{}
+}
+
+// Set changes the format of the appenders. The empty format is the default text format.
+func (formatSwitch *LogFormatSwitch) Set(format string) error {
+	if err := ValidateLogFormat(format); err != nil {
+		return err
+	}
+	formatSwitch.json.Store(format == LogFormatJSON)
+	return nil
+}
+
+// Get returns the current format.
+func (formatSwitch *LogFormatSwitch) Get() string {
+	if formatSwitch.json.Load() {
+		return LogFormatJSON
+	}
+	return LogFormatText
+}
+
+// NewAppender creates an appender writing to `writer` in the current format of the switch.
+func (formatSwitch *LogFormatSwitch) NewAppender(writer io.Writer) Appender {
+	return &switchedAppender{writer: writer, formatSwitch: formatSwitch}
+}
+
+type switchedAppender struct {
+	writer       io.Writer
+	formatSwitch *LogFormatSwitch
+}
+
+func (appender *switchedAppender) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if appender.formatSwitch.json.Load() {
+		return NewJSONWriterAppender(appender.writer).Write(entry, fields)
+	}
+	return NewWriterAppender(appender.writer).Write(entry, fields)
+}
+
+func (appender *switchedAppender) Sync() error {
+	return nil
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.viam.com/test"
+)
+
+func TestJSONAppender(t *testing.T) {
+	var buf bytes.Buffer
+	appender := NewJSONWriterAppender(&buf)
+	entry := zapcore.Entry{
+		Level:      zapcore.WarnLevel,
+		LoggerName: "rdk.resource_manager.rdk:component:camera/cam1",
+		Message:    "frame dropped",
+		Time:       time.Date(2024, 11, 18, 20, 37, 1, 0, time.UTC),
+		Caller:     zapcore.NewEntryCaller(0, "/src/rdk/camera/camera.go", 12, true),
+	}
+	test.That(t, appender.Write(entry, []zapcore.Field{zap.Int("fps", 3), zap.String("reason", "slow")}), test.ShouldBeNil)
+	test.That(t, appender.Write(zapcore.Entry{Level: zapcore.InfoLevel, LoggerName: "rdk", Message: "started"}, nil), test.ShouldBeNil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	test.That(t, lines, test.ShouldHaveLength, 2)
+	var first map[string]interface{}
+	test.That(t, json.Unmarshal([]byte(lines[0]), &first), test.ShouldBeNil)
+	test.That(t, first, test.ShouldResemble, map[string]interface{}{
+		"ts":       "2024-11-18T20:37:01.000Z",
+		"level":    "warn",
+		"logger":   "rdk.resource_manager.rdk:component:camera/cam1",
+		"resource": "rdk:component:camera/cam1",
+		"caller":   "camera/camera.go:12",
+		"msg":      "frame dropped",
+		"fields":   map[string]interface{}{"fps": 3.0, "reason": "slow"},
+	})
+
+	var second map[string]interface{}
+	test.That(t, json.Unmarshal([]byte(lines[1]), &second), test.ShouldBeNil)
+	test.That(t, second, test.ShouldNotContainKey, "resource")
+	test.That(t, second, test.ShouldNotContainKey, "fields")
+	test.That(t, second["msg"], test.ShouldEqual, "started")
+}
+
+func TestResourceNameFromLogger(t *testing.T) {
+	test.That(t, resourceNameFromLogger("rdk.resource_manager.rdk:service:motion/builtin"), test.ShouldEqual, "rdk:service:motion/builtin")
+	test.That(t, resourceNameFromLogger("rdk.resource_manager.acme:component:arm/arm1.kinematics"), test.ShouldEqual,
+		"acme:component:arm/arm1")
+	test.That(t, resourceNameFromLogger("rdk.modmanager.my-module"), test.ShouldEqual, "")
+	test.That(t, resourceNameFromLogger("rdk.networking"), test.ShouldEqual, "")
+}
+
+func TestLogFormatSwitch(t *testing.T) {
+	var buf bytes.Buffer
+	formatSwitch := NewLogFormatSwitch()
+	appender := formatSwitch.NewAppender(&buf)
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, LoggerName: "rdk", Message: "hello", Time: time.Now()}
+
+	test.That(t, formatSwitch.Get(), test.ShouldEqual, LogFormatText)
+	test.That(t, appender.Write(entry, nil), test.ShouldBeNil)
+	test.That(t, buf.String(), test.ShouldContainSubstring, "\tINFO\trdk\thello\n")
+
+	buf.Reset()
+	test.That(t, formatSwitch.Set(LogFormatJSON), test.ShouldBeNil)
+	test.That(t, appender.Write(entry, nil), test.ShouldBeNil)
+	test.That(t, buf.String(), test.ShouldStartWith, "{")
+	test.That(t, buf.String(), test.ShouldContainSubstring, `"msg":"hello"`)
+
+	test.That(t, formatSwitch.Set("xml"), test.ShouldNotBeNil)
+	test.That(t, formatSwitch.Get(), test.ShouldEqual, LogFormatJSON)
+	test.That(t, formatSwitch.Set(""), test.ShouldBeNil)
+	test.That(t, formatSwitch.Get(), test.ShouldEqual, LogFormatText)
+}
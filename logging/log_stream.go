@@ -0,0 +1,154 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+// logSubscriptionBuffer is how many live entries may wait to be read from a subscription before
+// new entries are dropped.
+const logSubscriptionBuffer = 1000
+
+// LogFilter selects the log entries streamed to a subscriber. The zero value selects all entries
+// logged after subscribing.
+type LogFilter struct {
+	// Level is the lowest level of the entries, e.g. "warn".
+	Level string `json:"level,omitempty"`
+	// Resource selects the entries of a resource and its subloggers, by its full name such as
+	// "rdk:component:arm/arm1" or by its short name such as "arm1".
+	Resource string `json:"resource,omitempty"`
+	// Pattern is a regular expression which the message or the JSON fields of the entries match.
+	Pattern string `json:"pattern,omitempty"`
+	// Since also replays the kept entries logged at or after this time, before streaming new
+	// entries.
+	Since time.Time `json:"since"`
+}
+
+// logMatcher is a compiled LogFilter.
+type logMatcher struct {
+	filter   LogFilter
+	minLevel zapcore.Level
+	pattern  *regexp.Regexp
+}
+
+func (filter LogFilter) compile() (*logMatcher, error) {
+	matcher := &logMatcher{filter: filter, minLevel: zapcore.DebugLevel}
+	if filter.Level != "" {
+		level, err := LevelFromString(filter.Level)
+		if err != nil {
+			return nil, err
+		}
+		matcher.minLevel = level.AsZap()
+	}
+	if filter.Pattern != "" {
+		pattern, err := regexp.Compile(filter.Pattern)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid log pattern")
+		}
+		matcher.pattern = pattern
+	}
+	return matcher, nil
+}
+
+func (matcher *logMatcher) matches(log *recentLog) bool {
+	if log.level < matcher.minLevel {
+		return false
+	}
+	if !matcher.filter.Since.IsZero() && log.time.Before(matcher.filter.Since) {
+		return false
+	}
+	if resource := matcher.filter.Resource; resource != "" {
+		if log.entry.Resource != resource && !strings.HasSuffix(log.entry.Resource, "/"+resource) {
+			return false
+		}
+	}
+	if matcher.pattern != nil {
+		if !matcher.pattern.MatchString(log.entry.Message) && !matcher.pattern.Match(log.entry.Fields) {
+			return false
+		}
+	}
+	return true
+}
+
+// LogSubscription receives the log entries of a RecentLogsAppender which match its filter. Entries
+// are dropped rather than holding up logging when the subscriber falls behind.
+type LogSubscription struct {
+	appender *RecentLogsAppender
+	matcher  *logMatcher
+	logs     chan JSONLogEntry
+	dropped  atomic.Int64
+	// closed is guarded by the mutex of the appender.
+	closed bool
+}
+
+// Logs returns the channel of the matching entries, which is closed when the subscription is.
+func (sub *LogSubscription) Logs() <-chan JSONLogEntry {
+	return sub.logs
+}
+
+// Dropped returns how many matching entries have been dropped as the subscriber fell behind.
+func (sub *LogSubscription) Dropped() int64 {
+	return sub.dropped.Load()
+}
+
+// Close stops the subscription.
+func (sub *LogSubscription) Close() {
+	sub.appender.mu.Lock()
+	defer sub.appender.mu.Unlock()
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(sub.appender.subscriptions, sub)
+	close(sub.logs)
+}
+
+// send must be called with the mutex of the appender held.
+func (sub *LogSubscription) send(log *recentLog) {
+	if !sub.matcher.matches(log) {
+		return
+	}
+	select {
+	case sub.logs <- log.entry:
+	default:
+		sub.dropped.Add(1)
+	}
+}
+
+// subscriptions is the set of subscriptions of a RecentLogsAppender.
+type subscriptions map[*LogSubscription]struct{}
+
+// Subscribe streams the entries which match `filter`, starting with the kept entries logged since
+// its Since time, if set. The subscription must be closed once it's no longer read.
+func (appender *RecentLogsAppender) Subscribe(filter LogFilter) (*LogSubscription, error) {
+	matcher, err := filter.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	appender.mu.Lock()
+	defer appender.mu.Unlock()
+	var replay []JSONLogEntry
+	if !filter.Since.IsZero() {
+		appender.forEachLocked(func(log *recentLog) {
+			if matcher.matches(log) {
+				replay = append(replay, log.entry)
+			}
+		})
+	}
+	sub := &LogSubscription{
+		appender: appender,
+		matcher:  matcher,
+		logs:     make(chan JSONLogEntry, len(replay)+logSubscriptionBuffer),
+	}
+	for _, entry := range replay {
+		sub.logs <- entry
+	}
+	appender.subscriptions[sub] = struct{}{}
+	return sub, nil
+}
@@ -15,11 +15,19 @@ type Registry struct {
 	loggers   map[string]Logger
 	logConfig []LoggerPatternConfig
 
+	// dedupOptOuts match the names of the loggers whose logs are never deduplicated.
+	dedupOptOuts []*regexp.Regexp
+
 	// DeduplicateLogs controls whether to deduplicate logs. Slightly odd to store this on
 	// the registry but preferable to having a global atomic.
 	DeduplicateLogs atomic.Bool
 }
 
+// dedupOptOutSetter is implemented by loggers which deduplicate their logs.
+type dedupOptOutSetter interface {
+	setDeduplicationOptOut(optOut bool)
+}
+
 func newRegistry() *Registry {
 	return &Registry{
 		loggers: make(map[string]Logger),
@@ -98,6 +106,48 @@ func (lr *Registry) Update(logConfig []LoggerPatternConfig, warnLogger Logger) e
 	return nil
 }
 
+// UpdateDeduplicationOptOuts sets the patterns of the loggers whose logs are never deduplicated,
+// e.g. because each repeated line of a resource matters while debugging it. Patterns are the same
+// as those of `Update`, and invalid patterns are warn-logged through the warnLogger.
+func (lr *Registry) UpdateDeduplicationOptOuts(patterns []string, warnLogger Logger) {
+	optOuts := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		if !validatePattern(pattern) {
+			warnLogger.Warnw("failed to validate a log deduplication opt out pattern", "pattern", pattern)
+			continue
+		}
+		r, err := regexp.Compile(buildRegexFromPattern(pattern))
+		if err != nil {
+			warnLogger.Warnw("failed to compile a log deduplication opt out pattern", "pattern", pattern, "error", err)
+			continue
+		}
+		optOuts = append(optOuts, r)
+	}
+
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	lr.dedupOptOuts = optOuts
+	for name, logger := range lr.loggers {
+		lr.applyDeduplicationOptOut(name, logger)
+	}
+}
+
+// applyDeduplicationOptOut must be called with `lr.mu` held.
+func (lr *Registry) applyDeduplicationOptOut(name string, logger Logger) {
+	setter, ok := logger.(dedupOptOutSetter)
+	if !ok {
+		return
+	}
+	optOut := false
+	for _, r := range lr.dedupOptOuts {
+		if r.MatchString(name) {
+			optOut = true
+			break
+		}
+	}
+	setter.setDeduplicationOptOut(optOut)
+}
+
 func (lr *Registry) getRegisteredLoggerNames() []string {
 	lr.mu.RLock()
 	defer lr.mu.RUnlock()
@@ -142,6 +192,7 @@ func (lr *Registry) getOrRegister(name string, logger Logger) Logger {
 	}
 
 	lr.loggers[name] = logger
+	lr.applyDeduplicationOptOut(name, logger)
 	for _, lpc := range lr.logConfig {
 		r, err := regexp.Compile(buildRegexFromPattern(lpc.Pattern))
 		if err != nil {
@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultRecentLogsCapacity is how many log lines viam-server keeps in memory for diagnostics
+// bundles.
+const DefaultRecentLogsCapacity = 10000
+
+// RecentLogsAppender keeps the most recent log lines in memory, formatted like ConsoleAppender
+// lines, such that they can be collected into diagnostics bundles after the fact. It also streams
+// entries to subscribers, see Subscribe.
+type RecentLogsAppender struct {
+	// mu guards the subscriptions alongside the kept entries, such that subscribers get each entry
+	// exactly once whether it's replayed or live.
+	mu sync.Mutex
+	// logs is a ring buffer, where `next` is the index of the oldest entry once it is full.
+	logs          []recentLog
+	next          int
+	full          bool
+	subscriptions subscriptions
+}
+
+// recentLog is a kept log entry.
+type recentLog struct {
+	level zapcore.Level
+	time  time.Time
+	line  string
+	entry JSONLogEntry
+}
+
+// NewRecentLogsAppender creates an appender which keeps the most recent `capacity` log lines.
+func NewRecentLogsAppender(capacity int) *RecentLogsAppender {
+	if capacity <= 0 {
+		capacity = DefaultRecentLogsCapacity
+	}
+	return &RecentLogsAppender{logs: make([]recentLog, capacity), subscriptions: subscriptions{}}
+}
+
+// Write keeps the formatted log entry, replacing the oldest line if the appender is full, and
+// sends it to the subscriptions it matches.
+func (appender *RecentLogsAppender) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	var line strings.Builder
+	if err := NewWriterAppender(&line).Write(entry, fields); err != nil {
+		return err
+	}
+	log := recentLog{
+		level: entry.Level,
+		time:  entry.Time,
+		line:  strings.TrimSuffix(line.String(), "\n"),
+		entry: newJSONEntry(entry, fields),
+	}
+
+	appender.mu.Lock()
+	defer appender.mu.Unlock()
+	appender.logs[appender.next] = log
+	appender.next++
+	if appender.next == len(appender.logs) {
+		appender.next = 0
+		appender.full = true
+	}
+	for sub := range appender.subscriptions {
+		sub.send(&log)
+	}
+	return nil
+}
+
+// Sync is a no-op.
+func (appender *RecentLogsAppender) Sync() error {
+	return nil
+}
+
+// Lines returns the kept log lines, oldest first.
+func (appender *RecentLogsAppender) Lines() []string {
+	appender.mu.Lock()
+	defer appender.mu.Unlock()
+	lines := make([]string, 0, len(appender.logs))
+	appender.forEachLocked(func(log *recentLog) {
+		lines = append(lines, log.line)
+	})
+	return lines
+}
+
+// forEachLocked calls `f` with the kept entries, oldest first. It must be called with `mu` held.
+func (appender *RecentLogsAppender) forEachLocked(f func(log *recentLog)) {
+	if appender.full {
+		for i := appender.next; i < len(appender.logs); i++ {
+			f(&appender.logs[i])
+		}
+	}
+	for i := 0; i < appender.next; i++ {
+		f(&appender.logs[i])
+	}
+}
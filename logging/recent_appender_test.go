@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestRecentLogsAppender(t *testing.T) {
+	appender := NewRecentLogsAppender(3)
+	logger := NewBlankLogger("recent")
+	logger.AddAppender(appender)
+
+	test.That(t, appender.Lines(), test.ShouldBeEmpty)
+	logger.Infow("first", "key", "value")
+	lines := appender.Lines()
+	test.That(t, lines, test.ShouldHaveLength, 1)
+	test.That(t, lines[0], test.ShouldContainSubstring, "INFO\trecent")
+	test.That(t, lines[0], test.ShouldEndWith, "first\t{\"key\":\"value\"}")
+
+	// only the most recent lines are kept, oldest first
+	for i := 0; i < 4; i++ {
+		logger.Info(fmt.Sprintf("line %d", i))
+	}
+	lines = appender.Lines()
+	test.That(t, lines, test.ShouldHaveLength, 3)
+	for i, line := range lines {
+		test.That(t, line, test.ShouldEndWith, fmt.Sprintf("line %d", i+1))
+	}
+}
+
+func TestRecentLogsSubscribe(t *testing.T) {
+	appender := NewRecentLogsAppender(10)
+	logger := NewBlankLogger("rdk")
+	logger.AddAppender(appender)
+	armLogger := logger.Sublogger("resource_manager.rdk:component:arm/arm1")
+	cameraLogger := logger.Sublogger("resource_manager.rdk:component:camera/cam1")
+
+	start := time.Now()
+	armLogger.Warn("joint 2 over temperature")
+	armLogger.Info("moving")
+
+	_, err := appender.Subscribe(LogFilter{Pattern: "("})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	sub, err := appender.Subscribe(LogFilter{Level: "warn", Resource: "arm1", Since: start})
+	test.That(t, err, test.ShouldBeNil)
+	patternSub, err := appender.Subscribe(LogFilter{Pattern: "frame|fps"})
+	test.That(t, err, test.ShouldBeNil)
+
+	armLogger.Error("joint 2 fault")
+	cameraLogger.Error("no frames")
+	cameraLogger.Infow("streaming", "fps", 30)
+	sub.Close()
+	patternSub.Close()
+	// closing again is a no-op
+	sub.Close()
+
+	var messages []string
+	for entry := range sub.Logs() {
+		test.That(t, entry.Resource, test.ShouldEqual, "rdk:component:arm/arm1")
+		messages = append(messages, entry.Message)
+	}
+	// kept entries are replayed before live ones
+	test.That(t, messages, test.ShouldResemble, []string{"joint 2 over temperature", "joint 2 fault"})
+
+	messages = nil
+	for entry := range patternSub.Logs() {
+		messages = append(messages, entry.Message)
+	}
+	test.That(t, messages, test.ShouldResemble, []string{"no frames", "streaming"})
+}
+
+func TestRecentLogsSubscribeDrops(t *testing.T) {
+	appender := NewRecentLogsAppender(10)
+	logger := NewBlankLogger("rdk")
+	logger.AddAppender(appender)
+	sub, err := appender.Subscribe(LogFilter{})
+	test.That(t, err, test.ShouldBeNil)
+	defer sub.Close()
+
+	// logging is never held up by a subscriber which isn't reading
+	for i := 0; i < logSubscriptionBuffer+5; i++ {
+		logger.Info(fmt.Sprintf("line %d", i))
+	}
+	test.That(t, len(sub.Logs()), test.ShouldEqual, logSubscriptionBuffer)
+	test.That(t, sub.Dropped(), test.ShouldEqual, 5)
+}
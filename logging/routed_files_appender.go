@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// DefaultLogFileMaxSizeMB is the size log files are rotated at when no size is set.
+const DefaultLogFileMaxSizeMB = 100
+
+// FileRotation configures when log files are rotated and how many rotated files are kept.
+type FileRotation struct {
+	// MaxSizeMB rotates a file before it grows past this many megabytes. Defaults to
+	// DefaultLogFileMaxSizeMB.
+	MaxSizeMB int
+	// MaxAge rotates a file once its first entry since it was opened or last rotated is this old,
+	// and deletes rotated files older than it. Zero never rotates files by age.
+	MaxAge time.Duration
+	// MaxBackups is how many rotated files are kept for each file. Zero keeps all of them.
+	MaxBackups int
+}
+
+// RoutedFilesAppender writes log entries to files in a directory, choosing the file of each entry
+// by the name of its logger, such that the logs of a resource or module can be read on their own.
+// Each file is rotated on its own.
+type RoutedFilesAppender struct {
+	dir      string
+	rotation FileRotation
+	route    func(loggerName string) string
+	// formatSwitch is the format of the files, or nil for the text format.
+	formatSwitch *LogFormatSwitch
+
+	mu    sync.Mutex
+	files map[string]*routedFile
+}
+
+type routedFile struct {
+	*lumberjack.Logger
+	// openedAt is the time of the first entry written to the file since it was opened or rotated.
+	openedAt time.Time
+}
+
+// NewRoutedFilesAppender creates an appender writing to files in `dir`, which is created if it
+// doesn't exist. `route` returns the name of the file for the entries of a logger. Entries are
+// written in the format of `formatSwitch`, or as text if it is nil.
+func NewRoutedFilesAppender(
+	dir string,
+	rotation FileRotation,
+	route func(loggerName string) string,
+	formatSwitch *LogFormatSwitch,
+) (*RoutedFilesAppender, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	if rotation.MaxSizeMB <= 0 {
+		rotation.MaxSizeMB = DefaultLogFileMaxSizeMB
+	}
+	return &RoutedFilesAppender{
+		dir:          dir,
+		rotation:     rotation,
+		route:        route,
+		formatSwitch: formatSwitch,
+		files:        map[string]*routedFile{},
+	}, nil
+}
+
+// Write writes the log entry to the file of its logger, rotating the file first if it is too old.
+func (appender *RoutedFilesAppender) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	filename := appender.route(entry.LoggerName)
+
+	appender.mu.Lock()
+	defer appender.mu.Unlock()
+	file, ok := appender.files[filename]
+	if !ok {
+		file = &routedFile{
+			Logger: &lumberjack.Logger{
+				Filename:   filepath.Join(appender.dir, filename),
+				MaxSize:    appender.rotation.MaxSizeMB,
+				MaxBackups: appender.rotation.MaxBackups,
+				MaxAge:     maxAgeDays(appender.rotation.MaxAge),
+			},
+			openedAt: entry.Time,
+		}
+		appender.files[filename] = file
+	}
+	if appender.rotation.MaxAge > 0 && entry.Time.Sub(file.openedAt) >= appender.rotation.MaxAge {
+		if err := file.Rotate(); err != nil {
+			return err
+		}
+		file.openedAt = entry.Time
+	}
+	if appender.formatSwitch != nil {
+		return appender.formatSwitch.NewAppender(file).Write(entry, fields)
+	}
+	return NewWriterAppender(file).Write(entry, fields)
+}
+
+// maxAgeDays converts a max age to the whole days lumberjack deletes rotated files after, rounding
+// up such that rotated files are never deleted early.
+func maxAgeDays(maxAge time.Duration) int {
+	if maxAge <= 0 {
+		return 0
+	}
+	const day = 24 * time.Hour
+	return int((maxAge + day - 1) / day)
+}
+
+// Sync is a no-op, as entries are written to their files unbuffered.
+func (appender *RoutedFilesAppender) Sync() error {
+	return nil
+}
+
+// Close closes all of the files.
+func (appender *RoutedFilesAppender) Close() error {
+	appender.mu.Lock()
+	defer appender.mu.Unlock()
+	var err error
+	for _, file := range appender.files {
+		err = multierr.Combine(err, file.Close())
+	}
+	appender.files = map[string]*routedFile{}
+	return err
+}
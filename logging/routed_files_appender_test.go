@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"go.viam.com/test"
+)
+
+func TestRoutedFilesAppender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	appender, err := NewRoutedFilesAppender(dir, FileRotation{MaxAge: time.Hour}, func(loggerName string) string {
+		return strings.SplitN(loggerName, ".", 2)[0] + ".log"
+	}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, appender.Close(), test.ShouldBeNil)
+	}()
+
+	start := time.Now()
+	write := func(loggerName, message string, at time.Time) {
+		t.Helper()
+		entry := zapcore.Entry{Level: zapcore.InfoLevel, LoggerName: loggerName, Message: message, Time: at}
+		test.That(t, appender.Write(entry, nil), test.ShouldBeNil)
+	}
+	write("camera.sub", "camera line", start)
+	write("motor", "motor line", start)
+	write("camera", "second camera line", start.Add(time.Minute))
+
+	readFile := func(name string) string {
+		t.Helper()
+		//nolint:gosec
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		test.That(t, err, test.ShouldBeNil)
+		return string(data)
+	}
+	camera := readFile("camera.log")
+	test.That(t, camera, test.ShouldContainSubstring, "camera line")
+	test.That(t, camera, test.ShouldContainSubstring, "second camera line")
+	test.That(t, camera, test.ShouldNotContainSubstring, "motor line")
+	test.That(t, readFile("motor.log"), test.ShouldContainSubstring, "motor line")
+
+	// files are rotated on their own once they are too old
+	write("camera", "later camera line", start.Add(2*time.Hour))
+	test.That(t, readFile("camera.log"), test.ShouldNotContainSubstring, "second camera line")
+	test.That(t, readFile("camera.log"), test.ShouldContainSubstring, "later camera line")
+	test.That(t, readFile("motor.log"), test.ShouldContainSubstring, "motor line")
+	entries, err := os.ReadDir(dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, entries, test.ShouldHaveLength, 3)
+}
+
+func TestMaxAgeDays(t *testing.T) {
+	test.That(t, maxAgeDays(0), test.ShouldEqual, 0)
+	test.That(t, maxAgeDays(time.Hour), test.ShouldEqual, 1)
+	test.That(t, maxAgeDays(24*time.Hour), test.ShouldEqual, 1)
+	test.That(t, maxAgeDays(25*time.Hour), test.ShouldEqual, 2)
+}
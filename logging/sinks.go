@@ -0,0 +1,280 @@
+package logging
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// The types of log sinks.
+const (
+	// SinkTypeSyslog sends log entries to the local syslog daemon.
+	SinkTypeSyslog = "syslog"
+	// SinkTypeJournald sends log entries to the systemd journal.
+	SinkTypeJournald = "journald"
+	// SinkTypeRemoteSyslog sends log entries to a remote syslog server.
+	SinkTypeRemoteSyslog = "remote_syslog"
+)
+
+// The protocols remote syslog servers can be reached over.
+const (
+	SyslogProtocolUDP = "udp"
+	SyslogProtocolTCP = "tcp"
+	SyslogProtocolTLS = "tls"
+)
+
+const (
+	// DefaultSinkTag is the application name log entries are sent to sinks with when no tag is set.
+	DefaultSinkTag = "viam-server"
+	// sinkQueueSize is how many entries may wait to be sent to a sink before new entries are
+	// dropped, such that a slow or unreachable sink never blocks logging.
+	sinkQueueSize = 1024
+)
+
+// SinkConfig configures a log sink, which receives the log entries of the machine in addition to
+// its usual outputs, such that they can be collected by existing log aggregation. Sinks are set
+// under "log_sinks" in local config files, as cloud configs have no field for them yet.
+type SinkConfig struct {
+	// Type is one of "syslog", "journald" or "remote_syslog".
+	Type string `json:"type"`
+	// Address is the host:port of the server of a remote_syslog sink.
+	Address string `json:"address,omitempty"`
+	// Protocol is how a remote_syslog sink reaches its server: "udp", "tcp" or "tls". Defaults to
+	// "tcp".
+	Protocol string `json:"protocol,omitempty"`
+	// CACertPath is a PEM file of the certificate authorities a "tls" remote_syslog server is
+	// verified with instead of those of the system.
+	CACertPath string `json:"ca_cert_path,omitempty"`
+	// Tag is the application name of the entries. Defaults to DefaultSinkTag.
+	Tag string `json:"tag,omitempty"`
+	// Facility is the syslog facility of the entries, such as "daemon" or "local0". Defaults to
+	// "daemon".
+	Facility string `json:"facility,omitempty"`
+	// Level is the lowest level of entries sent to the sink. Defaults to sending all entries
+	// which are logged.
+	Level string `json:"level,omitempty"`
+}
+
+// Validate returns an error if the sink can't be created from the config.
+func (conf SinkConfig) Validate() error {
+	switch conf.Type {
+	case SinkTypeSyslog, SinkTypeJournald:
+		if conf.Address != "" {
+			return errors.Errorf("address can only be set on %q sinks", SinkTypeRemoteSyslog)
+		}
+	case SinkTypeRemoteSyslog:
+		if conf.Address == "" {
+			return errors.New("address is required")
+		}
+		switch conf.Protocol {
+		case "", SyslogProtocolUDP, SyslogProtocolTCP, SyslogProtocolTLS:
+		default:
+			return errors.Errorf("unknown protocol %q, expected one of %q, %q or %q",
+				conf.Protocol, SyslogProtocolUDP, SyslogProtocolTCP, SyslogProtocolTLS)
+		}
+	case "":
+		return errors.New("type is required")
+	default:
+		return errors.Errorf("unknown type %q, expected one of %q, %q or %q",
+			conf.Type, SinkTypeSyslog, SinkTypeJournald, SinkTypeRemoteSyslog)
+	}
+	if conf.CACertPath != "" && conf.Protocol != SyslogProtocolTLS {
+		return errors.Errorf("ca_cert_path can only be set with the %q protocol", SyslogProtocolTLS)
+	}
+	if _, err := syslogFacility(conf.Facility); err != nil {
+		return err
+	}
+	if conf.Level != "" {
+		if _, err := LevelFromString(conf.Level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (conf SinkConfig) tag() string {
+	if conf.Tag == "" {
+		return DefaultSinkTag
+	}
+	return conf.Tag
+}
+
+// sinkWriter sends formatted entries to a sink. Calls are never concurrent.
+type sinkWriter interface {
+	write(entry sinkEntry) error
+	close() error
+}
+
+// sinkEntry is a log entry with its fields already encoded, as fields may not be safe to read
+// after Write returns.
+type sinkEntry struct {
+	zapcore.Entry
+	fieldsJSON string
+}
+
+// text is the message of the entry with its logger, caller and fields, as the time and level are
+// carried by the sinks themselves.
+func (entry sinkEntry) text() string {
+	toPrint := make([]string, 0, 4)
+	toPrint = append(toPrint, entry.LoggerName)
+	if entry.Caller.Defined {
+		toPrint = append(toPrint, callerToString(&entry.Caller))
+	}
+	toPrint = append(toPrint, entry.Message)
+	if entry.fieldsJSON != "" {
+		toPrint = append(toPrint, entry.fieldsJSON)
+	}
+	return strings.Join(toPrint, "\t")
+}
+
+// sinkAppender queues entries for a sinkWriter which sends them in the background.
+type sinkAppender struct {
+	minLevel zapcore.Level
+	writer   sinkWriter
+	queue    chan sinkEntry
+	done     chan struct{}
+}
+
+func newSinkAppender(conf SinkConfig) (*sinkAppender, error) {
+	minLevel := zapcore.DebugLevel
+	if conf.Level != "" {
+		level, err := LevelFromString(conf.Level)
+		if err != nil {
+			return nil, err
+		}
+		minLevel = level.AsZap()
+	}
+
+	var writer sinkWriter
+	var err error
+	switch conf.Type {
+	case SinkTypeSyslog:
+		writer, err = newLocalSyslogWriter(conf)
+	case SinkTypeJournald:
+		writer, err = newJournaldWriter(conf)
+	case SinkTypeRemoteSyslog:
+		writer, err = newRemoteSyslogWriter(conf)
+	default:
+		err = errors.Errorf("unknown log sink type %q", conf.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	appender := &sinkAppender{
+		minLevel: minLevel,
+		writer:   writer,
+		queue:    make(chan sinkEntry, sinkQueueSize),
+		done:     make(chan struct{}),
+	}
+	go appender.run()
+	return appender, nil
+}
+
+func (appender *sinkAppender) run() {
+	defer close(appender.done)
+	for entry := range appender.queue {
+		// Errors can't be logged without feeding back into the sink. Writers reconnect on the next
+		// entry, and entries which fail to be sent are dropped.
+		//nolint:errcheck
+		appender.writer.write(entry)
+	}
+}
+
+func (appender *sinkAppender) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if entry.Level < appender.minLevel {
+		return nil
+	}
+	sEntry := sinkEntry{Entry: entry}
+	if len(fields) > 0 {
+		if fieldsJSON, err := ZapcoreFieldsToJSON(fields); err == nil {
+			sEntry.fieldsJSON = fieldsJSON
+		}
+	}
+	select {
+	case appender.queue <- sEntry:
+	default:
+	}
+	return nil
+}
+
+// close sends the queued entries and closes the writer.
+func (appender *sinkAppender) close() error {
+	close(appender.queue)
+	<-appender.done
+	return appender.writer.close()
+}
+
+// Sinks is an Appender sending log entries to the log sinks of the machine config. The sinks can
+// be changed with Update while it's in use.
+type Sinks struct {
+	// updateMu serializes updates, such that old sinks can be closed without holding up Write.
+	updateMu sync.Mutex
+	configs  []SinkConfig
+
+	mu        sync.RWMutex
+	appenders []*sinkAppender
+}
+
+// NewSinks creates an Appender without any sinks.
+func NewSinks() *Sinks {
+	return &Sinks{}
+}
+
+// Update replaces the sinks with those of `configs`. Sinks which fail to be created are left
+// out and their errors returned, while the others are used. Nothing is changed if the configs
+// are the same as those of the last update.
+func (sinks *Sinks) Update(configs []SinkConfig) error {
+	sinks.updateMu.Lock()
+	defer sinks.updateMu.Unlock()
+	if reflect.DeepEqual(configs, sinks.configs) {
+		return nil
+	}
+
+	var err error
+	appenders := make([]*sinkAppender, 0, len(configs))
+	for idx, conf := range configs {
+		appender, createErr := newSinkAppender(conf)
+		if createErr != nil {
+			err = multierr.Combine(err, fmt.Errorf("log sink %d (%s): %w", idx, conf.Type, createErr))
+			continue
+		}
+		appenders = append(appenders, appender)
+	}
+	sinks.configs = configs
+
+	sinks.mu.Lock()
+	oldAppenders := sinks.appenders
+	sinks.appenders = appenders
+	sinks.mu.Unlock()
+	for _, appender := range oldAppenders {
+		err = multierr.Combine(err, appender.close())
+	}
+	return err
+}
+
+// Write queues the entry for each sink whose level it meets.
+func (sinks *Sinks) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	sinks.mu.RLock()
+	defer sinks.mu.RUnlock()
+	for _, appender := range sinks.appenders {
+		//nolint:errcheck
+		appender.Write(entry, fields)
+	}
+	return nil
+}
+
+// Sync is a no-op, as entries are sent in the background.
+func (sinks *Sinks) Sync() error {
+	return nil
+}
+
+// Close sends the queued entries and closes all of the sinks.
+func (sinks *Sinks) Close() error {
+	return sinks.Update(nil)
+}
@@ -0,0 +1,178 @@
+package logging
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.viam.com/test"
+)
+
+func TestSinkConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		conf SinkConfig
+		err  string
+	}{
+		{SinkConfig{Type: SinkTypeSyslog, Facility: "local3", Level: "warn"}, ""},
+		{SinkConfig{Type: SinkTypeJournald}, ""},
+		{SinkConfig{Type: SinkTypeRemoteSyslog, Address: "logs:6514", Protocol: "tls", CACertPath: "ca.pem"}, ""},
+		{SinkConfig{}, "type is required"},
+		{SinkConfig{Type: "kafka"}, "unknown type"},
+		{SinkConfig{Type: SinkTypeRemoteSyslog}, "address is required"},
+		{SinkConfig{Type: SinkTypeSyslog, Address: "logs:514"}, "address can only be set"},
+		{SinkConfig{Type: SinkTypeRemoteSyslog, Address: "logs:514", Protocol: "sctp"}, "unknown protocol"},
+		{SinkConfig{Type: SinkTypeRemoteSyslog, Address: "logs:514", CACertPath: "ca.pem"}, "ca_cert_path"},
+		{SinkConfig{Type: SinkTypeSyslog, Facility: "local9"}, "unknown syslog facility"},
+		{SinkConfig{Type: SinkTypeSyslog, Level: "loud"}, "loud"},
+	} {
+		err := tc.conf.Validate()
+		if tc.err == "" {
+			test.That(t, err, test.ShouldBeNil)
+		} else {
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+		}
+	}
+}
+
+func sinkTestEntry(level zapcore.Level, message string) zapcore.Entry {
+	return zapcore.Entry{
+		Level:      level,
+		LoggerName: "rdk.camera",
+		Message:    message,
+		Time:       time.Date(2024, 11, 18, 20, 37, 1, 0, time.UTC),
+	}
+}
+
+func TestRemoteSyslogSinkTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	test.That(t, err, test.ShouldBeNil)
+	defer listener.Close()
+	received := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			// octet counting framing: "<length> <message>"
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(length))
+			if err != nil {
+				return
+			}
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(reader, msg); err != nil {
+				return
+			}
+			received <- string(msg)
+		}
+	}()
+
+	sinks := NewSinks()
+	test.That(t, sinks.Update([]SinkConfig{{
+		Type:     SinkTypeRemoteSyslog,
+		Address:  listener.Addr().String(),
+		Facility: "local0",
+		Tag:      "robot",
+		Level:    "info",
+	}}), test.ShouldBeNil)
+	test.That(t, sinks.Write(sinkTestEntry(zapcore.DebugLevel, "too quiet"), nil), test.ShouldBeNil)
+	test.That(t, sinks.Write(sinkTestEntry(zapcore.WarnLevel, "too hot"), []zapcore.Field{zap.Int("temp", 90)}), test.ShouldBeNil)
+	test.That(t, sinks.Close(), test.ShouldBeNil)
+
+	select {
+	case msg := <-received:
+		// local0 (16) * 8 + warning (4)
+		test.That(t, msg, test.ShouldStartWith, "<132>1 2024-11-18T20:37:01Z ")
+		test.That(t, msg, test.ShouldContainSubstring, " robot ")
+		test.That(t, msg, test.ShouldEndWith, "- - rdk.camera\ttoo hot\t{\"temp\":90}")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for syslog message")
+	}
+	select {
+	case msg := <-received:
+		t.Fatalf("debug entry was sent: %s", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestRemoteSyslogSinkUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+
+	sinks := NewSinks()
+	test.That(t, sinks.Update([]SinkConfig{{
+		Type: SinkTypeRemoteSyslog, Address: conn.LocalAddr().String(), Protocol: SyslogProtocolUDP,
+	}}), test.ShouldBeNil)
+	test.That(t, sinks.Write(sinkTestEntry(zapcore.ErrorLevel, "broken"), nil), test.ShouldBeNil)
+	test.That(t, sinks.Close(), test.ShouldBeNil)
+
+	test.That(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)), test.ShouldBeNil)
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	test.That(t, err, test.ShouldBeNil)
+	// daemon (3) * 8 + error (3), without framing
+	test.That(t, string(buf[:n]), test.ShouldStartWith, "<27>1 ")
+	test.That(t, string(buf[:n]), test.ShouldContainSubstring, " viam-server ")
+	test.That(t, string(buf[:n]), test.ShouldEndWith, "rdk.camera\tbroken")
+}
+
+func TestJournaldSink(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	oldSocket := journaldSocket
+	journaldSocket = socket
+	defer func() {
+		journaldSocket = oldSocket
+	}()
+
+	sinks := NewSinks()
+	test.That(t, sinks.Update([]SinkConfig{{Type: SinkTypeJournald}}), test.ShouldBeNil)
+	entry := sinkTestEntry(zapcore.InfoLevel, "first\nsecond")
+	entry.Caller = zapcore.NewEntryCaller(0, "rdk/camera.go", 12, true)
+	test.That(t, sinks.Write(entry, nil), test.ShouldBeNil)
+	test.That(t, sinks.Close(), test.ShouldBeNil)
+
+	test.That(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)), test.ShouldBeNil)
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	test.That(t, err, test.ShouldBeNil)
+	msg := string(buf[:n])
+	// multiline values are length prefixed
+	test.That(t, msg, test.ShouldStartWith, "MESSAGE\n\x0c\x00\x00\x00\x00\x00\x00\x00first\nsecond\n")
+	test.That(t, msg, test.ShouldContainSubstring, "PRIORITY=6\n")
+	test.That(t, msg, test.ShouldContainSubstring, "SYSLOG_IDENTIFIER=viam-server\n")
+	test.That(t, msg, test.ShouldContainSubstring, "VIAM_LOGGER=rdk.camera\n")
+	test.That(t, msg, test.ShouldContainSubstring, "CODE_LINE=12\n")
+}
+
+func TestSinksUpdate(t *testing.T) {
+	sinks := NewSinks()
+	// sinks which can't be created are reported, without keeping the others from being used
+	err := sinks.Update([]SinkConfig{
+		{Type: SinkTypeRemoteSyslog, Address: "127.0.0.1:1", Protocol: SyslogProtocolUDP},
+		{Type: "kafka"},
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "log sink 1 (kafka)")
+	test.That(t, sinks.appenders, test.ShouldHaveLength, 1)
+
+	test.That(t, sinks.Close(), test.ShouldBeNil)
+	test.That(t, sinks.appenders, test.ShouldHaveLength, 0)
+}
@@ -0,0 +1,210 @@
+package logging
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// sinkDialTimeout bounds how long connecting to a sink may hold up the entries queued for it.
+	sinkDialTimeout = 5 * time.Second
+	// sinkWriteTimeout bounds how long sending one entry to a sink may take.
+	sinkWriteTimeout = 5 * time.Second
+)
+
+// syslogFacilities are the codes of the syslog facilities, from RFC 5424.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+func syslogFacility(name string) (int, error) {
+	if name == "" {
+		return syslogFacilities["daemon"], nil
+	}
+	facility, ok := syslogFacilities[strings.ToLower(name)]
+	if !ok {
+		return 0, errors.Errorf("unknown syslog facility %q", name)
+	}
+	return facility, nil
+}
+
+// syslogSeverity maps a level to its syslog severity, from RFC 5424.
+func syslogSeverity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	default: // "more threatening" levels than errors
+		return 2
+	}
+}
+
+// reconnectingConn is a connection which is redialed after it fails.
+type reconnectingConn struct {
+	dial func() (net.Conn, error)
+	conn net.Conn
+}
+
+// write sends `msg`, redialing and retrying once if the connection has failed.
+func (rc *reconnectingConn) write(msg []byte) error {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if rc.conn == nil {
+			if rc.conn, err = rc.dial(); err != nil {
+				rc.conn = nil
+				return err
+			}
+		}
+		if err = rc.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout)); err == nil {
+			if _, err = rc.conn.Write(msg); err == nil {
+				return nil
+			}
+		}
+		//nolint:errcheck
+		rc.conn.Close()
+		rc.conn = nil
+	}
+	return err
+}
+
+func (rc *reconnectingConn) close() error {
+	if rc.conn == nil {
+		return nil
+	}
+	err := rc.conn.Close()
+	rc.conn = nil
+	return err
+}
+
+// localSyslogSockets are where syslog daemons listen on common platforms.
+var localSyslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// localSyslogWriter sends entries to the local syslog daemon in the traditional format such
+// daemons expect, leaving the hostname out as the daemon adds it.
+type localSyslogWriter struct {
+	conn     reconnectingConn
+	facility int
+	tag      string
+}
+
+func newLocalSyslogWriter(conf SinkConfig) (sinkWriter, error) {
+	facility, err := syslogFacility(conf.Facility)
+	if err != nil {
+		return nil, err
+	}
+	dial := func() (net.Conn, error) {
+		for _, socket := range localSyslogSockets {
+			for _, network := range []string{"unixgram", "unix"} {
+				if conn, err := net.Dial(network, socket); err == nil {
+					return conn, nil
+				}
+			}
+		}
+		return nil, errors.New("no local syslog daemon found")
+	}
+	conn, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	return &localSyslogWriter{
+		conn:     reconnectingConn{dial: dial, conn: conn},
+		facility: facility,
+		tag:      conf.tag(),
+	}, nil
+}
+
+func (w *localSyslogWriter) write(entry sinkEntry) error {
+	msg := fmt.Sprintf("<%d>%s %s[%d]: %s\n",
+		w.facility*8+syslogSeverity(entry.Level), entry.Time.Format(time.Stamp), w.tag, os.Getpid(), entry.text())
+	return w.conn.write([]byte(msg))
+}
+
+func (w *localSyslogWriter) close() error {
+	return w.conn.close()
+}
+
+// remoteSyslogWriter sends entries to a remote syslog server in the RFC 5424 format. Over TCP and
+// TLS, messages are framed by octet counting as in RFC 6587.
+type remoteSyslogWriter struct {
+	conn     reconnectingConn
+	framed   bool
+	facility int
+	tag      string
+	hostname string
+}
+
+func newRemoteSyslogWriter(conf SinkConfig) (sinkWriter, error) {
+	facility, err := syslogFacility(conf.Facility)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: sinkDialTimeout}
+	var dial func() (net.Conn, error)
+	switch conf.Protocol {
+	case SyslogProtocolUDP:
+		dial = func() (net.Conn, error) { return dialer.Dial("udp", conf.Address) }
+	case "", SyslogProtocolTCP:
+		dial = func() (net.Conn, error) { return dialer.Dial("tcp", conf.Address) }
+	case SyslogProtocolTLS:
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if conf.CACertPath != "" {
+			//nolint:gosec
+			caCerts, err := os.ReadFile(conf.CACertPath)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(caCerts) {
+				return nil, errors.Errorf("no certificates found in %s", conf.CACertPath)
+			}
+		}
+		dial = func() (net.Conn, error) { return tls.DialWithDialer(dialer, "tcp", conf.Address, tlsConfig) }
+	default:
+		return nil, errors.Errorf("unknown syslog protocol %q", conf.Protocol)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	// The server is dialed with the first entry, such that an unreachable server doesn't hold up
+	// startup or reconfiguration.
+	return &remoteSyslogWriter{
+		conn:     reconnectingConn{dial: dial},
+		framed:   conf.Protocol != SyslogProtocolUDP,
+		facility: facility,
+		tag:      conf.tag(),
+		hostname: hostname,
+	}, nil
+}
+
+func (w *remoteSyslogWriter) write(entry sinkEntry) error {
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d - - %s",
+		w.facility*8+syslogSeverity(entry.Level), entry.Time.UTC().Format(time.RFC3339Nano),
+		w.hostname, w.tag, os.Getpid(), entry.text())
+	if w.framed {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	return w.conn.write([]byte(msg))
+}
+
+func (w *remoteSyslogWriter) close() error {
+	return w.conn.close()
+}
@@ -0,0 +1,131 @@
+package module
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/ftdc"
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	// ftdcServiceName is the service a module serves its FTDC stats on. It has no published proto.
+	// Its single method takes an empty message and returns a `google.protobuf.Struct` of flattened
+	// metric names to numbers.
+	ftdcServiceName     = "viam.module.v1.FTDCService"
+	ftdcGetStatsMethod  = "/" + ftdcServiceName + "/GetStats"
+	ftdcGetStatsTimeout = 200 * time.Millisecond
+)
+
+type ftdcServiceServer interface {
+	GetStats(ctx context.Context, req *emptypb.Empty) (*structpb.Struct, error)
+}
+
+// ftdcServiceDesc describes the FTDC service by hand, in place of generated code.
+var ftdcServiceDesc = grpc.ServiceDesc{
+	ServiceName: ftdcServiceName,
+	HandlerType: (*ftdcServiceServer)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "GetStats",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			req := &emptypb.Empty{}
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return srv.(ftdcServiceServer).GetStats(ctx, req)
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: ftdcGetStatsMethod}
+			return interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+				return srv.(ftdcServiceServer).GetStats(ctx, req.(*emptypb.Empty))
+			})
+		},
+	}},
+	Streams: []grpc.StreamDesc{},
+}
+
+// ftdcServer serves the stats of a module's FTDC registry.
+type ftdcServer struct {
+	registry *ftdc.Registry
+}
+
+func (fs *ftdcServer) GetStats(ctx context.Context, req *emptypb.Empty) (*structpb.Struct, error) {
+	readings, err := ftdc.Flatten(fs.registry.Stats())
+	if err != nil {
+		return nil, err
+	}
+	ret := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(readings))}
+	for _, reading := range readings {
+		ret.Fields[reading.MetricName] = structpb.NewNumberValue(float64(reading.Value))
+	}
+	return ret, nil
+}
+
+// FTDC returns the registry of the module's custom FTDC metrics. The module's resources that
+// implement `ftdc.Statser` are registered under their resource names while they exist. Other
+// statsers may be added and removed at any time, such as for metrics shared by a module's
+// resources. viam-server records the metrics of the registry in its FTDC file, as
+// `modules.<module name>.<statser name>.<field>`.
+func (m *Module) FTDC() *ftdc.Registry {
+	return m.ftdcStats
+}
+
+// addResourceStatser registers the resource's stats with the module's FTDC registry, if it has
+// any.
+func (m *Module) addResourceStatser(name string, res any) {
+	statser, ok := res.(ftdc.Statser)
+	if !ok {
+		return
+	}
+	if err := m.ftdcStats.Add(name, statser); err != nil {
+		m.logger.Warnw("Unable to record resource stats in FTDC", "resource", name, "err", err)
+	}
+}
+
+// moduleStatser is viam-server's statser of a module's custom FTDC metrics. Each call to `Stats`
+// asks the module for the current stats of its registry.
+type moduleStatser struct {
+	conn   grpc.ClientConnInterface
+	logger logging.Logger
+	// unsupported is set once the module is found to not serve FTDC stats. E.g: it was built with
+	// an older or non-Go SDK.
+	unsupported atomic.Bool
+}
+
+// NewFTDCStatser returns a statser of the custom FTDC metrics of the module served over the
+// connection. See `Module.FTDC`. Modules that do not serve FTDC stats have no metrics.
+func NewFTDCStatser(conn grpc.ClientConnInterface, logger logging.Logger) ftdc.Statser {
+	return &moduleStatser{conn: conn, logger: logger}
+}
+
+func (ms *moduleStatser) Stats() any {
+	ret := map[string]float32{}
+	if ms.unsupported.Load() {
+		return ret
+	}
+
+	// FTDC reads every statser in turn, so a module that is slow to respond must not hold up the
+	// others.
+	ctx, cancel := context.WithTimeout(context.Background(), ftdcGetStatsTimeout)
+	defer cancel()
+	resp := &structpb.Struct{}
+	if err := ms.conn.Invoke(ctx, ftdcGetStatsMethod, &emptypb.Empty{}, resp); err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			ms.unsupported.Store(true)
+		} else {
+			ms.logger.Debugw("Error getting module FTDC stats", "err", err)
+		}
+		return ret
+	}
+	for name, value := range resp.GetFields() {
+		ret[name] = float32(value.GetNumberValue())
+	}
+	return ret
+}
@@ -38,9 +38,11 @@ import (
 	modlib "go.viam.com/rdk/module"
 	modmanageroptions "go.viam.com/rdk/module/modmanager/options"
 	"go.viam.com/rdk/module/modmaninterface"
+	"go.viam.com/rdk/module/wasm"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot/packages"
+	"go.viam.com/rdk/tracing"
 	rutils "go.viam.com/rdk/utils"
 )
 
@@ -92,7 +94,10 @@ type module struct {
 	process    pexec.ManagedProcess
 	handles    modlib.HandlerMap
 	sharedConn rdkgrpc.SharedConn
-	client     pb.ModuleServiceClient
+	// wasm is the loaded module if the module is a WASM module, which runs in-process instead of as
+	// a module process.
+	wasm   *wasm.Module
+	client pb.ModuleServiceClient
 	// robotClient supplements the ModuleServiceClient client to serve select robot level methods from the module server
 	robotClient robotpb.RobotServiceClient
 	addr        string
@@ -406,23 +411,31 @@ func (mgr *Manager) startModule(ctx context.Context, mod *module) error {
 		ctx, "Waiting for module to complete startup and registration", "module", mod.cfg.Name, mod.logger)
 	defer cleanup()
 
-	if err := mgr.startModuleProcess(mod); err != nil {
-		return errors.WithMessage(err, "error while starting module "+mod.cfg.Name)
-	}
+	if exePath, err := mod.cfg.EvaluateExePath(packages.LocalPackagesDir(mgr.packagesDir)); err == nil && wasm.IsWASM(exePath) {
+		// WASM modules run in-process, so there is no process to start or connection to dial.
+		if mod.wasm, err = wasm.Load(ctx, mod.cfg.Name, exePath, mod.logger); err != nil {
+			return errors.WithMessage(err, "error while loading WASM module "+mod.cfg.Name)
+		}
+		mod.handles = mod.wasm.Handles()
+	} else {
+		if err := mgr.startModuleProcess(mod); err != nil {
+			return errors.WithMessage(err, "error while starting module "+mod.cfg.Name)
+		}
 
-	// Does a gRPC dial. Sets up a SharedConn with a PeerConnection that is not yet connected.
-	if err := mod.dial(); err != nil {
-		return errors.WithMessage(err, "error while dialing module "+mod.cfg.Name)
-	}
+		// Does a gRPC dial. Sets up a SharedConn with a PeerConnection that is not yet connected.
+		if err := mod.dial(); err != nil {
+			return errors.WithMessage(err, "error while dialing module "+mod.cfg.Name)
+		}
 
-	// Sends a ReadyRequest and waits on a ReadyResponse. The PeerConnection will async connect
-	// after this, so long as the module supports it.
-	if err := mod.checkReady(ctx, mgr.parentAddr); err != nil {
-		return errors.WithMessage(err, "error while waiting for module to be ready "+mod.cfg.Name)
-	}
+		// Sends a ReadyRequest and waits on a ReadyResponse. The PeerConnection will async connect
+		// after this, so long as the module supports it.
+		if err := mod.checkReady(ctx, mgr.parentAddr); err != nil {
+			return errors.WithMessage(err, "error while waiting for module to be ready "+mod.cfg.Name)
+		}
 
-	if pc := mod.sharedConn.PeerConn(); mgr.modPeerConnTracker != nil && pc != nil {
-		mgr.modPeerConnTracker.Add(mod.cfg.Name, pc)
+		if pc := mod.sharedConn.PeerConn(); mgr.modPeerConnTracker != nil && pc != nil {
+			mgr.modPeerConnTracker.Add(mod.cfg.Name, pc)
+		}
 	}
 
 	mod.registerResources(mgr)
@@ -516,25 +529,33 @@ func (mgr *Manager) closeModule(mod *module, reconfigure bool) error {
 		mod.logger.Warnw("Forcing removal of module with active resources", "module", mod.cfg.Name)
 	}
 
-	// need to actually close the resources within the module itself before stopping
-	for res := range mod.resources {
-		_, err := mod.client.RemoveResource(context.Background(), &pb.RemoveResourceRequest{Name: res.String()})
-		if err != nil {
-			mod.logger.Errorw("Error removing resource", "module", mod.cfg.Name, "resource", res.Name, "error", err)
-		} else {
-			mod.logger.Infow("Successfully removed resource from module", "module", mod.cfg.Name, "resource", res.Name)
+	if mod.wasm != nil {
+		// closing a WASM module closes its resources
+		if err := mod.wasm.Close(context.Background()); err != nil {
+			mod.logger.Errorw("Error closing WASM module", "module", mod.cfg.Name, "error", err)
+		}
+		mod.wasm = nil
+	} else {
+		// need to actually close the resources within the module itself before stopping
+		for res := range mod.resources {
+			_, err := mod.client.RemoveResource(context.Background(), &pb.RemoveResourceRequest{Name: res.String()})
+			if err != nil {
+				mod.logger.Errorw("Error removing resource", "module", mod.cfg.Name, "resource", res.Name, "error", err)
+			} else {
+				mod.logger.Infow("Successfully removed resource from module", "module", mod.cfg.Name, "resource", res.Name)
+			}
 		}
-	}
 
-	if err := mod.stopProcess(); err != nil {
-		return errors.WithMessage(err, "error while stopping module "+mod.cfg.Name)
-	}
+		if err := mod.stopProcess(); err != nil {
+			return errors.WithMessage(err, "error while stopping module "+mod.cfg.Name)
+		}
 
-	if mgr.modPeerConnTracker != nil {
-		mgr.modPeerConnTracker.Remove(mod.cfg.Name)
-	}
-	if err := mod.sharedConn.Close(); err != nil {
-		mod.logger.Warnw("Error closing connection to module", "error", err)
+		if mgr.modPeerConnTracker != nil {
+			mgr.modPeerConnTracker.Remove(mod.cfg.Name)
+		}
+		if err := mod.sharedConn.Close(); err != nil {
+			mod.logger.Warnw("Error closing connection to module", "error", err)
+		}
 	}
 
 	mod.deregisterResources()
@@ -572,6 +593,18 @@ func (mgr *Manager) addResource(ctx context.Context, conf resource.Config, deps
 
 	mod.logger.CInfow(ctx, "Adding resource to module", "resource", conf.Name, "module", mod.cfg.Name)
 
+	if mod.wasm != nil {
+		res, err := mod.wasm.AddResource(ctx, conf)
+		if err != nil {
+			return nil, err
+		}
+		mgr.rMap.Store(conf.ResourceName(), mod)
+		mod.resourcesMu.Lock()
+		defer mod.resourcesMu.Unlock()
+		mod.resources[conf.ResourceName()] = &addedResource{conf, deps}
+		return res, nil
+	}
+
 	confProto, err := config.ComponentConfigToProto(&conf)
 	if err != nil {
 		return nil, err
@@ -605,13 +638,19 @@ func (mgr *Manager) ReconfigureResource(ctx context.Context, conf resource.Confi
 
 	mod.logger.CInfow(ctx, "Reconfiguring resource for module", "resource", conf.Name, "module", mod.cfg.Name)
 
-	confProto, err := config.ComponentConfigToProto(&conf)
-	if err != nil {
-		return err
-	}
-	_, err = mod.client.ReconfigureResource(ctx, &pb.ReconfigureResourceRequest{Config: confProto, Dependencies: deps})
-	if err != nil {
-		return err
+	if mod.wasm != nil {
+		if err := mod.wasm.ReconfigureResource(ctx, conf); err != nil {
+			return err
+		}
+	} else {
+		confProto, err := config.ComponentConfigToProto(&conf)
+		if err != nil {
+			return err
+		}
+		_, err = mod.client.ReconfigureResource(ctx, &pb.ReconfigureResourceRequest{Config: confProto, Dependencies: deps})
+		if err != nil {
+			return err
+		}
 	}
 
 	mod.resourcesMu.Lock()
@@ -680,7 +719,12 @@ func (mgr *Manager) RemoveResource(ctx context.Context, name resource.Name) erro
 
 	mgr.rMap.Delete(name)
 	delete(mod.resources, name)
-	_, err := mod.client.RemoveResource(ctx, &pb.RemoveResourceRequest{Name: name.String()})
+	var err error
+	if mod.wasm != nil {
+		err = mod.wasm.RemoveResource(ctx, name)
+	} else {
+		_, err = mod.client.RemoveResource(ctx, &pb.RemoveResourceRequest{Name: name.String()})
+	}
 	if err != nil {
 		return err
 	}
@@ -701,6 +745,10 @@ func (mgr *Manager) ValidateConfig(ctx context.Context, conf resource.Config) ([
 			errors.Errorf("no module registered to serve resource api %s and model %s",
 				conf.API, conf.Model)
 	}
+	// WASM modules cannot call other resources, so their resources have no dependencies to validate.
+	if mod.wasm != nil {
+		return nil, nil
+	}
 
 	confProto, err := config.ComponentConfigToProto(&conf)
 	if err != nil {
@@ -928,6 +976,7 @@ func (mgr *Manager) newOnUnexpectedExitHandler(mod *module) func(exitCode int) b
 
 		if mgr.ftdc != nil {
 			mgr.ftdc.Remove(mod.getFTDCName())
+			mgr.ftdc.Remove(mod.getCustomFTDCName())
 		}
 
 		// If attemptRestart returns any orphaned resource names, restart failed,
@@ -1084,10 +1133,12 @@ func (m *module) dial() error {
 			rdkgrpc.EnsureTimeoutUnaryClientInterceptor,
 			grpc_retry.UnaryClientInterceptor(),
 			operation.UnaryClientInterceptor,
+			tracing.UnaryClientInterceptor,
 		),
 		grpc.WithChainStreamInterceptor(
 			grpc_retry.StreamClientInterceptor(),
 			operation.StreamClientInterceptor,
+			tracing.StreamClientInterceptor,
 		),
 	)
 	if err != nil {
@@ -1328,6 +1379,7 @@ func (m *module) stopProcess() error {
 		// while it's in shutdown.
 		if m.ftdc != nil {
 			m.ftdc.Remove(m.getFTDCName())
+			m.ftdc.Remove(m.getCustomFTDCName())
 		}
 	}()
 
@@ -1432,11 +1484,21 @@ func (m *module) getFTDCName() string {
 	return fmt.Sprintf("proc.modules.%s", m.process.ID())
 }
 
+// getCustomFTDCName is the FTDC section of the metrics the module records itself. See
+// `modlib.Module.FTDC`.
+func (m *module) getCustomFTDCName() string {
+	return fmt.Sprintf("modules.%s", m.cfg.Name)
+}
+
 func (m *module) registerProcessWithFTDC() {
 	if m.ftdc == nil {
 		return
 	}
 
+	// The module's own metrics are asked of it over its connection once it is up, whether or not
+	// its process can be monitored.
+	m.ftdc.Add(m.getCustomFTDCName(), modlib.NewFTDCStatser(m.sharedConn.GrpcConn(), m.logger))
+
 	pid, err := m.process.UnixPid()
 	if err != nil {
 		m.logger.Warnw("Module process has no pid. Cannot start ftdc.", "err", err)
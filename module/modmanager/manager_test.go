@@ -1536,6 +1536,70 @@ func TestFTDCAfterModuleCrash(t *testing.T) {
 	test.That(t, numModuleElapsedTimeMetricsSeen, test.ShouldBeGreaterThan, 0)
 }
 
+// TestModularResourceFTDC asserts that the stats of modular resources are recorded in FTDC while
+// the resources exist.
+func TestModularResourceFTDC(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	modCfg := config.Module{
+		Name:    "test-module",
+		ExePath: rtestutils.BuildTempModule(t, "module/testmodule2"),
+		Type:    config.ModuleTypeLocal,
+	}
+
+	ctx := context.Background()
+	parentAddr := setupSocketWithRobot(t)
+	opts := modmanageroptions.Options{UntrustedEnv: false}
+	ftdcData := bytes.NewBuffer(nil)
+	opts.FTDC = ftdc.NewWithWriter(ftdcData, logger)
+	// As with `TestFTDCAfterModuleCrash`, FTDC runs in the background and the test sleeps long
+	// enough for datums to be written.
+	opts.FTDC.Start()
+
+	mgr := setupModManager(t, ctx, parentAddr, logger, opts)
+	test.That(t, mgr.Add(ctx, modCfg), test.ShouldBeNil)
+
+	// testmodule2's helper records how many commands it was sent.
+	res, err := mgr.AddResource(ctx, resource.Config{
+		Name:  "foo",
+		API:   generic.API,
+		Model: resource.NewModel("rdk", "test", "helper2"),
+	}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	for range 2 {
+		_, err = res.DoCommand(ctx, map[string]interface{}{"command": "echo"})
+		test.That(t, err, test.ShouldBeNil)
+	}
+	time.Sleep(2 * time.Second)
+
+	test.That(t, mgr.RemoveResource(ctx, generic.Named("foo")), test.ShouldBeNil)
+	time.Sleep(2 * time.Second)
+
+	mgr.Close(ctx)
+	opts.FTDC.StopAndJoin(ctx)
+
+	datums, err := ftdc.Parse(ftdcData)
+	test.That(t, err, test.ShouldBeNil)
+	const metricName = "modules.test-module.rdk:component:generic/foo.NumCommands"
+	seen, seenSinceRemoved := false, false
+	for _, datum := range datums {
+		found := false
+		for _, reading := range datum.Readings {
+			if reading.MetricName == metricName && reading.Value == 2 {
+				found = true
+			}
+		}
+		if found {
+			seen = true
+			seenSinceRemoved = true
+		} else if seen {
+			seenSinceRemoved = false
+		}
+	}
+	test.That(t, seen, test.ShouldBeTrue)
+	// The stats of the removed resource are no longer recorded.
+	test.That(t, seenSinceRemoved, test.ShouldBeFalse)
+}
+
 func TestFirstRun(t *testing.T) {
 	t.Run("fails", func(t *testing.T) {
 		ctx := context.Background()
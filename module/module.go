@@ -31,6 +31,7 @@ import (
 
 	"go.viam.com/rdk/components/camera/rtppassthrough"
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/ftdc"
 	rgrpc "go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
@@ -38,6 +39,7 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot/client"
 	"go.viam.com/rdk/services/discovery"
+	"go.viam.com/rdk/tracing"
 	rutils "go.viam.com/rdk/utils"
 )
 
@@ -192,8 +194,10 @@ type Module struct {
 	activeBackgroundWorkers sync.WaitGroup
 	handlers                HandlerMap
 	collections             map[resource.API]resource.APIResourceCollection[resource.Resource]
+	ftdcStats               *ftdc.Registry
 	resLoggers              map[resource.Resource]logging.Logger
 	closeOnce               sync.Once
+	stopTracing             func(context.Context) error
 	pc                      *webrtc.PeerConnection
 	pcReady                 <-chan struct{}
 	pcClosed                <-chan struct{}
@@ -210,9 +214,11 @@ func NewModule(ctx context.Context, address string, logger logging.Logger) (*Mod
 	opMgr := operation.NewManager(logger)
 	unaries := []grpc.UnaryServerInterceptor{
 		rgrpc.EnsureTimeoutUnaryServerInterceptor,
+		tracing.UnaryServerInterceptor,
 		opMgr.UnaryServerInterceptor,
 	}
 	streams := []grpc.StreamServerInterceptor{
+		tracing.StreamServerInterceptor,
 		opMgr.StreamServerInterceptor,
 	}
 	opts := []grpc.ServerOption{
@@ -225,6 +231,16 @@ func NewModule(ctx context.Context, address string, logger logging.Logger) (*Mod
 	// If the env variable does not exist, the empty string is returned.
 	modName, _ := os.LookupEnv("VIAM_MODULE_NAME")
 
+	serviceName := modName
+	if serviceName == "" {
+		serviceName = filepath.Base(os.Args[0])
+	}
+	stopTracing, err := tracing.Start(ctx, serviceName)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
 	m := &Module{
 		name:                  modName,
 		shutdownCtx:           cancelCtx,
@@ -238,7 +254,9 @@ func NewModule(ctx context.Context, address string, logger logging.Logger) (*Mod
 		ready:                 true,
 		handlers:              HandlerMap{},
 		collections:           map[resource.API]resource.APIResourceCollection[resource.Resource]{},
+		ftdcStats:             ftdc.NewRegistry(),
 		resLoggers:            map[resource.Resource]logging.Logger{},
+		stopTracing:           stopTracing,
 	}
 	if err := m.server.RegisterServiceServer(ctx, &pb.ModuleService_ServiceDesc, m); err != nil {
 		return nil, err
@@ -246,6 +264,9 @@ func NewModule(ctx context.Context, address string, logger logging.Logger) (*Mod
 	if err := m.server.RegisterServiceServer(ctx, &streampb.StreamService_ServiceDesc, m); err != nil {
 		return nil, err
 	}
+	if err := m.server.RegisterServiceServer(ctx, &ftdcServiceDesc, &ftdcServer{registry: m.ftdcStats}); err != nil {
+		return nil, err
+	}
 	// We register the RobotService API to supplement the ModuleService in order to serve select robot level methods from the module server
 	if err := m.server.RegisterServiceServer(ctx, &robotpb.RobotService_ServiceDesc, m); err != nil {
 		return nil, err
@@ -338,6 +359,9 @@ func (m *Module) Close(ctx context.Context) {
 			m.logger.Error(err)
 		}
 		m.activeBackgroundWorkers.Wait()
+		if err := m.stopTracing(ctx); err != nil {
+			m.logger.Error(err)
+		}
 	})
 }
 
@@ -550,6 +574,7 @@ func (m *Module) AddResource(ctx context.Context, req *pb.AddResourceRequest) (*
 	}
 
 	m.resLoggers[res] = resLogger
+	m.addResourceStatser(conf.ResourceName().String(), res)
 
 	// add the video stream resources upon creation
 	if passthroughSource != nil {
@@ -631,10 +656,12 @@ func (m *Module) ReconfigureResource(ctx context.Context, req *pb.ReconfigureRes
 		return nil, errors.Errorf("invariant: no constructor for %q", conf.API)
 	}
 
+	m.ftdcStats.Remove(conf.ResourceName().String())
 	newRes, err := resInfo.Constructor(ctx, deps, *conf, m.logger)
 	if err != nil {
 		return nil, err
 	}
+	m.addResourceStatser(conf.ResourceName().String(), newRes)
 	var passthroughSource rtppassthrough.Source
 	if p, ok := newRes.(rtppassthrough.Source); ok {
 		passthroughSource = p
@@ -703,6 +730,7 @@ func (m *Module) RemoveResource(ctx context.Context, req *pb.RemoveResourceReque
 
 	delete(m.streamSourceByName, res.Name())
 	delete(m.activeResourceStreams, res.Name())
+	m.ftdcStats.Remove(name.String())
 
 	return &pb.RemoveResourceResponse{}, coll.Remove(name)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -80,7 +81,17 @@ type helper struct {
 	resource.Named
 	resource.TriviallyReconfigurable
 	resource.TriviallyCloseable
-	logger logging.Logger
+	logger      logging.Logger
+	numCommands atomic.Int64
+}
+
+type helperStats struct {
+	NumCommands int64
+}
+
+// Stats is recorded in viam-server's FTDC. For testing the FTDC metrics of modular resources.
+func (h *helper) Stats() any {
+	return helperStats{NumCommands: h.numCommands.Load()}
 }
 
 // DoCommand is the only method of this component. It looks up the "real" command from the map it's passed.
@@ -91,6 +102,7 @@ func (h *helper) DoCommand(ctx context.Context, req map[string]interface{}) (map
 	if !ok {
 		return nil, errors.New("missing 'command' string")
 	}
+	h.numCommands.Add(1)
 
 	switch req["command"] {
 	case "sleep":
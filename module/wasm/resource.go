@@ -0,0 +1,140 @@
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// wasmResource is a resource provided by a WASM module, running in its own instance of the module.
+// It implements sensor.Sensor, which is a superset of the generic component and service APIs.
+//
+// Canceling a call interrupts the module, which closes the instance. The next call then runs in a
+// new instance configured with the resource's last config, losing whatever state the resource kept
+// in memory.
+type wasmResource struct {
+	resource.Named
+	mod *Module
+
+	mu     sync.Mutex
+	conf   resource.Config
+	inst   *instance
+	closed bool
+}
+
+var _ sensor.Sensor = (*wasmResource)(nil)
+
+// newResource creates and configures a resource in a new instance of `mod`.
+func newResource(ctx context.Context, mod *Module, conf resource.Config) (*wasmResource, error) {
+	inst, err := mod.instantiate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := configureInstance(ctx, inst, conf); err != nil {
+		return nil, multierr.Combine(err, inst.close(ctx))
+	}
+	return &wasmResource{Named: conf.ResourceName().AsNamed(), mod: mod, conf: conf, inst: inst}, nil
+}
+
+// configureInstance passes a resource's config to an instance of the module.
+func configureInstance(ctx context.Context, inst *instance, conf resource.Config) error {
+	_, err := inst.call(ctx, "configure", map[string]interface{}{
+		"name":       conf.Name,
+		"api":        conf.API.String(),
+		"model":      conf.Model.String(),
+		"attributes": conf.Attributes,
+	})
+	return err
+}
+
+// instance returns the resource's instance of the module, replacing it if it was interrupted.
+func (res *wasmResource) instance(ctx context.Context) (*instance, error) {
+	res.mu.Lock()
+	defer res.mu.Unlock()
+	if res.closed {
+		return nil, errors.Errorf("resource %s is closed", res.Name())
+	}
+	if !res.inst.mod.IsClosed() {
+		return res.inst, nil
+	}
+	res.mod.logger.CWarnw(ctx, "Restarting WASM resource that was interrupted", "resource", res.Name())
+	inst, err := res.mod.instantiate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := configureInstance(ctx, inst, res.conf); err != nil {
+		return nil, multierr.Combine(err, inst.close(ctx))
+	}
+	res.inst = inst
+	return inst, nil
+}
+
+func (res *wasmResource) call(ctx context.Context, method string, args interface{}) (map[string]interface{}, error) {
+	inst, err := res.instance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return inst.call(ctx, method, args)
+}
+
+// Reconfigure passes the resource's new config to the module. WASM modules cannot call other
+// resources, so `deps` is unused.
+func (res *wasmResource) Reconfigure(ctx context.Context, _ resource.Dependencies, conf resource.Config) error {
+	inst, err := res.instance(ctx)
+	if err != nil {
+		return err
+	}
+	if err := configureInstance(ctx, inst, conf); err != nil {
+		return err
+	}
+	res.mu.Lock()
+	defer res.mu.Unlock()
+	res.conf = conf
+	return nil
+}
+
+// Readings returns the readings of the sensor.
+func (res *wasmResource) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	return res.call(ctx, "readings", extra)
+}
+
+// DoCommand sends a command to the resource.
+func (res *wasmResource) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return res.call(ctx, "do_command", cmd)
+}
+
+// Close closes the resource's instance of the module.
+func (res *wasmResource) Close(ctx context.Context) error {
+	res.mu.Lock()
+	defer res.mu.Unlock()
+	if res.closed {
+		return nil
+	}
+	res.closed = true
+	return res.inst.close(ctx)
+}
+
+// logWriter logs what a WASM module writes to stdout or stderr, a line at a time.
+type logWriter struct {
+	logger logging.Logger
+	module string
+	isErr  bool
+}
+
+func (w logWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(bytes.TrimRight(p, "\n"), []byte("\n")) {
+		if w.isErr {
+			w.logger.Errorw(string(line), "module", w.module)
+		} else {
+			w.logger.Infow(string(line), "module", w.module)
+		}
+	}
+	return len(p), nil
+}
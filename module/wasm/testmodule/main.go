@@ -0,0 +1,82 @@
+//go:build wasip1
+
+// Package main is a WASM module for testing. It provides a sensor that counts how often it was
+// read, starting from its "start" attribute, and echoes commands unless told to spin forever.
+package main
+
+import (
+	"encoding/json"
+	"unsafe"
+)
+
+func main() {}
+
+// buffers keeps memory handed to the host alive until the host frees it.
+var buffers = map[uint32][]byte{}
+
+//go:wasmexport viam_alloc
+func viamAlloc(size uint32) uint32 {
+	buf := make([]byte, size)
+	ptr := uint32(uintptr(unsafe.Pointer(unsafe.SliceData(buf))))
+	buffers[ptr] = buf
+	return ptr
+}
+
+//go:wasmexport viam_free
+func viamFree(ptr, _ uint32) {
+	delete(buffers, ptr)
+}
+
+//go:wasmimport viam log
+func hostLog(level, ptr, size uint32)
+
+func log(msg string) {
+	hostLog(1, uint32(uintptr(unsafe.Pointer(unsafe.StringData(msg)))), uint32(len(msg)))
+}
+
+// output hands `v` to the host as JSON.
+func output(v any) uint64 {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"error": err.Error()})
+	}
+	ptr := viamAlloc(uint32(len(data)))
+	copy(buffers[ptr], data)
+	return uint64(ptr)<<32 | uint64(len(data))
+}
+
+//go:wasmexport viam_models
+func viamModels() uint64 {
+	return output([]map[string]string{{"api": "rdk:component:sensor", "model": "acme:demo:counter"}})
+}
+
+var count float64
+
+//go:wasmexport viam_call
+func viamCall(ptr, size uint32) uint64 {
+	var req struct {
+		Method string                 `json:"method"`
+		Args   map[string]interface{} `json:"args"`
+	}
+	if err := json.Unmarshal(buffers[ptr][:size], &req); err != nil {
+		return output(map[string]string{"error": err.Error()})
+	}
+	switch req.Method {
+	case "configure":
+		attrs, _ := req.Args["attributes"].(map[string]interface{})
+		start, _ := attrs["start"].(float64)
+		count = start
+		log("configured " + req.Args["name"].(string))
+		return output(map[string]interface{}{})
+	case "readings":
+		count++
+		return output(map[string]interface{}{"result": map[string]interface{}{"count": count}})
+	case "do_command":
+		// spinning lets tests interrupt a module that never returns
+		for req.Args["spin"] == true {
+		}
+		return output(map[string]interface{}{"result": req.Args})
+	default:
+		return output(map[string]string{"error": "unknown method " + req.Method})
+	}
+}
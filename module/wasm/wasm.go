@@ -0,0 +1,345 @@
+// Package wasm runs modules compiled to WebAssembly in-process, as an alternative to running
+// modules as separate processes. WASM modules are sandboxed, and the same module file runs on
+// every platform, which suits lightweight sensors and logic that should not ship native binaries.
+//
+// Modules whose executable path ends in ".wasm" are run with this package. They can provide sensor,
+// generic component and generic service models. Each resource runs in its own instance of the
+// module, with its own memory, so resources of the same module share no state.
+//
+// # ABI
+//
+// A WASM module is a WASI reactor (e.g. built with `GOOS=wasip1 GOARCH=wasm go build
+// -buildmode=c-shared`) exporting:
+//
+//   - `viam_alloc(size i32) i32`: allocates `size` bytes of memory for the host to write to.
+//   - `viam_free(ptr i32, size i32)`: optional, frees memory allocated by `viam_alloc`.
+//   - `viam_models() i64`: returns the JSON list of models the module provides, e.g:
+//     `[{"api": "rdk:component:sensor", "model": "acme:demo:counter"}]`.
+//   - `viam_call(ptr i32, size i32) i64`: handles the JSON request at `ptr`, which is
+//     `{"method": <method>, "args": <object>}`, and returns the JSON response
+//     `{"result": <object>}` or `{"error": <message>}`.
+//
+// Values returned as i64 are JSON in memory allocated by `viam_alloc`, with the pointer in the
+// upper 32 bits and the size in the lower 32 bits. The host frees both requests and responses with
+// `viam_free` once it is done with them.
+//
+// The methods called with `viam_call` are:
+//
+//   - "configure": args are the resource's "name", "api", "model" and "attributes". It is called
+//     when the resource is created and whenever its config changes.
+//   - "readings": args are the extra of the Readings call. Only called on sensors.
+//   - "do_command": args are the command.
+//
+// The host provides the `viam` import module with `log(level i32, ptr i32, size i32)`, which logs
+// the message at `ptr` at debug (0), info (1), warn (2) or error (3) level. Modules' stdout and
+// stderr are also logged. WASM modules cannot call other resources.
+package wasm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	modlib "go.viam.com/rdk/module"
+	"go.viam.com/rdk/resource"
+	genericservice "go.viam.com/rdk/services/generic"
+)
+
+// IsWASM returns whether a module's executable path is a WASM module.
+func IsWASM(exePath string) bool {
+	return strings.HasSuffix(exePath, ".wasm")
+}
+
+// supportedAPIs are the APIs WASM modules can provide models of.
+var supportedAPIs = []resource.API{sensor.API, generic.API, genericservice.API}
+
+// Module is a loaded WASM module.
+type Module struct {
+	name     string
+	logger   logging.Logger
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+	models   map[resource.API][]resource.Model
+
+	mu        sync.Mutex
+	resources map[resource.Name]*wasmResource
+}
+
+// Load compiles the WASM module at `path` and reads the models it provides.
+func Load(ctx context.Context, name, path string, logger logging.Logger) (*Module, error) {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// Closing the runtime or canceling a call's context interrupts the guest, such that a module
+	// stuck in a loop does not hang the robot.
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCloseOnContextDone(true))
+	mod := &Module{
+		name:      name,
+		logger:    logger,
+		runtime:   runtime,
+		resources: map[resource.Name]*wasmResource{},
+	}
+	if err := mod.load(ctx, code); err != nil {
+		return nil, multierr.Combine(err, runtime.Close(ctx))
+	}
+	return mod, nil
+}
+
+func (mod *Module) load(ctx context.Context, code []byte) error {
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, mod.runtime); err != nil {
+		return err
+	}
+	if _, err := mod.runtime.NewHostModuleBuilder("viam").
+		NewFunctionBuilder().WithFunc(mod.hostLog).Export("log").
+		Instantiate(ctx); err != nil {
+		return err
+	}
+	var err error
+	if mod.compiled, err = mod.runtime.CompileModule(ctx, code); err != nil {
+		return errors.Wrap(err, "invalid WASM module")
+	}
+	for _, export := range []string{"viam_alloc", "viam_models", "viam_call"} {
+		if _, ok := mod.compiled.ExportedFunctions()[export]; !ok {
+			return errors.Errorf("WASM module does not export %s", export)
+		}
+	}
+
+	inst, err := mod.instantiate(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		mod.logger.CDebugw(ctx, "closed WASM instance used to read models", "error", inst.close(ctx))
+	}()
+	modelsJSON, err := inst.invoke(ctx, "viam_models")
+	if err != nil {
+		return errors.Wrap(err, "cannot read the models of WASM module")
+	}
+	var models []struct {
+		API   string `json:"api"`
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(modelsJSON, &models); err != nil {
+		return errors.Wrap(err, "invalid models of WASM module")
+	}
+
+	mod.models = map[resource.API][]resource.Model{}
+	for _, m := range models {
+		api, err := resource.NewAPIFromString(m.API)
+		if err != nil {
+			return err
+		}
+		supported := false
+		for _, s := range supportedAPIs {
+			supported = supported || api == s
+		}
+		if !supported {
+			return errors.Errorf("WASM modules cannot provide %s models", api)
+		}
+		model, err := resource.NewModelFromString(m.Model)
+		if err != nil {
+			return err
+		}
+		mod.models[api] = append(mod.models[api], model)
+	}
+	return nil
+}
+
+// hostLog is the `viam.log` host function.
+func (mod *Module) hostLog(ctx context.Context, guest api.Module, level, ptr, size uint32) {
+	msg, ok := guest.Memory().Read(ptr, size)
+	if !ok {
+		mod.logger.CWarnw(ctx, "WASM module logged out of bounds memory", "module", mod.name)
+		return
+	}
+	switch level {
+	case 0:
+		mod.logger.CDebug(ctx, string(msg))
+	case 1:
+		mod.logger.CInfo(ctx, string(msg))
+	case 2:
+		mod.logger.CWarn(ctx, string(msg))
+	default:
+		mod.logger.CError(ctx, string(msg))
+	}
+}
+
+// Handles returns the APIs and models the module provides, as a module process would report them.
+func (mod *Module) Handles() modlib.HandlerMap {
+	handles := modlib.HandlerMap{}
+	for api, models := range mod.models {
+		apiInfo, ok := resource.LookupGenericAPIRegistration(api)
+		if !ok {
+			continue
+		}
+		rpcAPI := resource.RPCAPI{
+			API:          api,
+			ProtoSvcName: apiInfo.RPCServiceDesc.ServiceName,
+			Desc:         apiInfo.ReflectRPCServiceDesc,
+		}
+		handles[rpcAPI] = append(handles[rpcAPI], models...)
+	}
+	return handles
+}
+
+// AddResource creates a resource in a new instance of the module.
+func (mod *Module) AddResource(ctx context.Context, conf resource.Config) (resource.Resource, error) {
+	res, err := newResource(ctx, mod, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+	mod.resources[conf.ResourceName()] = res
+	return res, nil
+}
+
+// ReconfigureResource configures an existing resource with a new config.
+func (mod *Module) ReconfigureResource(ctx context.Context, conf resource.Config) error {
+	mod.mu.Lock()
+	res, ok := mod.resources[conf.ResourceName()]
+	mod.mu.Unlock()
+	if !ok {
+		return errors.Errorf("WASM module %s has no resource %s", mod.name, conf.ResourceName())
+	}
+	return res.Reconfigure(ctx, nil, conf)
+}
+
+// RemoveResource closes a resource's instance of the module.
+func (mod *Module) RemoveResource(ctx context.Context, name resource.Name) error {
+	mod.mu.Lock()
+	res, ok := mod.resources[name]
+	delete(mod.resources, name)
+	mod.mu.Unlock()
+	if !ok {
+		return errors.Errorf("WASM module %s has no resource %s", mod.name, name)
+	}
+	return res.Close(ctx)
+}
+
+// Close closes every instance of the module.
+func (mod *Module) Close(ctx context.Context) error {
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+	var err error
+	for _, res := range mod.resources {
+		err = multierr.Combine(err, res.Close(ctx))
+	}
+	mod.resources = map[resource.Name]*wasmResource{}
+	return multierr.Combine(err, mod.runtime.Close(ctx))
+}
+
+// instance is an instance of a WASM module. WASM instances are single threaded, so calls into an
+// instance are serialized.
+type instance struct {
+	mu   sync.Mutex
+	mod  api.Module
+	free api.Function
+}
+
+func (mod *Module) instantiate(ctx context.Context) (*instance, error) {
+	stdout := logWriter{logger: mod.logger, module: mod.name}
+	stderr := logWriter{logger: mod.logger, module: mod.name, isErr: true}
+	// Instances are anonymous, such that the module can be instantiated once per resource. Reactors
+	// are initialized with `_initialize` instead of run with `_start`.
+	guest, err := mod.runtime.InstantiateModule(ctx, mod.compiled, wazero.NewModuleConfig().
+		WithName("").
+		WithStartFunctions("_initialize").
+		WithStdout(stdout).
+		WithStderr(stderr).
+		WithSysWalltime().
+		WithSysNanotime().
+		WithRandSource(rand.Reader))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot instantiate WASM module")
+	}
+	return &instance{mod: guest, free: guest.ExportedFunction("viam_free")}, nil
+}
+
+// call calls `viam_call` with a request of `method` and `args`, and returns the result.
+func (inst *instance) call(ctx context.Context, method string, args interface{}) (map[string]interface{}, error) {
+	req, err := json.Marshal(map[string]interface{}{"method": method, "args": args})
+	if err != nil {
+		return nil, err
+	}
+
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	allocated, err := inst.mod.ExportedFunction("viam_alloc").Call(ctx, uint64(len(req)))
+	if err != nil {
+		return nil, err
+	}
+	ptr := uint32(allocated[0])
+	if !inst.mod.Memory().Write(ptr, req) {
+		return nil, errors.New("WASM module allocated out of bounds memory")
+	}
+	respJSON, err := inst.invokeLocked(ctx, "viam_call", uint64(ptr), uint64(len(req)))
+	inst.freeLocked(ctx, ptr, uint32(len(req)))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Result map[string]interface{} `json:"result"`
+		Error  string                 `json:"error"`
+	}
+	if err := json.Unmarshal(respJSON, &resp); err != nil {
+		return nil, errors.Wrap(err, "invalid WASM module response")
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp.Result, nil
+}
+
+// invoke calls an exported function that returns JSON, and returns a copy of the JSON.
+func (inst *instance) invoke(ctx context.Context, function string, params ...uint64) ([]byte, error) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	return inst.invokeLocked(ctx, function, params...)
+}
+
+func (inst *instance) invokeLocked(ctx context.Context, function string, params ...uint64) ([]byte, error) {
+	results, err := inst.mod.ExportedFunction(function).Call(ctx, params...)
+	if err != nil {
+		return nil, err
+	}
+	ptr, size := uint32(results[0]>>32), uint32(results[0])
+	data, ok := inst.mod.Memory().Read(ptr, size)
+	if !ok {
+		return nil, errors.Errorf("WASM module %s returned out of bounds memory", function)
+	}
+	// the memory is about to be freed
+	data = append([]byte(nil), data...)
+	inst.freeLocked(ctx, ptr, size)
+	return data, nil
+}
+
+// freeLocked frees memory with `viam_free`, if the module exports it.
+func (inst *instance) freeLocked(ctx context.Context, ptr, size uint32) {
+	if inst.free == nil {
+		return
+	}
+	//nolint:errcheck
+	inst.free.Call(ctx, uint64(ptr), uint64(size))
+}
+
+func (inst *instance) close(ctx context.Context) error {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	return inst.mod.Close(ctx)
+}
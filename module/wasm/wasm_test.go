@@ -0,0 +1,105 @@
+package wasm_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/module/wasm"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils"
+	rutils "go.viam.com/rdk/utils"
+)
+
+func TestWASMModule(t *testing.T) {
+	ctx := context.Background()
+	logger, logs := logging.NewObservedTestLogger(t)
+	wasmPath := testutils.BuildTempWASMModule(t, "module/wasm/testmodule")
+	test.That(t, wasm.IsWASM(wasmPath), test.ShouldBeTrue)
+
+	mod, err := wasm.Load(ctx, "counter", wasmPath, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, mod.Close(ctx), test.ShouldBeNil)
+	}()
+
+	model := resource.NewModel("acme", "demo", "counter")
+	handles := mod.Handles()
+	test.That(t, handles, test.ShouldHaveLength, 1)
+	for api, models := range handles {
+		test.That(t, api.API, test.ShouldResemble, sensor.API)
+		test.That(t, api.ProtoSvcName, test.ShouldEqual, "viam.component.sensor.v1.SensorService")
+		test.That(t, models, test.ShouldResemble, []resource.Model{model})
+	}
+
+	conf := resource.Config{
+		Name:       "counter",
+		API:        sensor.API,
+		Model:      model,
+		Attributes: rutils.AttributeMap{"start": 10.},
+	}
+	res, err := mod.AddResource(ctx, conf)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, logs.FilterMessage("configured counter").Len(), test.ShouldEqual, 1)
+	s, ok := res.(sensor.Sensor)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	readings, err := s.Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings, test.ShouldResemble, map[string]interface{}{"count": 11.})
+	readings, err = s.Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings, test.ShouldResemble, map[string]interface{}{"count": 12.})
+
+	// Each resource runs in its own instance, so resources do not share state.
+	other := conf
+	other.Name = "other"
+	otherRes, err := mod.AddResource(ctx, other)
+	test.That(t, err, test.ShouldBeNil)
+	readings, err = otherRes.(sensor.Sensor).Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings, test.ShouldResemble, map[string]interface{}{"count": 11.})
+	test.That(t, mod.RemoveResource(ctx, other.ResourceName()), test.ShouldBeNil)
+	_, err = otherRes.(sensor.Sensor).Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeError, "resource rdk:component:sensor/other is closed")
+
+	conf.Attributes = rutils.AttributeMap{"start": 100.}
+	test.That(t, mod.ReconfigureResource(ctx, conf), test.ShouldBeNil)
+	readings, err = s.Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings, test.ShouldResemble, map[string]interface{}{"count": 101.})
+
+	resp, err := res.DoCommand(ctx, map[string]interface{}{"echo": "hello"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp, test.ShouldResemble, map[string]interface{}{"echo": "hello"})
+
+	t.Run("interrupted", func(t *testing.T) {
+		// A module that never returns is interrupted when the call's context is done, and the
+		// resource is restarted with its last config.
+		spinCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		_, err := res.DoCommand(spinCtx, map[string]interface{}{"spin": true})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		readings, err := s.Readings(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, readings, test.ShouldResemble, map[string]interface{}{"count": 101.})
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := wasm.Load(ctx, "missing", filepath.Join(t.TempDir(), "missing.wasm"), logger)
+		test.That(t, err, test.ShouldNotBeNil)
+
+		notWASM := filepath.Join(t.TempDir(), "module.wasm")
+		test.That(t, os.WriteFile(notWASM, []byte("#!/bin/sh"), 0o600), test.ShouldBeNil)
+		_, err = wasm.Load(ctx, "invalid", notWASM, logger)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "invalid WASM module")
+	})
+}
@@ -0,0 +1,220 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"context"
+	"math/rand"
+
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/motionplan/ik"
+	"go.viam.com/rdk/referenceframe"
+)
+
+const (
+	// Number of consecutive rounds of shortcutting and smoothing which do not improve a plan enough to be sent after which
+	// anytime planning stops.
+	defaultAnytimeStallRounds = 200
+	// Fraction by which a plan must lower the cost of the last plan sent for it to be sent.
+	defaultAnytimeMinImprovement = 0.01
+)
+
+// PlanMotionAnytime plans a motion from a provided plan request, returning the first feasible plan it finds without taking
+// the time to smooth it. It then keeps improving that plan in the background, shortcutting and smoothing its path, and sends
+// each plan which lowers the cost of the last one to the returned channel. The channel is closed once ctx is done or the plan
+// stops improving, so callers should cancel ctx at their deadline and use the last plan received.
+// Each goal is planned separately, so the "timeout" option applies to each goal rather than the whole motion. Plans for
+// PTG frames are not improved, so their channel is closed right away.
+func PlanMotionAnytime(ctx context.Context, request *PlanRequest) (Plan, <-chan Plan, error) {
+	if err := request.validatePlanRequest(); err != nil {
+		return nil, nil, err
+	}
+	rseed := defaultRandomSeed
+	if seed, ok := request.Options["rseed"].(int); ok {
+		rseed = seed
+	}
+	pm, err := newPlanManager(request.FrameSystem, request.Logger, rseed)
+	if err != nil {
+		return nil, nil, err
+	}
+	plans := make(chan Plan)
+
+	// planning each goal separately keeps the goals in the path, as improvements are made between the goals
+	options := deepAtomicCopyMap(request.Options)
+	options["smooth_iter"] = 0
+	anytime := &anytimePlanner{fs: request.FrameSystem, constraints: request.Constraints}
+	start := request.StartState
+	for _, goal := range request.Goals {
+		opt, err := pm.plannerSetupFromMoveRequest(
+			start,
+			goal,
+			start.configuration,
+			request.WorldState,
+			request.BoundingRegions,
+			request.Constraints,
+			options,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		if opt.useTPspace {
+			plan, err := PlanMotion(ctx, request)
+			if err != nil {
+				return nil, nil, err
+			}
+			close(plans)
+			return plan, plans, nil
+		}
+		//nolint: gosec
+		mp, err := newPlanner(request.FrameSystem, rand.New(rand.NewSource(int64(pm.randseed.Int()))), request.Logger, opt)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		goalRequest := *request
+		goalRequest.Goals = []*PlanState{goal}
+		goalRequest.StartState = start
+		goalRequest.Options = options
+		plan, err := pm.planMultiWaypoint(ctx, &goalRequest, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		steps := plan.Trajectory()
+		anytime.segments = append(anytime.segments, &anytimeSegment{mp: mp, steps: steps})
+		start = &PlanState{configuration: steps[len(steps)-1]}
+	}
+
+	plan, err := anytime.plan()
+	if err != nil {
+		return nil, nil, err
+	}
+	utils.PanicCapturingGo(func() {
+		defer close(plans)
+		anytime.improve(ctx, request, plans)
+	})
+	return plan, plans, nil
+}
+
+// anytimePlanner improves a plan made of the paths between each of its goals.
+type anytimePlanner struct {
+	fs          referenceframe.FrameSystem
+	constraints *Constraints
+	segments    []*anytimeSegment
+}
+
+// anytimeSegment is the path to a single goal, and the planner which checks changes to it.
+type anytimeSegment struct {
+	mp    *planner
+	steps Trajectory
+}
+
+// improve shortcuts and smooths the paths of the plan, sending each plan whose cost is sufficiently lower than that of the
+// last one sent, until ctx is done or the plan stops improving.
+func (ap *anytimePlanner) improve(ctx context.Context, request *PlanRequest, plans chan<- Plan) {
+	sentCost := ap.cost()
+	send := func() bool {
+		plan, err := ap.plan()
+		if err != nil {
+			request.Logger.CDebugf(ctx, "could not construct improved plan: %v", err)
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case plans <- plan:
+		}
+		sentCost = ap.cost()
+		return true
+	}
+
+	for stalled := 0; stalled < defaultAnytimeStallRounds; stalled++ {
+		if ctx.Err() != nil {
+			return
+		}
+		for _, segment := range ap.segments {
+			segment.shortcut()
+			segment.smooth()
+		}
+		if cost := ap.cost(); cost < sentCost*(1-defaultAnytimeMinImprovement) {
+			request.Logger.CDebugf(ctx, "anytime planning improved plan cost from %f to %f", sentCost, cost)
+			if !send() {
+				return
+			}
+			stalled = -1
+		}
+	}
+	// send whatever small improvements were made since the last plan was sent
+	if ap.cost() < sentCost {
+		send()
+	}
+}
+
+// plan returns the plan through the paths to each goal, timed if the request has velocity constraints.
+func (ap *anytimePlanner) plan() (Plan, error) {
+	var nodes []node
+	for i, segment := range ap.segments {
+		steps := segment.steps
+		if i > 0 {
+			// the first step of each path is the last step of the path before it
+			steps = steps[1:]
+		}
+		for _, step := range steps {
+			nodes = append(nodes, newConfigurationNode(step))
+		}
+	}
+	plan, err := newRRTPlan(nodes, ap.fs, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	if velConstraints := ap.constraints.GetVelocityConstraint(); len(velConstraints) > 0 {
+		return NewTimedPlan(plan, velConstraints...)
+	}
+	return plan, nil
+}
+
+func (ap *anytimePlanner) cost() float64 {
+	cost := 0.
+	for _, segment := range ap.segments {
+		cost += segment.steps.EvaluateCost(segment.mp.planOpts.scoreFunc)
+	}
+	return cost
+}
+
+// shortcut tries to connect two random steps of the path directly, skipping the steps between them.
+func (s *anytimeSegment) shortcut() {
+	if len(s.steps) < 3 {
+		return
+	}
+	i := s.mp.randseed.Intn(len(s.steps) - 2)
+	j := i + 2 + s.mp.randseed.Intn(len(s.steps)-i-2)
+	if s.segmentCost(s.steps[i], s.steps[j]) >= s.steps[i:j+1].EvaluateCost(s.mp.planOpts.scoreFunc) {
+		return
+	}
+	if s.mp.checkPath(s.steps[i], s.steps[j]) {
+		s.steps = append(s.steps[:i+1], s.steps[j:]...)
+	}
+}
+
+// smooth tries to cut the corner at a random step of the path, replacing it with the midpoints of the segments on either
+// side of it.
+func (s *anytimeSegment) smooth() {
+	if len(s.steps) < 3 {
+		return
+	}
+	i := 1 + s.mp.randseed.Intn(len(s.steps)-2)
+	prev, curr, next := s.steps[i-1], s.steps[i], s.steps[i+1]
+	in, out := lerpFSInputs(prev, curr, 0.5), lerpFSInputs(curr, next, 0.5)
+	// skip corners which are already nearly straight, so as not to keep adding steps for no gain
+	gain := s.segmentCost(in, curr) + s.segmentCost(curr, out) - s.segmentCost(in, out)
+	if gain < s.mp.planOpts.InputIdentDist {
+		return
+	}
+	if s.mp.checkPath(in, out) {
+		s.steps = append(s.steps[:i], append(Trajectory{in, out}, s.steps[i+1:]...)...)
+	}
+}
+
+func (s *anytimeSegment) segmentCost(from, to referenceframe.FrameSystemInputs) float64 {
+	return s.mp.planOpts.scoreFunc(&ik.SegmentFS{StartConfiguration: from, EndConfiguration: to, FS: s.mp.fs})
+}
@@ -0,0 +1,62 @@
+package motionplan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/motionplan/ik"
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestPlanMotionAnytime(t *testing.T) {
+	t.Parallel()
+	fs := makeTestFS(t)
+	positions := frame.NewZeroInputs(fs)
+	goal1 := spatialmath.NewPose(r3.Vector{X: 257, Y: 2100, Z: -300}, &spatialmath.OrientationVectorDegrees{OZ: -1})
+	goal2 := spatialmath.NewPose(r3.Vector{X: 157, Y: 1500, Z: -300}, &spatialmath.OrientationVectorDegrees{OZ: -1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	plan, plans, err := PlanMotionAnytime(ctx, &PlanRequest{
+		Logger: logger,
+		Goals: []*PlanState{
+			{poses: frame.FrameSystemPoses{"xArmVgripper": frame.NewPoseInFrame(frame.World, goal1)}},
+			{poses: frame.FrameSystemPoses{"xArmVgripper": frame.NewPoseInFrame(frame.World, goal2)}},
+		},
+		StartState:  &PlanState{configuration: positions},
+		FrameSystem: fs,
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	// reachesGoals checks that the plan passes through the first goal and ends at the second
+	reachesGoals := func(plan Plan) {
+		reached := false
+		for _, step := range plan.Trajectory() {
+			pose, err := fs.Transform(step, frame.NewPoseInFrame("xArmVgripper", spatialmath.NewZeroPose()), frame.World)
+			test.That(t, err, test.ShouldBeNil)
+			if spatialmath.PoseAlmostCoincidentEps(pose.(*frame.PoseInFrame).Pose(), goal1, 0.01) {
+				reached = true
+			}
+		}
+		test.That(t, reached, test.ShouldBeTrue)
+		last := plan.Trajectory()[len(plan.Trajectory())-1]
+		pose, err := fs.Transform(last, frame.NewPoseInFrame("xArmVgripper", spatialmath.NewZeroPose()), frame.World)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostCoincidentEps(pose.(*frame.PoseInFrame).Pose(), goal2, 0.01), test.ShouldBeTrue)
+	}
+	reachesGoals(plan)
+
+	// each plan sent is an improvement on the last, and the channel is closed once planning is done
+	cost := plan.Trajectory().EvaluateCost(ik.FSConfigurationL2Distance)
+	for improved := range plans {
+		reachesGoals(improved)
+		improvedCost := improved.Trajectory().EvaluateCost(ik.FSConfigurationL2Distance)
+		test.That(t, improvedCost, test.ShouldBeLessThan, cost)
+		cost = improvedCost
+	}
+}
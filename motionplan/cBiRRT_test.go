@@ -45,7 +45,7 @@ func TestSimpleLinearMotion(t *testing.T) {
 	mp, err := newCBiRRTMotionPlanner(fs, rand.New(rand.NewSource(42)), logger, opt)
 	test.That(t, err, test.ShouldBeNil)
 	cbirrt, _ := mp.(*cBiRRTMotionPlanner)
-	solutions, err := mp.getSolutions(ctx, referenceframe.FrameSystemInputs{m.Name(): home7}, goalMetric)
+	solutions, err := mp.getSolutions(ctx, referenceframe.FrameSystemInputs{m.Name(): home7}, goalMetric, nil)
 	test.That(t, err, test.ShouldBeNil)
 
 	near1 := &basicNode{q: referenceframe.FrameSystemInputs{m.Name(): home7}}
@@ -0,0 +1,74 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+	"sync"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+// stateFSCheckCache holds the results of checking frame system configurations against FS state constraints, so that the
+// configurations planners revisit, such as the ends of the segments extending their trees, are only checked once. It is
+// safe for concurrent use by the workers of the planners sharing a set of planner options.
+type stateFSCheckCache struct {
+	mu      sync.RWMutex
+	maxSize int
+	results map[string]stateCheckResult
+}
+
+type stateCheckResult struct {
+	ok     bool
+	failed string
+}
+
+func newStateFSCheckCache(maxSize int) *stateFSCheckCache {
+	return &stateFSCheckCache{maxSize: maxSize, results: map[string]stateCheckResult{}}
+}
+
+func (c *stateFSCheckCache) get(key string) (stateCheckResult, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result, ok := c.results[key]
+	return result, ok
+}
+
+func (c *stateFSCheckCache) put(key string, result stateCheckResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// start over rather than track recency, as planners mostly revisit configurations they checked recently
+	if len(c.results) >= c.maxSize {
+		c.results = map[string]stateCheckResult{}
+	}
+	c.results[key] = result
+}
+
+func (c *stateFSCheckCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results = map[string]stateCheckResult{}
+}
+
+// stateFSCacheKey returns a key which is equal for configurations with exactly the same inputs.
+func stateFSCacheKey(inputs referenceframe.FrameSystemInputs) string {
+	names := make([]string, 0, len(inputs))
+	size := 0
+	for name, frameInputs := range inputs {
+		names = append(names, name)
+		size += len(name) + 1 + 8*len(frameInputs)
+	}
+	sort.Strings(names)
+
+	key := make([]byte, 0, size)
+	for _, name := range names {
+		key = append(key, name...)
+		key = append(key, 0)
+		for _, input := range inputs[name] {
+			key = binary.LittleEndian.AppendUint64(key, math.Float64bits(input.Value))
+		}
+	}
+	return string(key)
+}
@@ -490,6 +490,16 @@ type OrientationConstraint struct {
 	OrientationToleranceDegs float64
 }
 
+// VelocityConstraint bounds how fast the components being moved may move and accelerate. Plans with a VelocityConstraint are
+// time parameterized to respect it, see TimedPlan. Zero values are unbounded. Joint limits apply to each input of the
+// components being moved, which are treated as radians, and linear limits apply to the origin of each moving frame.
+type VelocityConstraint struct {
+	MaxJointVelDegsPerSec  float64
+	MaxJointAccDegsPerSec2 float64
+	MaxLinearVelMmPerSec   float64
+	MaxLinearAccMmPerSec2  float64
+}
+
 // CollisionSpecificationAllowedFrameCollisions is used to define frames that are allowed to collide.
 type CollisionSpecificationAllowedFrameCollisions struct {
 	Frame1, Frame2 string
@@ -508,6 +518,8 @@ type Constraints struct {
 	PseudolinearConstraint []PseudolinearConstraint
 	OrientationConstraint  []OrientationConstraint
 	CollisionSpecification []CollisionSpecification
+	// VelocityConstraint has no protobuf representation, see ToProtobuf.
+	VelocityConstraint []VelocityConstraint
 }
 
 // NewEmptyConstraints creates a new, empty Constraints object.
@@ -598,7 +610,8 @@ func ConstraintsFromProtobuf(pbConstraint *motionpb.Constraints) *Constraints {
 	)
 }
 
-// ToProtobuf takes an existing Constraints object and converts it to a protobuf.
+// ToProtobuf takes an existing Constraints object and converts it to a protobuf. The protobuf has no fields for
+// PseudolinearConstraint or VelocityConstraint, which are dropped.
 func (c *Constraints) ToProtobuf() *motionpb.Constraints {
 	if c == nil {
 		return nil
@@ -706,6 +719,19 @@ func (c *Constraints) GetCollisionSpecification() []CollisionSpecification {
 	return nil
 }
 
+// AddVelocityConstraint appends a VelocityConstraint to a Constraints object.
+func (c *Constraints) AddVelocityConstraint(velConstraint VelocityConstraint) {
+	c.VelocityConstraint = append(c.VelocityConstraint, velConstraint)
+}
+
+// GetVelocityConstraint checks if the Constraints object is nil and if not then returns its VelocityConstraint field.
+func (c *Constraints) GetVelocityConstraint() []VelocityConstraint {
+	if c != nil {
+		return c.VelocityConstraint
+	}
+	return nil
+}
+
 type fsPathConstraint struct {
 	metricMap     map[string]ik.StateMetric
 	constraintMap map[string]StateConstraint
@@ -4,6 +4,10 @@ package motionplan
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"go.viam.com/utils"
 
 	"go.viam.com/rdk/motionplan/ik"
 	"go.viam.com/rdk/referenceframe"
@@ -11,6 +15,9 @@ import (
 
 var defaultMinStepCount = 2
 
+// Segments with fewer interpolated states than this are checked by a single worker.
+const defaultStatesBeforeParallelization = 8
+
 // ConstraintHandler is a convenient wrapper for constraint handling which is likely to be common among most motion
 // planners. Including a constraint handler as an anonymous struct member allows reuse.
 type ConstraintHandler struct {
@@ -18,6 +25,21 @@ type ConstraintHandler struct {
 	segmentFSConstraints map[string]SegmentFSConstraint
 	stateConstraints     map[string]StateConstraint
 	stateFSConstraints   map[string]StateFSConstraint
+
+	// checkWorkers is the number of workers checking the states of a segment against FS state constraints
+	checkWorkers int
+	stateFSCache *stateFSCheckCache
+}
+
+// setCheckConcurrency sets the number of workers which check the interpolated states of segments against FS state constraints,
+// and how many results of those checks are cached and shared by everything checking constraints with this handler. A cache size
+// that is not positive disables caching. The cache assumes that all configurations checked are of the same frame system.
+func (c *ConstraintHandler) setCheckConcurrency(workers, cacheSize int) {
+	c.checkWorkers = workers
+	c.stateFSCache = nil
+	if cacheSize > 0 {
+		c.stateFSCache = newStateFSCheckCache(cacheSize)
+	}
 }
 
 // CheckStateConstraints will check a given input against all state constraints.
@@ -39,6 +61,19 @@ func (c *ConstraintHandler) CheckStateConstraints(state *ik.State) (bool, string
 // -- a bool representing whether all constraints passed
 // -- if failing, a string naming the failed constraint.
 func (c *ConstraintHandler) CheckStateFSConstraints(state *ik.StateFS) (bool, string) {
+	if c.stateFSCache == nil {
+		return c.checkStateFSConstraints(state)
+	}
+	key := stateFSCacheKey(state.Configuration)
+	if result, ok := c.stateFSCache.get(key); ok {
+		return result.ok, result.failed
+	}
+	ok, failed := c.checkStateFSConstraints(state)
+	c.stateFSCache.put(key, stateCheckResult{ok: ok, failed: failed})
+	return ok, failed
+}
+
+func (c *ConstraintHandler) checkStateFSConstraints(state *ik.StateFS) (bool, string) {
 	for name, cFunc := range c.stateFSConstraints {
 		pass := cFunc(state)
 		if !pass {
@@ -269,11 +304,17 @@ func (c *ConstraintHandler) AddStateFSConstraint(name string, cons StateFSConstr
 	}
 	name = name + "_" + fmt.Sprintf("%p", cons)
 	c.stateFSConstraints[name] = cons
+	if c.stateFSCache != nil {
+		c.stateFSCache.clear()
+	}
 }
 
 // RemoveStateFSConstraint will remove the given constraint.
 func (c *ConstraintHandler) RemoveStateFSConstraint(name string) {
 	delete(c.stateFSConstraints, name)
+	if c.stateFSCache != nil {
+		c.stateFSCache.clear()
+	}
 }
 
 // StateFSConstraints will list all FS state constraints by name.
@@ -318,21 +359,66 @@ func (c *ConstraintHandler) CheckStateConstraintsAcrossSegmentFS(ci *ik.SegmentF
 	if err != nil {
 		return false, nil
 	}
-	var lastGood referenceframe.FrameSystemInputs
-	for i, interpConfig := range interpolatedConfigurations {
-		interpC := &ik.StateFS{FS: ci.FS, Configuration: interpConfig}
-		pass, _ := c.CheckStateFSConstraints(interpC)
-		if !pass {
-			if i == 0 {
-				// fail on start pos
-				return false, nil
+	states := make([]*ik.StateFS, 0, len(interpolatedConfigurations))
+	for _, interpConfig := range interpolatedConfigurations {
+		states = append(states, &ik.StateFS{FS: ci.FS, Configuration: interpConfig})
+	}
+	switch i := c.firstInvalidStateFS(states); i {
+	case -1:
+		return true, nil
+	case 0:
+		// fail on start pos
+		return false, nil
+	default:
+		return false, &ik.SegmentFS{StartConfiguration: ci.StartConfiguration, EndConfiguration: states[i-1].Configuration, FS: ci.FS}
+	}
+}
+
+// firstInvalidStateFS returns the index of the first state which does not satisfy all FS state constraints, or -1 if all do.
+// Long lists of states are split between the check workers.
+func (c *ConstraintHandler) firstInvalidStateFS(states []*ik.StateFS) int {
+	workers := min(c.checkWorkers, len(states))
+	if workers <= 1 || len(states) < defaultStatesBeforeParallelization {
+		for i, state := range states {
+			if pass, _ := c.CheckStateFSConstraints(state); !pass {
+				return i
 			}
-			return false, &ik.SegmentFS{StartConfiguration: ci.StartConfiguration, EndConfiguration: lastGood, FS: ci.FS}
 		}
-		lastGood = interpC.Configuration
+		return -1
 	}
 
-	return true, nil
+	// states are handed out in order, so every state before the first invalid one found is checked
+	var next atomic.Int64
+	var firstInvalid atomic.Int64
+	firstInvalid.Store(int64(len(states)))
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		utils.PanicCapturingGo(func() {
+			defer wg.Done()
+			for {
+				i := next.Add(1) - 1
+				if i >= firstInvalid.Load() {
+					return
+				}
+				if pass, _ := c.CheckStateFSConstraints(states[i]); pass {
+					continue
+				}
+				for {
+					current := firstInvalid.Load()
+					if i >= current || firstInvalid.CompareAndSwap(current, i) {
+						break
+					}
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	if first := int(firstInvalid.Load()); first < len(states) {
+		return first
+	}
+	return -1
 }
 
 // CheckSegmentAndStateValidityFS will check a segment input and confirm that it 1) meets all segment constraints, and 2) meets all
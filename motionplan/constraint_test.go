@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"runtime"
+	"sync/atomic"
 	"testing"
 
 	"github.com/golang/geo/r3"
@@ -327,6 +329,132 @@ func BenchmarkCollisionConstraints(b *testing.B) {
 	bt = b1
 }
 
+func TestStateFSCheckConcurrency(t *testing.T) {
+	model, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/example_kinematics/xarm6_kinematics_test.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptyFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	segment := &ik.SegmentFS{
+		StartConfiguration: frame.FrameSystemInputs{model.Name(): frame.FloatsToInputs([]float64{0, 0, 0, 0, 0, 0})},
+		EndConfiguration:   frame.FrameSystemInputs{model.Name(): frame.FloatsToInputs([]float64{1, 0, 0, 0, 0, 0})},
+		FS:                 fs,
+	}
+
+	var checks atomic.Int64
+	belowLimit := func(state *ik.StateFS) bool {
+		checks.Add(1)
+		return state.Configuration[model.Name()][0].Value <= 0.5
+	}
+	newHandler := func(workers, cacheSize int) *ConstraintHandler {
+		handler := &ConstraintHandler{}
+		handler.AddStateFSConstraint("limit", belowLimit)
+		handler.setCheckConcurrency(workers, cacheSize)
+		return handler
+	}
+
+	sequentialOK, sequentialValid := newHandler(1, 0).CheckStateConstraintsAcrossSegmentFS(segment, 1)
+	test.That(t, sequentialOK, test.ShouldBeFalse)
+	test.That(t, sequentialValid, test.ShouldNotBeNil)
+	test.That(t, sequentialValid.EndConfiguration[model.Name()][0].Value, test.ShouldBeLessThanOrEqualTo, 0.5)
+
+	t.Run("parallel checks find the same valid segment", func(t *testing.T) {
+		for _, workers := range []int{2, 4, 16} {
+			ok, valid := newHandler(workers, 0).CheckStateConstraintsAcrossSegmentFS(segment, 1)
+			test.That(t, ok, test.ShouldBeFalse)
+			test.That(t, valid, test.ShouldResemble, sequentialValid)
+		}
+		ok, valid := newHandler(4, 0).CheckStateConstraintsAcrossSegmentFS(sequentialValid, 1)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, valid, test.ShouldBeNil)
+	})
+
+	t.Run("results are cached", func(t *testing.T) {
+		handler := newHandler(4, 1000)
+		checks.Store(0)
+		handler.CheckStateConstraintsAcrossSegmentFS(segment, 1)
+		firstChecks := checks.Load()
+		test.That(t, firstChecks, test.ShouldBeGreaterThan, 0)
+		ok, valid := handler.CheckStateConstraintsAcrossSegmentFS(segment, 1)
+		test.That(t, ok, test.ShouldBeFalse)
+		test.That(t, valid, test.ShouldResemble, sequentialValid)
+		test.That(t, checks.Load(), test.ShouldEqual, firstChecks)
+
+		// results no longer hold once the constraints change
+		handler.AddStateFSConstraint("never", func(*ik.StateFS) bool { return false })
+		ok, valid = handler.CheckStateConstraintsAcrossSegmentFS(segment, 1)
+		test.That(t, ok, test.ShouldBeFalse)
+		test.That(t, valid, test.ShouldBeNil)
+	})
+
+	t.Run("cache is bounded", func(t *testing.T) {
+		handler := newHandler(1, 2)
+		for _, v := range []float64{0.1, 0.2, 0.3} {
+			handler.CheckStateFSConstraints(&ik.StateFS{
+				Configuration: frame.FrameSystemInputs{model.Name(): frame.FloatsToInputs([]float64{v, 0, 0, 0, 0, 0})},
+				FS:            fs,
+			})
+		}
+		test.That(t, len(handler.stateFSCache.results), test.ShouldBeLessThanOrEqualTo, 2)
+	})
+}
+
+// BenchmarkCheckSegmentFS checks segments of an arm moving through a cluttered scene, as planners do when extending their trees.
+func BenchmarkCheckSegmentFS(b *testing.B) {
+	model, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/example_kinematics/xarm6_kinematics_test.json"), "")
+	test.That(b, err, test.ShouldBeNil)
+	fs := frame.NewEmptyFrameSystem("test")
+	test.That(b, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	seedMap := frame.NewZeroInputs(fs)
+
+	movingGeometries, err := model.Geometries(seedMap[model.Name()])
+	test.That(b, err, test.ShouldBeNil)
+	obstacles := []spatial.Geometry{}
+	for x := -600.; x <= 600; x += 200 {
+		for y := -600.; y <= 600; y += 200 {
+			box, err := spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{X: x, Y: y, Z: 800}), r3.Vector{X: 20, Y: 20, Z: 20}, "")
+			test.That(b, err, test.ShouldBeNil)
+			obstacles = append(obstacles, box)
+		}
+	}
+	fsConstraints, _, err := createAllCollisionConstraints(
+		movingGeometries.Geometries(), nil, obstacles, nil, nil, defaultCollisionBufferMM,
+	)
+	test.That(b, err, test.ShouldBeNil)
+
+	// planners revisit configurations, so segments are drawn from a fixed set
+	rseed := rand.New(rand.NewSource(1))
+	segments := make([]*ik.SegmentFS, 20)
+	for i := range segments {
+		segments[i] = &ik.SegmentFS{
+			StartConfiguration: frame.FrameSystemInputs{model.Name(): frame.FloatsToInputs(frame.GenerateRandomConfiguration(model, rseed))},
+			EndConfiguration:   frame.FrameSystemInputs{model.Name(): frame.FloatsToInputs(frame.GenerateRandomConfiguration(model, rseed))},
+			FS:                 fs,
+		}
+	}
+
+	for _, bc := range []struct {
+		name      string
+		workers   int
+		cacheSize int
+	}{
+		{"sequential", 1, 0},
+		{"parallel", runtime.NumCPU(), 0},
+		{"parallel cached", runtime.NumCPU(), defaultStateCheckCacheSize},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			handler := &ConstraintHandler{}
+			for name, constraint := range fsConstraints {
+				handler.AddStateFSConstraint(name, constraint)
+			}
+			handler.setCheckConcurrency(bc.workers, bc.cacheSize)
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				bt, _ = handler.CheckStateConstraintsAcrossSegmentFS(segments[n%len(segments)], defaultResolution)
+			}
+		})
+	}
+}
+
 func TestConstraintConstructors(t *testing.T) {
 	c := NewEmptyConstraints()
 
@@ -0,0 +1,395 @@
+package motionplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// PlanExportFormat is a format that ExportPlan can write a plan in.
+type PlanExportFormat string
+
+const (
+	// PlanExportJSON writes the PlanScene of a plan as JSON.
+	PlanExportJSON = PlanExportFormat("json")
+	// PlanExportThreeJS writes a plan as a three.js JSON object scene, which three.js's ObjectLoader and meshcat's
+	// set_object load, with an animation clip of the plan. Scenes are in meters.
+	PlanExportThreeJS = PlanExportFormat("threejs")
+)
+
+// defaultExportStepDuration is how long each step of a plan that was not time parameterized is animated for.
+const defaultExportStepDuration = time.Second
+
+// PlanScene describes what a plan does: the poses and geometries of the robot at each of its steps, and the obstacles
+// it moves among, all in the world frame.
+type PlanScene struct {
+	Steps     []PlanSceneStep     `json:"steps"`
+	Obstacles []PlanSceneGeometry `json:"obstacles"`
+}
+
+// PlanSceneStep is the state of the robot at a step of a plan. Collisions lists the robot geometries that collide with
+// an obstacle at the step, which a valid plan has none of.
+type PlanSceneStep struct {
+	TimeSec    float64                  `json:"time_sec"`
+	Inputs     map[string][]float64     `json:"inputs"`
+	Poses      map[string]PlanScenePose `json:"poses"`
+	Geometries []PlanSceneGeometry      `json:"geometries"`
+	Collisions []string                 `json:"collisions,omitempty"`
+}
+
+// PlanScenePose is a pose in the world frame.
+type PlanScenePose struct {
+	Translation r3.Vector                     `json:"translation"`
+	Orientation spatialmath.OrientationConfig `json:"orientation"`
+}
+
+// PlanSceneGeometry is a geometry in the world frame, along with the frame it belongs to, if any.
+type PlanSceneGeometry struct {
+	Frame    string                      `json:"frame,omitempty"`
+	Geometry *spatialmath.GeometryConfig `json:"geometry"`
+
+	geometry spatialmath.Geometry
+}
+
+// ExportPlan writes `plan` in `format`, with the geometries of the frames of `fs` at each step and the obstacles of
+// `worldState`, so that a plan can be inspected before it is executed.
+func ExportPlan(
+	plan Plan,
+	fs referenceframe.FrameSystem,
+	worldState *referenceframe.WorldState,
+	format PlanExportFormat,
+) ([]byte, error) {
+	scene, err := NewPlanScene(plan, fs, worldState)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case PlanExportJSON:
+		return json.Marshal(scene)
+	case PlanExportThreeJS:
+		return json.Marshal(scene.threeJS())
+	default:
+		return nil, fmt.Errorf("unsupported plan export format %q, must be %q or %q", format, PlanExportJSON, PlanExportThreeJS)
+	}
+}
+
+// NewPlanScene returns the PlanScene of `plan` as it moves the frames of `fs` among the obstacles of `worldState`.
+// Frames without inputs in a step of the plan are at their zero inputs.
+func NewPlanScene(plan Plan, fs referenceframe.FrameSystem, worldState *referenceframe.WorldState) (*PlanScene, error) {
+	if worldState == nil {
+		worldState = referenceframe.NewEmptyWorldState()
+	}
+	trajectory := plan.Trajectory()
+	var times []time.Duration
+	if timed, ok := plan.(TimedPlan); ok {
+		times = timed.Times()
+	}
+
+	scene := &PlanScene{}
+	obstacles, err := worldState.ObstaclesInWorldFrame(fs, referenceframe.NewZeroInputs(fs))
+	if err != nil {
+		return nil, err
+	}
+	for _, obstacle := range obstacles.Geometries() {
+		geometry, err := newPlanSceneGeometry("", obstacle)
+		if err != nil {
+			return nil, err
+		}
+		scene.Obstacles = append(scene.Obstacles, geometry)
+	}
+
+	names := fs.FrameNames()
+	sort.Strings(names)
+	for i, step := range trajectory {
+		inputs := referenceframe.NewZeroInputs(fs)
+		for name, stepInputs := range step {
+			inputs[name] = stepInputs
+		}
+		sceneStep := PlanSceneStep{
+			TimeSec: float64(i) * defaultExportStepDuration.Seconds(),
+			Inputs:  map[string][]float64{},
+			Poses:   map[string]PlanScenePose{},
+		}
+		if len(times) == len(trajectory) {
+			sceneStep.TimeSec = times[i].Seconds()
+		}
+
+		frameGeometries, err := referenceframe.FrameSystemGeometries(fs, inputs)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if stepInputs, ok := step[name]; ok && len(stepInputs) > 0 {
+				sceneStep.Inputs[name] = referenceframe.InputsToFloats(stepInputs)
+			}
+			pose, err := fs.Transform(inputs, referenceframe.NewZeroPoseInFrame(name), referenceframe.World)
+			if err != nil {
+				return nil, err
+			}
+			scenePose, err := newPlanScenePose(pose.(*referenceframe.PoseInFrame).Pose())
+			if err != nil {
+				return nil, err
+			}
+			sceneStep.Poses[name] = scenePose
+
+			geometries, ok := frameGeometries[name]
+			if !ok {
+				continue
+			}
+			for _, g := range geometries.Geometries() {
+				geometry, err := newPlanSceneGeometry(name, g)
+				if err != nil {
+					return nil, err
+				}
+				sceneStep.Geometries = append(sceneStep.Geometries, geometry)
+				for _, obstacle := range scene.Obstacles {
+					collides, err := g.CollidesWith(obstacle.geometry, 0)
+					if err != nil {
+						return nil, err
+					}
+					if collides {
+						sceneStep.Collisions = append(
+							sceneStep.Collisions,
+							fmt.Sprintf("%s collides with %s", g.Label(), obstacle.geometry.Label()),
+						)
+					}
+				}
+			}
+		}
+		scene.Steps = append(scene.Steps, sceneStep)
+	}
+	return scene, nil
+}
+
+func newPlanScenePose(pose spatialmath.Pose) (PlanScenePose, error) {
+	orientation, err := spatialmath.NewOrientationConfig(pose.Orientation())
+	if err != nil {
+		return PlanScenePose{}, err
+	}
+	return PlanScenePose{Translation: pose.Point(), Orientation: *orientation}, nil
+}
+
+func newPlanSceneGeometry(frame string, g spatialmath.Geometry) (PlanSceneGeometry, error) {
+	config, err := spatialmath.NewGeometryConfig(g)
+	if err != nil {
+		return PlanSceneGeometry{}, errors.Wrapf(err, "cannot export geometry %q", g.Label())
+	}
+	return PlanSceneGeometry{Frame: frame, Geometry: config, geometry: g}, nil
+}
+
+// The three.js JSON object format, version 4, as read by three.js's ObjectLoader.
+type (
+	threeJSScene struct {
+		Metadata   threeJSMetadata    `json:"metadata"`
+		Geometries []threeJSGeometry  `json:"geometries"`
+		Materials  []threeJSMaterial  `json:"materials"`
+		Animations []threeJSAnimation `json:"animations,omitempty"`
+		Object     threeJSObject      `json:"object"`
+	}
+	threeJSMetadata struct {
+		Version   float64 `json:"version"`
+		Type      string  `json:"type"`
+		Generator string  `json:"generator"`
+	}
+	threeJSGeometry struct {
+		UUID           string  `json:"uuid"`
+		Type           string  `json:"type"`
+		Width          float64 `json:"width,omitempty"`
+		Height         float64 `json:"height,omitempty"`
+		Depth          float64 `json:"depth,omitempty"`
+		Radius         float64 `json:"radius,omitempty"`
+		Length         float64 `json:"length,omitempty"`
+		WidthSegments  int     `json:"widthSegments,omitempty"`
+		HeightSegments int     `json:"heightSegments,omitempty"`
+		CapSegments    int     `json:"capSegments,omitempty"`
+		RadialSegments int     `json:"radialSegments,omitempty"`
+
+		Data *threeJSBufferData `json:"data,omitempty"`
+	}
+	threeJSBufferData struct {
+		Attributes map[string]threeJSBufferAttribute `json:"attributes"`
+	}
+	threeJSBufferAttribute struct {
+		ItemSize   int       `json:"itemSize"`
+		Type       string    `json:"type"`
+		Array      []float64 `json:"array"`
+		Normalized bool      `json:"normalized"`
+	}
+	threeJSMaterial struct {
+		UUID        string  `json:"uuid"`
+		Type        string  `json:"type"`
+		Color       int     `json:"color"`
+		Opacity     float64 `json:"opacity"`
+		Transparent bool    `json:"transparent"`
+	}
+	threeJSAnimation struct {
+		UUID     string         `json:"uuid"`
+		Name     string         `json:"name"`
+		Duration float64        `json:"duration"`
+		Tracks   []threeJSTrack `json:"tracks"`
+	}
+	threeJSTrack struct {
+		Name   string    `json:"name"`
+		Type   string    `json:"type"`
+		Times  []float64 `json:"times"`
+		Values []float64 `json:"values"`
+	}
+	threeJSObject struct {
+		UUID       string          `json:"uuid"`
+		Type       string          `json:"type"`
+		Name       string          `json:"name,omitempty"`
+		Geometry   string          `json:"geometry,omitempty"`
+		Material   string          `json:"material,omitempty"`
+		Matrix     []float64       `json:"matrix,omitempty"`
+		Children   []threeJSObject `json:"children,omitempty"`
+		Animations []string        `json:"animations,omitempty"`
+	}
+)
+
+const (
+	threeJSRobotColor    = 0x8899aa
+	threeJSObstacleColor = 0xcc3333
+)
+
+// threeJS returns the scene in the three.js JSON object format. Robot geometries are placed as at the first step of
+// the plan and moved through the rest of its steps by the scene's animation clip.
+func (scene *PlanScene) threeJS() *threeJSScene {
+	robotMaterial := threeJSUUID("material", "robot")
+	obstacleMaterial := threeJSUUID("material", "obstacle")
+	three := &threeJSScene{
+		Metadata: threeJSMetadata{Version: 4.6, Type: "Object", Generator: "rdk motionplan"},
+		Materials: []threeJSMaterial{
+			{UUID: robotMaterial, Type: "MeshLambertMaterial", Color: threeJSRobotColor, Opacity: 1},
+			{UUID: obstacleMaterial, Type: "MeshLambertMaterial", Color: threeJSObstacleColor, Opacity: 0.5, Transparent: true},
+		},
+		Object: threeJSObject{UUID: threeJSUUID("scene", ""), Type: "Scene"},
+	}
+	addObject := func(kind string, geometry PlanSceneGeometry, material string) string {
+		objectUUID := threeJSUUID(kind, geometry.Frame+":"+geometry.geometry.Label())
+		threeGeometry, offset := newThreeJSGeometry(threeJSUUID("geometry", objectUUID), geometry.Geometry)
+		three.Geometries = append(three.Geometries, threeGeometry)
+		three.Object.Children = append(three.Object.Children, threeJSObject{
+			UUID:     objectUUID,
+			Type:     "Mesh",
+			Name:     geometry.geometry.Label(),
+			Geometry: threeGeometry.UUID,
+			Material: material,
+			Matrix:   threeJSMatrix(spatialmath.Compose(geometry.geometry.Pose(), offset)),
+		})
+		return objectUUID
+	}
+
+	for _, obstacle := range scene.Obstacles {
+		addObject("obstacle", obstacle, obstacleMaterial)
+	}
+	if len(scene.Steps) == 0 {
+		return three
+	}
+
+	clip := threeJSAnimation{
+		UUID:     threeJSUUID("animation", "plan"),
+		Name:     "plan",
+		Duration: scene.Steps[len(scene.Steps)-1].TimeSec,
+	}
+	for i, geometry := range scene.Steps[0].Geometries {
+		objectUUID := addObject("robot", geometry, robotMaterial)
+		_, offset := newThreeJSGeometry("", geometry.Geometry)
+		position := threeJSTrack{Name: objectUUID + ".position", Type: "vector"}
+		quaternion := threeJSTrack{Name: objectUUID + ".quaternion", Type: "quaternion"}
+		for _, step := range scene.Steps {
+			if i >= len(step.Geometries) {
+				continue
+			}
+			pose := spatialmath.Compose(step.Geometries[i].geometry.Pose(), offset)
+			pt := pose.Point().Mul(1e-3)
+			q := pose.Orientation().Quaternion()
+			position.Times = append(position.Times, step.TimeSec)
+			position.Values = append(position.Values, pt.X, pt.Y, pt.Z)
+			quaternion.Times = append(quaternion.Times, step.TimeSec)
+			quaternion.Values = append(quaternion.Values, q.Imag, q.Jmag, q.Kmag, q.Real)
+		}
+		clip.Tracks = append(clip.Tracks, position, quaternion)
+	}
+	three.Animations = []threeJSAnimation{clip}
+	three.Object.Animations = []string{clip.UUID}
+	return three
+}
+
+// newThreeJSGeometry returns the three.js geometry of `config` and the offset of the three.js geometry from the pose of
+// the geometry.
+func newThreeJSGeometry(geometryUUID string, config *spatialmath.GeometryConfig) (threeJSGeometry, spatialmath.Pose) {
+	threeGeometry := threeJSGeometry{UUID: geometryUUID}
+	offset := spatialmath.NewZeroPose()
+	switch config.Type {
+	case spatialmath.BoxType:
+		threeGeometry.Type = "BoxGeometry"
+		threeGeometry.Width, threeGeometry.Height, threeGeometry.Depth = config.X*1e-3, config.Y*1e-3, config.Z*1e-3
+	case spatialmath.CapsuleType:
+		// three.js capsules are along the y axis and their length does not include their caps
+		threeGeometry.Type = "CapsuleGeometry"
+		threeGeometry.Radius = config.R * 1e-3
+		threeGeometry.Length = (config.L - 2*config.R) * 1e-3
+		threeGeometry.CapSegments, threeGeometry.RadialSegments = 8, 16
+		offset = spatialmath.NewPoseFromOrientation(&spatialmath.R4AA{Theta: math.Pi / 2, RX: 1})
+	case spatialmath.SphereType:
+		threeGeometry.Type = "SphereGeometry"
+		threeGeometry.Radius = config.R * 1e-3
+		threeGeometry.WidthSegments, threeGeometry.HeightSegments = 16, 12
+	case spatialmath.PointType, spatialmath.UnknownType:
+		threeGeometry.Type = "SphereGeometry"
+		threeGeometry.Radius = 5e-3
+		threeGeometry.WidthSegments, threeGeometry.HeightSegments = 8, 6
+	case spatialmath.MeshType:
+		threeGeometry.Type = "BufferGeometry"
+		threeGeometry.Data = newThreeJSMeshData(config)
+	}
+	return threeGeometry, offset
+}
+
+// newThreeJSMeshData returns the vertices and normals, in the frame of the mesh, of each triangle of the mesh of
+// `config`.
+func newThreeJSMeshData(config *spatialmath.GeometryConfig) *threeJSBufferData {
+	var positions, normals []float64
+	if geometry, err := config.ParseConfig(); err == nil {
+		if mesh, ok := geometry.(*spatialmath.Mesh); ok {
+			for _, triangle := range mesh.Triangles() {
+				normal := triangle.Normal()
+				for _, pt := range triangle.Points() {
+					pt = pt.Mul(1e-3)
+					positions = append(positions, pt.X, pt.Y, pt.Z)
+					normals = append(normals, normal.X, normal.Y, normal.Z)
+				}
+			}
+		}
+	}
+	return &threeJSBufferData{Attributes: map[string]threeJSBufferAttribute{
+		"position": {ItemSize: 3, Type: "Float32Array", Array: positions},
+		"normal":   {ItemSize: 3, Type: "Float32Array", Array: normals},
+	}}
+}
+
+// threeJSMatrix returns the column major transformation matrix of `pose`, in meters.
+func threeJSMatrix(pose spatialmath.Pose) []float64 {
+	rm := pose.Orientation().RotationMatrix()
+	pt := pose.Point().Mul(1e-3)
+	return []float64{
+		rm.At(0, 0), rm.At(1, 0), rm.At(2, 0), 0,
+		rm.At(0, 1), rm.At(1, 1), rm.At(2, 1), 0,
+		rm.At(0, 2), rm.At(1, 2), rm.At(2, 2), 0,
+		pt.X, pt.Y, pt.Z, 1,
+	}
+}
+
+// threeJSUUID returns a UUID for a three.js object that is the same each time a scene is exported.
+func threeJSUUID(kind, name string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(kind+"/"+name)).String()
+}
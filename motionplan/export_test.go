@@ -0,0 +1,116 @@
+package motionplan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestExportPlan(t *testing.T) {
+	// a gantry moving a box along x towards an obstacle
+	box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "carriage")
+	test.That(t, err, test.ShouldBeNil)
+	gantry, err := referenceframe.NewTranslationalFrameWithGeometry(
+		"gantry", r3.Vector{X: 1}, referenceframe.Limit{Min: -1000, Max: 1000}, box,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	test.That(t, fs.AddFrame(gantry, fs.World()), test.ShouldBeNil)
+
+	obstacle, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 100}), r3.Vector{X: 10, Y: 10, Z: 10}, "wall")
+	test.That(t, err, test.ShouldBeNil)
+	worldState, err := referenceframe.NewWorldState(
+		[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{obstacle})},
+		nil,
+	)
+	test.That(t, err, test.ShouldBeNil)
+
+	trajectory := Trajectory{}
+	path := Path{}
+	for _, x := range []float64{0, 50, 100} {
+		inputs := referenceframe.FrameSystemInputs{"gantry": referenceframe.FloatsToInputs([]float64{x})}
+		poses, err := inputs.ComputePoses(fs)
+		test.That(t, err, test.ShouldBeNil)
+		trajectory = append(trajectory, inputs)
+		path = append(path, poses)
+	}
+	plan := NewSimplePlan(path, trajectory)
+
+	t.Run("scene", func(t *testing.T) {
+		scene, err := NewPlanScene(plan, fs, worldState)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(scene.Obstacles), test.ShouldEqual, 1)
+		test.That(t, scene.Obstacles[0].Geometry.Label, test.ShouldEqual, "wall")
+		test.That(t, len(scene.Steps), test.ShouldEqual, 3)
+
+		step := scene.Steps[1]
+		test.That(t, step.TimeSec, test.ShouldEqual, 1)
+		test.That(t, step.Inputs["gantry"], test.ShouldResemble, []float64{50})
+		test.That(t, step.Poses["gantry"].Translation.X, test.ShouldAlmostEqual, 50)
+		test.That(t, len(step.Geometries), test.ShouldEqual, 1)
+		test.That(t, step.Geometries[0].Frame, test.ShouldEqual, "gantry")
+		test.That(t, step.Geometries[0].Geometry.TranslationOffset.X, test.ShouldAlmostEqual, 50)
+
+		// only the last step runs into the wall
+		test.That(t, scene.Steps[0].Collisions, test.ShouldBeEmpty)
+		test.That(t, step.Collisions, test.ShouldBeEmpty)
+		test.That(t, len(scene.Steps[2].Collisions), test.ShouldEqual, 1)
+		test.That(t, scene.Steps[2].Collisions[0], test.ShouldContainSubstring, "wall")
+	})
+
+	t.Run("timed plans keep their times", func(t *testing.T) {
+		timed, err := NewTimedPlan(plan, VelocityConstraint{MaxLinearVelMmPerSec: 100})
+		test.That(t, err, test.ShouldBeNil)
+		scene, err := NewPlanScene(timed, fs, worldState)
+		test.That(t, err, test.ShouldBeNil)
+		for i, step := range scene.Steps {
+			test.That(t, step.TimeSec, test.ShouldEqual, timed.Times()[i].Seconds())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		exported, err := ExportPlan(plan, fs, worldState, PlanExportJSON)
+		test.That(t, err, test.ShouldBeNil)
+		var scene PlanScene
+		test.That(t, json.Unmarshal(exported, &scene), test.ShouldBeNil)
+		test.That(t, len(scene.Steps), test.ShouldEqual, 3)
+		test.That(t, scene.Steps[2].Inputs["gantry"], test.ShouldResemble, []float64{100})
+	})
+
+	t.Run("threejs", func(t *testing.T) {
+		exported, err := ExportPlan(plan, fs, worldState, PlanExportThreeJS)
+		test.That(t, err, test.ShouldBeNil)
+		var scene threeJSScene
+		test.That(t, json.Unmarshal(exported, &scene), test.ShouldBeNil)
+		test.That(t, scene.Metadata.Type, test.ShouldEqual, "Object")
+		test.That(t, len(scene.Geometries), test.ShouldEqual, 2)
+		test.That(t, scene.Geometries[0].Type, test.ShouldEqual, "BoxGeometry")
+		test.That(t, scene.Geometries[0].Width, test.ShouldAlmostEqual, 0.01)
+		test.That(t, len(scene.Object.Children), test.ShouldEqual, 2)
+
+		// the obstacle is placed in meters, and the carriage is animated along x
+		test.That(t, scene.Object.Children[0].Matrix[12], test.ShouldAlmostEqual, 0.1)
+		test.That(t, len(scene.Animations), test.ShouldEqual, 1)
+		test.That(t, scene.Object.Animations, test.ShouldResemble, []string{scene.Animations[0].UUID})
+		test.That(t, scene.Animations[0].Duration, test.ShouldEqual, 2)
+		position := scene.Animations[0].Tracks[0]
+		test.That(t, position.Name, test.ShouldEqual, scene.Object.Children[1].UUID+".position")
+		test.That(t, position.Times, test.ShouldResemble, []float64{0, 1, 2})
+		test.That(t, position.Values[6], test.ShouldAlmostEqual, 0.1)
+
+		// exports are the same each time
+		again, err := ExportPlan(plan, fs, worldState, PlanExportThreeJS)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, string(again), test.ShouldEqual, string(exported))
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := ExportPlan(plan, fs, worldState, PlanExportFormat("urdf"))
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
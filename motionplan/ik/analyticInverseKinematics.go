@@ -0,0 +1,238 @@
+package ik
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// errAnalyticUnsupported is returned when creating an AnalyticSolver for a model with no known closed form solution.
+var errAnalyticUnsupported = errors.New("analytic inverse kinematics are only supported for arms laid out like Universal Robots arms")
+
+// Distance, in mm, below which analytic solutions are considered to reach their goal.
+const analyticEpsilon = 1e-4
+
+// The joint axes of arms laid out like Universal Robots arms: a base joint about z, three parallel joints about -y, a wrist
+// joint about -z and a last one about -y.
+var urAxes = []r3.Vector{{Z: 1}, {Y: -1}, {Y: -1}, {Y: -1}, {Z: -1}, {Y: -1}}
+
+// AnalyticSolver computes the inverse kinematics of a model in closed form, finding every solution for a goal at once rather
+// than searching for them. Only 6DoF arms laid out like Universal Robots arms are supported.
+type AnalyticSolver struct {
+	model  referenceframe.Model
+	limits []referenceframe.Limit
+
+	// the offsets, in mm, between the joints of the arm, and the orientation of its end relative to its last joint
+	d1, a2, a3, d4, d5, d6 float64
+	tool                   spatialmath.Orientation
+}
+
+// NewAnalyticSolver returns an AnalyticSolver for the model, or an error if the model has no known closed form solution.
+func NewAnalyticSolver(model referenceframe.Model) (*AnalyticSolver, error) {
+	statics, axes, err := modelChain(model.ModelConfig())
+	if err != nil {
+		return nil, err
+	}
+	if len(axes) != len(urAxes) || len(model.DoF()) != len(urAxes) {
+		return nil, errAnalyticUnsupported
+	}
+	for i, axis := range axes {
+		if !spatialmath.R3VectorAlmostEqual(axis.Normalize(), urAxes[i], 1e-9) {
+			return nil, errAnalyticUnsupported
+		}
+	}
+	// every offset but the last is a translation along a single axis
+	for i, static := range statics[:len(statics)-1] {
+		if !spatialmath.OrientationAlmostEqual(static.Orientation(), spatialmath.NewZeroOrientation()) {
+			return nil, errAnalyticUnsupported
+		}
+		pt := static.Point()
+		if (i != 2 && i != 3 && pt.X != 0) || (i != 4 && i != 6 && pt.Y != 0) || (i != 0 && i != 5 && pt.Z != 0) {
+			return nil, errAnalyticUnsupported
+		}
+	}
+	last := statics[len(statics)-1]
+	if last.Point().X != 0 || last.Point().Z != 0 {
+		return nil, errAnalyticUnsupported
+	}
+	s := &AnalyticSolver{
+		model:  model,
+		limits: model.DoF(),
+		d1:     statics[0].Point().Z,
+		a2:     statics[2].Point().X,
+		a3:     statics[3].Point().X,
+		d4:     statics[4].Point().Y,
+		d5:     statics[5].Point().Z,
+		d6:     last.Point().Y,
+		tool:   last.Orientation(),
+	}
+	if s.a2 == 0 || s.a3 == 0 {
+		return nil, errAnalyticUnsupported
+	}
+	return s, nil
+}
+
+// modelChain returns the static offsets between the joints of a serial model, starting with the offset of its first joint
+// from its origin, and the axes of its joints, which must all be revolute.
+func modelChain(cfg *referenceframe.ModelConfig) ([]spatialmath.Pose, []r3.Vector, error) {
+	if cfg == nil || len(cfg.DHParams) > 0 {
+		return nil, nil, errAnalyticUnsupported
+	}
+	children := map[string]string{}
+	links := map[string]referenceframe.LinkConfig{}
+	joints := map[string]referenceframe.JointConfig{}
+	for _, link := range cfg.Links {
+		if _, ok := children[link.Parent]; ok {
+			return nil, nil, errAnalyticUnsupported
+		}
+		children[link.Parent] = link.ID
+		links[link.ID] = link
+	}
+	for _, joint := range cfg.Joints {
+		if _, ok := children[joint.Parent]; ok {
+			return nil, nil, errAnalyticUnsupported
+		}
+		children[joint.Parent] = joint.ID
+		joints[joint.ID] = joint
+	}
+
+	statics := []spatialmath.Pose{spatialmath.NewZeroPose()}
+	var axes []r3.Vector
+	for name := children[referenceframe.World]; name != ""; name = children[name] {
+		if link, ok := links[name]; ok {
+			orientation := spatialmath.NewZeroOrientation()
+			if link.Orientation != nil {
+				var err error
+				if orientation, err = link.Orientation.ParseConfig(); err != nil {
+					return nil, nil, err
+				}
+			}
+			statics[len(statics)-1] = spatialmath.Compose(statics[len(statics)-1], spatialmath.NewPose(link.Translation, orientation))
+			continue
+		}
+		joint := joints[name]
+		if joint.Type != referenceframe.RevoluteJoint || joint.Mimic != nil {
+			return nil, nil, errAnalyticUnsupported
+		}
+		axes = append(axes, r3.Vector(joint.Axis))
+		statics = append(statics, spatialmath.NewZeroPose())
+	}
+	return statics, axes, nil
+}
+
+// DoF returns the DoF of the solver.
+func (s *AnalyticSolver) DoF() []referenceframe.Limit {
+	return s.limits
+}
+
+// Solutions returns every configuration, within the limits of the model, which places the end of the model at the goal, relative
+// to the origin of the model. Joints which can reach their value in more than one turn are placed at the turn closest to the seed.
+func (s *AnalyticSolver) Solutions(goal spatialmath.Pose, seed []referenceframe.Input) [][]float64 {
+	// the pose of the last joint, and the position of the joint before it
+	flange := spatialmath.Compose(goal, spatialmath.NewPoseFromOrientation(spatialmath.OrientationInverse(s.tool)))
+	yf := rotate(flange.Orientation(), r3.Vector{Y: 1})
+	p5 := flange.Point().Sub(yf.Mul(s.d6)).Sub(r3.Vector{Z: s.d1})
+
+	// the base joint places the wrist, which is offset from the plane of the parallel joints, in that plane
+	r := math.Hypot(p5.X, p5.Y)
+	if r < math.Abs(s.d4) {
+		return nil
+	}
+	phi := math.Atan2(p5.Y, p5.X)
+	alpha := math.Asin(s.d4 / r)
+
+	var solutions [][]float64
+	for _, q1 := range []float64{phi - alpha, phi - math.Pi + alpha} {
+		base := zRotation(-q1)
+		baseFlange := spatialmath.Compose(spatialmath.NewPoseFromOrientation(base), flange).Orientation()
+		wrist := rotate(base, p5)
+
+		// the last joint's axis is tilted from the parallel joints' axis by the fifth joint
+		b := rotate(base, yf)
+		for _, q5 := range []float64{math.Acos(clamp(b.Y)), -math.Acos(clamp(b.Y))} {
+			// s is the sum of the angles of the parallel joints
+			var sum, q6 float64
+			if sin5 := math.Sin(q5); math.Abs(sin5) > 1e-9 {
+				sum = math.Atan2(b.Z/sin5, b.X/sin5)
+				m := composeOrientations(zRotation(q5), yRotation(sum), baseFlange)
+				mx := rotate(m, r3.Vector{X: 1})
+				q6 = math.Atan2(mx.Z, mx.X)
+			} else {
+				// the first and last joints are aligned, so any split of the rotation between them works
+				nx := rotate(composeOrientations(baseFlange, zRotation(q5)), r3.Vector{X: 1})
+				sum = math.Atan2(nx.Z, nx.X)
+			}
+
+			// the two links between the parallel joints reach the joint after them, like a planar arm
+			w := wrist.Sub(r3.Vector{Y: s.d4}).Sub(r3.Vector{X: -math.Sin(sum), Z: math.Cos(sum)}.Mul(s.d5))
+			c3 := (w.X*w.X + w.Z*w.Z - s.a2*s.a2 - s.a3*s.a3) / (2 * s.a2 * s.a3)
+			if math.Abs(c3) > 1+1e-9 {
+				continue
+			}
+			for _, q3 := range []float64{math.Acos(clamp(c3)), -math.Acos(clamp(c3))} {
+				q2 := math.Atan2(w.Z, w.X) - math.Atan2(s.a3*math.Sin(q3), s.a2+s.a3*math.Cos(q3))
+				q4 := sum - q2 - q3
+				if solution, ok := s.fitLimits([]float64{q1, q2, q3, q4, q5, q6}, seed); ok && s.reaches(solution, goal) {
+					solutions = append(solutions, solution)
+				}
+			}
+		}
+	}
+	return solutions
+}
+
+// fitLimits turns each joint of the solution to the equivalent angle within its limits closest to the seed, if there is one.
+func (s *AnalyticSolver) fitLimits(solution []float64, seed []referenceframe.Input) ([]float64, bool) {
+	for i, q := range solution {
+		target := 0.
+		if i < len(seed) {
+			target = seed[i].Value
+		}
+		q += 2 * math.Pi * math.Round((target-q)/(2*math.Pi))
+		for q > s.limits[i].Max && q-2*math.Pi >= s.limits[i].Min {
+			q -= 2 * math.Pi
+		}
+		for q < s.limits[i].Min && q+2*math.Pi <= s.limits[i].Max {
+			q += 2 * math.Pi
+		}
+		if q < s.limits[i].Min || q > s.limits[i].Max {
+			return nil, false
+		}
+		solution[i] = q
+	}
+	return solution, true
+}
+
+// reaches checks a solution against the model itself, guarding against a model whose configuration was misread.
+func (s *AnalyticSolver) reaches(solution []float64, goal spatialmath.Pose) bool {
+	pose, err := s.model.Transform(referenceframe.FloatsToInputs(solution))
+	return err == nil && spatialmath.PoseAlmostEqualEps(pose, goal, analyticEpsilon)
+}
+
+func rotate(o spatialmath.Orientation, v r3.Vector) r3.Vector {
+	return spatialmath.Compose(spatialmath.NewPoseFromOrientation(o), spatialmath.NewPoseFromPoint(v)).Point()
+}
+
+func composeOrientations(orientations ...spatialmath.Orientation) spatialmath.Orientation {
+	pose := spatialmath.NewZeroPose()
+	for _, o := range orientations {
+		pose = spatialmath.Compose(pose, spatialmath.NewPoseFromOrientation(o))
+	}
+	return pose.Orientation()
+}
+
+func zRotation(theta float64) spatialmath.Orientation {
+	return &spatialmath.R4AA{Theta: theta, RZ: 1}
+}
+
+func yRotation(theta float64) spatialmath.Orientation {
+	return &spatialmath.R4AA{Theta: theta, RY: 1}
+}
+
+func clamp(x float64) float64 {
+	return math.Max(-1, math.Min(1, x))
+}
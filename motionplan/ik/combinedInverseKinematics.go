@@ -30,22 +30,51 @@ func CreateCombinedIKSolver(
 	nCPU int,
 	goalThreshold float64,
 ) (Solver, error) {
-	ik := &combinedIK{}
-	ik.limits = limits
 	if nCPU == 0 {
 		nCPU = 1
 	}
+	solvers := make([]Solver, 0, nCPU)
 	for i := 1; i <= nCPU; i++ {
-		solver, err := CreateNloptSolver(ik.limits, logger, -1, true, true)
-		nlopt := solver.(*nloptIK)
-		nlopt.id = i
+		solver, err := CreateNloptSolver(limits, logger, -1, true, true)
 		if err != nil {
 			return nil, err
 		}
-		ik.solvers = append(ik.solvers, nlopt)
+		solver.(*nloptIK).id = i
+		solvers = append(solvers, solver)
 	}
-	ik.logger = logger
-	return ik, nil
+	return &combinedIK{solvers: solvers, logger: logger, limits: limits}, nil
+}
+
+// CreateTracIKSolver creates a combined parallel IK solver in the style of TRAC-IK, which races nlopt's SLSQP against
+// gradient descent. As the two get stuck in different places, together they solve more often and sooner than either alone.
+// Half of the nCPU solvers passed in, rounding up, are nlopt solvers.
+func CreateTracIKSolver(
+	limits []referenceframe.Limit,
+	logger logging.Logger,
+	nCPU int,
+	goalThreshold float64,
+) (Solver, error) {
+	if nCPU < 2 {
+		nCPU = 2
+	}
+	solvers := make([]Solver, 0, nCPU)
+	for i := 1; i <= nCPU; i++ {
+		var solver Solver
+		var err error
+		if i%2 == 1 {
+			solver, err = CreateNloptSolver(limits, logger, -1, true, true)
+			if err == nil {
+				solver.(*nloptIK).id = i
+			}
+		} else {
+			solver, err = CreateGradientDescentSolver(limits, logger, -1)
+		}
+		if err != nil {
+			return nil, err
+		}
+		solvers = append(solvers, solver)
+	}
+	return &combinedIK{solvers: solvers, logger: logger, limits: limits}, nil
 }
 
 // Solve will initiate solving for the given position in all child solvers, seeding with the specified initial joint
@@ -0,0 +1,145 @@
+//go:build !windows && !no_cgo
+
+package ik
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// The names of the IK solvers that can be selected.
+const (
+	// NloptSolverName is the default solver, which runs nlopt's SLSQP in parallel from different seeds.
+	NloptSolverName = "nlopt"
+	// TracIKSolverName is the solver which races nlopt's SLSQP against gradient descent, in the style of TRAC-IK.
+	TracIKSolverName = "trac_ik"
+	// AnalyticSolverName is the closed form solver of AnalyticSolver. As it solves for goal poses rather than minimizing a
+	// function, CreateSolver does not create it; callers use it directly for the models it supports.
+	AnalyticSolverName = "analytic"
+)
+
+// SolverConfig selects the IK solvers to use and how long each may run.
+type SolverConfig struct {
+	// The names of the solvers to try in order, each falling back to the next when it finds no solutions within the budget.
+	// If there are none, nlopt is used.
+	Solvers []string
+	// How long each solver may run for each call to Solve. Zero means each runs until it finishes.
+	Budget time.Duration
+}
+
+// CreateSolver creates the solver selected by the config, which runs on nCPU threads.
+func CreateSolver(
+	cfg SolverConfig,
+	limits []referenceframe.Limit,
+	logger logging.Logger,
+	nCPU int,
+	goalThreshold float64,
+) (Solver, error) {
+	names := cfg.Solvers
+	if len(names) == 0 {
+		names = []string{NloptSolverName}
+	}
+	solvers := make([]Solver, 0, len(names))
+	for _, name := range names {
+		var solver Solver
+		var err error
+		switch name {
+		case NloptSolverName:
+			solver, err = CreateCombinedIKSolver(limits, logger, nCPU, goalThreshold)
+		case TracIKSolverName:
+			solver, err = CreateTracIKSolver(limits, logger, nCPU, goalThreshold)
+		default:
+			return nil, fmt.Errorf("unknown IK solver %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		solvers = append(solvers, solver)
+	}
+	if len(solvers) == 1 && cfg.Budget <= 0 {
+		return solvers[0], nil
+	}
+	return &fallbackIK{solvers: solvers, names: names, budget: cfg.Budget, limits: limits, logger: logger}, nil
+}
+
+// fallbackIK runs solvers one after another, each for at most its budget, until one finds a solution.
+type fallbackIK struct {
+	solvers []Solver
+	names   []string
+	budget  time.Duration
+	limits  []referenceframe.Limit
+	logger  logging.Logger
+}
+
+// DoF returns the DoF of the solver.
+func (ik *fallbackIK) DoF() []referenceframe.Limit {
+	return ik.limits
+}
+
+// Solve runs each solver in turn, stopping after the first which finds any solutions. A solver which runs out of budget
+// after finding solutions has succeeded.
+func (ik *fallbackIK) Solve(ctx context.Context,
+	c chan<- *Solution,
+	seed []float64,
+	m func([]float64) float64,
+	rseed int,
+) error {
+	var errs error
+	for i, solver := range ik.solvers {
+		solveCtx, cancel := ctx, context.CancelFunc(func() {})
+		if ik.budget > 0 {
+			solveCtx, cancel = context.WithTimeout(ctx, ik.budget)
+		}
+		found, err := forwardSolutions(solveCtx, solver, c, seed, m, rseed)
+		cancel()
+		if found > 0 {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		errs = multierr.Combine(errs, err)
+		if i < len(ik.solvers)-1 {
+			ik.logger.Debugf("IK solver %q found no solutions, falling back to %q", ik.names[i], ik.names[i+1])
+		}
+	}
+	return multierr.Combine(errs, errNoSolve)
+}
+
+// forwardSolutions runs the solver, forwarding its solutions to c until ctx is done, and returns how many it found.
+func forwardSolutions(
+	ctx context.Context,
+	solver Solver,
+	c chan<- *Solution,
+	seed []float64,
+	m func([]float64) float64,
+	rseed int,
+) (int, error) {
+	solutions := make(chan *Solution)
+	errChan := make(chan error, 1)
+	utils.PanicCapturingGo(func() {
+		errChan <- solver.Solve(ctx, solutions, seed, m, rseed)
+	})
+
+	found := 0
+	for {
+		select {
+		case solution := <-solutions:
+			// keep receiving after ctx is done so the solver is never left blocked on sending
+			select {
+			case c <- solution:
+				found++
+			case <-ctx.Done():
+			}
+		case err := <-errChan:
+			return found, err
+		}
+	}
+}
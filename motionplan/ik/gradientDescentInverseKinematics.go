@@ -0,0 +1,214 @@
+package ik
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+)
+
+const (
+	defaultGradientDescentRestarts = 500
+	gradientDescentStepsPerRestart = 500
+	gradientDescentMinStep         = 1e-10
+	gradientDescentJump            = 1e-8
+)
+
+// gradientDescentIK minimizes with BFGS, a quasi-Newton gradient descent, and a backtracking line search, restarting from
+// random configurations. It needs no cgo, and as it gets stuck in different places than nlopt's SLSQP, the two complement each other.
+type gradientDescentIK struct {
+	limits   []referenceframe.Limit
+	restarts int
+	epsilon  float64
+	logger   logging.Logger
+}
+
+// CreateGradientDescentSolver creates a solver which minimizes functions by gradient descent, restarting from a random
+// configuration up to the given number of times. If the restart count is less than 1, it will be set to the default of 500.
+func CreateGradientDescentSolver(limits []referenceframe.Limit, logger logging.Logger, restarts int) (Solver, error) {
+	if restarts < 1 {
+		restarts = defaultGradientDescentRestarts
+	}
+	return &gradientDescentIK{
+		limits:   limits,
+		restarts: restarts,
+		epsilon:  defaultEpsilon * defaultEpsilon,
+		logger:   logger,
+	}, nil
+}
+
+// DoF returns the DoF of the solver.
+func (ik *gradientDescentIK) DoF() []referenceframe.Limit {
+	return ik.limits
+}
+
+// Solve runs gradient descent from the seed, and then from random configurations, sending each solution it finds to the channel.
+func (ik *gradientDescentIK) Solve(ctx context.Context,
+	solutionChan chan<- *Solution,
+	seed []float64,
+	minFunc func([]float64) float64,
+	rseed int,
+) error {
+	if len(seed) != len(ik.limits) {
+		return fmt.Errorf("gradient descent initialized with %d dof but seed was length %d", len(ik.limits), len(seed))
+	}
+	//nolint: gosec
+	randSeed := rand.New(rand.NewSource(int64(rseed)))
+	lowerBound, upperBound := limitsToArrays(ik.limits)
+
+	solutionsFound := 0
+	for restart := 0; restart < ik.restarts; restart++ {
+		solution, score, err := ik.descend(ctx, seed, minFunc, lowerBound, upperBound)
+		if err != nil {
+			return err
+		}
+		if score < ik.epsilon {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case solutionChan <- &Solution{Configuration: solution, Score: score, Exact: true}:
+			}
+			solutionsFound++
+		}
+		seed = generateRandomPositions(randSeed, lowerBound, upperBound)
+	}
+	if solutionsFound > 0 {
+		return nil
+	}
+	return errNoSolve
+}
+
+// descend minimizes from the seed with BFGS until reaching the goal or a local minimum.
+func (ik *gradientDescentIK) descend(
+	ctx context.Context,
+	seed []float64,
+	minFunc func([]float64) float64,
+	lowerBound, upperBound []float64,
+) ([]float64, float64, error) {
+	n := len(seed)
+	x := append([]float64{}, seed...)
+	clampToBounds(x, lowerBound, upperBound)
+	score := minFunc(x)
+	gradient := ik.gradient(x, score, minFunc, upperBound)
+
+	// the approximation of the inverse Hessian starts as the identity, making the first step one of steepest descent
+	hessian := identity(n)
+	direction := make([]float64, n)
+	candidate := make([]float64, n)
+	for i := 0; i < gradientDescentStepsPerRestart && score >= ik.epsilon; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+		slope := 0.
+		for j := range direction {
+			direction[j] = 0
+			for k := range gradient {
+				direction[j] -= hessian[j][k] * gradient[k]
+			}
+			slope += direction[j] * gradient[j]
+		}
+		if slope >= 0 {
+			// the approximation has gone bad, so start over from steepest descent
+			hessian = identity(n)
+			copy(direction, gradient)
+			slope = 0
+			for j := range direction {
+				direction[j] = -direction[j]
+				slope -= gradient[j] * gradient[j]
+			}
+		}
+		if slope == 0 || math.IsNaN(slope) || math.IsInf(slope, 0) {
+			break
+		}
+
+		// backtrack along the direction until the step sufficiently decreases the score
+		candidateScore := score
+		for step := 1.; step > gradientDescentMinStep; step /= 2 {
+			for j := range x {
+				candidate[j] = x[j] + step*direction[j]
+			}
+			clampToBounds(candidate, lowerBound, upperBound)
+			if candidateScore = minFunc(candidate); candidateScore <= score+1e-4*step*slope {
+				break
+			}
+		}
+		if candidateScore >= score {
+			break
+		}
+		candidateGradient := ik.gradient(candidate, candidateScore, minFunc, upperBound)
+
+		// update the inverse Hessian with the BFGS formula, skipping updates that would not keep it positive definite
+		s := make([]float64, n)
+		y := make([]float64, n)
+		sy := 0.
+		for j := range s {
+			s[j] = candidate[j] - x[j]
+			y[j] = candidateGradient[j] - gradient[j]
+			sy += s[j] * y[j]
+		}
+		if sy > 1e-12 {
+			hessian = bfgsUpdate(hessian, s, y, 1/sy)
+		}
+		copy(x, candidate)
+		score = candidateScore
+		gradient = candidateGradient
+	}
+	return x, score, nil
+}
+
+// gradient estimates the gradient of minFunc at x, whose score is given, by forward differences.
+func (ik *gradientDescentIK) gradient(x []float64, score float64, minFunc func([]float64) float64, upperBound []float64) []float64 {
+	gradient := make([]float64, len(x))
+	for j := range x {
+		// step backwards at the upper bound so as to stay within the limits
+		jump := gradientDescentJump
+		if x[j]+jump > upperBound[j] {
+			jump = -jump
+		}
+		orig := x[j]
+		x[j] += jump
+		gradient[j] = (minFunc(x) - score) / jump
+		x[j] = orig
+	}
+	return gradient
+}
+
+// bfgsUpdate returns (I - rho s y^T) h (I - rho y s^T) + rho s s^T.
+func bfgsUpdate(h [][]float64, s, y []float64, rho float64) [][]float64 {
+	n := len(s)
+	hy := make([]float64, n)
+	yhy := 0.
+	for i := range hy {
+		for j := range y {
+			hy[i] += h[i][j] * y[j]
+		}
+		yhy += y[i] * hy[i]
+	}
+	updated := make([][]float64, n)
+	for i := range updated {
+		updated[i] = make([]float64, n)
+		for j := range updated[i] {
+			// h is symmetric, so y^T h is hy transposed
+			updated[i][j] = h[i][j] - rho*(s[i]*hy[j]+hy[i]*s[j]) + (rho*rho*yhy+rho)*s[i]*s[j]
+		}
+	}
+	return updated
+}
+
+func identity(n int) [][]float64 {
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+		m[i][i] = 1
+	}
+	return m
+}
+
+func clampToBounds(x, lowerBound, upperBound []float64) {
+	for i := range x {
+		x[i] = math.Max(lowerBound[i], math.Min(upperBound[i], x[i]))
+	}
+}
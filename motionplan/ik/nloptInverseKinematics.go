@@ -17,10 +17,7 @@ import (
 	"go.viam.com/rdk/referenceframe"
 )
 
-var (
-	errNoSolve   = errors.New("kinematics could not solve for position")
-	errBadBounds = errors.New("cannot set upper or lower bounds for nlopt, slice is empty. Are you trying to move a static frame?")
-)
+var errBadBounds = errors.New("cannot set upper or lower bounds for nlopt, slice is empty. Are you trying to move a static frame?")
 
 const (
 	nloptStepsPerIter = 4001
@@ -8,6 +8,8 @@ import (
 	"math/rand"
 	"strings"
 
+	"github.com/pkg/errors"
+
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/referenceframe"
 )
@@ -23,6 +25,8 @@ const (
 	defaultGoalThreshold = defaultEpsilon * defaultEpsilon
 )
 
+var errNoSolve = errors.New("kinematics could not solve for position")
+
 // Solver defines an interface which, provided with seed inputs and a function to minimize to zero, will output all found
 // solutions to the provided channel until cancelled or otherwise completes.
 type Solver interface {
@@ -6,6 +6,7 @@ import (
 	"math"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/golang/geo/r3"
 	"go.viam.com/test"
@@ -72,6 +73,81 @@ func TestCombinedCPUs(t *testing.T) {
 	test.That(t, len(ik.(*combinedIK).solvers), test.ShouldEqual, 1)
 }
 
+func TestAnalyticIKinematics(t *testing.T) {
+	m, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	solver, err := NewAnalyticSolver(m)
+	test.That(t, err, test.ShouldBeNil)
+
+	seed := frame.FloatsToInputs([]float64{-1.2, 0.8, -1.5, 0.4, 1.1, -0.6})
+	goal, err := m.Transform(seed)
+	test.That(t, err, test.ShouldBeNil)
+	solutions := solver.Solutions(goal, seed)
+	test.That(t, len(solutions), test.ShouldBeGreaterThan, 1)
+
+	foundSeed := false
+	for _, solution := range solutions {
+		pose, err := m.Transform(frame.FloatsToInputs(solution))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatial.PoseAlmostCoincidentEps(pose, goal, 1e-3), test.ShouldBeTrue)
+		if frame.InputsL2Distance(seed, frame.FloatsToInputs(solution)) < 1e-6 {
+			foundSeed = true
+		}
+	}
+	test.That(t, foundSeed, test.ShouldBeTrue)
+
+	// a goal out of reach has no solutions
+	test.That(t, solver.Solutions(spatial.NewPoseFromPoint(r3.Vector{X: 5000}), seed), test.ShouldBeEmpty)
+
+	// arms laid out differently are not supported
+	xarm, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/example_kinematics/xarm6_kinematics_test.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = NewAnalyticSolver(xarm)
+	test.That(t, err, test.ShouldBeError, errAnalyticUnsupported)
+}
+
+func TestGradientDescentIKinematics(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	m, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	ik, err := CreateGradientDescentSolver(m.DoF(), logger, 0)
+	test.That(t, err, test.ShouldBeNil)
+
+	goal, err := m.Transform(frame.FloatsToInputs([]float64{-1.2, 0.8, -1.5, 0.4, 1.1, -0.6}))
+	test.That(t, err, test.ShouldBeNil)
+	solveFunc := NewMetricMinFunc(NewSquaredNormMetric(goal), m, logger)
+	_, err = solveTest(context.Background(), ik, solveFunc, home)
+	test.That(t, err, test.ShouldBeNil)
+}
+
+func TestCreateSolver(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	m, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+
+	ik, err := CreateSolver(SolverConfig{}, m.DoF(), logger, nCPU, defaultGoalThreshold)
+	test.That(t, err, test.ShouldBeNil)
+	_, ok := ik.(*combinedIK)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	_, err = CreateSolver(SolverConfig{Solvers: []string{"unknown"}}, m.DoF(), logger, nCPU, defaultGoalThreshold)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	ik, err = CreateSolver(
+		SolverConfig{Solvers: []string{TracIKSolverName, NloptSolverName}, Budget: 5 * time.Second},
+		m.DoF(),
+		logger,
+		nCPU,
+		defaultGoalThreshold,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	goal, err := m.Transform(frame.FloatsToInputs([]float64{-1.2, 0.8, -1.5, 0.4, 1.1, -0.6}))
+	test.That(t, err, test.ShouldBeNil)
+	solveFunc := NewMetricMinFunc(NewSquaredNormMetric(goal), m, logger)
+	_, err = solveTest(context.Background(), ik, solveFunc, home)
+	test.That(t, err, test.ShouldBeNil)
+}
+
 func solveTest(ctx context.Context, solver Solver, solveFunc func([]float64) float64, seed []float64) ([][]float64, error) {
 	solutionGen := make(chan *Solution)
 	ikErr := make(chan error)
@@ -0,0 +1,242 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan/ik"
+	"go.viam.com/rdk/referenceframe"
+)
+
+const (
+	// DefaultJointPathVelDegsPerSec and DefaultJointPathAccDegsPerSec2 limit joint paths that have no VelocityConstraint.
+	DefaultJointPathVelDegsPerSec  = 50.
+	DefaultJointPathAccDegsPerSec2 = 100.
+	// blendSamples is how many steps the curve around each blended waypoint is sampled at.
+	blendSamples = 8
+)
+
+// JointPathRequest is a request to move frames through joint-space waypoints as given, without searching for a path.
+type JointPathRequest struct {
+	Logger      logging.Logger
+	FrameSystem referenceframe.FrameSystem
+	// StartConfiguration is the configuration of the frame system at the start of the path.
+	StartConfiguration referenceframe.FrameSystemInputs
+	// Waypoints are the inputs of the moving frames at each waypoint, passed through in order. A frame absent from a
+	// waypoint keeps its inputs of the previous one.
+	Waypoints []referenceframe.FrameSystemInputs
+	// BlendRadius is how far from each intermediate waypoint, as the L2 distance of the inputs of the frame system, the
+	// path may start cutting its corner so that the frames do not stop at it. The frames stop at each waypoint if zero.
+	BlendRadius float64
+	WorldState  *referenceframe.WorldState
+	// Constraints may allow collisions between frames, and limit velocities. Without a velocity constraint, joints move
+	// at most DefaultJointPathVelDegsPerSec and DefaultJointPathAccDegsPerSec2.
+	Constraints *Constraints
+	// Options are planning options such as "collision_buffer_mm".
+	Options map[string]interface{}
+}
+
+func (req *JointPathRequest) validate() error {
+	if req.FrameSystem == nil {
+		return errors.New("JointPathRequest cannot have nil framesystem")
+	}
+	if req.StartConfiguration == nil {
+		return errors.New("joint path needs a start configuration")
+	}
+	if len(req.Waypoints) == 0 {
+		return errors.New("joint path needs at least one waypoint")
+	}
+	if req.BlendRadius < 0 {
+		return errors.New("blend radius cannot be negative")
+	}
+	for i, waypoint := range req.Waypoints {
+		for name, inputs := range waypoint {
+			frame := req.FrameSystem.Frame(name)
+			if frame == nil {
+				return referenceframe.NewFrameMissingError(name)
+			}
+			if len(inputs) != len(frame.DoF()) {
+				return fmt.Errorf("waypoint %d has %d inputs for %s, which has %d degrees of freedom",
+					i, len(inputs), name, len(frame.DoF()))
+			}
+			if len(req.StartConfiguration[name]) != len(inputs) {
+				return fmt.Errorf("start configuration has no inputs for %s", name)
+			}
+			if _, err := frame.Transform(inputs); err != nil {
+				return fmt.Errorf("waypoint %d is invalid for %s: %w", i, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// PlanJointPath returns a plan moving frames in straight lines through the joint-space waypoints of `request`, cutting
+// the corners of intermediate waypoints if it has a blend radius. The path is checked for collisions and constraint
+// violations, and time parameterized so that the frames start and end at rest, and stop at unblended waypoints.
+func PlanJointPath(ctx context.Context, request *JointPathRequest) (TimedPlan, error) {
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	// the full configuration of the frame system at the start and each waypoint
+	configurations := []referenceframe.FrameSystemInputs{request.StartConfiguration}
+	moving := referenceframe.FrameSystemInputs{}
+	for _, waypoint := range request.Waypoints {
+		configuration := copyInputs(configurations[len(configurations)-1])
+		for name, inputs := range waypoint {
+			configuration[name] = inputs
+			moving[name] = inputs
+		}
+		configurations = append(configurations, configuration)
+	}
+
+	traj, stops := blendJointPath(configurations, request.BlendRadius)
+	if err := checkJointPath(ctx, request, moving, traj); err != nil {
+		return nil, err
+	}
+
+	path := make(Path, 0, len(traj))
+	for _, step := range traj {
+		poses, err := step.ComputePoses(request.FrameSystem)
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, poses)
+	}
+
+	velConstraints := request.Constraints.GetVelocityConstraint()
+	if len(velConstraints) == 0 {
+		velConstraints = []VelocityConstraint{{
+			MaxJointVelDegsPerSec:  DefaultJointPathVelDegsPerSec,
+			MaxJointAccDegsPerSec2: DefaultJointPathAccDegsPerSec2,
+		}}
+	}
+	limits := mergeVelocityConstraints(velConstraints)
+	// the frames are at rest at each stop, so the path between each pair of stops is parameterized on its own
+	times := []time.Duration{0}
+	for i := 1; i < len(stops); i++ {
+		from, to := stops[i-1], stops[i]
+		runTimes, err := TimeParameterize(NewSimplePlan(path[from:to+1], traj[from:to+1]), limits)
+		if err != nil {
+			return nil, err
+		}
+		offset := times[len(times)-1]
+		for _, t := range runTimes[1:] {
+			times = append(times, offset+t)
+		}
+	}
+	return &timedPlan{Plan: NewSimplePlan(path, traj), times: times}, nil
+}
+
+// checkJointPath checks each segment of `traj` for collisions and constraint violations.
+func checkJointPath(
+	ctx context.Context,
+	request *JointPathRequest,
+	moving referenceframe.FrameSystemInputs,
+	traj Trajectory,
+) error {
+	pm, err := newPlanManager(request.FrameSystem, request.Logger, defaultRandomSeed)
+	if err != nil {
+		return err
+	}
+	options := request.Options
+	if options == nil {
+		options = map[string]interface{}{}
+	}
+	opt, err := pm.plannerSetupFromMoveRequest(
+		&PlanState{configuration: request.StartConfiguration},
+		&PlanState{configuration: moving},
+		request.StartConfiguration,
+		request.WorldState,
+		nil,
+		request.Constraints,
+		options,
+	)
+	if err != nil {
+		return err
+	}
+	if opt.useTPspace {
+		return errors.New("joint paths cannot be planned for PTG frames")
+	}
+	if ok, failed := opt.CheckStateFSConstraints(&ik.StateFS{Configuration: traj[0], FS: request.FrameSystem}); !ok {
+		return fmt.Errorf("start of joint path violates constraint %s", failed)
+	}
+	for i := 1; i < len(traj); i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		segment := &ik.SegmentFS{StartConfiguration: traj[i-1], EndConfiguration: traj[i], FS: request.FrameSystem}
+		if ok, _ := opt.CheckSegmentAndStateValidityFS(segment, opt.Resolution); !ok {
+			if ok, failed := opt.CheckStateFSConstraints(&ik.StateFS{Configuration: traj[i], FS: request.FrameSystem}); !ok {
+				return fmt.Errorf("step %d of joint path violates constraint %s", i, failed)
+			}
+			return fmt.Errorf("joint path between steps %d and %d collides or violates a constraint", i-1, i)
+		}
+	}
+	return nil
+}
+
+// blendJointPath returns the trajectory through `configurations`, replacing the corner at each intermediate
+// configuration with a curve starting and ending within `radius` of it, and the indices of the steps the path stops at.
+func blendJointPath(configurations []referenceframe.FrameSystemInputs, radius float64) (Trajectory, []int) {
+	traj := Trajectory{configurations[0]}
+	stops := []int{0}
+	for i := 1; i < len(configurations)-1; i++ {
+		prev, curr, next := configurations[i-1], configurations[i], configurations[i+1]
+		in, out := fsInputsDistance(prev, curr), fsInputsDistance(curr, next)
+		r := math.Min(radius, math.Min(in, out)/2)
+		if r <= 0 {
+			traj = append(traj, curr)
+			stops = append(stops, len(traj)-1)
+			continue
+		}
+		// a quadratic Bezier curve from the point `r` before the waypoint to the point `r` after it
+		start := lerpFSInputs(curr, prev, r/in)
+		end := lerpFSInputs(curr, next, r/out)
+		for s := 0; s <= blendSamples; s++ {
+			t := float64(s) / blendSamples
+			traj = append(traj, lerpFSInputs(lerpFSInputs(start, curr, t), lerpFSInputs(curr, end, t), t))
+		}
+	}
+	traj = append(traj, configurations[len(configurations)-1])
+	stops = append(stops, len(traj)-1)
+	return traj, stops
+}
+
+// fsInputsDistance returns the L2 distance between the inputs of all frames of two configurations.
+func fsInputsDistance(from, to referenceframe.FrameSystemInputs) float64 {
+	sum := 0.
+	for name, inputs := range to {
+		d := referenceframe.InputsL2Distance(from[name], inputs)
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// lerpFSInputs interpolates between the inputs of all frames of two configurations.
+func lerpFSInputs(from, to referenceframe.FrameSystemInputs, by float64) referenceframe.FrameSystemInputs {
+	interpolated := make(referenceframe.FrameSystemInputs, len(to))
+	for name, inputs := range to {
+		fromInputs := from[name]
+		step := make([]referenceframe.Input, len(inputs))
+		for i, input := range inputs {
+			step[i] = referenceframe.Input{Value: fromInputs[i].Value + (input.Value-fromInputs[i].Value)*by}
+		}
+		interpolated[name] = step
+	}
+	return interpolated
+}
+
+func copyInputs(inputs referenceframe.FrameSystemInputs) referenceframe.FrameSystemInputs {
+	copied := make(referenceframe.FrameSystemInputs, len(inputs))
+	for name, frameInputs := range inputs {
+		copied[name] = frameInputs
+	}
+	return copied
+}
@@ -0,0 +1,115 @@
+package motionplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestPlanJointPath(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	ur5e, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptyFrameSystem("test")
+	test.That(t, fs.AddFrame(ur5e, fs.World()), test.ShouldBeNil)
+
+	joints := func(base, shoulder float64) frame.FrameSystemInputs {
+		return frame.FrameSystemInputs{ur5e.Name(): frame.FloatsToInputs([]float64{base, shoulder, 0, 0, 0, 0})}
+	}
+	start := joints(0, 0)
+
+	t.Run("stops at waypoints", func(t *testing.T) {
+		plan, err := PlanJointPath(ctx, &JointPathRequest{
+			Logger:             logger,
+			FrameSystem:        fs,
+			StartConfiguration: start,
+			Waypoints:          []frame.FrameSystemInputs{joints(0.5, 0), joints(0.5, -0.5)},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, plan.Trajectory(), test.ShouldResemble, Trajectory{start, joints(0.5, 0), joints(0.5, -0.5)})
+		test.That(t, plan.Path(), test.ShouldHaveLength, 3)
+
+		// each waypoint is moved to from rest under the default limits, accelerating to and cruising at the velocity limit
+		times := plan.Times()
+		test.That(t, times, test.ShouldHaveLength, 3)
+		single := DefaultJointPathVelDegsPerSec/DefaultJointPathAccDegsPerSec2 + utils.RadToDeg(0.5)/DefaultJointPathVelDegsPerSec
+		test.That(t, times[1].Seconds(), test.ShouldAlmostEqual, single, 1e-6)
+		test.That(t, times[2].Seconds(), test.ShouldAlmostEqual, 2*single, 1e-6)
+	})
+
+	t.Run("blends waypoints", func(t *testing.T) {
+		constraints := &Constraints{}
+		constraints.AddVelocityConstraint(VelocityConstraint{MaxJointVelDegsPerSec: 20})
+		plan, err := PlanJointPath(ctx, &JointPathRequest{
+			Logger:             logger,
+			FrameSystem:        fs,
+			StartConfiguration: start,
+			Waypoints:          []frame.FrameSystemInputs{joints(0.5, 0), joints(0.5, -0.5)},
+			BlendRadius:        0.1,
+			Constraints:        constraints,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		traj := plan.Trajectory()
+		test.That(t, traj, test.ShouldHaveLength, blendSamples+3)
+		// the corner is cut
+		for _, step := range traj {
+			test.That(t, step, test.ShouldNotResemble, joints(0.5, 0))
+		}
+		test.That(t, traj[1], test.ShouldResemble, joints(0.4, 0))
+		test.That(t, traj[len(traj)-2], test.ShouldResemble, joints(0.5, -0.1))
+		times := plan.Times()
+		for i := 1; i < len(times); i++ {
+			test.That(t, times[i], test.ShouldBeGreaterThan, times[i-1])
+		}
+		// without an acceleration limit the path is traversed at the velocity limit
+		test.That(t, times[1].Seconds(), test.ShouldAlmostEqual, utils.RadToDeg(0.4)/20, 1e-3)
+	})
+
+	t.Run("collisions", func(t *testing.T) {
+		// an obstacle where the arm passes half way through its move
+		mid, err := frame.FrameSystemInputs(joints(0.75, 0)).ComputePoses(fs)
+		test.That(t, err, test.ShouldBeNil)
+		box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(mid[ur5e.Name()].Pose().Point()), r3.Vector{X: 100, Y: 100, Z: 100}, "box")
+		test.That(t, err, test.ShouldBeNil)
+		worldState, err := frame.NewWorldState(
+			[]*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, []spatialmath.Geometry{box})}, nil,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = PlanJointPath(ctx, &JointPathRequest{
+			Logger:             logger,
+			FrameSystem:        fs,
+			StartConfiguration: start,
+			Waypoints:          []frame.FrameSystemInputs{joints(1.5, 0)},
+			WorldState:         worldState,
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "collides")
+	})
+
+	t.Run("invalid waypoints", func(t *testing.T) {
+		_, err := PlanJointPath(ctx, &JointPathRequest{
+			Logger:             logger,
+			FrameSystem:        fs,
+			StartConfiguration: start,
+			Waypoints:          []frame.FrameSystemInputs{{ur5e.Name(): frame.FloatsToInputs([]float64{0})}},
+		})
+		test.That(t, err, test.ShouldBeError, "waypoint 0 has 1 inputs for UR5e, which has 6 degrees of freedom")
+
+		_, err = PlanJointPath(ctx, &JointPathRequest{
+			Logger:             logger,
+			FrameSystem:        fs,
+			StartConfiguration: start,
+			Waypoints:          []frame.FrameSystemInputs{joints(100, 0)},
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "waypoint 0 is invalid")
+	})
+}
@@ -36,7 +36,7 @@ type motionPlanner interface {
 	smoothPath(context.Context, []node) []node
 	checkPath(referenceframe.FrameSystemInputs, referenceframe.FrameSystemInputs) bool
 	checkInputs(referenceframe.FrameSystemInputs) bool
-	getSolutions(context.Context, referenceframe.FrameSystemInputs, ik.StateFSMetric) ([]node, error)
+	getSolutions(context.Context, referenceframe.FrameSystemInputs, ik.StateFSMetric, referenceframe.FrameSystemPoses) ([]node, error)
 	opt() *plannerOptions
 	sample(node, int) (node, error)
 }
@@ -237,6 +237,9 @@ func Replan(ctx context.Context, request *PlanRequest, currentPlan Plan, replanC
 		}
 	}
 
+	if velConstraints := request.Constraints.GetVelocityConstraint(); len(velConstraints) > 0 {
+		return NewTimedPlan(newPlan, velConstraints...)
+	}
 	return newPlan, nil
 }
 
@@ -244,6 +247,7 @@ type planner struct {
 	fs       referenceframe.FrameSystem
 	lfs      *linearizedFrameSystem
 	solver   ik.Solver
+	analytic map[string]*ik.AnalyticSolver
 	logger   logging.Logger
 	randseed *rand.Rand
 	start    time.Time
@@ -259,12 +263,35 @@ func newPlanner(fs referenceframe.FrameSystem, seed *rand.Rand, logger logging.L
 		opt = newBasicPlannerOptions()
 	}
 
-	solver, err := ik.CreateCombinedIKSolver(lfs.dof, logger, opt.NumThreads, opt.GoalThreshold)
+	// the analytic solver only solves for the frames that support it, leaving the rest of the frame system to the others
+	var numericSolvers []string
+	analytic := map[string]*ik.AnalyticSolver{}
+	for _, name := range opt.IKSolvers {
+		if name != ik.AnalyticSolverName {
+			numericSolvers = append(numericSolvers, name)
+			continue
+		}
+		for _, frameName := range fs.FrameNames() {
+			if model, ok := fs.Frame(frameName).(referenceframe.Model); ok {
+				if solver, err := ik.NewAnalyticSolver(model); err == nil {
+					analytic[frameName] = solver
+				}
+			}
+		}
+	}
+	solver, err := ik.CreateSolver(
+		ik.SolverConfig{Solvers: numericSolvers, Budget: time.Duration(opt.IKBudget * float64(time.Second))},
+		lfs.dof,
+		logger,
+		opt.NumThreads,
+		opt.GoalThreshold,
+	)
 	if err != nil {
 		return nil, err
 	}
 	mp := &planner{
 		solver:   solver,
+		analytic: analytic,
 		fs:       fs,
 		lfs:      lfs,
 		logger:   logger,
@@ -380,7 +407,13 @@ func (mp *planner) smoothPath(ctx context.Context, path []node) []node {
 // getSolutions will initiate an IK solver for the given position and seed, collect solutions, and score them by constraints.
 // If maxSolutions is positive, once that many solutions have been collected, the solver will terminate and return that many solutions.
 // If minScore is positive, if a solution scoring below that amount is found, the solver will terminate and return that one solution.
-func (mp *planner) getSolutions(ctx context.Context, seed referenceframe.FrameSystemInputs, metric ik.StateFSMetric) ([]node, error) {
+// If every goal is for a frame with an analytic solver, its closed form solutions are used instead of running the IK solver.
+func (mp *planner) getSolutions(
+	ctx context.Context,
+	seed referenceframe.FrameSystemInputs,
+	metric ik.StateFSMetric,
+	goals referenceframe.FrameSystemPoses,
+) ([]node, error) {
 	// Linter doesn't properly handle loop labels
 	nSolutions := mp.planOpts.MaxSolutions
 	if nSolutions == 0 {
@@ -417,6 +450,9 @@ func (mp *planner) getSolutions(ctx context.Context, seed referenceframe.FrameSy
 	utils.PanicCapturingGo(func() {
 		defer close(ikErr)
 		defer activeSolvers.Done()
+		if mp.sendAnalyticSolutions(ctxWithCancel, solutionGen, seed, goals) {
+			return
+		}
 		ikErr <- mp.solver.Solve(ctxWithCancel, solutionGen, linearSeed, minFunc, mp.randseed.Int())
 	})
 
@@ -541,6 +577,64 @@ IK:
 	return orderedSolutions, nil
 }
 
+// sendAnalyticSolutions sends every closed form solution for the goals to c, keeping the rest of the frame system at the seed,
+// and returns whether it sent any. Nothing is sent unless every goal is for a frame with an analytic solver.
+func (mp *planner) sendAnalyticSolutions(
+	ctx context.Context,
+	c chan<- *ik.Solution,
+	seed referenceframe.FrameSystemInputs,
+	goals referenceframe.FrameSystemPoses,
+) bool {
+	if len(mp.analytic) == 0 || len(goals) == 0 {
+		return false
+	}
+	configurations := []referenceframe.FrameSystemInputs{seed}
+	for frameName, goal := range goals {
+		solver, ok := mp.analytic[frameName]
+		if !ok {
+			return false
+		}
+		parent, err := mp.fs.Parent(mp.fs.Frame(frameName))
+		if err != nil {
+			return false
+		}
+		// analytic solutions are relative to the origin of the model, which is placed by its parent
+		tf, err := mp.fs.Transform(seed, goal, parent.Name())
+		if err != nil {
+			return false
+		}
+		solutions := solver.Solutions(tf.(*referenceframe.PoseInFrame).Pose(), seed[frameName])
+		if len(solutions) == 0 {
+			return false
+		}
+		next := make([]referenceframe.FrameSystemInputs, 0, len(configurations)*len(solutions))
+		for _, configuration := range configurations {
+			for _, solution := range solutions {
+				step := referenceframe.FrameSystemInputs{}
+				for name, inputs := range configuration {
+					step[name] = inputs
+				}
+				step[frameName] = referenceframe.FloatsToInputs(solution)
+				next = append(next, step)
+			}
+		}
+		configurations = next
+	}
+
+	for _, configuration := range configurations {
+		linear, err := mp.lfs.mapToSlice(configuration)
+		if err != nil {
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			return true
+		case c <- &ik.Solution{Configuration: linear, Exact: true}:
+		}
+	}
+	return true
+}
+
 // linearize the goal metric for use with solvers.
 // Since our solvers operate on arrays of floats, there needs to be a way to map bidirectionally between the framesystem configuration
 // of FrameSystemInputs and the []float64 that the solver expects. This is that mapping.
@@ -162,7 +162,7 @@ func generateNodeListForPlanState(
 		// If we have goal state poses, add them to the goal state configurations
 		goalMetric := mp.opt().getGoalMetric(state.poses)
 		// get many potential end goals from IK solver
-		solutions, err := mp.getSolutions(ctx, ikSeed, goalMetric)
+		solutions, err := mp.getSolutions(ctx, ikSeed, goalMetric, state.poses)
 		if err != nil {
 			return nil, err
 		}
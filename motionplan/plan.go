@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/golang/geo/r3"
 	geo "github.com/kellydunn/golang-geo"
@@ -37,6 +38,17 @@ func RemainingPlan(plan Plan, waypointIndex int) (Plan, error) {
 	if path != nil && waypointIndex > len(path) {
 		return nil, fmt.Errorf("could not access path index %d, must be less than %d", waypointIndex, len(plan.Path()))
 	}
+	if timed, ok := plan.(*timedPlan); ok {
+		remaining, err := RemainingPlan(timed.Plan, waypointIndex)
+		if err != nil {
+			return nil, err
+		}
+		times := make([]time.Duration, 0, len(timed.times)-waypointIndex)
+		for _, t := range timed.times[waypointIndex:] {
+			times = append(times, t-timed.times[waypointIndex])
+		}
+		return &timedPlan{Plan: remaining, times: times}, nil
+	}
 	simplePlan := NewSimplePlan(path[waypointIndex:], traj[waypointIndex:])
 	if rrt, ok := plan.(*rrtPlan); ok {
 		return &rrtPlan{SimplePlan: *simplePlan, nodes: rrt.nodes[waypointIndex:]}, nil
@@ -47,6 +59,9 @@ func RemainingPlan(plan Plan, waypointIndex int) (Plan, error) {
 // OffsetPlan returns a new Plan that is equivalent to the given Plan if its Path was offset by the given Pose.
 // Does not modify Trajectory.
 func OffsetPlan(plan Plan, offset spatialmath.Pose) Plan {
+	if timed, ok := plan.(*timedPlan); ok {
+		return &timedPlan{Plan: OffsetPlan(timed.Plan, offset), times: timed.times}
+	}
 	path := plan.Path()
 	if path == nil {
 		return NewSimplePlan(nil, plan.Trajectory())
@@ -616,6 +616,7 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 	if err != nil {
 		return nil, err
 	}
+	opt.setCheckConcurrency(opt.NumThreads, opt.StateCheckCacheSize)
 
 	alg, ok := planningOpts["planning_alg"]
 	if ok {
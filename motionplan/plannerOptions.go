@@ -72,6 +72,9 @@ const (
 
 var defaultNumThreads = runtime.NumCPU() / 2
 
+// Number of results of collision and other FS state constraint checks to keep for reuse while planning.
+const defaultStateCheckCacheSize = 50000
+
 // TODO: Make this an enum
 // the set of supported motion profiles.
 const (
@@ -114,6 +117,7 @@ func newBasicPlannerOptions() *plannerOptions {
 	opt.SmoothIter = defaultSmoothIter
 
 	opt.NumThreads = defaultNumThreads
+	opt.StateCheckCacheSize = defaultStateCheckCacheSize
 
 	return opt
 }
@@ -151,12 +155,22 @@ type plannerOptions struct {
 	// Number of times to try to smooth the path
 	SmoothIter int `json:"smooth_iter"`
 
-	// Number of cpu cores to use
+	// Number of cpu cores to use, for IK, nearest neighbor searches and checking constraints along segments
 	NumThreads int `json:"num_threads"`
 
+	// Number of results of collision and other FS state constraint checks shared by the workers of planners.
+	StateCheckCacheSize int `json:"state_check_cache_size"`
+
 	// How close to get to the goal
 	GoalThreshold float64 `json:"goal_threshold"`
 
+	// Names of the IK solvers to try in order, falling back to the next when one finds no solutions. One of "nlopt", "trac_ik"
+	// or "analytic"; analytic solutions are used for the frames that support them, with the others solving the rest.
+	IKSolvers []string `json:"ik_solvers"`
+
+	// Number of seconds each IK solver may run per IK call before falling back to the next. If <= 0, solvers run until done.
+	IKBudget float64 `json:"ik_budget"`
+
 	// Number of planner iterations before giving up.
 	PlanIter int `json:"plan_iter"`
 
@@ -0,0 +1,154 @@
+package motionplan
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"go.viam.com/rdk/utils"
+)
+
+// TimedPlan is a Plan whose Trajectory was time parameterized to respect a VelocityConstraint.
+type TimedPlan interface {
+	Plan
+	// Times returns when each step of the Trajectory should be reached, relative to the start of the plan.
+	Times() []time.Duration
+}
+
+type timedPlan struct {
+	Plan
+	times []time.Duration
+}
+
+func (plan *timedPlan) Times() []time.Duration {
+	return plan.times
+}
+
+// NewTimedPlan time parameterizes `plan` to respect the most restrictive limit of each of `constraints`.
+func NewTimedPlan(plan Plan, constraints ...VelocityConstraint) (TimedPlan, error) {
+	times, err := TimeParameterize(plan, mergeVelocityConstraints(constraints))
+	if err != nil {
+		return nil, err
+	}
+	return &timedPlan{Plan: plan, times: times}, nil
+}
+
+// mergeVelocityConstraints returns the most restrictive limits of `constraints`.
+func mergeVelocityConstraints(constraints []VelocityConstraint) VelocityConstraint {
+	lowest := func(a, b float64) float64 {
+		if a <= 0 || (b > 0 && b < a) {
+			return b
+		}
+		return a
+	}
+	var merged VelocityConstraint
+	for _, c := range constraints {
+		merged.MaxJointVelDegsPerSec = lowest(merged.MaxJointVelDegsPerSec, c.MaxJointVelDegsPerSec)
+		merged.MaxJointAccDegsPerSec2 = lowest(merged.MaxJointAccDegsPerSec2, c.MaxJointAccDegsPerSec2)
+		merged.MaxLinearVelMmPerSec = lowest(merged.MaxLinearVelMmPerSec, c.MaxLinearVelMmPerSec)
+		merged.MaxLinearAccMmPerSec2 = lowest(merged.MaxLinearAccMmPerSec2, c.MaxLinearAccMmPerSec2)
+	}
+	return merged
+}
+
+// TimeParameterize returns when each step of the plan's Trajectory should be reached so that no joint or frame moves or
+// accelerates faster than `limits` allows. The plan starts and ends at rest.
+//
+// Each segment between steps is traversed by a path parameter going from 0 to 1. A segment's limits bound the rate and
+// acceleration of its parameter by the segment's largest joint and frame displacements, and a forward and backward pass
+// finds the fastest parameter rates at each step that can be reached and stopped from within the acceleration limits.
+func TimeParameterize(plan Plan, limits VelocityConstraint) ([]time.Duration, error) {
+	if limits.MaxJointVelDegsPerSec < 0 || limits.MaxJointAccDegsPerSec2 < 0 ||
+		limits.MaxLinearVelMmPerSec < 0 || limits.MaxLinearAccMmPerSec2 < 0 {
+		return nil, errors.New("velocity constraint limits cannot be negative")
+	}
+	traj := plan.Trajectory()
+	if len(traj) == 0 {
+		return []time.Duration{}, nil
+	}
+	path := plan.Path()
+
+	// the largest rate and acceleration of the path parameter in each segment
+	numSegments := len(traj) - 1
+	maxRate := make([]float64, numSegments)
+	maxAcc := make([]float64, numSegments)
+	for i := 0; i < numSegments; i++ {
+		jointDist := 0.
+		for name, inputs := range traj[i+1] {
+			prior := traj[i][name]
+			if len(prior) != len(inputs) {
+				continue
+			}
+			for j, input := range inputs {
+				jointDist = math.Max(jointDist, utils.RadToDeg(math.Abs(input.Value-prior[j].Value)))
+			}
+		}
+		linearDist := 0.
+		if len(path) == len(traj) {
+			for name, pose := range path[i+1] {
+				if prior, ok := path[i][name]; ok {
+					linearDist = math.Max(linearDist, pose.Pose().Point().Distance(prior.Pose().Point()))
+				}
+			}
+		}
+		maxRate[i] = math.Min(
+			parameterLimit(limits.MaxJointVelDegsPerSec, jointDist),
+			parameterLimit(limits.MaxLinearVelMmPerSec, linearDist),
+		)
+		maxAcc[i] = math.Min(
+			parameterLimit(limits.MaxJointAccDegsPerSec2, jointDist),
+			parameterLimit(limits.MaxLinearAccMmPerSec2, linearDist),
+		)
+		if math.IsInf(maxRate[i], 1) && math.IsInf(maxAcc[i], 1) && (jointDist > 0 || linearDist > 0) {
+			return nil, errors.New("velocity constraint must limit the velocity or acceleration of the moving joints or frames")
+		}
+	}
+
+	// the rate of the path parameter at each step, starting and ending at rest
+	rates := make([]float64, len(traj))
+	for i := 1; i < numSegments; i++ {
+		rates[i] = math.Min(maxRate[i-1], maxRate[i])
+	}
+	for i := 1; i < len(traj); i++ {
+		rates[i] = math.Min(rates[i], math.Sqrt(rates[i-1]*rates[i-1]+2*maxAcc[i-1]))
+	}
+	for i := numSegments - 1; i >= 0; i-- {
+		rates[i] = math.Min(rates[i], math.Sqrt(rates[i+1]*rates[i+1]+2*maxAcc[i]))
+	}
+
+	times := make([]time.Duration, len(traj))
+	elapsed := 0.
+	for i := 0; i < numSegments; i++ {
+		elapsed += segmentDuration(rates[i], rates[i+1], maxRate[i], maxAcc[i])
+		times[i+1] = time.Duration(math.Round(elapsed * float64(time.Second)))
+	}
+	return times, nil
+}
+
+// parameterLimit returns the limit of the path parameter of a segment displacing `dist` under a `limit`, which is
+// unbounded if zero.
+func parameterLimit(limit, dist float64) float64 {
+	if limit == 0 || dist == 0 {
+		return math.Inf(1)
+	}
+	return limit / dist
+}
+
+// segmentDuration returns the seconds to traverse a segment of unit length starting at rate `start` and ending at rate
+// `end`, accelerating at `acc` up to at most rate `cruise`.
+func segmentDuration(start, end, cruise, acc float64) float64 {
+	if math.IsInf(acc, 1) {
+		if math.IsInf(cruise, 1) {
+			return 0
+		}
+		return 1 / cruise
+	}
+	// the peak rate reachable accelerating from start and decelerating to end within the segment
+	peak := math.Sqrt((2*acc + start*start + end*end) / 2)
+	if peak <= cruise {
+		return (2*peak - start - end) / acc
+	}
+	accelDist := (cruise*cruise - start*start) / (2 * acc)
+	decelDist := (cruise*cruise - end*end) / (2 * acc)
+	return (cruise-start)/acc + (cruise-end)/acc + (1-accelDist-decelDist)/cruise
+}
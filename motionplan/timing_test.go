@@ -0,0 +1,91 @@
+package motionplan
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func jointPlan(positionsDegs ...float64) Plan {
+	traj := Trajectory{}
+	path := Path{}
+	for _, deg := range positionsDegs {
+		traj = append(traj, referenceframe.FrameSystemInputs{"arm": referenceframe.FloatsToInputs([]float64{utils.DegToRad(deg)})})
+		path = append(path, referenceframe.FrameSystemPoses{
+			"arm": referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: deg})),
+		})
+	}
+	return NewSimplePlan(path, traj)
+}
+
+func TestTimeParameterize(t *testing.T) {
+	t.Run("velocity limit", func(t *testing.T) {
+		times, err := TimeParameterize(jointPlan(0, 10, 30), VelocityConstraint{MaxJointVelDegsPerSec: 10})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, times, test.ShouldResemble, []time.Duration{0, time.Second, 3 * time.Second})
+	})
+
+	t.Run("acceleration limit", func(t *testing.T) {
+		// starting and ending at rest, the fastest move accelerates half way and decelerates the rest
+		times, err := TimeParameterize(jointPlan(0, 20), VelocityConstraint{MaxJointAccDegsPerSec2: 5})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, times[1].Seconds(), test.ShouldAlmostEqual, 4)
+
+		// a velocity limit below the peak velocity adds a cruise
+		times, err = TimeParameterize(jointPlan(0, 20), VelocityConstraint{MaxJointVelDegsPerSec: 5, MaxJointAccDegsPerSec2: 5})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, times[1].Seconds(), test.ShouldAlmostEqual, 5)
+
+		// consecutive segments do not stop at each step
+		stepped, err := TimeParameterize(jointPlan(0, 10, 20), VelocityConstraint{MaxJointAccDegsPerSec2: 5})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, stepped[2].Seconds(), test.ShouldAlmostEqual, 4)
+		test.That(t, stepped[1].Seconds(), test.ShouldAlmostEqual, 2)
+	})
+
+	t.Run("linear limit", func(t *testing.T) {
+		times, err := TimeParameterize(jointPlan(0, 50), VelocityConstraint{MaxLinearVelMmPerSec: 100})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, times[1], test.ShouldEqual, 500*time.Millisecond)
+
+		// the slower of the joint and linear limits wins
+		times, err = TimeParameterize(jointPlan(0, 50), VelocityConstraint{MaxLinearVelMmPerSec: 100, MaxJointVelDegsPerSec: 25})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, times[1], test.ShouldEqual, 2*time.Second)
+	})
+
+	t.Run("invalid limits", func(t *testing.T) {
+		_, err := TimeParameterize(jointPlan(0, 10), VelocityConstraint{MaxJointVelDegsPerSec: -1})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = TimeParameterize(jointPlan(0, 10), VelocityConstraint{})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestTimedPlan(t *testing.T) {
+	plan, err := NewTimedPlan(jointPlan(0, 10, 30, 40),
+		VelocityConstraint{MaxJointVelDegsPerSec: 20},
+		VelocityConstraint{MaxJointVelDegsPerSec: 10, MaxLinearVelMmPerSec: 1000},
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, plan.Times(), test.ShouldResemble, []time.Duration{0, time.Second, 3 * time.Second, 4 * time.Second})
+
+	remaining, err := RemainingPlan(plan, 1)
+	test.That(t, err, test.ShouldBeNil)
+	timed, ok := remaining.(TimedPlan)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, timed.Times(), test.ShouldResemble, []time.Duration{0, 2 * time.Second, 3 * time.Second})
+	test.That(t, len(timed.Trajectory()), test.ShouldEqual, 3)
+
+	offset, ok := OffsetPlan(plan, spatialmath.NewPoseFromPoint(r3.Vector{Y: 1})).(TimedPlan)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, offset.Times(), test.ShouldResemble, plan.Times())
+	test.That(t, math.Abs(offset.Path()[0]["arm"].Pose().Point().Y-1), test.ShouldBeLessThan, 1e-9)
+}
@@ -17,6 +17,10 @@ type opidKeyType string
 
 const opidKey = opidKeyType("opid")
 
+// LongRunningThreshold is how long an operation runs before it is counted as long running in the
+// manager's stats, and logged with its duration once it finishes.
+var LongRunningThreshold = 10 * time.Second
+
 var methodPrefixesToFilter = [...]string{
 	"/proto.rpc.webrtc.v1.SignalingService",
 	"/viam.robot.v1.RobotService/StreamStatus",
@@ -66,8 +70,14 @@ func (o *Operation) CancelOtherWithLabel(label string) {
 	o.labels = append(o.labels, label)
 }
 
-func (o *Operation) cleanup() {
+func (o *Operation) cleanup(ctx context.Context) {
 	o.myManager.remove(o.ID)
+	if dur := time.Since(o.Started); dur >= LongRunningThreshold {
+		o.myManager.lock.Lock()
+		o.myManager.stats.LongRunningFinished++
+		o.myManager.lock.Unlock()
+		o.myManager.logger.CInfow(ctx, "long running operation finished", "method", o.Method, "duration", dur.String())
+	}
 }
 
 // NewManager creates a new manager for holding Operations.
@@ -81,6 +91,38 @@ type Manager struct {
 	ops    map[string]*Operation
 	lock   sync.Mutex
 	logger logging.Logger
+	stats  Stats
+}
+
+// Stats are the operation metrics recorded by FTDC.
+type Stats struct {
+	// Running is the number of running operations.
+	Running int64
+	// LongRunning is the number of running operations that have run for at least
+	// LongRunningThreshold.
+	LongRunning int64
+	// LongRunningFinished counts the operations that ran for at least LongRunningThreshold.
+	LongRunningFinished int64
+	// LongestRunningSecs is how long the oldest running operation has been running for.
+	LongestRunningSecs float64
+}
+
+// Stats returns the Stats of the running operations. It implements the FTDC Statser interface.
+func (m *Manager) Stats() any {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stats := m.stats
+	stats.Running = int64(len(m.ops))
+	for _, op := range m.ops {
+		secs := time.Since(op.Started).Seconds()
+		if secs >= LongRunningThreshold.Seconds() {
+			stats.LongRunning++
+		}
+		if secs > stats.LongestRunningSecs {
+			stats.LongestRunningSecs = secs
+		}
+	}
+	return stats
 }
 
 func (m *Manager) remove(id uuid.UUID) {
@@ -153,6 +195,7 @@ func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string,
 			method,
 		)
 		ctx = context.WithValue(ctx, opidKey, o)
+		ctx = logging.ContextWithOperationID(ctx, o.ID.String())
 		return ctx, func() {}
 	}
 
@@ -167,10 +210,12 @@ func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string,
 		op.SessionID = sess.ID()
 	}
 	ctx = context.WithValue(ctx, opidKey, op)
+	// Everything logged on behalf of the operation includes its id.
+	ctx = logging.ContextWithOperationID(ctx, id.String())
 	ctx, op.cancel = context.WithCancel(ctx)
 	m.add(op)
 
-	return ctx, func() { op.cleanup() }
+	return ctx, func() { op.cleanup(ctx) }
 }
 
 // Get returns the current Operation. This can be nil.
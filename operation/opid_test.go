@@ -115,3 +115,33 @@ func TestCreateDuplicate(t *testing.T) {
 	cleanup()
 	test.That(t, op3Ctx.Err(), test.ShouldBeError, context.Canceled)
 }
+
+func TestOperationIDInContext(t *testing.T) {
+	h := NewManager(logging.NewTestLogger(t))
+	ctx, cleanup := h.Create(context.Background(), "a", nil)
+	defer cleanup()
+	test.That(t, logging.OperationIDFromContext(ctx), test.ShouldEqual, Get(ctx).ID.String())
+
+	// an operation with the same id keeps the original operation's id
+	ctx2, cleanup2 := h.createWithID(context.Background(), Get(ctx).ID, "b", nil)
+	defer cleanup2()
+	test.That(t, logging.OperationIDFromContext(ctx2), test.ShouldEqual, Get(ctx).ID.String())
+}
+
+func TestStats(t *testing.T) {
+	h := NewManager(logging.NewTestLogger(t))
+	test.That(t, h.Stats(), test.ShouldResemble, Stats{})
+
+	ctx, cleanup := h.Create(context.Background(), "a", nil)
+	stats := h.Stats().(Stats)
+	test.That(t, stats.Running, test.ShouldEqual, 1)
+	test.That(t, stats.LongRunning, test.ShouldEqual, 0)
+
+	Get(ctx).Started = Get(ctx).Started.Add(-2 * LongRunningThreshold)
+	stats = h.Stats().(Stats)
+	test.That(t, stats.LongRunning, test.ShouldEqual, 1)
+	test.That(t, stats.LongestRunningSecs, test.ShouldBeGreaterThanOrEqualTo, 2*LongRunningThreshold.Seconds())
+
+	cleanup()
+	test.That(t, h.Stats(), test.ShouldResemble, Stats{LongRunningFinished: 1})
+}
@@ -0,0 +1,190 @@
+package pointcloud
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// FilterOptions reduce the size of a point cloud, typically before sending it over the network.
+// Zero values disable the corresponding filter. Filters are applied in the order range clipping,
+// voxel downsampling and decimation.
+type FilterOptions struct {
+	// MinRangeMM and MaxRangeMM drop points whose distance from the origin is outside of the range.
+	MinRangeMM float64
+	MaxRangeMM float64
+	// VoxelSizeMM replaces the points of each cubic voxel of this size by their centroid.
+	VoxelSizeMM float64
+	// MaxPoints keeps an evenly spread subset of at most this many points.
+	MaxPoints int
+}
+
+// IsZero returns true if the options do not filter any points.
+func (opts FilterOptions) IsZero() bool {
+	return opts == FilterOptions{}
+}
+
+// Validate ensures the options are consistent.
+func (opts FilterOptions) Validate() error {
+	if opts.MinRangeMM < 0 || opts.MaxRangeMM < 0 || opts.VoxelSizeMM < 0 || opts.MaxPoints < 0 {
+		return errors.New("point cloud filter options cannot be negative")
+	}
+	if opts.MaxRangeMM != 0 && opts.MaxRangeMM <= opts.MinRangeMM {
+		return errors.Errorf("max range %.2f must be greater than min range %.2f", opts.MaxRangeMM, opts.MinRangeMM)
+	}
+	return nil
+}
+
+// Filter returns a new point cloud with the filters of opts applied. The input is returned as is
+// if no filters are set.
+func Filter(cloud PointCloud, opts FilterOptions) (PointCloud, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	var err error
+	if opts.MinRangeMM > 0 || opts.MaxRangeMM > 0 {
+		if cloud, err = ClipRange(cloud, opts.MinRangeMM, opts.MaxRangeMM); err != nil {
+			return nil, err
+		}
+	}
+	if opts.VoxelSizeMM > 0 {
+		if cloud, err = VoxelDownsample(cloud, opts.VoxelSizeMM); err != nil {
+			return nil, err
+		}
+	}
+	if opts.MaxPoints > 0 {
+		if cloud, err = Decimate(cloud, opts.MaxPoints); err != nil {
+			return nil, err
+		}
+	}
+	return cloud, nil
+}
+
+// ClipRange returns the points whose distance from the origin is within [minRange, maxRange]. A
+// maxRange of zero means no upper bound.
+func ClipRange(cloud PointCloud, minRange, maxRange float64) (PointCloud, error) {
+	clipped := New()
+	var err error
+	cloud.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		dist := p.Norm()
+		if dist < minRange || (maxRange > 0 && dist > maxRange) {
+			return true
+		}
+		err = clipped.Set(p, d)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clipped, nil
+}
+
+type voxelAccumulator struct {
+	sum        r3.Vector
+	count      int
+	r, g, b    int
+	colorCount int
+	intensity  int
+	value      int
+	hasValue   bool
+}
+
+// VoxelDownsample partitions space into cubes of side voxelSize and replaces the points within
+// each by their centroid. Colors and intensities are averaged, and the value of the first point of
+// a voxel is kept.
+func VoxelDownsample(cloud PointCloud, voxelSize float64) (PointCloud, error) {
+	if voxelSize <= 0 {
+		return nil, errors.Errorf("voxel size must be positive, got %.2f", voxelSize)
+	}
+	voxels := map[[3]int64]*voxelAccumulator{}
+	order := make([][3]int64, 0)
+	cloud.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		key := [3]int64{
+			int64(math.Floor(p.X / voxelSize)),
+			int64(math.Floor(p.Y / voxelSize)),
+			int64(math.Floor(p.Z / voxelSize)),
+		}
+		acc, ok := voxels[key]
+		if !ok {
+			acc = &voxelAccumulator{}
+			voxels[key] = acc
+			order = append(order, key)
+		}
+		acc.sum = acc.sum.Add(p)
+		acc.count++
+		if d != nil {
+			if d.HasColor() {
+				r, g, b := d.RGB255()
+				acc.r += int(r)
+				acc.g += int(g)
+				acc.b += int(b)
+				acc.colorCount++
+			}
+			acc.intensity += int(d.Intensity())
+			if d.HasValue() && !acc.hasValue {
+				acc.value = d.Value()
+				acc.hasValue = true
+			}
+		}
+		return true
+	})
+
+	downsampled := NewWithPrealloc(len(order))
+	for _, key := range order {
+		acc := voxels[key]
+		d := NewBasicData()
+		if acc.colorCount > 0 {
+			d.SetColor(color.NRGBA{
+				uint8(acc.r / acc.colorCount),
+				uint8(acc.g / acc.colorCount),
+				uint8(acc.b / acc.colorCount),
+				255,
+			})
+		}
+		if acc.hasValue {
+			d.SetValue(acc.value)
+		}
+		if acc.intensity > 0 {
+			d.SetIntensity(uint16(acc.intensity / acc.count))
+		}
+		if err := downsampled.Set(acc.sum.Mul(1/float64(acc.count)), d); err != nil {
+			return nil, err
+		}
+	}
+	return downsampled, nil
+}
+
+// Decimate keeps an evenly spread subset of at most maxPoints points of the cloud, in iteration
+// order.
+func Decimate(cloud PointCloud, maxPoints int) (PointCloud, error) {
+	if maxPoints <= 0 {
+		return nil, errors.Errorf("max points must be positive, got %d", maxPoints)
+	}
+	size := cloud.Size()
+	if size <= maxPoints {
+		return cloud, nil
+	}
+	step := float64(size) / float64(maxPoints)
+	decimated := NewWithPrealloc(maxPoints)
+	var (
+		idx  int
+		next float64
+		err  error
+	)
+	cloud.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		if float64(idx) >= next {
+			if err = decimated.Set(p, d); err != nil {
+				return false
+			}
+			next += step
+		}
+		idx++
+		return decimated.Size() < maxPoints
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decimated, nil
+}
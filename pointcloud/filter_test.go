@@ -0,0 +1,82 @@
+package pointcloud
+
+import (
+	"image/color"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestClipRange(t *testing.T) {
+	cloud := New()
+	for _, z := range []float64{1, 5, 10, 20} {
+		test.That(t, cloud.Set(NewVector(0, 0, z), nil), test.ShouldBeNil)
+	}
+	clipped, err := ClipRange(cloud, 2, 10)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, clipped.Size(), test.ShouldEqual, 2)
+	_, got := clipped.At(0, 0, 5)
+	test.That(t, got, test.ShouldBeTrue)
+	_, got = clipped.At(0, 0, 10)
+	test.That(t, got, test.ShouldBeTrue)
+
+	// no upper bound
+	clipped, err = ClipRange(cloud, 2, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, clipped.Size(), test.ShouldEqual, 3)
+}
+
+func TestVoxelDownsample(t *testing.T) {
+	cloud := New()
+	test.That(t, cloud.Set(NewVector(1, 1, 1), NewColoredData(color.NRGBA{100, 0, 0, 255})), test.ShouldBeNil)
+	test.That(t, cloud.Set(NewVector(3, 3, 3), NewColoredData(color.NRGBA{200, 0, 0, 255})), test.ShouldBeNil)
+	test.That(t, cloud.Set(NewVector(15, 1, 1), NewValueData(7)), test.ShouldBeNil)
+
+	downsampled, err := VoxelDownsample(cloud, 10)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, downsampled.Size(), test.ShouldEqual, 2)
+	d, got := downsampled.At(2, 2, 2)
+	test.That(t, got, test.ShouldBeTrue)
+	r, _, _ := d.RGB255()
+	test.That(t, r, test.ShouldEqual, 150)
+	d, got = downsampled.At(15, 1, 1)
+	test.That(t, got, test.ShouldBeTrue)
+	test.That(t, d.Value(), test.ShouldEqual, 7)
+
+	_, err = VoxelDownsample(cloud, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestDecimate(t *testing.T) {
+	cloud := New()
+	for i := 0; i < 100; i++ {
+		test.That(t, cloud.Set(NewVector(float64(i), 0, 0), nil), test.ShouldBeNil)
+	}
+	decimated, err := Decimate(cloud, 10)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, decimated.Size(), test.ShouldEqual, 10)
+
+	same, err := Decimate(cloud, 1000)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, same, test.ShouldEqual, cloud)
+}
+
+func TestFilter(t *testing.T) {
+	cloud := New()
+	for i := 0; i < 100; i++ {
+		test.That(t, cloud.Set(NewVector(float64(i), 0, 0), nil), test.ShouldBeNil)
+	}
+	filtered, err := Filter(cloud, FilterOptions{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filtered, test.ShouldEqual, cloud)
+
+	// 50..99 are kept, merged into 5 voxels, of which 3 are kept
+	filtered, err = Filter(cloud, FilterOptions{MinRangeMM: 50, VoxelSizeMM: 10, MaxPoints: 3})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filtered.Size(), test.ShouldEqual, 3)
+
+	_, err = Filter(cloud, FilterOptions{MinRangeMM: 50, MaxRangeMM: 10})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = Filter(cloud, FilterOptions{VoxelSizeMM: -1})
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -5,13 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"image/color"
+	"runtime"
 	"sync"
-	"sync/atomic"
-	"time"
 
 	"github.com/golang/geo/r3"
 	"github.com/lucasb-eyer/go-colorful"
 	"go.opencensus.io/trace"
+	"go.uber.org/multierr"
 	"go.viam.com/utils"
 
 	"go.viam.com/rdk/logging"
@@ -36,99 +36,101 @@ func MergePointClouds(ctx context.Context, cloudFuncs []CloudAndOffsetFunc, logg
 	if len(cloudFuncs) == 0 {
 		return nil, errors.New("no point clouds to merge")
 	}
-	finalPoints := make(chan []PointAndData, 50)
-	activeReaders := int32(len(cloudFuncs))
-	for i, pcSrc := range cloudFuncs {
-		iCopy := i
-		pcSrcCopy := pcSrc
+
+	// Get all the clouds at once, as they may come from different cameras.
+	clouds := make([]PointCloud, len(cloudFuncs))
+	offsets := make([]spatialmath.Pose, len(cloudFuncs))
+	errs := make([]error, len(cloudFuncs))
+	var wg sync.WaitGroup
+	for i, cloudFunc := range cloudFuncs {
+		wg.Add(1)
 		utils.PanicCapturingGo(func() {
-			_, span := trace.StartSpan(ctx, "pointcloud::MergePointClouds::Cloud"+fmt.Sprint(iCopy))
+			defer wg.Done()
+			ctx, span := trace.StartSpan(ctx, "pointcloud::MergePointClouds::Cloud"+fmt.Sprint(i))
 			defer span.End()
-
-			defer func() {
-				atomic.AddInt32(&activeReaders, -1)
-			}()
-			pc, offset, err := pcSrcCopy(ctx)
-			if err != nil {
-				panic(err) // TODO(erh) is there something better to do?
-			}
-			var wg sync.WaitGroup
-			const numLoops = 8
-			for loop := 0; loop < numLoops; loop++ {
-				wg.Add(1)
-				f := func(loop int) {
-					defer wg.Done()
-					const batchSize = 500
-					batch := make([]PointAndData, 0, batchSize)
-					savedDualQuat := spatialmath.NewZeroPose()
-					pc.Iterate(numLoops, loop, func(p r3.Vector, d Data) bool {
-						if offset != nil {
-							spatialmath.ResetPoseDQTranslation(savedDualQuat, p)
-							newPose := spatialmath.Compose(offset, savedDualQuat)
-							p = newPose.Point()
-						}
-						batch = append(batch, PointAndData{P: p, D: d})
-						if len(batch) > batchSize {
-							finalPoints <- batch
-							batch = make([]PointAndData, 0, batchSize)
-						}
-						return true
-					})
-					finalPoints <- batch
-				}
-				loopCopy := loop
-				utils.PanicCapturingGo(func() { f(loopCopy) })
-			}
-			wg.Wait()
+			clouds[i], offsets[i], errs[i] = cloudFunc(ctx)
 		})
 	}
-	var pcTo PointCloud
-	var err error
-
-	dataLastTime := false // if there was data in the channel in the previous loop, continue reading.
-	for dataLastTime || atomic.LoadInt32(&activeReaders) > 0 {
-		select {
-		case ps := <-finalPoints:
-			for _, p := range ps {
-				if pcTo == nil {
-					if p.D == nil {
-						pcTo = NewAppendOnlyOnlyPointsPointCloud(len(cloudFuncs) * 640 * 800)
-					} else {
-						pcTo = NewWithPrealloc(len(cloudFuncs) * 640 * 800)
-					}
-				}
+	wg.Wait()
+	if err := multierr.Combine(errs...); err != nil {
+		return nil, err
+	}
 
-				myErr := pcTo.Set(p.P, p.D)
-				if myErr != nil {
-					err = myErr
-				}
-			}
-			dataLastTime = true
-		case <-time.After(5 * time.Millisecond):
-			dataLastTime = false
-			continue
-		}
+	total := 0
+	for _, pc := range clouds {
+		total += pc.Size()
 	}
-	// one last read to flush out any potential last data- sometimes there is still data in finalPoints.
-	lastBatches := len(finalPoints)
-	for i := 0; i < lastBatches; i++ {
-		lastPoints := <-finalPoints
-		if len(lastPoints) == 0 {
-			continue
+	// The positions are kept apart from their data, such that they are transformed as one
+	// contiguous slice of vectors.
+	positions := make([]r3.Vector, 0, total)
+	data := make([]Data, 0, total)
+	for i, pc := range clouds {
+		start := len(positions)
+		pc.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+			positions = append(positions, p)
+			data = append(data, d)
+			return true
+		})
+		if offsets[i] != nil {
+			transformPoints(positions[start:], offsets[i])
 		}
-		for _, p := range lastPoints {
-			myErr := pcTo.Set(p.P, p.D)
-			if myErr != nil {
-				err = myErr
-			}
+	}
+
+	var pcTo PointCloud
+	if len(data) > 0 && data[0] == nil {
+		pcTo = NewAppendOnlyOnlyPointsPointCloud(total)
+	} else {
+		pcTo = NewWithPrealloc(total)
+	}
+	for i, p := range positions {
+		if err := pcTo.Set(p, data[i]); err != nil {
+			return nil, err
 		}
 	}
+	return pcTo, nil
+}
 
-	if err != nil {
-		return nil, err
+// minPointsPerWorker is the fewest points transformed by one goroutine, below which starting it
+// costs more than it saves.
+const minPointsPerWorker = 16384
+
+// transformPoints transforms the points in place by pose, splitting them between as many
+// goroutines as can run in parallel.
+func transformPoints(points []r3.Vector, pose spatialmath.Pose) {
+	rm := pose.Orientation().RotationMatrix()
+	t := pose.Point()
+	workers := min(runtime.GOMAXPROCS(0), (len(points)+minPointsPerWorker-1)/minPointsPerWorker)
+	if workers <= 1 {
+		rotateAndTranslate(points, rm, t)
+		return
 	}
+	chunkSize := (len(points) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(points); start += chunkSize {
+		chunk := points[start:min(start+chunkSize, len(points))]
+		wg.Add(1)
+		utils.PanicCapturingGo(func() {
+			defer wg.Done()
+			rotateAndTranslate(chunk, rm, t)
+		})
+	}
+	wg.Wait()
+}
 
-	return pcTo, nil
+// rotateAndTranslate rotates each point by rm and then translates it by t. The matrix is kept in
+// locals, such that the loop only reads and writes the points. RotationMatrix holds the transpose
+// of the matrix that rotates column vectors, so its rows are the columns used here.
+func rotateAndTranslate(points []r3.Vector, rm *spatialmath.RotationMatrix, t r3.Vector) {
+	r00, r01, r02 := rm.At(0, 0), rm.At(1, 0), rm.At(2, 0)
+	r10, r11, r12 := rm.At(0, 1), rm.At(1, 1), rm.At(2, 1)
+	r20, r21, r22 := rm.At(0, 2), rm.At(1, 2), rm.At(2, 2)
+	for i := range points {
+		p := &points[i]
+		x, y, z := p.X, p.Y, p.Z
+		p.X = r00*x + r01*y + r02*z + t.X
+		p.Y = r10*x + r11*y + r12*z + t.Y
+		p.Z = r20*x + r21*y + r22*z + t.Z
+	}
 }
 
 // MergePointCloudsWithColor creates a union of point clouds from the slice of point clouds, giving
@@ -40,8 +40,6 @@ func makeThreeCloudsWithOffsets(t *testing.T) []CloudAndOffsetFunc {
 }
 
 func TestApplyOffset(t *testing.T) {
-	// TODO(RSDK-1200): remove skip when complete
-	t.Skip("remove skip once RSDK-1200 improvement is complete")
 	logger := logging.NewTestLogger(t)
 	pc1 := NewWithPrealloc(3)
 	err := pc1.Set(NewVector(1, 0, 0), NewColoredData(color.NRGBA{255, 0, 0, 255}))
@@ -115,8 +113,6 @@ func TestApplyOffset(t *testing.T) {
 }
 
 func TestMergePoints1(t *testing.T) {
-	// TODO(RSDK-1200): remove skip when complete
-	t.Skip("remove skip once RSDK-1200 improvement is complete")
 	logger := logging.NewTestLogger(t)
 	clouds := makeClouds(t)
 	cloudsWithOffset := make([]CloudAndOffsetFunc, 0, len(clouds))
@@ -134,8 +130,6 @@ func TestMergePoints1(t *testing.T) {
 }
 
 func TestMergePoints2(t *testing.T) {
-	// TODO(RSDK-1200): remove skip when complete
-	t.Skip("remove skip once RSDK-1200 improvement is complete")
 	logger := logging.NewTestLogger(t)
 	clouds := makeThreeCloudsWithOffsets(t)
 	pc, err := MergePointClouds(context.Background(), clouds, logger)
@@ -174,3 +168,47 @@ func TestMergePointsWithColor(t *testing.T) {
 	test.That(t, a.Color(), test.ShouldResemble, b.Color())
 	test.That(t, a.Color(), test.ShouldNotResemble, c.Color())
 }
+
+func TestApplyOffsetLargeCloud(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	// enough points to be transformed by several goroutines
+	pc := NewWithPrealloc(4 * minPointsPerWorker)
+	for i := 0; i < 4*minPointsPerWorker; i++ {
+		test.That(t, pc.Set(NewVector(float64(i%97), float64(i%89), float64(i)), nil), test.ShouldBeNil)
+	}
+	pose := spatialmath.NewPose(r3.Vector{10, -20, 30}, &spatialmath.OrientationVectorDegrees{OX: 1, OY: 2, OZ: 3, Theta: 40})
+	transformed, err := ApplyOffset(context.Background(), pc, pose, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, transformed.Size(), test.ShouldEqual, pc.Size())
+
+	var want []r3.Vector
+	pc.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		want = append(want, spatialmath.Compose(pose, spatialmath.NewPoseFromPoint(p)).Point())
+		return true
+	})
+	i := 0
+	transformed.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		test.That(t, p.X, test.ShouldAlmostEqual, want[i].X, 1e-6)
+		test.That(t, p.Y, test.ShouldAlmostEqual, want[i].Y, 1e-6)
+		test.That(t, p.Z, test.ShouldAlmostEqual, want[i].Z, 1e-6)
+		i++
+		return true
+	})
+}
+
+// BenchmarkApplyOffset transforms a 500k point cloud, the size of a lidar scan.
+func BenchmarkApplyOffset(b *testing.B) {
+	logger := logging.NewTestLogger(b)
+	pc := NewAppendOnlyOnlyPointsPointCloud(500000)
+	for i := 0; i < 500000; i++ {
+		test.That(b, pc.Set(NewVector(float64(i%1000), float64(i/1000), float64(i%7)), nil), test.ShouldBeNil)
+	}
+	pose := spatialmath.NewPose(r3.Vector{10, -20, 30}, &spatialmath.OrientationVectorDegrees{OX: 1, OY: 2, OZ: 3, Theta: 40})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := ApplyOffset(context.Background(), pc, pose, logger); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
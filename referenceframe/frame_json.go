@@ -33,6 +33,7 @@ type JointConfig struct {
 	Max      float64                 `json:"max"`                // in mm or degs
 	Min      float64                 `json:"min"`                // in mm or degs
 	Geometry *spatial.GeometryConfig `json:"geometry,omitempty"` // only valid for prismatic/translational joints
+	Mimic    *MimicConfig            `json:"mimic,omitempty"`
 }
 
 // DHParamConfig is a revolute and static frame combined in a set of Denavit Hartenberg parameters.
@@ -100,18 +101,25 @@ func (cfg *LinkConfig) Pose() (spatial.Pose, error) {
 	return spatial.NewPoseFromPoint(pt), nil
 }
 
-// ToFrame converts a JointConfig into a joint frame.
+// ToFrame converts a JointConfig into a joint frame. Joints that mimic another joint have no degrees of freedom of
+// their own, and are only moved by the model they are part of.
 func (cfg *JointConfig) ToFrame() (Frame, error) {
+	var frame Frame
+	var err error
 	switch cfg.Type {
 	case RevoluteJoint:
-		return NewRotationalFrame(cfg.ID, cfg.Axis.ParseConfig(),
+		frame, err = NewRotationalFrame(cfg.ID, cfg.Axis.ParseConfig(),
 			Limit{Min: utils.DegToRad(cfg.Min), Max: utils.DegToRad(cfg.Max)})
 	case PrismaticJoint:
-		return NewTranslationalFrame(cfg.ID, r3.Vector(cfg.Axis),
+		frame, err = NewTranslationalFrame(cfg.ID, r3.Vector(cfg.Axis),
 			Limit{Min: cfg.Min, Max: cfg.Max})
 	default:
 		return nil, NewUnsupportedJointTypeError(cfg.Type)
 	}
+	if err != nil || cfg.Mimic == nil {
+		return frame, err
+	}
+	return newMimicFrame(frame, *cfg.Mimic, cfg.Type == RevoluteJoint)
 }
 
 // ToDHFrames converts a DHParamConfig into a joint frame and a link frame.
@@ -0,0 +1,168 @@
+package referenceframe
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/component/arm/v1"
+
+	"go.viam.com/rdk/utils"
+)
+
+// KinematicsOverrides are joint limits and a home position set in the config of a component in place of those of its
+// kinematics, so that installations can keep, for example, an arm from winding up its cable harness or reaching outside of
+// its enclosure.
+type KinematicsOverrides struct {
+	// JointLimits narrows the limits of joints, by name, to within the limits of the kinematics.
+	JointLimits map[string]JointLimitsConfig `json:"joint_limits,omitempty"`
+	// Home is the position of each degree of freedom, in degrees for revolute joints or mm for prismatic joints, that the
+	// component rests at.
+	Home []float64 `json:"home,omitempty"`
+}
+
+// JointLimitsConfig are the limits of a joint, in degrees for revolute joints or mm for prismatic joints.
+type JointLimitsConfig struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// Validate checks the overrides for errors which do not depend on the model they are applied to.
+func (o *KinematicsOverrides) Validate() error {
+	if o == nil {
+		return nil
+	}
+	for name, limits := range o.JointLimits {
+		if limits.Min > limits.Max {
+			return errors.Errorf("joint %q has a min of %v greater than its max of %v", name, limits.Min, limits.Max)
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of the model with its joint limits narrowed by the overrides. It returns an error if an overridden
+// joint is not a revolute or prismatic joint of the model, if the narrowed limits are not within those of the model, or if
+// the home position is not within the narrowed limits.
+func (o *KinematicsOverrides) Apply(model Model) (Model, error) {
+	if o == nil {
+		return model, nil
+	}
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	if len(o.JointLimits) > 0 {
+		simple, ok := model.(*SimpleModel)
+		if !ok {
+			return nil, errors.Errorf("cannot override the joint limits of model %q", model.Name())
+		}
+		found := map[string]bool{}
+		narrowed, err := o.narrow(simple, found)
+		if err != nil {
+			return nil, err
+		}
+		for name := range o.JointLimits {
+			if !found[name] {
+				return nil, errors.Errorf("model %q has no joint %q to override the limits of", model.Name(), name)
+			}
+		}
+		model = narrowed
+	}
+
+	if len(o.Home) > 0 {
+		limits := model.DoF()
+		if len(o.Home) != len(limits) {
+			return nil, errors.Errorf("home position has %d values but model %q has %d degrees of freedom",
+				len(o.Home), model.Name(), len(limits))
+		}
+		for i, input := range o.HomeInputs(model) {
+			if input.Value < limits[i].Min || input.Value > limits[i].Max {
+				return nil, errors.Errorf("home position of joint %d is outside of its limits", i)
+			}
+		}
+	}
+	return model, nil
+}
+
+// HomeInputs returns the home position of the model as inputs, or nil if the overrides have none.
+func (o *KinematicsOverrides) HomeInputs(model Model) []Input {
+	if o == nil || len(o.Home) == 0 {
+		return nil
+	}
+	return model.InputFromProtobuf(&pb.JointPositions{Values: o.Home})
+}
+
+// narrow returns a copy of the model with the overridden joints replaced by ones with narrowed limits, recording in found
+// the names of the joints it narrowed.
+func (o *KinematicsOverrides) narrow(m *SimpleModel, found map[string]bool) (*SimpleModel, error) {
+	narrowed := NewSimpleModel(m.Name())
+	cfg, err := o.narrowConfig(m.modelConfig)
+	if err != nil {
+		return nil, err
+	}
+	narrowed.modelConfig = cfg
+
+	for i, transform := range m.OrdTransforms {
+		if model, ok := transform.(*SimpleModel); ok {
+			sub, err := o.narrow(model, found)
+			if err != nil {
+				return nil, err
+			}
+			narrowed.OrdTransforms = append(narrowed.OrdTransforms, sub)
+			continue
+		}
+		override, ok := o.JointLimits[transform.Name()]
+		if !ok {
+			narrowed.OrdTransforms = append(narrowed.OrdTransforms, transform)
+			continue
+		}
+
+		var limit Limit
+		switch f := transform.(type) {
+		case *rotationalFrame:
+			limit = Limit{Min: utils.DegToRad(override.Min), Max: utils.DegToRad(override.Max)}
+			transform = &rotationalFrame{&baseFrame{f.name, []Limit{limit}}, f.rotAxis}
+		case *translationalFrame:
+			limit = Limit{Min: override.Min, Max: override.Max}
+			transform = &translationalFrame{&baseFrame{f.name, []Limit{limit}}, f.transAxis, f.geometry}
+		default:
+			return nil, errors.Errorf("joint %q is not a revolute or prismatic joint, so its limits cannot be overridden", transform.Name())
+		}
+		original := m.OrdTransforms[i].DoF()[0]
+		if limit.Min < original.Min || limit.Max > original.Max {
+			return nil, errors.Errorf("limits of joint %q must be within its limits of [%v, %v]",
+				transform.Name(), original.Min, original.Max)
+		}
+		found[transform.Name()] = true
+		narrowed.OrdTransforms = append(narrowed.OrdTransforms, transform)
+	}
+	return narrowed, nil
+}
+
+// narrowConfig returns a copy of the config with the overridden joints' limits narrowed, whose original file is the copy
+// itself so that the narrowed limits are kept wherever the model is sent.
+func (o *KinematicsOverrides) narrowConfig(cfg *ModelConfig) (*ModelConfig, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	narrowed := *cfg
+	narrowed.OriginalFile = nil
+	narrowed.Joints = append([]JointConfig(nil), cfg.Joints...)
+	for i, joint := range narrowed.Joints {
+		if override, ok := o.JointLimits[joint.ID]; ok {
+			narrowed.Joints[i].Min = override.Min
+			narrowed.Joints[i].Max = override.Max
+		}
+	}
+	narrowed.DHParams = append([]DHParamConfig(nil), cfg.DHParams...)
+	for i, dh := range narrowed.DHParams {
+		if override, ok := o.JointLimits[dh.ID+"_j"]; ok {
+			narrowed.DHParams[i].Min = override.Min
+			narrowed.DHParams[i].Max = override.Max
+		}
+	}
+	data, err := json.Marshal(narrowed)
+	if err != nil {
+		return nil, err
+	}
+	narrowed.OriginalFile = &ModelFile{Bytes: data, Extension: "json"}
+	return &narrowed, nil
+}
@@ -0,0 +1,126 @@
+package referenceframe
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/utils"
+)
+
+func TestKinematicsOverrides(t *testing.T) {
+	m, err := ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+
+	overrides := &KinematicsOverrides{
+		JointLimits: map[string]JointLimitsConfig{
+			"shoulder_pan_joint": {Min: -90, Max: 90},
+			"wrist_3_joint":      {Min: 0, Max: 180},
+		},
+		Home: []float64{0, -90, 90, -90, -90, 90},
+	}
+	narrowed, err := overrides.Apply(m)
+	test.That(t, err, test.ShouldBeNil)
+
+	limits := narrowed.DoF()
+	test.That(t, limits[0], test.ShouldResemble, Limit{Min: -math.Pi / 2, Max: math.Pi / 2})
+	test.That(t, limits[1], test.ShouldResemble, m.DoF()[1])
+	test.That(t, limits[5], test.ShouldResemble, Limit{Min: 0, Max: math.Pi})
+	// the original model is untouched
+	test.That(t, m.DoF()[0], test.ShouldResemble, Limit{Min: -2 * math.Pi, Max: 2 * math.Pi})
+
+	home := overrides.HomeInputs(narrowed)
+	test.That(t, home[1].Value, test.ShouldAlmostEqual, -math.Pi/2)
+	pose, err := narrowed.Transform(home)
+	test.That(t, err, test.ShouldBeNil)
+	expected, err := m.Transform(home)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pose, test.ShouldResemble, expected)
+
+	// inputs within the original limits but outside of the narrowed ones are out of bounds
+	_, err = narrowed.Transform(FloatsToInputs([]float64{math.Pi, 0, 0, 0, 0, 0}))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, OOBErrString)
+
+	// the narrowed limits are kept when the model is serialized
+	data, err := narrowed.MarshalJSON()
+	test.That(t, err, test.ShouldBeNil)
+	unmarshaled, err := UnmarshalModelJSON(data, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, unmarshaled.DoF(), test.ShouldResemble, limits)
+	unmarshaled, err = UnmarshalModelJSON(narrowed.ModelConfig().OriginalFile.Bytes, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, unmarshaled.DoF(), test.ShouldResemble, limits)
+
+	t.Run("no overrides", func(t *testing.T) {
+		var none *KinematicsOverrides
+		same, err := none.Apply(m)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, same, test.ShouldEqual, m)
+		test.That(t, none.HomeInputs(m), test.ShouldBeNil)
+	})
+
+	t.Run("prismatic joints", func(t *testing.T) {
+		gantry := NewSimpleModel("gantry")
+		axis, err := NewTranslationalFrame("axis", r3.Vector{X: 1}, Limit{Min: 0, Max: 500})
+		test.That(t, err, test.ShouldBeNil)
+		gantry.OrdTransforms = append(gantry.OrdTransforms, axis)
+
+		narrowed, err := (&KinematicsOverrides{
+			JointLimits: map[string]JointLimitsConfig{"axis": {Min: 100, Max: 400}},
+			Home:        []float64{150},
+		}).Apply(gantry)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, narrowed.DoF(), test.ShouldResemble, []Limit{{Min: 100, Max: 400}})
+	})
+
+	t.Run("invalid overrides", func(t *testing.T) {
+		for _, tc := range []struct {
+			name      string
+			overrides *KinematicsOverrides
+			err       string
+		}{
+			{
+				"min greater than max",
+				&KinematicsOverrides{JointLimits: map[string]JointLimitsConfig{"elbow_joint": {Min: 10, Max: -10}}},
+				"greater than its max",
+			},
+			{
+				"unknown joint",
+				&KinematicsOverrides{JointLimits: map[string]JointLimitsConfig{"knee_joint": {Min: -10, Max: 10}}},
+				"no joint \"knee_joint\"",
+			},
+			{
+				"not a joint",
+				&KinematicsOverrides{JointLimits: map[string]JointLimitsConfig{"forearm_link": {Min: -10, Max: 10}}},
+				"not a revolute or prismatic joint",
+			},
+			{
+				"widened limits",
+				&KinematicsOverrides{JointLimits: map[string]JointLimitsConfig{"elbow_joint": {Min: -190, Max: 90}}},
+				"must be within its limits",
+			},
+			{
+				"home of the wrong length",
+				&KinematicsOverrides{Home: []float64{0, 0}},
+				"6 degrees of freedom",
+			},
+			{
+				"home outside of the narrowed limits",
+				&KinematicsOverrides{
+					JointLimits: map[string]JointLimitsConfig{"elbow_joint": {Min: 0, Max: 90}},
+					Home:        []float64{0, 0, -45, 0, 0, 0},
+				},
+				"home position of joint 2",
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := tc.overrides.Apply(m)
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+			})
+		}
+	})
+}
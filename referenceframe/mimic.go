@@ -0,0 +1,118 @@
+package referenceframe
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/component/arm/v1"
+
+	"go.viam.com/rdk/utils"
+)
+
+// MimicConfig makes a joint follow another joint of the same model instead of being moved on its own, as with
+// mechanically coupled joints. The joint's position is Multiplier times the position of the joint it mimics, plus
+// Offset, in the units of the joint configs (degrees for revolute joints, mm for prismatic joints).
+type MimicConfig struct {
+	Joint      string  `json:"joint"`
+	Multiplier float64 `json:"multiplier,omitempty"` // 0 is treated as 1
+	Offset     float64 `json:"offset,omitempty"`
+}
+
+// mimicFrame wraps a joint frame whose input is computed from the input of another joint of its model, so it adds no
+// degrees of freedom to the model.
+type mimicFrame struct {
+	Frame
+	config   MimicConfig
+	revolute bool
+
+	// set once the model's transforms are ordered
+	leaderIdx      int
+	leaderRevolute bool
+}
+
+func newMimicFrame(joint Frame, config MimicConfig, revolute bool) (*mimicFrame, error) {
+	if config.Joint == "" {
+		return nil, errors.Errorf("joint %q must name the joint it mimics", joint.Name())
+	}
+	if config.Joint == joint.Name() {
+		return nil, errors.Errorf("joint %q cannot mimic itself", joint.Name())
+	}
+	if config.Multiplier == 0 {
+		config.Multiplier = 1
+	}
+	return &mimicFrame{Frame: joint, config: config, revolute: revolute}, nil
+}
+
+// DoF returns no limits, as the joint is not moved on its own.
+func (mf *mimicFrame) DoF() []Limit {
+	return []Limit{}
+}
+
+// Interpolate returns no inputs, as the joint is not moved on its own.
+func (mf *mimicFrame) Interpolate(from, to []Input, by float64) ([]Input, error) {
+	return []Input{}, nil
+}
+
+// InputFromProtobuf returns no inputs, as the joint is not moved on its own.
+func (mf *mimicFrame) InputFromProtobuf(jp *pb.JointPositions) []Input {
+	return []Input{}
+}
+
+// ProtobufFromInput returns no positions, as the joint is not moved on its own.
+func (mf *mimicFrame) ProtobufFromInput(input []Input) *pb.JointPositions {
+	return &pb.JointPositions{Values: []float64{}}
+}
+
+// follow returns the input of the wrapped joint given the inputs of the whole model.
+func (mf *mimicFrame) follow(inputs []Input) []Input {
+	value := inputs[mf.leaderIdx].Value
+	if mf.leaderRevolute {
+		value = utils.RadToDeg(value)
+	}
+	value = mf.config.Multiplier*value + mf.config.Offset
+	if mf.revolute {
+		value = utils.DegToRad(value)
+	}
+	return []Input{{value}}
+}
+
+func (mf mimicFrame) MarshalJSON() ([]byte, error) {
+	data, err := mf.Frame.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var temp JointConfig
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return nil, err
+	}
+	config := mf.config
+	temp.Mimic = &config
+	return json.Marshal(temp)
+}
+
+// resolveMimics points the mimic joints among the ordered transforms of a model at the inputs of the joints they mimic.
+func resolveMimics(transforms []Frame) error {
+	leaders := map[string]int{}
+	revolute := map[string]bool{}
+	posIdx := 0
+	for _, transform := range transforms {
+		if len(transform.DoF()) == 1 {
+			leaders[transform.Name()] = posIdx
+			_, revolute[transform.Name()] = transform.(*rotationalFrame)
+		}
+		posIdx += len(transform.DoF())
+	}
+	for _, transform := range transforms {
+		mimic, ok := transform.(*mimicFrame)
+		if !ok {
+			continue
+		}
+		idx, ok := leaders[mimic.config.Joint]
+		if !ok {
+			return errors.Errorf("joint %q mimics %q, which is not a moving joint of the model", mimic.Name(), mimic.config.Joint)
+		}
+		mimic.leaderIdx = idx
+		mimic.leaderRevolute = revolute[mimic.config.Joint]
+	}
+	return nil
+}
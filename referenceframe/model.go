@@ -222,6 +222,9 @@ func (m *SimpleModel) inputsToFrames(inputs []Input, collectAll bool) ([]*static
 		dof := len(transform.DoF()) + posIdx
 		input := inputs[posIdx:dof]
 		posIdx = dof
+		if mimic, ok := transform.(*mimicFrame); ok {
+			input = mimic.follow(inputs)
+		}
 
 		pose, errNew := transform.Transform(input)
 		// Fail if inputs are incorrect and pose is nil, but allow querying out-of-bounds positions
@@ -120,6 +120,9 @@ func (cfg *ModelConfig) ParseConfig(modelName string) (Model, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := resolveMimics(model.OrdTransforms); err != nil {
+		return nil, err
+	}
 
 	return model, nil
 }
@@ -140,3 +140,43 @@ func Test2DMobileModelFrame(t *testing.T) {
 	limit := frame.DoF()
 	test.That(t, limit[0], test.ShouldResemble, expLimit[0])
 }
+
+func TestMimicJoints(t *testing.T) {
+	cfg := &ModelConfig{
+		Name: "mimic",
+		Links: []LinkConfig{
+			{ID: "ee", Parent: "follower", Translation: r3.Vector{X: 100}},
+		},
+		Joints: []JointConfig{
+			{ID: "leader", Type: RevoluteJoint, Parent: World, Axis: spatial.AxisConfig{Z: 1}, Min: -180, Max: 180},
+			{
+				ID: "follower", Type: RevoluteJoint, Parent: "leader", Axis: spatial.AxisConfig{Z: 1}, Min: -360, Max: 360,
+				Mimic: &MimicConfig{Joint: "leader", Multiplier: 2, Offset: 90},
+			},
+		},
+	}
+	m, err := cfg.ParseConfig("")
+	test.That(t, err, test.ShouldBeNil)
+	// only the leader is moved
+	test.That(t, len(m.DoF()), test.ShouldEqual, 1)
+
+	// the follower turns twice as far as the leader, plus 90 degrees
+	pose, err := m.Transform([]Input{{utils.DegToRad(30)}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pose.Point().X, test.ShouldAlmostEqual, 100*math.Cos(utils.DegToRad(180)))
+	test.That(t, pose.Point().Y, test.ShouldAlmostEqual, 100*math.Sin(utils.DegToRad(180)))
+
+	// the follower keeps its mimic config when serialized
+	data, err := m.(*SimpleModel).OrdTransforms[1].MarshalJSON()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(data), test.ShouldContainSubstring, `"mimic":{"joint":"leader","multiplier":2,"offset":90}`)
+
+	cfg.Joints[1].Mimic.Joint = "ee"
+	_, err = cfg.ParseConfig("")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not a moving joint")
+
+	cfg.Joints[1].Mimic.Joint = "follower"
+	_, err = cfg.ParseConfig("")
+	test.That(t, err, test.ShouldNotBeNil)
+}
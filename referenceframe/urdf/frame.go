@@ -3,6 +3,9 @@ package urdf
 import (
 	"encoding/xml"
 
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
 	"go.viam.com/rdk/spatialmath"
 )
 
@@ -16,12 +19,49 @@ type limit struct {
 	Upper   float64  `xml:"upper,attr"` // translation limits are in meters, revolute limits are in radians
 }
 
+// safetyController narrows the limits of a joint to the range the joint is meant to be used in.
+type safetyController struct {
+	XMLName   xml.Name `xml:"safety_controller"`
+	SoftLower *float64 `xml:"soft_lower_limit,attr"` // same units as the limits
+	SoftUpper *float64 `xml:"soft_upper_limit,attr"` // same units as the limits
+}
+
+// mimic makes a joint follow another joint, as position = multiplier * other position + offset.
+type mimic struct {
+	XMLName    xml.Name `xml:"mimic"`
+	Joint      string   `xml:"joint,attr"`
+	Multiplier *float64 `xml:"multiplier,attr"` // defaults to 1
+	Offset     float64  `xml:"offset,attr"`     // in meters or radians
+}
+
 type axis struct {
 	XMLName xml.Name `xml:"axis"`
 	XYZ     string   `xml:"xyz,attr"` // "x y z" format, in meters
 }
 
-func (a *axis) Parse() spatialmath.AxisConfig {
-	jointAxes := spaceDelimitedStringToFloatSlice(a.XYZ)
-	return spatialmath.AxisConfig{X: jointAxes[0], Y: jointAxes[1], Z: jointAxes[2]}
+// Parse returns the axis of a joint, which is the x axis if it is not given.
+func (a *axis) Parse() (spatialmath.AxisConfig, error) {
+	defaultAxis := r3.Vector{X: 1}
+	if a == nil {
+		return spatialmath.AxisConfig(defaultAxis), nil
+	}
+	jointAxis, err := parseVector(a.XYZ, defaultAxis)
+	if err != nil {
+		return spatialmath.AxisConfig{}, errors.Wrap(err, "axis xyz")
+	}
+	return spatialmath.AxisConfig(jointAxis), nil
+}
+
+// bounds returns the lower and upper limits of a joint, narrowed by its safety controller if it has one.
+func (l *limit) bounds(safety *safetyController) (float64, float64) {
+	lower, upper := l.Lower, l.Upper
+	if safety != nil {
+		if safety.SoftLower != nil && *safety.SoftLower > lower {
+			lower = *safety.SoftLower
+		}
+		if safety.SoftUpper != nil && *safety.SoftUpper < upper {
+			upper = *safety.SoftUpper
+		}
+	}
+	return lower, upper
 }
@@ -0,0 +1,198 @@
+package urdf
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// NewModelFromFrameSystemParts returns a URDF model of the frame system made of `parts`, such as those of a machine's
+// frame system config, so that it can be loaded into tools like RViz. Each frame is a link attached to the link of its
+// parent. The frames of models are attached through a link named after the frame with an "_origin" suffix, and the
+// kinematic chains of SimpleModels are kept, with links named after the frame and each of the model's transforms;
+// other models are fixed at their zero inputs. Geometries URDF cannot represent, such as meshes and points, are left
+// out.
+func NewModelFromFrameSystemParts(parts []*referenceframe.FrameSystemPart, name string) (*ModelConfig, error) {
+	b := &modelBuilder{
+		model:      &ModelConfig{Name: name, Links: []link{{Name: referenceframe.World}}},
+		jointTypes: map[string]string{},
+	}
+	for _, part := range parts {
+		if part == nil || part.FrameConfig == nil {
+			return nil, errors.New("frame system part has no frame config")
+		}
+		if err := b.addPart(part); err != nil {
+			return nil, errors.Wrapf(err, "frame %q", part.FrameConfig.Name())
+		}
+	}
+	return b.model, nil
+}
+
+// modelBuilder adds the links and joints of frame system parts to a URDF model.
+type modelBuilder struct {
+	model *ModelConfig
+	// jointTypes are the types of the joints added, by the name of their child links
+	jointTypes map[string]string
+}
+
+func (b *modelBuilder) addPart(part *referenceframe.FrameSystemPart) error {
+	cfg := part.FrameConfig
+	var geometries []spatialmath.Geometry
+	if cfg.Geometry() != nil {
+		geometries = []spatialmath.Geometry{cfg.Geometry()}
+	}
+	if part.ModelFrame == nil {
+		return b.addFixed(cfg.Parent(), cfg.Name(), cfg.Pose(), geometries)
+	}
+
+	// as in frame systems, the geometry of the part replaces those of models without degrees of freedom
+	origin := cfg.Name() + "_origin"
+	if err := b.addFixed(cfg.Parent(), origin, cfg.Pose(), geometries); err != nil {
+		return err
+	}
+	model, ok := part.ModelFrame.(*referenceframe.SimpleModel)
+	if !ok {
+		pose, err := part.ModelFrame.Transform(make([]referenceframe.Input, len(part.ModelFrame.DoF())))
+		if err != nil {
+			return err
+		}
+		return b.addFixed(origin, cfg.Name(), pose, nil)
+	}
+	withGeometries := len(model.DoF()) > 0 || cfg.Geometry() == nil
+	parent := origin
+	for _, transform := range model.OrdTransforms {
+		child := cfg.Name() + ":" + transform.Name()
+		if err := b.addTransform(parent, child, cfg.Name()+":", transform, withGeometries); err != nil {
+			return err
+		}
+		parent = child
+	}
+	return b.addFixed(parent, cfg.Name(), spatialmath.NewZeroPose(), nil)
+}
+
+// addFixed adds the link `child`, fixed at `pose` in `parent`, with geometries relative to `child`.
+func (b *modelBuilder) addFixed(parent, child string, pose spatialmath.Pose, geometries []spatialmath.Geometry) error {
+	collisions, err := newCollisions(geometries)
+	if err != nil {
+		return err
+	}
+	b.model.Links = append(b.model.Links, link{Name: child, Collision: collisions})
+	b.model.Joints = append(b.model.Joints, joint{
+		Name:   jointName(child),
+		Type:   referenceframe.FixedJoint,
+		Parent: frame{parent},
+		Child:  frame{child},
+		Origin: newPose(pose),
+	})
+	b.jointTypes[child] = referenceframe.FixedJoint
+	return nil
+}
+
+// addTransform adds the link `child` moved by `transform`, a transform of a model whose links are named with `prefix`.
+func (b *modelBuilder) addTransform(parent, child, prefix string, transform referenceframe.Frame, withGeometries bool) error {
+	data, err := transform.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	var cfg referenceframe.JointConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+
+	if cfg.Type != referenceframe.RevoluteJoint && cfg.Type != referenceframe.PrismaticJoint {
+		pose, err := transform.Transform([]referenceframe.Input{})
+		if err != nil {
+			return err
+		}
+		var geometries []spatialmath.Geometry
+		if withGeometries {
+			gif, err := transform.Geometries([]referenceframe.Input{})
+			if err != nil {
+				return err
+			}
+			// the geometries of frames are relative to their parents
+			for _, g := range gif.Geometries() {
+				geometries = append(geometries, g.Transform(spatialmath.PoseInverse(pose)))
+			}
+		}
+		return b.addFixed(parent, child, pose, geometries)
+	}
+
+	j := joint{
+		Name:   jointName(child),
+		Type:   cfg.Type,
+		Parent: frame{parent},
+		Child:  frame{child},
+		Origin: newPose(spatialmath.NewZeroPose()),
+		Axis:   &axis{XYZ: fmt.Sprintf("%f %f %f", cfg.Axis.X, cfg.Axis.Y, cfg.Axis.Z)},
+	}
+	if cfg.Type == referenceframe.RevoluteJoint && math.IsInf(cfg.Min, -1) && math.IsInf(cfg.Max, 1) {
+		j.Type = referenceframe.ContinuousJoint
+	} else {
+		j.Limit = &limit{Lower: fromJointUnits(cfg.Type, cfg.Min), Upper: fromJointUnits(cfg.Type, cfg.Max)}
+	}
+	if cfg.Mimic != nil {
+		leader := prefix + cfg.Mimic.Joint
+		leaderType, ok := b.jointTypes[leader]
+		if !ok {
+			return errors.Errorf("joint %q mimics %q, which comes after it", transform.Name(), cfg.Mimic.Joint)
+		}
+		if leaderType == referenceframe.ContinuousJoint {
+			leaderType = referenceframe.RevoluteJoint
+		}
+		// the inverse of the conversion of UnmarshalModelXML
+		multiplier := fromJointUnits(cfg.Type, cfg.Mimic.Multiplier) * toJointUnits(leaderType, 1)
+		j.Mimic = &mimic{Joint: jointName(leader), Multiplier: &multiplier, Offset: fromJointUnits(cfg.Type, cfg.Mimic.Offset)}
+	}
+
+	var collisions []collision
+	if withGeometries && cfg.Type == referenceframe.PrismaticJoint {
+		// at zero inputs the link of a prismatic joint is where the joint starts
+		gif, err := transform.Geometries([]referenceframe.Input{{Value: 0}})
+		if err != nil {
+			return err
+		}
+		if collisions, err = newCollisions(gif.Geometries()); err != nil {
+			return err
+		}
+	}
+	b.model.Links = append(b.model.Links, link{Name: child, Collision: collisions})
+	b.model.Joints = append(b.model.Joints, j)
+	b.jointTypes[child] = j.Type
+	return nil
+}
+
+// newCollisions returns the collisions of the geometries URDF can represent.
+func newCollisions(geometries []spatialmath.Geometry) ([]collision, error) {
+	var collisions []collision
+	for _, g := range geometries {
+		coll, err := newCollision(g)
+		if errors.Is(err, errGeometryTypeUnsupported) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		collisions = append(collisions, *coll)
+	}
+	return collisions, nil
+}
+
+func jointName(child string) string {
+	return child + "_joint"
+}
+
+// fromJointUnits converts a joint position from the units of joint configs (degrees or mm) to the units of URDF
+// (radians or meters).
+func fromJointUnits(jointType string, value float64) float64 {
+	if jointType == referenceframe.PrismaticJoint {
+		return utils.MMToMeters(value)
+	}
+	return utils.DegToRad(value)
+}
@@ -0,0 +1,125 @@
+package urdf
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestNewModelFromFrameSystemParts(t *testing.T) {
+	ur5e, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	armPose := spatialmath.NewPose(r3.Vector{X: 100, Y: -50}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90})
+	armPart := &referenceframe.FrameSystemPart{
+		FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, armPose, "arm", nil),
+		ModelFrame:  ur5e,
+	}
+	gripperBox, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Z: 50}), r3.Vector{X: 40, Y: 40, Z: 100}, "")
+	test.That(t, err, test.ShouldBeNil)
+	gripperPart := &referenceframe.FrameSystemPart{
+		FrameConfig: referenceframe.NewLinkInFrame("arm", spatialmath.NewPoseFromPoint(r3.Vector{Z: 10}), "gripper", gripperBox),
+	}
+
+	t.Run("kinematics are kept", func(t *testing.T) {
+		model, err := NewModelFromFrameSystemParts([]*referenceframe.FrameSystemPart{armPart}, "machine")
+		test.That(t, err, test.ShouldBeNil)
+		data, err := xml.MarshalIndent(model, "", "  ")
+		test.That(t, err, test.ShouldBeNil)
+
+		// the exported URDF moves like the frame system it came from
+		cfg, err := UnmarshalModelXML(data, "")
+		test.That(t, err, test.ShouldBeNil)
+		imported, err := cfg.ParseConfig("machine")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, imported.DoF(), test.ShouldHaveLength, 6)
+		fs, err := referenceframe.NewFrameSystem("test", []*referenceframe.FrameSystemPart{armPart}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		for _, inputs := range [][]float64{{0, 0, 0, 0, 0, 0}, {0.3, -1, 1.2, 0.5, -0.4, 2}} {
+			expected, err := fs.Transform(
+				referenceframe.FrameSystemInputs{"arm": referenceframe.FloatsToInputs(inputs)},
+				referenceframe.NewPoseInFrame("arm", spatialmath.NewZeroPose()),
+				referenceframe.World,
+			)
+			test.That(t, err, test.ShouldBeNil)
+			actual, err := imported.Transform(referenceframe.FloatsToInputs(inputs))
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, spatialmath.PoseAlmostEqualEps(actual, expected.(*referenceframe.PoseInFrame).Pose(), 1e-2), test.ShouldBeTrue)
+		}
+
+		for i, limit := range imported.DoF() {
+			test.That(t, limit.Min, test.ShouldAlmostEqual, ur5e.DoF()[i].Min, 1e-5)
+			test.That(t, limit.Max, test.ShouldAlmostEqual, ur5e.DoF()[i].Max, 1e-5)
+		}
+	})
+
+	t.Run("frames and geometries", func(t *testing.T) {
+		model, err := NewModelFromFrameSystemParts([]*referenceframe.FrameSystemPart{armPart, gripperPart}, "machine")
+		test.That(t, err, test.ShouldBeNil)
+		links := map[string]link{}
+		for _, l := range model.Links {
+			links[l.Name] = l
+		}
+		test.That(t, links, test.ShouldContainKey, referenceframe.World)
+		test.That(t, links, test.ShouldContainKey, "arm_origin")
+		test.That(t, links, test.ShouldContainKey, "arm")
+		test.That(t, links, test.ShouldContainKey, "arm:shoulder_pan_joint")
+		test.That(t, links["gripper"].Collision, test.ShouldHaveLength, 1)
+		test.That(t, links["gripper"].Collision[0].Geometry.Box.Size, test.ShouldEqual, "0.040000 0.040000 0.100000")
+		test.That(t, links["gripper"].Collision[0].Origin.XYZ, test.ShouldEqual, "0.000000 0.000000 0.050000")
+		// the capsules of the arm are exported as cylinders
+		cylinders := 0
+		for _, l := range model.Links {
+			for _, c := range l.Collision {
+				if c.Geometry.Cylinder != nil {
+					cylinders++
+				}
+			}
+		}
+		test.That(t, cylinders, test.ShouldBeGreaterThan, 0)
+
+		revolute := 0
+		for _, j := range model.Joints {
+			if j.Type == referenceframe.RevoluteJoint {
+				test.That(t, j.Limit.Upper, test.ShouldAlmostEqual, ur5e.DoF()[revolute].Max, 1e-5)
+				revolute++
+			}
+		}
+		test.That(t, revolute, test.ShouldEqual, 6)
+
+		sdf, err := MarshalSDF(model)
+		test.That(t, err, test.ShouldBeNil)
+		var parsed sdfRoot
+		test.That(t, xml.Unmarshal(sdf, &parsed), test.ShouldBeNil)
+		test.That(t, parsed.Version, test.ShouldEqual, sdfVersion)
+		test.That(t, parsed.Model.Links, test.ShouldHaveLength, len(model.Links)-1)
+		test.That(t, parsed.Model.Joints, test.ShouldHaveLength, len(model.Joints))
+		for _, l := range parsed.Model.Links {
+			switch l.Name {
+			case "arm_origin":
+				test.That(t, l.Pose.RelativeTo, test.ShouldBeEmpty)
+				test.That(t, strings.HasPrefix(l.Pose.Value, "0.100000 -0.050000 0.000000"), test.ShouldBeTrue)
+			case "gripper":
+				test.That(t, l.Pose.RelativeTo, test.ShouldEqual, "arm")
+				test.That(t, l.Collisions[0].Geometry.Box.Size, test.ShouldEqual, "0.040000 0.040000 0.100000")
+			}
+		}
+	})
+
+	t.Run("unsupported geometries are left out", func(t *testing.T) {
+		point := spatialmath.NewPoint(r3.Vector{}, "")
+		part := &referenceframe.FrameSystemPart{
+			FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, spatialmath.NewZeroPose(), "marker", point),
+		}
+		model, err := NewModelFromFrameSystemParts([]*referenceframe.FrameSystemPart{part}, "machine")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, model.Links, test.ShouldHaveLength, 2)
+		test.That(t, model.Links[1].Collision, test.ShouldBeEmpty)
+	})
+}
@@ -18,9 +18,11 @@ type collision struct {
 	XMLName  xml.Name `xml:"collision"`
 	Origin   *pose    `xml:"origin"`
 	Geometry struct {
-		XMLName xml.Name `xml:"geometry"`
-		Box     *box     `xml:"box,omitempty"`
-		Sphere  *sphere  `xml:"sphere,omitempty"`
+		XMLName  xml.Name  `xml:"geometry"`
+		Box      *box      `xml:"box,omitempty"`
+		Sphere   *sphere   `xml:"sphere,omitempty"`
+		Cylinder *cylinder `xml:"cylinder,omitempty"`
+		Mesh     *mesh     `xml:"mesh,omitempty"`
 	} `xml:"geometry"`
 }
 
@@ -34,6 +36,12 @@ type sphere struct {
 	Radius  float64  `xml:"radius,attr"` // in meters
 }
 
+type cylinder struct {
+	XMLName xml.Name `xml:"cylinder"`
+	Radius  float64  `xml:"radius,attr"` // in meters
+	Length  float64  `xml:"length,attr"` // in meters, along the z axis
+}
+
 func newCollision(g spatialmath.Geometry) (*collision, error) {
 	cfg, err := spatialmath.NewGeometryConfig(g)
 	if err != nil {
@@ -48,24 +56,67 @@ func newCollision(g spatialmath.Geometry) (*collision, error) {
 		urdf.Geometry.Box = &box{Size: fmt.Sprintf("%f %f %f", utils.MMToMeters(cfg.X), utils.MMToMeters(cfg.Y), utils.MMToMeters(cfg.Z))}
 	case spatialmath.SphereType:
 		urdf.Geometry.Sphere = &sphere{Radius: utils.MMToMeters(cfg.R)}
+	case spatialmath.CapsuleType:
+		// the inverse of toGeometry's approximation of cylinders
+		urdf.Geometry.Cylinder = &cylinder{Radius: utils.MMToMeters(cfg.R), Length: utils.MMToMeters(cfg.L - 2*cfg.R)}
 	default:
 		return nil, fmt.Errorf("%w %s", errGeometryTypeUnsupported, fmt.Sprintf("%T", cfg.Type))
 	}
 	return urdf, nil
 }
 
-func (c *collision) toGeometry() (spatialmath.Geometry, error) {
+// toGeometry converts the collision into a geometry. Meshes are looked for relative to `dir`, the directory of the
+// URDF; cylinders are approximated by the capsules enclosing them.
+func (c *collision) toGeometry(dir string) (spatialmath.Geometry, error) {
+	origin, err := c.Origin.Parse()
+	if err != nil {
+		return nil, err
+	}
 	switch {
 	case c.Geometry.Box != nil:
-		dims := spaceDelimitedStringToFloatSlice(c.Geometry.Box.Size)
-		return spatialmath.NewBox(
-			c.Origin.Parse(),
-			r3.Vector{X: utils.MetersToMM(dims[0]), Y: utils.MetersToMM(dims[1]), Z: utils.MetersToMM(dims[2])},
-			"",
-		)
+		dims, err := parseVector(c.Geometry.Box.Size, r3.Vector{})
+		if err != nil {
+			return nil, errors.Wrap(err, "box size")
+		}
+		return spatialmath.NewBox(origin, dims.Mul(1000), "")
 	case c.Geometry.Sphere != nil:
-		return spatialmath.NewSphere(c.Origin.Parse(), utils.MetersToMM(c.Geometry.Sphere.Radius), "")
+		return spatialmath.NewSphere(origin, utils.MetersToMM(c.Geometry.Sphere.Radius), "")
+	case c.Geometry.Cylinder != nil:
+		radius := utils.MetersToMM(c.Geometry.Cylinder.Radius)
+		return spatialmath.NewCapsule(origin, radius, utils.MetersToMM(c.Geometry.Cylinder.Length)+2*radius, "")
+	case c.Geometry.Mesh != nil:
+		return c.Geometry.Mesh.toMesh(origin, dir)
 	default:
 		return nil, errors.New("couldn't parse xml: no geometry defined")
 	}
 }
+
+// linkGeometry returns the geometry of a link with the given collisions, or nil if none of them can be used. Only one
+// geometry is supported per link, so the first collision that can be used is taken, except that all of the mesh
+// collisions of a link, such as the convex pieces of a decomposed mesh, are combined into one mesh.
+func linkGeometry(collisions []collision, dir string) (spatialmath.Geometry, error) {
+	var triangles []*spatialmath.Triangle
+	for _, coll := range collisions {
+		geometry, err := coll.toGeometry(dir)
+		if errors.Is(err, errMeshUnavailable) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		mesh, ok := geometry.(*spatialmath.Mesh)
+		if !ok {
+			if triangles == nil {
+				return geometry, nil
+			}
+			continue
+		}
+		for _, triangle := range mesh.Triangles() {
+			triangles = append(triangles, triangle.Transform(mesh.Pose()))
+		}
+	}
+	if triangles == nil {
+		return nil, nil
+	}
+	return spatialmath.NewMesh(spatialmath.NewZeroPose(), triangles, ""), nil
+}
@@ -29,7 +29,8 @@ func TestGeometrySerialization(t *testing.T) {
 	}{
 		{"box", box, true},
 		{"sphere", sphere, true},
-		{"capsule", capsule, false},
+		{"capsule", capsule, true},
+		{"point", spatialmath.NewPoint(r3.Vector{}, ""), false},
 	}
 
 	for _, tc := range testCases {
@@ -44,7 +45,7 @@ func TestGeometrySerialization(t *testing.T) {
 			test.That(t, err, test.ShouldBeNil)
 			var urdf2 collision
 			xml.Unmarshal(bytes, &urdf2)
-			g2, err := urdf2.toGeometry()
+			g2, err := urdf2.toGeometry("")
 			test.That(t, err, test.ShouldBeNil)
 			test.That(t, spatialmath.GeometriesAlmostEqual(tc.g, g2), test.ShouldBeTrue)
 		})
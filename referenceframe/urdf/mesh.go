@@ -0,0 +1,95 @@
+package urdf
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// rosPackagePathEnv lists the directories searched for the packages of "package://" mesh paths, as with ROS.
+const rosPackagePathEnv = "ROS_PACKAGE_PATH"
+
+// errMeshUnavailable is returned for mesh collisions of URDFs that were not read from a file, whose meshes cannot be found.
+var errMeshUnavailable = errors.New("mesh files can only be read for URDFs read from a file")
+
+type mesh struct {
+	XMLName  xml.Name `xml:"mesh"`
+	Filename string   `xml:"filename,attr"`
+	Scale    string   `xml:"scale,attr"` // "x y z" format, defaults to "1 1 1"
+}
+
+// toMesh reads the mesh, scaled and in mm, from its file. Meshes are looked for relative to `dir`, the directory of
+// the URDF.
+func (m *mesh) toMesh(pose spatialmath.Pose, dir string) (*spatialmath.Mesh, error) {
+	if dir == "" {
+		return nil, errMeshUnavailable
+	}
+	path, err := resolveMeshPath(m.Filename, dir)
+	if err != nil {
+		return nil, err
+	}
+	scale, err := parseVector(m.Scale, r3.Vector{X: 1, Y: 1, Z: 1})
+	if err != nil {
+		return nil, errors.Wrap(err, "mesh scale")
+	}
+	fileMesh, err := spatialmath.NewMeshFromFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read mesh %q", m.Filename)
+	}
+	if len(fileMesh.Triangles()) == 0 {
+		return nil, errors.Errorf("mesh %q has no triangles", m.Filename)
+	}
+	triangles := make([]*spatialmath.Triangle, 0, len(fileMesh.Triangles()))
+	for _, triangle := range fileMesh.Triangles() {
+		pts := triangle.Points()
+		for i, pt := range pts {
+			pts[i] = r3.Vector{X: pt.X * scale.X, Y: pt.Y * scale.Y, Z: pt.Z * scale.Z}
+		}
+		triangles = append(triangles, spatialmath.NewTriangle(pts[0], pts[1], pts[2]))
+	}
+	return spatialmath.NewMesh(pose, triangles, ""), nil
+}
+
+// resolveMeshPath finds the file of a mesh path of a URDF in the directory `dir`. Relative paths are relative to the
+// URDF, and the packages of "package://" paths are looked for among the ancestors of the URDF's directory and then
+// in the directories of ROS_PACKAGE_PATH.
+func resolveMeshPath(filename, dir string) (string, error) {
+	if path, ok := strings.CutPrefix(filename, "file://"); ok {
+		return path, nil
+	}
+	rest, ok := strings.CutPrefix(filename, "package://")
+	if !ok {
+		if filepath.IsAbs(filename) {
+			return filename, nil
+		}
+		return filepath.Join(dir, filename), nil
+	}
+	pkg, rest, _ := strings.Cut(rest, "/")
+	var candidates []string
+	for ancestor := dir; ; ancestor = filepath.Dir(ancestor) {
+		if filepath.Base(ancestor) == pkg {
+			candidates = append(candidates, filepath.Join(ancestor, rest))
+		}
+		candidates = append(candidates, filepath.Join(ancestor, pkg, rest))
+		if filepath.Dir(ancestor) == ancestor {
+			break
+		}
+	}
+	for _, root := range filepath.SplitList(os.Getenv(rosPackagePathEnv)) {
+		if root != "" {
+			candidates = append(candidates, filepath.Join(root, pkg, rest))
+		}
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", errors.Errorf("could not find mesh %q near the URDF or in %s", filename, rosPackagePathEnv)
+}
@@ -5,8 +5,9 @@ import (
 	"encoding/xml"
 	"math"
 	"os"
+	"path/filepath"
+	"strings"
 
-	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/referenceframe"
@@ -42,6 +43,9 @@ type joint struct {
 	Origin  *pose    `xml:"origin,omitempty"`
 	Axis    *axis    `xml:"axis,omitempty"`
 	Limit   *limit   `xml:"limit,omitempty"`
+	Mimic   *mimic   `xml:"mimic,omitempty"`
+
+	SafetyController *safetyController `xml:"safety_controller,omitempty"`
 }
 
 // NewModelFromWorldState creates a urdf.Config struct which can be marshalled into xml and will be a
@@ -80,8 +84,14 @@ func NewModelFromWorldState(ws *referenceframe.WorldState, name string) (*ModelC
 
 // UnmarshalModelXML will transfer the given URDF XML data into an equivalent ModelConfig. Direct unmarshaling in the
 // same fashion as ModelJSON is not possible, as URDF data will need to be evaluated to accommodate differences
-// between the two kinematics encoding schemes.
+// between the two kinematics encoding schemes. As the mesh files of mesh collisions cannot be found from the data
+// alone, links whose collisions are meshes have no geometry; use ParseModelXMLFile to read them.
 func UnmarshalModelXML(xmlData []byte, modelName string) (*referenceframe.ModelConfig, error) {
+	return unmarshalModelXML(xmlData, modelName, "")
+}
+
+// unmarshalModelXML converts URDF XML data into a ModelConfig, looking for its meshes relative to `dir`.
+func unmarshalModelXML(xmlData []byte, modelName, dir string) (*referenceframe.ModelConfig, error) {
 	// Unmarshal into a URDF ModelConfig
 	urdf := &ModelConfig{}
 	err := xml.Unmarshal(xmlData, urdf)
@@ -103,23 +113,36 @@ func UnmarshalModelXML(xmlData []byte, modelName string) (*referenceframe.ModelC
 		}
 
 		link := &referenceframe.LinkConfig{ID: linkElem.Name}
-		if len(linkElem.Collision) > 0 {
-			geometry, err := linkElem.Collision[0].toGeometry()
-			if err != nil {
-				return nil, err
-			}
-			geoCfg, err := spatialmath.NewGeometryConfig(geometry)
+		geometry, err := linkGeometry(linkElem.Collision, dir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "link %q", linkElem.Name)
+		}
+		if geometry != nil {
+			link.Geometry, err = spatialmath.NewGeometryConfig(geometry)
 			if err != nil {
 				return nil, err
 			}
-			link.Geometry = geoCfg
 		}
 		links[linkElem.Name] = link
 	}
 
 	// Read the joints next
+	jointTypes := make(map[string]string, len(urdf.Joints))
+	for _, jointElem := range urdf.Joints {
+		jointTypes[jointElem.Name] = jointElem.Type
+	}
 	joints := make([]referenceframe.JointConfig, 0)
 	for _, jointElem := range urdf.Joints {
+		// Generate child link translation and orientation data, which is held by this joint per the URDF design
+		childXYZ, childRPY, err := jointElem.Origin.parts()
+		if err != nil {
+			return nil, errors.Wrapf(err, "joint %q", jointElem.Name)
+		}
+		childOrient, err := spatialmath.NewOrientationConfig(childRPY)
+		if err != nil {
+			return nil, err
+		}
+
 		switch jointElem.Type {
 		case referenceframe.ContinuousJoint, referenceframe.RevoluteJoint, referenceframe.PrismaticJoint:
 			// Parse important details about each joint, including axes and limits
@@ -128,65 +151,55 @@ func UnmarshalModelXML(xmlData []byte, modelName string) (*referenceframe.ModelC
 				Type:   jointElem.Type,
 				Parent: jointElem.Parent.Link,
 			}
-			if jointElem.Axis != nil {
-				thisJoint.Axis = jointElem.Axis.Parse()
+			thisJoint.Axis, err = jointElem.Axis.Parse()
+			if err != nil {
+				return nil, errors.Wrapf(err, "joint %q", jointElem.Name)
 			}
 
 			// Slightly different limits handling for continuous, revolute, and prismatic joints
-			switch jointElem.Type {
-			case referenceframe.ContinuousJoint:
+			if jointElem.Type == referenceframe.ContinuousJoint {
 				thisJoint.Type = referenceframe.RevoluteJoint // Currently, we treate a continuous joint as a special case of a revolute joint
 				thisJoint.Min, thisJoint.Max = math.Inf(-1), math.Inf(1)
-			case referenceframe.PrismaticJoint:
-				thisJoint.Min, thisJoint.Max = utils.MetersToMM(jointElem.Limit.Lower), utils.MetersToMM(jointElem.Limit.Upper)
-			case referenceframe.RevoluteJoint:
-				thisJoint.Min, thisJoint.Max = utils.RadToDeg(jointElem.Limit.Lower), utils.RadToDeg(jointElem.Limit.Upper)
-			default:
-				return nil, err
+			} else {
+				if jointElem.Limit == nil {
+					return nil, errors.Errorf("%s joint %q must have limits", jointElem.Type, jointElem.Name)
+				}
+				lower, upper := jointElem.Limit.bounds(jointElem.SafetyController)
+				thisJoint.Min, thisJoint.Max = toJointUnits(jointElem.Type, lower), toJointUnits(jointElem.Type, upper)
 			}
-			joints = append(joints, thisJoint)
 
-			// Generate child link translation and orientation data, which is held by this joint per the URDF design
-			childXYZ := spaceDelimitedStringToFloatSlice(jointElem.Origin.XYZ)
-			childRPY := spaceDelimitedStringToFloatSlice(jointElem.Origin.RPY)
-			childOrient, err := spatialmath.NewOrientationConfig(&spatialmath.EulerAngles{
-				Roll:  childRPY[0],
-				Pitch: childRPY[1],
-				Yaw:   childRPY[2],
-			})
-			if err != nil {
-				return nil, err
+			if jointElem.Mimic != nil {
+				leaderType, ok := jointTypes[jointElem.Mimic.Joint]
+				if !ok {
+					return nil, errors.Errorf("joint %q mimics %q, which is not a joint of the URDF", jointElem.Name, jointElem.Mimic.Joint)
+				}
+				multiplier := 1.
+				if jointElem.Mimic.Multiplier != nil {
+					multiplier = *jointElem.Mimic.Multiplier
+				}
+				// the multiplier converts the units of the joint it mimics to the units of this joint
+				thisJoint.Mimic = &referenceframe.MimicConfig{
+					Joint:      jointElem.Mimic.Joint,
+					Multiplier: toJointUnits(jointElem.Type, multiplier) / toJointUnits(leaderType, 1),
+					Offset:     toJointUnits(jointElem.Type, jointElem.Mimic.Offset),
+				}
 			}
+			joints = append(joints, thisJoint)
 
 			// Add the transformation to the parent link which should be in the map of links
 			parentLink, ok := links[jointElem.Parent.Link]
 			if !ok {
 				return nil, referenceframe.NewFrameNotInListOfTransformsError(jointElem.Parent.Link)
 			}
-			parentLink.Translation = r3.Vector{
-				X: utils.MetersToMM(childXYZ[0]),
-				Y: utils.MetersToMM(childXYZ[1]),
-				Z: utils.MetersToMM(childXYZ[2]),
-			}
+			parentLink.Translation = childXYZ
 			parentLink.Orientation = childOrient
 
 		case referenceframe.FixedJoint:
 			// Handle fixed joints by converting them to links rather than a joint
-			linkXYZ := spaceDelimitedStringToFloatSlice(jointElem.Origin.XYZ)
-			linkRPY := spaceDelimitedStringToFloatSlice(jointElem.Origin.RPY)
-			linkOrient, err := spatialmath.NewOrientationConfig(&spatialmath.EulerAngles{
-				Roll:  linkRPY[0],
-				Pitch: linkRPY[1],
-				Yaw:   linkRPY[2],
-			})
-			if err != nil {
-				return nil, err
-			}
-
 			link := &referenceframe.LinkConfig{
 				ID:          jointElem.Name,
-				Translation: r3.Vector{X: utils.MetersToMM(linkXYZ[0]), Y: utils.MetersToMM(linkXYZ[1]), Z: utils.MetersToMM(linkXYZ[2])},
-				Orientation: linkOrient,
+				Translation: childXYZ,
+				Orientation: childOrient,
 				Parent:      jointElem.Parent.Link,
 			}
 			links[jointElem.Name] = link
@@ -219,7 +232,18 @@ func UnmarshalModelXML(xmlData []byte, modelName string) (*referenceframe.ModelC
 	}, nil
 }
 
-// ParseModelXMLFile will read a given file and parse the contained URDF XML data into an equivalent Model.
+// toJointUnits converts a joint position from the units of URDF (radians or meters) to the units of joint configs
+// (degrees or mm).
+func toJointUnits(jointType string, value float64) float64 {
+	if jointType == referenceframe.PrismaticJoint {
+		return utils.MetersToMM(value)
+	}
+	return utils.RadToDeg(value)
+}
+
+// ParseModelXMLFile will read a given file and parse the contained URDF XML data into an equivalent Model. The mesh
+// files of the URDF are looked for relative to it, or, for "package://" paths, in the ancestors of its directory and
+// the directories of ROS_PACKAGE_PATH.
 func ParseModelXMLFile(filename, modelName string) (referenceframe.Model, error) {
 	//nolint:gosec
 	xmlData, err := os.ReadFile(filename)
@@ -227,10 +251,26 @@ func ParseModelXMLFile(filename, modelName string) (referenceframe.Model, error)
 		return nil, errors.Wrap(err, "failed to read URDF file")
 	}
 
-	mc, err := UnmarshalModelXML(xmlData, modelName)
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return nil, err
+	}
+	mc, err := unmarshalModelXML(xmlData, modelName, dir)
 	if err != nil {
 		return nil, err
 	}
 
 	return mc.ParseConfig(modelName)
 }
+
+// ParseModelFile reads a kinematics file in either the URDF or the JSON format, based on its extension, into a Model.
+func ParseModelFile(filename, modelName string) (referenceframe.Model, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case "." + Extension, ".xml":
+		return ParseModelXMLFile(filename, modelName)
+	case ".json":
+		return referenceframe.ParseModelJSONFile(filename, modelName)
+	default:
+		return nil, errors.New("only files with .json and .urdf file extensions are supported")
+	}
+}
@@ -2,6 +2,10 @@ package urdf
 
 import (
 	"encoding/xml"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/golang/geo/r3"
@@ -81,3 +85,108 @@ func TestWorldStateConversion(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	_ = bytes
 }
+
+func TestMeshesAndMimicJoints(t *testing.T) {
+	filename := utils.ResolveFile("referenceframe/urdf/testfiles/mimic_gripper.urdf")
+	u, err := ParseModelFile(filename, "")
+	test.That(t, err, test.ShouldBeNil)
+
+	// the finger mimics the wrist, so only the wrist is moved, within its safety controller's limits
+	test.That(t, u.DoF(), test.ShouldHaveLength, 1)
+	test.That(t, u.DoF()[0].Min, test.ShouldAlmostEqual, -1.5708)
+	test.That(t, u.DoF()[0].Max, test.ShouldAlmostEqual, 1.5708)
+
+	// the finger moves 10mm per radian of the wrist, from 5mm
+	pose, err := u.Transform([]referenceframe.Input{{0.5}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pose.Point().X, test.ShouldAlmostEqual, 10*math.Cos(0.5))
+	test.That(t, pose.Point().Y, test.ShouldAlmostEqual, 10*math.Sin(0.5))
+	test.That(t, pose.Point().Z, test.ShouldAlmostEqual, 400)
+
+	gif, err := u.Geometries([]referenceframe.Input{{0}})
+	test.That(t, err, test.ShouldBeNil)
+	geometries := map[string]spatialmath.Geometry{}
+	for _, g := range gif.Geometries() {
+		geometries[g.Label()] = g
+	}
+	test.That(t, geometries, test.ShouldHaveLength, 3)
+
+	// meshes are read from their files and scaled
+	test.That(t, geometries["mimic_gripper:base_link"], test.ShouldHaveSameTypeAs, &spatialmath.Mesh{})
+	lower, upper := meshBounds(geometries["mimic_gripper:base_link"])
+	test.That(t, spatialmath.R3VectorAlmostEqual(lower, r3.Vector{}, 1e-3), test.ShouldBeTrue)
+	test.That(t, spatialmath.R3VectorAlmostEqual(upper, r3.Vector{X: 100, Y: 200, Z: 300}, 1e-3), test.ShouldBeTrue)
+	lower, upper = meshBounds(geometries["mimic_gripper:finger_link"])
+	test.That(t, spatialmath.R3VectorAlmostEqual(lower, r3.Vector{X: 5, Y: 0, Z: 250}, 1e-3), test.ShouldBeTrue)
+	test.That(t, spatialmath.R3VectorAlmostEqual(upper, r3.Vector{X: 55, Y: 100, Z: 400}, 1e-3), test.ShouldBeTrue)
+
+	// cylinders are approximated by the capsules enclosing them
+	wrist, err := spatialmath.NewCapsule(spatialmath.NewPoseFromPoint(r3.Vector{Z: 350}), 20, 140, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.GeometriesAlmostEqual(geometries["mimic_gripper:wrist_link"], wrist), test.ShouldBeTrue)
+
+	// without the file, the meshes cannot be found, so their links have no geometry
+	xmlData, err := os.ReadFile(filename)
+	test.That(t, err, test.ShouldBeNil)
+	mc, err := UnmarshalModelXML(xmlData, "")
+	test.That(t, err, test.ShouldBeNil)
+	m, err := mc.ParseConfig("")
+	test.That(t, err, test.ShouldBeNil)
+	gif, err = m.Geometries([]referenceframe.Input{{0}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, gif.Geometries(), test.ShouldHaveLength, 1)
+
+	// errors
+	_, err = UnmarshalModelXML([]byte(strings.Replace(string(xmlData), `joint="wrist_joint"`, `joint="elbow_joint"`, 1)), "")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not a joint of the URDF")
+	_, err = UnmarshalModelXML([]byte(strings.Replace(string(xmlData), `<limit lower="0" upper="0.04" effort="10" velocity="0.1"/>`, "", 1)), "")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must have limits")
+	_, err = ParseModelFile("kinematics.yaml", "")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestConvexDecomposedMeshes(t *testing.T) {
+	// a link whose collision is made of the convex pieces of a decomposed mesh, each placed by its origin
+	dir := t.TempDir()
+	stl, err := os.ReadFile(utils.ResolveFile("referenceframe/urdf/testfiles/meshes/base.stl"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, os.WriteFile(filepath.Join(dir, "piece.stl"), stl, 0o600), test.ShouldBeNil)
+	urdf := `<robot name="decomposed">
+  <link name="base_link">
+    <collision><geometry><mesh filename="piece.stl"/></geometry></collision>
+    <collision><origin xyz="0 0 0.3"/><geometry><mesh filename="piece.stl"/></geometry></collision>
+    <collision><geometry><sphere radius="1"/></geometry></collision>
+  </link>
+</robot>`
+	filename := filepath.Join(dir, "decomposed.urdf")
+	test.That(t, os.WriteFile(filename, []byte(urdf), 0o600), test.ShouldBeNil)
+
+	m, err := ParseModelXMLFile(filename, "")
+	test.That(t, err, test.ShouldBeNil)
+	gif, err := m.Geometries(nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, gif.Geometries(), test.ShouldHaveLength, 1)
+	lower, upper := meshBounds(gif.Geometries()[0])
+	test.That(t, spatialmath.R3VectorAlmostEqual(lower, r3.Vector{}, 1e-3), test.ShouldBeTrue)
+	test.That(t, spatialmath.R3VectorAlmostEqual(upper, r3.Vector{X: 100, Y: 200, Z: 600}, 1e-3), test.ShouldBeTrue)
+
+	// the combined mesh collides with geometries where its pieces meet
+	inside, err := spatialmath.NewSphere(spatialmath.NewPoseFromPoint(r3.Vector{X: 50, Y: 100, Z: 300}), 10, "")
+	test.That(t, err, test.ShouldBeNil)
+	collides, err := gif.Geometries()[0].CollidesWith(inside, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collides, test.ShouldBeTrue)
+}
+
+// meshBounds returns the corners of the box bounding a mesh geometry.
+func meshBounds(g spatialmath.Geometry) (r3.Vector, r3.Vector) {
+	lower := r3.Vector{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	upper := r3.Vector{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	for _, v := range g.ToPoints(1) {
+		lower = r3.Vector{X: math.Min(lower.X, v.X), Y: math.Min(lower.Y, v.Y), Z: math.Min(lower.Z, v.Z)}
+		upper = r3.Vector{X: math.Max(upper.X, v.X), Y: math.Max(upper.Y, v.Y), Z: math.Max(upper.Z, v.Z)}
+	}
+	return lower, upper
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/utils"
@@ -25,12 +26,27 @@ func newPose(p spatialmath.Pose) *pose {
 	}
 }
 
-func (p *pose) Parse() spatialmath.Pose {
-	// Offset for the geometry origin from the reference link origin
-	xyz := spaceDelimitedStringToFloatSlice(p.XYZ)
-	rpy := spaceDelimitedStringToFloatSlice(p.RPY)
-	return spatialmath.NewPose(
-		r3.Vector{X: utils.MetersToMM(xyz[0]), Y: utils.MetersToMM(xyz[1]), Z: utils.MetersToMM(xyz[2])},
-		&spatialmath.EulerAngles{Roll: rpy[0], Pitch: rpy[1], Yaw: rpy[2]},
-	)
+// Parse returns the pose of the origin, which is the identity if it or any of its fields are not given.
+func (p *pose) Parse() (spatialmath.Pose, error) {
+	pt, orientation, err := p.parts()
+	if err != nil {
+		return nil, err
+	}
+	return spatialmath.NewPose(pt, orientation), nil
+}
+
+// parts returns the translation of the origin in mm and its orientation.
+func (p *pose) parts() (r3.Vector, *spatialmath.EulerAngles, error) {
+	if p == nil {
+		return r3.Vector{}, &spatialmath.EulerAngles{}, nil
+	}
+	xyz, err := parseVector(p.XYZ, r3.Vector{})
+	if err != nil {
+		return r3.Vector{}, nil, errors.Wrap(err, "origin xyz")
+	}
+	rpy, err := parseVector(p.RPY, r3.Vector{})
+	if err != nil {
+		return r3.Vector{}, nil, errors.Wrap(err, "origin rpy")
+	}
+	return xyz.Mul(1000), &spatialmath.EulerAngles{Roll: rpy.X, Pitch: rpy.Y, Yaw: rpy.Z}, nil
 }
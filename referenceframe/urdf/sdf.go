@@ -0,0 +1,143 @@
+package urdf
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+// SDFExtension is the file extension associated with SDF files.
+const SDFExtension string = "sdf"
+
+// sdfVersion is the version of SDFormat written by MarshalSDF, the first to have pose frame semantics.
+const sdfVersion = "1.7"
+
+type sdfRoot struct {
+	XMLName xml.Name `xml:"sdf"`
+	Version string   `xml:"version,attr"`
+	Model   sdfModel `xml:"model"`
+}
+
+type sdfModel struct {
+	Name   string     `xml:"name,attr"`
+	Links  []sdfLink  `xml:"link"`
+	Joints []sdfJoint `xml:"joint"`
+}
+
+type sdfPose struct {
+	RelativeTo string `xml:"relative_to,attr,omitempty"`
+	Value      string `xml:",chardata"` // "x y z roll pitch yaw" format, in meters and radians
+}
+
+type sdfLink struct {
+	Name       string         `xml:"name,attr"`
+	Pose       *sdfPose       `xml:"pose,omitempty"`
+	Collisions []sdfCollision `xml:"collision"`
+}
+
+type sdfCollision struct {
+	Name     string   `xml:"name,attr"`
+	Pose     *sdfPose `xml:"pose,omitempty"`
+	Geometry struct {
+		Box      *sdfBox      `xml:"box,omitempty"`
+		Sphere   *sdfSphere   `xml:"sphere,omitempty"`
+		Cylinder *sdfCylinder `xml:"cylinder,omitempty"`
+	} `xml:"geometry"`
+}
+
+type sdfBox struct {
+	Size string `xml:"size"` // "x y z" format, in meters
+}
+
+type sdfSphere struct {
+	Radius float64 `xml:"radius"` // in meters
+}
+
+type sdfCylinder struct {
+	Radius float64 `xml:"radius"` // in meters
+	Length float64 `xml:"length"` // in meters, along the z axis
+}
+
+type sdfJoint struct {
+	Name   string   `xml:"name,attr"`
+	Type   string   `xml:"type,attr"`
+	Parent string   `xml:"parent"`
+	Child  string   `xml:"child"`
+	Axis   *sdfAxis `xml:"axis,omitempty"`
+}
+
+type sdfAxis struct {
+	XYZ   string    `xml:"xyz"`
+	Limit *sdfLimit `xml:"limit,omitempty"`
+}
+
+type sdfLimit struct {
+	Lower float64 `xml:"lower"` // translation limits are in meters, revolute limits are in radians
+	Upper float64 `xml:"upper"`
+}
+
+// MarshalSDF returns the model as an SDFormat model, such as for Gazebo. As the frames of URDF joints coincide with
+// those of their child links, each link is posed relative to the link of its parent joint and joints are posed at
+// their child links. Mimic joints are not represented, and move on their own.
+func MarshalSDF(model *ModelConfig) ([]byte, error) {
+	parentJoints := make(map[string]joint, len(model.Joints))
+	for _, j := range model.Joints {
+		parentJoints[j.Child.Link] = j
+	}
+
+	sdf := sdfRoot{Version: sdfVersion, Model: sdfModel{Name: model.Name}}
+	for _, l := range model.Links {
+		// the world is not a link of SDF models, which are placed in it
+		if l.Name == referenceframe.World {
+			continue
+		}
+		sl := sdfLink{Name: l.Name}
+		if j, ok := parentJoints[l.Name]; ok {
+			sl.Pose = &sdfPose{Value: sdfPoseValue(j.Origin)}
+			if j.Parent.Link != referenceframe.World {
+				sl.Pose.RelativeTo = j.Parent.Link
+			}
+		}
+		for i, c := range l.Collision {
+			sc := sdfCollision{Name: fmt.Sprintf("%s_collision_%d", l.Name, i), Pose: &sdfPose{Value: sdfPoseValue(c.Origin)}}
+			switch {
+			case c.Geometry.Box != nil:
+				sc.Geometry.Box = &sdfBox{Size: c.Geometry.Box.Size}
+			case c.Geometry.Sphere != nil:
+				sc.Geometry.Sphere = &sdfSphere{Radius: c.Geometry.Sphere.Radius}
+			case c.Geometry.Cylinder != nil:
+				sc.Geometry.Cylinder = &sdfCylinder{Radius: c.Geometry.Cylinder.Radius, Length: c.Geometry.Cylinder.Length}
+			default:
+				continue
+			}
+			sl.Collisions = append(sl.Collisions, sc)
+		}
+		sdf.Model.Links = append(sdf.Model.Links, sl)
+	}
+
+	for _, j := range model.Joints {
+		sj := sdfJoint{Name: j.Name, Type: j.Type, Parent: j.Parent.Link, Child: j.Child.Link}
+		if j.Axis != nil {
+			sj.Axis = &sdfAxis{XYZ: j.Axis.XYZ}
+			if j.Limit != nil {
+				sj.Axis.Limit = &sdfLimit{Lower: j.Limit.Lower, Upper: j.Limit.Upper}
+			}
+		}
+		sdf.Model.Joints = append(sdf.Model.Joints, sj)
+	}
+
+	data, err := xml.MarshalIndent(sdf, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// sdfPoseValue returns the origin in the format of SDF poses.
+func sdfPoseValue(p *pose) string {
+	if p == nil {
+		return "0 0 0 0 0 0"
+	}
+	return p.XYZ + " " + p.RPY
+}
@@ -4,6 +4,9 @@ import (
 	"math"
 	"strconv"
 	"strings"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
 )
 
 // spaceDelimitedStringToFloatSlice is a helper method to split up space-delimited fields in a string and converts them to floats.
@@ -19,3 +22,20 @@ func spaceDelimitedStringToFloatSlice(s string) []float64 {
 	}
 	return converted
 }
+
+// parseVector parses an "x y z" field, returning the fallback if the field is empty.
+func parseVector(s string, fallback r3.Vector) (r3.Vector, error) {
+	if strings.TrimSpace(s) == "" {
+		return fallback, nil
+	}
+	values := spaceDelimitedStringToFloatSlice(s)
+	if len(values) != 3 {
+		return r3.Vector{}, errors.Errorf("%q must have three values", s)
+	}
+	for _, value := range values {
+		if math.IsNaN(value) {
+			return r3.Vector{}, errors.Errorf("%q must only have numbers", s)
+		}
+	}
+	return r3.Vector{X: values[0], Y: values[1], Z: values[2]}, nil
+}
@@ -0,0 +1,207 @@
+// Package main generates typed DoCommand commands from Go request and response structs, such that
+// module authors need not build and pick apart map[string]interface{} requests by hand.
+//
+// For each command name passed with -commands, such as SetSpeed, the package must declare the
+// struct types SetSpeedRequest and SetSpeedResponse. Their fields are encoded in DoCommand requests
+// and responses as they are encoded to JSON. The generated file declares:
+//
+//   - SetSpeedCommand, the name of the command in DoCommand requests ("set_speed").
+//   - DoSetSpeed, which sends the command to a resource, such as a client of the module's resource.
+//   - CommandHandler, an interface with a SetSpeed method the module's resource implements.
+//   - HandleCommand, which the resource's DoCommand calls to call the method a request is for.
+//
+// Requests are validated on both sides if the request type has a `Validate() error` method.
+//
+// Usage, in a file declaring the request and response types:
+//
+//	//go:generate go run go.viam.com/rdk/resource/cmd/typedcommand -commands SetSpeed,Status
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+func main() {
+	var (
+		commands = flag.String("commands", "", "comma separated names of the commands to generate")
+		prefix   = flag.String("prefix", "", "prefix of the generated CommandHandler and HandleCommand names")
+		dir      = flag.String("dir", ".", "directory of the package declaring the request and response types")
+		output   = flag.String("output", "", "file to write, by default typed_commands.go in -dir")
+	)
+	flag.Parse()
+	if *output == "" {
+		*output = filepath.Join(*dir, "typed_commands.go")
+	}
+	if err := run(*dir, *output, *prefix, strings.Split(*commands, ",")); err != nil {
+		fmt.Fprintln(os.Stderr, "typedcommand:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output, prefix string, commands []string) error {
+	pkgName, structs, err := parseStructs(dir, output)
+	if err != nil {
+		return err
+	}
+	src, err := generate(pkgName, prefix, commands, structs)
+	if err != nil {
+		return err
+	}
+	//nolint:gosec
+	return os.WriteFile(output, src, 0o644)
+}
+
+// parseStructs returns the name of the package in dir and the names of the struct types it
+// declares, ignoring the previously generated output and tests.
+func parseStructs(dir, output string) (string, map[string]bool, error) {
+	fset := token.NewFileSet()
+	outputAbs, err := filepath.Abs(output)
+	if err != nil {
+		return "", nil, err
+	}
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		path, err := filepath.Abs(filepath.Join(dir, info.Name()))
+		return err == nil && path != outputAbs && !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(pkgs) != 1 {
+		return "", nil, errors.Errorf("expected one package in %q, found %d", dir, len(pkgs))
+	}
+	structs := map[string]bool{}
+	var pkgName string
+	for name, pkg := range pkgs {
+		pkgName = name
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				if spec, ok := n.(*ast.TypeSpec); ok {
+					if _, ok := spec.Type.(*ast.StructType); ok {
+						structs[spec.Name.Name] = true
+					}
+				}
+				return true
+			})
+		}
+	}
+	return pkgName, structs, nil
+}
+
+type command struct {
+	// Name is the Go name of the command, such as SetSpeed.
+	Name string
+	// Key is the name of the command in DoCommand requests, such as set_speed.
+	Key string
+}
+
+func generate(pkgName, prefix string, names []string, structs map[string]bool) ([]byte, error) {
+	var cmds []command
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return nil, errors.Errorf("command name %q must be an exported Go identifier", name)
+		}
+		for _, suffix := range []string{"Request", "Response"} {
+			if !structs[name+suffix] {
+				return nil, errors.Errorf("command %s needs a struct type named %s%s", name, name, suffix)
+			}
+		}
+		cmds = append(cmds, command{Name: name, Key: snakeCase(name)})
+	}
+	if len(cmds) == 0 {
+		return nil, errors.New("no commands given with -commands")
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, struct {
+		Package  string
+		Prefix   string
+		Commands []command
+	}{pkgName, prefix, cmds}); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// snakeCase returns the snake case of a Go identifier, such as "set_speed" for "SetSpeed" and
+// "get_gps_fix" for "GetGPSFix".
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// A word starts at an upper case letter after a lower case one, or at the last upper case
+			// letter of an acronym followed by a lower case one.
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var fileTemplate = template.Must(template.New("typed_commands").Parse(`// Code generated by typedcommand. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The names of the typed commands in DoCommand requests.
+const (
+{{- range .Commands}}
+	{{.Name}}Command = "{{.Key}}"
+{{- end}}
+)
+{{range .Commands}}
+// Do{{.Name}} sends a {{.Name}} command to res over DoCommand.
+func Do{{.Name}}(ctx context.Context, res resource.DoCommander, req {{.Name}}Request) ({{.Name}}Response, error) {
+	var resp {{.Name}}Response
+	err := resource.DoTypedCommand(ctx, res, {{.Name}}Command, &req, &resp)
+	return resp, err
+}
+{{end}}
+// {{.Prefix}}CommandHandler handles the typed commands sent to a resource over DoCommand.
+type {{.Prefix}}CommandHandler interface {
+{{- range .Commands}}
+	{{.Name}}(ctx context.Context, req {{.Name}}Request) ({{.Name}}Response, error)
+{{- end}}
+}
+
+// Handle{{.Prefix}}Command calls the method of h for the typed command a DoCommand request is for.
+func Handle{{.Prefix}}Command(
+	ctx context.Context,
+	h {{.Prefix}}CommandHandler,
+	cmd map[string]interface{},
+) (map[string]interface{}, error) {
+	switch cmd[resource.TypedCommandKey] {
+{{- range .Commands}}
+	case {{.Name}}Command:
+		return resource.HandleTypedCommand(ctx, cmd, h.{{.Name}})
+{{- end}}
+	default:
+		return nil, resource.NewUnknownTypedCommandError(cmd)
+	}
+}
+`))
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"SetSpeed":  "set_speed",
+		"Status":    "status",
+		"GetGPSFix": "get_gps_fix",
+		"ReadADC":   "read_adc",
+	} {
+		test.That(t, snakeCase(name), test.ShouldEqual, expected)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	src := `package mymodule
+
+type SetSpeedRequest struct {
+	RPM float64 ` + "`json:\"rpm\"`" + `
+}
+
+type SetSpeedResponse struct{}
+`
+	test.That(t, os.WriteFile(filepath.Join(dir, "commands.go"), []byte(src), 0o600), test.ShouldBeNil)
+	output := filepath.Join(dir, "typed_commands.go")
+
+	err := run(dir, output, "", []string{"SetSpeed"})
+	test.That(t, err, test.ShouldBeNil)
+	generated, err := os.ReadFile(output)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(generated), test.ShouldContainSubstring, "package mymodule")
+	test.That(t, string(generated), test.ShouldContainSubstring, `SetSpeedCommand = "set_speed"`)
+	test.That(t, string(generated), test.ShouldContainSubstring, "func DoSetSpeed(")
+	test.That(t, string(generated), test.ShouldContainSubstring, "func HandleCommand(")
+
+	// the previous output is ignored when generating again
+	test.That(t, run(dir, output, "", []string{"SetSpeed"}), test.ShouldBeNil)
+
+	err = run(dir, output, "", []string{"Stop"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "StopRequest")
+}
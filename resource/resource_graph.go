@@ -30,6 +30,10 @@ type Graph struct {
 	// (the resource updates), the logicalClock is incremented.
 	logicalClock *atomic.Int64
 	ftdc         *ftdc.FTDC
+	// published is a copy of nodes that is replaced, never changed, whenever a node is added or
+	// removed. Lookups read it without taking mu, such that API calls looking up resources do not
+	// wait on reconfiguration, which holds mu for long stretches.
+	published atomic.Pointer[graphNodes]
 }
 
 // NewGraph creates a new resource graph.
@@ -128,13 +132,30 @@ func (g *Graph) Clone() *Graph {
 }
 
 func (g *Graph) clone() *Graph {
-	return &Graph{
+	cloned := &Graph{
 		children:                copyNodeMap(g.children),
 		nodes:                   copyNodes(g.nodes),
 		parents:                 copyNodeMap(g.parents),
 		transitiveClosureMatrix: copyTransitiveClosureMatrix(g.transitiveClosureMatrix),
 		logicalClock:            g.logicalClock,
 	}
+	cloned.publishNodes()
+	return cloned
+}
+
+// publishNodes publishes a copy of the nodes for lookups. A client must hold [Graph.mu] while
+// calling this method.
+func (g *Graph) publishNodes() {
+	nodes := copyNodes(g.nodes)
+	g.published.Store(&nodes)
+}
+
+// publishedNodes returns the nodes last published, which must not be modified.
+func (g *Graph) publishedNodes() graphNodes {
+	if nodes := g.published.Load(); nodes != nil {
+		return *nodes
+	}
+	return nil
 }
 
 func addResToSet(rd resourceDependencies, key, node Name) {
@@ -171,21 +192,18 @@ func (g *Graph) AddNode(node Name, nodeVal *GraphNode) error {
 	return g.addNode(node, nodeVal)
 }
 
-// Node returns the node named name.
+// Node returns the node named name. It does not wait on changes to the graph.
 func (g *Graph) Node(node Name) (*GraphNode, bool) {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-	rNode, ok := g.nodes[node]
+	rNode, ok := g.publishedNodes()[node]
 	return rNode, ok
 }
 
-// Names returns the all resource graph names.
+// Names returns the all resource graph names. It does not wait on changes to the graph.
 func (g *Graph) Names() []Name {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-	names := make([]Name, len(g.nodes))
+	nodes := g.publishedNodes()
+	names := make([]Name, len(nodes))
 	i := 0
-	for k := range g.nodes {
+	for k := range nodes {
 		names[i] = k
 		i++
 	}
@@ -208,12 +226,11 @@ func (g *Graph) ReachableNames() []Name {
 	return names
 }
 
-// FindNodesByShortNameAndAPI will look for resources matching both the API and the name.
+// FindNodesByShortNameAndAPI will look for resources matching both the API and the name. It does
+// not wait on changes to the graph.
 func (g *Graph) FindNodesByShortNameAndAPI(name Name) []Name {
-	g.mu.Lock()
-	defer g.mu.Unlock()
 	var ret []Name
-	for k, v := range g.nodes {
+	for k, v := range g.publishedNodes() {
 		if name.Name == k.Name && name.API == k.API && v != nil {
 			ret = append(ret, k)
 		}
@@ -221,12 +238,10 @@ func (g *Graph) FindNodesByShortNameAndAPI(name Name) []Name {
 	return ret
 }
 
-// FindNodesByAPI finds nodes with the given API.
+// FindNodesByAPI finds nodes with the given API. It does not wait on changes to the graph.
 func (g *Graph) FindNodesByAPI(api API) []Name {
-	g.mu.Lock()
-	defer g.mu.Unlock()
 	var ret []Name
-	for k := range g.nodes {
+	for k := range g.publishedNodes() {
 		if k.API == api {
 			ret = append(ret, k)
 		}
@@ -289,6 +304,7 @@ func (g *Graph) addNode(node Name, nodeVal *GraphNode) error {
 		g.ftdc.Add(node.String(), nodeVal)
 	}
 	g.nodes[node] = nodeVal
+	g.publishNodes()
 
 	if _, ok := g.transitiveClosureMatrix[node]; !ok {
 		g.transitiveClosureMatrix[node] = map[Name]int{}
@@ -384,6 +400,7 @@ func (g *Graph) remove(node Name) {
 	delete(g.parents, node)
 	delete(g.children, node)
 	delete(g.nodes, node)
+	g.publishNodes()
 	if g.ftdc != nil {
 		g.ftdc.Remove(node.String())
 	}
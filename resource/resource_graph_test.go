@@ -688,6 +688,44 @@ func TestResourceGraphFindNodeByName(t *testing.T) {
 	test.That(t, names, test.ShouldHaveLength, 0)
 }
 
+func TestResourceGraphLookupsDuringChanges(t *testing.T) {
+	g := NewGraph()
+	nameA := NewName(apiA, "A")
+	nameB := NewName(apiA, "B")
+	test.That(t, g.AddNode(nameA, &GraphNode{}), test.ShouldBeNil)
+
+	// lookups do not wait on changes holding the lock, such as a reconfiguration
+	g.mu.Lock()
+	looked := make(chan struct{})
+	go func() {
+		defer close(looked)
+		_, ok := g.Node(nameA)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, g.Names(), test.ShouldResemble, []Name{nameA})
+		test.That(t, g.FindNodesByAPI(apiA), test.ShouldResemble, []Name{nameA})
+		test.That(t, g.FindNodesByShortNameAndAPI(nameA), test.ShouldResemble, []Name{nameA})
+	}()
+	select {
+	case <-looked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lookup waited on the graph lock")
+	}
+	g.mu.Unlock()
+
+	// changes are seen by the next lookup
+	test.That(t, g.AddNode(nameB, &GraphNode{}), test.ShouldBeNil)
+	_, ok := g.Node(nameB)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, g.Names(), test.ShouldHaveLength, 2)
+
+	cloned := g.Clone()
+	g.remove(nameB)
+	_, ok = g.Node(nameB)
+	test.That(t, ok, test.ShouldBeFalse)
+	_, ok = cloned.Node(nameB)
+	test.That(t, ok, test.ShouldBeTrue)
+}
+
 var cfgA = []fakeComponent{
 	{
 		Name:      NewName(apiA, "A"),
@@ -0,0 +1,109 @@
+package resource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+
+	"github.com/pkg/errors"
+)
+
+// TypedCommandKey is the key of a DoCommand request naming the typed command it is for. The other
+// keys of the request are the fields of the command's request type.
+const TypedCommandKey = "command"
+
+// A DoCommander is anything DoCommand requests can be sent to, such as a Resource.
+type DoCommander interface {
+	DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
+}
+
+// DoTypedCommand sends the typed command with the given name and request to res over DoCommand,
+// and decodes the response into resp, which must be a pointer. The request is validated first if it
+// has a `Validate() error` method. Code calling it is usually generated by typedcommand.
+func DoTypedCommand(ctx context.Context, res DoCommander, name string, req, resp interface{}) error {
+	if err := validateTypedCommand(req); err != nil {
+		return errors.Wrapf(err, "invalid %q command", name)
+	}
+	cmd, err := typedCommandToMap(req)
+	if err != nil {
+		return err
+	}
+	cmd[TypedCommandKey] = name
+	out, err := res.DoCommand(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	// Fields unknown to this client are ignored, such that servers can add fields to responses.
+	if err := typedCommandFromMap(out, resp, false); err != nil {
+		return errors.Wrapf(err, "invalid %q command response", name)
+	}
+	return nil
+}
+
+// HandleTypedCommand decodes a DoCommand request for a typed command, calls handle with it, and
+// encodes the response handle returns. Requests with fields the request type does not have are
+// rejected, and requests are validated if the request type has a `Validate() error` method. Code
+// calling it is usually generated by typedcommand.
+func HandleTypedCommand[Req, Resp any](
+	ctx context.Context,
+	cmd map[string]interface{},
+	handle func(ctx context.Context, req Req) (Resp, error),
+) (map[string]interface{}, error) {
+	name := cmd[TypedCommandKey]
+	fields := maps.Clone(cmd)
+	delete(fields, TypedCommandKey)
+	var req Req
+	if err := typedCommandFromMap(fields, &req, true); err != nil {
+		return nil, errors.Wrapf(err, "invalid %q command", name)
+	}
+	if err := validateTypedCommand(&req); err != nil {
+		return nil, errors.Wrapf(err, "invalid %q command", name)
+	}
+	resp, err := handle(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return typedCommandToMap(resp)
+}
+
+// NewUnknownTypedCommandError returns an error for a DoCommand request for a typed command that is
+// not handled.
+func NewUnknownTypedCommandError(cmd map[string]interface{}) error {
+	name, ok := cmd[TypedCommandKey]
+	if !ok {
+		return errors.Errorf("missing %q in command", TypedCommandKey)
+	}
+	return errors.Errorf("unknown command %v", name)
+}
+
+func validateTypedCommand(req interface{}) error {
+	if v, ok := req.(interface{ Validate() error }); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+func typedCommandToMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func typedCommandFromMap(m map[string]interface{}, v interface{}, strict bool) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	return dec.Decode(v)
+}
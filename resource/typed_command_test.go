@@ -0,0 +1,85 @@
+package resource_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+)
+
+type setSpeedRequest struct {
+	RPM float64 `json:"rpm"`
+}
+
+func (req *setSpeedRequest) Validate() error {
+	if req.RPM < 0 {
+		return errors.New("rpm cannot be negative")
+	}
+	return nil
+}
+
+type setSpeedResponse struct {
+	PreviousRPM float64 `json:"previous_rpm"`
+}
+
+type doCommandFunc func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
+
+func (f doCommandFunc) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return f(ctx, cmd)
+}
+
+func TestTypedCommand(t *testing.T) {
+	var rpm float64
+	res := doCommandFunc(func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		switch cmd[resource.TypedCommandKey] {
+		case "set_speed":
+			return resource.HandleTypedCommand(ctx, cmd, func(ctx context.Context, req setSpeedRequest) (setSpeedResponse, error) {
+				resp := setSpeedResponse{PreviousRPM: rpm}
+				rpm = req.RPM
+				return resp, nil
+			})
+		default:
+			return nil, resource.NewUnknownTypedCommandError(cmd)
+		}
+	})
+
+	var resp setSpeedResponse
+	err := resource.DoTypedCommand(context.Background(), res, "set_speed", &setSpeedRequest{RPM: 10}, &resp)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rpm, test.ShouldEqual, 10)
+	test.That(t, resp.PreviousRPM, test.ShouldEqual, 0)
+
+	err = resource.DoTypedCommand(context.Background(), res, "set_speed", &setSpeedRequest{RPM: 20}, &resp)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.PreviousRPM, test.ShouldEqual, 10)
+
+	t.Run("invalid requests", func(t *testing.T) {
+		err := resource.DoTypedCommand(context.Background(), res, "set_speed", &setSpeedRequest{RPM: -1}, &resp)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "negative")
+
+		_, err = res.DoCommand(context.Background(), map[string]interface{}{resource.TypedCommandKey: "set_speed", "rpm": -1})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "negative")
+
+		_, err = res.DoCommand(context.Background(), map[string]interface{}{resource.TypedCommandKey: "set_speed", "rmp": 1})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "unknown field")
+
+		_, err = res.DoCommand(context.Background(), map[string]interface{}{resource.TypedCommandKey: "set_speed", "rpm": "fast"})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("unknown commands", func(t *testing.T) {
+		err := resource.DoTypedCommand(context.Background(), res, "stop", &setSpeedRequest{}, &resp)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `unknown command stop`)
+
+		_, err = res.DoCommand(context.Background(), map[string]interface{}{})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "missing")
+	})
+}
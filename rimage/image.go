@@ -189,3 +189,36 @@ func NewH264Image(bytes []byte, width, height, gopSize int) image.Image {
 		GOPSize: gopSize,
 	}
 }
+
+// H265 is an image.Image that holds an H265 encoded frame.
+type H265 struct {
+	Bytes   []byte
+	Width   int
+	Height  int
+	GOPSize int
+}
+
+// ColorModel unimplemented.
+func (h H265) ColorModel() color.Model {
+	panic("not implemented")
+}
+
+// Bounds unimplemented.
+func (h H265) Bounds() image.Rectangle {
+	panic("not implemented")
+}
+
+// At unimplemented.
+func (h H265) At(x, y int) color.Color {
+	panic("not implemented")
+}
+
+// NewH265Image returns a new image.Image from the given bytes and stores the given parameters.
+func NewH265Image(bytes []byte, width, height, gopSize int) image.Image {
+	return H265{
+		Bytes:   bytes,
+		Width:   width,
+		Height:  height,
+		GOPSize: gopSize,
+	}
+}
@@ -316,47 +316,67 @@ func EncodeImage(ctx context.Context, img image.Image, mimeType string) ([]byte,
 		}
 		return EncodeImage(ctx, lazy.decodedImage, actualOutMIME)
 	}
-	var buf bytes.Buffer
+	buf := getEncodeBuffer()
+	defer putEncodeBuffer(buf)
 	switch actualOutMIME {
 	case ut.MimeTypeRawDepth:
-		if _, err := WriteViamDepthMapTo(img, &buf); err != nil {
+		if _, err := WriteViamDepthMapTo(img, buf); err != nil {
 			return nil, err
 		}
 	case ut.MimeTypeRawRGBA:
-		// Here we create a custom header to prepend to Raw RGBA data. Credit to
-		// Ben Zotto for inventing this formulation
-		// https://bzotto.medium.com/introducing-the-rgba-bitmap-file-format-4a8a94329e2c
-		buf.Write(RGBABitmapMagicNumber)
-		widthBytes := make([]byte, 4)
-		heightBytes := make([]byte, 4)
-		bounds := img.Bounds()
-		binary.BigEndian.PutUint32(widthBytes, uint32(bounds.Dx()))
-		binary.BigEndian.PutUint32(heightBytes, uint32(bounds.Dy()))
-		buf.Write(widthBytes)
-		buf.Write(heightBytes)
-		imgStruct := image.NewNRGBA(bounds)
-		draw.Draw(imgStruct, bounds, img, bounds.Min, draw.Src)
-		buf.Write(imgStruct.Pix)
+		writeRawRGBA(buf, img)
 	case ut.MimeTypePNG:
-		if err := png.Encode(&buf, img); err != nil {
+		if err := pngEncoder.Encode(buf, img); err != nil {
 			return nil, err
 		}
 	case ut.MimeTypeJPEG:
-		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 75}); err != nil {
+		if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: 75}); err != nil {
 			return nil, err
 		}
 	case ut.MimeTypeQOI:
-		if err := qoi.Encode(&buf, img); err != nil {
+		if err := qoi.Encode(buf, img); err != nil {
 			return nil, err
 		}
 	case ut.MimeTypeH264:
 		frame := img.(H264)
-		buf.Write(frame.Bytes)
+		return frame.Bytes, nil
+	case ut.MimeTypeH265:
+		frame := img.(H265)
+		return frame.Bytes, nil
 	default:
 		return nil, errors.Errorf("do not know how to encode %q", actualOutMIME)
 	}
 
-	return buf.Bytes(), nil
+	// The buffer goes back to the pool, so the encoding is copied out of it once at its exact size.
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// writeRawRGBA writes img to buf as raw RGBA data, with a custom header prepended to it. Credit to
+// Ben Zotto for inventing this formulation
+// https://bzotto.medium.com/introducing-the-rgba-bitmap-file-format-4a8a94329e2c
+func writeRawRGBA(buf *bytes.Buffer, img image.Image) {
+	bounds := img.Bounds()
+	var header [RawRGBAHeaderLength]byte
+	copy(header[:], RGBABitmapMagicNumber)
+	binary.BigEndian.PutUint32(header[4:8], uint32(bounds.Dx()))
+	binary.BigEndian.PutUint32(header[8:12], uint32(bounds.Dy()))
+	buf.Write(header[:])
+
+	rowLen := 4 * bounds.Dx()
+	if nrgba, ok := img.(*image.NRGBA); ok {
+		// The pixels are already in the encoded format, so they are copied as they are.
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			i := nrgba.PixOffset(bounds.Min.X, y)
+			buf.Write(nrgba.Pix[i : i+rowLen])
+		}
+		return
+	}
+	// Otherwise the image is drawn straight into the free space of the buffer.
+	n := rowLen * bounds.Dy()
+	buf.Grow(n)
+	pix := buf.AvailableBuffer()[:n]
+	draw.Draw(&image.NRGBA{Pix: pix, Stride: rowLen, Rect: bounds}, bounds, img, bounds.Min, draw.Src)
+	buf.Write(pix)
 }
 
 func fastConvertNRGBA(dst *Image, src *image.NRGBA) {
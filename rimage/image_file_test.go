@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"image"
+	"image/color"
 	"image/jpeg"
 	"image/png"
 	"os"
@@ -150,3 +151,79 @@ func TestRawDepthEncodingDecoding(t *testing.T) {
 	test.That(t, decodedDm.GetDepth(2, 3), test.ShouldEqual, img.GetDepth(2, 3))
 	test.That(t, decodedDm.GetDepth(1, 0), test.ShouldEqual, img.GetDepth(1, 0))
 }
+
+func TestRawRGBAEncodingFromOtherImages(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 8))
+	img.Set(3, 3, Red)
+	want, err := EncodeImage(context.Background(), img, utils.MimeTypeRawRGBA)
+	test.That(t, err, test.ShouldBeNil)
+
+	// a sub image has the stride of its parent
+	parent := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	parent.Set(5, 5, Red)
+	sub := parent.SubImage(image.Rect(2, 2, 6, 10))
+	encoded, err := EncodeImage(context.Background(), sub, utils.MimeTypeRawRGBA)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, encoded, test.ShouldResemble, want)
+
+	// other images are converted
+	rgba := image.NewRGBA(image.Rect(0, 0, 4, 8))
+	rgba.Set(3, 3, Red)
+	encoded, err = EncodeImage(context.Background(), rgba, utils.MimeTypeRawRGBA)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, encoded, test.ShouldResemble, want)
+
+	// encodings do not share the pooled buffers they are encoded in
+	_, err = EncodeImage(context.Background(), image.NewRGBA(image.Rect(0, 0, 4, 8)), utils.MimeTypeRawRGBA)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, encoded, test.ShouldResemble, want)
+}
+
+func TestNewPooledRGBA(t *testing.T) {
+	img, release := NewPooledRGBA(image.Rect(0, 0, 4, 8))
+	test.That(t, img.Bounds(), test.ShouldResemble, image.Rect(0, 0, 4, 8))
+	test.That(t, img.Pix, test.ShouldHaveLength, 4*4*8)
+	img.Set(3, 3, Red)
+	test.That(t, img.At(3, 3), test.ShouldResemble, color.RGBA{R: 255, A: 255})
+	release()
+
+	img, release = NewPooledRGBA(image.Rect(0, 0, 2, 2))
+	defer release()
+	test.That(t, img.Pix, test.ShouldHaveLength, 4*2*2)
+	test.That(t, img.Stride, test.ShouldEqual, 8)
+}
+
+// benchmarkEncodeFrames encodes a 640x480 frame, as a camera streaming at 30fps does 30 times a
+// second. The allocations reported are per frame.
+func benchmarkEncodeFrames(b *testing.B, img image.Image, mimeType string) {
+	b.Helper()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := EncodeImage(context.Background(), img, mimeType); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkFrame() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	for y := 0; y < 480; y++ {
+		for x := 0; x < 640; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: uint8(x + y), A: 255})
+		}
+	}
+	return img
+}
+
+func BenchmarkEncodeJPEG(b *testing.B) {
+	benchmarkEncodeFrames(b, benchmarkFrame(), utils.MimeTypeJPEG)
+}
+
+func BenchmarkEncodePNG(b *testing.B) {
+	benchmarkEncodeFrames(b, benchmarkFrame(), utils.MimeTypePNG)
+}
+
+func BenchmarkEncodeRawRGBA(b *testing.B) {
+	benchmarkEncodeFrames(b, benchmarkFrame(), utils.MimeTypeRawRGBA)
+}
@@ -0,0 +1,128 @@
+package rimage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/jpeg"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	aviHasIndex     = 0x10
+	aviKeyFrame     = 0x10
+	aviMainHeader   = 56
+	aviStreamHeader = 56
+	aviBitmapHeader = 40
+	aviIndexEntry   = 16
+)
+
+// WriteMJPEGAVI writes the JPEG encoded frames as a motion JPEG video in an AVI container, which
+// most video players can play. All frames must be the same size as the first one.
+func WriteMJPEGAVI(w io.Writer, frames [][]byte, frameRate float64) error {
+	if len(frames) == 0 {
+		return errors.New("a video needs at least one frame")
+	}
+	if frameRate <= 0 {
+		return errors.Errorf("frame rate must be positive, got %v", frameRate)
+	}
+	config, err := jpeg.DecodeConfig(bytes.NewReader(frames[0]))
+	if err != nil {
+		return errors.Wrap(err, "first frame is not a JPEG")
+	}
+
+	var maxFrameSize, moviSize int
+	for _, frame := range frames {
+		maxFrameSize = max(maxFrameSize, len(frame))
+		moviSize += 8 + len(frame) + len(frame)%2
+	}
+	// the frame rate is stored as a ratio of dwRate/dwScale frames per second
+	const scale = 1000
+	rate := uint32(math.Round(frameRate * scale))
+	width, height := uint32(config.Width), uint32(config.Height)
+
+	var b bytes.Buffer
+	le := func(vs ...interface{}) {
+		for _, v := range vs {
+			//nolint:errcheck
+			binary.Write(&b, binary.LittleEndian, v)
+		}
+	}
+	fourcc := func(s string) { b.WriteString(s) }
+
+	hdrlSize := 4 + (8 + aviMainHeader) + (8 + 4 + (8 + aviStreamHeader) + (8 + aviBitmapHeader))
+	riffSize := 4 + (8 + hdrlSize) + (8 + 4 + moviSize) + (8 + aviIndexEntry*len(frames))
+
+	fourcc("RIFF")
+	le(uint32(riffSize))
+	fourcc("AVI ")
+
+	fourcc("LIST")
+	le(uint32(hdrlSize))
+	fourcc("hdrl")
+	fourcc("avih")
+	le(uint32(aviMainHeader),
+		uint32(math.Round(1e6/frameRate)), // microseconds per frame
+		uint32(float64(maxFrameSize)*frameRate),
+		uint32(0), // padding granularity
+		uint32(aviHasIndex),
+		uint32(len(frames)),
+		uint32(0), // initial frames
+		uint32(1), // streams
+		uint32(maxFrameSize),
+		width, height,
+		[4]uint32{})
+
+	fourcc("LIST")
+	le(uint32(4 + (8 + aviStreamHeader) + (8 + aviBitmapHeader)))
+	fourcc("strl")
+	fourcc("strh")
+	le(uint32(aviStreamHeader))
+	fourcc("vids")
+	fourcc("MJPG")
+	le(uint32(0), // flags
+		uint16(0), uint16(0), // priority, language
+		uint32(0), // initial frames
+		uint32(scale), rate,
+		uint32(0), // start
+		uint32(len(frames)),
+		uint32(maxFrameSize),
+		int32(-1), // default quality
+		uint32(0), // sample size, which varies
+		[4]int16{0, 0, int16(width), int16(height)})
+	fourcc("strf")
+	le(uint32(aviBitmapHeader),
+		uint32(aviBitmapHeader), int32(width), int32(height),
+		uint16(1), uint16(24)) // planes, bits per pixel
+	fourcc("MJPG")
+	le(width*height*3,
+		[4]uint32{}) // pixels per meter and colors
+
+	fourcc("LIST")
+	le(uint32(4 + moviSize))
+	fourcc("movi")
+	offsets := make([]uint32, 0, len(frames))
+	offset := uint32(4)
+	for _, frame := range frames {
+		offsets = append(offsets, offset)
+		fourcc("00dc")
+		le(uint32(len(frame)))
+		b.Write(frame)
+		if len(frame)%2 == 1 {
+			b.WriteByte(0)
+		}
+		offset += uint32(8 + len(frame) + len(frame)%2)
+	}
+
+	fourcc("idx1")
+	le(uint32(aviIndexEntry * len(frames)))
+	for i, frame := range frames {
+		fourcc("00dc")
+		le(uint32(aviKeyFrame), offsets[i], uint32(len(frame)))
+	}
+
+	_, err = w.Write(b.Bytes())
+	return err
+}
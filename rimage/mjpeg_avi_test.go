@@ -0,0 +1,61 @@
+package rimage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestWriteMJPEGAVI(t *testing.T) {
+	var frames [][]byte
+	for i := 0; i < 3; i++ {
+		img := image.NewGray(image.Rect(0, 0, 16, 8))
+		for j := range img.Pix {
+			img.Pix[j] = uint8(j * i * 37)
+		}
+		var buf bytes.Buffer
+		test.That(t, jpeg.Encode(&buf, img, nil), test.ShouldBeNil)
+		frames = append(frames, buf.Bytes())
+	}
+
+	var out bytes.Buffer
+	test.That(t, WriteMJPEGAVI(&out, frames, 12.5), test.ShouldBeNil)
+	avi := out.Bytes()
+	u32 := func(offset int) uint32 { return binary.LittleEndian.Uint32(avi[offset:]) }
+
+	test.That(t, string(avi[:4]), test.ShouldEqual, "RIFF")
+	test.That(t, int(u32(4)), test.ShouldEqual, len(avi)-8)
+	test.That(t, string(avi[8:12]), test.ShouldEqual, "AVI ")
+
+	// the main header has the frame count and size
+	avih := bytes.Index(avi, []byte("avih")) + 8
+	test.That(t, u32(avih), test.ShouldEqual, 80000)
+	test.That(t, u32(avih+16), test.ShouldEqual, 3)
+	test.That(t, u32(avih+32), test.ShouldEqual, 16)
+	test.That(t, u32(avih+36), test.ShouldEqual, 8)
+	strh := bytes.Index(avi, []byte("strh")) + 8
+	test.That(t, string(avi[strh:strh+8]), test.ShouldEqual, "vidsMJPG")
+	test.That(t, float64(u32(strh+24))/float64(u32(strh+20)), test.ShouldEqual, 12.5)
+
+	// the index points at each frame relative to the movi list
+	movi := bytes.Index(avi, []byte("movi"))
+	idx1 := bytes.Index(avi, []byte("idx1"))
+	test.That(t, int(u32(movi-4)), test.ShouldEqual, idx1-movi)
+	test.That(t, int(u32(idx1+4)), test.ShouldEqual, 16*len(frames))
+	for i, frame := range frames {
+		entry := idx1 + 8 + 16*i
+		test.That(t, string(avi[entry:entry+4]), test.ShouldEqual, "00dc")
+		offset, size := int(u32(entry+8)), int(u32(entry+12))
+		test.That(t, size, test.ShouldEqual, len(frame))
+		test.That(t, string(avi[movi+offset:movi+offset+4]), test.ShouldEqual, "00dc")
+		test.That(t, avi[movi+offset+8:movi+offset+8+size], test.ShouldResemble, frame)
+	}
+
+	test.That(t, WriteMJPEGAVI(&out, nil, 10), test.ShouldNotBeNil)
+	test.That(t, WriteMJPEGAVI(&out, frames, 0), test.ShouldNotBeNil)
+	test.That(t, WriteMJPEGAVI(&out, [][]byte{[]byte("not a jpeg")}, 10), test.ShouldNotBeNil)
+}
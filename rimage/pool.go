@@ -0,0 +1,70 @@
+package rimage
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"sync"
+)
+
+// maxPooledBufferSize bounds the buffers kept for reuse, such that one unusually large image does
+// not keep its memory around for the life of the process.
+const maxPooledBufferSize = 64 << 20
+
+// encodeBufferPool holds the buffers images are encoded into. Encoding a stream of frames reuses
+// the buffer of an earlier frame, which has already grown to the size of one, instead of growing a
+// new buffer by doubling for every frame.
+var encodeBufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+func getEncodeBuffer() *bytes.Buffer {
+	buf := encodeBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putEncodeBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	encodeBufferPool.Put(buf)
+}
+
+// pngBufferPool lets the PNG encoder reuse its compression state and row buffers across frames.
+type pngBufferPool struct {
+	pool sync.Pool
+}
+
+func (p *pngBufferPool) Get() *png.EncoderBuffer {
+	buf, _ := p.pool.Get().(*png.EncoderBuffer)
+	return buf
+}
+
+func (p *pngBufferPool) Put(buf *png.EncoderBuffer) {
+	p.pool.Put(buf)
+}
+
+var pngEncoder = png.Encoder{BufferPool: &pngBufferPool{}}
+
+var rgbaPool sync.Pool
+
+// NewPooledRGBA returns an RGBA image with the given bounds whose pixels may be those of an image
+// released earlier, and a function that releases it for reuse. It is for images that only live
+// for one frame, such as an image scaled only to be encoded, and must not be used after it is
+// released. The pixels are not cleared, so the image should be drawn over with draw.Src.
+func NewPooledRGBA(r image.Rectangle) (*image.RGBA, func()) {
+	n := 4 * r.Dx() * r.Dy()
+	var pix []byte
+	if pooled, ok := rgbaPool.Get().(*[]byte); ok && cap(*pooled) >= n {
+		pix = (*pooled)[:n]
+	} else {
+		pix = make([]byte, n)
+	}
+	img := &image.RGBA{Pix: pix, Stride: 4 * r.Dx(), Rect: r}
+	return img, func() {
+		if cap(pix) <= maxPooledBufferSize {
+			rgbaPool.Put(&pix)
+		}
+	}
+}
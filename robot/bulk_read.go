@@ -0,0 +1,245 @@
+package robot
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/robot/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/protoutils"
+	"go.viam.com/rdk/resource"
+)
+
+const (
+	// DefaultBulkReadTimeout is how long each resource is given to be read by BulkRead when no timeout is set.
+	DefaultBulkReadTimeout = time.Second
+	// BulkReadOptionsMetadataKey is the gRPC metadata key carrying the JSON BulkReadOptions of a bulk
+	// read, which is served by the robot service's GetStatus RPC as it has no field for them.
+	BulkReadOptionsMetadataKey = "viam-bulk-read-options"
+)
+
+// The keys of the status of a resource in a GetStatus response to a bulk read.
+const (
+	bulkReadReadingKey = "reading"
+	bulkReadErrorKey   = "error"
+	bulkReadTimeKey    = "time"
+)
+
+// ResourceReader reads the current state of a resource for BulkRead, such as the readings of a sensor
+// or the positions of an actuator. The state must be convertible to a protobuf struct.
+type ResourceReader func(ctx context.Context, res resource.Resource, extra map[string]interface{}) (map[string]interface{}, error)
+
+var (
+	resourceReadersMu sync.RWMutex
+	resourceReaders   = map[resource.API]ResourceReader{}
+)
+
+// RegisterResourceReader registers how BulkRead reads the resources of an API. Resources of APIs
+// without a reader are read with their Readings if they have any.
+func RegisterResourceReader(api resource.API, reader ResourceReader) {
+	resourceReadersMu.Lock()
+	defer resourceReadersMu.Unlock()
+	resourceReaders[api] = reader
+}
+
+// ResourceReading is the state of a resource read by BulkRead.
+type ResourceReading struct {
+	Name resource.Name
+	// Time is when the resource finished being read.
+	Time time.Time
+	// Reading is the state of the resource, if it was read successfully.
+	Reading map[string]interface{}
+	// Err is why the resource could not be read, including taking longer than its timeout.
+	Err error
+}
+
+// ErrBulkReadUnsupported is returned by a BulkReader that cannot read in bulk, such as a client of a
+// machine running an older version, in which case the resources are read one by one.
+var ErrBulkReadUnsupported = errors.New("bulk read is not supported")
+
+// BulkReader is implemented by robots that read resources in bulk themselves, such as robot clients
+// which read all the resources in one round trip rather than one per resource.
+type BulkReader interface {
+	BulkRead(ctx context.Context, names []resource.Name, opts BulkReadOptions) ([]ResourceReading, error)
+}
+
+// BulkReadOptions configure BulkRead.
+type BulkReadOptions struct {
+	// Timeout is how long each resource is given to be read. Defaults to DefaultBulkReadTimeout.
+	Timeout time.Duration
+	// Timeouts override Timeout for some resources, such as cameras which are slower to read.
+	Timeouts map[resource.Name]time.Duration
+	// Extra is passed to each resource's read.
+	Extra map[string]interface{}
+}
+
+type bulkReadOptionsJSON struct {
+	TimeoutMs  int64                  `json:"timeout_ms,omitempty"`
+	TimeoutsMs map[string]int64       `json:"timeouts_ms,omitempty"`
+	Extra      map[string]interface{} `json:"extra,omitempty"`
+}
+
+// MarshalJSON marshals the options with timeouts in milliseconds.
+func (opts BulkReadOptions) MarshalJSON() ([]byte, error) {
+	j := bulkReadOptionsJSON{TimeoutMs: opts.Timeout.Milliseconds(), Extra: opts.Extra}
+	if len(opts.Timeouts) != 0 {
+		j.TimeoutsMs = make(map[string]int64, len(opts.Timeouts))
+		for name, timeout := range opts.Timeouts {
+			j.TimeoutsMs[name.String()] = timeout.Milliseconds()
+		}
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON unmarshals options marshaled by MarshalJSON.
+func (opts *BulkReadOptions) UnmarshalJSON(data []byte) error {
+	var j bulkReadOptionsJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*opts = BulkReadOptions{Timeout: time.Duration(j.TimeoutMs) * time.Millisecond, Extra: j.Extra}
+	if len(j.TimeoutsMs) != 0 {
+		opts.Timeouts = make(map[resource.Name]time.Duration, len(j.TimeoutsMs))
+		for nameStr, timeoutMs := range j.TimeoutsMs {
+			name, err := resource.NewFromString(nameStr)
+			if err != nil {
+				return errors.Wrapf(err, "invalid bulk read timeout resource name %q", nameStr)
+			}
+			opts.Timeouts[name] = time.Duration(timeoutMs) * time.Millisecond
+		}
+	}
+	return nil
+}
+
+func (opts BulkReadOptions) timeout(name resource.Name) time.Duration {
+	if timeout, ok := opts.Timeouts[name]; ok && timeout > 0 {
+		return timeout
+	}
+	if opts.Timeout > 0 {
+		return opts.Timeout
+	}
+	return DefaultBulkReadTimeout
+}
+
+// BulkRead reads the state of the named resources concurrently, each within its own timeout, and
+// returns their readings in the order of `names`. Resources which fail or time out are reported in
+// their reading's error, so that one slow resource doesn't fail the others. This lets dashboards
+// poll many resources in one round trip.
+//
+// BulkRead example:
+//
+//	names := []resource.Name{sensor.Named("temperature"), arm.Named("my_arm"), camera.Named("cam")}
+//	readings := robot.BulkRead(ctx, machine, names, robot.BulkReadOptions{
+//		Timeouts: map[resource.Name]time.Duration{camera.Named("cam"): 3 * time.Second},
+//	})
+//	for _, reading := range readings {
+//		logger.Infof("%s: %v %v", reading.Name.ShortName(), reading.Reading, reading.Err)
+//	}
+func BulkRead(ctx context.Context, r Robot, names []resource.Name, opts BulkReadOptions) []ResourceReading {
+	if br, ok := r.(BulkReader); ok {
+		readings, err := br.BulkRead(ctx, names, opts)
+		if err == nil {
+			return readings
+		}
+		if !errors.Is(err, ErrBulkReadUnsupported) {
+			readings = make([]ResourceReading, 0, len(names))
+			for _, name := range names {
+				readings = append(readings, ResourceReading{Name: name, Time: time.Now(), Err: err})
+			}
+			return readings
+		}
+	}
+	readings := make([]ResourceReading, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		readings[i].Name = name
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			readCtx, cancel := context.WithTimeout(ctx, opts.timeout(name))
+			defer cancel()
+			reading, err := readResourceWithin(readCtx, r, name, opts.Extra)
+			readings[i].Time = time.Now()
+			readings[i].Reading = reading
+			readings[i].Err = err
+		}()
+	}
+	wg.Wait()
+	return readings
+}
+
+// readResourceWithin reads the named resource, returning once ctx is done even if the resource
+// ignores it.
+func readResourceWithin(ctx context.Context, r Robot, name resource.Name, extra map[string]interface{}) (map[string]interface{}, error) {
+	type result struct {
+		reading map[string]interface{}
+		err     error
+	}
+	// buffered so that resources which finish reading after their timeout don't block
+	results := make(chan result, 1)
+	go func() {
+		reading, err := ReadResource(ctx, r, name, extra)
+		results <- result{reading, err}
+	}()
+	select {
+	case res := <-results:
+		return res.reading, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ReadResource reads the state of the named resource with the reader registered for its API, or its
+// Readings if there is none.
+func ReadResource(ctx context.Context, r Robot, name resource.Name, extra map[string]interface{}) (map[string]interface{}, error) {
+	res, err := r.ResourceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	resourceReadersMu.RLock()
+	reader, ok := resourceReaders[name.API]
+	resourceReadersMu.RUnlock()
+	if ok {
+		return reader(ctx, res, extra)
+	}
+	if s, ok := res.(resource.Sensor); ok {
+		return s.Readings(ctx, extra)
+	}
+	return nil, errors.Errorf("%s cannot be read", name)
+}
+
+// ResourceReadingToProto converts a reading to the status of its resource in a GetStatus response.
+// Readings are converted like those of sensors, so that values such as geo points round trip.
+func ResourceReadingToProto(reading ResourceReading) (*pb.Status, error) {
+	fields := map[string]*structpb.Value{
+		bulkReadTimeKey: structpb.NewStringValue(reading.Time.Format(time.RFC3339Nano)),
+	}
+	if reading.Err != nil {
+		fields[bulkReadErrorKey] = structpb.NewStringValue(reading.Err.Error())
+	} else {
+		readingFields, err := protoutils.ReadingGoToProto(reading.Reading)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading of %s", reading.Name)
+		}
+		fields[bulkReadReadingKey] = structpb.NewStructValue(&structpb.Struct{Fields: readingFields})
+	}
+	return &pb.Status{Name: protoutils.ResourceNameToProto(reading.Name), Status: &structpb.Struct{Fields: fields}}, nil
+}
+
+// ResourceReadingFromProto converts the status of a resource in a GetStatus response to its reading.
+func ResourceReadingFromProto(status *pb.Status) ResourceReading {
+	fields := status.GetStatus().GetFields()
+	reading := ResourceReading{Name: protoutils.ResourceNameFromProto(status.GetName())}
+	//nolint:errcheck
+	reading.Time, _ = time.Parse(time.RFC3339Nano, fields[bulkReadTimeKey].GetStringValue())
+	if errValue, ok := fields[bulkReadErrorKey]; ok {
+		reading.Err = errors.New(errValue.GetStringValue())
+		return reading
+	}
+	reading.Reading, reading.Err = protoutils.ReadingProtoToGo(fields[bulkReadReadingKey].GetStructValue().GetFields())
+	return reading
+}
@@ -0,0 +1,130 @@
+package robot_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/navigation"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestBulkRead(t *testing.T) {
+	fast := inject.NewSensor("fast")
+	fast.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"temp": 20.5, "extra": extra["unit"]}, nil
+	}
+	slow := inject.NewSensor("slow")
+	slow.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		// ignores its context, so is reported as timed out without being waited for
+		time.Sleep(300 * time.Millisecond)
+		return map[string]interface{}{"temp": 10.}, nil
+	}
+	m := inject.NewMotor("motor")
+	m.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		return 12.5, nil
+	}
+	m.IsPoweredFunc = func(ctx context.Context, extra map[string]interface{}) (bool, float64, error) {
+		return true, 0.5, nil
+	}
+	brokenMotor := inject.NewMotor("broken")
+	errBroken := errors.New("broken")
+	brokenMotor.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		return 0, errBroken
+	}
+	resources := map[resource.Name]resource.Resource{
+		sensor.Named("fast"):    fast,
+		sensor.Named("slow"):    slow,
+		motor.Named("motor"):    m,
+		motor.Named("broken"):   brokenMotor,
+		navigation.Named("nav"): inject.NewNavigationService("nav"),
+	}
+	r := &inject.Robot{}
+	r.ResourceByNameFunc = func(name resource.Name) (resource.Resource, error) {
+		res, ok := resources[name]
+		if !ok {
+			return nil, resource.NewNotFoundError(name)
+		}
+		return res, nil
+	}
+
+	names := []resource.Name{
+		sensor.Named("fast"), sensor.Named("slow"), motor.Named("motor"), motor.Named("broken"),
+		navigation.Named("nav"), sensor.Named("missing"),
+	}
+	start := time.Now()
+	readings := robot.BulkRead(context.Background(), r, names, robot.BulkReadOptions{
+		Timeout: 100 * time.Millisecond,
+		Extra:   map[string]interface{}{"unit": "C"},
+	})
+	// the slow sensor doesn't hold up the others
+	test.That(t, time.Since(start), test.ShouldBeLessThan, 250*time.Millisecond)
+	test.That(t, readings, test.ShouldHaveLength, len(names))
+	for i, reading := range readings {
+		test.That(t, reading.Name, test.ShouldResemble, names[i])
+		test.That(t, reading.Time.IsZero(), test.ShouldBeFalse)
+	}
+	test.That(t, readings[0].Err, test.ShouldBeNil)
+	test.That(t, readings[0].Reading, test.ShouldResemble, map[string]interface{}{"temp": 20.5, "extra": "C"})
+	test.That(t, readings[1].Err, test.ShouldBeError, context.DeadlineExceeded)
+	test.That(t, readings[2].Err, test.ShouldBeNil)
+	test.That(t, readings[2].Reading, test.ShouldResemble, map[string]interface{}{"position": 12.5, "is_powered": true, "power_pct": 0.5})
+	test.That(t, readings[3].Err, test.ShouldBeError, errBroken)
+	test.That(t, readings[4].Err.Error(), test.ShouldContainSubstring, "cannot be read")
+	test.That(t, resource.IsNotFoundError(readings[5].Err), test.ShouldBeTrue)
+
+	// per resource timeouts override the default
+	readings = robot.BulkRead(context.Background(), r, names[:2], robot.BulkReadOptions{
+		Timeout:  100 * time.Millisecond,
+		Timeouts: map[resource.Name]time.Duration{sensor.Named("slow"): time.Second},
+	})
+	test.That(t, readings[1].Err, test.ShouldBeNil)
+	test.That(t, readings[1].Reading, test.ShouldResemble, map[string]interface{}{"temp": 10.})
+}
+
+func TestBulkReadOptionsJSON(t *testing.T) {
+	opts := robot.BulkReadOptions{
+		Timeout:  200 * time.Millisecond,
+		Timeouts: map[resource.Name]time.Duration{sensor.Named("slow"): 2 * time.Second},
+		Extra:    map[string]interface{}{"unit": "C"},
+	}
+	data, err := json.Marshal(opts)
+	test.That(t, err, test.ShouldBeNil)
+	var parsed robot.BulkReadOptions
+	test.That(t, json.Unmarshal(data, &parsed), test.ShouldBeNil)
+	test.That(t, parsed, test.ShouldResemble, opts)
+
+	test.That(t, json.Unmarshal([]byte(`{"timeouts_ms": {"not a name": 5}}`), &parsed), test.ShouldNotBeNil)
+}
+
+func TestResourceReadingProto(t *testing.T) {
+	now := time.Now().Round(0)
+	reading := robot.ResourceReading{
+		Name:    sensor.Named("gps"),
+		Time:    now,
+		Reading: map[string]interface{}{"position": geo.NewPoint(40.7, -73.9), "fix": 1.},
+	}
+	status, err := robot.ResourceReadingToProto(reading)
+	test.That(t, err, test.ShouldBeNil)
+	roundTripped := robot.ResourceReadingFromProto(status)
+	test.That(t, roundTripped.Time.Equal(now), test.ShouldBeTrue)
+	roundTripped.Time = now
+	test.That(t, roundTripped, test.ShouldResemble, reading)
+
+	failed := robot.ResourceReading{Name: sensor.Named("gps"), Time: now, Err: errors.New("no fix")}
+	status, err = robot.ResourceReadingToProto(failed)
+	test.That(t, err, test.ShouldBeNil)
+	roundTripped = robot.ResourceReadingFromProto(status)
+	test.That(t, roundTripped.Reading, test.ShouldBeNil)
+	test.That(t, roundTripped.Err, test.ShouldBeError, failed.Err)
+	test.That(t, roundTripped.Time.Equal(now), test.ShouldBeTrue)
+}
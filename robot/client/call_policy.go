@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	googlegrpc "google.golang.org/grpc"
+
+	"go.viam.com/rdk/resource"
+)
+
+// A CallPolicy configures the deadline and retries of the unary calls a RobotClient makes to
+// resources. Streaming calls are left as they are made.
+type CallPolicy struct {
+	// Timeout is the deadline given to calls made with a context without one. If zero, such calls
+	// have no deadline.
+	Timeout time.Duration
+	// MaxRetries is how many times a call that failed because the machine was unavailable is
+	// retried. Only calls that read state, such as GetPosition, are retried. Calls that actuate are
+	// never retried, as retrying a call that did reach the machine would repeat it.
+	MaxRetries uint
+	// RetryBackoff is how long to wait before the first retry. The wait doubles with each retry.
+	RetryBackoff time.Duration
+}
+
+// callPolicies are the CallPolicy of each API, and the default CallPolicy of other calls.
+type callPolicies struct {
+	defaultPolicy *CallPolicy
+	// byService holds the policies by the gRPC service of their API, as calls are made to methods
+	// of services.
+	byService map[string]CallPolicy
+}
+
+func newCallPolicies(defaultPolicy *CallPolicy, byAPI map[resource.API]CallPolicy) *callPolicies {
+	policies := &callPolicies{defaultPolicy: defaultPolicy, byService: map[string]CallPolicy{}}
+	for api, policy := range byAPI {
+		reg, ok := resource.LookupGenericAPIRegistration(api)
+		if !ok || reg.RPCServiceDesc == nil {
+			continue
+		}
+		policies.byService[reg.RPCServiceDesc.ServiceName] = policy
+	}
+	return policies
+}
+
+// policyFor returns the policy of a full gRPC method name, such as
+// "/viam.component.arm.v1.ArmService/GetEndPosition".
+func (cp *callPolicies) policyFor(method string) (CallPolicy, bool) {
+	service, _, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if policy, ok := cp.byService[service]; ok {
+		return policy, true
+	}
+	if cp.defaultPolicy != nil {
+		return *cp.defaultPolicy, true
+	}
+	return CallPolicy{}, false
+}
+
+// isReadMethod returns whether a full gRPC method name is for a call that only reads state, and so
+// is safe to repeat.
+func isReadMethod(method string) bool {
+	name := method[strings.LastIndex(method, "/")+1:]
+	for _, prefix := range []string{"Get", "Is", "List"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// unaryClientInterceptor gives calls the deadline and retries of their policy. It must run before
+// the retry interceptor, which reads the retry options it adds to the call.
+func (cp *callPolicies) unaryClientInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *googlegrpc.ClientConn,
+	invoker googlegrpc.UnaryInvoker,
+	opts ...googlegrpc.CallOption,
+) error {
+	policy, ok := cp.policyFor(method)
+	if !ok {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline && policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, policy.Timeout)
+		defer cancel()
+	}
+	if policy.MaxRetries > 0 && isReadMethod(method) {
+		opts = append(opts,
+			grpc_retry.WithMax(policy.MaxRetries),
+			grpc_retry.WithBackoff(grpc_retry.BackoffExponential(policy.RetryBackoff)),
+		)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	googlegrpc "google.golang.org/grpc"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/resource"
+)
+
+func TestCallPolicies(t *testing.T) {
+	armPolicy := CallPolicy{Timeout: time.Second, MaxRetries: 3}
+	defaultPolicy := CallPolicy{Timeout: time.Minute}
+
+	t.Run("by api", func(t *testing.T) {
+		policies := newCallPolicies(&defaultPolicy, map[resource.API]CallPolicy{arm.API: armPolicy})
+		policy, ok := policies.policyFor("/viam.component.arm.v1.ArmService/GetEndPosition")
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, policy, test.ShouldResemble, armPolicy)
+
+		policy, ok = policies.policyFor("/viam.component.motor.v1.MotorService/GoFor")
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, policy, test.ShouldResemble, defaultPolicy)
+	})
+
+	t.Run("without default", func(t *testing.T) {
+		policies := newCallPolicies(nil, map[resource.API]CallPolicy{motor.API: armPolicy})
+		_, ok := policies.policyFor("/viam.component.arm.v1.ArmService/GetEndPosition")
+		test.That(t, ok, test.ShouldBeFalse)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		policies := newCallPolicies(nil, map[resource.API]CallPolicy{arm.API: armPolicy})
+		var deadline time.Time
+		var hasDeadline bool
+		invoker := func(ctx context.Context, _ string, _, _ interface{}, _ *googlegrpc.ClientConn, _ ...googlegrpc.CallOption) error {
+			deadline, hasDeadline = ctx.Deadline()
+			return nil
+		}
+		err := policies.unaryClientInterceptor(context.Background(), "/viam.component.arm.v1.ArmService/Stop", nil, nil, nil, invoker)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, hasDeadline, test.ShouldBeTrue)
+		test.That(t, time.Until(deadline), test.ShouldBeLessThanOrEqualTo, time.Second)
+
+		// a deadline set by the caller is kept
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		err = policies.unaryClientInterceptor(ctx, "/viam.component.arm.v1.ArmService/Stop", nil, nil, nil, invoker)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, time.Until(deadline), test.ShouldBeGreaterThan, time.Minute)
+	})
+
+	t.Run("retries reads only", func(t *testing.T) {
+		policies := newCallPolicies(nil, map[resource.API]CallPolicy{arm.API: armPolicy})
+		var numOpts int
+		invoker := func(_ context.Context, _ string, _, _ interface{}, _ *googlegrpc.ClientConn, opts ...googlegrpc.CallOption) error {
+			numOpts = len(opts)
+			return nil
+		}
+		err := policies.unaryClientInterceptor(context.Background(),
+			"/viam.component.arm.v1.ArmService/GetEndPosition", nil, nil, nil, invoker)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, numOpts, test.ShouldEqual, 2)
+
+		err = policies.unaryClientInterceptor(context.Background(),
+			"/viam.component.arm.v1.ArmService/MoveToPosition", nil, nil, nil, invoker)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, numOpts, test.ShouldEqual, 0)
+	})
+}
@@ -3,9 +3,11 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -48,6 +50,7 @@ import (
 	"go.viam.com/rdk/robot/packages"
 	"go.viam.com/rdk/session"
 	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/tracing"
 	"go.viam.com/rdk/tunnel"
 	"go.viam.com/rdk/utils/contextutils"
 )
@@ -77,6 +80,8 @@ type RobotClient struct {
 	remoteName  string
 	address     string
 	dialOptions []rpc.DialOption
+	// quicOptions, if set, dial the robot over QUIC instead.
+	quicOptions *grpc.QUICDialOptions
 
 	mu                       sync.RWMutex
 	resourceNames            []resource.Name
@@ -91,6 +96,9 @@ type RobotClient struct {
 	connected                atomic.Bool
 	rpcSubtypesUnimplemented bool
 
+	maxReconnectEvery       time.Duration
+	connectionStateCallback func(ConnectionState)
+
 	activeBackgroundWorkers sync.WaitGroup
 	backgroundCtx           context.Context
 	backgroundCtxCancel     func()
@@ -116,6 +124,14 @@ type RobotClient struct {
 
 	pc         *webrtc.PeerConnection
 	sharedConn *grpc.SharedConn
+
+	// parent is the client of the main part whose connection calls to this remote part are routed
+	// over, if any.
+	parent *RobotClient
+	part   string
+
+	partClientsMu sync.Mutex
+	partClients   map[string]*RobotClient
 }
 
 // RemoteTypeName is the type name used for a remote. This is for internal use.
@@ -255,6 +271,9 @@ func New(ctx context.Context, address string, clientLogger logging.ZapCompatible
 	for _, opt := range opts {
 		opt.apply(&rOpts)
 	}
+	if err := grpc.ValidateCompression(rOpts.compression); err != nil {
+		return nil, err
+	}
 	backgroundCtx, backgroundCtxCancel := context.WithCancel(context.Background())
 	heartbeatCtx, heartbeatCtxCancel := context.WithCancel(context.Background())
 
@@ -269,38 +288,71 @@ func New(ctx context.Context, address string, clientLogger logging.ZapCompatible
 		notifyParent:        nil,
 		resourceClients:     make(map[resource.Name]resource.Resource),
 		remoteNameMap:       make(map[resource.Name]resource.Name),
-		sessionsDisabled:    rOpts.disableSessions,
+		sessionsDisabled:    rOpts.disableSessions || rOpts.parent != nil,
 		heartbeatCtx:        heartbeatCtx,
 		heartbeatCtxCancel:  heartbeatCtxCancel,
+
+		maxReconnectEvery:       defaultMaxReconnectEvery,
+		connectionStateCallback: rOpts.connectionStateCallback,
+
+		parent:      rOpts.parent,
+		part:        rOpts.part,
+		partClients: map[string]*RobotClient{},
+	}
+	if rOpts.maxReconnectEvery != nil {
+		rc.maxReconnectEvery = *rOpts.maxReconnectEvery
 	}
 
 	// interceptors are applied in order from first to last
-	rc.dialOptions = append(
-		rc.dialOptions,
-		rpc.WithUnaryClientInterceptor(contextutils.ContextWithMetadataUnaryClientInterceptor),
+	unaryInterceptors := []googlegrpc.UnaryClientInterceptor{
+		contextutils.ContextWithMetadataUnaryClientInterceptor,
 		// error handling
-		rpc.WithUnaryClientInterceptor(rc.handleUnaryDisconnect),
-		rpc.WithStreamClientInterceptor(rc.handleStreamDisconnect),
+		rc.handleUnaryDisconnect,
+		// deadlines and retries, read by the retry interceptor
+		newCallPolicies(rOpts.defaultCallPolicy, rOpts.callPolicies).unaryClientInterceptor,
 		// sessions
-		rpc.WithUnaryClientInterceptor(grpc_retry.UnaryClientInterceptor()),
-		rpc.WithStreamClientInterceptor(grpc_retry.StreamClientInterceptor()),
-		rpc.WithUnaryClientInterceptor(rc.sessionUnaryClientInterceptor),
-		rpc.WithStreamClientInterceptor(rc.sessionStreamClientInterceptor),
+		grpc_retry.UnaryClientInterceptor(),
+		rc.sessionUnaryClientInterceptor,
 		// operations
-		rpc.WithUnaryClientInterceptor(operation.UnaryClientInterceptor),
-		rpc.WithStreamClientInterceptor(operation.StreamClientInterceptor),
-		rpc.WithUnaryClientInterceptor(logging.UnaryClientInterceptor),
+		operation.UnaryClientInterceptor,
+		logging.UnaryClientInterceptor,
+		// tracing, a span per attempt
+		tracing.UnaryClientInterceptor,
 		// sending version metadata
-		rpc.WithUnaryClientInterceptor(unaryClientInterceptor()),
-		rpc.WithStreamClientInterceptor(streamClientInterceptor()),
-	)
+		unaryClientInterceptor(),
+	}
+	streamInterceptors := []googlegrpc.StreamClientInterceptor{
+		rc.handleStreamDisconnect,
+		grpc_retry.StreamClientInterceptor(),
+		rc.sessionStreamClientInterceptor,
+		operation.StreamClientInterceptor,
+		tracing.StreamClientInterceptor,
+		streamClientInterceptor(),
+	}
 
 	// If we're a client running as part of a module, we annotate our requests with our module
 	// name. That way the receiver (e.g: viam-server) can execute logic based on where a request
 	// came from. Such as knowing what WebRTC connection to add a video track to.
 	if rOpts.modName != "" {
 		inter := &grpc.ModInterceptors{ModName: rOpts.modName}
-		rc.dialOptions = append(rc.dialOptions, rpc.WithUnaryClientInterceptor(inter.UnaryClientInterceptor))
+		unaryInterceptors = append(unaryInterceptors, inter.UnaryClientInterceptor)
+	}
+
+	for _, interceptor := range unaryInterceptors {
+		rc.dialOptions = append(rc.dialOptions, rpc.WithUnaryClientInterceptor(interceptor))
+	}
+	for _, interceptor := range streamInterceptors {
+		rc.dialOptions = append(rc.dialOptions, rpc.WithStreamClientInterceptor(interceptor))
+	}
+	if rOpts.quic != nil {
+		quicOpts := *rOpts.quic
+		quicOpts.UnaryInterceptors = append(quicOpts.UnaryInterceptors, unaryInterceptors...)
+		quicOpts.StreamInterceptors = append(quicOpts.StreamInterceptors, streamInterceptors...)
+		rc.quicOptions = &quicOpts
+	}
+
+	if rOpts.compression != "" {
+		rc.dialOptions = append(rc.dialOptions, grpc.CompressionDialOptions(rOpts.compression)...)
 	}
 
 	numAttempts := 3
@@ -435,6 +487,7 @@ func (rc *RobotClient) Connect(ctx context.Context) error {
 		return err
 	}
 	rc.Logger().CInfow(ctx, "successfully (re)connected to remote at address", "address", rc.address)
+	rc.setConnectionState(ctx, ConnectionStateConnected)
 	if rc.notifyParent != nil {
 		rc.notifyParent()
 		rc.Logger().CDebugw(ctx, "successfully notified parent after (re)connection", "address", rc.address)
@@ -450,6 +503,44 @@ func (rc *RobotClient) connectWithLock(ctx context.Context) error {
 		return err
 	}
 
+	var conn rpc.ClientConn
+	if rc.parent != nil {
+		// Calls to a part are routed to it by the main part, over the main part's connection.
+		conn = grpc.NewPartConn(&rc.parent.conn, rc.part)
+	} else {
+		var err error
+		if conn, err = rc.dialWithLock(ctx); err != nil {
+			return err
+		}
+	}
+
+	client := pb.NewRobotServiceClient(conn)
+
+	refClient := grpcreflect.NewClientV1Alpha(rc.backgroundCtx, reflectpb.NewServerReflectionClient(conn))
+
+	rc.conn.ReplaceConn(conn)
+	rc.client = client
+	rc.refClient = refClient
+	rc.connected.Store(true)
+	if len(rc.resourceClients) != 0 {
+		if err := rc.updateResources(ctx); err != nil {
+			return err
+		}
+	}
+
+	if rc.changeChan != nil {
+		rc.changeChan <- true
+	}
+	return nil
+}
+
+// dialWithLock dials the machine, over WebRTC if it can be, and returns the connection. It must be
+// called with `rc.mu` held.
+func (rc *RobotClient) dialWithLock(ctx context.Context) (rpc.ClientConn, error) {
+	if rc.quicOptions != nil {
+		return grpc.DialQUIC(ctx, rc.address, *rc.quicOptions)
+	}
+
 	// Try forcing a webrtc connection.
 	dialOptionsWebRTCOnly := make([]rpc.DialOption, len(rc.dialOptions)+1)
 	// Put our "disable GRPC" option in front and the user input values at the end. This ensures
@@ -491,27 +582,9 @@ func (rc *RobotClient) connectWithLock(ctx context.Context) error {
 		}
 	}
 	if err != nil {
-		return err
-	}
-
-	client := pb.NewRobotServiceClient(conn)
-
-	refClient := grpcreflect.NewClientV1Alpha(rc.backgroundCtx, reflectpb.NewServerReflectionClient(conn))
-
-	rc.conn.ReplaceConn(conn)
-	rc.client = client
-	rc.refClient = refClient
-	rc.connected.Store(true)
-	if len(rc.resourceClients) != 0 {
-		if err := rc.updateResources(ctx); err != nil {
-			return err
-		}
-	}
-
-	if rc.changeChan != nil {
-		rc.changeChan <- true
+		return nil, err
 	}
-	return nil
+	return conn, nil
 }
 
 func (rc *RobotClient) updateResourceClients(ctx context.Context) error {
@@ -538,13 +611,15 @@ func (rc *RobotClient) updateResourceClients(ctx context.Context) error {
 
 // checkConnection either checks if the client is still connected, or attempts to reconnect to the remote.
 func (rc *RobotClient) checkConnection(ctx context.Context, checkEvery, reconnectEvery time.Duration, refresh bool) {
+	// nextReconnect backs off from reconnectEvery while attempts to reconnect keep failing.
+	nextReconnect := reconnectEvery
 	for {
 		var waitTime time.Duration
 		if rc.connected.Load() {
 			waitTime = checkEvery
 		} else {
 			if reconnectEvery != 0 {
-				waitTime = reconnectEvery
+				waitTime = nextReconnect
 			} else {
 				// if reconnectEvery is unset, we will not attempt to reconnect
 				return
@@ -555,10 +630,14 @@ func (rc *RobotClient) checkConnection(ctx context.Context, checkEvery, reconnec
 		}
 		if !rc.connected.Load() {
 			rc.Logger().CInfow(ctx, "trying to reconnect to remote at address", "address", rc.address)
+			rc.setConnectionState(ctx, ConnectionStateReconnecting)
 			if err := rc.Connect(ctx); err != nil {
-				rc.Logger().CErrorw(ctx, "failed to reconnect remote", "error", err, "address", rc.address)
+				nextReconnect = nextReconnectEvery(nextReconnect, rc.maxReconnectEvery)
+				rc.Logger().CErrorw(ctx, "failed to reconnect remote",
+					"error", err, "address", rc.address, "next_attempt_in", nextReconnect.String())
 				continue
 			}
+			nextReconnect = reconnectEvery
 		} else {
 			check := func() error {
 				if refresh {
@@ -610,6 +689,7 @@ func (rc *RobotClient) checkConnection(ctx context.Context, checkEvery, reconnec
 				if notifyParentFn != nil {
 					notifyParentFn()
 				}
+				rc.setConnectionState(ctx, ConnectionStateDisconnected)
 			}
 		}
 	}
@@ -625,10 +705,17 @@ func (rc *RobotClient) Close(ctx context.Context) error {
 		close(rc.changeChan)
 		rc.changeChan = nil
 	}
+	rc.partClientsMu.Lock()
+	var err error
+	for name, part := range rc.partClients {
+		err = multierr.Combine(err, part.Close(ctx))
+		delete(rc.partClients, name)
+	}
+	rc.partClientsMu.Unlock()
 	rc.refClient.Reset()
 	rc.heartbeatCtxCancel()
 	rc.heartbeatWorkers.Wait()
-	return rc.conn.Close()
+	return multierr.Combine(err, rc.conn.Close())
 }
 
 func (rc *RobotClient) checkConnected() error {
@@ -655,10 +742,27 @@ func (rc *RobotClient) RefreshEvery(ctx context.Context, every time.Duration) {
 	}
 }
 
-// RemoteByName returns a remote machine by name. It is assumed to exist on the
-// other end. Right now this method is unimplemented.
+// RemoteByName returns a client for the remote part of the machine with the given name. Its calls
+// are routed to the part by the machine, over this client's connection, rather than the part being
+// dialed separately.
 func (rc *RobotClient) RemoteByName(name string) (robot.Robot, bool) {
-	panic(errUnimplemented)
+	if !slices.Contains(rc.RemoteNames(), name) {
+		return nil, false
+	}
+	rc.partClientsMu.Lock()
+	defer rc.partClientsMu.Unlock()
+	if part, ok := rc.partClients[name]; ok {
+		return part, true
+	}
+	ctx, cancel := context.WithTimeout(rc.backgroundCtx, defaultResourcesTimeout)
+	defer cancel()
+	part, err := New(ctx, rc.address+"/"+name, rc.logger.Sublogger(name), WithPartOf(rc, name))
+	if err != nil {
+		rc.logger.Errorw("error connecting to remote part", "remote", name, "error", err)
+		return nil, false
+	}
+	rc.partClients[name] = part
+	return part, true
 }
 
 // ResourceByName returns resource by name.
@@ -825,7 +929,19 @@ func (rc *RobotClient) updateRemoteNameMap() {
 
 // RemoteNames returns the names of all known remotes.
 func (rc *RobotClient) RemoteNames() []string {
-	return nil
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	var names []string
+	for _, name := range rc.resourceNames {
+		if !name.ContainsRemoteNames() {
+			continue
+		}
+		remote, _, _ := strings.Cut(name.Remote, ":")
+		if !slices.Contains(names, remote) {
+			names = append(names, remote)
+		}
+	}
+	return names
 }
 
 // ProcessManager returns a useless process manager for the sake of
@@ -1167,6 +1283,128 @@ func (rc *RobotClient) MachineStatus(ctx context.Context) (robot.MachineStatus,
 	return mStatus, nil
 }
 
+// BulkRead reads the state of the named resources in one round trip, see robot.BulkRead. It returns
+// robot.ErrBulkReadUnsupported if the machine cannot read resources in bulk.
+func (rc *RobotClient) BulkRead(
+	ctx context.Context,
+	names []resource.Name,
+	opts robot.BulkReadOptions,
+) ([]robot.ResourceReading, error) {
+	optsJSON, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, robot.BulkReadOptionsMetadataKey, string(optsJSON))
+	req := &pb.GetStatusRequest{ResourceNames: make([]*commonpb.ResourceName, 0, len(names))}
+	for _, name := range names {
+		req.ResourceNames = append(req.ResourceNames, rprotoutils.ResourceNameToProto(name))
+	}
+	resp, err := rc.client.GetStatus(ctx, req)
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return nil, robot.ErrBulkReadUnsupported
+		}
+		return nil, err
+	}
+	readings := make([]robot.ResourceReading, 0, len(resp.Status))
+	for _, pbStatus := range resp.Status {
+		readings = append(readings, robot.ResourceReadingFromProto(pbStatus))
+	}
+	return readings, nil
+}
+
+// DiagnosticsBundle writes a diagnostics bundle generated by the machine to `w`, see
+// robot.WriteDiagnosticsBundle. It returns robot.ErrDiagnosticsUnsupported if the machine cannot
+// generate diagnostics bundles.
+func (rc *RobotClient) DiagnosticsBundle(ctx context.Context, opts robot.DiagnosticsOptions, w io.Writer) error {
+	optsJSON, err := json.Marshal(opts)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, robot.DiagnosticsBundleMetadataKey, string(optsJSON))
+	stream, err := rc.client.StreamStatus(ctx, &pb.StreamStatusRequest{})
+	for err == nil {
+		var resp *pb.StreamStatusResponse
+		if resp, err = stream.Recv(); err != nil {
+			break
+		}
+		var chunk []byte
+		if chunk, err = robot.DiagnosticsChunkFromProto(resp); err != nil {
+			break
+		}
+		_, err = w.Write(chunk)
+	}
+	switch {
+	case errors.Is(err, io.EOF):
+		return nil
+	case status.Code(err) == codes.Unimplemented:
+		return robot.ErrDiagnosticsUnsupported
+	default:
+		return err
+	}
+}
+
+// StreamLogs streams the logs of the machine which match `filter`, calling `handle` with each
+// batch of entries as they arrive, until `ctx` is done or `handle` returns an error. Entries are
+// filtered by the machine, such that only matching entries are sent over the network. If the
+// connection is lost, the stream is resumed once it is re-established, starting from the entries
+// logged after the last one handled.
+func (rc *RobotClient) StreamLogs(
+	ctx context.Context,
+	filter logging.LogFilter,
+	handle func(entries []logging.JSONLogEntry) error,
+) error {
+	return grpc.ResumeStream(ctx, &rc.conn, func(ctx context.Context) error {
+		return rc.streamLogs(ctx, filter, func(entries []logging.JSONLogEntry) error {
+			if len(entries) != 0 {
+				// Entry times are truncated to milliseconds.
+				if last, err := time.Parse(logging.DefaultTimeFormatStr, entries[len(entries)-1].Time); err == nil {
+					filter.Since = last.Add(time.Millisecond)
+				}
+			}
+			return handle(entries)
+		})
+	})
+}
+
+func (rc *RobotClient) streamLogs(
+	ctx context.Context,
+	filter logging.LogFilter,
+	handle func(entries []logging.JSONLogEntry) error,
+) error {
+	filterJSON, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, robot.LogStreamMetadataKey, string(filterJSON))
+	stream, err := rc.client.StreamStatus(ctx, &pb.StreamStatusRequest{})
+	for err == nil {
+		var resp *pb.StreamStatusResponse
+		if resp, err = stream.Recv(); err != nil {
+			break
+		}
+		var entries []logging.JSONLogEntry
+		if entries, err = robot.LogEntriesFromProto(resp); err != nil {
+			break
+		}
+		err = handle(entries)
+	}
+	switch {
+	case errors.Is(err, io.EOF):
+		return nil
+	case status.Code(err) == codes.Unimplemented:
+		return robot.ErrLogStreamUnsupported
+	case status.Code(err) == codes.Canceled && ctx.Err() != nil:
+		return ctx.Err()
+	default:
+		return err
+	}
+}
+
 // Version returns version information about the machine.
 func (rc *RobotClient) Version(ctx context.Context) (robot.VersionResponse, error) {
 	mVersion := robot.VersionResponse{}
@@ -1291,6 +1529,12 @@ func (rc *RobotClient) SetPeerConnection(pc *webrtc.PeerConnection) {
 	rc.mu.Unlock()
 }
 
+// ClientConn returns the connection the client makes its calls over, such that calls for the
+// machine's own remote parts can be routed over it.
+func (rc *RobotClient) ClientConn() rpc.ClientConn {
+	return &rc.conn
+}
+
 func (rc *RobotClient) getClientConn() rpc.ClientConn {
 	// Must be called with `rc.mu` in ReadLock+ mode.
 	if rc.sharedConn != nil {
@@ -4,6 +4,9 @@ import (
 	"time"
 
 	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/resource"
 )
 
 // robotClientOpts configure a Dial call. robotClientOpts are set by the RobotClientOption
@@ -24,6 +27,13 @@ type robotClientOpts struct {
 	// it will automatically refresh every 1s
 	reconnectEvery *time.Duration
 
+	// maxReconnectEvery is the most reconnectEvery is doubled to after failed attempts to
+	// reconnect. If unset, it is 30s.
+	maxReconnectEvery *time.Duration
+
+	// connectionStateCallback is called whenever the state of the connection changes.
+	connectionStateCallback func(ConnectionState)
+
 	// dialOptions are options using for clients dialing gRPC servers.
 	dialOptions []rpc.DialOption
 
@@ -39,6 +49,21 @@ type robotClientOpts struct {
 	initialConnectionAttempts *int
 
 	modName string
+
+	// defaultCallPolicy is the CallPolicy of calls to APIs without one in callPolicies.
+	defaultCallPolicy *CallPolicy
+	callPolicies      map[resource.API]CallPolicy
+
+	// parent is the client of the main part whose connection the client's calls to the remote part
+	// named part are routed over, if any.
+	parent *RobotClient
+	part   string
+
+	// compression is the compressor to compress requests with, if any.
+	compression string
+
+	// quic, if set, dials the robot over QUIC instead of over WebRTC or direct gRPC.
+	quic *grpc.QUICDialOptions
 }
 
 // RobotClientOption configures how we set up the connection.
@@ -71,6 +96,44 @@ func WithModName(modName string) RobotClientOption {
 	})
 }
 
+// WithCompression returns a RobotClientOption to compress requests sent directly over gRPC with
+// the named compressor, "gzip" or "snappy". Large payloads such as point clouds benefit the most.
+func WithCompression(name string) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.compression = name
+	})
+}
+
+// WithCallPolicy returns a RobotClientOption that gives calls to resources of the API the deadline
+// and retries of the policy, rather than every caller setting them on its own context.
+func WithCallPolicy(api resource.API, policy CallPolicy) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		if o.callPolicies == nil {
+			o.callPolicies = map[resource.API]CallPolicy{}
+		}
+		o.callPolicies[api] = policy
+	})
+}
+
+// WithDefaultCallPolicy returns a RobotClientOption that gives calls the deadline and retries of
+// the policy, unless their API has a policy set with WithCallPolicy.
+func WithDefaultCallPolicy(policy CallPolicy) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.defaultCallPolicy = &policy
+	})
+}
+
+// WithPartOf returns a RobotClientOption to talk to the remote part with the given name of the
+// main part `parent` is connected to, over the connection of `parent` rather than by dialing the
+// part. Remotes of remotes are named by joining their names with ":". The address passed to New is
+// then only used to identify the part in logs. Sessions are disabled for such clients.
+func WithPartOf(parent *RobotClient, part string) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.parent = parent
+		o.part = part
+	})
+}
+
 // WithRefreshEvery returns a RobotClientOption for how often to refresh the status/parts of the
 // robot.
 func WithRefreshEvery(refreshEvery time.Duration) RobotClientOption {
@@ -102,6 +165,24 @@ func WithReconnectEvery(reconnectEvery time.Duration) RobotClientOption {
 	})
 }
 
+// WithReconnectBackoff returns a RobotClientOption for the longest to wait between attempts to
+// reconnect the robot. The wait starts at the interval set with WithReconnectEvery and doubles
+// after each failed attempt, up to maxReconnectEvery.
+func WithReconnectBackoff(maxReconnectEvery time.Duration) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.maxReconnectEvery = &maxReconnectEvery
+	})
+}
+
+// WithConnectionStateCallback returns a RobotClientOption that calls `callback` whenever the
+// connection to the robot is established, lost, or being re-established. It is called from the
+// client's background goroutines, so it should not block.
+func WithConnectionStateCallback(callback func(ConnectionState)) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.connectionStateCallback = callback
+	})
+}
+
 // WithRemoteName returns a RobotClientOption setting the name of the remote robot.
 func WithRemoteName(remoteName string) RobotClientOption {
 	return newFuncRobotClientOption(func(o *robotClientOpts) {
@@ -116,6 +197,15 @@ func WithDisableSessions() RobotClientOption {
 	})
 }
 
+// WithQUIC returns a RobotClientOption which connects to the robot over QUIC, which holds up better
+// than TCP over lossy links such as cellular ones. The address passed to New must then be the
+// address the robot serves QUIC at, and dial options do not apply. Requests are not compressed.
+func WithQUIC(opts grpc.QUICDialOptions) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.quic = &opts
+	})
+}
+
 // WithDialOptions returns a RobotClientOption which sets the options for making
 // gRPC connections to other servers.
 func WithDialOptions(opts ...rpc.DialOption) RobotClientOption {
@@ -1,7 +1,9 @@
 package client
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
@@ -584,7 +586,8 @@ func TestStatusClient(t *testing.T) {
 	client, err = New(context.Background(), listener2.Addr().String(), logger)
 	test.That(t, err, test.ShouldBeNil)
 
-	test.That(t, func() { client.RemoteByName("remote1") }, test.ShouldPanic)
+	_, ok := client.RemoteByName("remote1")
+	test.That(t, ok, test.ShouldBeFalse)
 
 	arm1, err = arm.FromRobot(client, "arm1")
 	test.That(t, err, test.ShouldBeNil)
@@ -2207,6 +2210,203 @@ func TestMachineStatus(t *testing.T) {
 	}
 }
 
+func TestClientBulkRead(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	gServer := grpc.NewServer()
+
+	fast := inject.NewSensor("fast")
+	fast.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"temp": 20.5, "unit": extra["unit"]}, nil
+	}
+	slow := inject.NewSensor("slow")
+	slow.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	injectRobot := &inject.Robot{
+		ResourceNamesFunc:   func() []resource.Name { return nil },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+		MachineStatusFunc: func(ctx context.Context) (robot.MachineStatus, error) {
+			return robot.MachineStatus{State: robot.StateRunning}, nil
+		},
+		ResourceByNameFunc: func(name resource.Name) (resource.Resource, error) {
+			switch name {
+			case sensor.Named("fast"):
+				return fast, nil
+			case sensor.Named("slow"):
+				return slow, nil
+			default:
+				return nil, resource.NewNotFoundError(name)
+			}
+		},
+	}
+	pb.RegisterRobotServiceServer(gServer, server.New(injectRobot))
+
+	go gServer.Serve(listener)
+	defer gServer.Stop()
+
+	client, err := New(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	names := []resource.Name{sensor.Named("fast"), sensor.Named("slow"), sensor.Named("missing")}
+	readings := robot.BulkRead(context.Background(), client, names, robot.BulkReadOptions{
+		Timeouts: map[resource.Name]time.Duration{sensor.Named("slow"): 50 * time.Millisecond},
+		Extra:    map[string]interface{}{"unit": "C"},
+	})
+	test.That(t, readings, test.ShouldHaveLength, 3)
+	test.That(t, readings[0].Name, test.ShouldResemble, sensor.Named("fast"))
+	test.That(t, readings[0].Err, test.ShouldBeNil)
+	test.That(t, readings[0].Reading, test.ShouldResemble, map[string]interface{}{"temp": 20.5, "unit": "C"})
+	test.That(t, readings[1].Err.Error(), test.ShouldContainSubstring, context.DeadlineExceeded.Error())
+	test.That(t, readings[2].Err.Error(), test.ShouldContainSubstring, "not found")
+
+	_, err = client.BulkRead(context.Background(), nil, robot.BulkReadOptions{})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestClientDiagnosticsBundle(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	gServer := grpc.NewServer()
+
+	injectRobot := &inject.Robot{
+		ResourceNamesFunc:   func() []resource.Name { return nil },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+		MachineStatusFunc: func(ctx context.Context) (robot.MachineStatus, error) {
+			return robot.MachineStatus{State: robot.StateRunning}, nil
+		},
+		VersionFunc: func(ctx context.Context) (robot.VersionResponse, error) {
+			return robot.VersionResponse{Version: "v1.2.3"}, nil
+		},
+		ConfigFunc: func() *config.Config {
+			return &config.Config{}
+		},
+	}
+	pb.RegisterRobotServiceServer(gServer, server.New(injectRobot))
+
+	go gServer.Serve(listener)
+	defer gServer.Stop()
+
+	client, err := New(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	var bundle bytes.Buffer
+	test.That(t, client.DiagnosticsBundle(context.Background(), robot.DiagnosticsOptions{}, &bundle), test.ShouldBeNil)
+	gz, err := gzip.NewReader(&bundle)
+	test.That(t, err, test.ShouldBeNil)
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		test.That(t, err, test.ShouldBeNil)
+		names = append(names, header.Name)
+	}
+	test.That(t, names, test.ShouldResemble, []string{"version.json", "config.json", "machine_status.json"})
+
+	// streaming statuses is still unimplemented
+	stream, err := client.client.StreamStatus(context.Background(), &pb.StreamStatusRequest{})
+	test.That(t, err, test.ShouldBeNil)
+	_, err = stream.Recv()
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unimplemented)
+}
+
+// logStreamRobot streams the logs of an appender from an injected robot.
+type logStreamRobot struct {
+	*inject.Robot
+	logs *logging.RecentLogsAppender
+}
+
+func (r *logStreamRobot) SubscribeLogs(filter logging.LogFilter) (*logging.LogSubscription, error) {
+	return r.logs.Subscribe(filter)
+}
+
+func TestClientStreamLogs(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	gServer := grpc.NewServer()
+
+	robotLogs := logging.NewRecentLogsAppender(100)
+	robotLogger := logging.NewBlankLogger("rdk")
+	robotLogger.AddAppender(robotLogs)
+	armLogger := robotLogger.Sublogger("resource_manager.rdk:component:arm/arm1")
+	start := time.Now()
+	armLogger.Error("already failed")
+	armLogger.Info("not an error")
+
+	injectRobot := &logStreamRobot{
+		Robot: &inject.Robot{
+			ResourceNamesFunc:   func() []resource.Name { return nil },
+			ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+			MachineStatusFunc: func(ctx context.Context) (robot.MachineStatus, error) {
+				return robot.MachineStatus{State: robot.StateRunning}, nil
+			},
+		},
+		logs: robotLogs,
+	}
+	pb.RegisterRobotServiceServer(gServer, server.New(injectRobot))
+
+	go gServer.Serve(listener)
+	defer gServer.Stop()
+
+	client, err := New(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan logging.JSONLogEntry, 10)
+	streamErr := make(chan error, 1)
+	go func() {
+		filter := logging.LogFilter{Level: "error", Resource: "arm1", Since: start}
+		streamErr <- client.StreamLogs(ctx, filter, func(entries []logging.JSONLogEntry) error {
+			for _, entry := range entries {
+				received <- entry
+			}
+			return nil
+		})
+	}()
+	nextEntry := func() logging.JSONLogEntry {
+		t.Helper()
+		select {
+		case entry := <-received:
+			return entry
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for streamed logs")
+			return logging.JSONLogEntry{}
+		}
+	}
+	// the kept entry is replayed once the machine has subscribed, so live entries are streamed after
+	test.That(t, nextEntry().Message, test.ShouldEqual, "already failed")
+	robotLogger.Error("not the arm")
+	armLogger.Errorw("failed again", "joint", 2)
+	entry := nextEntry()
+	test.That(t, entry.Message, test.ShouldEqual, "failed again")
+	test.That(t, entry.Resource, test.ShouldEqual, "rdk:component:arm/arm1")
+	test.That(t, string(entry.Fields), test.ShouldEqual, `{"joint":2}`)
+	cancel()
+	test.That(t, <-streamErr, test.ShouldBeError, context.Canceled)
+	test.That(t, received, test.ShouldBeEmpty)
+
+	// invalid filters are rejected by the machine
+	err = client.StreamLogs(context.Background(), logging.LogFilter{Pattern: "("}, func([]logging.JSONLogEntry) error { return nil })
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "invalid log pattern")
+}
+
 func TestVersion(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 	listener, err := net.Listen("tcp", "localhost:0")
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// A ConnectionState is the state of a RobotClient's connection to its machine.
+type ConnectionState int
+
+// The states of a RobotClient's connection, in the order they are passed through when the
+// connection is lost.
+const (
+	ConnectionStateConnected ConnectionState = iota
+	ConnectionStateDisconnected
+	ConnectionStateReconnecting
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionStateConnected:
+		return "connected"
+	case ConnectionStateDisconnected:
+		return "disconnected"
+	case ConnectionStateReconnecting:
+		return "reconnecting"
+	default:
+		return "unknown"
+	}
+}
+
+// defaultMaxReconnectEvery is the longest the client waits between attempts to reconnect, unless
+// set with WithReconnectBackoff.
+const defaultMaxReconnectEvery = 30 * time.Second
+
+// setConnectionState reports a change in the state of the connection to the callback set with
+// WithConnectionStateCallback, if any. It must not be called with rc.mu held, as the callback may
+// call back into the client.
+func (rc *RobotClient) setConnectionState(ctx context.Context, state ConnectionState) {
+	rc.Logger().CDebugw(ctx, "connection state changed", "address", rc.address, "state", state.String())
+	if rc.connectionStateCallback != nil {
+		rc.connectionStateCallback(state)
+	}
+}
+
+// nextReconnectEvery returns how long to wait before the next attempt to reconnect, after an
+// attempt that waited `current` failed. The wait doubles after each failed attempt, up to
+// maxReconnectEvery, so that a machine that is down for long is not dialed in a tight loop.
+func nextReconnectEvery(current, maxReconnectEvery time.Duration) time.Duration {
+	next := 2 * current
+	if next > maxReconnectEvery {
+		next = maxReconnectEvery
+	}
+	if next < current {
+		return current
+	}
+	return next
+}
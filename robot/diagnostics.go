@@ -0,0 +1,267 @@
+package robot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/robot/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/config"
+)
+
+const (
+	// DiagnosticsBundleMetadataKey is the gRPC metadata key carrying the JSON DiagnosticsOptions of a
+	// request for a diagnostics bundle, which is served by the robot service's StreamStatus RPC as
+	// there is no RPC for them.
+	DiagnosticsBundleMetadataKey = "viam-diagnostics-bundle"
+	// DiagnosticsChunkSize is the most bytes of a diagnostics bundle sent in one StreamStatus
+	// response.
+	DiagnosticsChunkSize = 64 * 1024
+	// DefaultDiagnosticsFTDCWindow is how far back the FTDC files of a diagnostics bundle go when
+	// no window is set.
+	DefaultDiagnosticsFTDCWindow = 24 * time.Hour
+)
+
+// ErrDiagnosticsUnsupported is returned when asking a machine running an older version for a
+// diagnostics bundle.
+var ErrDiagnosticsUnsupported = errors.New("diagnostics bundles are not supported")
+
+// A DiagnosticsRobot is a Robot that can include its recent logs and FTDC files in diagnostics
+// bundles.
+type DiagnosticsRobot interface {
+	Robot
+
+	// RecentLogs returns the most recent log lines of the robot, oldest first.
+	RecentLogs() []string
+
+	// FTDCFiles returns the paths of the FTDC data files written by the robot, if FTDC is enabled.
+	FTDCFiles() ([]string, error)
+}
+
+// DiagnosticsOptions configure which files a diagnostics bundle includes.
+type DiagnosticsOptions struct {
+	// FTDCWindow is how far back the included FTDC files go, by when they were last written.
+	// Defaults to DefaultDiagnosticsFTDCWindow.
+	FTDCWindow time.Duration `json:"ftdc_window,omitempty"`
+	// SkipFTDC leaves FTDC files, which can be large, out of the bundle.
+	SkipFTDC bool `json:"skip_ftdc,omitempty"`
+}
+
+// The files of a diagnostics bundle.
+const (
+	diagnosticsVersionFile       = "version.json"
+	diagnosticsConfigFile        = "config.json"
+	diagnosticsMachineStatusFile = "machine_status.json"
+	diagnosticsLogsFile          = "logs/recent.log"
+	diagnosticsFTDCDir           = "ftdc"
+	// diagnosticsErrorsFile lists the parts of the bundle which could not be collected.
+	diagnosticsErrorsFile = "errors.txt"
+)
+
+// diagnosticsResourceStatus is the status of a resource in a diagnostics bundle.
+type diagnosticsResourceStatus struct {
+	Name        string    `json:"name"`
+	State       string    `json:"state"`
+	LastUpdated time.Time `json:"last_updated"`
+	Revision    string    `json:"revision,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+type diagnosticsMachineStatus struct {
+	State     string                      `json:"state"`
+	Config    config.Revision             `json:"config"`
+	Resources []diagnosticsResourceStatus `json:"resources"`
+}
+
+// WriteDiagnosticsBundle writes a gzipped tarball for support to `w`, holding the version of the
+// robot, its config with secrets redacted, the statuses of its resources and, if it is a
+// DiagnosticsRobot, its recent logs and FTDC files. Parts which can't be collected are listed in
+// the errors.txt file of the bundle rather than failing it, as bundles are most useful when
+// something is wrong.
+//
+// WriteDiagnosticsBundle example:
+//
+//	f, err := os.Create("diagnostics.tar.gz")
+//	if err != nil {
+//		return err
+//	}
+//	defer f.Close()
+//	err = robot.WriteDiagnosticsBundle(ctx, machine, robot.DiagnosticsOptions{FTDCWindow: time.Hour}, f)
+func WriteDiagnosticsBundle(ctx context.Context, r Robot, opts DiagnosticsOptions, w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	var collectErrs []string
+	collectErr := func(part string, err error) {
+		collectErrs = append(collectErrs, fmt.Sprintf("%s: %v", part, err))
+	}
+	addJSON := func(name string, v interface{}) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			collectErr(name, err)
+			return nil
+		}
+		return addDiagnosticsFile(tw, name, data, now)
+	}
+
+	if version, err := r.Version(ctx); err != nil {
+		collectErr(diagnosticsVersionFile, err)
+	} else if err := addJSON(diagnosticsVersionFile, version); err != nil {
+		return err
+	}
+
+	if lr, ok := r.(LocalRobot); ok {
+		if conf, err := config.Redacted(lr.Config()); err != nil {
+			collectErr(diagnosticsConfigFile, err)
+		} else if err := addJSON(diagnosticsConfigFile, conf); err != nil {
+			return err
+		}
+	}
+
+	if status, err := r.MachineStatus(ctx); err != nil {
+		collectErr(diagnosticsMachineStatusFile, err)
+	} else if err := addJSON(diagnosticsMachineStatusFile, diagnosticsStatus(status)); err != nil {
+		return err
+	}
+
+	if dr, ok := r.(DiagnosticsRobot); ok {
+		var logs strings.Builder
+		for _, line := range dr.RecentLogs() {
+			logs.WriteString(line)
+			logs.WriteByte('\n')
+		}
+		if err := addDiagnosticsFile(tw, diagnosticsLogsFile, []byte(logs.String()), now); err != nil {
+			return err
+		}
+
+		if !opts.SkipFTDC {
+			if err := addFTDCFiles(ctx, tw, dr, opts, now, collectErr); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(collectErrs) > 0 {
+		if err := addDiagnosticsFile(tw, diagnosticsErrorsFile, []byte(strings.Join(collectErrs, "\n")+"\n"), now); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func diagnosticsStatus(status MachineStatus) diagnosticsMachineStatus {
+	result := diagnosticsMachineStatus{Config: status.Config, Resources: []diagnosticsResourceStatus{}}
+	switch status.State {
+	case StateInitializing:
+		result.State = "initializing"
+	case StateRunning:
+		result.State = "running"
+	case StateUnknown:
+		result.State = "unknown"
+	}
+	for _, res := range status.Resources {
+		resStatus := diagnosticsResourceStatus{
+			Name:        res.Name.String(),
+			State:       res.State.String(),
+			LastUpdated: res.LastUpdated,
+			Revision:    res.Revision,
+		}
+		if res.Error != nil {
+			resStatus.Error = res.Error.Error()
+		}
+		result.Resources = append(result.Resources, resStatus)
+	}
+	return result
+}
+
+// addFTDCFiles adds the FTDC files last written within the window of `opts`.
+func addFTDCFiles(
+	ctx context.Context,
+	tw *tar.Writer,
+	dr DiagnosticsRobot,
+	opts DiagnosticsOptions,
+	now time.Time,
+	collectErr func(string, error),
+) error {
+	paths, err := dr.FTDCFiles()
+	if err != nil {
+		collectErr(diagnosticsFTDCDir, err)
+		return nil
+	}
+	window := opts.FTDCWindow
+	if window <= 0 {
+		window = DefaultDiagnosticsFTDCWindow
+	}
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			collectErr(path, err)
+			continue
+		}
+		if now.Sub(info.ModTime()) > window {
+			continue
+		}
+		// files are read whole so that the header size matches files which are still being written
+		data, err := os.ReadFile(path) //nolint:gosec
+		if err != nil {
+			collectErr(path, err)
+			continue
+		}
+		if err := addDiagnosticsFile(tw, diagnosticsFTDCDir+"/"+filepath.Base(path), data, info.ModTime()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addDiagnosticsFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// diagnosticsChunkKey is the key of the base64 encoded chunk of a diagnostics bundle in the status
+// of a StreamStatus response.
+const diagnosticsChunkKey = "chunk"
+
+// DiagnosticsChunkToProto converts a chunk of a diagnostics bundle to a StreamStatus response.
+func DiagnosticsChunkToProto(chunk []byte) *pb.StreamStatusResponse {
+	return &pb.StreamStatusResponse{Status: []*pb.Status{{
+		Status: &structpb.Struct{Fields: map[string]*structpb.Value{
+			diagnosticsChunkKey: structpb.NewStringValue(base64.StdEncoding.EncodeToString(chunk)),
+		}},
+	}}}
+}
+
+// DiagnosticsChunkFromProto converts a StreamStatus response to the chunk of a diagnostics bundle
+// it carries.
+func DiagnosticsChunkFromProto(resp *pb.StreamStatusResponse) ([]byte, error) {
+	if len(resp.GetStatus()) != 1 {
+		return nil, errors.Errorf("expected one status in a diagnostics chunk, got %d", len(resp.GetStatus()))
+	}
+	return base64.StdEncoding.DecodeString(resp.GetStatus()[0].GetStatus().GetFields()[diagnosticsChunkKey].GetStringValue())
+}
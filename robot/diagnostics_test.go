@@ -0,0 +1,152 @@
+package robot_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// diagnosticsRobot adds recent logs and FTDC files to an injected robot.
+type diagnosticsRobot struct {
+	*inject.Robot
+	logs      []string
+	ftdcFiles []string
+}
+
+func (r *diagnosticsRobot) RecentLogs() []string {
+	return r.logs
+}
+
+func (r *diagnosticsRobot) FTDCFiles() ([]string, error) {
+	return r.ftdcFiles, nil
+}
+
+// readBundle returns the contents of the files of a diagnostics bundle by name.
+func readBundle(t *testing.T, bundle []byte) map[string]string {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(bundle))
+	test.That(t, err, test.ShouldBeNil)
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files
+		}
+		test.That(t, err, test.ShouldBeNil)
+		data, err := io.ReadAll(tr)
+		test.That(t, err, test.ShouldBeNil)
+		files[header.Name] = string(data)
+	}
+}
+
+func TestWriteDiagnosticsBundle(t *testing.T) {
+	ftdcDir := t.TempDir()
+	recentFTDC := filepath.Join(ftdcDir, "viam-server-2024-11-18T20-37-01Z.ftdc")
+	oldFTDC := filepath.Join(ftdcDir, "viam-server-2024-11-01T20-37-01Z.ftdc")
+	test.That(t, os.WriteFile(recentFTDC, []byte("recent"), 0o600), test.ShouldBeNil)
+	test.That(t, os.WriteFile(oldFTDC, []byte("old"), 0o600), test.ShouldBeNil)
+	twoDaysAgo := time.Now().Add(-48 * time.Hour)
+	test.That(t, os.Chtimes(oldFTDC, twoDaysAgo, twoDaysAgo), test.ShouldBeNil)
+
+	r := &diagnosticsRobot{
+		Robot: &inject.Robot{
+			VersionFunc: func(ctx context.Context) (robot.VersionResponse, error) {
+				return robot.VersionResponse{Platform: "rdk", Version: "v1.2.3"}, nil
+			},
+			ConfigFunc: func() *config.Config {
+				return &config.Config{
+					Cloud: &config.Cloud{ID: "part", Secret: "cloud secret"},
+					Components: []resource.Config{{
+						Name:       "arm1",
+						API:        arm.API,
+						Model:      resource.DefaultModelFamily.WithModel("fake"),
+						Attributes: map[string]interface{}{"host": "10.0.0.2", "api_key": "arm secret"},
+					}},
+				}
+			},
+			MachineStatusFunc: func(ctx context.Context) (robot.MachineStatus, error) {
+				return robot.MachineStatus{
+					State: robot.StateRunning,
+					Resources: []resource.Status{{NodeStatus: resource.NodeStatus{
+						Name:  arm.Named("arm1"),
+						State: resource.NodeStateUnhealthy,
+						Error: errors.New("arm is disconnected"),
+					}}},
+				}, nil
+			},
+		},
+		logs:      []string{"first line", "second line"},
+		ftdcFiles: []string{oldFTDC, recentFTDC},
+	}
+
+	var bundle bytes.Buffer
+	test.That(t, robot.WriteDiagnosticsBundle(context.Background(), r, robot.DiagnosticsOptions{}, &bundle), test.ShouldBeNil)
+	files := readBundle(t, bundle.Bytes())
+	test.That(t, files, test.ShouldHaveLength, 5)
+	test.That(t, files["version.json"], test.ShouldContainSubstring, "v1.2.3")
+	test.That(t, files["logs/recent.log"], test.ShouldEqual, "first line\nsecond line\n")
+	test.That(t, files["ftdc/viam-server-2024-11-18T20-37-01Z.ftdc"], test.ShouldEqual, "recent")
+
+	// secrets are redacted from the config
+	test.That(t, files["config.json"], test.ShouldContainSubstring, "10.0.0.2")
+	test.That(t, files["config.json"], test.ShouldNotContainSubstring, "cloud secret")
+	test.That(t, files["config.json"], test.ShouldNotContainSubstring, "arm secret")
+
+	var status struct {
+		State     string `json:"state"`
+		Resources []struct {
+			Name  string `json:"name"`
+			State string `json:"state"`
+			Error string `json:"error"`
+		} `json:"resources"`
+	}
+	test.That(t, json.Unmarshal([]byte(files["machine_status.json"]), &status), test.ShouldBeNil)
+	test.That(t, status.State, test.ShouldEqual, "running")
+	test.That(t, status.Resources, test.ShouldHaveLength, 1)
+	test.That(t, status.Resources[0].Name, test.ShouldEqual, arm.Named("arm1").String())
+	test.That(t, status.Resources[0].State, test.ShouldEqual, "Unhealthy")
+	test.That(t, status.Resources[0].Error, test.ShouldEqual, "arm is disconnected")
+
+	// the window includes older FTDC files, and parts which fail to be collected are listed
+	r.MachineStatusFunc = func(ctx context.Context) (robot.MachineStatus, error) {
+		return robot.MachineStatus{}, errors.New("still starting")
+	}
+	bundle.Reset()
+	opts := robot.DiagnosticsOptions{FTDCWindow: 72 * time.Hour}
+	test.That(t, robot.WriteDiagnosticsBundle(context.Background(), r, opts, &bundle), test.ShouldBeNil)
+	files = readBundle(t, bundle.Bytes())
+	test.That(t, files["ftdc/viam-server-2024-11-01T20-37-01Z.ftdc"], test.ShouldEqual, "old")
+	test.That(t, files, test.ShouldNotContainKey, "machine_status.json")
+	test.That(t, files["errors.txt"], test.ShouldEqual, "machine_status.json: still starting\n")
+
+	bundle.Reset()
+	opts = robot.DiagnosticsOptions{SkipFTDC: true}
+	test.That(t, robot.WriteDiagnosticsBundle(context.Background(), r, opts, &bundle), test.ShouldBeNil)
+	for name := range readBundle(t, bundle.Bytes()) {
+		test.That(t, name, test.ShouldNotStartWith, "ftdc/")
+	}
+}
+
+func TestDiagnosticsChunkProto(t *testing.T) {
+	chunk := []byte{0, 1, 2, 255}
+	roundTripped, err := robot.DiagnosticsChunkFromProto(robot.DiagnosticsChunkToProto(chunk))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, roundTripped, test.ShouldResemble, chunk)
+}
@@ -21,3 +21,8 @@ func DependencyNotFoundError(name string) error {
 func NotInputEnabledError(component resource.Resource) error {
 	return errors.Errorf("%v(%T) is not InputEnabled", component.Name(), component)
 }
+
+// DynamicFrameNotFoundError is returned when removing a frame which was not added at runtime.
+func DynamicFrameNotFoundError(name string) error {
+	return errors.Errorf("frame %q was not added at runtime", name)
+}
@@ -64,6 +64,15 @@ type InputEnabled interface {
 //	myCurrentInputs, err := fsService.CurrentInputs(context.Background())
 //
 //	frameSystem, err := fsService.FrameSystem(context.Background(), nil)
+//
+// UpdateFrames example:
+//
+//	// Swap the gripper frame attached to myArm for the frame of a suction cup.
+//	suction := referenceframe.NewLinkInFrame("myArm", spatialmath.NewPoseFromPoint(r3.Vector{Z: 80}), "suction", nil)
+//	err := fsService.UpdateFrames(context.Background(), framesystem.FrameUpdate{
+//		Upsert: []*referenceframe.LinkInFrame{suction},
+//		Remove: []string{"gripper"},
+//	})
 type Service interface {
 	resource.Resource
 
@@ -85,6 +94,22 @@ type Service interface {
 
 	// FrameSystem returns the frame system of the machine and incorporates any specified additional transformations.
 	FrameSystem(ctx context.Context, additionalTransforms []*referenceframe.LinkInFrame) (referenceframe.FrameSystem, error)
+
+	// UpdateFrames adds, updates and removes frames of the frame system without reconfiguring the machine. Either all of
+	// the update is applied, or none of it if the frame system it results in is invalid.
+	UpdateFrames(ctx context.Context, update FrameUpdate) error
+
+	// DynamicFrames returns the frames added by UpdateFrames, which are part of the frame system along with the
+	// configured ones.
+	DynamicFrames(ctx context.Context) ([]*referenceframe.LinkInFrame, error)
+}
+
+// FrameUpdate is a change to the frames of a frame system made at runtime, such as a tool changer swapping end
+// effectors. Frames of Upsert are added, or replace the frames of the same name added before. Frames named by Remove,
+// which must have been added at runtime, are removed.
+type FrameUpdate struct {
+	Upsert []*referenceframe.LinkInFrame
+	Remove []string
 }
 
 // FromDependencies is a helper for getting the framesystem from a collection of dependencies.
@@ -162,8 +187,10 @@ type frameSystemService struct {
 	components map[string]resource.Resource
 	logger     logging.Logger
 
-	parts   []*referenceframe.FrameSystemPart
-	partsMu sync.RWMutex
+	parts []*referenceframe.FrameSystemPart
+	// dynamicFrames are the frames added by UpdateFrames, in the order they were added
+	dynamicFrames []*referenceframe.LinkInFrame
+	partsMu       sync.RWMutex
 }
 
 // Reconfigure will rebuild the frame system from the newly updated robot.
@@ -195,10 +222,94 @@ func (svc *frameSystemService) Reconfigure(ctx context.Context, deps resource.De
 		return err
 	}
 	svc.parts = sortedParts
+	svc.dynamicFrames = svc.fittingDynamicFrames()
 	svc.logger.Debugf("reconfigured robot frame system: %v", (&Config{Parts: sortedParts}).String())
 	return nil
 }
 
+// fittingDynamicFrames returns the dynamic frames that still fit into the configured frame system, dropping those whose
+// names are now taken or whose parents are gone.
+func (svc *frameSystemService) fittingDynamicFrames() []*referenceframe.LinkInFrame {
+	if _, err := referenceframe.NewFrameSystem(LocalFrameSystemName, svc.parts, svc.dynamicFrames); err == nil {
+		return svc.dynamicFrames
+	}
+	kept := make([]*referenceframe.LinkInFrame, 0, len(svc.dynamicFrames))
+	remaining := svc.dynamicFrames
+	// frames may be parented to frames after them, so keep adding the frames that fit until none do
+	for added := true; added; {
+		added = false
+		var unfit []*referenceframe.LinkInFrame
+		for _, frame := range remaining {
+			candidate := append(append([]*referenceframe.LinkInFrame{}, kept...), frame)
+			if _, err := referenceframe.NewFrameSystem(LocalFrameSystemName, svc.parts, candidate); err != nil {
+				unfit = append(unfit, frame)
+				continue
+			}
+			kept = candidate
+			added = true
+		}
+		remaining = unfit
+	}
+	for _, frame := range remaining {
+		svc.logger.Warnw("removing frame added at runtime which no longer fits into the configured frame system",
+			"frame", frame.Name(), "parent", frame.Parent())
+	}
+	return kept
+}
+
+// UpdateFrames applies `update` to the frames added at runtime if the frame system it results in is valid.
+func (svc *frameSystemService) UpdateFrames(ctx context.Context, update FrameUpdate) error {
+	_, span := trace.StartSpan(ctx, "services::framesystem::UpdateFrames")
+	defer span.End()
+
+	svc.partsMu.Lock()
+	defer svc.partsMu.Unlock()
+
+	updated := append([]*referenceframe.LinkInFrame{}, svc.dynamicFrames...)
+	for _, frame := range update.Upsert {
+		if frame == nil || frame.Name() == "" || frame.Parent() == "" {
+			return referenceframe.ErrEmptyStringFrameName
+		}
+		replaced := false
+		for i, existing := range updated {
+			if existing.Name() == frame.Name() {
+				updated[i] = frame
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			updated = append(updated, frame)
+		}
+	}
+	for _, name := range update.Remove {
+		removed := false
+		for i, existing := range updated {
+			if existing.Name() == name {
+				updated = append(updated[:i], updated[i+1:]...)
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			return DynamicFrameNotFoundError(name)
+		}
+	}
+	if _, err := referenceframe.NewFrameSystem(LocalFrameSystemName, svc.parts, updated); err != nil {
+		return errors.Wrap(err, "frame update results in an invalid frame system")
+	}
+	svc.dynamicFrames = updated
+	svc.logger.CDebugw(ctx, "updated frames added at runtime", "upserted", len(update.Upsert), "removed", update.Remove)
+	return nil
+}
+
+// DynamicFrames returns the frames added by UpdateFrames.
+func (svc *frameSystemService) DynamicFrames(ctx context.Context) ([]*referenceframe.LinkInFrame, error) {
+	svc.partsMu.RLock()
+	defer svc.partsMu.RUnlock()
+	return append([]*referenceframe.LinkInFrame{}, svc.dynamicFrames...), nil
+}
+
 // TransformPose will transform the pose of the requested poseInFrame to the desired frame in the robot's frame system.
 func (svc *frameSystemService) TransformPose(
 	ctx context.Context,
@@ -299,7 +410,14 @@ func (svc *frameSystemService) FrameSystem(
 ) (referenceframe.FrameSystem, error) {
 	_, span := trace.StartSpan(ctx, "services::framesystem::FrameSystem")
 	defer span.End()
-	return referenceframe.NewFrameSystem(LocalFrameSystemName, svc.parts, additionalTransforms)
+
+	svc.partsMu.RLock()
+	transforms := make([]*referenceframe.LinkInFrame, 0, len(svc.dynamicFrames)+len(additionalTransforms))
+	transforms = append(transforms, svc.dynamicFrames...)
+	parts := svc.parts
+	svc.partsMu.RUnlock()
+	transforms = append(transforms, additionalTransforms...)
+	return referenceframe.NewFrameSystem(LocalFrameSystemName, parts, transforms)
 }
 
 // TransformPointCloud applies the same pose offset to each point in a single pointcloud and returns the transformed point cloud.
@@ -13,6 +13,7 @@ import (
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
 	robotimpl "go.viam.com/rdk/robot/impl"
 	_ "go.viam.com/rdk/services/register"
 	"go.viam.com/rdk/spatialmath"
@@ -273,3 +274,92 @@ func TestNewFrameSystemFromBadConfig(t *testing.T) {
 		test.That(t, fs, test.ShouldBeNil)
 	})
 }
+
+func TestUpdateFrames(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	cfg, err := config.Read(ctx, rdkutils.ResolveFile("robot/impl/data/fake.json"), logger, nil)
+	test.That(t, err, test.ShouldBeNil)
+	r, err := robotimpl.New(ctx, cfg, nil, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer r.Close(ctx)
+	res, err := r.ResourceByName(framesystem.InternalServiceName)
+	test.That(t, err, test.ShouldBeNil)
+	fsSvc, err := rdkutils.AssertType[framesystem.Service](res)
+	test.That(t, err, test.ShouldBeNil)
+
+	offset := spatialmath.NewPoseFromPoint(r3.Vector{Z: 100})
+	gripper := referenceframe.NewLinkInFrame("pieceArm", offset, "gripper", nil)
+	finger := referenceframe.NewLinkInFrame("gripper", offset, "finger", nil)
+	dynamicFrameNames := func() []string {
+		frames, err := fsSvc.DynamicFrames(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		names := make([]string, 0, len(frames))
+		for _, frame := range frames {
+			names = append(names, frame.Name())
+		}
+		return names
+	}
+	configuredParts, err := r.FrameSystemConfig(ctx)
+	test.That(t, err, test.ShouldBeNil)
+
+	test.That(t, fsSvc.UpdateFrames(ctx, framesystem.FrameUpdate{Upsert: []*referenceframe.LinkInFrame{finger, gripper}}), test.ShouldBeNil)
+	test.That(t, dynamicFrameNames(), test.ShouldResemble, []string{"finger", "gripper"})
+	fs, err := fsSvc.FrameSystem(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.Frame("finger"), test.ShouldNotBeNil)
+	// clients of the robot see the new frames too
+	fsCfg, err := r.FrameSystemConfig(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fsCfg.Parts, test.ShouldHaveLength, len(configuredParts.Parts)+2)
+	pose, err := r.TransformPose(ctx, referenceframe.NewPoseInFrame("finger", spatialmath.NewZeroPose()), "pieceArm", nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pose.Pose().Point(), test.ShouldResemble, r3.Vector{Z: 200})
+
+	t.Run("updates replace frames", func(t *testing.T) {
+		longer := referenceframe.NewLinkInFrame("gripper", spatialmath.NewPoseFromPoint(r3.Vector{Z: 50}), "finger", nil)
+		test.That(t, fsSvc.UpdateFrames(ctx, framesystem.FrameUpdate{Upsert: []*referenceframe.LinkInFrame{longer}}), test.ShouldBeNil)
+		test.That(t, dynamicFrameNames(), test.ShouldResemble, []string{"finger", "gripper"})
+		pose, err := r.TransformPose(ctx, referenceframe.NewPoseInFrame("finger", spatialmath.NewZeroPose()), "pieceArm", nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pose.Pose().Point(), test.ShouldResemble, r3.Vector{Z: 150})
+	})
+
+	t.Run("invalid updates change nothing", func(t *testing.T) {
+		suction := referenceframe.NewLinkInFrame("nowhere", offset, "suction", nil)
+		err := fsSvc.UpdateFrames(ctx, framesystem.FrameUpdate{
+			Upsert: []*referenceframe.LinkInFrame{suction},
+			Remove: []string{"finger"},
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		// the finger would be left without its parent
+		err = fsSvc.UpdateFrames(ctx, framesystem.FrameUpdate{Remove: []string{"gripper"}})
+		test.That(t, err, test.ShouldNotBeNil)
+		// configured frames cannot be replaced or removed
+		err = fsSvc.UpdateFrames(ctx, framesystem.FrameUpdate{
+			Upsert: []*referenceframe.LinkInFrame{referenceframe.NewLinkInFrame(referenceframe.World, offset, "pieceArm", nil)},
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		err = fsSvc.UpdateFrames(ctx, framesystem.FrameUpdate{Remove: []string{"pieceArm"}})
+		test.That(t, err, test.ShouldBeError, framesystem.DynamicFrameNotFoundError("pieceArm"))
+		test.That(t, dynamicFrameNames(), test.ShouldResemble, []string{"finger", "gripper"})
+	})
+
+	t.Run("reconfiguring drops frames which no longer fit", func(t *testing.T) {
+		noArm := &config.Config{}
+		for _, conf := range cfg.Components {
+			if conf.Name != "pieceArm" && conf.Name != "pieceGripper" && conf.Name != "movement_sensor2" {
+				noArm.Components = append(noArm.Components, conf)
+			}
+		}
+		camera := referenceframe.NewLinkInFrame("cameraOver", offset, "lens", nil)
+		test.That(t, fsSvc.UpdateFrames(ctx, framesystem.FrameUpdate{Upsert: []*referenceframe.LinkInFrame{camera}}), test.ShouldBeNil)
+		r.Reconfigure(ctx, noArm)
+		test.That(t, dynamicFrameNames(), test.ShouldResemble, []string{"lens"})
+	})
+
+	t.Run("frames are removed", func(t *testing.T) {
+		test.That(t, fsSvc.UpdateFrames(ctx, framesystem.FrameUpdate{Remove: []string{"lens"}}), test.ShouldBeNil)
+		test.That(t, dynamicFrameNames(), test.ShouldBeEmpty)
+	})
+}
@@ -7,11 +7,13 @@ package robotimpl
 import (
 	"context"
 	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/benbjohnson/clock"
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
 	packagespb "go.viam.com/api/app/packages/v1"
@@ -90,6 +92,9 @@ type localRobot struct {
 	localModuleVersions map[string]semver.Version
 	startFtdcOnce       sync.Once
 	ftdc                *ftdc.FTDC
+	recentLogs          *logging.RecentLogsAppender
+	// clk, if set, replaces the wall clock for the robot and the resources it builds.
+	clk clock.Clock
 
 	// whether the robot is actively reconfiguring
 	reconfiguring atomic.Bool
@@ -374,14 +379,43 @@ func newWithResources(
 		//   constructed to get a valid copy of its stats object (for the schema's sake). Even if
 		//   the web service has not been "started".
 		ftdcWorker = ftdc.New(ftdc.DefaultDirectory(utils.ViamDotDir, partID), logger.Sublogger("ftdc"))
+		if rOpts.clk != nil {
+			ftdcWorker.SetClock(rOpts.clk)
+		}
+		metadata := ftdc.Metadata{PartID: partID}
+		if cfg.Cloud != nil && cfg.Cloud.FQDN != "" {
+			// The FQDN is of the form `<robot part name>.<location id>.viam.cloud`.
+			metadata.RobotName, _, _ = strings.Cut(cfg.Cloud.FQDN, ".")
+		}
+		if version, err := robot.Version(); err == nil {
+			metadata.Version = version.Version
+		}
+		ftdcWorker.SetMetadata(metadata)
 		if statser, err := sys.NewSelfSysUsageStatser(); err == nil {
 			ftdcWorker.Add("proc.viam-server", statser)
 		}
 		if statser, err := sys.NewNetUsage(); err == nil {
 			ftdcWorker.Add("net", statser)
 		}
+		if pressure, err := sys.NewSystemPressure(); err == nil {
+			ftdcWorker.SetPressureReader(pressure)
+		}
+		if rOpts.ftdcPrometheusURL != "" {
+			exporter, err := ftdc.NewPrometheusExporter(ftdc.PrometheusConfig{
+				URL:    rOpts.ftdcPrometheusURL,
+				Labels: map[string]string{"part_id": partID},
+			}, logger.Sublogger("ftdc.prometheus"))
+			if err != nil {
+				return nil, err
+			}
+			exporter.Start()
+			ftdcWorker.AddExporter(exporter)
+		}
 	}
 
+	if rOpts.clk != nil {
+		ctx = utils.WithClock(ctx, rOpts.clk)
+	}
 	closeCtx, cancel := context.WithCancel(ctx)
 	r := &localRobot{
 		manager: newResourceManager(
@@ -408,6 +442,8 @@ func newWithResources(
 		shutdownCallback:           rOpts.shutdownCallback,
 		localModuleVersions:        make(map[string]semver.Version),
 		ftdc:                       ftdcWorker,
+		recentLogs:                 rOpts.recentLogs,
+		clk:                        rOpts.clk,
 	}
 
 	r.mostRecentCfg.Store(config.Config{})
@@ -417,7 +453,11 @@ func newWithResources(
 	} else {
 		heartbeatWindow = cfg.Network.Sessions.HeartbeatWindow
 	}
-	r.sessionManager = robot.NewSessionManager(r, heartbeatWindow)
+	if r.clk != nil {
+		r.sessionManager = robot.NewSessionManagerWithClock(r, heartbeatWindow, r.clk)
+	} else {
+		r.sessionManager = robot.NewSessionManager(r, heartbeatWindow)
+	}
 
 	var successful bool
 	defer func() {
@@ -460,6 +500,7 @@ func newWithResources(
 	r.webSvc = web.New(r, logger, rOpts.webOptions...)
 	if r.ftdc != nil {
 		r.ftdc.Add("web", r.webSvc.RequestCounter())
+		r.ftdc.Add("operations", r.operations)
 	}
 	r.frameSvc, err = framesystem.New(ctx, resource.Dependencies{}, logger)
 	if err != nil {
@@ -748,7 +789,7 @@ func (r *localRobot) updateWeakDependents(ctx context.Context) {
 					r.Logger().CErrorw(ctx, "failed to reconfigure internal service during weak dependencies update", "service", resName, "error", err)
 				}
 			case framesystem.InternalServiceName:
-				fsCfg, err := r.FrameSystemConfig(ctxWithTimeout)
+				fsCfg, err := r.configuredFrameSystemConfig(ctxWithTimeout)
 				if err != nil {
 					r.Logger().CErrorw(ctx, "failed to reconfigure internal service during weak dependencies update", "service", resName, "error", err)
 					break
@@ -852,7 +893,28 @@ func (r *localRobot) updateWeakDependents(ctx context.Context) {
 // Config returns the info of each individual part that makes up the frame system
 // The output of this function is to be sent over GRPC to the client, so the client
 // can build its frame system. requests the remote components from the remote's frame system service.
+// It includes the frames added at runtime through the frame system service.
 func (r *localRobot) FrameSystemConfig(ctx context.Context) (*framesystem.Config, error) {
+	fsCfg, err := r.configuredFrameSystemConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dynamicFrames, err := r.frameSvc.DynamicFrames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, frame := range dynamicFrames {
+		part, err := referenceframe.LinkInFrameToFrameSystemPart(frame)
+		if err != nil {
+			return nil, err
+		}
+		fsCfg.Parts = append(fsCfg.Parts, part)
+	}
+	return fsCfg, nil
+}
+
+// configuredFrameSystemConfig returns the parts of the frame system given by the robot's config and its remotes.
+func (r *localRobot) configuredFrameSystemConfig(ctx context.Context) (*framesystem.Config, error) {
 	localParts, err := r.getLocalFrameSystemParts()
 	if err != nil {
 		return nil, err
@@ -1099,6 +1161,10 @@ func (r *localRobot) applyLocalModuleVersions(cfg *config.Config) {
 }
 
 func (r *localRobot) reconfigure(ctx context.Context, newConfig *config.Config, forceSync bool) {
+	if r.clk != nil {
+		// Resources are built with the robot's clock, whatever context the caller reconfigures with.
+		ctx = utils.WithClock(ctx, r.clk)
+	}
 	defer func() {
 		// Always update the `initializing` value at the end of this function. Resources may
 		// be equal or `reconfigure` may otherwise return early, but we still want to move
@@ -1121,9 +1187,15 @@ func (r *localRobot) reconfigure(ctx context.Context, newConfig *config.Config,
 	r.configRevision = config.Revision{
 		Revision:    newConfig.Revision,
 		LastUpdated: time.Now(),
+		CachedAt:    newConfig.CachedAt,
 	}
 	r.configRevisionMu.Unlock()
 
+	if !newConfig.CachedAt.IsZero() {
+		r.logger.CWarnw(ctx, "running on cached config; cloud config could not be fetched",
+			"cached_at", newConfig.CachedAt.Format(logging.DefaultTimeFormatStr), "revision", newConfig.Revision)
+	}
+
 	var allErrs error
 
 	// Sync Packages before reconfiguring rest of robot and resolving references to any packages
@@ -1440,6 +1512,47 @@ func (r *localRobot) MachineStatus(ctx context.Context) (robot.MachineStatus, er
 	return result, nil
 }
 
+// LatestFTDC returns the most recently recorded FTDC datum.
+func (r *localRobot) LatestFTDC() (ftdc.FlatDatum, bool) {
+	if r.ftdc == nil {
+		return ftdc.FlatDatum{}, false
+	}
+	return r.ftdc.Latest()
+}
+
+// SubscribeFTDC returns a stream of the FTDC datums recorded from now on.
+func (r *localRobot) SubscribeFTDC(bufferSize int, metricPrefixes ...string) (*ftdc.Subscription, bool) {
+	if r.ftdc == nil {
+		return nil, false
+	}
+	return r.ftdc.Subscribe(bufferSize, metricPrefixes...), true
+}
+
+// RecentLogs returns the most recent log lines of the robot, if it was created WithRecentLogs.
+func (r *localRobot) RecentLogs() []string {
+	if r.recentLogs == nil {
+		return nil
+	}
+	return r.recentLogs.Lines()
+}
+
+// SubscribeLogs streams the logs of the robot which match `filter`, if it was created
+// WithRecentLogs.
+func (r *localRobot) SubscribeLogs(filter logging.LogFilter) (*logging.LogSubscription, error) {
+	if r.recentLogs == nil {
+		return nil, robot.ErrLogStreamUnsupported
+	}
+	return r.recentLogs.Subscribe(filter)
+}
+
+// FTDCFiles returns the paths of the FTDC data files written by the robot.
+func (r *localRobot) FTDCFiles() ([]string, error) {
+	if r.ftdc == nil {
+		return nil, nil
+	}
+	return r.ftdc.Files()
+}
+
 // Version returns version information about the robot.
 func (r *localRobot) Version(ctx context.Context) (robot.VersionResponse, error) {
 	return robot.Version()
@@ -0,0 +1,48 @@
+package robotimpl
+
+import (
+	"strings"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The files logs are split into by LogFilename.
+const (
+	// serverLogFilename is the file of the logs which are not of a resource or module.
+	serverLogFilename = "viam-server.log"
+	moduleLogPrefix   = "module_"
+	logFileExtension  = ".log"
+)
+
+// LogFilename returns the name of the file under a log directory that the logs of the named logger
+// are written to, such that the logs of each resource and each module are in files of their own.
+// Resource loggers are named after their resource, e.g. "rdk.resource_manager.rdk:component:camera/cam1",
+// whose logs are written to "rdk_component_camera_cam1.log", and module loggers after their module,
+// e.g. "rdk.modmanager.my-module", whose logs are written to "module_my-module.log". The logs of
+// all other loggers are written to "viam-server.log".
+func LogFilename(loggerName string) string {
+	segments := strings.Split(loggerName, ".")
+	for i, segment := range segments {
+		// resource loggers, and the subloggers of resources, are named after the resource
+		if _, err := resource.NewFromString(segment); err == nil {
+			return sanitizeLogFilename(segment) + logFileExtension
+		}
+		if segment == "modmanager" && i+1 < len(segments) {
+			return moduleLogPrefix + sanitizeLogFilename(segments[i+1]) + logFileExtension
+		}
+	}
+	return serverLogFilename
+}
+
+// sanitizeLogFilename replaces the characters of a resource or module name which are not safe in
+// filenames.
+func sanitizeLogFilename(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,22 @@
+package robotimpl
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestLogFilename(t *testing.T) {
+	for loggerName, filename := range map[string]string{
+		"rdk.resource_manager.rdk:component:camera/cam1":           "rdk_component_camera_cam1.log",
+		"rdk.resource_manager.rdk:component:camera/cam1.stream":    "rdk_component_camera_cam1.log",
+		"rdk.resource_manager.acme:service:tracker/remote1:track1": "acme_service_tracker_remote1_track1.log",
+		"rdk.modmanager.my-module":                                 "module_my-module.log",
+		"rdk.modmanager.my-module.StdErr":                          "module_my-module.log",
+		"rdk.modmanager":                                           "viam-server.log",
+		"rdk.networking":                                           "viam-server.log",
+		"":                                                         "viam-server.log",
+	} {
+		test.That(t, LogFilename(loggerName), test.ShouldEqual, filename)
+	}
+}
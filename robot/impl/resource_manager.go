@@ -192,6 +192,19 @@ type internalRemoteRobot interface {
 	robot.Robot
 }
 
+// remoteConfig returns the config of the remote with the given node name, or nil if it has none.
+func (manager *resourceManager) remoteConfig(remoteName resource.Name) *config.Remote {
+	gNode, ok := manager.resources.Node(remoteName)
+	if !ok {
+		return nil
+	}
+	remConf, err := resource.NativeConfig[*config.Remote](gNode.Config())
+	if err != nil {
+		return nil
+	}
+	return remConf
+}
+
 // updateRemoteResourceNames is called when the Remote robot has changed (either connection or disconnection).
 // It will pull the current remote resources and update the resource tree adding or removing nodes accordingly.
 // The recreateAllClients flag will re-add all remote resource nodes if true and only new / uninitialized
@@ -237,9 +250,14 @@ func (manager *resourceManager) updateRemoteResourceNames(
 
 	anythingChanged := false
 
+	remConf := manager.remoteConfig(remoteName)
 	for _, resName := range newResources {
 		remoteResName := resName
 		resLogger := logger.WithFields("resource", remoteResName)
+		if remConf != nil && !remConf.AllowsAPI(resName.API) {
+			resLogger.CDebugw(ctx, "skipping remote resource whose API is not in allowed_apis")
+			continue
+		}
 		res, err := rr.ResourceByName(remoteResName) // this returns a remote known OR foreign resource client
 		if err != nil {
 			if errors.Is(err, client.ErrMissingClientRegistration) {
@@ -251,6 +269,9 @@ func (manager *resourceManager) updateRemoteResourceNames(
 			}
 			continue
 		}
+		if remConf != nil && remConf.Prefix != "" {
+			resName = resource.Name{API: resName.API, Remote: resName.Remote, Name: remConf.Prefix + resName.Name}
+		}
 		resName = resName.PrependRemote(remoteName.Name)
 		gNode, nodeAlreadyExists := manager.resources.Node(resName)
 		if _, alreadyCurrent := activeResourceNames[resName]; alreadyCurrent {
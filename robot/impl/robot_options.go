@@ -1,6 +1,9 @@
 package robotimpl
 
 import (
+	"github.com/benbjohnson/clock"
+
+	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/robot/web"
 )
 
@@ -22,6 +25,15 @@ type options struct {
 	// whether or not to run FTDC
 	enableFTDC bool
 
+	// ftdcPrometheusURL, if set, is a prometheus remote-write endpoint FTDC data is pushed to.
+	ftdcPrometheusURL string
+
+	// recentLogs keeps the most recent logs of the robot for diagnostics bundles.
+	recentLogs *logging.RecentLogsAppender
+
+	// clk, if set, replaces the wall clock for the robot and the resources it builds.
+	clk clock.Clock
+
 	// disableCompleteConfigWorker starts the robot without the complete config worker - should only be used for tests.
 	disableCompleteConfigWorker bool
 }
@@ -55,6 +67,32 @@ func WithFTDC() Option {
 	})
 }
 
+// WithFTDCPrometheus returns an Option which pushes the FTDC data of the robot, if FTDC is enabled,
+// to the prometheus remote-write endpoint at url.
+func WithFTDCPrometheus(url string) Option {
+	return newFuncOption(func(o *options) {
+		o.ftdcPrometheusURL = url
+	})
+}
+
+// WithClock returns an Option which makes the robot expire sessions, write FTDC data and build
+// resources by clk instead of the wall clock. Resources get clk from the context they are built with
+// by way of `utils.ClockFromContext`. Tests use it with a mock clock to advance time instantly
+// instead of sleeping.
+func WithClock(clk clock.Clock) Option {
+	return newFuncOption(func(o *options) {
+		o.clk = clk
+	})
+}
+
+// WithRecentLogs returns an Option which includes the log lines kept by `appender` in the
+// diagnostics bundles of the robot. The appender must be added to the robot's loggers.
+func WithRecentLogs(appender *logging.RecentLogsAppender) Option {
+	return newFuncOption(func(o *options) {
+		o.recentLogs = appender
+	})
+}
+
 // WithWebOptions returns a Option which sets the streamConfig
 // used to enable audio/video streaming over WebRTC.
 func WithWebOptions(opts ...web.Option) Option {
@@ -0,0 +1,64 @@
+package robot
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/robot/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	// LogStreamMetadataKey is the gRPC metadata key carrying the JSON logging.LogFilter of a request
+	// to stream logs, which is served by the robot service's StreamStatus RPC as there is no RPC
+	// for it.
+	LogStreamMetadataKey = "viam-log-stream"
+	// LogStreamBatchSize is the most log entries sent in one StreamStatus response.
+	LogStreamBatchSize = 100
+)
+
+// ErrLogStreamUnsupported is returned when streaming the logs of a machine running an older
+// version or one which doesn't keep its recent logs.
+var ErrLogStreamUnsupported = errors.New("streaming logs is not supported")
+
+// A LogStreamRobot is a Robot that can stream its logs, filtered before they are sent.
+type LogStreamRobot interface {
+	Robot
+
+	// SubscribeLogs streams the logs of the robot which match `filter`. The subscription must be
+	// closed once it's no longer read.
+	SubscribeLogs(filter logging.LogFilter) (*logging.LogSubscription, error)
+}
+
+// logEntryKey is the key of the JSON log entry in each status of a StreamStatus response.
+const logEntryKey = "log"
+
+// LogEntriesToProto converts a batch of streamed log entries to a StreamStatus response.
+func LogEntriesToProto(entries []logging.JSONLogEntry) (*pb.StreamStatusResponse, error) {
+	statuses := make([]*pb.Status, 0, len(entries))
+	for _, entry := range entries {
+		entryJSON, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, &pb.Status{Status: &structpb.Struct{Fields: map[string]*structpb.Value{
+			logEntryKey: structpb.NewStringValue(string(entryJSON)),
+		}}})
+	}
+	return &pb.StreamStatusResponse{Status: statuses}, nil
+}
+
+// LogEntriesFromProto converts a StreamStatus response to the batch of log entries it carries.
+func LogEntriesFromProto(resp *pb.StreamStatusResponse) ([]logging.JSONLogEntry, error) {
+	entries := make([]logging.JSONLogEntry, 0, len(resp.GetStatus()))
+	for _, s := range resp.GetStatus() {
+		var entry logging.JSONLogEntry
+		if err := json.Unmarshal([]byte(s.GetStatus().GetFields()[logEntryKey].GetStringValue()), &entry); err != nil {
+			return nil, errors.Wrap(err, "invalid streamed log entry")
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
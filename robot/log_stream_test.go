@@ -0,0 +1,31 @@
+package robot_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/robot"
+)
+
+func TestLogEntriesProto(t *testing.T) {
+	entries := []logging.JSONLogEntry{
+		{Time: "2024-11-18T20:37:01.000Z", Level: "error", Logger: "rdk", Message: "failed"},
+		{
+			Time:     "2024-11-18T20:37:02.000Z",
+			Level:    "info",
+			Logger:   "rdk.resource_manager.rdk:component:arm/arm1",
+			Resource: "rdk:component:arm/arm1",
+			Message:  "moving",
+			Fields:   json.RawMessage(`{"joint":2}`),
+		},
+	}
+	resp, err := robot.LogEntriesToProto(entries)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.GetStatus(), test.ShouldHaveLength, 2)
+	roundTripped, err := robot.LogEntriesFromProto(resp)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, roundTripped, test.ShouldResemble, entries)
+}
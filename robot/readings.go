@@ -0,0 +1,149 @@
+package robot
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+const (
+	defaultReadingsRateHz = 10
+	maxReadingsRateHz     = 1000
+)
+
+// ReadingsFrame holds the readings of a set of sensors taken together.
+type ReadingsFrame struct {
+	// Time is when the sensors were read.
+	Time time.Time
+	// Readings are the readings of each sensor which was read successfully.
+	Readings map[resource.Name]map[string]interface{}
+	// Errors are the errors of the sensors which could not be read, including those which took
+	// longer than a period to read.
+	Errors map[resource.Name]error
+}
+
+// ReadingsOptions configure StreamReadings.
+type ReadingsOptions struct {
+	// RateHz is how often the sensors are read. Defaults to 10Hz, and is at most 1000Hz.
+	RateHz float64
+	// Extra is passed to each sensor's Readings.
+	Extra map[string]interface{}
+}
+
+// StreamReadings reads the named sensors, which may be any resource with readings such as sensors,
+// movement sensors and power sensors, at opts.RateHz and calls `handle` with a frame of all of
+// their readings each period, until ctx is done or `handle` fails. The sensors are read
+// concurrently, and a sensor which fails or takes longer than a period to read is reported in the
+// frame's errors rather than ending the stream, so one slow sensor doesn't hold up the others.
+//
+// StreamReadings example:
+//
+//	names := []resource.Name{sensor.Named("temperature"), movementsensor.Named("imu")}
+//	err := robot.StreamReadings(ctx, machine, names, robot.ReadingsOptions{RateHz: 20},
+//		func(frame robot.ReadingsFrame) error {
+//			for name, readings := range frame.Readings {
+//				logger.Infof("%v %s: %v", frame.Time, name.ShortName(), readings)
+//			}
+//			return nil
+//		})
+func StreamReadings(
+	ctx context.Context,
+	r Robot,
+	names []resource.Name,
+	opts ReadingsOptions,
+	handle func(ReadingsFrame) error,
+) error {
+	if len(names) == 0 {
+		return errors.New("no sensors to stream readings from")
+	}
+	if opts.RateHz < 0 || opts.RateHz > maxReadingsRateHz {
+		return errors.Errorf("readings rate must be between 0 and %dHz, got %.0fHz", maxReadingsRateHz, opts.RateHz)
+	}
+	if opts.RateHz == 0 {
+		opts.RateHz = defaultReadingsRateHz
+	}
+	period := time.Duration(float64(time.Second) / opts.RateHz)
+
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		// sensors are looked up each period so that reconfigured sensors are followed
+		frame := readFrame(ctx, r, names, opts.Extra, period)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := handle(frame); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// readFrame reads the named sensors concurrently, giving each at most timeout.
+func readFrame(
+	ctx context.Context,
+	r Robot,
+	names []resource.Name,
+	extra map[string]interface{},
+	timeout time.Duration,
+) ReadingsFrame {
+	frame := ReadingsFrame{
+		Time:     time.Now(),
+		Readings: make(map[resource.Name]map[string]interface{}, len(names)),
+		Errors:   map[resource.Name]error{},
+	}
+	readCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		name     resource.Name
+		readings map[string]interface{}
+		err      error
+	}
+	// buffered so that sensors which finish reading after the frame is sent don't block
+	results := make(chan result, len(names))
+	for _, name := range names {
+		go func() {
+			readings, err := readSensor(readCtx, r, name, extra)
+			results <- result{name, readings, err}
+		}()
+	}
+	for range names {
+		select {
+		case res := <-results:
+			if res.err != nil {
+				frame.Errors[res.name] = res.err
+			} else {
+				frame.Readings[res.name] = res.readings
+			}
+		case <-readCtx.Done():
+			// sensors which ignore their context are not waited for
+			for _, name := range names {
+				if _, ok := frame.Readings[name]; !ok && frame.Errors[name] == nil {
+					frame.Errors[name] = readCtx.Err()
+				}
+			}
+			return frame
+		}
+	}
+	return frame
+}
+
+func readSensor(ctx context.Context, r Robot, name resource.Name, extra map[string]interface{}) (map[string]interface{}, error) {
+	res, err := r.ResourceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := res.(resource.Sensor)
+	if !ok {
+		return nil, errors.Errorf("%s has no readings", name)
+	}
+	return s.Readings(ctx, extra)
+}
@@ -0,0 +1,90 @@
+package robot_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestStreamReadings(t *testing.T) {
+	fast := inject.NewSensor("fast")
+	fast.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"temp": 20.5, "extra": extra["unit"]}, nil
+	}
+	slow := inject.NewSensor("slow")
+	slow.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		// takes longer than a period, so is reported as timed out
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			return map[string]interface{}{}, nil
+		}
+	}
+	broken := inject.NewSensor("broken")
+	errBroken := errors.New("broken")
+	broken.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return nil, errBroken
+	}
+	resources := map[resource.Name]resource.Resource{
+		sensor.Named("fast"):   fast,
+		sensor.Named("slow"):   slow,
+		sensor.Named("broken"): broken,
+		arm.Named("arm"):       inject.NewArm("arm"),
+	}
+	r := &inject.Robot{}
+	r.ResourceByNameFunc = func(name resource.Name) (resource.Resource, error) {
+		res, ok := resources[name]
+		if !ok {
+			return nil, resource.NewNotFoundError(name)
+		}
+		return res, nil
+	}
+
+	names := []resource.Name{sensor.Named("fast"), sensor.Named("slow"), sensor.Named("broken"), arm.Named("arm"), sensor.Named("missing")}
+	var frames []robot.ReadingsFrame
+	start := time.Now()
+	err := robot.StreamReadings(context.Background(), r, names, robot.ReadingsOptions{RateHz: 50, Extra: map[string]interface{}{"unit": "C"}},
+		func(frame robot.ReadingsFrame) error {
+			frames = append(frames, frame)
+			if len(frames) == 5 {
+				return errors.New("done")
+			}
+			return nil
+		})
+	test.That(t, err, test.ShouldBeError, errors.New("done"))
+	// the slow sensor doesn't hold up the stream
+	test.That(t, time.Since(start), test.ShouldBeLessThan, 500*time.Millisecond)
+
+	for _, frame := range frames {
+		test.That(t, frame.Readings, test.ShouldResemble, map[resource.Name]map[string]interface{}{
+			sensor.Named("fast"): {"temp": 20.5, "extra": "C"},
+		})
+		test.That(t, frame.Errors, test.ShouldHaveLength, 4)
+		test.That(t, frame.Errors[sensor.Named("slow")], test.ShouldBeError, context.DeadlineExceeded)
+		test.That(t, frame.Errors[sensor.Named("broken")], test.ShouldBeError, errBroken)
+		test.That(t, frame.Errors[arm.Named("arm")].Error(), test.ShouldContainSubstring, "has no readings")
+		test.That(t, resource.IsNotFoundError(frame.Errors[sensor.Named("missing")]), test.ShouldBeTrue)
+	}
+	test.That(t, frames[4].Time.Sub(frames[0].Time), test.ShouldBeGreaterThanOrEqualTo, 60*time.Millisecond)
+
+	// the stream ends with its context
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = robot.StreamReadings(ctx, r, names[:1], robot.ReadingsOptions{}, func(robot.ReadingsFrame) error { return nil })
+	test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+
+	err = robot.StreamReadings(ctx, r, nil, robot.ReadingsOptions{}, func(robot.ReadingsFrame) error { return nil })
+	test.That(t, err, test.ShouldNotBeNil)
+	err = robot.StreamReadings(ctx, r, names, robot.ReadingsOptions{RateHz: 5000}, func(robot.ReadingsFrame) error { return nil })
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -14,6 +14,7 @@ import (
 
 	"go.viam.com/rdk/cloud"
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/ftdc"
 	"go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
@@ -193,6 +194,20 @@ type LocalRobot interface {
 	Kill()
 }
 
+// An FTDCRobot is a Robot that records FTDC and can return the metrics it recorded most recently.
+type FTDCRobot interface {
+	Robot
+
+	// LatestFTDC returns the most recently recorded FTDC datum, or false if FTDC is not enabled or
+	// nothing has been recorded yet.
+	LatestFTDC() (ftdc.FlatDatum, bool)
+
+	// SubscribeFTDC returns a stream of the FTDC datums recorded from now on, limited to metrics
+	// starting with one of `metricPrefixes`. See `ftdc.FTDC.Subscribe`. It returns false if FTDC is
+	// not enabled. Callers must `Unsubscribe` when done.
+	SubscribeFTDC(bufferSize int, metricPrefixes ...string) (*ftdc.Subscription, bool)
+}
+
 // A RemoteRobot is a Robot that was created through a connection.
 type RemoteRobot interface {
 	Robot
@@ -4,8 +4,10 @@
 package server
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -20,6 +22,9 @@ import (
 	"go.viam.com/utils"
 	vprotoutils "go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -541,6 +546,137 @@ func (s *Server) GetMachineStatus(ctx context.Context, _ *pb.GetMachineStatusReq
 	return &result, nil
 }
 
+// GetStatus reads the state of the named resources in bulk, such as the readings of sensors and the
+// positions of actuators, see robot.BulkRead. The robot.BulkReadOptions of the read are carried in the
+// robot.BulkReadOptionsMetadataKey metadata of the request.
+func (s *Server) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	if len(req.ResourceNames) == 0 {
+		return nil, errors.New("no resources to read")
+	}
+	var opts robot.BulkReadOptions
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(robot.BulkReadOptionsMetadataKey); len(values) > 0 {
+			if err := json.Unmarshal([]byte(values[0]), &opts); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", robot.BulkReadOptionsMetadataKey, err)
+			}
+		}
+	}
+	names := make([]resource.Name, 0, len(req.ResourceNames))
+	for _, name := range req.ResourceNames {
+		names = append(names, protoutils.ResourceNameFromProto(name))
+	}
+	readings := robot.BulkRead(ctx, s.robot, names, opts)
+	resp := &pb.GetStatusResponse{Status: make([]*pb.Status, 0, len(readings))}
+	for _, reading := range readings {
+		status, err := robot.ResourceReadingToProto(reading)
+		if err != nil {
+			// readings which can't be sent are reported as errors rather than failing the others
+			reading.Err = err
+			if status, err = robot.ResourceReadingToProto(reading); err != nil {
+				return nil, err
+			}
+		}
+		resp.Status = append(resp.Status, status)
+	}
+	return resp, nil
+}
+
+// StreamStatus streams a diagnostics bundle of the robot in chunks, see robot.WriteDiagnosticsBundle,
+// when the request carries its robot.DiagnosticsOptions in the robot.DiagnosticsBundleMetadataKey
+// metadata, or the logs of the robot when the request carries a logging.LogFilter in the
+// robot.LogStreamMetadataKey metadata. Streaming statuses is otherwise unimplemented.
+func (s *Server) StreamStatus(req *pb.StreamStatusRequest, stream pb.RobotService_StreamStatusServer) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	if filters := md.Get(robot.LogStreamMetadataKey); len(filters) > 0 {
+		return s.streamLogs(filters[0], stream)
+	}
+	values := md.Get(robot.DiagnosticsBundleMetadataKey)
+	if len(values) == 0 {
+		return status.Error(codes.Unimplemented, "method StreamStatus not implemented")
+	}
+	var opts robot.DiagnosticsOptions
+	if err := json.Unmarshal([]byte(values[0]), &opts); err != nil {
+		return fmt.Errorf("invalid %s: %w", robot.DiagnosticsBundleMetadataKey, err)
+	}
+	w := bufio.NewWriterSize(diagnosticsChunkWriter{stream}, robot.DiagnosticsChunkSize)
+	if err := robot.WriteDiagnosticsBundle(stream.Context(), s.robot, opts, w); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// streamLogs streams the logs of the robot which match the JSON logging.LogFilter until the client
+// cancels the stream. Entries are batched as they arrive, and entries dropped because the client
+// fell behind are reported in an entry of their own.
+func (s *Server) streamLogs(filterJSON string, stream pb.RobotService_StreamStatusServer) error {
+	lr, ok := s.robot.(robot.LogStreamRobot)
+	if !ok {
+		return status.Error(codes.Unimplemented, robot.ErrLogStreamUnsupported.Error())
+	}
+	var filter logging.LogFilter
+	if err := json.Unmarshal([]byte(filterJSON), &filter); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid %s: %v", robot.LogStreamMetadataKey, err)
+	}
+	sub, err := lr.SubscribeLogs(filter)
+	if err != nil {
+		if errors.Is(err, robot.ErrLogStreamUnsupported) {
+			return status.Error(codes.Unimplemented, err.Error())
+		}
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	defer sub.Close()
+
+	var reportedDropped int64
+	for {
+		var batch []logging.JSONLogEntry
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case entry := <-sub.Logs():
+			batch = append(batch, entry)
+		}
+	drain:
+		for len(batch) < robot.LogStreamBatchSize {
+			select {
+			case entry := <-sub.Logs():
+				batch = append(batch, entry)
+			default:
+				break drain
+			}
+		}
+		if dropped := sub.Dropped(); dropped > reportedDropped {
+			batch = append(batch, logging.JSONLogEntry{
+				Time:    time.Now().UTC().Format(logging.DefaultTimeFormatStr),
+				Level:   "warn",
+				Logger:  "rdk.log_stream",
+				Message: fmt.Sprintf("%d log entries were dropped as the stream fell behind", dropped-reportedDropped),
+			})
+			reportedDropped = dropped
+		}
+		resp, err := robot.LogEntriesToProto(batch)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// diagnosticsChunkWriter sends each write as a chunk of a diagnostics bundle.
+type diagnosticsChunkWriter struct {
+	stream pb.RobotService_StreamStatusServer
+}
+
+func (w diagnosticsChunkWriter) Write(p []byte) (int, error) {
+	for sent := 0; sent < len(p); sent += robot.DiagnosticsChunkSize {
+		if err := w.stream.Send(robot.DiagnosticsChunkToProto(p[sent:min(len(p), sent+robot.DiagnosticsChunkSize)])); err != nil {
+			return sent, err
+		}
+	}
+	return len(p), nil
+}
+
 // GetVersion returns version information about the robot.
 func (s *Server) GetVersion(ctx context.Context, _ *pb.GetVersionRequest) (*pb.GetVersionResponse, error) {
 	result, err := robot.Version()
@@ -5,25 +5,37 @@ import (
 	"sync"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
-	"go.viam.com/utils"
 
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/session"
+	rutils "go.viam.com/rdk/utils"
 )
 
 // NewSessionManager creates a new manager for holding sessions.
 func NewSessionManager(robot Robot, heartbeatWindow time.Duration) *SessionManager {
+	return NewSessionManagerWithClock(robot, heartbeatWindow, clock.New())
+}
+
+// NewSessionManagerWithClock creates a new manager for holding sessions that expires them by clk.
+func NewSessionManagerWithClock(robot Robot, heartbeatWindow time.Duration, clk clock.Clock) *SessionManager {
 	m := &SessionManager{
 		robot:             robot,
 		heartbeatWindow:   heartbeatWindow,
+		clk:               clk,
 		logger:            robot.Logger().Sublogger("networking.session_manager"),
 		sessions:          map[uuid.UUID]*session.Session{},
 		resourceToSession: map[resource.Name]uuid.UUID{},
 	}
-	m.workers = utils.NewBackgroundStoppableWorkers(m.expireLoop)
+	m.expireWorker = rutils.NewPeriodicWorker(rutils.PeriodicWorkerConfig{
+		Name:     "session_manager.expire",
+		Interval: 10 * time.Millisecond,
+		Clock:    clk,
+		Logger:   m.logger,
+	}, m.expireSessions)
 	return m
 }
 
@@ -32,6 +44,7 @@ func NewSessionManager(robot Robot, heartbeatWindow time.Duration) *SessionManag
 type SessionManager struct {
 	robot           Robot
 	heartbeatWindow time.Duration
+	clk             clock.Clock
 	logger          logging.Logger
 
 	sessionResourceMu sync.RWMutex
@@ -39,7 +52,7 @@ type SessionManager struct {
 
 	resourceToSession map[resource.Name]uuid.UUID
 
-	workers *utils.StoppableWorkers
+	expireWorker *rutils.PeriodicWorker
 }
 
 // All returns all active sessions.
@@ -53,96 +66,89 @@ func (m *SessionManager) All() []*session.Session {
 	return sessions
 }
 
-func (m *SessionManager) expireLoop(ctx context.Context) {
-	ticker := time.NewTicker(10 * time.Millisecond)
-	defer ticker.Stop()
-
-	for {
-		if !utils.SelectContextOrWaitChan(ctx, ticker.C) {
-			return
-		}
+// expireSessions deletes the sessions that have expired and stops the resources associated with
+// them.
+func (m *SessionManager) expireSessions(ctx context.Context) {
+	now := m.clk.Now()
 
-		now := time.Now()
-
-		toDelete := map[uuid.UUID]struct{}{}
-		var toStop []resource.Name
-		m.sessionResourceMu.RLock()
-		for id, sess := range m.sessions {
-			if !sess.Active(now) {
-				toDelete[id] = struct{}{}
-			}
+	toDelete := map[uuid.UUID]struct{}{}
+	var toStop []resource.Name
+	m.sessionResourceMu.RLock()
+	for id, sess := range m.sessions {
+		if !sess.Active(now) {
+			toDelete[id] = struct{}{}
 		}
-		for res, sess := range m.resourceToSession {
-			if _, ok := toDelete[sess]; ok {
-				resCopy := res
-				toStop = append(toStop, resCopy)
-			}
+	}
+	for res, sess := range m.resourceToSession {
+		if _, ok := toDelete[sess]; ok {
+			resCopy := res
+			toStop = append(toStop, resCopy)
 		}
-		m.sessionResourceMu.RUnlock()
+	}
+	m.sessionResourceMu.RUnlock()
 
-		var resourceErrs []error
-		var serverClosing bool
-		func() {
-			m.sessionResourceMu.Lock()
-			defer m.sessionResourceMu.Unlock()
-			for id := range toDelete {
-				delete(m.sessions, id)
-			}
+	var resourceErrs []error
+	var serverClosing bool
+	func() {
+		m.sessionResourceMu.Lock()
+		defer m.sessionResourceMu.Unlock()
+		for id := range toDelete {
+			delete(m.sessions, id)
+		}
 
-			if len(toStop) == 0 {
-				return
-			}
-			for _, resName := range toStop {
-				func() {
-					defer func() {
-						if err := recover(); err != nil {
-							resourceErrs = append(resourceErrs, errors.Errorf("panic stopping %q: %v", resName, err))
-						}
-					}()
-					res, err := m.robot.ResourceByName(resName)
-					if err != nil {
-						// It's possible at this point that the robot is Closing, the
-						// resource manager has already been closed, and the resource
-						// associated with the session has been removed from the graph and
-						// cannot be found. If the error is a not found error and the
-						// context has errored, return without appending to resourceErrs
-						// and set serverClosing to true.
-						if resource.IsNotFoundError(err) && ctx.Err() != nil {
-							serverClosing = true
-							return
-						}
-						resourceErrs = append(resourceErrs, err)
+		if len(toStop) == 0 {
+			return
+		}
+		for _, resName := range toStop {
+			func() {
+				defer func() {
+					if err := recover(); err != nil {
+						resourceErrs = append(resourceErrs, errors.Errorf("panic stopping %q: %v", resName, err))
+					}
+				}()
+				res, err := m.robot.ResourceByName(resName)
+				if err != nil {
+					// It's possible at this point that the robot is Closing, the
+					// resource manager has already been closed, and the resource
+					// associated with the session has been removed from the graph and
+					// cannot be found. If the error is a not found error and the
+					// context has errored, return without appending to resourceErrs
+					// and set serverClosing to true.
+					if resource.IsNotFoundError(err) && ctx.Err() != nil {
+						serverClosing = true
 						return
 					}
+					resourceErrs = append(resourceErrs, err)
+					return
+				}
 
-					if actuator, ok := res.(resource.Actuator); ok {
-						if err := actuator.Stop(ctx, nil); err != nil {
-							resourceErrs = append(resourceErrs, err)
-						}
+				if actuator, ok := res.(resource.Actuator); ok {
+					if err := actuator.Stop(ctx, nil); err != nil {
+						resourceErrs = append(resourceErrs, err)
 					}
-				}()
-				if serverClosing {
-					return
 				}
+			}()
+			if serverClosing {
+				return
 			}
-		}()
-		if serverClosing {
-			return
 		}
+	}()
+	if serverClosing {
+		return
+	}
 
-		if len(toDelete) != 0 {
-			var deletedIDs []string
-			for id := range toDelete {
-				deletedIDs = append(deletedIDs, id.String())
-			}
-			m.logger.CDebugw(ctx, "sessions expired", "session_ids", deletedIDs)
-		}
-		if len(toStop) != 0 {
-			m.logger.CDebugw(ctx, "tried to stop some resources", "resources", toStop)
-		}
-		if len(resourceErrs) != 0 {
-			m.logger.CErrorw(ctx, "failed to stop some resources", "errors", resourceErrs)
+	if len(toDelete) != 0 {
+		var deletedIDs []string
+		for id := range toDelete {
+			deletedIDs = append(deletedIDs, id.String())
 		}
+		m.logger.CDebugw(ctx, "sessions expired", "session_ids", deletedIDs)
+	}
+	if len(toStop) != 0 {
+		m.logger.CDebugw(ctx, "tried to stop some resources", "resources", toStop)
+	}
+	if len(resourceErrs) != 0 {
+		m.logger.CErrorw(ctx, "failed to stop some resources", "errors", resourceErrs)
 	}
 }
 
@@ -152,7 +158,7 @@ const (
 
 // Start creates a new session that expects at least one heartbeat within the configured window.
 func (m *SessionManager) Start(ctx context.Context, ownerID string) (*session.Session, error) {
-	sess := session.New(ctx, ownerID, m.heartbeatWindow, m.AssociateResource)
+	sess := session.New(rutils.WithClock(ctx, m.clk), ownerID, m.heartbeatWindow, m.AssociateResource)
 	m.sessionResourceMu.Lock()
 	if len(m.sessions) > maxSessions {
 		return nil, errors.New("too many concurrent sessions")
@@ -191,5 +197,5 @@ func (m *SessionManager) AssociateResource(id uuid.UUID, resourceName resource.N
 
 // Close stops the session manager but will not explicitly expire any sessions.
 func (m *SessionManager) Close() {
-	m.workers.Stop()
+	m.expireWorker.Stop()
 }
@@ -0,0 +1,171 @@
+package robot
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+)
+
+// StateSnapshotter captures and restores the restorable state of the resources of an API, such as
+// the position of a servo or the mode of a navigation service. The captured state must be
+// marshalable to JSON, and restoring must accept it after a round trip through JSON.
+type StateSnapshotter struct {
+	Capture func(ctx context.Context, res resource.Resource) (map[string]interface{}, error)
+	Restore func(ctx context.Context, res resource.Resource, state map[string]interface{}) error
+}
+
+var (
+	stateSnapshottersMu sync.RWMutex
+	stateSnapshotters   = map[resource.API]StateSnapshotter{}
+)
+
+// RegisterStateSnapshotter registers how the resources of an API are snapshotted. Resources of APIs
+// without a snapshotter have no restorable state.
+func RegisterStateSnapshotter(api resource.API, snapshotter StateSnapshotter) {
+	stateSnapshottersMu.Lock()
+	defer stateSnapshottersMu.Unlock()
+	stateSnapshotters[api] = snapshotter
+}
+
+func lookupStateSnapshotter(api resource.API) (StateSnapshotter, bool) {
+	stateSnapshottersMu.RLock()
+	defer stateSnapshottersMu.RUnlock()
+	snapshotter, ok := stateSnapshotters[api]
+	return snapshotter, ok
+}
+
+// Snapshot is the restorable state of a machine at a point in time. It marshals to JSON, such that
+// it can be saved to disk and restored after the machine power cycles.
+type Snapshot struct {
+	// Time is when the snapshot was taken.
+	Time time.Time
+	// States are the states of each snapshotted resource.
+	States map[resource.Name]map[string]interface{}
+	// Transforms are named frame transforms that are not part of the machine's config, such as the
+	// pose of a fixture located at runtime. They are not captured from the machine, but saved with
+	// the snapshot so that they can be passed to motion planning again after restoring.
+	Transforms []*referenceframe.LinkConfig
+}
+
+type snapshotJSON struct {
+	Time       time.Time                         `json:"time"`
+	States     map[string]map[string]interface{} `json:"states"`
+	Transforms []*referenceframe.LinkConfig      `json:"transforms,omitempty"`
+}
+
+// MarshalJSON marshals the snapshot with resources named by their fully qualified names.
+func (snap Snapshot) MarshalJSON() ([]byte, error) {
+	j := snapshotJSON{
+		Time:       snap.Time,
+		States:     make(map[string]map[string]interface{}, len(snap.States)),
+		Transforms: snap.Transforms,
+	}
+	for name, state := range snap.States {
+		j.States[name.String()] = state
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON unmarshals a snapshot marshaled by MarshalJSON.
+func (snap *Snapshot) UnmarshalJSON(data []byte) error {
+	var j snapshotJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*snap = Snapshot{
+		Time:       j.Time,
+		States:     make(map[resource.Name]map[string]interface{}, len(j.States)),
+		Transforms: j.Transforms,
+	}
+	for nameStr, state := range j.States {
+		name, err := resource.NewFromString(nameStr)
+		if err != nil {
+			return errors.Wrapf(err, "invalid snapshot resource name %q", nameStr)
+		}
+		snap.States[name] = state
+	}
+	return nil
+}
+
+// TakeSnapshot captures the restorable state of the named resources, or of every resource with
+// restorable state if `names` is empty. It fails if any named resource has no restorable state or
+// cannot be captured, rather than returning a snapshot that would silently not restore it.
+//
+// TakeSnapshot example:
+//
+//	snap, err := robot.TakeSnapshot(ctx, machine, nil)
+//	data, err := json.Marshal(snap)
+//	err = os.WriteFile("/var/lib/workcell/snapshot.json", data, 0o600)
+func TakeSnapshot(ctx context.Context, r Robot, names []resource.Name) (*Snapshot, error) {
+	if len(names) == 0 {
+		for _, name := range r.ResourceNames() {
+			if _, ok := lookupStateSnapshotter(name.API); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	snap := &Snapshot{Time: time.Now(), States: make(map[resource.Name]map[string]interface{}, len(names))}
+	var errs error
+	for _, name := range names {
+		snapshotter, ok := lookupStateSnapshotter(name.API)
+		if !ok {
+			errs = multierr.Append(errs, errors.Errorf("%s has no restorable state", name))
+			continue
+		}
+		var state map[string]interface{}
+		res, err := r.ResourceByName(name)
+		if err == nil {
+			state, err = snapshotter.Capture(ctx, res)
+		}
+		if err != nil {
+			errs = multierr.Append(errs, errors.Wrapf(err, "cannot capture the state of %s", name))
+			continue
+		}
+		snap.States[name] = state
+	}
+	if errs != nil {
+		return nil, errs
+	}
+	return snap, nil
+}
+
+// RestoreSnapshot restores the state of each resource in the snapshot, one at a time in the order of
+// their names, such that restoring the same snapshot always moves actuators in the same order. A
+// resource that fails to restore does not stop the others from being restored, and all failures are
+// returned.
+func RestoreSnapshot(ctx context.Context, r Robot, snap *Snapshot) error {
+	names := make([]resource.Name, 0, len(snap.States))
+	for name := range snap.States {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i].String() < names[j].String() })
+
+	var errs error
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return multierr.Append(errs, err)
+		}
+		snapshotter, ok := lookupStateSnapshotter(name.API)
+		if !ok {
+			errs = multierr.Append(errs, errors.Errorf("%s has no restorable state", name))
+			continue
+		}
+		res, err := r.ResourceByName(name)
+		if err == nil {
+			err = snapshotter.Restore(ctx, res, snap.States[name])
+		}
+		if err != nil {
+			errs = multierr.Append(errs, errors.Wrapf(err, "cannot restore the state of %s", name))
+		}
+	}
+	return errs
+}
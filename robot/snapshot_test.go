@@ -0,0 +1,105 @@
+package robot_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/gantry"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/components/servo"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/navigation"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	// restored records the order resources were restored in, and to what.
+	var restored []interface{}
+	s := inject.NewServo("servo")
+	servoPosition := uint32(90)
+	s.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (uint32, error) {
+		return servoPosition, nil
+	}
+	s.MoveFunc = func(ctx context.Context, angleDeg uint32, extra map[string]interface{}) error {
+		restored = append(restored, angleDeg)
+		return nil
+	}
+	g := inject.NewGantry("gantry")
+	gantryPositions := []float64{100, 250.5}
+	g.PositionFunc = func(ctx context.Context, extra map[string]interface{}) ([]float64, error) {
+		return gantryPositions, nil
+	}
+	g.MoveToPositionFunc = func(ctx context.Context, pos, speed []float64, extra map[string]interface{}) error {
+		restored = append(restored, pos)
+		return nil
+	}
+	nav := inject.NewNavigationService("nav")
+	navMode := navigation.ModeWaypoint
+	nav.ModeFunc = func(ctx context.Context, extra map[string]interface{}) (navigation.Mode, error) {
+		return navMode, nil
+	}
+	nav.SetModeFunc = func(ctx context.Context, mode navigation.Mode, extra map[string]interface{}) error {
+		restored = append(restored, mode)
+		return nil
+	}
+	resources := map[resource.Name]resource.Resource{
+		servo.Named("servo"):    s,
+		gantry.Named("gantry"):  g,
+		navigation.Named("nav"): nav,
+		sensor.Named("sensor"):  inject.NewSensor("sensor"),
+	}
+	r := &inject.Robot{}
+	r.MockResourcesFromMap(resources)
+
+	// Only resources with restorable state are snapshotted.
+	snap, err := robot.TakeSnapshot(ctx, r, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, snap.States, test.ShouldHaveLength, 3)
+	test.That(t, snap.States[servo.Named("servo")], test.ShouldResemble, map[string]interface{}{"position_deg": uint32(90)})
+	test.That(t, snap.States[navigation.Named("nav")], test.ShouldResemble, map[string]interface{}{"mode": "Waypoint"})
+
+	// The snapshot survives being saved and loaded, e.g: across a power cycle.
+	snap.Transforms = []*referenceframe.LinkConfig{{ID: "fixture", Parent: referenceframe.World, Translation: r3.Vector{X: 1}}}
+	data, err := json.Marshal(snap)
+	test.That(t, err, test.ShouldBeNil)
+	var loaded robot.Snapshot
+	test.That(t, json.Unmarshal(data, &loaded), test.ShouldBeNil)
+	test.That(t, loaded.Time.Equal(snap.Time), test.ShouldBeTrue)
+	test.That(t, loaded.Transforms, test.ShouldHaveLength, 1)
+	test.That(t, loaded.Transforms[0].ID, test.ShouldEqual, "fixture")
+
+	servoPosition, gantryPositions, navMode = 0, []float64{0, 0}, navigation.ModeManual
+	test.That(t, robot.RestoreSnapshot(ctx, r, &loaded), test.ShouldBeNil)
+	// Resources are restored in the order of their names.
+	test.That(t, restored, test.ShouldResemble, []interface{}{
+		[]float64{100, 250.5}, uint32(90), navigation.ModeWaypoint,
+	})
+
+	t.Run("no restorable state", func(t *testing.T) {
+		_, err := robot.TakeSnapshot(ctx, r, []resource.Name{servo.Named("servo"), sensor.Named("sensor")})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "has no restorable state")
+	})
+
+	t.Run("restore continues past failures", func(t *testing.T) {
+		restored = nil
+		snap := &robot.Snapshot{States: map[resource.Name]map[string]interface{}{
+			gantry.Named("gantry"): {"positions_mm": "bad"},
+			servo.Named("missing"): {"position_deg": 10.},
+			servo.Named("servo"):   {"position_deg": 45.},
+		}}
+		err := robot.RestoreSnapshot(ctx, r, snap)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "invalid gantry state")
+		test.That(t, err.Error(), test.ShouldContainSubstring, "missing")
+		test.That(t, restored, test.ShouldResemble, []interface{}{uint32(45)})
+	})
+}
@@ -0,0 +1,154 @@
+package web
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"slices"
+	"time"
+
+	"go.viam.com/utils/rpc"
+	"goji.io"
+	"goji.io/pat"
+	"google.golang.org/grpc/metadata"
+
+	weboptions "go.viam.com/rdk/robot/web/options"
+)
+
+// debugGoroutineFunctions is how many of the functions running the most goroutines are listed in
+// runtime snapshots.
+const debugGoroutineFunctions = 20
+
+// initDebugHandlers serves pprof and runtime snapshots under /debug when profiling is enabled.
+// When the web service requires authentication, so do they, with an access token in a
+// "Authorization: Bearer <token>" header as returned by the Authenticate RPC.
+func (svc *webService) initDebugHandlers(mux *goji.Mux, options weboptions.Options) {
+	if !options.Pprof {
+		return
+	}
+	handle := func(path string, handler http.HandlerFunc) {
+		if len(options.Auth.Handlers) != 0 {
+			handler = svc.requireAuth(handler)
+		}
+		mux.HandleFunc(pat.New(path), handler)
+	}
+	handle("/debug/pprof/", pprof.Index)
+	handle("/debug/pprof/*", pprof.Index)
+	handle("/debug/pprof/cmdline", pprof.Cmdline)
+	handle("/debug/pprof/profile", pprof.Profile)
+	handle("/debug/pprof/symbol", pprof.Symbol)
+	handle("/debug/pprof/trace", pprof.Trace)
+	handle("/debug/runtime", svc.handleRuntimeSnapshot)
+}
+
+// requireAuth only calls `handler` for requests authenticated the same way as gRPC requests.
+func (svc *webService) requireAuth(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		md := metadata.Pairs(rpc.MetadataFieldAuthorization, r.Header.Get("Authorization"))
+		ctx, err := svc.rpcServer.EnsureAuthed(metadata.NewIncomingContext(r.Context(), md))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r.WithContext(ctx))
+	}
+}
+
+// runtimeSnapshot is the state of the Go runtime served by /debug/runtime.
+type runtimeSnapshot struct {
+	Time       time.Time `json:"time"`
+	GoVersion  string    `json:"go_version"`
+	NumCPU     int       `json:"num_cpu"`
+	GOMAXPROCS int       `json:"gomaxprocs"`
+	CgoCalls   int64     `json:"cgo_calls"`
+	Goroutines int       `json:"goroutines"`
+	// GoroutinesByFunction counts the goroutines by the function each is running, most first.
+	GoroutinesByFunction []goroutineCount `json:"goroutines_by_function"`
+	Memory               memorySnapshot   `json:"memory"`
+}
+
+type goroutineCount struct {
+	Function string `json:"function"`
+	Count    int    `json:"count"`
+}
+
+type memorySnapshot struct {
+	HeapAllocBytes   uint64 `json:"heap_alloc_bytes"`
+	HeapInuseBytes   uint64 `json:"heap_inuse_bytes"`
+	HeapObjects      uint64 `json:"heap_objects"`
+	StackInuseBytes  uint64 `json:"stack_inuse_bytes"`
+	SysBytes         uint64 `json:"sys_bytes"`
+	TotalAllocBytes  uint64 `json:"total_alloc_bytes"`
+	Mallocs          uint64 `json:"mallocs"`
+	Frees            uint64 `json:"frees"`
+	NumGC            uint32 `json:"num_gc"`
+	GCPauseTotalNs   uint64 `json:"gc_pause_total_ns"`
+	LastGCPauseNs    uint64 `json:"last_gc_pause_ns"`
+	NextGCTargetSize uint64 `json:"next_gc_target_bytes"`
+}
+
+func (svc *webService) handleRuntimeSnapshot(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(takeRuntimeSnapshot()); err != nil {
+		svc.logger.Warnw("unable to write runtime snapshot", "error", err)
+	}
+}
+
+func takeRuntimeSnapshot() runtimeSnapshot {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	snapshot := runtimeSnapshot{
+		Time:       time.Now(),
+		GoVersion:  runtime.Version(),
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		CgoCalls:   runtime.NumCgoCall(),
+		Memory: memorySnapshot{
+			HeapAllocBytes:   mem.HeapAlloc,
+			HeapInuseBytes:   mem.HeapInuse,
+			HeapObjects:      mem.HeapObjects,
+			StackInuseBytes:  mem.StackInuse,
+			SysBytes:         mem.Sys,
+			TotalAllocBytes:  mem.TotalAlloc,
+			Mallocs:          mem.Mallocs,
+			Frees:            mem.Frees,
+			NumGC:            mem.NumGC,
+			GCPauseTotalNs:   mem.PauseTotalNs,
+			LastGCPauseNs:    mem.PauseNs[(mem.NumGC+255)%256],
+			NextGCTargetSize: mem.NextGC,
+		},
+	}
+
+	// Goroutines may start between counting and collecting them, so leave room for some.
+	records := make([]runtime.StackRecord, runtime.NumGoroutine()+64)
+	n, ok := runtime.GoroutineProfile(records)
+	if !ok {
+		snapshot.Goroutines = runtime.NumGoroutine()
+		return snapshot
+	}
+	snapshot.Goroutines = n
+	counts := map[string]int{}
+	for _, record := range records[:n] {
+		function := "unknown"
+		if stack := record.Stack(); len(stack) > 0 {
+			frame, _ := runtime.CallersFrames(stack).Next()
+			function = frame.Function
+		}
+		counts[function]++
+	}
+	for function, count := range counts {
+		snapshot.GoroutinesByFunction = append(snapshot.GoroutinesByFunction, goroutineCount{function, count})
+	}
+	slices.SortFunc(snapshot.GoroutinesByFunction, func(a, b goroutineCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Function, b.Function))
+	})
+	if len(snapshot.GoroutinesByFunction) > debugGoroutineFunctions {
+		snapshot.GoroutinesByFunction = snapshot.GoroutinesByFunction[:debugGoroutineFunctions]
+	}
+	return snapshot
+}
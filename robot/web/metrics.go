@@ -0,0 +1,126 @@
+package web
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"goji.io"
+	"goji.io/pat"
+
+	"go.viam.com/rdk/robot"
+	weboptions "go.viam.com/rdk/robot/web/options"
+)
+
+//go:embed metrics.html
+var metricsPage []byte
+
+// latestMetrics is the most recently recorded FTDC datum as served by /debug/metrics/latest.
+type latestMetrics struct {
+	// TimeMs is when the metrics were recorded, in milliseconds since the epoch.
+	TimeMs   int64              `json:"time_ms"`
+	Readings map[string]float32 `json:"readings"`
+}
+
+// initMetricsHandlers serves a page charting FTDC metrics live at /debug/metrics, the latest
+// recorded metrics it polls at /debug/metrics/latest, and a stream of newly recorded metrics at
+// /debug/metrics/stream. The metrics require authentication the same way as the other debug
+// handlers.
+func (svc *webService) initMetricsHandlers(mux *goji.Mux, options weboptions.Options) {
+	mux.HandleFunc(pat.New("/debug/metrics"), func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if _, err := w.Write(metricsPage); err != nil {
+			svc.logger.Warnw("unable to write metrics page", "error", err)
+		}
+	})
+	latestHandler, streamHandler := svc.handleLatestMetrics, svc.handleStreamMetrics
+	if len(options.Auth.Handlers) != 0 {
+		latestHandler = svc.requireAuth(latestHandler)
+		streamHandler = svc.requireAuth(streamHandler)
+	}
+	mux.HandleFunc(pat.New("/debug/metrics/latest"), latestHandler)
+	mux.HandleFunc(pat.New("/debug/metrics/stream"), streamHandler)
+}
+
+// handleLatestMetrics writes the most recently recorded FTDC metrics. Only metrics starting with
+// one of the "metric" query parameters are written, or all of them if there are none.
+func (svc *webService) handleLatestMetrics(w http.ResponseWriter, r *http.Request) {
+	ftdcRobot, ok := svc.r.(robot.FTDCRobot)
+	if !ok {
+		http.Error(w, "robot does not record metrics", http.StatusNotFound)
+		return
+	}
+	latest, ok := ftdcRobot.LatestFTDC()
+	if !ok {
+		http.Error(w, "no metrics have been recorded", http.StatusServiceUnavailable)
+		return
+	}
+
+	prefixes := r.URL.Query()["metric"]
+	metrics := latestMetrics{TimeMs: latest.ConvertedTime().UnixMilli(), Readings: map[string]float32{}}
+	for _, reading := range latest.Readings {
+		if len(prefixes) != 0 && !hasAnyPrefix(reading.MetricName, prefixes) {
+			continue
+		}
+		metrics.Readings[reading.MetricName] = reading.Value
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(metrics); err != nil {
+		svc.logger.Warnw("unable to write latest metrics", "error", err)
+	}
+}
+
+// handleStreamMetrics writes each FTDC datum as it is recorded, as one JSON object per line, until
+// the client disconnects. Metrics are filtered by the "metric" query parameters the same way as
+// for /debug/metrics/latest.
+func (svc *webService) handleStreamMetrics(w http.ResponseWriter, r *http.Request) {
+	ftdcRobot, ok := svc.r.(robot.FTDCRobot)
+	if !ok {
+		http.Error(w, "robot does not record metrics", http.StatusNotFound)
+		return
+	}
+	sub, ok := ftdcRobot.SubscribeFTDC(0, r.URL.Query()["metric"]...)
+	if !ok {
+		http.Error(w, "robot does not record metrics", http.StatusNotFound)
+		return
+	}
+	defer sub.Unsubscribe()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-store")
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case datum, open := <-sub.C():
+			if !open {
+				return
+			}
+			metrics := latestMetrics{TimeMs: datum.ConvertedTime().UnixMilli(), Readings: map[string]float32{}}
+			for _, reading := range datum.Readings {
+				metrics.Readings[reading.MetricName] = reading.Value
+			}
+			if err := encoder.Encode(metrics); err != nil {
+				svc.logger.Debugw("metrics stream closed", "error", err)
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
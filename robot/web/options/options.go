@@ -69,6 +69,14 @@ type Options struct {
 	BakedAuthCreds  rpc.Credentials
 
 	DisableMulticastDNS bool
+
+	// RTSPAddress, if set, is where to serve every camera as an RTSP stream, e.g: `:8554`. The
+	// streams are not authenticated.
+	RTSPAddress string
+
+	// QUICAddress, if set, is the UDP address where to also serve gRPC over QUIC, e.g: `:8443`, for
+	// direct connections over lossy links. It requires a TLS certificate.
+	QUICAddress string
 }
 
 // New returns a default set of options which will have the
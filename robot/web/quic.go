@@ -0,0 +1,72 @@
+package web
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/grpc"
+	weboptions "go.viam.com/rdk/robot/web/options"
+)
+
+// quicServer serves the same handlers as the HTTP server over HTTP/3, which runs over QUIC. Calls
+// then do not block each other when packets are lost, and the robot can be reached through a single
+// exposed UDP port.
+type quicServer struct {
+	server *http3.Server
+	conn   net.PacketConn
+}
+
+// newQUICServer listens for QUIC connections at options.QUICAddress. QUIC is always encrypted, so it
+// requires the TLS certificate the HTTP server is configured with.
+func newQUICServer(handler http.Handler, options weboptions.Options) (*quicServer, error) {
+	var tlsConfig *tls.Config
+	switch {
+	case options.Network.TLSConfig != nil:
+		tlsConfig = options.Network.TLSConfig.Clone()
+	case options.Network.TLSCertFile != "":
+		cert, err := tls.LoadX509KeyPair(options.Network.TLSCertFile, options.Network.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS13, Certificates: []tls.Certificate{cert}}
+	default:
+		return nil, errors.New("serving over QUIC requires a TLS certificate")
+	}
+
+	conn, err := net.ListenPacket("udp", options.QUICAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &quicServer{
+		server: &http3.Server{
+			Handler:    grpc.QUICHandler(handler),
+			TLSConfig:  http3.ConfigureTLSConfig(tlsConfig),
+			QUICConfig: &quic.Config{KeepAlivePeriod: grpc.QUICKeepAlivePeriod},
+		},
+		conn: conn,
+	}, nil
+}
+
+// Addr returns the address the server listens at.
+func (qs *quicServer) Addr() net.Addr {
+	return qs.conn.LocalAddr()
+}
+
+// Serve serves until the server is closed.
+func (qs *quicServer) Serve() error {
+	if err := qs.server.Serve(qs.conn); err != nil && !errors.Is(err, http.ErrServerClosed) && !errors.Is(err, quic.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Close stops serving and closes every connection.
+func (qs *quicServer) Close() error {
+	return multierr.Combine(qs.server.Close(), qs.conn.Close())
+}
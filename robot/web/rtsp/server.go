@@ -0,0 +1,274 @@
+// Package rtsp serves the robot's cameras as RTSP streams, such that NVRs and third party viewers
+// can consume robot video without a Viam SDK.
+package rtsp
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/bluenviron/gortsplib/v4/pkg/rtptime"
+	"github.com/pion/rtp"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/utils"
+)
+
+// frameInterval is how often a frame is read from a camera being streamed.
+const frameInterval = 100 * time.Millisecond
+
+// Server serves each camera of a robot as an MJPEG RTSP stream at `rtsp://<address>/<camera name>`.
+// Frames are only read from a camera while at least one client is playing its stream. The
+// streams are not authenticated, so the address should only be reachable from trusted networks.
+type Server struct {
+	server *gortsplib.Server
+	robot  robot.Robot
+	logger logging.Logger
+
+	mu      sync.Mutex
+	streams map[string]*cameraStream
+	// playing maps each session playing a stream to the name of the camera it is playing.
+	playing map[*gortsplib.ServerSession]string
+	workers sync.WaitGroup
+}
+
+// cameraStream is the RTSP stream of a single camera.
+type cameraStream struct {
+	name   string
+	stream *gortsplib.ServerStream
+	media  *description.Media
+	format *format.MJPEG
+
+	readers int
+	// cancel stops reading frames from the camera. It is nil when no client is playing.
+	cancel context.CancelFunc
+}
+
+// NewServer returns a new Server listening on `address`, e.g: `:8554`. Call `Start` to serve.
+func NewServer(address string, r robot.Robot, logger logging.Logger) *Server {
+	server := &Server{
+		robot:   r,
+		logger:  logger,
+		streams: make(map[string]*cameraStream),
+		playing: make(map[*gortsplib.ServerSession]string),
+	}
+	server.server = &gortsplib.Server{
+		Handler:     server,
+		RTSPAddress: address,
+	}
+	return server
+}
+
+// Start starts listening for RTSP clients.
+func (s *Server) Start() error {
+	if err := s.server.Start(); err != nil {
+		return err
+	}
+	s.logger.Infow("serving cameras over RTSP", "url", "rtsp://"+s.server.RTSPAddress+"/<camera name>")
+	return nil
+}
+
+// Close disconnects all clients and stops reading from cameras.
+func (s *Server) Close() {
+	s.mu.Lock()
+	for _, cs := range s.streams {
+		if cs.cancel != nil {
+			cs.cancel()
+		}
+	}
+	s.mu.Unlock()
+
+	s.server.Close()
+	s.workers.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, cs := range s.streams {
+		cs.stream.Close()
+	}
+	s.streams = make(map[string]*cameraStream)
+}
+
+// cameraName returns the name of the camera requested by an RTSP path, e.g: `/front-cam`.
+func cameraName(path string) string {
+	return strings.Trim(path, "/")
+}
+
+// streamFor returns the stream of the camera, creating it if this is the first request for it. It
+// returns nil if the robot has no such camera.
+func (s *Server) streamFor(name string) *cameraStream {
+	if _, err := camera.FromRobot(s.robot, name); err != nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cs, exists := s.streams[name]; exists {
+		return cs
+	}
+
+	forma := &format.MJPEG{}
+	media := &description.Media{Type: description.MediaTypeVideo, Formats: []format.Format{forma}}
+	cs := &cameraStream{
+		name:   name,
+		stream: gortsplib.NewServerStream(s.server, &description.Session{Medias: []*description.Media{media}}),
+		media:  media,
+		format: forma,
+	}
+	s.streams[name] = cs
+	return cs
+}
+
+// OnDescribe returns the stream of the requested camera, or 404 if there is no such camera.
+func (s *Server) OnDescribe(ctx *gortsplib.ServerHandlerOnDescribeCtx) (*base.Response, *gortsplib.ServerStream, error) {
+	cs := s.streamFor(cameraName(ctx.Path))
+	if cs == nil {
+		return &base.Response{StatusCode: base.StatusNotFound}, nil, nil
+	}
+	return &base.Response{StatusCode: base.StatusOK}, cs.stream, nil
+}
+
+// OnSetup returns the stream of the requested camera, or 404 if there is no such camera.
+func (s *Server) OnSetup(ctx *gortsplib.ServerHandlerOnSetupCtx) (*base.Response, *gortsplib.ServerStream, error) {
+	cs := s.streamFor(cameraName(ctx.Path))
+	if cs == nil {
+		return &base.Response{StatusCode: base.StatusNotFound}, nil, nil
+	}
+	return &base.Response{StatusCode: base.StatusOK}, cs.stream, nil
+}
+
+// OnPlay starts reading frames from the camera, if no other client was already playing it.
+func (s *Server) OnPlay(ctx *gortsplib.ServerHandlerOnPlayCtx) (*base.Response, error) {
+	name := cameraName(ctx.Path)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cs, exists := s.streams[name]
+	if !exists {
+		return &base.Response{StatusCode: base.StatusNotFound}, nil
+	}
+	if _, alreadyPlaying := s.playing[ctx.Session]; alreadyPlaying {
+		return &base.Response{StatusCode: base.StatusOK}, nil
+	}
+
+	s.playing[ctx.Session] = name
+	cs.readers++
+	if cs.cancel == nil {
+		var cancelCtx context.Context
+		cancelCtx, cs.cancel = context.WithCancel(context.Background())
+		s.workers.Add(1)
+		goutils.PanicCapturingGo(func() {
+			defer s.workers.Done()
+			s.streamFrames(cancelCtx, cs)
+		})
+	}
+	return &base.Response{StatusCode: base.StatusOK}, nil
+}
+
+// OnSessionClose stops reading frames from the camera the session was playing, if it was the last
+// client playing it.
+func (s *Server) OnSessionClose(ctx *gortsplib.ServerHandlerOnSessionCloseCtx) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name, playing := s.playing[ctx.Session]
+	if !playing {
+		return
+	}
+	delete(s.playing, ctx.Session)
+
+	cs := s.streams[name]
+	cs.readers--
+	if cs.readers == 0 && cs.cancel != nil {
+		// Do not wait for the frame streaming goroutine to exit. It may be in the middle of writing
+		// to the stream, which can require the session to finish closing.
+		cs.cancel()
+		cs.cancel = nil
+	}
+}
+
+// streamFrames reads JPEG frames from the camera and writes them to the stream until `ctx` is
+// canceled. The camera is looked up for every frame, such that the stream survives the camera
+// being reconfigured.
+func (s *Server) streamFrames(ctx context.Context, cs *cameraStream) {
+	encoder, err := cs.format.CreateEncoder()
+	if err != nil {
+		s.logger.Errorw("error creating MJPEG encoder", "camera", cs.name, "error", err)
+		return
+	}
+	rtpTime := &rtptime.Encoder{ClockRate: cs.format.ClockRate()}
+	if err := rtpTime.Initialize(); err != nil {
+		s.logger.Errorw("error creating RTP timestamp encoder", "camera", cs.name, "error", err)
+		return
+	}
+	start := time.Now()
+
+	// lastErr is the error of the previous frame. Errors are only logged when they change, such
+	// that a disconnected camera does not log every frame.
+	var lastErr string
+	ticker := time.NewTicker(frameInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := s.writeFrame(ctx, cs, encoder.Encode, rtpTime.Encode(time.Since(start)))
+		switch {
+		case err != nil && err.Error() != lastErr && ctx.Err() == nil:
+			s.logger.Warnw("error streaming camera over RTSP", "camera", cs.name, "error", err)
+			lastErr = err.Error()
+		case err == nil:
+			lastErr = ""
+		}
+	}
+}
+
+func (s *Server) writeFrame(
+	ctx context.Context,
+	cs *cameraStream,
+	encode func([]byte) ([]*rtp.Packet, error),
+	timestamp uint32,
+) error {
+	cam, err := camera.FromRobot(s.robot, cs.name)
+	if err != nil {
+		return err
+	}
+	frame, metadata, err := cam.Image(ctx, utils.MimeTypeJPEG, nil)
+	if err != nil {
+		return err
+	}
+
+	packets, err := encode(frame)
+	if err != nil || !strings.HasPrefix(metadata.MimeType, utils.MimeTypeJPEG) {
+		// The camera returned another format, or a JPEG that RTP cannot carry (e.g: progressive or
+		// with unsupported chroma subsampling). Re-encode it as a baseline JPEG.
+		img, err := rimage.DecodeImage(ctx, frame, metadata.MimeType)
+		if err != nil {
+			return err
+		}
+		if frame, err = rimage.EncodeImage(ctx, img, utils.MimeTypeJPEG); err != nil {
+			return err
+		}
+		if packets, err = encode(frame); err != nil {
+			return err
+		}
+	}
+
+	for _, packet := range packets {
+		packet.Timestamp = timestamp
+		if err := cs.stream.WritePacketRTP(cs.media, packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
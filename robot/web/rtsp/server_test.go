@@ -0,0 +1,102 @@
+package rtsp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v4"
+	"github.com/bluenviron/gortsplib/v4/pkg/base"
+	"github.com/bluenviron/gortsplib/v4/pkg/description"
+	"github.com/bluenviron/gortsplib/v4/pkg/format"
+	"github.com/pion/rtp"
+	"go.viam.com/test"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	rutils "go.viam.com/rdk/utils"
+)
+
+// fakeRobot only has the cameras in its map.
+type fakeRobot struct {
+	robot.Robot
+	cameras map[resource.Name]camera.Camera
+}
+
+func (r *fakeRobot) ResourceByName(name resource.Name) (resource.Resource, error) {
+	cam, ok := r.cameras[name]
+	if !ok {
+		return nil, resource.NewNotFoundError(name)
+	}
+	return cam, nil
+}
+
+// fakeCamera returns the same PNG for every image.
+type fakeCamera struct {
+	camera.Camera
+	png []byte
+}
+
+func (cam *fakeCamera) Image(ctx context.Context, mimeType string, extra map[string]interface{}) ([]byte, camera.ImageMetadata, error) {
+	return cam.png, camera.ImageMetadata{MimeType: rutils.MimeTypePNG}, nil
+}
+
+func TestServeCamera(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+
+	// The camera returns PNGs, which the server must re-encode as JPEGs.
+	var frame bytes.Buffer
+	test.That(t, png.Encode(&frame, image.NewRGBA(image.Rect(0, 0, 64, 48))), test.ShouldBeNil)
+	r := &fakeRobot{cameras: map[resource.Name]camera.Camera{camera.Named("cam"): &fakeCamera{png: frame.Bytes()}}}
+
+	port, err := utils.TryReserveRandomPort()
+	test.That(t, err, test.ShouldBeNil)
+	server := NewServer(fmt.Sprintf("localhost:%d", port), r, logger)
+	test.That(t, server.Start(), test.ShouldBeNil)
+	defer server.Close()
+
+	transport := gortsplib.TransportTCP
+	client := gortsplib.Client{Transport: &transport}
+	test.That(t, client.Start("rtsp", fmt.Sprintf("localhost:%d", port)), test.ShouldBeNil)
+	defer client.Close()
+
+	t.Run("missing camera", func(t *testing.T) {
+		u, err := base.ParseURL(fmt.Sprintf("rtsp://localhost:%d/missing", port))
+		test.That(t, err, test.ShouldBeNil)
+		_, resp, err := client.Describe(u)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, resp.StatusCode, test.ShouldEqual, base.StatusNotFound)
+	})
+
+	u, err := base.ParseURL(fmt.Sprintf("rtsp://localhost:%d/cam", port))
+	test.That(t, err, test.ShouldBeNil)
+	desc, _, err := client.Describe(u)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, desc.Medias, test.ShouldHaveLength, 1)
+	test.That(t, desc.Medias[0].Type, test.ShouldEqual, description.MediaTypeVideo)
+	test.That(t, client.SetupAll(desc.BaseURL, desc.Medias), test.ShouldBeNil)
+
+	packets := make(chan *rtp.Packet, 1024)
+	client.OnPacketRTPAny(func(medi *description.Media, forma format.Format, pkt *rtp.Packet) {
+		select {
+		case packets <- pkt:
+		default:
+		}
+	})
+	_, err = client.Play(nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	select {
+	case pkt := <-packets:
+		test.That(t, pkt.Payload, test.ShouldNotBeEmpty)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for a frame")
+	}
+}
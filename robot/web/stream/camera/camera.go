@@ -13,6 +13,7 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/rimage"
 	"go.viam.com/rdk/robot"
+	rutils "go.viam.com/rdk/utils"
 )
 
 // Camera returns the camera from the robot (derived from the stream) or
@@ -44,6 +45,10 @@ func VideoSourceFromCamera(ctx context.Context, cam camera.Camera) (gostream.Vid
 		return gostream.NewVideoSource(reader, prop.Video{}), nil //nolint:nilerr
 	}
 	if lazyImg, ok := img.(*rimage.LazyEncodedImage); ok {
+		if IsVideoMIMEType(lazyImg.MIMEType()) {
+			// Encoded video frames are streamed as is, so their dimensions are not needed.
+			return gostream.NewVideoSource(reader, prop.Video{}), nil
+		}
 		if err := lazyImg.DecodeConfig(); err != nil {
 			return nil, fmt.Errorf("failed to decode lazy encoded image: %w", err)
 		}
@@ -51,3 +56,8 @@ func VideoSourceFromCamera(ctx context.Context, cam camera.Camera) (gostream.Vid
 
 	return gostream.NewVideoSource(reader, prop.Video{Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}), nil
 }
+
+// IsVideoMIMEType returns whether the MIME type is an encoded video format rather than an image.
+func IsVideoMIMEType(mimeType string) bool {
+	return mimeType == rutils.MimeTypeH264 || mimeType == rutils.MimeTypeH265
+}
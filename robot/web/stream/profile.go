@@ -0,0 +1,239 @@
+package webstream
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc/metadata"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/gostream/codec"
+	camerautils "go.viam.com/rdk/robot/web/stream/camera"
+	"go.viam.com/rdk/robot/web/stream/state"
+	rutils "go.viam.com/rdk/utils"
+)
+
+// The gRPC metadata keys a client sets on an AddStream call to request a Profile.
+const (
+	ProfileWidthMetadataKey     = "viam-stream-width"
+	ProfileHeightMetadataKey    = "viam-stream-height"
+	ProfileFrameRateMetadataKey = "viam-stream-fps"
+	ProfileCodecMetadataKey     = "viam-stream-codec"
+)
+
+// A Profile is how a client asks for a camera's video to be streamed to it. Clients that ask for
+// the same profile of a camera share an encoding of it, apart from clients that did not ask for
+// one, which share the camera's own stream. Zero fields are left as the camera streams them.
+type Profile struct {
+	Width, Height int
+	FrameRate     int
+	// MIMEType is the codec to encode the video with, e.g. "video/H264".
+	MIMEType string
+}
+
+// IsZero returns whether the profile asks for nothing more than the camera's own stream.
+func (p Profile) IsZero() bool {
+	return p == Profile{}
+}
+
+func (p Profile) String() string {
+	return fmt.Sprintf("%dx%d@%dfps:%s", p.Width, p.Height, p.FrameRate, p.MIMEType)
+}
+
+// Validate returns an error if the profile cannot be streamed.
+func (p Profile) Validate() error {
+	if p.Width < 0 || p.Height < 0 || p.FrameRate < 0 {
+		return fmt.Errorf("invalid stream profile %v: width, height and frame rate cannot be negative", p)
+	}
+	if (p.Width == 0) != (p.Height == 0) {
+		return fmt.Errorf("invalid stream profile %v: width and height must be set together", p)
+	}
+	if p.Width%2 != 0 || p.Height%2 != 0 {
+		return fmt.Errorf("invalid stream profile %v: width and height must be even", p)
+	}
+	return nil
+}
+
+// WithProfile returns a context that asks for the given profile when used to call AddStream.
+func WithProfile(ctx context.Context, profile Profile) context.Context {
+	var kv []string
+	if profile.Width != 0 {
+		kv = append(kv, ProfileWidthMetadataKey, strconv.Itoa(profile.Width))
+	}
+	if profile.Height != 0 {
+		kv = append(kv, ProfileHeightMetadataKey, strconv.Itoa(profile.Height))
+	}
+	if profile.FrameRate != 0 {
+		kv = append(kv, ProfileFrameRateMetadataKey, strconv.Itoa(profile.FrameRate))
+	}
+	if profile.MIMEType != "" {
+		kv = append(kv, ProfileCodecMetadataKey, profile.MIMEType)
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// ProfileFromContext returns the profile a client asked for in the metadata of a call.
+func ProfileFromContext(ctx context.Context) (Profile, error) {
+	var profile Profile
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return profile, nil
+	}
+	for key, field := range map[string]*int{
+		ProfileWidthMetadataKey:     &profile.Width,
+		ProfileHeightMetadataKey:    &profile.Height,
+		ProfileFrameRateMetadataKey: &profile.FrameRate,
+	} {
+		values := md.Get(key)
+		if len(values) == 0 {
+			continue
+		}
+		value, err := strconv.Atoi(values[0])
+		if err != nil {
+			return Profile{}, errors.Wrapf(err, "invalid %s", key)
+		}
+		*field = value
+	}
+	if values := md.Get(ProfileCodecMetadataKey); len(values) != 0 {
+		profile.MIMEType = values[0]
+	}
+	return profile, profile.Validate()
+}
+
+// profileStream is an encoding of a camera's video for the clients that asked for a profile.
+type profileStream struct {
+	key         string
+	name        string
+	profile     Profile
+	streamState *state.StreamState
+	source      gostream.HotSwappableVideoSource
+	cancel      context.CancelFunc
+	clients     int
+}
+
+func profileStreamKey(name string, profile Profile) string {
+	return name + "/" + profile.String()
+}
+
+// videoSourceForProfile returns the video of the camera with the given stream name scaled to the
+// profile's resolution.
+func (server *Server) videoSourceForProfile(ctx context.Context, name string, profile Profile) (gostream.VideoSource, error) {
+	cam, err := camera.FromRobot(server.robot, name)
+	if err != nil {
+		return nil, err
+	}
+	src, err := camerautils.VideoSourceFromCamera(ctx, cam)
+	if err != nil {
+		return nil, err
+	}
+	if profile.Width != 0 {
+		src = gostream.NewResizeVideoSource(src, profile.Width, profile.Height)
+	}
+	return src, nil
+}
+
+// encoderFactoryForProfile returns the encoder for the profile's codec, if the camera with the
+// given stream name can be streamed with it.
+func (server *Server) encoderFactoryForProfile(name string, profile Profile) (codec.VideoEncoderFactory, error) {
+	factory := server.videoEncoderFactoryForCamera(name)
+	if profile.MIMEType != "" && !strings.EqualFold(profile.MIMEType, factory.MIMEType()) {
+		configured := server.streamConfig.VideoEncoderFactory
+		if configured == nil || !strings.EqualFold(profile.MIMEType, configured.MIMEType()) {
+			return nil, fmt.Errorf("stream %q cannot be encoded as %q, only as %q", name, profile.MIMEType, factory.MIMEType())
+		}
+		factory = configured
+	}
+	if profile.Width != 0 && factory.MIMEType() == rutils.MimeTypeH265 {
+		return nil, fmt.Errorf("stream %q is streamed as the camera encodes it and cannot be resized", name)
+	}
+	return factory, nil
+}
+
+// profileStream returns the stream of the camera with the given stream name for clients that
+// asked for the profile, creating and starting it if no client is watching it yet. It must be
+// called with the server's lock held.
+func (server *Server) profileStream(ctx context.Context, name string, profile Profile) (*profileStream, error) {
+	key := profileStreamKey(name, profile)
+	if ps, ok := server.profileStreams[key]; ok {
+		return ps, nil
+	}
+
+	factory, err := server.encoderFactoryForProfile(name, profile)
+	if err != nil {
+		return nil, err
+	}
+	frameRate := profile.FrameRate
+	if frameRate == 0 {
+		if frameRate, err = server.getFramerateFromCamera(name); err != nil {
+			server.logger.Debugf("error getting framerate from camera %q: %v", name, err)
+		}
+	}
+	src, err := server.videoSourceForProfile(ctx, name, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stream is named after the camera so that clients find its track like the camera's own.
+	stream, err := gostream.NewStream(gostream.StreamConfig{
+		Name:                name,
+		VideoEncoderFactory: factory,
+		TargetFrameRate:     frameRate,
+	}, server.logger)
+	if err != nil {
+		return nil, err
+	}
+	streamState := state.New(stream, server.robot, server.logger.Sublogger(key))
+	// RTP passthrough streams the camera's video as it is, so it cannot honor the profile.
+	if err := streamState.Resize(); err != nil {
+		return nil, multierr.Combine(err, streamState.Close())
+	}
+
+	ps := &profileStream{
+		key:         key,
+		name:        name,
+		profile:     profile,
+		streamState: streamState,
+		source:      gostream.NewHotSwappableVideoSource(src),
+	}
+	var streamCtx context.Context
+	streamCtx, ps.cancel = context.WithCancel(context.Background())
+	server.startVideoStream(streamCtx, ps.source, stream)
+	server.profileStreams[key] = ps
+	server.logger.Infow("started stream for profile", "name", name, "profile", profile.String())
+	return ps, nil
+}
+
+// closeProfileStream stops a profile's stream once no client is watching it. It must be called
+// with the server's lock held.
+func (server *Server) closeProfileStream(ps *profileStream) error {
+	if ps.clients > 0 {
+		return nil
+	}
+	delete(server.profileStreams, ps.key)
+	ps.cancel()
+	server.logger.Infow("stopped stream for profile", "name", ps.name, "profile", ps.profile.String())
+	return ps.streamState.Close()
+}
+
+// refreshProfileSources swaps the sources of the profile streams of the camera with the given
+// stream name for new ones, after the camera changed.
+func (server *Server) refreshProfileSources(ctx context.Context, name string) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for _, ps := range server.profileStreams {
+		if ps.name != name {
+			continue
+		}
+		src, err := server.videoSourceForProfile(ctx, name, ps.profile)
+		if err != nil {
+			server.logger.Errorf("error creating video source for stream %q profile %v: %v", name, ps.profile, err)
+			continue
+		}
+		ps.source.Swap(src)
+	}
+}
@@ -0,0 +1,45 @@
+package webstream_test
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/metadata"
+
+	webstream "go.viam.com/rdk/robot/web/stream"
+)
+
+func TestProfileFromContext(t *testing.T) {
+	incoming := func(ctx context.Context) context.Context {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		return metadata.NewIncomingContext(context.Background(), md)
+	}
+
+	profile, err := webstream.ProfileFromContext(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, profile.IsZero(), test.ShouldBeTrue)
+
+	want := webstream.Profile{Width: 640, Height: 480, FrameRate: 10, MIMEType: "video/H264"}
+	profile, err = webstream.ProfileFromContext(incoming(webstream.WithProfile(context.Background(), want)))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, profile, test.ShouldResemble, want)
+
+	want = webstream.Profile{FrameRate: 5}
+	profile, err = webstream.ProfileFromContext(incoming(webstream.WithProfile(context.Background(), want)))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, profile, test.ShouldResemble, want)
+
+	_, err = webstream.ProfileFromContext(incoming(webstream.WithProfile(context.Background(), webstream.Profile{Width: 640})))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must be set together")
+
+	_, err = webstream.ProfileFromContext(incoming(webstream.WithProfile(context.Background(), webstream.Profile{Width: 641, Height: 480})))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must be even")
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(webstream.ProfileFrameRateMetadataKey, "fast"))
+	_, err = webstream.ProfileFromContext(ctx)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, webstream.ProfileFrameRateMetadataKey)
+}
@@ -19,6 +19,8 @@ import (
 	"go.viam.com/rdk/components/audioinput"
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/gostream/codec"
+	"go.viam.com/rdk/gostream/codec/h265"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
@@ -41,6 +43,19 @@ const (
 type peerState struct {
 	streamState *state.StreamState
 	senders     []*webrtc.RTPSender
+	// profile is the stream the peer asked for with a profile, or nil if it watches the camera's
+	// own stream.
+	profile *profileStream
+}
+
+// release stops sending the stream to the peer.
+func (ps *peerState) release(server *Server) error {
+	err := ps.streamState.Decrement()
+	if ps.profile != nil {
+		ps.profile.clients--
+		err = multierr.Combine(err, server.closeProfileStream(ps.profile))
+	}
+	return err
 }
 
 // Server implements the gRPC audio/video streaming service.
@@ -54,6 +69,7 @@ type Server struct {
 	mu                      sync.RWMutex
 	nameToStreamState       map[string]*state.StreamState
 	activePeerStreams       map[*webrtc.PeerConnection]map[string]*peerState
+	profileStreams          map[string]*profileStream
 	activeBackgroundWorkers sync.WaitGroup
 	isAlive                 bool
 
@@ -83,6 +99,7 @@ func NewServer(
 		logger:            logger,
 		nameToStreamState: map[string]*state.StreamState{},
 		activePeerStreams: map[*webrtc.PeerConnection]map[string]*peerState{},
+		profileStreams:    map[string]*profileStream{},
 		isAlive:           true,
 		streamConfig:      streamConfig,
 		videoSources:      map[string]gostream.HotSwappableVideoSource{},
@@ -182,6 +199,24 @@ func (server *Server) AddStream(ctx context.Context, req *streampb.AddStreamRequ
 		server.logger.Error(err.Error())
 		return nil, err
 	}
+
+	// a caller that asked for a profile is sent the camera's video encoded for that profile
+	profile, err := ProfileFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var profileStreamToAdd *profileStream
+	if !profile.IsZero() {
+		if isCamErr != nil {
+			return nil, errors.Errorf("stream %q is not a camera and cannot be streamed with a profile", req.Name)
+		}
+		profileStreamToAdd, err = server.profileStream(server.closedCtx, req.Name, profile)
+		if err != nil {
+			server.logger.Error(err.Error())
+			return nil, err
+		}
+		streamStateToAdd = profileStreamToAdd.streamState
+	}
 	nameToPeerState, ok := server.activePeerStreams[pc]
 	// if there is no active video data being sent, set up a callback to remove the peer connection from
 	// the active streams & stop the stream from doing h264 encode if this is the last peer connection
@@ -219,7 +254,7 @@ func (server *Server) AddStream(ctx context.Context, req *streampb.AddStreamRequ
 						defer delete(server.activePeerStreams, pc)
 						var errs error
 						for _, ps := range server.activePeerStreams[pc] {
-							errs = multierr.Combine(errs, ps.streamState.Decrement())
+							errs = multierr.Combine(errs, ps.release(server))
 						}
 						// We don't want to log this if the streamState was closed (as it only happens if viam-server is terminating)
 						if errs != nil && !errors.Is(errs, state.ErrClosed) {
@@ -241,7 +276,7 @@ func (server *Server) AddStream(ctx context.Context, req *streampb.AddStreamRequ
 	ps, ok := nameToPeerState[req.Name]
 	// if the active peer stream doesn't have a peerState, add one containing the stream in question
 	if !ok {
-		ps = &peerState{streamState: streamStateToAdd}
+		ps = &peerState{streamState: streamStateToAdd, profile: profileStreamToAdd}
 		nameToPeerState[req.Name] = ps
 	}
 
@@ -249,6 +284,10 @@ func (server *Server) AddStream(ctx context.Context, req *streampb.AddStreamRequ
 		for _, sender := range ps.senders {
 			utils.UncheckedError(pc.RemoveTrack(sender))
 		}
+		if profileStreamToAdd != nil {
+			delete(nameToPeerState, req.Name)
+			utils.UncheckedError(server.closeProfileStream(profileStreamToAdd))
+		}
 	})
 	defer guard.OnFail()
 
@@ -258,6 +297,7 @@ func (server *Server) AddStream(ctx context.Context, req *streampb.AddStreamRequ
 			return err
 		}
 		ps.senders = append(ps.senders, sender)
+		streamStateToAdd.Stream.AdaptToSender(sender)
 		return nil
 	}
 
@@ -279,6 +319,9 @@ func (server *Server) AddStream(ctx context.Context, req *streampb.AddStreamRequ
 		server.logger.Error(err.Error())
 		return nil, err
 	}
+	if profileStreamToAdd != nil {
+		profileStreamToAdd.clients++
+	}
 
 	guard.Success()
 	return &streampb.AddStreamResponse{}, nil
@@ -313,12 +356,13 @@ func (server *Server) RemoveStream(ctx context.Context, req *streampb.RemoveStre
 		return &streampb.RemoveStreamResponse{}, nil
 	}
 
-	if _, ok := server.activePeerStreams[pc][req.Name]; !ok {
+	ps, ok := server.activePeerStreams[pc][req.Name]
+	if !ok {
 		return &streampb.RemoveStreamResponse{}, nil
 	}
 
 	var errs error
-	for _, sender := range server.activePeerStreams[pc][req.Name].senders {
+	for _, sender := range ps.senders {
 		errs = multierr.Combine(errs, pc.RemoveTrack(sender))
 	}
 	if errs != nil {
@@ -326,7 +370,7 @@ func (server *Server) RemoveStream(ctx context.Context, req *streampb.RemoveStre
 		return nil, errs
 	}
 
-	if err := streamToRemove.Decrement(); err != nil {
+	if err := ps.release(server); err != nil {
 		server.logger.Error(err.Error())
 		return nil, err
 	}
@@ -525,7 +569,7 @@ func (server *Server) AddNewStreams(ctx context.Context) error {
 		// "started".
 		config := gostream.StreamConfig{
 			Name:                name,
-			VideoEncoderFactory: server.streamConfig.VideoEncoderFactory,
+			VideoEncoderFactory: server.videoEncoderFactoryForCamera(name),
 			TargetFrameRate:     framerate,
 		}
 		// Call `createStream`. `createStream` is responsible for first checking if the stream
@@ -571,6 +615,10 @@ func (server *Server) Close() error {
 	for _, streamState := range server.nameToStreamState {
 		errs = multierr.Combine(errs, streamState.Close())
 	}
+	for _, ps := range server.profileStreams {
+		ps.cancel()
+		errs = multierr.Combine(errs, ps.streamState.Close())
+	}
 	if errs != nil {
 		server.logger.Errorf("Stream Server Close > StreamState.Close() errs: %s", errs)
 	}
@@ -655,7 +703,7 @@ func (server *Server) removeMissingStreams() {
 				server.logger.Warn(errs.Error())
 			}
 
-			if err := streamState.Decrement(); err != nil {
+			if err := peerState.release(server); err != nil {
 				server.logger.Warn(err.Error())
 			}
 			delete(server.activePeerStreams[pc], camName)
@@ -697,6 +745,7 @@ func (server *Server) refreshVideoSources(ctx context.Context) {
 						)
 						resizer := gostream.NewResizeVideoSource(src, width, height)
 						existing.Swap(resizer)
+						server.refreshProfileSources(ctx, cam.Name().SDPTrackName())
 						continue
 					}
 				}
@@ -710,6 +759,7 @@ func (server *Server) refreshVideoSources(ctx context.Context) {
 				}
 			}
 			existing.Swap(src)
+			server.refreshProfileSources(ctx, cam.Name().SDPTrackName())
 			continue
 		}
 		newSwapper := gostream.NewHotSwappableVideoSource(src)
@@ -789,6 +839,26 @@ func (server *Server) getFramerateFromCamera(name string) (int, error) {
 	return int(props.FrameRate), nil
 }
 
+// videoEncoderFactoryForCamera returns the H.265 passthrough encoder for cameras that produce H.265
+// frames, and the configured video encoder otherwise.
+func (server *Server) videoEncoderFactoryForCamera(name string) codec.VideoEncoderFactory {
+	cam, err := camera.FromRobot(server.robot, name)
+	if err != nil {
+		return server.streamConfig.VideoEncoderFactory
+	}
+	props, err := cam.Properties(context.Background())
+	if err != nil {
+		return server.streamConfig.VideoEncoderFactory
+	}
+	for _, mimeType := range props.MimeTypes {
+		if mimeType == rutils.MimeTypeH265 {
+			server.logger.Debugw("camera produces H.265 frames, streaming them without re-encoding", "camera", name)
+			return h265.NewEncoderFactory()
+		}
+	}
+	return server.streamConfig.VideoEncoderFactory
+}
+
 // GenerateResolutions takes the original width and height of an image and returns
 // a list of the original resolution with 4 smaller width/height options that maintain
 // the same aspect ratio.
@@ -71,6 +71,10 @@ func (mS *mockStream) InputAudioChunks(props prop.Audio) (chan<- gostream.MediaR
 	return make(chan gostream.MediaReleasePair[wave.Audio]), nil
 }
 
+func (mS *mockStream) AdaptToSender(sender *webrtc.RTPSender) {
+	test.That(mS.t, "should not be called", test.ShouldBeFalse)
+}
+
 func (mS *mockStream) VideoTrackLocal() (webrtc.TrackLocal, bool) {
 	test.That(mS.t, "should not be called", test.ShouldBeFalse)
 	return nil, false
@@ -8,7 +8,6 @@ import (
 	"io"
 	"net"
 	"net/http"
-	"net/http/pprof"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -39,7 +38,9 @@ import (
 	"go.viam.com/rdk/robot"
 	grpcserver "go.viam.com/rdk/robot/server"
 	weboptions "go.viam.com/rdk/robot/web/options"
+	"go.viam.com/rdk/robot/web/rtsp"
 	webstream "go.viam.com/rdk/robot/web/stream"
+	"go.viam.com/rdk/tracing"
 	rutils "go.viam.com/rdk/utils"
 )
 
@@ -108,6 +109,7 @@ type webService struct {
 
 	requestCounter     RequestCounter
 	modPeerConnTracker *grpc.ModPeerConnTracker
+	remoteAccess       *grpc.RemoteAccessPolicy
 }
 
 var internalWebServiceName = resource.NewName(
@@ -222,7 +224,8 @@ func (svc *webService) StartModule(ctx context.Context) error {
 		streamInterceptors []googlegrpc.StreamServerInterceptor
 	)
 
-	unaryInterceptors = append(unaryInterceptors, grpc.EnsureTimeoutUnaryServerInterceptor)
+	unaryInterceptors = append(unaryInterceptors, grpc.EnsureTimeoutUnaryServerInterceptor, tracing.UnaryServerInterceptor)
+	streamInterceptors = append(streamInterceptors, tracing.StreamServerInterceptor)
 
 	// Attach the module name (as defined by the robot config) to the handler context. Can be
 	// accessed via `grpc.GetModuleName`.
@@ -309,6 +312,7 @@ func (svc *webService) updateResources(resources map[resource.Name]resource.Reso
 		}
 		delete(groupedResources, api)
 	}
+	svc.updateRemoteAccess(resources)
 
 	// If there are any groupedResources remaining, check if they are registered/internal/remote.
 	//  * Custom APIs are registered and do not have a dedicated gRPC service as requests for them are routed through the
@@ -337,6 +341,43 @@ func (svc *webService) updateResources(resources map[resource.Name]resource.Reso
 	return nil
 }
 
+// updateRemoteAccess updates the entities allowed to call the resources of each remote from the
+// allowed_entities of the remote configs.
+func (svc *webService) updateRemoteAccess(resources map[resource.Name]resource.Resource) {
+	localRobot, ok := svc.r.(robot.LocalRobot)
+	if !ok {
+		return
+	}
+	allowedEntities := map[string][]string{}
+	for _, remote := range localRobot.Config().Remotes {
+		if len(remote.AllowedEntities) != 0 {
+			allowedEntities[remote.Name] = remote.AllowedEntities
+		}
+	}
+	if len(allowedEntities) == 0 {
+		svc.remoteAccess.Update(nil, nil)
+		return
+	}
+
+	// Resources can be called by their full name, or by their name alone when it is unique.
+	resourceRemotes := map[string]string{}
+	nameCounts := map[string]int{}
+	for name := range resources {
+		nameCounts[name.Name]++
+	}
+	for name := range resources {
+		if !name.ContainsRemoteNames() {
+			continue
+		}
+		remote, _, _ := strings.Cut(name.Remote, ":")
+		resourceRemotes[name.ShortName()] = remote
+		if nameCounts[name.Name] == 1 {
+			resourceRemotes[name.Name] = remote
+		}
+	}
+	svc.remoteAccess.Update(allowedEntities, resourceRemotes)
+}
+
 // Stop stops the main web service prior to actually closing (it leaves the module server running.)
 func (svc *webService) Stop() {
 	svc.mu.Lock()
@@ -452,6 +493,24 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 		return err
 	}
 
+	var rtspServer *rtsp.Server
+	if options.RTSPAddress != "" {
+		rtspServer = rtsp.NewServer(options.RTSPAddress, svc.r, svc.logger.Sublogger("rtsp"))
+		if err := rtspServer.Start(); err != nil {
+			return err
+		}
+	}
+
+	var quicServer *quicServer
+	if options.QUICAddress != "" {
+		if quicServer, err = newQUICServer(httpServer.Handler, options); err != nil {
+			if rtspServer != nil {
+				rtspServer.Close()
+			}
+			return errors.Wrap(err, "cannot serve over QUIC")
+		}
+	}
+
 	// Serve
 
 	svc.webWorkers.Add(1)
@@ -469,6 +528,14 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 			}
 		}()
 		svc.closeStreamServer()
+		if rtspServer != nil {
+			rtspServer.Close()
+		}
+		if quicServer != nil {
+			if err := quicServer.Close(); err != nil {
+				svc.logger.Errorw("error closing QUIC server", "error", err)
+			}
+		}
 	})
 	svc.webWorkers.Add(1)
 	utils.PanicCapturingGo(func() {
@@ -496,6 +563,16 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 		urlFields = append(urlFields, "url", localURL, "alt_url", listenerURL)
 	}
 	svc.logger.Infow("serving", urlFields...)
+	if quicServer != nil {
+		svc.logger.Infow("serving over QUIC", "address", quicServer.Addr().String())
+		svc.webWorkers.Add(1)
+		utils.PanicCapturingGo(func() {
+			defer svc.webWorkers.Done()
+			if err := quicServer.Serve(); err != nil {
+				svc.logger.Errorw("error serving over QUIC", "error", err)
+			}
+		})
+	}
 
 	svc.webWorkers.Add(1)
 	utils.PanicCapturingGo(func() {
@@ -611,8 +688,13 @@ func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options webo
 	}
 
 	var unaryInterceptors []googlegrpc.UnaryServerInterceptor
-	unaryInterceptors = append(unaryInterceptors, grpc.EnsureTimeoutUnaryServerInterceptor)
+	unaryInterceptors = append(unaryInterceptors, grpc.EnsureTimeoutUnaryServerInterceptor, tracing.UnaryServerInterceptor)
 	unaryInterceptors = append(unaryInterceptors, svc.requestCounter.UnaryInterceptor)
+	// Calls for remotes are checked against the entities they allow before they are routed.
+	unaryInterceptors = append(unaryInterceptors, svc.remoteAccess.UnaryServerInterceptor)
+	// Calls for remote parts are forwarded before they are associated with this part's sessions
+	// and operations.
+	unaryInterceptors = append(unaryInterceptors, grpc.PartRouteUnaryServerInterceptor(svc.partConn))
 
 	if options.Debug {
 		rpcOpts = append(rpcOpts, rpc.WithDebug())
@@ -639,7 +721,11 @@ func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options webo
 	}
 	rpcOpts = append(rpcOpts, authOpts...)
 
-	var streamInterceptors []googlegrpc.StreamServerInterceptor
+	streamInterceptors := []googlegrpc.StreamServerInterceptor{
+		tracing.StreamServerInterceptor,
+		svc.remoteAccess.StreamServerInterceptor,
+		grpc.PartRouteStreamServerInterceptor(svc.partConn),
+	}
 
 	opManager := svc.r.OperationManager()
 	sessManagerInts := svc.r.SessionManager().ServerInterceptors()
@@ -654,6 +740,11 @@ func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options webo
 	}
 	streamInterceptors = append(streamInterceptors, opManager.StreamServerInterceptor)
 
+	if compression := options.Network.GRPCCompression; compression != "" {
+		unaryInterceptors = append(unaryInterceptors, grpc.CompressionUnaryServerInterceptor(compression))
+		streamInterceptors = append(streamInterceptors, grpc.CompressionStreamServerInterceptor(compression))
+	}
+
 	rpcOpts = append(
 		rpcOpts,
 		rpc.WithUnknownServiceHandler(svc.foreignServiceHandler),
@@ -798,13 +889,8 @@ func (svc *webService) initMux(options weboptions.Options) *goji.Mux {
 		}
 	})
 
-	if options.Pprof {
-		mux.HandleFunc(pat.New("/debug/pprof/"), pprof.Index)
-		mux.HandleFunc(pat.New("/debug/pprof/cmdline"), pprof.Cmdline)
-		mux.HandleFunc(pat.New("/debug/pprof/profile"), pprof.Profile)
-		mux.HandleFunc(pat.New("/debug/pprof/symbol"), pprof.Symbol)
-		mux.HandleFunc(pat.New("/debug/pprof/trace"), pprof.Trace)
-	}
+	svc.initDebugHandlers(mux, options)
+	svc.initMetricsHandlers(mux, options)
 
 	// serve resource graph visualization
 	// TODO: hide behind option
@@ -845,6 +931,19 @@ func (svc *webService) initMux(options weboptions.Options) *goji.Mux {
 // It is invoked instead of returning the "unimplemented" gRPC error whenever a request is received for
 // an unregistered service or method. These method could be registered on a remote viam-server or a module server
 // so this handler will attempt to route the request to the correct next node in the chain.
+// partConn returns the connection to the remote part with the given name, for calls routed to it.
+func (svc *webService) partConn(part string) (rpc.ClientConn, bool) {
+	remote, ok := svc.r.RemoteByName(part)
+	if !ok {
+		return nil, false
+	}
+	connRemote, ok := remote.(interface{ ClientConn() rpc.ClientConn })
+	if !ok {
+		return nil, false
+	}
+	return connRemote.ClientConn(), true
+}
+
 func (svc *webService) foreignServiceHandler(srv interface{}, stream googlegrpc.ServerStream) error {
 	// method will be in the form of PackageName.ServiceName/MethodName
 	method, ok := googlegrpc.MethodFromServerStream(stream)
@@ -873,16 +972,19 @@ func (svc *webService) foreignServiceHandler(srv interface{}, stream googlegrpc.
 		return err
 	}
 
-	if fqName.ContainsRemoteNames() {
-		firstMsg.SetFieldByName("name", fqName.PopRemote().ShortName())
-	}
-
 	foreignRes, ok := resource.(*grpc.ForeignResource)
 	if !ok {
 		svc.logger.Errorf("expected resource to be a foreign RPC resource but was %T", foreignRes)
 		return grpc.UnimplementedError
 	}
 
+	// The remote knows the resource by its own name, which has neither the remote name nor the
+	// prefix of the remote config.
+	remoteShortName := foreignRes.Name().ShortName()
+	if fqName.ContainsRemoteNames() {
+		firstMsg.SetFieldByName("name", remoteShortName)
+	}
+
 	foreignClient := foreignRes.NewStub()
 
 	// see https://github.com/fullstorydev/grpcurl/blob/76bbedeed0ec9b6e09ad1e1cb88fffe4726c0db2/invoke.go
@@ -921,7 +1023,7 @@ func (svc *webService) foreignServiceHandler(srv interface{}, stream googlegrpc.
 				}
 				// remove a remote from the name if needed
 				if fqName.ContainsRemoteNames() {
-					msg.SetFieldByName("name", fqName.PopRemote().ShortName())
+					msg.SetFieldByName("name", remoteShortName)
 				}
 				err = bidiStream.SendMsg(msg)
 			}
@@ -972,7 +1074,7 @@ func (svc *webService) foreignServiceHandler(srv interface{}, stream googlegrpc.
 				return err
 			}
 			if fqName.ContainsRemoteNames() {
-				msg.SetFieldByName("name", fqName.PopRemote().ShortName())
+				msg.SetFieldByName("name", remoteShortName)
 			}
 			if err := clientStream.SendMsg(msg); err != nil {
 				if errors.Is(err, io.EOF) {
@@ -32,6 +32,7 @@ func New(r robot.Robot, logger logging.Logger, opts ...Option) Service {
 		streamServer:       nil,
 		services:           map[resource.API]resource.APIResourceCollection[resource.Resource]{},
 		modPeerConnTracker: grpc.NewModPeerConnTracker(),
+		remoteAccess:       grpc.NewRemoteAccessPolicy(),
 		opts:               wOpts,
 	}
 	return webSvc
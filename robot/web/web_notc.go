@@ -24,6 +24,7 @@ func New(r robot.Robot, logger logging.Logger, opts ...Option) Service {
 		rpcServer:          nil,
 		services:           map[resource.API]resource.APIResourceCollection[resource.Resource]{},
 		modPeerConnTracker: grpc.NewModPeerConnTracker(),
+		remoteAccess:       grpc.NewRemoteAccessPolicy(),
 		opts:               wOpts,
 	}
 	return webSvc
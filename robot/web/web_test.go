@@ -6,8 +6,10 @@ import (
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"testing"
 	"time"
@@ -23,6 +25,7 @@ import (
 	streampb "go.viam.com/api/stream/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils"
+	rpcpb "go.viam.com/utils/proto/rpc/v1"
 	"go.viam.com/utils/rpc"
 	"go.viam.com/utils/testutils"
 	"google.golang.org/grpc"
@@ -35,6 +38,7 @@ import (
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/config"
 	gizmopb "go.viam.com/rdk/examples/customresources/apis/proto/api/component/gizmo/v1"
+	"go.viam.com/rdk/ftdc"
 	"go.viam.com/rdk/gostream"
 	"go.viam.com/rdk/gostream/codec/opus"
 	"go.viam.com/rdk/gostream/codec/x264"
@@ -733,6 +737,146 @@ func TestWebWithOnlyNewAPIKeyAuthHandlers(t *testing.T) {
 	test.That(t, svc.Close(context.Background()), test.ShouldBeNil)
 }
 
+func TestWebDebugEndpoints(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+
+	svc := web.New(injectRobot, logger)
+
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	options.Pprof = true
+	apiKeyID := uuid.New().String()
+	apiKey := utils.RandomAlphaString(32)
+	options.Auth.Handlers = []config.AuthHandlerConfig{
+		{
+			Type: rpc.CredentialsTypeAPIKey,
+			Config: rutils.AttributeMap{
+				apiKeyID: apiKey,
+				"keys":   []string{apiKeyID},
+			},
+		},
+	}
+
+	err := svc.Start(ctx, options)
+	test.That(t, err, test.ShouldBeNil)
+
+	get := func(path, token string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+path, nil)
+		test.That(t, err, test.ShouldBeNil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		test.That(t, err, test.ShouldBeNil)
+		return resp
+	}
+
+	resp := get("/debug/runtime", "")
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusUnauthorized)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+
+	resp = get("/debug/pprof/goroutine", "not-a-token")
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusUnauthorized)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+
+	conn, err := rgrpc.Dial(context.Background(), addr, logger,
+		rpc.WithAllowInsecureWithCredentialsDowngrade(),
+		rpc.WithEntityCredentials(apiKeyID, rpc.Credentials{
+			Type:    rpc.CredentialsTypeAPIKey,
+			Payload: apiKey,
+		}),
+		rpc.WithForceDirectGRPC(),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	authResp, err := rpcpb.NewAuthServiceClient(conn).Authenticate(ctx, &rpcpb.AuthenticateRequest{
+		Entity:      apiKeyID,
+		Credentials: &rpcpb.Credentials{Type: string(rpc.CredentialsTypeAPIKey), Payload: apiKey},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conn.Close(), test.ShouldBeNil)
+
+	resp = get("/debug/pprof/goroutine?debug=1", authResp.AccessToken)
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+
+	resp = get("/debug/runtime", authResp.AccessToken)
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+	var snapshot struct {
+		Goroutines           int `json:"goroutines"`
+		GoroutinesByFunction []struct {
+			Function string `json:"function"`
+			Count    int    `json:"count"`
+		} `json:"goroutines_by_function"`
+		Memory struct {
+			HeapAllocBytes uint64 `json:"heap_alloc_bytes"`
+		} `json:"memory"`
+	}
+	test.That(t, json.NewDecoder(resp.Body).Decode(&snapshot), test.ShouldBeNil)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+	test.That(t, snapshot.Goroutines, test.ShouldBeGreaterThan, 0)
+	test.That(t, snapshot.GoroutinesByFunction, test.ShouldNotBeEmpty)
+	test.That(t, snapshot.Memory.HeapAllocBytes, test.ShouldBeGreaterThan, 0)
+
+	test.That(t, svc.Close(context.Background()), test.ShouldBeNil)
+}
+
+type ftdcRobot struct {
+	robot.Robot
+	latest ftdc.FlatDatum
+}
+
+func (r *ftdcRobot) LatestFTDC() (ftdc.FlatDatum, bool) {
+	return r.latest, true
+}
+
+func (r *ftdcRobot) SubscribeFTDC(bufferSize int, metricPrefixes ...string) (*ftdc.Subscription, bool) {
+	return nil, false
+}
+
+func TestWebMetrics(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+	recorded := time.Now().Truncate(time.Millisecond)
+	r := &ftdcRobot{Robot: injectRobot, latest: ftdc.FlatDatum{
+		Time: recorded.UnixNano(),
+		Readings: []ftdc.Reading{
+			{MetricName: "proc.viam-server.UserCPUSecs", Value: 1.5},
+			{MetricName: "proc.viam-server.RssMB", Value: 100},
+			{MetricName: "net.TxBytes", Value: 42},
+		},
+	}}
+
+	svc := web.New(r, logger)
+
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	err := svc.Start(ctx, options)
+	test.That(t, err, test.ShouldBeNil)
+
+	resp, err := http.Get("http://" + addr + "/debug/metrics")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+	test.That(t, resp.Header.Get("Content-Type"), test.ShouldContainSubstring, "text/html")
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+
+	resp, err = http.Get("http://" + addr + "/debug/metrics/latest?metric=proc.viam-server.U&metric=net.")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+	var latest struct {
+		TimeMs   int64              `json:"time_ms"`
+		Readings map[string]float32 `json:"readings"`
+	}
+	test.That(t, json.NewDecoder(resp.Body).Decode(&latest), test.ShouldBeNil)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+	test.That(t, latest.TimeMs, test.ShouldEqual, recorded.UnixMilli())
+	test.That(t, latest.Readings, test.ShouldResemble, map[string]float32{
+		"proc.viam-server.UserCPUSecs": 1.5,
+		"net.TxBytes":                  42,
+	})
+
+	test.That(t, svc.Close(context.Background()), test.ShouldBeNil)
+}
+
 func TestWebReconfigure(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 	// robot is configured with an arm
@@ -0,0 +1,105 @@
+// Package alerting defines an alerting service, which evaluates rules over the readings and health
+// of a machine's resources and sends notifications when they fire and resolve.
+//
+// There is no alerting proto, so alerting services are generic services whose typed methods are
+// carried over DoCommand. Models implement Service and answer the reserved commands by calling
+// HandleDoCommand from their DoCommand.
+package alerting
+
+import (
+	"context"
+	"time"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/generic"
+)
+
+// API is the resource API alerting services are served under.
+var API = generic.API
+
+// Named is a helper for getting the named alerting service's typed resource name.
+func Named(name string) resource.Name {
+	return resource.NewName(API, name)
+}
+
+// The severities of alerts.
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// A Service evaluates alerting rules and notifies of the alerts they raise.
+//
+// Alerts example:
+//
+//	myAlerts, err := alerting.FromRobot(machine, "alerts")
+//	alerts, err := myAlerts.Alerts(context.Background())
+//	for _, alert := range alerts {
+//		if alert.Firing {
+//			fmt.Printf("%s: %s\n", alert.Severity, alert.Message)
+//		}
+//	}
+//
+// Silence example:
+//
+//	// Don't notify about the battery while the machine is being serviced.
+//	err := myAlerts.Silence(context.Background(), "battery-low", 2*time.Hour)
+type Service interface {
+	resource.Resource
+
+	// Alerts returns the state of each rule's alert.
+	Alerts(ctx context.Context) ([]Alert, error)
+
+	// Silence stops notifications about the named rule's alert, or about all alerts if rule is
+	// empty, for `duration`. A zero duration ends the silence. Alerts still fire while silenced,
+	// and are notified of when the silence ends if they are still firing.
+	Silence(ctx context.Context, rule string, duration time.Duration) error
+}
+
+// Alert is the state of a rule's alert.
+type Alert struct {
+	Rule     string
+	Severity string
+	// Firing is whether the rule's condition holds.
+	Firing bool
+	// Since is when the alert started firing, or when it last resolved.
+	Since time.Time
+	// Message describes the alert, including the value which fired it.
+	Message string
+	// Value is the value the rule last evaluated.
+	Value interface{}
+	// SilencedUntil is when the alert's silence ends, if it is silenced.
+	SilencedUntil time.Time
+	// LastNotified is when notifications were last sent about the alert.
+	LastNotified time.Time
+}
+
+// FromResource returns `res` as a Service. Resources that do not implement Service, such as the
+// clients of remote alerting services, are wrapped in a client that calls them over DoCommand.
+func FromResource(res resource.Resource) Service {
+	if s, ok := res.(Service); ok {
+		return s
+	}
+	return NewClientFromResource(res)
+}
+
+// FromDependencies is a helper for getting the named alerting service from a collection of
+// dependencies.
+func FromDependencies(deps resource.Dependencies, name string) (Service, error) {
+	res, err := resource.FromDependencies[resource.Resource](deps, Named(name))
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
+
+// FromRobot is a helper for getting the named alerting service from the given Robot.
+func FromRobot(r robot.Robot, name string) (Service, error) {
+	res, err := generic.FromRobot(r, name)
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
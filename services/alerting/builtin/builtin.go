@@ -0,0 +1,525 @@
+// Package builtin implements an alerting service which evaluates rules over the machine's own
+// resources and notifies webhooks, MQTT brokers and email addresses of their alerts.
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+	vprotoutils "go.viam.com/utils/protoutils"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/protoutils"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/alerting"
+	"go.viam.com/rdk/services/scheduler"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+// Model is the model of the builtin alerting service.
+var Model = resource.DefaultModelFamily.WithModel("alert_manager")
+
+func init() {
+	resource.RegisterService(alerting.API, Model, resource.Registration[resource.Resource, *Config]{
+		Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			newConf, err := resource.NativeConfig[*Config](conf)
+			if err != nil {
+				return nil, err
+			}
+			return newAlertManager(conf.ResourceName(), newConf, deps, rdkutils.ClockFromContext(ctx), logger)
+		},
+	})
+}
+
+type alertManager struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	clk       clock.Clock
+	logger    logging.Logger
+	rules     []*rule
+	notifiers map[string]notifier
+	silences  []silence
+	workers   *goutils.StoppableWorkers
+
+	// evalMu is held while rules are evaluated, so evaluations never overlap.
+	evalMu sync.Mutex
+
+	mu sync.Mutex
+	// silencedUntil holds the silences set with Silence, by rule, with "" silencing all rules.
+	silencedUntil map[string]time.Time
+}
+
+// silence is a configured recurring silence window.
+type silence struct {
+	rules    map[string]bool
+	schedule *scheduler.Schedule
+	duration time.Duration
+}
+
+// until returns when the silence window `t` is within ends, or false if `t` is not within one.
+func (s silence) until(rule string, t time.Time) (time.Time, bool) {
+	if len(s.rules) > 0 && !s.rules[rule] {
+		return time.Time{}, false
+	}
+	start := s.schedule.Next(t.Add(-s.duration))
+	if start.IsZero() || start.After(t) {
+		return time.Time{}, false
+	}
+	return start.Add(s.duration), true
+}
+
+func newAlertManager(
+	name resource.Name,
+	conf *Config,
+	deps resource.Dependencies,
+	clk clock.Clock,
+	logger logging.Logger,
+) (alerting.Service, error) {
+	m := &alertManager{
+		Named:         name.AsNamed(),
+		clk:           clk,
+		logger:        logger,
+		notifiers:     map[string]notifier{},
+		silencedUntil: map[string]time.Time{},
+	}
+	for _, notifierConf := range conf.Notifiers {
+		m.notifiers[notifierConf.Name] = newNotifier(notifierConf)
+	}
+	for _, ruleConf := range conf.Rules {
+		r, err := newRule(ruleConf, deps)
+		if err != nil {
+			return nil, errors.Wrapf(err, "rule %q", ruleConf.Name)
+		}
+		if len(r.conf.Notifiers) == 0 {
+			for _, notifierConf := range conf.Notifiers {
+				r.conf.Notifiers = append(r.conf.Notifiers, notifierConf.Name)
+			}
+		}
+		m.rules = append(m.rules, r)
+	}
+	for _, silenceConf := range conf.Silences {
+		schedule, err := scheduler.ParseSchedule(silenceConf.Schedule)
+		if err != nil {
+			return nil, err
+		}
+		s := silence{
+			rules:    map[string]bool{},
+			schedule: schedule,
+			duration: time.Duration(silenceConf.DurationSeconds * float64(time.Second)),
+		}
+		for _, name := range silenceConf.Rules {
+			s.rules[name] = true
+		}
+		m.silences = append(m.silences, s)
+	}
+
+	interval := conf.evaluationInterval()
+	m.workers = goutils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
+		ticker := m.clk.Ticker(interval)
+		defer ticker.Stop()
+		for {
+			m.evaluate(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+	return m, nil
+}
+
+// evaluate evaluates all rules and sends the notifications due.
+func (m *alertManager) evaluate(ctx context.Context) {
+	m.evalMu.Lock()
+	defer m.evalMu.Unlock()
+	now := m.clk.Now()
+
+	var wg sync.WaitGroup
+	results := make([]evaluation, len(m.rules))
+	for i, r := range m.rules {
+		wg.Add(1)
+		goutils.PanicCapturingGo(func() {
+			defer wg.Done()
+			results[i] = r.evaluate(ctx)
+		})
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	for i, r := range m.rules {
+		if results[i].err != nil {
+			m.logger.CDebugw(ctx, "cannot evaluate rule", "rule", r.conf.Name, "error", results[i].err)
+		}
+		for _, n := range r.update(results[i], now, m.silencedAt(r.conf.Name, now)) {
+			m.dispatch(ctx, r, n)
+		}
+	}
+}
+
+// silencedAt returns when the silence of the named rule at `t` ends, or the zero time if it is
+// not silenced.
+func (m *alertManager) silencedAt(rule string, t time.Time) time.Time {
+	var until time.Time
+	m.mu.Lock()
+	for _, name := range []string{"", rule} {
+		if end := m.silencedUntil[name]; end.After(t) && end.After(until) {
+			until = end
+		}
+	}
+	m.mu.Unlock()
+	for _, s := range m.silences {
+		if end, ok := s.until(rule, t); ok && end.After(until) {
+			until = end
+		}
+	}
+	return until
+}
+
+// dispatch sends the notification to the notifiers it is for, and records which ones were sent
+// it so that the rest are retried at the next evaluation.
+func (m *alertManager) dispatch(ctx context.Context, r *rule, n pendingNotification) {
+	for _, name := range n.notifiers {
+		notifyCtx, cancel := context.WithTimeout(ctx, notifyTimeout)
+		err := m.notifiers[name].notify(notifyCtx, n.Notification)
+		cancel()
+		if err != nil {
+			m.logger.CWarnw(ctx, "cannot send notification, will retry", "rule", r.conf.Name, "notifier", name, "error", err)
+			continue
+		}
+		r.sent(name, n.State, m.clk.Now())
+	}
+}
+
+func (m *alertManager) Alerts(ctx context.Context) ([]alerting.Alert, error) {
+	now := m.clk.Now()
+	alerts := make([]alerting.Alert, 0, len(m.rules))
+	for _, r := range m.rules {
+		alert := r.alert()
+		alert.SilencedUntil = m.silencedAt(r.conf.Name, now)
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+func (m *alertManager) Silence(ctx context.Context, rule string, duration time.Duration) error {
+	if rule != "" {
+		found := false
+		for _, r := range m.rules {
+			found = found || r.conf.Name == rule
+		}
+		if !found {
+			return errors.Errorf("no rule named %q", rule)
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if duration <= 0 {
+		delete(m.silencedUntil, rule)
+		return nil
+	}
+	m.silencedUntil[rule] = m.clk.Now().Add(duration)
+	return nil
+}
+
+func (m *alertManager) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	resp, handled, err := alerting.HandleDoCommand(ctx, m, cmd)
+	if handled {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+func (m *alertManager) Close(ctx context.Context) error {
+	m.workers.Stop()
+	return nil
+}
+
+// evaluation is the result of evaluating a rule.
+type evaluation struct {
+	value interface{}
+	// holds is whether the rule's condition holds, and is only meaningful if err is nil or the rule
+	// watches for failures.
+	holds bool
+	err   error
+}
+
+// rule is a configured rule and the state of its alert.
+type rule struct {
+	conf RuleConfig
+	call func(ctx context.Context) (map[string]interface{}, error)
+	path []string
+
+	mu           sync.Mutex
+	holdingSince time.Time
+	firing       bool
+	since        time.Time
+	value        interface{}
+	message      string
+	lastNotified time.Time
+	// notified holds when each notifier was last sent the current firing, and resolved holds the
+	// notifiers still to be sent that it resolved.
+	notified map[string]time.Time
+	resolved map[string]bool
+}
+
+func newRule(conf RuleConfig, deps resource.Dependencies) (*rule, error) {
+	res, err := findDependency(deps, conf.Resource)
+	if err != nil {
+		return nil, err
+	}
+	r := &rule{conf: conf, notified: map[string]time.Time{}, resolved: map[string]bool{}}
+	if conf.Field != "" {
+		r.path = strings.Split(conf.Field, ".")
+	}
+	switch conf.Method {
+	case "", MethodReadings:
+		s, ok := res.(resource.Sensor)
+		if !ok {
+			return nil, errors.Errorf("%q has no readings", conf.Resource)
+		}
+		r.call = func(ctx context.Context) (map[string]interface{}, error) {
+			readings, err := s.Readings(ctx, nil)
+			if err != nil {
+				return nil, err
+			}
+			// readings are compared as they would be sent to a client, so all numbers are float64
+			encoded, err := protoutils.ReadingGoToProto(readings)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string]interface{}, len(encoded))
+			for k, v := range encoded {
+				result[k] = v.AsInterface()
+			}
+			return result, nil
+		}
+	case MethodDoCommand:
+		r.call = func(ctx context.Context) (map[string]interface{}, error) {
+			result, err := res.DoCommand(ctx, conf.Command)
+			if err != nil {
+				return nil, err
+			}
+			encoded, err := vprotoutils.StructToStructPb(result)
+			if err != nil {
+				return nil, err
+			}
+			return encoded.AsMap(), nil
+		}
+	}
+	return r, nil
+}
+
+// evaluate calls the rule's resource and evaluates its condition.
+func (r *rule) evaluate(ctx context.Context) evaluation {
+	result, err := r.call(ctx)
+	if r.conf.Operator == OperatorFailing {
+		if err != nil {
+			return evaluation{value: err.Error(), holds: true}
+		}
+		return evaluation{}
+	}
+	if err != nil {
+		return evaluation{err: err}
+	}
+	var value interface{} = result
+	for _, key := range r.path {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return evaluation{err: errors.Errorf("%q is not in the result", r.conf.Field)}
+		}
+		if value, ok = fields[key]; !ok {
+			return evaluation{err: errors.Errorf("%q is not in the result", r.conf.Field)}
+		}
+	}
+	return evaluation{value: value, holds: compare(value, r.conf.Operator, r.conf.Value)}
+}
+
+// pendingNotification is a notification and the notifiers it is still to be sent to.
+type pendingNotification struct {
+	Notification
+	notifiers []string
+}
+
+// update updates the alert with the evaluation at `now` and returns the notifications due. The
+// alert is silenced until `silencedUntil` if it is in the future.
+func (r *rule) update(result evaluation, now, silencedUntil time.Time) []pendingNotification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// a rule that cannot be evaluated keeps its alert as it was
+	if result.err == nil {
+		r.value = result.value
+		if result.holds {
+			if r.holdingSince.IsZero() {
+				r.holdingSince = now
+			}
+			if !r.firing && now.Sub(r.holdingSince) >= time.Duration(r.conf.ForSeconds*float64(time.Second)) {
+				r.firing = true
+				r.since = now
+				r.notified = map[string]time.Time{}
+				r.resolved = map[string]bool{}
+			}
+		} else {
+			r.holdingSince = time.Time{}
+			if r.firing {
+				r.firing = false
+				r.since = now
+				if r.conf.NotifyResolved {
+					for name := range r.notified {
+						r.resolved[name] = true
+					}
+				}
+				r.notified = map[string]time.Time{}
+			}
+		}
+		if r.firing {
+			r.message = r.describe()
+		}
+	}
+
+	var pending []pendingNotification
+	if r.firing && !silencedUntil.After(now) {
+		var due []string
+		repeat := time.Duration(r.conf.RepeatIntervalSeconds * float64(time.Second))
+		for _, name := range r.conf.Notifiers {
+			last, ok := r.notified[name]
+			if !ok || (repeat > 0 && now.Sub(last) >= repeat) {
+				due = append(due, name)
+			}
+		}
+		if len(due) > 0 {
+			pending = append(pending, pendingNotification{Notification: r.notification(StateFiring, now), notifiers: due})
+		}
+	}
+	if !r.firing && len(r.resolved) > 0 {
+		var due []string
+		for _, name := range r.conf.Notifiers {
+			if r.resolved[name] {
+				due = append(due, name)
+			}
+		}
+		pending = append(pending, pendingNotification{Notification: r.notification(StateResolved, now), notifiers: due})
+	}
+	return pending
+}
+
+// sent records that the named notifier was sent a notification of the alert's state.
+func (r *rule) sent(notifier, state string, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastNotified = now
+	switch state {
+	case StateFiring:
+		if r.firing {
+			r.notified[notifier] = now
+		}
+	case StateResolved:
+		delete(r.resolved, notifier)
+	}
+}
+
+// describe returns the alert's message. It must be called with mu held.
+func (r *rule) describe() string {
+	if r.conf.Message != "" {
+		return strings.ReplaceAll(r.conf.Message, "{value}", fmt.Sprint(r.value))
+	}
+	if r.conf.Operator == OperatorFailing {
+		return fmt.Sprintf("%s is failing: %v", r.conf.Resource, r.value)
+	}
+	return fmt.Sprintf("%s %s is %v (%s %v)", r.conf.Resource, r.conf.Field, r.value, r.conf.Operator, r.conf.Value)
+}
+
+// notification returns a notification of the alert's state. It must be called with mu held.
+func (r *rule) notification(state string, now time.Time) Notification {
+	return Notification{
+		Rule:     r.conf.Name,
+		Severity: r.conf.severity(),
+		State:    state,
+		Message:  r.message,
+		Resource: r.conf.Resource,
+		Value:    r.value,
+		Since:    r.since,
+		Time:     now,
+	}
+}
+
+func (r *rule) alert() alerting.Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return alerting.Alert{
+		Rule:         r.conf.Name,
+		Severity:     r.conf.severity(),
+		Firing:       r.firing,
+		Since:        r.since,
+		Message:      r.message,
+		Value:        r.value,
+		LastNotified: r.lastNotified,
+	}
+}
+
+// findDependency returns the dependency of the given short or fully qualified name.
+func findDependency(deps resource.Dependencies, name string) (resource.Resource, error) {
+	if fullName, err := resource.NewFromString(name); err == nil {
+		return deps.Lookup(fullName)
+	}
+	var found resource.Resource
+	for depName, res := range deps {
+		if depName.ShortName() != name {
+			continue
+		}
+		if found != nil {
+			return nil, errors.Errorf("more than one resource is named %q, use its fully qualified name", name)
+		}
+		found = res
+	}
+	if found == nil {
+		return nil, errors.Errorf("resource %q not found", name)
+	}
+	return found, nil
+}
+
+// compare returns whether `actual` relates to `expected` by `op`. Values of different types are
+// never equal, and only numbers are ordered.
+func compare(actual interface{}, op string, expected interface{}) bool {
+	a, aIsNumber := actual.(float64)
+	e, eIsNumber := expected.(float64)
+	if aIsNumber && eIsNumber {
+		switch op {
+		case OperatorEqual:
+			return a == e
+		case OperatorNotEqual:
+			return a != e
+		case OperatorGreaterThan:
+			return a > e
+		case OperatorGreaterThanOrEqual:
+			return a >= e
+		case OperatorLessThan:
+			return a < e
+		case OperatorLessThanOrEqual:
+			return a <= e
+		}
+		return false
+	}
+	switch op {
+	case OperatorEqual:
+		return actual == expected
+	case OperatorNotEqual:
+		return actual != expected
+	}
+	return false
+}
@@ -0,0 +1,398 @@
+package builtin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/alerting"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils/mqtt/mqtttest"
+)
+
+func TestConfigValidate(t *testing.T) {
+	conf := &Config{
+		Rules: []RuleConfig{
+			{Name: "battery-low", Resource: "battery", Field: "voltage", Operator: OperatorLessThan, Value: 11.5, Notifiers: []string{"ops"}},
+			{Name: "camera-down", Resource: "rdk:component:camera/front", Operator: OperatorFailing},
+			{
+				Name: "queue", Resource: "capture", Method: MethodDoCommand, Command: map[string]interface{}{"metrics": true},
+				Field: "queue.state", Operator: OperatorEqual, Value: "full",
+			},
+		},
+		Notifiers: []NotifierConfig{{Name: "ops", Type: NotifierWebhook, URL: "http://localhost"}},
+		Silences:  []SilenceConfig{{Rules: []string{"battery-low"}, Schedule: "0 22 * * *", DurationSeconds: 3600}},
+	}
+	deps, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"battery", "rdk:component:camera/front", "capture"})
+
+	for _, tc := range []struct {
+		conf     Config
+		expected string
+	}{
+		{Config{Rules: []RuleConfig{{Resource: "a", Operator: OperatorFailing}}}, "name"},
+		{Config{Rules: []RuleConfig{{Name: "a", Operator: OperatorFailing}}}, "resource"},
+		{Config{Rules: []RuleConfig{{Name: "a", Resource: "a", Operator: "~"}}}, `unknown operator "~"`},
+		{Config{Rules: []RuleConfig{{Name: "a", Resource: "a", Operator: OperatorLessThan, Value: 1.0}}}, "needs a field"},
+		{Config{Rules: []RuleConfig{{Name: "a", Resource: "a", Field: "f", Operator: OperatorLessThan, Value: "1"}}}, "only compare numbers"},
+		{Config{Rules: []RuleConfig{{Name: "a", Resource: "a", Field: "f", Operator: OperatorEqual}}}, "needs a value"},
+		{Config{Rules: []RuleConfig{{Name: "a", Resource: "a", Method: MethodDoCommand, Operator: OperatorFailing}}}, "command"},
+		{Config{Rules: []RuleConfig{{Name: "a", Resource: "a", Operator: OperatorFailing, Severity: "dire"}}}, "unknown severity"},
+		{Config{Rules: []RuleConfig{{Name: "a", Resource: "a", Operator: OperatorFailing, Notifiers: []string{"ops"}}}}, `unknown notifier "ops"`},
+		{Config{Notifiers: []NotifierConfig{{Name: "ops", Type: NotifierMQTT, Broker: "tcp://localhost"}}}, "topic"},
+		{Config{Notifiers: []NotifierConfig{{Name: "ops", Type: NotifierEmail, SMTPServer: "localhost:25", From: "a@b"}}}, "to"},
+		{Config{Notifiers: []NotifierConfig{{Name: "ops", Type: "pager"}}}, "type must be one of"},
+		{Config{Silences: []SilenceConfig{{Schedule: "@often", DurationSeconds: 1}}}, "invalid schedule"},
+		{Config{Silences: []SilenceConfig{{Schedule: "@daily"}}}, "duration_seconds"},
+		{Config{Silences: []SilenceConfig{{Rules: []string{"a"}, Schedule: "@daily", DurationSeconds: 1}}}, `unknown rule "a"`},
+	} {
+		_, err := tc.conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.expected)
+	}
+
+	failing := RuleConfig{Name: "a", Resource: "a", Operator: OperatorFailing}
+	_, err = (&Config{Rules: []RuleConfig{failing, failing}}).Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, `duplicate rule name "a"`)
+}
+
+// webhook is a webhook server recording the notifications it is sent.
+type webhook struct {
+	*httptest.Server
+	status        atomic.Int64
+	notifications chan Notification
+	header        chan string
+}
+
+func newWebhook(t *testing.T) *webhook {
+	t.Helper()
+	w := &webhook{notifications: make(chan Notification, 10), header: make(chan string, 10)}
+	w.status.Store(http.StatusOK)
+	w.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		status := int(w.status.Load())
+		if status == http.StatusOK {
+			var n Notification
+			if err := json.NewDecoder(req.Body).Decode(&n); err != nil {
+				status = http.StatusBadRequest
+			} else {
+				w.notifications <- n
+				w.header <- req.Header.Get("Authorization")
+			}
+		}
+		rw.WriteHeader(status)
+	}))
+	t.Cleanup(w.Close)
+	return w
+}
+
+func (w *webhook) expectNone(t *testing.T) {
+	t.Helper()
+	select {
+	case n := <-w.notifications:
+		t.Fatalf("unexpected notification %+v", n)
+	default:
+	}
+}
+
+type reading struct {
+	value float64
+	err   error
+}
+
+func TestAlerts(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	clk := clock.NewMock()
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	clk.Set(start)
+
+	// voltage holds the battery's voltage, or the error reading it fails with
+	var voltage atomic.Value
+	voltage.Store(reading{value: 12.5})
+	battery := inject.NewSensor("battery")
+	battery.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		r := voltage.Load().(reading)
+		if r.err != nil {
+			return nil, r.err
+		}
+		return map[string]interface{}{"voltage": r.value}, nil
+	}
+	hook := newWebhook(t)
+
+	conf := &Config{
+		EvaluationIntervalSeconds: 3600,
+		Rules: []RuleConfig{{
+			Name: "battery-low", Resource: "battery", Field: "voltage", Operator: OperatorLessThan, Value: 11.5,
+			ForSeconds: 60, Severity: alerting.SeverityCritical, Message: "battery at {value}V", NotifyResolved: true,
+		}},
+		Notifiers: []NotifierConfig{{Name: "ops", Type: NotifierWebhook, URL: hook.URL, Headers: map[string]string{"Authorization": "Bearer x"}}},
+	}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	deps := resource.Dependencies{sensor.Named("battery"): battery}
+	svc, err := newAlertManager(alerting.Named("alerts"), conf, deps, clk, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer svc.Close(ctx)
+	m := svc.(*alertManager)
+	// the typed methods are carried over DoCommand for clients
+	client := alerting.FromResource(struct{ resource.Resource }{svc})
+
+	m.evaluate(ctx)
+	alerts, err := client.Alerts(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, alerts, test.ShouldHaveLength, 1)
+	test.That(t, alerts[0].Firing, test.ShouldBeFalse)
+	test.That(t, alerts[0].Value, test.ShouldEqual, 12.5)
+
+	t.Run("fires once the condition holds long enough", func(t *testing.T) {
+		voltage.Store(reading{value: 11.0})
+		m.evaluate(ctx)
+		hook.expectNone(t)
+		clk.Add(time.Minute)
+		m.evaluate(ctx)
+		n := <-hook.notifications
+		test.That(t, <-hook.header, test.ShouldEqual, "Bearer x")
+		test.That(t, n, test.ShouldResemble, Notification{
+			Rule: "battery-low", Severity: alerting.SeverityCritical, State: StateFiring, Message: "battery at 11V",
+			Resource: "battery", Value: 11.0, Since: clk.Now(), Time: clk.Now(),
+		})
+
+		// firing alerts are only notified of once
+		clk.Add(time.Minute)
+		voltage.Store(reading{value: 10.5})
+		m.evaluate(ctx)
+		hook.expectNone(t)
+
+		alerts, err := client.Alerts(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, alerts[0].Firing, test.ShouldBeTrue)
+		test.That(t, alerts[0].Message, test.ShouldEqual, "battery at 10.5V")
+		test.That(t, alerts[0].Since.Equal(start.Add(time.Minute)), test.ShouldBeTrue)
+		test.That(t, alerts[0].LastNotified.Equal(start.Add(time.Minute)), test.ShouldBeTrue)
+	})
+
+	t.Run("unreadable resources keep the alert as it was", func(t *testing.T) {
+		voltage.Store(reading{err: errors.New("i2c timeout")})
+		m.evaluate(ctx)
+		hook.expectNone(t)
+		alerts, err := client.Alerts(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, alerts[0].Firing, test.ShouldBeTrue)
+	})
+
+	t.Run("resolving is notified of", func(t *testing.T) {
+		voltage.Store(reading{value: 12.0})
+		m.evaluate(ctx)
+		n := <-hook.notifications
+		<-hook.header
+		test.That(t, n.State, test.ShouldEqual, StateResolved)
+		m.evaluate(ctx)
+		hook.expectNone(t)
+	})
+
+	t.Run("failed notifications are retried", func(t *testing.T) {
+		hook.status.Store(http.StatusServiceUnavailable)
+		voltage.Store(reading{value: 11.0})
+		m.evaluate(ctx)
+		clk.Add(time.Minute)
+		m.evaluate(ctx)
+		hook.expectNone(t)
+
+		hook.status.Store(http.StatusOK)
+		clk.Add(10 * time.Second)
+		m.evaluate(ctx)
+		n := <-hook.notifications
+		<-hook.header
+		test.That(t, n.State, test.ShouldEqual, StateFiring)
+		test.That(t, n.Since.Equal(clk.Now().Add(-10*time.Second)), test.ShouldBeTrue)
+	})
+
+	t.Run("silenced alerts are notified of when the silence ends", func(t *testing.T) {
+		voltage.Store(reading{value: 12.0})
+		m.evaluate(ctx)
+		<-hook.notifications
+		<-hook.header
+
+		test.That(t, client.Silence(ctx, "battery", time.Hour), test.ShouldBeError, `no rule named "battery"`)
+		test.That(t, client.Silence(ctx, "", time.Hour), test.ShouldBeNil)
+		voltage.Store(reading{value: 11.0})
+		m.evaluate(ctx)
+		clk.Add(time.Minute)
+		m.evaluate(ctx)
+		hook.expectNone(t)
+		alerts, err := client.Alerts(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, alerts[0].Firing, test.ShouldBeTrue)
+		test.That(t, alerts[0].SilencedUntil.Equal(clk.Now().Add(59*time.Minute)), test.ShouldBeTrue)
+
+		test.That(t, client.Silence(ctx, "", 0), test.ShouldBeNil)
+		m.evaluate(ctx)
+		n := <-hook.notifications
+		<-hook.header
+		test.That(t, n.State, test.ShouldEqual, StateFiring)
+	})
+}
+
+func TestSilenceWindowsAndMQTT(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	clk := clock.NewMock()
+	clk.Set(time.Date(2026, 3, 2, 23, 0, 0, 0, time.UTC))
+
+	broker, err := mqtttest.NewBroker()
+	test.That(t, err, test.ShouldBeNil)
+	defer broker.Close()
+
+	var healthy atomic.Bool
+	cam := inject.NewCamera("front")
+	cam.DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		if !healthy.Load() {
+			return nil, errors.New("no frames")
+		}
+		return map[string]interface{}{}, nil
+	}
+
+	conf := &Config{
+		EvaluationIntervalSeconds: 3600,
+		Rules: []RuleConfig{{
+			Name: "camera-down", Resource: "front", Method: MethodDoCommand, Command: map[string]interface{}{"health": true},
+			Operator: OperatorFailing, RepeatIntervalSeconds: 600,
+		}},
+		Notifiers: []NotifierConfig{{Name: "fleet", Type: NotifierMQTT, Broker: broker.Addr(), Topic: "alerts", Username: "robot"}},
+		Silences:  []SilenceConfig{{Schedule: "0 22 * * *", DurationSeconds: 8 * 3600}},
+	}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	deps := resource.Dependencies{camera.Named("front"): cam}
+	svc, err := newAlertManager(alerting.Named("alerts"), conf, deps, clk, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer svc.Close(ctx)
+	m := svc.(*alertManager)
+
+	m.evaluate(ctx)
+	alerts, err := svc.Alerts(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, alerts[0].Firing, test.ShouldBeTrue)
+	test.That(t, alerts[0].Message, test.ShouldEqual, "front is failing: no frames")
+	test.That(t, alerts[0].SilencedUntil, test.ShouldResemble, time.Date(2026, 3, 3, 6, 0, 0, 0, time.UTC))
+
+	clk.Set(time.Date(2026, 3, 3, 6, 0, 0, 0, time.UTC))
+	m.evaluate(ctx)
+	msg := <-broker.Messages()
+	test.That(t, msg.Topic, test.ShouldEqual, "alerts")
+	test.That(t, msg.QoS, test.ShouldEqual, 1)
+	test.That(t, msg.Username, test.ShouldEqual, "robot")
+	var n Notification
+	test.That(t, json.Unmarshal(msg.Payload, &n), test.ShouldBeNil)
+	test.That(t, n.Rule, test.ShouldEqual, "camera-down")
+	test.That(t, n.Severity, test.ShouldEqual, alerting.SeverityWarning)
+	test.That(t, n.Value, test.ShouldEqual, "no frames")
+
+	// notifications repeat while the alert fires
+	clk.Add(5 * time.Minute)
+	m.evaluate(ctx)
+	clk.Add(5 * time.Minute)
+	m.evaluate(ctx)
+	msg = <-broker.Messages()
+	test.That(t, json.Unmarshal(msg.Payload, &n), test.ShouldBeNil)
+	test.That(t, n.Time, test.ShouldResemble, clk.Now())
+
+	healthy.Store(true)
+	m.evaluate(ctx)
+	alerts, err = svc.Alerts(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, alerts[0].Firing, test.ShouldBeFalse)
+}
+
+// smtpServer accepts one SMTP session and records the message sent in it.
+func smtpServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	messages := make(chan string, 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) {
+			//nolint:errcheck
+			conn.Write([]byte(line + "\r\n"))
+		}
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		for inData := false; ; {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch {
+			case inData && line == ".\r\n":
+				inData = false
+				messages <- data.String()
+				reply("250 OK")
+			case inData:
+				data.WriteString(line)
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case strings.HasPrefix(line, "DATA"):
+				inData = true
+				reply("354 go ahead")
+			case strings.HasPrefix(line, "AUTH"):
+				reply("235 OK")
+			case strings.HasPrefix(line, "QUIT"):
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		listener.Close()
+		wg.Wait()
+	})
+	return listener.Addr().String(), messages
+}
+
+func TestEmail(t *testing.T) {
+	addr, messages := smtpServer(t)
+	n := newNotifier(NotifierConfig{
+		Name: "oncall", Type: NotifierEmail, SMTPServer: addr, From: "robot@example.com", To: []string{"oncall@example.com"},
+		Username: "robot", Password: "secret",
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := n.notify(ctx, Notification{
+		Rule: "battery-low", Severity: alerting.SeverityCritical, State: StateFiring, Message: "battery at 11V",
+		Resource: "battery", Value: 11.0, Since: time.Unix(0, 0), Time: time.Unix(0, 0),
+	})
+	test.That(t, err, test.ShouldBeNil)
+	msg := <-messages
+	test.That(t, msg, test.ShouldContainSubstring, "To: oncall@example.com\r\n")
+	test.That(t, msg, test.ShouldContainSubstring, "Subject: [CRITICAL] battery-low firing: battery at 11V\r\n")
+	test.That(t, msg, test.ShouldContainSubstring, "Value: 11\r\n")
+}
@@ -0,0 +1,291 @@
+package builtin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/alerting"
+	"go.viam.com/rdk/services/scheduler"
+)
+
+const defaultEvaluationInterval = 10 * time.Second
+
+// The operators a rule can compare a value with.
+const (
+	OperatorEqual              = "=="
+	OperatorNotEqual           = "!="
+	OperatorGreaterThan        = ">"
+	OperatorGreaterThanOrEqual = ">="
+	OperatorLessThan           = "<"
+	OperatorLessThanOrEqual    = "<="
+	// OperatorFailing fires while the rule's resource cannot be read, which watches its health.
+	OperatorFailing = "failing"
+)
+
+// The methods a rule can evaluate a resource with.
+const (
+	// MethodReadings gets the readings of a sensor, movement sensor or other resource with
+	// readings. It is the default.
+	MethodReadings = "readings"
+	// MethodDoCommand sends the rule's command to the resource's DoCommand, such as a command
+	// returning a module's metrics.
+	MethodDoCommand = "do_command"
+)
+
+// The types of notifiers.
+const (
+	NotifierWebhook = "webhook"
+	NotifierMQTT    = "mqtt"
+	NotifierEmail   = "email"
+)
+
+// RuleConfig configures an alerting rule, which fires while a field of a resource's readings or
+// DoCommand result compares with a value, or while the resource fails.
+//
+// For example, to alert when a battery runs low for a minute, or when a camera stops responding:
+//
+//	{"name": "battery-low", "resource": "battery", "field": "voltage", "operator": "<", "value": 11.5, "for_seconds": 60}
+//	{"name": "camera-down", "resource": "front", "method": "do_command", "command": {"health": {}}, "operator": "failing"}
+type RuleConfig struct {
+	Name     string `json:"name"`
+	Resource string `json:"resource"`
+	// Method is how the resource is evaluated, "readings" by default.
+	Method  string                 `json:"method,omitempty"`
+	Command map[string]interface{} `json:"command,omitempty"`
+	// Field is the dot separated path of the compared field, such as "position.lat".
+	Field    string      `json:"field,omitempty"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value,omitempty"`
+	// ForSeconds is how long the condition must hold before the alert fires.
+	ForSeconds float64 `json:"for_seconds,omitempty"`
+	// Severity is "info", "warning" or "critical", "warning" by default.
+	Severity string `json:"severity,omitempty"`
+	// Message describes the alert, with "{value}" replaced by the evaluated value. It describes the
+	// condition by default.
+	Message string `json:"message,omitempty"`
+	// Notifiers are the names of the notifiers notified of the alert, all of them by default.
+	Notifiers []string `json:"notifiers,omitempty"`
+	// RepeatIntervalSeconds is how often notifications are repeated while the alert fires. They are
+	// only sent when it starts firing by default.
+	RepeatIntervalSeconds float64 `json:"repeat_interval_seconds,omitempty"`
+	// NotifyResolved sends notifications when the alert stops firing too.
+	NotifyResolved bool `json:"notify_resolved,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *RuleConfig) Validate(path string) error {
+	if conf.Name == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "name")
+	}
+	if conf.Resource == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "resource")
+	}
+	switch conf.Method {
+	case "", MethodReadings:
+	case MethodDoCommand:
+		if len(conf.Command) == 0 {
+			return resource.NewConfigValidationFieldRequiredError(path, "command")
+		}
+	default:
+		return resource.NewConfigValidationError(path, errors.Errorf(
+			"method must be %q or %q, got %q", MethodReadings, MethodDoCommand, conf.Method))
+	}
+	switch conf.Operator {
+	case OperatorFailing:
+	case OperatorEqual, OperatorNotEqual:
+		if err := conf.validateComparison(); err != nil {
+			return resource.NewConfigValidationError(path, err)
+		}
+	case OperatorGreaterThan, OperatorGreaterThanOrEqual, OperatorLessThan, OperatorLessThanOrEqual:
+		if err := conf.validateComparison(); err != nil {
+			return resource.NewConfigValidationError(path, err)
+		}
+		if _, ok := conf.Value.(float64); !ok {
+			return resource.NewConfigValidationError(path, errors.Errorf("can only compare numbers with %q", conf.Operator))
+		}
+	default:
+		return resource.NewConfigValidationError(path, errors.Errorf("unknown operator %q", conf.Operator))
+	}
+	switch conf.Severity {
+	case "", alerting.SeverityInfo, alerting.SeverityWarning, alerting.SeverityCritical:
+	default:
+		return resource.NewConfigValidationError(path, errors.Errorf("unknown severity %q", conf.Severity))
+	}
+	if conf.ForSeconds < 0 || conf.RepeatIntervalSeconds < 0 {
+		return resource.NewConfigValidationError(path, errors.New("for_seconds and repeat_interval_seconds cannot be negative"))
+	}
+	return nil
+}
+
+func (conf *RuleConfig) validateComparison() error {
+	if conf.Field == "" {
+		return errors.New("comparing needs a field")
+	}
+	switch conf.Value.(type) {
+	case float64, string, bool:
+		return nil
+	case nil:
+		return errors.New("comparing needs a value")
+	default:
+		return errors.New("can only compare with a number, string or bool")
+	}
+}
+
+func (conf *RuleConfig) severity() string {
+	if conf.Severity == "" {
+		return alerting.SeverityWarning
+	}
+	return conf.Severity
+}
+
+// NotifierConfig configures where notifications are sent. Webhooks are sent the notification as a
+// JSON POST, MQTT brokers have it published as JSON, and emails describe it.
+//
+// For example:
+//
+//	{"name": "ops", "type": "webhook", "url": "https://hooks.example.com/alerts", "headers": {"Authorization": "Bearer token"}}
+//	{"name": "fleet", "type": "mqtt", "broker": "ssl://broker.example.com:8883", "topic": "machines/alerts", "username": "robot", "password": "secret"}
+//	{"name": "oncall", "type": "email", "smtp_server": "smtp.example.com:587", "from": "robot@example.com", "to": ["oncall@example.com"]}
+type NotifierConfig struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// URL and Headers are the webhook's.
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Broker, Topic and QoS are the MQTT broker's, which is published to with QoS 1 by default.
+	Broker string `json:"broker,omitempty"`
+	Topic  string `json:"topic,omitempty"`
+	QoS    *int   `json:"qos,omitempty"`
+
+	// SMTPServer, From and To address emails.
+	SMTPServer string   `json:"smtp_server,omitempty"`
+	From       string   `json:"from,omitempty"`
+	To         []string `json:"to,omitempty"`
+
+	// Username and Password authenticate with the MQTT broker or SMTP server.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *NotifierConfig) Validate(path string) error {
+	if conf.Name == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "name")
+	}
+	switch conf.Type {
+	case NotifierWebhook:
+		if conf.URL == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, "url")
+		}
+	case NotifierMQTT:
+		if conf.Broker == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, "broker")
+		}
+		if conf.Topic == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, "topic")
+		}
+		if conf.QoS != nil && (*conf.QoS < 0 || *conf.QoS > 1) {
+			return resource.NewConfigValidationError(path, errors.New("qos must be 0 or 1"))
+		}
+	case NotifierEmail:
+		if conf.SMTPServer == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, "smtp_server")
+		}
+		if conf.From == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, "from")
+		}
+		if len(conf.To) == 0 {
+			return resource.NewConfigValidationFieldRequiredError(path, "to")
+		}
+	default:
+		return resource.NewConfigValidationError(path, errors.Errorf(
+			"type must be one of %q, %q or %q, got %q", NotifierWebhook, NotifierMQTT, NotifierEmail, conf.Type))
+	}
+	return nil
+}
+
+// SilenceConfig configures a recurring window in which notifications are not sent, such as
+// during nightly maintenance.
+//
+// For example, to silence the battery alert for 8 hours from 10pm every day:
+//
+//	{"rules": ["battery-low"], "schedule": "0 22 * * *", "duration_seconds": 28800}
+type SilenceConfig struct {
+	// Rules are the names of the silenced rules, all of them by default.
+	Rules []string `json:"rules,omitempty"`
+	// Schedule is when the silence starts, as a cron expression or descriptor, see
+	// scheduler.Schedule.
+	Schedule        string  `json:"schedule"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// Config is the config of the builtin alerting service.
+type Config struct {
+	// EvaluationIntervalSeconds is how often rules are evaluated, 10 seconds by default.
+	EvaluationIntervalSeconds float64          `json:"evaluation_interval_seconds,omitempty"`
+	Rules                     []RuleConfig     `json:"rules"`
+	Notifiers                 []NotifierConfig `json:"notifiers,omitempty"`
+	Silences                  []SilenceConfig  `json:"silences,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid and returns the resources its rules evaluate.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.EvaluationIntervalSeconds < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("evaluation_interval_seconds cannot be negative"))
+	}
+	notifiers := map[string]bool{}
+	for i, notifier := range conf.Notifiers {
+		if err := notifier.Validate(fmt.Sprintf("%s.notifiers.%d", path, i)); err != nil {
+			return nil, err
+		}
+		if notifiers[notifier.Name] {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf("duplicate notifier name %q", notifier.Name))
+		}
+		notifiers[notifier.Name] = true
+	}
+	var deps []string
+	rules := map[string]bool{}
+	for i, rule := range conf.Rules {
+		rulePath := fmt.Sprintf("%s.rules.%d", path, i)
+		if err := rule.Validate(rulePath); err != nil {
+			return nil, err
+		}
+		if rules[rule.Name] {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf("duplicate rule name %q", rule.Name))
+		}
+		rules[rule.Name] = true
+		for _, name := range rule.Notifiers {
+			if !notifiers[name] {
+				return nil, resource.NewConfigValidationError(rulePath, errors.Errorf("unknown notifier %q", name))
+			}
+		}
+		deps = append(deps, rule.Resource)
+	}
+	for i, silence := range conf.Silences {
+		silencePath := fmt.Sprintf("%s.silences.%d", path, i)
+		if _, err := scheduler.ParseSchedule(silence.Schedule); err != nil {
+			return nil, resource.NewConfigValidationError(silencePath, err)
+		}
+		if silence.DurationSeconds <= 0 {
+			return nil, resource.NewConfigValidationError(silencePath, errors.New("duration_seconds must be positive"))
+		}
+		for _, name := range silence.Rules {
+			if !rules[name] {
+				return nil, resource.NewConfigValidationError(silencePath, errors.Errorf("unknown rule %q", name))
+			}
+		}
+	}
+	return deps, nil
+}
+
+func (conf *Config) evaluationInterval() time.Duration {
+	if conf.EvaluationIntervalSeconds == 0 {
+		return defaultEvaluationInterval
+	}
+	return time.Duration(conf.EvaluationIntervalSeconds * float64(time.Second))
+}
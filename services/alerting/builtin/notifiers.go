@@ -0,0 +1,159 @@
+package builtin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/utils/mqtt"
+)
+
+// notifyTimeout bounds sending a notification.
+const notifyTimeout = 30 * time.Second
+
+// The states an alert is notified of.
+const (
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+// Notification is what notifiers are sent about an alert. Webhooks and MQTT brokers are sent it
+// as JSON.
+type Notification struct {
+	Rule     string      `json:"rule"`
+	Severity string      `json:"severity"`
+	State    string      `json:"state"`
+	Message  string      `json:"message"`
+	Resource string      `json:"resource"`
+	Value    interface{} `json:"value,omitempty"`
+	// Since is when the alert started firing, or when it resolved.
+	Since time.Time `json:"since"`
+	// Time is when the notification was sent.
+	Time time.Time `json:"time"`
+}
+
+// subject summarizes the notification in a line.
+func (n Notification) subject() string {
+	return fmt.Sprintf("[%s] %s %s: %s", strings.ToUpper(n.Severity), n.Rule, n.State, n.Message)
+}
+
+type notifier interface {
+	notify(ctx context.Context, n Notification) error
+}
+
+func newNotifier(conf NotifierConfig) notifier {
+	switch conf.Type {
+	case NotifierMQTT:
+		return &mqttNotifier{conf: conf}
+	case NotifierEmail:
+		return &emailNotifier{conf: conf}
+	default:
+		return &webhookNotifier{conf: conf, client: http.DefaultClient}
+	}
+}
+
+type webhookNotifier struct {
+	conf   NotifierConfig
+	client *http.Client
+}
+
+func (w *webhookNotifier) notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.conf.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.conf.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		//nolint:errcheck
+		io.Copy(io.Discard, resp.Body)
+		//nolint:errcheck
+		resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+type mqttNotifier struct {
+	conf NotifierConfig
+}
+
+// notify connects to the broker for each notification, as alerts are rare and a long lived
+// connection would need to be kept alive and reconnected.
+func (m *mqttNotifier) notify(ctx context.Context, n Notification) error {
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	client, err := mqtt.Dial(ctx, m.conf.Broker, mqtt.Options{Username: m.conf.Username, Password: m.conf.Password})
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck
+	defer client.Close()
+	qos := byte(1)
+	if m.conf.QoS != nil {
+		qos = byte(*m.conf.QoS)
+	}
+	return client.Publish(ctx, m.conf.Topic, payload, qos, false)
+}
+
+type emailNotifier struct {
+	conf NotifierConfig
+}
+
+func (e *emailNotifier) notify(ctx context.Context, n Notification) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.conf.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.conf.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", n.subject())
+	fmt.Fprintf(&msg, "Date: %s\r\n", n.Time.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&msg, "%s\r\n\r\n", n.Message)
+	fmt.Fprintf(&msg, "Rule: %s\r\nSeverity: %s\r\nState: %s\r\nResource: %s\r\n", n.Rule, n.Severity, n.State, n.Resource)
+	if n.Value != nil {
+		fmt.Fprintf(&msg, "Value: %v\r\n", n.Value)
+	}
+	fmt.Fprintf(&msg, "Since: %s\r\n", n.Since.Format(time.RFC3339))
+
+	var auth smtp.Auth
+	if e.conf.Username != "" {
+		host, _, err := net.SplitHostPort(e.conf.SMTPServer)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", e.conf.Username, e.conf.Password, host)
+	}
+	// smtp.SendMail cannot be canceled, so it is bounded by ctx from another goroutine
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(e.conf.SMTPServer, auth, e.conf.From, e.conf.To, msg.Bytes())
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,138 @@
+package alerting
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The alerting commands are carried over DoCommand using the following reserved keys.
+const (
+	alertsKey        = "alerts"
+	silenceKey       = "silence"
+	ruleKey          = "rule"
+	durationSecsKey  = "duration_secs"
+	severityKey      = "severity"
+	firingKey        = "firing"
+	sinceKey         = "since"
+	messageKey       = "message"
+	valueKey         = "value"
+	silencedUntilKey = "silenced_until"
+	lastNotifiedKey  = "last_notified"
+)
+
+// client implements Service over the DoCommand of a resource that does not implement it, such as
+// the generic client of a remote alerting service.
+type client struct {
+	resource.Resource
+}
+
+// NewClientFromResource returns a Service calling `res` over DoCommand.
+func NewClientFromResource(res resource.Resource) Service {
+	return &client{Resource: res}
+}
+
+func (c *client) Alerts(ctx context.Context) ([]Alert, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{alertsKey: map[string]interface{}{}})
+	if err != nil {
+		return nil, err
+	}
+	list, ok := resp[alertsKey].([]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected %q in response, got %v", alertsKey, resp)
+	}
+	alerts := make([]Alert, 0, len(list))
+	for _, raw := range list {
+		encoded, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%q entries must be objects", alertsKey)
+		}
+		alerts = append(alerts, alertFromMap(encoded))
+	}
+	return alerts, nil
+}
+
+func (c *client) Silence(ctx context.Context, rule string, duration time.Duration) error {
+	if duration < 0 {
+		return errors.New("silence duration cannot be negative")
+	}
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		silenceKey: map[string]interface{}{ruleKey: rule, durationSecsKey: duration.Seconds()},
+	})
+	return err
+}
+
+// HandleDoCommand handles the reserved alerting DoCommand keys. Models call it first from their
+// DoCommand, and handle `cmd` themselves if it returns false.
+func HandleDoCommand(ctx context.Context, s Service, cmd map[string]interface{}) (map[string]interface{}, bool, error) {
+	if _, ok := cmd[alertsKey]; ok {
+		alerts, err := s.Alerts(ctx)
+		if err != nil {
+			return nil, true, err
+		}
+		encoded := make([]interface{}, 0, len(alerts))
+		for _, alert := range alerts {
+			encoded = append(encoded, alertToMap(alert))
+		}
+		return map[string]interface{}{alertsKey: encoded}, true, nil
+	}
+	if payload, ok := cmd[silenceKey]; ok {
+		args, ok := payload.(map[string]interface{})
+		if !ok {
+			return nil, true, errors.Errorf("%q must be an object", silenceKey)
+		}
+		rule, _ := args[ruleKey].(string) //nolint:errcheck
+		secs, ok := args[durationSecsKey].(float64)
+		if !ok || secs < 0 {
+			return nil, true, errors.Errorf("%q must be a non-negative number", durationSecsKey)
+		}
+		return map[string]interface{}{}, true, s.Silence(ctx, rule, time.Duration(secs*float64(time.Second)))
+	}
+	return nil, false, nil
+}
+
+func alertToMap(alert Alert) map[string]interface{} {
+	encoded := map[string]interface{}{
+		ruleKey:     alert.Rule,
+		severityKey: alert.Severity,
+		firingKey:   alert.Firing,
+		messageKey:  alert.Message,
+	}
+	for key, t := range map[string]time.Time{
+		sinceKey:         alert.Since,
+		silencedUntilKey: alert.SilencedUntil,
+		lastNotifiedKey:  alert.LastNotified,
+	} {
+		if !t.IsZero() {
+			encoded[key] = t.Format(time.RFC3339Nano)
+		}
+	}
+	if alert.Value != nil {
+		encoded[valueKey] = alert.Value
+	}
+	return encoded
+}
+
+func alertFromMap(encoded map[string]interface{}) Alert {
+	alert := Alert{Value: encoded[valueKey]}
+	alert.Rule, _ = encoded[ruleKey].(string)         //nolint:errcheck
+	alert.Severity, _ = encoded[severityKey].(string) //nolint:errcheck
+	alert.Firing, _ = encoded[firingKey].(bool)       //nolint:errcheck
+	alert.Message, _ = encoded[messageKey].(string)   //nolint:errcheck
+	alert.Since = timeFromInterface(encoded[sinceKey])
+	alert.SilencedUntil = timeFromInterface(encoded[silencedUntilKey])
+	alert.LastNotified = timeFromInterface(encoded[lastNotifiedKey])
+	return alert
+}
+
+func timeFromInterface(raw interface{}) time.Time {
+	s, _ := raw.(string) //nolint:errcheck
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
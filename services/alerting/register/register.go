@@ -0,0 +1,7 @@
+// Package register registers all relevant alerting services.
+package register
+
+import (
+	// register alerting services.
+	_ "go.viam.com/rdk/services/alerting/builtin"
+)
@@ -23,16 +23,17 @@ import (
 // Constants for the system including the max speed and angle (TBD: allow to be set as config vars)
 // as well as the various control modes including oneJoystick (control via a joystick), triggerSpeed
 // (triggers control speed and joystick angle), button (four buttons X, Y, A, B to  control speed and
-// angle) and arrow (arrows buttons used to control speed and angle).
+// angle), arrow (arrows buttons used to control speed and angle) and custom (a user-defined mapping).
 const (
 	joyStickControl = controlMode(iota)
 	triggerSpeedControl
 	buttonControl
 	arrowControl
 	droneControl
+	customControl
 )
 
-var modes = []string{"joystickControl", "triggerSpeedControl", "buttonControl", "arrowControl", "droneControl"}
+var modes = []string{"joystickControl", "triggerSpeedControl", "buttonControl", "arrowControl", "droneControl", "customControl"}
 
 func init() {
 	resource.RegisterService(baseremotecontrol.API, resource.DefaultServiceModel, resource.Registration[baseremotecontrol.Service, *Config]{
@@ -50,6 +51,9 @@ type Config struct {
 	ControlModeName     string  `json:"control_mode,omitempty"`
 	MaxAngularVelocity  float64 `json:"max_angular_deg_per_sec,omitempty"`
 	MaxLinearVelocity   float64 `json:"max_linear_mm_per_sec,omitempty"`
+	// Mapping maps the controls of the input controller to the base in the custom control mode, which it
+	// selects if no control mode is set.
+	Mapping *MappingConfig `json:"mapping,omitempty"`
 }
 
 // Validate creates the list of implicit dependencies.
@@ -79,6 +83,18 @@ func (conf *Config) Validate(path string) ([]string, error) {
 		}
 	}
 
+	if conf.Mapping != nil {
+		if conf.ControlModeName != "" && conf.ControlModeName != "customControl" {
+			return nil, resource.NewConfigValidationError(path,
+				errors.Errorf("a mapping requires the customControl mode, not '%s'", conf.ControlModeName))
+		}
+		if err := conf.Mapping.Validate(path + ".mapping"); err != nil {
+			return nil, err
+		}
+	} else if conf.ControlModeName == "customControl" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "mapping")
+	}
+
 	return deps, nil
 }
 
@@ -90,6 +106,7 @@ type builtIn struct {
 	base            base.Base
 	inputController input.Controller
 	controlMode     controlMode
+	mapping         *mapping
 	config          *Config
 
 	state                   throttleState
@@ -144,14 +161,18 @@ func (svc *builtIn) Reconfigure(
 	}
 
 	var controlMode1 controlMode
-	switch svcConfig.ControlModeName {
-	case "triggerSpeedControl":
+	var mapping1 *mapping
+	switch {
+	case svcConfig.Mapping != nil:
+		controlMode1 = customControl
+		mapping1 = newMapping(svcConfig.Mapping)
+	case svcConfig.ControlModeName == "triggerSpeedControl":
 		controlMode1 = triggerSpeedControl
-	case "buttonControl":
+	case svcConfig.ControlModeName == "buttonControl":
 		controlMode1 = buttonControl
-	case "joystickControl":
+	case svcConfig.ControlModeName == "joystickControl":
 		controlMode1 = joyStickControl
-	case "droneControl":
+	case svcConfig.ControlModeName == "droneControl":
 		controlMode1 = droneControl
 	default:
 		controlMode1 = arrowControl
@@ -161,6 +182,7 @@ func (svc *builtIn) Reconfigure(
 	svc.base = base1
 	svc.inputController = controller
 	svc.controlMode = controlMode1
+	svc.mapping = mapping1
 	svc.config = svcConfig
 	svc.mu.Unlock()
 	svc.instance.Add(1)
@@ -240,23 +262,18 @@ func (svc *builtIn) registerCallbacks(ctx context.Context, state *throttleState)
 		if err := func() error {
 			svc.mu.RLock()
 			defer svc.mu.RUnlock()
-			var err error
+			triggers := []input.EventType{input.PositionChangeAbs}
 			if svc.controlMode == buttonControl {
-				err = svc.inputController.RegisterControlCallback(
-					ctx,
-					control,
-					[]input.EventType{input.ButtonChange},
-					remoteCtl,
-					map[string]interface{}{},
-				)
-			} else {
-				err = svc.inputController.RegisterControlCallback(ctx,
-					control,
-					[]input.EventType{input.PositionChangeAbs},
-					remoteCtl,
-					map[string]interface{}{},
-				)
+				triggers = []input.EventType{input.ButtonChange}
+			} else if svc.controlMode == customControl && svc.mapping.buttons[control] != nil {
+				triggers = []input.EventType{input.ButtonPress}
 			}
+			err := svc.inputController.RegisterControlCallback(ctx,
+				control,
+				triggers,
+				remoteCtl,
+				map[string]interface{}{},
+			)
 			if err != nil {
 				return err
 			}
@@ -299,6 +316,9 @@ func (svc *builtIn) ControllerInputs() []input.Control {
 		return []input.Control{input.AbsoluteX, input.AbsoluteY}
 	case droneControl:
 		return []input.Control{input.AbsoluteX, input.AbsoluteY, input.AbsoluteRX, input.AbsoluteRY}
+	case customControl:
+		axes, buttons := svc.mapping.controls()
+		return append(axes, buttons...)
 	}
 	return []input.Control{}
 }
@@ -391,6 +411,7 @@ func (svc *builtIn) processEvent(ctx context.Context, state *throttleState, even
 	oldAngular := state.angularThrottle
 	newLinear := oldLinear
 	newAngular := oldAngular
+	var cmd map[string]interface{}
 
 	svc.mu.RLock()
 	defer svc.mu.RUnlock()
@@ -406,11 +427,19 @@ func (svc *builtIn) processEvent(ctx context.Context, state *throttleState, even
 		newLinear.Y, newAngular.Z, state.buttons = buttonControlEvent(event, state.buttons)
 	case arrowControl:
 		newLinear.Y, newAngular.Z, state.arrows = arrowEvent(event, state.arrows)
+	case customControl:
+		newLinear, newAngular, cmd = svc.mapping.event(event, state)
 	}
 	state.linearThrottle = newLinear
 	state.angularThrottle = newAngular
 	state.mu.Unlock()
 
+	if cmd != nil {
+		if _, err := svc.base.DoCommand(ctx, cmd); err != nil {
+			svc.logger.CErrorw(ctx, "error sending command of button to base", "button", event.Control, "error", err)
+		}
+	}
+
 	if similar(newLinear, oldLinear, .05) && similar(newAngular, oldAngular, .05) {
 		return
 	}
@@ -582,6 +611,9 @@ type throttleState struct {
 	linearThrottle, angularThrottle r3.Vector
 	buttons                         map[input.Control]bool
 	arrows                          map[input.Control]float64
+	// axes holds the positions of the axes and speedScale the speed preset of the custom control mode
+	axes       map[input.Control]float64
+	speedScale float64
 }
 
 func (ts *throttleState) init() {
@@ -596,4 +628,7 @@ func (ts *throttleState) init() {
 		input.AbsoluteHat0X: 0.0,
 		input.AbsoluteHat0Y: 0.0,
 	}
+
+	ts.axes = map[input.Control]float64{}
+	ts.speedScale = 1
 }
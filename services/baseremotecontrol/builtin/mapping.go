@@ -0,0 +1,189 @@
+package builtin
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/input"
+	"go.viam.com/rdk/resource"
+)
+
+// The throttles an axis of a custom mapping can drive.
+var axisTargets = []string{"linear_x", "linear_y", "linear_z", "angular_x", "angular_y", "angular_z"}
+
+// MappingConfig is a user-defined mapping of the controls of the input controller to the base, used instead of
+// one of the built-in control modes.
+type MappingConfig struct {
+	Axes    []*AxisMappingConfig   `json:"axes,omitempty"`
+	Buttons []*ButtonMappingConfig `json:"buttons,omitempty"`
+}
+
+// AxisMappingConfig maps an axis, such as a joystick or trigger, to a linear or angular throttle of the base.
+type AxisMappingConfig struct {
+	Control string `json:"control"`
+	// Target is the throttle driven by the axis, one of linear_x, linear_y, linear_z, angular_x, angular_y
+	// and angular_z. The throttles of axes with the same target add up.
+	Target string `json:"target"`
+	Invert bool   `json:"invert,omitempty"`
+	// Deadzone is how far from its center, between 0 and 1, the axis is ignored.
+	Deadzone float64 `json:"deadzone,omitempty"`
+	// Expo, between 0 and 1, blends the response of the axis from linear to cubic, for finer control near
+	// the center.
+	Expo float64 `json:"expo,omitempty"`
+}
+
+// ButtonMappingConfig maps a button to either a speed preset or a DoCommand sent to the base when pressed.
+type ButtonMappingConfig struct {
+	Control string `json:"control"`
+	// SpeedPreset scales the throttles of all axes once the button is pressed, such as 0.5 for half speed.
+	SpeedPreset float64                `json:"speed_preset,omitempty"`
+	DoCommand   map[string]interface{} `json:"do_command,omitempty"`
+}
+
+// Validate ensures all parts of the mapping are valid.
+func (conf *MappingConfig) Validate(path string) error {
+	if len(conf.Axes) == 0 && len(conf.Buttons) == 0 {
+		return resource.NewConfigValidationError(path, errors.New("a mapping must map at least one axis or button"))
+	}
+	for i, axis := range conf.Axes {
+		axisPath := fmt.Sprintf("%s.axes.%d", path, i)
+		if axis.Control == "" {
+			return resource.NewConfigValidationFieldRequiredError(axisPath, "control")
+		}
+		if axisTarget(axis.Target) < 0 {
+			return resource.NewConfigValidationError(axisPath, errors.Errorf("target '%s' is not in %v", axis.Target, axisTargets))
+		}
+		if axis.Deadzone < 0 || axis.Deadzone >= 1 {
+			return resource.NewConfigValidationError(axisPath, errors.New("deadzone must be at least 0 and less than 1"))
+		}
+		if axis.Expo < 0 || axis.Expo > 1 {
+			return resource.NewConfigValidationError(axisPath, errors.New("expo must be between 0 and 1"))
+		}
+	}
+	buttons := map[string]bool{}
+	for i, button := range conf.Buttons {
+		buttonPath := fmt.Sprintf("%s.buttons.%d", path, i)
+		if button.Control == "" {
+			return resource.NewConfigValidationFieldRequiredError(buttonPath, "control")
+		}
+		if buttons[button.Control] {
+			return resource.NewConfigValidationError(buttonPath, errors.Errorf("button '%s' is mapped more than once", button.Control))
+		}
+		buttons[button.Control] = true
+		if (button.SpeedPreset != 0) == (button.DoCommand != nil) {
+			return resource.NewConfigValidationError(buttonPath, errors.New("a button must map to exactly one of speed_preset and do_command"))
+		}
+		if button.SpeedPreset < 0 || button.SpeedPreset > 1 {
+			return resource.NewConfigValidationError(buttonPath, errors.New("speed_preset must be between 0 and 1"))
+		}
+	}
+	return nil
+}
+
+// axisTarget returns the index of target in axisTargets, or -1 if it is not a target.
+func axisTarget(target string) int {
+	for i, t := range axisTargets {
+		if t == target {
+			return i
+		}
+	}
+	return -1
+}
+
+type axisMapping struct {
+	control  input.Control
+	target   int
+	invert   bool
+	deadzone float64
+	expo     float64
+}
+
+// mapping is a custom mapping, with the throttles of the axes in the order of axisTargets.
+type mapping struct {
+	axes    []axisMapping
+	buttons map[input.Control]*ButtonMappingConfig
+}
+
+func newMapping(conf *MappingConfig) *mapping {
+	m := &mapping{buttons: map[input.Control]*ButtonMappingConfig{}}
+	for _, axis := range conf.Axes {
+		m.axes = append(m.axes, axisMapping{
+			control:  input.Control(axis.Control),
+			target:   axisTarget(axis.Target),
+			invert:   axis.Invert,
+			deadzone: axis.Deadzone,
+			expo:     axis.Expo,
+		})
+	}
+	for _, button := range conf.Buttons {
+		m.buttons[input.Control(button.Control)] = button
+	}
+	return m
+}
+
+// controls returns the axes and buttons of the mapping.
+func (m *mapping) controls() ([]input.Control, []input.Control) {
+	var axes, buttons []input.Control
+	seen := map[input.Control]bool{}
+	for _, axis := range m.axes {
+		if !seen[axis.control] {
+			seen[axis.control] = true
+			axes = append(axes, axis.control)
+		}
+	}
+	for control := range m.buttons {
+		buttons = append(buttons, control)
+	}
+	return axes, buttons
+}
+
+// event updates the positions of the axes and the speed preset with the event, and returns the new throttles
+// and the DoCommand of the button pressed, if any.
+func (m *mapping) event(event input.Event, state *throttleState) (r3.Vector, r3.Vector, map[string]interface{}) {
+	var cmd map[string]interface{}
+	switch event.Event {
+	case input.PositionChangeAbs:
+		state.axes[event.Control] = event.Value
+	case input.ButtonPress:
+		if button, ok := m.buttons[event.Control]; ok {
+			if button.SpeedPreset != 0 {
+				state.speedScale = button.SpeedPreset
+			}
+			cmd = button.DoCommand
+		}
+	case input.AllEvents, input.ButtonChange, input.ButtonHold, input.ButtonRelease, input.Connect, input.Disconnect,
+		input.PositionChangeRel:
+		fallthrough
+	default:
+	}
+
+	var throttles [6]float64
+	for _, axis := range m.axes {
+		value := shapeAxis(state.axes[axis.control], axis.deadzone, axis.expo)
+		if axis.invert {
+			value = -value
+		}
+		throttles[axis.target] += value
+	}
+	for i, throttle := range throttles {
+		throttles[i] = state.speedScale * math.Max(-1, math.Min(1, throttle))
+	}
+	return r3.Vector{X: throttles[0], Y: throttles[1], Z: throttles[2]},
+		r3.Vector{X: throttles[3], Y: throttles[4], Z: throttles[5]},
+		cmd
+}
+
+// shapeAxis applies the deadzone and expo curve of an axis to its position. The response is rescaled to
+// start from 0 at the edge of the deadzone.
+func shapeAxis(value, deadzone, expo float64) float64 {
+	magnitude := math.Abs(value)
+	if magnitude <= deadzone {
+		return 0
+	}
+	magnitude = math.Min(1, (magnitude-deadzone)/(1-deadzone))
+	magnitude = (1-expo)*magnitude + expo*magnitude*magnitude*magnitude
+	return math.Copysign(magnitude, value)
+}
@@ -0,0 +1,167 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/input"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/baseremotecontrol"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func testMappingConfig() *MappingConfig {
+	return &MappingConfig{
+		Axes: []*AxisMappingConfig{
+			{Control: "AbsoluteY", Target: "linear_y", Invert: true, Deadzone: 0.1},
+			{Control: "AbsoluteRX", Target: "angular_z", Invert: true, Expo: 1},
+			{Control: "AbsoluteX", Target: "angular_z", Invert: true},
+		},
+		Buttons: []*ButtonMappingConfig{
+			{Control: "ButtonLT", SpeedPreset: 0.5},
+			{Control: "ButtonRT", SpeedPreset: 1},
+			{Control: "ButtonSouth", DoCommand: map[string]interface{}{"horn": true}},
+		},
+	}
+}
+
+func TestMappingValidate(t *testing.T) {
+	cfg := &Config{BaseName: "base", InputControllerName: "gamepad", Mapping: testMappingConfig()}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	cfg.ControlModeName = "customControl"
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	cfg.ControlModeName = "arrowControl"
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "requires the customControl mode")
+	cfg.ControlModeName = "customControl"
+	cfg.Mapping = nil
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `Field: "mapping"`)
+
+	cases := []struct {
+		mapping *MappingConfig
+		err     string
+	}{
+		{&MappingConfig{}, "at least one axis or button"},
+		{&MappingConfig{Axes: []*AxisMappingConfig{{Target: "linear_y"}}}, `Field: "control"`},
+		{&MappingConfig{Axes: []*AxisMappingConfig{{Control: "AbsoluteY", Target: "up"}}}, "target 'up' is not in"},
+		{&MappingConfig{Axes: []*AxisMappingConfig{{Control: "AbsoluteY", Target: "linear_y", Deadzone: 1}}}, "deadzone"},
+		{&MappingConfig{Axes: []*AxisMappingConfig{{Control: "AbsoluteY", Target: "linear_y", Expo: 2}}}, "expo"},
+		{&MappingConfig{Buttons: []*ButtonMappingConfig{{Control: "ButtonLT"}}}, "exactly one of"},
+		{&MappingConfig{Buttons: []*ButtonMappingConfig{{Control: "ButtonLT", SpeedPreset: 2}}}, "speed_preset"},
+		{
+			&MappingConfig{Buttons: []*ButtonMappingConfig{{Control: "ButtonLT", SpeedPreset: 1}, {Control: "ButtonLT", SpeedPreset: 0.5}}},
+			"mapped more than once",
+		},
+	}
+	for _, tc := range cases {
+		cfg.Mapping = tc.mapping
+		_, err := cfg.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+	}
+}
+
+func TestShapeAxis(t *testing.T) {
+	test.That(t, shapeAxis(0.5, 0, 0), test.ShouldAlmostEqual, 0.5)
+	test.That(t, shapeAxis(-0.05, 0.1, 0), test.ShouldEqual, 0)
+	// the response starts from 0 at the edge of the deadzone
+	test.That(t, shapeAxis(0.55, 0.1, 0), test.ShouldAlmostEqual, 0.5)
+	test.That(t, shapeAxis(-1, 0.1, 0), test.ShouldAlmostEqual, -1)
+	test.That(t, shapeAxis(0.5, 0, 1), test.ShouldAlmostEqual, 0.125)
+	test.That(t, shapeAxis(-0.5, 0, 0.5), test.ShouldAlmostEqual, -0.3125)
+	test.That(t, shapeAxis(1, 0, 0.5), test.ShouldAlmostEqual, 1)
+}
+
+func TestMappingEvents(t *testing.T) {
+	m := newMapping(testMappingConfig())
+	axes, buttons := m.controls()
+	test.That(t, axes, test.ShouldResemble, []input.Control{input.AbsoluteY, input.AbsoluteRX, input.AbsoluteX})
+	test.That(t, buttons, test.ShouldHaveLength, 3)
+
+	var state throttleState
+	state.init()
+	linear, angular, cmd := m.event(input.Event{Event: input.PositionChangeAbs, Control: input.AbsoluteY, Value: -0.55}, &state)
+	test.That(t, linear.Y, test.ShouldAlmostEqual, 0.5)
+	test.That(t, angular, test.ShouldResemble, r3.Vector{})
+	test.That(t, cmd, test.ShouldBeNil)
+
+	// axes with the same target add up, and are clamped
+	_, angular, _ = m.event(input.Event{Event: input.PositionChangeAbs, Control: input.AbsoluteRX, Value: 0.5}, &state)
+	test.That(t, angular.Z, test.ShouldAlmostEqual, -0.125)
+	_, angular, _ = m.event(input.Event{Event: input.PositionChangeAbs, Control: input.AbsoluteX, Value: 1}, &state)
+	test.That(t, angular.Z, test.ShouldAlmostEqual, -1)
+
+	// speed presets scale all throttles
+	linear, angular, cmd = m.event(input.Event{Event: input.ButtonPress, Control: input.ButtonLT}, &state)
+	test.That(t, linear.Y, test.ShouldAlmostEqual, 0.25)
+	test.That(t, angular.Z, test.ShouldAlmostEqual, -0.5)
+	test.That(t, cmd, test.ShouldBeNil)
+	linear, _, _ = m.event(input.Event{Event: input.ButtonPress, Control: input.ButtonRT}, &state)
+	test.That(t, linear.Y, test.ShouldAlmostEqual, 0.5)
+
+	linear, _, cmd = m.event(input.Event{Event: input.ButtonPress, Control: input.ButtonSouth}, &state)
+	test.That(t, linear.Y, test.ShouldAlmostEqual, 0.5)
+	test.That(t, cmd, test.ShouldResemble, map[string]interface{}{"horn": true})
+}
+
+func TestCustomControlMode(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	triggers := map[input.Control][]input.EventType{}
+	controller := &inject.InputController{}
+	controller.RegisterControlCallbackFunc = func(
+		ctx context.Context,
+		control input.Control,
+		eventTypes []input.EventType,
+		ctrlFunc input.ControlFunction,
+		extra map[string]interface{},
+	) error {
+		triggers[control] = append(triggers[control], eventTypes...)
+		return nil
+	}
+	commands := make(chan map[string]interface{}, 1)
+	injectBase := inject.NewBase("base")
+	injectBase.DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		commands <- cmd
+		return nil, nil
+	}
+	injectBase.SetPowerFunc = func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+		return nil
+	}
+
+	tmpSvc, err := NewBuiltIn(ctx, resource.Dependencies{
+		input.Named("gamepad"): controller,
+		base.Named("base"):     injectBase,
+	}, resource.Config{
+		Name:                "base_remote_control",
+		API:                 baseremotecontrol.API,
+		ConvertedAttributes: &Config{BaseName: "base", InputControllerName: "gamepad", Mapping: testMappingConfig()},
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer tmpSvc.Close(ctx)
+	svc, ok := tmpSvc.(*builtIn)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	test.That(t, svc.ControllerInputs(), test.ShouldHaveLength, 6)
+	test.That(t, triggers[input.AbsoluteY], test.ShouldContain, input.PositionChangeAbs)
+	test.That(t, triggers[input.ButtonSouth], test.ShouldContain, input.ButtonPress)
+	test.That(t, triggers[input.ButtonSouth], test.ShouldNotContain, input.PositionChangeAbs)
+
+	svc.processEvent(ctx, &svc.state, input.Event{Event: input.ButtonPress, Control: input.ButtonSouth})
+	test.That(t, <-commands, test.ShouldResemble, map[string]interface{}{"horn": true})
+	svc.processEvent(ctx, &svc.state, input.Event{Event: input.PositionChangeAbs, Control: input.AbsoluteY, Value: -1})
+	svc.state.mu.Lock()
+	test.That(t, svc.state.linearThrottle.Y, test.ShouldAlmostEqual, 1)
+	svc.state.mu.Unlock()
+}
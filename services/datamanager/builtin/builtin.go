@@ -17,7 +17,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/benbjohnson/clock"
 	v1 "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/utils/rpc"
 	"google.golang.org/grpc"
@@ -39,10 +38,6 @@ var (
 	// `-untrusted-env` and the capture directory is not `~/.viam/capture`.
 	ErrCaptureDirectoryConfigurationDisabled = errors.New("changing the capture directory is prohibited in this environment")
 	viamCaptureDotDir                        = filepath.Join(os.Getenv("HOME"), ".viam", "capture")
-	// This clock only exists for tests.
-	// At time of writing only a single test depends on it.
-	// We should endevor to not add more tests that depend on it unless absolutiely necessary.
-	clk = clock.New()
 	// diskSummaryLogInterval is the frequency a summary of the sync paths are logged.
 	diskSummaryLogInterval = time.Minute
 )
@@ -100,6 +95,7 @@ func New(
 ) (datamanager.Service, error) {
 	logger.Info("New START")
 	defer logger.Info("New END")
+	clk := utils.ClockFromContext(ctx)
 	capture := capture.New(
 		clk,
 		logger.Sublogger("capture"),
@@ -154,6 +150,15 @@ func (b *builtIn) Sync(ctx context.Context, extra map[string]interface{}) error
 	return b.sync.Sync(ctx, extra)
 }
 
+// StartCaptureSession starts or extends the configured capture session with the given name.
+func (b *builtIn) StartCaptureSession(
+	ctx context.Context, name string, duration time.Duration, extra map[string]interface{},
+) (time.Time, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.capture.StartCaptureSession(name, duration)
+}
+
 // Reconfigure updates the data manager service when the config has changed.
 // At time of writing Reconfigure only returns an error in one of the following unrecoverable error cases:
 //  1. There is some static (aka compile time) error which we currently are only able to detected at runtime:
@@ -192,6 +197,7 @@ func (b *builtIn) Reconfigure(ctx context.Context, deps resource.Dependencies, c
 	}
 
 	captureConfig := c.captureConfig(b.logger)
+	captureConfig.Dependencies = deps
 	collectorConfigsByResource, err := lookupCollectorConfigsByResource(deps, conf, captureConfig.CaptureDir, b.logger)
 	if err != nil {
 		// If this error occurs it's a resource graph error
@@ -256,16 +256,12 @@ func TestFileDeletion(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 	mockClock := clock.NewMock()
 
-	prevClock := clk
-	clk = mockClock
-
 	tempDir := t.TempDir()
-	ctx := context.Background()
+	ctx := utils.WithClock(context.Background(), mockClock)
 
 	fsThresholdToTriggerDeletion := datasync.FSThresholdToTriggerDeletion
 	captureDirToFSUsageRatio := datasync.CaptureDirToFSUsageRatio
 	t.Cleanup(func() {
-		clk = prevClock
 		datasync.FSThresholdToTriggerDeletion = fsThresholdToTriggerDeletion
 		datasync.CaptureDirToFSUsageRatio = captureDirToFSUsageRatio
 	})
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 
 	"github.com/benbjohnson/clock"
@@ -64,6 +65,17 @@ type Capture struct {
 	maxCaptureFileSize int64
 	mongoMU            sync.Mutex
 	mongo              captureMongo
+	mqtt               captureMQTT
+
+	sessionsMu      sync.Mutex
+	sessionConfigs  map[string]datamanager.CaptureSessionConfig
+	runningSessions map[string]*runningSession
+	// sessionCaptureConfig is the config capture sessions capture with
+	sessionCaptureConfig Config
+	// clipRecorders are the video clip recorders of each capture session
+	clipRecorders map[string][]*clipRecorder
+	// sessionWorkers run the capture session triggers and video clip recorders
+	sessionWorkers *goutils.StoppableWorkers
 }
 
 type captureMongo struct {
@@ -86,6 +98,8 @@ type collectorAndConfig struct {
 	Resource  resource.Resource
 	Collector data.Collector
 	Config    datamanager.DataCaptureConfig
+	// ConditionResources are the resources the capture conditions of the config use.
+	ConditionResources []resource.Resource
 }
 
 // Identifier for a particular collector: component name, component model, component type,
@@ -96,9 +110,11 @@ func New(
 	logger logging.Logger,
 ) *Capture {
 	return &Capture{
-		clk:        clock,
-		logger:     logger,
-		collectors: collectors{},
+		clk:             clock,
+		logger:          logger,
+		collectors:      collectors{},
+		runningSessions: map[string]*runningSession{},
+		sessionWorkers:  goutils.NewBackgroundStoppableWorkers(),
 	}
 }
 
@@ -227,6 +243,7 @@ func (c *Capture) Reconfigure(
 	}
 
 	collection := c.mongoReconfigure(ctx, config.MongoConfig)
+	c.mqtt.reconfigure(config.MQTTConfig, c.logger)
 	newCollectors := c.newCollectors(collectorConfigsByResource, config, collection)
 	// If a component/method has been removed from the config, close the collector.
 	c.collectorsMu.Lock()
@@ -240,12 +257,15 @@ func (c *Capture) Reconfigure(
 	c.collectorsMu.Unlock()
 	c.captureDir = config.CaptureDir
 	c.maxCaptureFileSize = config.MaximumCaptureFileSizeBytes
+	c.reconfigureSessions(config)
 }
 
 // Close closes the capture manager.
 func (c *Capture) Close(ctx context.Context) {
+	c.closeSessions()
 	c.FlushCollectors()
 	c.closeCollectors()
+	c.mqtt.close()
 	c.mongoMU.Lock()
 	defer c.mongoMU.Unlock()
 	if c.mongo.client != nil {
@@ -276,8 +296,7 @@ func (c *Capture) initializeOrUpdateCollector(
 	config Config,
 	collection *mongo.Collection,
 ) (*collectorAndConfig, error) {
-	// TODO(DATA-451): validate method params
-	methodParams, err := protoutils.ConvertStringMapToAnyPBMap(collectorConfig.AdditionalParams)
+	filter, conditionResources, err := newCaptureFilter(collectorConfig.Conditions, config.Dependencies)
 	if err != nil {
 		return nil, err
 	}
@@ -286,6 +305,7 @@ func (c *Capture) initializeOrUpdateCollector(
 	if storedCollectorAndConfig, ok := c.collectors[md]; ok {
 		if storedCollectorAndConfig.Config.Equals(&collectorConfig) &&
 			res == storedCollectorAndConfig.Resource &&
+			slices.Equal(conditionResources, storedCollectorAndConfig.ConditionResources) &&
 			!maxFileSizeChanged {
 			// If the attributes have not changed, do nothing and leave the existing collector.
 			return c.collectors[md], nil
@@ -295,6 +315,29 @@ func (c *Capture) initializeOrUpdateCollector(
 		storedCollectorAndConfig.Collector.Close()
 	}
 
+	collector, err := c.newCollector(res, md, collectorConfig, config, collection, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &collectorAndConfig{res, collector, collectorConfig, conditionResources}, nil
+}
+
+// newCollector creates a collector for the resource/method of collectorConfig and starts collecting.
+func (c *Capture) newCollector(
+	res resource.Resource,
+	md collectorMetadata,
+	collectorConfig datamanager.DataCaptureConfig,
+	config Config,
+	collection *mongo.Collection,
+	filter data.CaptureFilter,
+) (data.Collector, error) {
+	// TODO(DATA-451): validate method params
+	methodParams, err := protoutils.ConvertStringMapToAnyPBMap(collectorConfig.AdditionalParams)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get collector constructor for the component API and method.
 	collectorConstructor := data.CollectorLookup(md.MethodMetadata)
 	if collectorConstructor == nil {
@@ -332,18 +375,35 @@ func (c *Capture) initializeOrUpdateCollector(
 		methodParams,
 		collectorConfig.Tags,
 	)
+	var target data.CaptureBufferedWriter = data.NewCaptureBuffer(targetDir, captureMetadata, config.MaximumCaptureFileSizeBytes)
+	if config.TabularFileFormat == data.CaptureFileFormatParquet && dataType == data.CaptureTypeTabular {
+		target = data.NewParquetCaptureBuffer(targetDir, captureMetadata, config.MaximumCaptureFileSizeBytes)
+	}
+	if aggregation := collectorConfig.Aggregation; aggregation != nil {
+		if dataType != data.CaptureTypeTabular {
+			return nil, errors.Errorf("aggregation is only supported for tabular data, %s captures binary data", md)
+		}
+		if err := aggregation.Validate(); err != nil {
+			return nil, err
+		}
+		if target, err = data.NewAggregatingCaptureBuffer(target, aggregation.Interval(), aggregation.Functions); err != nil {
+			return nil, err
+		}
+	}
 	// Parameters to initialize collector.
 	queueSize := defaultIfZeroVal(collectorConfig.CaptureQueueSize, defaultCaptureQueueSize)
 	bufferSize := defaultIfZeroVal(collectorConfig.CaptureBufferSize, defaultCaptureBufferSize)
 	collector, err := collectorConstructor(res, data.CollectorParams{
 		MongoCollection: collection,
+		Publisher:       c.mqtt.collectorPublisher(md, collectorConfig.Tags),
 		DataType:        dataType,
+		Filter:          filter,
 		ComponentName:   collectorConfig.Name.ShortName(),
 		ComponentType:   collectorConfig.Name.API.String(),
 		MethodName:      collectorConfig.Method,
 		Interval:        data.GetDurationFromHz(collectorConfig.CaptureFrequencyHz),
 		MethodParams:    methodParams,
-		Target:          data.NewCaptureBuffer(targetDir, captureMetadata, config.MaximumCaptureFileSizeBytes),
+		Target:          target,
 		// Set queue size to defaultCaptureQueueSize if it was not set in the config.
 		QueueSize:  queueSize,
 		BufferSize: bufferSize,
@@ -358,8 +418,7 @@ func (c *Capture) initializeOrUpdateCollector(
 	c.logger.Infof("collector initialized; collector: %s, config: %s",
 		md, collectorConfigDescription(collectorConfig, targetDir, config.MaximumCaptureFileSizeBytes, queueSize, bufferSize))
 	collector.Collect()
-
-	return &collectorAndConfig{res, collector, collectorConfig}, nil
+	return collector, nil
 }
 
 func collectorConfigDescription(
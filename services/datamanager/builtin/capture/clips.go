@@ -0,0 +1,181 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/utils"
+)
+
+// videoClipMethod is the directory under the camera's capture directory which video clips are
+// written to, in place of the capture method.
+const videoClipMethod = "VideoClip"
+
+type clipFrame struct {
+	at   time.Time
+	jpeg []byte
+}
+
+// clipRecorder continuously buffers the frames of a camera for the pre-roll of a capture
+// session's video clip, and records them until the session ends once it starts.
+type clipRecorder struct {
+	session    string
+	config     datamanager.VideoClipConfig
+	cam        camera.Camera
+	captureDir string
+	clk        clock.Clock
+	logger     logging.Logger
+
+	mu     sync.Mutex
+	frames []clipFrame
+	// recordFrom is when the clip being recorded starts, and recordUntil when it ends. Both are
+	// zero while no clip is being recorded.
+	recordFrom  time.Time
+	recordUntil time.Time
+}
+
+// record records a clip from the pre-roll before now until until, or extends the clip being
+// recorded to until.
+func (r *clipRecorder) record(until time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.recordUntil.IsZero() {
+		r.recordFrom = r.clk.Now().Add(-r.config.PreRoll())
+	}
+	if until.After(r.recordUntil) {
+		r.recordUntil = until
+	}
+}
+
+// run captures frames until ctx is done, which writes the clip being recorded so far.
+func (r *clipRecorder) run(ctx context.Context) {
+	t := r.clk.Ticker(r.config.FrameInterval())
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			r.mu.Lock()
+			clip := r.takeClip()
+			r.mu.Unlock()
+			r.writeClip(clip)
+			return
+		case <-t.C:
+		}
+
+		frame, err := r.captureFrame(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.logger.Debugw("failed to capture video clip frame", "camera", r.config.Camera, "error", err)
+			}
+			continue
+		}
+
+		r.mu.Lock()
+		r.frames = append(r.frames, frame)
+		var clip []clipFrame
+		if !r.recordUntil.IsZero() && !frame.at.Before(r.recordUntil) {
+			clip = r.takeClip()
+		} else if r.recordUntil.IsZero() {
+			r.trimFrames(frame.at.Add(-r.config.PreRoll()))
+		}
+		r.mu.Unlock()
+		r.writeClip(clip)
+	}
+}
+
+// takeClip returns the frames of the clip being recorded and stops recording. It must be called
+// with mu held.
+func (r *clipRecorder) takeClip() []clipFrame {
+	if r.recordUntil.IsZero() {
+		return nil
+	}
+	r.trimFrames(r.recordFrom)
+	clip := r.frames
+	r.frames = nil
+	r.recordFrom, r.recordUntil = time.Time{}, time.Time{}
+	return clip
+}
+
+// trimFrames drops the frames captured before from. It must be called with mu held.
+func (r *clipRecorder) trimFrames(from time.Time) {
+	i := 0
+	for i < len(r.frames) && r.frames[i].at.Before(from) {
+		i++
+	}
+	r.frames = r.frames[i:]
+}
+
+func (r *clipRecorder) captureFrame(ctx context.Context) (clipFrame, error) {
+	img, md, err := r.cam.Image(ctx, utils.MimeTypeJPEG, nil)
+	if err != nil {
+		return clipFrame{}, err
+	}
+	at := r.clk.Now()
+	if mimeType, _ := utils.CheckLazyMIMEType(md.MimeType); mimeType != utils.MimeTypeJPEG {
+		decoded, err := rimage.DecodeImage(ctx, img, md.MimeType)
+		if err != nil {
+			return clipFrame{}, err
+		}
+		if img, err = rimage.EncodeImage(ctx, decoded, utils.MimeTypeJPEG); err != nil {
+			return clipFrame{}, err
+		}
+	}
+	return clipFrame{at: at, jpeg: img}, nil
+}
+
+// writeClip writes the frames as an AVI video to the camera's video clip directory. The file is
+// written as in progress and renamed when complete, so that it isn't synced while being written.
+func (r *clipRecorder) writeClip(frames []clipFrame) {
+	if len(frames) == 0 {
+		return
+	}
+	if err := r.writeClipFile(frames); err != nil {
+		r.logger.Errorw("failed to write video clip", "session", r.session, "camera", r.config.Camera, "error", err)
+	}
+}
+
+func (r *clipRecorder) writeClipFile(frames []clipFrame) error {
+	jpegs := make([][]byte, 0, len(frames))
+	for _, frame := range frames {
+		jpegs = append(jpegs, frame.jpeg)
+	}
+	// use the rate the frames were actually captured at, which may be lower than configured
+	frameRate := float64(time.Second) / float64(r.config.FrameInterval())
+	if elapsed := frames[len(frames)-1].at.Sub(frames[0].at); len(frames) > 1 && elapsed > 0 {
+		frameRate = float64(len(frames)-1) / elapsed.Seconds()
+	}
+	var buf bytes.Buffer
+	if err := rimage.WriteMJPEGAVI(&buf, jpegs, frameRate); err != nil {
+		return err
+	}
+
+	dir := data.CaptureFilePathWithReplacedReservedChars(
+		filepath.Join(r.captureDir, camera.API.String(), r.config.Camera, videoClipMethod))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s_%s", r.session, frames[0].at.UTC().Format("2006-01-02T15_04_05.000Z"))
+	path := data.CaptureFilePathWithReplacedReservedChars(filepath.Join(dir, name))
+	if err := os.WriteFile(path+data.InProgressCaptureFileExt, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(path+data.InProgressCaptureFileExt, path+".avi"); err != nil {
+		return errors.Wrap(err, "failed to complete video clip")
+	}
+	r.logger.Infof("wrote %s video clip of %s for capture session %q to %s",
+		frames[len(frames)-1].at.Sub(frames[0].at).Round(time.Millisecond), r.config.Camera, r.session, path+".avi")
+	return nil
+}
@@ -0,0 +1,121 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+)
+
+func TestCaptureSessionVideoClip(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	var pngFrame bytes.Buffer
+	test.That(t, png.Encode(&pngFrame, image.NewGray(image.Rect(0, 0, 8, 4))), test.ShouldBeNil)
+	var images atomic.Int64
+	cam := inject.NewCamera("front")
+	cam.ImageFunc = func(ctx context.Context, mimeType string, extra map[string]interface{}) ([]byte, camera.ImageMetadata, error) {
+		images.Add(1)
+		// the camera doesn't support JPEG, so frames are re-encoded
+		return pngFrame.Bytes(), camera.ImageMetadata{MimeType: utils.MimeTypePNG}, nil
+	}
+
+	captureDir := t.TempDir()
+	c := New(clock.New(), logger)
+	defer c.Close(ctx)
+	c.Reconfigure(ctx, CollectorConfigsByResource{}, Config{
+		CaptureDir:                  captureDir,
+		MaximumCaptureFileSizeBytes: 1024,
+		Dependencies:                resource.Dependencies{camera.Named("front"): cam},
+		Sessions: []datamanager.CaptureSessionConfig{{
+			Name:            "bump",
+			DurationSeconds: 0.2,
+			VideoClips: []datamanager.VideoClipConfig{
+				{Camera: "front", FrameRateHz: 50, PreRollSeconds: 0.2},
+			},
+		}},
+	})
+	clipDir := data.CaptureFilePathWithReplacedReservedChars(filepath.Join(captureDir, camera.API.String(), "front", videoClipMethod))
+	clips := func() []string {
+		paths, err := filepath.Glob(filepath.Join(clipDir, "bump_*.avi"))
+		test.That(t, err, test.ShouldBeNil)
+		return paths
+	}
+
+	// the camera is buffered before the session starts, but no clip is written
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, images.Load(), test.ShouldBeGreaterThan, 15)
+	})
+	test.That(t, clips(), test.ShouldBeEmpty)
+
+	_, err := c.StartCaptureSession("bump", 0)
+	test.That(t, err, test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, clips(), test.ShouldHaveLength, 1)
+	})
+
+	avi, err := os.ReadFile(clips()[0])
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(avi[:4]), test.ShouldEqual, "RIFF")
+	// the clip spans the pre-roll and the session, about 20 frames at 50Hz
+	avih := bytes.Index(avi, []byte("avih")) + 8
+	frames := binary.LittleEndian.Uint32(avi[avih+16:])
+	test.That(t, frames, test.ShouldBeBetweenOrEqual, 12, 24)
+	test.That(t, binary.LittleEndian.Uint32(avi[avih+32:]), test.ShouldEqual, 8)
+
+	progFiles, err := filepath.Glob(filepath.Join(clipDir, "*.prog"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, progFiles, test.ShouldBeEmpty)
+}
+
+func TestClipRecorderWritesClipOnClose(t *testing.T) {
+	var pngFrame bytes.Buffer
+	test.That(t, png.Encode(&pngFrame, image.NewGray(image.Rect(0, 0, 4, 4))), test.ShouldBeNil)
+	cam := inject.NewCamera("front")
+	cam.ImageFunc = func(ctx context.Context, mimeType string, extra map[string]interface{}) ([]byte, camera.ImageMetadata, error) {
+		return pngFrame.Bytes(), camera.ImageMetadata{MimeType: utils.MimeTypePNG}, nil
+	}
+	captureDir := t.TempDir()
+	r := &clipRecorder{
+		session:    "bump",
+		config:     datamanager.VideoClipConfig{Camera: "front", FrameRateHz: 50},
+		cam:        cam,
+		captureDir: captureDir,
+		clk:        clock.New(),
+		logger:     logging.NewTestLogger(t),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.run(ctx)
+		close(done)
+	}()
+	// the clip being recorded when the recorder stops is written
+	r.record(time.Now().Add(time.Hour))
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	<-done
+	clipDir := data.CaptureFilePathWithReplacedReservedChars(filepath.Join(captureDir, camera.API.String(), "front", videoClipMethod))
+	paths, err := filepath.Glob(filepath.Join(clipDir, "bump_*.avi"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, paths, test.ShouldHaveLength, 1)
+}
@@ -0,0 +1,156 @@
+package capture
+
+import (
+	"context"
+	"image"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// newCaptureFilter returns a filter that only keeps captures for which all of the conditions hold,
+// along with the vision services the conditions use. It returns a nil filter if there are no
+// conditions.
+func newCaptureFilter(
+	conditions []datamanager.CaptureCondition,
+	deps resource.Dependencies,
+) (data.CaptureFilter, []resource.Resource, error) {
+	if len(conditions) == 0 {
+		return nil, nil, nil
+	}
+	checks := make([]data.CaptureFilter, 0, len(conditions))
+	var used []resource.Resource
+	for _, cond := range conditions {
+		if err := cond.Validate(); err != nil {
+			return nil, nil, err
+		}
+		if cond.Field != "" {
+			checks = append(checks, fieldCondition(cond))
+			continue
+		}
+		svc, err := vision.FromDependencies(deps, cond.VisionService)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "capture condition vision_service %q not found", cond.VisionService)
+		}
+		used = append(used, svc)
+		checks = append(checks, visionCondition(cond, svc))
+	}
+	return func(ctx context.Context, result data.CaptureResult) (bool, error) {
+		for _, check := range checks {
+			keep, err := check(ctx, result)
+			if err != nil || !keep {
+				return false, err
+			}
+		}
+		return true, nil
+	}, used, nil
+}
+
+func fieldCondition(cond datamanager.CaptureCondition) data.CaptureFilter {
+	path := strings.Split(cond.Field, ".")
+	return func(ctx context.Context, result data.CaptureResult) (bool, error) {
+		if result.Type != data.CaptureTypeTabular {
+			return false, errors.Errorf("capture condition on %q only applies to tabular data", cond.Field)
+		}
+		var value interface{} = result.TabularData.Payload.AsMap()
+		for _, key := range path {
+			fields, ok := value.(map[string]interface{})
+			if !ok {
+				return false, nil
+			}
+			if value, ok = fields[key]; !ok {
+				return false, nil
+			}
+		}
+		return compare(value, cond.Operator, cond.Value), nil
+	}
+}
+
+// compare returns whether `actual` relates to `expected` by `op`. Values of different types are
+// never equal, and only numbers are ordered.
+func compare(actual interface{}, op string, expected interface{}) bool {
+	a, aIsNumber := actual.(float64)
+	e, eIsNumber := expected.(float64)
+	if aIsNumber && eIsNumber {
+		switch op {
+		case datamanager.OperatorEqual:
+			return a == e
+		case datamanager.OperatorNotEqual:
+			return a != e
+		case datamanager.OperatorGreaterThan:
+			return a > e
+		case datamanager.OperatorGreaterThanOrEqual:
+			return a >= e
+		case datamanager.OperatorLessThan:
+			return a < e
+		case datamanager.OperatorLessThanOrEqual:
+			return a <= e
+		}
+		return false
+	}
+	switch op {
+	case datamanager.OperatorEqual:
+		return actual == expected
+	case datamanager.OperatorNotEqual:
+		return actual != expected
+	}
+	return false
+}
+
+func visionCondition(cond datamanager.CaptureCondition, svc vision.Service) data.CaptureFilter {
+	return func(ctx context.Context, result data.CaptureResult) (bool, error) {
+		img, err := capturedImage(ctx, result)
+		if err != nil {
+			return false, err
+		}
+		var detections []objectdetection.Detection
+		switch {
+		case img != nil:
+			detections, err = svc.Detections(ctx, img, data.FromDMExtraMap)
+		case cond.Camera != "":
+			detections, err = svc.DetectionsFromCamera(ctx, cond.Camera, data.FromDMExtraMap)
+		default:
+			err = errors.Errorf(
+				"capture condition on vision_service %q needs a camera when the captured data isn't an image", cond.VisionService)
+		}
+		if err != nil {
+			return false, err
+		}
+		for _, d := range detections {
+			if (cond.Label == "" || strings.EqualFold(d.Label(), cond.Label)) && d.Score() >= cond.MinConfidence {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// capturedImage returns the first image of a binary capture, or nil if the capture has no images.
+func capturedImage(ctx context.Context, result data.CaptureResult) (image.Image, error) {
+	if result.Type != data.CaptureTypeBinary {
+		return nil, nil
+	}
+	for _, b := range result.Binaries {
+		var mimeType string
+		switch b.MimeType {
+		case data.MimeTypeImageJpeg:
+			mimeType = utils.MimeTypeJPEG
+		case data.MimeTypeImagePng:
+			mimeType = utils.MimeTypePNG
+		case data.MimeTypeUnspecified, data.MimeTypeApplicationPcd:
+			continue
+		default:
+			continue
+		}
+		return rimage.DecodeImage(ctx, b.Payload, mimeType)
+	}
+	return nil, nil
+}
@@ -0,0 +1,127 @@
+package capture
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestFieldConditions(t *testing.T) {
+	ctx := context.Background()
+	ts := data.Timestamps{TimeRequested: time.Now(), TimeReceived: time.Now()}
+	reading := func(readings map[string]interface{}) data.CaptureResult {
+		result, err := data.NewTabularCaptureResultReadings(ts, readings)
+		test.That(t, err, test.ShouldBeNil)
+		return result
+	}
+
+	filter, used, err := newCaptureFilter([]datamanager.CaptureCondition{
+		{Field: "readings.speed_mps", Operator: datamanager.OperatorGreaterThan, Value: 0.},
+		{Field: "readings.fix", Operator: datamanager.OperatorNotEqual, Value: "none"},
+	}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, used, test.ShouldBeEmpty)
+
+	for _, tc := range []struct {
+		readings map[string]interface{}
+		keep     bool
+	}{
+		{map[string]interface{}{"speed_mps": 1.5, "fix": "rtk"}, true},
+		{map[string]interface{}{"speed_mps": 0, "fix": "rtk"}, false},
+		{map[string]interface{}{"speed_mps": 1.5, "fix": "none"}, false},
+		// readings without the field are not saved
+		{map[string]interface{}{"fix": "rtk"}, false},
+		// nor are ones where it can't be ordered
+		{map[string]interface{}{"speed_mps": "fast", "fix": "rtk"}, false},
+	} {
+		keep, err := filter(ctx, reading(tc.readings))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, keep, test.ShouldEqual, tc.keep)
+	}
+
+	_, err = filter(ctx, data.NewBinaryCaptureResult(ts, []data.Binary{{Payload: []byte("bytes")}}))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "only applies to tabular data")
+
+	filter, _, err = newCaptureFilter(nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filter, test.ShouldBeNil)
+
+	_, _, err = newCaptureFilter([]datamanager.CaptureCondition{{Field: "readings.a", Operator: "~", Value: 1.}}, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestVisionConditions(t *testing.T) {
+	ctx := context.Background()
+	ts := data.Timestamps{TimeRequested: time.Now(), TimeReceived: time.Now()}
+	personWithConfidence := 0.8
+	var detectedImages, detectedCameras int
+	svc := inject.NewVisionService("detector")
+	detections := func() []objectdetection.Detection {
+		return []objectdetection.Detection{
+			objectdetection.NewDetectionWithoutImgBounds(image.Rect(0, 0, 10, 10), 0.9, "dog"),
+			objectdetection.NewDetectionWithoutImgBounds(image.Rect(0, 0, 10, 10), personWithConfidence, "Person"),
+		}
+	}
+	svc.DetectionsFunc = func(ctx context.Context, img image.Image, extra map[string]interface{}) ([]objectdetection.Detection, error) {
+		test.That(t, img.Bounds(), test.ShouldResemble, image.Rect(0, 0, 4, 4))
+		detectedImages++
+		return detections(), nil
+	}
+	svc.DetectionsFromCameraFunc = func(
+		ctx context.Context, cameraName string, extra map[string]interface{},
+	) ([]objectdetection.Detection, error) {
+		test.That(t, cameraName, test.ShouldEqual, "cam")
+		detectedCameras++
+		return detections(), nil
+	}
+	deps := resource.Dependencies{vision.Named("detector"): svc}
+
+	imgBytes, err := rimage.EncodeImage(ctx, image.NewRGBA(image.Rect(0, 0, 4, 4)), utils.MimeTypePNG)
+	test.That(t, err, test.ShouldBeNil)
+	imageCapture := data.NewBinaryCaptureResult(ts, []data.Binary{{Payload: imgBytes, MimeType: data.MimeTypeImagePng}})
+	readingCapture, err := data.NewTabularCaptureResultReadings(ts, map[string]interface{}{"a": 1})
+	test.That(t, err, test.ShouldBeNil)
+
+	// images are detected on
+	filter, used, err := newCaptureFilter([]datamanager.CaptureCondition{
+		{VisionService: "detector", Label: "person", MinConfidence: 0.6},
+	}, deps)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, used, test.ShouldResemble, []resource.Resource{svc})
+	keep, err := filter(ctx, imageCapture)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, keep, test.ShouldBeTrue)
+	test.That(t, detectedImages, test.ShouldEqual, 1)
+
+	personWithConfidence = 0.5
+	keep, err = filter(ctx, imageCapture)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, keep, test.ShouldBeFalse)
+
+	// other data needs a camera to detect on
+	_, err = filter(ctx, readingCapture)
+	test.That(t, err, test.ShouldNotBeNil)
+	filter, _, err = newCaptureFilter([]datamanager.CaptureCondition{{VisionService: "detector", Camera: "cam"}}, deps)
+	test.That(t, err, test.ShouldBeNil)
+	keep, err = filter(ctx, readingCapture)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, keep, test.ShouldBeTrue)
+	test.That(t, detectedCameras, test.ShouldEqual, 1)
+
+	_, _, err = newCaptureFilter([]datamanager.CaptureCondition{{VisionService: "missing"}}, deps)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "missing")
+}
@@ -1,5 +1,10 @@
 package capture
 
+import (
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+)
+
 // MongoConfig is the optional data capture mongo config.
 type MongoConfig struct {
 	URI        string `json:"uri"`
@@ -24,6 +29,15 @@ type Config struct {
 	// (.prog) files should be allowed to grow to before they are convered into .capture
 	// files
 	MaximumCaptureFileSizeBytes int64
+	// TabularFileFormat is the file format tabular data is captured to, data.CaptureFileFormatCapture
+	// (the default) or data.CaptureFileFormatParquet. Binary data is always captured to .capture files.
+	TabularFileFormat string
 
 	MongoConfig *MongoConfig
+	MQTTConfig  *MQTTConfig
+
+	// Dependencies are the resources capture conditions can refer to, such as vision services.
+	Dependencies resource.Dependencies
+	// Sessions are the capture sessions which can be started on demand or by a trigger.
+	Sessions []datamanager.CaptureSessionConfig
 }
@@ -0,0 +1,295 @@
+package capture
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	defaultMQTTTopicTemplate = "viam/{type}/{name}/{method}"
+	defaultMQTTClientID      = "viam-data-manager"
+	mqttConnectTimeout       = 5 * time.Second
+	// mqttDisconnectQuiesceMs is how long in-flight messages are given to be delivered on close.
+	mqttDisconnectQuiesceMs = 250
+)
+
+// mqttTopicPlaceholder matches the placeholders of an MQTT topic template.
+var mqttTopicPlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// MQTTTLSConfig is the TLS config used to connect to an MQTT broker.
+type MQTTTLSConfig struct {
+	// CACertFile is a PEM file of the certificate authorities the broker certificate is verified
+	// against. The system certificate authorities are used when it is unset.
+	CACertFile string `json:"ca_cert_file"`
+	// CertFile and KeyFile are a PEM client certificate and key used to authenticate to the broker.
+	CertFile           string `json:"cert_file"`
+	KeyFile            string `json:"key_file"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+}
+
+// MQTTConfig is the optional data capture MQTT config. Captures of the selected streams are
+// published to the broker as they are collected, in addition to being written to capture files.
+// Tabular captures are published as JSON and each binary capture payload is published as is.
+type MQTTConfig struct {
+	// Broker is the URL of the broker, such as tcp://localhost:1883 or ssl://localhost:8883.
+	Broker   string `json:"broker"`
+	ClientID string `json:"client_id"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// TopicTemplate is the topic captures are published to. The placeholders {name}, {type},
+	// {api} and {method} are replaced by the resource name, the resource API subtype (such as
+	// sensor), the full resource API and the captured method.
+	TopicTemplate string         `json:"topic_template"`
+	QoS           byte           `json:"qos"`
+	Retain        bool           `json:"retain"`
+	TLS           *MQTTTLSConfig `json:"tls"`
+	// Streams selects the captures which are published, either all methods of a resource
+	// ("my-sensor") or a single method ("my-sensor/Readings"). All captures are published when it
+	// is empty.
+	Streams []string `json:"streams"`
+}
+
+// Validate returns an error if the config is invalid.
+func (mc *MQTTConfig) Validate() error {
+	if mc.Broker == "" {
+		return errors.New("broker is required")
+	}
+	broker, err := url.Parse(mc.Broker)
+	if err != nil {
+		return errors.Wrap(err, "invalid broker")
+	}
+	switch broker.Scheme {
+	case "tcp", "mqtt", "ssl", "tls", "mqtts", "ws", "wss":
+	default:
+		return errors.Errorf("unsupported broker scheme %q", broker.Scheme)
+	}
+	if mc.QoS > 2 {
+		return errors.Errorf("qos must be 0, 1 or 2, got %d", mc.QoS)
+	}
+	if mc.TopicTemplate != "" {
+		if strings.ContainsAny(mc.TopicTemplate, "+#") {
+			return errors.New("topic_template can't contain the wildcards + or #")
+		}
+		for _, placeholder := range mqttTopicPlaceholder.FindAllString(mc.TopicTemplate, -1) {
+			switch placeholder {
+			case "{name}", "{type}", "{api}", "{method}":
+			default:
+				return errors.Errorf("unknown topic_template placeholder %s", placeholder)
+			}
+		}
+	}
+	if mc.TLS != nil && (mc.TLS.CertFile == "") != (mc.TLS.KeyFile == "") {
+		return errors.New("tls cert_file and key_file must be set together")
+	}
+	for _, stream := range mc.Streams {
+		if stream == "" || strings.Count(stream, "/") > 1 {
+			return errors.Errorf("invalid stream %q, must be a resource name optionally followed by /method", stream)
+		}
+	}
+	return nil
+}
+
+// Equal returns true when both MQTTConfigs are equal.
+func (mc MQTTConfig) Equal(o MQTTConfig) bool {
+	tlsEqual := mc.TLS == o.TLS || (mc.TLS != nil && o.TLS != nil && *mc.TLS == *o.TLS)
+	return mc.Broker == o.Broker && mc.ClientID == o.ClientID && mc.Username == o.Username &&
+		mc.Password == o.Password && mc.TopicTemplate == o.TopicTemplate && mc.QoS == o.QoS &&
+		mc.Retain == o.Retain && tlsEqual && slices.Equal(mc.Streams, o.Streams)
+}
+
+// selects returns whether captures of the resource method are published.
+func (mc MQTTConfig) selects(md collectorMetadata) bool {
+	if len(mc.Streams) == 0 {
+		return true
+	}
+	return slices.Contains(mc.Streams, md.ResourceName) ||
+		slices.Contains(mc.Streams, md.ResourceName+"/"+md.MethodMetadata.MethodName)
+}
+
+// topic returns the topic captures of the resource method are published to.
+func (mc MQTTConfig) topic(md collectorMetadata) string {
+	template := defaultIfZeroVal(mc.TopicTemplate, defaultMQTTTopicTemplate)
+	return strings.NewReplacer(
+		"{name}", md.ResourceName,
+		"{type}", md.MethodMetadata.API.SubtypeName,
+		"{api}", md.MethodMetadata.API.String(),
+		"{method}", md.MethodMetadata.MethodName,
+	).Replace(template)
+}
+
+func (mc MQTTConfig) tlsConfig() (*tls.Config, error) {
+	//nolint:gosec
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: mc.TLS.InsecureSkipVerify}
+	if mc.TLS.CACertFile != "" {
+		caCert, err := os.ReadFile(mc.TLS.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, errors.Errorf("no certificates found in %s", mc.TLS.CACertFile)
+		}
+	}
+	if mc.TLS.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(mc.TLS.CertFile, mc.TLS.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// mqttTabularMessage is the JSON message a tabular capture is published as.
+type mqttTabularMessage struct {
+	TimeRequested time.Time              `json:"time_requested"`
+	TimeReceived  time.Time              `json:"time_received"`
+	ComponentName string                 `json:"component_name"`
+	ComponentType string                 `json:"component_type"`
+	MethodName    string                 `json:"method_name"`
+	Tags          []string               `json:"tags,omitempty"`
+	Data          map[string]interface{} `json:"data"`
+}
+
+// mqttPublisher publishes captures to an MQTT broker.
+type mqttPublisher struct {
+	client mqtt.Client
+	config MQTTConfig
+	logger logging.Logger
+}
+
+func newMQTTPublisher(config MQTTConfig, logger logging.Logger) (*mqttPublisher, error) {
+	opts := mqtt.NewClientOptions().
+		AddBroker(config.Broker).
+		SetClientID(defaultIfZeroVal(config.ClientID, defaultMQTTClientID)).
+		SetUsername(config.Username).
+		SetPassword(config.Password).
+		SetConnectTimeout(mqttConnectTimeout).
+		// Keep retrying in the background so that a broker which is down does not stop capture.
+		SetConnectRetry(true).
+		SetAutoReconnect(true).
+		SetOnConnectHandler(func(mqtt.Client) {
+			logger.Infof("connected to mqtt broker %s", config.Broker)
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.Warnw("lost connection to mqtt broker", "broker", config.Broker, "error", err)
+		})
+	if config.TLS != nil {
+		tlsConfig, err := config.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+	client := mqtt.NewClient(opts)
+	client.Connect()
+	return &mqttPublisher{client: client, config: config, logger: logger}, nil
+}
+
+// publish publishes a capture of the resource method if the config selects it. It does not wait
+// for the broker to acknowledge the capture.
+func (p *mqttPublisher) publish(md collectorMetadata, tags []string, result data.CaptureResult) {
+	if !p.config.selects(md) {
+		return
+	}
+	topic := p.config.topic(md)
+	switch result.Type {
+	case data.CaptureTypeTabular:
+		if result.TabularData.Payload == nil {
+			return
+		}
+		payload, err := json.Marshal(mqttTabularMessage{
+			TimeRequested: result.TimeRequested,
+			TimeReceived:  result.TimeReceived,
+			ComponentName: md.ResourceName,
+			ComponentType: md.MethodMetadata.API.String(),
+			MethodName:    md.MethodMetadata.MethodName,
+			Tags:          tags,
+			Data:          result.TabularData.Payload.AsMap(),
+		})
+		if err != nil {
+			p.logger.Debugw("failed to encode capture for mqtt", "collector", md, "error", err)
+			return
+		}
+		p.send(topic, payload)
+	case data.CaptureTypeBinary:
+		for _, binary := range result.Binaries {
+			p.send(topic, binary.Payload)
+		}
+	case data.CaptureTypeUnspecified:
+	}
+}
+
+func (p *mqttPublisher) send(topic string, payload []byte) {
+	token := p.client.Publish(topic, p.config.QoS, p.config.Retain, payload)
+	select {
+	case <-token.Done():
+		if err := token.Error(); err != nil {
+			p.logger.Debugw("failed to publish capture to mqtt", "topic", topic, "error", err)
+		}
+	default:
+	}
+}
+
+func (p *mqttPublisher) close() {
+	p.client.Disconnect(mqttDisconnectQuiesceMs)
+}
+
+// captureMQTT holds the MQTT publisher of the current config. Collectors look it up on every
+// capture, so that it can be changed without restarting them.
+type captureMQTT struct {
+	publisher atomic.Pointer[mqttPublisher]
+}
+
+// reconfigure replaces the publisher when the config changes, closing the previous one.
+func (cm *captureMQTT) reconfigure(newConfig *MQTTConfig, logger logging.Logger) {
+	old := cm.publisher.Load()
+	if newConfig == nil || newConfig.Broker == "" {
+		cm.close()
+		return
+	}
+	if old != nil && old.config.Equal(*newConfig) {
+		return
+	}
+	publisher, err := newMQTTPublisher(*newConfig, logger)
+	if err != nil {
+		logger.Warnw("failed to create mqtt client with mqtt_capture_config", "error", err)
+		cm.close()
+		return
+	}
+	if replaced := cm.publisher.Swap(publisher); replaced != nil {
+		replaced.close()
+	}
+	logger.Info("mqtt client created")
+}
+
+func (cm *captureMQTT) close() {
+	if old := cm.publisher.Swap(nil); old != nil {
+		old.close()
+	}
+}
+
+// collectorPublisher returns the data.CapturePublisher of a collector, which publishes its
+// captures with the publisher current at the time of the capture.
+func (cm *captureMQTT) collectorPublisher(md collectorMetadata, tags []string) data.CapturePublisher {
+	return func(_ context.Context, result data.CaptureResult) {
+		if publisher := cm.publisher.Load(); publisher != nil {
+			publisher.publish(md, tags, result)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/services/datamanager"
+)
+
+type publishedMessage struct {
+	topic    string
+	qos      byte
+	retained bool
+	payload  []byte
+}
+
+// fakeMQTTClient records published messages. Its other methods are not implemented.
+type fakeMQTTClient struct {
+	mqtt.Client
+	published []publishedMessage
+}
+
+func (c *fakeMQTTClient) Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+	c.published = append(c.published, publishedMessage{topic, qos, retained, payload.([]byte)})
+	return &mqtt.DummyToken{}
+}
+
+func TestMQTTConfig(t *testing.T) {
+	t.Run("Validate", func(t *testing.T) {
+		tcs := []struct {
+			name   string
+			config MQTTConfig
+			err    string
+		}{
+			{name: "valid", config: MQTTConfig{
+				Broker:        "ssl://localhost:8883",
+				TopicTemplate: "robots/{api}/{name}/{method}",
+				QoS:           1,
+				TLS:           &MQTTTLSConfig{CertFile: "cert.pem", KeyFile: "key.pem"},
+				Streams:       []string{"sensor1", "camera1/ReadImage"},
+			}},
+			{name: "missing broker", config: MQTTConfig{}, err: "broker is required"},
+			{name: "unsupported scheme", config: MQTTConfig{Broker: "http://localhost"}, err: `unsupported broker scheme "http"`},
+			{name: "invalid qos", config: MQTTConfig{Broker: "tcp://localhost:1883", QoS: 3}, err: "qos must be 0, 1 or 2, got 3"},
+			{
+				name:   "wildcard topic",
+				config: MQTTConfig{Broker: "tcp://localhost:1883", TopicTemplate: "viam/#"},
+				err:    "topic_template can't contain the wildcards + or #",
+			},
+			{
+				name:   "unknown placeholder",
+				config: MQTTConfig{Broker: "tcp://localhost:1883", TopicTemplate: "viam/{part}/{name}"},
+				err:    "unknown topic_template placeholder {part}",
+			},
+			{
+				name:   "cert without key",
+				config: MQTTConfig{Broker: "tcp://localhost:1883", TLS: &MQTTTLSConfig{CertFile: "cert.pem"}},
+				err:    "tls cert_file and key_file must be set together",
+			},
+			{
+				name:   "invalid stream",
+				config: MQTTConfig{Broker: "tcp://localhost:1883", Streams: []string{"a/b/c"}},
+				err:    `invalid stream "a/b/c", must be a resource name optionally followed by /method`,
+			},
+		}
+		for _, tc := range tcs {
+			t.Run(tc.name, func(t *testing.T) {
+				err := tc.config.Validate()
+				if tc.err == "" {
+					test.That(t, err, test.ShouldBeNil)
+				} else {
+					test.That(t, err, test.ShouldBeError, tc.err)
+				}
+			})
+		}
+	})
+
+	t.Run("Equal", func(t *testing.T) {
+		config := MQTTConfig{Broker: "ssl://localhost:8883", TLS: &MQTTTLSConfig{CACertFile: "ca.pem"}, Streams: []string{"a"}}
+		other := config
+		other.TLS = &MQTTTLSConfig{CACertFile: "ca.pem"}
+		other.Streams = []string{"a"}
+		test.That(t, config.Equal(other), test.ShouldBeTrue)
+		other.TLS.InsecureSkipVerify = true
+		test.That(t, config.Equal(other), test.ShouldBeFalse)
+		other.TLS = config.TLS
+		other.Streams = nil
+		test.That(t, config.Equal(other), test.ShouldBeFalse)
+	})
+
+	t.Run("topic and selects", func(t *testing.T) {
+		md := newCollectorMetadata(datamanager.DataCaptureConfig{Name: sensor.Named("sensor1"), Method: "Readings"})
+		test.That(t, MQTTConfig{}.topic(md), test.ShouldEqual, "viam/sensor/sensor1/Readings")
+		test.That(t, MQTTConfig{TopicTemplate: "{api}/{name}"}.topic(md), test.ShouldEqual, "rdk:component:sensor/sensor1")
+
+		test.That(t, MQTTConfig{}.selects(md), test.ShouldBeTrue)
+		test.That(t, MQTTConfig{Streams: []string{"sensor1"}}.selects(md), test.ShouldBeTrue)
+		test.That(t, MQTTConfig{Streams: []string{"sensor1/Readings"}}.selects(md), test.ShouldBeTrue)
+		test.That(t, MQTTConfig{Streams: []string{"sensor1/DoCommand"}}.selects(md), test.ShouldBeFalse)
+		test.That(t, MQTTConfig{Streams: []string{"sensor2"}}.selects(md), test.ShouldBeFalse)
+	})
+}
+
+func TestMQTTPublisher(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	client := &fakeMQTTClient{}
+	var cm captureMQTT
+	cm.publisher.Store(&mqttPublisher{
+		client: client,
+		config: MQTTConfig{QoS: 1, Retain: true, Streams: []string{"sensor1", "camera1"}},
+		logger: logger,
+	})
+
+	readingsMD := newCollectorMetadata(datamanager.DataCaptureConfig{Name: sensor.Named("sensor1"), Method: "Readings"})
+	ts := data.Timestamps{TimeRequested: time.Unix(100, 0).UTC(), TimeReceived: time.Unix(101, 0).UTC()}
+	readings, err := data.NewTabularCaptureResultReadings(ts, map[string]interface{}{"temperature": 21.5})
+	test.That(t, err, test.ShouldBeNil)
+	cm.collectorPublisher(readingsMD, []string{"tag1"})(context.Background(), readings)
+
+	imageMD := newCollectorMetadata(datamanager.DataCaptureConfig{Name: camera.Named("camera1"), Method: "ReadImage"})
+	cm.collectorPublisher(imageMD, nil)(context.Background(), data.NewBinaryCaptureResult(ts, []data.Binary{
+		{Payload: []byte("image1")},
+		{Payload: []byte("image2")},
+	}))
+
+	// Streams which are not selected are not published.
+	otherMD := newCollectorMetadata(datamanager.DataCaptureConfig{Name: sensor.Named("sensor2"), Method: "Readings"})
+	cm.collectorPublisher(otherMD, nil)(context.Background(), readings)
+
+	test.That(t, client.published, test.ShouldHaveLength, 3)
+	test.That(t, client.published[0].topic, test.ShouldEqual, "viam/sensor/sensor1/Readings")
+	test.That(t, client.published[0].qos, test.ShouldEqual, 1)
+	test.That(t, client.published[0].retained, test.ShouldBeTrue)
+	var msg map[string]interface{}
+	test.That(t, json.Unmarshal(client.published[0].payload, &msg), test.ShouldBeNil)
+	test.That(t, msg, test.ShouldResemble, map[string]interface{}{
+		"time_requested": "1970-01-01T00:01:40Z",
+		"time_received":  "1970-01-01T00:01:41Z",
+		"component_name": "sensor1",
+		"component_type": "rdk:component:sensor",
+		"method_name":    "Readings",
+		"tags":           []interface{}{"tag1"},
+		"data":           map[string]interface{}{"readings": map[string]interface{}{"temperature": 21.5}},
+	})
+	test.That(t, client.published[1].topic, test.ShouldEqual, "viam/camera/camera1/ReadImage")
+	test.That(t, client.published[1].payload, test.ShouldResemble, []byte("image1"))
+	test.That(t, client.published[2].payload, test.ShouldResemble, []byte("image2"))
+
+	// Nothing is published once the publisher is removed.
+	cm.publisher.Store(nil)
+	cm.collectorPublisher(readingsMD, nil)(context.Background(), readings)
+	test.That(t, client.published, test.ShouldHaveLength, 3)
+}
@@ -0,0 +1,291 @@
+package capture
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+)
+
+// captureSessionTagPrefix prefixes the tag added to the data captured by a capture session, so the
+// data of an incident can be found by the name of its session.
+const captureSessionTagPrefix = "capture_session:"
+
+// runningSession is a capture session which is capturing until endsAt.
+type runningSession struct {
+	config     datamanager.CaptureSessionConfig
+	resources  []resource.Resource
+	collectors []data.Collector
+	endsAt     time.Time
+	workers    *goutils.StoppableWorkers
+}
+
+func (s *runningSession) stop() {
+	s.workers.Stop()
+	for _, collector := range s.collectors {
+		collector.Close()
+	}
+}
+
+// StartCaptureSession starts the configured capture session with the given name for duration, or
+// for its configured duration if duration is zero. If the session is already running, it is
+// extended to end no earlier than duration from now. It returns when the session will end.
+func (c *Capture) StartCaptureSession(name string, duration time.Duration) (time.Time, error) {
+	c.sessionsMu.Lock()
+	defer c.sessionsMu.Unlock()
+	config, ok := c.sessionConfigs[name]
+	if !ok {
+		return time.Time{}, errors.Errorf("no capture session named %q", name)
+	}
+	if duration <= 0 {
+		duration = config.Duration()
+	}
+	endsAt := c.clk.Now().Add(duration)
+	for _, recorder := range c.clipRecorders[name] {
+		recorder.record(endsAt)
+	}
+	if s, ok := c.runningSessions[name]; ok {
+		if endsAt.After(s.endsAt) {
+			s.endsAt = endsAt
+		}
+		return s.endsAt, nil
+	}
+
+	s := &runningSession{config: config, endsAt: endsAt}
+	for _, collectorConfig := range config.CaptureMethods {
+		collector, res, err := c.newSessionCollector(name, collectorConfig)
+		if err != nil {
+			for _, collector := range s.collectors {
+				collector.Close()
+			}
+			return time.Time{}, errors.Wrapf(err, "failed to start capture session %q", name)
+		}
+		s.collectors = append(s.collectors, collector)
+		s.resources = append(s.resources, res)
+	}
+	c.logger.Infof("capture session %q started, ends at %s", name, endsAt)
+	c.runningSessions[name] = s
+	s.workers = goutils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
+		c.endSessionWhenDone(ctx, name, s)
+	})
+	return endsAt, nil
+}
+
+func (c *Capture) newSessionCollector(
+	name string,
+	collectorConfig datamanager.DataCaptureConfig,
+) (data.Collector, resource.Resource, error) {
+	config := c.sessionCaptureConfig
+	res, err := config.Dependencies.Lookup(collectorConfig.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	collectorConfig.Tags = append(slices.Clone(config.Tags), captureSessionTagPrefix+name)
+	collectorConfig.CaptureDirectory = config.CaptureDir
+	filter, _, err := newCaptureFilter(collectorConfig.Conditions, config.Dependencies)
+	if err != nil {
+		return nil, nil, err
+	}
+	collector, err := c.newCollector(res, newCollectorMetadata(collectorConfig), collectorConfig, config, nil, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return collector, res, nil
+}
+
+// endSessionWhenDone waits until the session ends, which may be extended while waiting, and then
+// stops its collectors.
+func (c *Capture) endSessionWhenDone(ctx context.Context, name string, s *runningSession) {
+	for {
+		c.sessionsMu.Lock()
+		remaining := s.endsAt.Sub(c.clk.Now())
+		c.sessionsMu.Unlock()
+		if remaining <= 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.clk.After(remaining):
+		}
+	}
+	c.sessionsMu.Lock()
+	if c.runningSessions[name] == s {
+		delete(c.runningSessions, name)
+	}
+	c.sessionsMu.Unlock()
+	for _, collector := range s.collectors {
+		collector.Close()
+	}
+	c.logger.Infof("capture session %q ended", name)
+}
+
+// reconfigureSessions replaces the configured capture sessions and restarts their triggers and
+// video clip recorders. Running sessions keep capturing unless their config or resources changed,
+// though their video clips are split at the reconfigure.
+func (c *Capture) reconfigureSessions(config Config) {
+	c.sessionWorkers.Stop()
+	c.sessionsMu.Lock()
+	var toStop []*runningSession
+	configs := make(map[string]datamanager.CaptureSessionConfig, len(config.Sessions))
+	for _, session := range config.Sessions {
+		configs[session.Name] = session
+	}
+	for name, s := range c.runningSessions {
+		newConfig, ok := configs[name]
+		if !ok || !reflect.DeepEqual(newConfig, s.config) || !sessionResourcesUnchanged(s, config.Dependencies) {
+			c.logger.Infof("stopping capture session %q as its config changed", name)
+			delete(c.runningSessions, name)
+			toStop = append(toStop, s)
+		}
+	}
+	c.sessionConfigs = configs
+	c.sessionCaptureConfig = config
+	c.sessionsMu.Unlock()
+	for _, s := range toStop {
+		s.stop()
+	}
+
+	var workers []func(context.Context)
+	recorders := map[string][]*clipRecorder{}
+	for _, session := range config.Sessions {
+		for _, clip := range session.VideoClips {
+			cam, err := camera.FromDependencies(config.Dependencies, clip.Camera)
+			if err != nil {
+				c.logger.Warnw("capture session video clip disabled", "session", session.Name, "error", err)
+				continue
+			}
+			recorder := &clipRecorder{
+				session:    session.Name,
+				config:     clip,
+				cam:        cam,
+				captureDir: config.CaptureDir,
+				clk:        c.clk,
+				logger:     c.logger,
+			}
+			recorders[session.Name] = append(recorders[session.Name], recorder)
+			workers = append(workers, recorder.run)
+		}
+		if session.Trigger == nil {
+			continue
+		}
+		check, err := newTriggerCheck(*session.Trigger, config.Dependencies)
+		if err != nil {
+			c.logger.Warnw("capture session trigger disabled", "session", session.Name, "error", err)
+			continue
+		}
+		name, interval := session.Name, session.Trigger.PollInterval()
+		workers = append(workers, func(ctx context.Context) {
+			c.runTrigger(ctx, name, interval, check)
+		})
+	}
+
+	c.sessionsMu.Lock()
+	defer c.sessionsMu.Unlock()
+	for name, s := range c.runningSessions {
+		for _, recorder := range recorders[name] {
+			recorder.record(s.endsAt)
+		}
+	}
+	c.clipRecorders = recorders
+	c.sessionWorkers = goutils.NewBackgroundStoppableWorkers(workers...)
+}
+
+func sessionResourcesUnchanged(s *runningSession, deps resource.Dependencies) bool {
+	for i, collectorConfig := range s.config.CaptureMethods {
+		res, err := deps.Lookup(collectorConfig.Name)
+		if err != nil || res != s.resources[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// closeSessions stops all triggers, video clip recorders and running capture sessions.
+func (c *Capture) closeSessions() {
+	c.sessionWorkers.Stop()
+	c.sessionsMu.Lock()
+	c.clipRecorders = nil
+	running := c.runningSessions
+	c.runningSessions = map[string]*runningSession{}
+	c.sessionConfigs = nil
+	c.sessionsMu.Unlock()
+	for _, s := range running {
+		s.stop()
+	}
+}
+
+// runTrigger starts the session whenever check starts to hold.
+func (c *Capture) runTrigger(ctx context.Context, name string, interval time.Duration, check func(context.Context) (bool, error)) {
+	t := c.clk.Ticker(interval)
+	defer t.Stop()
+	var fired bool
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		fires, err := check(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logger.Debugw("failed to check capture session trigger", "session", name, "error", err)
+			}
+			continue
+		}
+		if fires && !fired {
+			c.logger.Infof("capture session %q triggered", name)
+			if _, err := c.StartCaptureSession(name, 0); err != nil {
+				c.logger.Warnw("failed to start triggered capture session", "session", name, "error", err)
+			}
+		}
+		fired = fires
+	}
+}
+
+// newTriggerCheck returns a function that returns whether the trigger's digital input is high, or
+// whether its condition holds for the readings of its sensor.
+func newTriggerCheck(
+	trigger datamanager.CaptureSessionTrigger,
+	deps resource.Dependencies,
+) (func(context.Context) (bool, error), error) {
+	if trigger.Board != "" {
+		b, err := board.FromDependencies(deps, trigger.Board)
+		if err != nil {
+			return nil, err
+		}
+		pin, err := b.GPIOPinByName(trigger.Pin)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) (bool, error) {
+			return pin.Get(ctx, nil)
+		}, nil
+	}
+	s, err := sensor.FromDependencies(deps, trigger.Sensor)
+	if err != nil {
+		return nil, err
+	}
+	condition := fieldCondition(*trigger.Condition)
+	return func(ctx context.Context) (bool, error) {
+		readings, err := s.Readings(ctx, data.FromDMExtraMap)
+		if err != nil {
+			return false, err
+		}
+		result, err := data.NewTabularCaptureResultReadings(data.Timestamps{}, readings)
+		if err != nil {
+			return false, err
+		}
+		return condition(ctx, result)
+	}, nil
+}
@@ -0,0 +1,178 @@
+package capture
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func newSessionTestDeps() (resource.Dependencies, *atomic.Bool, *atomic.Int64) {
+	var pinHigh atomic.Bool
+	var readings atomic.Int64
+	s := inject.NewSensor("imu")
+	s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"count": readings.Add(1)}, nil
+	}
+	b := inject.NewBoard("pi")
+	b.GPIOPinByNameFunc = func(name string) (board.GPIOPin, error) {
+		return &inject.GPIOPin{GetFunc: func(ctx context.Context, extra map[string]interface{}) (bool, error) {
+			return pinHigh.Load(), nil
+		}}, nil
+	}
+	return resource.Dependencies{sensor.Named("imu"): s, board.Named("pi"): b}, &pinHigh, &readings
+}
+
+// sessionCaptureFileTags returns the tags of the capture files in captureDir.
+func sessionCaptureFileTags(t *testing.T, captureDir string) [][]string {
+	t.Helper()
+	var tags [][]string
+	test.That(t, filepath.WalkDir(captureDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != data.CompletedCaptureFileExt {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		captureFile, err := data.ReadCaptureFile(f)
+		if err != nil {
+			return err
+		}
+		tags = append(tags, captureFile.ReadMetadata().GetTags())
+		return nil
+	}), test.ShouldBeNil)
+	return tags
+}
+
+func TestCaptureSession(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	deps, _, readings := newSessionTestDeps()
+	captureDir := t.TempDir()
+	session := datamanager.CaptureSessionConfig{
+		Name:            "bump",
+		DurationSeconds: 0.2,
+		CaptureMethods: []datamanager.DataCaptureConfig{
+			{Name: sensor.Named("imu"), Method: "Readings", CaptureFrequencyHz: 100},
+		},
+	}
+	c := New(clock.New(), logger)
+	defer c.Close(ctx)
+	c.Reconfigure(ctx, CollectorConfigsByResource{}, Config{
+		CaptureDir:                  captureDir,
+		Tags:                        []string{"robot"},
+		MaximumCaptureFileSizeBytes: 1024,
+		Dependencies:                deps,
+		Sessions:                    []datamanager.CaptureSessionConfig{session},
+	})
+
+	_, err := c.StartCaptureSession("missing", 0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	start := time.Now()
+	endsAt, err := c.StartCaptureSession("bump", 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, endsAt.Sub(start), test.ShouldAlmostEqual, 200*time.Millisecond, float64(50*time.Millisecond))
+	// starting a running session extends it
+	extendedEndsAt, err := c.StartCaptureSession("bump", 300*time.Millisecond)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, extendedEndsAt.After(endsAt), test.ShouldBeTrue)
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		c.sessionsMu.Lock()
+		defer c.sessionsMu.Unlock()
+		test.That(tb, c.runningSessions, test.ShouldBeEmpty)
+	})
+	test.That(t, time.Now().Before(extendedEndsAt), test.ShouldBeFalse)
+	test.That(t, readings.Load(), test.ShouldBeGreaterThan, 5)
+
+	// no more readings are captured after the session ends
+	captured := readings.Load()
+	time.Sleep(50 * time.Millisecond)
+	test.That(t, readings.Load(), test.ShouldEqual, captured)
+
+	tags := sessionCaptureFileTags(t, captureDir)
+	test.That(t, tags, test.ShouldNotBeEmpty)
+	for _, fileTags := range tags {
+		test.That(t, fileTags, test.ShouldResemble, []string{"robot", "capture_session:bump"})
+	}
+}
+
+func TestCaptureSessionTriggers(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	deps, pinHigh, _ := newSessionTestDeps()
+	methods := []datamanager.DataCaptureConfig{{Name: sensor.Named("imu"), Method: "Readings", CaptureFrequencyHz: 100}}
+	c := New(clock.New(), logger)
+	defer c.Close(ctx)
+	c.Reconfigure(ctx, CollectorConfigsByResource{}, Config{
+		CaptureDir:                  t.TempDir(),
+		MaximumCaptureFileSizeBytes: 1024,
+		Dependencies:                deps,
+		Sessions: []datamanager.CaptureSessionConfig{
+			{
+				Name:            "pin",
+				DurationSeconds: 60,
+				CaptureMethods:  methods,
+				Trigger:         &datamanager.CaptureSessionTrigger{Board: "pi", Pin: "37", PollFrequencyHz: 100},
+			},
+			{
+				Name:            "readings",
+				DurationSeconds: 60,
+				CaptureMethods:  methods,
+				Trigger: &datamanager.CaptureSessionTrigger{
+					Sensor: "imu",
+					Condition: &datamanager.CaptureCondition{
+						Field: "readings.count", Operator: datamanager.OperatorGreaterThan, Value: 30.,
+					},
+					PollFrequencyHz: 100,
+				},
+			},
+		},
+	})
+	running := func() []string {
+		c.sessionsMu.Lock()
+		defer c.sessionsMu.Unlock()
+		var names []string
+		for name := range c.runningSessions {
+			names = append(names, name)
+		}
+		return names
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	test.That(t, running(), test.ShouldBeEmpty)
+	pinHigh.Store(true)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, strings.Join(running(), ","), test.ShouldContainSubstring, "pin")
+	})
+	// the session readings trigger once enough readings have been captured
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, strings.Join(running(), ","), test.ShouldContainSubstring, "readings")
+	})
+
+	// sessions whose config is removed are stopped
+	c.Reconfigure(ctx, CollectorConfigsByResource{}, Config{
+		CaptureDir:                  t.TempDir(),
+		MaximumCaptureFileSizeBytes: 1024,
+		Dependencies:                deps,
+	})
+	test.That(t, running(), test.ShouldBeEmpty)
+}
@@ -2,14 +2,17 @@ package builtin
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 
 	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/internal/cloud"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/services/datamanager"
 	"go.viam.com/rdk/services/datamanager/builtin/capture"
 	datasync "go.viam.com/rdk/services/datamanager/builtin/sync"
 	"go.viam.com/rdk/utils"
@@ -46,6 +49,13 @@ type Config struct {
 	DeleteEveryNthWhenDiskFull  int                  `json:"delete_every_nth_when_disk_full"`
 	MaximumCaptureFileSizeBytes int64                `json:"maximum_capture_file_size_bytes"`
 	MongoCaptureConfig          *capture.MongoConfig `json:"mongo_capture_config"`
+	MQTTCaptureConfig           *capture.MQTTConfig  `json:"mqtt_capture_config"`
+	TabularCaptureFileFormat    string               `json:"tabular_capture_file_format"`
+	CaptureDirQuotaBytes        int64                `json:"capture_dir_quota_bytes"`
+	EvictionPolicy              string               `json:"eviction_policy"`
+	CapturePriorities           map[string]int       `json:"capture_priorities"`
+	// CaptureSessions are bounded high rate capture sessions, see datamanager.CaptureSessionConfig.
+	CaptureSessions []datamanager.CaptureSessionConfig `json:"capture_sessions"`
 	// Sync
 	AdditionalSyncPaths    []string `json:"additional_sync_paths"`
 	FileLastModifiedMillis int      `json:"file_last_modified_millis"`
@@ -53,6 +63,11 @@ type Config struct {
 	ScheduledSyncDisabled  bool     `json:"sync_disabled"`
 	SelectiveSyncerName    string   `json:"selective_syncer_name"`
 	SyncIntervalMins       float64  `json:"sync_interval_mins"`
+	// MaximumUploadBytesPerSec, SyncWindows and SyncNetworkInterfaces keep sync from competing with other
+	// traffic, see sync.Config.
+	MaximumUploadBytesPerSec int64                 `json:"maximum_upload_bytes_per_sec"`
+	SyncWindows              []datasync.SyncWindow `json:"sync_windows"`
+	SyncNetworkInterfaces    []string              `json:"sync_network_interfaces"`
 }
 
 // Validate returns components which will be depended upon weakly due to the above matcher.
@@ -72,6 +87,41 @@ func (c *Config) Validate(path string) ([]string, error) {
 	if c.DeleteEveryNthWhenDiskFull < 0 {
 		return nil, errors.New("delete_every_nth_when_disk_full can't be negative")
 	}
+	switch c.TabularCaptureFileFormat {
+	case "", data.CaptureFileFormatCapture, data.CaptureFileFormatParquet:
+	default:
+		return nil, fmt.Errorf("unknown tabular_capture_file_format %q, must be %q or %q",
+			c.TabularCaptureFileFormat, data.CaptureFileFormatCapture, data.CaptureFileFormatParquet)
+	}
+	if c.MQTTCaptureConfig != nil {
+		if err := c.MQTTCaptureConfig.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid mqtt_capture_config: %w", err)
+		}
+	}
+	if c.CaptureDirQuotaBytes < 0 {
+		return nil, errors.New("capture_dir_quota_bytes can't be negative")
+	}
+	if err := datasync.ValidateEvictionPolicy(c.EvictionPolicy); err != nil {
+		return nil, err
+	}
+	if c.MaximumUploadBytesPerSec < 0 {
+		return nil, errors.New("maximum_upload_bytes_per_sec can't be negative")
+	}
+	for _, window := range c.SyncWindows {
+		if err := window.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	sessionNames := map[string]bool{}
+	for _, session := range c.CaptureSessions {
+		if err := session.Validate(); err != nil {
+			return nil, err
+		}
+		if sessionNames[session.Name] {
+			return nil, fmt.Errorf("duplicate capture session name %q", session.Name)
+		}
+		sessionNames[session.Name] = true
+	}
 	return []string{cloud.InternalServiceName.String()}, nil
 }
 
@@ -102,6 +152,9 @@ func (c *Config) captureConfig(logger logging.Logger) capture.Config {
 		Tags:                        c.Tags,
 		MaximumCaptureFileSizeBytes: maximumCaptureFileSizeBytes,
 		MongoConfig:                 c.MongoCaptureConfig,
+		MQTTConfig:                  c.MQTTCaptureConfig,
+		Sessions:                    c.CaptureSessions,
+		TabularFileFormat:           c.TabularCaptureFileFormat,
 	}
 }
 
@@ -131,6 +184,15 @@ func (c *Config) syncConfig(syncSensor sensor.Sensor, syncSensorEnabled bool, lo
 			c.SyncIntervalMins, syncIntervalMinsEpsilon, defaultSyncIntervalMins)
 	}
 
+	var capturePriorities map[string]int
+	if len(c.CapturePriorities) > 0 {
+		// match the names of the directories capture files are written to
+		capturePriorities = make(map[string]int, len(c.CapturePriorities))
+		for key, priority := range c.CapturePriorities {
+			capturePriorities[data.CaptureFilePathWithReplacedReservedChars(key)] = priority
+		}
+	}
+
 	return datasync.Config{
 		AdditionalSyncPaths:        c.AdditionalSyncPaths,
 		Tags:                       c.Tags,
@@ -144,5 +206,11 @@ func (c *Config) syncConfig(syncSensor sensor.Sensor, syncSensorEnabled bool, lo
 		SyncIntervalMins:           syncIntervalMins,
 		SelectiveSyncSensor:        syncSensor,
 		SelectiveSyncSensorEnabled: syncSensorEnabled,
+		CaptureDirQuotaBytes:       c.CaptureDirQuotaBytes,
+		EvictionPolicy:             c.EvictionPolicy,
+		CapturePriorities:          capturePriorities,
+		MaximumUploadBytesPerSec:   c.MaximumUploadBytesPerSec,
+		SyncWindows:                c.SyncWindows,
+		SyncNetworkInterfaces:      c.SyncNetworkInterfaces,
 	}
 }
@@ -28,6 +28,12 @@ var fullConfig = &Config{
 	SelectiveSyncerName:         "some name",
 	SyncIntervalMins:            0.5,
 	Tags:                        []string{"a", "b", "c"},
+	CaptureDirQuotaBytes:        1024,
+	EvictionPolicy:              sync.EvictionPolicyLowestPriorityFirst,
+	CapturePriorities:           map[string]int{"remote:camera": 2, "movement_sensor/Position": 1},
+	MaximumUploadBytesPerSec:    4096,
+	SyncWindows:                 []sync.SyncWindow{{Start: "01:00", End: "05:00"}},
+	SyncNetworkInterfaces:       []string{"eth0"},
 }
 
 func TestConfig(t *testing.T) {
@@ -71,6 +77,50 @@ func TestConfig(t *testing.T) {
 				config: Config{DeleteEveryNthWhenDiskFull: -1},
 				err:    errors.New("delete_every_nth_when_disk_full can't be negative"),
 			},
+			{
+				name:   "returns an error if CaptureDirQuotaBytes is negative",
+				config: Config{CaptureDirQuotaBytes: -1},
+				err:    errors.New("capture_dir_quota_bytes can't be negative"),
+			},
+			{
+				name:   "returns an error if TabularCaptureFileFormat is unknown",
+				config: Config{TabularCaptureFileFormat: "csv"},
+				err:    errors.New(`unknown tabular_capture_file_format "csv", must be "capture" or "parquet"`),
+			},
+			{
+				name:   "returns an error if MQTTCaptureConfig is invalid",
+				config: Config{MQTTCaptureConfig: &capture.MQTTConfig{Broker: "tcp://localhost:1883", QoS: 3}},
+				err:    errors.New("invalid mqtt_capture_config: qos must be 0, 1 or 2, got 3"),
+			},
+			{
+				name:   "returns an error if EvictionPolicy is unknown",
+				config: Config{EvictionPolicy: "random"},
+				err:    errors.New(`unknown eviction_policy "random", must be "oldest_first" or "lowest_priority_first"`),
+			},
+			{
+				name:   "returns an error if MaximumUploadBytesPerSec is negative",
+				config: Config{MaximumUploadBytesPerSec: -1},
+				err:    errors.New("maximum_upload_bytes_per_sec can't be negative"),
+			},
+			{
+				name:   "returns an error if a sync window is invalid",
+				config: Config{SyncWindows: []sync.SyncWindow{{Start: "1am", End: "05:00"}}},
+				err:    errors.New(`invalid sync window start "1am", must be a time such as "01:00"`),
+			},
+			{
+				name:   "returns an error if a sync window is empty",
+				config: Config{SyncWindows: []sync.SyncWindow{{Start: "01:00", End: "01:00"}}},
+				err:    errors.New("sync window from 01:00 to 01:00 is empty"),
+			},
+			{
+				name: "returns the internal cloud service name with sync windows spanning midnight",
+				config: Config{
+					MaximumUploadBytesPerSec: 1000,
+					SyncWindows:              []sync.SyncWindow{{Start: "22:00", End: "02:00"}},
+					SyncNetworkInterfaces:    []string{"eth0"},
+				},
+				deps: []string{cloud.InternalServiceName.String()},
+			},
 		}
 
 		for _, tc := range tcs {
@@ -161,6 +211,12 @@ func TestConfig(t *testing.T) {
 				SelectiveSyncerName:        "some name",
 				SyncIntervalMins:           0.5,
 				Tags:                       []string{"a", "b", "c"},
+				CaptureDirQuotaBytes:       1024,
+				EvictionPolicy:             sync.EvictionPolicyLowestPriorityFirst,
+				CapturePriorities:          map[string]int{"remote_camera": 2, "movement_sensor/Position": 1},
+				MaximumUploadBytesPerSec:   4096,
+				SyncWindows:                []sync.SyncWindow{{Start: "01:00", End: "05:00"}},
+				SyncNetworkInterfaces:      []string{"eth0"},
 			})
 		})
 	})
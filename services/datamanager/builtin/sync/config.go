@@ -85,6 +85,27 @@ type Config struct {
 	// unil the Readings method of the SelectiveSyncSensor (when called on the SyncIntervalMins interval) returns
 	// the a key of datamanager.ShouldSyncKey and a value of `true`
 	SelectiveSyncSensor sensor.Sensor
+	// CaptureDirQuotaBytes, when positive, is the maximum size of the CaptureDir.
+	// If data capture is enabled, completed capture files which have not been synced
+	// yet are evicted in the order of EvictionPolicy whenever the CaptureDir grows
+	// past it, so that capture can't fill the file system while offline.
+	CaptureDirQuotaBytes int64
+	// EvictionPolicy is the order in which capture files are evicted when the
+	// CaptureDir exceeds CaptureDirQuotaBytes. Defaults to EvictionPolicyOldestFirst.
+	EvictionPolicy string
+	// CapturePriorities maps "<resource name>" or "<resource name>/<method>" to the
+	// priority of its captured data, for EvictionPolicyLowestPriorityFirst. Unlisted
+	// capture methods have priority 0.
+	CapturePriorities map[string]int
+	// MaximumUploadBytesPerSec, when positive, caps the bandwidth of all uploads combined so that
+	// sync doesn't compete with other traffic, such as teleoperation.
+	MaximumUploadBytesPerSec int64
+	// SyncWindows, when non empty, are the daily time windows, in local time, during which scheduled
+	// sync runs. Scheduled sync stops handing out files once its window ends. Manual syncs are not affected.
+	SyncWindows []SyncWindow
+	// SyncNetworkInterfaces, when non empty, are the network interfaces, such as "eth0", of which at
+	// least one must be up for scheduled sync to run, such as to only sync over Ethernet.
+	SyncNetworkInterfaces []string
 }
 
 func (c Config) schedulerEnabled() bool {
@@ -106,7 +127,13 @@ func (c Config) Equal(o Config) bool {
 		c.SyncIntervalMins == o.SyncIntervalMins &&
 		reflect.DeepEqual(c.Tags, o.Tags) &&
 		c.SelectiveSyncSensorEnabled == o.SelectiveSyncSensorEnabled &&
-		c.SelectiveSyncSensor == o.SelectiveSyncSensor
+		c.SelectiveSyncSensor == o.SelectiveSyncSensor &&
+		c.CaptureDirQuotaBytes == o.CaptureDirQuotaBytes &&
+		c.EvictionPolicy == o.EvictionPolicy &&
+		reflect.DeepEqual(c.CapturePriorities, o.CapturePriorities) &&
+		c.MaximumUploadBytesPerSec == o.MaximumUploadBytesPerSec &&
+		reflect.DeepEqual(c.SyncWindows, o.SyncWindows) &&
+		reflect.DeepEqual(c.SyncNetworkInterfaces, o.SyncNetworkInterfaces)
 }
 
 func (c *Config) logDiff(o Config, logger logging.Logger) {
@@ -173,6 +200,31 @@ func (c *Config) logDiff(o Config, logger logging.Logger) {
 		}
 		logger.Infof("SelectiveSyncSensor: old: %s, new: %s", oldName, newName)
 	}
+
+	if c.CaptureDirQuotaBytes != o.CaptureDirQuotaBytes {
+		logger.Infof("capture_dir_quota_bytes: old: %d, new: %d", c.CaptureDirQuotaBytes, o.CaptureDirQuotaBytes)
+	}
+
+	if c.EvictionPolicy != o.EvictionPolicy {
+		logger.Infof("eviction_policy: old: %s, new: %s", c.EvictionPolicy, o.EvictionPolicy)
+	}
+
+	if !reflect.DeepEqual(c.CapturePriorities, o.CapturePriorities) {
+		logger.Infof("capture_priorities: old: %v, new: %v", c.CapturePriorities, o.CapturePriorities)
+	}
+
+	if c.MaximumUploadBytesPerSec != o.MaximumUploadBytesPerSec {
+		logger.Infof("maximum_upload_bytes_per_sec: old: %d, new: %d", c.MaximumUploadBytesPerSec, o.MaximumUploadBytesPerSec)
+	}
+
+	if !reflect.DeepEqual(c.SyncWindows, o.SyncWindows) {
+		logger.Infof("sync_windows: old: %v, new: %v", c.SyncWindows, o.SyncWindows)
+	}
+
+	if !reflect.DeepEqual(c.SyncNetworkInterfaces, o.SyncNetworkInterfaces) {
+		logger.Infof("sync_network_interfaces: old: %s, new: %s",
+			strings.Join(c.SyncNetworkInterfaces, " "), strings.Join(o.SyncNetworkInterfaces, " "))
+	}
 }
 
 // SyncPaths returns the capture directory and additional sync paths as a slice.
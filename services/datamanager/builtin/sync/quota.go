@@ -0,0 +1,195 @@
+package sync
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+)
+
+// The policies which decide which capture files are evicted first when the capture directory
+// exceeds its quota.
+const (
+	// EvictionPolicyOldestFirst evicts the least recently written capture files first.
+	EvictionPolicyOldestFirst = "oldest_first"
+	// EvictionPolicyLowestPriorityFirst evicts the capture files of the lowest priority capture
+	// methods first, and the oldest of those first.
+	EvictionPolicyLowestPriorityFirst = "lowest_priority_first"
+)
+
+// ValidateEvictionPolicy returns an error if policy is not a known eviction policy. The empty
+// string is valid and means EvictionPolicyOldestFirst.
+func ValidateEvictionPolicy(policy string) error {
+	switch policy {
+	case "", EvictionPolicyOldestFirst, EvictionPolicyLowestPriorityFirst:
+		return nil
+	default:
+		return errors.Errorf("unknown eviction_policy %q, must be %q or %q",
+			policy, EvictionPolicyOldestFirst, EvictionPolicyLowestPriorityFirst)
+	}
+}
+
+type captureFileInfo struct {
+	path     string
+	size     int64
+	modTime  time.Time
+	priority int
+}
+
+func enforceCaptureDirQuotaOnSchedule(
+	ctx context.Context,
+	fileTracker *fileTracker,
+	config Config,
+	stats *atomicUploadStats,
+	clock clock.Clock,
+	logger logging.Logger,
+) {
+	t := clock.Ticker(CheckDeleteExcessFilesInterval)
+	defer t.Stop()
+	for {
+		if err := ctx.Err(); err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			start := clock.Now()
+			evictedFileCount, evictedBytes, err := enforceCaptureDirQuota(ctx, fileTracker, config, logger)
+			stats.evictedFileCount.Add(uint64(evictedFileCount))
+			stats.evictedBytes.Add(uint64(evictedBytes))
+			duration := clock.Since(start)
+			switch {
+			case err != nil:
+				logger.Errorw("error enforcing capture directory quota", "error", err, "execution time", duration.String())
+			case evictedFileCount > 0:
+				logger.Warnf("%d files (%s) have been evicted to keep the capture directory under its quota of %s, execution time: %s",
+					evictedFileCount, data.FormatBytesI64(evictedBytes), data.FormatBytesI64(config.CaptureDirQuotaBytes), duration)
+			default:
+				logger.Debugf("capture directory is under its quota, execution time: %s", duration)
+			}
+		}
+	}
+}
+
+// enforceCaptureDirQuota deletes completed capture and parquet files, in the order of the config's
+// eviction policy, until the capture directory is no larger than its quota. Files which are being
+// synced are skipped. It returns the number and total size of the deleted files.
+func enforceCaptureDirQuota(
+	ctx context.Context,
+	fileTracker *fileTracker,
+	config Config,
+	logger logging.Logger,
+) (int, int64, error) {
+	var dirSize int64
+	var candidates []captureFileInfo
+	readFile := func(path string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			// files can be renamed from .prog to .capture or be synced while walking the dir
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		fileInfo, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		dirSize += fileInfo.Size()
+		if ext := filepath.Ext(path); ext == data.CompletedCaptureFileExt || ext == data.ParquetCaptureFileExt {
+			candidates = append(candidates, captureFileInfo{
+				path:     path,
+				size:     fileInfo.Size(),
+				modTime:  fileInfo.ModTime(),
+				priority: capturePriority(config.CaptureDir, path, config.CapturePriorities),
+			})
+		}
+		return nil
+	}
+	if err := filepath.WalkDir(config.CaptureDir, readFile); err != nil {
+		return 0, 0, err
+	}
+	if dirSize <= config.CaptureDirQuotaBytes {
+		return 0, 0, nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if config.EvictionPolicy == EvictionPolicyLowestPriorityFirst && candidates[i].priority != candidates[j].priority {
+			return candidates[i].priority < candidates[j].priority
+		}
+		return candidates[i].modTime.Before(candidates[j].modTime)
+	})
+
+	var evictedFileCount int
+	var evictedBytes int64
+	for _, f := range candidates {
+		if dirSize <= config.CaptureDirQuotaBytes {
+			break
+		}
+		if ctx.Err() != nil {
+			return evictedFileCount, evictedBytes, ctx.Err()
+		}
+		if !fileTracker.markInProgress(f.path) {
+			logger.Debugw("Tried to mark file as in progress but lock already held", "file", f.path)
+			continue
+		}
+		if err := os.Remove(f.path); err != nil {
+			fileTracker.unmarkInProgress(f.path)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			logger.Warnw("error evicting file", "error", err)
+			return evictedFileCount, evictedBytes, err
+		}
+		logger.Debugf("evicted %s", f.path)
+		dirSize -= f.size
+		evictedFileCount++
+		evictedBytes += f.size
+	}
+	if dirSize > config.CaptureDirQuotaBytes {
+		logger.Warnf("capture directory is %s which is still over its quota of %s, as the remaining files are being written or synced",
+			data.FormatBytesI64(dirSize), data.FormatBytesI64(config.CaptureDirQuotaBytes))
+	}
+	return evictedFileCount, evictedBytes, nil
+}
+
+// capturePriority returns the priority of the capture file at path, which is the priority of its
+// "<resource name>/<method>" if set, otherwise that of its "<resource name>", otherwise 0.
+// Capture files are written to <capture dir>/<api>/<resource name>/<method>/.
+func capturePriority(captureDir, path string, priorities map[string]int) int {
+	if len(priorities) == 0 {
+		return 0
+	}
+	rel, err := filepath.Rel(captureDir, filepath.Dir(path))
+	if err != nil {
+		return 0
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 3 {
+		return 0
+	}
+	name, method := parts[1], parts[2]
+	if priority, ok := priorities[name+"/"+method]; ok {
+		return priority
+	}
+	return priorities[name]
+}
@@ -0,0 +1,109 @@
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestEnforceCaptureDirQuota(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	now := time.Now()
+	type captureFile struct {
+		dir string
+		// age is how many minutes ago the file was last written
+		age int
+	}
+	cam := filepath.Join("rdk_component_camera", "cam", "ReadImage")
+	gps := filepath.Join("rdk_component_movement_sensor", "gps", "Position")
+	files := []captureFile{
+		{cam, 1}, {cam, 2}, {cam, 3},
+		{gps, 4}, {gps, 5},
+	}
+	writeFiles := func(t *testing.T) string {
+		t.Helper()
+		captureDir := t.TempDir()
+		for i, f := range files {
+			dir := filepath.Join(captureDir, f.dir)
+			test.That(t, os.MkdirAll(dir, 0o700), test.ShouldBeNil)
+			path := filepath.Join(dir, filepath.Base(f.dir)+string(rune('0'+i))+".capture")
+			test.That(t, os.WriteFile(path, make([]byte, 100), 0o600), test.ShouldBeNil)
+			modTime := now.Add(-time.Duration(f.age) * time.Minute)
+			test.That(t, os.Chtimes(path, modTime, modTime), test.ShouldBeNil)
+		}
+		// files being written count towards the quota but are never evicted
+		test.That(t, os.WriteFile(filepath.Join(captureDir, cam, "5.prog"), make([]byte, 100), 0o600), test.ShouldBeNil)
+		return captureDir
+	}
+	remaining := func(t *testing.T, captureDir string) []string {
+		t.Helper()
+		var names []string
+		test.That(t, filepath.WalkDir(captureDir, func(path string, d os.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				names = append(names, d.Name())
+			}
+			return err
+		}), test.ShouldBeNil)
+		return names
+	}
+
+	t.Run("does nothing under the quota", func(t *testing.T) {
+		captureDir := writeFiles(t)
+		n, bytes, err := enforceCaptureDirQuota(context.Background(), newFileTracker(),
+			Config{CaptureDir: captureDir, CaptureDirQuotaBytes: 600}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, n, test.ShouldEqual, 0)
+		test.That(t, bytes, test.ShouldEqual, 0)
+		test.That(t, remaining(t, captureDir), test.ShouldHaveLength, 6)
+	})
+
+	t.Run("evicts the oldest files first", func(t *testing.T) {
+		captureDir := writeFiles(t)
+		n, bytes, err := enforceCaptureDirQuota(context.Background(), newFileTracker(),
+			Config{CaptureDir: captureDir, CaptureDirQuotaBytes: 350}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, n, test.ShouldEqual, 3)
+		test.That(t, bytes, test.ShouldEqual, 300)
+		test.That(t, remaining(t, captureDir), test.ShouldResemble, []string{"5.prog", "ReadImage0.capture", "ReadImage1.capture"})
+	})
+
+	t.Run("evicts the lowest priority files first", func(t *testing.T) {
+		captureDir := writeFiles(t)
+		config := Config{
+			CaptureDir:           captureDir,
+			CaptureDirQuotaBytes: 350,
+			EvictionPolicy:       EvictionPolicyLowestPriorityFirst,
+			CapturePriorities:    map[string]int{"gps": 1, "cam/ReadImage": -1},
+		}
+		n, _, err := enforceCaptureDirQuota(context.Background(), newFileTracker(), config, logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, n, test.ShouldEqual, 3)
+		test.That(t, remaining(t, captureDir), test.ShouldResemble, []string{"5.prog", "Position3.capture", "Position4.capture"})
+	})
+
+	t.Run("skips files being synced", func(t *testing.T) {
+		captureDir := writeFiles(t)
+		ft := newFileTracker()
+		inProgress := filepath.Join(captureDir, gps, "Position4.capture")
+		test.That(t, ft.markInProgress(inProgress), test.ShouldBeTrue)
+		n, _, err := enforceCaptureDirQuota(context.Background(), ft,
+			Config{CaptureDir: captureDir, CaptureDirQuotaBytes: 350}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, n, test.ShouldEqual, 3)
+		test.That(t, remaining(t, captureDir), test.ShouldResemble, []string{"5.prog", "ReadImage0.capture", "Position4.capture"})
+		test.That(t, ft.inProgress(inProgress), test.ShouldBeTrue)
+	})
+}
+
+func TestValidateEvictionPolicy(t *testing.T) {
+	test.That(t, ValidateEvictionPolicy(""), test.ShouldBeNil)
+	test.That(t, ValidateEvictionPolicy(EvictionPolicyOldestFirst), test.ShouldBeNil)
+	test.That(t, ValidateEvictionPolicy(EvictionPolicyLowestPriorityFirst), test.ShouldBeNil)
+	test.That(t, ValidateEvictionPolicy("newest_first"), test.ShouldNotBeNil)
+}
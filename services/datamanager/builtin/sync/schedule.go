@@ -0,0 +1,191 @@
+package sync
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// syncWindowLayout is the layout of the start and end of sync windows, in local time.
+const syncWindowLayout = "15:04"
+
+// SyncWindow is a daily time window, in local time, during which scheduled sync may run. Windows whose
+// end is before their start span midnight, such as 22:00 to 02:00.
+type SyncWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Validate returns an error if the start or end of the window are not times of day such as "01:00".
+func (w SyncWindow) Validate() error {
+	start, err := time.Parse(syncWindowLayout, w.Start)
+	if err != nil {
+		return errors.Errorf("invalid sync window start %q, must be a time such as \"01:00\"", w.Start)
+	}
+	end, err := time.Parse(syncWindowLayout, w.End)
+	if err != nil {
+		return errors.Errorf("invalid sync window end %q, must be a time such as \"05:00\"", w.End)
+	}
+	if start.Equal(end) {
+		return errors.Errorf("sync window from %s to %s is empty", w.Start, w.End)
+	}
+	return nil
+}
+
+// minutes returns the start and end of the window in minutes since midnight.
+func (w SyncWindow) minutes() (int, int) {
+	//nolint:errcheck
+	start, _ := time.Parse(syncWindowLayout, w.Start)
+	//nolint:errcheck
+	end, _ := time.Parse(syncWindowLayout, w.End)
+	return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute()
+}
+
+// syncWindowEnd returns whether now is in one of the windows and, if so, when the latest ending window
+// containing now ends. Without windows, sync is always allowed and the end is zero.
+func syncWindowEnd(windows []SyncWindow, now time.Time) (bool, time.Time) {
+	if len(windows) == 0 {
+		return true, time.Time{}
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	nowMinutes := int(now.Sub(midnight) / time.Minute)
+	var in bool
+	var end time.Time
+	for _, w := range windows {
+		start, stop := w.minutes()
+		var windowEnd time.Time
+		switch {
+		case start < stop && nowMinutes >= start && nowMinutes < stop:
+			windowEnd = midnight.Add(time.Duration(stop) * time.Minute)
+		case start > stop && nowMinutes >= start:
+			windowEnd = midnight.AddDate(0, 0, 1).Add(time.Duration(stop) * time.Minute)
+		case start > stop && nowMinutes < stop:
+			windowEnd = midnight.Add(time.Duration(stop) * time.Minute)
+		default:
+			continue
+		}
+		in = true
+		if windowEnd.After(end) {
+			end = windowEnd
+		}
+	}
+	return in, end
+}
+
+// networkInterfaceUp returns whether the named network interface is up and has an address. It is a
+// variable so that tests can replace it.
+var networkInterfaceUp = func(name string) bool {
+	iface, err := net.InterfaceByName(name)
+	if err != nil || iface.Flags&net.FlagUp == 0 {
+		return false
+	}
+	addrs, err := iface.Addrs()
+	return err == nil && len(addrs) > 0
+}
+
+// anyNetworkInterfaceUp returns whether any of the named network interfaces is up, or true if there are none.
+func anyNetworkInterfaceUp(names []string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, name := range names {
+		if networkInterfaceUp(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// newUploadLimiter returns a limiter of the upload bandwidth to bytesPerSec, or nil if it is not positive.
+// The burst allows a full second of bandwidth so that idle workers can catch up.
+func newUploadLimiter(bytesPerSec int64) *rate.Limiter {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(bytesPerSec), int(bytesPerSec))
+}
+
+// waitToUpload blocks until the limiter allows size bytes to be uploaded, in bursts of at most the
+// limiter's burst so that messages larger than it are not rejected.
+func waitToUpload(ctx context.Context, limiter *rate.Limiter, size int) error {
+	for size > 0 {
+		n := min(size, limiter.Burst())
+		if err := limiter.WaitN(ctx, n); err != nil {
+			return err
+		}
+		size -= n
+	}
+	return nil
+}
+
+// throttledClient is a v1.DataSyncServiceClient whose uploads share the bandwidth of a limiter.
+type throttledClient struct {
+	v1.DataSyncServiceClient
+	limiter *rate.Limiter
+}
+
+func (c throttledClient) DataCaptureUpload(
+	ctx context.Context,
+	in *v1.DataCaptureUploadRequest,
+	opts ...grpc.CallOption,
+) (*v1.DataCaptureUploadResponse, error) {
+	if err := waitToUpload(ctx, c.limiter, proto.Size(in)); err != nil {
+		return nil, err
+	}
+	return c.DataSyncServiceClient.DataCaptureUpload(ctx, in, opts...)
+}
+
+func (c throttledClient) FileUpload(ctx context.Context, opts ...grpc.CallOption) (v1.DataSyncService_FileUploadClient, error) {
+	stream, err := c.DataSyncServiceClient.FileUpload(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return throttledFileUploadClient{DataSyncService_FileUploadClient: stream, ctx: ctx, limiter: c.limiter}, nil
+}
+
+func (c throttledClient) StreamingDataCaptureUpload(
+	ctx context.Context,
+	opts ...grpc.CallOption,
+) (v1.DataSyncService_StreamingDataCaptureUploadClient, error) {
+	stream, err := c.DataSyncServiceClient.StreamingDataCaptureUpload(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return throttledStreamingDataCaptureUploadClient{
+		DataSyncService_StreamingDataCaptureUploadClient: stream,
+		ctx:     ctx,
+		limiter: c.limiter,
+	}, nil
+}
+
+type throttledFileUploadClient struct {
+	v1.DataSyncService_FileUploadClient
+	ctx     context.Context
+	limiter *rate.Limiter
+}
+
+func (c throttledFileUploadClient) Send(req *v1.FileUploadRequest) error {
+	if err := waitToUpload(c.ctx, c.limiter, proto.Size(req)); err != nil {
+		return err
+	}
+	return c.DataSyncService_FileUploadClient.Send(req)
+}
+
+type throttledStreamingDataCaptureUploadClient struct {
+	v1.DataSyncService_StreamingDataCaptureUploadClient
+	ctx     context.Context
+	limiter *rate.Limiter
+}
+
+func (c throttledStreamingDataCaptureUploadClient) Send(req *v1.StreamingDataCaptureUploadRequest) error {
+	if err := waitToUpload(c.ctx, c.limiter, proto.Size(req)); err != nil {
+		return err
+	}
+	return c.DataSyncService_StreamingDataCaptureUploadClient.Send(req)
+}
@@ -0,0 +1,109 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSyncWindowEnd(t *testing.T) {
+	day := func(hour, minute int) time.Time {
+		return time.Date(2024, 6, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	in, end := syncWindowEnd(nil, day(12, 0))
+	test.That(t, in, test.ShouldBeTrue)
+	test.That(t, end.IsZero(), test.ShouldBeTrue)
+
+	night := []SyncWindow{{Start: "01:00", End: "05:00"}}
+	in, end = syncWindowEnd(night, day(0, 59))
+	test.That(t, in, test.ShouldBeFalse)
+	in, end = syncWindowEnd(night, day(1, 0))
+	test.That(t, in, test.ShouldBeTrue)
+	test.That(t, end, test.ShouldEqual, day(5, 0))
+	in, _ = syncWindowEnd(night, day(5, 0))
+	test.That(t, in, test.ShouldBeFalse)
+
+	// windows spanning midnight
+	overnight := []SyncWindow{{Start: "22:00", End: "02:00"}}
+	in, end = syncWindowEnd(overnight, day(23, 30))
+	test.That(t, in, test.ShouldBeTrue)
+	test.That(t, end, test.ShouldEqual, day(2, 0).AddDate(0, 0, 1))
+	in, end = syncWindowEnd(overnight, day(1, 30))
+	test.That(t, in, test.ShouldBeTrue)
+	test.That(t, end, test.ShouldEqual, day(2, 0))
+	in, _ = syncWindowEnd(overnight, day(12, 0))
+	test.That(t, in, test.ShouldBeFalse)
+
+	// the latest ending of overlapping windows
+	in, end = syncWindowEnd(append(night, SyncWindow{Start: "04:00", End: "06:30"}), day(4, 15))
+	test.That(t, in, test.ShouldBeTrue)
+	test.That(t, end, test.ShouldEqual, day(6, 30))
+}
+
+func TestAnyNetworkInterfaceUp(t *testing.T) {
+	orig := networkInterfaceUp
+	defer func() { networkInterfaceUp = orig }()
+	networkInterfaceUp = func(name string) bool { return name == "eth0" }
+
+	test.That(t, anyNetworkInterfaceUp(nil), test.ShouldBeTrue)
+	test.That(t, anyNetworkInterfaceUp([]string{"eth0"}), test.ShouldBeTrue)
+	test.That(t, anyNetworkInterfaceUp([]string{"wlan0", "eth0"}), test.ShouldBeTrue)
+	test.That(t, anyNetworkInterfaceUp([]string{"wlan0"}), test.ShouldBeFalse)
+}
+
+func TestThrottledClient(t *testing.T) {
+	test.That(t, newUploadLimiter(0), test.ShouldBeNil)
+
+	var sent int
+	client := throttledClient{
+		DataSyncServiceClient: &MockDataSyncServiceClient{
+			T: t,
+			FileUploadFunc: func(context.Context, ...grpc.CallOption) (v1.DataSyncService_FileUploadClient, error) {
+				return &ClientStreamingMock[*v1.FileUploadRequest, *v1.FileUploadResponse]{
+					T: t,
+					SendFunc: func(req *v1.FileUploadRequest) error {
+						sent += proto.Size(req)
+						return nil
+					},
+				}, nil
+			},
+		},
+		limiter: newUploadLimiter(20000),
+	}
+
+	ctx := context.Background()
+	stream, err := client.FileUpload(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	chunk := &v1.FileUploadRequest{UploadPacket: &v1.FileUploadRequest_FileContents{
+		FileContents: &v1.FileData{Data: make([]byte, 5000)},
+	}}
+	// the first second of bandwidth is the burst, the rest must wait
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		test.That(t, stream.Send(chunk), test.ShouldBeNil)
+	}
+	test.That(t, sent, test.ShouldBeGreaterThan, 30000)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThan, 400*time.Millisecond)
+
+	// messages larger than the burst are sent in several waits rather than rejected
+	client.limiter = newUploadLimiter(100000)
+	stream, err = client.FileUpload(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	large := &v1.FileUploadRequest{UploadPacket: &v1.FileUploadRequest_FileContents{
+		FileContents: &v1.FileData{Data: make([]byte, 150000)},
+	}}
+	test.That(t, stream.Send(large), test.ShouldBeNil)
+
+	// waiting stops once the upload is cancelled
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	stream, err = client.FileUpload(cancelCtx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stream.Send(chunk), test.ShouldBeError, context.Canceled)
+}
@@ -17,6 +17,10 @@ type atomicUploadStats struct {
 	binary    atomicStat
 	tabular   atomicStat
 	arbitrary atomicStat
+	// evictedFileCount and evictedBytes count the capture files deleted to keep the
+	// capture directory under its quota.
+	evictedFileCount atomic.Uint64
+	evictedBytes     atomic.Uint64
 }
 
 type atomicStat struct {
@@ -26,9 +30,11 @@ type atomicStat struct {
 }
 
 type uploadStats struct {
-	binary    stat
-	tabular   stat
-	arbitrary stat
+	binary           stat
+	tabular          stat
+	arbitrary        stat
+	evictedFileCount uint64
+	evictedBytes     uint64
 }
 
 type stat struct {
@@ -77,9 +83,11 @@ func (sw *statsWorker) close() {
 
 func newUploadStats(stats *atomicUploadStats) uploadStats {
 	return uploadStats{
-		binary:    newStat(&stats.binary),
-		tabular:   newStat(&stats.tabular),
-		arbitrary: newStat(&stats.arbitrary),
+		binary:           newStat(&stats.binary),
+		tabular:          newStat(&stats.tabular),
+		arbitrary:        newStat(&stats.arbitrary),
+		evictedFileCount: stats.evictedFileCount.Load(),
+		evictedBytes:     stats.evictedBytes.Load(),
 	}
 }
 
@@ -100,6 +108,11 @@ func summary(oldState, newState uploadStats, interval time.Duration) []string {
 	summary = summarizeStat(summary, "arbitrary", oldState.arbitrary, newState.arbitrary, interval)
 	summary = summarizeStat(summary, "binary", oldState.binary, newState.binary, interval)
 	summary = summarizeStat(summary, "tabular", oldState.tabular, newState.tabular, interval)
+	if newState.evictedFileCount != 0 {
+		summary = append(summary, fmt.Sprintf("total evicted to stay under quota: %d files, %s, rate: %s/sec",
+			newState.evictedFileCount, data.FormatBytesU64(newState.evictedBytes),
+			bytesPerSecond(newState.evictedBytes-oldState.evictedBytes, interval)))
+	}
 	return summary
 }
 
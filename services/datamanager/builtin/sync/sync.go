@@ -17,6 +17,7 @@ import (
 	v1 "go.viam.com/api/app/datasync/v1"
 	goutils "go.viam.com/utils"
 	"go.viam.com/utils/rpc"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 
@@ -77,6 +78,9 @@ type Sync struct {
 	// FileDeletingWorkers is only public for tests
 	FileDeletingWorkers *goutils.StoppableWorkers
 	statsWorker         *statsWorker
+	// uploadLimiter caps the upload bandwidth of the workers, if configured. It is only set while the
+	// workers are stopped.
+	uploadLimiter *rate.Limiter
 	// MaxSyncThreads only exists for tests
 	MaxSyncThreads int
 }
@@ -154,6 +158,7 @@ func (s *Sync) Reconfigure(_ context.Context, config Config, cloudConnSvc cloud.
 	s.configCtx, s.configCancelFunc = context.WithCancel(context.Background())
 
 	// start workers
+	s.uploadLimiter = newUploadLimiter(config.MaximumUploadBytesPerSec)
 	s.startWorkers(config)
 	if config.schedulerEnabled() {
 		// time.Duration loses precision at low floating point values, so turn intervalMins to milliseconds.
@@ -184,6 +189,12 @@ func (s *Sync) Reconfigure(_ context.Context, config Config, cloudConnSvc cloud.
 				s.logger,
 			)
 		})
+		// the quota applies regardless of how full the disk is
+		if config.CaptureDirQuotaBytes > 0 {
+			s.FileDeletingWorkers.Add(func(ctx context.Context) {
+				enforceCaptureDirQuotaOnSchedule(ctx, s.fileTracker, config, s.atomicUploadStats, s.clock, s.logger)
+			})
+		}
 	}
 }
 
@@ -386,7 +397,7 @@ func (s *Sync) syncDataCaptureFile(f *os.File, captureDir string, logger logging
 	retry := newExponentialRetry(s.configCtx, s.clock, s.logger, f.Name(), func(ctx context.Context) (uint64, error) {
 		msg := "error uploading data capture file %s, size: %s, md: %s"
 		errMetadata := fmt.Sprintf(msg, captureFile.GetPath(), data.FormatBytesI64(captureFile.Size()), captureFile.ReadMetadata())
-		bytesUploaded, err := uploadDataCaptureFile(ctx, captureFile, s.cloudConn, logger)
+		bytesUploaded, err := uploadDataCaptureFile(ctx, captureFile, s.uploadConn(), logger)
 		if err != nil {
 			return 0, errors.Wrap(err, errMetadata)
 		}
@@ -435,7 +446,7 @@ func (s *Sync) syncDataCaptureFile(f *os.File, captureDir string, logger logging
 func (s *Sync) syncArbitraryFile(f *os.File, tags []string, fileLastModifiedMillis int, logger logging.Logger) {
 	retry := newExponentialRetry(s.configCtx, s.clock, s.logger, f.Name(), func(ctx context.Context) (uint64, error) {
 		errMetadata := fmt.Sprintf("error uploading arbitrary file %s", f.Name())
-		bytesUploaded, err := uploadArbitraryFile(ctx, f, s.cloudConn, tags, fileLastModifiedMillis, s.clock, logger)
+		bytesUploaded, err := uploadArbitraryFile(ctx, f, s.uploadConn(), tags, fileLastModifiedMillis, s.clock, logger)
 		if err != nil {
 			return 0, errors.Wrap(err, errMetadata)
 		}
@@ -474,6 +485,15 @@ func (s *Sync) syncArbitraryFile(f *os.File, tags []string, fileLastModifiedMill
 	s.atomicUploadStats.arbitrary.uploadedBytes.Add(bytesUploaded)
 }
 
+// uploadConn returns the cloud connection to upload with, throttled if the upload bandwidth is capped.
+func (s *Sync) uploadConn() cloudConn {
+	conn := s.cloudConn
+	if s.uploadLimiter != nil {
+		conn.client = throttledClient{DataSyncServiceClient: conn.client, limiter: s.uploadLimiter}
+	}
+	return conn
+}
+
 // moveFailedData takes any data that could not be synced in the parentDir and
 // moves it to a new subdirectory "failed" that will not be synced.
 func moveFailedData(path, parentDir string, cause error, logger logging.Logger) error {
@@ -537,13 +557,36 @@ func (s *Sync) runScheduler(ctx context.Context, tkr *clock.Ticker, config Confi
 				continue
 			}
 
-			if err := s.walkDirsAndSendFilesToSync(ctx, config); err != nil && !errors.Is(err, context.Canceled) {
+			inWindow, windowEnd := syncWindowEnd(config.SyncWindows, s.clock.Now())
+			if !inWindow {
+				s.logger.Debug("data manager: NOT syncing data to the cloud as it's outside of its sync windows")
+				continue
+			}
+
+			if !anyNetworkInterfaceUp(config.SyncNetworkInterfaces) {
+				s.logger.Debugf("data manager: NOT syncing data to the cloud as none of its sync network interfaces %v are up",
+					config.SyncNetworkInterfaces)
+				continue
+			}
+
+			if err := s.walkDirsInWindow(ctx, config, windowEnd); err != nil &&
+				!errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
 				goutils.UncheckedError(err)
 			}
 		}
 	}
 }
 
+// walkDirsInWindow sends files to sync until the sync window ends at windowEnd, if not zero.
+func (s *Sync) walkDirsInWindow(ctx context.Context, config Config, windowEnd time.Time) error {
+	if windowEnd.IsZero() {
+		return s.walkDirsAndSendFilesToSync(ctx, config)
+	}
+	windowCtx, cancel := s.clock.WithDeadline(ctx, windowEnd)
+	defer cancel()
+	return s.walkDirsAndSendFilesToSync(windowCtx, config)
+}
+
 // returns early with an error if either ctx is cancelled or if the reconfigure is called
 // while walkDirsAndSendFilesToSync.
 func (s *Sync) walkDirsAndSendFilesToSync(ctx context.Context, config Config) error {
@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 	v1 "go.viam.com/api/app/datasync/v1"
 
+	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/logging"
 )
 
@@ -76,18 +77,30 @@ func uploadArbitraryFile(
 		return 0, errors.Wrap(err, "error creating FileUpload client")
 	}
 
+	md := &v1.UploadMetadata{
+		PartId:        conn.partID,
+		Type:          v1.DataType_DATA_TYPE_FILE,
+		FileName:      path,
+		FileExtension: filepath.Ext(f.Name()),
+		Tags:          tags,
+	}
+	// Parquet capture files are attributed to the resource & method they were captured from.
+	if filepath.Ext(path) == data.ParquetCaptureFileExt {
+		if captureMD, err := data.ReadParquetCaptureMetadata(f, info.Size()); err == nil {
+			md.ComponentType = captureMD.GetComponentType()
+			md.ComponentName = captureMD.GetComponentName()
+			md.MethodName = captureMD.GetMethodName()
+			md.MethodParameters = captureMD.GetMethodParameters()
+			md.Tags = append(captureMD.GetTags(), tags...)
+		} else {
+			logger.Debugw("failed to read capture metadata of parquet file, syncing it as an arbitrary file", "file", path, "error", err)
+		}
+	}
+
 	// Send metadata FileUploadRequest.
 	logger.Debugf("datasync.FileUpload request sending metadata for arbitrary file: %s", path)
 	if err := stream.Send(&v1.FileUploadRequest{
-		UploadPacket: &v1.FileUploadRequest_Metadata{
-			Metadata: &v1.UploadMetadata{
-				PartId:        conn.partID,
-				Type:          v1.DataType_DATA_TYPE_FILE,
-				FileName:      path,
-				FileExtension: filepath.Ext(f.Name()),
-				Tags:          tags,
-			},
-		},
+		UploadPacket: &v1.FileUploadRequest_Metadata{Metadata: md},
 	}); err != nil {
 		return 0, errors.Wrap(err, "FileUpload failed sending metadata")
 	}
@@ -0,0 +1,40 @@
+package datamanager
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/data"
+)
+
+// CaptureAggregation reduces the tabular readings of a capture method over each interval to a
+// single reading before they are written, such as to save the mean, minimum and maximum of a high
+// rate sensor every few seconds rather than every reading:
+//
+//	"aggregation": {"interval_secs": 10, "functions": ["mean", "min", "max"]}
+//
+// Every number of the readings is reduced with each function, and other values keep their last
+// value. Functions default to mean, min and max.
+type CaptureAggregation struct {
+	IntervalSecs float64  `json:"interval_secs"`
+	Functions    []string `json:"functions,omitempty"`
+}
+
+// Validate returns an error if the aggregation is invalid.
+func (a CaptureAggregation) Validate() error {
+	if a.IntervalSecs <= 0 {
+		return errors.Errorf("aggregation interval_secs must be positive, got %v", a.IntervalSecs)
+	}
+	for _, fn := range a.Functions {
+		if err := data.ValidateAggregateFunction(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Interval returns the interval readings are reduced over.
+func (a CaptureAggregation) Interval() time.Duration {
+	return time.Duration(a.IntervalSecs * float64(time.Second))
+}
@@ -0,0 +1,70 @@
+package datamanager
+
+import (
+	"github.com/pkg/errors"
+)
+
+// The operators a CaptureCondition can compare a field of a reading with.
+const (
+	OperatorEqual              = "=="
+	OperatorNotEqual           = "!="
+	OperatorGreaterThan        = ">"
+	OperatorGreaterThanOrEqual = ">="
+	OperatorLessThan           = "<"
+	OperatorLessThanOrEqual    = "<="
+)
+
+// CaptureCondition gates the data a capture method saves. A condition either compares a field of
+// the captured reading with a value, or requires a vision service to detect an object.
+//
+// For example, to only save GPS positions while moving, or only save images with a person in them:
+//
+//	"conditions": [{"field": "readings.speed_mps", "operator": ">", "value": 0}]
+//	"conditions": [{"vision_service": "person-detector", "label": "person", "min_confidence": 0.6}]
+type CaptureCondition struct {
+	// Field is the dot separated path of the compared field of the captured reading, such as
+	// "readings.temperature". Readings without the field are not saved.
+	Field    string      `json:"field,omitempty"`
+	Operator string      `json:"operator,omitempty"`
+	Value    interface{} `json:"value,omitempty"`
+
+	// VisionService is the name of the vision service that must detect an object. It detects on the
+	// captured image, or on the next image from Camera if the captured data isn't an image.
+	VisionService string `json:"vision_service,omitempty"`
+	Camera        string `json:"camera,omitempty"`
+	// Label is the label of the object that must be detected. Any object is enough if it is unset.
+	Label         string  `json:"label,omitempty"`
+	MinConfidence float64 `json:"min_confidence,omitempty"`
+}
+
+// Validate returns an error if the condition is invalid.
+func (c CaptureCondition) Validate() error {
+	switch {
+	case c.Field != "" && c.VisionService != "":
+		return errors.New("a capture condition cannot have both a field and a vision_service")
+	case c.Field != "":
+		switch c.Value.(type) {
+		case float64, string, bool:
+		case nil:
+			return errors.Errorf("capture condition on %q needs a value", c.Field)
+		default:
+			return errors.Errorf("capture condition on %q can only compare with a number, string or bool", c.Field)
+		}
+		switch c.Operator {
+		case OperatorEqual, OperatorNotEqual:
+		case OperatorGreaterThan, OperatorGreaterThanOrEqual, OperatorLessThan, OperatorLessThanOrEqual:
+			if _, ok := c.Value.(float64); !ok {
+				return errors.Errorf("capture condition on %q can only compare numbers with %q", c.Field, c.Operator)
+			}
+		default:
+			return errors.Errorf("capture condition on %q has unknown operator %q", c.Field, c.Operator)
+		}
+	case c.VisionService != "":
+		if c.MinConfidence < 0 || c.MinConfidence > 1 {
+			return errors.Errorf("capture condition min_confidence must be between 0 and 1, got %v", c.MinConfidence)
+		}
+	default:
+		return errors.New("a capture condition needs a field or a vision_service")
+	}
+	return nil
+}
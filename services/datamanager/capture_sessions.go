@@ -0,0 +1,224 @@
+package datamanager
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// The keys of the DoCommand requests and responses that start capture sessions. These are reserved
+// by the data manager API and are not passed on to the DoCommand of the service.
+const (
+	StartCaptureSessionCommand       = "start_capture_session"
+	CaptureSessionNameKey            = "name"
+	CaptureSessionDurationSecondsKey = "duration_seconds"
+	CaptureSessionExtraKey           = "extra"
+	CaptureSessionEndsAtKey          = "ends_at"
+)
+
+const (
+	// defaultTriggerPollFrequencyHz is how often capture session triggers are checked by default.
+	defaultTriggerPollFrequencyHz = 10.
+	// defaultVideoClipFrameRateHz is the default frame rate of video clips.
+	defaultVideoClipFrameRateHz = 10.
+	// maxVideoClipFrameRateHz is the highest supported frame rate of video clips.
+	maxVideoClipFrameRateHz = 60.
+)
+
+// CaptureSessionConfig describes a capture session: a set of capture methods which only capture
+// for a bounded time after the session is started, typically at a higher rate than regular capture,
+// to record what happened around an incident. Sessions are started with StartCaptureSession or when
+// their trigger fires. For example:
+//
+//	"capture_sessions": [{
+//	  "name": "bump",
+//	  "duration_seconds": 30,
+//	  "capture_methods": [
+//	    {"name": "rdk:component:camera/front", "method": "ReadImage", "capture_frequency_hz": 10},
+//	    {"name": "rdk:component:movement_sensor/imu", "method": "LinearAcceleration", "capture_frequency_hz": 200}
+//	  ],
+//	  "video_clips": [{"camera": "front", "frame_rate_hz": 15, "pre_roll_seconds": 10}],
+//	  "trigger": {"board": "pi", "pin": "37"}
+//	}]
+type CaptureSessionConfig struct {
+	Name            string              `json:"name"`
+	DurationSeconds float64             `json:"duration_seconds"`
+	CaptureMethods  []DataCaptureConfig `json:"capture_methods"`
+	// VideoClips are video clips recorded around the session, which are written to the capture
+	// directory as .avi files and synced like other files.
+	VideoClips []VideoClipConfig `json:"video_clips,omitempty"`
+	// Trigger, if set, starts the session when it fires.
+	Trigger *CaptureSessionTrigger `json:"trigger,omitempty"`
+}
+
+// VideoClipConfig records a video clip of a camera for a capture session, from PreRollSeconds
+// before the session starts until it ends. To have the pre-roll available, the camera is
+// continuously buffered at FrameRateHz, which uses memory for PreRollSeconds of JPEG images.
+type VideoClipConfig struct {
+	Camera string `json:"camera"`
+	// FrameRateHz defaults to 10.
+	FrameRateHz    float64 `json:"frame_rate_hz,omitempty"`
+	PreRollSeconds float64 `json:"pre_roll_seconds,omitempty"`
+}
+
+// FrameInterval returns the interval at which frames of the clip are recorded.
+func (c VideoClipConfig) FrameInterval() time.Duration {
+	hz := c.FrameRateHz
+	if hz <= 0 {
+		hz = defaultVideoClipFrameRateHz
+	}
+	return time.Duration(float64(time.Second) / hz)
+}
+
+// PreRoll returns how long before the session starts the clip starts.
+func (c VideoClipConfig) PreRoll() time.Duration {
+	return time.Duration(c.PreRollSeconds * float64(time.Second))
+}
+
+// CaptureSessionTrigger starts a capture session when a digital input of a board goes high, or when
+// a condition on the readings of a sensor starts to hold.
+type CaptureSessionTrigger struct {
+	Board string `json:"board,omitempty"`
+	Pin   string `json:"pin,omitempty"`
+
+	// Condition is a field condition on the readings of Sensor, such as
+	// {"field": "readings.bumped", "operator": "==", "value": true}.
+	Sensor    string            `json:"sensor,omitempty"`
+	Condition *CaptureCondition `json:"condition,omitempty"`
+
+	// PollFrequencyHz is how often the trigger is checked. Defaults to 10.
+	PollFrequencyHz float64 `json:"poll_frequency_hz,omitempty"`
+}
+
+// PollInterval returns the interval at which the trigger is checked.
+func (t CaptureSessionTrigger) PollInterval() time.Duration {
+	hz := t.PollFrequencyHz
+	if hz <= 0 {
+		hz = defaultTriggerPollFrequencyHz
+	}
+	return time.Duration(float64(time.Second) / hz)
+}
+
+// Duration returns how long the session captures for once started.
+func (c CaptureSessionConfig) Duration() time.Duration {
+	return time.Duration(c.DurationSeconds * float64(time.Second))
+}
+
+// Validate returns an error if the capture session is invalid.
+func (c CaptureSessionConfig) Validate() error {
+	if c.Name == "" {
+		return errors.New("capture session needs a name")
+	}
+	if c.DurationSeconds <= 0 {
+		return errors.Errorf("capture session %q duration_seconds must be positive", c.Name)
+	}
+	if len(c.CaptureMethods) == 0 && len(c.VideoClips) == 0 {
+		return errors.Errorf("capture session %q needs capture_methods or video_clips", c.Name)
+	}
+	for _, clip := range c.VideoClips {
+		if clip.Camera == "" {
+			return errors.Errorf("capture session %q video clips need a camera", c.Name)
+		}
+		if clip.FrameRateHz < 0 || clip.FrameRateHz > maxVideoClipFrameRateHz {
+			return errors.Errorf("capture session %q video clip frame_rate_hz must be between 0 and %v", c.Name, maxVideoClipFrameRateHz)
+		}
+		if clip.PreRollSeconds < 0 {
+			return errors.Errorf("capture session %q video clip pre_roll_seconds can't be negative", c.Name)
+		}
+	}
+	for _, method := range c.CaptureMethods {
+		if method.Method == "" || method.Name.Name == "" {
+			return errors.Errorf("capture session %q capture methods need a name and a method", c.Name)
+		}
+		if method.CaptureFrequencyHz <= 0 {
+			return errors.Errorf("capture session %q capture method %s/%s needs a positive capture_frequency_hz",
+				c.Name, method.Name, method.Method)
+		}
+	}
+	if c.Trigger == nil {
+		return nil
+	}
+	switch t := c.Trigger; {
+	case t.Board != "" && t.Sensor != "":
+		return errors.Errorf("capture session %q trigger cannot have both a board and a sensor", c.Name)
+	case t.Board != "":
+		if t.Pin == "" {
+			return errors.Errorf("capture session %q trigger needs the pin of board %q", c.Name, t.Board)
+		}
+	case t.Sensor != "":
+		if t.Condition == nil || t.Condition.Field == "" {
+			return errors.Errorf("capture session %q trigger needs a condition on a field of the readings of %q", c.Name, t.Sensor)
+		}
+		if err := t.Condition.Validate(); err != nil {
+			return errors.Wrapf(err, "capture session %q trigger", c.Name)
+		}
+	default:
+		return errors.Errorf("capture session %q trigger needs a board or a sensor", c.Name)
+	}
+	return nil
+}
+
+// CaptureSessionStarter is implemented by data manager services which support capture sessions.
+//
+// StartCaptureSession example:
+//
+//	starter, ok := myDataManager.(datamanager.CaptureSessionStarter)
+//	if ok {
+//		// Capture the configured "bump" session for 10 seconds instead of its configured duration.
+//		endsAt, err := starter.StartCaptureSession(context.Background(), "bump", 10*time.Second, nil)
+//	}
+type CaptureSessionStarter interface {
+	// StartCaptureSession starts the configured capture session with the given name, or extends it if
+	// it is already running, and returns when it will end. A zero duration uses the configured one.
+	StartCaptureSession(ctx context.Context, name string, duration time.Duration, extra map[string]interface{}) (time.Time, error)
+}
+
+// StartCaptureSession starts a capture session of svc over DoCommand.
+func (c *client) StartCaptureSession(
+	ctx context.Context, name string, duration time.Duration, extra map[string]interface{},
+) (time.Time, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		StartCaptureSessionCommand:       true,
+		CaptureSessionNameKey:            name,
+		CaptureSessionDurationSecondsKey: duration.Seconds(),
+		CaptureSessionExtraKey:           extra,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	endsAt, ok := resp[CaptureSessionEndsAtKey].(string)
+	if !ok {
+		return time.Time{}, errors.New("start_capture_session response has no ends_at")
+	}
+	return time.Parse(time.RFC3339Nano, endsAt)
+}
+
+// doStartCaptureSessionCommand handles the reserved start_capture_session DoCommand. It returns
+// false if req is not a start_capture_session request.
+func doStartCaptureSessionCommand(
+	ctx context.Context, svc Service, req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	cmd := req.GetCommand().AsMap()
+	if _, ok := cmd[StartCaptureSessionCommand]; !ok {
+		return nil, false, nil
+	}
+	starter, ok := svc.(CaptureSessionStarter)
+	if !ok {
+		return nil, true, errors.Errorf("data manager %q does not support capture sessions", svc.Name().ShortName())
+	}
+	name, _ := cmd[CaptureSessionNameKey].(string)                   //nolint:errcheck
+	seconds, _ := cmd[CaptureSessionDurationSecondsKey].(float64)    //nolint:errcheck
+	extra, _ := cmd[CaptureSessionExtraKey].(map[string]interface{}) //nolint:errcheck
+	endsAt, err := starter.StartCaptureSession(ctx, name, time.Duration(seconds*float64(time.Second)), extra)
+	if err != nil {
+		return nil, true, err
+	}
+	result, err := structpb.NewStruct(map[string]interface{}{CaptureSessionEndsAtKey: endsAt.Format(time.RFC3339Nano)})
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: result}, true, nil
+}
@@ -0,0 +1,151 @@
+package datamanager_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/components/camera"
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/testutils"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestCaptureSessionConfigValidate(t *testing.T) {
+	methods := []datamanager.DataCaptureConfig{
+		{Name: camera.Named("front"), Method: "ReadImage", CaptureFrequencyHz: 10},
+	}
+	valid := datamanager.CaptureSessionConfig{Name: "bump", DurationSeconds: 30, CaptureMethods: methods}
+	test.That(t, valid.Validate(), test.ShouldBeNil)
+	test.That(t, valid.Duration(), test.ShouldEqual, 30*time.Second)
+
+	for _, tc := range []struct {
+		session datamanager.CaptureSessionConfig
+		err     string
+	}{
+		{datamanager.CaptureSessionConfig{DurationSeconds: 1, CaptureMethods: methods}, "needs a name"},
+		{datamanager.CaptureSessionConfig{Name: "a", CaptureMethods: methods}, "duration_seconds must be positive"},
+		{datamanager.CaptureSessionConfig{Name: "a", DurationSeconds: 1}, "needs capture_methods or video_clips"},
+		{
+			datamanager.CaptureSessionConfig{
+				Name: "a", DurationSeconds: 1,
+				CaptureMethods: []datamanager.DataCaptureConfig{{Name: camera.Named("front"), Method: "ReadImage"}},
+			},
+			"positive capture_frequency_hz",
+		},
+		{
+			datamanager.CaptureSessionConfig{
+				Name: "a", DurationSeconds: 1, VideoClips: []datamanager.VideoClipConfig{{Camera: "front", FrameRateHz: 120}},
+			},
+			"frame_rate_hz must be between 0 and 60",
+		},
+		{
+			datamanager.CaptureSessionConfig{
+				Name: "a", DurationSeconds: 1,
+				VideoClips: []datamanager.VideoClipConfig{{Camera: "front", PreRollSeconds: -1}},
+			},
+			"pre_roll_seconds can't be negative",
+		},
+		{
+			datamanager.CaptureSessionConfig{
+				Name: "a", DurationSeconds: 1, CaptureMethods: methods,
+				Trigger: &datamanager.CaptureSessionTrigger{Board: "pi"},
+			},
+			"needs the pin",
+		},
+		{
+			datamanager.CaptureSessionConfig{
+				Name: "a", DurationSeconds: 1, CaptureMethods: methods,
+				Trigger: &datamanager.CaptureSessionTrigger{Sensor: "bumper"},
+			},
+			"needs a condition",
+		},
+		{
+			datamanager.CaptureSessionConfig{
+				Name: "a", DurationSeconds: 1, CaptureMethods: methods,
+				Trigger: &datamanager.CaptureSessionTrigger{Board: "pi", Pin: "37", Sensor: "bumper"},
+			},
+			"cannot have both",
+		},
+	} {
+		err := tc.session.Validate()
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+	}
+
+	trigger := datamanager.CaptureSessionTrigger{
+		Sensor:    "bumper",
+		Condition: &datamanager.CaptureCondition{Field: "readings.bumped", Operator: datamanager.OperatorEqual, Value: true},
+	}
+	valid.Trigger = &trigger
+	test.That(t, valid.Validate(), test.ShouldBeNil)
+	test.That(t, trigger.PollInterval(), test.ShouldEqual, 100*time.Millisecond)
+
+	clip := datamanager.VideoClipConfig{Camera: "front", PreRollSeconds: 2.5}
+	test.That(t, datamanager.CaptureSessionConfig{
+		Name: "clip", DurationSeconds: 5, VideoClips: []datamanager.VideoClipConfig{clip},
+	}.Validate(), test.ShouldBeNil)
+	test.That(t, clip.FrameInterval(), test.ShouldEqual, 100*time.Millisecond)
+	test.That(t, clip.PreRoll(), test.ShouldEqual, 2500*time.Millisecond)
+}
+
+func TestClientStartCaptureSession(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	endsAt := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	injectDS := inject.NewDataManagerService(testDataManagerServiceName)
+	injectDS.StartCaptureSessionFunc = func(
+		ctx context.Context, name string, duration time.Duration, extra map[string]interface{},
+	) (time.Time, error) {
+		if name != "bump" {
+			return time.Time{}, errors.Errorf("no capture session named %q", name)
+		}
+		test.That(t, duration, test.ShouldEqual, 5*time.Second)
+		test.That(t, extra, test.ShouldResemble, map[string]interface{}{"reason": "test"})
+		return endsAt, nil
+	}
+	injectDS.DoCommandFunc = testutils.EchoFunc
+	svc, err := resource.NewAPIResourceCollection(datamanager.API, map[resource.Name]datamanager.Service{
+		datamanager.Named(testDataManagerServiceName): injectDS,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[datamanager.Service](datamanager.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, svc), test.ShouldBeNil)
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() { test.That(t, conn.Close(), test.ShouldBeNil) }()
+	client, err := datamanager.NewClientFromConn(context.Background(), conn, "", datamanager.Named(testDataManagerServiceName), logger)
+	test.That(t, err, test.ShouldBeNil)
+	starter, ok := client.(datamanager.CaptureSessionStarter)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	got, err := starter.StartCaptureSession(context.Background(), "bump", 5*time.Second, map[string]interface{}{"reason": "test"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, got.Equal(endsAt), test.ShouldBeTrue)
+
+	_, err = starter.StartCaptureSession(context.Background(), "missing", 5*time.Second, map[string]interface{}{"reason": "test"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `no capture session named "missing"`)
+
+	// other commands still reach DoCommand
+	resp, err := client.DoCommand(context.Background(), testutils.TestCommand)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["command"], test.ShouldEqual, testutils.TestCommand["command"])
+}
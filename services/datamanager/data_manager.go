@@ -150,6 +150,10 @@ type DataCaptureConfig struct {
 	Disabled           bool              `json:"disabled"`
 	Tags               []string          `json:"tags,omitempty"`
 	CaptureDirectory   string            `json:"capture_directory"`
+	// Conditions must all hold for a capture to be saved.
+	Conditions []CaptureCondition `json:"conditions,omitempty"`
+	// Aggregation reduces tabular readings over intervals before they are saved.
+	Aggregation *CaptureAggregation `json:"aggregation,omitempty"`
 }
 
 // Equals checks if one capture config is equal to another.
@@ -162,7 +166,9 @@ func (c *DataCaptureConfig) Equals(other *DataCaptureConfig) bool {
 		c.Disabled == other.Disabled &&
 		slices.Compare(c.Tags, other.Tags) == 0 &&
 		reflect.DeepEqual(c.AdditionalParams, other.AdditionalParams) &&
-		c.CaptureDirectory == other.CaptureDirectory
+		c.CaptureDirectory == other.CaptureDirectory &&
+		reflect.DeepEqual(c.Conditions, other.Conditions) &&
+		reflect.DeepEqual(c.Aggregation, other.Aggregation)
 }
 
 // ShouldSyncKey is a special key we use within a modular sensor to pass a boolean
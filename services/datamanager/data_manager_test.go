@@ -2,6 +2,7 @@ package datamanager
 
 import (
 	"testing"
+	"time"
 
 	"go.viam.com/test"
 
@@ -165,6 +166,26 @@ func TestDataCaptureConfig(t *testing.T) {
 			},
 			equal: false,
 		},
+		{
+			name: "different Conditions are not equal",
+			a: &DataCaptureConfig{
+				Conditions: []CaptureCondition{{Field: "readings.a", Operator: OperatorGreaterThan, Value: 1.}},
+			},
+			b: &DataCaptureConfig{
+				Conditions: []CaptureCondition{{Field: "readings.a", Operator: OperatorGreaterThan, Value: 2.}},
+			},
+			equal: false,
+		},
+		{
+			name: "different Aggregation are not equal",
+			a: &DataCaptureConfig{
+				Aggregation: &CaptureAggregation{IntervalSecs: 10},
+			},
+			b: &DataCaptureConfig{
+				Aggregation: &CaptureAggregation{IntervalSecs: 10, Functions: []string{"mean"}},
+			},
+			equal: false,
+		},
 	}
 
 	for _, tc := range tcs {
@@ -174,3 +195,51 @@ func TestDataCaptureConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestCaptureConditionValidate(t *testing.T) {
+	for _, tc := range []struct {
+		cond CaptureCondition
+		err  string
+	}{
+		{CaptureCondition{Field: "readings.speed", Operator: OperatorGreaterThan, Value: 0.}, ""},
+		{CaptureCondition{Field: "readings.state", Operator: OperatorEqual, Value: "moving"}, ""},
+		{CaptureCondition{VisionService: "detector", Label: "person", MinConfidence: 0.5}, ""},
+		{CaptureCondition{}, "needs a field or a vision_service"},
+		{CaptureCondition{Field: "readings.a", VisionService: "detector"}, "cannot have both"},
+		{CaptureCondition{Field: "readings.a", Operator: OperatorEqual}, "needs a value"},
+		{CaptureCondition{Field: "readings.a", Operator: OperatorEqual, Value: []interface{}{}}, "number, string or bool"},
+		{CaptureCondition{Field: "readings.a", Operator: OperatorLessThan, Value: "b"}, "can only compare numbers"},
+		{CaptureCondition{Field: "readings.a", Operator: "=", Value: 1.}, "unknown operator"},
+		{CaptureCondition{VisionService: "detector", MinConfidence: 2}, "min_confidence"},
+	} {
+		err := tc.cond.Validate()
+		if tc.err == "" {
+			test.That(t, err, test.ShouldBeNil)
+		} else {
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+		}
+	}
+}
+
+func TestCaptureAggregationValidate(t *testing.T) {
+	for _, tc := range []struct {
+		aggregation CaptureAggregation
+		err         string
+	}{
+		{CaptureAggregation{IntervalSecs: 10}, ""},
+		{CaptureAggregation{IntervalSecs: 0.5, Functions: []string{"mean", "max"}}, ""},
+		{CaptureAggregation{}, "interval_secs must be positive"},
+		{CaptureAggregation{IntervalSecs: -1}, "interval_secs must be positive"},
+		{CaptureAggregation{IntervalSecs: 10, Functions: []string{"median"}}, "unknown aggregate function"},
+	} {
+		err := tc.aggregation.Validate()
+		if tc.err == "" {
+			test.That(t, err, test.ShouldBeNil)
+		} else {
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+		}
+	}
+	test.That(t, CaptureAggregation{IntervalSecs: 0.5}.Interval(), test.ShouldEqual, 500*time.Millisecond)
+}
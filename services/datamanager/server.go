@@ -42,5 +42,8 @@ func (server *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, ok, err := doStartCaptureSessionCommand(ctx, svc, req); ok {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, svc, req)
 }
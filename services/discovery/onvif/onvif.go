@@ -0,0 +1,167 @@
+// Package onvif implements a discovery service that finds ONVIF network cameras on the local
+// network and surfaces configs for them.
+package onvif
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.viam.com/rdk/components/camera"
+	onvifcamera "go.viam.com/rdk/components/camera/onvif"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/discovery"
+	"go.viam.com/rdk/utils"
+)
+
+// Model is the model of the ONVIF discovery service.
+var Model = resource.DefaultModelFamily.WithModel("onvif")
+
+// defaultTimeout is how long discovery waits for cameras to answer.
+const defaultTimeout = 3 * time.Second
+
+// invalidNameChars matches the characters resource names cannot contain.
+var invalidNameChars = regexp.MustCompile(`[^-\w]+`)
+
+// Config is the config of the ONVIF discovery service.
+type Config struct {
+	// Username and Password are used to find the media profiles of discovered cameras, and are put
+	// in the configs of the cameras.
+	Username  string `json:"username,omitempty"`
+	Password  string `json:"password,omitempty"`
+	TimeoutMs int    `json:"timeout_ms,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.TimeoutMs < 0 {
+		return nil, resource.NewConfigValidationError(path, fmt.Errorf("timeout_ms must not be negative, got %d", cfg.TimeoutMs))
+	}
+	return []string{}, nil
+}
+
+func init() {
+	resource.RegisterService(
+		discovery.API,
+		Model,
+		resource.Registration[discovery.Service, *Config]{Constructor: newDiscovery})
+}
+
+type onvifDiscovery struct {
+	resource.Named
+	resource.AlwaysRebuild
+	resource.TriviallyCloseable
+
+	conf   *Config
+	logger logging.Logger
+	// discover is replaced in tests.
+	discover func(ctx context.Context, timeout time.Duration) ([]onvifcamera.DiscoveredDevice, error)
+}
+
+func newDiscovery(
+	ctx context.Context,
+	_ resource.Dependencies,
+	conf resource.Config,
+	logger logging.Logger,
+) (discovery.Service, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	return &onvifDiscovery{
+		Named:    conf.ResourceName().AsNamed(),
+		conf:     newConf,
+		logger:   logger,
+		discover: onvifcamera.Discover,
+	}, nil
+}
+
+// DiscoverResources probes the local network for ONVIF cameras and returns a camera config for
+// each. If credentials are configured, a config is returned for each media profile of a camera,
+// whose stream URI is found when the camera is built. "timeout_ms" in extra overrides the
+// configured timeout.
+func (dis *onvifDiscovery) DiscoverResources(ctx context.Context, extra map[string]any) ([]resource.Config, error) {
+	timeout := defaultTimeout
+	if dis.conf.TimeoutMs > 0 {
+		timeout = time.Duration(dis.conf.TimeoutMs) * time.Millisecond
+	}
+	if timeoutMs, ok := extra["timeout_ms"].(float64); ok && timeoutMs > 0 {
+		timeout = time.Duration(timeoutMs * float64(time.Millisecond))
+	}
+	devices, err := dis.discover(ctx, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfgs []resource.Config
+	names := map[string]int{}
+	for _, device := range devices {
+		address := device.Addresses[0]
+		name := cameraName(device, names)
+		profiles := dis.profiles(ctx, address)
+		if len(profiles) == 0 {
+			cfgs = append(cfgs, dis.cameraConfig(name, address, ""))
+			continue
+		}
+		for i, profile := range profiles {
+			profileName := name
+			if i > 0 {
+				profileName = fmt.Sprintf("%s-%s", name, invalidNameChars.ReplaceAllString(profile.Token, "_"))
+			}
+			cfgs = append(cfgs, dis.cameraConfig(profileName, address, profile.Token))
+		}
+	}
+	return cfgs, nil
+}
+
+// profiles returns the media profiles of the camera at `address`, or none if they cannot be read.
+func (dis *onvifDiscovery) profiles(ctx context.Context, address string) []onvifcamera.Profile {
+	if dis.conf.Username == "" {
+		return nil
+	}
+	device, err := onvifcamera.Connect(ctx, address, dis.conf.Username, dis.conf.Password)
+	if err != nil {
+		dis.logger.CDebugw(ctx, "failed to connect to discovered ONVIF camera", "address", address, "error", err)
+		return nil
+	}
+	profiles, err := device.Profiles(ctx)
+	if err != nil {
+		dis.logger.CDebugw(ctx, "failed to get profiles of discovered ONVIF camera", "address", address, "error", err)
+		return nil
+	}
+	return profiles
+}
+
+func (dis *onvifDiscovery) cameraConfig(name, address, profile string) resource.Config {
+	attributes := utils.AttributeMap{"address": address}
+	if dis.conf.Username != "" {
+		attributes["username"] = dis.conf.Username
+		attributes["password"] = dis.conf.Password
+	}
+	if profile != "" {
+		attributes["profile"] = profile
+	}
+	return resource.Config{
+		Name:       name,
+		API:        camera.API,
+		Model:      onvifcamera.Model,
+		Attributes: attributes,
+	}
+}
+
+// cameraName returns a unique resource name for a discovered camera, from the name it reports if
+// it has one.
+func cameraName(device onvifcamera.DiscoveredDevice, names map[string]int) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(device.Name, "-"), "-_")
+	if name == "" {
+		name = "onvif-camera"
+	}
+	names[name]++
+	if count := names[name]; count > 1 {
+		return fmt.Sprintf("%s-%d", name, count)
+	}
+	return name
+}
@@ -0,0 +1,58 @@
+package onvif
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	onvifcamera "go.viam.com/rdk/components/camera/onvif"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/discovery"
+	"go.viam.com/rdk/utils"
+)
+
+func TestDiscoverResources(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	conf := resource.Config{
+		Name:                "onvif",
+		API:                 discovery.API,
+		Model:               Model,
+		ConvertedAttributes: &Config{TimeoutMs: 100},
+	}
+	svc, err := newDiscovery(ctx, nil, conf, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	var gotTimeout time.Duration
+	svc.(*onvifDiscovery).discover = func(ctx context.Context, timeout time.Duration) ([]onvifcamera.DiscoveredDevice, error) {
+		gotTimeout = timeout
+		return []onvifcamera.DiscoveredDevice{
+			{Endpoint: "urn:uuid:1", Addresses: []string{"http://192.168.1.10/onvif/device_service"}, Name: "Front Door"},
+			{Endpoint: "urn:uuid:2", Addresses: []string{"http://192.168.1.11/onvif/device_service"}, Name: "Front Door"},
+			{Endpoint: "urn:uuid:3", Addresses: []string{"http://192.168.1.12/onvif/device_service"}},
+		}, nil
+	}
+
+	cfgs, err := svc.DiscoverResources(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, gotTimeout, test.ShouldEqual, 100*time.Millisecond)
+	test.That(t, len(cfgs), test.ShouldEqual, 3)
+	test.That(t, cfgs[0].Name, test.ShouldEqual, "Front-Door")
+	test.That(t, cfgs[1].Name, test.ShouldEqual, "Front-Door-2")
+	test.That(t, cfgs[2].Name, test.ShouldEqual, "onvif-camera")
+	for _, cfg := range cfgs {
+		test.That(t, cfg.API, test.ShouldResemble, camera.API)
+		test.That(t, cfg.Model, test.ShouldResemble, onvifcamera.Model)
+		test.That(t, utils.ValidateResourceName(cfg.Name), test.ShouldBeNil)
+	}
+	test.That(t, cfgs[0].Attributes, test.ShouldResemble, utils.AttributeMap{"address": "http://192.168.1.10/onvif/device_service"})
+
+	_, err = svc.DiscoverResources(ctx, map[string]any{"timeout_ms": 250.})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, gotTimeout, test.ShouldEqual, 250*time.Millisecond)
+}
@@ -4,4 +4,7 @@ package register
 import (
 	// for discovery models.
 	_ "go.viam.com/rdk/services/discovery/fake"
+	_ "go.viam.com/rdk/services/discovery/onvif"
+	_ "go.viam.com/rdk/services/discovery/rtsp"
+	_ "go.viam.com/rdk/services/discovery/serial"
 )
@@ -0,0 +1,167 @@
+package rtsp
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// errNotRTSP is returned when a server does not speak RTSP.
+var errNotRTSP = errors.New("server does not speak RTSP")
+
+var authParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// stream is an RTSP stream found on a server.
+type stream struct {
+	// url is the URL of the stream, without credentials.
+	url string
+	// found is whether the stream was described, rather than guessed.
+	found bool
+	// needsAuth is whether the stream could not be described without credentials.
+	needsAuth bool
+}
+
+// rtspConn is a connection to an RTSP server, which requests are made over one at a time.
+type rtspConn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+	cseq    int
+}
+
+type response struct {
+	status int
+	header textproto.MIMEHeader
+}
+
+// do sends a request and reads its response, discarding its body.
+func (c *rtspConn) do(method, url string, header map[string]string) (response, error) {
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return response{}, err
+	}
+	c.cseq++
+	var req strings.Builder
+	fmt.Fprintf(&req, "%s %s RTSP/1.0\r\nCSeq: %d\r\nUser-Agent: viam-discovery\r\n", method, url, c.cseq)
+	for k, v := range header {
+		fmt.Fprintf(&req, "%s: %s\r\n", k, v)
+	}
+	req.WriteString("\r\n")
+	if _, err := io.WriteString(c.conn, req.String()); err != nil {
+		return response{}, err
+	}
+
+	tp := textproto.NewReader(c.reader)
+	line, err := tp.ReadLine()
+	if err != nil {
+		return response{}, err
+	}
+	version, rest, ok := strings.Cut(line, " ")
+	if !ok || !strings.HasPrefix(version, "RTSP/") {
+		return response{}, errNotRTSP
+	}
+	code, _, _ := strings.Cut(rest, " ")
+	status, err := strconv.Atoi(code)
+	if err != nil {
+		return response{}, errNotRTSP
+	}
+	respHeader, err := tp.ReadMIMEHeader()
+	if err != nil {
+		return response{}, err
+	}
+	if length, err := strconv.Atoi(respHeader.Get("Content-Length")); err == nil && length > 0 {
+		if _, err := io.CopyN(io.Discard, c.reader, int64(length)); err != nil {
+			return response{}, err
+		}
+	}
+	return response{status: status, header: respHeader}, nil
+}
+
+// authorization returns the Authorization header answering a challenge.
+func authorization(challenge, method, url, username, password string) string {
+	if strings.HasPrefix(strings.ToLower(challenge), "basic") {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+	}
+	params := map[string]string{}
+	for _, match := range authParamPattern.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	ha1 := md5Hex(username + ":" + params["realm"] + ":" + password)
+	ha2 := md5Hex(method + ":" + url)
+	return fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`,
+		username, params["realm"], params["nonce"], url, md5Hex(ha1+":"+params["nonce"]+":"+ha2))
+}
+
+func md5Hex(s string) string {
+	//nolint:gosec
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// probe checks whether an RTSP server listens at `address` and looks for a stream at each of
+// `paths`. It returns the first stream found, or the first that needs credentials it does not
+// have, or else the server's root.
+func probe(
+	ctx context.Context,
+	address string,
+	paths []string,
+	username, password string,
+	timeout time.Duration,
+) (stream, error) {
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return stream{}, err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		//nolint:errcheck
+		conn.Close()
+	})
+	defer func() {
+		stop()
+		//nolint:errcheck
+		conn.Close()
+	}()
+	c := &rtspConn{conn: conn, reader: bufio.NewReader(conn), timeout: timeout}
+
+	base := "rtsp://" + address + "/"
+	if _, err := c.do("OPTIONS", base, nil); err != nil {
+		return stream{}, err
+	}
+
+	// the server is known to speak RTSP from here, so it is returned even if it stops answering
+	var fallback *stream
+	for _, path := range paths {
+		url := base + strings.TrimPrefix(path, "/")
+		header := map[string]string{"Accept": "application/sdp"}
+		resp, err := c.do("DESCRIBE", url, header)
+		if err == nil && resp.status == 401 && username != "" {
+			header["Authorization"] = authorization(resp.header.Get("WWW-Authenticate"), "DESCRIBE", url, username, password)
+			resp, err = c.do("DESCRIBE", url, header)
+		}
+		if err != nil {
+			break
+		}
+		switch {
+		case resp.status == 200:
+			return stream{url: url, found: true}, nil
+		case resp.status == 401 && fallback == nil:
+			fallback = &stream{url: url, needsAuth: true}
+		}
+	}
+	if fallback != nil {
+		return *fallback, nil
+	}
+	return stream{url: base}, nil
+}
@@ -0,0 +1,303 @@
+// Package rtsp implements a discovery service that scans the local network for RTSP cameras,
+// such as cameras that do not answer ONVIF discovery, and surfaces ffmpeg camera configs for them.
+package rtsp
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/camera/ffmpeg"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/discovery"
+	"go.viam.com/rdk/utils"
+)
+
+// Model is the model of the RTSP discovery service.
+var Model = resource.DefaultModelFamily.WithModel("rtsp")
+
+const (
+	// defaultTimeout is how long connecting and each request to a host may take.
+	defaultTimeout = 500 * time.Millisecond
+	// maxHosts bounds how many hosts one scan probes.
+	maxHosts = 4096
+	// maxConcurrentProbes bounds how many hosts are probed at once.
+	maxConcurrentProbes = 64
+)
+
+var (
+	defaultPorts = []int{554, 8554}
+	// defaultPaths are the stream paths of common camera brands.
+	defaultPaths = []string{
+		"",
+		"stream1",
+		"live",
+		"h264",
+		"Streaming/Channels/101",
+		"cam/realmonitor?channel=1&subtype=0",
+		"videoMain",
+		"live/ch00_0",
+		"11",
+	}
+)
+
+// Config is the config of the RTSP discovery service.
+type Config struct {
+	// Subnets are the CIDR subnets scanned, such as "192.168.1.0/24". They are the /24 subnets of
+	// the machine's network interfaces by default.
+	Subnets []string `json:"subnets,omitempty"`
+	// Ports are the ports scanned on each host, 554 and 8554 by default.
+	Ports []int `json:"ports,omitempty"`
+	// Paths are the stream paths tried on each RTSP server, in order. Paths of common camera
+	// brands are tried by default.
+	Paths []string `json:"paths,omitempty"`
+	// Username and Password are used to describe streams, and are put in the configs of the
+	// cameras.
+	Username  string `json:"username,omitempty"`
+	Password  string `json:"password,omitempty"`
+	TimeoutMs int    `json:"timeout_ms,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.TimeoutMs < 0 {
+		return nil, resource.NewConfigValidationError(path, fmt.Errorf("timeout_ms must not be negative, got %d", cfg.TimeoutMs))
+	}
+	if _, err := hosts(cfg.Subnets); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	for _, port := range cfg.Ports {
+		if port <= 0 || port > 65535 {
+			return nil, resource.NewConfigValidationError(path, fmt.Errorf("invalid port %d", port))
+		}
+	}
+	return []string{}, nil
+}
+
+func init() {
+	resource.RegisterService(
+		discovery.API,
+		Model,
+		resource.Registration[discovery.Service, *Config]{Constructor: newDiscovery})
+}
+
+type rtspDiscovery struct {
+	resource.Named
+	resource.AlwaysRebuild
+	resource.TriviallyCloseable
+
+	conf   *Config
+	logger logging.Logger
+}
+
+func newDiscovery(
+	ctx context.Context,
+	_ resource.Dependencies,
+	conf resource.Config,
+	logger logging.Logger,
+) (discovery.Service, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	return &rtspDiscovery{
+		Named:  conf.ResourceName().AsNamed(),
+		conf:   newConf,
+		logger: logger,
+	}, nil
+}
+
+// DiscoverResources probes the ports of each host of the subnets for RTSP servers, and returns an
+// ffmpeg camera config for the stream found on each. "subnets" and "timeout_ms" in extra override
+// the configured ones.
+func (dis *rtspDiscovery) DiscoverResources(ctx context.Context, extra map[string]any) ([]resource.Config, error) {
+	timeout := defaultTimeout
+	if dis.conf.TimeoutMs > 0 {
+		timeout = time.Duration(dis.conf.TimeoutMs) * time.Millisecond
+	}
+	if timeoutMs, ok := extra["timeout_ms"].(float64); ok && timeoutMs > 0 {
+		timeout = time.Duration(timeoutMs * float64(time.Millisecond))
+	}
+	subnets := dis.conf.Subnets
+	if raw, ok := extra["subnets"].([]interface{}); ok {
+		subnets = nil
+		for _, subnet := range raw {
+			s, ok := subnet.(string)
+			if !ok {
+				return nil, errors.New("subnets must be strings")
+			}
+			subnets = append(subnets, s)
+		}
+	}
+	if len(subnets) == 0 {
+		var err error
+		if subnets, err = localSubnets(); err != nil {
+			return nil, err
+		}
+	}
+	ips, err := hosts(subnets)
+	if err != nil {
+		return nil, err
+	}
+	ports := dis.conf.Ports
+	if len(ports) == 0 {
+		ports = defaultPorts
+	}
+	paths := dis.conf.Paths
+	if len(paths) == 0 {
+		paths = defaultPaths
+	}
+
+	var (
+		mu      sync.Mutex
+		streams = map[string]stream{}
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, maxConcurrentProbes)
+	)
+	for _, ip := range ips {
+		for _, port := range ports {
+			address := net.JoinHostPort(ip.String(), strconv.Itoa(port))
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return nil, ctx.Err()
+			}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				found, err := probe(ctx, address, paths, dis.conf.Username, dis.conf.Password, timeout)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				streams[address] = found
+				mu.Unlock()
+			}()
+		}
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	addresses := make([]string, 0, len(streams))
+	for address := range streams {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	cfgs := make([]resource.Config, 0, len(addresses))
+	for _, address := range addresses {
+		found := streams[address]
+		if !found.found {
+			dis.logger.CInfow(ctx, "found an RTSP server without finding its stream path, its config needs to be edited",
+				"address", address, "needs_credentials", found.needsAuth)
+		}
+		cfgs = append(cfgs, dis.cameraConfig(address, found.url))
+	}
+	return cfgs, nil
+}
+
+func (dis *rtspDiscovery) cameraConfig(address, streamURL string) resource.Config {
+	if dis.conf.Username != "" {
+		if u, err := url.Parse(streamURL); err == nil {
+			u.User = url.UserPassword(dis.conf.Username, dis.conf.Password)
+			streamURL = u.String()
+		}
+	}
+	host, port, _ := net.SplitHostPort(address) //nolint:errcheck
+	name := "rtsp-" + strings.NewReplacer(".", "-", ":", "-").Replace(host)
+	if port != "554" {
+		name += "-" + port
+	}
+	return resource.Config{
+		Name:  name,
+		API:   camera.API,
+		Model: ffmpeg.Model,
+		Attributes: utils.AttributeMap{
+			"video_path":    streamURL,
+			"input_kw_args": map[string]interface{}{"rtsp_transport": "tcp"},
+		},
+	}
+}
+
+// localSubnets returns the /24 subnets, or smaller, of the machine's IPv4 network interfaces.
+func localSubnets() ([]string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var subnets []string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.To4() == nil {
+				continue
+			}
+			ones, _ := ipNet.Mask.Size()
+			ones = max(ones, 24)
+			subnet := net.IPNet{IP: ipNet.IP.To4().Mask(net.CIDRMask(ones, 32)), Mask: net.CIDRMask(ones, 32)}
+			subnets = append(subnets, subnet.String())
+		}
+	}
+	if len(subnets) == 0 {
+		return nil, errors.New("no IPv4 network interfaces to scan")
+	}
+	return subnets, nil
+}
+
+// hosts returns the host addresses of IPv4 subnets, without their network and broadcast addresses.
+func hosts(subnets []string) ([]net.IP, error) {
+	var ips []net.IP
+	seen := map[uint32]bool{}
+	for _, subnet := range subnets {
+		_, ipNet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return nil, err
+		}
+		if ipNet.IP.To4() == nil {
+			return nil, errors.Errorf("only IPv4 subnets can be scanned, got %q", subnet)
+		}
+		ones, _ := ipNet.Mask.Size()
+		size := uint32(1) << (32 - ones)
+		if len(ips)+int(size) > maxHosts {
+			return nil, errors.Errorf("cannot scan more than %d hosts, use smaller subnets", maxHosts)
+		}
+		first := binary.BigEndian.Uint32(ipNet.IP.To4())
+		for i := uint32(0); i < size; i++ {
+			if size > 2 && (i == 0 || i == size-1) {
+				continue
+			}
+			n := first + i
+			if seen[n] {
+				continue
+			}
+			seen[n] = true
+			ip := make(net.IP, 4)
+			binary.BigEndian.PutUint32(ip, n)
+			ips = append(ips, ip)
+		}
+	}
+	return ips, nil
+}
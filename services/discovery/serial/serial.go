@@ -0,0 +1,293 @@
+// Package serial implements a discovery service that enumerates the machine's USB serial devices
+// and surfaces configs for those it recognizes by their USB vendor and product IDs.
+package serial
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/discovery"
+	"go.viam.com/rdk/utils"
+)
+
+// Model is the model of the serial discovery service.
+var Model = resource.DefaultModelFamily.WithModel("serial")
+
+const (
+	defaultSysRoot       = "/sys"
+	defaultDevRoot       = "/dev"
+	defaultPathAttribute = "serial_path"
+)
+
+// invalidNameChars matches the characters resource names cannot contain.
+var invalidNameChars = regexp.MustCompile(`[^-\w]+`)
+
+// defaultRules recognize common USB serial devices.
+var defaultRules = []DeviceRule{
+	{
+		VendorID: "1546", Name: "gps", API: "rdk:component:movement_sensor", Model: "viam:gps:gps-nmea",
+		Attributes: utils.AttributeMap{"connection_type": "serial"},
+	},
+}
+
+// DeviceRule maps USB serial devices to a component config.
+type DeviceRule struct {
+	// VendorID and ProductID are the hexadecimal USB IDs of the devices matched, such as "1546".
+	// A rule without a product ID matches all products of the vendor.
+	VendorID  string `json:"vendor_id"`
+	ProductID string `json:"product_id,omitempty"`
+	// API and Model are those of the components configured for the devices matched.
+	API   string `json:"api"`
+	Model string `json:"model"`
+	// Name is the name of the components, the product name of the device by default.
+	Name       string             `json:"name,omitempty"`
+	Attributes utils.AttributeMap `json:"attributes,omitempty"`
+	// PathAttribute is the attribute the device path is put in, "serial_path" by default.
+	PathAttribute string `json:"path_attribute,omitempty"`
+}
+
+func (rule DeviceRule) matches(device Device) bool {
+	return strings.EqualFold(rule.VendorID, device.VendorID) &&
+		(rule.ProductID == "" || strings.EqualFold(rule.ProductID, device.ProductID))
+}
+
+// Config is the config of the serial discovery service.
+type Config struct {
+	// Devices are the rules devices are recognized by, ahead of the default rules.
+	Devices []DeviceRule `json:"devices,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	for i, rule := range cfg.Devices {
+		rulePath := fmt.Sprintf("%s.devices.%d", path, i)
+		if rule.VendorID == "" {
+			return nil, resource.NewConfigValidationFieldRequiredError(rulePath, "vendor_id")
+		}
+		if rule.API == "" {
+			return nil, resource.NewConfigValidationFieldRequiredError(rulePath, "api")
+		}
+		if _, err := resource.NewAPIFromString(rule.API); err != nil {
+			return nil, resource.NewConfigValidationError(rulePath, err)
+		}
+		if rule.Model == "" {
+			return nil, resource.NewConfigValidationFieldRequiredError(rulePath, "model")
+		}
+		if _, err := resource.NewModelFromString(rule.Model); err != nil {
+			return nil, resource.NewConfigValidationError(rulePath, err)
+		}
+	}
+	return []string{}, nil
+}
+
+func init() {
+	resource.RegisterService(
+		discovery.API,
+		Model,
+		resource.Registration[discovery.Service, *Config]{Constructor: newDiscovery})
+}
+
+// Device is a USB serial device.
+type Device struct {
+	// Path is the stable /dev/serial/by-id path of the device if it has one, or else its /dev path.
+	Path         string
+	VendorID     string
+	ProductID    string
+	Manufacturer string
+	Product      string
+	SerialNumber string
+}
+
+type serialDiscovery struct {
+	resource.Named
+	resource.AlwaysRebuild
+	resource.TriviallyCloseable
+
+	rules  []DeviceRule
+	logger logging.Logger
+	// sysRoot and devRoot are replaced in tests.
+	sysRoot, devRoot string
+}
+
+func newDiscovery(
+	ctx context.Context,
+	_ resource.Dependencies,
+	conf resource.Config,
+	logger logging.Logger,
+) (discovery.Service, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	return &serialDiscovery{
+		Named:   conf.ResourceName().AsNamed(),
+		rules:   append(append([]DeviceRule{}, newConf.Devices...), defaultRules...),
+		logger:  logger,
+		sysRoot: defaultSysRoot,
+		devRoot: defaultDevRoot,
+	}, nil
+}
+
+// DiscoverResources returns a component config for each USB serial device matching a rule.
+func (dis *serialDiscovery) DiscoverResources(ctx context.Context, extra map[string]any) ([]resource.Config, error) {
+	devices, err := dis.devices()
+	if err != nil {
+		return nil, err
+	}
+	var cfgs []resource.Config
+	names := map[string]int{}
+	for _, device := range devices {
+		cfg, ok := dis.componentConfig(device, names)
+		if !ok {
+			dis.logger.CDebugw(ctx, "no rule matches serial device", "path", device.Path,
+				"vendor_id", device.VendorID, "product_id", device.ProductID, "product", device.Product)
+			continue
+		}
+		cfgs = append(cfgs, cfg)
+	}
+	return cfgs, nil
+}
+
+// DoCommand lists all USB serial devices, including those no rule matches, for writing rules
+// with {"list_devices": {}}.
+func (dis *serialDiscovery) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := cmd["list_devices"]; !ok {
+		return nil, resource.ErrDoUnimplemented
+	}
+	devices, err := dis.devices()
+	if err != nil {
+		return nil, err
+	}
+	encoded := make([]interface{}, 0, len(devices))
+	for _, device := range devices {
+		encoded = append(encoded, map[string]interface{}{
+			"path":          device.Path,
+			"vendor_id":     device.VendorID,
+			"product_id":    device.ProductID,
+			"manufacturer":  device.Manufacturer,
+			"product":       device.Product,
+			"serial_number": device.SerialNumber,
+		})
+	}
+	return map[string]interface{}{"devices": encoded}, nil
+}
+
+func (dis *serialDiscovery) componentConfig(device Device, names map[string]int) (resource.Config, bool) {
+	for _, rule := range dis.rules {
+		if !rule.matches(device) {
+			continue
+		}
+		// validated or default rules always parse
+		api, _ := resource.NewAPIFromString(rule.API)       //nolint:errcheck
+		model, _ := resource.NewModelFromString(rule.Model) //nolint:errcheck
+		attributes := utils.AttributeMap{}
+		for k, v := range rule.Attributes {
+			attributes[k] = v
+		}
+		pathAttribute := rule.PathAttribute
+		if pathAttribute == "" {
+			pathAttribute = defaultPathAttribute
+		}
+		attributes[pathAttribute] = device.Path
+
+		name := rule.Name
+		if name == "" {
+			name = device.Product
+		}
+		name = strings.Trim(invalidNameChars.ReplaceAllString(name, "-"), "-_")
+		if name == "" {
+			name = "serial-device"
+		}
+		names[name]++
+		if count := names[name]; count > 1 {
+			name = fmt.Sprintf("%s-%d", name, count)
+		}
+		return resource.Config{Name: name, API: api, Model: model, Attributes: attributes}, true
+	}
+	return resource.Config{}, false
+}
+
+// devices returns the USB serial devices of the machine from sysfs, ordered by tty name. Machines
+// without sysfs have none.
+func (dis *serialDiscovery) devices() ([]Device, error) {
+	ttys, err := os.ReadDir(filepath.Join(dis.sysRoot, "class", "tty"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	byID := dis.byIDPaths()
+
+	var devices []Device
+	for _, tty := range ttys {
+		usbDir, ok := usbDeviceDir(filepath.Join(dis.sysRoot, "class", "tty", tty.Name(), "device"))
+		if !ok {
+			continue
+		}
+		device := Device{
+			Path:         filepath.Join(dis.devRoot, tty.Name()),
+			VendorID:     readAttribute(usbDir, "idVendor"),
+			ProductID:    readAttribute(usbDir, "idProduct"),
+			Manufacturer: readAttribute(usbDir, "manufacturer"),
+			Product:      readAttribute(usbDir, "product"),
+			SerialNumber: readAttribute(usbDir, "serial"),
+		}
+		if path, ok := byID[tty.Name()]; ok {
+			device.Path = path
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// byIDPaths maps the names of ttys to their stable /dev/serial/by-id paths, which do not change
+// when devices are plugged in in another order.
+func (dis *serialDiscovery) byIDPaths() map[string]string {
+	dir := filepath.Join(dis.devRoot, "serial", "by-id")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	paths := map[string]string{}
+	for _, entry := range entries {
+		target, err := os.Readlink(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		paths[filepath.Base(target)] = filepath.Join(dir, entry.Name())
+	}
+	return paths
+}
+
+// usbDeviceDir returns the sysfs directory of the USB device a tty belongs to, which is an
+// ancestor of the tty's device directory holding the device's IDs.
+func usbDeviceDir(ttyDevice string) (string, bool) {
+	dir, err := filepath.EvalSymlinks(ttyDevice)
+	if err != nil {
+		return "", false
+	}
+	for ; dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "idVendor")); err == nil {
+			return dir, true
+		}
+		if filepath.Base(dir) == "devices" {
+			break
+		}
+	}
+	return "", false
+}
+
+func readAttribute(dir, name string) string {
+	data, err := os.ReadFile(filepath.Clean(filepath.Join(dir, name)))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,151 @@
+package serial
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/discovery"
+	"go.viam.com/rdk/utils"
+)
+
+// fakeTree is a fake sysfs and /dev holding USB serial devices.
+type fakeTree struct {
+	t                *testing.T
+	sysRoot, devRoot string
+}
+
+func newFakeTree(t *testing.T) *fakeTree {
+	t.Helper()
+	root := t.TempDir()
+	tree := &fakeTree{t: t, sysRoot: filepath.Join(root, "sys"), devRoot: filepath.Join(root, "dev")}
+	test.That(t, os.MkdirAll(filepath.Join(tree.sysRoot, "class", "tty"), 0o750), test.ShouldBeNil)
+	test.That(t, os.MkdirAll(filepath.Join(tree.devRoot, "serial", "by-id"), 0o750), test.ShouldBeNil)
+	return tree
+}
+
+// addTTY adds a tty whose device is at `devicePath` under sysfs' devices. If `attributes` is not
+// nil, they are written to the USB device directory `usbPath`.
+func (tree *fakeTree) addTTY(name, usbPath, devicePath string, attributes map[string]string) {
+	usbDir := filepath.Join(tree.sysRoot, "devices", usbPath)
+	deviceDir := filepath.Join(tree.sysRoot, "devices", devicePath)
+	test.That(tree.t, os.MkdirAll(deviceDir, 0o750), test.ShouldBeNil)
+	for k, v := range attributes {
+		test.That(tree.t, os.WriteFile(filepath.Join(usbDir, k), []byte(v+"\n"), 0o600), test.ShouldBeNil)
+	}
+	ttyDir := filepath.Join(tree.sysRoot, "class", "tty", name)
+	test.That(tree.t, os.MkdirAll(ttyDir, 0o750), test.ShouldBeNil)
+	test.That(tree.t, os.Symlink(deviceDir, filepath.Join(ttyDir, "device")), test.ShouldBeNil)
+}
+
+func newTestDiscovery(t *testing.T, tree *fakeTree, conf *Config) *serialDiscovery {
+	t.Helper()
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	svc, err := newDiscovery(context.Background(), nil, resource.Config{
+		Name:                "serial",
+		API:                 discovery.API,
+		Model:               Model,
+		ConvertedAttributes: conf,
+	}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	dis := svc.(*serialDiscovery)
+	dis.sysRoot, dis.devRoot = tree.sysRoot, tree.devRoot
+	return dis
+}
+
+func TestDiscoverResources(t *testing.T) {
+	ctx := context.Background()
+	tree := newFakeTree(t)
+	tree.addTTY("ttyACM0", "usb1/1-1", "usb1/1-1/1-1:1.0/tty/ttyACM0", map[string]string{
+		"idVendor": "1546", "idProduct": "01a9", "manufacturer": "u-blox AG", "product": "u-blox GNSS receiver",
+	})
+	tree.addTTY("ttyUSB0", "usb1/1-2", "usb1/1-2/1-2:1.0/ttyUSB0", map[string]string{
+		"idVendor": "0403", "idProduct": "6014", "product": "FT232H", "serial": "FT1234",
+	})
+	tree.addTTY("ttyUSB1", "usb1/1-3", "usb1/1-3/1-3:1.0/ttyUSB1", map[string]string{
+		"idVendor": "0403", "idProduct": "6014", "product": "FT232H",
+	})
+	tree.addTTY("ttyS0", "", "platform/serial8250/tty/ttyS0", nil)
+	byID := filepath.Join(tree.devRoot, "serial", "by-id", "usb-FTDI_FT232H_FT1234-if00-port0")
+	test.That(t, os.Symlink("../../ttyUSB0", byID), test.ShouldBeNil)
+
+	t.Run("default rules", func(t *testing.T) {
+		dis := newTestDiscovery(t, tree, &Config{})
+		cfgs, err := dis.DiscoverResources(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, cfgs, test.ShouldHaveLength, 1)
+		test.That(t, cfgs[0].Name, test.ShouldEqual, "gps")
+		test.That(t, cfgs[0].API, test.ShouldResemble, movementsensor.API)
+		test.That(t, cfgs[0].Model.String(), test.ShouldEqual, "viam:gps:gps-nmea")
+		test.That(t, cfgs[0].Attributes, test.ShouldResemble, utils.AttributeMap{
+			"connection_type": "serial",
+			"serial_path":     filepath.Join(tree.devRoot, "ttyACM0"),
+		})
+	})
+
+	t.Run("configured rules", func(t *testing.T) {
+		dis := newTestDiscovery(t, tree, &Config{Devices: []DeviceRule{{
+			VendorID: "0403", ProductID: "6014", API: "rdk:component:board", Model: "acme:ftdi:ft232h",
+			PathAttribute: "path",
+		}}})
+		cfgs, err := dis.DiscoverResources(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, cfgs, test.ShouldHaveLength, 3)
+		test.That(t, cfgs[0].Name, test.ShouldEqual, "gps")
+		// the stable path is preferred, and names are made unique
+		test.That(t, cfgs[1].Name, test.ShouldEqual, "FT232H")
+		test.That(t, cfgs[1].Attributes, test.ShouldResemble, utils.AttributeMap{"path": byID})
+		test.That(t, cfgs[2].Name, test.ShouldEqual, "FT232H-2")
+		test.That(t, cfgs[2].Attributes, test.ShouldResemble, utils.AttributeMap{"path": filepath.Join(tree.devRoot, "ttyUSB1")})
+	})
+
+	t.Run("list devices", func(t *testing.T) {
+		dis := newTestDiscovery(t, tree, &Config{})
+		resp, err := dis.DoCommand(ctx, map[string]interface{}{"list_devices": map[string]interface{}{}})
+		test.That(t, err, test.ShouldBeNil)
+		devices := resp["devices"].([]interface{})
+		test.That(t, devices, test.ShouldHaveLength, 3)
+		test.That(t, devices[0], test.ShouldResemble, map[string]interface{}{
+			"path":          filepath.Join(tree.devRoot, "ttyACM0"),
+			"vendor_id":     "1546",
+			"product_id":    "01a9",
+			"manufacturer":  "u-blox AG",
+			"product":       "u-blox GNSS receiver",
+			"serial_number": "",
+		})
+
+		_, err = dis.DoCommand(ctx, map[string]interface{}{"other": true})
+		test.That(t, err, test.ShouldEqual, resource.ErrDoUnimplemented)
+	})
+
+	t.Run("no sysfs", func(t *testing.T) {
+		dis := newTestDiscovery(t, tree, &Config{})
+		dis.sysRoot = filepath.Join(t.TempDir(), "missing")
+		cfgs, err := dis.DiscoverResources(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, cfgs, test.ShouldBeEmpty)
+	})
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		rule     DeviceRule
+		expected string
+	}{
+		{DeviceRule{API: "rdk:component:board", Model: "acme:ftdi:ft232h"}, "vendor_id"},
+		{DeviceRule{VendorID: "0403", Model: "acme:ftdi:ft232h"}, "api"},
+		{DeviceRule{VendorID: "0403", API: "board", Model: "acme:ftdi:ft232h"}, "not a valid api"},
+		{DeviceRule{VendorID: "0403", API: "rdk:component:board"}, "model"},
+	} {
+		_, err := (&Config{Devices: []DeviceRule{tc.rule}}).Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.expected)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
+	genericcomponent "go.viam.com/rdk/components/generic"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 )
@@ -55,3 +56,8 @@ func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map
 	}
 	return resp.Result.AsMap(), nil
 }
+
+// DoCommandStream serves a command stream opened with `cmd` on the server.
+func (c *client) DoCommandStream(ctx context.Context, cmd map[string]interface{}, conn genericcomponent.CommandStreamConn) error {
+	return genericcomponent.DoCommandStreamWithDoCommand(ctx, c.DoCommand, cmd, conn)
+}
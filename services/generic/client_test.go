@@ -2,16 +2,19 @@ package generic_test
 
 import (
 	"context"
+	"io"
 	"net"
 	"testing"
 
 	"go.viam.com/test"
 	"go.viam.com/utils/rpc"
 
+	genericcomponent "go.viam.com/rdk/components/generic"
 	viamgrpc "go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/generic"
+	"go.viam.com/rdk/services/generic/fake"
 	"go.viam.com/rdk/testutils"
 	"go.viam.com/rdk/testutils/inject"
 )
@@ -19,6 +22,7 @@ import (
 var (
 	testGenericName = "gen1"
 	failGenericName = "gen2"
+	fakeGenericName = "gen3"
 )
 
 func TestClient(t *testing.T) {
@@ -45,6 +49,7 @@ func TestClient(t *testing.T) {
 	resourceMap := map[resource.Name]resource.Resource{
 		generic.Named(testGenericName): workingGeneric,
 		generic.Named(failGenericName): failingGeneric,
+		generic.Named(fakeGenericName): &fake.Generic{Named: generic.Named(fakeGenericName).AsNamed()},
 	}
 	genericSvc, err := resource.NewAPIResourceCollection(generic.API, resourceMap)
 	test.That(t, err, test.ShouldBeNil)
@@ -104,6 +109,30 @@ func TestClient(t *testing.T) {
 		test.That(t, resp["cmd"], test.ShouldEqual, testutils.TestCommand["cmd"])
 		test.That(t, resp["data"], test.ShouldEqual, testutils.TestCommand["data"])
 
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	})
+	t.Run("command stream", func(t *testing.T) {
+		conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		client, err := generic.NewClientFromConn(context.Background(), conn, "", generic.Named(fakeGenericName), logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		stream, err := genericcomponent.OpenCommandStream(context.Background(), client, testutils.TestCommand)
+		test.That(t, err, test.ShouldBeNil)
+		msg, err := stream.Recv()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, msg["cmd"], test.ShouldEqual, testutils.TestCommand["cmd"])
+
+		test.That(t, stream.Send(map[string]interface{}{"n": 1}), test.ShouldBeNil)
+		msg, err = stream.Recv()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, msg, test.ShouldResemble, map[string]interface{}{"n": 1.})
+
+		test.That(t, stream.CloseSend(), test.ShouldBeNil)
+		_, err = stream.Recv()
+		test.That(t, err, test.ShouldBeError, io.EOF)
+		test.That(t, stream.Close(), test.ShouldBeNil)
+
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
 }
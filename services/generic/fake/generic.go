@@ -3,7 +3,11 @@ package fake
 
 import (
 	"context"
+	"io"
 
+	"github.com/pkg/errors"
+
+	genericcomponent "go.viam.com/rdk/components/generic"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/generic"
@@ -27,7 +31,8 @@ func newGeneric(name resource.Name, logger logging.Logger) resource.Resource {
 	return &Generic{Named: name.AsNamed(), logger: logger}
 }
 
-// Generic is a fake Generic service that always echos input back to the caller.
+// Generic is a fake Generic service that always echos input back to the caller, including the
+// messages of command streams.
 type Generic struct {
 	resource.Named
 	resource.TriviallyReconfigurable
@@ -39,3 +44,27 @@ type Generic struct {
 func (fg *Generic) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	return cmd, nil
 }
+
+// DoCommandStream echos the command the stream was opened with, and then each message, back to the
+// caller until it closes its send side.
+func (fg *Generic) DoCommandStream(
+	ctx context.Context,
+	cmd map[string]interface{},
+	conn genericcomponent.CommandStreamConn,
+) error {
+	if err := conn.Send(cmd); err != nil {
+		return err
+	}
+	for {
+		msg, err := conn.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := conn.Send(msg); err != nil {
+			return err
+		}
+	}
+}
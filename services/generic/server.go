@@ -8,18 +8,20 @@ import (
 	genericpb "go.viam.com/api/service/generic/v1"
 	"go.viam.com/utils/protoutils"
 
+	genericcomponent "go.viam.com/rdk/components/generic"
 	"go.viam.com/rdk/resource"
 )
 
 // serviceServer implements the resource.Generic service.
 type serviceServer struct {
 	genericpb.UnimplementedGenericServiceServer
-	coll resource.APIResourceCollection[resource.Resource]
+	coll    resource.APIResourceCollection[resource.Resource]
+	streams *genericcomponent.CommandStreams
 }
 
 // NewRPCServiceServer constructs an generic gRPC service serviceServer.
 func NewRPCServiceServer(coll resource.APIResourceCollection[resource.Resource]) interface{} {
-	return &serviceServer{coll: coll}
+	return &serviceServer{coll: coll, streams: genericcomponent.NewCommandStreams()}
 }
 
 // DoCommand returns an arbitrary command and returns arbitrary results.
@@ -28,6 +30,9 @@ func (s *serviceServer) DoCommand(ctx context.Context, req *commonpb.DoCommandRe
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := genericcomponent.DoCommandStreamCommand(ctx, s.streams, genericDevice, req); handled {
+		return resp, err
+	}
 	result, err := genericDevice.DoCommand(ctx, req.Command.AsMap())
 	if err != nil {
 		return nil, err
@@ -0,0 +1,322 @@
+// Package builtin implements a machine health service which checks the machine's resources, its
+// FTDC metrics and the usage of its disks, CPU and memory.
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/health"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+// Model is the model of the builtin machine health service.
+var Model = resource.DefaultModelFamily.WithModel("machine_health")
+
+func init() {
+	resource.RegisterService(health.API, Model, resource.Registration[resource.Resource, *Config]{
+		DeprecatedRobotConstructor: func(
+			ctx context.Context,
+			r any,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			newConf, err := resource.NativeConfig[*Config](conf)
+			if err != nil {
+				return nil, err
+			}
+			actualR, err := rdkutils.AssertType[robot.Robot](r)
+			if err != nil {
+				return nil, err
+			}
+			return newMachineHealth(conf.ResourceName(), newConf, actualR, systemUsage{}, rdkutils.ClockFromContext(ctx), logger)
+		},
+	})
+}
+
+type machineHealth struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	conf   *Config
+	robot  robot.Robot
+	clk    clock.Clock
+	logger logging.Logger
+
+	ignored    map[string]bool
+	metrics    map[string]threshold
+	metricList []string
+	disk       threshold
+	cpu        threshold
+	memory     threshold
+
+	usage usageReader
+
+	server  *http.Server
+	workers *goutils.StoppableWorkers
+
+	// sampleMu is held while sampling, so samples never overlap.
+	sampleMu sync.Mutex
+	// the previous CPU times, which CPU usage is measured since.
+	prevBusy, prevTotal float64
+
+	mu      sync.Mutex
+	history []health.Status
+}
+
+func newMachineHealth(
+	name resource.Name,
+	conf *Config,
+	r robot.Robot,
+	usage usageReader,
+	clk clock.Clock,
+	logger logging.Logger,
+) (health.Service, error) {
+	h := &machineHealth{
+		Named:   name.AsNamed(),
+		conf:    conf,
+		robot:   r,
+		clk:     clk,
+		logger:  logger,
+		ignored: map[string]bool{},
+		metrics: map[string]threshold{},
+		disk:    thresholds(conf.DiskWarningPercent, conf.DiskCriticalPercent, defaultDiskWarningPercent, defaultDiskCriticalPercent),
+		cpu:     thresholds(conf.CPUWarningPercent, conf.CPUCriticalPercent, defaultCPUWarningPercent, defaultCPUCriticalPercent),
+		memory:  thresholds(conf.MemoryWarningPercent, conf.MemoryCriticalPercent, defaultMemoryWarningPercent, defaultMemoryCriticalPercent),
+		usage:   usage,
+	}
+	for _, name := range conf.IgnoreResources {
+		h.ignored[name] = true
+	}
+	for _, metric := range conf.Metrics {
+		h.metrics[metric.Metric] = threshold{warning: metric.Warning, critical: metric.Critical, below: metric.Below}
+		h.metricList = append(h.metricList, metric.Metric)
+	}
+
+	if conf.HTTPAddress != "" {
+		listener, err := net.Listen("tcp", conf.HTTPAddress)
+		if err != nil {
+			return nil, err
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/healthz", h.serveHTTP)
+		h.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		goutils.PanicCapturingGo(func() {
+			if err := h.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				h.logger.Errorw("health HTTP server stopped", "error", err)
+			}
+		})
+	}
+
+	interval := conf.interval()
+	h.workers = goutils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
+		ticker := h.clk.Ticker(interval)
+		defer ticker.Stop()
+		for {
+			h.sample(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+	return h, nil
+}
+
+// sample checks the machine's health and records its status.
+func (h *machineHealth) sample(ctx context.Context) health.Status {
+	h.sampleMu.Lock()
+	defer h.sampleMu.Unlock()
+
+	status := health.Status{Time: h.clk.Now(), Level: health.LevelHealthy}
+	add := func(check health.Check) {
+		status.Checks = append(status.Checks, check)
+		status.Level = health.Worse(status.Level, check.Level)
+	}
+	for _, check := range h.checkResources(ctx) {
+		add(check)
+	}
+	for _, check := range h.checkMetrics() {
+		add(check)
+	}
+	for _, check := range h.checkUsage() {
+		add(check)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.history = append(h.history, status)
+	if extra := len(h.history) - h.conf.historySize(); extra > 0 {
+		h.history = append([]health.Status(nil), h.history[extra:]...)
+	}
+	return status
+}
+
+// checkResources checks the state of the machine and each of its resources.
+func (h *machineHealth) checkResources(ctx context.Context) []health.Check {
+	machineStatus, err := h.robot.MachineStatus(ctx)
+	if err != nil {
+		return []health.Check{{Name: "machine", Level: health.LevelUnhealthy, Message: err.Error()}}
+	}
+	checks := []health.Check{{Name: "machine", Level: health.LevelHealthy}}
+	if machineStatus.State == robot.StateInitializing {
+		checks[0] = health.Check{Name: "machine", Level: health.LevelDegraded, Message: "initializing"}
+	}
+	for _, res := range machineStatus.Resources {
+		if res.Name.API.Type.Namespace == resource.APINamespaceRDKInternal ||
+			res.Name == h.Name() || h.ignored[res.Name.ShortName()] || h.ignored[res.Name.String()] {
+			continue
+		}
+		check := health.Check{Name: "resource:" + res.Name.String()}
+		switch res.State {
+		case resource.NodeStateReady:
+			check.Level = health.LevelHealthy
+		case resource.NodeStateRemoving:
+			continue
+		case resource.NodeStateUnhealthy:
+			check.Level = health.LevelUnhealthy
+			if res.Error != nil {
+				check.Message = res.Error.Error()
+			}
+		default:
+			check.Level = health.LevelDegraded
+			check.Message = res.State.String()
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkMetrics checks the configured metrics that were most recently recorded with FTDC.
+func (h *machineHealth) checkMetrics() []health.Check {
+	if len(h.metricList) == 0 {
+		return nil
+	}
+	var values map[string]float64
+	if ftdcRobot, ok := h.robot.(robot.FTDCRobot); ok {
+		if latest, ok := ftdcRobot.LatestFTDC(); ok {
+			values = make(map[string]float64, len(latest.Readings))
+			for _, reading := range latest.Readings {
+				values[reading.MetricName] = float64(reading.Value)
+			}
+		}
+	}
+	checks := make([]health.Check, 0, len(h.metricList))
+	for _, metric := range h.metricList {
+		check := health.Check{Name: "metric:" + metric}
+		value, ok := values[metric]
+		switch {
+		case values == nil:
+			check.Level = health.LevelDegraded
+			check.Message = "FTDC has not recorded any metrics, it may not be enabled"
+		case !ok:
+			check.Level = health.LevelDegraded
+			check.Message = "metric is not recorded by FTDC"
+		default:
+			check.Value = value
+			check.Level = h.metrics[metric].level(value)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkUsage checks the usage of the machine's disks, CPU and memory. Usages that cannot be read
+// on this platform are not checked.
+func (h *machineHealth) checkUsage() []health.Check {
+	var checks []health.Check
+	for _, path := range h.conf.diskPaths() {
+		percent, err := h.usage.diskPercent(path)
+		if err != nil {
+			h.logger.Debugw("cannot read disk usage", "path", path, "error", err)
+			continue
+		}
+		checks = append(checks, usageCheck("disk:"+path, percent, h.disk))
+	}
+
+	busy, total, err := h.usage.cpuTimes()
+	if err != nil {
+		h.logger.Debugw("cannot read CPU usage", "error", err)
+	} else {
+		if h.prevTotal > 0 && total > h.prevTotal {
+			checks = append(checks, usageCheck("cpu", 100*(busy-h.prevBusy)/(total-h.prevTotal), h.cpu))
+		}
+		h.prevBusy, h.prevTotal = busy, total
+	}
+
+	if percent, err := h.usage.memoryPercent(); err != nil {
+		h.logger.Debugw("cannot read memory usage", "error", err)
+	} else {
+		checks = append(checks, usageCheck("memory", percent, h.memory))
+	}
+	return checks
+}
+
+func usageCheck(name string, percent float64, t threshold) health.Check {
+	check := health.Check{Name: name, Value: percent, Level: t.level(percent)}
+	if check.Level != health.LevelHealthy {
+		check.Message = fmt.Sprintf("%.1f%% used", percent)
+	}
+	return check
+}
+
+func (h *machineHealth) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	status, err := h.Status(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if status.Level == health.LevelUnhealthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	//nolint:errcheck
+	json.NewEncoder(w).Encode(health.StatusToMap(status))
+}
+
+func (h *machineHealth) Status(ctx context.Context) (health.Status, error) {
+	h.mu.Lock()
+	if len(h.history) > 0 {
+		defer h.mu.Unlock()
+		return h.history[len(h.history)-1], nil
+	}
+	h.mu.Unlock()
+	// nothing has been sampled yet
+	return h.sample(ctx), nil
+}
+
+func (h *machineHealth) History(ctx context.Context) ([]health.Status, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]health.Status(nil), h.history...), nil
+}
+
+func (h *machineHealth) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	resp, handled, err := health.HandleDoCommand(ctx, h, cmd)
+	if handled {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+func (h *machineHealth) Close(ctx context.Context) error {
+	var err error
+	if h.server != nil {
+		err = h.server.Close()
+	}
+	h.workers.Stop()
+	return err
+}
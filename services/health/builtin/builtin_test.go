@@ -0,0 +1,218 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/ftdc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/health"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestConfigValidate(t *testing.T) {
+	limit := 100.0
+	conf := &Config{Metrics: []MetricConfig{{Metric: "proc.viam-server.RssMB", Warning: &limit}}}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, tc := range []struct {
+		conf     Config
+		expected string
+	}{
+		{Config{IntervalSeconds: -1}, "interval_seconds"},
+		{Config{HistorySize: -1}, "history_size"},
+		{Config{Metrics: []MetricConfig{{Warning: &limit}}}, "metric"},
+		{Config{Metrics: []MetricConfig{{Metric: "net.rx"}}}, "needs a warning or critical threshold"},
+		{Config{DiskWarningPercent: -5}, "disk_warning_percent"},
+	} {
+		_, err := tc.conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.expected)
+	}
+}
+
+func TestThreshold(t *testing.T) {
+	warning, critical := 10.0, 20.0
+	above := threshold{warning: &warning, critical: &critical}
+	test.That(t, above.level(5), test.ShouldEqual, health.LevelHealthy)
+	test.That(t, above.level(15), test.ShouldEqual, health.LevelDegraded)
+	test.That(t, above.level(25), test.ShouldEqual, health.LevelUnhealthy)
+
+	below := threshold{warning: &critical, below: true}
+	test.That(t, below.level(25), test.ShouldEqual, health.LevelHealthy)
+	test.That(t, below.level(5), test.ShouldEqual, health.LevelDegraded)
+}
+
+// fakeUsage reports usages set by tests.
+type fakeUsage struct {
+	mu          sync.Mutex
+	busy, total float64
+	memory      float64
+	disk        map[string]float64
+}
+
+func (u *fakeUsage) cpuTimes() (float64, float64, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.busy, u.total, nil
+}
+
+func (u *fakeUsage) memoryPercent() (float64, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.memory, nil
+}
+
+func (u *fakeUsage) diskPercent(path string) (float64, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	percent, ok := u.disk[path]
+	if !ok {
+		return 0, errors.New("no such file system")
+	}
+	return percent, nil
+}
+
+// ftdcRobot is an injected robot recording FTDC.
+type ftdcRobot struct {
+	*inject.Robot
+	mu     sync.Mutex
+	latest *ftdc.FlatDatum
+}
+
+func (r *ftdcRobot) LatestFTDC() (ftdc.FlatDatum, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.latest == nil {
+		return ftdc.FlatDatum{}, false
+	}
+	return *r.latest, true
+}
+
+func (r *ftdcRobot) SubscribeFTDC(bufferSize int, metricPrefixes ...string) (*ftdc.Subscription, bool) {
+	return nil, false
+}
+
+func TestMachineHealth(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	clk := clock.NewMock()
+
+	var mu sync.Mutex
+	machineStatus := robot.MachineStatus{
+		State: robot.StateRunning,
+		Resources: []resource.Status{
+			{NodeStatus: resource.NodeStatus{Name: arm.Named("arm"), State: resource.NodeStateReady}},
+			{NodeStatus: resource.NodeStatus{Name: sensor.Named("flaky"), State: resource.NodeStateReady}},
+			{NodeStatus: resource.NodeStatus{Name: health.Named("health"), State: resource.NodeStateConfiguring}},
+		},
+	}
+	injectRobot := &inject.Robot{MachineStatusFunc: func(ctx context.Context) (robot.MachineStatus, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return machineStatus, nil
+	}}
+	r := &ftdcRobot{Robot: injectRobot}
+	usage := &fakeUsage{busy: 10, total: 100, memory: 40, disk: map[string]float64{"/": 50}}
+
+	rssWarning, rssCritical := 1000.0, 2000.0
+	conf := &Config{
+		IntervalSeconds: 3600,
+		HistorySize:     3,
+		IgnoreResources: []string{"flaky"},
+		Metrics:         []MetricConfig{{Metric: "proc.viam-server.RssMB", Warning: &rssWarning, Critical: &rssCritical}},
+	}
+	svc, err := newMachineHealth(health.Named("health"), conf, r, usage, clk, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer svc.Close(ctx)
+	h := svc.(*machineHealth)
+	// the typed methods are carried over DoCommand for clients
+	client := health.FromResource(struct{ resource.Resource }{svc})
+
+	t.Run("metrics FTDC has not recorded degrade the machine", func(t *testing.T) {
+		status := h.sample(ctx)
+		test.That(t, status.Level, test.ShouldEqual, health.LevelDegraded)
+		test.That(t, status.Checks, test.ShouldResemble, []health.Check{
+			{Name: "machine", Level: health.LevelHealthy},
+			{Name: "resource:rdk:component:arm/arm", Level: health.LevelHealthy},
+			{
+				Name: "metric:proc.viam-server.RssMB", Level: health.LevelDegraded,
+				Message: "FTDC has not recorded any metrics, it may not be enabled",
+			},
+			{Name: "disk:/", Level: health.LevelHealthy, Value: 50},
+			{Name: "memory", Level: health.LevelHealthy, Value: 40},
+		})
+	})
+
+	t.Run("healthy", func(t *testing.T) {
+		r.mu.Lock()
+		r.latest = &ftdc.FlatDatum{Readings: []ftdc.Reading{{MetricName: "proc.viam-server.RssMB", Value: 500}}}
+		r.mu.Unlock()
+		usage.mu.Lock()
+		usage.busy, usage.total = 60, 200
+		usage.mu.Unlock()
+		clk.Add(time.Second)
+		h.sample(ctx)
+
+		status, err := client.Status(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, status.Level, test.ShouldEqual, health.LevelHealthy)
+		test.That(t, status.Time.Equal(clk.Now()), test.ShouldBeTrue)
+		test.That(t, status.Checks[2], test.ShouldResemble, health.Check{
+			Name: "metric:proc.viam-server.RssMB", Level: health.LevelHealthy, Value: 500,
+		})
+		// CPU usage is measured between samples
+		test.That(t, status.Checks[4], test.ShouldResemble, health.Check{Name: "cpu", Level: health.LevelHealthy, Value: 50})
+	})
+
+	t.Run("unhealthy", func(t *testing.T) {
+		mu.Lock()
+		machineStatus.Resources[0].State = resource.NodeStateUnhealthy
+		machineStatus.Resources[0].Error = errors.New("cannot reach arm")
+		mu.Unlock()
+		usage.mu.Lock()
+		usage.disk["/"] = 90
+		usage.mu.Unlock()
+		clk.Add(time.Second)
+		h.sample(ctx)
+
+		status, err := client.Status(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, status.Level, test.ShouldEqual, health.LevelUnhealthy)
+		test.That(t, status.Checks[1], test.ShouldResemble, health.Check{
+			Name: "resource:rdk:component:arm/arm", Level: health.LevelUnhealthy, Message: "cannot reach arm",
+		})
+		test.That(t, status.Checks[3], test.ShouldResemble, health.Check{
+			Name: "disk:/", Level: health.LevelDegraded, Message: "90.0% used", Value: 90,
+		})
+
+		rec := httptest.NewRecorder()
+		h.serveHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		test.That(t, rec.Code, test.ShouldEqual, http.StatusServiceUnavailable)
+		var encoded map[string]interface{}
+		test.That(t, json.Unmarshal(rec.Body.Bytes(), &encoded), test.ShouldBeNil)
+		test.That(t, health.StatusFromMap(encoded), test.ShouldResemble, status)
+	})
+
+	t.Run("history", func(t *testing.T) {
+		statuses, err := client.History(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, statuses, test.ShouldHaveLength, 3)
+		test.That(t, statuses[0].Level, test.ShouldEqual, health.LevelDegraded)
+		test.That(t, statuses[2].Level, test.ShouldEqual, health.LevelUnhealthy)
+	})
+}
@@ -0,0 +1,164 @@
+package builtin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/health"
+)
+
+const (
+	defaultInterval              = 10 * time.Second
+	defaultHistorySize           = 360
+	defaultDiskWarningPercent    = 85
+	defaultDiskCriticalPercent   = 95
+	defaultCPUWarningPercent     = 90
+	defaultCPUCriticalPercent    = 99
+	defaultMemoryWarningPercent  = 85
+	defaultMemoryCriticalPercent = 95
+)
+
+// MetricConfig configures thresholds of a metric recorded with FTDC. The machine is degraded when
+// the metric crosses its warning threshold and unhealthy when it crosses its critical one.
+//
+// For example, to check the memory the viam-server process uses:
+//
+//	{"metric": "proc.viam-server.RssMB", "warning": 1024, "critical": 2048}
+type MetricConfig struct {
+	// Metric is the fully qualified name of the metric, as in FTDC files.
+	Metric   string   `json:"metric"`
+	Warning  *float64 `json:"warning,omitempty"`
+	Critical *float64 `json:"critical,omitempty"`
+	// Below makes values below the thresholds cross them, rather than values above them.
+	Below bool `json:"below,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *MetricConfig) Validate(path string) error {
+	if conf.Metric == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "metric")
+	}
+	if conf.Warning == nil && conf.Critical == nil {
+		return resource.NewConfigValidationError(path, errors.New("a metric needs a warning or critical threshold"))
+	}
+	return nil
+}
+
+// Config is the config of the builtin machine health service. The usage thresholds are
+// percentages, and checks can be turned off by setting their thresholds above 100.
+type Config struct {
+	// IntervalSeconds is how often the machine's health is checked, 10 seconds by default.
+	IntervalSeconds float64 `json:"interval_seconds,omitempty"`
+	// HistorySize is how many of the most recent statuses are kept, 360 by default.
+	HistorySize int `json:"history_size,omitempty"`
+
+	// IgnoreResources are the short or fully qualified names of resources whose state is not
+	// checked.
+	IgnoreResources []string       `json:"ignore_resources,omitempty"`
+	Metrics         []MetricConfig `json:"metrics,omitempty"`
+
+	// DiskPaths are the paths of the file systems whose usage is checked, "/" by default.
+	DiskPaths             []string `json:"disk_paths,omitempty"`
+	DiskWarningPercent    float64  `json:"disk_warning_percent,omitempty"`
+	DiskCriticalPercent   float64  `json:"disk_critical_percent,omitempty"`
+	CPUWarningPercent     float64  `json:"cpu_warning_percent,omitempty"`
+	CPUCriticalPercent    float64  `json:"cpu_critical_percent,omitempty"`
+	MemoryWarningPercent  float64  `json:"memory_warning_percent,omitempty"`
+	MemoryCriticalPercent float64  `json:"memory_critical_percent,omitempty"`
+
+	// HTTPAddress, if set, is the address the status is served at over HTTP on /healthz, such as
+	// ":8090". It responds with 503 while the machine is unhealthy and 200 otherwise, so that load
+	// balancers can check it.
+	HTTPAddress string `json:"http_address,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.IntervalSeconds < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("interval_seconds cannot be negative"))
+	}
+	if conf.HistorySize < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("history_size cannot be negative"))
+	}
+	for i, metric := range conf.Metrics {
+		if err := metric.Validate(fmt.Sprintf("%s.metrics.%d", path, i)); err != nil {
+			return nil, err
+		}
+	}
+	for field, value := range map[string]float64{
+		"disk_warning_percent":    conf.DiskWarningPercent,
+		"disk_critical_percent":   conf.DiskCriticalPercent,
+		"cpu_warning_percent":     conf.CPUWarningPercent,
+		"cpu_critical_percent":    conf.CPUCriticalPercent,
+		"memory_warning_percent":  conf.MemoryWarningPercent,
+		"memory_critical_percent": conf.MemoryCriticalPercent,
+	} {
+		if value < 0 {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf("%s cannot be negative", field))
+		}
+	}
+	return nil, nil
+}
+
+func (conf *Config) interval() time.Duration {
+	if conf.IntervalSeconds == 0 {
+		return defaultInterval
+	}
+	return time.Duration(conf.IntervalSeconds * float64(time.Second))
+}
+
+func (conf *Config) historySize() int {
+	if conf.HistorySize == 0 {
+		return defaultHistorySize
+	}
+	return conf.HistorySize
+}
+
+func (conf *Config) diskPaths() []string {
+	if len(conf.DiskPaths) == 0 {
+		return []string{"/"}
+	}
+	return conf.DiskPaths
+}
+
+// thresholds returns the warning and critical thresholds of a usage, defaulting each that is not
+// set.
+func thresholds(warning, critical, defaultWarning, defaultCritical float64) threshold {
+	if warning == 0 {
+		warning = defaultWarning
+	}
+	if critical == 0 {
+		critical = defaultCritical
+	}
+	return threshold{warning: &warning, critical: &critical}
+}
+
+// threshold levels values.
+type threshold struct {
+	warning, critical *float64
+	below             bool
+}
+
+// level returns the level of `value`.
+func (t threshold) level(value float64) string {
+	crosses := func(limit *float64) bool {
+		if limit == nil {
+			return false
+		}
+		if t.below {
+			return value < *limit
+		}
+		return value > *limit
+	}
+	switch {
+	case crosses(t.critical):
+		return health.LevelUnhealthy
+	case crosses(t.warning):
+		return health.LevelDegraded
+	default:
+		return health.LevelHealthy
+	}
+}
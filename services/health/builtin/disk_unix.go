@@ -0,0 +1,17 @@
+//go:build !windows
+
+package builtin
+
+import "golang.org/x/sys/unix"
+
+func (systemUsage) diskPercent(path string) (float64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	total := float64(stat.Blocks) * float64(stat.Bsize)
+	if total == 0 {
+		return 0, nil
+	}
+	return 100 * (1 - float64(stat.Bavail)*float64(stat.Bsize)/total), nil
+}
@@ -0,0 +1,8 @@
+package builtin
+
+import "github.com/pkg/errors"
+
+// Disk usage is not read on windows.
+func (systemUsage) diskPercent(path string) (float64, error) {
+	return 0, errors.New("disk usage is not supported on windows")
+}
@@ -0,0 +1,52 @@
+package builtin
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prometheus/procfs"
+)
+
+// usageReader reads how much of the machine's disks, CPU and memory are used.
+type usageReader interface {
+	// cpuTimes returns the seconds the machine's CPUs have been busy and in total. CPU usage is the
+	// ratio of their changes between samples.
+	cpuTimes() (float64, float64, error)
+	// memoryPercent returns the percentage of the machine's memory that is not available to start
+	// new processes with.
+	memoryPercent() (float64, error)
+	// diskPercent returns the percentage of the file system at `path` that is not available to
+	// unprivileged users.
+	diskPercent(path string) (float64, error)
+}
+
+// systemUsage reads the usage of the machine the service runs on.
+type systemUsage struct{}
+
+func (systemUsage) cpuTimes() (float64, float64, error) {
+	fs, err := procfs.NewDefaultFS()
+	if err != nil {
+		return 0, 0, err
+	}
+	stat, err := fs.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	cpu := stat.CPUTotal
+	idle := cpu.Idle + cpu.Iowait
+	total := idle + cpu.User + cpu.Nice + cpu.System + cpu.IRQ + cpu.SoftIRQ + cpu.Steal
+	return total - idle, total, nil
+}
+
+func (systemUsage) memoryPercent() (float64, error) {
+	fs, err := procfs.NewDefaultFS()
+	if err != nil {
+		return 0, err
+	}
+	info, err := fs.Meminfo()
+	if err != nil {
+		return 0, err
+	}
+	if info.MemTotal == nil || info.MemAvailable == nil || *info.MemTotal == 0 {
+		return 0, errors.New("memory usage is not reported")
+	}
+	return 100 * float64(*info.MemTotal-*info.MemAvailable) / float64(*info.MemTotal), nil
+}
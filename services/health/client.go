@@ -0,0 +1,134 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The health commands are carried over DoCommand using the following reserved keys.
+const (
+	statusKey   = "health_status"
+	historyKey  = "health_history"
+	statusesKey = "statuses"
+	timeKey     = "time"
+	levelKey    = "level"
+	checksKey   = "checks"
+	nameKey     = "name"
+	messageKey  = "message"
+	valueKey    = "value"
+)
+
+// client implements Service over the DoCommand of a resource that does not implement it, such as
+// the generic client of a remote health service.
+type client struct {
+	resource.Resource
+}
+
+// NewClientFromResource returns a Service calling `res` over DoCommand.
+func NewClientFromResource(res resource.Resource) Service {
+	return &client{Resource: res}
+}
+
+func (c *client) Status(ctx context.Context) (Status, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{statusKey: map[string]interface{}{}})
+	if err != nil {
+		return Status{}, err
+	}
+	encoded, ok := resp[statusKey].(map[string]interface{})
+	if !ok {
+		return Status{}, errors.Errorf("expected %q in response, got %v", statusKey, resp)
+	}
+	return StatusFromMap(encoded), nil
+}
+
+func (c *client) History(ctx context.Context) ([]Status, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{historyKey: map[string]interface{}{}})
+	if err != nil {
+		return nil, err
+	}
+	list, ok := resp[statusesKey].([]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected %q in response, got %v", statusesKey, resp)
+	}
+	statuses := make([]Status, 0, len(list))
+	for _, raw := range list {
+		encoded, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%q entries must be objects", statusesKey)
+		}
+		statuses = append(statuses, StatusFromMap(encoded))
+	}
+	return statuses, nil
+}
+
+// HandleDoCommand handles the reserved health DoCommand keys. Models call it first from their
+// DoCommand, and handle `cmd` themselves if it returns false.
+func HandleDoCommand(ctx context.Context, s Service, cmd map[string]interface{}) (map[string]interface{}, bool, error) {
+	if _, ok := cmd[statusKey]; ok {
+		status, err := s.Status(ctx)
+		if err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{statusKey: StatusToMap(status)}, true, nil
+	}
+	if _, ok := cmd[historyKey]; ok {
+		statuses, err := s.History(ctx)
+		if err != nil {
+			return nil, true, err
+		}
+		encoded := make([]interface{}, 0, len(statuses))
+		for _, status := range statuses {
+			encoded = append(encoded, StatusToMap(status))
+		}
+		return map[string]interface{}{statusesKey: encoded}, true, nil
+	}
+	return nil, false, nil
+}
+
+// StatusToMap encodes a status as it is sent over DoCommand, which is also suitable for encoding
+// as JSON.
+func StatusToMap(status Status) map[string]interface{} {
+	checks := make([]interface{}, 0, len(status.Checks))
+	for _, check := range status.Checks {
+		encoded := map[string]interface{}{nameKey: check.Name, levelKey: check.Level}
+		if check.Message != "" {
+			encoded[messageKey] = check.Message
+		}
+		if check.Value != 0 {
+			encoded[valueKey] = check.Value
+		}
+		checks = append(checks, encoded)
+	}
+	return map[string]interface{}{
+		timeKey:   status.Time.Format(time.RFC3339Nano),
+		levelKey:  status.Level,
+		checksKey: checks,
+	}
+}
+
+// StatusFromMap decodes a status encoded by StatusToMap.
+func StatusFromMap(encoded map[string]interface{}) Status {
+	var status Status
+	rawTime, _ := encoded[timeKey].(string) //nolint:errcheck
+	//nolint:errcheck
+	status.Time, _ = time.Parse(time.RFC3339Nano, rawTime)
+	status.Level, _ = encoded[levelKey].(string)       //nolint:errcheck
+	rawChecks, _ := encoded[checksKey].([]interface{}) //nolint:errcheck
+	for _, raw := range rawChecks {
+		fields, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var check Check
+		check.Name, _ = fields[nameKey].(string)       //nolint:errcheck
+		check.Level, _ = fields[levelKey].(string)     //nolint:errcheck
+		check.Message, _ = fields[messageKey].(string) //nolint:errcheck
+		check.Value, _ = fields[valueKey].(float64)    //nolint:errcheck
+		status.Checks = append(status.Checks, check)
+	}
+	return status
+}
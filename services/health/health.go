@@ -0,0 +1,125 @@
+// Package health defines a machine health service, which summarizes the state of a machine's
+// resources, the metrics it records with FTDC, and the pressure on its disks, CPU and memory into
+// one status, such as for fleet dashboards and load balancer health checks.
+//
+// There is no health proto, so health services are generic services whose typed methods are
+// carried over DoCommand. Models implement Service and answer the reserved commands by calling
+// HandleDoCommand from their DoCommand.
+package health
+
+import (
+	"context"
+	"time"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/generic"
+)
+
+// API is the resource API health services are served under.
+var API = generic.API
+
+// Named is a helper for getting the named health service's typed resource name.
+func Named(name string) resource.Name {
+	return resource.NewName(API, name)
+}
+
+// The levels of health, from best to worst.
+const (
+	LevelHealthy   = "healthy"
+	LevelDegraded  = "degraded"
+	LevelUnhealthy = "unhealthy"
+)
+
+// Worse returns the worse of two levels.
+func Worse(a, b string) string {
+	if severity(b) > severity(a) {
+		return b
+	}
+	return a
+}
+
+func severity(level string) int {
+	switch level {
+	case LevelHealthy:
+		return 0
+	case LevelDegraded:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// A Service summarizes the health of a machine.
+//
+// Status example:
+//
+//	myHealth, err := health.FromRobot(machine, "health")
+//	status, err := myHealth.Status(context.Background())
+//	if status.Level != health.LevelHealthy {
+//		for _, check := range status.Checks {
+//			if check.Level != health.LevelHealthy {
+//				fmt.Printf("%s is %s: %s\n", check.Name, check.Level, check.Message)
+//			}
+//		}
+//	}
+//
+// History example:
+//
+//	// Get the statuses kept since the service started, oldest first.
+//	statuses, err := myHealth.History(context.Background())
+type Service interface {
+	resource.Resource
+
+	// Status returns the most recent status of the machine.
+	Status(ctx context.Context) (Status, error)
+
+	// History returns the most recent statuses of the machine, oldest first.
+	History(ctx context.Context) ([]Status, error)
+}
+
+// Status is the health of a machine at a point in time.
+type Status struct {
+	Time time.Time
+	// Level is the worst level of the checks.
+	Level  string
+	Checks []Check
+}
+
+// Check is the result of checking one part of a machine's health, such as a resource, a metric,
+// or the usage of a disk.
+type Check struct {
+	Name    string
+	Level   string
+	Message string
+	// Value is the measured value of metric and usage checks.
+	Value float64
+}
+
+// FromResource returns `res` as a Service. Resources that do not implement Service, such as the
+// clients of remote health services, are wrapped in a client that calls them over DoCommand.
+func FromResource(res resource.Resource) Service {
+	if s, ok := res.(Service); ok {
+		return s
+	}
+	return NewClientFromResource(res)
+}
+
+// FromDependencies is a helper for getting the named health service from a collection of
+// dependencies.
+func FromDependencies(deps resource.Dependencies, name string) (Service, error) {
+	res, err := resource.FromDependencies[resource.Resource](deps, Named(name))
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
+
+// FromRobot is a helper for getting the named health service from the given Robot.
+func FromRobot(r robot.Robot, name string) (Service, error) {
+	res, err := generic.FromRobot(r, name)
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
@@ -0,0 +1,7 @@
+// Package register registers all relevant health services.
+package register
+
+import (
+	// register health services.
+	_ "go.viam.com/rdk/services/health/builtin"
+)
@@ -0,0 +1,254 @@
+package mlmodel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorgonia.org/tensor"
+
+	"go.viam.com/rdk/ml"
+)
+
+// defaultMaxBatchDelay is how long a batch waits for more calls by default.
+const defaultMaxBatchDelay = 5 * time.Millisecond
+
+// BatchConfig configures the batching of concurrent Infer calls, so that services such as vision
+// services sharing one model run it once for all of their inputs, which uses accelerators much
+// more efficiently. Calls are batched by concatenating their input tensors along the first
+// dimension, so batching only suits models whose inputs and outputs are all batched along their
+// first dimension.
+type BatchConfig struct {
+	// MaxBatchSize is the most inputs that are inferred at once.
+	MaxBatchSize int `json:"max_batch_size"`
+	// MaxDelayMs is how long a call waits for other calls to batch with, 5ms by default.
+	MaxDelayMs float64 `json:"max_delay_ms,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (c *BatchConfig) Validate(path string) error {
+	if c.MaxBatchSize < 2 {
+		return errors.Errorf("%s: max_batch_size must be at least 2 to batch, got %d", path, c.MaxBatchSize)
+	}
+	if c.MaxDelayMs < 0 {
+		return errors.Errorf("%s: max_delay_ms cannot be negative", path)
+	}
+	return nil
+}
+
+// MaxDelay returns how long a call waits for other calls to batch with.
+func (c *BatchConfig) MaxDelay() time.Duration {
+	if c.MaxDelayMs == 0 {
+		return defaultMaxBatchDelay
+	}
+	return time.Duration(c.MaxDelayMs * float64(time.Millisecond))
+}
+
+// CheckBatchable returns an error if the inputs cannot be batched, as their first dimension is
+// not dynamic.
+func CheckBatchable(inputs []TensorInfo) error {
+	for _, info := range inputs {
+		if len(info.Shape) > 0 && info.Shape[0] >= 0 {
+			return errors.Errorf("input %q has a fixed batch size of %d, so inferences cannot be batched", info.Name, info.Shape[0])
+		}
+	}
+	return nil
+}
+
+// A Batcher batches concurrent inferences. Calls with the same input names, data types and
+// shapes other than their first dimension are batched together.
+type Batcher struct {
+	config BatchConfig
+	infer  func(context.Context, ml.Tensors) (ml.Tensors, error)
+
+	mu      sync.Mutex
+	pending map[string]*inferBatch
+}
+
+type inferBatch struct {
+	calls []*batchedCall
+	size  int
+	full  chan struct{}
+}
+
+type batchedCall struct {
+	tensors ml.Tensors
+	size    int
+	outputs ml.Tensors
+	err     error
+	done    chan struct{}
+}
+
+// NewBatcher returns a Batcher which batches the concurrent calls to infer.
+func NewBatcher(config BatchConfig, infer func(context.Context, ml.Tensors) (ml.Tensors, error)) *Batcher {
+	return &Batcher{config: config, infer: infer, pending: map[string]*inferBatch{}}
+}
+
+// Infer infers on tensors, batched with the concurrent calls that arrive within the configured
+// delay of the first call of the batch. Calls which cannot be batched, or which fill a batch on
+// their own, are inferred right away.
+func (b *Batcher) Infer(ctx context.Context, tensors ml.Tensors) (ml.Tensors, error) {
+	size, key, ok := batchKey(tensors)
+	if !ok || size >= b.config.MaxBatchSize {
+		return b.infer(ctx, tensors)
+	}
+	call := &batchedCall{tensors: tensors, size: size, done: make(chan struct{})}
+
+	b.mu.Lock()
+	batch := b.pending[key]
+	if batch != nil && batch.size+size > b.config.MaxBatchSize {
+		// run the pending batch now, as the call doesn't fit
+		delete(b.pending, key)
+		close(batch.full)
+		batch = nil
+	}
+	if batch == nil {
+		batch = &inferBatch{full: make(chan struct{})}
+		b.pending[key] = batch
+		go b.run(key, batch)
+	}
+	batch.calls = append(batch.calls, call)
+	batch.size += size
+	if batch.size == b.config.MaxBatchSize {
+		delete(b.pending, key)
+		close(batch.full)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-call.done:
+		return call.outputs, call.err
+	}
+}
+
+// run infers on the batch once it is full or its delay has passed.
+func (b *Batcher) run(key string, batch *inferBatch) {
+	timer := time.NewTimer(b.config.MaxDelay())
+	defer timer.Stop()
+	select {
+	case <-batch.full:
+	case <-timer.C:
+		b.mu.Lock()
+		if b.pending[key] == batch {
+			delete(b.pending, key)
+		}
+		b.mu.Unlock()
+	}
+
+	// the batch is not canceled by any one call giving up on it
+	ctx := context.Background()
+	defer func() {
+		for _, call := range batch.calls {
+			close(call.done)
+		}
+	}()
+	if len(batch.calls) == 1 {
+		call := batch.calls[0]
+		call.outputs, call.err = b.infer(ctx, call.tensors)
+		return
+	}
+	outputs, err := b.inferBatch(ctx, batch)
+	if err != nil {
+		for _, call := range batch.calls {
+			call.err = err
+		}
+		return
+	}
+	if outputs == nil {
+		// the outputs are not batched along their first dimension, so infer each call on its own
+		for _, call := range batch.calls {
+			call.outputs, call.err = b.infer(ctx, call.tensors)
+		}
+		return
+	}
+	for i, call := range batch.calls {
+		call.outputs = outputs[i]
+	}
+}
+
+// inferBatch infers on the concatenated inputs of the batch's calls and splits the outputs
+// between them. It returns no outputs if they cannot be split.
+func (b *Batcher) inferBatch(ctx context.Context, batch *inferBatch) ([]ml.Tensors, error) {
+	inputs := ml.Tensors{}
+	for name, first := range batch.calls[0].tensors {
+		others := make([]*tensor.Dense, 0, len(batch.calls)-1)
+		for _, call := range batch.calls[1:] {
+			others = append(others, call.tensors[name])
+		}
+		concatenated, err := first.Concat(0, others...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to batch input %q", name)
+		}
+		inputs[name] = concatenated
+	}
+	batchOutputs, err := b.infer(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	for _, output := range batchOutputs {
+		if shape := output.Shape(); len(shape) == 0 || shape[0] != batch.size {
+			return nil, nil
+		}
+	}
+
+	outputs := make([]ml.Tensors, len(batch.calls))
+	start := 0
+	for i, call := range batch.calls {
+		outputs[i] = ml.Tensors{}
+		for name, output := range batchOutputs {
+			slice, err := output.Slice(tensor.S(start, start+call.size))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to split output %q", name)
+			}
+			dense, ok := tensor.Materialize(slice).(*tensor.Dense)
+			if !ok {
+				return nil, errors.Errorf("failed to split output %q", name)
+			}
+			// slicing out a single row drops the batch dimension, so restore the shape
+			shape := append([]int{call.size}, output.Shape()[1:]...)
+			if dense.IsView() {
+				dense, ok = dense.Clone().(*tensor.Dense)
+				if !ok {
+					return nil, errors.Errorf("failed to split output %q", name)
+				}
+			}
+			if err := dense.Reshape(shape...); err != nil {
+				return nil, errors.Wrapf(err, "failed to split output %q", name)
+			}
+			outputs[i][name] = dense
+		}
+		start += call.size
+	}
+	return outputs, nil
+}
+
+// batchKey returns the batch size of tensors, which is the first dimension shared by all of
+// them, and a key which is the same for tensors that can be batched together.
+func batchKey(tensors ml.Tensors) (int, string, bool) {
+	if len(tensors) == 0 {
+		return 0, "", false
+	}
+	names := make([]string, 0, len(tensors))
+	for name := range tensors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	size := -1
+	var key strings.Builder
+	for _, name := range names {
+		t := tensors[name]
+		shape := t.Shape()
+		if len(shape) == 0 || (size >= 0 && shape[0] != size) {
+			return 0, "", false
+		}
+		size = shape[0]
+		fmt.Fprintf(&key, "%s:%s:%v;", name, t.Dtype(), []int(shape[1:]))
+	}
+	return size, key.String(), size > 0
+}
@@ -0,0 +1,154 @@
+package mlmodel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"gorgonia.org/tensor"
+
+	"go.viam.com/rdk/ml"
+)
+
+// doublingInfer returns an inference function that doubles its "in" input as "out" and sums the
+// rows of it as "sums", recording the batch sizes it is called with.
+func doublingInfer(batchSizes *[]int, mu *sync.Mutex) func(context.Context, ml.Tensors) (ml.Tensors, error) {
+	return func(ctx context.Context, tensors ml.Tensors) (ml.Tensors, error) {
+		in := tensors["in"]
+		mu.Lock()
+		*batchSizes = append(*batchSizes, in.Shape()[0])
+		mu.Unlock()
+		values := in.Data().([]float32)
+		doubled := make([]float32, len(values))
+		rows := in.Shape()[0]
+		sums := make([]float32, rows)
+		for i, v := range values {
+			doubled[i] = 2 * v
+			sums[i/(len(values)/rows)] += v
+		}
+		return ml.Tensors{
+			"out":  tensor.New(tensor.WithShape(in.Shape()...), tensor.WithBacking(doubled)),
+			"sums": tensor.New(tensor.WithShape(rows), tensor.WithBacking(sums)),
+		}, nil
+	}
+}
+
+func rowsTensor(rows int, value float32) *tensor.Dense {
+	backing := make([]float32, rows*2)
+	for i := range backing {
+		backing[i] = value
+	}
+	return tensor.New(tensor.WithShape(rows, 2), tensor.WithBacking(backing))
+}
+
+func TestBatcher(t *testing.T) {
+	var mu sync.Mutex
+	var batchSizes []int
+	b := NewBatcher(BatchConfig{MaxBatchSize: 4, MaxDelayMs: 1000}, doublingInfer(&batchSizes, &mu))
+
+	// concurrent calls which fill a batch are inferred at once, and each gets its own outputs
+	var wg sync.WaitGroup
+	outputs := make([]ml.Tensors, 3)
+	for i, rows := range []int{1, 2, 1} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, err := b.Infer(context.Background(), ml.Tensors{"in": rowsTensor(rows, float32(i+1))})
+			test.That(t, err, test.ShouldBeNil)
+			outputs[i] = out
+		}()
+	}
+	wg.Wait()
+	test.That(t, batchSizes, test.ShouldResemble, []int{4})
+	for i, rows := range []int{1, 2, 1} {
+		test.That(t, outputs[i]["out"].Shape(), test.ShouldResemble, tensor.Shape{rows, 2})
+		test.That(t, outputs[i]["out"].Data(), test.ShouldResemble, rowsTensor(rows, float32(2*(i+1))).Data())
+		test.That(t, outputs[i]["sums"].Shape()[0], test.ShouldEqual, rows)
+		test.That(t, outputs[i]["sums"].Data(), test.ShouldResemble, tensor.New(
+			tensor.WithShape(rows), tensor.WithBacking(rowsSums(rows, float32(i+1)))).Data())
+	}
+
+	// a call which fills a batch on its own is inferred right away
+	batchSizes = nil
+	_, err := b.Infer(context.Background(), ml.Tensors{"in": rowsTensor(4, 1)})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, batchSizes, test.ShouldResemble, []int{4})
+}
+
+func rowsSums(rows int, value float32) []float32 {
+	sums := make([]float32, rows)
+	for i := range sums {
+		sums[i] = 2 * value
+	}
+	return sums
+}
+
+func TestBatcherDelay(t *testing.T) {
+	var mu sync.Mutex
+	var batchSizes []int
+	b := NewBatcher(BatchConfig{MaxBatchSize: 8, MaxDelayMs: 20}, doublingInfer(&batchSizes, &mu))
+
+	// a batch which doesn't fill up runs after the delay
+	start := time.Now()
+	out, err := b.Infer(context.Background(), ml.Tensors{"in": rowsTensor(1, 3)})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 20*time.Millisecond)
+	test.That(t, out["out"].Data(), test.ShouldResemble, []float32{6, 6})
+
+	// calls of different shapes are not batched together
+	batchSizes = nil
+	var wg sync.WaitGroup
+	for _, in := range []*tensor.Dense{rowsTensor(1, 1), tensor.New(tensor.WithShape(1, 3), tensor.WithBacking([]float32{1, 2, 3}))} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := b.Infer(context.Background(), ml.Tensors{"in": in})
+			test.That(t, err, test.ShouldBeNil)
+		}()
+	}
+	wg.Wait()
+	test.That(t, batchSizes, test.ShouldResemble, []int{1, 1})
+
+	// a call that gives up doesn't cancel the batch
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err = b.Infer(ctx, ml.Tensors{"in": rowsTensor(1, 1)})
+	test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+}
+
+func TestBatcherUnbatchedOutputs(t *testing.T) {
+	var calls atomic.Int64
+	// the outputs are not batched along their first dimension, like the detections of a detector
+	b := NewBatcher(BatchConfig{MaxBatchSize: 2, MaxDelayMs: 1000}, func(ctx context.Context, tensors ml.Tensors) (ml.Tensors, error) {
+		calls.Add(1)
+		return ml.Tensors{"boxes": tensor.New(tensor.WithShape(5, 4), tensor.WithBacking(make([]float32, 20)))}, nil
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, err := b.Infer(context.Background(), ml.Tensors{"in": rowsTensor(1, 1)})
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, out["boxes"].Shape(), test.ShouldResemble, tensor.Shape{5, 4})
+		}()
+	}
+	wg.Wait()
+	// one batched inference and then one for each call
+	test.That(t, calls.Load(), test.ShouldEqual, 3)
+}
+
+func TestBatchConfig(t *testing.T) {
+	c := &BatchConfig{MaxBatchSize: 8}
+	test.That(t, c.Validate("batching"), test.ShouldBeNil)
+	test.That(t, c.MaxDelay(), test.ShouldEqual, 5*time.Millisecond)
+	test.That(t, (&BatchConfig{MaxBatchSize: 1}).Validate("batching"), test.ShouldNotBeNil)
+	test.That(t, (&BatchConfig{MaxBatchSize: 2, MaxDelayMs: -1}).Validate("batching"), test.ShouldNotBeNil)
+
+	test.That(t, CheckBatchable([]TensorInfo{{Name: "images", Shape: []int{-1, 3, 320, 320}}, {Name: "any"}}), test.ShouldBeNil)
+	err := CheckBatchable([]TensorInfo{{Name: "images", Shape: []int{1, 3, 320, 320}}})
+	test.That(t, err, test.ShouldBeError, `input "images" has a fixed batch size of 1, so inferences cannot be batched`)
+}
@@ -0,0 +1,249 @@
+package onnx
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"go.viam.com/rdk/services/mlmodel"
+)
+
+// Field numbers of the parts of the ONNX ModelProto (onnx/onnx.proto) that the metadata is read from.
+// The ONNX protos are not vendored, so models are read with protowire instead of generated code.
+const (
+	modelDocStringField     = 6
+	modelGraphField         = 7
+	modelMetadataPropsField = 14
+
+	graphNameField        = 2
+	graphInitializerField = 5
+	graphDocStringField   = 10
+	graphInputField       = 11
+	graphOutputField      = 12
+
+	tensorProtoNameField = 8
+
+	valueInfoNameField      = 1
+	valueInfoTypeField      = 2
+	valueInfoDocStringField = 3
+
+	typeTensorTypeField = 1
+	tensorElemTypeField = 1
+	tensorShapeField    = 2
+	shapeDimField       = 1
+	dimValueField       = 1
+
+	stringEntryKeyField   = 1
+	stringEntryValueField = 2
+)
+
+// The ONNX tensor element types, which are also the element types of the ONNX Runtime C API.
+const (
+	elemTypeFloat32 = 1
+	elemTypeUint8   = 2
+	elemTypeInt8    = 3
+	elemTypeUint16  = 4
+	elemTypeInt16   = 5
+	elemTypeInt32   = 6
+	elemTypeInt64   = 7
+	elemTypeFloat64 = 11
+	elemTypeUint32  = 12
+	elemTypeUint64  = 13
+)
+
+// dataTypes are the ML model service data types of the ONNX element types that can be inferred on.
+var dataTypes = map[int32]string{
+	elemTypeFloat32: "float32",
+	elemTypeUint8:   "uint8",
+	elemTypeInt8:    "int8",
+	elemTypeUint16:  "uint16",
+	elemTypeInt16:   "int16",
+	elemTypeInt32:   "int32",
+	elemTypeInt64:   "int64",
+	elemTypeFloat64: "float64",
+	elemTypeUint32:  "uint32",
+	elemTypeUint64:  "uint64",
+}
+
+// modelTypeKey is the metadata_props key models can set their mlmodel.MLMetadata ModelType with.
+const modelTypeKey = "model_type"
+
+// readMetadata returns the metadata of an ONNX model. Graph inputs that are initializers, which
+// older exporters list as inputs, are not inputs of the metadata. Dimensions without a fixed size
+// are -1.
+func readMetadata(model []byte) (mlmodel.MLMetadata, error) {
+	var md mlmodel.MLMetadata
+	var graph []byte
+	err := walkFields(model, func(num protowire.Number, value []byte) error {
+		switch num {
+		case modelDocStringField:
+			md.ModelDescription = string(value)
+		case modelGraphField:
+			graph = value
+		case modelMetadataPropsField:
+			var key, val string
+			if err := walkFields(value, func(num protowire.Number, value []byte) error {
+				switch num {
+				case stringEntryKeyField:
+					key = string(value)
+				case stringEntryValueField:
+					val = string(value)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			if key == modelTypeKey {
+				md.ModelType = val
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return mlmodel.MLMetadata{}, errors.Wrap(err, "invalid ONNX model")
+	}
+	if graph == nil {
+		return mlmodel.MLMetadata{}, errors.New("invalid ONNX model: it has no graph")
+	}
+
+	var inputs, outputs [][]byte
+	initializers := map[string]bool{}
+	err = walkFields(graph, func(num protowire.Number, value []byte) error {
+		switch num {
+		case graphNameField:
+			md.ModelName = string(value)
+		case graphDocStringField:
+			if md.ModelDescription == "" {
+				md.ModelDescription = string(value)
+			}
+		case graphInputField:
+			inputs = append(inputs, value)
+		case graphOutputField:
+			outputs = append(outputs, value)
+		case graphInitializerField:
+			return walkFields(value, func(num protowire.Number, value []byte) error {
+				if num == tensorProtoNameField {
+					initializers[string(value)] = true
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return mlmodel.MLMetadata{}, errors.Wrap(err, "invalid ONNX graph")
+	}
+
+	for _, input := range inputs {
+		info, err := readTensorInfo(input)
+		if err != nil {
+			return mlmodel.MLMetadata{}, err
+		}
+		if !initializers[info.Name] {
+			md.Inputs = append(md.Inputs, info)
+		}
+	}
+	for _, output := range outputs {
+		info, err := readTensorInfo(output)
+		if err != nil {
+			return mlmodel.MLMetadata{}, err
+		}
+		md.Outputs = append(md.Outputs, info)
+	}
+	if len(md.Inputs) == 0 || len(md.Outputs) == 0 {
+		return mlmodel.MLMetadata{}, errors.New("invalid ONNX model: it needs at least one input and one output")
+	}
+	return md, nil
+}
+
+// readTensorInfo reads an ONNX ValueInfoProto of a tensor.
+func readTensorInfo(valueInfo []byte) (mlmodel.TensorInfo, error) {
+	var info mlmodel.TensorInfo
+	var elemType int32
+	err := walkFields(valueInfo, func(num protowire.Number, value []byte) error {
+		switch num {
+		case valueInfoNameField:
+			info.Name = string(value)
+		case valueInfoDocStringField:
+			info.Description = string(value)
+		case valueInfoTypeField:
+			return walkFields(value, func(num protowire.Number, value []byte) error {
+				if num != typeTensorTypeField {
+					return nil
+				}
+				return walkFields(value, func(num protowire.Number, value []byte) error {
+					switch num {
+					case tensorElemTypeField:
+						v, err := varint(value)
+						elemType = int32(v)
+						return err
+					case tensorShapeField:
+						info.Shape = []int{}
+						return walkFields(value, func(num protowire.Number, value []byte) error {
+							if num != shapeDimField {
+								return nil
+							}
+							dim := -1
+							if err := walkFields(value, func(num protowire.Number, value []byte) error {
+								if num == dimValueField {
+									v, err := varint(value)
+									dim = int(v)
+									return err
+								}
+								return nil
+							}); err != nil {
+								return err
+							}
+							info.Shape = append(info.Shape, dim)
+							return nil
+						})
+					}
+					return nil
+				})
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return mlmodel.TensorInfo{}, errors.Wrap(err, "invalid ONNX tensor")
+	}
+	dataType, ok := dataTypes[elemType]
+	if !ok {
+		return mlmodel.TensorInfo{}, errors.Errorf("tensor %q has unsupported ONNX element type %d", info.Name, elemType)
+	}
+	info.DataType = dataType
+	return info, nil
+}
+
+// walkFields calls f with the number and value of each field of a protobuf message. The values of
+// varint fields are passed still encoded, to be decoded with varint.
+func walkFields(msg []byte, f func(num protowire.Number, value []byte) error) error {
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+		n = protowire.ConsumeFieldValue(num, typ, msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		value := msg[:n]
+		if typ == protowire.BytesType {
+			value, _ = protowire.ConsumeBytes(value)
+		}
+		if err := f(num, value); err != nil {
+			return err
+		}
+		msg = msg[n:]
+	}
+	return nil
+}
+
+// varint decodes the value of a varint field passed by walkFields.
+func varint(value []byte) (uint64, error) {
+	v, n := protowire.ConsumeVarint(value)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	return v, nil
+}
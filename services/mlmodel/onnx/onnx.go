@@ -0,0 +1,282 @@
+// Package onnx implements an ML model service that runs ONNX models, such as detectors and
+// classifiers exported from PyTorch, with ONNX Runtime.
+//
+// ONNX Runtime is a native library, so inference is only available in builds with the onnxruntime
+// build tag and the ONNX Runtime headers and library installed, e.g.
+//
+//	CGO_CFLAGS=-I/opt/onnxruntime/include CGO_LDFLAGS=-L/opt/onnxruntime/lib go build -tags onnxruntime ./web/cmd/server
+package onnx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	"gorgonia.org/tensor"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/ml"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/mlmodel"
+)
+
+// Model is the model of the ONNX ML model service.
+var Model = resource.DefaultModelFamily.WithModel("onnx")
+
+// The execution providers that ONNX models can be run with.
+const (
+	ExecutionProviderCPU  = "cpu"
+	ExecutionProviderCUDA = "cuda"
+	// ExecutionProviderAuto runs the model on a GPU if one is available, otherwise on the CPU.
+	ExecutionProviderAuto = "auto"
+)
+
+func init() {
+	resource.RegisterService(mlmodel.API, Model, resource.Registration[mlmodel.Service, *Config]{
+		Constructor: func(
+			ctx context.Context,
+			_ resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (mlmodel.Service, error) {
+			newConf, err := resource.NativeConfig[*Config](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewModel(ctx, conf.ResourceName(), newConf, logger)
+		},
+	})
+}
+
+// Config describes how to configure the ONNX ML model service.
+type Config struct {
+	ModelPath string `json:"model_path"`
+	// LabelPath is a file of labels, one per line, that vision services use to name the classes the
+	// model outputs.
+	LabelPath string `json:"label_path,omitempty"`
+	// ExecutionProvider is "cpu", the default, "cuda" to run the model on an NVIDIA GPU, or "auto"
+	// to run it on an NVIDIA GPU if one is available and on the CPU otherwise.
+	ExecutionProvider string `json:"execution_provider,omitempty"`
+	// CUDADeviceID is the GPU the model runs on when ExecutionProvider is "cuda" or "auto".
+	CUDADeviceID int `json:"cuda_device_id,omitempty"`
+	// NumThreads is how many threads each inference may use. ONNX Runtime picks if unset.
+	NumThreads int `json:"num_threads,omitempty"`
+	// Batching, if set, batches concurrent inferences, which needs a model whose inputs and outputs
+	// are batched along their first dimension.
+	Batching *mlmodel.BatchConfig `json:"batching,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.ModelPath == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "model_path")
+	}
+	switch conf.ExecutionProvider {
+	case "", ExecutionProviderCPU, ExecutionProviderCUDA, ExecutionProviderAuto:
+	default:
+		return nil, resource.NewConfigValidationError(path, errors.Errorf(
+			"execution_provider must be %q, %q or %q, got %q",
+			ExecutionProviderCPU, ExecutionProviderCUDA, ExecutionProviderAuto, conf.ExecutionProvider))
+	}
+	if conf.CUDADeviceID < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("cuda_device_id cannot be negative"))
+	}
+	if conf.NumThreads < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("num_threads cannot be negative"))
+	}
+	if conf.Batching != nil {
+		if err := conf.Batching.Validate(path + ".batching"); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// session runs an ONNX model. It is implemented with ONNX Runtime in builds with the onnxruntime
+// build tag.
+type session interface {
+	// run infers on `inputs`, which are in the order of the model's inputs, and returns the outputs
+	// named by `outputNames`.
+	run(inputs []*namedTensor, outputNames []string) (ml.Tensors, error)
+	close() error
+}
+
+// namedTensor is an input tensor and the model input it is for.
+type namedTensor struct {
+	info   mlmodel.TensorInfo
+	tensor *tensor.Dense
+}
+
+// onnxModel is an ML model service that runs an ONNX model.
+type onnxModel struct {
+	resource.Named
+	resource.AlwaysRebuild
+	logger   logging.Logger
+	metadata mlmodel.MLMetadata
+
+	// batcher batches concurrent inferences if batching is configured.
+	batcher *mlmodel.Batcher
+
+	// mu keeps the session from being closed during inferences, which may run concurrently.
+	mu      sync.RWMutex
+	session session
+}
+
+// NewModel returns an ML model service that runs the ONNX model of `conf`.
+func NewModel(ctx context.Context, name resource.Name, conf *Config, logger logging.Logger) (mlmodel.Service, error) {
+	_, span := trace.StartSpan(ctx, "mlmodel::onnx::NewModel")
+	defer span.End()
+
+	model, err := os.ReadFile(conf.ModelPath)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := readMetadata(model)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read %s", conf.ModelPath)
+	}
+	if conf.LabelPath != "" {
+		if _, err := os.Stat(conf.LabelPath); err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", conf.LabelPath, err)
+		}
+		for i := range metadata.Outputs {
+			if metadata.Outputs[i].Extra == nil {
+				metadata.Outputs[i].Extra = map[string]interface{}{}
+			}
+			metadata.Outputs[i].Extra["labels"] = conf.LabelPath
+		}
+	}
+	if conf.Batching != nil {
+		if err := mlmodel.CheckBatchable(metadata.Inputs); err != nil {
+			return nil, errors.Wrapf(err, "cannot batch %s", conf.ModelPath)
+		}
+	}
+	sess, provider, err := newSessionOnDevice(model, conf, logger)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("loaded ONNX model %q with execution provider %q", metadata.ModelName, provider)
+	m := &onnxModel{
+		Named:    name.AsNamed(),
+		logger:   logger,
+		metadata: metadata,
+		session:  sess,
+	}
+	if conf.Batching != nil {
+		m.batcher = mlmodel.NewBatcher(*conf.Batching, m.run)
+	}
+	return m, nil
+}
+
+// newSessionOnDevice creates a session with the configured execution provider, falling back to
+// the CPU for the auto execution provider if the GPU cannot be used. It returns the execution
+// provider the session runs with.
+func newSessionOnDevice(model []byte, conf *Config, logger logging.Logger) (session, string, error) {
+	switch conf.ExecutionProvider {
+	case "":
+		sess, err := newSession(model, conf)
+		return sess, ExecutionProviderCPU, err
+	case ExecutionProviderAuto:
+		gpuConf := *conf
+		gpuConf.ExecutionProvider = ExecutionProviderCUDA
+		sess, err := newSession(model, &gpuConf)
+		if err == nil {
+			return sess, ExecutionProviderCUDA, nil
+		}
+		logger.Infow("cannot run the model on a GPU, running it on the CPU", "cuda_device_id", conf.CUDADeviceID, "error", err)
+		cpuConf := *conf
+		cpuConf.ExecutionProvider = ExecutionProviderCPU
+		sess, err = newSession(model, &cpuConf)
+		return sess, ExecutionProviderCPU, err
+	default:
+		sess, err := newSession(model, conf)
+		return sess, conf.ExecutionProvider, err
+	}
+}
+
+// Infer runs the model on `tensors`, which are named by the model's input names. A model with a
+// single input accepts a single tensor of any name.
+func (m *onnxModel) Infer(ctx context.Context, tensors ml.Tensors) (ml.Tensors, error) {
+	_, span := trace.StartSpan(ctx, "mlmodel::onnx::Infer")
+	defer span.End()
+
+	inputs := make(ml.Tensors, len(m.metadata.Inputs))
+	for _, info := range m.metadata.Inputs {
+		t, ok := tensors[info.Name]
+		if !ok && len(m.metadata.Inputs) == 1 && len(tensors) == 1 {
+			for _, only := range tensors {
+				t = only
+			}
+			ok = true
+		}
+		if !ok {
+			return nil, errors.Errorf("missing input tensor %q", info.Name)
+		}
+		if err := checkInput(info, t.Shape(), t.Dtype().Name()); err != nil {
+			return nil, err
+		}
+		inputs[info.Name] = t
+	}
+	if m.batcher != nil {
+		return m.batcher.Infer(ctx, inputs)
+	}
+	return m.run(ctx, inputs)
+}
+
+// run runs the session on the checked inputs, which are named by the model's input names.
+func (m *onnxModel) run(ctx context.Context, tensors ml.Tensors) (ml.Tensors, error) {
+	inputs := make([]*namedTensor, 0, len(m.metadata.Inputs))
+	for _, info := range m.metadata.Inputs {
+		inputs = append(inputs, &namedTensor{info: info, tensor: tensors[info.Name]})
+	}
+	outputNames := make([]string, 0, len(m.metadata.Outputs))
+	for _, info := range m.metadata.Outputs {
+		outputNames = append(outputNames, info.Name)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.session == nil {
+		return nil, errors.New("model is closed")
+	}
+	return m.session.run(inputs, outputNames)
+}
+
+// checkInput returns an error if a tensor of `shape` and `dataType` cannot be input as `info`.
+func checkInput(info mlmodel.TensorInfo, shape []int, dataType string) error {
+	if dataType != info.DataType {
+		return errors.Errorf("input tensor %q must be %s, got %s", info.Name, info.DataType, dataType)
+	}
+	if len(info.Shape) == 0 {
+		return nil
+	}
+	if len(shape) != len(info.Shape) {
+		return errors.Errorf("input tensor %q must have shape %v, got %v", info.Name, info.Shape, shape)
+	}
+	for i, dim := range info.Shape {
+		if dim >= 0 && shape[i] != dim {
+			return errors.Errorf("input tensor %q must have shape %v, got %v", info.Name, info.Shape, shape)
+		}
+	}
+	return nil
+}
+
+// Metadata returns the metadata of the model.
+func (m *onnxModel) Metadata(ctx context.Context) (mlmodel.MLMetadata, error) {
+	return m.metadata, nil
+}
+
+// Close releases the model's ONNX Runtime session.
+func (m *onnxModel) Close(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.session == nil {
+		return nil
+	}
+	err := m.session.close()
+	m.session = nil
+	return err
+}
@@ -0,0 +1,214 @@
+package onnx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+	"google.golang.org/protobuf/encoding/protowire"
+	"gorgonia.org/tensor"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/ml"
+	"go.viam.com/rdk/services/mlmodel"
+)
+
+func appendBytesField(b []byte, num protowire.Number, value []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, value)
+}
+
+func appendVarintField(b []byte, num protowire.Number, value uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, value)
+}
+
+// valueInfo returns an ONNX ValueInfoProto of a tensor. Negative dimensions are symbolic.
+func valueInfo(name string, elemType uint64, dims ...int) []byte {
+	var shape []byte
+	for _, dim := range dims {
+		var d []byte
+		if dim >= 0 {
+			d = appendVarintField(d, dimValueField, uint64(dim))
+		} else {
+			d = appendBytesField(d, 2, []byte("batch"))
+		}
+		shape = appendBytesField(shape, shapeDimField, d)
+	}
+	tensorType := appendVarintField(nil, tensorElemTypeField, elemType)
+	tensorType = appendBytesField(tensorType, tensorShapeField, shape)
+	typ := appendBytesField(nil, typeTensorTypeField, tensorType)
+	info := appendBytesField(nil, valueInfoNameField, []byte(name))
+	return appendBytesField(info, valueInfoTypeField, typ)
+}
+
+// detectorModel returns an ONNX model with the inputs and outputs of a detector exported from PyTorch.
+func detectorModel() []byte {
+	var graph []byte
+	graph = appendBytesField(graph, graphNameField, []byte("detector"))
+	graph = appendBytesField(graph, graphInitializerField, appendBytesField(nil, tensorProtoNameField, []byte("weights")))
+	graph = appendBytesField(graph, graphInputField, valueInfo("images", elemTypeFloat32, -1, 3, 320, 320))
+	graph = appendBytesField(graph, graphInputField, valueInfo("weights", elemTypeFloat32, 16))
+	graph = appendBytesField(graph, graphOutputField, valueInfo("boxes", elemTypeFloat32, -1, 4))
+	graph = appendBytesField(graph, graphOutputField, valueInfo("labels", elemTypeInt64, -1))
+
+	var model []byte
+	model = appendVarintField(model, 1, 8) // ir_version
+	model = appendBytesField(model, 2, []byte("pytorch"))
+	model = appendBytesField(model, modelDocStringField, []byte("finds things"))
+	model = appendBytesField(model, modelGraphField, graph)
+	entry := appendBytesField(nil, stringEntryKeyField, []byte(modelTypeKey))
+	entry = appendBytesField(entry, stringEntryValueField, []byte("object_detector"))
+	return appendBytesField(model, modelMetadataPropsField, entry)
+}
+
+func TestReadMetadata(t *testing.T) {
+	md, err := readMetadata(detectorModel())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, md.ModelName, test.ShouldEqual, "detector")
+	test.That(t, md.ModelType, test.ShouldEqual, "object_detector")
+	test.That(t, md.ModelDescription, test.ShouldEqual, "finds things")
+	// initializers are not inputs
+	test.That(t, md.Inputs, test.ShouldResemble, []mlmodel.TensorInfo{
+		{Name: "images", DataType: "float32", Shape: []int{-1, 3, 320, 320}},
+	})
+	test.That(t, md.Outputs, test.ShouldResemble, []mlmodel.TensorInfo{
+		{Name: "boxes", DataType: "float32", Shape: []int{-1, 4}},
+		{Name: "labels", DataType: "int64", Shape: []int{-1}},
+	})
+
+	_, err = readMetadata([]byte("not a model"))
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = readMetadata(appendBytesField(nil, 2, []byte("pytorch")))
+	test.That(t, err, test.ShouldBeError, "invalid ONNX model: it has no graph")
+	strings := appendBytesField(nil, graphInputField, valueInfo("text", 8, 1))
+	strings = appendBytesField(strings, graphOutputField, valueInfo("out", elemTypeFloat32, 1))
+	_, err = readMetadata(appendBytesField(nil, modelGraphField, strings))
+	test.That(t, err, test.ShouldBeError, `tensor "text" has unsupported ONNX element type 8`)
+}
+
+func TestConfigValidate(t *testing.T) {
+	conf := &Config{ModelPath: "model.onnx"}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	conf.ExecutionProvider = ExecutionProviderCUDA
+	conf.CUDADeviceID = 1
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	conf.ExecutionProvider = ExecutionProviderAuto
+	conf.Batching = &mlmodel.BatchConfig{MaxBatchSize: 8}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	conf.Batching.MaxBatchSize = 1
+	_, err = conf.Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "path.batching")
+
+	conf.Batching = nil
+	conf.ExecutionProvider = "tensorrt"
+	_, err = conf.Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "execution_provider")
+
+	_, err = (&Config{}).Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "model_path")
+	_, err = (&Config{ModelPath: "model.onnx", NumThreads: -1}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = (&Config{ModelPath: "model.onnx", CUDADeviceID: -1}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestNewModelErrors(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	dir := t.TempDir()
+	_, err := NewModel(context.Background(), mlmodel.Named("onnx"), &Config{ModelPath: filepath.Join(dir, "missing.onnx")}, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	badModel := filepath.Join(dir, "bad.onnx")
+	test.That(t, os.WriteFile(badModel, []byte("not a model"), 0o600), test.ShouldBeNil)
+	_, err = NewModel(context.Background(), mlmodel.Named("onnx"), &Config{ModelPath: badModel}, logger)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "invalid ONNX model")
+
+	model := filepath.Join(dir, "detector.onnx")
+	test.That(t, os.WriteFile(model, detectorModel(), 0o600), test.ShouldBeNil)
+	_, err = NewModel(context.Background(), mlmodel.Named("onnx"), &Config{
+		ModelPath: model,
+		LabelPath: filepath.Join(dir, "missing.txt"),
+	}, logger)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "missing.txt")
+}
+
+type fakeSession struct {
+	inputs []*namedTensor
+	closed bool
+}
+
+func (s *fakeSession) run(inputs []*namedTensor, outputNames []string) (ml.Tensors, error) {
+	s.inputs = inputs
+	outputs := ml.Tensors{}
+	for _, name := range outputNames {
+		outputs[name] = tensor.New(tensor.WithShape(1), tensor.WithBacking([]float32{1}))
+	}
+	return outputs, nil
+}
+
+func (s *fakeSession) close() error {
+	s.closed = true
+	return nil
+}
+
+func TestInfer(t *testing.T) {
+	md, err := readMetadata(detectorModel())
+	test.That(t, err, test.ShouldBeNil)
+	sess := &fakeSession{}
+	m := &onnxModel{Named: mlmodel.Named("onnx").AsNamed(), logger: logging.NewTestLogger(t), metadata: md, session: sess}
+
+	image := tensor.New(tensor.WithShape(2, 3, 320, 320), tensor.WithBacking(make([]float32, 2*3*320*320)))
+	outputs, err := m.Infer(context.Background(), ml.Tensors{"images": image})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(outputs), test.ShouldEqual, 2)
+	test.That(t, outputs["boxes"], test.ShouldNotBeNil)
+	test.That(t, len(sess.inputs), test.ShouldEqual, 1)
+	test.That(t, sess.inputs[0].tensor, test.ShouldEqual, image)
+
+	// a single input tensor is used for a single input model whatever its name
+	_, err = m.Infer(context.Background(), ml.Tensors{"image": image})
+	test.That(t, err, test.ShouldBeNil)
+
+	_, err = m.Infer(context.Background(), ml.Tensors{"a": image, "b": image})
+	test.That(t, err, test.ShouldBeError, `missing input tensor "images"`)
+	wrongShape := tensor.New(tensor.WithShape(1, 3, 224, 224), tensor.WithBacking(make([]float32, 3*224*224)))
+	_, err = m.Infer(context.Background(), ml.Tensors{"images": wrongShape})
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must have shape [-1 3 320 320]")
+	wrongType := tensor.New(tensor.WithShape(1, 3, 320, 320), tensor.WithBacking(make([]uint8, 3*320*320)))
+	_, err = m.Infer(context.Background(), ml.Tensors{"images": wrongType})
+	test.That(t, err, test.ShouldBeError, `input tensor "images" must be float32, got uint8`)
+
+	test.That(t, m.Close(context.Background()), test.ShouldBeNil)
+	test.That(t, sess.closed, test.ShouldBeTrue)
+	_, err = m.Infer(context.Background(), ml.Tensors{"images": image})
+	test.That(t, err, test.ShouldBeError, "model is closed")
+}
+
+func TestInferBatched(t *testing.T) {
+	md, err := readMetadata(detectorModel())
+	test.That(t, err, test.ShouldBeNil)
+	sess := &fakeSession{}
+	m := &onnxModel{Named: mlmodel.Named("onnx").AsNamed(), logger: logging.NewTestLogger(t), metadata: md, session: sess}
+	m.batcher = mlmodel.NewBatcher(mlmodel.BatchConfig{MaxBatchSize: 2}, m.run)
+
+	// the fake session's outputs are not batched, so each call is inferred on its own
+	image := tensor.New(tensor.WithShape(1, 3, 320, 320), tensor.WithBacking(make([]float32, 3*320*320)))
+	outputs, err := m.Infer(context.Background(), ml.Tensors{"image": image})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, outputs["boxes"].Shape(), test.ShouldResemble, tensor.Shape{1})
+	test.That(t, sess.inputs[0].tensor, test.ShouldEqual, image)
+
+	// inputs are still checked before being batched
+	wrongType := tensor.New(tensor.WithShape(1, 3, 320, 320), tensor.WithBacking(make([]uint8, 3*320*320)))
+	_, err = m.Infer(context.Background(), ml.Tensors{"images": wrongType})
+	test.That(t, err, test.ShouldBeError, `input tensor "images" must be float32, got uint8`)
+}
@@ -0,0 +1,320 @@
+//go:build onnxruntime && cgo
+
+package onnx
+
+/*
+#cgo LDFLAGS: -lonnxruntime
+#include <stdlib.h>
+#include <string.h>
+#include <onnxruntime_c_api.h>
+
+static const OrtApi* ort_api(void) {
+	return OrtGetApiBase()->GetApi(ORT_API_VERSION);
+}
+
+// cgo cannot call the function pointers of the OrtApi table, so each call used is wrapped.
+
+static char* ort_error(OrtStatus* status) {
+	if (status == NULL) {
+		return NULL;
+	}
+	char* msg = strdup(ort_api()->GetErrorMessage(status));
+	ort_api()->ReleaseStatus(status);
+	return msg;
+}
+
+static char* ort_create_session(const void* model, size_t model_len, int num_threads,
+		int use_cuda, const char* cuda_device_id, OrtEnv** env, OrtSession** session) {
+	const OrtApi* api = ort_api();
+	char* err = ort_error(api->CreateEnv(ORT_LOGGING_LEVEL_WARNING, "viam", env));
+	if (err != NULL) {
+		return err;
+	}
+	OrtSessionOptions* options = NULL;
+	if ((err = ort_error(api->CreateSessionOptions(&options))) != NULL) {
+		return err;
+	}
+	if (num_threads > 0) {
+		err = ort_error(api->SetIntraOpNumThreads(options, num_threads));
+	}
+	if (err == NULL && use_cuda) {
+		OrtCUDAProviderOptionsV2* cuda = NULL;
+		err = ort_error(api->CreateCUDAProviderOptions(&cuda));
+		if (err == NULL) {
+			const char* keys[] = {"device_id"};
+			const char* values[] = {cuda_device_id};
+			err = ort_error(api->UpdateCUDAProviderOptions(cuda, keys, values, 1));
+			if (err == NULL) {
+				err = ort_error(api->SessionOptionsAppendExecutionProvider_CUDA_V2(options, cuda));
+			}
+			api->ReleaseCUDAProviderOptions(cuda);
+		}
+	}
+	if (err == NULL) {
+		err = ort_error(api->CreateSessionFromArray(*env, model, model_len, options, session));
+	}
+	api->ReleaseSessionOptions(options);
+	return err;
+}
+
+static char* ort_create_tensor(void* data, size_t data_len, const int64_t* shape, size_t shape_len,
+		ONNXTensorElementDataType elem_type, OrtValue** out) {
+	const OrtApi* api = ort_api();
+	OrtMemoryInfo* info = NULL;
+	char* err = ort_error(api->CreateCpuMemoryInfo(OrtArenaAllocator, OrtMemTypeDefault, &info));
+	if (err != NULL) {
+		return err;
+	}
+	err = ort_error(api->CreateTensorWithDataAsOrtValue(info, data, data_len, shape, shape_len, elem_type, out));
+	api->ReleaseMemoryInfo(info);
+	return err;
+}
+
+static char* ort_run(OrtSession* session, const char** input_names, const OrtValue** inputs, size_t num_inputs,
+		const char** output_names, size_t num_outputs, OrtValue** outputs) {
+	return ort_error(ort_api()->Run(session, NULL, input_names, inputs, num_inputs, output_names, num_outputs, outputs));
+}
+
+static char* ort_tensor_info(const OrtValue* value, ONNXTensorElementDataType* elem_type, int64_t* shape,
+		size_t max_dims, size_t* num_dims, size_t* num_elements, void** data) {
+	const OrtApi* api = ort_api();
+	OrtTensorTypeAndShapeInfo* info = NULL;
+	char* err = ort_error(api->GetTensorTypeAndShape(value, &info));
+	if (err != NULL) {
+		return err;
+	}
+	err = ort_error(api->GetTensorElementType(info, elem_type));
+	if (err == NULL) {
+		err = ort_error(api->GetDimensionsCount(info, num_dims));
+	}
+	if (err == NULL && *num_dims > max_dims) {
+		err = strdup("output tensor has too many dimensions");
+	}
+	if (err == NULL) {
+		err = ort_error(api->GetDimensions(info, shape, *num_dims));
+	}
+	if (err == NULL) {
+		err = ort_error(api->GetTensorShapeElementCount(info, num_elements));
+	}
+	api->ReleaseTensorTypeAndShapeInfo(info);
+	if (err == NULL) {
+		err = ort_error(api->GetTensorMutableData((OrtValue*)value, data));
+	}
+	return err;
+}
+
+static void ort_release_value(OrtValue* value) {
+	if (value != NULL) {
+		ort_api()->ReleaseValue(value);
+	}
+}
+
+static void ort_release_session(OrtEnv* env, OrtSession* session) {
+	if (session != NULL) {
+		ort_api()->ReleaseSession(session);
+	}
+	if (env != NULL) {
+		ort_api()->ReleaseEnv(env);
+	}
+}
+*/
+import "C"
+
+import (
+	"strconv"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"gorgonia.org/tensor"
+
+	"go.viam.com/rdk/ml"
+)
+
+// maxOutputDims is the most dimensions an output tensor can have.
+const maxOutputDims = 16
+
+// elemTypesByDataType are the ONNX element types of the ML model service data types.
+var elemTypesByDataType = func() map[string]int32 {
+	elemTypes := make(map[string]int32, len(dataTypes))
+	for elemType, dataType := range dataTypes {
+		elemTypes[dataType] = elemType
+	}
+	return elemTypes
+}()
+
+type ortSession struct {
+	env     *C.OrtEnv
+	session *C.OrtSession
+}
+
+func newSession(model []byte, conf *Config) (session, error) {
+	cModel := C.CBytes(model)
+	defer C.free(cModel)
+	cDeviceID := C.CString(strconv.Itoa(conf.CUDADeviceID))
+	defer C.free(unsafe.Pointer(cDeviceID))
+	useCUDA := C.int(0)
+	if conf.ExecutionProvider == ExecutionProviderCUDA {
+		useCUDA = 1
+	}
+
+	s := &ortSession{}
+	if err := ortError(C.ort_create_session(
+		cModel, C.size_t(len(model)), C.int(conf.NumThreads), useCUDA, cDeviceID, &s.env, &s.session,
+	)); err != nil {
+		C.ort_release_session(s.env, s.session)
+		return nil, errors.Wrap(err, "cannot create ONNX Runtime session")
+	}
+	return s, nil
+}
+
+func (s *ortSession) run(inputs []*namedTensor, outputNames []string) (ml.Tensors, error) {
+	// ONNX Runtime keeps pointers to the names and tensor data for the whole run, so they are copied
+	// into C memory rather than passed from Go memory.
+	cInputNames := make([]*C.char, len(inputs))
+	cInputs := make([]*C.OrtValue, len(inputs))
+	var cData []unsafe.Pointer
+	defer func() {
+		for i := range inputs {
+			C.free(unsafe.Pointer(cInputNames[i]))
+			C.ort_release_value(cInputs[i])
+		}
+		for _, data := range cData {
+			C.free(data)
+		}
+	}()
+	for i, input := range inputs {
+		cInputNames[i] = C.CString(input.info.Name)
+		raw, err := tensorBytes(input.tensor)
+		if err != nil {
+			return nil, err
+		}
+		data := C.CBytes(raw)
+		cData = append(cData, data)
+		shape := make([]C.int64_t, 0, len(input.tensor.Shape()))
+		for _, dim := range input.tensor.Shape() {
+			shape = append(shape, C.int64_t(dim))
+		}
+		var shapePtr *C.int64_t
+		if len(shape) > 0 {
+			shapePtr = &shape[0]
+		}
+		if err := ortError(C.ort_create_tensor(
+			data, C.size_t(len(raw)), shapePtr, C.size_t(len(shape)),
+			C.ONNXTensorElementDataType(elemTypesByDataType[input.info.DataType]), &cInputs[i],
+		)); err != nil {
+			return nil, errors.Wrapf(err, "cannot create input tensor %q", input.info.Name)
+		}
+	}
+
+	cOutputNames := (**C.char)(C.malloc(C.size_t(len(outputNames)) * C.size_t(unsafe.Sizeof(uintptr(0)))))
+	defer C.free(unsafe.Pointer(cOutputNames))
+	outputNamesSlice := unsafe.Slice(cOutputNames, len(outputNames))
+	for i, name := range outputNames {
+		outputNamesSlice[i] = C.CString(name)
+	}
+	defer func() {
+		for _, name := range outputNamesSlice {
+			C.free(unsafe.Pointer(name))
+		}
+	}()
+	cInputNamesC := (**C.char)(C.malloc(C.size_t(len(inputs)) * C.size_t(unsafe.Sizeof(uintptr(0)))))
+	defer C.free(unsafe.Pointer(cInputNamesC))
+	copy(unsafe.Slice(cInputNamesC, len(inputs)), cInputNames)
+	cInputsC := (**C.OrtValue)(C.malloc(C.size_t(len(inputs)) * C.size_t(unsafe.Sizeof(uintptr(0)))))
+	defer C.free(unsafe.Pointer(cInputsC))
+	copy(unsafe.Slice(cInputsC, len(inputs)), cInputs)
+	cOutputs := (**C.OrtValue)(C.calloc(C.size_t(len(outputNames)), C.size_t(unsafe.Sizeof(uintptr(0)))))
+	defer C.free(unsafe.Pointer(cOutputs))
+	outputs := unsafe.Slice(cOutputs, len(outputNames))
+	defer func() {
+		for _, output := range outputs {
+			C.ort_release_value(output)
+		}
+	}()
+
+	if err := ortError(C.ort_run(
+		s.session, cInputNamesC, cInputsC, C.size_t(len(inputs)), cOutputNames, C.size_t(len(outputNames)), cOutputs,
+	)); err != nil {
+		return nil, errors.Wrap(err, "ONNX Runtime inference failed")
+	}
+
+	results := make(ml.Tensors, len(outputNames))
+	for i, name := range outputNames {
+		t, err := outputTensor(outputs[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read output tensor %q", name)
+		}
+		results[name] = t
+	}
+	return results, nil
+}
+
+// outputTensor copies an ONNX Runtime output into a tensor.
+func outputTensor(value *C.OrtValue) (*tensor.Dense, error) {
+	var elemType C.ONNXTensorElementDataType
+	var cShape [maxOutputDims]C.int64_t
+	var numDims, numElements C.size_t
+	var data unsafe.Pointer
+	if err := ortError(C.ort_tensor_info(
+		value, &elemType, &cShape[0], maxOutputDims, &numDims, &numElements, &data,
+	)); err != nil {
+		return nil, err
+	}
+	shape := make([]int, 0, int(numDims))
+	for _, dim := range cShape[:numDims] {
+		shape = append(shape, int(dim))
+	}
+	n := int(numElements)
+	var backing interface{}
+	switch int32(elemType) {
+	case elemTypeFloat32:
+		backing = append([]float32(nil), unsafe.Slice((*float32)(data), n)...)
+	case elemTypeUint8:
+		backing = append([]uint8(nil), unsafe.Slice((*uint8)(data), n)...)
+	case elemTypeInt8:
+		backing = append([]int8(nil), unsafe.Slice((*int8)(data), n)...)
+	case elemTypeUint16:
+		backing = append([]uint16(nil), unsafe.Slice((*uint16)(data), n)...)
+	case elemTypeInt16:
+		backing = append([]int16(nil), unsafe.Slice((*int16)(data), n)...)
+	case elemTypeInt32:
+		backing = append([]int32(nil), unsafe.Slice((*int32)(data), n)...)
+	case elemTypeInt64:
+		backing = append([]int64(nil), unsafe.Slice((*int64)(data), n)...)
+	case elemTypeFloat64:
+		backing = append([]float64(nil), unsafe.Slice((*float64)(data), n)...)
+	case elemTypeUint32:
+		backing = append([]uint32(nil), unsafe.Slice((*uint32)(data), n)...)
+	case elemTypeUint64:
+		backing = append([]uint64(nil), unsafe.Slice((*uint64)(data), n)...)
+	default:
+		return nil, errors.Errorf("unsupported ONNX element type %d", int32(elemType))
+	}
+	return tensor.New(tensor.WithShape(shape...), tensor.WithBacking(backing)), nil
+}
+
+// tensorBytes returns the raw bytes of a tensor's data.
+func tensorBytes(t *tensor.Dense) ([]byte, error) {
+	if t.Size() == 0 {
+		return nil, errors.New("tensor is empty")
+	}
+	if !t.IsNativelyAccessible() || t.RequiresIterator() {
+		t = t.Materialize().(*tensor.Dense)
+	}
+	return t.Header.Raw, nil
+}
+
+// ortError converts an error message returned by the C wrappers to an error, freeing the message.
+func ortError(msg *C.char) error {
+	if msg == nil {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(msg))
+	return errors.New(C.GoString(msg))
+}
+
+func (s *ortSession) close() error {
+	C.ort_release_session(s.env, s.session)
+	s.env, s.session = nil, nil
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !onnxruntime || !cgo
+
+package onnx
+
+import "github.com/pkg/errors"
+
+func newSession(model []byte, conf *Config) (session, error) {
+	return nil, errors.New("ONNX models need viam-server to be built with ONNX Runtime, using the onnxruntime build tag")
+}
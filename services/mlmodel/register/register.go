@@ -4,4 +4,6 @@ package register
 import (
 	// for ML model service models.
 	_ "go.viam.com/rdk/services/mlmodel"
+	_ "go.viam.com/rdk/services/mlmodel/onnx"
+	_ "go.viam.com/rdk/services/mlmodel/triton"
 )
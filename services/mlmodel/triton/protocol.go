@@ -0,0 +1,308 @@
+package triton
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"go.viam.com/rdk/services/mlmodel"
+)
+
+// The methods of the KServe v2 GRPCInferenceService (grpc_service.proto) that Triton serves.
+const (
+	modelMetadataMethod = "/inference.GRPCInferenceService/ModelMetadata"
+	modelInferMethod    = "/inference.GRPCInferenceService/ModelInfer"
+)
+
+// Field numbers of the parts of the KServe v2 messages that are used. The Triton protos are not
+// vendored, so messages are encoded with protowire instead of generated code.
+const (
+	metadataRequestNameField    = 1
+	metadataRequestVersionField = 2
+
+	metadataResponseNameField     = 1
+	metadataResponsePlatformField = 3
+	metadataResponseInputsField   = 4
+	metadataResponseOutputsField  = 5
+
+	tensorMetadataNameField     = 1
+	tensorMetadataDatatypeField = 2
+	tensorMetadataShapeField    = 3
+
+	inferRequestModelNameField    = 1
+	inferRequestModelVersionField = 2
+	inferRequestInputsField       = 5
+	inferRequestOutputsField      = 6
+	inferRequestRawContentsField  = 7
+
+	inferInputNameField     = 1
+	inferInputDatatypeField = 2
+	inferInputShapeField    = 3
+
+	requestedOutputNameField = 1
+
+	inferResponseOutputsField     = 5
+	inferResponseRawContentsField = 6
+
+	inferOutputNameField     = 1
+	inferOutputDatatypeField = 2
+	inferOutputShapeField    = 3
+)
+
+// dataTypes are the ML model service data types of the Triton data types that can be inferred on.
+var dataTypes = map[string]string{
+	"FP32":   "float32",
+	"FP64":   "float64",
+	"UINT8":  "uint8",
+	"UINT16": "uint16",
+	"UINT32": "uint32",
+	"UINT64": "uint64",
+	"INT8":   "int8",
+	"INT16":  "int16",
+	"INT32":  "int32",
+	"INT64":  "int64",
+}
+
+// tritonDataType returns the Triton data type of an ML model service data type.
+func tritonDataType(dataType string) (string, bool) {
+	for triton, ours := range dataTypes {
+		if ours == dataType {
+			return triton, true
+		}
+	}
+	return "", false
+}
+
+// rawMessage is an encoded protobuf message.
+type rawMessage []byte
+
+// rawCodec passes already encoded messages through gRPC.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	msg, ok := v.(rawMessage)
+	if !ok {
+		return nil, errors.Errorf("cannot marshal %T", v)
+	}
+	return msg, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	msg, ok := v.(*rawMessage)
+	if !ok {
+		return errors.Errorf("cannot unmarshal into %T", v)
+	}
+	*msg = append((*msg)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// invoke calls a method of the inference service.
+func invoke(ctx context.Context, conn *grpc.ClientConn, method string, req rawMessage) (rawMessage, error) {
+	var resp rawMessage
+	if err := conn.Invoke(ctx, method, req, &resp, grpc.ForceCodec(rawCodec{})); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// encodeMetadataRequest returns a ModelMetadataRequest. An empty version is the latest version.
+func encodeMetadataRequest(name, version string) rawMessage {
+	req := appendStringField(nil, metadataRequestNameField, name)
+	if version != "" {
+		req = appendStringField(req, metadataRequestVersionField, version)
+	}
+	return req
+}
+
+// decodeMetadataResponse reads a ModelMetadataResponse. Dimensions without a fixed size are -1.
+func decodeMetadataResponse(resp rawMessage) (mlmodel.MLMetadata, error) {
+	var md mlmodel.MLMetadata
+	err := walkFields(resp, func(num protowire.Number, value []byte) error {
+		switch num {
+		case metadataResponseNameField:
+			md.ModelName = string(value)
+		case metadataResponsePlatformField:
+			md.ModelDescription = "Triton " + string(value) + " model"
+		case metadataResponseInputsField, metadataResponseOutputsField:
+			info, err := decodeTensorMetadata(value)
+			if err != nil {
+				return err
+			}
+			if num == metadataResponseInputsField {
+				md.Inputs = append(md.Inputs, info)
+			} else {
+				md.Outputs = append(md.Outputs, info)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return mlmodel.MLMetadata{}, errors.Wrap(err, "invalid Triton model metadata")
+	}
+	if len(md.Inputs) == 0 || len(md.Outputs) == 0 {
+		return mlmodel.MLMetadata{}, errors.New("invalid Triton model metadata: the model needs at least one input and one output")
+	}
+	return md, nil
+}
+
+// decodeTensorMetadata reads a TensorMetadata.
+func decodeTensorMetadata(msg []byte) (mlmodel.TensorInfo, error) {
+	var info mlmodel.TensorInfo
+	var tritonType string
+	err := walkFields(msg, func(num protowire.Number, value []byte) error {
+		switch num {
+		case tensorMetadataNameField:
+			info.Name = string(value)
+		case tensorMetadataDatatypeField:
+			tritonType = string(value)
+		case tensorMetadataShapeField:
+			dims, err := int64s(value)
+			info.Shape = append(info.Shape, dims...)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return mlmodel.TensorInfo{}, err
+	}
+	dataType, ok := dataTypes[tritonType]
+	if !ok {
+		return mlmodel.TensorInfo{}, errors.Errorf("tensor %q has unsupported Triton data type %q", info.Name, tritonType)
+	}
+	info.DataType = dataType
+	if info.Shape == nil {
+		info.Shape = []int{}
+	}
+	return info, nil
+}
+
+// inferTensor is an input or output tensor of an inference, with its data in little endian.
+type inferTensor struct {
+	name     string
+	dataType string
+	shape    []int
+	raw      []byte
+}
+
+// encodeInferRequest returns a ModelInferRequest with the data of the inputs in raw_input_contents.
+func encodeInferRequest(name, version string, inputs []inferTensor, outputNames []string) rawMessage {
+	req := appendStringField(nil, inferRequestModelNameField, name)
+	if version != "" {
+		req = appendStringField(req, inferRequestModelVersionField, version)
+	}
+	for _, input := range inputs {
+		msg := appendStringField(nil, inferInputNameField, input.name)
+		msg = appendStringField(msg, inferInputDatatypeField, input.dataType)
+		var shape []byte
+		for _, dim := range input.shape {
+			shape = protowire.AppendVarint(shape, uint64(dim))
+		}
+		msg = protowire.AppendTag(msg, inferInputShapeField, protowire.BytesType)
+		msg = protowire.AppendBytes(msg, shape)
+		req = protowire.AppendTag(req, inferRequestInputsField, protowire.BytesType)
+		req = protowire.AppendBytes(req, msg)
+	}
+	for _, output := range outputNames {
+		req = protowire.AppendTag(req, inferRequestOutputsField, protowire.BytesType)
+		req = protowire.AppendBytes(req, appendStringField(nil, requestedOutputNameField, output))
+	}
+	for _, input := range inputs {
+		req = protowire.AppendTag(req, inferRequestRawContentsField, protowire.BytesType)
+		req = protowire.AppendBytes(req, input.raw)
+	}
+	return req
+}
+
+// decodeInferResponse reads the outputs of a ModelInferResponse, which must have their data in
+// raw_output_contents.
+func decodeInferResponse(resp rawMessage) ([]inferTensor, error) {
+	var outputs []inferTensor
+	var contents [][]byte
+	err := walkFields(resp, func(num protowire.Number, value []byte) error {
+		switch num {
+		case inferResponseOutputsField:
+			var output inferTensor
+			if err := walkFields(value, func(num protowire.Number, value []byte) error {
+				switch num {
+				case inferOutputNameField:
+					output.name = string(value)
+				case inferOutputDatatypeField:
+					output.dataType = string(value)
+				case inferOutputShapeField:
+					dims, err := int64s(value)
+					output.shape = append(output.shape, dims...)
+					return err
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			outputs = append(outputs, output)
+		case inferResponseRawContentsField:
+			contents = append(contents, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Triton inference response")
+	}
+	if len(contents) != len(outputs) {
+		return nil, errors.Errorf("invalid Triton inference response: %d outputs but %d raw output contents",
+			len(outputs), len(contents))
+	}
+	for i := range outputs {
+		outputs[i].raw = contents[i]
+	}
+	return outputs, nil
+}
+
+func appendStringField(b []byte, num protowire.Number, value string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+// walkFields calls f with the number and value of each field of a protobuf message. The values of
+// varint fields are passed still encoded.
+func walkFields(msg []byte, f func(num protowire.Number, value []byte) error) error {
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+		n = protowire.ConsumeFieldValue(num, typ, msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		value := msg[:n]
+		if typ == protowire.BytesType {
+			value, _ = protowire.ConsumeBytes(value)
+		}
+		if err := f(num, value); err != nil {
+			return err
+		}
+		msg = msg[n:]
+	}
+	return nil
+}
+
+// int64s decodes the value of a repeated int64 field passed by walkFields, which is either a single
+// varint or, when packed, a run of varints.
+func int64s(value []byte) ([]int, error) {
+	var ret []int
+	for len(value) > 0 {
+		v, n := protowire.ConsumeVarint(value)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		ret = append(ret, int(int64(v)))
+		value = value[n:]
+	}
+	return ret, nil
+}
@@ -0,0 +1,255 @@
+// Package triton implements an ML model service that delegates inference to a model served by a
+// Triton Inference Server over gRPC. Heavy models can then run on a GPU machine while the robot
+// stays thin.
+//
+// Triton batches concurrent inferences itself if dynamic batching is configured in the model's
+// Triton config, so this service does not batch.
+package triton
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"gorgonia.org/tensor"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/ml"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/mlmodel"
+)
+
+// Model is the model of the Triton ML model service.
+var Model = resource.DefaultModelFamily.WithModel("triton")
+
+// maxMessageBytes bounds the size of inference requests and responses, which carry whole tensors.
+const maxMessageBytes = 256 << 20
+
+func init() {
+	resource.RegisterService(mlmodel.API, Model, resource.Registration[mlmodel.Service, *Config]{
+		Constructor: func(
+			ctx context.Context,
+			_ resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (mlmodel.Service, error) {
+			newConf, err := resource.NativeConfig[*Config](conf)
+			if err != nil {
+				return nil, err
+			}
+			return NewModel(ctx, conf.ResourceName(), newConf, logger)
+		},
+	})
+}
+
+// Config describes how to configure the Triton ML model service.
+type Config struct {
+	// Address is the host and port of Triton's gRPC endpoint, e.g: "gpu-box.local:8001".
+	Address string `json:"address"`
+	// ModelName is the name of the model in Triton's model repository.
+	ModelName string `json:"model_name"`
+	// ModelVersion is the version of the model to infer with. Triton picks by its version policy if
+	// unset.
+	ModelVersion string `json:"model_version,omitempty"`
+	// TLS connects to Triton with TLS, e.g: when it is behind a TLS terminating proxy.
+	TLS bool `json:"tls,omitempty"`
+	// LabelPath is a file of labels, one per line, that vision services use to name the classes the
+	// model outputs.
+	LabelPath string `json:"label_path,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.Address == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "address")
+	}
+	if conf.ModelName == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "model_name")
+	}
+	return nil, nil
+}
+
+// tritonModel is an ML model service that infers with a model served by Triton.
+type tritonModel struct {
+	resource.Named
+	resource.AlwaysRebuild
+	logger   logging.Logger
+	conf     *Config
+	metadata mlmodel.MLMetadata
+	conn     *grpc.ClientConn
+}
+
+// NewModel returns an ML model service that infers with the Triton model of `conf`. It fails if
+// Triton cannot be reached or does not serve the model.
+func NewModel(ctx context.Context, name resource.Name, conf *Config, logger logging.Logger) (mlmodel.Service, error) {
+	ctx, span := trace.StartSpan(ctx, "mlmodel::triton::NewModel")
+	defer span.End()
+
+	creds := insecure.NewCredentials()
+	if conf.TLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	conn, err := grpc.NewClient(conf.Address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMessageBytes), grpc.MaxCallSendMsgSize(maxMessageBytes)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := invoke(ctx, conn, modelMetadataMethod, encodeMetadataRequest(conf.ModelName, conf.ModelVersion))
+	if err != nil {
+		return nil, multierr.Combine(errors.Wrapf(err, "cannot get the metadata of Triton model %q", conf.ModelName), conn.Close())
+	}
+	metadata, err := decodeMetadataResponse(resp)
+	if err != nil {
+		return nil, multierr.Combine(err, conn.Close())
+	}
+	if conf.LabelPath != "" {
+		if _, err := os.Stat(conf.LabelPath); err != nil {
+			return nil, multierr.Combine(fmt.Errorf("failed to read file %s: %w", conf.LabelPath, err), conn.Close())
+		}
+		for i := range metadata.Outputs {
+			if metadata.Outputs[i].Extra == nil {
+				metadata.Outputs[i].Extra = map[string]interface{}{}
+			}
+			metadata.Outputs[i].Extra["labels"] = conf.LabelPath
+		}
+	}
+	logger.Debugf("inferring with Triton model %q at %s", metadata.ModelName, conf.Address)
+
+	return &tritonModel{
+		Named:    name.AsNamed(),
+		logger:   logger,
+		conf:     conf,
+		metadata: metadata,
+		conn:     conn,
+	}, nil
+}
+
+// Infer runs the model on `tensors`, which are named by the model's input names. A model with a
+// single input accepts a single tensor of any name.
+func (m *tritonModel) Infer(ctx context.Context, tensors ml.Tensors) (ml.Tensors, error) {
+	ctx, span := trace.StartSpan(ctx, "mlmodel::triton::Infer")
+	defer span.End()
+
+	inputs := make([]inferTensor, 0, len(m.metadata.Inputs))
+	for _, info := range m.metadata.Inputs {
+		t, ok := tensors[info.Name]
+		if !ok && len(m.metadata.Inputs) == 1 && len(tensors) == 1 {
+			for _, only := range tensors {
+				t = only
+			}
+			ok = true
+		}
+		if !ok {
+			return nil, errors.Errorf("missing input tensor %q", info.Name)
+		}
+		input, err := toInferTensor(info, t)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, input)
+	}
+	outputNames := make([]string, 0, len(m.metadata.Outputs))
+	for _, info := range m.metadata.Outputs {
+		outputNames = append(outputNames, info.Name)
+	}
+
+	resp, err := invoke(ctx, m.conn, modelInferMethod,
+		encodeInferRequest(m.conf.ModelName, m.conf.ModelVersion, inputs, outputNames))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Triton inference with model %q failed", m.conf.ModelName)
+	}
+	outputs, err := decodeInferResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(ml.Tensors, len(outputs))
+	for _, output := range outputs {
+		t, err := fromInferTensor(output)
+		if err != nil {
+			return nil, err
+		}
+		ret[output.name] = t
+	}
+	return ret, nil
+}
+
+// toInferTensor checks that `t` can be input as `info`, and returns it as a Triton input. Triton
+// takes data in little endian, which is the byte order of the machines robots run on, so the data
+// is sent as is.
+func toInferTensor(info mlmodel.TensorInfo, t *tensor.Dense) (inferTensor, error) {
+	dataType := t.Dtype().Name()
+	if dataType != info.DataType {
+		return inferTensor{}, errors.Errorf("input tensor %q must be %s, got %s", info.Name, info.DataType, dataType)
+	}
+	shape := t.Shape()
+	if len(info.Shape) > 0 {
+		if len(shape) != len(info.Shape) {
+			return inferTensor{}, errors.Errorf("input tensor %q must have shape %v, got %v", info.Name, info.Shape, shape)
+		}
+		for i, dim := range info.Shape {
+			if dim >= 0 && shape[i] != dim {
+				return inferTensor{}, errors.Errorf("input tensor %q must have shape %v, got %v", info.Name, info.Shape, shape)
+			}
+		}
+	}
+	tritonType, ok := tritonDataType(dataType)
+	if !ok {
+		return inferTensor{}, errors.Errorf("input tensor %q has unsupported data type %s", info.Name, dataType)
+	}
+	if !t.IsNativelyAccessible() || t.RequiresIterator() {
+		t = t.Materialize().(*tensor.Dense)
+	}
+	return inferTensor{name: info.Name, dataType: tritonType, shape: shape, raw: t.Header.Raw}, nil
+}
+
+// tensorDtypes are the tensor types of the ML model service data types.
+var tensorDtypes = map[string]tensor.Dtype{
+	"float32": tensor.Float32,
+	"float64": tensor.Float64,
+	"uint8":   tensor.Uint8,
+	"uint16":  tensor.Uint16,
+	"uint32":  tensor.Uint32,
+	"uint64":  tensor.Uint64,
+	"int8":    tensor.Int8,
+	"int16":   tensor.Int16,
+	"int32":   tensor.Int32,
+	"int64":   tensor.Int64,
+}
+
+// fromInferTensor copies a Triton output into a tensor.
+func fromInferTensor(output inferTensor) (*tensor.Dense, error) {
+	dataType, ok := dataTypes[output.dataType]
+	if !ok {
+		return nil, errors.Errorf("output tensor %q has unsupported Triton data type %q", output.name, output.dataType)
+	}
+	shape := output.shape
+	if len(shape) == 0 {
+		shape = []int{1}
+	}
+	t := tensor.New(tensor.Of(tensorDtypes[dataType]), tensor.WithShape(shape...))
+	if len(output.raw) != len(t.Header.Raw) {
+		return nil, errors.Errorf("output tensor %q of shape %v has %d bytes, expected %d",
+			output.name, output.shape, len(output.raw), len(t.Header.Raw))
+	}
+	copy(t.Header.Raw, output.raw)
+	return t, nil
+}
+
+// Metadata returns the metadata of the model.
+func (m *tritonModel) Metadata(ctx context.Context) (mlmodel.MLMetadata, error) {
+	return m.metadata, nil
+}
+
+// Close closes the connection to Triton.
+func (m *tritonModel) Close(ctx context.Context) error {
+	return m.conn.Close()
+}
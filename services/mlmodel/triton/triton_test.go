@@ -0,0 +1,172 @@
+package triton
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+	"gorgonia.org/tensor"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/ml"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/mlmodel"
+)
+
+func appendBytesField(b []byte, num protowire.Number, value []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, value)
+}
+
+func tensorMetadata(name, dataType string, dims ...int) []byte {
+	msg := appendStringField(nil, tensorMetadataNameField, name)
+	msg = appendStringField(msg, tensorMetadataDatatypeField, dataType)
+	var shape []byte
+	for _, dim := range dims {
+		shape = protowire.AppendVarint(shape, uint64(dim))
+	}
+	return appendBytesField(msg, tensorMetadataShapeField, shape)
+}
+
+// fakeTriton serves a "summer" model, which outputs the sum of its FP32 input.
+type fakeTriton struct {
+	requests []rawMessage
+}
+
+func (ft *fakeTriton) handle(_ any, stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+	var req rawMessage
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+	ft.requests = append(ft.requests, req)
+
+	var resp []byte
+	switch method {
+	case modelMetadataMethod:
+		resp = appendStringField(resp, metadataResponseNameField, "summer")
+		resp = appendStringField(resp, metadataResponsePlatformField, "onnxruntime_onnx")
+		resp = appendBytesField(resp, metadataResponseInputsField, tensorMetadata("values", "FP32", -1, 3))
+		resp = appendBytesField(resp, metadataResponseOutputsField, tensorMetadata("sum", "FP32", 1))
+	case modelInferMethod:
+		var sum float32
+		if err := walkFields(req, func(num protowire.Number, value []byte) error {
+			if num == inferRequestRawContentsField {
+				for i := 0; i < len(value); i += 4 {
+					sum += math.Float32frombits(binary.LittleEndian.Uint32(value[i:]))
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		output := appendStringField(nil, inferOutputNameField, "sum")
+		output = appendStringField(output, inferOutputDatatypeField, "FP32")
+		output = appendBytesField(output, inferOutputShapeField, protowire.AppendVarint(nil, 1))
+		resp = appendBytesField(resp, inferResponseOutputsField, output)
+		resp = appendBytesField(resp, inferResponseRawContentsField, binary.LittleEndian.AppendUint32(nil, math.Float32bits(sum)))
+	default:
+		return errors.Errorf("unexpected method %s", method)
+	}
+	return stream.SendMsg(rawMessage(resp))
+}
+
+func TestTritonModel(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	fake := &fakeTriton{}
+	server := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}), grpc.UnknownServiceHandler(fake.handle))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	name := mlmodel.Named("triton")
+	conf := &Config{Address: listener.Addr().String(), ModelName: "summer", ModelVersion: "2"}
+	model, err := NewModel(ctx, name, conf, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, model.Close(ctx), test.ShouldBeNil)
+	}()
+
+	md, err := model.Metadata(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, md.ModelName, test.ShouldEqual, "summer")
+	test.That(t, md.ModelDescription, test.ShouldEqual, "Triton onnxruntime_onnx model")
+	test.That(t, md.Inputs, test.ShouldResemble, []mlmodel.TensorInfo{{Name: "values", DataType: "float32", Shape: []int{-1, 3}}})
+	test.That(t, md.Outputs, test.ShouldResemble, []mlmodel.TensorInfo{{Name: "sum", DataType: "float32", Shape: []int{1}}})
+	test.That(t, fake.requests[0], test.ShouldResemble, encodeMetadataRequest("summer", "2"))
+
+	// A model with a single input accepts a tensor of any name.
+	values := tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]float32{1, 2, 3, 4, 5, 6.5}))
+	outputs, err := model.Infer(ctx, ml.Tensors{"input": values})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, outputs["sum"].Shape(), test.ShouldResemble, tensor.Shape{1})
+	test.That(t, outputs["sum"].Data(), test.ShouldResemble, []float32{21.5})
+
+	// The request names the model, its version, the input and the output.
+	var modelName, modelVersion string
+	var inputShape []int
+	var outputNames []string
+	test.That(t, walkFields(fake.requests[1], func(num protowire.Number, value []byte) error {
+		switch num {
+		case inferRequestModelNameField:
+			modelName = string(value)
+		case inferRequestModelVersionField:
+			modelVersion = string(value)
+		case inferRequestInputsField:
+			return walkFields(value, func(num protowire.Number, value []byte) error {
+				if num == inferInputShapeField {
+					dims, err := int64s(value)
+					inputShape = dims
+					return err
+				}
+				return nil
+			})
+		case inferRequestOutputsField:
+			return walkFields(value, func(num protowire.Number, value []byte) error {
+				if num == requestedOutputNameField {
+					outputNames = append(outputNames, string(value))
+				}
+				return nil
+			})
+		}
+		return nil
+	}), test.ShouldBeNil)
+	test.That(t, modelName, test.ShouldEqual, "summer")
+	test.That(t, modelVersion, test.ShouldEqual, "2")
+	test.That(t, inputShape, test.ShouldResemble, []int{2, 3})
+	test.That(t, outputNames, test.ShouldResemble, []string{"sum"})
+
+	_, err = model.Infer(ctx, ml.Tensors{"values": tensor.New(tensor.WithShape(2, 4), tensor.Of(tensor.Float32))})
+	test.That(t, err, test.ShouldBeError, "input tensor \"values\" must have shape [-1 3], got (2, 4)")
+	_, err = model.Infer(ctx, ml.Tensors{"values": tensor.New(tensor.WithShape(2, 3), tensor.Of(tensor.Int32))})
+	test.That(t, err, test.ShouldBeError, "input tensor \"values\" must be float32, got int32")
+
+	t.Run("unreachable", func(t *testing.T) {
+		_, err := NewModel(ctx, name, &Config{Address: "localhost:1", ModelName: "summer"}, logger)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `cannot get the metadata of Triton model "summer"`)
+	})
+}
+
+func TestConfigValidate(t *testing.T) {
+	_, err := (&Config{Address: "localhost:8001", ModelName: "summer"}).Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = (&Config{ModelName: "summer"}).Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "address")
+	_, err = (&Config{Address: "localhost:8001"}).Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "model_name")
+}
+
+func TestRegistered(t *testing.T) {
+	_, ok := resource.LookupRegistration(mlmodel.API, Model)
+	test.That(t, ok, test.ShouldBeTrue)
+}
@@ -4,14 +4,18 @@ package builtin
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-viper/mapstructure/v2"
 	"github.com/golang/geo/r3"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 	pb "go.viam.com/api/service/motion/v1"
+	goutils "go.viam.com/utils"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/structpb"
 
@@ -47,8 +51,15 @@ func init() {
 
 // export keys to be used with DoCommand so they can be referenced by clients.
 const (
-	DoPlan    = "plan"
-	DoExecute = "execute"
+	DoPlan              = "plan"
+	DoExecute           = "execute"
+	DoUpdateWorldState  = "update_world_state"
+	DoCachePlan         = "cache_plan"
+	DoExecuteCachedPlan = "execute_cached_plan"
+	DoExportPlan        = "export_plan"
+	DoExportFormat      = "export_format"
+	DoMoveThroughJoints = "move_through_joints"
+	DoUpdateFrames      = "update_frames"
 )
 
 const (
@@ -83,10 +94,17 @@ type inputEnabledActuator interface {
 // Config describes how to configure the service; currently only used for specifying dependency on framesystem service.
 type Config struct {
 	LogFilePath string `json:"log_file_path"`
+	// ExecutionMonitoring, if set, stops moves whose components don't follow their plans.
+	ExecutionMonitoring *motion.ExecutionMonitoringConfig `json:"execution_monitoring,omitempty"`
 }
 
 // Validate here adds a dependency on the internal framesystem service.
 func (c *Config) Validate(path string) ([]string, error) {
+	if c.ExecutionMonitoring != nil {
+		if err := c.ExecutionMonitoring.Validate(); err != nil {
+			return nil, resource.NewConfigValidationError(path+".execution_monitoring", err)
+		}
+	}
 	return []string{framesystem.InternalServiceName.String()}, nil
 }
 
@@ -95,8 +113,10 @@ func NewBuiltIn(
 	ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger,
 ) (motion.Service, error) {
 	ms := &builtIn{
-		Named:  conf.ResourceName().AsNamed(),
-		logger: logger,
+		Named:             conf.ResourceName().AsNamed(),
+		logger:            logger,
+		worldStateUpdates: newWorldStateUpdates(),
+		planCache:         newPlanCache(),
 	}
 
 	if err := ms.Reconfigure(ctx, deps, conf); err != nil {
@@ -144,6 +164,7 @@ func (ms *builtIn) Reconfigure(
 	ms.slamServices = slamServices
 	ms.visionServices = visionServices
 	ms.components = components
+	ms.trackingMonitor = newTrackingMonitor(config.ExecutionMonitoring)
 	if ms.state != nil {
 		ms.state.Stop()
 	}
@@ -166,6 +187,34 @@ type builtIn struct {
 	components      map[resource.Name]resource.Resource
 	logger          logging.Logger
 	state           *state.State
+	// worldStateUpdates are added to the world state of arm moves, including those in progress
+	worldStateUpdates *worldStateUpdates
+	// planCache holds plans to replay instead of replanning repeated moves
+	planCache *planCache
+	// geofenceViolations counts the MoveOnGlobe destinations, plans and executions that violated a geofence
+	geofenceViolations atomic.Int64
+	// localizationDegradations counts the MoveOnMap requests and executions stopped because localization degraded
+	localizationDegradations atomic.Int64
+	// trackingMonitor checks that components follow their plans while executing them, if configured
+	trackingMonitor *trackingMonitor
+	// trackingErrors counts the moves stopped because a component exceeded its tracking error limit
+	trackingErrors atomic.Int64
+}
+
+// motionStats are the statistics of the motion service recorded by FTDC.
+type motionStats struct {
+	GeofenceViolations       int64
+	LocalizationDegradations int64
+	TrackingErrors           int64
+}
+
+// Stats returns the statistics of the motion service, which are recorded by FTDC.
+func (ms *builtIn) Stats() any {
+	return motionStats{
+		GeofenceViolations:       ms.geofenceViolations.Load(),
+		LocalizationDegradations: ms.localizationDegradations.Load(),
+		TrackingErrors:           ms.trackingErrors.Load(),
+	}
 }
 
 func (ms *builtIn) Close(ctx context.Context) error {
@@ -182,11 +231,7 @@ func (ms *builtIn) Move(ctx context.Context, req motion.MoveReq) (bool, error) {
 	defer ms.mu.RUnlock()
 	operation.CancelOtherWithLabel(ctx, builtinOpLabel)
 
-	plan, err := ms.plan(ctx, req)
-	if err != nil {
-		return false, err
-	}
-	err = ms.execute(ctx, plan.Trajectory())
+	err := ms.moveWithWorldStateUpdates(ctx, req)
 	return err == nil, err
 }
 
@@ -210,10 +255,13 @@ func (ms *builtIn) MoveOnMap(ctx context.Context, req motion.MoveOnMapReq) (moti
 }
 
 type validatedExtra struct {
-	maxReplans       int
-	replanCostFactor float64
-	motionProfile    string
-	extra            map[string]interface{}
+	maxReplans          int
+	replanCostFactor    float64
+	motionProfile       string
+	obstacleClearanceMM float64
+	// minLocalizationScore is the lowest SLAM match score MoveOnMap keeps moving at
+	minLocalizationScore float64
+	extra                map[string]interface{}
 }
 
 func newValidatedExtra(extra map[string]interface{}) (validatedExtra, error) {
@@ -226,9 +274,29 @@ func newValidatedExtra(extra map[string]interface{}) (validatedExtra, error) {
 		return v, nil
 	}
 	if replansRaw, ok := extra["max_replans"]; ok {
-		if replans, ok := replansRaw.(int); ok {
+		switch replans := replansRaw.(type) {
+		case int:
 			maxReplans = replans
+		case float64:
+			// numbers in extras sent over the network are float64s
+			maxReplans = int(replans)
+		}
+	}
+	var obstacleClearanceMM float64
+	if clearanceRaw, ok := extra["obstacle_clearance_mm"]; ok {
+		clearance, ok := clearanceRaw.(float64)
+		if !ok || clearance < 0 {
+			return validatedExtra{}, errors.New("obstacle_clearance_mm must be a non-negative number")
 		}
+		obstacleClearanceMM = clearance
+	}
+	var minLocalizationScore float64
+	if scoreRaw, ok := extra["min_localization_score"]; ok {
+		score, ok := scoreRaw.(float64)
+		if !ok || score < 0 || score > 1 {
+			return validatedExtra{}, errors.New("min_localization_score must be a number between 0 and 1")
+		}
+		minLocalizationScore = score
 	}
 	if profile, ok := extra["motion_profile"]; ok {
 		motionProfile, ok = profile.(string)
@@ -249,10 +317,12 @@ func newValidatedExtra(extra map[string]interface{}) (validatedExtra, error) {
 	}
 
 	return validatedExtra{
-		maxReplans:       maxReplans,
-		motionProfile:    motionProfile,
-		replanCostFactor: replanCostFactor,
-		extra:            extra,
+		maxReplans:           maxReplans,
+		motionProfile:        motionProfile,
+		replanCostFactor:     replanCostFactor,
+		obstacleClearanceMM:  obstacleClearanceMM,
+		minLocalizationScore: minLocalizationScore,
+		extra:                extra,
 	}, nil
 }
 
@@ -333,7 +403,7 @@ func (ms *builtIn) PlanHistory(
 	return ms.state.PlanHistory(req)
 }
 
-// DoCommand supports two commands which are specified through the command map
+// DoCommand supports eight commands which are specified through the command map
 //   - DoPlan generates and returns a Trajectory for a given motionpb.MoveRequest without executing it
 //     required key: DoPlan
 //     input value: a motionpb.MoveRequest which will be used to create a Trajectory
@@ -342,31 +412,75 @@ func (ms *builtIn) PlanHistory(
 //     required key: DoExecute
 //     input value: a motionplan.Trajectory
 //     output value: a bool
+//   - DoUpdateWorldState sets obstacles that are added to the world state of Move requests, including those already
+//     executing, which are stopped and replanned if an obstacle blocks their remaining trajectory. It does not cancel
+//     in progress moves.
+//     required key: DoUpdateWorldState
+//     input value: a commonpb.WorldState, or an empty string to clear the obstacles
+//     output value: a bool
+//   - DoCachePlan plans a motionpb.MoveRequest from the current state of the robot and caches the plan, or finds the
+//     plan already cached for the same start, goal and world state, without executing it
+//     required key: DoCachePlan
+//     input value: a motionpb.MoveRequest
+//     output value: the key of the cached plan
+//   - DoExecuteCachedPlan executes a plan cached with DoCachePlan without replanning it, if the robot is at its start
+//     required key: DoExecuteCachedPlan
+//     input value: the key of the cached plan
+//     output value: a bool
+//   - DoExportPlan plans a motionpb.MoveRequest without executing it and exports the plan, with the geometries of the
+//     robot at each step and the obstacles of the request, so that it can be inspected
+//     required key: DoExportPlan
+//     optional key: DoExportFormat, "json" for a motionplan.PlanScene (the default) or "threejs" for a three.js scene
+//     input value: a motionpb.MoveRequest
+//     output value: the exported plan as a string
+//   - DoMoveThroughJoints moves a component through joint-space waypoints in straight lines, optionally blending through
+//     them, after checking the path for collisions with the obstacles of its world state and of DoUpdateWorldState
+//     required key: DoMoveThroughJoints
+//     input value: a JointWaypointsRequest as a map
+//     output value: a bool
+//   - DoUpdateFrames adds, updates and removes frames of the machine's frame system without reconfiguring it, all at
+//     once or not at all, so that later requests and clients of the machine use the new frames
+//     required key: DoUpdateFrames
+//     input value: a FrameUpdateRequest as a map
+//     output value: a bool
+//
+// Move requests whose extra has "use_plan_cache" set to true also replay cached plans, and cache the plans they make.
 func (ms *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
-	operation.CancelOtherWithLabel(ctx, builtinOpLabel)
 
 	resp := make(map[string]interface{}, 0)
-	if req, ok := cmd[DoPlan]; ok {
+	if req, ok := cmd[DoUpdateWorldState]; ok {
 		s, err := utils.AssertType[string](req)
 		if err != nil {
 			return nil, err
 		}
-		var moveReqProto pb.MoveRequest
-		err = protojson.Unmarshal([]byte(s), &moveReqProto)
+		worldState, err := worldStateFromJSON(s)
 		if err != nil {
 			return nil, err
 		}
-		fields := moveReqProto.Extra.AsMap()
-		if extra, err := utils.AssertType[map[string]interface{}](fields["fields"]); err == nil {
-			v, err := structpb.NewStruct(extra)
-			if err != nil {
-				return nil, err
-			}
-			moveReqProto.Extra = v
+		ms.worldStateUpdates.set(worldState)
+		resp[DoUpdateWorldState] = true
+	}
+	if req, ok := cmd[DoUpdateFrames]; ok {
+		if err := ms.updateFrames(ctx, req); err != nil {
+			return nil, err
 		}
-		moveReq, err := motion.MoveReqFromProto(&moveReqProto)
+		resp[DoUpdateFrames] = true
+	}
+	_, doPlan := cmd[DoPlan]
+	_, doExecute := cmd[DoExecute]
+	_, doCachePlan := cmd[DoCachePlan]
+	_, doExecuteCachedPlan := cmd[DoExecuteCachedPlan]
+	_, doExportPlan := cmd[DoExportPlan]
+	_, doMoveThroughJoints := cmd[DoMoveThroughJoints]
+	if !doPlan && !doExecute && !doCachePlan && !doExecuteCachedPlan && !doExportPlan && !doMoveThroughJoints {
+		return resp, nil
+	}
+	operation.CancelOtherWithLabel(ctx, builtinOpLabel)
+
+	if req, ok := cmd[DoPlan]; ok {
+		moveReq, err := moveReqFromDoCommand(req)
 		if err != nil {
 			return nil, err
 		}
@@ -386,9 +500,100 @@ func (ms *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (m
 		}
 		resp[DoExecute] = true
 	}
+	if req, ok := cmd[DoCachePlan]; ok {
+		moveReq, err := moveReqFromDoCommand(req)
+		if err != nil {
+			return nil, err
+		}
+		_, key, err := ms.cachePlan(ctx, moveReq)
+		if err != nil {
+			return nil, err
+		}
+		resp[DoCachePlan] = key
+	}
+	if req, ok := cmd[DoExecuteCachedPlan]; ok {
+		key, err := utils.AssertType[string](req)
+		if err != nil {
+			return nil, err
+		}
+		if err := ms.executeCachedPlan(ctx, key); err != nil {
+			return nil, err
+		}
+		resp[DoExecuteCachedPlan] = true
+	}
+	if req, ok := cmd[DoExportPlan]; ok {
+		moveReq, err := moveReqFromDoCommand(req)
+		if err != nil {
+			return nil, err
+		}
+		format := motionplan.PlanExportJSON
+		if rawFormat, ok := cmd[DoExportFormat]; ok {
+			formatString, err := utils.AssertType[string](rawFormat)
+			if err != nil {
+				return nil, err
+			}
+			format = motionplan.PlanExportFormat(formatString)
+		}
+		exported, err := ms.exportPlan(ctx, moveReq, format)
+		if err != nil {
+			return nil, err
+		}
+		resp[DoExportPlan] = string(exported)
+	}
+	if req, ok := cmd[DoMoveThroughJoints]; ok {
+		plan, err := ms.planJointWaypoints(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if err := ms.executeTimed(ctx, plan.Trajectory(), plan.Times()); err != nil {
+			return nil, err
+		}
+		resp[DoMoveThroughJoints] = true
+	}
 	return resp, nil
 }
 
+// exportPlan plans `req`, among the obstacles of the world state updates as Move would, and exports the plan in
+// `format`.
+func (ms *builtIn) exportPlan(ctx context.Context, req motion.MoveReq, format motionplan.PlanExportFormat) ([]byte, error) {
+	updates, _ := ms.worldStateUpdates.get()
+	req, err := withWorldStateUpdates(req, updates)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := ms.plan(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	frameSys, err := ms.fsService.FrameSystem(ctx, req.WorldState.Transforms())
+	if err != nil {
+		return nil, err
+	}
+	return motionplan.ExportPlan(plan, frameSys, req.WorldState, format)
+}
+
+// moveReqFromDoCommand returns the move request of a DoCommand, given as a motionpb.MoveRequest in JSON.
+func moveReqFromDoCommand(req interface{}) (motion.MoveReq, error) {
+	s, err := utils.AssertType[string](req)
+	if err != nil {
+		return motion.MoveReq{}, err
+	}
+	var moveReqProto pb.MoveRequest
+	err = protojson.Unmarshal([]byte(s), &moveReqProto)
+	if err != nil {
+		return motion.MoveReq{}, err
+	}
+	fields := moveReqProto.Extra.AsMap()
+	if extra, err := utils.AssertType[map[string]interface{}](fields["fields"]); err == nil {
+		v, err := structpb.NewStruct(extra)
+		if err != nil {
+			return motion.MoveReq{}, err
+		}
+		moveReqProto.Extra = v
+	}
+	return motion.MoveReqFromProto(&moveReqProto)
+}
+
 func (ms *builtIn) plan(ctx context.Context, req motion.MoveReq) (motionplan.Plan, error) {
 	frameSys, err := ms.fsService.FrameSystem(ctx, req.WorldState.Transforms())
 	if err != nil {
@@ -417,7 +622,9 @@ func (ms *builtIn) plan(ctx context.Context, req motion.MoveReq) (motionplan.Pla
 	// The contents of waypoints can be gigantic, and if so, making copies of `extra` becomes the majority of motion planning runtime.
 	// As the meaning from `waypoints` has already been extracted above into its proper data structure, there is no longer a need to
 	// keep it in `extra`.
+	// The caller's extra is left as is, so that the request can be planned again.
 	if req.Extra != nil {
+		req.Extra = maps.Clone(req.Extra)
 		req.Extra["waypoints"] = nil
 	}
 
@@ -522,23 +729,138 @@ func (ms *builtIn) execute(ctx context.Context, trajectory motionplan.Trajectory
 	combinedSteps = append(combinedSteps, currStep)
 
 	for _, step := range combinedSteps {
+		if err := ms.goToInputsTogether(ctx, resources, step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// goToInputsTogether moves the components of `step` together, monitoring their tracking error if configured.
+func (ms *builtIn) goToInputsTogether(
+	ctx context.Context,
+	resources map[string]framesystem.InputEnabled,
+	step map[string][][]referenceframe.Input,
+) error {
+	err := goToInputsTogether(ctx, resources, step, ms.trackingMonitor)
+	if errors.Is(err, motion.ErrTrackingErrorExceeded) {
+		ms.trackingErrors.Add(1)
+		ms.logger.CWarn(ctx, err.Error())
+	}
+	return err
+}
+
+// goToInputsTogether moves each component of `step` through its inputs at the same time, so that components planned to
+// move together, such as the arms of a multi-arm move, stay in sync. If any component fails to move, the components of
+// the step are stopped. If `monitor` is not nil, the components are also stopped once one of them doesn't follow its
+// path, with a motion.TrackingError.
+func goToInputsTogether(
+	ctx context.Context,
+	resources map[string]framesystem.InputEnabled,
+	step map[string][][]referenceframe.Input,
+	monitor *trackingMonitor,
+) error {
+	moving := map[string]framesystem.InputEnabled{}
+	for name, inputs := range step {
+		if len(inputs) == 0 {
+			continue
+		}
+		r, ok := resources[name]
+		if !ok {
+			return fmt.Errorf("plan had step for resource %s but no resource with that name found in framesystem", name)
+		}
+		moving[name] = r
+	}
+
+	var paths []trackedPath
+	if monitor != nil {
+		var err error
+		if paths, err = monitor.paths(ctx, moving, step); err != nil {
+			return err
+		}
+	}
+	moveCtx, cancelMove := context.WithCancel(ctx)
+	defer cancelMove()
+	var trackingErr error
+	watchDone := make(chan struct{})
+	if len(paths) > 0 {
+		goutils.PanicCapturingGo(func() {
+			defer close(watchDone)
+			if trackingErr = monitor.watch(moveCtx, paths); trackingErr != nil {
+				cancelMove()
+			}
+		})
+	} else {
+		close(watchDone)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var moveErr error
+	for name, r := range moving {
+		wg.Add(1)
+		goutils.PanicCapturingGo(func() {
+			defer wg.Done()
+			if err := r.GoToInputs(moveCtx, step[name]...); err != nil {
+				mu.Lock()
+				moveErr = multierr.Combine(moveErr, err)
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	cancelMove()
+	<-watchDone
+	switch {
+	case trackingErr != nil:
+		// the components were cancelled because of the tracking error
+		moveErr = trackingErr
+	case moveErr == nil && len(paths) > 0:
+		moveErr = monitor.checkArrival(ctx, paths)
+	}
+	if moveErr == nil {
+		return nil
+	}
+
+	// If there is an error on GoToInputs, stop the components if possible before returning the error
+	for _, r := range moving {
+		if actuator, ok := r.(inputEnabledActuator); ok {
+			if stopErr := actuator.Stop(ctx, nil); stopErr != nil {
+				return errors.Wrap(moveErr, stopErr.Error())
+			}
+		}
+	}
+	return moveErr
+}
+
+// executeTimed executes a time parameterized trajectory, moving the components to each step no earlier than its time.
+// Steps are not batched, so the components cannot move through them faster than the plan's velocity constraints allow.
+func (ms *builtIn) executeTimed(ctx context.Context, trajectory motionplan.Trajectory, times []time.Duration) error {
+	if len(times) != len(trajectory) {
+		return fmt.Errorf("trajectory has %d steps but %d times", len(trajectory), len(times))
+	}
+	_, resources, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	for i, step := range trajectory {
+		if !goutils.SelectContextOrWait(ctx, times[i]-time.Since(start)) {
+			return ctx.Err()
+		}
+		changed := map[string][][]referenceframe.Input{}
 		for name, inputs := range step {
 			if len(inputs) == 0 {
 				continue
 			}
-			r, ok := resources[name]
-			if !ok {
-				return fmt.Errorf("plan had step for resource %s but no resource with that name found in framesystem", name)
-			}
-			if err := r.GoToInputs(ctx, inputs...); err != nil {
-				// If there is an error on GoToInputs, stop the component if possible before returning the error
-				if actuator, ok := r.(inputEnabledActuator); ok {
-					if stopErr := actuator.Stop(ctx, nil); stopErr != nil {
-						return errors.Wrap(err, stopErr.Error())
-					}
-				}
-				return err
+			if i > 0 && referenceframe.InputsL2Distance(trajectory[i-1][name], inputs) == 0 {
+				continue
 			}
+			changed[name] = [][]referenceframe.Input{inputs}
+		}
+		if err := ms.goToInputsTogether(ctx, resources, changed); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -597,9 +919,16 @@ func waypointsFromRequest(
 		} else {
 			return nil, nil, errors.New("extras goal_state could not be interpreted as map[string]interface{}")
 		}
-	} else if req.Destination != nil {
-		goalState := motionplan.NewPlanState(referenceframe.FrameSystemPoses{req.ComponentName.ShortName(): req.Destination}, nil)
-		waypoints = append(waypoints, goalState)
+	} else if req.Destination != nil || len(req.Destinations) > 0 {
+		// the components are planned to their destinations together, so that they do not collide with each other
+		goalPoses := referenceframe.FrameSystemPoses{}
+		if req.Destination != nil {
+			goalPoses[req.ComponentName.ShortName()] = req.Destination
+		}
+		for name, destination := range req.Destinations {
+			goalPoses[name.ShortName()] = destination
+		}
+		waypoints = append(waypoints, motionplan.NewPlanState(goalPoses, nil))
 	}
 	return startState, waypoints, nil
 }
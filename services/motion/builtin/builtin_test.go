@@ -2,9 +2,12 @@ package builtin
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -31,6 +34,7 @@ import (
 	"go.viam.com/rdk/pointcloud"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/framesystem"
 	robotimpl "go.viam.com/rdk/robot/impl"
 	"go.viam.com/rdk/services/motion"
 	"go.viam.com/rdk/services/motion/builtin/state"
@@ -186,6 +190,16 @@ func TestArmMove(t *testing.T) {
 		test.That(t, err, test.ShouldBeNil)
 	})
 
+	t.Run("succeeds with velocity constraints", func(t *testing.T) {
+		ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+		defer teardown()
+		constraints := motionplan.NewEmptyConstraints()
+		constraints.AddVelocityConstraint(motionplan.VelocityConstraint{MaxJointVelDegsPerSec: 720, MaxJointAccDegsPerSec2: 1440})
+		grabPose := referenceframe.NewPoseInFrame("c", spatialmath.NewPoseFromPoint(r3.Vector{X: 0, Y: -30, Z: -50}))
+		_, err = ms.Move(ctx, motion.MoveReq{ComponentName: gripper.Named("pieceGripper"), Destination: grabPose, Constraints: constraints})
+		test.That(t, err, test.ShouldBeNil)
+	})
+
 	t.Run("succeeds with supplemental info in world state", func(t *testing.T) {
 		ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
 		defer teardown()
@@ -1323,6 +1337,115 @@ func TestDoCommand(t *testing.T) {
 		test.That(t, resp, test.ShouldBeTrue)
 	})
 
+	t.Run("DoExportPlan", func(t *testing.T) {
+		ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+		defer teardown()
+
+		proto, err := moveReq.ToProto(ms.Name().Name)
+		test.That(t, err, test.ShouldBeNil)
+		bytes, err := protojson.Marshal(proto)
+		test.That(t, err, test.ShouldBeNil)
+
+		respMap, err := doOverWire(ms, map[string]interface{}{DoExportPlan: string(bytes)})
+		test.That(t, err, test.ShouldBeNil)
+		exported, ok := respMap[DoExportPlan].(string)
+		test.That(t, ok, test.ShouldBeTrue)
+		var scene motionplan.PlanScene
+		test.That(t, json.Unmarshal([]byte(exported), &scene), test.ShouldBeNil)
+		test.That(t, len(scene.Steps), test.ShouldEqual, 2)
+		test.That(t, len(scene.Obstacles), test.ShouldEqual, 1)
+		test.That(t, scene.Steps[0].Geometries, test.ShouldNotBeEmpty)
+
+		respMap, err = doOverWire(ms, map[string]interface{}{DoExportPlan: string(bytes), DoExportFormat: "threejs"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, respMap[DoExportPlan], test.ShouldContainSubstring, "BoxGeometry")
+
+		_, err = doOverWire(ms, map[string]interface{}{DoExportPlan: string(bytes), DoExportFormat: "obj"})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("DoMoveThroughJoints", func(t *testing.T) {
+		ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+		defer teardown()
+
+		cmd := map[string]interface{}{DoMoveThroughJoints: map[string]interface{}{
+			"component_name":             "pieceArm",
+			"waypoints":                  []interface{}{[]interface{}{0.2, 0, 0, 0, 0, 0}, []interface{}{0.2, -0.2, 0, 0, 0, 0}},
+			"blend_radius":               0.05,
+			"max_joint_vel_degs_per_sec": 360.,
+		}}
+		respMap, err := doOverWire(ms, cmd)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, respMap[DoMoveThroughJoints], test.ShouldBeTrue)
+		_, resources, err := ms.(*builtIn).fsService.CurrentInputs(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		inputs, err := resources["pieceArm"].CurrentInputs(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, referenceframe.InputsToFloats(inputs), test.ShouldResemble, []float64{0.2, -0.2, 0, 0, 0, 0})
+
+		// paths are checked against the obstacles of the request, such as one the arm would pass through
+		frameSys, err := ms.(*builtIn).fsService.FrameSystem(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		midInputs := referenceframe.FrameSystemInputs{"pieceArm": referenceframe.FloatsToInputs([]float64{0.7, -0.2, 0, 0, 0, 0})}
+		midPoses, err := midInputs.ComputePoses(frameSys)
+		test.That(t, err, test.ShouldBeNil)
+		obstacle, err := spatialmath.NewBox(midPoses["pieceArm"].Pose(), r3.Vector{X: 100, Y: 100, Z: 100}, "obstacle")
+		test.That(t, err, test.ShouldBeNil)
+		obstacleState, err := referenceframe.NewWorldState(
+			[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{obstacle})},
+			nil,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		obstacleProto, err := obstacleState.ToProtobuf()
+		test.That(t, err, test.ShouldBeNil)
+		obstacleJSON, err := protojson.Marshal(obstacleProto)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = doOverWire(ms, map[string]interface{}{DoMoveThroughJoints: map[string]interface{}{
+			"component_name": "pieceArm",
+			"waypoints":      []interface{}{[]interface{}{1.2, -0.2, 0, 0, 0, 0}},
+			"world_state":    string(obstacleJSON),
+		}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "collides")
+
+		_, err = doOverWire(ms, map[string]interface{}{DoMoveThroughJoints: map[string]interface{}{
+			"component_name": "pieceArm",
+			"waypoints":      []interface{}{[]interface{}{0}},
+		}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "degrees of freedom")
+	})
+
+	t.Run("DoUpdateFrames", func(t *testing.T) {
+		ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+		defer teardown()
+
+		tool := referenceframe.NewLinkInFrame("pieceArm", spatialmath.NewPoseFromPoint(r3.Vector{Z: 100}), "tool", nil)
+		toolProto, err := referenceframe.LinkInFrameToTransformProtobuf(tool)
+		test.That(t, err, test.ShouldBeNil)
+		toolJSON, err := protojson.Marshal(toolProto)
+		test.That(t, err, test.ShouldBeNil)
+		respMap, err := doOverWire(ms, map[string]interface{}{DoUpdateFrames: map[string]interface{}{
+			"upsert": []interface{}{string(toolJSON)},
+		}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, respMap[DoUpdateFrames], test.ShouldBeTrue)
+		pose, err := ms.(*builtIn).fsService.TransformPose(
+			ctx, referenceframe.NewPoseInFrame("tool", spatialmath.NewZeroPose()), "pieceArm", nil,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.R3VectorAlmostEqual(pose.Pose().Point(), r3.Vector{Z: 100}, 1e-6), test.ShouldBeTrue)
+
+		_, err = doOverWire(ms, map[string]interface{}{DoUpdateFrames: map[string]interface{}{"remove": []interface{}{"gripper"}}})
+		test.That(t, err, test.ShouldNotBeNil)
+		respMap, err = doOverWire(ms, map[string]interface{}{DoUpdateFrames: map[string]interface{}{"remove": []interface{}{"tool"}}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, respMap[DoUpdateFrames], test.ShouldBeTrue)
+		frameSys, err := ms.(*builtIn).fsService.FrameSystem(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, frameSys.Frame("tool"), test.ShouldBeNil)
+	})
+
 	t.Run("Extras transmitted correctly", func(t *testing.T) {
 		// test that DoPlan correctly breaks if bad inputs are provided, meaning it is being parsed correctly
 		moveReq.Extra = map[string]interface{}{
@@ -1335,6 +1458,361 @@ func TestDoCommand(t *testing.T) {
 	})
 }
 
+func TestWorldStateUpdates(t *testing.T) {
+	ctx := context.Background()
+	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+	defer teardown()
+	builtinMS := ms.(*builtIn)
+
+	updateWorldState := func(center, dims r3.Vector) {
+		box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(center), dims, "")
+		test.That(t, err, test.ShouldBeNil)
+		worldState, err := referenceframe.NewWorldState(
+			[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{box})},
+			nil,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		worldStateProto, err := worldState.ToProtobuf()
+		test.That(t, err, test.ShouldBeNil)
+		bytes, err := protojson.Marshal(worldStateProto)
+		test.That(t, err, test.ShouldBeNil)
+		resp, err := ms.DoCommand(ctx, map[string]interface{}{DoUpdateWorldState: string(bytes)})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp[DoUpdateWorldState], test.ShouldBeTrue)
+	}
+
+	t.Run("settings", func(t *testing.T) {
+		behavior, maxReplans, err := obstacleUpdateSettings(nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, behavior, test.ShouldEqual, obstacleUpdateReplan)
+		test.That(t, maxReplans, test.ShouldEqual, defaultObstacleUpdateReplans)
+
+		behavior, maxReplans, err = obstacleUpdateSettings(map[string]interface{}{"obstacle_update_behavior": "stop", "max_replans": 1.})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, behavior, test.ShouldEqual, obstacleUpdateStop)
+		test.That(t, maxReplans, test.ShouldEqual, 1)
+
+		_, _, err = obstacleUpdateSettings(map[string]interface{}{"obstacle_update_behavior": "swerve"})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("obstacles in the way stop an executing plan", func(t *testing.T) {
+		frameSys, err := builtinMS.fsService.FrameSystem(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		current, _, err := builtinMS.fsService.CurrentInputs(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		start := referenceframe.FrameSystemInputs{}
+		goal := referenceframe.FrameSystemInputs{}
+		for name, inputs := range current {
+			start[name] = append([]referenceframe.Input{}, inputs...)
+			goal[name] = append([]referenceframe.Input{}, inputs...)
+		}
+		goal["pieceArm"][0].Value += 1
+		path := motionplan.Path{}
+		for _, inputs := range []referenceframe.FrameSystemInputs{start, goal} {
+			poses, err := inputs.ComputePoses(frameSys)
+			test.That(t, err, test.ShouldBeNil)
+			path = append(path, poses)
+		}
+		// slow enough that the plan is still executing when the world state is updated
+		plan, err := motionplan.NewTimedPlan(
+			motionplan.NewSimplePlan(path, motionplan.Trajectory{start, goal}),
+			motionplan.VelocityConstraint{MaxJointVelDegsPerSec: 1},
+		)
+		test.That(t, err, test.ShouldBeNil)
+
+		_, changed := builtinMS.worldStateUpdates.get()
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- builtinMS.executeWatchingWorldState(ctx, motion.MoveReq{ComponentName: gripper.Named("pieceGripper")}, plan, changed)
+		}()
+
+		// an obstacle out of the way does not stop the plan
+		updateWorldState(r3.Vector{X: 5000, Y: 5000, Z: 5000}, r3.Vector{X: 10, Y: 10, Z: 10})
+		select {
+		case err := <-errCh:
+			t.Fatalf("plan stopped early: %v", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		// an obstacle where the gripper is going does
+		updateWorldState(path[1]["pieceGripper"].Pose().Point(), r3.Vector{X: 100, Y: 100, Z: 100})
+		select {
+		case err := <-errCh:
+			test.That(t, errors.Is(err, errPlanInvalidated), test.ShouldBeTrue)
+		case <-time.After(10 * time.Second):
+			t.Fatal("plan was not stopped")
+		}
+
+		// clearing the updates removes their obstacles from later moves
+		resp, err := ms.DoCommand(ctx, map[string]interface{}{DoUpdateWorldState: ""})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp[DoUpdateWorldState], test.ShouldBeTrue)
+		updates, _ := builtinMS.worldStateUpdates.get()
+		test.That(t, len(updates.ObstacleNames()), test.ShouldEqual, 0)
+	})
+}
+
+func TestPlanCache(t *testing.T) {
+	ctx := context.Background()
+	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+	defer teardown()
+	builtinMS := ms.(*builtIn)
+
+	moveReq := motion.MoveReq{
+		ComponentName: gripper.Named("pieceGripper"),
+		WorldState:    referenceframe.NewEmptyWorldState(),
+		Destination:   referenceframe.NewPoseInFrame("c", spatialmath.NewPoseFromPoint(r3.Vector{X: 0, Y: -30, Z: -50})),
+	}
+	reqProto, err := moveReq.ToProto(ms.Name().Name)
+	test.That(t, err, test.ShouldBeNil)
+	reqBytes, err := protojson.Marshal(reqProto)
+	test.That(t, err, test.ShouldBeNil)
+
+	t.Run("replays the plan of a repeated request", func(t *testing.T) {
+		resp, err := ms.DoCommand(ctx, map[string]interface{}{DoCachePlan: string(reqBytes)})
+		test.That(t, err, test.ShouldBeNil)
+		key, ok := resp[DoCachePlan].(string)
+		test.That(t, ok, test.ShouldBeTrue)
+		plan, ok := builtinMS.planCache.get(key)
+		test.That(t, ok, test.ShouldBeTrue)
+
+		resp, err = ms.DoCommand(ctx, map[string]interface{}{DoCachePlan: string(reqBytes)})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp[DoCachePlan], test.ShouldEqual, key)
+		replayed, ok := builtinMS.planCache.get(key)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, replayed, test.ShouldEqual, plan)
+
+		// a different world state is a different plan
+		box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 1000, Y: 1000, Z: 1000}), r3.Vector{X: 1, Y: 1, Z: 1}, "box")
+		test.That(t, err, test.ShouldBeNil)
+		otherReq := moveReq
+		otherReq.WorldState, err = referenceframe.NewWorldState(
+			[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{box})},
+			nil,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		otherKey, err := builtinMS.planCacheKey(ctx, otherReq)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, otherKey, test.ShouldNotEqual, key)
+
+		// the cached plan executes from its start, but not once the robot has moved away from it
+		resp, err = ms.DoCommand(ctx, map[string]interface{}{DoExecuteCachedPlan: key})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp[DoExecuteCachedPlan], test.ShouldBeTrue)
+		_, resources, err := builtinMS.fsService.CurrentInputs(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		pieceArm, ok := resources["pieceArm"]
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, pieceArm.GoToInputs(ctx, referenceframe.FloatsToInputs([]float64{1, 0, 0, 0, 0, 0})), test.ShouldBeNil)
+		_, err = ms.DoCommand(ctx, map[string]interface{}{DoExecuteCachedPlan: key})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "not at the start of the plan")
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := ms.DoCommand(ctx, map[string]interface{}{DoExecuteCachedPlan: "nope"})
+		test.That(t, errors.Is(err, errCachedPlanNotFound), test.ShouldBeTrue)
+	})
+
+	t.Run("move with use_plan_cache", func(t *testing.T) {
+		cachedReq := moveReq
+		cachedReq.Destination = referenceframe.NewPoseInFrame("c", spatialmath.NewPoseFromPoint(r3.Vector{X: 0, Y: -10, Z: -50}))
+		cachedReq.Extra = map[string]interface{}{usePlanCacheKey: true}
+		plan, err := builtinMS.planWithCache(ctx, cachedReq)
+		test.That(t, err, test.ShouldBeNil)
+		replayed, err := builtinMS.planWithCache(ctx, cachedReq)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, replayed, test.ShouldEqual, plan)
+
+		_, err = ms.Move(ctx, cachedReq)
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("evicts the oldest plans", func(t *testing.T) {
+		cache := newPlanCache()
+		for i := 0; i <= maxCachedPlans; i++ {
+			cache.put(fmt.Sprint(i), motionplan.NewSimplePlan(nil, nil))
+		}
+		_, ok := cache.get("0")
+		test.That(t, ok, test.ShouldBeFalse)
+		_, ok = cache.get(fmt.Sprint(maxCachedPlans))
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, len(cache.plans), test.ShouldEqual, maxCachedPlans)
+	})
+}
+
+func TestMultiComponentMove(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("destinations are planned together", func(t *testing.T) {
+		req := motion.MoveReq{
+			ComponentName: arm.Named("arm1"),
+			Destination:   referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 100})),
+			Destinations: map[resource.Name]*referenceframe.PoseInFrame{
+				arm.Named("arm2"): referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: -100})),
+			},
+		}
+		_, waypoints, err := waypointsFromRequest(req, referenceframe.FrameSystemInputs{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(waypoints), test.ShouldEqual, 1)
+		test.That(t, len(waypoints[0].Poses()), test.ShouldEqual, 2)
+		test.That(t, waypoints[0].Poses()["arm1"], test.ShouldEqual, req.Destination)
+		test.That(t, waypoints[0].Poses()["arm2"], test.ShouldEqual, req.Destinations[arm.Named("arm2")])
+	})
+
+	t.Run("components move in sync", func(t *testing.T) {
+		// each arm waits for the other to start moving, which only happens if they move at the same time
+		started := map[string]chan struct{}{"arm1": make(chan struct{}), "arm2": make(chan struct{})}
+		resources := map[string]framesystem.InputEnabled{}
+		for name, other := range map[string]string{"arm1": "arm2", "arm2": "arm1"} {
+			injectArm := inject.NewArm(name)
+			injectArm.GoToInputsFunc = func(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+				close(started[name])
+				select {
+				case <-started[other]:
+					return nil
+				case <-time.After(5 * time.Second):
+					return errors.New("arms did not move together")
+				}
+			}
+			resources[name] = injectArm
+		}
+		inputs := [][]referenceframe.Input{{{Value: 1}}}
+		err := goToInputsTogether(ctx, resources, map[string][][]referenceframe.Input{"arm1": inputs, "arm2": inputs}, nil)
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("a failed component stops the others", func(t *testing.T) {
+		stopped := map[string]bool{}
+		var mu sync.Mutex
+		resources := map[string]framesystem.InputEnabled{}
+		for _, name := range []string{"arm1", "arm2"} {
+			injectArm := inject.NewArm(name)
+			injectArm.GoToInputsFunc = func(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+				if name == "arm1" {
+					return errors.New("bad joint")
+				}
+				return nil
+			}
+			injectArm.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+				mu.Lock()
+				defer mu.Unlock()
+				stopped[name] = true
+				return nil
+			}
+			resources[name] = injectArm
+		}
+		inputs := [][]referenceframe.Input{{{Value: 1}}}
+		err := goToInputsTogether(ctx, resources, map[string][][]referenceframe.Input{"arm1": inputs, "arm2": inputs}, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "bad joint")
+		test.That(t, stopped, test.ShouldResemble, map[string]bool{"arm1": true, "arm2": true})
+	})
+}
+
+func TestExecutionMonitoring(t *testing.T) {
+	ctx := context.Background()
+	monitor := newTrackingMonitor(&motion.ExecutionMonitoringConfig{MaxTrackingError: 0.1, CheckIntervalMs: 5})
+
+	// newArm returns an arm which moves from 0 to 1 in 20ms, reporting positions `offset` from its plan.
+	newArm := func(name string, offset float64, stopped *atomic.Bool) *inject.Arm {
+		var position atomic.Value
+		position.Store(0.)
+		injectArm := inject.NewArm(name)
+		injectArm.CurrentInputsFunc = func(ctx context.Context) ([]referenceframe.Input, error) {
+			return []referenceframe.Input{{Value: position.Load().(float64) + offset}}, nil
+		}
+		injectArm.GoToInputsFunc = func(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+			for i := 1; i <= 20; i++ {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Millisecond):
+				}
+				position.Store(float64(i) / 20)
+			}
+			return nil
+		}
+		injectArm.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+			stopped.Store(true)
+			return nil
+		}
+		return injectArm
+	}
+	inputs := [][]referenceframe.Input{{{Value: 1}}}
+
+	t.Run("components following their plans finish", func(t *testing.T) {
+		var stopped atomic.Bool
+		resources := map[string]framesystem.InputEnabled{"arm1": newArm("arm1", 0.05, &stopped)}
+		err := goToInputsTogether(ctx, resources, map[string][][]referenceframe.Input{"arm1": inputs}, monitor)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, stopped.Load(), test.ShouldBeFalse)
+	})
+
+	t.Run("components not following their plans are stopped", func(t *testing.T) {
+		var stopped1, stopped2 atomic.Bool
+		resources := map[string]framesystem.InputEnabled{
+			"arm1": newArm("arm1", 0, &stopped1),
+			"arm2": newArm("arm2", 0.5, &stopped2),
+		}
+		err := goToInputsTogether(ctx, resources, map[string][][]referenceframe.Input{"arm1": inputs, "arm2": inputs}, monitor)
+		test.That(t, errors.Is(err, motion.ErrTrackingErrorExceeded), test.ShouldBeTrue)
+		var trackingErr *motion.TrackingError
+		test.That(t, errors.As(err, &trackingErr), test.ShouldBeTrue)
+		test.That(t, trackingErr.Component, test.ShouldEqual, "arm2")
+		test.That(t, trackingErr.Limit, test.ShouldEqual, 0.1)
+		test.That(t, stopped1.Load(), test.ShouldBeTrue)
+		test.That(t, stopped2.Load(), test.ShouldBeTrue)
+	})
+
+	t.Run("components can have their own limits", func(t *testing.T) {
+		var stopped atomic.Bool
+		monitor := newTrackingMonitor(&motion.ExecutionMonitoringConfig{
+			MaxTrackingError:          0.1,
+			ComponentMaxTrackingError: map[string]float64{"arm1": 1},
+			CheckIntervalMs:           5,
+		})
+		resources := map[string]framesystem.InputEnabled{"arm1": newArm("arm1", 0.5, &stopped)}
+		err := goToInputsTogether(ctx, resources, map[string][][]referenceframe.Input{"arm1": inputs}, monitor)
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("stalled components fail on arrival", func(t *testing.T) {
+		var stopped atomic.Bool
+		injectArm := newArm("arm1", 0, &stopped)
+		injectArm.GoToInputsFunc = func(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+			return nil
+		}
+		resources := map[string]framesystem.InputEnabled{"arm1": injectArm}
+		err := goToInputsTogether(ctx, resources, map[string][][]referenceframe.Input{"arm1": inputs}, monitor)
+		test.That(t, errors.Is(err, motion.ErrTrackingErrorExceeded), test.ShouldBeTrue)
+		test.That(t, stopped.Load(), test.ShouldBeTrue)
+	})
+
+	t.Run("distance to path", func(t *testing.T) {
+		path := [][]referenceframe.Input{{{Value: 0}, {Value: 0}}, {{Value: 1}, {Value: 0}}, {{Value: 1}, {Value: 1}}}
+		test.That(t, distanceToPath(path, []referenceframe.Input{{Value: 0.5}, {Value: 0.2}}), test.ShouldAlmostEqual, 0.2)
+		test.That(t, distanceToPath(path, []referenceframe.Input{{Value: 1.3}, {Value: 0.5}}), test.ShouldAlmostEqual, 0.3)
+		test.That(t, distanceToPath(path, []referenceframe.Input{{Value: -1}, {Value: 0}}), test.ShouldAlmostEqual, 1)
+		test.That(t, distanceToPath(path[:1], []referenceframe.Input{{Value: 3}, {Value: 4}}), test.ShouldAlmostEqual, 5)
+	})
+
+	t.Run("config validation", func(t *testing.T) {
+		conf := Config{ExecutionMonitoring: &motion.ExecutionMonitoringConfig{MaxTrackingError: -1}}
+		_, err := conf.Validate("motion")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "max_tracking_error")
+		conf.ExecutionMonitoring = &motion.ExecutionMonitoringConfig{ComponentMaxTrackingError: map[string]float64{"arm1": 0}}
+		_, err = conf.Validate("motion")
+		test.That(t, err, test.ShouldNotBeNil)
+		conf.ExecutionMonitoring = &motion.ExecutionMonitoringConfig{MaxTrackingError: 0.1}
+		_, err = conf.Validate("motion")
+		test.That(t, err, test.ShouldBeNil)
+	})
+}
+
 func TestMultiWaypointPlanning(t *testing.T) {
 	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
 	defer teardown()
@@ -0,0 +1,44 @@
+package builtin
+
+import (
+	"context"
+
+	"github.com/go-viper/mapstructure/v2"
+	commonpb "go.viam.com/api/common/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
+)
+
+// FrameUpdateRequest is the input of DoUpdateFrames, which changes the frames of the machine's frame system without
+// reconfiguring it, such as when a tool changer swaps end effectors.
+type FrameUpdateRequest struct {
+	// Upsert are commonpb.Transforms in JSON, added to the frame system or replacing the frames of the same name added
+	// before.
+	Upsert []string `mapstructure:"upsert"`
+	// Remove are the names of frames added before which are removed from the frame system.
+	Remove []string `mapstructure:"remove"`
+}
+
+// updateFrames applies the frame update of `rawReq` to the frame system service, which the motion service and clients
+// build their frame systems from.
+func (ms *builtIn) updateFrames(ctx context.Context, rawReq interface{}) error {
+	var req FrameUpdateRequest
+	if err := mapstructure.Decode(rawReq, &req); err != nil {
+		return err
+	}
+	update := framesystem.FrameUpdate{Remove: req.Remove}
+	for _, s := range req.Upsert {
+		var transform commonpb.Transform
+		if err := protojson.Unmarshal([]byte(s), &transform); err != nil {
+			return err
+		}
+		frame, err := referenceframe.LinkInFrameFromTransformProtobuf(&transform)
+		if err != nil {
+			return err
+		}
+		update.Upsert = append(update.Upsert, frame)
+	}
+	return ms.fsService.UpdateFrames(ctx, update)
+}
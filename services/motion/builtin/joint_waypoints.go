@@ -0,0 +1,99 @@
+package builtin
+
+import (
+	"context"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// JointWaypointsRequest is the input of DoMoveThroughJoints, which moves a component through joint-space waypoints as
+// given, rather than planning a path to a goal.
+type JointWaypointsRequest struct {
+	// ComponentName is the name of the component moved.
+	ComponentName string `mapstructure:"component_name"`
+	// Waypoints are the inputs of the component at each waypoint, in radians or millimeters as its frame takes them.
+	Waypoints [][]float64 `mapstructure:"waypoints"`
+	// BlendRadius is how far from each intermediate waypoint, as the L2 distance of the inputs, the component may start
+	// cutting its corner so that it does not stop at it. The component stops at each waypoint if zero.
+	BlendRadius float64 `mapstructure:"blend_radius"`
+	// MaxJointVelDegsPerSec and MaxJointAccDegsPerSec2 limit how fast the joints move, to the defaults of
+	// motionplan.PlanJointPath if zero.
+	MaxJointVelDegsPerSec  float64 `mapstructure:"max_joint_vel_degs_per_sec"`
+	MaxJointAccDegsPerSec2 float64 `mapstructure:"max_joint_acc_degs_per_sec2"`
+	// WorldState is a commonpb.WorldState in JSON, whose obstacles are avoided along with those set by
+	// DoUpdateWorldState.
+	WorldState string `mapstructure:"world_state"`
+}
+
+// worldStateFromJSON returns the world state of a commonpb.WorldState in JSON, or an empty world state for an empty
+// string.
+func worldStateFromJSON(s string) (*referenceframe.WorldState, error) {
+	if s == "" {
+		return referenceframe.NewEmptyWorldState(), nil
+	}
+	var worldStateProto commonpb.WorldState
+	if err := protojson.Unmarshal([]byte(s), &worldStateProto); err != nil {
+		return nil, err
+	}
+	return referenceframe.WorldStateFromProtobuf(&worldStateProto)
+}
+
+// planJointWaypoints plans a collision checked and time parameterized path of a component through the waypoints of
+// `req` from where it is now.
+func (ms *builtIn) planJointWaypoints(ctx context.Context, rawReq interface{}) (motionplan.TimedPlan, error) {
+	var req JointWaypointsRequest
+	if err := mapstructure.Decode(rawReq, &req); err != nil {
+		return nil, err
+	}
+	if req.ComponentName == "" {
+		return nil, errors.New("joint waypoints need a component_name")
+	}
+	worldState, err := worldStateFromJSON(req.WorldState)
+	if err != nil {
+		return nil, err
+	}
+	updates, _ := ms.worldStateUpdates.get()
+	if worldState, err = mergeWorldStates(worldState, updates); err != nil {
+		return nil, err
+	}
+	frameSys, err := ms.fsService.FrameSystem(ctx, worldState.Transforms())
+	if err != nil {
+		return nil, err
+	}
+	inputs, _, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	waypoints := make([]referenceframe.FrameSystemInputs, 0, len(req.Waypoints))
+	for _, waypoint := range req.Waypoints {
+		waypoints = append(waypoints, referenceframe.FrameSystemInputs{req.ComponentName: referenceframe.FloatsToInputs(waypoint)})
+	}
+	velConstraint := motionplan.VelocityConstraint{
+		MaxJointVelDegsPerSec:  motionplan.DefaultJointPathVelDegsPerSec,
+		MaxJointAccDegsPerSec2: motionplan.DefaultJointPathAccDegsPerSec2,
+	}
+	if req.MaxJointVelDegsPerSec > 0 {
+		velConstraint.MaxJointVelDegsPerSec = req.MaxJointVelDegsPerSec
+	}
+	if req.MaxJointAccDegsPerSec2 > 0 {
+		velConstraint.MaxJointAccDegsPerSec2 = req.MaxJointAccDegsPerSec2
+	}
+	constraints := &motionplan.Constraints{}
+	constraints.AddVelocityConstraint(velConstraint)
+	return motionplan.PlanJointPath(ctx, &motionplan.JointPathRequest{
+		Logger:             ms.logger,
+		FrameSystem:        frameSys,
+		StartConfiguration: inputs,
+		Waypoints:          waypoints,
+		BlendRadius:        req.BlendRadius,
+		WorldState:         worldState,
+		Constraints:        constraints,
+	})
+}
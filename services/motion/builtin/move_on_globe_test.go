@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -286,6 +287,171 @@ func TestBoundingRegionsConstraint(t *testing.T) {
 	})
 }
 
+// squareGeofenceConfig returns the config of a geofence covering the square with the given corners.
+func squareGeofenceConfig(name string, fenceType motion.GeofenceType, minPt, maxPt *geo.Point) *motion.GeofenceConfig {
+	return &motion.GeofenceConfig{
+		Name: name,
+		Type: fenceType,
+		GeoJSON: map[string]interface{}{
+			"type": "Polygon",
+			"coordinates": []interface{}{[]interface{}{
+				[]interface{}{minPt.Lng(), minPt.Lat()},
+				[]interface{}{maxPt.Lng(), minPt.Lat()},
+				[]interface{}{maxPt.Lng(), maxPt.Lat()},
+				[]interface{}{minPt.Lng(), maxPt.Lat()},
+				[]interface{}{minPt.Lng(), minPt.Lat()},
+			}},
+		},
+	}
+}
+
+func TestGeofences(t *testing.T) {
+	ctx := context.Background()
+	origin := geo.NewPoint(0, 0)
+	dst := geo.NewPoint(origin.Lat(), origin.Lng()+1e-5)
+	extra := map[string]interface{}{
+		"motion_profile": "position_only",
+		"timeout":        5.,
+		"smooth_iter":    5.,
+	}
+	motionCfg := &motion.MotionConfiguration{PlanDeviationMM: 10}
+
+	moveWithGeofences := func(t *testing.T, confs ...*motion.GeofenceConfig) (motion.Service, error) {
+		t.Helper()
+		_, ms, closeFunc := CreateMoveOnGlobeTestEnvironment(ctx, t, origin, 80, nil)
+		t.Cleanup(func() { closeFunc(ctx) })
+		geofences, err := motion.NewGeofences(confs)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = ms.MoveOnGlobe(ctx, motion.MoveOnGlobeReq{
+			ComponentName:      baseResource,
+			MovementSensorName: moveSensorResource,
+			Destination:        dst,
+			Geofences:          geofences,
+			MotionCfg:          motionCfg,
+			Extra:              extra,
+		})
+		return ms, err
+	}
+	geofenceViolations := func(ms motion.Service) int64 {
+		stats, ok := ms.(*builtIn).Stats().(motionStats)
+		test.That(t, ok, test.ShouldBeTrue)
+		return stats.GeofenceViolations
+	}
+
+	t.Run("destination inside a keep-out geofence fails", func(t *testing.T) {
+		ms, err := moveWithGeofences(t, squareGeofenceConfig(
+			"pond", motion.GeofenceKeepOut, geo.NewPoint(-1e-6, 9e-6), geo.NewPoint(1e-6, 11e-6),
+		))
+		test.That(t, errors.Is(err, motion.ErrGeofenceViolation), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "pond")
+		test.That(t, geofenceViolations(ms), test.ShouldEqual, 1)
+	})
+
+	t.Run("destination outside of the keep-in geofences fails", func(t *testing.T) {
+		ms, err := moveWithGeofences(t, squareGeofenceConfig(
+			"yard", motion.GeofenceKeepIn, geo.NewPoint(-1e-5, -1e-5), geo.NewPoint(1e-5, 5e-6),
+		))
+		test.That(t, errors.Is(err, motion.ErrGeofenceViolation), test.ShouldBeTrue)
+		test.That(t, geofenceViolations(ms), test.ShouldEqual, 1)
+	})
+
+	t.Run("a plan through a keep-out geofence fails", func(t *testing.T) {
+		// a strip across the whole route, which the planner does not know to avoid
+		geofences, err := motion.NewGeofences([]*motion.GeofenceConfig{squareGeofenceConfig(
+			"fence", motion.GeofenceKeepOut, geo.NewPoint(-1e-3, 4e-6), geo.NewPoint(1e-3, 6e-6),
+		)})
+		test.That(t, err, test.ShouldBeNil)
+		mr := &moveRequest{
+			geoPoseOrigin:      spatialmath.NewGeoPose(origin, 90),
+			geofences:          geofences,
+			geofenceViolations: &atomic.Int64{},
+		}
+		// spatialmath.GeoPointToPoint(dst, origin) is r3.Vector{1111.92, 0, 0}
+		start := spatialmath.NewZeroPose()
+		goal := spatialmath.NewPoseFromPoint(r3.Vector{X: 1111.92})
+		err = mr.checkGeofences(start, goal)
+		test.That(t, errors.Is(err, motion.ErrGeofenceViolation), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "fence")
+		test.That(t, mr.geofenceViolations.Load(), test.ShouldEqual, 1)
+
+		// a detour around the strip's end is fine
+		detour := []spatialmath.Pose{
+			start,
+			spatialmath.NewPoseFromPoint(r3.Vector{Y: 2e5}),
+			spatialmath.NewPoseFromPoint(r3.Vector{X: 1111.92, Y: 2e5}),
+			goal,
+		}
+		test.That(t, mr.checkGeofences(detour...), test.ShouldBeNil)
+		test.That(t, mr.geofenceViolations.Load(), test.ShouldEqual, 1)
+	})
+
+	t.Run("moves within the geofences succeed", func(t *testing.T) {
+		ms, err := moveWithGeofences(t,
+			squareGeofenceConfig("yard", motion.GeofenceKeepIn, geo.NewPoint(-1e-4, -1e-4), geo.NewPoint(1e-4, 1e-4)),
+			squareGeofenceConfig("shed", motion.GeofenceKeepOut, geo.NewPoint(5e-5, 5e-5), geo.NewPoint(6e-5, 6e-5)),
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, geofenceViolations(ms), test.ShouldEqual, 0)
+	})
+}
+
+func TestSpeedLimitedBase(t *testing.T) {
+	ctx := context.Background()
+	zone := squareGeofenceConfig("", "", geo.NewPoint(-1e-5, -1e-5), geo.NewPoint(1e-5, 1e-5))
+	limits, err := motion.NewSpeedLimits([]*motion.SpeedLimitConfig{
+		{Name: "door", GeoJSON: zone.GeoJSON, MaxLinearMPerSec: 0.1, MaxAngularDegsPerSec: 10},
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	position := geo.NewPoint(0, 0)
+	ms := inject.NewMovementSensor("gps")
+	ms.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+		return position, 0, nil
+	}
+	var linear, angular r3.Vector
+	var mmPerSec, degsPerSec float64
+	injectBase := inject.NewBase("base")
+	injectBase.SetVelocityFunc = func(ctx context.Context, l, a r3.Vector, extra map[string]interface{}) error {
+		linear, angular = l, a
+		return nil
+	}
+	injectBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, speed float64, extra map[string]interface{}) error {
+		mmPerSec = speed
+		return nil
+	}
+	injectBase.SpinFunc = func(ctx context.Context, angleDeg, speed float64, extra map[string]interface{}) error {
+		degsPerSec = speed
+		return nil
+	}
+	b := newSpeedLimitedBase(injectBase, ms, limits)
+
+	// velocities are scaled together so that the base keeps its arc
+	test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 400}, r3.Vector{Z: 20}, nil), test.ShouldBeNil)
+	test.That(t, linear, test.ShouldResemble, r3.Vector{Y: 100})
+	test.That(t, angular, test.ShouldResemble, r3.Vector{Z: 5})
+	test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 50}, r3.Vector{Z: -40}, nil), test.ShouldBeNil)
+	test.That(t, linear, test.ShouldResemble, r3.Vector{Y: 12.5})
+	test.That(t, angular, test.ShouldResemble, r3.Vector{Z: -10})
+	test.That(t, b.MoveStraight(ctx, 100, -300, nil), test.ShouldBeNil)
+	test.That(t, mmPerSec, test.ShouldEqual, -100)
+	test.That(t, b.Spin(ctx, 90, 45, nil), test.ShouldBeNil)
+	test.That(t, degsPerSec, test.ShouldEqual, 10)
+
+	// outside of the zone commands are unchanged
+	position = geo.NewPoint(1, 1)
+	test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 400}, r3.Vector{Z: 20}, nil), test.ShouldBeNil)
+	test.That(t, linear, test.ShouldResemble, r3.Vector{Y: 400})
+	test.That(t, angular, test.ShouldResemble, r3.Vector{Z: 20})
+	test.That(t, b.MoveStraight(ctx, 100, -300, nil), test.ShouldBeNil)
+	test.That(t, mmPerSec, test.ShouldEqual, -300)
+
+	// the base is not moved if where it is is unknown
+	ms.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+		return nil, 0, errors.New("no fix")
+	}
+	test.That(t, b.Spin(ctx, 90, 45, nil), test.ShouldBeError, "no fix")
+}
+
 func TestObstacleReplanningGlobe(t *testing.T) {
 	ctx := context.Background()
 	ctx, cFunc := context.WithCancel(ctx)
@@ -3,6 +3,7 @@ package builtin
 import (
 	"context"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -347,3 +348,46 @@ func TestMoveOnMapStaticObs(t *testing.T) {
 		test.That(t, err, test.ShouldBeError, errors.New("context deadline exceeded"))
 	})
 }
+
+func TestMoveOnMapLocalizationQuality(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	matchScore := 0.2
+	injectSlam := createInjectedSlam("test_slam")
+	injectSlam.LocalizationQualityFunc = func(ctx context.Context, extra map[string]interface{}) (slam.LocalizationQuality, error) {
+		return slam.LocalizationQuality{MatchScore: matchScore}, nil
+	}
+
+	t.Run("moves do not start while localization is degraded", func(t *testing.T) {
+		deps := resource.Dependencies{injectSlam.Name(): injectSlam}
+		ms, err := NewBuiltIn(ctx, deps, resource.Config{ConvertedAttributes: &Config{}}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer ms.Close(context.Background())
+
+		req := motion.MoveOnMapReq{
+			ComponentName: base.Named("test-base"),
+			Destination:   spatialmath.NewPoseFromPoint(r3.Vector{X: 1000}),
+			SlamName:      slam.Named("test_slam"),
+			Extra:         map[string]interface{}{"min_localization_score": 0.5},
+		}
+		_, err = ms.(*builtIn).MoveOnMap(ctx, req)
+		test.That(t, errors.Is(err, motion.ErrLocalizationDegraded), test.ShouldBeTrue)
+		test.That(t, ms.(*builtIn).Stats(), test.ShouldResemble, motionStats{LocalizationDegradations: 1})
+	})
+
+	t.Run("executions stop when localization degrades", func(t *testing.T) {
+		var degradations atomic.Int64
+		mr := &moveRequest{slamService: injectSlam, minLocalizationScore: 0.5, localizationDegradations: &degradations}
+		matchScore = 0.9
+		test.That(t, mr.checkLocalizationQuality(ctx), test.ShouldBeNil)
+		matchScore = 0.4
+		err := mr.checkLocalizationQuality(ctx)
+		test.That(t, errors.Is(err, motion.ErrLocalizationDegraded), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "match score 0.40")
+		test.That(t, degradations.Load(), test.ShouldEqual, 1)
+
+		// requests without a minimum score never check
+		mr.minLocalizationScore = 0
+		test.That(t, mr.checkLocalizationQuality(ctx), test.ShouldBeNil)
+	})
+}
@@ -7,8 +7,10 @@ import (
 	"math"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	geo "github.com/kellydunn/golang-geo"
 	"github.com/pkg/errors"
 	goutils "go.viam.com/utils"
 
@@ -65,7 +67,18 @@ type moveRequest struct {
 	seedPlan          motionplan.Plan
 	kinematicBase     kinematicbase.KinematicBase
 	obstacleDetectors map[vision.Service][]resource.Name
-	replanCostFactor  float64
+	// obstacleClearanceMM is how much detected obstacles are grown by, so that plans keep clear of them
+	obstacleClearanceMM float64
+	// geofences are only set if requestType == requestTypeMoveOnGlobe. Violations of them are counted in
+	// geofenceViolations.
+	geofences          []*motion.Geofence
+	geofenceViolations *atomic.Int64
+	// slamService is only set if requestType == requestTypeMoveOnMap. Executions stop when its match score falls
+	// below minLocalizationScore, which are counted in localizationDegradations.
+	slamService              slam.Service
+	minLocalizationScore     float64
+	localizationDegradations *atomic.Int64
+	replanCostFactor         float64
 	// TODO(RSDK-8683): remove atGoalCheck and put it in the motionplan package
 	// atGoalCheck func(basePose spatialmath.Pose) *state.ExecuteResponse
 	atGoalCheck func(basePose spatialmath.Pose) bool
@@ -125,7 +138,36 @@ func (mr *moveRequest) Plan(ctx context.Context) (motionplan.Plan, error) {
 	}
 
 	// TODO(RSDK-5634): this should pass in mr.seedplan and the appropriate replanCostFactor once this bug is found and fixed.
-	return motionplan.Replan(ctx, &planRequestCopy, nil, 0)
+	plan, err := motionplan.Replan(ctx, &planRequestCopy, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	poses, err := plan.Path().GetFramePoses(mr.kinematicBase.Kinematics().Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := mr.checkGeofences(poses...); err != nil {
+		return nil, errors.Wrap(err, "plan")
+	}
+	return plan, nil
+}
+
+// checkGeofences returns an error if the path through `poses`, which are relative to the request's GPS origin, violates
+// the request's geofences.
+func (mr *moveRequest) checkGeofences(poses ...spatialmath.Pose) error {
+	if len(mr.geofences) == 0 {
+		return nil
+	}
+	origin := spatialmath.NewGeoPose(mr.geoPoseOrigin.Location(), 0)
+	points := make([]*geo.Point, 0, len(poses))
+	for _, pose := range poses {
+		points = append(points, spatialmath.PoseToGeoPose(origin, pose).Location())
+	}
+	err := motion.CheckGeofences(mr.geofences, points...)
+	if err != nil {
+		mr.geofenceViolations.Add(1)
+	}
+	return err
 }
 
 func (mr *moveRequest) Execute(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
@@ -195,6 +237,18 @@ func (mr *moveRequest) deviatedFromPlan(ctx context.Context, plan motionplan.Pla
 		return state.ExecuteResponse{}, err
 	}
 
+	// components that have strayed out of their geofences are stopped rather than replanned
+	if currentPose, ok := executionState.CurrentPoses()[mr.kinematicBase.LocalizationFrame().Name()]; ok {
+		if err := mr.checkGeofences(currentPose.Pose()); err != nil {
+			return state.ExecuteResponse{}, errors.Wrap(err, "current position")
+		}
+	}
+
+	// components that can no longer trust where they are are stopped rather than replanned
+	if err := mr.checkLocalizationQuality(ctx); err != nil {
+		return state.ExecuteResponse{}, err
+	}
+
 	// check if the error state is outside the acceptable bounds
 	if errorState.Point().Norm() > mr.config.planDeviationMM {
 		msg := "error state exceeds planDeviationMM; planDeviationMM: %f, errorstate.Point().Norm(): %f, errorstate.Point(): %#v "
@@ -204,6 +258,19 @@ func (mr *moveRequest) deviatedFromPlan(ctx context.Context, plan motionplan.Pla
 	return state.ExecuteResponse{}, nil
 }
 
+// checkLocalizationQuality returns an error if the request's SLAM service is localized worse than the request's
+// minimum localization score.
+func (mr *moveRequest) checkLocalizationQuality(ctx context.Context) error {
+	if mr.slamService == nil || mr.minLocalizationScore <= 0 {
+		return nil
+	}
+	err := motion.CheckLocalizationQuality(ctx, mr.slamService, mr.minLocalizationScore)
+	if errors.Is(err, motion.ErrLocalizationDegraded) {
+		mr.localizationDegradations.Add(1)
+	}
+	return err
+}
+
 // getTransientDetections returns a list of geometries as observed by the provided vision service and camera.
 // Depending on the caller, the geometries returned are either in their relative position
 // with respect to the base or in their absolute position with respect to the world.
@@ -244,6 +311,11 @@ func (mr *moveRequest) getTransientDetections(
 	transientGeoms := []spatialmath.Geometry{}
 	for i, detection := range detections {
 		geometry := detection.Geometry
+		if mr.obstacleClearanceMM > 0 {
+			if geometry, err = inflateGeometry(geometry, mr.obstacleClearanceMM); err != nil {
+				return nil, err
+			}
+		}
 		// update the label of the geometry so we know it is transient
 		label := camName.ShortName() + "_transientObstacle_" + strconv.Itoa(i)
 		if geometry.Label() != "" {
@@ -271,6 +343,31 @@ func (mr *moveRequest) getTransientDetections(
 	return referenceframe.NewGeometriesInFrame(referenceframe.World, transientGeoms), nil
 }
 
+// inflateGeometry returns `geometry` grown by `clearanceMM` in every direction. Points become spheres.
+func inflateGeometry(geometry spatialmath.Geometry, clearanceMM float64) (spatialmath.Geometry, error) {
+	config, err := spatialmath.NewGeometryConfig(geometry)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot add clearance to detected obstacle")
+	}
+	switch config.Type {
+	case spatialmath.BoxType:
+		config.X += 2 * clearanceMM
+		config.Y += 2 * clearanceMM
+		config.Z += 2 * clearanceMM
+	case spatialmath.CapsuleType:
+		config.R += clearanceMM
+		config.L += 2 * clearanceMM
+	case spatialmath.SphereType:
+		config.R += clearanceMM
+	case spatialmath.PointType, spatialmath.UnknownType:
+		config.Type = spatialmath.SphereType
+		config.R = clearanceMM
+	case spatialmath.MeshType:
+		return nil, errors.New("cannot add clearance to detected mesh obstacle")
+	}
+	return config.ParseConfig()
+}
+
 // obstaclesIntersectPlan takes a list of waypoints and an index of a waypoint on that Plan and reports an error indicating
 // whether or not any obstacle detectors report geometries in positions which would cause a collision with the executor
 // following the Plan.
@@ -587,6 +684,10 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 	if math.IsNaN(req.Destination.Lat()) || math.IsNaN(req.Destination.Lng()) {
 		return nil, errors.New("destination may not contain NaN")
 	}
+	if err := motion.CheckGeofences(req.Geofences, req.Destination); err != nil {
+		ms.geofenceViolations.Add(1)
+		return nil, errors.Wrap(err, "destination")
+	}
 
 	// build kinematic options
 	kinematicsOptions := kbOptionsFromCfg(motionCfg, valExtra)
@@ -626,6 +727,9 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 	if !ok {
 		return nil, fmt.Errorf("cannot move component of type %T because it is not a Base", baseComponent)
 	}
+	if len(req.SpeedLimits) > 0 {
+		b = newSpeedLimitedBase(b, movementSensor, req.SpeedLimits)
+	}
 
 	fs, err := ms.fsService.FrameSystem(ctx, nil)
 	if err != nil {
@@ -678,6 +782,8 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 	mr.requestType = requestTypeMoveOnGlobe
 	mr.geoPoseOrigin = spatialmath.NewGeoPose(origin, heading)
 	mr.planRequest.BoundingRegions = boundingRegions
+	mr.geofences = req.Geofences
+	mr.geofenceViolations = &ms.geofenceViolations
 	return mr, nil
 }
 
@@ -723,6 +829,17 @@ func (ms *builtIn) newMoveOnMapRequest(
 		return nil, fmt.Errorf("expected SLAM to be in localization only mode, got %v", slamProps.MappingMode)
 	}
 
+	// don't start moving if localization is already too poor to follow a plan
+	if valExtra.minLocalizationScore > 0 {
+		err := motion.CheckLocalizationQuality(ctx, slamSvc, valExtra.minLocalizationScore)
+		if errors.Is(err, motion.ErrLocalizationDegraded) {
+			ms.localizationDegradations.Add(1)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// gets the extents of the SLAM map
 	limits, err := slam.Limits(ctx, slamSvc, true)
 	if err != nil {
@@ -784,6 +901,9 @@ func (ms *builtIn) newMoveOnMapRequest(
 		return nil, err
 	}
 	mr.requestType = requestTypeMoveOnMap
+	mr.slamService = slamSvc
+	mr.minLocalizationScore = valExtra.minLocalizationScore
+	mr.localizationDegradations = &ms.localizationDegradations
 	return mr, nil
 }
 
@@ -931,12 +1051,13 @@ func (ms *builtIn) createBaseMoveRequest(
 			WorldState:  worldState,
 			Options:     valExtra.extra,
 		},
-		kinematicBase:     kb,
-		replanCostFactor:  valExtra.replanCostFactor,
-		atGoalCheck:       atGoalCheck,
-		obstacleDetectors: obstacleDetectors,
-		fsService:         ms.fsService,
-		localizingFS:      collisionFS,
+		kinematicBase:       kb,
+		replanCostFactor:    valExtra.replanCostFactor,
+		atGoalCheck:         atGoalCheck,
+		obstacleDetectors:   obstacleDetectors,
+		obstacleClearanceMM: valExtra.obstacleClearanceMM,
+		fsService:           ms.fsService,
+		localizingFS:        collisionFS,
 
 		executeBackgroundWorkers: &backgroundWorkers,
 
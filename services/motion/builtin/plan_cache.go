@@ -0,0 +1,164 @@
+package builtin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+)
+
+const (
+	// usePlanCacheKey is the extra of a move request that replays a cached plan for the same start, goal, and world state
+	// instead of planning, and caches the plan when there is none.
+	usePlanCacheKey = "use_plan_cache"
+	// maxCachedPlans is how many plans are cached before the oldest are evicted.
+	maxCachedPlans = 100
+	// planCacheInputResolution is the resolution that start inputs are rounded to when keying plans, so that a cycle
+	// returning to about the same start reuses its plan.
+	planCacheInputResolution = 1e-3
+	// planCacheStartTolerance is how far, by the L2 norm of each component's inputs, the components may be from the start
+	// of a cached plan to replay it.
+	planCacheStartTolerance = 1e-2
+)
+
+var errCachedPlanNotFound = errors.New("no cached plan with key")
+
+// planCache holds plans keyed by the hash of the move request and start state they were planned for.
+type planCache struct {
+	mu    sync.Mutex
+	plans map[string]motionplan.Plan
+	// keys are in the order their plans were cached, for eviction
+	keys []string
+}
+
+func newPlanCache() *planCache {
+	return &planCache{plans: map[string]motionplan.Plan{}}
+}
+
+func (c *planCache) get(key string) (motionplan.Plan, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	plan, ok := c.plans[key]
+	return plan, ok
+}
+
+func (c *planCache) put(key string, plan motionplan.Plan) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.plans[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
+	c.plans[key] = plan
+	for len(c.keys) > maxCachedPlans {
+		delete(c.plans, c.keys[0])
+		c.keys = c.keys[1:]
+	}
+}
+
+// planCacheKey returns the key of the plan for `req` from the current state of the frame system: a hash of the request,
+// which holds its goal, world state and constraints, of the frame system, and of the current inputs.
+func (ms *builtIn) planCacheKey(ctx context.Context, req motion.MoveReq) (string, error) {
+	reqProto, err := req.ToProto(ms.Name().ShortName())
+	if err != nil {
+		return "", err
+	}
+	reqBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(reqProto)
+	if err != nil {
+		return "", err
+	}
+	frameSys, err := ms.fsService.FrameSystem(ctx, req.WorldState.Transforms())
+	if err != nil {
+		return "", err
+	}
+	inputs, _, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(reqBytes)
+	names := frameSys.FrameNames()
+	sort.Strings(names)
+	for _, name := range names {
+		frame := frameSys.Frame(name)
+		parent, err := frameSys.Parent(frame)
+		if err != nil {
+			return "", err
+		}
+		frameBytes, err := frame.MarshalJSON()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s:%s:%s;", name, parent.Name(), frameBytes)
+		for _, input := range inputs[name] {
+			fmt.Fprintf(hash, "%d,", int64(math.Round(input.Value/planCacheInputResolution)))
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// planWithCache plans `req`, or replays the cached plan for it if the request's extra has usePlanCacheKey set.
+func (ms *builtIn) planWithCache(ctx context.Context, req motion.MoveReq) (motionplan.Plan, error) {
+	if useCache, _ := req.Extra[usePlanCacheKey].(bool); !useCache {
+		return ms.plan(ctx, req)
+	}
+	plan, _, err := ms.cachePlan(ctx, req)
+	return plan, err
+}
+
+// cachePlan returns the cached plan for `req` and its key, planning and caching it if there is none.
+func (ms *builtIn) cachePlan(ctx context.Context, req motion.MoveReq) (motionplan.Plan, string, error) {
+	key, err := ms.planCacheKey(ctx, req)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "cannot key plan cache")
+	}
+	if plan, ok := ms.planCache.get(key); ok {
+		ms.logger.CDebugw(ctx, "replaying cached plan", "key", key)
+		return plan, key, nil
+	}
+	plan, err := ms.plan(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	ms.planCache.put(key, plan)
+	return plan, key, nil
+}
+
+// executeCachedPlan executes the cached plan with `key` if the components are at its start.
+func (ms *builtIn) executeCachedPlan(ctx context.Context, key string) error {
+	plan, ok := ms.planCache.get(key)
+	if !ok {
+		return errors.Wrap(errCachedPlanNotFound, key)
+	}
+	trajectory := plan.Trajectory()
+	if len(trajectory) == 0 {
+		return nil
+	}
+	inputs, _, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	for name, startInputs := range trajectory[0] {
+		if len(startInputs) == 0 {
+			continue
+		}
+		if len(inputs[name]) != len(startInputs) ||
+			referenceframe.InputsL2Distance(startInputs, inputs[name]) > planCacheStartTolerance {
+			return fmt.Errorf("cannot replay cached plan, %s is not at the start of the plan", name)
+		}
+	}
+	if timed, ok := plan.(motionplan.TimedPlan); ok {
+		return ms.executeTimed(ctx, trajectory, timed.Times())
+	}
+	return ms.execute(ctx, trajectory)
+}
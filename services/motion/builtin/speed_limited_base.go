@@ -0,0 +1,72 @@
+package builtin
+
+import (
+	"context"
+	"math"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/services/motion"
+)
+
+// speedLimitedBase is a base whose velocity commands are scaled down to the speed limits of the zone the movement
+// sensor is in when they are given.
+type speedLimitedBase struct {
+	base.Base
+	movementSensor movementsensor.MovementSensor
+	limits         []*motion.SpeedLimit
+}
+
+func newSpeedLimitedBase(b base.Base, movementSensor movementsensor.MovementSensor, limits []*motion.SpeedLimit) base.Base {
+	return &speedLimitedBase{Base: b, movementSensor: movementSensor, limits: limits}
+}
+
+// limitsHere returns the speed limits, in mm/sec and degs/sec, where the base is.
+func (b *speedLimitedBase) limitsHere(ctx context.Context) (mmPerSec, degsPerSec float64, err error) {
+	position, _, err := b.movementSensor.Position(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	linear, angular := motion.SpeedLimitsAt(b.limits, position)
+	return linear * 1000, angular, nil
+}
+
+func (b *speedLimitedBase) MoveStraight(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+	maxMMPerSec, _, err := b.limitsHere(ctx)
+	if err != nil {
+		return err
+	}
+	if math.Abs(mmPerSec) > maxMMPerSec {
+		mmPerSec = math.Copysign(maxMMPerSec, mmPerSec)
+	}
+	return b.Base.MoveStraight(ctx, distanceMm, mmPerSec, extra)
+}
+
+func (b *speedLimitedBase) Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+	_, maxDegsPerSec, err := b.limitsHere(ctx)
+	if err != nil {
+		return err
+	}
+	if math.Abs(degsPerSec) > maxDegsPerSec {
+		degsPerSec = math.Copysign(maxDegsPerSec, degsPerSec)
+	}
+	return b.Base.Spin(ctx, angleDeg, degsPerSec, extra)
+}
+
+// SetVelocity scales both velocities by the same factor so that the base keeps following the same arc.
+func (b *speedLimitedBase) SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	maxMMPerSec, maxDegsPerSec, err := b.limitsHere(ctx)
+	if err != nil {
+		return err
+	}
+	scale := 1.
+	if speed := linear.Norm(); speed > maxMMPerSec {
+		scale = maxMMPerSec / speed
+	}
+	if speed := angular.Norm(); speed > maxDegsPerSec {
+		scale = math.Min(scale, maxDegsPerSec/speed)
+	}
+	return b.Base.SetVelocity(ctx, linear.Mul(scale), angular.Mul(scale), extra)
+}
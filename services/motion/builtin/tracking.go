@@ -0,0 +1,137 @@
+package builtin
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/services/motion"
+)
+
+// trackingMonitor checks that components follow their plans while they move, see motion.ExecutionMonitoringConfig.
+type trackingMonitor struct {
+	conf     motion.ExecutionMonitoringConfig
+	interval time.Duration
+}
+
+// newTrackingMonitor returns the monitor configured by `conf`, or nil if execution is not monitored.
+func newTrackingMonitor(conf *motion.ExecutionMonitoringConfig) *trackingMonitor {
+	if conf == nil {
+		return nil
+	}
+	intervalMs := conf.CheckIntervalMs
+	if intervalMs == 0 {
+		intervalMs = motion.DefaultTrackingCheckIntervalMs
+	}
+	return &trackingMonitor{conf: *conf, interval: time.Duration(intervalMs) * time.Millisecond}
+}
+
+// trackedPath is the path a monitored component is planned to move along during a step.
+type trackedPath struct {
+	name  string
+	r     framesystem.InputEnabled
+	limit float64
+	// path starts from the inputs of the component when the step started
+	path [][]referenceframe.Input
+}
+
+// paths returns the planned paths of the monitored components moving through `step`.
+func (m *trackingMonitor) paths(
+	ctx context.Context,
+	moving map[string]framesystem.InputEnabled,
+	step map[string][][]referenceframe.Input,
+) ([]trackedPath, error) {
+	var paths []trackedPath
+	for name, r := range moving {
+		limit := m.conf.Limit(name)
+		if limit <= 0 {
+			continue
+		}
+		start, err := r.CurrentInputs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, trackedPath{
+			name:  name,
+			r:     r,
+			limit: limit,
+			path:  append([][]referenceframe.Input{start}, step[name]...),
+		})
+	}
+	return paths, nil
+}
+
+// watch compares the inputs of the components to their paths every interval until ctx is done, and returns the
+// first tracking error. Components whose inputs can't be read are checked again at the next interval.
+func (m *trackingMonitor) watch(ctx context.Context, paths []trackedPath) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		for _, p := range paths {
+			inputs, err := p.r.CurrentInputs(ctx)
+			if err != nil {
+				continue
+			}
+			if distance := distanceToPath(p.path, inputs); distance > p.limit {
+				return &motion.TrackingError{Component: p.name, Inputs: inputs, Distance: distance, Limit: p.limit}
+			}
+		}
+	}
+}
+
+// checkArrival returns a tracking error if a component finished moving further than its limit from the end of its
+// path, such as one which stalled.
+func (m *trackingMonitor) checkArrival(ctx context.Context, paths []trackedPath) error {
+	for _, p := range paths {
+		inputs, err := p.r.CurrentInputs(ctx)
+		if err != nil {
+			return err
+		}
+		if distance := referenceframe.InputsL2Distance(p.path[len(p.path)-1], inputs); distance > p.limit {
+			return &motion.TrackingError{Component: p.name, Inputs: inputs, Distance: distance, Limit: p.limit}
+		}
+	}
+	return nil
+}
+
+// distanceToPath returns the L2 distance from `inputs` to the closest point of the piecewise linear path through
+// `path`, as components may be anywhere between two consecutive inputs of their plan.
+func distanceToPath(path [][]referenceframe.Input, inputs []referenceframe.Input) float64 {
+	if len(path) == 1 {
+		return referenceframe.InputsL2Distance(path[0], inputs)
+	}
+	distance := math.Inf(1)
+	for i := 1; i < len(path); i++ {
+		distance = math.Min(distance, distanceToSegment(path[i-1], path[i], inputs))
+	}
+	return distance
+}
+
+func distanceToSegment(from, to, inputs []referenceframe.Input) float64 {
+	if len(from) != len(inputs) || len(to) != len(inputs) {
+		return math.Inf(1)
+	}
+	var lengthSq, projection float64
+	for i := range from {
+		d := to[i].Value - from[i].Value
+		lengthSq += d * d
+		projection += (inputs[i].Value - from[i].Value) * d
+	}
+	var t float64
+	if lengthSq > 0 {
+		t = math.Max(0, math.Min(1, projection/lengthSq))
+	}
+	var distanceSq float64
+	for i := range from {
+		d := inputs[i].Value - (from[i].Value + t*(to[i].Value-from[i].Value))
+		distanceSq += d * d
+	}
+	return math.Sqrt(distanceSq)
+}
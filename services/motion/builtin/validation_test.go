@@ -859,3 +859,57 @@ func TestNewValidatedMotionCfg(t *testing.T) {
 		})
 	})
 }
+
+func TestNewValidatedExtra(t *testing.T) {
+	t.Run("replan budget and obstacle clearance", func(t *testing.T) {
+		// numbers sent over the network are float64s
+		valExtra, err := newValidatedExtra(map[string]interface{}{"max_replans": 3., "obstacle_clearance_mm": 150.})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.maxReplans, test.ShouldEqual, 3)
+		test.That(t, valExtra.obstacleClearanceMM, test.ShouldEqual, 150)
+
+		valExtra, err = newValidatedExtra(map[string]interface{}{"max_replans": 2})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.maxReplans, test.ShouldEqual, 2)
+		test.That(t, valExtra.obstacleClearanceMM, test.ShouldEqual, 0)
+
+		_, err = newValidatedExtra(map[string]interface{}{"obstacle_clearance_mm": -1.})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("minimum localization score", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{"min_localization_score": 0.6})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.minLocalizationScore, test.ShouldEqual, 0.6)
+
+		_, err = newValidatedExtra(map[string]interface{}{"min_localization_score": 1.5})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = newValidatedExtra(map[string]interface{}{"min_localization_score": "high"})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestInflateGeometry(t *testing.T) {
+	pose := spatialmath.NewPoseFromPoint(r3.Vector{X: 10, Y: 20})
+	box, err := spatialmath.NewBox(pose, r3.Vector{X: 100, Y: 200, Z: 300}, "box")
+	test.That(t, err, test.ShouldBeNil)
+	inflated, err := inflateGeometry(box, 50)
+	test.That(t, err, test.ShouldBeNil)
+	expected, err := spatialmath.NewBox(pose, r3.Vector{X: 200, Y: 300, Z: 400}, "box")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.GeometriesAlmostEqual(inflated, expected), test.ShouldBeTrue)
+
+	sphere, err := spatialmath.NewSphere(pose, 10, "sphere")
+	test.That(t, err, test.ShouldBeNil)
+	inflated, err = inflateGeometry(sphere, 50)
+	test.That(t, err, test.ShouldBeNil)
+	expected, err = spatialmath.NewSphere(pose, 60, "sphere")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.GeometriesAlmostEqual(inflated, expected), test.ShouldBeTrue)
+
+	inflated, err = inflateGeometry(spatialmath.NewPoint(pose.Point(), "point"), 50)
+	test.That(t, err, test.ShouldBeNil)
+	expected, err = spatialmath.NewSphere(spatialmath.NewPoseFromPoint(pose.Point()), 50, "point")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.GeometriesAlmostEqual(inflated, expected), test.ShouldBeTrue)
+}
@@ -0,0 +1,255 @@
+package builtin
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	commonpb "go.viam.com/api/common/v1"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// Behaviors of in-progress moves when a world state update invalidates their remaining trajectory, set with the
+// "obstacle_update_behavior" extra of a move request.
+const (
+	obstacleUpdateReplan = "replan"
+	obstacleUpdateStop   = "stop"
+)
+
+// defaultObstacleUpdateReplans is how many times a move replans around world state updates before failing, unless
+// "max_replans" is set in its extra.
+const defaultObstacleUpdateReplans = 3
+
+var errPlanInvalidated = errors.New("world state update invalidated the remaining plan")
+
+// worldStateUpdates hold the world state set with DoUpdateWorldState, whose obstacles are added to those of each
+// move request, including moves that are already executing.
+type worldStateUpdates struct {
+	mu         sync.Mutex
+	worldState *referenceframe.WorldState
+	// changed is closed and replaced whenever the world state is set
+	changed chan struct{}
+}
+
+func newWorldStateUpdates() *worldStateUpdates {
+	return &worldStateUpdates{worldState: referenceframe.NewEmptyWorldState(), changed: make(chan struct{})}
+}
+
+func (u *worldStateUpdates) set(worldState *referenceframe.WorldState) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.worldState = worldState
+	close(u.changed)
+	u.changed = make(chan struct{})
+}
+
+// get returns the current world state and a channel closed when it next changes.
+func (u *worldStateUpdates) get() (*referenceframe.WorldState, <-chan struct{}) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.worldState, u.changed
+}
+
+// mergeWorldStates returns a world state with the obstacles and transforms of both `a` and `b`.
+func mergeWorldStates(a, b *referenceframe.WorldState) (*referenceframe.WorldState, error) {
+	aProto, err := a.ToProtobuf()
+	if err != nil {
+		return nil, err
+	}
+	bProto, err := b.ToProtobuf()
+	if err != nil {
+		return nil, err
+	}
+	return referenceframe.WorldStateFromProtobuf(&commonpb.WorldState{
+		Obstacles:  append(aProto.GetObstacles(), bProto.GetObstacles()...),
+		Transforms: append(aProto.GetTransforms(), bProto.GetTransforms()...),
+	})
+}
+
+// withWorldStateUpdates returns `req` with the obstacles of `updates` added to its world state.
+func withWorldStateUpdates(req motion.MoveReq, updates *referenceframe.WorldState) (motion.MoveReq, error) {
+	merged, err := mergeWorldStates(req.WorldState, updates)
+	if err != nil {
+		return motion.MoveReq{}, errors.Wrap(err, "cannot add world state updates to move request")
+	}
+	req.WorldState = merged
+	return req, nil
+}
+
+// obstacleUpdateSettings returns how a move handles world state updates that invalidate its plan.
+func obstacleUpdateSettings(extra map[string]interface{}) (string, int, error) {
+	behavior := obstacleUpdateReplan
+	if raw, ok := extra["obstacle_update_behavior"]; ok {
+		var isString bool
+		if behavior, isString = raw.(string); !isString || (behavior != obstacleUpdateReplan && behavior != obstacleUpdateStop) {
+			return "", 0, errors.Errorf("obstacle_update_behavior must be %q or %q", obstacleUpdateReplan, obstacleUpdateStop)
+		}
+	}
+	maxReplans := defaultObstacleUpdateReplans
+	switch replans := extra["max_replans"].(type) {
+	case int:
+		maxReplans = replans
+	case float64:
+		maxReplans = int(replans)
+	}
+	return behavior, maxReplans, nil
+}
+
+// moveWithWorldStateUpdates plans and executes `req`, checking the remaining plan against each world state update
+// made while it executes. An invalidated plan is stopped and, depending on the request's obstacle_update_behavior,
+// replanned from where the components stopped.
+func (ms *builtIn) moveWithWorldStateUpdates(ctx context.Context, req motion.MoveReq) error {
+	behavior, maxReplans, err := obstacleUpdateSettings(req.Extra)
+	if err != nil {
+		return err
+	}
+	for replans := 0; ; replans++ {
+		updates, changed := ms.worldStateUpdates.get()
+		updatedReq, err := withWorldStateUpdates(req, updates)
+		if err != nil {
+			return err
+		}
+		plan, err := ms.planWithCache(ctx, updatedReq)
+		if err != nil {
+			return err
+		}
+		err = ms.executeWatchingWorldState(ctx, req, plan, changed)
+		if !errors.Is(err, errPlanInvalidated) || behavior == obstacleUpdateStop || replans >= maxReplans {
+			return err
+		}
+		ms.logger.CInfow(ctx, "replanning move", "reason", err)
+	}
+}
+
+// executeWatchingWorldState executes `plan`, stopping the components it moves if a world state update puts an obstacle
+// in the way of its remaining trajectory.
+func (ms *builtIn) executeWatchingWorldState(
+	ctx context.Context,
+	req motion.MoveReq,
+	plan motionplan.Plan,
+	changed <-chan struct{},
+) error {
+	execCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan error, 1)
+	goutils.PanicCapturingGo(func() {
+		if timed, ok := plan.(motionplan.TimedPlan); ok {
+			done <- ms.executeTimed(execCtx, timed.Trajectory(), timed.Times())
+		} else {
+			done <- ms.execute(execCtx, plan.Trajectory())
+		}
+	})
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-changed:
+			var updates *referenceframe.WorldState
+			updates, changed = ms.worldStateUpdates.get()
+			checkErr := ms.checkRemainingPlan(ctx, req, plan, updates)
+			if checkErr == nil {
+				continue
+			}
+			cancel()
+			<-done
+			if err := ms.stopPlanComponents(ctx, plan); err != nil {
+				return errors.Wrap(err, checkErr.Error())
+			}
+			return errors.Wrap(errPlanInvalidated, checkErr.Error())
+		}
+	}
+}
+
+// checkRemainingPlan checks the rest of `plan`, from the step closest to where the components are now, for collisions
+// with the obstacles of the move request and `updates`.
+func (ms *builtIn) checkRemainingPlan(
+	ctx context.Context,
+	req motion.MoveReq,
+	plan motionplan.Plan,
+	updates *referenceframe.WorldState,
+) error {
+	updatedReq, err := withWorldStateUpdates(req, updates)
+	if err != nil {
+		return err
+	}
+	frameSys, err := ms.fsService.FrameSystem(ctx, updatedReq.WorldState.Transforms())
+	if err != nil {
+		return err
+	}
+	inputs, _, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	name := req.ComponentName.ShortName()
+	checkFrame := frameSys.Frame(name)
+	if checkFrame == nil {
+		return referenceframe.NewFrameMissingError(name)
+	}
+	currentPose, err := frameSys.Transform(
+		inputs,
+		referenceframe.NewPoseInFrame(name, spatialmath.NewZeroPose()),
+		referenceframe.World,
+	)
+	if err != nil {
+		return err
+	}
+	currentPoseInFrame, ok := currentPose.(*referenceframe.PoseInFrame)
+	if !ok {
+		return errors.New("could not convert transformable to a PoseInFrame")
+	}
+	executionState, err := motionplan.NewExecutionState(
+		plan,
+		closestStep(plan.Trajectory(), inputs),
+		inputs,
+		referenceframe.FrameSystemPoses{name: currentPoseInFrame},
+	)
+	if err != nil {
+		return err
+	}
+	return motionplan.CheckPlan(checkFrame, executionState, updatedReq.WorldState, frameSys, lookAheadDistanceMM, ms.logger)
+}
+
+// closestStep returns the index of the step of `trajectory` whose inputs are closest to `inputs`.
+func closestStep(trajectory motionplan.Trajectory, inputs referenceframe.FrameSystemInputs) int {
+	closest := 0
+	closestDist := math.Inf(1)
+	for i, step := range trajectory {
+		dist := 0.
+		for name, stepInputs := range step {
+			dist += referenceframe.InputsL2Distance(stepInputs, inputs[name])
+		}
+		if dist < closestDist {
+			closest, closestDist = i, dist
+		}
+	}
+	return closest
+}
+
+// stopPlanComponents stops each component moved by `plan`.
+func (ms *builtIn) stopPlanComponents(ctx context.Context, plan motionplan.Plan) error {
+	_, resources, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	var stopErr error
+	moved := map[string]bool{}
+	for _, step := range plan.Trajectory() {
+		for name, inputs := range step {
+			if len(inputs) == 0 || moved[name] {
+				continue
+			}
+			moved[name] = true
+			if actuator, ok := resources[name].(inputEnabledActuator); ok {
+				stopErr = multierr.Combine(stopErr, actuator.Stop(ctx, nil))
+			}
+		}
+	}
+	return stopErr
+}
@@ -0,0 +1,181 @@
+package motion
+
+import (
+	"encoding/json"
+	"fmt"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// GeofenceType is whether a geofence keeps components out of or inside of its zone.
+type GeofenceType string
+
+// The kinds of geofences.
+const (
+	// GeofenceKeepOut zones may not be entered.
+	GeofenceKeepOut = GeofenceType("keep_out")
+	// GeofenceKeepIn zones may not be left. When there are several, components must stay within one of them.
+	GeofenceKeepIn = GeofenceType("keep_in")
+)
+
+// ErrGeofenceViolation is wrapped by the errors of moves that would violate or have violated a geofence. Since the
+// errors of moves reach clients as plan status reasons, its message can also be used to recognize them.
+var ErrGeofenceViolation = errors.New("geofence violation")
+
+// GeofenceConfig describes a geofence. Its zone is given by the polygons of a GeoJSON object.
+type GeofenceConfig struct {
+	Name    string                 `json:"name"`
+	Type    GeofenceType           `json:"type"`
+	GeoJSON map[string]interface{} `json:"geojson"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *GeofenceConfig) Validate(path string) error {
+	if _, err := NewGeofence(conf); err != nil {
+		return resource.NewConfigValidationError(path, err)
+	}
+	return nil
+}
+
+// Geofence is a zone of GPS coordinates that components moved with MoveOnGlobe must stay out of or inside of.
+type Geofence struct {
+	conf     GeofenceConfig
+	polygons []*spatialmath.GeoPolygon
+}
+
+// NewGeofence returns the geofence described by `conf`.
+func NewGeofence(conf *GeofenceConfig) (*Geofence, error) {
+	if conf.Name == "" {
+		return nil, errors.New("geofences need a name")
+	}
+	if conf.Type != GeofenceKeepOut && conf.Type != GeofenceKeepIn {
+		return nil, errors.Errorf("geofence %q type must be %q or %q", conf.Name, GeofenceKeepOut, GeofenceKeepIn)
+	}
+	data, err := json.Marshal(conf.GeoJSON)
+	if err != nil {
+		return nil, err
+	}
+	polygons, err := spatialmath.GeoPolygonsFromGeoJSON(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "geofence %q", conf.Name)
+	}
+	// keep the GeoJSON as plain JSON values so that it can be carried in protobuf structs
+	normalized := GeofenceConfig{Name: conf.Name, Type: conf.Type}
+	if err := json.Unmarshal(data, &normalized.GeoJSON); err != nil {
+		return nil, err
+	}
+	return &Geofence{conf: normalized, polygons: polygons}, nil
+}
+
+// NewGeofences returns the geofences described by `confs`.
+func NewGeofences(confs []*GeofenceConfig) ([]*Geofence, error) {
+	geofences := make([]*Geofence, 0, len(confs))
+	for _, conf := range confs {
+		geofence, err := NewGeofence(conf)
+		if err != nil {
+			return nil, err
+		}
+		geofences = append(geofences, geofence)
+	}
+	return geofences, nil
+}
+
+// Name returns the name of the geofence.
+func (g *Geofence) Name() string {
+	return g.conf.Name
+}
+
+// Type returns whether the geofence is a keep-out or keep-in zone.
+func (g *Geofence) Type() GeofenceType {
+	return g.conf.Type
+}
+
+// Config returns the config describing the geofence.
+func (g *Geofence) Config() *GeofenceConfig {
+	conf := g.conf
+	return &conf
+}
+
+// Contains returns whether the point is inside the geofence's zone.
+func (g *Geofence) Contains(pt *geo.Point) bool {
+	for _, polygon := range g.polygons {
+		if polygon.Contains(pt) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsSegment returns whether the segment from `a` to `b` is inside a single polygon of the geofence's zone.
+func (g *Geofence) containsSegment(a, b *geo.Point) bool {
+	for _, polygon := range g.polygons {
+		if polygon.Contains(a) && polygon.Contains(b) && !polygon.CrossedBy(a, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// crossedBy returns whether the segment from `a` to `b` enters the geofence's zone.
+func (g *Geofence) crossedBy(a, b *geo.Point) bool {
+	for _, polygon := range g.polygons {
+		if polygon.Contains(a) || polygon.Contains(b) || polygon.CrossedBy(a, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckGeofences returns an error wrapping ErrGeofenceViolation if the path through `points` enters a keep-out
+// geofence or leaves the keep-in geofences. A single point is checked on its own.
+func CheckGeofences(geofences []*Geofence, points ...*geo.Point) error {
+	if len(points) == 0 {
+		return nil
+	}
+	segments := [][2]*geo.Point{}
+	for i := 1; i < len(points); i++ {
+		segments = append(segments, [2]*geo.Point{points[i-1], points[i]})
+	}
+	if len(points) == 1 {
+		segments = append(segments, [2]*geo.Point{points[0], points[0]})
+	}
+
+	var keepIn []*Geofence
+	for _, geofence := range geofences {
+		if geofence.Type() == GeofenceKeepIn {
+			keepIn = append(keepIn, geofence)
+		}
+	}
+	for _, segment := range segments {
+		for _, geofence := range geofences {
+			if geofence.Type() == GeofenceKeepOut && geofence.crossedBy(segment[0], segment[1]) {
+				return errors.Wrapf(ErrGeofenceViolation, "%s enters keep-out geofence %q", describeSegment(segment), geofence.Name())
+			}
+		}
+		if len(keepIn) == 0 {
+			continue
+		}
+		inside := false
+		for _, geofence := range keepIn {
+			if geofence.containsSegment(segment[0], segment[1]) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			return errors.Wrapf(ErrGeofenceViolation, "%s leaves the keep-in geofences", describeSegment(segment))
+		}
+	}
+	return nil
+}
+
+func describeSegment(segment [2]*geo.Point) string {
+	if *segment[0] == *segment[1] {
+		return fmt.Sprintf("point (%f, %f)", segment[0].Lat(), segment[0].Lng())
+	}
+	return fmt.Sprintf("path from (%f, %f) to (%f, %f)", segment[0].Lat(), segment[0].Lng(), segment[1].Lat(), segment[1].Lng())
+}
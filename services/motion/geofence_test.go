@@ -0,0 +1,107 @@
+package motion
+
+import (
+	"testing"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+)
+
+func squareGeofence(t *testing.T, name string, fenceType GeofenceType, minLat, minLng, maxLat, maxLng float64) *Geofence {
+	t.Helper()
+	geofence, err := NewGeofence(&GeofenceConfig{
+		Name: name,
+		Type: fenceType,
+		GeoJSON: map[string]interface{}{
+			"type": "Polygon",
+			"coordinates": [][][]float64{{
+				{minLng, minLat}, {maxLng, minLat}, {maxLng, maxLat}, {minLng, maxLat}, {minLng, minLat},
+			}},
+		},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	return geofence
+}
+
+func TestNewGeofence(t *testing.T) {
+	polygon := map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{{{0, 0}, {1, 0}, {1, 1}}}}
+	_, err := NewGeofence(&GeofenceConfig{Type: GeofenceKeepOut, GeoJSON: polygon})
+	test.That(t, err, test.ShouldBeError, "geofences need a name")
+	_, err = NewGeofence(&GeofenceConfig{Name: "pond", Type: "keep_away", GeoJSON: polygon})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewGeofence(&GeofenceConfig{Name: "pond", Type: GeofenceKeepOut})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	conf := &GeofenceConfig{Name: "pond", Type: GeofenceKeepOut, GeoJSON: polygon}
+	test.That(t, conf.Validate("path"), test.ShouldBeNil)
+	geofence, err := NewGeofence(conf)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, geofence.Name(), test.ShouldEqual, "pond")
+	test.That(t, geofence.Type(), test.ShouldEqual, GeofenceKeepOut)
+	test.That(t, geofence.Contains(geo.NewPoint(0.2, 0.5)), test.ShouldBeTrue)
+	test.That(t, geofence.Contains(geo.NewPoint(0.5, 0.2)), test.ShouldBeFalse)
+	// the GeoJSON is kept as plain JSON values
+	test.That(t, geofence.Config().GeoJSON["coordinates"], test.ShouldHaveSameTypeAs, []interface{}{})
+}
+
+func TestCheckGeofences(t *testing.T) {
+	yard := squareGeofence(t, "yard", GeofenceKeepIn, 0, 0, 10, 10)
+	field := squareGeofence(t, "field", GeofenceKeepIn, 0, 10, 10, 20)
+	pond := squareGeofence(t, "pond", GeofenceKeepOut, 4, 4, 6, 6)
+	geofences := []*Geofence{yard, field, pond}
+
+	test.That(t, CheckGeofences(geofences), test.ShouldBeNil)
+	test.That(t, CheckGeofences(geofences, geo.NewPoint(1, 1)), test.ShouldBeNil)
+	test.That(t, CheckGeofences(geofences, geo.NewPoint(1, 1), geo.NewPoint(1, 9)), test.ShouldBeNil)
+	test.That(t, CheckGeofences(geofences, geo.NewPoint(1, 15)), test.ShouldBeNil)
+
+	err := CheckGeofences(geofences, geo.NewPoint(5, 5))
+	test.That(t, errors.Is(err, ErrGeofenceViolation), test.ShouldBeTrue)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `keep-out geofence "pond"`)
+
+	// passing through the pond without stopping in it is a violation too
+	err = CheckGeofences(geofences, geo.NewPoint(5, 1), geo.NewPoint(5, 9))
+	test.That(t, errors.Is(err, ErrGeofenceViolation), test.ShouldBeTrue)
+
+	err = CheckGeofences(geofences, geo.NewPoint(1, 1), geo.NewPoint(11, 1))
+	test.That(t, errors.Is(err, ErrGeofenceViolation), test.ShouldBeTrue)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "leaves the keep-in geofences")
+
+	// keep-out geofences apply on their own
+	test.That(t, CheckGeofences([]*Geofence{pond}, geo.NewPoint(50, 50)), test.ShouldBeNil)
+}
+
+func TestMoveOnGlobeReqGeofences(t *testing.T) {
+	extra := map[string]interface{}{"smooth_iter": 10.}
+	req := MoveOnGlobeReq{
+		ComponentName:      base.Named("base"),
+		Destination:        geo.NewPoint(1, 2),
+		MovementSensorName: movementsensor.Named("gps"),
+		Geofences: []*Geofence{
+			squareGeofence(t, "yard", GeofenceKeepIn, 0, 0, 10, 10),
+			squareGeofence(t, "pond", GeofenceKeepOut, 4, 4, 6, 6),
+		},
+		Extra: extra,
+	}
+
+	pbReq, err := req.toProto("motion")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, extra, test.ShouldResemble, map[string]interface{}{"smooth_iter": 10.})
+
+	roundTrip, err := moveOnGlobeRequestFromProto(pbReq)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, roundTrip.Extra, test.ShouldResemble, extra)
+	test.That(t, len(roundTrip.Geofences), test.ShouldEqual, 2)
+	for i, geofence := range req.Geofences {
+		test.That(t, roundTrip.Geofences[i].Config(), test.ShouldResemble, geofence.Config())
+	}
+
+	pbReq.Extra.Fields[geofencesKey] = structpb.NewStringValue("there")
+	_, err = moveOnGlobeRequestFromProto(pbReq)
+	test.That(t, err, test.ShouldNotBeNil)
+}
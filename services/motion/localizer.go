@@ -18,6 +18,28 @@ import (
 // However, for a rover's relative planning frame, driving forwards increments +Y. Thus we must adjust where the rover thinks it is.
 var SLAMOrientationAdjustment = spatialmath.NewPoseFromOrientation(&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: -90})
 
+// ErrLocalizationDegraded is returned when a move is stopped because a SLAM service's localization
+// quality fell below the minimum the move requires.
+var ErrLocalizationDegraded = errors.New("localization degraded")
+
+// CheckLocalizationQuality returns an error wrapping ErrLocalizationDegraded if `svc` reports a
+// match score below minMatchScore. SLAM services that cannot report their localization quality
+// always pass.
+func CheckLocalizationQuality(ctx context.Context, svc slam.Service, minMatchScore float64) error {
+	reporter, ok := svc.(slam.LocalizationQualityReporter)
+	if !ok {
+		return nil
+	}
+	quality, err := reporter.LocalizationQuality(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if quality.MatchScore < minMatchScore {
+		return errors.Wrapf(ErrLocalizationDegraded, "match score %.2f is below the minimum of %.2f", quality.MatchScore, minMatchScore)
+	}
+	return nil
+}
+
 // Localizer is an interface which both slam and movementsensor can satisfy when wrapped respectively.
 type Localizer interface {
 	CurrentPosition(context.Context) (*referenceframe.PoseInFrame, error)
@@ -49,6 +49,9 @@ type MoveReq struct {
 	ComponentName resource.Name
 	// Goal destination the component should be moved to
 	Destination *referenceframe.PoseInFrame
+	// Goal destinations of other components, such as other arms, to move together with the component. They are planned
+	// as one motion that keeps the components from colliding with each other, and moved in sync.
+	Destinations map[resource.Name]*referenceframe.PoseInFrame
 	// The external environment to be considered for the duration of the move
 	WorldState *referenceframe.WorldState
 	// Constraints which need to be satisfied during the movement
@@ -71,6 +74,10 @@ type MoveOnGlobeReq struct {
 	Obstacles []*spatialmath.GeoGeometry
 	// Set of bounds which the robot must remain within while navigating
 	BoundingRegions []*spatialmath.GeoGeometry
+	// Keep-out and keep-in zones which the destination and the path to it may not violate
+	Geofences []*Geofence
+	// Zones in which the component is commanded no faster than their limits
+	SpeedLimits []*SpeedLimit
 	// Optional motion configuration
 	MotionCfg *MotionConfiguration
 	Extra     map[string]interface{}
@@ -79,7 +86,15 @@ type MoveOnGlobeReq struct {
 func (r MoveOnGlobeReq) String() string {
 	template := "motion.MoveOnGlobeReq{ComponentName: %s, " +
 		"Destination: %+v, Heading: %f, MovementSensorName: %s, " +
-		"Obstacles: %v, BoundingRegions: %v, MotionCfg: %#v, Extra: %s}"
+		"Obstacles: %v, BoundingRegions: %v, Geofences: %v, SpeedLimits: %v, MotionCfg: %#v, Extra: %s}"
+	geofenceNames := make([]string, 0, len(r.Geofences))
+	for _, geofence := range r.Geofences {
+		geofenceNames = append(geofenceNames, geofence.Name())
+	}
+	speedLimitNames := make([]string, 0, len(r.SpeedLimits))
+	for _, limit := range r.SpeedLimits {
+		speedLimitNames = append(speedLimitNames, limit.Name())
+	}
 	return fmt.Sprintf(template,
 		r.ComponentName,
 		r.Destination,
@@ -87,6 +102,8 @@ func (r MoveOnGlobeReq) String() string {
 		r.MovementSensorName,
 		r.Obstacles,
 		r.BoundingRegions,
+		geofenceNames,
+		speedLimitNames,
 		r.MotionCfg,
 		r.Extra)
 }
@@ -84,5 +84,14 @@ func MoveArm(ctx context.Context, logger logging.Logger, a arm.Arm, dst spatialm
 	if err != nil {
 		return err
 	}
+	if streamer, ok := a.(arm.TrajectoryStreamer); ok {
+		trajectory := arm.TimeParameterize(plan, arm.DefaultTrajectoryMaxVelRads)
+		points := make(chan arm.TrajectoryPoint, len(trajectory))
+		for _, point := range trajectory {
+			points <- point
+		}
+		close(points)
+		return streamer.StreamTrajectory(ctx, points, nil, nil)
+	}
 	return a.MoveThroughJointPositions(ctx, plan, nil, nil)
 }
@@ -16,6 +16,7 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/components/movementsensor"
@@ -824,6 +825,66 @@ func TestConfiguration(t *testing.T) {
 	})
 }
 
+func TestMoveReqVelocityConstraints(t *testing.T) {
+	constraints := motionplan.NewEmptyConstraints()
+	constraints.AddVelocityConstraint(motionplan.VelocityConstraint{MaxJointVelDegsPerSec: 30, MaxLinearAccMmPerSec2: 500})
+	extra := map[string]interface{}{"smooth_iter": 10.}
+	req := MoveReq{
+		ComponentName: base.Named("my-base"),
+		Destination:   referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewZeroPose()),
+		Constraints:   constraints,
+		Extra:         extra,
+	}
+
+	pbReq, err := req.ToProto("motion")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, extra, test.ShouldResemble, map[string]interface{}{"smooth_iter": 10.})
+	test.That(t, pbReq.Extra.AsMap()[velocityConstraintsKey], test.ShouldNotBeNil)
+
+	roundTrip, err := MoveReqFromProto(pbReq)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, roundTrip.Constraints.GetVelocityConstraint(), test.ShouldResemble, constraints.VelocityConstraint)
+	test.That(t, roundTrip.Extra, test.ShouldResemble, extra)
+
+	pbReq.Extra.Fields[velocityConstraintsKey] = structpb.NewStringValue("fast")
+	_, err = MoveReqFromProto(pbReq)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestMoveReqDestinations(t *testing.T) {
+	extra := map[string]interface{}{"smooth_iter": 10.}
+	req := MoveReq{
+		ComponentName: arm.Named("arm1"),
+		Destination:   referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewZeroPose()),
+		Destinations: map[resource.Name]*referenceframe.PoseInFrame{
+			arm.Named("arm2"): referenceframe.NewPoseInFrame("arm1", spatialmath.NewPose(
+				r3.Vector{X: 1, Y: 2, Z: 3},
+				&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90},
+			)),
+			arm.Named("arm3"): referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 4})),
+		},
+		Extra: extra,
+	}
+
+	pbReq, err := req.ToProto("motion")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, extra, test.ShouldResemble, map[string]interface{}{"smooth_iter": 10.})
+	test.That(t, pbReq.Extra.AsMap()[destinationsKey], test.ShouldNotBeNil)
+
+	roundTrip, err := MoveReqFromProto(pbReq)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, roundTrip.Extra, test.ShouldResemble, extra)
+	test.That(t, len(roundTrip.Destinations), test.ShouldEqual, 2)
+	for name, destination := range req.Destinations {
+		test.That(t, roundTrip.Destinations[name].Parent(), test.ShouldEqual, destination.Parent())
+		test.That(t, spatialmath.PoseAlmostEqual(roundTrip.Destinations[name].Pose(), destination.Pose()), test.ShouldBeTrue)
+	}
+
+	pbReq.Extra.Fields[destinationsKey] = structpb.NewStringValue("there")
+	_, err = MoveReqFromProto(pbReq)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
 func TestMoveOnGlobeReq(t *testing.T) {
 	name := "somename"
 	dst := geo.NewPoint(1, 2)
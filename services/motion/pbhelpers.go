@@ -2,6 +2,7 @@ package motion
 
 import (
 	"math"
+	"sort"
 
 	"github.com/google/uuid"
 	geo "github.com/kellydunn/golang-geo"
@@ -13,13 +14,16 @@ import (
 	"go.viam.com/rdk/motionplan"
 	rprotoutils "go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/spatialmath"
 )
 
 // ToProto converts a MoveReq to a pb.MoveRequest
 // the name argument should correspond to the name of the motion service the request will be used with.
 func (r MoveReq) ToProto(name string) (*pb.MoveRequest, error) {
-	ext, err := vprotoutils.StructToStructPb(r.Extra)
+	ext, err := vprotoutils.StructToStructPb(
+		destinationsToExtra(r.Destinations, velocityConstraintsToExtra(r.Constraints.GetVelocityConstraint(), r.Extra)),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -55,15 +59,275 @@ func MoveReqFromProto(req *pb.MoveRequest) (MoveReq, error) {
 		destination = referenceframe.ProtobufToPoseInFrame(req.GetDestination())
 	}
 
+	constraints := motionplan.ConstraintsFromProtobuf(req.GetConstraints())
+	extra := req.Extra.AsMap()
+	velConstraints, err := velocityConstraintsFromExtra(extra)
+	if err != nil {
+		return MoveReq{}, err
+	}
+	for _, velConstraint := range velConstraints {
+		constraints.AddVelocityConstraint(velConstraint)
+	}
+	destinations, err := destinationsFromExtra(extra)
+	if err != nil {
+		return MoveReq{}, err
+	}
+
 	return MoveReq{
-		rprotoutils.ResourceNameFromProto(req.GetComponentName()),
-		destination,
-		worldState,
-		motionplan.ConstraintsFromProtobuf(req.GetConstraints()),
-		req.Extra.AsMap(),
+		ComponentName: rprotoutils.ResourceNameFromProto(req.GetComponentName()),
+		Destination:   destination,
+		Destinations:  destinations,
+		WorldState:    worldState,
+		Constraints:   constraints,
+		Extra:         extra,
 	}, nil
 }
 
+// The motion protos have no velocity constraints, so they are carried in the extra of move requests under
+// velocityConstraintsKey.
+const velocityConstraintsKey = "velocity_constraints"
+
+// velocityConstraintsToExtra returns a copy of `extra` carrying `constraints`.
+func velocityConstraintsToExtra(constraints []motionplan.VelocityConstraint, extra map[string]interface{}) map[string]interface{} {
+	if len(constraints) == 0 {
+		return extra
+	}
+	withConstraints := make(map[string]interface{}, len(extra)+1)
+	for k, v := range extra {
+		withConstraints[k] = v
+	}
+	encoded := make([]interface{}, 0, len(constraints))
+	for _, c := range constraints {
+		encoded = append(encoded, map[string]interface{}{
+			"max_joint_vel_degs_per_sec":  c.MaxJointVelDegsPerSec,
+			"max_joint_acc_degs_per_sec2": c.MaxJointAccDegsPerSec2,
+			"max_linear_vel_mm_per_sec":   c.MaxLinearVelMmPerSec,
+			"max_linear_acc_mm_per_sec2":  c.MaxLinearAccMmPerSec2,
+		})
+	}
+	withConstraints[velocityConstraintsKey] = encoded
+	return withConstraints
+}
+
+// velocityConstraintsFromExtra removes the velocity constraints carried in `extra` and returns them.
+func velocityConstraintsFromExtra(extra map[string]interface{}) ([]motionplan.VelocityConstraint, error) {
+	raw, ok := extra[velocityConstraintsKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(extra, velocityConstraintsKey)
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("%q must be a list", velocityConstraintsKey)
+	}
+	constraints := make([]motionplan.VelocityConstraint, 0, len(list))
+	for _, rawEntry := range list {
+		entry, ok := rawEntry.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%q entries must be objects", velocityConstraintsKey)
+		}
+		var c motionplan.VelocityConstraint
+		c.MaxJointVelDegsPerSec, _ = entry["max_joint_vel_degs_per_sec"].(float64)   //nolint:errcheck
+		c.MaxJointAccDegsPerSec2, _ = entry["max_joint_acc_degs_per_sec2"].(float64) //nolint:errcheck
+		c.MaxLinearVelMmPerSec, _ = entry["max_linear_vel_mm_per_sec"].(float64)     //nolint:errcheck
+		c.MaxLinearAccMmPerSec2, _ = entry["max_linear_acc_mm_per_sec2"].(float64)   //nolint:errcheck
+		constraints = append(constraints, c)
+	}
+	return constraints, nil
+}
+
+// The motion protos have a single destination, so the destinations of the other components of a move are carried in
+// the extra of move requests under destinationsKey.
+const destinationsKey = "destinations"
+
+// destinationsToExtra returns a copy of `extra` carrying `destinations`.
+func destinationsToExtra(
+	destinations map[resource.Name]*referenceframe.PoseInFrame,
+	extra map[string]interface{},
+) map[string]interface{} {
+	if len(destinations) == 0 {
+		return extra
+	}
+	withDestinations := make(map[string]interface{}, len(extra)+1)
+	for k, v := range extra {
+		withDestinations[k] = v
+	}
+	// sorted so that the same destinations always encode the same way
+	names := make([]resource.Name, 0, len(destinations))
+	for name := range destinations {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i].String() < names[j].String() })
+	encoded := make([]interface{}, 0, len(destinations))
+	for _, name := range names {
+		destination := destinations[name]
+		pose := spatialmath.PoseToProtobuf(destination.Pose())
+		encoded = append(encoded, map[string]interface{}{
+			"component_name":  name.String(),
+			"reference_frame": destination.Parent(),
+			"x":               pose.X,
+			"y":               pose.Y,
+			"z":               pose.Z,
+			"o_x":             pose.OX,
+			"o_y":             pose.OY,
+			"o_z":             pose.OZ,
+			"theta":           pose.Theta,
+		})
+	}
+	withDestinations[destinationsKey] = encoded
+	return withDestinations
+}
+
+// destinationsFromExtra removes the destinations carried in `extra` and returns them.
+func destinationsFromExtra(extra map[string]interface{}) (map[resource.Name]*referenceframe.PoseInFrame, error) {
+	raw, ok := extra[destinationsKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(extra, destinationsKey)
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("%q must be a list", destinationsKey)
+	}
+	destinations := make(map[resource.Name]*referenceframe.PoseInFrame, len(list))
+	for _, rawEntry := range list {
+		entry, ok := rawEntry.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%q entries must be objects", destinationsKey)
+		}
+		rawName, _ := entry["component_name"].(string) //nolint:errcheck
+		name, err := resource.NewFromString(rawName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid component_name in %q", destinationsKey)
+		}
+		var pose commonpb.Pose
+		pose.X, _ = entry["x"].(float64)              //nolint:errcheck
+		pose.Y, _ = entry["y"].(float64)              //nolint:errcheck
+		pose.Z, _ = entry["z"].(float64)              //nolint:errcheck
+		pose.OX, _ = entry["o_x"].(float64)           //nolint:errcheck
+		pose.OY, _ = entry["o_y"].(float64)           //nolint:errcheck
+		pose.OZ, _ = entry["o_z"].(float64)           //nolint:errcheck
+		pose.Theta, _ = entry["theta"].(float64)      //nolint:errcheck
+		frame, _ := entry["reference_frame"].(string) //nolint:errcheck
+		destinations[name] = referenceframe.NewPoseInFrame(frame, spatialmath.NewPoseFromProtobuf(&pose))
+	}
+	return destinations, nil
+}
+
+// The motion protos have no geofences, so the geofences of MoveOnGlobe requests are carried in their extra under
+// geofencesKey, encoded as their configs.
+const geofencesKey = "geofences"
+
+// geofencesToExtra returns a copy of `extra` carrying `geofences`.
+func geofencesToExtra(geofences []*Geofence, extra map[string]interface{}) map[string]interface{} {
+	if len(geofences) == 0 {
+		return extra
+	}
+	withGeofences := make(map[string]interface{}, len(extra)+1)
+	for k, v := range extra {
+		withGeofences[k] = v
+	}
+	encoded := make([]interface{}, 0, len(geofences))
+	for _, geofence := range geofences {
+		encoded = append(encoded, map[string]interface{}{
+			"name":    geofence.Name(),
+			"type":    string(geofence.Type()),
+			"geojson": geofence.Config().GeoJSON,
+		})
+	}
+	withGeofences[geofencesKey] = encoded
+	return withGeofences
+}
+
+// geofencesFromExtra removes the geofences carried in `extra` and returns them.
+func geofencesFromExtra(extra map[string]interface{}) ([]*Geofence, error) {
+	raw, ok := extra[geofencesKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(extra, geofencesKey)
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("%q must be a list", geofencesKey)
+	}
+	geofences := make([]*Geofence, 0, len(list))
+	for _, rawEntry := range list {
+		entry, ok := rawEntry.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%q entries must be objects", geofencesKey)
+		}
+		var conf GeofenceConfig
+		conf.Name, _ = entry["name"].(string)                       //nolint:errcheck
+		fenceType, _ := entry["type"].(string)                      //nolint:errcheck
+		conf.GeoJSON, _ = entry["geojson"].(map[string]interface{}) //nolint:errcheck
+		conf.Type = GeofenceType(fenceType)
+		geofence, err := NewGeofence(&conf)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid geofence in %q", geofencesKey)
+		}
+		geofences = append(geofences, geofence)
+	}
+	return geofences, nil
+}
+
+// The motion protos have no speed limits either, so the speed limits of MoveOnGlobe requests are carried in their extra
+// under speedLimitsKey, encoded as their configs.
+const speedLimitsKey = "speed_limits"
+
+// speedLimitsToExtra returns a copy of `extra` carrying `limits`.
+func speedLimitsToExtra(limits []*SpeedLimit, extra map[string]interface{}) map[string]interface{} {
+	if len(limits) == 0 {
+		return extra
+	}
+	withLimits := make(map[string]interface{}, len(extra)+1)
+	for k, v := range extra {
+		withLimits[k] = v
+	}
+	encoded := make([]interface{}, 0, len(limits))
+	for _, limit := range limits {
+		conf := limit.Config()
+		encoded = append(encoded, map[string]interface{}{
+			"name":                     conf.Name,
+			"geojson":                  conf.GeoJSON,
+			"max_linear_m_per_sec":     conf.MaxLinearMPerSec,
+			"max_angular_degs_per_sec": conf.MaxAngularDegsPerSec,
+		})
+	}
+	withLimits[speedLimitsKey] = encoded
+	return withLimits
+}
+
+// speedLimitsFromExtra removes the speed limits carried in `extra` and returns them.
+func speedLimitsFromExtra(extra map[string]interface{}) ([]*SpeedLimit, error) {
+	raw, ok := extra[speedLimitsKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(extra, speedLimitsKey)
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("%q must be a list", speedLimitsKey)
+	}
+	limits := make([]*SpeedLimit, 0, len(list))
+	for _, rawEntry := range list {
+		entry, ok := rawEntry.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%q entries must be objects", speedLimitsKey)
+		}
+		var conf SpeedLimitConfig
+		conf.Name, _ = entry["name"].(string)                                      //nolint:errcheck
+		conf.GeoJSON, _ = entry["geojson"].(map[string]interface{})                //nolint:errcheck
+		conf.MaxLinearMPerSec, _ = entry["max_linear_m_per_sec"].(float64)         //nolint:errcheck
+		conf.MaxAngularDegsPerSec, _ = entry["max_angular_degs_per_sec"].(float64) //nolint:errcheck
+		limit, err := NewSpeedLimit(&conf)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid speed limit in %q", speedLimitsKey)
+		}
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
+
 // planWithStatusFromProto converts a *pb.PlanWithStatus to a PlanWithStatus.
 func planWithStatusFromProto(pws *pb.PlanWithStatus) (PlanWithStatus, error) {
 	if pws == nil {
@@ -203,7 +467,7 @@ func planStateFromProto(ps pb.PlanState) PlanState {
 
 // toProto converts a MoveOnGlobeRequest to a *pb.MoveOnGlobeRequest.
 func (r MoveOnGlobeReq) toProto(name string) (*pb.MoveOnGlobeRequest, error) {
-	ext, err := vprotoutils.StructToStructPb(r.Extra)
+	ext, err := vprotoutils.StructToStructPb(speedLimitsToExtra(r.SpeedLimits, geofencesToExtra(r.Geofences, r.Extra)))
 	if err != nil {
 		return nil, err
 	}
@@ -279,6 +543,16 @@ func moveOnGlobeRequestFromProto(req *pb.MoveOnGlobeRequest) (MoveOnGlobeReq, er
 		boundingRegionGeometries = append(boundingRegionGeometries, convObst)
 	}
 
+	extra := req.Extra.AsMap()
+	geofences, err := geofencesFromExtra(extra)
+	if err != nil {
+		return MoveOnGlobeReq{}, err
+	}
+	speedLimits, err := speedLimitsFromExtra(extra)
+	if err != nil {
+		return MoveOnGlobeReq{}, err
+	}
+
 	protoComponentName := req.GetComponentName()
 	if protoComponentName == nil {
 		return MoveOnGlobeReq{}, errors.New("received nil *commonpb.ResourceName")
@@ -300,7 +574,9 @@ func moveOnGlobeRequestFromProto(req *pb.MoveOnGlobeRequest) (MoveOnGlobeReq, er
 		Obstacles:          obstacles,
 		MotionCfg:          motionCfg,
 		BoundingRegions:    boundingRegionGeometries,
-		Extra:              req.Extra.AsMap(),
+		Geofences:          geofences,
+		SpeedLimits:        speedLimits,
+		Extra:              extra,
 	}, nil
 }
 
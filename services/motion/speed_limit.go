@@ -0,0 +1,116 @@
+package motion
+
+import (
+	"encoding/json"
+	"math"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// SpeedLimitConfig describes a speed limit zone, such as one slowing a base near doorways. Its zone is given by the
+// polygons of a GeoJSON object. Unset limits do not apply.
+type SpeedLimitConfig struct {
+	Name                 string                 `json:"name"`
+	GeoJSON              map[string]interface{} `json:"geojson"`
+	MaxLinearMPerSec     float64                `json:"max_linear_m_per_sec,omitempty"`
+	MaxAngularDegsPerSec float64                `json:"max_angular_degs_per_sec,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *SpeedLimitConfig) Validate(path string) error {
+	if _, err := NewSpeedLimit(conf); err != nil {
+		return resource.NewConfigValidationError(path, err)
+	}
+	return nil
+}
+
+// SpeedLimit is a zone of GPS coordinates in which components moved with MoveOnGlobe are commanded no faster than its
+// limits.
+type SpeedLimit struct {
+	conf     SpeedLimitConfig
+	polygons []*spatialmath.GeoPolygon
+}
+
+// NewSpeedLimit returns the speed limit described by `conf`.
+func NewSpeedLimit(conf *SpeedLimitConfig) (*SpeedLimit, error) {
+	if conf.Name == "" {
+		return nil, errors.New("speed limits need a name")
+	}
+	if conf.MaxLinearMPerSec < 0 || conf.MaxAngularDegsPerSec < 0 {
+		return nil, errors.Errorf("speed limit %q cannot be negative", conf.Name)
+	}
+	if conf.MaxLinearMPerSec == 0 && conf.MaxAngularDegsPerSec == 0 {
+		return nil, errors.Errorf("speed limit %q needs a max_linear_m_per_sec or max_angular_degs_per_sec", conf.Name)
+	}
+	data, err := json.Marshal(conf.GeoJSON)
+	if err != nil {
+		return nil, err
+	}
+	polygons, err := spatialmath.GeoPolygonsFromGeoJSON(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "speed limit %q", conf.Name)
+	}
+	// keep the GeoJSON as plain JSON values so that it can be carried in protobuf structs
+	normalized := *conf
+	normalized.GeoJSON = nil
+	if err := json.Unmarshal(data, &normalized.GeoJSON); err != nil {
+		return nil, err
+	}
+	return &SpeedLimit{conf: normalized, polygons: polygons}, nil
+}
+
+// NewSpeedLimits returns the speed limits described by `confs`.
+func NewSpeedLimits(confs []*SpeedLimitConfig) ([]*SpeedLimit, error) {
+	limits := make([]*SpeedLimit, 0, len(confs))
+	for _, conf := range confs {
+		limit, err := NewSpeedLimit(conf)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
+
+// Name returns the name of the speed limit.
+func (s *SpeedLimit) Name() string {
+	return s.conf.Name
+}
+
+// Config returns the config describing the speed limit.
+func (s *SpeedLimit) Config() *SpeedLimitConfig {
+	conf := s.conf
+	return &conf
+}
+
+// Contains returns whether the point is inside the speed limit's zone.
+func (s *SpeedLimit) Contains(pt *geo.Point) bool {
+	for _, polygon := range s.polygons {
+		if polygon.Contains(pt) {
+			return true
+		}
+	}
+	return false
+}
+
+// SpeedLimitsAt returns the lowest linear and angular limits of the speed limits whose zones contain `pt`. Limits that
+// do not apply are +Inf.
+func SpeedLimitsAt(limits []*SpeedLimit, pt *geo.Point) (linearMPerSec, angularDegsPerSec float64) {
+	linearMPerSec, angularDegsPerSec = math.Inf(1), math.Inf(1)
+	for _, limit := range limits {
+		if !limit.Contains(pt) {
+			continue
+		}
+		if limit.conf.MaxLinearMPerSec > 0 {
+			linearMPerSec = math.Min(linearMPerSec, limit.conf.MaxLinearMPerSec)
+		}
+		if limit.conf.MaxAngularDegsPerSec > 0 {
+			angularDegsPerSec = math.Min(angularDegsPerSec, limit.conf.MaxAngularDegsPerSec)
+		}
+	}
+	return linearMPerSec, angularDegsPerSec
+}
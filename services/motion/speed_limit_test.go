@@ -0,0 +1,97 @@
+package motion
+
+import (
+	"math"
+	"testing"
+
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+)
+
+func squareSpeedLimit(t *testing.T, name string, linear, angular, minLat, minLng, maxLat, maxLng float64) *SpeedLimit {
+	t.Helper()
+	limit, err := NewSpeedLimit(&SpeedLimitConfig{
+		Name: name,
+		GeoJSON: map[string]interface{}{
+			"type": "Polygon",
+			"coordinates": [][][]float64{{
+				{minLng, minLat}, {maxLng, minLat}, {maxLng, maxLat}, {minLng, maxLat}, {minLng, minLat},
+			}},
+		},
+		MaxLinearMPerSec:     linear,
+		MaxAngularDegsPerSec: angular,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	return limit
+}
+
+func TestNewSpeedLimit(t *testing.T) {
+	polygon := map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{{{0, 0}, {1, 0}, {1, 1}}}}
+	_, err := NewSpeedLimit(&SpeedLimitConfig{GeoJSON: polygon, MaxLinearMPerSec: 1})
+	test.That(t, err, test.ShouldBeError, "speed limits need a name")
+	_, err = NewSpeedLimit(&SpeedLimitConfig{Name: "door", GeoJSON: polygon})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewSpeedLimit(&SpeedLimitConfig{Name: "door", GeoJSON: polygon, MaxLinearMPerSec: -1})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewSpeedLimit(&SpeedLimitConfig{Name: "door", MaxLinearMPerSec: 1})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	conf := &SpeedLimitConfig{Name: "door", GeoJSON: polygon, MaxLinearMPerSec: 0.2}
+	test.That(t, conf.Validate("path"), test.ShouldBeNil)
+	limit, err := NewSpeedLimit(conf)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, limit.Name(), test.ShouldEqual, "door")
+	test.That(t, limit.Contains(geo.NewPoint(0.2, 0.5)), test.ShouldBeTrue)
+	test.That(t, limit.Contains(geo.NewPoint(0.5, 0.2)), test.ShouldBeFalse)
+	test.That(t, limit.Config().GeoJSON["coordinates"], test.ShouldHaveSameTypeAs, []interface{}{})
+}
+
+func TestSpeedLimitsAt(t *testing.T) {
+	yard := squareSpeedLimit(t, "yard", 1, 0, 0, 0, 10, 10)
+	door := squareSpeedLimit(t, "door", 0.2, 30, 4, 4, 6, 6)
+	limits := []*SpeedLimit{yard, door}
+
+	linear, angular := SpeedLimitsAt(limits, geo.NewPoint(20, 20))
+	test.That(t, math.IsInf(linear, 1), test.ShouldBeTrue)
+	test.That(t, math.IsInf(angular, 1), test.ShouldBeTrue)
+
+	linear, angular = SpeedLimitsAt(limits, geo.NewPoint(1, 1))
+	test.That(t, linear, test.ShouldEqual, 1)
+	test.That(t, math.IsInf(angular, 1), test.ShouldBeTrue)
+
+	// the lowest limits apply where zones overlap
+	linear, angular = SpeedLimitsAt(limits, geo.NewPoint(5, 5))
+	test.That(t, linear, test.ShouldEqual, 0.2)
+	test.That(t, angular, test.ShouldEqual, 30)
+}
+
+func TestMoveOnGlobeReqSpeedLimits(t *testing.T) {
+	extra := map[string]interface{}{"smooth_iter": 10.}
+	req := MoveOnGlobeReq{
+		ComponentName:      base.Named("base"),
+		Destination:        geo.NewPoint(1, 2),
+		MovementSensorName: movementsensor.Named("gps"),
+		Geofences:          []*Geofence{squareGeofence(t, "yard", GeofenceKeepIn, 0, 0, 10, 10)},
+		SpeedLimits:        []*SpeedLimit{squareSpeedLimit(t, "door", 0.2, 30, 4, 4, 6, 6)},
+		Extra:              extra,
+	}
+
+	pbReq, err := req.toProto("motion")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, extra, test.ShouldResemble, map[string]interface{}{"smooth_iter": 10.})
+
+	roundTrip, err := moveOnGlobeRequestFromProto(pbReq)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, roundTrip.Extra, test.ShouldResemble, extra)
+	test.That(t, roundTrip.Geofences, test.ShouldHaveLength, 1)
+	test.That(t, roundTrip.SpeedLimits, test.ShouldHaveLength, 1)
+	test.That(t, roundTrip.SpeedLimits[0].Config(), test.ShouldResemble, req.SpeedLimits[0].Config())
+
+	pbReq.Extra.Fields[speedLimitsKey] = structpb.NewStringValue("there")
+	_, err = moveOnGlobeRequestFromProto(pbReq)
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,78 @@
+package motion
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+// DefaultTrackingCheckIntervalMs is how often the positions of moving components are compared to their plan when
+// execution monitoring does not set an interval.
+const DefaultTrackingCheckIntervalMs = 50
+
+// ErrTrackingErrorExceeded is wrapped by the errors of moves aborted because a component stopped following its plan.
+// Since the errors of moves reach clients as their error messages, its message can also be used to recognize them.
+var ErrTrackingErrorExceeded = errors.New("tracking error exceeded")
+
+// TrackingError is the error of a move aborted because the reported inputs of a component, such as the joint
+// positions of an arm, were further from its plan than allowed.
+type TrackingError struct {
+	// Component is the name of the component which did not follow its plan.
+	Component string
+	// Inputs are the reported inputs of the component.
+	Inputs []referenceframe.Input
+	// Distance is how far the inputs were from the plan, in the units of the inputs.
+	Distance float64
+	// Limit is the tracking error allowed for the component.
+	Limit float64
+}
+
+func (e *TrackingError) Error() string {
+	return fmt.Sprintf("%s: %s is %.4g from its plan, more than its limit of %.4g", ErrTrackingErrorExceeded, e.Component, e.Distance, e.Limit)
+}
+
+// Unwrap returns ErrTrackingErrorExceeded, so that tracking errors can be recognized with errors.Is.
+func (e *TrackingError) Unwrap() error {
+	return ErrTrackingErrorExceeded
+}
+
+// ExecutionMonitoringConfig configures how the motion service checks that components follow their plans while
+// executing them. The tracking error of a component is the L2 distance between its reported inputs and the path
+// through its planned inputs, in the units of the inputs: radians for revolute joints and millimeters for prismatic
+// ones. Moves whose components exceed their limit are stopped with a TrackingError.
+type ExecutionMonitoringConfig struct {
+	// MaxTrackingError is the tracking error allowed for components without a limit of their own. Components are not
+	// monitored when it is zero.
+	MaxTrackingError float64 `json:"max_tracking_error,omitempty"`
+	// ComponentMaxTrackingError is the tracking error allowed for each named component.
+	ComponentMaxTrackingError map[string]float64 `json:"component_max_tracking_error,omitempty"`
+	// CheckIntervalMs is how often the positions of moving components are checked. Defaults to
+	// DefaultTrackingCheckIntervalMs.
+	CheckIntervalMs int `json:"check_interval_ms,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *ExecutionMonitoringConfig) Validate() error {
+	if conf.MaxTrackingError < 0 {
+		return errors.New("max_tracking_error can't be negative")
+	}
+	for name, limit := range conf.ComponentMaxTrackingError {
+		if limit <= 0 {
+			return errors.Errorf("component_max_tracking_error of %q must be positive", name)
+		}
+	}
+	if conf.CheckIntervalMs < 0 {
+		return errors.New("check_interval_ms can't be negative")
+	}
+	return nil
+}
+
+// Limit returns the tracking error allowed for the named component, or 0 if it is not monitored.
+func (conf *ExecutionMonitoringConfig) Limit(component string) float64 {
+	if limit, ok := conf.ComponentMaxTrackingError[component]; ok {
+		return limit
+	}
+	return conf.MaxTrackingError
+}
@@ -5,8 +5,10 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"os"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -45,6 +47,8 @@ var (
 	errNegativeObstaclePollingFrequencyHz = errors.New("obstacle_polling_frequency_hz must be non-negative if set")
 	errNegativePlanDeviationM             = errors.New("plan_deviation_m must be non-negative if set")
 	errNegativeReplanCostFactor           = errors.New("replan_cost_factor must be non-negative if set")
+	errNegativeObstacleClearanceMM        = errors.New("obstacle_clearance_mm must be non-negative if set")
+	errNegativeMaxReplans                 = errors.New("max_replans must be non-negative if set")
 	errObstacleGeomWithTranslation        = errors.New("obstacle " + geomWithTranslation)
 	errBoundingRegionsGeomWithTranslation = errors.New("bounding region " + geomWithTranslation)
 	errObstacleGeomParse                  = errors.New("obstacle unable to be converted from geometry config")
@@ -75,6 +79,9 @@ const (
 
 	// frequency in milliseconds.
 	planHistoryPollFrequency = time.Millisecond * 50
+
+	// the number of points of the traveled path that are kept, beyond which the oldest are dropped.
+	maxTraveledPathPoints = 10000
 )
 
 func init() {
@@ -109,6 +116,27 @@ type Config struct {
 	PlanDeviationM             float64                          `json:"plan_deviation_m,omitempty"`
 	ReplanCostFactor           float64                          `json:"replan_cost_factor,omitempty"`
 	LogFilePath                string                           `json:"log_file_path"`
+
+	// ObstacleClearanceMM is how far routes replanned around obstacles found by the obstacle detectors keep from them.
+	ObstacleClearanceMM float64 `json:"obstacle_clearance_mm,omitempty"`
+	// MaxReplans is how many times the route to a waypoint may be replanned, such as around newly detected obstacles,
+	// before navigating to the waypoint fails and is retried. It is unlimited if unset.
+	MaxReplans *int `json:"max_replans,omitempty"`
+
+	// Geofences are keep-out and keep-in zones that waypoints and the routes to them may not violate.
+	Geofences []*motion.GeofenceConfig `json:"geofences,omitempty"`
+
+	// SpeedLimits are zones, such as around doorways, in which the base is slowed down when navigating to waypoints.
+	SpeedLimits []*motion.SpeedLimitConfig `json:"speed_limits,omitempty"`
+
+	// WaypointsFile is a GPX or GeoJSON file whose waypoints are added when the service is first
+	// configured with it, and again whenever it is changed to another file.
+	WaypointsFile string `json:"waypoints_file,omitempty"`
+
+	// Docks are where the base can return to, such as to recharge.
+	Docks []*DockConfig `json:"docks,omitempty"`
+	// DockOnLowBattery makes the base return to a dock when its battery runs low.
+	DockOnLowBattery *BatteryDockingConfig `json:"dock_on_low_battery,omitempty"`
 }
 
 type executionWaypoint struct {
@@ -181,6 +209,12 @@ func (conf *Config) Validate(path string) ([]string, error) {
 	if conf.ReplanCostFactor < 0 {
 		return nil, errNegativeReplanCostFactor
 	}
+	if conf.ObstacleClearanceMM < 0 {
+		return nil, errNegativeObstacleClearanceMM
+	}
+	if conf.MaxReplans != nil && *conf.MaxReplans < 0 {
+		return nil, errNegativeMaxReplans
+	}
 
 	// Ensure obstacles have no translation
 	for _, obs := range conf.Obstacles {
@@ -200,6 +234,30 @@ func (conf *Config) Validate(path string) ([]string, error) {
 		}
 	}
 
+	for i, geofence := range conf.Geofences {
+		if err := geofence.Validate(fmt.Sprintf("%s.geofences.%d", path, i)); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, limit := range conf.SpeedLimits {
+		if err := limit.Validate(fmt.Sprintf("%s.speed_limits.%d", path, i)); err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.WaypointsFile != "" {
+		if _, err := navigation.WaypointFormatFromPath(conf.WaypointsFile); err != nil {
+			return nil, resource.NewConfigValidationError(path, err)
+		}
+	}
+
+	dockingDeps, err := conf.validateDocking(path)
+	if err != nil {
+		return nil, err
+	}
+	deps = append(deps, dockingDeps...)
+
 	// add framesystem service as dependency to be used by builtin and explore motion service
 	deps = append(deps, framesystem.InternalServiceName.String())
 
@@ -238,18 +296,35 @@ type builtIn struct {
 	motionService        motion.Service
 	obstacles            []*spatialmath.GeoGeometry
 	boundingRegions      []*spatialmath.GeoGeometry
-
-	motionCfg        *motion.MotionConfiguration
-	replanCostFactor float64
+	geofences            []*motion.Geofence
+	speedLimits          []*motion.SpeedLimit
+	// geofenceViolations counts the waypoints and routes to waypoints rejected for violating a geofence
+	geofenceViolations atomic.Int64
+	// waypointsFile is the configured file whose waypoints were last imported
+	waypointsFile string
+	// traveledPath holds where the base has been while in waypoint mode, oldest first
+	traveledPath []navigation.TraveledPoint
+	docks        []*dock
+	// dockings and dockingFailures count the attempts to dock that succeeded and failed
+	dockings        atomic.Int64
+	dockingFailures atomic.Int64
+
+	motionCfg           *motion.MotionConfiguration
+	replanCostFactor    float64
+	obstacleClearanceMM float64
+	maxReplans          *int
 
 	logger                    logging.Logger
 	wholeServiceCancelFunc    func()
 	currentWaypointCancelFunc func()
 	waypointInProgress        *navigation.Waypoint
 	activeBackgroundWorkers   sync.WaitGroup
+	batteryMonitorCancelFunc  func()
+	batteryMonitorWorkers     sync.WaitGroup
 }
 
 func (svc *builtIn) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	svc.stopBatteryMonitor()
 	svc.actionMu.Lock()
 	defer svc.actionMu.Unlock()
 
@@ -387,13 +462,37 @@ func (svc *builtIn) Reconfigure(ctx context.Context, deps resource.Dependencies,
 		return errors.Wrap(errBoundingRegionsGeomParse, err.Error())
 	}
 
+	newGeofences, err := motion.NewGeofences(svcConfig.Geofences)
+	if err != nil {
+		return err
+	}
+	newSpeedLimits, err := motion.NewSpeedLimits(svcConfig.SpeedLimits)
+	if err != nil {
+		return err
+	}
+	newDocks, batteryDocking, err := newDocks(deps, svcConfig)
+	if err != nil {
+		return err
+	}
+
 	svc.mode = navigation.ModeManual
 	svc.base = baseComponent
 	svc.mapType = mapType
 	svc.motionService = motionSvc
 	svc.obstacles = newObstacles
 	svc.boundingRegions = newBoundingRegions
+	svc.geofences = newGeofences
+	svc.speedLimits = newSpeedLimits
+	svc.docks = newDocks
+	if svcConfig.WaypointsFile != "" && svcConfig.WaypointsFile != svc.waypointsFile {
+		if _, err := svc.importWaypointsFile(ctx, svcConfig.WaypointsFile); err != nil {
+			return err
+		}
+	}
+	svc.waypointsFile = svcConfig.WaypointsFile
 	svc.replanCostFactor = replanCostFactor
+	svc.obstacleClearanceMM = svcConfig.ObstacleClearanceMM
+	svc.maxReplans = svcConfig.MaxReplans
 	svc.visionServicesByName = visionServicesByName
 	svc.motionCfg = &motion.MotionConfiguration{
 		ObstacleDetectors:     obstacleDetectorNamePairs,
@@ -404,6 +503,15 @@ func (svc *builtIn) Reconfigure(ctx context.Context, deps resource.Dependencies,
 		ObstaclePollingFreqHz: &obstaclePollingFrequencyHz,
 	}
 
+	if batteryDocking != nil {
+		monitorCtx, cancelFunc := context.WithCancel(context.Background())
+		svc.batteryMonitorCancelFunc = cancelFunc
+		svc.batteryMonitorWorkers.Add(1)
+		utils.ManagedGo(func() {
+			svc.monitorBattery(monitorCtx, batteryDocking)
+		}, svc.batteryMonitorWorkers.Done)
+	}
+
 	return nil
 }
 
@@ -502,10 +610,124 @@ func (svc *builtIn) Waypoints(ctx context.Context, extra map[string]interface{})
 
 func (svc *builtIn) AddWaypoint(ctx context.Context, point *geo.Point, extra map[string]interface{}) error {
 	svc.logger.CInfof(ctx, "AddWaypoint called with %#v", *point)
+	svc.mu.RLock()
+	geofences := svc.geofences
+	svc.mu.RUnlock()
+	if err := motion.CheckGeofences(geofences, point); err != nil {
+		svc.geofenceViolations.Add(1)
+		return errors.Wrap(err, "waypoint")
+	}
 	_, err := svc.store.AddWaypoint(ctx, point)
 	return err
 }
 
+// ImportWaypoints adds the waypoints of a GPX or GeoJSON file, in order. None are added if any of
+// them violate a geofence.
+func (svc *builtIn) ImportWaypoints(
+	ctx context.Context,
+	data []byte,
+	format navigation.WaypointFormat,
+	extra map[string]interface{},
+) (int, error) {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	return svc.importWaypoints(ctx, data, format)
+}
+
+// importWaypointsFile imports the waypoints of the GPX or GeoJSON file at `path`. svc.mu must be held.
+func (svc *builtIn) importWaypointsFile(ctx context.Context, path string) (int, error) {
+	format, err := navigation.WaypointFormatFromPath(path)
+	if err != nil {
+		return 0, err
+	}
+	//nolint:gosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	count, err := svc.importWaypoints(ctx, data, format)
+	if err != nil {
+		return 0, errors.Wrapf(err, "cannot import waypoints from %q", path)
+	}
+	svc.logger.CInfof(ctx, "imported %d waypoints from %q", count, path)
+	return count, nil
+}
+
+// importWaypoints adds the waypoints of `data`. svc.mu must be held.
+func (svc *builtIn) importWaypoints(ctx context.Context, data []byte, format navigation.WaypointFormat) (int, error) {
+	points, err := navigation.ParseWaypoints(data, format)
+	if err != nil {
+		return 0, err
+	}
+	for i, point := range points {
+		if err := motion.CheckGeofences(svc.geofences, point); err != nil {
+			svc.geofenceViolations.Add(1)
+			return 0, errors.Wrapf(err, "waypoint %d", i)
+		}
+	}
+	for _, point := range points {
+		if _, err := svc.store.AddWaypoint(ctx, point); err != nil {
+			return 0, err
+		}
+	}
+	return len(points), nil
+}
+
+// ExportTraveledPath returns the path the base traveled while in waypoint mode as a GPX or GeoJSON file.
+func (svc *builtIn) ExportTraveledPath(
+	ctx context.Context,
+	format navigation.WaypointFormat,
+	extra map[string]interface{},
+) ([]byte, error) {
+	svc.mu.RLock()
+	path := slices.Clone(svc.traveledPath)
+	svc.mu.RUnlock()
+	return navigation.EncodeTraveledPath(path, format)
+}
+
+// recordTraveledPath adds the location of the base to the traveled path at the position polling
+// frequency, until `ctx` is done.
+func (svc *builtIn) recordTraveledPath(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		point, _, err := svc.movementSensor.Position(ctx, nil)
+		if err != nil {
+			svc.logger.CDebugf(ctx, "cannot record traveled path: %s", err)
+			continue
+		}
+		svc.mu.Lock()
+		if n := len(svc.traveledPath); n == 0 || *svc.traveledPath[n-1].Point != *point {
+			svc.traveledPath = append(svc.traveledPath, navigation.TraveledPoint{Point: point, Time: time.Now()})
+			if len(svc.traveledPath) > maxTraveledPathPoints {
+				svc.traveledPath = slices.Delete(svc.traveledPath, 0, len(svc.traveledPath)-maxTraveledPathPoints)
+			}
+		}
+		svc.mu.Unlock()
+	}
+}
+
+// navigationStats are the statistics of the navigation service recorded by FTDC.
+type navigationStats struct {
+	GeofenceViolations int64
+	Dockings           int64
+	DockingFailures    int64
+}
+
+// Stats returns the statistics of the navigation service, which are recorded by FTDC.
+func (svc *builtIn) Stats() any {
+	return navigationStats{
+		GeofenceViolations: svc.geofenceViolations.Load(),
+		Dockings:           svc.dockings.Load(),
+		DockingFailures:    svc.dockingFailures.Load(),
+	}
+}
+
 func (svc *builtIn) RemoveWaypoint(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
 	svc.mu.Lock()
 	defer svc.mu.Unlock()
@@ -535,6 +757,7 @@ func (svc *builtIn) waypointReached(ctx context.Context) error {
 }
 
 func (svc *builtIn) Close(ctx context.Context) error {
+	svc.stopBatteryMonitor()
 	svc.actionMu.Lock()
 	defer svc.actionMu.Unlock()
 
@@ -551,6 +774,8 @@ func (svc *builtIn) moveToWaypoint(ctx context.Context, wp navigation.Waypoint,
 		Obstacles:          svc.obstacles,
 		MotionCfg:          svc.motionCfg,
 		BoundingRegions:    svc.boundingRegions,
+		Geofences:          svc.geofences,
+		SpeedLimits:        svc.speedLimits,
 		Extra:              extra,
 	}
 	cancelCtx, cancelFn := context.WithCancel(ctx)
@@ -605,6 +830,25 @@ func (svc *builtIn) startWaypointMode(ctx context.Context, extra map[string]inte
 	}
 
 	extra["motion_profile"] = "position_only"
+	// routes are replanned around obstacles the obstacle detectors find while traversing them
+	if _, ok := extra["obstacle_clearance_mm"]; !ok && svc.obstacleClearanceMM > 0 {
+		extra["obstacle_clearance_mm"] = svc.obstacleClearanceMM
+	}
+	if _, ok := extra["max_replans"]; !ok && svc.maxReplans != nil {
+		extra["max_replans"] = *svc.maxReplans
+	}
+
+	// the traveled path is recorded for as long as the service is in waypoint mode
+	if svc.movementSensor != nil {
+		period := time.Second
+		if hz := svc.motionCfg.PositionPollingFreqHz; hz != nil && *hz > 0 {
+			period = time.Duration(float64(time.Second) / *hz)
+		}
+		svc.activeBackgroundWorkers.Add(1)
+		utils.ManagedGo(func() {
+			svc.recordTraveledPath(ctx, period)
+		}, svc.activeBackgroundWorkers.Done)
+	}
 
 	svc.activeBackgroundWorkers.Add(1)
 	utils.ManagedGo(func() {
@@ -627,6 +871,10 @@ func (svc *builtIn) startWaypointMode(ctx context.Context, extra map[string]inte
 
 			svc.logger.CInfof(ctx, "navigating to waypoint: %+v", wp)
 			if err := svc.moveToWaypoint(cancelCtx, wp, extra); err != nil {
+				// the errors of moves reach us as plan status reasons, so violations are recognized by their message
+				if strings.Contains(err.Error(), motion.ErrGeofenceViolation.Error()) {
+					svc.geofenceViolations.Add(1)
+				}
 				if svc.waypointIsDeleted() {
 					svc.logger.CInfof(ctx, "skipping waypoint %+v since it was deleted", wp)
 					continue
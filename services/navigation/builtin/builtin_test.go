@@ -3,7 +3,11 @@ package builtin
 import (
 	"context"
 	"errors"
+	"fmt"
+	"image"
 	"math"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -15,6 +19,7 @@ import (
 	"go.uber.org/atomic"
 	"go.viam.com/test"
 	"go.viam.com/utils"
+	"go.viam.com/utils/testutils"
 
 	"go.viam.com/rdk/components/base"
 	baseFake "go.viam.com/rdk/components/base/fake"
@@ -22,12 +27,14 @@ import (
 	_ "go.viam.com/rdk/components/camera/fake"
 	"go.viam.com/rdk/components/movementsensor"
 	_ "go.viam.com/rdk/components/movementsensor/fake"
+	"go.viam.com/rdk/components/powersensor"
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/motionplan"
 	"go.viam.com/rdk/pointcloud"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage/transform"
 	"go.viam.com/rdk/robot/framesystem"
 	robotimpl "go.viam.com/rdk/robot/impl"
 	"go.viam.com/rdk/services/motion"
@@ -37,7 +44,9 @@ import (
 	_ "go.viam.com/rdk/services/vision/colordetector"
 	"go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/testutils/inject"
+	rdkutils "go.viam.com/rdk/utils"
 	viz "go.viam.com/rdk/vision"
+	"go.viam.com/rdk/vision/objectdetection"
 )
 
 type startWaypointState struct {
@@ -45,6 +54,8 @@ type startWaypointState struct {
 	injectMS       *inject.MotionService
 	base           base.Base
 	movementSensor *inject.MovementSensor
+	deps           resource.Dependencies
+	config         resource.Config
 	closeFunc      func()
 	sync.RWMutex
 	pws   []motion.PlanWithStatus
@@ -69,6 +80,7 @@ func setupNavigationServiceFromConfig(t *testing.T, configFilename string) (navi
 }
 
 func TestValidateConfig(t *testing.T) {
+	negativeReplans := -1
 	path := ""
 
 	cases := []struct {
@@ -180,6 +192,40 @@ func TestValidateConfig(t *testing.T) {
 			numDeps:     0,
 			expectedErr: errNegativeReplanCostFactor,
 		},
+		{
+			description: "invalid config negative obstacle_clearance_mm",
+			cfg: Config{
+				BaseName:            "base",
+				MovementSensorName:  "localizer",
+				ObstacleClearanceMM: -1,
+			},
+			numDeps:     0,
+			expectedErr: errNegativeObstacleClearanceMM,
+		},
+		{
+			description: "invalid config negative max_replans",
+			cfg: Config{
+				BaseName:           "base",
+				MovementSensorName: "localizer",
+				MaxReplans:         &negativeReplans,
+			},
+			numDeps:     0,
+			expectedErr: errNegativeMaxReplans,
+		},
+		{
+			description: "invalid geofence type",
+			cfg: Config{
+				BaseName:           "base",
+				MovementSensorName: "localizer",
+				Geofences: []*motion.GeofenceConfig{{
+					Name:    "pond",
+					Type:    "keep_away",
+					GeoJSON: map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{{{0, 0}, {1, 0}, {1, 1}}}},
+				}},
+			},
+			numDeps:     0,
+			expectedErr: errors.New(`geofence "pond" type must be`),
+		},
 	}
 
 	for _, tt := range cases {
@@ -648,6 +694,8 @@ func setupStartWaypoint(ctx context.Context, t *testing.T, logger logging.Logger
 		injectMS:       injectMS,
 		base:           fakeBase,
 		movementSensor: injectMovementSensor,
+		deps:           deps,
+		config:         config,
 		closeFunc:      func() { test.That(t, ns.Close(context.Background()), test.ShouldBeNil) },
 	}
 }
@@ -847,6 +895,456 @@ func TestPaths(t *testing.T) {
 	})
 }
 
+func TestObstacleReplanning(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	s := setupStartWaypoint(ctx, t, logger)
+	defer s.closeFunc()
+
+	// the clearance and replan budget are passed to motion, which replans around the obstacles that the obstacle
+	// detectors find
+	maxReplans := 5
+	svc, ok := s.ns.(*builtIn)
+	test.That(t, ok, test.ShouldBeTrue)
+	svc.obstacleClearanceMM = 250
+	svc.maxReplans = &maxReplans
+
+	reqs := make(chan motion.MoveOnGlobeReq, 1)
+	s.injectMS.MoveOnGlobeFunc = func(ctx context.Context, req motion.MoveOnGlobeReq) (motion.ExecutionID, error) {
+		select {
+		case reqs <- req:
+		default:
+		}
+		return uuid.Nil, errors.New("no route")
+	}
+	s.injectMS.StopPlanFunc = func(ctx context.Context, req motion.StopPlanReq) error {
+		return nil
+	}
+
+	test.That(t, s.ns.AddWaypoint(ctx, geo.NewPoint(1, 0), nil), test.ShouldBeNil)
+	test.That(t, s.ns.SetMode(ctx, navigation.ModeWaypoint, nil), test.ShouldBeNil)
+	select {
+	case req := <-reqs:
+		test.That(t, req.Extra["obstacle_clearance_mm"], test.ShouldEqual, 250.)
+		test.That(t, req.Extra["max_replans"], test.ShouldEqual, 5)
+		test.That(t, len(req.MotionCfg.ObstacleDetectors), test.ShouldEqual, 1)
+	case <-time.After(5 * time.Second):
+		t.Fatal("MoveOnGlobe was not called")
+	}
+	test.That(t, s.ns.SetMode(ctx, navigation.ModeManual, nil), test.ShouldBeNil)
+}
+
+func TestGeofences(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	s := setupStartWaypoint(ctx, t, logger)
+	defer s.closeFunc()
+
+	svc, ok := s.ns.(*builtIn)
+	test.That(t, ok, test.ShouldBeTrue)
+	geofences, err := motion.NewGeofences([]*motion.GeofenceConfig{{
+		Name: "pond",
+		Type: motion.GeofenceKeepOut,
+		GeoJSON: map[string]interface{}{
+			"type":        "Polygon",
+			"coordinates": [][][]float64{{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}, {-1, -1}}},
+		},
+	}})
+	test.That(t, err, test.ShouldBeNil)
+	svc.geofences = geofences
+	geofenceViolations := func() int64 {
+		stats, ok := svc.Stats().(navigationStats)
+		test.That(t, ok, test.ShouldBeTrue)
+		return stats.GeofenceViolations
+	}
+
+	// waypoints inside of keep-out geofences are rejected
+	err = s.ns.AddWaypoint(ctx, geo.NewPoint(0, 0), nil)
+	test.That(t, errors.Is(err, motion.ErrGeofenceViolation), test.ShouldBeTrue)
+	test.That(t, geofenceViolations(), test.ShouldEqual, 1)
+	wps, err := s.ns.Waypoints(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, wps, test.ShouldBeEmpty)
+
+	// routes to the other waypoints are checked by motion, whose violations are counted too
+	reqs := make(chan motion.MoveOnGlobeReq, 1)
+	s.injectMS.MoveOnGlobeFunc = func(ctx context.Context, req motion.MoveOnGlobeReq) (motion.ExecutionID, error) {
+		select {
+		case reqs <- req:
+		default:
+		}
+		return uuid.Nil, fmt.Errorf(`plan: path enters keep-out geofence "pond": %w`, motion.ErrGeofenceViolation)
+	}
+	s.injectMS.StopPlanFunc = func(ctx context.Context, req motion.StopPlanReq) error {
+		return nil
+	}
+	test.That(t, s.ns.AddWaypoint(ctx, geo.NewPoint(2, 0), nil), test.ShouldBeNil)
+	test.That(t, s.ns.SetMode(ctx, navigation.ModeWaypoint, nil), test.ShouldBeNil)
+	select {
+	case req := <-reqs:
+		test.That(t, req.Geofences, test.ShouldResemble, geofences)
+	case <-time.After(5 * time.Second):
+		t.Fatal("MoveOnGlobe was not called")
+	}
+	test.That(t, s.ns.SetMode(ctx, navigation.ModeManual, nil), test.ShouldBeNil)
+	test.That(t, geofenceViolations(), test.ShouldBeGreaterThan, 1)
+}
+
+func TestSpeedLimits(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	doorway := &motion.SpeedLimitConfig{
+		Name: "doorway",
+		GeoJSON: map[string]interface{}{
+			"type":        "Polygon",
+			"coordinates": [][][]float64{{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}, {-1, -1}}},
+		},
+		MaxLinearMPerSec: 0.2,
+	}
+
+	cfg := Config{BaseName: "base", MapType: "GPS", MovementSensorName: "localizer"}
+	cfg.SpeedLimits = []*motion.SpeedLimitConfig{doorway, {Name: "hall", GeoJSON: doorway.GeoJSON}}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "path.speed_limits.1")
+	cfg.SpeedLimits = cfg.SpeedLimits[:1]
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	s := setupStartWaypoint(ctx, t, logger)
+	defer s.closeFunc()
+	svc, ok := s.ns.(*builtIn)
+	test.That(t, ok, test.ShouldBeTrue)
+	speedLimits, err := motion.NewSpeedLimits(cfg.SpeedLimits)
+	test.That(t, err, test.ShouldBeNil)
+	svc.speedLimits = speedLimits
+
+	// the speed limits are passed to motion, which enforces them
+	reqs := make(chan motion.MoveOnGlobeReq, 1)
+	s.injectMS.MoveOnGlobeFunc = func(ctx context.Context, req motion.MoveOnGlobeReq) (motion.ExecutionID, error) {
+		select {
+		case reqs <- req:
+		default:
+		}
+		return uuid.Nil, errors.New("no plan")
+	}
+	s.injectMS.StopPlanFunc = func(ctx context.Context, req motion.StopPlanReq) error {
+		return nil
+	}
+	test.That(t, s.ns.AddWaypoint(ctx, geo.NewPoint(2, 0), nil), test.ShouldBeNil)
+	test.That(t, s.ns.SetMode(ctx, navigation.ModeWaypoint, nil), test.ShouldBeNil)
+	select {
+	case req := <-reqs:
+		test.That(t, req.SpeedLimits, test.ShouldResemble, speedLimits)
+	case <-time.After(5 * time.Second):
+		t.Fatal("MoveOnGlobe was not called")
+	}
+	test.That(t, s.ns.SetMode(ctx, navigation.ModeManual, nil), test.ShouldBeNil)
+}
+
+func TestDocking(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	charger := &DockConfig{
+		Name:              "charger",
+		Latitude:          1,
+		Longitude:         1,
+		HeadingDegs:       90,
+		ApproachDistanceM: 2,
+		Alignment:         &DockAlignmentConfig{VisionServiceName: "vision", CameraName: "camera", Label: "marker"},
+	}
+	garage := &DockConfig{Name: "garage", Latitude: 2, Longitude: 2, Approach: DockApproachDirect}
+
+	t.Run("validate", func(t *testing.T) {
+		cfg := Config{BaseName: "base", MapType: "GPS", MovementSensorName: "localizer"}
+		cfg.Docks = []*DockConfig{charger, garage}
+		cfg.DockOnLowBattery = &BatteryDockingConfig{PowerSensorName: "battery", MinVolts: 11.5}
+		deps, err := cfg.Validate("path")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, deps, test.ShouldContain, resource.NewName(vision.API, "vision").String())
+		test.That(t, deps, test.ShouldContain, resource.NewName(camera.API, "camera").String())
+		test.That(t, deps, test.ShouldContain, resource.NewName(powersensor.API, "battery").String())
+
+		cases := []struct {
+			docks   []*DockConfig
+			battery *BatteryDockingConfig
+			err     string
+		}{
+			{[]*DockConfig{{Latitude: 1}}, nil, `Field: "name"`},
+			{[]*DockConfig{{Name: "a", Latitude: 91}}, nil, "not a valid latitude"},
+			{[]*DockConfig{{Name: "a", Approach: "sideways"}}, nil, "unknown dock approach"},
+			{[]*DockConfig{{Name: "a", Approach: DockApproachDirect, Alignment: charger.Alignment}}, nil, "staged approach"},
+			{[]*DockConfig{{Name: "a", Alignment: &DockAlignmentConfig{VisionServiceName: "vision", CameraName: "camera"}}}, nil, `Field: "label"`},
+			{[]*DockConfig{garage, garage}, nil, "more than once"},
+			{nil, &BatteryDockingConfig{PowerSensorName: "battery", MinVolts: 11.5}, "requires a dock"},
+			{[]*DockConfig{garage}, &BatteryDockingConfig{MinVolts: 11.5}, `Field: "power_sensor"`},
+			{[]*DockConfig{garage}, &BatteryDockingConfig{PowerSensorName: "battery"}, "min_volts"},
+			{[]*DockConfig{garage}, &BatteryDockingConfig{PowerSensorName: "battery", MinVolts: 11.5, Dock: "charger"}, `no dock named "charger"`},
+		}
+		for _, tc := range cases {
+			cfg.Docks = tc.docks
+			cfg.DockOnLowBattery = tc.battery
+			_, err := cfg.Validate("path")
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+		}
+
+		cfg.Docks = []*DockConfig{garage}
+		cfg.DockOnLowBattery = nil
+		cfg.MapType = "None"
+		_, err = cfg.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "GPS map type")
+	})
+
+	s := setupStartWaypoint(ctx, t, logger)
+	defer s.closeFunc()
+
+	var mu sync.Mutex
+	var moves []motion.MoveOnGlobeReq
+	var spins []float64
+	var straights []int
+	s.injectMS.MoveOnGlobeFunc = func(ctx context.Context, req motion.MoveOnGlobeReq) (motion.ExecutionID, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		moves = append(moves, req)
+		return uuid.New(), nil
+	}
+	s.injectMS.PlanHistoryFunc = func(ctx context.Context, req motion.PlanHistoryReq) ([]motion.PlanWithStatus, error) {
+		return []motion.PlanWithStatus{{StatusHistory: []motion.PlanStatus{{State: motion.PlanStateSucceeded}}}}, nil
+	}
+	s.injectMS.StopPlanFunc = func(ctx context.Context, req motion.StopPlanReq) error {
+		return nil
+	}
+	injectBase := inject.NewBase("test_base")
+	injectBase.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		spins = append(spins, angleDeg)
+		return nil
+	}
+	injectBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		straights = append(straights, distanceMm)
+		return nil
+	}
+	injectCamera := inject.NewCamera("camera")
+	injectCamera.PropertiesFunc = func(ctx context.Context) (camera.Properties, error) {
+		return camera.Properties{
+			IntrinsicParams: &transform.PinholeCameraIntrinsics{Width: 640, Height: 480, Fx: 500, Fy: 500, Ppx: 320, Ppy: 240},
+		}, nil
+	}
+	// the marker is first 10 degrees to the right of the base, and centered once the base has turned towards it
+	injectVision := inject.NewVisionService("vision")
+	injectVision.DetectionsFromCameraFunc = func(
+		ctx context.Context, cameraName string, extra map[string]interface{},
+	) ([]objectdetection.Detection, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		centerX := 320 + int(math.Round(500*math.Tan(rdkutils.DegToRad(10))))
+		if len(spins) > 0 {
+			centerX = 320
+		}
+		return []objectdetection.Detection{
+			objectdetection.NewDetectionWithoutImgBounds(image.Rect(0, 0, 10, 10), 0.9, "other"),
+			objectdetection.NewDetectionWithoutImgBounds(image.Rect(centerX-20, 200, centerX+20, 240), 0.8, "marker"),
+		}, nil
+	}
+	injectBattery := inject.NewPowerSensor("battery")
+	volts := atomic.NewFloat64(12)
+	injectBattery.VoltageFunc = func(ctx context.Context, extra map[string]interface{}) (float64, bool, error) {
+		return volts.Load(), false, nil
+	}
+
+	deps := resource.Dependencies{}
+	for name, dep := range s.deps {
+		deps[name] = dep
+	}
+	deps[injectBase.Name()] = injectBase
+	deps[injectCamera.Name()] = injectCamera
+	deps[injectVision.Name()] = injectVision
+	deps[injectBattery.Name()] = injectBattery
+	conf := s.config
+	svcConfig := *conf.ConvertedAttributes.(*Config)
+	svcConfig.Docks = []*DockConfig{charger, garage}
+	svcConfig.DockOnLowBattery = &BatteryDockingConfig{PowerSensorName: "battery", MinVolts: 11.5, Dock: "garage", PollingFrequencyHz: 100}
+	conf.ConvertedAttributes = &svcConfig
+	test.That(t, s.ns.Reconfigure(ctx, deps, conf), test.ShouldBeNil)
+	docker, ok := s.ns.(navigation.Docker)
+	test.That(t, ok, test.ShouldBeTrue)
+	svc := s.ns.(*builtIn)
+
+	t.Run("staged approach with alignment", func(t *testing.T) {
+		test.That(t, docker.DockNow(ctx, "", nil), test.ShouldBeNil)
+		mu.Lock()
+		defer mu.Unlock()
+		// the base first drives to 2m west of the dock, facing east
+		test.That(t, len(moves), test.ShouldEqual, 1)
+		test.That(t, moves[0].Heading, test.ShouldEqual, 90)
+		dockLocation := geo.NewPoint(1, 1)
+		offset := spatialmath.GeoPointToPoint(moves[0].Destination, dockLocation)
+		test.That(t, offset.X, test.ShouldAlmostEqual, -2000, 1)
+		test.That(t, offset.Y, test.ShouldAlmostEqual, 0, 1)
+		// then turns right towards the marker and drives straight in
+		test.That(t, len(spins), test.ShouldEqual, 1)
+		test.That(t, spins[0], test.ShouldAlmostEqual, -10, 0.1)
+		test.That(t, straights, test.ShouldResemble, []int{2000})
+		test.That(t, svc.Stats().(navigationStats).Dockings, test.ShouldEqual, 1)
+	})
+
+	t.Run("direct approach", func(t *testing.T) {
+		test.That(t, docker.DockNow(ctx, "garage", nil), test.ShouldBeNil)
+		mu.Lock()
+		defer mu.Unlock()
+		test.That(t, len(moves), test.ShouldEqual, 2)
+		test.That(t, *moves[1].Destination, test.ShouldResemble, *geo.NewPoint(2, 2))
+		test.That(t, len(straights), test.ShouldEqual, 1)
+	})
+
+	t.Run("unknown dock", func(t *testing.T) {
+		err := docker.DockNow(ctx, "missing", nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `no dock named "missing"`)
+	})
+
+	t.Run("failed docking", func(t *testing.T) {
+		injectVision.DetectionsFromCameraFunc = func(
+			ctx context.Context, cameraName string, extra map[string]interface{},
+		) ([]objectdetection.Detection, error) {
+			return nil, nil
+		}
+		err := docker.DockNow(ctx, "charger", nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `dock marker "marker" not detected`)
+		test.That(t, svc.Stats().(navigationStats).DockingFailures, test.ShouldEqual, 1)
+	})
+
+	t.Run("low battery", func(t *testing.T) {
+		dockings := func() int64 { return svc.Stats().(navigationStats).Dockings }
+		volts.Store(11)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, dockings(), test.ShouldEqual, 3)
+		})
+		mu.Lock()
+		test.That(t, *moves[len(moves)-1].Destination, test.ShouldResemble, *geo.NewPoint(2, 2))
+		mu.Unlock()
+
+		// the base docks once each time the battery runs low
+		time.Sleep(100 * time.Millisecond)
+		test.That(t, dockings(), test.ShouldEqual, 3)
+		volts.Store(12)
+		time.Sleep(100 * time.Millisecond)
+		volts.Store(11)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, dockings(), test.ShouldEqual, 4)
+		})
+	})
+}
+
+func TestWaypointFiles(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	t.Run("import waypoints", func(t *testing.T) {
+		s := setupStartWaypoint(ctx, t, logger)
+		defer s.closeFunc()
+		filer, ok := s.ns.(navigation.WaypointFiler)
+		test.That(t, ok, test.ShouldBeTrue)
+
+		count, err := filer.ImportWaypoints(ctx, []byte(`{
+			"type": "LineString",
+			"coordinates": [[2, 1], [4, 3], [6, 5]]
+		}`), navigation.WaypointFormatGeoJSON, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, count, test.ShouldEqual, 3)
+		wps, err := s.ns.Waypoints(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(wps), test.ShouldEqual, 3)
+		for i, wp := range wps {
+			test.That(t, wp.Lat, test.ShouldEqual, float64(2*i+1))
+			test.That(t, wp.Long, test.ShouldEqual, float64(2*i+2))
+		}
+
+		// nothing is imported if any waypoint violates a geofence
+		svc, ok := s.ns.(*builtIn)
+		test.That(t, ok, test.ShouldBeTrue)
+		geofences, err := motion.NewGeofences([]*motion.GeofenceConfig{{
+			Name:    "pond",
+			Type:    motion.GeofenceKeepOut,
+			GeoJSON: map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{{{9, 9}, {11, 9}, {11, 11}, {9, 11}}}},
+		}})
+		test.That(t, err, test.ShouldBeNil)
+		svc.geofences = geofences
+		_, err = filer.ImportWaypoints(ctx, []byte(`<gpx>
+  <wpt lat="20" lon="20"/>
+  <wpt lat="10" lon="10"/>
+</gpx>`), navigation.WaypointFormatGPX, nil)
+		test.That(t, errors.Is(err, motion.ErrGeofenceViolation), test.ShouldBeTrue)
+		wps, err = s.ns.Waypoints(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(wps), test.ShouldEqual, 3)
+	})
+
+	t.Run("waypoints file", func(t *testing.T) {
+		s := setupStartWaypoint(ctx, t, logger)
+		defer s.closeFunc()
+
+		waypointsFile := filepath.Join(t.TempDir(), "survey.gpx")
+		gpx := `<gpx><rte><rtept lat="1" lon="2"/><rtept lat="3" lon="4"/></rte></gpx>`
+		test.That(t, os.WriteFile(waypointsFile, []byte(gpx), 0o600), test.ShouldBeNil)
+		conf, ok := s.config.ConvertedAttributes.(*Config)
+		test.That(t, ok, test.ShouldBeTrue)
+		conf.WaypointsFile = waypointsFile
+
+		// the waypoints are imported once, and not again when reconfiguring with the same file
+		for i := 0; i < 2; i++ {
+			test.That(t, s.ns.Reconfigure(ctx, s.deps, s.config), test.ShouldBeNil)
+			wps, err := s.ns.Waypoints(ctx, nil)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, len(wps), test.ShouldEqual, 2)
+			test.That(t, wps[1].Lat, test.ShouldEqual, 3)
+		}
+
+		conf.WaypointsFile = filepath.Join(t.TempDir(), "missing.gpx")
+		test.That(t, s.ns.Reconfigure(ctx, s.deps, s.config), test.ShouldNotBeNil)
+
+		conf.WaypointsFile = "survey.kml"
+		_, err := conf.Validate("")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("export traveled path", func(t *testing.T) {
+		s := setupStartWaypoint(ctx, t, logger)
+		defer s.closeFunc()
+		svc, ok := s.ns.(*builtIn)
+		test.That(t, ok, test.ShouldBeTrue)
+
+		// the base moves north, stopping for a while at its second location
+		recordCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		lats := []float64{1, 2, 2, 2, 3}
+		polls := 0
+		s.movementSensor.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+			lat := lats[min(polls, len(lats)-1)]
+			polls++
+			if polls == len(lats) {
+				cancel()
+			}
+			return geo.NewPoint(lat, 0), 0, nil
+		}
+		svc.recordTraveledPath(recordCtx, time.Millisecond)
+
+		data, err := svc.ExportTraveledPath(ctx, navigation.WaypointFormatGeoJSON, nil)
+		test.That(t, err, test.ShouldBeNil)
+		points, err := navigation.ParseWaypoints(data, navigation.WaypointFormatGeoJSON)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, points, test.ShouldResemble, []*geo.Point{geo.NewPoint(1, 0), geo.NewPoint(2, 0), geo.NewPoint(3, 0)})
+	})
+}
+
 func TestStartWaypoint(t *testing.T) {
 	ctx := context.Background()
 	logger := logging.NewTestLogger(t)
@@ -0,0 +1,439 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/powersensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/services/navigation"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+// The strategies a base can approach a dock with.
+const (
+	// DockApproachStaged drives to a point in front of the dock, facing it, and then straight in.
+	DockApproachStaged = "staged"
+	// DockApproachDirect drives to the dock like to a waypoint, arriving with the heading of the dock.
+	DockApproachDirect = "direct"
+)
+
+const (
+	defaultDockApproachDistanceM      = 1.
+	defaultDockAlignmentToleranceDegs = 2.
+	defaultBatteryPollingHz           = 0.1
+
+	// how many times the base turns towards the marker of a dock before giving up on aligning with it.
+	maxDockAlignmentAttempts = 5
+)
+
+// DockConfig describes a dock, such as a charger, that the base can return to.
+type DockConfig struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// HeadingDegs is the compass heading of the base when docked, which is the direction it drives in to dock.
+	HeadingDegs float64 `json:"heading_degs"`
+	// Approach is the strategy used to reach the dock, staged if unset.
+	Approach string `json:"approach,omitempty"`
+	// ApproachDistanceM is how far in front of the dock a staged approach starts driving straight in.
+	ApproachDistanceM float64 `json:"approach_distance_m,omitempty"`
+	// Alignment turns the base towards a marker on the dock before a staged approach drives straight in.
+	Alignment *DockAlignmentConfig `json:"alignment,omitempty"`
+}
+
+// DockAlignmentConfig describes the vision-based final alignment of the base with a dock.
+type DockAlignmentConfig struct {
+	VisionServiceName string `json:"vision_service"`
+	CameraName        string `json:"camera"`
+	// Label is the label of the detections of the marker of the dock.
+	Label string `json:"label"`
+	// ToleranceDegs is how far off the center of the camera the marker may be for the base to be aligned.
+	ToleranceDegs float64 `json:"tolerance_degs,omitempty"`
+}
+
+// BatteryDockingConfig describes when the base returns to a dock on its own to recharge.
+type BatteryDockingConfig struct {
+	PowerSensorName string `json:"power_sensor"`
+	// MinVolts is the voltage below which the base docks. It docks again once the voltage has recovered
+	// and dropped below it again.
+	MinVolts float64 `json:"min_volts"`
+	// Dock is the name of the dock to return to, the first dock if unset.
+	Dock               string  `json:"dock,omitempty"`
+	PollingFrequencyHz float64 `json:"polling_frequency_hz,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid and returns the dependencies of the alignment.
+func (conf *DockConfig) Validate(path string) ([]string, error) {
+	if conf.Name == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "name")
+	}
+	if conf.Latitude < -90 || conf.Latitude > 90 || conf.Longitude < -180 || conf.Longitude > 180 {
+		return nil, resource.NewConfigValidationError(path,
+			errors.Errorf("dock location (%v, %v) is not a valid latitude and longitude", conf.Latitude, conf.Longitude))
+	}
+	switch conf.Approach {
+	case "", DockApproachStaged, DockApproachDirect:
+	default:
+		return nil, resource.NewConfigValidationError(path,
+			errors.Errorf("unknown dock approach %q, must be %q or %q", conf.Approach, DockApproachStaged, DockApproachDirect))
+	}
+	if conf.ApproachDistanceM < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("approach_distance_m must be non-negative if set"))
+	}
+	if conf.Alignment == nil {
+		return nil, nil
+	}
+	if conf.Approach == DockApproachDirect {
+		return nil, resource.NewConfigValidationError(path, errors.New("alignment requires a staged approach"))
+	}
+	alignmentPath := path + ".alignment"
+	if conf.Alignment.VisionServiceName == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(alignmentPath, "vision_service")
+	}
+	if conf.Alignment.CameraName == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(alignmentPath, "camera")
+	}
+	if conf.Alignment.Label == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(alignmentPath, "label")
+	}
+	if conf.Alignment.ToleranceDegs < 0 {
+		return nil, resource.NewConfigValidationError(alignmentPath, errors.New("tolerance_degs must be non-negative if set"))
+	}
+	return []string{
+		resource.NewName(vision.API, conf.Alignment.VisionServiceName).String(),
+		resource.NewName(camera.API, conf.Alignment.CameraName).String(),
+	}, nil
+}
+
+// validateDocking validates the docks and battery docking of the config and returns their dependencies.
+func (conf *Config) validateDocking(path string) ([]string, error) {
+	var deps []string
+	names := map[string]bool{}
+	for i, dock := range conf.Docks {
+		dockDeps, err := dock.Validate(fmt.Sprintf("%s.docks.%d", path, i))
+		if err != nil {
+			return nil, err
+		}
+		if names[dock.Name] {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf("dock %q is configured more than once", dock.Name))
+		}
+		names[dock.Name] = true
+		deps = append(deps, dockDeps...)
+	}
+	if len(conf.Docks) > 0 && conf.MapType != "" && conf.MapType != navigation.GPSMap.String() {
+		return nil, resource.NewConfigValidationError(path, errors.New("docks require the GPS map type"))
+	}
+
+	if conf.DockOnLowBattery == nil {
+		return deps, nil
+	}
+	batteryPath := path + ".dock_on_low_battery"
+	if conf.DockOnLowBattery.PowerSensorName == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(batteryPath, "power_sensor")
+	}
+	if conf.DockOnLowBattery.MinVolts <= 0 {
+		return nil, resource.NewConfigValidationError(batteryPath, errors.New("min_volts must be positive"))
+	}
+	if conf.DockOnLowBattery.PollingFrequencyHz < 0 {
+		return nil, resource.NewConfigValidationError(batteryPath, errors.New("polling_frequency_hz must be non-negative if set"))
+	}
+	if len(conf.Docks) == 0 {
+		return nil, resource.NewConfigValidationError(batteryPath, errors.New("docking on low battery requires a dock"))
+	}
+	if dock := conf.DockOnLowBattery.Dock; dock != "" && !names[dock] {
+		return nil, resource.NewConfigValidationError(batteryPath, errors.Errorf("no dock named %q", dock))
+	}
+	return append(deps, resource.NewName(powersensor.API, conf.DockOnLowBattery.PowerSensorName).String()), nil
+}
+
+// dock is a configured dock, with the resources its alignment uses.
+type dock struct {
+	name               string
+	pose               *spatialmath.GeoPose
+	approach           string
+	approachDistanceMM float64
+	alignment          *dockAlignment
+}
+
+type dockAlignment struct {
+	visionSvc     vision.Service
+	camera        camera.Camera
+	label         string
+	toleranceDegs float64
+}
+
+type batteryDocking struct {
+	sensor   powersensor.PowerSensor
+	minVolts float64
+	dock     string
+	period   time.Duration
+}
+
+// newDocks returns the configured docks and battery docking, looking up the resources they use in deps.
+func newDocks(deps resource.Dependencies, conf *Config) ([]*dock, *batteryDocking, error) {
+	docks := make([]*dock, 0, len(conf.Docks))
+	for _, dockCfg := range conf.Docks {
+		d := &dock{
+			name:               dockCfg.Name,
+			pose:               spatialmath.NewGeoPose(geo.NewPoint(dockCfg.Latitude, dockCfg.Longitude), dockCfg.HeadingDegs),
+			approach:           DockApproachStaged,
+			approachDistanceMM: 1e3 * defaultDockApproachDistanceM,
+		}
+		if dockCfg.Approach != "" {
+			d.approach = dockCfg.Approach
+		}
+		if dockCfg.ApproachDistanceM != 0 {
+			d.approachDistanceMM = 1e3 * dockCfg.ApproachDistanceM
+		}
+		if alignmentCfg := dockCfg.Alignment; alignmentCfg != nil {
+			visionSvc, err := vision.FromDependencies(deps, alignmentCfg.VisionServiceName)
+			if err != nil {
+				return nil, nil, err
+			}
+			cam, err := camera.FromDependencies(deps, alignmentCfg.CameraName)
+			if err != nil {
+				return nil, nil, err
+			}
+			d.alignment = &dockAlignment{
+				visionSvc:     visionSvc,
+				camera:        cam,
+				label:         alignmentCfg.Label,
+				toleranceDegs: defaultDockAlignmentToleranceDegs,
+			}
+			if alignmentCfg.ToleranceDegs != 0 {
+				d.alignment.toleranceDegs = alignmentCfg.ToleranceDegs
+			}
+		}
+		docks = append(docks, d)
+	}
+
+	batteryCfg := conf.DockOnLowBattery
+	if batteryCfg == nil {
+		return docks, nil, nil
+	}
+	sensor, err := powersensor.FromDependencies(deps, batteryCfg.PowerSensorName)
+	if err != nil {
+		return nil, nil, err
+	}
+	pollingHz := defaultBatteryPollingHz
+	if batteryCfg.PollingFrequencyHz != 0 {
+		pollingHz = batteryCfg.PollingFrequencyHz
+	}
+	return docks, &batteryDocking{
+		sensor:   sensor,
+		minVolts: batteryCfg.MinVolts,
+		dock:     batteryCfg.Dock,
+		period:   time.Duration(float64(time.Second) / pollingHz),
+	}, nil
+}
+
+// findDock returns the dock named `name`, or the first dock if `name` is empty. svc.mu must be held.
+func (svc *builtIn) findDock(name string) (*dock, error) {
+	if len(svc.docks) == 0 {
+		return nil, errors.New("no docks are configured")
+	}
+	if name == "" {
+		return svc.docks[0], nil
+	}
+	for _, d := range svc.docks {
+		if d.name == name {
+			return d, nil
+		}
+	}
+	return nil, errors.Errorf("no dock named %q", name)
+}
+
+// DockNow stops navigating to waypoints and drives the base to the named dock, or the first dock if name is
+// empty. The service is left in manual mode. Docking is canceled when the mode is set to waypoint mode, the
+// service is reconfigured or closed, or ctx is done.
+func (svc *builtIn) DockNow(ctx context.Context, name string, extra map[string]interface{}) error {
+	svc.actionMu.Lock()
+	svc.mu.RLock()
+	d, err := svc.findDock(name)
+	svc.mu.RUnlock()
+	if err != nil {
+		svc.actionMu.Unlock()
+		return err
+	}
+	svc.logger.CInfof(ctx, "DockNow called: docking at %q", d.name)
+
+	// docking takes the place of the active mode, so that changing modes cancels it
+	svc.stopActiveMode()
+	svc.mu.Lock()
+	dockCtx, cancelFunc := context.WithCancel(ctx)
+	defer cancelFunc()
+	svc.wholeServiceCancelFunc = cancelFunc
+	svc.mode = navigation.ModeManual
+	svc.activeBackgroundWorkers.Add(1)
+	svc.mu.Unlock()
+	svc.actionMu.Unlock()
+	defer svc.activeBackgroundWorkers.Done()
+
+	if err := svc.dock(dockCtx, d, extra); err != nil {
+		svc.dockingFailures.Add(1)
+		return errors.Wrapf(err, "failed to dock at %q", d.name)
+	}
+	svc.dockings.Add(1)
+	svc.logger.CInfof(ctx, "docked at %q", d.name)
+	return nil
+}
+
+func (svc *builtIn) dock(ctx context.Context, d *dock, extra map[string]interface{}) error {
+	if d.approach == DockApproachDirect {
+		return svc.moveOnGlobeSync(ctx, d.pose.Location(), d.pose.Heading(), extra)
+	}
+
+	// the approach point is behind the dock, along its heading
+	approach := spatialmath.PoseToGeoPose(d.pose, spatialmath.NewPoseFromPoint(r3.Vector{Y: -d.approachDistanceMM}))
+	if err := svc.moveOnGlobeSync(ctx, approach.Location(), d.pose.Heading(), extra); err != nil {
+		return errors.Wrap(err, "failed to reach the approach point")
+	}
+
+	svc.mu.RLock()
+	b := svc.base
+	linearMPerSec := svc.motionCfg.LinearMPerSec
+	angularDegsPerSec := svc.motionCfg.AngularDegsPerSec
+	svc.mu.RUnlock()
+	if d.alignment != nil {
+		if err := alignWithDock(ctx, b, d.alignment, angularDegsPerSec); err != nil {
+			return err
+		}
+	}
+	return b.MoveStraight(ctx, int(d.approachDistanceMM), 1e3*linearMPerSec, nil)
+}
+
+// moveOnGlobeSync moves the base to `destination` with `heading` and waits for it to get there.
+func (svc *builtIn) moveOnGlobeSync(
+	ctx context.Context,
+	destination *geo.Point,
+	heading float64,
+	extra map[string]interface{},
+) error {
+	svc.mu.RLock()
+	req := motion.MoveOnGlobeReq{
+		ComponentName:      svc.base.Name(),
+		Destination:        destination,
+		Heading:            heading,
+		MovementSensorName: svc.movementSensor.Name(),
+		Obstacles:          svc.obstacles,
+		MotionCfg:          svc.motionCfg,
+		BoundingRegions:    svc.boundingRegions,
+		Geofences:          svc.geofences,
+		SpeedLimits:        svc.speedLimits,
+		Extra:              extra,
+	}
+	motionSvc := svc.motionService
+	svc.mu.RUnlock()
+
+	executionID, err := motionSvc.MoveOnGlobe(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		timeoutCtx, timeoutCancelFn := context.WithTimeout(context.Background(), time.Second*5)
+		defer timeoutCancelFn()
+		if err := motionSvc.StopPlan(timeoutCtx, motion.StopPlanReq{ComponentName: req.ComponentName}); err != nil {
+			svc.logger.CErrorf(ctx, "hit error trying to stop plan %s", err)
+		}
+	}()
+	return motion.PollHistoryUntilSuccessOrError(ctx, motionSvc, planHistoryPollFrequency,
+		motion.PlanHistoryReq{
+			ComponentName: req.ComponentName,
+			ExecutionID:   executionID,
+			LastPlanOnly:  true,
+		},
+	)
+}
+
+// alignWithDock spins the base until the marker of the dock is in the center of the camera.
+func alignWithDock(ctx context.Context, b base.Base, a *dockAlignment, degsPerSec float64) error {
+	props, err := a.camera.Properties(ctx)
+	if err != nil {
+		return err
+	}
+	if props.IntrinsicParams == nil {
+		return errors.Errorf("camera %q has no intrinsic parameters to align with the dock", a.camera.Name().ShortName())
+	}
+	for i := 0; i < maxDockAlignmentAttempts; i++ {
+		detections, err := a.visionSvc.DetectionsFromCamera(ctx, a.camera.Name().ShortName(), nil)
+		if err != nil {
+			return err
+		}
+		bestScore := -1.
+		var offsetDegs float64
+		for _, detection := range detections {
+			if detection.Label() != a.label || detection.Score() <= bestScore {
+				continue
+			}
+			bestScore = detection.Score()
+			box := detection.BoundingBox()
+			centerX := float64(box.Min.X+box.Max.X) / 2
+			offsetDegs = rdkutils.RadToDeg(math.Atan2(centerX-props.IntrinsicParams.Ppx, props.IntrinsicParams.Fx))
+		}
+		if bestScore < 0 {
+			return errors.Errorf("dock marker %q not detected", a.label)
+		}
+		if math.Abs(offsetDegs) <= a.toleranceDegs {
+			return nil
+		}
+		// a marker right of the center of the image is to the right of the base, and turning right is a negative spin
+		if err := b.Spin(ctx, -offsetDegs, degsPerSec, nil); err != nil {
+			return err
+		}
+	}
+	return errors.Errorf("could not align with dock marker %q in %d attempts", a.label, maxDockAlignmentAttempts)
+}
+
+// monitorBattery docks the base when the voltage of the battery drops below the minimum, until ctx is done.
+func (svc *builtIn) monitorBattery(ctx context.Context, bd *batteryDocking) {
+	ticker := time.NewTicker(bd.period)
+	defer ticker.Stop()
+	// the base docks once each time the voltage drops below the minimum
+	armed := true
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		volts, _, err := bd.sensor.Voltage(ctx, nil)
+		if err != nil {
+			svc.logger.CDebugf(ctx, "cannot check battery voltage: %s", err)
+			continue
+		}
+		if volts >= bd.minVolts {
+			armed = true
+			continue
+		}
+		if !armed {
+			continue
+		}
+		armed = false
+		svc.logger.CWarnf(ctx, "battery voltage %.2fV is below %.2fV, returning to dock", volts, bd.minVolts)
+		if err := svc.DockNow(ctx, bd.dock, nil); err != nil && ctx.Err() == nil {
+			svc.logger.CErrorf(ctx, "failed to dock on low battery: %s", err)
+		}
+	}
+}
+
+// stopBatteryMonitor stops docking on low battery. It must not be called with svc.actionMu held, since the
+// monitor takes it to dock.
+func (svc *builtIn) stopBatteryMonitor() {
+	if svc.batteryMonitorCancelFunc != nil {
+		svc.batteryMonitorCancelFunc()
+		svc.batteryMonitorCancelFunc = nil
+	}
+	svc.batteryMonitorWorkers.Wait()
+}
@@ -0,0 +1,70 @@
+package navigation
+
+import (
+	"context"
+
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+)
+
+// The navigation proto has no docking RPCs. Docking is carried over DoCommand using the following
+// reserved keys.
+const (
+	dockNowKey   = "dock_now"
+	dockNameKey  = "dock"
+	dockExtraKey = "extra"
+)
+
+// Docker is implemented by navigation services that can drive the machine back to a dock, such as
+// a charger, so that it can recharge autonomously.
+//
+// DockNow example:
+//
+//	myNav, err := navigation.FromRobot(machine, "my_nav_service")
+//	if docker, ok := myNav.(navigation.Docker); ok {
+//		// Drive to the first configured dock.
+//		err := docker.DockNow(context.Background(), "", nil)
+//	}
+type Docker interface {
+	// DockNow stops navigating to waypoints and drives the machine to the named dock, or to the
+	// first configured dock if name is empty. It returns once the machine is docked.
+	DockNow(ctx context.Context, name string, extra map[string]interface{}) error
+}
+
+func (c *client) DockNow(ctx context.Context, name string, extra map[string]interface{}) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		dockNowKey: map[string]interface{}{
+			dockNameKey:  name,
+			dockExtraKey: extra,
+		},
+	})
+	return err
+}
+
+// doDockingCommand handles the reserved docking DoCommand keys. It returns false if `req` is not a
+// docking command or the service does not implement Docker.
+func doDockingCommand(
+	ctx context.Context,
+	svc Service,
+	req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	docker, ok := svc.(Docker)
+	if !ok {
+		return nil, false, nil
+	}
+	payload, ok := req.GetCommand().AsMap()[dockNowKey]
+	if !ok {
+		return nil, false, nil
+	}
+	args, _ := payload.(map[string]interface{})             //nolint:errcheck
+	name, _ := args[dockNameKey].(string)                   //nolint:errcheck
+	extra, _ := args[dockExtraKey].(map[string]interface{}) //nolint:errcheck
+	if err := docker.DockNow(ctx, name, extra); err != nil {
+		return nil, true, err
+	}
+	res, err := protoutils.StructToStructPb(map[string]interface{}{})
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
@@ -0,0 +1,66 @@
+package navigation_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/navigation"
+	"go.viam.com/rdk/testutils"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestClientDockNow(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	var docked string
+	injectNav := inject.NewNavigationService(testSvcName1.Name)
+	injectNav.DockNowFunc = func(ctx context.Context, name string, extra map[string]interface{}) error {
+		if name == "missing" {
+			return errors.New("no dock named missing")
+		}
+		docked = name
+		return nil
+	}
+	injectNav.DoCommandFunc = testutils.EchoFunc
+
+	navSvc, err := resource.NewAPIResourceCollection(navigation.API, map[resource.Name]navigation.Service{testSvcName1: injectNav})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[navigation.Service](navigation.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, navSvc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	client, err := navigation.NewClientFromConn(context.Background(), conn, "", testSvcName1, logger)
+	test.That(t, err, test.ShouldBeNil)
+	docker, ok := client.(navigation.Docker)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	test.That(t, docker.DockNow(context.Background(), "charger", nil), test.ShouldBeNil)
+	test.That(t, docked, test.ShouldEqual, "charger")
+	err = docker.DockNow(context.Background(), "missing", nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "no dock named missing")
+
+	// other commands still reach the service's DoCommand
+	resp, err := client.DoCommand(context.Background(), testutils.TestCommand)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["command"], test.ShouldEqual, testutils.TestCommand["command"])
+}
@@ -24,6 +24,35 @@ func init() {
 		RPCServiceDesc:              &servicepb.NavigationService_ServiceDesc,
 		RPCClient:                   NewClientFromConn,
 	})
+	robot.RegisterStateSnapshotter(API, robot.StateSnapshotter{Capture: captureMode, Restore: restoreMode})
+}
+
+// captureMode captures the mode of a navigation service, such that a robot that was navigating
+// waypoints resumes after a restore.
+func captureMode(ctx context.Context, res resource.Resource) (map[string]interface{}, error) {
+	svc, err := resource.AsType[Service](res)
+	if err != nil {
+		return nil, err
+	}
+	mode, err := svc.Mode(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"mode": mode.String()}, nil
+}
+
+// restoreMode sets the mode of a navigation service captured by captureMode.
+func restoreMode(ctx context.Context, res resource.Resource, state map[string]interface{}) error {
+	svc, err := resource.AsType[Service](res)
+	if err != nil {
+		return err
+	}
+	for _, mode := range []Mode{ModeManual, ModeWaypoint, ModeExplore} {
+		if state["mode"] == mode.String() {
+			return svc.SetMode(ctx, mode, nil)
+		}
+	}
+	return errors.Errorf("invalid navigation state %v", state)
 }
 
 // Mode describes what mode to operate the service in.
@@ -195,5 +195,11 @@ func (server *serviceServer) DoCommand(
 	if err != nil {
 		return nil, err
 	}
+	if resp, handled, err := doWaypointFileCommand(ctx, svc, req); handled {
+		return resp, err
+	}
+	if resp, handled, err := doDockingCommand(ctx, svc, req); handled {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, svc, req)
 }
@@ -0,0 +1,274 @@
+package navigation
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// The navigation proto has no waypoint file RPCs. Waypoint files are carried over DoCommand using
+// the following reserved keys.
+const (
+	importWaypointsKey    = "import_waypoints"
+	exportTraveledPathKey = "export_traveled_path"
+	waypointFileDataKey   = "data"
+	waypointFileFormatKey = "format"
+	waypointCountKey      = "count"
+	waypointExtraKey      = "extra"
+)
+
+// WaypointFormat is a file format that waypoints can be imported from and traveled paths exported to.
+type WaypointFormat string
+
+// The supported waypoint file formats.
+const (
+	WaypointFormatGPX     = WaypointFormat("gpx")
+	WaypointFormatGeoJSON = WaypointFormat("geojson")
+)
+
+// WaypointFormatFromPath returns the format of a waypoint file from its extension, which is one of
+// .gpx, .geojson or .json.
+func WaypointFormatFromPath(path string) (WaypointFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gpx":
+		return WaypointFormatGPX, nil
+	case ".geojson", ".json":
+		return WaypointFormatGeoJSON, nil
+	default:
+		return "", errors.Errorf("cannot tell the waypoint format of %q from its extension", path)
+	}
+}
+
+// TraveledPoint is a location a machine passed through while navigating.
+type TraveledPoint struct {
+	Point *geo.Point
+	Time  time.Time
+}
+
+// WaypointFiler is implemented by navigation services that can import waypoints from, and export
+// the path they traveled to, GPX and GeoJSON files, for interop with mapping tools.
+//
+// ImportWaypoints example:
+//
+//	myNav, err := navigation.FromRobot(machine, "my_nav_service")
+//	if filer, ok := myNav.(navigation.WaypointFiler); ok {
+//		data, err := os.ReadFile("survey.gpx")
+//		count, err := filer.ImportWaypoints(context.Background(), data, navigation.WaypointFormatGPX, nil)
+//		logger.Infof("imported %d waypoints", count)
+//	}
+//
+// ExportTraveledPath example:
+//
+//	if filer, ok := myNav.(navigation.WaypointFiler); ok {
+//		data, err := filer.ExportTraveledPath(context.Background(), navigation.WaypointFormatGeoJSON, nil)
+//		err = os.WriteFile("traveled.geojson", data, 0o644)
+//	}
+type WaypointFiler interface {
+	// ImportWaypoints adds the waypoints of a GPX or GeoJSON file, in order, and returns how many
+	// were added.
+	ImportWaypoints(ctx context.Context, data []byte, format WaypointFormat, extra map[string]interface{}) (int, error)
+
+	// ExportTraveledPath returns the path the machine traveled while navigating to waypoints as a
+	// GPX or GeoJSON file.
+	ExportTraveledPath(ctx context.Context, format WaypointFormat, extra map[string]interface{}) ([]byte, error)
+}
+
+type gpxFile struct {
+	XMLName   xml.Name   `xml:"gpx"`
+	Version   string     `xml:"version,attr,omitempty"`
+	Creator   string     `xml:"creator,attr,omitempty"`
+	Xmlns     string     `xml:"xmlns,attr,omitempty"`
+	Waypoints []gpxPoint `xml:"wpt"`
+	Routes    []gpxRoute `xml:"rte"`
+	Tracks    []gpxTrack `xml:"trk"`
+}
+
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Time string  `xml:"time,omitempty"`
+}
+
+type gpxRoute struct {
+	Points []gpxPoint `xml:"rtept"`
+}
+
+type gpxTrack struct {
+	Name     string       `xml:"name,omitempty"`
+	Segments []gpxSegment `xml:"trkseg"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+// ParseWaypoints returns, in order, the waypoints of a GPX or GeoJSON file. The waypoints of a GPX
+// file are its <wpt> points if it has any, otherwise its route points, otherwise its track points.
+// The waypoints of a GeoJSON file are the positions of its Points, MultiPoints and LineStrings.
+func ParseWaypoints(data []byte, format WaypointFormat) ([]*geo.Point, error) {
+	switch format {
+	case WaypointFormatGPX:
+		var gpx gpxFile
+		if err := xml.Unmarshal(data, &gpx); err != nil {
+			return nil, errors.Wrap(err, "invalid GPX")
+		}
+		gpxPoints := gpx.Waypoints
+		if len(gpxPoints) == 0 {
+			for _, route := range gpx.Routes {
+				gpxPoints = append(gpxPoints, route.Points...)
+			}
+		}
+		if len(gpxPoints) == 0 {
+			for _, track := range gpx.Tracks {
+				for _, segment := range track.Segments {
+					gpxPoints = append(gpxPoints, segment.Points...)
+				}
+			}
+		}
+		if len(gpxPoints) == 0 {
+			return nil, errors.New("GPX contains no waypoints, routes or tracks")
+		}
+		points := make([]*geo.Point, 0, len(gpxPoints))
+		for _, pt := range gpxPoints {
+			points = append(points, geo.NewPoint(pt.Lat, pt.Lon))
+		}
+		return points, nil
+	case WaypointFormatGeoJSON:
+		return spatialmath.GeoPointsFromGeoJSON(data)
+	default:
+		return nil, errors.Errorf("unsupported waypoint format %q", format)
+	}
+}
+
+// EncodeTraveledPath returns `path` as a GPX track, or as a GeoJSON LineString feature whose
+// "times" property holds the RFC 3339 time of each of its positions.
+func EncodeTraveledPath(path []TraveledPoint, format WaypointFormat) ([]byte, error) {
+	switch format {
+	case WaypointFormatGPX:
+		segment := gpxSegment{Points: make([]gpxPoint, 0, len(path))}
+		for _, pt := range path {
+			segment.Points = append(segment.Points, gpxPoint{
+				Lat:  pt.Point.Lat(),
+				Lon:  pt.Point.Lng(),
+				Time: pt.Time.UTC().Format(time.RFC3339Nano),
+			})
+		}
+		gpx := gpxFile{
+			Version: "1.1",
+			Creator: "viam-server",
+			Xmlns:   "http://www.topografix.com/GPX/1/1",
+			Tracks:  []gpxTrack{{Name: "traveled path", Segments: []gpxSegment{segment}}},
+		}
+		data, err := xml.MarshalIndent(gpx, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(xml.Header), data...), nil
+	case WaypointFormatGeoJSON:
+		coordinates := make([][]float64, 0, len(path))
+		times := make([]string, 0, len(path))
+		for _, pt := range path {
+			coordinates = append(coordinates, []float64{pt.Point.Lng(), pt.Point.Lat()})
+			times = append(times, pt.Time.UTC().Format(time.RFC3339Nano))
+		}
+		return json.Marshal(map[string]interface{}{
+			"type":       "Feature",
+			"properties": map[string]interface{}{"name": "traveled path", "times": times},
+			"geometry":   map[string]interface{}{"type": "LineString", "coordinates": coordinates},
+		})
+	default:
+		return nil, errors.Errorf("unsupported waypoint format %q", format)
+	}
+}
+
+func (c *client) ImportWaypoints(
+	ctx context.Context,
+	data []byte,
+	format WaypointFormat,
+	extra map[string]interface{},
+) (int, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		importWaypointsKey: map[string]interface{}{
+			waypointFileDataKey:   string(data),
+			waypointFileFormatKey: string(format),
+			waypointExtraKey:      extra,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	count, ok := resp[waypointCountKey].(float64)
+	if !ok {
+		return 0, errors.Errorf("expected %q in response, got %v", waypointCountKey, resp)
+	}
+	return int(count), nil
+}
+
+func (c *client) ExportTraveledPath(ctx context.Context, format WaypointFormat, extra map[string]interface{}) ([]byte, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		exportTraveledPathKey: map[string]interface{}{
+			waypointFileFormatKey: string(format),
+			waypointExtraKey:      extra,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	data, ok := resp[waypointFileDataKey].(string)
+	if !ok {
+		return nil, errors.Errorf("expected %q in response, got %v", waypointFileDataKey, resp)
+	}
+	return []byte(data), nil
+}
+
+// doWaypointFileCommand handles the reserved waypoint file DoCommand keys. It returns false if
+// `req` is not a waypoint file command or the service does not implement WaypointFiler.
+func doWaypointFileCommand(
+	ctx context.Context,
+	svc Service,
+	req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	filer, ok := svc.(WaypointFiler)
+	if !ok {
+		return nil, false, nil
+	}
+	cmd := req.GetCommand().AsMap()
+	var result map[string]interface{}
+	if payload, ok := cmd[importWaypointsKey]; ok {
+		args, _ := payload.(map[string]interface{})                 //nolint:errcheck
+		data, _ := args[waypointFileDataKey].(string)               //nolint:errcheck
+		format, _ := args[waypointFileFormatKey].(string)           //nolint:errcheck
+		extra, _ := args[waypointExtraKey].(map[string]interface{}) //nolint:errcheck
+		count, err := filer.ImportWaypoints(ctx, []byte(data), WaypointFormat(format), extra)
+		if err != nil {
+			return nil, true, err
+		}
+		result = map[string]interface{}{waypointCountKey: count}
+	} else if payload, ok := cmd[exportTraveledPathKey]; ok {
+		args, _ := payload.(map[string]interface{})                 //nolint:errcheck
+		format, _ := args[waypointFileFormatKey].(string)           //nolint:errcheck
+		extra, _ := args[waypointExtraKey].(map[string]interface{}) //nolint:errcheck
+		data, err := filer.ExportTraveledPath(ctx, WaypointFormat(format), extra)
+		if err != nil {
+			return nil, true, err
+		}
+		result = map[string]interface{}{waypointFileDataKey: string(data)}
+	} else {
+		return nil, false, nil
+	}
+	res, err := protoutils.StructToStructPb(result)
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
@@ -0,0 +1,163 @@
+package navigation_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/navigation"
+	"go.viam.com/rdk/testutils"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestWaypointFormatFromPath(t *testing.T) {
+	format, err := navigation.WaypointFormatFromPath("/data/survey.GPX")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, format, test.ShouldEqual, navigation.WaypointFormatGPX)
+	format, err = navigation.WaypointFormatFromPath("survey.geojson")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, format, test.ShouldEqual, navigation.WaypointFormatGeoJSON)
+	_, err = navigation.WaypointFormatFromPath("survey.kml")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestParseWaypoints(t *testing.T) {
+	t.Run("gpx waypoints", func(t *testing.T) {
+		points, err := navigation.ParseWaypoints([]byte(`<?xml version="1.0"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <wpt lat="40.1" lon="-73.1"><name>a</name></wpt>
+  <wpt lat="40.2" lon="-73.2"><name>b</name></wpt>
+  <rte><rtept lat="1" lon="1"/></rte>
+</gpx>`), navigation.WaypointFormatGPX)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, points, test.ShouldResemble, []*geo.Point{geo.NewPoint(40.1, -73.1), geo.NewPoint(40.2, -73.2)})
+	})
+
+	t.Run("gpx routes then tracks", func(t *testing.T) {
+		points, err := navigation.ParseWaypoints([]byte(`<gpx>
+  <rte><rtept lat="1" lon="2"/><rtept lat="3" lon="4"/></rte>
+  <trk><trkseg><trkpt lat="5" lon="6"/></trkseg></trk>
+</gpx>`), navigation.WaypointFormatGPX)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, points, test.ShouldResemble, []*geo.Point{geo.NewPoint(1, 2), geo.NewPoint(3, 4)})
+
+		points, err = navigation.ParseWaypoints([]byte(`<gpx>
+  <trk><trkseg><trkpt lat="5" lon="6"/></trkseg><trkseg><trkpt lat="7" lon="8"/></trkseg></trk>
+</gpx>`), navigation.WaypointFormatGPX)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, points, test.ShouldResemble, []*geo.Point{geo.NewPoint(5, 6), geo.NewPoint(7, 8)})
+
+		_, err = navigation.ParseWaypoints([]byte(`<gpx></gpx>`), navigation.WaypointFormatGPX)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("geojson", func(t *testing.T) {
+		points, err := navigation.ParseWaypoints(
+			[]byte(`{"type": "MultiPoint", "coordinates": [[-73.1, 40.1], [-73.2, 40.2]]}`),
+			navigation.WaypointFormatGeoJSON,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, points, test.ShouldResemble, []*geo.Point{geo.NewPoint(40.1, -73.1), geo.NewPoint(40.2, -73.2)})
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := navigation.ParseWaypoints([]byte(`{}`), navigation.WaypointFormat("kml"))
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestEncodeTraveledPath(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	path := []navigation.TraveledPoint{
+		{Point: geo.NewPoint(40.1, -73.1), Time: start},
+		{Point: geo.NewPoint(40.2, -73.2), Time: start.Add(time.Second)},
+	}
+	expected := []*geo.Point{path[0].Point, path[1].Point}
+
+	for _, format := range []navigation.WaypointFormat{navigation.WaypointFormatGPX, navigation.WaypointFormatGeoJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			data, err := navigation.EncodeTraveledPath(path, format)
+			test.That(t, err, test.ShouldBeNil)
+			// exported paths can be imported again as waypoints
+			points, err := navigation.ParseWaypoints(data, format)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, points, test.ShouldResemble, expected)
+			test.That(t, string(data), test.ShouldContainSubstring, "2024-01-02T03:04:06Z")
+		})
+	}
+
+	data, err := navigation.EncodeTraveledPath(path, navigation.WaypointFormatGeoJSON)
+	test.That(t, err, test.ShouldBeNil)
+	var feature map[string]interface{}
+	test.That(t, json.Unmarshal(data, &feature), test.ShouldBeNil)
+	test.That(t, feature["type"], test.ShouldEqual, "Feature")
+
+	_, err = navigation.EncodeTraveledPath(path, navigation.WaypointFormat("kml"))
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestClientWaypointFiles(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	var imported []byte
+	var importedFormat navigation.WaypointFormat
+	injectNav := inject.NewNavigationService(testSvcName1.Name)
+	injectNav.ImportWaypointsFunc = func(
+		ctx context.Context, data []byte, format navigation.WaypointFormat, extra map[string]interface{},
+	) (int, error) {
+		imported, importedFormat = data, format
+		return 2, nil
+	}
+	injectNav.ExportTraveledPathFunc = func(
+		ctx context.Context, format navigation.WaypointFormat, extra map[string]interface{},
+	) ([]byte, error) {
+		return []byte("<gpx/>"), nil
+	}
+	injectNav.DoCommandFunc = testutils.EchoFunc
+
+	navSvc, err := resource.NewAPIResourceCollection(navigation.API, map[resource.Name]navigation.Service{testSvcName1: injectNav})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[navigation.Service](navigation.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, navSvc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	client, err := navigation.NewClientFromConn(context.Background(), conn, "", testSvcName1, logger)
+	test.That(t, err, test.ShouldBeNil)
+	filer, ok := client.(navigation.WaypointFiler)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	count, err := filer.ImportWaypoints(context.Background(), []byte("<gpx/>"), navigation.WaypointFormatGPX, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, count, test.ShouldEqual, 2)
+	test.That(t, string(imported), test.ShouldEqual, "<gpx/>")
+	test.That(t, importedFormat, test.ShouldEqual, navigation.WaypointFormatGPX)
+
+	data, err := filer.ExportTraveledPath(context.Background(), navigation.WaypointFormatGPX, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(data), test.ShouldEqual, "<gpx/>")
+
+	// other commands still reach the service's DoCommand
+	resp, err := client.DoCommand(context.Background(), testutils.TestCommand)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["command"], test.ShouldEqual, testutils.TestCommand["command"])
+}
@@ -0,0 +1,412 @@
+// Package builtin implements a job scheduler which runs jobs against the machine's own resources.
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/scheduler"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+// Model is the model of the builtin job scheduler.
+var Model = resource.DefaultModelFamily.WithModel("job_scheduler")
+
+func init() {
+	resource.RegisterService(scheduler.API, Model, resource.Registration[resource.Resource, *Config]{
+		Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			newConf, err := resource.NativeConfig[*Config](conf)
+			if err != nil {
+				return nil, err
+			}
+			return newScheduler(conf.ResourceName(), newConf, deps, rdkutils.ClockFromContext(ctx), logger)
+		},
+	})
+}
+
+const (
+	defaultJobTimeout        = time.Minute
+	defaultFailureBackoff    = 10 * time.Second
+	defaultMaxFailureBackoff = time.Hour
+	defaultHistorySize       = 20
+)
+
+// The methods a job can call on its resource.
+const (
+	// MethodDoCommand sends the job's command to the resource's DoCommand. It is the default.
+	MethodDoCommand = "do_command"
+	// MethodReadings gets the readings of a sensor, movement sensor or other resource with readings.
+	MethodReadings = "readings"
+	// MethodStop stops an actuator such as a motor or base.
+	MethodStop = "stop"
+)
+
+// JobConfig configures a job. A job either calls a method of a resource or runs a script with a
+// shell service.
+//
+// For example, to calibrate a sensor every night and restart a process every 15 minutes on weekdays:
+//
+//	{"name": "calibrate", "schedule": "@daily", "resource": "imu", "command": {"calibrate": {}}}
+//	{"name": "restart", "schedule": "*/15 * * * 1-5", "shell_service": "shell", "script": "systemctl restart logger"}
+type JobConfig struct {
+	Name string `json:"name"`
+	// Schedule is a cron expression or descriptor, see scheduler.Schedule.
+	Schedule string `json:"schedule"`
+
+	// Resource is the short or fully qualified name of the resource the job calls.
+	Resource string `json:"resource,omitempty"`
+	// Method is the method called on the resource, "do_command" by default.
+	Method string `json:"method,omitempty"`
+	// Command is sent to the resource's DoCommand.
+	Command map[string]interface{} `json:"command,omitempty"`
+	// Extra is passed to the readings and stop methods.
+	Extra map[string]interface{} `json:"extra,omitempty"`
+
+	// ShellService is the name of the shell service Script is run with.
+	ShellService string `json:"shell_service,omitempty"`
+	// Script is run in a shell, and fails the job if it exits with a non-zero status.
+	Script string `json:"script,omitempty"`
+
+	// TimeoutSeconds bounds each run of the job, one minute by default.
+	TimeoutSeconds float64 `json:"timeout_seconds,omitempty"`
+	// JitterSeconds delays each scheduled run by a random time up to it, so that jobs of many
+	// machines on the same schedule don't all run at once.
+	JitterSeconds float64 `json:"jitter_seconds,omitempty"`
+	// FailureBackoffSeconds is how long scheduled runs are skipped for after a failure, 10 seconds
+	// by default. It doubles with each consecutive failure up to MaxFailureBackoffSeconds, one hour
+	// by default.
+	FailureBackoffSeconds    float64 `json:"failure_backoff_seconds,omitempty"`
+	MaxFailureBackoffSeconds float64 `json:"max_failure_backoff_seconds,omitempty"`
+	// HistorySize is how many of the job's most recent runs are kept, 20 by default.
+	HistorySize int `json:"history_size,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid and returns the job's dependencies.
+func (conf *JobConfig) Validate(path string) ([]string, error) {
+	if conf.Name == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "name")
+	}
+	if _, err := scheduler.ParseSchedule(conf.Schedule); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	for field, value := range map[string]float64{
+		"timeout_seconds":             conf.TimeoutSeconds,
+		"jitter_seconds":              conf.JitterSeconds,
+		"failure_backoff_seconds":     conf.FailureBackoffSeconds,
+		"max_failure_backoff_seconds": conf.MaxFailureBackoffSeconds,
+	} {
+		if value < 0 {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf("%s cannot be negative", field))
+		}
+	}
+	if conf.HistorySize < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("history_size cannot be negative"))
+	}
+
+	switch {
+	case conf.Resource != "" && conf.ShellService != "":
+		return nil, resource.NewConfigValidationError(path, errors.New("a job cannot have both a resource and a shell_service"))
+	case conf.Resource != "":
+		switch conf.Method {
+		case "", MethodDoCommand:
+			if len(conf.Command) == 0 {
+				return nil, resource.NewConfigValidationFieldRequiredError(path, "command")
+			}
+		case MethodReadings, MethodStop:
+		default:
+			return nil, resource.NewConfigValidationError(path, errors.Errorf(
+				"method must be one of %q, %q or %q, got %q", MethodDoCommand, MethodReadings, MethodStop, conf.Method))
+		}
+		return []string{conf.Resource}, nil
+	case conf.ShellService != "":
+		if conf.Script == "" {
+			return nil, resource.NewConfigValidationFieldRequiredError(path, "script")
+		}
+		return []string{conf.ShellService}, nil
+	default:
+		return nil, resource.NewConfigValidationError(path, errors.New("a job needs a resource or a shell_service"))
+	}
+}
+
+func (conf *JobConfig) timeout() time.Duration {
+	if conf.TimeoutSeconds == 0 {
+		return defaultJobTimeout
+	}
+	return secondsToDuration(conf.TimeoutSeconds)
+}
+
+// backoff returns how long scheduled runs are skipped for after `failures` consecutive failures.
+func (conf *JobConfig) backoff(failures int) time.Duration {
+	if failures == 0 {
+		return 0
+	}
+	backoff, maxBackoff := defaultFailureBackoff, defaultMaxFailureBackoff
+	if conf.FailureBackoffSeconds > 0 {
+		backoff = secondsToDuration(conf.FailureBackoffSeconds)
+	}
+	if conf.MaxFailureBackoffSeconds > 0 {
+		maxBackoff = secondsToDuration(conf.MaxFailureBackoffSeconds)
+	}
+	for i := 1; i < failures && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
+
+func (conf *JobConfig) historySize() int {
+	if conf.HistorySize == 0 {
+		return defaultHistorySize
+	}
+	return conf.HistorySize
+}
+
+func secondsToDuration(secs float64) time.Duration {
+	return time.Duration(secs * float64(time.Second))
+}
+
+// Config is the config of the builtin job scheduler.
+type Config struct {
+	Jobs []JobConfig `json:"jobs"`
+}
+
+// Validate ensures all parts of the config are valid and returns the resources its jobs use.
+func (conf *Config) Validate(path string) ([]string, error) {
+	var deps []string
+	names := map[string]bool{}
+	for i, job := range conf.Jobs {
+		jobDeps, err := job.Validate(fmt.Sprintf("%s.jobs.%d", path, i))
+		if err != nil {
+			return nil, err
+		}
+		if names[job.Name] {
+			return nil, resource.NewConfigValidationError(path, errors.Errorf("duplicate job name %q", job.Name))
+		}
+		names[job.Name] = true
+		deps = append(deps, jobDeps...)
+	}
+	return deps, nil
+}
+
+type jobScheduler struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	clk     clock.Clock
+	logger  logging.Logger
+	jobs    map[string]*job
+	order   []string
+	workers *goutils.StoppableWorkers
+}
+
+func newScheduler(
+	name resource.Name,
+	conf *Config,
+	deps resource.Dependencies,
+	clk clock.Clock,
+	logger logging.Logger,
+) (scheduler.Scheduler, error) {
+	s := &jobScheduler{
+		Named:  name.AsNamed(),
+		clk:    clk,
+		logger: logger,
+		jobs:   map[string]*job{},
+	}
+	for _, jobConf := range conf.Jobs {
+		j, err := newJob(jobConf, deps, clk, logger.Sublogger(jobConf.Name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "job %q", jobConf.Name)
+		}
+		s.jobs[jobConf.Name] = j
+		s.order = append(s.order, jobConf.Name)
+	}
+	s.workers = goutils.NewBackgroundStoppableWorkers()
+	for _, name := range s.order {
+		s.workers.Add(s.jobs[name].runOnSchedule)
+	}
+	return s, nil
+}
+
+// job is a configured job and the state of its runs.
+type job struct {
+	conf     JobConfig
+	schedule *scheduler.Schedule
+	call     func(ctx context.Context) (map[string]interface{}, error)
+	clk      clock.Clock
+	logger   logging.Logger
+
+	// runMu is held while the job runs, so runs never overlap.
+	runMu sync.Mutex
+
+	mu       sync.Mutex
+	running  bool
+	nextRun  time.Time
+	failures int
+	lastEnd  time.Time
+	history  []scheduler.JobRun
+}
+
+// runOnSchedule runs the job at each time of its schedule until ctx is done.
+func (j *job) runOnSchedule(ctx context.Context) {
+	scheduled := j.clk.Now()
+	for {
+		next, ok := j.next(scheduled)
+		if !ok {
+			j.logger.Warnw("job's schedule has no more times to run at", "schedule", j.conf.Schedule)
+			return
+		}
+		scheduled = next
+		runAt := next
+		if j.conf.JitterSeconds > 0 {
+			//nolint:gosec
+			runAt = runAt.Add(time.Duration(rand.Float64() * j.conf.JitterSeconds * float64(time.Second)))
+		}
+		j.mu.Lock()
+		j.nextRun = runAt
+		j.mu.Unlock()
+
+		timer := j.clk.Timer(runAt.Sub(j.clk.Now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		j.run(ctx, false)
+	}
+}
+
+// next returns the first time of the job's schedule after `after` that is not within the backoff
+// of its last failure.
+func (j *job) next(after time.Time) (time.Time, bool) {
+	j.mu.Lock()
+	notBefore := j.lastEnd.Add(j.conf.backoff(j.failures))
+	j.mu.Unlock()
+	if now := j.clk.Now(); after.Before(now) {
+		// don't catch up on times missed while the last run was going
+		after = now
+	}
+	for {
+		next := j.schedule.Next(after)
+		if next.IsZero() {
+			return next, false
+		}
+		if !next.Before(notBefore) {
+			return next, true
+		}
+		after = next
+	}
+}
+
+// run runs the job and records its run.
+func (j *job) run(ctx context.Context, manual bool) scheduler.JobRun {
+	j.runMu.Lock()
+	defer j.runMu.Unlock()
+	j.mu.Lock()
+	j.running = true
+	j.mu.Unlock()
+
+	run := scheduler.JobRun{Job: j.conf.Name, Start: j.clk.Now(), Manual: manual}
+	callCtx, cancel := context.WithTimeout(ctx, j.conf.timeout())
+	result, err := j.call(callCtx)
+	cancel()
+	run.End = j.clk.Now()
+	run.Result = result
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.running = false
+	j.lastEnd = run.End
+	if err != nil {
+		j.failures++
+		j.logger.CWarnw(ctx, "job failed", "error", err, "consecutive_failures", j.failures)
+	} else {
+		j.failures = 0
+		j.logger.CDebugw(ctx, "job succeeded", "duration", run.End.Sub(run.Start))
+	}
+	j.history = append(j.history, run)
+	if extra := len(j.history) - j.conf.historySize(); extra > 0 {
+		j.history = append([]scheduler.JobRun(nil), j.history[extra:]...)
+	}
+	return run
+}
+
+func (j *job) status() scheduler.JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := scheduler.JobStatus{
+		Name:                j.conf.Name,
+		Schedule:            j.conf.Schedule,
+		NextRun:             j.nextRun,
+		Running:             j.running,
+		ConsecutiveFailures: j.failures,
+	}
+	if len(j.history) > 0 {
+		lastRun := j.history[len(j.history)-1]
+		status.LastRun = &lastRun
+	}
+	return status
+}
+
+func (s *jobScheduler) job(name string) (*job, error) {
+	j, ok := s.jobs[name]
+	if !ok {
+		return nil, errors.Errorf("no job named %q", name)
+	}
+	return j, nil
+}
+
+func (s *jobScheduler) Jobs(ctx context.Context) ([]scheduler.JobStatus, error) {
+	jobs := make([]scheduler.JobStatus, 0, len(s.order))
+	for _, name := range s.order {
+		jobs = append(jobs, s.jobs[name].status())
+	}
+	return jobs, nil
+}
+
+func (s *jobScheduler) History(ctx context.Context, name string) ([]scheduler.JobRun, error) {
+	j, err := s.job(name)
+	if err != nil {
+		return nil, err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]scheduler.JobRun(nil), j.history...), nil
+}
+
+func (s *jobScheduler) RunJob(ctx context.Context, name string) (scheduler.JobRun, error) {
+	j, err := s.job(name)
+	if err != nil {
+		return scheduler.JobRun{}, err
+	}
+	return j.run(ctx, true), nil
+}
+
+func (s *jobScheduler) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	resp, handled, err := scheduler.HandleDoCommand(ctx, s, cmd)
+	if handled {
+		return resp, err
+	}
+	return nil, resource.ErrDoUnimplemented
+}
+
+func (s *jobScheduler) Close(ctx context.Context) error {
+	s.workers.Stop()
+	return nil
+}
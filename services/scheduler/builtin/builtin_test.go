@@ -0,0 +1,234 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/scheduler"
+	"go.viam.com/rdk/services/shell"
+	shellbuiltin "go.viam.com/rdk/services/shell/builtin"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestConfigValidate(t *testing.T) {
+	conf := &Config{Jobs: []JobConfig{
+		{Name: "calibrate", Schedule: "@daily", Resource: "imu", Command: map[string]interface{}{"calibrate": true}},
+		{Name: "log", Schedule: "*/5 * * * *", Resource: "rdk:component:sensor/temp", Method: MethodReadings},
+		{Name: "restart", Schedule: "0 3 * * 1-5", ShellService: "shell", Script: "systemctl restart logger"},
+	}}
+	deps, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"imu", "rdk:component:sensor/temp", "shell"})
+
+	for _, tc := range []struct {
+		job      JobConfig
+		expected string
+	}{
+		{JobConfig{Schedule: "@daily", Resource: "imu", Method: MethodStop}, "name"},
+		{JobConfig{Name: "a", Schedule: "@often", Resource: "imu", Method: MethodStop}, "invalid schedule"},
+		{JobConfig{Name: "a", Schedule: "@daily"}, "needs a resource or a shell_service"},
+		{JobConfig{Name: "a", Schedule: "@daily", Resource: "imu", ShellService: "shell"}, "cannot have both"},
+		{JobConfig{Name: "a", Schedule: "@daily", Resource: "imu"}, "command"},
+		{JobConfig{Name: "a", Schedule: "@daily", Resource: "imu", Method: "move"}, "method must be one of"},
+		{JobConfig{Name: "a", Schedule: "@daily", ShellService: "shell"}, "script"},
+		{JobConfig{Name: "a", Schedule: "@daily", Resource: "imu", Method: MethodStop, JitterSeconds: -1}, "jitter_seconds"},
+	} {
+		_, err := (&Config{Jobs: []JobConfig{tc.job}}).Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.expected)
+	}
+
+	stop := JobConfig{Name: "a", Schedule: "@daily", Resource: "imu", Method: MethodStop}
+	_, err = (&Config{Jobs: []JobConfig{stop, stop}}).Validate("path")
+	test.That(t, err.Error(), test.ShouldContainSubstring, `duplicate job name "a"`)
+}
+
+func TestBackoff(t *testing.T) {
+	conf := JobConfig{FailureBackoffSeconds: 1, MaxFailureBackoffSeconds: 5}
+	test.That(t, conf.backoff(0), test.ShouldEqual, 0)
+	test.That(t, conf.backoff(1), test.ShouldEqual, time.Second)
+	test.That(t, conf.backoff(2), test.ShouldEqual, 2*time.Second)
+	test.That(t, conf.backoff(3), test.ShouldEqual, 4*time.Second)
+	test.That(t, conf.backoff(4), test.ShouldEqual, 5*time.Second)
+	test.That(t, (&JobConfig{}).backoff(100), test.ShouldEqual, time.Hour)
+}
+
+// waitForNextRun waits for the job to be scheduled after `after` and returns when it runs.
+func waitForNextRun(t *testing.T, s scheduler.Scheduler, job string, after time.Time) time.Time {
+	t.Helper()
+	var next time.Time
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		jobs, err := s.Jobs(context.Background())
+		test.That(tb, err, test.ShouldBeNil)
+		for _, j := range jobs {
+			if j.Name == job {
+				next = j.NextRun
+			}
+		}
+		test.That(tb, next.After(after), test.ShouldBeTrue)
+	})
+	return next
+}
+
+func TestScheduler(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	clk := clock.NewMock()
+
+	var commands atomic.Int64
+	imu := inject.NewSensor("imu")
+	imu.DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"calibrated": commands.Add(1)}, nil
+	}
+	imu.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"temp": 21.5}, nil
+	}
+	var stops atomic.Int64
+	pump := inject.NewMotor("pump")
+	pump.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		stops.Add(1)
+		return errors.New("stuck")
+	}
+	deps := resource.Dependencies{sensor.Named("imu"): imu, motor.Named("pump"): pump}
+
+	s, err := newScheduler(scheduler.Named("jobs"), &Config{Jobs: []JobConfig{
+		{Name: "calibrate", Schedule: "@every 10s", Resource: "imu", Command: map[string]interface{}{"calibrate": true}, HistorySize: 2},
+		{Name: "read", Schedule: "@hourly", Resource: "rdk:component:sensor/imu", Method: MethodReadings},
+	}}, deps, clk, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer s.Close(ctx)
+
+	t.Run("jobs run on their schedules", func(t *testing.T) {
+		start := clk.Now()
+		next := waitForNextRun(t, s, "calibrate", start)
+		test.That(t, next, test.ShouldEqual, start.Add(10*time.Second))
+		clk.Add(10 * time.Second)
+		waitForNextRun(t, s, "calibrate", next)
+		test.That(t, commands.Load(), test.ShouldEqual, 1)
+
+		runs, err := s.History(ctx, "calibrate")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, runs, test.ShouldHaveLength, 1)
+		test.That(t, runs[0].Succeeded(), test.ShouldBeTrue)
+		test.That(t, runs[0].Manual, test.ShouldBeFalse)
+		test.That(t, runs[0].Result, test.ShouldResemble, map[string]interface{}{"calibrated": int64(1)})
+	})
+
+	t.Run("jobs can be run now and keep a bounded history", func(t *testing.T) {
+		run, err := s.RunJob(ctx, "read")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, run.Manual, test.ShouldBeTrue)
+		test.That(t, run.Result, test.ShouldResemble, map[string]interface{}{"temp": 21.5})
+
+		for i := 0; i < 3; i++ {
+			_, err := s.RunJob(ctx, "calibrate")
+			test.That(t, err, test.ShouldBeNil)
+		}
+		runs, err := s.History(ctx, "calibrate")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, runs, test.ShouldHaveLength, 2)
+		test.That(t, runs[1].Result["calibrated"], test.ShouldEqual, commands.Load())
+
+		_, err = s.RunJob(ctx, "missing")
+		test.That(t, err, test.ShouldBeError, `no job named "missing"`)
+	})
+
+	t.Run("jobs are available over DoCommand", func(t *testing.T) {
+		// a resource without the scheduler's methods, like the client of a remote scheduler
+		client := scheduler.FromResource(struct{ resource.Resource }{s})
+		jobs, err := client.Jobs(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, jobs, test.ShouldHaveLength, 2)
+		test.That(t, jobs[0].Name, test.ShouldEqual, "calibrate")
+		test.That(t, jobs[0].Schedule, test.ShouldEqual, "@every 10s")
+		test.That(t, jobs[0].LastRun, test.ShouldNotBeNil)
+
+		run, err := client.RunJob(ctx, "read")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, run.Manual, test.ShouldBeTrue)
+		test.That(t, run.Result, test.ShouldResemble, map[string]interface{}{"temp": 21.5})
+		test.That(t, run.End.IsZero(), test.ShouldBeFalse)
+
+		runs, err := client.History(ctx, "read")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, runs, test.ShouldHaveLength, 2)
+	})
+
+	t.Run("failing jobs back off", func(t *testing.T) {
+		clk := clock.NewMock()
+		s, err := newScheduler(scheduler.Named("jobs"), &Config{Jobs: []JobConfig{
+			{Name: "stop", Schedule: "@every 1s", Resource: "pump", Method: MethodStop, FailureBackoffSeconds: 5},
+		}}, deps, clk, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Close(ctx)
+
+		clk.Add(waitForNextRun(t, s, "stop", clk.Now()).Sub(clk.Now()))
+		// the first failure skips runs for 5 seconds, and the second for 10
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, stops.Load(), test.ShouldBeGreaterThanOrEqualTo, 1)
+		})
+		failedAt := clk.Now()
+		next := waitForNextRun(t, s, "stop", failedAt)
+		test.That(t, next.Sub(failedAt), test.ShouldBeBetweenOrEqual, 5*time.Second, 6*time.Second)
+		clk.Add(next.Sub(clk.Now()))
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, stops.Load(), test.ShouldEqual, 2)
+		})
+		next2 := waitForNextRun(t, s, "stop", next)
+		test.That(t, next2.Sub(next), test.ShouldBeBetweenOrEqual, 10*time.Second, 11*time.Second)
+
+		jobs, err := s.Jobs(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, jobs[0].ConsecutiveFailures, test.ShouldEqual, 2)
+		test.That(t, jobs[0].LastRun.Error, test.ShouldEqual, "stuck")
+	})
+
+	_, err = newScheduler(scheduler.Named("jobs"), &Config{Jobs: []JobConfig{
+		{Name: "read", Schedule: "@hourly", Resource: "pump", Method: MethodReadings},
+	}}, deps, clk, logger)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `"pump" has no readings`)
+}
+
+func TestScriptJob(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell not supported on windows")
+	}
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	sh, err := shellbuiltin.NewBuiltIn(shell.Named("shell"), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer sh.Close(ctx)
+
+	s, err := newScheduler(scheduler.Named("jobs"), &Config{Jobs: []JobConfig{
+		{Name: "ok", Schedule: "@daily", ShellService: "shell", Script: "echo hello"},
+		{Name: "fail", Schedule: "@daily", ShellService: "shell", Script: "false"},
+	}}, resource.Dependencies{shell.Named("shell"): sh}, clock.New(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer s.Close(ctx)
+
+	run, err := s.RunJob(ctx, "ok")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, run.Error, test.ShouldBeEmpty)
+	test.That(t, run.Result["exit_status"], test.ShouldEqual, 0)
+	test.That(t, run.Result["output"], test.ShouldContainSubstring, "hello")
+
+	run, err = s.RunJob(ctx, "fail")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, run.Error, test.ShouldEqual, "script exited with status 1")
+	test.That(t, run.Result["exit_status"], test.ShouldEqual, 1)
+}
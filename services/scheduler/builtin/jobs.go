@@ -0,0 +1,158 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/protoutils"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/scheduler"
+	"go.viam.com/rdk/services/shell"
+)
+
+const (
+	// scriptExitMarker is echoed with the exit status of a script after it runs, as shell services
+	// only stream a terminal's output.
+	scriptExitMarker = "__viam_job_exit="
+	// maxScriptOutput is how much of the end of a script's output is kept in its run's result.
+	maxScriptOutput = 16 << 10
+)
+
+var scriptExitPattern = regexp.MustCompile(scriptExitMarker + `(\d+)`)
+
+func newJob(conf JobConfig, deps resource.Dependencies, clk clock.Clock, logger logging.Logger) (*job, error) {
+	schedule, err := scheduler.ParseSchedule(conf.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	j := &job{conf: conf, schedule: schedule, clk: clk, logger: logger}
+	if conf.ShellService != "" {
+		sh, err := resource.FromDependencies[shell.Service](deps, shell.Named(conf.ShellService))
+		if err != nil {
+			return nil, err
+		}
+		j.call = func(ctx context.Context) (map[string]interface{}, error) {
+			return runScript(ctx, sh, conf.Script)
+		}
+		return j, nil
+	}
+
+	res, err := findDependency(deps, conf.Resource)
+	if err != nil {
+		return nil, err
+	}
+	switch conf.Method {
+	case "", MethodDoCommand:
+		j.call = func(ctx context.Context) (map[string]interface{}, error) {
+			return res.DoCommand(ctx, conf.Command)
+		}
+	case MethodReadings:
+		s, ok := res.(resource.Sensor)
+		if !ok {
+			return nil, errors.Errorf("%q has no readings", conf.Resource)
+		}
+		j.call = func(ctx context.Context) (map[string]interface{}, error) {
+			readings, err := s.Readings(ctx, conf.Extra)
+			if err != nil {
+				return nil, err
+			}
+			// readings such as geo points are kept as they would be sent to a client
+			encoded, err := protoutils.ReadingGoToProto(readings)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[string]interface{}, len(encoded))
+			for k, v := range encoded {
+				result[k] = v.AsInterface()
+			}
+			return result, nil
+		}
+	case MethodStop:
+		a, ok := res.(resource.Actuator)
+		if !ok {
+			return nil, errors.Errorf("%q cannot be stopped", conf.Resource)
+		}
+		j.call = func(ctx context.Context) (map[string]interface{}, error) {
+			return nil, a.Stop(ctx, conf.Extra)
+		}
+	}
+	return j, nil
+}
+
+// findDependency returns the dependency of the given short or fully qualified name.
+func findDependency(deps resource.Dependencies, name string) (resource.Resource, error) {
+	if fullName, err := resource.NewFromString(name); err == nil {
+		return deps.Lookup(fullName)
+	}
+	var found resource.Resource
+	for depName, res := range deps {
+		if depName.ShortName() != name {
+			continue
+		}
+		if found != nil {
+			return nil, errors.Errorf("more than one resource is named %q, use its fully qualified name", name)
+		}
+		found = res
+	}
+	if found == nil {
+		return nil, errors.Errorf("resource %q not found", name)
+	}
+	return found, nil
+}
+
+// runScript runs `script` in a shell of `sh` and returns its output and exit status.
+func runScript(ctx context.Context, sh shell.Service, script string) (map[string]interface{}, error) {
+	// the shell ends when the script does or the run is canceled
+	shellCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	input, _, output, err := sh.Shell(shellCtx, nil)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case input <- fmt.Sprintf("%s\necho %s$?\nexit\n", script, scriptExitMarker):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var out []byte
+	for done := false; !done; {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case o, ok := <-output:
+			if !ok || o.EOF {
+				done = true
+				continue
+			}
+			out = append(out, o.Output...)
+			out = append(out, o.Error...)
+			if len(out) > 2*maxScriptOutput {
+				out = append([]byte(nil), out[len(out)-maxScriptOutput:]...)
+			}
+		}
+	}
+	if len(out) > maxScriptOutput {
+		out = out[len(out)-maxScriptOutput:]
+	}
+
+	matches := scriptExitPattern.FindAllSubmatch(out, -1)
+	if len(matches) == 0 {
+		return nil, errors.New("script ended without reporting its exit status")
+	}
+	status, err := strconv.Atoi(string(matches[len(matches)-1][1]))
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{"output": string(out), "exit_status": status}
+	if status != 0 {
+		return result, errors.Errorf("script exited with status %d", status)
+	}
+	return result, nil
+}
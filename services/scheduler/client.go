@@ -0,0 +1,221 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// The scheduler commands are carried over DoCommand using the following reserved keys.
+const (
+	jobsKey                = "jobs"
+	historyKey             = "job_history"
+	runJobKey              = "run_job"
+	jobKey                 = "job"
+	runsKey                = "runs"
+	runKey                 = "run"
+	nameKey                = "name"
+	scheduleKey            = "schedule"
+	nextRunKey             = "next_run"
+	runningKey             = "running"
+	consecutiveFailuresKey = "consecutive_failures"
+	lastRunKey             = "last_run"
+	startKey               = "start"
+	endKey                 = "end"
+	manualKey              = "manual"
+	resultKey              = "result"
+	errorKey               = "error"
+)
+
+// client implements Scheduler over the DoCommand of a resource that does not implement it, such as
+// the generic client of a remote scheduler.
+type client struct {
+	resource.Resource
+}
+
+// NewClientFromResource returns a Scheduler calling `res` over DoCommand.
+func NewClientFromResource(res resource.Resource) Scheduler {
+	return &client{Resource: res}
+}
+
+func (c *client) Jobs(ctx context.Context) ([]JobStatus, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{jobsKey: map[string]interface{}{}})
+	if err != nil {
+		return nil, err
+	}
+	list, ok := resp[jobsKey].([]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected %q in response, got %v", jobsKey, resp)
+	}
+	jobs := make([]JobStatus, 0, len(list))
+	for _, raw := range list {
+		encoded, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%q entries must be objects", jobsKey)
+		}
+		jobs = append(jobs, jobStatusFromMap(encoded))
+	}
+	return jobs, nil
+}
+
+func (c *client) History(ctx context.Context, job string) ([]JobRun, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{historyKey: map[string]interface{}{jobKey: job}})
+	if err != nil {
+		return nil, err
+	}
+	list, ok := resp[runsKey].([]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected %q in response, got %v", runsKey, resp)
+	}
+	runs := make([]JobRun, 0, len(list))
+	for _, raw := range list {
+		encoded, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%q entries must be objects", runsKey)
+		}
+		runs = append(runs, jobRunFromMap(encoded))
+	}
+	return runs, nil
+}
+
+func (c *client) RunJob(ctx context.Context, job string) (JobRun, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{runJobKey: map[string]interface{}{jobKey: job}})
+	if err != nil {
+		return JobRun{}, err
+	}
+	encoded, ok := resp[runKey].(map[string]interface{})
+	if !ok {
+		return JobRun{}, errors.Errorf("expected %q in response, got %v", runKey, resp)
+	}
+	return jobRunFromMap(encoded), nil
+}
+
+// HandleDoCommand handles the reserved scheduler DoCommand keys. Models call it first from their
+// DoCommand, and handle `cmd` themselves if it returns false.
+func HandleDoCommand(ctx context.Context, s Scheduler, cmd map[string]interface{}) (map[string]interface{}, bool, error) {
+	if _, ok := cmd[jobsKey]; ok {
+		jobs, err := s.Jobs(ctx)
+		if err != nil {
+			return nil, true, err
+		}
+		encoded := make([]interface{}, 0, len(jobs))
+		for _, job := range jobs {
+			encoded = append(encoded, jobStatusToMap(job))
+		}
+		return map[string]interface{}{jobsKey: encoded}, true, nil
+	}
+	if payload, ok := cmd[historyKey]; ok {
+		job, err := jobArg(historyKey, payload)
+		if err != nil {
+			return nil, true, err
+		}
+		runs, err := s.History(ctx, job)
+		if err != nil {
+			return nil, true, err
+		}
+		encoded := make([]interface{}, 0, len(runs))
+		for _, run := range runs {
+			encoded = append(encoded, jobRunToMap(run))
+		}
+		return map[string]interface{}{runsKey: encoded}, true, nil
+	}
+	if payload, ok := cmd[runJobKey]; ok {
+		job, err := jobArg(runJobKey, payload)
+		if err != nil {
+			return nil, true, err
+		}
+		run, err := s.RunJob(ctx, job)
+		if err != nil {
+			return nil, true, err
+		}
+		return map[string]interface{}{runKey: jobRunToMap(run)}, true, nil
+	}
+	return nil, false, nil
+}
+
+func jobArg(key string, payload interface{}) (string, error) {
+	args, ok := payload.(map[string]interface{})
+	if !ok {
+		return "", errors.Errorf("%q must be an object", key)
+	}
+	job, ok := args[jobKey].(string)
+	if !ok || job == "" {
+		return "", errors.Errorf("%q needs a %q", key, jobKey)
+	}
+	return job, nil
+}
+
+func jobStatusToMap(job JobStatus) map[string]interface{} {
+	encoded := map[string]interface{}{
+		nameKey:                job.Name,
+		scheduleKey:            job.Schedule,
+		nextRunKey:             timeToString(job.NextRun),
+		runningKey:             job.Running,
+		consecutiveFailuresKey: job.ConsecutiveFailures,
+	}
+	if job.LastRun != nil {
+		encoded[lastRunKey] = jobRunToMap(*job.LastRun)
+	}
+	return encoded
+}
+
+func jobStatusFromMap(encoded map[string]interface{}) JobStatus {
+	job := JobStatus{}
+	job.Name, _ = encoded[nameKey].(string)         //nolint:errcheck
+	job.Schedule, _ = encoded[scheduleKey].(string) //nolint:errcheck
+	job.NextRun = timeFromInterface(encoded[nextRunKey])
+	job.Running, _ = encoded[runningKey].(bool)              //nolint:errcheck
+	failures, _ := encoded[consecutiveFailuresKey].(float64) //nolint:errcheck
+	job.ConsecutiveFailures = int(failures)
+	if lastRun, ok := encoded[lastRunKey].(map[string]interface{}); ok {
+		run := jobRunFromMap(lastRun)
+		job.LastRun = &run
+	}
+	return job
+}
+
+func jobRunToMap(run JobRun) map[string]interface{} {
+	encoded := map[string]interface{}{
+		jobKey:    run.Job,
+		startKey:  timeToString(run.Start),
+		endKey:    timeToString(run.End),
+		manualKey: run.Manual,
+	}
+	if run.Result != nil {
+		encoded[resultKey] = run.Result
+	}
+	if run.Error != "" {
+		encoded[errorKey] = run.Error
+	}
+	return encoded
+}
+
+func jobRunFromMap(encoded map[string]interface{}) JobRun {
+	run := JobRun{}
+	run.Job, _ = encoded[jobKey].(string) //nolint:errcheck
+	run.Start = timeFromInterface(encoded[startKey])
+	run.End = timeFromInterface(encoded[endKey])
+	run.Manual, _ = encoded[manualKey].(bool)                   //nolint:errcheck
+	run.Result, _ = encoded[resultKey].(map[string]interface{}) //nolint:errcheck
+	run.Error, _ = encoded[errorKey].(string)                   //nolint:errcheck
+	return run
+}
+
+func timeToString(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func timeFromInterface(raw interface{}) time.Time {
+	s, _ := raw.(string) //nolint:errcheck
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
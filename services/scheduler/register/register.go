@@ -0,0 +1,7 @@
+// Package register registers all relevant job schedulers.
+package register
+
+import (
+	// register job schedulers.
+	_ "go.viam.com/rdk/services/scheduler/builtin"
+)
@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// scheduleSearchLimit bounds how far ahead a schedule is searched for its next time, so that a
+// schedule which never matches, such as the 31st of February, doesn't search forever.
+const scheduleSearchLimit = 5 * 366 * 24 * time.Hour
+
+// A Schedule is when a job runs. Schedules are written as standard five field cron expressions
+// of minute, hour, day of month, month and day of week, such as "30 2 * * 1-5" for 2:30 on
+// weekdays, or as one of the following descriptors:
+//
+//	@every <duration>  at a fixed interval, such as "@every 90s"
+//	@hourly            "0 * * * *"
+//	@daily             "0 0 * * *", also @midnight
+//	@weekly            "0 0 * * 0"
+//	@monthly           "0 0 1 * *"
+//	@yearly            "0 0 1 1 *", also @annually
+//
+// Fields are "*", numbers, ranges like "1-5", lists like "1,15", and steps like "*/10" or "0-30/5".
+// A day of week of 0 or 7 is Sunday. As in cron, a job whose day of month and day of week are both
+// restricted runs on days matching either.
+type Schedule struct {
+	every time.Duration
+
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	// anyDayOfMonth and anyDayOfWeek are whether the fields are "*", which decides how days are
+	// matched.
+	anyDayOfMonth, anyDayOfWeek bool
+}
+
+var scheduleDescriptors = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+type scheduleField struct {
+	name     string
+	min, max int
+}
+
+var scheduleFields = []scheduleField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseSchedule parses a cron expression or descriptor.
+func ParseSchedule(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid schedule %q", spec)
+		}
+		if every < time.Second {
+			return nil, errors.Errorf("invalid schedule %q: jobs can run at most every second", spec)
+		}
+		return &Schedule{every: every}, nil
+	}
+	if expr, ok := scheduleDescriptors[spec]; ok {
+		spec = expr
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != len(scheduleFields) {
+		return nil, errors.Errorf("invalid schedule %q: expected 5 fields of minute, hour, day of month, month and day of week", spec)
+	}
+	masks := make([]uint64, len(parts))
+	for i, part := range parts {
+		mask, err := parseScheduleField(part, scheduleFields[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid schedule %q", spec)
+		}
+		masks[i] = mask
+	}
+	s := &Schedule{
+		minute:        masks[0],
+		hour:          masks[1],
+		dayOfMonth:    masks[2],
+		month:         masks[3],
+		dayOfWeek:     masks[4],
+		anyDayOfMonth: parts[2] == "*",
+		anyDayOfWeek:  parts[4] == "*",
+	}
+	// Sunday is both 0 and 7
+	if s.dayOfWeek&(1<<7) != 0 {
+		s.dayOfWeek |= 1
+	}
+	return s, nil
+}
+
+// parseScheduleField returns the values a field matches as a bit mask.
+func parseScheduleField(spec string, field scheduleField) (uint64, error) {
+	var mask uint64
+	for _, item := range strings.Split(spec, ",") {
+		rangeSpec, stepSpec, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepSpec); err != nil || step <= 0 {
+				return 0, errors.Errorf("invalid %s step %q", field.name, stepSpec)
+			}
+		}
+		low, high := field.min, field.max
+		if rangeSpec != "*" {
+			lowSpec, highSpec, isRange := strings.Cut(rangeSpec, "-")
+			var err error
+			if low, err = strconv.Atoi(lowSpec); err != nil {
+				return 0, errors.Errorf("invalid %s %q", field.name, item)
+			}
+			high = low
+			if isRange {
+				if high, err = strconv.Atoi(highSpec); err != nil {
+					return 0, errors.Errorf("invalid %s %q", field.name, item)
+				}
+			} else if hasStep {
+				// "5/15" is every 15 from 5
+				high = field.max
+			}
+		}
+		if low < field.min || high > field.max || low > high {
+			return 0, errors.Errorf("%s %q is outside of %d-%d", field.name, item, field.min, field.max)
+		}
+		for v := low; v <= high; v += step {
+			mask |= 1 << v
+		}
+	}
+	return mask, nil
+}
+
+// Next returns the first time of the schedule after `after`, or the zero time if there is none.
+// The times of cron expressions are in the location of `after`.
+func (s *Schedule) Next(after time.Time) time.Time {
+	if s.every > 0 {
+		return after.Add(s.every)
+	}
+	t := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), after.Minute(), 0, 0, after.Location())
+	t = t.Add(time.Minute)
+	limit := after.Add(scheduleSearchLimit)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			// skip straight to the next matching minute of the hour, if any
+			rest := s.minute >> uint(t.Minute())
+			if rest == 0 {
+				t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			} else {
+				t = t.Add(time.Duration(bits.TrailingZeros64(rest)) * time.Minute)
+			}
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	dom := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dow := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dom && dow
+	}
+	return dom || dow
+}
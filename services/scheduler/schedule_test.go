@@ -0,0 +1,50 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/services/scheduler"
+)
+
+func TestSchedule(t *testing.T) {
+	// a Wednesday
+	start := time.Date(2024, 5, 15, 10, 17, 30, 0, time.UTC)
+	for _, tc := range []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, 5, 15, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 5, 15, 10, 30, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2024, 5, 15, 10, 25, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2024, 5, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2024, 5, 16, 2, 30, 0, 0, time.UTC)},
+		{"30 2 * * 1-5", time.Date(2024, 5, 16, 2, 30, 0, 0, time.UTC)},
+		{"0 9 * * 6,7", time.Date(2024, 5, 18, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 0", time.Date(2024, 5, 19, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		// the day of month or the day of week
+		{"0 0 1 * 5", time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 5, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, 5, 19, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", start.Add(90 * time.Second)},
+		// never
+		{"0 0 31 2 *", time.Time{}},
+	} {
+		t.Run(tc.spec, func(t *testing.T) {
+			s, err := scheduler.ParseSchedule(tc.spec)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, s.Next(start), test.ShouldEqual, tc.expected)
+		})
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@every 1ms", "@every soon"} {
+		_, err := scheduler.ParseSchedule(spec)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
@@ -0,0 +1,116 @@
+// Package scheduler defines a job scheduler, a service which runs configured jobs such as
+// DoCommands, sensor readings and shell scripts on cron-like schedules, so that routine tasks on a
+// machine don't need an external orchestrator.
+//
+// There is no scheduler proto, so schedulers are generic services whose typed methods are carried
+// over DoCommand. Models implement Scheduler and answer the reserved commands by calling
+// HandleDoCommand from their DoCommand.
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/generic"
+)
+
+// API is the resource API schedulers are served under.
+var API = generic.API
+
+// Named is a helper for getting the named scheduler's typed resource name.
+func Named(name string) resource.Name {
+	return resource.NewName(API, name)
+}
+
+// A Scheduler runs jobs on schedules and keeps a history of their runs.
+//
+// Jobs example:
+//
+//	myScheduler, err := scheduler.FromRobot(machine, "jobs")
+//	jobs, err := myScheduler.Jobs(context.Background())
+//	for _, job := range jobs {
+//		fmt.Printf("%s next runs at %v\n", job.Name, job.NextRun)
+//	}
+//
+// History example:
+//
+//	runs, err := myScheduler.History(context.Background(), "nightly-calibration")
+//
+// RunJob example:
+//
+//	// Run a job now rather than waiting for its schedule.
+//	run, err := myScheduler.RunJob(context.Background(), "nightly-calibration")
+type Scheduler interface {
+	resource.Resource
+
+	// Jobs returns the status of each job.
+	Jobs(ctx context.Context) ([]JobStatus, error)
+
+	// History returns the most recent runs of the named job, oldest first.
+	History(ctx context.Context, job string) ([]JobRun, error)
+
+	// RunJob runs the named job now, outside of its schedule, and returns its run. A job which is
+	// already running is not run again until it finishes.
+	RunJob(ctx context.Context, job string) (JobRun, error)
+}
+
+// JobStatus describes a job.
+type JobStatus struct {
+	Name     string
+	Schedule string
+	// NextRun is when the job is next run on its schedule, including any jitter and failure
+	// backoff.
+	NextRun time.Time
+	Running bool
+	// ConsecutiveFailures is how many runs of the job have failed since it last succeeded.
+	ConsecutiveFailures int
+	// LastRun is the job's most recent run, if it has run.
+	LastRun *JobRun
+}
+
+// JobRun is a run of a job.
+type JobRun struct {
+	Job   string
+	Start time.Time
+	End   time.Time
+	// Manual is whether the run was asked for with RunJob rather than being scheduled.
+	Manual bool
+	// Result is what the job returned, such as the response of a DoCommand.
+	Result map[string]interface{}
+	// Error is why the run failed, or empty if it succeeded.
+	Error string
+}
+
+// Succeeded returns whether the run succeeded.
+func (run JobRun) Succeeded() bool {
+	return run.Error == ""
+}
+
+// FromResource returns `res` as a Scheduler. Resources that do not implement Scheduler, such as
+// the clients of remote schedulers, are wrapped in a client that calls them over DoCommand.
+func FromResource(res resource.Resource) Scheduler {
+	if s, ok := res.(Scheduler); ok {
+		return s
+	}
+	return NewClientFromResource(res)
+}
+
+// FromDependencies is a helper for getting the named scheduler from a collection of dependencies.
+func FromDependencies(deps resource.Dependencies, name string) (Scheduler, error) {
+	res, err := resource.FromDependencies[resource.Resource](deps, Named(name))
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
+
+// FromRobot is a helper for getting the named scheduler from the given Robot.
+func FromRobot(r robot.Robot, name string) (Scheduler, error) {
+	res, err := generic.FromRobot(r, name)
+	if err != nil {
+		return nil, err
+	}
+	return FromResource(res), nil
+}
@@ -33,14 +33,18 @@ func init() {
 // NewBuiltIn returns a new shell service for the given robot.
 func NewBuiltIn(name resource.Name, logger logging.Logger) (shell.Service, error) {
 	return &builtIn{
-		Named:  name.AsNamed(),
-		logger: logger,
+		Named:                   name.AsNamed(),
+		ResumableFileTransferer: shell.NewLocalFileTransferer(true),
+		logger:                  logger,
 	}, nil
 }
 
 type builtIn struct {
 	resource.Named
 	resource.TriviallyReconfigurable
+	// ResumableFileTransferer transfers files relative to the home directory, like
+	// CopyFilesFromMachine.
+	shell.ResumableFileTransferer
 	logger                  logging.Logger
 	activeBackgroundWorkers sync.WaitGroup
 }
@@ -175,17 +179,17 @@ func (svc *builtIn) Shell(ctx context.Context, extra map[string]interface{}) (
 						return
 					}
 				} else {
-					if _, err := f.Write([]byte{4}); err != nil {
+					// a closed pty means the shell has already exited
+					if _, err := f.Write([]byte{4}); err != nil && !errors.Is(err, os.ErrClosed) {
 						svc.logger.CErrorw(ctx, "error writing EOT", "error", err)
-						return
 					}
 					return
 				}
 			case <-ctx.Done():
-				if _, err := f.Write([]byte{4}); err != nil {
+				if _, err := f.Write([]byte{4}); err != nil && !errors.Is(err, os.ErrClosed) {
 					svc.logger.CErrorw(ctx, "error writing EOT", "error", err)
-					return
 				}
+				return
 			}
 		}
 	})
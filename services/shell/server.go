@@ -205,5 +205,8 @@ func (server *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, ok, err := doFileTransferCommand(ctx, svc, req); ok {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, svc, req)
 }
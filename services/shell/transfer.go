@@ -0,0 +1,337 @@
+package shell
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"go.viam.com/utils"
+)
+
+// The CopyFiles streams can neither tell the sender how much of a file the receiver already has
+// nor verify what was received, so resumable transfers are made in checksummed chunks over these
+// reserved DoCommand commands instead. An interrupted transfer resumes from its last chunk.
+const (
+	// FileTransferStatCommand returns the FileTransferStat of a path.
+	FileTransferStatCommand = "file_transfer_stat"
+	// FileTransferWriteCommand appends a chunk to the partial upload of a path.
+	FileTransferWriteCommand = "file_transfer_write"
+	// FileTransferCommitCommand verifies the partial upload of a path and moves it into place.
+	FileTransferCommitCommand = "file_transfer_commit"
+	// FileTransferReadCommand reads a chunk of a path.
+	FileTransferReadCommand = "file_transfer_read"
+
+	fileTransferPathKey          = "path"
+	fileTransferChecksumKey      = "checksum"
+	fileTransferOffsetKey        = "offset"
+	fileTransferLengthKey        = "length"
+	fileTransferDataKey          = "data"
+	fileTransferSHA256Key        = "sha256"
+	fileTransferSizeKey          = "size"
+	fileTransferModeKey          = "mode"
+	fileTransferModTimeKey       = "mod_time"
+	fileTransferExistsKey        = "exists"
+	fileTransferIsDirKey         = "is_dir"
+	fileTransferPartialSizeKey   = "partial_size"
+	fileTransferPartialSHA256Key = "partial_sha256"
+)
+
+const (
+	// DefaultFileTransferChunkSize is the size of the chunks files are transferred in by default.
+	DefaultFileTransferChunkSize = 1 << 20
+	// MaxFileTransferChunkSize is the largest chunk that can be transferred, which keeps base64
+	// encoded chunks well under the RPC message size limit.
+	MaxFileTransferChunkSize = 8 << 20
+	// PartialFileTransferExt is the extension of files which are still being transferred.
+	PartialFileTransferExt = ".viam-part"
+)
+
+// FileTransferStat describes a path on a machine and any partial upload to it.
+type FileTransferStat struct {
+	Exists  bool
+	IsDir   bool
+	Size    int64
+	Mode    fs.FileMode
+	ModTime time.Time
+	// SHA256 is the hex encoded SHA-256 checksum of the file, if requested.
+	SHA256 string
+	// PartialSize is how much of an upload to the path has been received.
+	PartialSize int64
+	// PartialSHA256 is the hex encoded SHA-256 checksum of the partial upload, if requested.
+	PartialSHA256 string
+}
+
+// A ResumableFileTransferer transfers files to and from a machine in checksummed chunks, so that
+// an interrupted transfer can resume where it left off. See UploadFile and DownloadFile.
+//
+// ResumableFileTransferer example:
+//
+//	transferer, ok := myShell.(shell.ResumableFileTransferer)
+//	if ok {
+//		// Upload a file, resuming any earlier attempt to upload it.
+//		err := shell.UploadFile(context.Background(), transferer, "recording.bag", "~/recording.bag", shell.FileTransferOptions{})
+//	}
+type ResumableFileTransferer interface {
+	// StatFileTransfer describes the path and any partial upload to it, with their checksums if
+	// checksum is set.
+	StatFileTransfer(ctx context.Context, path string, checksum bool) (FileTransferStat, error)
+	// WriteFileChunk checks data against its hex encoded SHA-256 checksum and writes it to the
+	// partial upload to path at offset, which must be 0 to start over or the partial upload's size.
+	WriteFileChunk(ctx context.Context, path string, offset int64, data []byte, checksum string) error
+	// CommitFileTransfer checks the partial upload to path against the size and hex encoded SHA-256
+	// checksum of the complete file and moves it to path. A zero mode or mod time is not preserved.
+	CommitFileTransfer(ctx context.Context, path string, size int64, checksum string, mode fs.FileMode, modTime time.Time) error
+	// ReadFileChunk reads up to length bytes of path at offset and returns them with their hex
+	// encoded SHA-256 checksum. It returns no data at the end of the file.
+	ReadFileChunk(ctx context.Context, path string, offset int64, length int) ([]byte, string, error)
+}
+
+// FileTransferOptions are the options of UploadFile and DownloadFile.
+type FileTransferOptions struct {
+	// ChunkSize defaults to DefaultFileTransferChunkSize.
+	ChunkSize int
+	// Preserve the mode and modification time of the file.
+	Preserve bool
+	// Progress, if set, is called after each chunk with how much of the file has been transferred.
+	Progress func(transferred, total int64)
+}
+
+func (opts FileTransferOptions) chunkSize() int {
+	if opts.ChunkSize <= 0 {
+		return DefaultFileTransferChunkSize
+	}
+	return min(opts.ChunkSize, MaxFileTransferChunkSize)
+}
+
+func (opts FileTransferOptions) progress(transferred, total int64) {
+	if opts.Progress != nil {
+		opts.Progress(transferred, total)
+	}
+}
+
+// UploadFile uploads the local file at localPath to remotePath on the machine. It resumes a
+// partial upload of the same file, skips the upload if remotePath already has the same contents,
+// and only moves the file into place once its checksum is verified.
+func UploadFile(
+	ctx context.Context,
+	transferer ResumableFileTransferer,
+	localPath, remotePath string,
+	opts FileTransferOptions,
+) error {
+	//nolint:gosec
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer utils.UncheckedErrorFunc(f.Close)
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%q is a directory, only files can be transferred", localPath)
+	}
+	size := info.Size()
+	checksum, err := fileSHA256(f, size)
+	if err != nil {
+		return err
+	}
+
+	remote, err := transferer.StatFileTransfer(ctx, remotePath, true)
+	if err != nil {
+		return err
+	}
+	if remote.IsDir {
+		return fmt.Errorf("remote %q is a directory", remotePath)
+	}
+	if remote.Exists && remote.Size == size && remote.SHA256 == checksum {
+		opts.progress(size, size)
+		return nil
+	}
+
+	// only resume if what was received so far is the start of this file
+	var offset int64
+	if remote.PartialSize > 0 && remote.PartialSize <= size {
+		prefixChecksum, err := fileSHA256(f, remote.PartialSize)
+		if err != nil {
+			return err
+		}
+		if prefixChecksum == remote.PartialSHA256 {
+			offset = remote.PartialSize
+		}
+	}
+	opts.progress(offset, size)
+
+	buf := make([]byte, opts.chunkSize())
+	for offset < size || offset == 0 {
+		n, err := f.ReadAt(buf, offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if err := transferer.WriteFileChunk(ctx, remotePath, offset, buf[:n], sha256Hex(buf[:n])); err != nil {
+			return err
+		}
+		offset += int64(n)
+		opts.progress(offset, size)
+		if n == 0 {
+			break
+		}
+	}
+
+	var mode fs.FileMode
+	var modTime time.Time
+	if opts.Preserve {
+		mode, modTime = info.Mode(), info.ModTime()
+	}
+	return transferer.CommitFileTransfer(ctx, remotePath, size, checksum, mode, modTime)
+}
+
+// DownloadFile downloads remotePath on the machine to the local file at localPath. It resumes a
+// partial download of the same file, skips the download if localPath already has the same
+// contents, and only moves the file into place once its checksum is verified.
+func DownloadFile(
+	ctx context.Context,
+	transferer ResumableFileTransferer,
+	remotePath, localPath string,
+	opts FileTransferOptions,
+) error {
+	remote, err := transferer.StatFileTransfer(ctx, remotePath, true)
+	if err != nil {
+		return err
+	}
+	if !remote.Exists {
+		return fmt.Errorf("remote %q does not exist", remotePath)
+	}
+	if remote.IsDir {
+		return fmt.Errorf("remote %q is a directory, only files can be transferred", remotePath)
+	}
+	if local, err := os.Stat(localPath); err == nil {
+		if local.IsDir() {
+			return fmt.Errorf("%q is a directory", localPath)
+		}
+		if local.Size() == remote.Size {
+			if checksum, err := pathSHA256(localPath); err == nil && checksum == remote.SHA256 {
+				opts.progress(remote.Size, remote.Size)
+				return nil
+			}
+		}
+	}
+
+	// the partial download is named after the file's checksum, so a partial download of another
+	// version of the file is never resumed
+	partialPath := fmt.Sprintf("%s.%s%s", localPath, remote.SHA256[:min(len(remote.SHA256), 16)], PartialFileTransferExt)
+	//nolint:gosec
+	partial, err := os.OpenFile(partialPath, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return err
+	}
+	defer utils.UncheckedErrorFunc(partial.Close)
+	info, err := partial.Stat()
+	if err != nil {
+		return err
+	}
+	offset := info.Size()
+	if offset > remote.Size {
+		offset = 0
+	}
+	if err := partial.Truncate(offset); err != nil {
+		return err
+	}
+	opts.progress(offset, remote.Size)
+
+	for offset < remote.Size {
+		data, checksum, err := transferer.ReadFileChunk(ctx, remotePath, offset, opts.chunkSize())
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return fmt.Errorf("remote %q ended at %d bytes, expected %d", remotePath, offset, remote.Size)
+		}
+		if sha256Hex(data) != checksum {
+			return fmt.Errorf("chunk of %q at %d does not match its checksum", remotePath, offset)
+		}
+		if _, err := partial.WriteAt(data, offset); err != nil {
+			return err
+		}
+		offset += int64(len(data))
+		opts.progress(offset, remote.Size)
+	}
+	if err := partial.Sync(); err != nil {
+		return err
+	}
+
+	var mode fs.FileMode
+	var modTime time.Time
+	if opts.Preserve {
+		mode, modTime = remote.Mode, remote.ModTime
+	}
+	return commitPartialFile(partial, partialPath, localPath, remote.Size, remote.SHA256, mode, modTime)
+}
+
+// commitPartialFile verifies the partial file's size and checksum and moves it to path, giving it
+// mode and modTime if they are not zero. A partial file which fails verification is removed so the
+// next transfer starts over.
+func commitPartialFile(partial *os.File, partialPath, path string, size int64, checksum string, mode fs.FileMode, modTime time.Time) error {
+	actual, err := fileSHA256(partial, size)
+	if err != nil {
+		return err
+	}
+	info, err := partial.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() != size || actual != checksum {
+		utils.UncheckedError(os.Remove(partialPath))
+		return fmt.Errorf("transfer of %q does not match its checksum, it will start over if retried", path)
+	}
+	if mode == 0 {
+		mode = 0o640
+	}
+	if err := partial.Chmod(mode.Perm()); err != nil {
+		return err
+	}
+	if err := partial.Close(); err != nil && !errors.Is(err, fs.ErrClosed) {
+		return err
+	}
+	if err := os.Rename(partialPath, path); err != nil {
+		return err
+	}
+	if !modTime.IsZero() {
+		return os.Chtimes(path, time.Now(), modTime)
+	}
+	return nil
+}
+
+// fileSHA256 returns the hex encoded SHA-256 checksum of the first size bytes of f.
+func fileSHA256(f io.ReaderAt, size int64) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, io.NewSectionReader(f, 0, size)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func pathSHA256(path string) (string, error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer utils.UncheckedErrorFunc(f.Close)
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	return fileSHA256(f, info.Size())
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,135 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.viam.com/utils"
+)
+
+// A localFileTransferer is the ResumableFileTransferer of the local filesystem.
+type localFileTransferer struct {
+	relativeToHome bool
+}
+
+// NewLocalFileTransferer returns a ResumableFileTransferer of the local filesystem. Relative paths
+// are relative to the home directory if relativeToHome is set, otherwise the working directory.
+func NewLocalFileTransferer(relativeToHome bool) ResumableFileTransferer {
+	return localFileTransferer{relativeToHome: relativeToHome}
+}
+
+func (t localFileTransferer) StatFileTransfer(ctx context.Context, path string, checksum bool) (FileTransferStat, error) {
+	path, err := fixPeerPath(path, false, t.relativeToHome)
+	if err != nil {
+		return FileTransferStat{}, err
+	}
+	var stat FileTransferStat
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		stat.Exists, stat.IsDir = true, info.IsDir()
+		stat.Size, stat.Mode, stat.ModTime = info.Size(), info.Mode(), info.ModTime()
+		if checksum && !info.IsDir() {
+			if stat.SHA256, err = pathSHA256(path); err != nil {
+				return FileTransferStat{}, err
+			}
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return FileTransferStat{}, err
+	}
+
+	partialInfo, err := os.Stat(path + PartialFileTransferExt)
+	switch {
+	case err == nil:
+		stat.PartialSize = partialInfo.Size()
+		if checksum {
+			if stat.PartialSHA256, err = pathSHA256(path + PartialFileTransferExt); err != nil {
+				return FileTransferStat{}, err
+			}
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return FileTransferStat{}, err
+	}
+	return stat, nil
+}
+
+func (t localFileTransferer) WriteFileChunk(ctx context.Context, path string, offset int64, data []byte, checksum string) error {
+	path, err := fixPeerPath(path, false, t.relativeToHome)
+	if err != nil {
+		return err
+	}
+	if sha256Hex(data) != checksum {
+		return fmt.Errorf("chunk of %q at %d does not match its checksum", path, offset)
+	}
+	flags := os.O_CREATE | os.O_WRONLY
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	//nolint:gosec // this is from an authenticated/authorized connection
+	partial, err := os.OpenFile(path+PartialFileTransferExt, flags, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := partial.Stat()
+	if err != nil {
+		return multierr.Combine(err, partial.Close())
+	}
+	if info.Size() != offset {
+		return multierr.Combine(
+			fmt.Errorf("partial transfer of %q has %d bytes, not %d", path, info.Size(), offset), partial.Close())
+	}
+	if _, err := partial.WriteAt(data, offset); err != nil {
+		return multierr.Combine(err, partial.Close())
+	}
+	return partial.Close()
+}
+
+func (t localFileTransferer) CommitFileTransfer(
+	ctx context.Context, path string, size int64, checksum string, mode fs.FileMode, modTime time.Time,
+) error {
+	path, err := fixPeerPath(path, false, t.relativeToHome)
+	if err != nil {
+		return err
+	}
+	//nolint:gosec // this is from an authenticated/authorized connection
+	partial, err := os.OpenFile(path+PartialFileTransferExt, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer utils.UncheckedErrorFunc(func() error {
+		if err := partial.Close(); !errors.Is(err, fs.ErrClosed) {
+			return err
+		}
+		return nil
+	})
+	return commitPartialFile(partial, path+PartialFileTransferExt, path, size, checksum, mode, modTime)
+}
+
+func (t localFileTransferer) ReadFileChunk(ctx context.Context, path string, offset int64, length int) ([]byte, string, error) {
+	path, err := fixPeerPath(path, false, t.relativeToHome)
+	if err != nil {
+		return nil, "", err
+	}
+	if length <= 0 || length > MaxFileTransferChunkSize {
+		return nil, "", fmt.Errorf("chunk length must be between 1 and %d, got %d", MaxFileTransferChunkSize, length)
+	}
+	//nolint:gosec // this is from an authenticated/authorized connection
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer utils.UncheckedErrorFunc(f.Close)
+	data := make([]byte, length)
+	n, err := f.ReadAt(data, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, "", err
+	}
+	data = data[:n]
+	return data, sha256Hex(data), nil
+}
@@ -0,0 +1,189 @@
+package shell
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"time"
+
+	commonpb "go.viam.com/api/common/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// transfer_rpc implements the resumable file transfers of a remote machine over the shell
+// service's DoCommand.
+
+// StatFileTransfer describes path on the machine over DoCommand.
+func (c *client) StatFileTransfer(ctx context.Context, path string, checksum bool) (FileTransferStat, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		FileTransferStatCommand: true,
+		fileTransferPathKey:     path,
+		fileTransferChecksumKey: checksum,
+	})
+	if err != nil {
+		return FileTransferStat{}, err
+	}
+	return fileTransferStatFromMap(resp)
+}
+
+// WriteFileChunk writes a chunk of an upload to the machine over DoCommand.
+func (c *client) WriteFileChunk(ctx context.Context, path string, offset int64, data []byte, checksum string) error {
+	_, err := c.DoCommand(ctx, map[string]interface{}{
+		FileTransferWriteCommand: true,
+		fileTransferPathKey:      path,
+		fileTransferOffsetKey:    offset,
+		fileTransferDataKey:      base64.StdEncoding.EncodeToString(data),
+		fileTransferSHA256Key:    checksum,
+	})
+	return err
+}
+
+// CommitFileTransfer commits an upload to the machine over DoCommand.
+func (c *client) CommitFileTransfer(
+	ctx context.Context, path string, size int64, checksum string, mode fs.FileMode, modTime time.Time,
+) error {
+	cmd := map[string]interface{}{
+		FileTransferCommitCommand: true,
+		fileTransferPathKey:       path,
+		fileTransferSizeKey:       size,
+		fileTransferSHA256Key:     checksum,
+		fileTransferModeKey:       uint32(mode),
+	}
+	if !modTime.IsZero() {
+		cmd[fileTransferModTimeKey] = modTime.Format(time.RFC3339Nano)
+	}
+	_, err := c.DoCommand(ctx, cmd)
+	return err
+}
+
+// ReadFileChunk reads a chunk of a file on the machine over DoCommand.
+func (c *client) ReadFileChunk(ctx context.Context, path string, offset int64, length int) ([]byte, string, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		FileTransferReadCommand: true,
+		fileTransferPathKey:     path,
+		fileTransferOffsetKey:   offset,
+		fileTransferLengthKey:   length,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	encoded, _ := resp[fileTransferDataKey].(string)    //nolint:errcheck
+	checksum, _ := resp[fileTransferSHA256Key].(string) //nolint:errcheck
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, checksum, nil
+}
+
+func fileTransferStatToMap(stat FileTransferStat) map[string]interface{} {
+	m := map[string]interface{}{
+		fileTransferExistsKey:        stat.Exists,
+		fileTransferIsDirKey:         stat.IsDir,
+		fileTransferSizeKey:          stat.Size,
+		fileTransferModeKey:          uint32(stat.Mode),
+		fileTransferSHA256Key:        stat.SHA256,
+		fileTransferPartialSizeKey:   stat.PartialSize,
+		fileTransferPartialSHA256Key: stat.PartialSHA256,
+	}
+	if !stat.ModTime.IsZero() {
+		m[fileTransferModTimeKey] = stat.ModTime.Format(time.RFC3339Nano)
+	}
+	return m
+}
+
+func fileTransferStatFromMap(m map[string]interface{}) (FileTransferStat, error) {
+	var stat FileTransferStat
+	stat.Exists, _ = m[fileTransferExistsKey].(bool)                 //nolint:errcheck
+	stat.IsDir, _ = m[fileTransferIsDirKey].(bool)                   //nolint:errcheck
+	stat.SHA256, _ = m[fileTransferSHA256Key].(string)               //nolint:errcheck
+	stat.PartialSHA256, _ = m[fileTransferPartialSHA256Key].(string) //nolint:errcheck
+	size, _ := m[fileTransferSizeKey].(float64)                      //nolint:errcheck
+	partialSize, _ := m[fileTransferPartialSizeKey].(float64)        //nolint:errcheck
+	mode, _ := m[fileTransferModeKey].(float64)                      //nolint:errcheck
+	stat.Size, stat.PartialSize, stat.Mode = int64(size), int64(partialSize), fs.FileMode(mode)
+	modTime, err := optionalTime(m)
+	if err != nil {
+		return FileTransferStat{}, err
+	}
+	stat.ModTime = modTime
+	return stat, nil
+}
+
+func optionalTime(m map[string]interface{}) (time.Time, error) {
+	s, ok := m[fileTransferModTimeKey].(string)
+	if !ok || s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// doFileTransferCommand handles the reserved file transfer DoCommands. It returns false if req is
+// not a file transfer request.
+func doFileTransferCommand(
+	ctx context.Context, svc Service, req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	cmd := req.GetCommand().AsMap()
+	var isTransfer bool
+	for _, key := range []string{
+		FileTransferStatCommand, FileTransferWriteCommand, FileTransferCommitCommand, FileTransferReadCommand,
+	} {
+		if _, ok := cmd[key]; ok {
+			isTransfer = true
+		}
+	}
+	if !isTransfer {
+		return nil, false, nil
+	}
+	transferer, ok := svc.(ResumableFileTransferer)
+	if !ok {
+		return nil, true, fmt.Errorf("shell service %q does not support resumable file transfers", svc.Name().ShortName())
+	}
+
+	path, _ := cmd[fileTransferPathKey].(string)       //nolint:errcheck
+	offset, _ := cmd[fileTransferOffsetKey].(float64)  //nolint:errcheck
+	checksum, _ := cmd[fileTransferSHA256Key].(string) //nolint:errcheck
+	result := map[string]interface{}{}
+	switch {
+	case cmd[FileTransferStatCommand] != nil:
+		withChecksum, _ := cmd[fileTransferChecksumKey].(bool) //nolint:errcheck
+		stat, err := transferer.StatFileTransfer(ctx, path, withChecksum)
+		if err != nil {
+			return nil, true, err
+		}
+		result = fileTransferStatToMap(stat)
+	case cmd[FileTransferWriteCommand] != nil:
+		encoded, _ := cmd[fileTransferDataKey].(string) //nolint:errcheck
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, true, err
+		}
+		if err := transferer.WriteFileChunk(ctx, path, int64(offset), data, checksum); err != nil {
+			return nil, true, err
+		}
+	case cmd[FileTransferCommitCommand] != nil:
+		size, _ := cmd[fileTransferSizeKey].(float64) //nolint:errcheck
+		mode, _ := cmd[fileTransferModeKey].(float64) //nolint:errcheck
+		modTime, err := optionalTime(cmd)
+		if err != nil {
+			return nil, true, err
+		}
+		if err := transferer.CommitFileTransfer(ctx, path, int64(size), checksum, fs.FileMode(mode), modTime); err != nil {
+			return nil, true, err
+		}
+	default:
+		length, _ := cmd[fileTransferLengthKey].(float64) //nolint:errcheck
+		data, dataChecksum, err := transferer.ReadFileChunk(ctx, path, int64(offset), int(length))
+		if err != nil {
+			return nil, true, err
+		}
+		result[fileTransferDataKey] = base64.StdEncoding.EncodeToString(data)
+		result[fileTransferSHA256Key] = dataChecksum
+	}
+	resp, err := structpb.NewStruct(result)
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: resp}, true, nil
+}
@@ -0,0 +1,190 @@
+package shell_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/shell"
+	"go.viam.com/rdk/testutils"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+type transferShell struct {
+	*inject.ShellService
+	shell.ResumableFileTransferer
+}
+
+// flakyTransferer fails after chunksLeft chunks have been transferred, like a dropped connection.
+type flakyTransferer struct {
+	shell.ResumableFileTransferer
+	chunksLeft int
+}
+
+var errDropped = errors.New("connection dropped")
+
+func (t *flakyTransferer) WriteFileChunk(ctx context.Context, path string, offset int64, data []byte, checksum string) error {
+	if t.chunksLeft == 0 {
+		return errDropped
+	}
+	t.chunksLeft--
+	return t.ResumableFileTransferer.WriteFileChunk(ctx, path, offset, data, checksum)
+}
+
+func (t *flakyTransferer) ReadFileChunk(ctx context.Context, path string, offset int64, length int) ([]byte, string, error) {
+	if t.chunksLeft == 0 {
+		return nil, "", errDropped
+	}
+	t.chunksLeft--
+	return t.ResumableFileTransferer.ReadFileChunk(ctx, path, offset, length)
+}
+
+type progressRecorder struct {
+	transferred []int64
+}
+
+func (r *progressRecorder) options() shell.FileTransferOptions {
+	return shell.FileTransferOptions{
+		ChunkSize: 1000,
+		Progress: func(transferred, total int64) {
+			r.transferred = append(r.transferred, transferred)
+		},
+	}
+}
+
+func TestResumableFileTransfer(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	injectShell := inject.NewShellService(testSvcName1.ShortName())
+	injectShell.DoCommandFunc = testutils.EchoFunc
+	svc, err := resource.NewAPIResourceCollection(shell.API, map[resource.Name]shell.Service{
+		testSvcName1: transferShell{injectShell, shell.NewLocalFileTransferer(false)},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[shell.Service](shell.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, svc), test.ShouldBeNil)
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() { test.That(t, conn.Close(), test.ShouldBeNil) }()
+	client, err := shell.NewClientFromConn(context.Background(), conn, "", testSvcName1, logger)
+	test.That(t, err, test.ShouldBeNil)
+	transferer, ok := client.(shell.ResumableFileTransferer)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	ctx := context.Background()
+	contents := make([]byte, 10500)
+	//nolint:gosec
+	rand.New(rand.NewSource(1)).Read(contents)
+	localDir, remoteDir := t.TempDir(), t.TempDir()
+	localPath, remotePath := filepath.Join(localDir, "bag"), filepath.Join(remoteDir, "bag")
+	test.That(t, os.WriteFile(localPath, contents, 0o600), test.ShouldBeNil)
+
+	t.Run("upload resumes after an interruption", func(t *testing.T) {
+		err := shell.UploadFile(ctx, &flakyTransferer{transferer, 4}, localPath, remotePath, shell.FileTransferOptions{ChunkSize: 1000})
+		test.That(t, err, test.ShouldBeError, errDropped)
+		_, err = os.Stat(remotePath)
+		test.That(t, errors.Is(err, os.ErrNotExist), test.ShouldBeTrue)
+
+		var progress progressRecorder
+		test.That(t, shell.UploadFile(ctx, transferer, localPath, remotePath, progress.options()), test.ShouldBeNil)
+		test.That(t, progress.transferred[0], test.ShouldEqual, 4000)
+		test.That(t, progress.transferred[len(progress.transferred)-1], test.ShouldEqual, 10500)
+		uploaded, err := os.ReadFile(remotePath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, bytes.Equal(uploaded, contents), test.ShouldBeTrue)
+		_, err = os.Stat(remotePath + shell.PartialFileTransferExt)
+		test.That(t, errors.Is(err, os.ErrNotExist), test.ShouldBeTrue)
+	})
+
+	t.Run("upload of an identical file is skipped", func(t *testing.T) {
+		var progress progressRecorder
+		test.That(t, shell.UploadFile(ctx, transferer, localPath, remotePath, progress.options()), test.ShouldBeNil)
+		test.That(t, progress.transferred, test.ShouldResemble, []int64{10500})
+	})
+
+	t.Run("upload starts over when the partial upload is of another file", func(t *testing.T) {
+		otherPath := filepath.Join(remoteDir, "other")
+		test.That(t, os.WriteFile(otherPath+shell.PartialFileTransferExt, []byte("something else"), 0o600), test.ShouldBeNil)
+		var progress progressRecorder
+		test.That(t, shell.UploadFile(ctx, transferer, localPath, otherPath, progress.options()), test.ShouldBeNil)
+		test.That(t, progress.transferred[0], test.ShouldEqual, 0)
+		uploaded, err := os.ReadFile(otherPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, bytes.Equal(uploaded, contents), test.ShouldBeTrue)
+	})
+
+	t.Run("download resumes after an interruption and preserves the mode", func(t *testing.T) {
+		modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		test.That(t, os.Chmod(remotePath, 0o604), test.ShouldBeNil)
+		test.That(t, os.Chtimes(remotePath, modTime, modTime), test.ShouldBeNil)
+		downloadPath := filepath.Join(localDir, "downloaded")
+		opts := shell.FileTransferOptions{ChunkSize: 1000, Preserve: true}
+		err := shell.DownloadFile(ctx, &flakyTransferer{transferer, 3}, remotePath, downloadPath, opts)
+		test.That(t, err, test.ShouldBeError, errDropped)
+
+		var progress progressRecorder
+		opts.Progress = progress.options().Progress
+		test.That(t, shell.DownloadFile(ctx, transferer, remotePath, downloadPath, opts), test.ShouldBeNil)
+		test.That(t, progress.transferred[0], test.ShouldEqual, 3000)
+		downloaded, err := os.ReadFile(downloadPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, bytes.Equal(downloaded, contents), test.ShouldBeTrue)
+		info, err := os.Stat(downloadPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, info.Mode().Perm(), test.ShouldEqual, os.FileMode(0o604))
+		test.That(t, info.ModTime().Equal(modTime), test.ShouldBeTrue)
+		partials, err := filepath.Glob(filepath.Join(localDir, "*"+shell.PartialFileTransferExt))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, partials, test.ShouldBeEmpty)
+	})
+
+	t.Run("chunks and files are checked against their checksums", func(t *testing.T) {
+		path := filepath.Join(remoteDir, "corrupt")
+		err := transferer.WriteFileChunk(ctx, path, 0, []byte("data"), "bad")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "does not match its checksum")
+
+		data := []byte("data")
+		stat, err := transferer.StatFileTransfer(ctx, localPath, true)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, stat.Exists, test.ShouldBeTrue)
+		test.That(t, stat.Size, test.ShouldEqual, 10500)
+		test.That(t, transferer.WriteFileChunk(ctx, path, 0, data, stat.SHA256), test.ShouldNotBeNil)
+		_, checksum, err := transferer.ReadFileChunk(ctx, localPath, 0, 4)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, transferer.WriteFileChunk(ctx, path, 0, contents[:4], checksum), test.ShouldBeNil)
+		// chunks must be written in order
+		test.That(t, transferer.WriteFileChunk(ctx, path, 8, contents[:4], checksum), test.ShouldNotBeNil)
+		err = transferer.CommitFileTransfer(ctx, path, 4, stat.SHA256, 0, time.Time{})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "will start over")
+		_, err = os.Stat(path + shell.PartialFileTransferExt)
+		test.That(t, errors.Is(err, os.ErrNotExist), test.ShouldBeTrue)
+	})
+
+	// other commands still reach DoCommand
+	resp, err := client.DoCommand(ctx, testutils.TestCommand)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["command"], test.ShouldEqual, testutils.TestCommand["command"])
+}
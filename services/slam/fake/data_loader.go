@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -65,6 +66,19 @@ func fakePointCloudMap(ctx context.Context, datasetDir string, slamSvc *SLAM) (f
 	return f, nil
 }
 
+// chunkedBytes returns a callback that returns `data` in chunks, then io.EOF.
+func chunkedBytes(data []byte) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		if len(data) == 0 {
+			return nil, io.EOF
+		}
+		n := min(len(data), chunkSizeBytes)
+		chunk := data[:n]
+		data = data[n:]
+		return chunk, nil
+	}
+}
+
 func fakeInternalState(ctx context.Context, datasetDir string, slamSvc *SLAM) (func() ([]byte, error), error) {
 	path := filepath.Clean(artifact.MustPath(fmt.Sprintf(internalStateTemplate, datasetDir, slamSvc.getCount())))
 	slamSvc.logger.CDebug(ctx, "Reading "+path)
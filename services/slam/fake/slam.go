@@ -4,6 +4,7 @@ package fake
 import (
 	"bytes"
 	"context"
+	"sync"
 	"time"
 
 	"go.opencensus.io/trace"
@@ -45,6 +46,10 @@ type SLAM struct {
 	dataCount    int
 	logger       logging.Logger
 	mapTimestamp time.Time
+
+	mu sync.Mutex
+	// mergedMap, once maps have been merged, replaces the dataset map returned by PointCloudMap.
+	mergedMap []byte
 }
 
 // NewSLAM is a constructor for a fake slam service.
@@ -77,9 +82,71 @@ func (slamSvc *SLAM) PointCloudMap(ctx context.Context, returnEditedMap bool) (f
 	ctx, span := trace.StartSpan(ctx, "slam::fake::PointCloudMap")
 	defer span.End()
 	slamSvc.incrementDataCount()
+	slamSvc.mu.Lock()
+	mergedMap := slamSvc.mergedMap
+	slamSvc.mu.Unlock()
+	if mergedMap != nil {
+		return chunkedBytes(mergedMap), nil
+	}
 	return fakePointCloudMap(ctx, datasetDirectory, slamSvc)
 }
 
+// fakeLocalizationQuality is the localization quality the fake always reports: a good match, with
+// the position known to within 10mm and the heading to within 0.01 radians.
+var fakeLocalizationQuality = slam.LocalizationQuality{
+	MatchScore: 0.95,
+	Covariance: []float64{100, 0, 0, 0, 100, 0, 0, 0, 0.0001},
+}
+
+// LocalizationQuality returns how well the fake is localized, which is always well.
+func (slamSvc *SLAM) LocalizationQuality(ctx context.Context, extra map[string]interface{}) (slam.LocalizationQuality, error) {
+	_, span := trace.StartSpan(ctx, "slam::fake::LocalizationQuality")
+	defer span.End()
+	return fakeLocalizationQuality, nil
+}
+
+// slamStats are the statistics of the fake slam service recorded by FTDC.
+type slamStats struct {
+	MatchScore       float64
+	PositionStdDevMM float64
+}
+
+// Stats returns the localization quality of the fake, which is recorded by FTDC.
+func (slamSvc *SLAM) Stats() any {
+	return slamStats{
+		MatchScore:       fakeLocalizationQuality.MatchScore,
+		PositionStdDevMM: fakeLocalizationQuality.PositionStdDevMM(),
+	}
+}
+
+// MergeMaps merges saved maps into the map returned by PointCloudMap. When extending the current
+// map, the dataset map the fake is currently serving is merged in with no offset.
+func (slamSvc *SLAM) MergeMaps(ctx context.Context, req slam.MergeMapsRequest) (int, error) {
+	ctx, span := trace.StartSpan(ctx, "slam::fake::MergeMaps")
+	defer span.End()
+	maps := req.Maps
+	if req.ExtendCurrentMap {
+		current, err := slam.PointCloudMapFull(ctx, slamSvc, false)
+		if err != nil {
+			return 0, err
+		}
+		maps = append([]slam.SavedMap{{PointCloudMap: current}}, maps...)
+	}
+	merged, err := slam.MergePointCloudMaps(ctx, maps, req.VoxelSizeMM, slamSvc.logger)
+	if err != nil {
+		return 0, err
+	}
+	var buf bytes.Buffer
+	if err := pointcloud.ToPCD(merged, &buf, pointcloud.PCDBinary); err != nil {
+		return 0, err
+	}
+	slamSvc.mu.Lock()
+	defer slamSvc.mu.Unlock()
+	slamSvc.mergedMap = buf.Bytes()
+	slamSvc.mapTimestamp = time.Now().UTC()
+	return merged.Size(), nil
+}
+
 // InternalState returns a callback function which will return the next chunk of the current internal
 // state of the slam algo.
 func (slamSvc *SLAM) InternalState(ctx context.Context) (func() ([]byte, error), error) {
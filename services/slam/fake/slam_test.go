@@ -214,3 +214,52 @@ func helperConcatenateChunksToFull(f func() ([]byte, error)) ([]byte, error) {
 		fullBytes = append(fullBytes, chunk...)
 	}
 }
+
+func TestFakeSLAMMergeMaps(t *testing.T) {
+	slamSvc := NewSLAM(slam.Named("test"), logging.NewTestLogger(t))
+	mapOf := func(points ...r3.Vector) []byte {
+		cloud := pointcloud.New()
+		for _, p := range points {
+			test.That(t, cloud.Set(p, nil), test.ShouldBeNil)
+		}
+		var buf bytes.Buffer
+		test.That(t, pointcloud.ToPCD(cloud, &buf, pointcloud.PCDBinary), test.ShouldBeNil)
+		return buf.Bytes()
+	}
+
+	numPoints, err := slamSvc.MergeMaps(context.Background(), slam.MergeMapsRequest{
+		Maps: []slam.SavedMap{
+			{PointCloudMap: mapOf(r3.Vector{X: 1})},
+			{PointCloudMap: mapOf(r3.Vector{X: 1}), Offset: spatialmath.NewPoseFromPoint(r3.Vector{Y: 1000})},
+		},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, numPoints, test.ShouldEqual, 2)
+
+	// the merged map is served in place of the dataset
+	data, err := slam.PointCloudMapFull(context.Background(), slamSvc, false)
+	test.That(t, err, test.ShouldBeNil)
+	merged, err := pointcloud.ReadPCD(bytes.NewReader(data))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, merged.Size(), test.ShouldEqual, 2)
+	_, ok := merged.At(1, 1000, 0)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// extending the merged map from a new session keeps its points
+	numPoints, err = slamSvc.MergeMaps(context.Background(), slam.MergeMapsRequest{
+		Maps:             []slam.SavedMap{{PointCloudMap: mapOf(r3.Vector{X: 1}, r3.Vector{X: 500})}},
+		ExtendCurrentMap: true,
+		VoxelSizeMM:      10,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, numPoints, test.ShouldEqual, 3)
+}
+
+func TestFakeSLAMLocalizationQuality(t *testing.T) {
+	slamSvc := NewSLAM(slam.Named("test"), logging.NewTestLogger(t))
+	quality, err := slamSvc.LocalizationQuality(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, quality.MatchScore, test.ShouldEqual, 0.95)
+	test.That(t, quality.PositionStdDevMM(), test.ShouldEqual, 10)
+	test.That(t, slamSvc.Stats(), test.ShouldResemble, slamStats{MatchScore: 0.95, PositionStdDevMM: 10})
+}
@@ -0,0 +1,113 @@
+package slam
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+)
+
+// The slam proto has no localization quality RPC. Localization quality is carried over DoCommand
+// using the following reserved keys.
+const (
+	getLocalizationQualityKey = "get_localization_quality"
+	localizationMatchScoreKey = "match_score"
+	localizationCovarianceKey = "covariance"
+	localizationExtraKey      = "extra"
+)
+
+// LocalizationQuality describes how confident a SLAM algorithm is in the position it reports.
+type LocalizationQuality struct {
+	// MatchScore is how well the latest sensor readings match the map, from 0 (no match) to 1.
+	MatchScore float64
+	// Covariance is the row-major 3x3 covariance of the x (mm), y (mm) and heading (radians) of
+	// the reported position. It is nil if the algorithm does not estimate it.
+	Covariance []float64
+}
+
+// PositionStdDevMM returns the standard deviation in mm of the reported position along its least
+// certain axis, or NaN if the covariance is unknown.
+func (q LocalizationQuality) PositionStdDevMM() float64 {
+	if len(q.Covariance) != 9 {
+		return math.NaN()
+	}
+	return math.Sqrt(math.Max(q.Covariance[0], q.Covariance[4]))
+}
+
+// LocalizationQualityReporter is implemented by SLAM services that can report how well they are
+// localized, so that clients can slow down or stop when localization degrades.
+//
+// LocalizationQuality example:
+//
+//	mySLAMService, err := slam.FromRobot(machine, "my_slam_service")
+//	if reporter, ok := mySLAMService.(slam.LocalizationQualityReporter); ok {
+//		quality, err := reporter.LocalizationQuality(context.Background(), nil)
+//		if quality.MatchScore < 0.5 {
+//			logger.Warnf("localization degraded, position is only known to %.0fmm", quality.PositionStdDevMM())
+//		}
+//	}
+type LocalizationQualityReporter interface {
+	// LocalizationQuality returns the quality of the SLAM algorithm's current position estimate.
+	LocalizationQuality(ctx context.Context, extra map[string]interface{}) (LocalizationQuality, error)
+}
+
+func (c *client) LocalizationQuality(ctx context.Context, extra map[string]interface{}) (LocalizationQuality, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		getLocalizationQualityKey: map[string]interface{}{localizationExtraKey: extra},
+	})
+	if err != nil {
+		return LocalizationQuality{}, err
+	}
+	score, ok := resp[localizationMatchScoreKey].(float64)
+	if !ok {
+		return LocalizationQuality{}, errors.Errorf("expected %q in response, got %v", localizationMatchScoreKey, resp)
+	}
+	quality := LocalizationQuality{MatchScore: score}
+	if covariance, ok := resp[localizationCovarianceKey].([]interface{}); ok {
+		quality.Covariance = make([]float64, 0, len(covariance))
+		for _, v := range covariance {
+			f, _ := v.(float64) //nolint:errcheck
+			quality.Covariance = append(quality.Covariance, f)
+		}
+	}
+	return quality, nil
+}
+
+// doLocalizationQualityCommand handles the reserved localization quality DoCommand key. It returns
+// false if `req` is not a localization quality command or the service does not implement
+// LocalizationQualityReporter.
+func doLocalizationQualityCommand(
+	ctx context.Context,
+	svc Service,
+	req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	reporter, ok := svc.(LocalizationQualityReporter)
+	if !ok {
+		return nil, false, nil
+	}
+	payload, ok := req.GetCommand().AsMap()[getLocalizationQualityKey]
+	if !ok {
+		return nil, false, nil
+	}
+	args, _ := payload.(map[string]interface{})                     //nolint:errcheck
+	extra, _ := args[localizationExtraKey].(map[string]interface{}) //nolint:errcheck
+	quality, err := reporter.LocalizationQuality(ctx, extra)
+	if err != nil {
+		return nil, true, err
+	}
+	result := map[string]interface{}{localizationMatchScoreKey: quality.MatchScore}
+	if quality.Covariance != nil {
+		covariance := make([]interface{}, 0, len(quality.Covariance))
+		for _, v := range quality.Covariance {
+			covariance = append(covariance, v)
+		}
+		result[localizationCovarianceKey] = covariance
+	}
+	res, err := protoutils.StructToStructPb(result)
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
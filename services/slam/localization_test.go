@@ -0,0 +1,68 @@
+package slam_test
+
+import (
+	"context"
+	"math"
+	"net"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestLocalizationQualityPositionStdDev(t *testing.T) {
+	quality := slam.LocalizationQuality{MatchScore: 0.8, Covariance: []float64{25, 0, 0, 0, 100, 0, 0, 0, 0.01}}
+	test.That(t, quality.PositionStdDevMM(), test.ShouldEqual, 10)
+	test.That(t, math.IsNaN(slam.LocalizationQuality{MatchScore: 0.8}.PositionStdDevMM()), test.ShouldBeTrue)
+}
+
+func TestClientLocalizationQuality(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	quality := slam.LocalizationQuality{MatchScore: 0.75, Covariance: []float64{25, 1, 0, 1, 100, 0, 0, 0, 0.01}}
+	var receivedExtra map[string]interface{}
+	injectSLAM := inject.NewSLAMService(nameSucc)
+	injectSLAM.LocalizationQualityFunc = func(ctx context.Context, extra map[string]interface{}) (slam.LocalizationQuality, error) {
+		receivedExtra = extra
+		return quality, nil
+	}
+
+	svc, err := resource.NewAPIResourceCollection(slam.API, map[resource.Name]slam.Service{slam.Named(nameSucc): injectSLAM})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[slam.Service](slam.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, svc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	client, err := slam.NewClientFromConn(context.Background(), conn, "", slam.Named(nameSucc), logger)
+	test.That(t, err, test.ShouldBeNil)
+	reporter, ok := client.(slam.LocalizationQualityReporter)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	got, err := reporter.LocalizationQuality(context.Background(), map[string]interface{}{"foo": "bar"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, got, test.ShouldResemble, quality)
+	test.That(t, receivedExtra, test.ShouldResemble, map[string]interface{}{"foo": "bar"})
+
+	// services that don't estimate covariance leave it unset
+	quality = slam.LocalizationQuality{MatchScore: 0.5}
+	got, err = reporter.LocalizationQuality(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, got, test.ShouldResemble, quality)
+}
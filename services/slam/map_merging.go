@@ -0,0 +1,212 @@
+package slam
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// The slam proto has no map merging RPC. Map merges are carried over DoCommand using the following
+// reserved keys.
+const (
+	mergeMapsKey         = "merge_maps"
+	mergeMapsMapsKey     = "maps"
+	mergeMapsPCDKey      = "pcd"
+	mergeMapsOffsetKey   = "offset"
+	mergeMapsExtendKey   = "extend_current_map"
+	mergeMapsVoxelKey    = "voxel_size_mm"
+	mergeMapsExtraKey    = "extra"
+	mergeMapsNumPointKey = "num_points"
+)
+
+// SavedMap is a point cloud map saved from a previous SLAM session.
+type SavedMap struct {
+	// PointCloudMap is the map in PCD format, as returned by PointCloudMapFull.
+	PointCloudMap []byte
+	// Offset is the pose of the saved map's origin in the frame of the merged map. A nil offset
+	// means the frames are the same.
+	Offset spatialmath.Pose
+}
+
+// MergeMapsRequest describes how saved maps are merged into the map of a SLAM service.
+type MergeMapsRequest struct {
+	Maps []SavedMap
+	// ExtendCurrentMap merges Maps into the service's current map rather than replacing the
+	// current map by their merge.
+	ExtendCurrentMap bool
+	// VoxelSizeMM, if positive, is the side of the cubes that the points of the merged map are
+	// downsampled to, so overlapping areas of the maps are not duplicated.
+	VoxelSizeMM float64
+	Extra       map[string]interface{}
+}
+
+// MapMerger is implemented by SLAM services that can merge maps saved from several sessions, so
+// that large facilities can be mapped in more than one pass. After a merge, PointCloudMap returns
+// the merged map.
+//
+// MergeMaps example:
+//
+//	mySLAMService, err := slam.FromRobot(machine, "my_slam_service")
+//	if merger, ok := mySLAMService.(slam.MapMerger); ok {
+//		eastWing, err := os.ReadFile("east_wing.pcd")
+//		numPoints, err := merger.MergeMaps(context.Background(), slam.MergeMapsRequest{
+//			Maps:             []slam.SavedMap{{PointCloudMap: eastWing, Offset: spatialmath.NewPoseFromPoint(r3.Vector{X: 20000})}},
+//			ExtendCurrentMap: true,
+//			VoxelSizeMM:      50,
+//		})
+//	}
+type MapMerger interface {
+	// MergeMaps merges saved maps into the map returned by PointCloudMap and returns the number of
+	// points in the merged map.
+	MergeMaps(ctx context.Context, req MergeMapsRequest) (int, error)
+}
+
+// MergePointCloudMaps returns the union of the given maps, each moved by its offset. If voxelSizeMM
+// is positive, the union is downsampled to voxels of that size.
+func MergePointCloudMaps(
+	ctx context.Context,
+	maps []SavedMap,
+	voxelSizeMM float64,
+	logger logging.Logger,
+) (pointcloud.PointCloud, error) {
+	if len(maps) == 0 {
+		return nil, errors.New("no maps to merge")
+	}
+	cloudFuncs := make([]pointcloud.CloudAndOffsetFunc, 0, len(maps))
+	for i, savedMap := range maps {
+		cloud, err := pointcloud.ReadPCD(bytes.NewReader(savedMap.PointCloudMap))
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read map %d", i)
+		}
+		offset := savedMap.Offset
+		cloudFuncs = append(cloudFuncs, func(ctx context.Context) (pointcloud.PointCloud, spatialmath.Pose, error) {
+			return cloud, offset, nil
+		})
+	}
+	merged, err := pointcloud.MergePointClouds(ctx, cloudFuncs, logger)
+	if err != nil {
+		return nil, err
+	}
+	if voxelSizeMM > 0 {
+		return pointcloud.VoxelDownsample(merged, voxelSizeMM)
+	}
+	return merged, nil
+}
+
+func (c *client) MergeMaps(ctx context.Context, req MergeMapsRequest) (int, error) {
+	maps := make([]interface{}, 0, len(req.Maps))
+	for _, savedMap := range req.Maps {
+		encoded := map[string]interface{}{
+			mergeMapsPCDKey: base64.StdEncoding.EncodeToString(savedMap.PointCloudMap),
+		}
+		if savedMap.Offset != nil {
+			encoded[mergeMapsOffsetKey] = poseToMap(savedMap.Offset)
+		}
+		maps = append(maps, encoded)
+	}
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		mergeMapsKey: map[string]interface{}{
+			mergeMapsMapsKey:   maps,
+			mergeMapsExtendKey: req.ExtendCurrentMap,
+			mergeMapsVoxelKey:  req.VoxelSizeMM,
+			mergeMapsExtraKey:  req.Extra,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	numPoints, ok := resp[mergeMapsNumPointKey].(float64)
+	if !ok {
+		return 0, errors.Errorf("expected %q in response, got %v", mergeMapsNumPointKey, resp)
+	}
+	return int(numPoints), nil
+}
+
+// poseToMap encodes a pose with the field names of the common Pose proto.
+func poseToMap(pose spatialmath.Pose) map[string]interface{} {
+	pb := spatialmath.PoseToProtobuf(pose)
+	return map[string]interface{}{
+		"x": pb.X, "y": pb.Y, "z": pb.Z, "o_x": pb.OX, "o_y": pb.OY, "o_z": pb.OZ, "theta": pb.Theta,
+	}
+}
+
+// poseFromMap decodes a pose encoded by poseToMap. Missing fields are zero.
+func poseFromMap(m map[string]interface{}) spatialmath.Pose {
+	field := func(key string) float64 {
+		v, _ := m[key].(float64) //nolint:errcheck
+		return v
+	}
+	return spatialmath.NewPoseFromProtobuf(&commonpb.Pose{
+		X: field("x"), Y: field("y"), Z: field("z"),
+		OX: field("o_x"), OY: field("o_y"), OZ: field("o_z"), Theta: field("theta"),
+	})
+}
+
+// mergeMapsRequestFromCommand parses the arguments of a merge_maps DoCommand.
+func mergeMapsRequestFromCommand(payload interface{}) (MergeMapsRequest, error) {
+	args, _ := payload.(map[string]interface{})                  //nolint:errcheck
+	encodedMaps, _ := args[mergeMapsMapsKey].([]interface{})     //nolint:errcheck
+	extend, _ := args[mergeMapsExtendKey].(bool)                 //nolint:errcheck
+	voxelSize, _ := args[mergeMapsVoxelKey].(float64)            //nolint:errcheck
+	extra, _ := args[mergeMapsExtraKey].(map[string]interface{}) //nolint:errcheck
+	req := MergeMapsRequest{ExtendCurrentMap: extend, VoxelSizeMM: voxelSize, Extra: extra}
+	for i, encodedMap := range encodedMaps {
+		encoded, ok := encodedMap.(map[string]interface{})
+		if !ok {
+			return MergeMapsRequest{}, errors.Errorf("map %d is not an object", i)
+		}
+		pcd, _ := encoded[mergeMapsPCDKey].(string) //nolint:errcheck
+		data, err := base64.StdEncoding.DecodeString(pcd)
+		if err != nil {
+			return MergeMapsRequest{}, errors.Wrapf(err, "map %d has invalid PCD data", i)
+		}
+		savedMap := SavedMap{PointCloudMap: data}
+		if offset, ok := encoded[mergeMapsOffsetKey]; ok {
+			offsetMap, ok := offset.(map[string]interface{})
+			if !ok {
+				return MergeMapsRequest{}, errors.Errorf("map %d has an invalid offset", i)
+			}
+			savedMap.Offset = poseFromMap(offsetMap)
+		}
+		req.Maps = append(req.Maps, savedMap)
+	}
+	return req, nil
+}
+
+// doMapMergeCommand handles the reserved map merging DoCommand key. It returns false if `req` is
+// not a map merging command or the service does not implement MapMerger.
+func doMapMergeCommand(
+	ctx context.Context,
+	svc Service,
+	req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	merger, ok := svc.(MapMerger)
+	if !ok {
+		return nil, false, nil
+	}
+	payload, ok := req.GetCommand().AsMap()[mergeMapsKey]
+	if !ok {
+		return nil, false, nil
+	}
+	mergeReq, err := mergeMapsRequestFromCommand(payload)
+	if err != nil {
+		return nil, true, err
+	}
+	numPoints, err := merger.MergeMaps(ctx, mergeReq)
+	if err != nil {
+		return nil, true, err
+	}
+	res, err := protoutils.StructToStructPb(map[string]interface{}{mergeMapsNumPointKey: numPoints})
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
@@ -0,0 +1,115 @@
+package slam_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func pcdOf(t *testing.T, points ...r3.Vector) []byte {
+	t.Helper()
+	cloud := pointcloud.New()
+	for _, p := range points {
+		test.That(t, cloud.Set(p, nil), test.ShouldBeNil)
+	}
+	var buf bytes.Buffer
+	test.That(t, pointcloud.ToPCD(cloud, &buf, pointcloud.PCDBinary), test.ShouldBeNil)
+	return buf.Bytes()
+}
+
+func TestMergePointCloudMaps(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	first := pcdOf(t, r3.Vector{X: 0, Y: 0}, r3.Vector{X: 1000, Y: 0})
+	// the second session started 1m further along x, so its first point overlaps the first map
+	second := pcdOf(t, r3.Vector{X: 2, Y: 1}, r3.Vector{X: 1000, Y: 0})
+	maps := []slam.SavedMap{
+		{PointCloudMap: first},
+		{PointCloudMap: second, Offset: spatialmath.NewPoseFromPoint(r3.Vector{X: 1000})},
+	}
+
+	merged, err := slam.MergePointCloudMaps(context.Background(), maps, 0, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, merged.Size(), test.ShouldEqual, 4)
+	_, ok := merged.At(2000, 0, 0)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	merged, err = slam.MergePointCloudMaps(context.Background(), maps, 50, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, merged.Size(), test.ShouldEqual, 3)
+
+	_, err = slam.MergePointCloudMaps(context.Background(), nil, 0, logger)
+	test.That(t, err, test.ShouldBeError, "no maps to merge")
+	_, err = slam.MergePointCloudMaps(context.Background(), []slam.SavedMap{{PointCloudMap: []byte("not a pcd")}}, 0, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestClientMergeMaps(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	pcd := pcdOf(t, r3.Vector{X: 1, Y: 2, Z: 3})
+	offset := spatialmath.NewPose(r3.Vector{X: 1000, Y: -500}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90})
+	var received slam.MergeMapsRequest
+	injectSLAM := inject.NewSLAMService(nameSucc)
+	injectSLAM.MergeMapsFunc = func(ctx context.Context, req slam.MergeMapsRequest) (int, error) {
+		received = req
+		return 7, nil
+	}
+	injectSLAM.DoCommandFunc = testutils.EchoFunc
+
+	svc, err := resource.NewAPIResourceCollection(slam.API, map[resource.Name]slam.Service{slam.Named(nameSucc): injectSLAM})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[slam.Service](slam.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, svc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	client, err := slam.NewClientFromConn(context.Background(), conn, "", slam.Named(nameSucc), logger)
+	test.That(t, err, test.ShouldBeNil)
+	merger, ok := client.(slam.MapMerger)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	numPoints, err := merger.MergeMaps(context.Background(), slam.MergeMapsRequest{
+		Maps:             []slam.SavedMap{{PointCloudMap: pcd}, {PointCloudMap: pcd, Offset: offset}},
+		ExtendCurrentMap: true,
+		VoxelSizeMM:      50,
+		Extra:            map[string]interface{}{"foo": "bar"},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, numPoints, test.ShouldEqual, 7)
+	test.That(t, len(received.Maps), test.ShouldEqual, 2)
+	test.That(t, received.Maps[0].PointCloudMap, test.ShouldResemble, pcd)
+	test.That(t, received.Maps[0].Offset, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostEqual(received.Maps[1].Offset, offset), test.ShouldBeTrue)
+	test.That(t, received.ExtendCurrentMap, test.ShouldBeTrue)
+	test.That(t, received.VoxelSizeMM, test.ShouldEqual, 50)
+	test.That(t, received.Extra, test.ShouldResemble, map[string]interface{}{"foo": "bar"})
+
+	// other commands still reach the service's DoCommand
+	resp, err := client.DoCommand(context.Background(), testutils.TestCommand)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["command"], test.ShouldEqual, testutils.TestCommand["command"])
+}
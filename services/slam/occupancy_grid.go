@@ -0,0 +1,326 @@
+package slam
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// Values of the cells of an OccupancyGrid. Known cells hold the probability, from 0 to 100, that they are
+// occupied, like the cells of a ROS nav_msgs/OccupancyGrid.
+const (
+	OccupancyUnknown  = -1
+	OccupancyFree     = 0
+	OccupancyOccupied = 100
+)
+
+// The probabilities above and below which cells are written as occupied and free to images. They match the
+// occupied_thresh and free_thresh written to ROS map YAML files.
+const (
+	OccupiedThreshold = 65
+	FreeThreshold     = 19
+)
+
+// The values of the pixels of PGM images, following the ROS map_server conventions.
+const (
+	pgmOccupied = 0
+	pgmFree     = 254
+	pgmUnknown  = 205
+)
+
+// maxOccupancyGridCells bounds the size of a grid, so that a too fine resolution fails rather than using up memory.
+const maxOccupancyGridCells = 1 << 28
+
+// OccupancyGrid is a 2D occupancy grid of a SLAM map, the format most map-editing and fleet tools consume.
+type OccupancyGrid struct {
+	// ResolutionMM is the length of the side of a cell, in mm.
+	ResolutionMM float64
+	// Origin is the position in the map of the corner of the cell (0, 0), the cell with the lowest X and Y.
+	Origin r3.Vector
+	Width  int
+	Height int
+	// Cells are the values of the cells row by row, starting with the cell (0, 0). X increases along rows and Y
+	// from row to row.
+	Cells []int8
+}
+
+// NewOccupancyGrid projects the points of a SLAM map onto the XY plane of the map to build an occupancy grid
+// with cells of resolutionMM. The value of a cell is the highest probability of its points, read from their
+// value or the blue channel of their color as SLAM maps store it. Points with neither are occupied, and cells
+// without points are unknown.
+func NewOccupancyGrid(pc pointcloud.PointCloud, resolutionMM float64) (*OccupancyGrid, error) {
+	if resolutionMM <= 0 {
+		return nil, errors.Errorf("occupancy grid resolution must be positive, got %v", resolutionMM)
+	}
+	if pc.Size() == 0 {
+		return nil, errors.New("cannot build an occupancy grid from an empty map")
+	}
+
+	meta := pc.MetaData()
+	origin := r3.Vector{X: meta.MinX, Y: meta.MinY}
+	width := int(math.Floor((meta.MaxX-meta.MinX)/resolutionMM)) + 1
+	height := int(math.Floor((meta.MaxY-meta.MinY)/resolutionMM)) + 1
+	if float64(width)*float64(height) > maxOccupancyGridCells {
+		return nil, errors.Errorf("an occupancy grid of %dx%d cells is too large, use a coarser resolution", width, height)
+	}
+
+	grid := &OccupancyGrid{
+		ResolutionMM: resolutionMM,
+		Origin:       origin,
+		Width:        width,
+		Height:       height,
+		Cells:        make([]int8, width*height),
+	}
+	for i := range grid.Cells {
+		grid.Cells[i] = OccupancyUnknown
+	}
+	pc.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+		x := min(int((p.X-origin.X)/resolutionMM), width-1)
+		y := min(int((p.Y-origin.Y)/resolutionMM), height-1)
+		i := y*width + x
+		grid.Cells[i] = max(grid.Cells[i], occupancyProbability(d))
+		return true
+	})
+	return grid, nil
+}
+
+// OccupancyGridFromService builds an occupancy grid of the current map of a SLAM service. See NewOccupancyGrid.
+func OccupancyGridFromService(ctx context.Context, slamSvc Service, resolutionMM float64) (*OccupancyGrid, error) {
+	ctx, span := trace.StartSpan(ctx, "slam::OccupancyGridFromService")
+	defer span.End()
+	pcdBytes, err := PointCloudMapFull(ctx, slamSvc, true)
+	if err != nil {
+		return nil, err
+	}
+	pc, err := pointcloud.ReadPCD(bytes.NewReader(pcdBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the point cloud map")
+	}
+	return NewOccupancyGrid(pc, resolutionMM)
+}
+
+// occupancyProbability returns the probability that a point of a SLAM map is occupied.
+func occupancyProbability(d pointcloud.Data) int8 {
+	var prob int
+	switch {
+	case d == nil:
+		return OccupancyOccupied
+	case d.HasColor():
+		_, _, b := d.RGB255()
+		prob = int(b)
+	case d.HasValue():
+		prob = d.Value()
+	default:
+		return OccupancyOccupied
+	}
+	return int8(min(max(prob, OccupancyFree), OccupancyOccupied))
+}
+
+// At returns the value of the cell (x, y), or OccupancyUnknown if it is outside of the grid.
+func (g *OccupancyGrid) At(x, y int) int8 {
+	if x < 0 || y < 0 || x >= g.Width || y >= g.Height {
+		return OccupancyUnknown
+	}
+	return g.Cells[y*g.Width+x]
+}
+
+// WritePGM writes the grid as a binary PGM image as the ROS map_server reads it: occupied cells are black, free
+// cells white and unknown cells grey. The top row of the image is the row of the grid with the highest Y.
+func (g *OccupancyGrid) WritePGM(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintf(bw, "P5\n# resolution %v mm\n%d %d\n255\n", g.ResolutionMM, g.Width, g.Height); err != nil {
+		return err
+	}
+	row := make([]byte, g.Width)
+	for y := g.Height - 1; y >= 0; y-- {
+		for x := range row {
+			switch v := g.At(x, y); {
+			case v == OccupancyUnknown:
+				row[x] = pgmUnknown
+			case v >= OccupiedThreshold:
+				row[x] = pgmOccupied
+			case v <= FreeThreshold:
+				row[x] = pgmFree
+			default:
+				row[x] = pgmUnknown
+			}
+		}
+		if _, err := bw.Write(row); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteROSMapYAML writes the ROS map_server metadata of the grid, for the PGM image at imagePath as written by
+// WritePGM. ROS maps are in meters.
+func (g *OccupancyGrid) WriteROSMapYAML(w io.Writer, imagePath string) error {
+	_, err := fmt.Fprintf(w,
+		"image: %s\nmode: trinary\nresolution: %v\norigin: [%v, %v, 0.0]\nnegate: 0\noccupied_thresh: %v\nfree_thresh: %v\n",
+		imagePath,
+		g.ResolutionMM/1000,
+		g.Origin.X/1000,
+		g.Origin.Y/1000,
+		float64(OccupiedThreshold)/100,
+		float64(FreeThreshold)/100,
+	)
+	return err
+}
+
+// TIFF tags and GeoTIFF keys written by WriteGeoTIFF.
+const (
+	tiffTagImageWidth                = 256
+	tiffTagImageLength               = 257
+	tiffTagBitsPerSample             = 258
+	tiffTagCompression               = 259
+	tiffTagPhotometricInterpretation = 262
+	tiffTagStripOffsets              = 273
+	tiffTagSamplesPerPixel           = 277
+	tiffTagRowsPerStrip              = 278
+	tiffTagStripByteCounts           = 279
+	tiffTagPlanarConfiguration       = 284
+	tiffTagModelTransformation       = 34264
+	tiffTagGeoKeyDirectory           = 34735
+	tiffTagGDALNoData                = 42113
+
+	tiffTypeASCII  = 2
+	tiffTypeShort  = 3
+	tiffTypeLong   = 4
+	tiffTypeDouble = 12
+
+	geoKeyModelType      = 1024
+	geoKeyRasterType     = 1025
+	geoKeyGeographicType = 2048
+	modelTypeGeographic  = 2
+	rasterPixelIsArea    = 1
+	epsgWGS84            = 4326
+
+	geoTIFFNoData = 255
+)
+
+type tiffEntry struct {
+	tag, typ     uint16
+	count, value uint32
+}
+
+// WriteGeoTIFF writes the grid as an 8 bit GeoTIFF image in WGS 84 coordinates, for outdoor maps, given the
+// geographic pose of the origin of the map. Pixels hold the probability that cells are occupied, with unknown
+// cells marked as no data. The image is georeferenced with an affine transformation, so maps which are not
+// aligned to north keep their heading. The transformation is linearized over the extent of the map, which is
+// accurate for maps up to a few kilometers across.
+func (g *OccupancyGrid) WriteGeoTIFF(w io.Writer, mapOrigin *spatialmath.GeoPose) error {
+	if mapOrigin == nil || mapOrigin.Location() == nil {
+		return errors.New("writing a GeoTIFF requires the geographic pose of the map origin")
+	}
+
+	// The affine transformation maps pixel (col, row) to (longitude, latitude), from the geographic positions of
+	// the top left, top right and bottom left corners of the image.
+	geoAt := func(x, y float64) (float64, float64) {
+		gp := spatialmath.PoseToGeoPose(mapOrigin, spatialmath.NewPoseFromPoint(r3.Vector{X: x, Y: y}))
+		return gp.Location().Lng(), gp.Location().Lat()
+	}
+	top := g.Origin.Y + float64(g.Height)*g.ResolutionMM
+	right := g.Origin.X + float64(g.Width)*g.ResolutionMM
+	lng0, lat0 := geoAt(g.Origin.X, top)
+	lngRight, latRight := geoAt(right, top)
+	lngBottom, latBottom := geoAt(g.Origin.X, g.Origin.Y)
+	transformation := []float64{
+		(lngRight - lng0) / float64(g.Width), (lngBottom - lng0) / float64(g.Height), 0, lng0,
+		(latRight - lat0) / float64(g.Width), (latBottom - lat0) / float64(g.Height), 0, lat0,
+		0, 0, 0, 0,
+		0, 0, 0, 1,
+	}
+	geoKeys := []uint16{
+		1, 1, 0, 3,
+		geoKeyModelType, 0, 1, modelTypeGeographic,
+		geoKeyRasterType, 0, 1, rasterPixelIsArea,
+		geoKeyGeographicType, 0, 1, epsgWGS84,
+	}
+
+	// The file is laid out as the header, the pixels, the values too large to be stored in the directory and
+	// the directory.
+	const headerSize = 8
+	pixelsSize := g.Width * g.Height
+	transformationOffset := headerSize + pixelsSize + pixelsSize%2
+	geoKeysOffset := transformationOffset + 8*len(transformation)
+	ifdOffset := geoKeysOffset + 2*len(geoKeys)
+	var noData [4]byte
+	copy(noData[:], fmt.Sprintf("%d", geoTIFFNoData))
+	entries := []tiffEntry{
+		{tiffTagImageWidth, tiffTypeLong, 1, uint32(g.Width)},
+		{tiffTagImageLength, tiffTypeLong, 1, uint32(g.Height)},
+		{tiffTagBitsPerSample, tiffTypeShort, 1, 8},
+		{tiffTagCompression, tiffTypeShort, 1, 1},
+		{tiffTagPhotometricInterpretation, tiffTypeShort, 1, 1},
+		{tiffTagStripOffsets, tiffTypeLong, 1, headerSize},
+		{tiffTagSamplesPerPixel, tiffTypeShort, 1, 1},
+		{tiffTagRowsPerStrip, tiffTypeLong, 1, uint32(g.Height)},
+		{tiffTagStripByteCounts, tiffTypeLong, 1, uint32(pixelsSize)},
+		{tiffTagPlanarConfiguration, tiffTypeShort, 1, 1},
+		{tiffTagModelTransformation, tiffTypeDouble, uint32(len(transformation)), uint32(transformationOffset)},
+		{tiffTagGeoKeyDirectory, tiffTypeShort, uint32(len(geoKeys)), uint32(geoKeysOffset)},
+		{tiffTagGDALNoData, tiffTypeASCII, 4, binary.LittleEndian.Uint32(noData[:])},
+	}
+
+	bw := bufio.NewWriter(w)
+	write := func(data any) error {
+		return binary.Write(bw, binary.LittleEndian, data)
+	}
+	if _, err := bw.WriteString("II"); err != nil {
+		return err
+	}
+	if err := write([]uint16{42}); err != nil {
+		return err
+	}
+	if err := write([]uint32{uint32(ifdOffset)}); err != nil {
+		return err
+	}
+	row := make([]byte, g.Width)
+	for y := g.Height - 1; y >= 0; y-- {
+		for x := range row {
+			if v := g.At(x, y); v == OccupancyUnknown {
+				row[x] = geoTIFFNoData
+			} else {
+				row[x] = byte(v)
+			}
+		}
+		if _, err := bw.Write(row); err != nil {
+			return err
+		}
+	}
+	if pixelsSize%2 == 1 {
+		// values are aligned to words
+		if err := bw.WriteByte(0); err != nil {
+			return err
+		}
+	}
+	if err := write(transformation); err != nil {
+		return err
+	}
+	if err := write(geoKeys); err != nil {
+		return err
+	}
+	if err := write([]uint16{uint16(len(entries))}); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := write(e); err != nil {
+			return err
+		}
+	}
+	if err := write([]uint32{0}); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
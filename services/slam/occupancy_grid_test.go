@@ -0,0 +1,143 @@
+package slam_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+	"golang.org/x/image/tiff"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// testOccupancyMap returns a map of 3x2 cells of 10mm, with an occupied, a free and a colored cell, and three
+// unknown cells.
+func testOccupancyMap(t *testing.T) pointcloud.PointCloud {
+	t.Helper()
+	pc := pointcloud.New()
+	test.That(t, pc.Set(r3.Vector{X: 0, Y: 0}, pointcloud.NewValueData(90)), test.ShouldBeNil)
+	test.That(t, pc.Set(r3.Vector{X: 1, Y: 2}, pointcloud.NewValueData(30)), test.ShouldBeNil)
+	test.That(t, pc.Set(r3.Vector{X: 15, Y: 5}, pointcloud.NewValueData(5)), test.ShouldBeNil)
+	test.That(t, pc.Set(r3.Vector{X: 25, Y: 15}, pointcloud.NewColoredData(color.NRGBA{B: 100, A: 255})), test.ShouldBeNil)
+	return pc
+}
+
+func TestNewOccupancyGrid(t *testing.T) {
+	_, err := slam.NewOccupancyGrid(testOccupancyMap(t), 0)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = slam.NewOccupancyGrid(pointcloud.New(), 10)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	grid, err := slam.NewOccupancyGrid(testOccupancyMap(t), 10)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, grid.Width, test.ShouldEqual, 3)
+	test.That(t, grid.Height, test.ShouldEqual, 2)
+	test.That(t, grid.Origin, test.ShouldResemble, r3.Vector{})
+	test.That(t, grid.Cells, test.ShouldResemble, []int8{90, 5, slam.OccupancyUnknown, slam.OccupancyUnknown, slam.OccupancyUnknown, 100})
+	test.That(t, grid.At(-1, 0), test.ShouldEqual, slam.OccupancyUnknown)
+	test.That(t, grid.At(3, 0), test.ShouldEqual, slam.OccupancyUnknown)
+
+	pc := pointcloud.New()
+	test.That(t, pc.Set(r3.Vector{X: -100, Y: 50}, pointcloud.NewBasicData()), test.ShouldBeNil)
+	grid, err = slam.NewOccupancyGrid(pc, 10)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, grid.Origin, test.ShouldResemble, r3.Vector{X: -100, Y: 50})
+	test.That(t, grid.Cells, test.ShouldResemble, []int8{slam.OccupancyOccupied})
+
+	_, err = slam.NewOccupancyGrid(testOccupancyMap(t), 1e-6)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "too large")
+}
+
+func TestOccupancyGridFromService(t *testing.T) {
+	var pcd bytes.Buffer
+	test.That(t, pointcloud.ToPCD(testOccupancyMap(t), &pcd, pointcloud.PCDBinary), test.ShouldBeNil)
+	svc := inject.NewSLAMService("slam")
+	svc.PointCloudMapFunc = func(ctx context.Context, returnEditedMap bool) (func() ([]byte, error), error) {
+		sent := false
+		return func() ([]byte, error) {
+			if sent {
+				return nil, io.EOF
+			}
+			sent = true
+			return pcd.Bytes(), nil
+		}, nil
+	}
+
+	grid, err := slam.OccupancyGridFromService(context.Background(), svc, 10)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, grid.Width, test.ShouldEqual, 3)
+	test.That(t, grid.Height, test.ShouldEqual, 2)
+}
+
+func TestOccupancyGridROS(t *testing.T) {
+	grid, err := slam.NewOccupancyGrid(testOccupancyMap(t), 10)
+	test.That(t, err, test.ShouldBeNil)
+	grid.Origin = r3.Vector{X: -1500, Y: 2000}
+
+	var pgm bytes.Buffer
+	test.That(t, grid.WritePGM(&pgm), test.ShouldBeNil)
+	header := "P5\n# resolution 10 mm\n3 2\n255\n"
+	test.That(t, strings.HasPrefix(pgm.String(), header), test.ShouldBeTrue)
+	// the top row of the image is the last row of the grid
+	test.That(t, pgm.Bytes()[len(header):], test.ShouldResemble, []byte{
+		205, 205, 0,
+		0, 254, 205,
+	})
+
+	var yaml bytes.Buffer
+	test.That(t, grid.WriteROSMapYAML(&yaml, "map.pgm"), test.ShouldBeNil)
+	test.That(t, yaml.String(), test.ShouldEqual, `image: map.pgm
+mode: trinary
+resolution: 0.01
+origin: [-1.5, 2, 0.0]
+negate: 0
+occupied_thresh: 0.65
+free_thresh: 0.19
+`)
+}
+
+func TestOccupancyGridGeoTIFF(t *testing.T) {
+	grid, err := slam.NewOccupancyGrid(testOccupancyMap(t), 10)
+	test.That(t, err, test.ShouldBeNil)
+
+	var buf bytes.Buffer
+	test.That(t, grid.WriteGeoTIFF(&buf, nil), test.ShouldNotBeNil)
+
+	origin := spatialmath.NewGeoPose(geo.NewPoint(40.7, -74), 0)
+	test.That(t, grid.WriteGeoTIFF(&buf, origin), test.ShouldBeNil)
+
+	img, err := tiff.Decode(bytes.NewReader(buf.Bytes()))
+	test.That(t, err, test.ShouldBeNil)
+	gray, ok := img.(*image.Gray)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, gray.Bounds(), test.ShouldResemble, image.Rect(0, 0, 3, 2))
+	test.That(t, gray.Pix, test.ShouldResemble, []byte{255, 255, 100, 90, 5, 255})
+
+	// the transformation places the top left corner of the image north of the origin, by the height of the map
+	data := buf.Bytes()
+	transformation := make([]float64, 16)
+	offset := 8 + 6
+	test.That(t, binary.Read(bytes.NewReader(data[offset:]), binary.LittleEndian, transformation), test.ShouldBeNil)
+	test.That(t, transformation[3], test.ShouldAlmostEqual, -74, 1e-9)
+	topLeft := geo.NewPoint(transformation[7], transformation[3])
+	test.That(t, origin.Location().GreatCircleDistance(topLeft)*1e6, test.ShouldAlmostEqual, 20, 1e-3)
+	test.That(t, origin.Location().BearingTo(topLeft), test.ShouldAlmostEqual, 0, 1e-3)
+	// pixels are 10mm apart, east along rows and south along columns
+	test.That(t, transformation[0], test.ShouldBeGreaterThan, 0)
+	test.That(t, transformation[5], test.ShouldBeLessThan, 0)
+	test.That(t, math.Abs(transformation[1]), test.ShouldBeLessThan, 1e-12)
+	test.That(t, math.Abs(transformation[4]), test.ShouldBeLessThan, 1e-12)
+}
@@ -170,5 +170,11 @@ func (server *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, ok, err := doMapMergeCommand(ctx, svc, req); ok {
+		return resp, err
+	}
+	if resp, ok, err := doLocalizationQualityCommand(ctx, svc, req); ok {
+		return resp, err
+	}
 	return protoutils.DoFromResourceServer(ctx, svc, req)
 }
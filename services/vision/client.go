@@ -288,7 +288,10 @@ func (c *client) CaptureAllFromCamera(
 	if err != nil {
 		return viscapture.VisCapture{}, err
 	}
+	return captureFromProto(ctx, resp)
+}
 
+func captureFromProto(ctx context.Context, resp *pb.CaptureAllFromCameraResponse) (viscapture.VisCapture, error) {
 	dets, err := protoToDets(resp.Detections)
 	if err != nil {
 		return viscapture.VisCapture{}, err
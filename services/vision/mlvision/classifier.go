@@ -46,8 +46,11 @@ func attemptToBuildClassifier(mlm mlmodel.Service,
 	} else {
 		inHeight, inWidth = shape[1], shape[2]
 	}
-	// creates postprocessor to filter on labels and confidences
-	postprocessor := createClassificationFilter(params.DefaultConfidence, params.LabelConfidenceMap)
+	// creates postprocessor to calibrate scores and filter on labels and confidences
+	postprocessor, err := createClassificationPostprocessor(params)
+	if err != nil {
+		return nil, err
+	}
 
 	return func(ctx context.Context, img image.Image) (classification.Classifications, error) {
 		origW, origH := img.Bounds().Dx(), img.Bounds().Dy()
@@ -130,8 +133,31 @@ func checkIfClassifierWorks(ctx context.Context, cf classification.Classifier) e
 	return nil
 }
 
+// createClassificationPostprocessor returns the calibration of the scores of classifications followed by
+// their filter, or nil if there are neither.
+func createClassificationPostprocessor(params *MLModelConfig) (classification.Postprocessor, error) {
+	filter := createClassificationFilter(params.DefaultConfidence, params.LabelConfidenceMap, params.LabelThresholds)
+	if params.Calibration == nil {
+		return filter, nil
+	}
+	temperature, err := params.Calibration.temperature()
+	if err != nil {
+		return nil, err
+	}
+	scaler := classification.NewTemperatureScaler(temperature)
+	if filter == nil {
+		return scaler, nil
+	}
+	return func(in classification.Classifications) classification.Classifications {
+		return filter(scaler(in))
+	}, nil
+}
+
 // createClassificationFilter creates a post processor function that filters on the outputs of the model.
-func createClassificationFilter(minConf float64, labelMap map[string]float64) classification.Postprocessor {
+func createClassificationFilter(minConf float64, labelMap, labelThresholds map[string]float64) classification.Postprocessor {
+	if len(labelThresholds) != 0 {
+		return classification.NewLabelThresholdFilter(labelThresholds, minConf)
+	}
 	if len(labelMap) != 0 {
 		return classification.NewLabelConfidenceFilter(labelMap)
 	}
@@ -5,6 +5,7 @@ package mlvision
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -21,6 +22,7 @@ import (
 	"go.viam.com/rdk/services/mlmodel"
 	"go.viam.com/rdk/services/vision"
 	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/classification"
 )
 
 var model = resource.DefaultModelFamily.WithModel("mlmodel")
@@ -69,6 +71,57 @@ type MLModelConfig struct {
 	LabelConfidenceMap map[string]float64 `json:"label_confidences"`
 	LabelPath          string             `json:"label_path"`
 	DefaultCamera      string             `json:"camera_name"`
+	// optional per-label minimum confidences of classifications. Unlike label_confidences, classifications
+	// of labels without one are kept if they are above default_minimum_confidence.
+	LabelThresholds map[string]float64 `json:"label_thresholds,omitempty"`
+	// optional calibration of the scores of classifications, applied before they are filtered
+	Calibration *CalibrationConfig `json:"calibration,omitempty"`
+}
+
+// CalibrationConfig calibrates the scores of a classifier with temperature scaling, either with a given
+// temperature or with the one fitting a calibration set best.
+type CalibrationConfig struct {
+	Temperature float64 `json:"temperature,omitempty"`
+	// CalibrationSetPath is a JSON file of a list of calibration samples, each the "scores" of the model for
+	// each label on an image and the true "label" of the image.
+	CalibrationSetPath string `json:"calibration_set_path,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *CalibrationConfig) Validate() error {
+	if (conf.Temperature != 0) == (conf.CalibrationSetPath != "") {
+		return errors.New("calibration must have exactly one of temperature and calibration_set_path")
+	}
+	if conf.Temperature < 0 {
+		return errors.New("calibration temperature must be positive")
+	}
+	if conf.CalibrationSetPath != "" {
+		if _, err := os.Stat(conf.CalibrationSetPath); err != nil {
+			return fmt.Errorf("failed to read file %s: %w", conf.CalibrationSetPath, err)
+		}
+	}
+	return nil
+}
+
+// temperature returns the configured temperature, or fits one to the calibration set.
+func (conf *CalibrationConfig) temperature() (float64, error) {
+	if conf.Temperature != 0 {
+		return conf.Temperature, nil
+	}
+	//nolint:gosec
+	data, err := os.ReadFile(conf.CalibrationSetPath)
+	if err != nil {
+		return 0, err
+	}
+	var samples []classification.CalibrationSample
+	if err := json.Unmarshal(data, &samples); err != nil {
+		return 0, errors.Wrapf(err, "invalid calibration set %s", conf.CalibrationSetPath)
+	}
+	temperature, err := classification.FitTemperature(samples)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to calibrate with %s", conf.CalibrationSetPath)
+	}
+	return temperature, nil
 }
 
 // Validate will add the ModelName as an implicit dependency to the robot.
@@ -97,6 +150,19 @@ func (conf *MLModelConfig) Validate(path string) ([]string, error) {
 			return nil, errors.New("input_image_std_dev is not allowed to have 0 values, will cause division by 0")
 		}
 	}
+	if len(conf.LabelThresholds) != 0 && len(conf.LabelConfidenceMap) != 0 {
+		return nil, errors.New("only one of label_confidences and label_thresholds can be set")
+	}
+	for label, threshold := range conf.LabelThresholds {
+		if threshold < 0 || threshold > 1 {
+			return nil, errors.Errorf("label_thresholds of %q must be between 0 and 1, got %v", label, threshold)
+		}
+	}
+	if conf.Calibration != nil {
+		if err := conf.Calibration.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	return []string{conf.ModelName}, nil
 }
 
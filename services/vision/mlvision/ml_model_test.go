@@ -2,6 +2,9 @@ package mlvision
 
 import (
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 
@@ -604,3 +607,98 @@ func getNClassifications(
 		test.That(t, res[0].Score(), test.ShouldNotBeNil)
 	}
 }
+
+func TestClassificationCalibration(t *testing.T) {
+	setPath := filepath.Join(t.TempDir(), "calibration.json")
+	// an overconfident classifier, right only 3 times out of 4 with a score of 0.99
+	samples := []classification.CalibrationSample{
+		{Scores: map[string]float64{"cat": 0.99, "dog": 0.01}, Label: "cat"},
+		{Scores: map[string]float64{"cat": 0.01, "dog": 0.99}, Label: "dog"},
+		{Scores: map[string]float64{"cat": 0.99, "dog": 0.01}, Label: "cat"},
+		{Scores: map[string]float64{"cat": 0.99, "dog": 0.01}, Label: "dog"},
+	}
+	data, err := json.Marshal(samples)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, os.WriteFile(setPath, data, 0o600), test.ShouldBeNil)
+
+	t.Run("validation", func(t *testing.T) {
+		conf := &MLModelConfig{ModelName: "model", Calibration: &CalibrationConfig{}}
+		_, err := conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "exactly one of temperature and calibration_set_path")
+
+		conf.Calibration = &CalibrationConfig{Temperature: 2, CalibrationSetPath: setPath}
+		_, err = conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+
+		conf.Calibration = &CalibrationConfig{Temperature: -1}
+		_, err = conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "must be positive")
+
+		conf.Calibration = &CalibrationConfig{CalibrationSetPath: setPath + ".missing"}
+		_, err = conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "failed to read file")
+
+		conf.Calibration = &CalibrationConfig{CalibrationSetPath: setPath}
+		_, err = conf.Validate("path")
+		test.That(t, err, test.ShouldBeNil)
+
+		conf.LabelThresholds = map[string]float64{"cat": 1.5}
+		_, err = conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "must be between 0 and 1")
+
+		conf.LabelThresholds = map[string]float64{"cat": 0.5}
+		conf.LabelConfidenceMap = map[string]float64{"cat": 0.5}
+		_, err = conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "only one of label_confidences and label_thresholds")
+	})
+
+	in := classification.Classifications{
+		classification.NewClassification(0.99, "cat"),
+		classification.NewClassification(0.01, "dog"),
+	}
+
+	t.Run("temperature", func(t *testing.T) {
+		postprocessor, err := createClassificationPostprocessor(&MLModelConfig{Calibration: &CalibrationConfig{Temperature: 2}})
+		test.That(t, err, test.ShouldBeNil)
+		out := postprocessor(in)
+		test.That(t, len(out), test.ShouldEqual, 2)
+		// log odds of log(99) halved
+		test.That(t, out[0].Score(), test.ShouldAlmostEqual, 0.90867, 1e-4)
+		test.That(t, out[0].Score()+out[1].Score(), test.ShouldAlmostEqual, 1.)
+	})
+
+	t.Run("calibration set and thresholds", func(t *testing.T) {
+		postprocessor, err := createClassificationPostprocessor(&MLModelConfig{
+			Calibration:       &CalibrationConfig{CalibrationSetPath: setPath},
+			LabelThresholds:   map[string]float64{"cat": 0.8},
+			DefaultConfidence: 0.5,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		out := postprocessor(in)
+		// calibrated to about 0.75, the cat is below its threshold, and the dog below the default
+		test.That(t, out, test.ShouldBeEmpty)
+
+		postprocessor, err = createClassificationPostprocessor(&MLModelConfig{
+			Calibration:     &CalibrationConfig{CalibrationSetPath: setPath},
+			LabelThresholds: map[string]float64{"cat": 0.7},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		out = postprocessor(in)
+		test.That(t, len(out), test.ShouldEqual, 2)
+		test.That(t, out[0].Label(), test.ShouldEqual, "cat")
+		test.That(t, out[0].Score(), test.ShouldAlmostEqual, 0.75, 1e-3)
+	})
+
+	t.Run("invalid calibration set", func(t *testing.T) {
+		badPath := filepath.Join(t.TempDir(), "bad.json")
+		test.That(t, os.WriteFile(badPath, []byte("not json"), 0o600), test.ShouldBeNil)
+		_, err := createClassificationPostprocessor(&MLModelConfig{Calibration: &CalibrationConfig{CalibrationSetPath: badPath}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "invalid calibration set")
+	})
+}
@@ -14,6 +14,7 @@ import (
 
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/rimage"
 	"go.viam.com/rdk/rimage/depthadapter"
@@ -48,7 +49,6 @@ func init() {
 
 // ObsDepthConfig specifies the parameters to be used for the obstacle depth service.
 type ObsDepthConfig struct {
-	resource.TriviallyValidateConfig
 	MinPtsInPlane        int     `json:"min_points_in_plane"`
 	MinPtsInSegment      int     `json:"min_points_in_segment"`
 	MaxDistFromPlane     float64 `json:"max_dist_from_plane_mm"`
@@ -56,11 +56,55 @@ type ObsDepthConfig struct {
 	ClusteringStrictness float64 `json:"clustering_strictness"`
 	AngleTolerance       float64 `json:"ground_angle_tolerance_degs"`
 	DefaultCamera        string  `json:"camera_name"`
+	// GroundNormal is the normal of the ground plane in the frame of the camera, which defaults to up for a level camera.
+	GroundNormal *r3.Vector `json:"ground_plane_normal_vec,omitempty"`
+	// MinRangeMM and MaxRangeMM ignore depths closer or farther than them, such as noise next to the camera or the
+	// background. Zero values disable them.
+	MinRangeMM float64 `json:"min_range_mm,omitempty"`
+	MaxRangeMM float64 `json:"max_range_mm,omitempty"`
+	// VoxelSizeMM downsamples the point cloud of the depth frame before segmenting it, so that fewer points need to be
+	// clustered. Point counts like min_points_in_plane apply to the downsampled cloud.
+	VoxelSizeMM float64 `json:"voxel_size_mm,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *ObsDepthConfig) Validate(path string) ([]string, error) {
+	if err := conf.filterOptions().Validate(); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	if conf.GroundNormal != nil && conf.GroundNormal.Norm() == 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("ground_plane_normal_vec cannot be zero"))
+	}
+	if err := conf.clusteringConfig().CheckValid(); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	return nil, nil
+}
+
+func (conf *ObsDepthConfig) filterOptions() pointcloud.FilterOptions {
+	return pointcloud.FilterOptions{MinRangeMM: conf.MinRangeMM, MaxRangeMM: conf.MaxRangeMM, VoxelSizeMM: conf.VoxelSizeMM}
+}
+
+func (conf *ObsDepthConfig) clusteringConfig() *segmentation.ErCCLConfig {
+	normal := r3.Vector{0, -1, 0}
+	if conf.GroundNormal != nil {
+		normal = *conf.GroundNormal
+	}
+	return &segmentation.ErCCLConfig{
+		MinPtsInPlane:        conf.MinPtsInPlane,
+		MinPtsInSegment:      conf.MinPtsInSegment,
+		MaxDistFromPlane:     conf.MaxDistFromPlane,
+		NormalVec:            normal,
+		AngleTolerance:       conf.AngleTolerance,
+		ClusteringRadius:     conf.ClusteringRadius,
+		ClusteringStrictness: conf.ClusteringStrictness,
+	}
 }
 
 // obsDepth is the underlying struct actually used by the service.
 type obsDepth struct {
 	clusteringConf *segmentation.ErCCLConfig
+	filter         pointcloud.FilterOptions
 	intrinsics     *transform.PinholeCameraIntrinsics
 }
 
@@ -77,21 +121,18 @@ func registerObstaclesDepth(
 		return nil, errors.New("config for obstacles_depth cannot be nil")
 	}
 	// build the clustering config
-	cfg := &segmentation.ErCCLConfig{
-		MinPtsInPlane:        conf.MinPtsInPlane,
-		MinPtsInSegment:      conf.MinPtsInSegment,
-		MaxDistFromPlane:     conf.MaxDistFromPlane,
-		NormalVec:            r3.Vector{0, -1, 0},
-		AngleTolerance:       conf.AngleTolerance,
-		ClusteringRadius:     conf.ClusteringRadius,
-		ClusteringStrictness: conf.ClusteringStrictness,
-	}
+	cfg := conf.clusteringConfig()
 	err := cfg.CheckValid()
 	if err != nil {
 		return nil, errors.Wrap(err, "error building clustering config for obstacles_depth")
 	}
+	filter := conf.filterOptions()
+	if err := filter.Validate(); err != nil {
+		return nil, errors.Wrap(err, "error building point cloud filter for obstacles_depth")
+	}
 	myObsDep := &obsDepth{
 		clusteringConf: cfg,
+		filter:         filter,
 	}
 	if conf.DefaultCamera != "" {
 		_, err = camera.FromRobot(r, conf.DefaultCamera)
@@ -122,7 +163,7 @@ func (o *obsDepth) buildObsDepth(logger logging.Logger) func(
 	}
 }
 
-// buildObsDepthNoIntrinsics will return the median depth in the depth map as a Geometry point.
+// buildObsDepthNoIntrinsics will return the median of the valid depths within range in the depth map as a Geometry point.
 func (o *obsDepth) obsDepthNoIntrinsics(ctx context.Context, src camera.Camera) ([]*vision.Object, error) {
 	img, err := camera.DecodeImageFromCamera(ctx, "", nil, src)
 	if err != nil {
@@ -133,10 +174,20 @@ func (o *obsDepth) obsDepthNoIntrinsics(ctx context.Context, src camera.Camera)
 	if err != nil {
 		return nil, errors.New("could not convert image to depth map")
 	}
-	depData := dm.Data()
-	if len(depData) == 0 {
+	if len(dm.Data()) == 0 {
 		return nil, errors.New("could not get info from depth map")
 	}
+	// Without intrinsics depths are the best available ranges. Zero depths are missing readings.
+	depData := make([]rimage.Depth, 0, len(dm.Data()))
+	for _, d := range dm.Data() {
+		if d == 0 || float64(d) < o.filter.MinRangeMM || (o.filter.MaxRangeMM > 0 && float64(d) > o.filter.MaxRangeMM) {
+			continue
+		}
+		depData = append(depData, d)
+	}
+	if len(depData) == 0 {
+		return []*vision.Object{}, nil
+	}
 	// Sort the depth data [smallest...largest]
 	sort.Slice(depData, func(i, j int) bool {
 		return depData[i] < depData[j]
@@ -163,6 +214,11 @@ func (o *obsDepth) obsDepthWithIntrinsics(ctx context.Context, src camera.Camera
 	if err != nil {
 		return nil, errors.New("could not convert image to depth map")
 	}
-	cloud := depthadapter.ToPointCloud(dm, o.intrinsics)
+	var cloud pointcloud.PointCloud = depthadapter.ToPointCloud(dm, o.intrinsics)
+	if !o.filter.IsZero() {
+		if cloud, err = pointcloud.Filter(cloud, o.filter); err != nil {
+			return nil, err
+		}
+	}
 	return segmentation.ApplyERCCLToPointCloud(ctx, cloud, o.clusteringConf)
 }
@@ -37,6 +37,30 @@ func (r testReader) Close(ctx context.Context) error {
 	return nil
 }
 
+// sceneReader serves the depth image of a level camera 300mm above the ground, facing an obstacle 800mm away.
+type sceneReader struct{}
+
+func (r sceneReader) Read(ctx context.Context) (image.Image, func(), error) {
+	d := rimage.NewEmptyDepthMap(100, 80)
+	for v := 41; v < 80; v++ {
+		for u := 0; u < 100; u++ {
+			d.Set(u, v, rimage.Depth(sceneIntrinsics.Fy*300/float64(v-40)))
+		}
+	}
+	for v := 20; v < 60; v++ {
+		for u := 40; u < 60; u++ {
+			d.Set(u, v, 800)
+		}
+	}
+	return d, nil, nil
+}
+
+func (r sceneReader) Close(ctx context.Context) error {
+	return nil
+}
+
+var sceneIntrinsics = transform.PinholeCameraIntrinsics{Width: 100, Height: 80, Fx: 100, Fy: 100, Ppx: 50, Ppy: 40}
+
 // fullReader grabs and serves a fake depth image for testing.
 type fullReader struct{}
 
@@ -197,6 +221,91 @@ func TestObstacleDepth(t *testing.T) {
 	})
 }
 
+func TestObstacleDepthFiltering(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	sceneSrc, err := camera.NewVideoSourceFromReader(
+		ctx, sceneReader{}, &transform.PinholeCameraModel{PinholeCameraIntrinsics: &sceneIntrinsics}, camera.DepthStream,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	sceneCam := camera.FromVideoSource(resource.Name{Name: "sceneCam"}, sceneSrc, logger)
+	flatSrc, err := camera.NewVideoSourceFromReader(ctx, testReader{}, nil, camera.DepthStream)
+	test.That(t, err, test.ShouldBeNil)
+	flatCam := camera.FromVideoSource(resource.Name{Name: "flatCam"}, flatSrc, logger)
+	r := &inject.Robot{}
+	r.ResourceByNameFunc = func(n resource.Name) (resource.Resource, error) {
+		switch n.Name {
+		case "sceneCam":
+			return sceneCam, nil
+		case "flatCam":
+			return flatCam, nil
+		default:
+			return nil, resource.NewNotFoundError(n)
+		}
+	}
+	name := vision.Named("test")
+
+	t.Run("depth range without intrinsics", func(t *testing.T) {
+		// the depths of the test reader are all 400mm
+		srv, err := registerObstaclesDepth(ctx, name, &ObsDepthConfig{MaxRangeMM: 300}, r, logger)
+		test.That(t, err, test.ShouldBeNil)
+		obs, err := srv.GetObjectPointClouds(ctx, "flatCam", nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, obs, test.ShouldBeEmpty)
+
+		srv, err = registerObstaclesDepth(ctx, name, &ObsDepthConfig{MinRangeMM: 300, MaxRangeMM: 500}, r, logger)
+		test.That(t, err, test.ShouldBeNil)
+		obs, err = srv.GetObjectPointClouds(ctx, "flatCam", nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, obs, test.ShouldHaveLength, 1)
+		test.That(t, obs[0].Geometry.Pose().Point().Z, test.ShouldEqual, 400)
+	})
+
+	cfg := ObsDepthConfig{
+		MinPtsInPlane:        50,
+		MinPtsInSegment:      10,
+		MaxDistFromPlane:     10,
+		ClusteringRadius:     5,
+		ClusteringStrictness: 0.0001,
+		MaxRangeMM:           5000,
+	}
+	segmentedPoints := func(cfg ObsDepthConfig) int {
+		srv, err := registerObstaclesDepth(ctx, name, &cfg, r, logger)
+		test.That(t, err, test.ShouldBeNil)
+		obs, err := srv.GetObjectPointClouds(ctx, "sceneCam", nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, obs, test.ShouldNotBeEmpty)
+		points := 0
+		for _, o := range obs {
+			// the ground is removed, leaving the obstacle
+			test.That(t, o.Geometry.Pose().Point().Z, test.ShouldAlmostEqual, 800, 1)
+			points += o.PointCloud.Size()
+		}
+		return points
+	}
+	fullPoints := segmentedPoints(cfg)
+	cfg.VoxelSizeMM = 20
+	test.That(t, segmentedPoints(cfg), test.ShouldBeLessThan, fullPoints)
+}
+
+func TestObstacleDepthConfigValidate(t *testing.T) {
+	_, err := (&ObsDepthConfig{}).Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = (&ObsDepthConfig{MinRangeMM: 100, MaxRangeMM: 2000, VoxelSizeMM: 10, GroundNormal: &r3.Vector{Z: 1}}).Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	_, err = (&ObsDepthConfig{MinRangeMM: 2000, MaxRangeMM: 100}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must be greater than min range")
+	_, err = (&ObsDepthConfig{VoxelSizeMM: -1}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = (&ObsDepthConfig{GroundNormal: &r3.Vector{}}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "ground_plane_normal_vec")
+	_, err = (&ObsDepthConfig{MinPtsInSegment: -1}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
 func BenchmarkObstacleDepthIntrinsics(b *testing.B) {
 	someIntrinsics := transform.PinholeCameraIntrinsics{
 		Width:  424,
@@ -25,13 +25,14 @@ import (
 // serviceServer implements the Vision Service.
 type serviceServer struct {
 	pb.UnimplementedVisionServiceServer
-	coll resource.APIResourceCollection[Service]
+	coll     resource.APIResourceCollection[Service]
+	captures *captureStreams
 }
 
 // NewRPCServiceServer constructs a vision gRPC service server.
 // It is intentionally untyped to prevent use outside of tests.
 func NewRPCServiceServer(coll resource.APIResourceCollection[Service]) interface{} {
-	return &serviceServer{coll: coll}
+	return &serviceServer{coll: coll, captures: newCaptureStreams()}
 }
 
 func (server *serviceServer) GetDetections(
@@ -257,13 +258,16 @@ func (server *serviceServer) CaptureAllFromCamera(
 	if err != nil {
 		return nil, err
 	}
+	return captureToProto(ctx, req.CameraName, capt)
+}
 
-	objProto, err := segmentsToProto(req.CameraName, capt.Objects)
+func captureToProto(ctx context.Context, cameraName string, capt viscapture.VisCapture) (*pb.CaptureAllFromCameraResponse, error) {
+	objProto, err := segmentsToProto(cameraName, capt.Objects)
 	if err != nil {
 		return nil, err
 	}
 
-	imgProto, err := imageToProto(ctx, capt.Image, req.CameraName)
+	imgProto, err := imageToProto(ctx, capt.Image, cameraName)
 	if err != nil {
 		return nil, err
 	}
@@ -326,5 +330,11 @@ func (server *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if resp, ok, err := doTracksCommand(ctx, svc, req); ok {
+		return resp, err
+	}
+	if resp, ok, err := doStreamCapturesCommand(ctx, server.captures, svc, req); ok {
+		return resp, err
+	}
 	return rprotoutils.DoFromResourceServer(ctx, svc, req)
 }
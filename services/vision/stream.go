@@ -0,0 +1,431 @@
+package vision
+
+import (
+	"context"
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	pb "go.viam.com/api/service/vision/v1"
+	goutils "go.viam.com/utils"
+	"go.viam.com/utils/protoutils"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/vision/viscapture"
+)
+
+// The vision protos have no streaming RPC. Capture streams are carried over DoCommand using the
+// following reserved keys. The first request of a stream subscribes, after which the server
+// captures from the camera at the requested rate, and each request returns the captures made
+// since the previous one, waiting for the next if there are none.
+const (
+	streamCapturesKey          = "stream_captures"
+	stopCapturesKey            = "stop_captures"
+	captureSubscriptionIDKey   = "subscription_id"
+	captureCameraKey           = "camera_name"
+	captureRateHzKey           = "rate_hz"
+	captureReturnImageKey      = "return_image"
+	captureReturnDetectionsKey = "return_detections"
+	captureReturnClassifsKey   = "return_classifications"
+	captureReturnObjectsKey    = "return_object_point_clouds"
+	captureExtraKey            = "extra"
+	capturesKey                = "captures"
+	captureTimeUnixNanosKey    = "time_unix_nanos"
+	captureEncodedKey          = "capture"
+	defaultCaptureStreamRateHz = 5
+	maxCaptureStreamRateHz     = 60
+	// captureSubscriptionIdleTimeout is how long a subscription is kept without its client asking
+	// for captures, after which it is assumed the client went away.
+	captureSubscriptionIdleTimeout = 10 * time.Second
+	// captureSubscriptionBuffered is the number of captures a subscription holds for its client.
+	captureSubscriptionBuffered = 8
+)
+
+// CaptureStreamOptions configure a capture stream.
+type CaptureStreamOptions struct {
+	viscapture.CaptureOptions
+	// RateHz is how often the camera is captured from. Defaults to 5Hz, and is at most 60Hz. Captures
+	// are made no faster than the vision service can process them.
+	RateHz float64
+}
+
+func (opts CaptureStreamOptions) withDefaults() (CaptureStreamOptions, error) {
+	if opts.RateHz < 0 {
+		return opts, errors.New("capture stream rate cannot be negative")
+	}
+	if opts.RateHz > maxCaptureStreamRateHz {
+		return opts, errors.Errorf("capture stream rate %.0fHz is above the maximum of %dHz", opts.RateHz, maxCaptureStreamRateHz)
+	}
+	if opts.RateHz == 0 {
+		opts.RateHz = defaultCaptureStreamRateHz
+	}
+	return opts, nil
+}
+
+// TimedCapture is a capture and the time it was made.
+type TimedCapture struct {
+	Time time.Time
+	viscapture.VisCapture
+}
+
+// CaptureStreamer is implemented by vision service clients, which have the server capture from the
+// camera and push each capture to them, rather than making a request per capture.
+type CaptureStreamer interface {
+	// StreamCaptures captures from the camera at opts.RateHz and calls `handle` with each capture,
+	// until ctx is done or capturing or `handle` fails.
+	StreamCaptures(
+		ctx context.Context,
+		cameraName string,
+		opts CaptureStreamOptions,
+		extra map[string]interface{},
+		handle func(TimedCapture) error,
+	) error
+}
+
+// StreamCaptures captures the detections, classifications and object point clouds of the camera's
+// frames with `svc` at opts.RateHz, and calls `handle` with each capture until ctx is done or
+// capturing or `handle` fails. Vision services on other machines capture on their server, which
+// sends each capture as soon as it is made, so clients don't poll CaptureAllFromCamera in a loop.
+//
+// StreamCaptures example:
+//
+//	myDetectorService, err := vision.FromRobot(machine, "my_detector")
+//	opts := vision.CaptureStreamOptions{
+//		CaptureOptions: viscapture.CaptureOptions{ReturnDetections: true},
+//		RateHz:         10,
+//	}
+//	err = vision.StreamCaptures(ctx, myDetectorService, "my_camera", opts, nil, func(capture vision.TimedCapture) error {
+//		logger.Infof("%v: %d detections", capture.Time, len(capture.Detections))
+//		return nil
+//	})
+func StreamCaptures(
+	ctx context.Context,
+	svc Service,
+	cameraName string,
+	opts CaptureStreamOptions,
+	extra map[string]interface{},
+	handle func(TimedCapture) error,
+) error {
+	if streamer, ok := svc.(CaptureStreamer); ok {
+		return streamer.StreamCaptures(ctx, cameraName, opts, extra, handle)
+	}
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return err
+	}
+	return sampleCaptures(ctx, svc, cameraName, opts, extra, handle)
+}
+
+// sampleCaptures captures from the camera on a ticker and calls `handle` with each capture.
+func sampleCaptures(
+	ctx context.Context,
+	svc Service,
+	cameraName string,
+	opts CaptureStreamOptions,
+	extra map[string]interface{},
+	handle func(TimedCapture) error,
+) error {
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.RateHz))
+	defer ticker.Stop()
+	for {
+		captured := time.Now()
+		capt, err := svc.CaptureAllFromCamera(ctx, cameraName, opts.CaptureOptions, extra)
+		if err != nil {
+			return err
+		}
+		if err := handle(TimedCapture{Time: captured, VisCapture: capt}); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// captureSubscription captures from a camera on behalf of a client.
+type captureSubscription struct {
+	cancel   context.CancelFunc
+	captures chan encodedCapture
+	done     chan struct{}
+	err      error
+
+	mu       sync.Mutex
+	lastPoll time.Time
+}
+
+// encodedCapture is a capture encoded for its client when it is made, so subscriptions don't hold
+// on to images and point clouds.
+type encodedCapture struct {
+	time    time.Time
+	capture string
+}
+
+func (sub *captureSubscription) poll() {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	sub.lastPoll = time.Now()
+}
+
+func (sub *captureSubscription) idle() bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return time.Since(sub.lastPoll) > captureSubscriptionIdleTimeout
+}
+
+// next returns the captures made since the last call, waiting for one if there are none.
+func (sub *captureSubscription) next(ctx context.Context) ([]encodedCapture, error) {
+	sub.poll()
+	var captures []encodedCapture
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case c := <-sub.captures:
+		captures = append(captures, c)
+	case <-sub.done:
+		// deliver captures made before the subscription ended
+		select {
+		case c := <-sub.captures:
+			captures = append(captures, c)
+		default:
+			if sub.err != nil {
+				return nil, sub.err
+			}
+			return nil, errors.New("capture subscription ended")
+		}
+	}
+	for {
+		select {
+		case c := <-sub.captures:
+			captures = append(captures, c)
+		default:
+			return captures, nil
+		}
+	}
+}
+
+// captureStreams are the capture subscriptions of the vision service server.
+type captureStreams struct {
+	mu   sync.Mutex
+	subs map[string]*captureSubscription
+}
+
+func newCaptureStreams() *captureStreams {
+	return &captureStreams{subs: map[string]*captureSubscription{}}
+}
+
+func (cs *captureStreams) subscribe(
+	svc Service,
+	cameraName string,
+	opts CaptureStreamOptions,
+	extra map[string]interface{},
+) string {
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := &captureSubscription{
+		cancel:   cancel,
+		captures: make(chan encodedCapture, captureSubscriptionBuffered),
+		done:     make(chan struct{}),
+		lastPoll: time.Now(),
+	}
+	id := uuid.NewString()
+	cs.mu.Lock()
+	cs.subs[id] = sub
+	cs.mu.Unlock()
+
+	goutils.PanicCapturingGo(func() {
+		defer close(sub.done)
+		defer cs.remove(id)
+		sub.err = sampleCaptures(ctx, svc, cameraName, opts, extra, func(capt TimedCapture) error {
+			if sub.idle() {
+				return errors.New("capture subscription expired")
+			}
+			resp, err := captureToProto(ctx, cameraName, capt.VisCapture)
+			if err != nil {
+				return err
+			}
+			encoded, err := proto.Marshal(resp)
+			if err != nil {
+				return err
+			}
+			c := encodedCapture{time: capt.Time, capture: base64.StdEncoding.EncodeToString(encoded)}
+			select {
+			case sub.captures <- c:
+			default:
+				// the client is not keeping up, so drop the oldest capture
+				select {
+				case <-sub.captures:
+				default:
+				}
+				sub.captures <- c
+			}
+			return nil
+		})
+	})
+	return id
+}
+
+func (cs *captureStreams) get(id string) (*captureSubscription, bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	sub, ok := cs.subs[id]
+	return sub, ok
+}
+
+func (cs *captureStreams) remove(id string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if sub, ok := cs.subs[id]; ok {
+		sub.cancel()
+		delete(cs.subs, id)
+	}
+}
+
+// doStreamCapturesCommand handles the reserved capture stream DoCommand keys. It returns false if
+// `req` is not a capture stream command.
+func doStreamCapturesCommand(
+	ctx context.Context,
+	cs *captureStreams,
+	svc Service,
+	req *commonpb.DoCommandRequest,
+) (*commonpb.DoCommandResponse, bool, error) {
+	cmd := req.GetCommand().AsMap()
+	if payload, ok := cmd[stopCapturesKey]; ok {
+		args, _ := payload.(map[string]interface{})      //nolint:errcheck
+		id, _ := args[captureSubscriptionIDKey].(string) //nolint:errcheck
+		cs.remove(id)
+		return streamCapturesResponse(map[string]interface{}{})
+	}
+	payload, ok := cmd[streamCapturesKey]
+	if !ok {
+		return nil, false, nil
+	}
+	args, ok := payload.(map[string]interface{})
+	if !ok {
+		return nil, true, errors.Errorf("%q must be an object", streamCapturesKey)
+	}
+	id, _ := args[captureSubscriptionIDKey].(string) //nolint:errcheck
+	if id == "" {
+		cameraName, _ := args[captureCameraKey].(string)           //nolint:errcheck
+		rate, _ := args[captureRateHzKey].(float64)                //nolint:errcheck
+		extra, _ := args[captureExtraKey].(map[string]interface{}) //nolint:errcheck
+		opts := CaptureStreamOptions{RateHz: rate}
+		opts.ReturnImage, _ = args[captureReturnImageKey].(bool)              //nolint:errcheck
+		opts.ReturnDetections, _ = args[captureReturnDetectionsKey].(bool)    //nolint:errcheck
+		opts.ReturnClassifications, _ = args[captureReturnClassifsKey].(bool) //nolint:errcheck
+		opts.ReturnObject, _ = args[captureReturnObjectsKey].(bool)           //nolint:errcheck
+		opts, err := opts.withDefaults()
+		if err != nil {
+			return nil, true, err
+		}
+		id = cs.subscribe(svc, cameraName, opts, extra)
+	}
+	sub, ok := cs.get(id)
+	if !ok {
+		return nil, true, errors.Errorf("no capture subscription %q", id)
+	}
+	captures, err := sub.next(ctx)
+	if err != nil {
+		return nil, true, err
+	}
+	encoded := make([]interface{}, 0, len(captures))
+	for _, c := range captures {
+		encoded = append(encoded, map[string]interface{}{
+			captureTimeUnixNanosKey: float64(c.time.UnixNano()),
+			captureEncodedKey:       c.capture,
+		})
+	}
+	return streamCapturesResponse(map[string]interface{}{captureSubscriptionIDKey: id, capturesKey: encoded})
+}
+
+func streamCapturesResponse(result map[string]interface{}) (*commonpb.DoCommandResponse, bool, error) {
+	res, err := protoutils.StructToStructPb(result)
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
+
+// StreamCaptures has the server capture from the camera and receives each capture as it is made.
+func (c *client) StreamCaptures(
+	ctx context.Context,
+	cameraName string,
+	opts CaptureStreamOptions,
+	extra map[string]interface{},
+	handle func(TimedCapture) error,
+) error {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return err
+	}
+	var id string
+	defer func() {
+		if id == "" {
+			return
+		}
+		// let the server stop capturing now rather than when the subscription expires
+		stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		//nolint:errcheck
+		c.DoCommand(stopCtx, map[string]interface{}{stopCapturesKey: map[string]interface{}{captureSubscriptionIDKey: id}})
+	}()
+	for {
+		args := map[string]interface{}{captureSubscriptionIDKey: id}
+		if id == "" {
+			args = map[string]interface{}{
+				captureCameraKey:           cameraName,
+				captureRateHzKey:           opts.RateHz,
+				captureReturnImageKey:      opts.ReturnImage,
+				captureReturnDetectionsKey: opts.ReturnDetections,
+				captureReturnClassifsKey:   opts.ReturnClassifications,
+				captureReturnObjectsKey:    opts.ReturnObject,
+				captureExtraKey:            extra,
+			}
+		}
+		resp, err := c.DoCommand(ctx, map[string]interface{}{streamCapturesKey: args})
+		if err != nil {
+			return err
+		}
+		if id, _ = resp[captureSubscriptionIDKey].(string); id == "" { //nolint:errcheck
+			return errors.Errorf("expected %q in response, got %v", captureSubscriptionIDKey, resp)
+		}
+		list, ok := resp[capturesKey].([]interface{})
+		if !ok {
+			return errors.Errorf("%q must be a list", capturesKey)
+		}
+		for _, raw := range list {
+			capt, err := decodeTimedCapture(ctx, raw)
+			if err != nil {
+				return err
+			}
+			if err := handle(capt); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decodeTimedCapture(ctx context.Context, raw interface{}) (TimedCapture, error) {
+	entry, ok := raw.(map[string]interface{})
+	if !ok {
+		return TimedCapture{}, errors.Errorf("%q entries must be objects", capturesKey)
+	}
+	nanos, _ := entry[captureTimeUnixNanosKey].(float64) //nolint:errcheck
+	encoded, _ := entry[captureEncodedKey].(string)      //nolint:errcheck
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return TimedCapture{}, errors.Wrap(err, "invalid capture")
+	}
+	var resp pb.CaptureAllFromCameraResponse
+	if err := proto.Unmarshal(data, &resp); err != nil {
+		return TimedCapture{}, errors.Wrap(err, "invalid capture")
+	}
+	capt, err := captureFromProto(ctx, &resp)
+	if err != nil {
+		return TimedCapture{}, err
+	}
+	return TimedCapture{Time: time.Unix(0, int64(nanos)), VisCapture: capt}, nil
+}
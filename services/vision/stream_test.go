@@ -0,0 +1,146 @@
+package vision_test
+
+import (
+	"context"
+	"errors"
+	"image"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/classification"
+	"go.viam.com/rdk/vision/objectdetection"
+	"go.viam.com/rdk/vision/viscapture"
+)
+
+var (
+	errEnoughCaptures = errors.New("enough captures")
+	errCaptureFailed  = errors.New("capture failed")
+)
+
+// newCountingVisionService returns a vision service that detects as many objects as it has been
+// captured with.
+func newCountingVisionService(name string) (*inject.VisionService, *atomic.Int64) {
+	var count atomic.Int64
+	svc := inject.NewVisionService(name)
+	svc.CaptureAllFromCameraFunc = func(
+		ctx context.Context, cameraName string, opts viscapture.CaptureOptions, extra map[string]interface{},
+	) (viscapture.VisCapture, error) {
+		n := int(count.Add(1))
+		var capt viscapture.VisCapture
+		if opts.ReturnDetections {
+			for i := 0; i < n; i++ {
+				capt.Detections = append(capt.Detections,
+					objectdetection.NewDetection(image.Rect(0, 0, 100, 100), image.Rect(i, i, i+10, i+10), 0.5, cameraName))
+			}
+		}
+		if opts.ReturnClassifications {
+			label, _ := extra["label"].(string)
+			capt.Classifications = classification.Classifications{classification.NewClassification(0.7, label)}
+		}
+		return capt, nil
+	}
+	return svc, &count
+}
+
+func TestStreamCapturesLocal(t *testing.T) {
+	svc, _ := newCountingVisionService(testVisionServiceName)
+	var captures []vision.TimedCapture
+	opts := vision.CaptureStreamOptions{CaptureOptions: viscapture.CaptureOptions{ReturnDetections: true}, RateHz: 50}
+	err := vision.StreamCaptures(context.Background(), svc, testCameraName, opts, nil, func(capt vision.TimedCapture) error {
+		captures = append(captures, capt)
+		if len(captures) == 3 {
+			return errEnoughCaptures
+		}
+		return nil
+	})
+	test.That(t, err, test.ShouldBeError, errEnoughCaptures)
+	test.That(t, captures, test.ShouldHaveLength, 3)
+	test.That(t, captures[2].Detections, test.ShouldHaveLength, 3)
+	test.That(t, captures[2].Classifications, test.ShouldBeEmpty)
+	test.That(t, captures[2].Time.After(captures[0].Time), test.ShouldBeTrue)
+
+	opts.RateHz = 1000
+	err = vision.StreamCaptures(context.Background(), svc, testCameraName, opts, nil,
+		func(capt vision.TimedCapture) error { return nil })
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestStreamCapturesClient(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	working, count := newCountingVisionService(testVisionServiceName)
+	failing := inject.NewVisionService(testVisionServiceName2)
+	failing.CaptureAllFromCameraFunc = func(
+		ctx context.Context, cameraName string, opts viscapture.CaptureOptions, extra map[string]interface{},
+	) (viscapture.VisCapture, error) {
+		return viscapture.VisCapture{}, errCaptureFailed
+	}
+	svc, err := resource.NewAPIResourceCollection(vision.API, map[resource.Name]vision.Service{
+		vision.Named(testVisionServiceName):  working,
+		vision.Named(testVisionServiceName2): failing,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[vision.Service](vision.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, svc), test.ShouldBeNil)
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() { test.That(t, conn.Close(), test.ShouldBeNil) }()
+
+	client, err := vision.NewClientFromConn(context.Background(), conn, "", vision.Named(testVisionServiceName), logger)
+	test.That(t, err, test.ShouldBeNil)
+	_, ok = client.(vision.CaptureStreamer)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	var received []vision.TimedCapture
+	opts := vision.CaptureStreamOptions{
+		CaptureOptions: viscapture.CaptureOptions{ReturnDetections: true, ReturnClassifications: true},
+		RateHz:         50,
+	}
+	err = vision.StreamCaptures(context.Background(), client, testCameraName, opts, map[string]interface{}{"label": "cat"},
+		func(capt vision.TimedCapture) error {
+			received = append(received, capt)
+			if len(received) >= 5 {
+				return errEnoughCaptures
+			}
+			return nil
+		})
+	test.That(t, err, test.ShouldBeError, errEnoughCaptures)
+	test.That(t, received, test.ShouldHaveLength, 5)
+	// captures arrive in order and were all made on the server
+	for i := 1; i < len(received); i++ {
+		test.That(t, len(received[i].Detections), test.ShouldBeGreaterThan, len(received[i-1].Detections))
+		test.That(t, received[i].Time.Before(received[i-1].Time), test.ShouldBeFalse)
+	}
+	det := received[0].Detections[0]
+	test.That(t, det.Label(), test.ShouldEqual, testCameraName)
+	test.That(t, det.NormalizedBoundingBox(), test.ShouldResemble, []float64{0, 0, 0.1, 0.1})
+	test.That(t, received[0].Classifications, test.ShouldHaveLength, 1)
+	test.That(t, received[0].Classifications[0].Label(), test.ShouldEqual, "cat")
+	test.That(t, received[0].Image, test.ShouldBeNil)
+	test.That(t, count.Load(), test.ShouldBeGreaterThanOrEqualTo, 5)
+
+	failingClient, err := vision.NewClientFromConn(context.Background(), conn, "", vision.Named(testVisionServiceName2), logger)
+	test.That(t, err, test.ShouldBeNil)
+	err = vision.StreamCaptures(context.Background(), failingClient, testCameraName, vision.CaptureStreamOptions{}, nil,
+		func(capt vision.TimedCapture) error { return nil })
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, errCaptureFailed.Error())
+}
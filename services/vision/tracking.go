@@ -0,0 +1,233 @@
+package vision
+
+import (
+	"context"
+	"image"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/utils/protoutils"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// The vision proto has no tracking RPC. GetTracks is carried over DoCommand using the following
+// reserved keys.
+const (
+	getTracksKey        = "get_tracks"
+	tracksCameraKey     = "camera_name"
+	tracksExtraKey      = "extra"
+	tracksKey           = "tracks"
+	trackIDKey          = "id"
+	trackVelocityXKey   = "velocity_x"
+	trackVelocityYKey   = "velocity_y"
+	trackHitsKey        = "hits"
+	trackMissesKey      = "misses"
+	trackFirstSeenKey   = "first_seen"
+	trackLastSeenKey    = "last_seen"
+	trackXMinKey        = "x_min"
+	trackYMinKey        = "y_min"
+	trackXMaxKey        = "x_max"
+	trackYMaxKey        = "y_max"
+	trackImageWidthKey  = "image_width"
+	trackImageHeightKey = "image_height"
+	trackScoreKey       = "score"
+	trackLabelKey       = "label"
+)
+
+// Tracker is implemented by vision services that can follow detected objects across frames,
+// giving each object an ID that persists for as long as it is tracked.
+//
+// Tracks example:
+//
+//	myDetectorService, err := vision.FromRobot(machine, "my_detector")
+//	if tracker, ok := myDetectorService.(vision.Tracker); ok {
+//		// Call Tracks for every frame to follow objects across them
+//		tracks, err := tracker.Tracks(context.Background(), "my_camera", nil)
+//		for _, track := range tracks {
+//			logger.Infof("object %d is a %s at %v", track.ID, track.Detection.Label(), track.Detection.BoundingBox())
+//		}
+//	}
+type Tracker interface {
+	// Tracks detects the objects in the next image from the camera, continues the camera's tracks
+	// with them, and returns the tracks.
+	Tracks(ctx context.Context, cameraName string, extra map[string]interface{}) ([]objectdetection.Track, error)
+}
+
+// Tracks returns the tracks of the given camera after detecting the objects in its next image.
+// Each camera is tracked separately.
+func (vm *vizModel) Tracks(
+	ctx context.Context,
+	cameraName string,
+	extra map[string]interface{},
+) ([]objectdetection.Track, error) {
+	ctx, span := trace.StartSpan(ctx, "service::vision::Tracks::"+vm.Named.Name().String())
+	defer span.End()
+	if cameraName == "" && vm.defaultCamera == "" {
+		return nil, errors.New("no camera name provided and no default camera found")
+	} else if cameraName == "" {
+		cameraName = vm.defaultCamera
+	}
+	if vm.detectorFunc == nil {
+		return nil, errors.Errorf("vision model %q does not implement a Detector", vm.Named.Name())
+	}
+	cam, err := camera.FromRobot(vm.r, cameraName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not find camera named %s", cameraName)
+	}
+	img, err := camera.DecodeImageFromCamera(ctx, "", extra, cam)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get image from %s", cameraName)
+	}
+	captured := time.Now()
+	detections, err := vm.detectorFunc(ctx, img)
+	if err != nil {
+		return nil, err
+	}
+
+	vm.trackersMu.Lock()
+	defer vm.trackersMu.Unlock()
+	tracker, ok := vm.trackers[cameraName]
+	if !ok {
+		tracker = objectdetection.NewTracker(objectdetection.DefaultTrackerConfig)
+		vm.trackers[cameraName] = tracker
+	}
+	return tracker.Update(detections, img.Bounds(), captured), nil
+}
+
+func (c *client) Tracks(
+	ctx context.Context,
+	cameraName string,
+	extra map[string]interface{},
+) ([]objectdetection.Track, error) {
+	ctx, span := trace.StartSpan(ctx, "service::vision::client::Tracks")
+	defer span.End()
+	resp, err := c.DoCommand(ctx, map[string]interface{}{
+		getTracksKey: map[string]interface{}{tracksCameraKey: cameraName, tracksExtraKey: extra},
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoded, ok := resp[tracksKey].([]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected %q in response, got %v", tracksKey, resp)
+	}
+	tracks := make([]objectdetection.Track, 0, len(encoded))
+	for _, e := range encoded {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("invalid track %v", e)
+		}
+		track, err := trackFromMap(m)
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks, nil
+}
+
+// doTracksCommand handles the reserved GetTracks DoCommand key. It returns false if `req` is not a
+// GetTracks command or the service does not implement Tracker.
+func doTracksCommand(ctx context.Context, svc Service, req *commonpb.DoCommandRequest) (*commonpb.DoCommandResponse, bool, error) {
+	tracker, ok := svc.(Tracker)
+	if !ok {
+		return nil, false, nil
+	}
+	payload, ok := req.GetCommand().AsMap()[getTracksKey]
+	if !ok {
+		return nil, false, nil
+	}
+	args, _ := payload.(map[string]interface{})               //nolint:errcheck
+	cameraName, _ := args[tracksCameraKey].(string)           //nolint:errcheck
+	extra, _ := args[tracksExtraKey].(map[string]interface{}) //nolint:errcheck
+	tracks, err := tracker.Tracks(ctx, cameraName, extra)
+	if err != nil {
+		return nil, true, err
+	}
+	encoded := make([]interface{}, 0, len(tracks))
+	for _, track := range tracks {
+		encoded = append(encoded, trackToMap(track))
+	}
+	res, err := protoutils.StructToStructPb(map[string]interface{}{tracksKey: encoded})
+	if err != nil {
+		return nil, true, err
+	}
+	return &commonpb.DoCommandResponse{Result: res}, true, nil
+}
+
+func trackToMap(track objectdetection.Track) map[string]interface{} {
+	box := track.Detection.BoundingBox()
+	m := map[string]interface{}{
+		trackIDKey:        float64(track.ID),
+		trackVelocityXKey: track.VelocityX,
+		trackVelocityYKey: track.VelocityY,
+		trackHitsKey:      float64(track.Hits),
+		trackMissesKey:    float64(track.Misses),
+		trackFirstSeenKey: track.FirstSeen.Format(time.RFC3339Nano),
+		trackLastSeenKey:  track.LastSeen.Format(time.RFC3339Nano),
+		trackXMinKey:      float64(box.Min.X),
+		trackYMinKey:      float64(box.Min.Y),
+		trackXMaxKey:      float64(box.Max.X),
+		trackYMaxKey:      float64(box.Max.Y),
+		trackScoreKey:     track.Detection.Score(),
+		trackLabelKey:     track.Detection.Label(),
+	}
+	// the image bounds are recovered from the normalized box, as in protoToDets
+	if norm := track.Detection.NormalizedBoundingBox(); len(norm) == 4 && norm[2] > 0 && norm[3] > 0 {
+		m[trackImageWidthKey] = float64(box.Max.X) / norm[2]
+		m[trackImageHeightKey] = float64(box.Max.Y) / norm[3]
+	}
+	return m
+}
+
+func trackFromMap(m map[string]interface{}) (objectdetection.Track, error) {
+	number := func(key string) (float64, error) {
+		v, ok := m[key].(float64)
+		if !ok {
+			return 0, errors.Errorf("invalid track %v: missing %q", m, key)
+		}
+		return v, nil
+	}
+	var coords [4]float64
+	for i, key := range []string{trackXMinKey, trackYMinKey, trackXMaxKey, trackYMaxKey} {
+		v, err := number(key)
+		if err != nil {
+			return objectdetection.Track{}, err
+		}
+		coords[i] = v
+	}
+	id, err := number(trackIDKey)
+	if err != nil {
+		return objectdetection.Track{}, err
+	}
+	box := image.Rect(int(coords[0]), int(coords[1]), int(coords[2]), int(coords[3]))
+	score, _ := m[trackScoreKey].(float64) //nolint:errcheck
+	label, _ := m[trackLabelKey].(string)  //nolint:errcheck
+	var det objectdetection.Detection
+	width, hasWidth := m[trackImageWidthKey].(float64)
+	height, hasHeight := m[trackImageHeightKey].(float64)
+	if hasWidth && hasHeight {
+		det = objectdetection.NewDetection(image.Rect(0, 0, int(width+0.5), int(height+0.5)), box, score, label)
+	} else {
+		det = objectdetection.NewDetectionWithoutImgBounds(box, score, label)
+	}
+
+	track := objectdetection.Track{ID: int64(id), Detection: det}
+	track.VelocityX, _ = m[trackVelocityXKey].(float64) //nolint:errcheck
+	track.VelocityY, _ = m[trackVelocityYKey].(float64) //nolint:errcheck
+	hits, _ := m[trackHitsKey].(float64)                //nolint:errcheck
+	misses, _ := m[trackMissesKey].(float64)            //nolint:errcheck
+	track.Hits, track.Misses = int(hits), int(misses)
+	for key, t := range map[string]*time.Time{trackFirstSeenKey: &track.FirstSeen, trackLastSeenKey: &track.LastSeen} {
+		if s, ok := m[key].(string); ok {
+			if *t, err = time.Parse(time.RFC3339Nano, s); err != nil {
+				return objectdetection.Track{}, errors.Wrapf(err, "invalid track %q", key)
+			}
+		}
+	}
+	return track, nil
+}
@@ -0,0 +1,156 @@
+package vision_test
+
+import (
+	"context"
+	"image"
+	"net"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/components/camera"
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/testutils"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestTracks(t *testing.T) {
+	fakeCamera := &inject.Camera{
+		ImageFunc: func(ctx context.Context, mimeType string, extra map[string]interface{}) ([]byte, camera.ImageMetadata, error) {
+			imgBytes, err := rimage.EncodeImage(ctx, image.NewRGBA(image.Rect(0, 0, 200, 100)), utils.MimeTypePNG)
+			test.That(t, err, test.ShouldBeNil)
+			return imgBytes, camera.ImageMetadata{MimeType: utils.MimeTypePNG}, nil
+		},
+	}
+	var r inject.Robot
+	r.ResourceByNameFunc = func(name resource.Name) (resource.Resource, error) {
+		return fakeCamera, nil
+	}
+
+	// an object moves 5 pixels to the right every frame
+	frame := 0
+	detect := func(ctx context.Context, img image.Image) ([]objectdetection.Detection, error) {
+		frame++
+		box := image.Rect(10+5*frame, 10, 40+5*frame, 40)
+		return []objectdetection.Detection{objectdetection.NewDetection(img.Bounds(), box, 0.9, "ball")}, nil
+	}
+	svc, err := vision.NewService(vision.Named("testService"), &r, nil, nil, detect, nil, testCameraName)
+	test.That(t, err, test.ShouldBeNil)
+	tracker, ok := svc.(vision.Tracker)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	tracks, err := tracker.Tracks(context.Background(), "", nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tracks, test.ShouldBeEmpty)
+	var id int64
+	for i := 0; i < 3; i++ {
+		tracks, err = tracker.Tracks(context.Background(), "", nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(tracks), test.ShouldEqual, 1)
+		if id == 0 {
+			id = tracks[0].ID
+		}
+		test.That(t, tracks[0].ID, test.ShouldEqual, id)
+		test.That(t, tracks[0].Detection.Label(), test.ShouldEqual, "ball")
+	}
+
+	// each camera is tracked separately
+	tracks, err = tracker.Tracks(context.Background(), "other_camera", nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tracks, test.ShouldBeEmpty)
+
+	noDetector, err := vision.NewService(vision.Named("testService"), &r, nil, nil, nil, (&simpleSegmenter{}).Segment, "")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = noDetector.(vision.Tracker).Tracks(context.Background(), testCameraName, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "does not implement a Detector")
+}
+
+func TestClientTracks(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	seen := time.Now().UTC()
+	expected := []objectdetection.Track{
+		{
+			ID:        4,
+			Detection: objectdetection.NewDetection(image.Rect(0, 0, 640, 480), image.Rect(64, 48, 128, 96), 0.9, "person"),
+			VelocityX: 12.5,
+			VelocityY: -3,
+			Hits:      10,
+			Misses:    1,
+			FirstSeen: seen.Add(-time.Second),
+			LastSeen:  seen,
+		},
+		{
+			ID:        7,
+			Detection: objectdetection.NewDetectionWithoutImgBounds(image.Rect(1, 2, 3, 4), 0.5, "dog"),
+			Hits:      2,
+			FirstSeen: seen,
+			LastSeen:  seen,
+		},
+	}
+	var receivedCamera string
+	var receivedExtra map[string]interface{}
+	injectVision := inject.NewVisionService(visName1.Name)
+	injectVision.TracksFunc = func(
+		ctx context.Context, cameraName string, extra map[string]interface{},
+	) ([]objectdetection.Track, error) {
+		receivedCamera, receivedExtra = cameraName, extra
+		return expected, nil
+	}
+	injectVision.DoCommandFunc = testutils.EchoFunc
+
+	svc, err := resource.NewAPIResourceCollection(vision.API, map[resource.Name]vision.Service{visName1: injectVision})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[vision.Service](vision.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, svc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	client, err := vision.NewClientFromConn(context.Background(), conn, "", visName1, logger)
+	test.That(t, err, test.ShouldBeNil)
+	tracker, ok := client.(vision.Tracker)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	tracks, err := tracker.Tracks(context.Background(), "my_camera", map[string]interface{}{"foo": "bar"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, receivedCamera, test.ShouldEqual, "my_camera")
+	test.That(t, receivedExtra, test.ShouldResemble, map[string]interface{}{"foo": "bar"})
+	test.That(t, len(tracks), test.ShouldEqual, 2)
+	for i, track := range tracks {
+		test.That(t, track.ID, test.ShouldEqual, expected[i].ID)
+		test.That(t, track.VelocityX, test.ShouldEqual, expected[i].VelocityX)
+		test.That(t, track.VelocityY, test.ShouldEqual, expected[i].VelocityY)
+		test.That(t, track.Hits, test.ShouldEqual, expected[i].Hits)
+		test.That(t, track.Misses, test.ShouldEqual, expected[i].Misses)
+		test.That(t, track.FirstSeen.Equal(expected[i].FirstSeen), test.ShouldBeTrue)
+		test.That(t, track.LastSeen.Equal(expected[i].LastSeen), test.ShouldBeTrue)
+		test.That(t, track.Detection.BoundingBox(), test.ShouldResemble, expected[i].Detection.BoundingBox())
+		test.That(t, track.Detection.NormalizedBoundingBox(), test.ShouldResemble, expected[i].Detection.NormalizedBoundingBox())
+		test.That(t, track.Detection.Score(), test.ShouldEqual, expected[i].Detection.Score())
+		test.That(t, track.Detection.Label(), test.ShouldEqual, expected[i].Detection.Label())
+	}
+
+	// other commands still reach DoCommand
+	resp, err := client.DoCommand(context.Background(), testutils.TestCommand)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["command"], test.ShouldEqual, testutils.TestCommand["command"])
+}
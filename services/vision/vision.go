@@ -8,6 +8,7 @@ package vision
 import (
 	"context"
 	"image"
+	"sync"
 
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
@@ -246,6 +247,9 @@ type vizModel struct {
 	detectorFunc    objectdetection.Detector
 	segmenter3DFunc segmentation.Segmenter
 	defaultCamera   string
+
+	trackersMu sync.Mutex
+	trackers   map[string]*objectdetection.Tracker // by camera name
 }
 
 // Properties returns various information regarding the current vision service,
@@ -291,6 +295,7 @@ func NewService(
 		detectorFunc:    df,
 		segmenter3DFunc: s3f,
 		defaultCamera:   defaultCamera,
+		trackers:        map[string]*objectdetection.Tracker{},
 	}, nil
 }
 
@@ -6,11 +6,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/google/uuid"
 	pb "go.viam.com/api/robot/v1"
 	"go.viam.com/utils/rpc"
 
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
 )
 
 // A Session allows a client to express that it is actively connected and
@@ -22,6 +24,7 @@ type Session struct {
 	ownerID         []byte
 	deadline        time.Time
 	heartbeatWindow time.Duration
+	clk             clock.Clock
 
 	associateResource func(id uuid.UUID, resourceName resource.Name)
 }
@@ -36,7 +39,7 @@ func New(
 	return NewWithID(ctx, uuid.New(), ownerID, heartbeatWindow, associateResource)
 }
 
-// NewWithID makes a new session with an ID.
+// NewWithID makes a new session with an ID. The session expires by the clock ctx carries, if any.
 func NewWithID(
 	ctx context.Context,
 	id uuid.UUID,
@@ -48,6 +51,7 @@ func NewWithID(
 		id:                id,
 		ownerID:           []byte(ownerID),
 		heartbeatWindow:   heartbeatWindow,
+		clk:               utils.ClockFromContext(ctx),
 		associateResource: associateResource,
 	}
 	sess.Heartbeat(ctx)
@@ -67,7 +71,7 @@ func (s *Session) CheckOwnerID(against string) bool {
 // Heartbeat signals a single heartbeat to the session.
 func (s *Session) Heartbeat(ctx context.Context) {
 	s.mu.Lock()
-	s.deadline = time.Now().Add(s.heartbeatWindow)
+	s.deadline = s.clk.Now().Add(s.heartbeatWindow)
 	s.peerConnInfo = peerConnectionInfoToProto(rpc.PeerConnectionInfoFromContext(ctx))
 	s.mu.Unlock()
 }
@@ -97,7 +101,7 @@ func (s *Session) Deadline() time.Time {
 }
 
 func (s *Session) associateWith(targetName resource.Name) {
-	if !s.Active(time.Now()) {
+	if !s.Active(s.clk.Now()) {
 		return
 	}
 	if s.associateResource != nil {
@@ -6,12 +6,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/google/uuid"
 	v1 "go.viam.com/api/robot/v1"
 	"go.viam.com/test"
 	"google.golang.org/grpc/peer"
 
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
 )
 
 func TestNew(t *testing.T) {
@@ -100,3 +102,16 @@ func TestNewWithID(t *testing.T) {
 	test.That(t, sess1.Deadline().After(now), test.ShouldBeTrue)
 	test.That(t, sess1.Deadline().Before(now.Add(2*dur)), test.ShouldBeFalse)
 }
+
+func TestNewWithClock(t *testing.T) {
+	mockClock := clock.NewMock()
+	ctx := utils.WithClock(context.Background(), mockClock)
+	sess := New(ctx, "owner1", time.Second, nil)
+	test.That(t, sess.Deadline(), test.ShouldEqual, mockClock.Now().Add(time.Second))
+	test.That(t, sess.Active(mockClock.Now()), test.ShouldBeTrue)
+
+	mockClock.Add(time.Second)
+	test.That(t, sess.Active(mockClock.Now()), test.ShouldBeFalse)
+	sess.Heartbeat(ctx)
+	test.That(t, sess.Active(mockClock.Now()), test.ShouldBeTrue)
+}
@@ -0,0 +1,238 @@
+package spatialmath
+
+import (
+	"encoding/json"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+)
+
+// GeoPolygon is a polygon of GPS coordinates. Its first ring is its exterior, and any other rings are holes in it.
+// Rings are implicitly closed, and edges are treated as straight lines in latitude and longitude, which is accurate
+// for regions up to a few kilometers across.
+type GeoPolygon struct {
+	Rings [][]*geo.Point
+}
+
+// NewGeoPolygon returns a polygon with the given exterior ring and holes.
+func NewGeoPolygon(exterior []*geo.Point, holes ...[]*geo.Point) (*GeoPolygon, error) {
+	rings := append([][]*geo.Point{exterior}, holes...)
+	for _, ring := range rings {
+		if len(ring) < 3 {
+			return nil, errors.New("polygon rings need at least 3 points")
+		}
+	}
+	return &GeoPolygon{Rings: rings}, nil
+}
+
+// Contains returns whether the point is inside the polygon and outside its holes.
+func (p *GeoPolygon) Contains(pt *geo.Point) bool {
+	inside := false
+	for _, ring := range p.Rings {
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if (a.Lat() > pt.Lat()) != (b.Lat() > pt.Lat()) &&
+				pt.Lng() < a.Lng()+(pt.Lat()-a.Lat())*(b.Lng()-a.Lng())/(b.Lat()-a.Lat()) {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// CrossedBy returns whether the segment from `a` to `b` touches or crosses any of the polygon's edges.
+func (p *GeoPolygon) CrossedBy(a, b *geo.Point) bool {
+	for _, ring := range p.Rings {
+		for i := range ring {
+			if segmentsIntersect(a, b, ring[i], ring[(i+1)%len(ring)]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// segmentsIntersect returns whether segment p1-p2 touches segment q1-q2, treating longitude as x and latitude as y.
+func segmentsIntersect(p1, p2, q1, q2 *geo.Point) bool {
+	cross := func(o, a, b *geo.Point) float64 {
+		return (a.Lng()-o.Lng())*(b.Lat()-o.Lat()) - (a.Lat()-o.Lat())*(b.Lng()-o.Lng())
+	}
+	onSegment := func(a, b, pt *geo.Point) bool {
+		return pt.Lng() >= min(a.Lng(), b.Lng()) && pt.Lng() <= max(a.Lng(), b.Lng()) &&
+			pt.Lat() >= min(a.Lat(), b.Lat()) && pt.Lat() <= max(a.Lat(), b.Lat())
+	}
+	d1, d2 := cross(q1, q2, p1), cross(q1, q2, p2)
+	d3, d4 := cross(p1, p2, q1), cross(p1, p2, q2)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(q1, q2, p1)) || (d2 == 0 && onSegment(q1, q2, p2)) ||
+		(d3 == 0 && onSegment(p1, p2, q1)) || (d4 == 0 && onSegment(p1, p2, q2))
+}
+
+// geoJSONObject holds the members of any GeoJSON object used by GeoPolygonsFromGeoJSON and GeoPointsFromGeoJSON.
+type geoJSONObject struct {
+	Type        string           `json:"type"`
+	Coordinates json.RawMessage  `json:"coordinates"`
+	Geometry    *geoJSONObject   `json:"geometry"`
+	Geometries  []*geoJSONObject `json:"geometries"`
+	Features    []*geoJSONObject `json:"features"`
+}
+
+// GeoPolygonsFromGeoJSON returns the polygons of a GeoJSON Polygon or MultiPolygon, or of all the polygons in a
+// Feature, FeatureCollection or GeometryCollection. Other geometries are ignored, and it is an error for there to be
+// no polygons at all.
+func GeoPolygonsFromGeoJSON(data []byte) ([]*GeoPolygon, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, errors.Wrap(err, "invalid GeoJSON")
+	}
+	polygons, err := obj.polygons()
+	if err != nil {
+		return nil, err
+	}
+	if len(polygons) == 0 {
+		return nil, errors.New("GeoJSON contains no polygons")
+	}
+	return polygons, nil
+}
+
+// GeoPointsFromGeoJSON returns, in order, the positions of the Points, MultiPoints and LineStrings of a GeoJSON object,
+// including those in a Feature, FeatureCollection or GeometryCollection. Other geometries are ignored, and it is an
+// error for there to be no points at all.
+func GeoPointsFromGeoJSON(data []byte) ([]*geo.Point, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, errors.Wrap(err, "invalid GeoJSON")
+	}
+	points, err := obj.points()
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, errors.New("GeoJSON contains no points")
+	}
+	return points, nil
+}
+
+func (obj *geoJSONObject) points() ([]*geo.Point, error) {
+	switch obj.Type {
+	case "Point":
+		var position []float64
+		if err := json.Unmarshal(obj.Coordinates, &position); err != nil {
+			return nil, errors.Wrap(err, "invalid Point coordinates")
+		}
+		return geoPointsFromPositions([][]float64{position})
+	case "MultiPoint", "LineString":
+		var positions [][]float64
+		if err := json.Unmarshal(obj.Coordinates, &positions); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s coordinates", obj.Type)
+		}
+		return geoPointsFromPositions(positions)
+	case "Feature":
+		if obj.Geometry == nil {
+			return nil, nil
+		}
+		return obj.Geometry.points()
+	case "FeatureCollection", "GeometryCollection":
+		var points []*geo.Point
+		for _, child := range append(obj.Features, obj.Geometries...) {
+			if child == nil {
+				continue
+			}
+			childPoints, err := child.points()
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, childPoints...)
+		}
+		return points, nil
+	case "":
+		return nil, errors.New("GeoJSON object has no type")
+	default:
+		return nil, nil
+	}
+}
+
+// geoPointsFromPositions converts GeoJSON [longitude, latitude] positions to points.
+func geoPointsFromPositions(positions [][]float64) ([]*geo.Point, error) {
+	points := make([]*geo.Point, 0, len(positions))
+	for _, position := range positions {
+		if len(position) < 2 {
+			return nil, errors.New("GeoJSON positions need a longitude and a latitude")
+		}
+		points = append(points, geo.NewPoint(position[1], position[0]))
+	}
+	return points, nil
+}
+
+func (obj *geoJSONObject) polygons() ([]*GeoPolygon, error) {
+	switch obj.Type {
+	case "Polygon":
+		var coords [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &coords); err != nil {
+			return nil, errors.Wrap(err, "invalid Polygon coordinates")
+		}
+		polygon, err := geoPolygonFromCoordinates(coords)
+		if err != nil {
+			return nil, err
+		}
+		return []*GeoPolygon{polygon}, nil
+	case "MultiPolygon":
+		var coords [][][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &coords); err != nil {
+			return nil, errors.Wrap(err, "invalid MultiPolygon coordinates")
+		}
+		polygons := make([]*GeoPolygon, 0, len(coords))
+		for _, polygonCoords := range coords {
+			polygon, err := geoPolygonFromCoordinates(polygonCoords)
+			if err != nil {
+				return nil, err
+			}
+			polygons = append(polygons, polygon)
+		}
+		return polygons, nil
+	case "Feature":
+		if obj.Geometry == nil {
+			return nil, nil
+		}
+		return obj.Geometry.polygons()
+	case "FeatureCollection", "GeometryCollection":
+		var polygons []*GeoPolygon
+		for _, child := range append(obj.Features, obj.Geometries...) {
+			if child == nil {
+				continue
+			}
+			childPolygons, err := child.polygons()
+			if err != nil {
+				return nil, err
+			}
+			polygons = append(polygons, childPolygons...)
+		}
+		return polygons, nil
+	case "":
+		return nil, errors.New("GeoJSON object has no type")
+	default:
+		return nil, nil
+	}
+}
+
+// geoPolygonFromCoordinates converts GeoJSON polygon coordinates, which are [longitude, latitude] positions, to a
+// GeoPolygon. The closing position GeoJSON repeats at the end of each ring is dropped.
+func geoPolygonFromCoordinates(coords [][][]float64) (*GeoPolygon, error) {
+	if len(coords) == 0 {
+		return nil, errors.New("polygon has no rings")
+	}
+	rings := make([][]*geo.Point, 0, len(coords))
+	for _, ringCoords := range coords {
+		ring, err := geoPointsFromPositions(ringCoords)
+		if err != nil {
+			return nil, err
+		}
+		if len(ring) > 1 && *ring[0] == *ring[len(ring)-1] {
+			ring = ring[:len(ring)-1]
+		}
+		rings = append(rings, ring)
+	}
+	return NewGeoPolygon(rings[0], rings[1:]...)
+}
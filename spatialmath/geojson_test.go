@@ -0,0 +1,82 @@
+package spatialmath
+
+import (
+	"testing"
+
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+)
+
+func TestGeoPolygonsFromGeoJSON(t *testing.T) {
+	t.Run("polygon with a hole", func(t *testing.T) {
+		polygons, err := GeoPolygonsFromGeoJSON([]byte(`{
+			"type": "Polygon",
+			"coordinates": [
+				[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]],
+				[[4, 4], [6, 4], [6, 6], [4, 6], [4, 4]]
+			]
+		}`))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(polygons), test.ShouldEqual, 1)
+		polygon := polygons[0]
+		// the closing positions are dropped, and positions are [longitude, latitude]
+		test.That(t, len(polygon.Rings), test.ShouldEqual, 2)
+		test.That(t, len(polygon.Rings[0]), test.ShouldEqual, 4)
+		test.That(t, polygon.Rings[0][1].Lng(), test.ShouldEqual, 10)
+		test.That(t, polygon.Rings[0][1].Lat(), test.ShouldEqual, 0)
+
+		test.That(t, polygon.Contains(geo.NewPoint(2, 2)), test.ShouldBeTrue)
+		test.That(t, polygon.Contains(geo.NewPoint(5, 5)), test.ShouldBeFalse)
+		test.That(t, polygon.Contains(geo.NewPoint(5, 11)), test.ShouldBeFalse)
+
+		test.That(t, polygon.CrossedBy(geo.NewPoint(2, 2), geo.NewPoint(2, 8)), test.ShouldBeFalse)
+		test.That(t, polygon.CrossedBy(geo.NewPoint(2, 2), geo.NewPoint(5, 5)), test.ShouldBeTrue)
+		test.That(t, polygon.CrossedBy(geo.NewPoint(-1, 5), geo.NewPoint(11, 5)), test.ShouldBeTrue)
+	})
+
+	t.Run("features", func(t *testing.T) {
+		polygons, err := GeoPolygonsFromGeoJSON([]byte(`{
+			"type": "FeatureCollection",
+			"features": [
+				{"type": "Feature", "properties": {}, "geometry": {"type": "Point", "coordinates": [1, 1]}},
+				{"type": "Feature", "properties": {}, "geometry": {
+					"type": "MultiPolygon",
+					"coordinates": [[[[0, 0], [1, 0], [1, 1]]], [[[2, 2], [3, 2], [3, 3]]]]
+				}}
+			]
+		}`))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(polygons), test.ShouldEqual, 2)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := GeoPolygonsFromGeoJSON([]byte(`{"type": "Point", "coordinates": [1, 1]}`))
+		test.That(t, err, test.ShouldBeError, "GeoJSON contains no polygons")
+		_, err = GeoPolygonsFromGeoJSON([]byte(`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0]]]}`))
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = GeoPolygonsFromGeoJSON([]byte(`{"coordinates": []}`))
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = GeoPolygonsFromGeoJSON([]byte(`not json`))
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestGeoPointsFromGeoJSON(t *testing.T) {
+	points, err := GeoPointsFromGeoJSON([]byte(`{
+		"type": "FeatureCollection",
+		"features": [
+			{"type": "Feature", "properties": {"name": "start"}, "geometry": {"type": "Point", "coordinates": [1, 2]}},
+			{"type": "Feature", "properties": {}, "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1]]]}},
+			{"type": "Feature", "properties": {}, "geometry": {"type": "LineString", "coordinates": [[3, 4], [5, 6, 7]]}}
+		]
+	}`))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(points), test.ShouldEqual, 3)
+	test.That(t, *points[0], test.ShouldResemble, *geo.NewPoint(2, 1))
+	test.That(t, *points[2], test.ShouldResemble, *geo.NewPoint(6, 5))
+
+	_, err = GeoPointsFromGeoJSON([]byte(`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1]]]}`))
+	test.That(t, err, test.ShouldBeError, "GeoJSON contains no points")
+	_, err = GeoPointsFromGeoJSON([]byte(`{"type": "Point", "coordinates": [1]}`))
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -61,6 +61,7 @@ const (
 	SphereType  = GeometryType("sphere")
 	CapsuleType = GeometryType("capsule")
 	PointType   = GeometryType("point")
+	MeshType    = GeometryType("mesh")
 )
 
 // GeometryConfig specifies the format of geometries specified through JSON configuration files.
@@ -78,6 +79,10 @@ type GeometryConfig struct {
 	// parameter used for defining a capsule's length
 	L float64 `json:"l"`
 
+	// parameters used for defining a mesh, the contents and type, such as "stl", of its mesh file
+	MeshData        []byte `json:"mesh_data,omitempty"`
+	MeshContentType string `json:"mesh_content_type,omitempty"`
+
 	// define an offset to position the geometry
 	TranslationOffset r3.Vector         `json:"translation,omitempty"`
 	OrientationOffset OrientationConfig `json:"orientation,omitempty"`
@@ -107,6 +112,12 @@ func NewGeometryConfig(g Geometry) (*GeometryConfig, error) {
 	case *point:
 		config.Type = PointType
 		config.Label = gType.label
+	case *Mesh:
+		mesh := gType.ToProtobuf().GetMesh()
+		config.Type = MeshType
+		config.MeshData = mesh.Mesh
+		config.MeshContentType = mesh.ContentType
+		config.Label = gType.label
 	default:
 		return nil, fmt.Errorf("%w %s", errGeometryTypeUnsupported, fmt.Sprintf("%T", gType))
 	}
@@ -140,6 +151,8 @@ func (config *GeometryConfig) ParseConfig() (Geometry, error) {
 		return NewCapsule(offset, config.R, config.L, config.Label)
 	case PointType:
 		return NewPoint(offset.Point(), config.Label), nil
+	case MeshType:
+		return newMeshFromBytes(offset, config.MeshData, meshType(config.MeshContentType), config.Label)
 	case UnknownType:
 		// no type specified, iterate through supported types and try to infer intent
 		boxDims := r3.Vector{X: config.X, Y: config.Y, Z: config.Z}
@@ -179,6 +192,8 @@ func GeometriesAlmostEqual(a, b Geometry) bool {
 		return gType.almostEqual(b)
 	case *point:
 		return gType.almostEqual(b)
+	case *Mesh:
+		return gType.almostEqual(b)
 	default:
 		return false
 	}
@@ -1,16 +1,15 @@
 package spatialmath
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"math"
 	"os"
+	"path/filepath"
+	"strings"
 
-	"github.com/chenzhekl/goply"
 	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
-	"github.com/spf13/cast"
 	commonpb "go.viam.com/api/common/v1"
 )
 
@@ -21,7 +20,11 @@ import (
 // The set of supported mesh file types.
 type meshType string
 
-const plyType = meshType("ply")
+const (
+	plyType = meshType("ply")
+	stlType = meshType("stl")
+	objType = meshType("obj")
+)
 
 // Mesh is a set of triangles at some pose. Triangle points are in the frame of the mesh.
 type Mesh struct {
@@ -35,10 +38,7 @@ type Mesh struct {
 }
 
 // NewMesh creates a mesh from the given triangles and pose.
-// A Mesh created this way should not be attempted to be converted to protobuf
-// as there are not conversion functions to support it currently.
 func NewMesh(pose Pose, triangles []*Triangle, label string) *Mesh {
-	// TODO(RSDK-10314): Fix proto for meshes created from triangles.
 	return &Mesh{
 		pose:      pose,
 		triangles: triangles,
@@ -48,6 +48,17 @@ func NewMesh(pose Pose, triangles []*Triangle, label string) *Mesh {
 
 // NewMeshFromPLYFile is a helper function to create a Mesh geometry from a PLY file.
 func NewMeshFromPLYFile(path string) (*Mesh, error) {
+	return newMeshFromFile(path, plyType)
+}
+
+// NewMeshFromFile creates a Mesh geometry from a PLY, STL or OBJ file, as given by its extension. The file's units are
+// meters. All of the objects of an OBJ file, such as the convex pieces of a convex decomposition, are read into the
+// one mesh.
+func NewMeshFromFile(path string) (*Mesh, error) {
+	return newMeshFromFile(path, meshType(strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")))
+}
+
+func newMeshFromFile(path string, fileType meshType) (*Mesh, error) {
 	//nolint:gosec
 	file, err := os.Open(path)
 	if err != nil {
@@ -59,61 +70,36 @@ func NewMeshFromPLYFile(path string) (*Mesh, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newMeshFromBytes(NewZeroPose(), bytes, path)
+	return newMeshFromBytes(NewZeroPose(), bytes, fileType, path)
 }
 
-func newMeshFromBytes(pose Pose, data []byte, label string) (mesh *Mesh, err error) {
-	// the library we are using for PLY parsing is fragile, so
-	defer func() {
-		if r := recover(); r != nil {
-			err = errors.Wrap(errors.Errorf("%v", r), "error reading mesh")
-		}
-	}()
-
-	ply := goply.New(bytes.NewReader(data))
-	vertices := ply.Elements("vertex")
-	faces := ply.Elements("face")
-	triangles := []*Triangle{}
-	for _, face := range faces {
-		pts := []r3.Vector{}
-		idxIface := face["vertex_indices"]
-		for _, i := range idxIface.([]any) {
-			x, err := cast.ToFloat64E(vertices[cast.ToInt(i)]["x"])
-			if err != nil {
-				return nil, err
-			}
-			y, err := cast.ToFloat64E(vertices[cast.ToInt(i)]["y"])
-			if err != nil {
-				return nil, err
-			}
-			z, err := cast.ToFloat64E(vertices[cast.ToInt(i)]["z"])
-			if err != nil {
-				return nil, err
-			}
-			pts = append(pts, r3.Vector{X: x * 1000, Y: y * 1000, Z: z * 1000})
-		}
-		if len(pts) != 3 {
-			return nil, errors.New("triangle did not have three points")
-		}
-		tri := NewTriangle(pts[0], pts[1], pts[2])
-		triangles = append(triangles, tri)
+func newMeshFromBytes(pose Pose, data []byte, fileType meshType, label string) (*Mesh, error) {
+	var triangles []*Triangle
+	var err error
+	switch fileType {
+	case plyType:
+		triangles, err = parsePLY(data)
+	case stlType:
+		triangles, err = parseSTL(data)
+	case objType:
+		triangles, err = parseOBJ(data)
+	default:
+		return nil, fmt.Errorf("unsupported Mesh type: %s", fileType)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading mesh")
 	}
 	return &Mesh{
 		pose:      pose,
 		triangles: triangles,
 		label:     label,
-		fileType:  plyType,
+		fileType:  fileType,
 		rawBytes:  data,
 	}, nil
 }
 
 func newMeshFromProto(pose Pose, m *commonpb.Mesh, label string) (*Mesh, error) {
-	switch m.ContentType {
-	case string(plyType):
-		return newMeshFromBytes(pose, m.Mesh, label)
-	default:
-		return nil, fmt.Errorf("unsupported Mesh type: %s", m.ContentType)
-	}
+	return newMeshFromBytes(pose, m.Mesh, meshType(m.ContentType), label)
 }
 
 // String returns a human readable string that represents the box.
@@ -122,15 +108,18 @@ func (m *Mesh) String() string {
 		m.pose.Point().X, m.pose.Point().Y, m.pose.Point().Z, len(m.triangles))
 }
 
-// ToProtobuf converts a Mesh to its protobuf representation.
-// Note that if the mesh's rawBytes and fileType fields are unset this will result in a malformed message.
+// ToProtobuf converts a Mesh to its protobuf representation. Meshes not read from a file are encoded as STL.
 func (m *Mesh) ToProtobuf() *commonpb.Geometry {
+	fileType, rawBytes := m.fileType, m.rawBytes
+	if rawBytes == nil {
+		fileType, rawBytes = stlType, encodeSTL(m.triangles)
+	}
 	return &commonpb.Geometry{
 		Center: PoseToProtobuf(m.pose),
 		GeometryType: &commonpb.Geometry_Mesh{
 			Mesh: &commonpb.Mesh{
-				ContentType: string(m.fileType),
-				Mesh:        m.rawBytes,
+				ContentType: string(fileType),
+				Mesh:        rawBytes,
 			},
 		},
 		Label: m.label,
@@ -142,6 +131,22 @@ func (m *Mesh) Pose() Pose {
 	return m.pose
 }
 
+func (m *Mesh) almostEqual(g Geometry) bool {
+	other, ok := g.(*Mesh)
+	if !ok || len(m.triangles) != len(other.triangles) {
+		return false
+	}
+	for i, tri := range m.triangles {
+		otherPts := other.triangles[i].Points()
+		for j, pt := range tri.Points() {
+			if !R3VectorAlmostEqual(pt, otherPts[j], 1e-3) {
+				return false
+			}
+		}
+	}
+	return PoseAlmostEqualEps(m.pose, other.pose, 1e-6)
+}
+
 // Triangles returns the triangles associated with the mesh.
 func (m *Mesh) Triangles() []*Triangle {
 	return m.triangles
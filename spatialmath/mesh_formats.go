@@ -0,0 +1,197 @@
+package spatialmath
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/chenzhekl/goply"
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// Mesh files are in meters, while meshes are in mm.
+const metersToMM = 1000
+
+// The sizes, in bytes, of the header and of each triangle of a binary STL file.
+const (
+	stlHeaderSize   = 84
+	stlTriangleSize = 50
+)
+
+func parsePLY(data []byte) (triangles []*Triangle, err error) {
+	// the library we are using for PLY parsing is fragile, so recover from any panics it has
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("%v", r)
+		}
+	}()
+
+	ply := goply.New(bytes.NewReader(data))
+	vertices := ply.Elements("vertex")
+	faces := ply.Elements("face")
+	for _, face := range faces {
+		pts := []r3.Vector{}
+		idxIface := face["vertex_indices"]
+		for _, i := range idxIface.([]any) {
+			x, err := cast.ToFloat64E(vertices[cast.ToInt(i)]["x"])
+			if err != nil {
+				return nil, err
+			}
+			y, err := cast.ToFloat64E(vertices[cast.ToInt(i)]["y"])
+			if err != nil {
+				return nil, err
+			}
+			z, err := cast.ToFloat64E(vertices[cast.ToInt(i)]["z"])
+			if err != nil {
+				return nil, err
+			}
+			pts = append(pts, r3.Vector{X: x, Y: y, Z: z}.Mul(metersToMM))
+		}
+		if len(pts) != 3 {
+			return nil, errors.New("triangle did not have three points")
+		}
+		triangles = append(triangles, NewTriangle(pts[0], pts[1], pts[2]))
+	}
+	return triangles, nil
+}
+
+// parseSTL reads the triangles of binary or ASCII STL data.
+func parseSTL(data []byte) ([]*Triangle, error) {
+	if len(data) >= stlHeaderSize {
+		count := int(binary.LittleEndian.Uint32(data[80:stlHeaderSize]))
+		if len(data) == stlHeaderSize+count*stlTriangleSize {
+			triangles := make([]*Triangle, 0, count)
+			for i := 0; i < count; i++ {
+				// skip the normal, then read the three vertices
+				offset := stlHeaderSize + i*stlTriangleSize + 12
+				var pts [3]r3.Vector
+				for j := range pts {
+					pts[j] = r3.Vector{
+						X: float64(math.Float32frombits(binary.LittleEndian.Uint32(data[offset:]))),
+						Y: float64(math.Float32frombits(binary.LittleEndian.Uint32(data[offset+4:]))),
+						Z: float64(math.Float32frombits(binary.LittleEndian.Uint32(data[offset+8:]))),
+					}.Mul(metersToMM)
+					offset += 12
+				}
+				triangles = append(triangles, NewTriangle(pts[0], pts[1], pts[2]))
+			}
+			return triangles, nil
+		}
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("solid")) {
+		return nil, errors.New("not a binary or ASCII STL file")
+	}
+	var triangles []*Triangle
+	var pts []r3.Vector
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "vertex":
+			pt, err := parseMeshVertex(fields[1:])
+			if err != nil {
+				return nil, err
+			}
+			pts = append(pts, pt)
+		case "endfacet":
+			if len(pts) != 3 {
+				return nil, errors.New("facet did not have three vertices")
+			}
+			triangles = append(triangles, NewTriangle(pts[0], pts[1], pts[2]))
+			pts = pts[:0]
+		}
+	}
+	return triangles, scanner.Err()
+}
+
+// encodeSTL writes triangles as binary STL data.
+func encodeSTL(triangles []*Triangle) []byte {
+	data := make([]byte, stlHeaderSize+len(triangles)*stlTriangleSize)
+	binary.LittleEndian.PutUint32(data[80:stlHeaderSize], uint32(len(triangles)))
+	for i, tri := range triangles {
+		offset := stlHeaderSize + i*stlTriangleSize
+		vectors := append([]r3.Vector{tri.Normal()}, tri.Points()...)
+		for j, v := range vectors {
+			// the normal is a unit vector, the vertices are in meters
+			if j > 0 {
+				v = v.Mul(1. / metersToMM)
+			}
+			for _, f := range []float64{v.X, v.Y, v.Z} {
+				binary.LittleEndian.PutUint32(data[offset:], math.Float32bits(float32(f)))
+				offset += 4
+			}
+		}
+	}
+	return data
+}
+
+// parseOBJ reads the triangles of Wavefront OBJ data. Faces with more than three vertices are split into triangles,
+// and the faces of all of the data's objects, such as the convex pieces of a convex decomposition, are read together.
+func parseOBJ(data []byte) ([]*Triangle, error) {
+	var vertices []r3.Vector
+	var triangles []*Triangle
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "v":
+			pt, err := parseMeshVertex(fields[1:])
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", line)
+			}
+			vertices = append(vertices, pt)
+		case "f":
+			if len(fields) < 4 {
+				return nil, errors.Errorf("line %d: face has fewer than three vertices", line)
+			}
+			pts := make([]r3.Vector, 0, len(fields)-1)
+			for _, field := range fields[1:] {
+				// faces may also reference texture coordinates and normals, as in "1/2/3" or "1//3"
+				ref, _, _ := strings.Cut(field, "/")
+				i, err := strconv.Atoi(ref)
+				if err != nil {
+					return nil, errors.Wrapf(err, "line %d", line)
+				}
+				// negative indices count back from the most recent vertex
+				if i < 0 {
+					i += len(vertices) + 1
+				}
+				if i < 1 || i > len(vertices) {
+					return nil, errors.Errorf("line %d: vertex %s does not exist", line, ref)
+				}
+				pts = append(pts, vertices[i-1])
+			}
+			for i := 1; i < len(pts)-1; i++ {
+				triangles = append(triangles, NewTriangle(pts[0], pts[i], pts[i+1]))
+			}
+		}
+	}
+	return triangles, scanner.Err()
+}
+
+// parseMeshVertex parses the x, y and z coordinates, in meters, of a mesh file vertex, ignoring any other coordinates.
+func parseMeshVertex(fields []string) (r3.Vector, error) {
+	if len(fields) < 3 {
+		return r3.Vector{}, errors.New("vertex has fewer than three coordinates")
+	}
+	var coords [3]float64
+	for i := range coords {
+		f, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return r3.Vector{}, err
+		}
+		coords[i] = f
+	}
+	return r3.Vector{X: coords[0], Y: coords[1], Z: coords[2]}.Mul(metersToMM), nil
+}
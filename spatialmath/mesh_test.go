@@ -1,6 +1,8 @@
 package spatialmath
 
 import (
+	"encoding/binary"
+	"math"
 	"testing"
 
 	"github.com/golang/geo/r3"
@@ -201,3 +203,87 @@ func TestMeshEncompassedBy(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, encompassed, test.ShouldBeFalse)
 }
+
+func TestMeshFileFormats(t *testing.T) {
+	t.Run("binary STL", func(t *testing.T) {
+		// one triangle, in meters
+		data := make([]byte, 84+50)
+		binary.LittleEndian.PutUint32(data[80:], 1)
+		for i, v := range []float32{0, 0, 1, 0.1, 0.2, 0.3, -0.1, 0, 0, 0, 0, 0.5} {
+			binary.LittleEndian.PutUint32(data[84+4*i:], math.Float32bits(v))
+		}
+		triangles, err := parseSTL(data)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, triangles, test.ShouldHaveLength, 1)
+		pts := triangles[0].Points()
+		test.That(t, pts[0].X, test.ShouldAlmostEqual, 100, 1e-3)
+		test.That(t, pts[1].X, test.ShouldAlmostEqual, -100, 1e-3)
+		test.That(t, pts[2].Z, test.ShouldAlmostEqual, 500, 1e-3)
+
+		_, err = parseSTL([]byte("not a mesh"))
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("ASCII STL", func(t *testing.T) {
+		triangles, err := parseSTL([]byte(`solid one
+  facet normal 0 0 1
+    outer loop
+      vertex 0 0 0
+      vertex 0.001 0 0
+      vertex 0 0.001 0
+    endloop
+  endfacet
+endsolid one`))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, triangles, test.ShouldHaveLength, 1)
+		test.That(t, triangles[0].Points()[1], test.ShouldResemble, r3.Vector{X: 1})
+	})
+
+	t.Run("OBJ", func(t *testing.T) {
+		// two objects, as from a convex decomposition, the second a quad referenced relatively
+		triangles, err := parseOBJ([]byte(`# decomposed
+o piece1
+v 0 0 0
+v 0.001 0 0
+v 0 0.001 0
+vn 0 0 1
+f 1//1 2//1 3//1
+o piece2
+v 0 0 0.001
+v 0.001 0 0.001
+v 0.001 0.001 0.001
+v 0 0.001 0.001
+f -4/1 -3/2 -2/3 -1/4
+`))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, triangles, test.ShouldHaveLength, 3)
+		test.That(t, triangles[2].Points(), test.ShouldResemble, []r3.Vector{{Z: 1}, {X: 1, Y: 1, Z: 1}, {Y: 1, Z: 1}})
+
+		_, err = parseOBJ([]byte("v 0 0 0\nf 1 2 3\n"))
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "line 2")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := newMeshFromBytes(NewZeroPose(), nil, meshType("dae"), "")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestMeshFromTrianglesConversion(t *testing.T) {
+	// meshes built from triangles are encoded as STL
+	mesh := makeSimpleTriangleMesh()
+	mesh.SetLabel("simple")
+	m, err := NewGeometryFromProto(mesh.ToProtobuf())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, GeometriesAlmostEqual(mesh, m), test.ShouldBeTrue)
+	test.That(t, m.Label(), test.ShouldEqual, "simple")
+
+	config, err := NewGeometryConfig(mesh.Transform(NewPoseFromPoint(r3.Vector{X: 10})))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, config.Type, test.ShouldEqual, MeshType)
+	m, err = config.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, GeometriesAlmostEqual(mesh.Transform(NewPoseFromPoint(r3.Vector{X: 10})), m), test.ShouldBeTrue)
+	test.That(t, GeometriesAlmostEqual(mesh, m), test.ShouldBeFalse)
+}
@@ -0,0 +1,141 @@
+package spatialmath
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/num/quat"
+)
+
+var errTooFewKeyframes = errors.New("a spline needs at least two keyframes")
+
+// OrientationSpline smoothly interpolates through a sequence of orientations using spherical quadrangle
+// interpolation (squad). Unlike chaining slerps between consecutive orientations, the angular velocity
+// of the interpolated orientation is continuous as it passes through each keyframe.
+type OrientationSpline struct {
+	keys     []quat.Number
+	controls []quat.Number
+}
+
+// NewOrientationSpline returns an OrientationSpline passing through the given orientations in order.
+func NewOrientationSpline(orientations ...Orientation) (*OrientationSpline, error) {
+	if len(orientations) < 2 {
+		return nil, errTooFewKeyframes
+	}
+	keys := make([]quat.Number, 0, len(orientations))
+	for i, o := range orientations {
+		q := Normalize(o.Quaternion())
+		// pick the cover of each keyframe closest to the one before it so every segment takes the short way around
+		if i > 0 && quatDot(keys[i-1], q) < 0 {
+			q = Flip(q)
+		}
+		keys = append(keys, q)
+	}
+
+	controls := make([]quat.Number, len(keys))
+	controls[0] = keys[0]
+	controls[len(keys)-1] = keys[len(keys)-1]
+	for i := 1; i < len(keys)-1; i++ {
+		controls[i] = squadControlPoint(keys[i-1], keys[i], keys[i+1])
+	}
+	return &OrientationSpline{keys: keys, controls: controls}, nil
+}
+
+// Interpolate returns the orientation the set amount along the spline.
+// by == 0 will return the first orientation, by == 1 will return the last, and the keyframes in between
+// are evenly spaced along by. Values outside of [0, 1] are clamped.
+func (s *OrientationSpline) Interpolate(by float64) Orientation {
+	i, t := splineSegment(len(s.keys), by)
+	q := squad(s.keys[i], s.keys[i+1], s.controls[i], s.controls[i+1], t)
+	return (*Quaternion)(&q)
+}
+
+// PoseSpline smoothly interpolates through a sequence of poses. Positions follow a Catmull-Rom spline and
+// orientations follow an OrientationSpline, so both the velocity and angular velocity are continuous
+// through every keyframe.
+type PoseSpline struct {
+	points       []r3.Vector
+	tangents     []r3.Vector
+	orientations *OrientationSpline
+}
+
+// NewPoseSpline returns a PoseSpline passing through the given poses in order.
+func NewPoseSpline(poses ...Pose) (*PoseSpline, error) {
+	if len(poses) < 2 {
+		return nil, errTooFewKeyframes
+	}
+	points := make([]r3.Vector, 0, len(poses))
+	orientations := make([]Orientation, 0, len(poses))
+	for _, p := range poses {
+		points = append(points, p.Point())
+		orientations = append(orientations, p.Orientation())
+	}
+	orientationSpline, err := NewOrientationSpline(orientations...)
+	if err != nil {
+		return nil, err
+	}
+
+	last := len(points) - 1
+	tangents := make([]r3.Vector, len(points))
+	tangents[0] = points[1].Sub(points[0])
+	tangents[last] = points[last].Sub(points[last-1])
+	for i := 1; i < last; i++ {
+		tangents[i] = points[i+1].Sub(points[i-1]).Mul(0.5)
+	}
+	return &PoseSpline{points: points, tangents: tangents, orientations: orientationSpline}, nil
+}
+
+// Interpolate returns the pose the set amount along the spline.
+// by == 0 will return the first pose, by == 1 will return the last, and the keyframes in between
+// are evenly spaced along by. Values outside of [0, 1] are clamped.
+func (s *PoseSpline) Interpolate(by float64) Pose {
+	i, t := splineSegment(len(s.points), by)
+	// cubic Hermite basis functions
+	t2, t3 := t*t, t*t*t
+	h00 := 2*t3 - 3*t2 + 1
+	h10 := t3 - 2*t2 + t
+	h01 := -2*t3 + 3*t2
+	h11 := t3 - t2
+	point := s.points[i].Mul(h00).
+		Add(s.tangents[i].Mul(h10)).
+		Add(s.points[i+1].Mul(h01)).
+		Add(s.tangents[i+1].Mul(h11))
+	return NewPose(point, s.orientations.Interpolate(by))
+}
+
+// splineSegment maps an amount along a spline with n evenly spaced keyframes to the index of the segment's
+// first keyframe and the amount along that segment.
+func splineSegment(n int, by float64) (int, float64) {
+	by = math.Max(0, math.Min(1, by))
+	segments := float64(n - 1)
+	i := int(math.Min(math.Floor(by*segments), segments-1))
+	return i, by*segments - float64(i)
+}
+
+// squad interpolates between q1 and q2 along the quadrangle formed with their control points s1 and s2.
+func squad(q1, q2, s1, s2 quat.Number, by float64) quat.Number {
+	return slerpDirect(slerpDirect(q1, q2, by), slerpDirect(s1, s2, by), 2*by*(1-by))
+}
+
+// squadControlPoint returns the inner control point for the keyframe cur, chosen so that the spline's
+// tangent at cur is the same on both of its sides.
+func squadControlPoint(prev, cur, next quat.Number) quat.Number {
+	inv := quat.Conj(cur)
+	sum := quat.Add(quat.Log(quat.Mul(inv, next)), quat.Log(quat.Mul(inv, prev)))
+	return Normalize(quat.Mul(cur, quat.Exp(quat.Scale(-0.25, sum))))
+}
+
+// slerpDirect is slerp without any double cover correction, which squad relies on to keep its control
+// points on the path chosen when the spline was built.
+func slerpDirect(qN1, qN2 quat.Number, by float64) quat.Number {
+	q1 := mgl64.Quat{qN1.Real, mgl64.Vec3{qN1.Imag, qN1.Jmag, qN1.Kmag}}
+	q2 := mgl64.Quat{qN2.Real, mgl64.Vec3{qN2.Imag, qN2.Jmag, qN2.Kmag}}
+	q := mgl64.QuatSlerp(q1, q2, by)
+	return quat.Number{q.W, q.X(), q.Y(), q.Z()}
+}
+
+func quatDot(q1, q2 quat.Number) float64 {
+	return q1.Real*q2.Real + q1.Imag*q2.Imag + q1.Jmag*q2.Jmag + q1.Kmag*q2.Kmag
+}
@@ -0,0 +1,95 @@
+package spatialmath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+	"gonum.org/v1/gonum/num/quat"
+)
+
+func TestOrientationSpline(t *testing.T) {
+	_, err := NewOrientationSpline(NewZeroOrientation())
+	test.That(t, err, test.ShouldBeError, errTooFewKeyframes)
+
+	keys := []Orientation{
+		NewZeroOrientation(),
+		&R4AA{Theta: math.Pi / 2, RX: 1},
+		&R4AA{Theta: math.Pi / 2, RY: 1},
+		// the same orientation as the one before, from the other cover
+		(*Quaternion)(&quat.Number{}),
+	}
+	flipped := Flip(keys[2].Quaternion())
+	keys[3] = (*Quaternion)(&flipped)
+	s, err := NewOrientationSpline(keys...)
+	test.That(t, err, test.ShouldBeNil)
+
+	t.Run("passes through keyframes", func(t *testing.T) {
+		for i, o := range keys {
+			by := float64(i) / float64(len(keys)-1)
+			test.That(t, OrientationAlmostEqual(s.Interpolate(by), o), test.ShouldBeTrue)
+		}
+		test.That(t, OrientationAlmostEqual(s.Interpolate(-1), keys[0]), test.ShouldBeTrue)
+		test.That(t, OrientationAlmostEqual(s.Interpolate(2), keys[3]), test.ShouldBeTrue)
+	})
+
+	t.Run("does not spin between identical keyframes", func(t *testing.T) {
+		for _, by := range []float64{0.7, 5. / 6, 0.95} {
+			theta := OrientationBetween(s.Interpolate(by), keys[2]).AxisAngles().Theta
+			test.That(t, theta, test.ShouldBeLessThan, 0.5)
+		}
+	})
+
+	t.Run("angular velocity is continuous at keyframes", func(t *testing.T) {
+		const h = 1e-5
+		for _, by := range []float64{1. / 3, 2. / 3} {
+			before := angularVelocity(s.Interpolate(by-h), s.Interpolate(by), h)
+			after := angularVelocity(s.Interpolate(by), s.Interpolate(by+h), h)
+			test.That(t, R3VectorAlmostEqual(before, after, 1e-3), test.ShouldBeTrue)
+		}
+	})
+
+	t.Run("two keyframes match slerp", func(t *testing.T) {
+		s, err := NewOrientationSpline(keys[0], keys[1])
+		test.That(t, err, test.ShouldBeNil)
+		for _, by := range []float64{0.1, 0.25, 0.7} {
+			expected := slerp(keys[0].Quaternion(), keys[1].Quaternion(), by)
+			test.That(t, OrientationAlmostEqual(s.Interpolate(by), (*Quaternion)(&expected)), test.ShouldBeTrue)
+		}
+	})
+}
+
+func TestPoseSpline(t *testing.T) {
+	_, err := NewPoseSpline(NewZeroPose())
+	test.That(t, err, test.ShouldBeError, errTooFewKeyframes)
+
+	keys := []Pose{
+		NewZeroPose(),
+		NewPose(r3.Vector{X: 100}, &R4AA{Theta: math.Pi / 2, RZ: 1}),
+		NewPose(r3.Vector{X: 100, Y: 100}, &R4AA{Theta: math.Pi, RZ: 1}),
+	}
+	s, err := NewPoseSpline(keys...)
+	test.That(t, err, test.ShouldBeNil)
+
+	for i, p := range keys {
+		test.That(t, PoseAlmostEqual(s.Interpolate(float64(i)/2), p), test.ShouldBeTrue)
+	}
+
+	// velocity is continuous through the middle keyframe
+	const h = 1e-5
+	before := s.Interpolate(0.5).Point().Sub(s.Interpolate(0.5 - h).Point()).Mul(1 / h)
+	after := s.Interpolate(0.5 + h).Point().Sub(s.Interpolate(0.5).Point()).Mul(1 / h)
+	test.That(t, R3VectorAlmostEqual(before, after, 1e-1), test.ShouldBeTrue)
+	test.That(t, R3VectorAlmostEqual(before, r3.Vector{X: 100, Y: 100}, 1e-1), test.ShouldBeTrue)
+
+	// evenly spaced collinear points are followed in a straight line
+	s, err = NewPoseSpline(NewZeroPose(), NewPoseFromPoint(r3.Vector{Z: 10}), NewPoseFromPoint(r3.Vector{Z: 20}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, R3VectorAlmostEqual(s.Interpolate(0.25).Point(), r3.Vector{Z: 5}, 1e-6), test.ShouldBeTrue)
+}
+
+// angularVelocity approximates the angular velocity moving from o1 to o2 over dt.
+func angularVelocity(o1, o2 Orientation, dt float64) r3.Vector {
+	return QuatToR3AA(OrientationBetween(o1, o2).Quaternion()).Mul(1 / dt)
+}
@@ -47,6 +47,23 @@ func BuildTempModule(tb testing.TB, modDir string) string {
 	return exePath
 }
 
+// BuildTempWASMModule will attempt to build the WASM module in the provided directory and put the
+// resulting ".wasm" file into a temporary directory. If successful, this function will return the
+// path to the ".wasm" file.
+func BuildTempWASMModule(tb testing.TB, modDir string) string {
+	tb.Helper()
+
+	wasmPath := filepath.Join(tb.TempDir(), filepath.Base(modDir)+".wasm")
+	//nolint:gosec
+	builder := exec.Command("go", "build", "-buildmode=c-shared", "-o", wasmPath, ".")
+	builder.Dir = utils.ResolveFile(modDir)
+	builder.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	if out, err := builder.CombinedOutput(); err != nil {
+		tb.Fatalf("failed to build temporary WASM module for testing: %v: %s", err, out)
+	}
+	return wasmPath
+}
+
 // BuildTempModuleWithFirstRun will attempt to build the module in the provided directory and put the
 // resulting executable binary into a temporary directory. After building, it will also copy "meta.json"
 // and "first_run.sh" into the same temporary directory. It is assumed that these files are in the
@@ -3,6 +3,8 @@ package inject
 import (
 	"context"
 
+	"github.com/pkg/errors"
+
 	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
@@ -31,6 +33,19 @@ type Arm struct {
 	CurrentInputsFunc  func(ctx context.Context) ([]referenceframe.Input, error)
 	GoToInputsFunc     func(ctx context.Context, inputSteps ...[]referenceframe.Input) error
 	GeometriesFunc     func(ctx context.Context) ([]spatialmath.Geometry, error)
+
+	SetJointVelocitiesFunc         func(ctx context.Context, velocities []float64, extra map[string]interface{}) error
+	MoveThroughJointVelocitiesFunc func(
+		ctx context.Context,
+		segments []arm.JointVelocitySegment,
+		extra map[string]interface{},
+	) error
+	StreamTrajectoryFunc func(
+		ctx context.Context,
+		points <-chan arm.TrajectoryPoint,
+		feedback func(arm.TrajectoryFeedback),
+		extra map[string]interface{},
+	) error
 }
 
 // NewArm returns a new injected arm.
@@ -96,6 +111,51 @@ func (a *Arm) Stop(ctx context.Context, extra map[string]interface{}) error {
 	return a.StopFunc(ctx, extra)
 }
 
+// SetJointVelocities calls the injected SetJointVelocities or the real version.
+func (a *Arm) SetJointVelocities(ctx context.Context, velocities []float64, extra map[string]interface{}) error {
+	if a.SetJointVelocitiesFunc == nil {
+		velArm, ok := a.Arm.(arm.JointVelocityController)
+		if !ok {
+			return errors.New("SetJointVelocities unimplemented")
+		}
+		return velArm.SetJointVelocities(ctx, velocities, extra)
+	}
+	return a.SetJointVelocitiesFunc(ctx, velocities, extra)
+}
+
+// MoveThroughJointVelocities calls the injected MoveThroughJointVelocities or the real version.
+func (a *Arm) MoveThroughJointVelocities(
+	ctx context.Context,
+	segments []arm.JointVelocitySegment,
+	extra map[string]interface{},
+) error {
+	if a.MoveThroughJointVelocitiesFunc == nil {
+		velArm, ok := a.Arm.(arm.JointVelocityController)
+		if !ok {
+			return errors.New("MoveThroughJointVelocities unimplemented")
+		}
+		return velArm.MoveThroughJointVelocities(ctx, segments, extra)
+	}
+	return a.MoveThroughJointVelocitiesFunc(ctx, segments, extra)
+}
+
+// StreamTrajectory calls the injected StreamTrajectory or the real version.
+func (a *Arm) StreamTrajectory(
+	ctx context.Context,
+	points <-chan arm.TrajectoryPoint,
+	feedback func(arm.TrajectoryFeedback),
+	extra map[string]interface{},
+) error {
+	if a.StreamTrajectoryFunc == nil {
+		streamer, ok := a.Arm.(arm.TrajectoryStreamer)
+		if !ok {
+			return errors.New("StreamTrajectory unimplemented")
+		}
+		return streamer.StreamTrajectory(ctx, points, feedback, extra)
+	}
+	return a.StreamTrajectoryFunc(ctx, points, feedback, extra)
+}
+
 // IsMoving calls the injected IsMoving or the real version.
 func (a *Arm) IsMoving(ctx context.Context) (bool, error) {
 	if a.IsMovingFunc == nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/pkg/errors"
 	boardpb "go.viam.com/api/component/board/v1"
 
 	"go.viam.com/rdk/components/board"
@@ -25,6 +26,9 @@ type Board struct {
 	SetPowerModeFunc           func(ctx context.Context, mode boardpb.PowerMode, duration *time.Duration) error
 	StreamTicksFunc            func(ctx context.Context,
 		interrupts []board.DigitalInterrupt, ch chan board.Tick, extra map[string]interface{}) error
+	SetPWMGroupFunc func(ctx context.Context, group board.PWMGroup, extra map[string]interface{}) error
+	I2CTransferFunc func(ctx context.Context, transfer board.I2CTransfer, extra map[string]interface{}) ([]byte, error)
+	SPITransferFunc func(ctx context.Context, transfer board.SPITransfer, extra map[string]interface{}) ([]byte, error)
 }
 
 // NewBoard returns a new injected board.
@@ -129,3 +133,39 @@ func (b *Board) StreamTicks(ctx context.Context,
 	}
 	return b.StreamTicksFunc(ctx, interrupts, ch, extra)
 }
+
+// SetPWMGroup calls the injected SetPWMGroup or the real version.
+func (b *Board) SetPWMGroup(ctx context.Context, group board.PWMGroup, extra map[string]interface{}) error {
+	if b.SetPWMGroupFunc == nil {
+		grouper, ok := b.Board.(board.PWMGrouper)
+		if !ok {
+			return errors.New("SetPWMGroup unimplemented")
+		}
+		return grouper.SetPWMGroup(ctx, group, extra)
+	}
+	return b.SetPWMGroupFunc(ctx, group, extra)
+}
+
+// I2CTransfer calls the injected I2CTransfer or the real version.
+func (b *Board) I2CTransfer(ctx context.Context, transfer board.I2CTransfer, extra map[string]interface{}) ([]byte, error) {
+	if b.I2CTransferFunc == nil {
+		transactor, ok := b.Board.(board.BusTransactor)
+		if !ok {
+			return nil, errors.New("I2CTransfer unimplemented")
+		}
+		return transactor.I2CTransfer(ctx, transfer, extra)
+	}
+	return b.I2CTransferFunc(ctx, transfer, extra)
+}
+
+// SPITransfer calls the injected SPITransfer or the real version.
+func (b *Board) SPITransfer(ctx context.Context, transfer board.SPITransfer, extra map[string]interface{}) ([]byte, error) {
+	if b.SPITransferFunc == nil {
+		transactor, ok := b.Board.(board.BusTransactor)
+		if !ok {
+			return nil, errors.New("SPITransfer unimplemented")
+		}
+		return transactor.SPITransfer(ctx, transfer, extra)
+	}
+	return b.SPITransferFunc(ctx, transfer, extra)
+}
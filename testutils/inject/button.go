@@ -3,6 +3,8 @@ package inject
 import (
 	"context"
 
+	"github.com/pkg/errors"
+
 	"go.viam.com/rdk/components/button"
 	"go.viam.com/rdk/resource"
 )
@@ -14,6 +16,8 @@ type Button struct {
 	PushFunc  func(ctx context.Context, extra map[string]interface{}) error
 	DoFunc    func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
 	CloseFunc func(ctx context.Context) error
+
+	StreamPressesFunc func(ctx context.Context, extra map[string]interface{}, handle func(button.PressEvent) error) error
 }
 
 // NewButton returns a new injected button.
@@ -49,3 +53,15 @@ func (b *Button) Close(ctx context.Context) error {
 	}
 	return b.CloseFunc(ctx)
 }
+
+// StreamPresses calls StreamPressesFunc or the real version.
+func (b *Button) StreamPresses(ctx context.Context, extra map[string]interface{}, handle func(button.PressEvent) error) error {
+	if b.StreamPressesFunc == nil {
+		streamer, ok := b.Button.(button.PressStreamer)
+		if !ok {
+			return errors.New("StreamPresses unimplemented")
+		}
+		return streamer.StreamPresses(ctx, extra, handle)
+	}
+	return b.StreamPressesFunc(ctx, extra, handle)
+}
@@ -2,6 +2,9 @@ package inject
 
 import (
 	"context"
+	"time"
+
+	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/datamanager"
@@ -15,7 +18,9 @@ type DataManagerService struct {
 	SyncFunc      func(ctx context.Context, extra map[string]interface{}) error
 	DoCommandFunc func(ctx context.Context,
 		cmd map[string]interface{}) (map[string]interface{}, error)
-	CloseFunc func(ctx context.Context) error
+	CloseFunc               func(ctx context.Context) error
+	StartCaptureSessionFunc func(ctx context.Context, name string, duration time.Duration,
+		extra map[string]interface{}) (time.Time, error)
 }
 
 // NewDataManagerService returns a new injected data manager service.
@@ -36,6 +41,19 @@ func (svc *DataManagerService) Sync(ctx context.Context, extra map[string]interf
 	return svc.SyncFunc(ctx, extra)
 }
 
+// StartCaptureSession calls the injected StartCaptureSession or the real variant.
+func (svc *DataManagerService) StartCaptureSession(
+	ctx context.Context, name string, duration time.Duration, extra map[string]interface{},
+) (time.Time, error) {
+	if svc.StartCaptureSessionFunc == nil {
+		if starter, ok := svc.Service.(datamanager.CaptureSessionStarter); ok {
+			return starter.StartCaptureSession(ctx, name, duration, extra)
+		}
+		return time.Time{}, errors.New("StartCaptureSession unimplemented")
+	}
+	return svc.StartCaptureSessionFunc(ctx, name, duration, extra)
+}
+
 // DoCommand calls the injected DoCommand or the real variant.
 func (svc *DataManagerService) DoCommand(ctx context.Context,
 	cmd map[string]interface{},
@@ -31,7 +31,9 @@ type FrameSystemService struct {
 		ctx context.Context,
 		additionalTransforms []*referenceframe.LinkInFrame,
 	) (referenceframe.FrameSystem, error)
-	DoCommandFunc func(
+	UpdateFramesFunc  func(ctx context.Context, update framesystem.FrameUpdate) error
+	DynamicFramesFunc func(ctx context.Context) ([]*referenceframe.LinkInFrame, error)
+	DoCommandFunc     func(
 		ctx context.Context,
 		cmd map[string]interface{},
 	) (map[string]interface{}, error)
@@ -98,6 +100,22 @@ func (fs *FrameSystemService) FrameSystem(
 	return fs.FrameSystemFunc(ctx, additionalTransforms)
 }
 
+// UpdateFrames calls the injected method or the real variant.
+func (fs *FrameSystemService) UpdateFrames(ctx context.Context, update framesystem.FrameUpdate) error {
+	if fs.UpdateFramesFunc == nil {
+		return fs.Service.UpdateFrames(ctx, update)
+	}
+	return fs.UpdateFramesFunc(ctx, update)
+}
+
+// DynamicFrames calls the injected method or the real variant.
+func (fs *FrameSystemService) DynamicFrames(ctx context.Context) ([]*referenceframe.LinkInFrame, error) {
+	if fs.DynamicFramesFunc == nil {
+		return fs.Service.DynamicFrames(ctx)
+	}
+	return fs.DynamicFramesFunc(ctx)
+}
+
 // DoCommand calls the injected DoCommand or the real variant.
 func (fs *FrameSystemService) DoCommand(ctx context.Context,
 	cmd map[string]interface{},
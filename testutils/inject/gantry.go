@@ -3,6 +3,8 @@ package inject
 import (
 	"context"
 
+	"github.com/pkg/errors"
+
 	"go.viam.com/rdk/components/gantry"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
@@ -21,6 +23,7 @@ type Gantry struct {
 	IsMovingFunc       func(context.Context) (bool, error)
 	CloseFunc          func(ctx context.Context) error
 	ModelFrameFunc     func() referenceframe.Model
+	MoveLinearFunc     func(ctx context.Context, pos []float64, opts gantry.LinearMoveOptions, extra map[string]interface{}) error
 }
 
 // NewGantry returns a new injected gantry.
@@ -107,3 +110,20 @@ func (g *Gantry) DoCommand(ctx context.Context, cmd map[string]interface{}) (map
 	}
 	return g.DoFunc(ctx, cmd)
 }
+
+// MoveLinear calls the injected MoveLinear or the real version.
+func (g *Gantry) MoveLinear(
+	ctx context.Context,
+	positions []float64,
+	opts gantry.LinearMoveOptions,
+	extra map[string]interface{},
+) error {
+	if g.MoveLinearFunc == nil {
+		mover, ok := g.Gantry.(gantry.LinearMover)
+		if !ok {
+			return errors.New("MoveLinear unimplemented")
+		}
+		return mover.MoveLinear(ctx, positions, opts, extra)
+	}
+	return g.MoveLinearFunc(ctx, positions, opts, extra)
+}
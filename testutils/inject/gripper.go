@@ -3,6 +3,8 @@ package inject
 import (
 	"context"
 
+	"github.com/pkg/errors"
+
 	"go.viam.com/rdk/components/gripper"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/spatialmath"
@@ -11,14 +13,15 @@ import (
 // Gripper is an injected gripper.
 type Gripper struct {
 	gripper.Gripper
-	name           resource.Name
-	DoFunc         func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
-	OpenFunc       func(ctx context.Context, extra map[string]interface{}) error
-	GrabFunc       func(ctx context.Context, extra map[string]interface{}) (bool, error)
-	StopFunc       func(ctx context.Context, extra map[string]interface{}) error
-	IsMovingFunc   func(context.Context) (bool, error)
-	CloseFunc      func(ctx context.Context) error
-	GeometriesFunc func(ctx context.Context) ([]spatialmath.Geometry, error)
+	name                   resource.Name
+	DoFunc                 func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
+	OpenFunc               func(ctx context.Context, extra map[string]interface{}) error
+	GrabFunc               func(ctx context.Context, extra map[string]interface{}) (bool, error)
+	StopFunc               func(ctx context.Context, extra map[string]interface{}) error
+	IsMovingFunc           func(context.Context) (bool, error)
+	CloseFunc              func(ctx context.Context) error
+	GeometriesFunc         func(ctx context.Context) ([]spatialmath.Geometry, error)
+	IsHoldingSomethingFunc func(ctx context.Context, extra map[string]interface{}) (gripper.HoldingStatus, error)
 }
 
 // NewGripper returns a new injected gripper.
@@ -89,3 +92,15 @@ func (g *Gripper) Geometries(ctx context.Context, extra map[string]interface{})
 	}
 	return g.GeometriesFunc(ctx)
 }
+
+// IsHoldingSomething calls the injected IsHoldingSomething or the real version.
+func (g *Gripper) IsHoldingSomething(ctx context.Context, extra map[string]interface{}) (gripper.HoldingStatus, error) {
+	if g.IsHoldingSomethingFunc == nil {
+		sensor, ok := g.Gripper.(gripper.HoldingSensor)
+		if !ok {
+			return gripper.HoldingStatus{}, errors.New("IsHoldingSomething unimplemented")
+		}
+		return sensor.IsHoldingSomething(ctx, extra)
+	}
+	return g.IsHoldingSomethingFunc(ctx, extra)
+}
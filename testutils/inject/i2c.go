@@ -29,6 +29,8 @@ type I2CHandle struct {
 	WriteByteDataFunc  func(ctx context.Context, register, data byte) error
 	ReadBlockDataFunc  func(ctx context.Context, register byte, numBytes uint8) ([]byte, error)
 	WriteBlockDataFunc func(ctx context.Context, register byte, data []byte) error
+	TransferFunc       func(ctx context.Context, tx []byte, count int) ([]byte, error)
+	SetSpeedFunc       func(ctx context.Context, speedHz uint) error
 	CloseFunc          func() error
 }
 
@@ -80,6 +82,22 @@ func (handle *I2CHandle) Write(ctx context.Context, tx []byte) error {
 	return handle.WriteFunc(ctx, tx)
 }
 
+// Transfer calls the injected TransferFunc or the real version.
+func (handle *I2CHandle) Transfer(ctx context.Context, tx []byte, count int) ([]byte, error) {
+	if handle.TransferFunc == nil {
+		return handle.I2CHandle.Transfer(ctx, tx, count)
+	}
+	return handle.TransferFunc(ctx, tx, count)
+}
+
+// SetSpeed calls the injected SetSpeedFunc or the real version.
+func (handle *I2CHandle) SetSpeed(ctx context.Context, speedHz uint) error {
+	if handle.SetSpeedFunc == nil {
+		return handle.I2CHandle.SetSpeed(ctx, speedHz)
+	}
+	return handle.SetSpeedFunc(ctx, speedHz)
+}
+
 // Close calls the injected CloseFunc or the real version.
 func (handle *I2CHandle) Close() error {
 	if handle.CloseFunc == nil {
@@ -2,6 +2,9 @@ package inject
 
 import (
 	"context"
+	"image/color"
+
+	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/components/input"
 	"go.viam.com/rdk/resource"
@@ -21,6 +24,8 @@ type InputController struct {
 		ctrlFunc input.ControlFunction,
 		extra map[string]interface{},
 	) error
+	RumbleFunc      func(ctx context.Context, rumble input.Rumble, extra map[string]interface{}) error
+	SetLEDColorFunc func(ctx context.Context, c color.Color, extra map[string]interface{}) error
 }
 
 // NewInputController returns a new injected input controller.
@@ -71,6 +76,30 @@ func (s *InputController) DoCommand(ctx context.Context, cmd map[string]interfac
 	return s.DoFunc(ctx, cmd)
 }
 
+// Rumble calls the injected Rumble or the real version.
+func (s *InputController) Rumble(ctx context.Context, rumble input.Rumble, extra map[string]interface{}) error {
+	if s.RumbleFunc == nil {
+		fb, ok := s.Controller.(input.FeedbackController)
+		if !ok {
+			return errors.New("Rumble unimplemented")
+		}
+		return fb.Rumble(ctx, rumble, extra)
+	}
+	return s.RumbleFunc(ctx, rumble, extra)
+}
+
+// SetLEDColor calls the injected SetLEDColor or the real version.
+func (s *InputController) SetLEDColor(ctx context.Context, c color.Color, extra map[string]interface{}) error {
+	if s.SetLEDColorFunc == nil {
+		fb, ok := s.Controller.(input.FeedbackController)
+		if !ok {
+			return errors.New("SetLEDColor unimplemented")
+		}
+		return fb.SetLEDColor(ctx, c, extra)
+	}
+	return s.SetLEDColorFunc(ctx, c, extra)
+}
+
 // TriggerableInputController is an injected injectable InputController.
 type TriggerableInputController struct {
 	InputController
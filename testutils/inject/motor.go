@@ -2,6 +2,9 @@ package inject
 
 import (
 	"context"
+	"time"
+
+	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/components/motor"
 	"go.viam.com/rdk/resource"
@@ -22,6 +25,9 @@ type Motor struct {
 	StopFunc              func(ctx context.Context, extra map[string]interface{}) error
 	IsPoweredFunc         func(ctx context.Context, extra map[string]interface{}) (bool, float64, error)
 	IsMovingFunc          func(context.Context) (bool, error)
+	SetCurrentFunc        func(ctx context.Context, amps float64, extra map[string]interface{}) error
+	CurrentFunc           func(ctx context.Context, extra map[string]interface{}) (float64, error)
+	FaultsFunc            func(ctx context.Context, since time.Time, extra map[string]interface{}) ([]motor.Fault, error)
 }
 
 // NewMotor returns a new injected motor.
@@ -121,3 +127,39 @@ func (m *Motor) IsMoving(ctx context.Context) (bool, error) {
 	}
 	return m.IsMovingFunc(ctx)
 }
+
+// SetCurrent calls the injected SetCurrent or the real version.
+func (m *Motor) SetCurrent(ctx context.Context, amps float64, extra map[string]interface{}) error {
+	if m.SetCurrentFunc == nil {
+		currentMotor, ok := m.Motor.(motor.CurrentController)
+		if !ok {
+			return errors.New("SetCurrent unimplemented")
+		}
+		return currentMotor.SetCurrent(ctx, amps, extra)
+	}
+	return m.SetCurrentFunc(ctx, amps, extra)
+}
+
+// Current calls the injected Current or the real version.
+func (m *Motor) Current(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	if m.CurrentFunc == nil {
+		currentMotor, ok := m.Motor.(motor.CurrentController)
+		if !ok {
+			return 0, errors.New("Current unimplemented")
+		}
+		return currentMotor.Current(ctx, extra)
+	}
+	return m.CurrentFunc(ctx, extra)
+}
+
+// Faults calls the injected Faults or the real version.
+func (m *Motor) Faults(ctx context.Context, since time.Time, extra map[string]interface{}) ([]motor.Fault, error) {
+	if m.FaultsFunc == nil {
+		reporter, ok := m.Motor.(motor.FaultReporter)
+		if !ok {
+			return nil, errors.New("Faults unimplemented")
+		}
+		return reporter.Faults(ctx, since, extra)
+	}
+	return m.FaultsFunc(ctx, since, extra)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"go.viam.com/rdk/resource"
@@ -29,6 +30,12 @@ type NavigationService struct {
 
 	PropertiesFunc func(ctx context.Context) (navigation.Properties, error)
 
+	ImportWaypointsFunc func(
+		ctx context.Context, data []byte, format navigation.WaypointFormat, extra map[string]interface{},
+	) (int, error)
+	ExportTraveledPathFunc func(ctx context.Context, format navigation.WaypointFormat, extra map[string]interface{}) ([]byte, error)
+	DockNowFunc            func(ctx context.Context, name string, extra map[string]interface{}) error
+
 	DoCommandFunc func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
 	CloseFunc     func(ctx context.Context) error
 }
@@ -115,6 +122,51 @@ func (ns *NavigationService) Properties(ctx context.Context) (navigation.Propert
 	return ns.PropertiesFunc(ctx)
 }
 
+// ImportWaypoints calls the injected ImportWaypoints or the real variant.
+func (ns *NavigationService) ImportWaypoints(
+	ctx context.Context,
+	data []byte,
+	format navigation.WaypointFormat,
+	extra map[string]interface{},
+) (int, error) {
+	if ns.ImportWaypointsFunc == nil {
+		filer, ok := ns.Service.(navigation.WaypointFiler)
+		if !ok {
+			return 0, errors.New("ImportWaypoints unimplemented")
+		}
+		return filer.ImportWaypoints(ctx, data, format, extra)
+	}
+	return ns.ImportWaypointsFunc(ctx, data, format, extra)
+}
+
+// ExportTraveledPath calls the injected ExportTraveledPath or the real variant.
+func (ns *NavigationService) ExportTraveledPath(
+	ctx context.Context,
+	format navigation.WaypointFormat,
+	extra map[string]interface{},
+) ([]byte, error) {
+	if ns.ExportTraveledPathFunc == nil {
+		filer, ok := ns.Service.(navigation.WaypointFiler)
+		if !ok {
+			return nil, errors.New("ExportTraveledPath unimplemented")
+		}
+		return filer.ExportTraveledPath(ctx, format, extra)
+	}
+	return ns.ExportTraveledPathFunc(ctx, format, extra)
+}
+
+// DockNow calls the injected DockNow or the real variant.
+func (ns *NavigationService) DockNow(ctx context.Context, name string, extra map[string]interface{}) error {
+	if ns.DockNowFunc == nil {
+		docker, ok := ns.Service.(navigation.Docker)
+		if !ok {
+			return errors.New("DockNow unimplemented")
+		}
+		return docker.DockNow(ctx, name, extra)
+	}
+	return ns.DockNowFunc(ctx, name, extra)
+}
+
 // DoCommand calls the injected DoCommand or the real variant.
 func (ns *NavigationService) DoCommand(ctx context.Context,
 	cmd map[string]interface{},
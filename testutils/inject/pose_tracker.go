@@ -14,6 +14,10 @@ type PoseTracker struct {
 	name      resource.Name
 	PosesFunc func(ctx context.Context, bodyNames []string, extra map[string]interface{}) (referenceframe.FrameSystemPoses, error)
 	DoFunc    func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
+
+	TrackedPosesFunc func(
+		ctx context.Context, bodyNames []string, extra map[string]interface{},
+	) (map[string]posetracker.TrackedPose, error)
 }
 
 // NewPoseTracker returns a new injected pose tracker.
@@ -43,3 +47,17 @@ func (pT *PoseTracker) DoCommand(ctx context.Context, cmd map[string]interface{}
 	}
 	return pT.DoFunc(ctx, cmd)
 }
+
+// TrackedPoses calls the injected TrackedPoses or the real version.
+func (pT *PoseTracker) TrackedPoses(
+	ctx context.Context, bodyNames []string, extra map[string]interface{},
+) (map[string]posetracker.TrackedPose, error) {
+	if pT.TrackedPosesFunc == nil {
+		if tracker, ok := pT.PoseTracker.(posetracker.TrackedPoser); ok {
+			return tracker.TrackedPoses(ctx, bodyNames, extra)
+		}
+		// hide this TrackedPoses so that the poses come from the injected Poses
+		return posetracker.GetTrackedPoses(ctx, struct{ posetracker.PoseTracker }{pT}, bodyNames, extra)
+	}
+	return pT.TrackedPosesFunc(ctx, bodyNames, extra)
+}
@@ -3,6 +3,8 @@ package inject
 import (
 	"context"
 
+	"github.com/pkg/errors"
+
 	"go.viam.com/rdk/components/powersensor"
 	"go.viam.com/rdk/resource"
 )
@@ -16,6 +18,9 @@ type PowerSensor struct {
 	PowerFunc    func(ctx context.Context, extra map[string]interface{}) (float64, error)
 	ReadingsFunc func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error)
 	DoFunc       func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
+
+	EnergyFunc      func(ctx context.Context, extra map[string]interface{}) (powersensor.EnergyStats, error)
+	ResetEnergyFunc func(ctx context.Context, extra map[string]interface{}) error
 }
 
 // NewPowerSensor returns a new injected movement sensor.
@@ -67,3 +72,27 @@ func (i *PowerSensor) Readings(ctx context.Context, cmd map[string]interface{})
 	}
 	return i.ReadingsFunc(ctx, cmd)
 }
+
+// Energy calls the injected Energy or the real version.
+func (i *PowerSensor) Energy(ctx context.Context, extra map[string]interface{}) (powersensor.EnergyStats, error) {
+	if i.EnergyFunc == nil {
+		meter, ok := i.PowerSensor.(powersensor.EnergyMeter)
+		if !ok {
+			return powersensor.EnergyStats{}, errors.New("Energy unimplemented")
+		}
+		return meter.Energy(ctx, extra)
+	}
+	return i.EnergyFunc(ctx, extra)
+}
+
+// ResetEnergy calls the injected ResetEnergy or the real version.
+func (i *PowerSensor) ResetEnergy(ctx context.Context, extra map[string]interface{}) error {
+	if i.ResetEnergyFunc == nil {
+		meter, ok := i.PowerSensor.(powersensor.EnergyMeter)
+		if !ok {
+			return errors.New("ResetEnergy unimplemented")
+		}
+		return meter.ResetEnergy(ctx, extra)
+	}
+	return i.ResetEnergyFunc(ctx, extra)
+}
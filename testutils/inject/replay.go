@@ -0,0 +1,307 @@
+package inject
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+	pb "go.viam.com/api/component/arm/v1"
+	goutils "go.viam.com/utils"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/ftdc"
+	"go.viam.com/rdk/protoutils"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// ReplayOptions configure how a replaying fake component plays back recorded outputs.
+type ReplayOptions struct {
+	// Loop starts the recording over once its last output has been replayed. Otherwise the last
+	// output is returned from then on.
+	Loop bool
+}
+
+// replayEntry is an output recorded at an offset from the first output of a recording.
+type replayEntry[T any] struct {
+	offset time.Duration
+	value  T
+}
+
+// replayer plays back recorded outputs with their original timing, starting from the first time
+// it is asked for one.
+type replayer[T any] struct {
+	mu      sync.Mutex
+	entries []replayEntry[T]
+	loop    bool
+	now     func() time.Time
+	start   time.Time
+}
+
+func newReplayer[T any](times []time.Time, values []T, opts ReplayOptions) (*replayer[T], error) {
+	if len(values) == 0 {
+		return nil, errors.New("no recorded outputs to replay")
+	}
+	entries := make([]replayEntry[T], 0, len(values))
+	for i, value := range values {
+		entries = append(entries, replayEntry[T]{offset: times[i].Sub(times[0]), value: value})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].offset < entries[j].offset })
+	return &replayer[T]{entries: entries, loop: opts.Loop, now: time.Now}, nil
+}
+
+// next returns the latest output recorded at or before the time elapsed since replay started.
+func (r *replayer[T]) next() T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	if r.start.IsZero() {
+		r.start = now
+	}
+	elapsed := now.Sub(r.start)
+	last := r.entries[len(r.entries)-1].offset
+	if r.loop && last > 0 {
+		elapsed %= last
+	}
+	i := sort.Search(len(r.entries), func(i int) bool { return r.entries[i].offset > elapsed })
+	if i == 0 {
+		i = 1
+	}
+	return r.entries[i-1].value
+}
+
+// readCapturedData returns the data captured from the given method of the named component in the
+// completed capture files under captureDir.
+func readCapturedData(captureDir, componentName, method string) ([]time.Time, []*v1.SensorData, error) {
+	var times []time.Time
+	var captured []*v1.SensorData
+	err := filepath.WalkDir(captureDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != data.CompletedCaptureFileExt {
+			return nil
+		}
+		//nolint:gosec
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer goutils.UncheckedErrorFunc(f.Close)
+		captureFile, err := data.ReadCaptureFile(f)
+		if err != nil {
+			return errors.Wrapf(err, "reading capture file %q", path)
+		}
+		md := captureFile.ReadMetadata()
+		if md.GetComponentName() != componentName || md.GetMethodName() != method {
+			return nil
+		}
+		sensorData, err := data.SensorDataFromCaptureFile(captureFile)
+		if err != nil {
+			return errors.Wrapf(err, "reading capture file %q", path)
+		}
+		for _, sd := range sensorData {
+			times = append(times, sd.GetMetadata().GetTimeRequested().AsTime())
+			captured = append(captured, sd)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(captured) == 0 {
+		return nil, nil, errors.Errorf("no %s data captured for %q in %q", method, componentName, captureDir)
+	}
+	return times, captured, nil
+}
+
+// NewReplaySensor returns an injected sensor whose readings are those captured by data capture
+// from the Readings of the named sensor in captureDir, replayed with their original timing.
+func NewReplaySensor(name, captureDir string, opts ReplayOptions) (*Sensor, error) {
+	times, captured, err := readCapturedData(captureDir, name, "Readings")
+	if err != nil {
+		return nil, err
+	}
+	readings := make([]map[string]interface{}, 0, len(captured))
+	for _, sd := range captured {
+		// Readings are captured under a top level "readings" key, which older captures lack.
+		payload := sd.GetStruct()
+		if nested := payload.GetFields()["readings"].GetStructValue(); nested != nil {
+			payload = nested
+		}
+		r, err := protoutils.ReadingProtoToGo(payload.GetFields())
+		if err != nil {
+			return nil, err
+		}
+		readings = append(readings, r)
+	}
+	r, err := newReplayer(times, readings, opts)
+	if err != nil {
+		return nil, err
+	}
+	s := NewSensor(name)
+	s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return r.next(), nil
+	}
+	return s, nil
+}
+
+// NewReplaySensorFromFTDC returns an injected sensor whose readings are the metrics starting with
+// metricPrefix in the FTDC file at ftdcPath, replayed with their original timing. Readings are
+// keyed by the rest of their metric names, such that the prefix "sensor1.Readings" turns the metric
+// "sensor1.Readings.temperature" into the reading "temperature".
+func NewReplaySensorFromFTDC(name, ftdcPath, metricPrefix string, opts ReplayOptions) (*Sensor, error) {
+	//nolint:gosec
+	f, err := os.Open(ftdcPath)
+	if err != nil {
+		return nil, err
+	}
+	defer goutils.UncheckedErrorFunc(f.Close)
+	datums, err := ftdc.Parse(f)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := strings.TrimSuffix(metricPrefix, ".") + "."
+	var times []time.Time
+	var readings []map[string]interface{}
+	for _, datum := range datums {
+		r := map[string]interface{}{}
+		for _, reading := range datum.Readings {
+			if key, ok := strings.CutPrefix(reading.MetricName, prefix); ok {
+				r[key] = float64(reading.Value)
+			}
+		}
+		if len(r) == 0 {
+			continue
+		}
+		times = append(times, datum.ConvertedTime())
+		readings = append(readings, r)
+	}
+	r, err := newReplayer(times, readings, opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "no metrics starting with %q in %q", prefix, ftdcPath)
+	}
+	s := NewSensor(name)
+	s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return r.next(), nil
+	}
+	return s, nil
+}
+
+// NewReplayArm returns an injected arm whose end positions and joint positions are those captured
+// by data capture from the named arm in captureDir, replayed with their original timing. Only the
+// methods captureDir has data for are injected. Joint positions are converted to inputs with model,
+// and are taken to be in degrees if model is nil.
+func NewReplayArm(name, captureDir string, model referenceframe.Model, opts ReplayOptions) (*Arm, error) {
+	a := NewArm(name)
+	if model != nil {
+		a.ModelFrameFunc = func() referenceframe.Model { return model }
+	}
+
+	poseTimes, poseData, poseErr := readCapturedData(captureDir, name, "EndPosition")
+	if poseErr == nil {
+		poses := make([]spatialmath.Pose, 0, len(poseData))
+		for _, sd := range poseData {
+			var resp pb.GetEndPositionResponse
+			if err := unmarshalCapturedStruct(sd.GetStruct(), &resp); err != nil {
+				return nil, err
+			}
+			poses = append(poses, spatialmath.NewPoseFromProtobuf(resp.GetPose()))
+		}
+		r, err := newReplayer(poseTimes, poses, opts)
+		if err != nil {
+			return nil, err
+		}
+		a.EndPositionFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.Pose, error) {
+			return r.next(), nil
+		}
+	}
+
+	jointTimes, jointData, jointErr := readCapturedData(captureDir, name, "JointPositions")
+	if jointErr == nil {
+		positions := make([][]referenceframe.Input, 0, len(jointData))
+		for _, sd := range jointData {
+			var resp pb.GetJointPositionsResponse
+			if err := unmarshalCapturedStruct(sd.GetStruct(), &resp); err != nil {
+				return nil, err
+			}
+			inputs, err := referenceframe.InputsFromJointPositions(model, resp.GetPositions())
+			if err != nil {
+				return nil, err
+			}
+			positions = append(positions, inputs)
+		}
+		r, err := newReplayer(jointTimes, positions, opts)
+		if err != nil {
+			return nil, err
+		}
+		a.JointPositionsFunc = func(ctx context.Context, extra map[string]interface{}) ([]referenceframe.Input, error) {
+			return r.next(), nil
+		}
+	}
+
+	if poseErr != nil && jointErr != nil {
+		return nil, errors.Wrap(poseErr, jointErr.Error())
+	}
+	return a, nil
+}
+
+// NewReplayCamera returns an injected camera whose images are those captured by data capture from
+// the ReadImage method of the named camera in captureDir, replayed with their original timing.
+func NewReplayCamera(name, captureDir string, opts ReplayOptions) (*Camera, error) {
+	type image struct {
+		bytes    []byte
+		mimeType string
+	}
+	times, captured, err := readCapturedData(captureDir, name, "ReadImage")
+	if err != nil {
+		return nil, err
+	}
+	images := make([]image, 0, len(captured))
+	for _, sd := range captured {
+		img := image{bytes: sd.GetBinary()}
+		switch data.MimeTypeFromProto(sd.GetMetadata().GetMimeType()) {
+		case data.MimeTypeImageJpeg:
+			img.mimeType = utils.MimeTypeJPEG
+		case data.MimeTypeImagePng:
+			img.mimeType = utils.MimeTypePNG
+		case data.MimeTypeUnspecified, data.MimeTypeApplicationPcd:
+		}
+		images = append(images, img)
+	}
+	r, err := newReplayer(times, images, opts)
+	if err != nil {
+		return nil, err
+	}
+	c := NewCamera(name)
+	c.ImageFunc = func(ctx context.Context, mimeType string, extra map[string]interface{}) ([]byte, camera.ImageMetadata, error) {
+		img := r.next()
+		if img.mimeType != "" {
+			mimeType = img.mimeType
+		}
+		return img.bytes, camera.ImageMetadata{MimeType: mimeType}, nil
+	}
+	return c, nil
+}
+
+// unmarshalCapturedStruct decodes tabular data captured from a response message back into it.
+func unmarshalCapturedStruct(s *structpb.Struct, msg proto.Message) error {
+	b, err := protojson.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(b, msg)
+}
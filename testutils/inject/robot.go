@@ -51,6 +51,7 @@ type Robot struct {
 	MachineStatusFunc       func(ctx context.Context) (robot.MachineStatus, error)
 	ShutdownFunc            func(ctx context.Context) error
 	ListTunnelsFunc         func(ctx context.Context) ([]config.TrafficTunnelEndpoint, error)
+	VersionFunc             func(ctx context.Context) (robot.VersionResponse, error)
 
 	ops        *operation.Manager
 	SessMgr    session.Manager
@@ -296,6 +297,16 @@ func (r *Robot) MachineStatus(ctx context.Context) (robot.MachineStatus, error)
 	return r.MachineStatusFunc(ctx)
 }
 
+// Version calls the injected Version or the real one.
+func (r *Robot) Version(ctx context.Context) (robot.VersionResponse, error) {
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	if r.VersionFunc == nil {
+		return r.LocalRobot.Version(ctx)
+	}
+	return r.VersionFunc(ctx)
+}
+
 // Shutdown calls the injected Shutdown or the real one.
 func (r *Robot) Shutdown(ctx context.Context) error {
 	r.Mu.RLock()
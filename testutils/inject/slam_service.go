@@ -3,6 +3,8 @@ package inject
 import (
 	"context"
 
+	"github.com/pkg/errors"
+
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/slam"
 	"go.viam.com/rdk/spatialmath"
@@ -11,13 +13,15 @@ import (
 // SLAMService represents a fake instance of a slam service.
 type SLAMService struct {
 	slam.Service
-	name              resource.Name
-	PositionFunc      func(ctx context.Context) (spatialmath.Pose, error)
-	PointCloudMapFunc func(ctx context.Context, returnEditedMap bool) (func() ([]byte, error), error)
-	InternalStateFunc func(ctx context.Context) (func() ([]byte, error), error)
-	PropertiesFunc    func(ctx context.Context) (slam.Properties, error)
-	DoCommandFunc     func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
-	CloseFunc         func(ctx context.Context) error
+	name                    resource.Name
+	PositionFunc            func(ctx context.Context) (spatialmath.Pose, error)
+	PointCloudMapFunc       func(ctx context.Context, returnEditedMap bool) (func() ([]byte, error), error)
+	InternalStateFunc       func(ctx context.Context) (func() ([]byte, error), error)
+	PropertiesFunc          func(ctx context.Context) (slam.Properties, error)
+	MergeMapsFunc           func(ctx context.Context, req slam.MergeMapsRequest) (int, error)
+	LocalizationQualityFunc func(ctx context.Context, extra map[string]interface{}) (slam.LocalizationQuality, error)
+	DoCommandFunc           func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
+	CloseFunc               func(ctx context.Context) error
 }
 
 // NewSLAMService returns a new injected SLAM service.
@@ -62,6 +66,30 @@ func (slamSvc *SLAMService) Properties(ctx context.Context) (slam.Properties, er
 	return slamSvc.PropertiesFunc(ctx)
 }
 
+// MergeMaps calls the injected MergeMapsFunc or the real version.
+func (slamSvc *SLAMService) MergeMaps(ctx context.Context, req slam.MergeMapsRequest) (int, error) {
+	if slamSvc.MergeMapsFunc == nil {
+		merger, ok := slamSvc.Service.(slam.MapMerger)
+		if !ok {
+			return 0, errors.New("MergeMaps unimplemented")
+		}
+		return merger.MergeMaps(ctx, req)
+	}
+	return slamSvc.MergeMapsFunc(ctx, req)
+}
+
+// LocalizationQuality calls the injected LocalizationQualityFunc or the real version.
+func (slamSvc *SLAMService) LocalizationQuality(ctx context.Context, extra map[string]interface{}) (slam.LocalizationQuality, error) {
+	if slamSvc.LocalizationQualityFunc == nil {
+		reporter, ok := slamSvc.Service.(slam.LocalizationQualityReporter)
+		if !ok {
+			return slam.LocalizationQuality{}, errors.New("LocalizationQuality unimplemented")
+		}
+		return reporter.LocalizationQuality(ctx, extra)
+	}
+	return slamSvc.LocalizationQualityFunc(ctx, extra)
+}
+
 // DoCommand calls the injected DoCommand or the real variant.
 func (slamSvc *SLAMService) DoCommand(ctx context.Context,
 	cmd map[string]interface{},
@@ -1,6 +1,8 @@
 package inject
 
 import (
+	"context"
+
 	"go.viam.com/rdk/components/board/genericlinux/buses"
 )
 
@@ -17,3 +19,26 @@ func (s *SPI) OpenHandle() (buses.SPIHandle, error) {
 	}
 	return s.OpenHandleFunc()
 }
+
+// SPIHandle is an injected SPIHandle.
+type SPIHandle struct {
+	buses.SPIHandle
+	XferFunc  func(ctx context.Context, baud uint, chipSelect string, mode uint, tx []byte) ([]byte, error)
+	CloseFunc func() error
+}
+
+// Xfer calls the injected XferFunc or the real version.
+func (handle *SPIHandle) Xfer(ctx context.Context, baud uint, chipSelect string, mode uint, tx []byte) ([]byte, error) {
+	if handle.XferFunc == nil {
+		return handle.SPIHandle.Xfer(ctx, baud, chipSelect, mode, tx)
+	}
+	return handle.XferFunc(ctx, baud, chipSelect, mode, tx)
+}
+
+// Close calls the injected CloseFunc or the real version.
+func (handle *SPIHandle) Close() error {
+	if handle.CloseFunc == nil {
+		return handle.SPIHandle.Close()
+	}
+	return handle.CloseFunc()
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"image"
 
+	"github.com/pkg/errors"
+
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/vision"
 	viz "go.viam.com/rdk/vision"
@@ -35,6 +37,8 @@ type VisionService struct {
 		opts viscapture.CaptureOptions,
 		extra map[string]interface{},
 	) (viscapture.VisCapture, error)
+	TracksFunc func(ctx context.Context,
+		cameraName string, extra map[string]interface{}) ([]objectdetection.Track, error)
 	DoCommandFunc func(ctx context.Context,
 		cmd map[string]interface{}) (map[string]interface{}, error)
 	CloseFunc func(ctx context.Context) error
@@ -53,7 +57,7 @@ func (vs *VisionService) Name() resource.Name {
 // DetectionsFromCamera calls the injected DetectionsFromCamera or the real variant.
 func (vs *VisionService) DetectionsFromCamera(ctx context.Context, cameraName string, extra map[string]interface{},
 ) ([]objectdetection.Detection, error) {
-	if vs.DetectionsFunc == nil {
+	if vs.DetectionsFromCameraFunc == nil {
 		return vs.Service.DetectionsFromCamera(ctx, cameraName, extra)
 	}
 	return vs.DetectionsFromCameraFunc(ctx, cameraName, extra)
@@ -122,6 +126,20 @@ func (vs *VisionService) CaptureAllFromCamera(ctx context.Context,
 	return vs.CaptureAllFromCameraFunc(ctx, cameraName, opts, extra)
 }
 
+// Tracks calls the injected Tracks or the real variant.
+func (vs *VisionService) Tracks(ctx context.Context,
+	cameraName string, extra map[string]interface{},
+) ([]objectdetection.Track, error) {
+	if vs.TracksFunc == nil {
+		tracker, ok := vs.Service.(vision.Tracker)
+		if !ok {
+			return nil, errors.New("Tracks unimplemented")
+		}
+		return tracker.Tracks(ctx, cameraName, extra)
+	}
+	return vs.TracksFunc(ctx, cameraName, extra)
+}
+
 // DoCommand calls the injected DoCommand or the real variant.
 func (vs *VisionService) DoCommand(ctx context.Context,
 	cmd map[string]interface{},
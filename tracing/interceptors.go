@@ -0,0 +1,148 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metadataCarrier carries span contexts across processes in gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// startServerSpan starts a span for an incoming call, as a child of the span of the caller if the
+// incoming metadata carries one.
+func startServerSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	}
+	return StartSpan(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(append(attrs, semconv.RPCSystemGRPC, semconv.RPCMethod(method))...))
+}
+
+// startClientSpan starts a span for an outgoing call and adds it to the outgoing metadata, such
+// that the server's span is its child.
+func startClientSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	ctx, span := StartSpan(ctx, method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.RPCSystemGRPC, semconv.RPCMethod(method)))
+	if !span.SpanContext().IsValid() {
+		// Tracing is not started.
+		return ctx, span
+	}
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// endSpan records the status of a call on its span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		s := status.Convert(err)
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(s.Code())))
+		span.SetStatus(codes.Error, s.Message())
+	}
+	span.End()
+}
+
+// UnaryServerInterceptor traces the handling of unary calls. Spans of calls on a resource, such as
+// an arm's MoveToPosition, have the resource's name as their ResourceNameKey attribute.
+func UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	var attrs []attribute.KeyValue
+	if named, ok := req.(interface{ GetName() string }); ok && named.GetName() != "" {
+		attrs = append(attrs, ResourceNameKey.String(named.GetName()))
+	}
+	ctx, span := startServerSpan(ctx, info.FullMethod, attrs...)
+	resp, err := handler(ctx, req)
+	endSpan(span, err)
+	return resp, err
+}
+
+// StreamServerInterceptor traces the handling of streaming calls.
+func StreamServerInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, span := startServerSpan(ss.Context(), info.FullMethod)
+	err := handler(srv, &serverStreamWithContext{ss, ctx})
+	endSpan(span, err)
+	return err
+}
+
+type serverStreamWithContext struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStreamWithContext) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryClientInterceptor traces unary calls and propagates their spans to the server.
+func UnaryClientInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	ctx, span := startClientSpan(ctx, method)
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	endSpan(span, err)
+	return err
+}
+
+// StreamClientInterceptor traces the opening of streaming calls and propagates their spans to the
+// server.
+func StreamClientInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	ctx, span := startClientSpan(ctx, method)
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	endSpan(span, err)
+	return cs, err
+}
@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	pb "go.viam.com/api/component/arm/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestPropagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	const method = "/viam.component.arm.v1.ArmService/MoveToPosition"
+	// The invoker hands the outgoing metadata of the client to the server as a separate process would.
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		serverCtx := metadata.NewIncomingContext(context.Background(), md)
+		_, err := UnaryServerInterceptor(serverCtx, req, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, status.Error(grpccodes.Unavailable, "arm is off")
+			})
+		return err
+	}
+	err := UnaryClientInterceptor(context.Background(), method, &pb.MoveToPositionRequest{Name: "arm1"}, nil, nil, invoker)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	spans := recorder.Ended()
+	test.That(t, spans, test.ShouldHaveLength, 2)
+	server, client := spans[0], spans[1]
+	test.That(t, client.Name(), test.ShouldEqual, method)
+	test.That(t, server.Name(), test.ShouldEqual, method)
+	test.That(t, server.Parent().SpanID(), test.ShouldEqual, client.SpanContext().SpanID())
+	test.That(t, server.SpanContext().TraceID(), test.ShouldEqual, client.SpanContext().TraceID())
+	test.That(t, server.Attributes(), test.ShouldContain, ResourceNameKey.String("arm1"))
+	test.That(t, server.Status().Code, test.ShouldEqual, codes.Error)
+	test.That(t, client.Status().Description, test.ShouldEqual, "arm is off")
+}
@@ -0,0 +1,71 @@
+// Package tracing exports OpenTelemetry traces of the gRPC calls between clients, viam-server and
+// modules, such that the latency of a call can be followed across processes.
+//
+// Tracing is off unless an OTLP endpoint is configured with the standard OpenTelemetry environment
+// variables, such as OTEL_EXPORTER_OTLP_ENDPOINT=http://localhost:4317. Modules inherit the
+// environment of viam-server, so configuring viam-server also traces the modules it runs.
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "go.viam.com/rdk"
+
+// ResourceNameKey is the attribute naming the resource a span is for.
+const ResourceNameKey = attribute.Key("viam.resource.name")
+
+// Enabled returns whether an OTLP endpoint to export traces to is configured.
+func Enabled() bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Start starts exporting the traces of this process as serviceName, if Enabled. The returned
+// function flushes the remaining spans and stops exporting, and must be called before exiting.
+// Spans are no-ops until Start is called.
+func Start(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := sdkresource.Merge(
+		sdkresource.Default(),
+		sdkresource.NewSchemaless(semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span as a child of the span in ctx, if any. The span must be ended by the
+// caller.
+func StartSpan(
+	ctx context.Context,
+	name string,
+	opts ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
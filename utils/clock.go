@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/benbjohnson/clock"
+)
+
+const ctxKeyClock ctxKey = ctxKeyTrusted + 1
+
+// WithClock returns a context carrying clk, such that the resources built with it poll and time
+// out by clk instead of the wall clock. Tests use it with a mock clock to advance time instantly
+// instead of sleeping.
+func WithClock(ctx context.Context, clk clock.Clock) context.Context {
+	return context.WithValue(ctx, ctxKeyClock, clk)
+}
+
+// ClockFromContext returns the clock carried by ctx, or the wall clock if it carries none.
+func ClockFromContext(ctx context.Context) clock.Clock {
+	if clk, ok := ctx.Value(ctxKeyClock).(clock.Clock); ok && clk != nil {
+		return clk
+	}
+	return clock.New()
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+)
+
+func TestClockFromContext(t *testing.T) {
+	_, isMock := ClockFromContext(context.Background()).(*clock.Mock)
+	test.That(t, isMock, test.ShouldBeFalse)
+
+	mockClock := clock.NewMock()
+	ctx := WithClock(context.Background(), mockClock)
+	test.That(t, ClockFromContext(ctx), test.ShouldEqual, mockClock)
+
+	before := ClockFromContext(ctx).Now()
+	mockClock.Add(time.Hour)
+	test.That(t, ClockFromContext(ctx).Now().Sub(before), test.ShouldEqual, time.Hour)
+}
@@ -41,6 +41,9 @@ const (
 
 	// MimeTypeH264 used to indicate H264 frames.
 	MimeTypeH264 = "video/h264"
+
+	// MimeTypeH265 used to indicate H265 frames.
+	MimeTypeH265 = "video/h265"
 )
 
 // WithLazyMIMEType attaches the lazy suffix to a MIME.
@@ -0,0 +1,349 @@
+// Package mqtt is a minimal MQTT 3.1.1 client for publishing messages to a broker.
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// MQTT control packet types, shifted into the upper nibble of the fixed header.
+const (
+	packetConnect    = 1 << 4
+	packetConnack    = 2 << 4
+	packetPublish    = 3 << 4
+	packetPuback     = 4 << 4
+	packetPingreq    = 12 << 4
+	packetPingresp   = 13 << 4
+	packetDisconnect = 14 << 4
+)
+
+const (
+	protocolLevel = 4 // MQTT 3.1.1
+	defaultPort   = "1883"
+	defaultTLS    = "8883"
+	// maxRemainingLength is the largest packet MQTT can encode.
+	maxRemainingLength = 268435455
+)
+
+// Options configure a connection to a broker.
+type Options struct {
+	// ClientID identifies the client to the broker. A random ID is used if it is empty.
+	ClientID string
+	Username string
+	Password string
+	// KeepAlive is how often the client promises to talk to the broker, which disconnects it if it
+	// doesn't. Zero disables keep alive.
+	KeepAlive time.Duration
+	// TLSConfig is used for brokers with an ssl, tls or mqtts scheme.
+	TLSConfig *tls.Config
+}
+
+// A Client publishes messages to an MQTT broker. It is safe for concurrent use.
+type Client struct {
+	mu       sync.Mutex
+	conn     net.Conn
+	r        *bufio.Reader
+	packetID uint16
+	closed   bool
+}
+
+// Dial connects to the broker at `broker`, which is a URL such as "tcp://localhost:1883" or
+// "ssl://broker.example.com:8883", or a host and port.
+func Dial(ctx context.Context, broker string, opts Options) (*Client, error) {
+	address, useTLS, err := parseBroker(broker)
+	if err != nil {
+		return nil, err
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if useTLS {
+		tlsConfig := opts.TLSConfig
+		if tlsConfig == nil {
+			host, _, _ := net.SplitHostPort(address) //nolint:errcheck
+			tlsConfig = &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return nil, multierr.Combine(err, conn.Close())
+		}
+		conn = tlsConn
+	}
+
+	c := &Client{conn: conn, r: bufio.NewReader(conn)}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, multierr.Combine(err, conn.Close())
+		}
+	}
+	if err := c.connect(opts); err != nil {
+		return nil, multierr.Combine(err, conn.Close())
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return nil, multierr.Combine(err, conn.Close())
+	}
+	return c, nil
+}
+
+func parseBroker(broker string) (string, bool, error) {
+	if !strings.Contains(broker, "://") {
+		if _, _, err := net.SplitHostPort(broker); err != nil {
+			broker = net.JoinHostPort(broker, defaultPort)
+		}
+		return broker, false, nil
+	}
+	u, err := url.Parse(broker)
+	if err != nil {
+		return "", false, errors.Wrapf(err, "invalid MQTT broker %q", broker)
+	}
+	var useTLS bool
+	switch u.Scheme {
+	case "tcp", "mqtt":
+	case "ssl", "tls", "mqtts":
+		useTLS = true
+	default:
+		return "", false, errors.Errorf("unsupported MQTT broker scheme %q", u.Scheme)
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+		if useTLS {
+			port = defaultTLS
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port), useTLS, nil
+}
+
+func (c *Client) connect(opts Options) error {
+	clientID := opts.ClientID
+	if clientID == "" {
+		clientID = fmt.Sprintf("viam-%d", time.Now().UnixNano())
+	}
+	flags := byte(0x02) // clean session
+	var payload []byte
+	payload = appendString(payload, clientID)
+	if opts.Username != "" {
+		flags |= 0x80
+		payload = appendString(payload, opts.Username)
+	}
+	if opts.Password != "" {
+		flags |= 0x40
+		payload = appendString(payload, opts.Password)
+	}
+	var body []byte
+	body = appendString(body, "MQTT")
+	body = append(body, protocolLevel, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(opts.KeepAlive/time.Second))
+	body = append(body, payload...)
+	if err := c.writePacket(packetConnect, body); err != nil {
+		return err
+	}
+
+	header, ack, err := c.readPacket()
+	if err != nil {
+		return err
+	}
+	if header&0xF0 != packetConnack || len(ack) != 2 {
+		return errors.Errorf("expected CONNACK from MQTT broker, got packet type %d", header>>4)
+	}
+	if code := ack[1]; code != 0 {
+		return errors.Errorf("MQTT broker refused the connection: %s", connackReason(code))
+	}
+	return nil
+}
+
+func connackReason(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "client identifier rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad username or password"
+	case 5:
+		return "not authorized"
+	default:
+		return fmt.Sprintf("return code %d", code)
+	}
+}
+
+// Publish publishes `payload` to `topic` with the quality of service `qos`, which is 0 to send it
+// at most once or 1 to wait for the broker to acknowledge it.
+func (c *Client) Publish(ctx context.Context, topic string, payload []byte, qos byte, retain bool) error {
+	if qos > 1 {
+		return errors.Errorf("MQTT QoS %d is not supported", qos)
+	}
+	if topic == "" || strings.ContainsAny(topic, "+#") {
+		return errors.Errorf("invalid MQTT topic %q", topic)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errors.New("MQTT client is closed")
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := c.conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+		//nolint:errcheck
+		defer c.conn.SetDeadline(time.Time{})
+	}
+
+	header := byte(packetPublish) | qos<<1
+	if retain {
+		header |= 1
+	}
+	var body []byte
+	body = appendString(body, topic)
+	var id uint16
+	if qos > 0 {
+		c.packetID++
+		if c.packetID == 0 {
+			c.packetID = 1
+		}
+		id = c.packetID
+		body = binary.BigEndian.AppendUint16(body, id)
+	}
+	body = append(body, payload...)
+	if err := c.writePacket(header, body); err != nil {
+		return err
+	}
+	if qos == 0 {
+		return nil
+	}
+	for {
+		packet, ack, err := c.readPacket()
+		if err != nil {
+			return err
+		}
+		// the broker may answer earlier pings before the acknowledgement
+		if packet&0xF0 == packetPingresp {
+			continue
+		}
+		if packet&0xF0 != packetPuback || len(ack) != 2 {
+			return errors.Errorf("expected PUBACK from MQTT broker, got packet type %d", packet>>4)
+		}
+		if binary.BigEndian.Uint16(ack) == id {
+			return nil
+		}
+	}
+}
+
+// Ping checks the connection to the broker, and keeps it alive.
+func (c *Client) Ping(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errors.New("MQTT client is closed")
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := c.conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+		//nolint:errcheck
+		defer c.conn.SetDeadline(time.Time{})
+	}
+	if err := c.writePacket(packetPingreq, nil); err != nil {
+		return err
+	}
+	packet, _, err := c.readPacket()
+	if err != nil {
+		return err
+	}
+	if packet&0xF0 != packetPingresp {
+		return errors.Errorf("expected PINGRESP from MQTT broker, got packet type %d", packet>>4)
+	}
+	return nil
+}
+
+// Close disconnects from the broker.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	//nolint:errcheck
+	c.writePacket(packetDisconnect, nil)
+	return c.conn.Close()
+}
+
+func (c *Client) writePacket(header byte, body []byte) error {
+	if len(body) > maxRemainingLength {
+		return errors.Errorf("MQTT packet of %d bytes is too large", len(body))
+	}
+	packet := make([]byte, 0, len(body)+5)
+	packet = append(packet, header)
+	packet = appendRemainingLength(packet, len(body))
+	packet = append(packet, body...)
+	_, err := c.conn.Write(packet)
+	return err
+}
+
+func (c *Client) readPacket() (byte, []byte, error) {
+	header, err := c.r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, err := readRemainingLength(c.r)
+	if err != nil {
+		return 0, nil, err
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+func appendRemainingLength(b []byte, length int) []byte {
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		b = append(b, digit)
+		if length == 0 {
+			return b
+		}
+	}
+}
+
+func readRemainingLength(r io.ByteReader) (int, error) {
+	var length, multiplier int = 0, 1
+	for i := 0; i < 4; i++ {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		length += int(digit&0x7F) * multiplier
+		if digit&0x80 == 0 {
+			return length, nil
+		}
+		multiplier *= 128
+	}
+	return 0, errors.New("malformed MQTT remaining length")
+}
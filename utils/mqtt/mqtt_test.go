@@ -0,0 +1,65 @@
+package mqtt_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/utils/mqtt"
+	"go.viam.com/rdk/utils/mqtt/mqtttest"
+)
+
+func TestPublish(t *testing.T) {
+	broker, err := mqtttest.NewBroker()
+	test.That(t, err, test.ShouldBeNil)
+	defer broker.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, err := mqtt.Dial(ctx, broker.Addr(), mqtt.Options{ClientID: "robot", Username: "user", Password: "pass"})
+	test.That(t, err, test.ShouldBeNil)
+	defer client.Close()
+
+	test.That(t, client.Publish(ctx, "alerts/battery", []byte("low"), 1, true), test.ShouldBeNil)
+	msg := <-broker.Messages()
+	test.That(t, msg, test.ShouldResemble, mqtttest.Message{
+		ClientID: "robot",
+		Username: "user",
+		Password: "pass",
+		Topic:    "alerts/battery",
+		Payload:  []byte("low"),
+		QoS:      1,
+		Retain:   true,
+	})
+
+	// large payloads need a multi-byte remaining length
+	large := bytes.Repeat([]byte("x"), 200000)
+	test.That(t, client.Publish(ctx, "bulk", large, 0, false), test.ShouldBeNil)
+	test.That(t, client.Ping(ctx), test.ShouldBeNil)
+	msg = <-broker.Messages()
+	test.That(t, msg.Payload, test.ShouldResemble, large)
+	test.That(t, msg.QoS, test.ShouldEqual, 0)
+
+	test.That(t, client.Publish(ctx, "alerts/#", nil, 0, false), test.ShouldNotBeNil)
+	test.That(t, client.Publish(ctx, "alerts", nil, 2, false), test.ShouldNotBeNil)
+	test.That(t, client.Close(), test.ShouldBeNil)
+	test.That(t, client.Publish(ctx, "alerts", nil, 0, false), test.ShouldBeError, "MQTT client is closed")
+}
+
+func TestDialErrors(t *testing.T) {
+	broker, err := mqtttest.NewBroker()
+	test.That(t, err, test.ShouldBeNil)
+	defer broker.Close()
+	broker.RefuseCode = 5
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = mqtt.Dial(ctx, broker.Addr(), mqtt.Options{})
+	test.That(t, err, test.ShouldBeError, "MQTT broker refused the connection: not authorized")
+
+	_, err = mqtt.Dial(ctx, "ws://localhost:1883", mqtt.Options{})
+	test.That(t, err, test.ShouldBeError, `unsupported MQTT broker scheme "ws"`)
+}
@@ -0,0 +1,216 @@
+// Package mqtttest provides an MQTT broker for testing publishers.
+package mqtttest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// A Message is a message published to a Broker.
+type Message struct {
+	ClientID string
+	Username string
+	Password string
+	Topic    string
+	Payload  []byte
+	QoS      byte
+	Retain   bool
+}
+
+// A Broker accepts MQTT connections on localhost and records the messages published to it. It
+// does not deliver messages to subscribers.
+type Broker struct {
+	listener net.Listener
+	messages chan Message
+	// RefuseCode, if set before connecting, is the CONNACK return code connections are refused with.
+	RefuseCode byte
+
+	wg sync.WaitGroup
+}
+
+// NewBroker starts a broker on a random localhost port.
+func NewBroker() (*Broker, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return nil, err
+	}
+	b := &Broker{listener: listener, messages: make(chan Message, 1024)}
+	b.wg.Add(1)
+	go b.accept()
+	return b, nil
+}
+
+// Addr returns the address of the broker as a tcp:// URL.
+func (b *Broker) Addr() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+// Messages returns the messages published to the broker.
+func (b *Broker) Messages() <-chan Message {
+	return b.messages
+}
+
+// Close stops the broker.
+func (b *Broker) Close() error {
+	err := b.listener.Close()
+	b.wg.Wait()
+	return err
+}
+
+func (b *Broker) accept() {
+	defer b.wg.Done()
+	var conns sync.WaitGroup
+	defer conns.Wait()
+	var mu sync.Mutex
+	var open []net.Conn
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range open {
+			//nolint:errcheck
+			conn.Close()
+		}
+	}()
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		open = append(open, conn)
+		mu.Unlock()
+		conns.Add(1)
+		go func() {
+			defer conns.Done()
+			//nolint:errcheck
+			defer conn.Close()
+			//nolint:errcheck
+			b.serve(conn)
+		}()
+	}
+}
+
+func (b *Broker) serve(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	var clientID, username, password string
+	for {
+		header, body, err := readPacket(r)
+		if err != nil {
+			return err
+		}
+		switch header >> 4 {
+		case 1: // CONNECT
+			if clientID, username, password, err = parseConnect(body); err != nil {
+				return err
+			}
+			if _, err := conn.Write([]byte{2 << 4, 2, 0, b.RefuseCode}); err != nil {
+				return err
+			}
+			if b.RefuseCode != 0 {
+				return nil
+			}
+		case 3: // PUBLISH
+			qos := header >> 1 & 3
+			topic, rest, err := readString(body)
+			if err != nil {
+				return err
+			}
+			if qos > 0 {
+				if len(rest) < 2 {
+					return errors.New("PUBLISH is missing its packet id")
+				}
+				if _, err := conn.Write([]byte{4 << 4, 2, rest[0], rest[1]}); err != nil {
+					return err
+				}
+				rest = rest[2:]
+			}
+			b.messages <- Message{
+				ClientID: clientID,
+				Username: username,
+				Password: password,
+				Topic:    topic,
+				Payload:  append([]byte(nil), rest...),
+				QoS:      qos,
+				Retain:   header&1 != 0,
+			}
+		case 12: // PINGREQ
+			if _, err := conn.Write([]byte{13 << 4, 0}); err != nil {
+				return err
+			}
+		case 14: // DISCONNECT
+			return nil
+		default:
+			return errors.Errorf("unexpected MQTT packet type %d", header>>4)
+		}
+	}
+}
+
+func parseConnect(body []byte) (string, string, string, error) {
+	_, rest, err := readString(body)
+	if err != nil {
+		return "", "", "", err
+	}
+	if len(rest) < 4 {
+		return "", "", "", errors.New("CONNECT is too short")
+	}
+	flags := rest[1]
+	rest = rest[4:]
+	var clientID, username, password string
+	if clientID, rest, err = readString(rest); err != nil {
+		return "", "", "", err
+	}
+	if flags&0x80 != 0 {
+		if username, rest, err = readString(rest); err != nil {
+			return "", "", "", err
+		}
+	}
+	if flags&0x40 != 0 {
+		if password, _, err = readString(rest); err != nil {
+			return "", "", "", err
+		}
+	}
+	return clientID, username, password, nil
+}
+
+func readString(b []byte) (string, []byte, error) {
+	if len(b) < 2 {
+		return "", nil, errors.New("MQTT string is too short")
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if len(b) < 2+n {
+		return "", nil, errors.New("MQTT string is too short")
+	}
+	return string(b[2 : 2+n]), b[2+n:], nil
+}
+
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	var length, multiplier int = 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, errors.New("malformed MQTT remaining length")
+		}
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7F) * multiplier
+		if digit&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
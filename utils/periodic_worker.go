@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/logging"
+)
+
+// PeriodicWorkerConfig configures a PeriodicWorker.
+type PeriodicWorkerConfig struct {
+	// Name identifies the worker in logs.
+	Name string
+	// Interval is how often the work runs. Runs are skipped while the work is still running, as
+	// with a time.Ticker.
+	Interval time.Duration
+	// Jitter delays each run by a random fraction of Interval of up to Jitter, between 0 and 1, such
+	// that workers started together do not run in lockstep.
+	Jitter float64
+	// Clock times the runs. The wall clock is used if it is nil.
+	Clock clock.Clock
+	// Logger logs panics recovered from runs, if it is set.
+	Logger logging.Logger
+}
+
+// PeriodicWorkerStats are the health and latency metrics of a PeriodicWorker. They can be recorded
+// by FTDC.
+type PeriodicWorkerStats struct {
+	// Runs counts the runs of the work, including those that panicked.
+	Runs int64
+	// Panics counts the runs that panicked. Panics are recovered and the worker keeps running.
+	Panics int64
+	// Overruns counts the runs that took longer than the interval.
+	Overruns int64
+	// LastLatencyMs and MaxLatencyMs are how long the last and longest runs took.
+	LastLatencyMs float64
+	MaxLatencyMs  float64
+}
+
+// A PeriodicWorker runs work in the background every interval until it is stopped, recovering from
+// panics in the work and keeping metrics on its runs.
+type PeriodicWorker struct {
+	cfg    PeriodicWorkerConfig
+	work   func(ctx context.Context)
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu    sync.Mutex
+	stats PeriodicWorkerStats
+}
+
+// NewPeriodicWorker starts running work every interval. The first run is an interval after it
+// returns. The context work is called with is canceled when the worker is stopped.
+func NewPeriodicWorker(cfg PeriodicWorkerConfig, work func(ctx context.Context)) *PeriodicWorker {
+	if cfg.Clock == nil {
+		cfg.Clock = clock.New()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &PeriodicWorker{
+		cfg:    cfg,
+		work:   work,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	// The ticker is made before returning, such that tests can advance a mock clock right away.
+	ticker := cfg.Clock.Ticker(cfg.Interval)
+	goutils.PanicCapturingGo(func() {
+		defer close(w.done)
+		defer ticker.Stop()
+		for {
+			if !goutils.SelectContextOrWaitChan(ctx, ticker.C) {
+				return
+			}
+			if cfg.Jitter > 0 {
+				//nolint:gosec
+				delay := time.Duration(rand.Float64() * cfg.Jitter * float64(cfg.Interval))
+				timer := cfg.Clock.Timer(delay)
+				if !goutils.SelectContextOrWaitChan(ctx, timer.C) {
+					timer.Stop()
+					return
+				}
+			}
+			w.run()
+		}
+	})
+	return w
+}
+
+func (w *PeriodicWorker) run() {
+	start := w.cfg.Clock.Now()
+	defer func() {
+		panicked := recover()
+		latency := w.cfg.Clock.Since(start)
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.stats.Runs++
+		if panicked != nil {
+			w.stats.Panics++
+			if w.cfg.Logger != nil {
+				w.cfg.Logger.Errorw("recovered from panic in periodic worker", "worker", w.cfg.Name, "panic", panicked)
+			}
+		}
+		if latency > w.cfg.Interval {
+			w.stats.Overruns++
+		}
+		latencyMs := float64(latency) / float64(time.Millisecond)
+		w.stats.LastLatencyMs = latencyMs
+		if latencyMs > w.stats.MaxLatencyMs {
+			w.stats.MaxLatencyMs = latencyMs
+		}
+	}()
+	w.work(w.ctx)
+}
+
+// Context returns the context work is called with, which is canceled when the worker is stopped.
+func (w *PeriodicWorker) Context() context.Context {
+	return w.ctx
+}
+
+// Stop stops the worker and waits for the run in progress, if any, to return.
+func (w *PeriodicWorker) Stop() {
+	w.cancel()
+	<-w.done
+}
+
+// Stats returns the PeriodicWorkerStats of the worker. It implements the FTDC Statser interface.
+func (w *PeriodicWorker) Stats() any {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stats
+}
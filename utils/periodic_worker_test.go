@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestPeriodicWorker(t *testing.T) {
+	mockClock := clock.NewMock()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var runs atomic.Int64
+	w := NewPeriodicWorker(PeriodicWorkerConfig{
+		Name:     "test",
+		Interval: time.Second,
+		Clock:    mockClock,
+		Logger:   logging.NewTestLogger(t),
+	}, func(ctx context.Context) {
+		switch runs.Add(1) {
+		case 1:
+			close(started)
+			<-release
+		case 2:
+			panic("second run")
+		}
+	})
+
+	mockClock.Add(time.Second)
+	<-started
+	mockClock.Add(2 * time.Second)
+	close(release)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, w.Stats().(PeriodicWorkerStats).Runs, test.ShouldBeGreaterThanOrEqualTo, 1)
+	})
+	stats := w.Stats().(PeriodicWorkerStats)
+	test.That(t, stats.Overruns, test.ShouldEqual, 1)
+	test.That(t, stats.MaxLatencyMs, test.ShouldEqual, 2000)
+
+	// a panicking run is recovered from, and the worker keeps running
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		mockClock.Add(time.Second)
+		test.That(tb, w.Stats().(PeriodicWorkerStats).Runs, test.ShouldBeGreaterThanOrEqualTo, 3)
+	})
+	test.That(t, w.Stats().(PeriodicWorkerStats).Panics, test.ShouldEqual, 1)
+
+	w.Stop()
+	test.That(t, w.Context().Err(), test.ShouldNotBeNil)
+	stopped := runs.Load()
+	mockClock.Add(time.Minute)
+	test.That(t, runs.Load(), test.ShouldEqual, stopped)
+}
+
+func TestPeriodicWorkerJitter(t *testing.T) {
+	mockClock := clock.NewMock()
+	ran := make(chan time.Time, 10)
+	w := NewPeriodicWorker(PeriodicWorkerConfig{
+		Interval: time.Second,
+		Jitter:   0.5,
+		Clock:    mockClock,
+	}, func(ctx context.Context) {
+		ran <- mockClock.Now()
+	})
+	defer w.Stop()
+
+	var at time.Time
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		mockClock.Add(100 * time.Millisecond)
+		test.That(tb, len(ran), test.ShouldBeGreaterThan, 0)
+	})
+	at = <-ran
+	// the first run is delayed by up to half an interval
+	test.That(t, at.Sub(time.Unix(0, 0)), test.ShouldBeGreaterThanOrEqualTo, time.Second)
+	test.That(t, at.Sub(time.Unix(0, 0)), test.ShouldBeLessThanOrEqualTo, 1600*time.Millisecond)
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"math"
+	"time"
+)
+
+// TrapezoidalProfile plans a move over a distance that accelerates, cruises and decelerates
+// within speed and acceleration limits.
+type TrapezoidalProfile struct {
+	distance float64
+	accel    float64
+	// rampTime is the time spent accelerating, and again decelerating.
+	rampTime  time.Duration
+	totalTime time.Duration
+	peakSpeed float64
+}
+
+// NewTrapezoidalProfile plans a move over the absolute value of distance. A maxSpeed or maxAccel
+// of zero means no limit. Without a speed limit the move accelerates through its first half and
+// decelerates through its second. Without an acceleration limit it moves at constant speed.
+// Without either it completes immediately.
+func NewTrapezoidalProfile(distance, maxSpeed, maxAccel float64) *TrapezoidalProfile {
+	dist := math.Abs(distance)
+	p := &TrapezoidalProfile{distance: dist, accel: maxAccel}
+	seconds := func(s float64) time.Duration { return time.Duration(s * float64(time.Second)) }
+	switch {
+	case dist == 0 || (maxSpeed == 0 && maxAccel == 0):
+	case maxAccel == 0:
+		p.peakSpeed = maxSpeed
+		p.totalTime = seconds(dist / maxSpeed)
+	case maxSpeed == 0 || maxSpeed*maxSpeed/maxAccel > dist:
+		// triangular: the speed limit is never reached
+		ramp := math.Sqrt(dist / maxAccel)
+		p.peakSpeed = maxAccel * ramp
+		p.rampTime = seconds(ramp)
+		p.totalTime = 2 * p.rampTime
+	default:
+		ramp := maxSpeed / maxAccel
+		p.peakSpeed = maxSpeed
+		p.rampTime = seconds(ramp)
+		p.totalTime = 2*p.rampTime + seconds((dist-maxSpeed*ramp)/maxSpeed)
+	}
+	return p
+}
+
+// Duration returns how long the move takes.
+func (p *TrapezoidalProfile) Duration() time.Duration {
+	return p.totalTime
+}
+
+// PeakSpeed returns the highest speed reached during the move.
+func (p *TrapezoidalProfile) PeakSpeed() float64 {
+	return p.peakSpeed
+}
+
+// Fraction returns the fraction of the distance covered after elapsed time, between 0 and 1.
+func (p *TrapezoidalProfile) Fraction(elapsed time.Duration) float64 {
+	if elapsed >= p.totalTime {
+		return 1
+	}
+	if elapsed <= 0 {
+		return 0
+	}
+	t := elapsed.Seconds()
+	ramp := p.rampTime.Seconds()
+	total := p.totalTime.Seconds()
+	var covered float64
+	switch {
+	case p.rampTime == 0:
+		covered = p.peakSpeed * t
+	case t < ramp:
+		covered = 0.5 * p.accel * t * t
+	case t < total-ramp:
+		covered = 0.5*p.accel*ramp*ramp + p.peakSpeed*(t-ramp)
+	default:
+		remaining := total - t
+		covered = p.distance - 0.5*p.accel*remaining*remaining
+	}
+	return covered / p.distance
+}
+
+// Speed returns the speed of the move after elapsed time.
+func (p *TrapezoidalProfile) Speed(elapsed time.Duration) float64 {
+	if elapsed <= 0 || elapsed >= p.totalTime {
+		return 0
+	}
+	t := elapsed.Seconds()
+	ramp := p.rampTime.Seconds()
+	total := p.totalTime.Seconds()
+	switch {
+	case p.rampTime == 0, t >= ramp && t < total-ramp:
+		return p.peakSpeed
+	case t < ramp:
+		return p.accel * t
+	default:
+		return p.accel * (total - t)
+	}
+}
@@ -0,0 +1,145 @@
+package classification
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// The range of temperatures FitTemperature searches.
+const (
+	minTemperature = 0.05
+	maxTemperature = 20.
+)
+
+// CalibrationSample is the output of a classifier for an image whose true label is known, used to
+// calibrate the scores of the classifier.
+type CalibrationSample struct {
+	// Scores are the scores of the classifier for each label.
+	Scores map[string]float64 `json:"scores"`
+	// Label is the true label of the image. For a binary classifier, which has the score of a single
+	// label, any other label means the image is not of that label.
+	Label string `json:"label"`
+}
+
+// NewTemperatureScaler returns a function that calibrates the scores of classifications with
+// temperature scaling, so that they are usable as probabilities. Temperatures above 1 soften
+// overconfident scores and temperatures below 1 sharpen underconfident ones. The scores of a
+// classifier with several labels are assumed to be a softmax over all of them, and a single score
+// to be the sigmoid of a binary classifier.
+func NewTemperatureScaler(temperature float64) Postprocessor {
+	return func(in Classifications) Classifications {
+		if len(in) == 0 || temperature == 1 {
+			return in
+		}
+		scores := make([]float64, len(in))
+		for i, c := range in {
+			scores[i] = c.Score()
+		}
+		scores = scaleScores(scores, 1/temperature)
+		out := make(Classifications, 0, len(in))
+		for i, c := range in {
+			out = append(out, NewClassification(scores[i], c.Label()))
+		}
+		return out
+	}
+}
+
+// FitTemperature returns the temperature that calibrates the scores of the samples best, as the one
+// minimizing their negative log likelihood.
+func FitTemperature(samples []CalibrationSample) (float64, error) {
+	if len(samples) == 0 {
+		return 0, errors.New("cannot calibrate with no samples")
+	}
+	type sample struct {
+		scores []float64
+		// truth is the index of the true label, or -1 if a binary sample is not of its label
+		truth int
+	}
+	prepared := make([]sample, 0, len(samples))
+	for i, s := range samples {
+		if len(s.Scores) == 0 {
+			return 0, errors.Errorf("calibration sample %d has no scores", i)
+		}
+		prepared = append(prepared, sample{truth: -1})
+		p := &prepared[len(prepared)-1]
+		for label, score := range s.Scores {
+			if score < 0 || score > 1 {
+				return 0, errors.Errorf("calibration sample %d has score %v for %q, scores must be between 0 and 1", i, score, label)
+			}
+			if label == s.Label {
+				p.truth = len(p.scores)
+			}
+			p.scores = append(p.scores, score)
+		}
+		if p.truth < 0 && len(p.scores) > 1 {
+			return 0, errors.Errorf("calibration sample %d has no score for its label %q", i, s.Label)
+		}
+	}
+
+	nll := func(logTemperature float64) float64 {
+		invTemperature := math.Exp(-logTemperature)
+		var total float64
+		for _, s := range prepared {
+			scores := scaleScores(s.scores, invTemperature)
+			var p float64
+			if s.truth < 0 {
+				p = 1 - scores[0]
+			} else {
+				p = scores[s.truth]
+			}
+			total -= math.Log(math.Max(p, 1e-12))
+		}
+		return total
+	}
+
+	// the negative log likelihood is convex in the log of the temperature, so a golden section search
+	// finds its minimum
+	invPhi := (math.Sqrt(5) - 1) / 2
+	lo, hi := math.Log(minTemperature), math.Log(maxTemperature)
+	a, b := hi-invPhi*(hi-lo), lo+invPhi*(hi-lo)
+	fa, fb := nll(a), nll(b)
+	for hi-lo > 1e-6 {
+		if fa < fb {
+			hi, b, fb = b, a, fa
+			a = hi - invPhi*(hi-lo)
+			fa = nll(a)
+		} else {
+			lo, a, fa = a, b, fb
+			b = lo + invPhi*(hi-lo)
+			fb = nll(b)
+		}
+	}
+	return math.Exp((lo + hi) / 2), nil
+}
+
+// scaleScores divides the logits of scores by the temperature, given its inverse.
+func scaleScores(scores []float64, invTemperature float64) []float64 {
+	out := make([]float64, len(scores))
+	if len(scores) == 1 {
+		// the logit of a binary classifier is log(p / (1 - p))
+		pos, neg := math.Pow(scores[0], invTemperature), math.Pow(1-scores[0], invTemperature)
+		out[0] = pos / (pos + neg)
+		return out
+	}
+	// softmax scores are the exponentials of their logits, up to a constant, so the scaled scores are
+	// the softmax of their scaled logs
+	maxLog := math.Inf(-1)
+	for i, score := range scores {
+		out[i] = invTemperature * math.Log(score)
+		maxLog = math.Max(maxLog, out[i])
+	}
+	if math.IsInf(maxLog, -1) {
+		// all the scores are 0
+		return append(out[:0], scores...)
+	}
+	var sum float64
+	for i := range out {
+		out[i] = math.Exp(out[i] - maxLog)
+		sum += out[i]
+	}
+	for i := range out {
+		out[i] /= sum
+	}
+	return out
+}
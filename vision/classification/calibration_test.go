@@ -0,0 +1,69 @@
+package classification
+
+import (
+	"math"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestTemperatureScaler(t *testing.T) {
+	in := Classifications{NewClassification(0.8, "cat"), NewClassification(0.2, "dog")}
+	test.That(t, NewTemperatureScaler(1)(in), test.ShouldResemble, in)
+
+	// a temperature of 2 takes the square root of the odds
+	out := NewTemperatureScaler(2)(in)
+	test.That(t, out[0].Label(), test.ShouldEqual, "cat")
+	test.That(t, out[0].Score(), test.ShouldAlmostEqual, 2./3)
+	test.That(t, out[1].Score(), test.ShouldAlmostEqual, 1./3)
+	test.That(t, in[0].Score(), test.ShouldEqual, 0.8)
+
+	out = NewTemperatureScaler(0.5)(in)
+	test.That(t, out[0].Score(), test.ShouldAlmostEqual, 16./17)
+
+	// a single score is the sigmoid of a binary classifier
+	out = NewTemperatureScaler(2)(Classifications{NewClassification(0.9, "cat")})
+	test.That(t, out[0].Score(), test.ShouldAlmostEqual, 0.75)
+
+	out = NewTemperatureScaler(2)(Classifications{NewClassification(0, "cat"), NewClassification(0, "dog")})
+	test.That(t, out[0].Score(), test.ShouldEqual, 0)
+	test.That(t, NewTemperatureScaler(2)(Classifications{}), test.ShouldBeEmpty)
+}
+
+func TestFitTemperature(t *testing.T) {
+	_, err := FitTemperature(nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = FitTemperature([]CalibrationSample{{Scores: map[string]float64{"cat": 0.5, "dog": 0.5}, Label: "bird"}})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `no score for its label "bird"`)
+	_, err = FitTemperature([]CalibrationSample{{Scores: map[string]float64{"cat": 2}, Label: "cat"}})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// an overconfident classifier: it is right 3 times out of 4 when it gives a score of 0.9
+	overconfident := []CalibrationSample{}
+	for i := 0; i < 4; i++ {
+		label := "cat"
+		if i == 3 {
+			label = "dog"
+		}
+		overconfident = append(overconfident, CalibrationSample{Scores: map[string]float64{"cat": 0.9, "dog": 0.1}, Label: label})
+	}
+	temperature, err := FitTemperature(overconfident)
+	test.That(t, err, test.ShouldBeNil)
+	// scaling the odds of 9 to the odds of 3 takes a temperature of log(9) / log(3) = 2
+	test.That(t, temperature, test.ShouldAlmostEqual, 2, 1e-3)
+	calibrated := NewTemperatureScaler(temperature)(Classifications{NewClassification(0.9, "cat"), NewClassification(0.1, "dog")})
+	test.That(t, calibrated[0].Score(), test.ShouldAlmostEqual, 0.75, 1e-3)
+
+	// binary samples of another label are negatives
+	binary := []CalibrationSample{
+		{Scores: map[string]float64{"cat": 0.6}, Label: "cat"},
+		{Scores: map[string]float64{"cat": 0.6}, Label: "cat"},
+		{Scores: map[string]float64{"cat": 0.6}, Label: "cat"},
+		{Scores: map[string]float64{"cat": 0.6}, Label: "not cat"},
+	}
+	temperature, err = FitTemperature(binary)
+	test.That(t, err, test.ShouldBeNil)
+	// the odds of 1.5 are scaled to the odds of 3
+	test.That(t, temperature, test.ShouldAlmostEqual, math.Log(1.5)/math.Log(3), 1e-3)
+}
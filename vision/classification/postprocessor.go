@@ -59,3 +59,27 @@ func NewLabelConfidenceFilter(labels map[string]float64) Postprocessor {
 		return out
 	}
 }
+
+// NewLabelThresholdFilter returns a function that filters out classifications below the confidence
+// threshold of their label. Unlike NewLabelConfidenceFilter, classifications of labels without a
+// threshold are kept if they are above defaultConf.
+func NewLabelThresholdFilter(thresholds map[string]float64, defaultConf float64) Postprocessor {
+	// ensure all the label names are lower case
+	theThresholds := make(map[string]float64, len(thresholds))
+	for name, conf := range thresholds {
+		theThresholds[strings.ToLower(name)] = conf
+	}
+	return func(in Classifications) Classifications {
+		out := make(Classifications, 0, len(in))
+		for _, c := range in {
+			conf, ok := theThresholds[strings.ToLower(c.Label())]
+			if !ok {
+				conf = defaultConf
+			}
+			if c.Score() >= conf {
+				out = append(out, c)
+			}
+		}
+		return out
+	}
+}
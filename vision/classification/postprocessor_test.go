@@ -31,3 +31,23 @@ func TestLabelConfidencePostprocessor(t *testing.T) {
 	test.That(t, labelList, test.ShouldContain, "A")
 	test.That(t, labelList, test.ShouldContain, "b")
 }
+
+func TestLabelThresholdPostprocessor(t *testing.T) {
+	d := []Classification{
+		NewClassification(0.5, "A"),
+		NewClassification(0.1, "B"),
+		NewClassification(0.6, "C"),
+		NewClassification(0.3, "D"),
+	}
+
+	results := NewLabelThresholdFilter(nil, 0)(d)
+	test.That(t, results, test.ShouldResemble, Classifications(d))
+
+	// labels without a threshold are kept above the default confidence
+	results = NewLabelThresholdFilter(map[string]float64{"a": 0.6, "B": 0.05}, 0.4)(d)
+	labels := make([]string, 0, len(results))
+	for _, c := range results {
+		labels = append(labels, c.Label())
+	}
+	test.That(t, labels, test.ShouldResemble, []string{"B", "C"})
+}
@@ -0,0 +1,263 @@
+package objectdetection
+
+import (
+	"image"
+	"math"
+	"sort"
+	"time"
+)
+
+// TrackerConfig describes how a Tracker associates detections across frames.
+type TrackerConfig struct {
+	// IoUThreshold is the least overlap, as intersection over union, that a detection must have
+	// with a track's predicted bounding box to continue the track.
+	IoUThreshold float64
+	// MaxMisses is how many frames in a row a track may go undetected before it is dropped.
+	MaxMisses int
+	// MinHits is how many frames a track must be detected in before it is reported.
+	MinHits int
+	// MatchLabels only continues tracks with detections of the same label.
+	MatchLabels bool
+}
+
+// DefaultTrackerConfig is a TrackerConfig that suits detectors running at a few frames per second.
+var DefaultTrackerConfig = TrackerConfig{
+	IoUThreshold: 0.3,
+	MaxMisses:    5,
+	MinHits:      2,
+	MatchLabels:  true,
+}
+
+// Track is an object followed across frames.
+type Track struct {
+	// ID identifies the object for as long as it is tracked.
+	ID int64
+	// Detection is the object's latest detection. While the object goes undetected, its bounding
+	// box is where the object is predicted to be.
+	Detection Detection
+	// VelocityX and VelocityY are the velocity of the center of the object's bounding box, in pixels
+	// per second.
+	VelocityX, VelocityY float64
+	// Hits is how many frames the object was detected in.
+	Hits int
+	// Misses is how many frames in a row the object has gone undetected.
+	Misses    int
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// Tracker assigns persistent IDs to the detections of consecutive frames, following SORT: each
+// track's bounding box is predicted with a constant velocity Kalman filter, and detections continue
+// the tracks whose predicted boxes they overlap most. A Tracker is not safe for concurrent use.
+type Tracker struct {
+	conf     TrackerConfig
+	nextID   int64
+	tracks   []*trackState
+	lastTime time.Time
+}
+
+type trackState struct {
+	id                  int64
+	cx, cy, w, h        kalman1D
+	label               string
+	score               float64
+	imageBounds         image.Rectangle
+	hits, misses        int
+	firstSeen, lastSeen time.Time
+}
+
+// NewTracker returns a tracker with no tracks.
+func NewTracker(conf TrackerConfig) *Tracker {
+	return &Tracker{conf: conf, nextID: 1}
+}
+
+// Update continues the tracks with the detections of a frame taken at `t`, starting new tracks for
+// detections that continue none, and returns the reported tracks ordered by ID. `imageBounds` is
+// used to normalize the bounding boxes of the returned detections, and may be empty.
+func (tr *Tracker) Update(detections []Detection, imageBounds image.Rectangle, t time.Time) []Track {
+	dt := 0.
+	if !tr.lastTime.IsZero() {
+		dt = t.Sub(tr.lastTime).Seconds()
+	}
+	tr.lastTime = t
+	for _, track := range tr.tracks {
+		track.predict(dt)
+	}
+
+	// greedily match the most overlapping track and detection pairs
+	type pair struct {
+		track, detection int
+		iou              float64
+	}
+	var pairs []pair
+	for i, track := range tr.tracks {
+		predicted := track.box()
+		for j, det := range detections {
+			if tr.conf.MatchLabels && det.Label() != track.label {
+				continue
+			}
+			if iou := IoU(predicted, *det.BoundingBox()); iou >= tr.conf.IoUThreshold && iou > 0 {
+				pairs = append(pairs, pair{i, j, iou})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool { return pairs[a].iou > pairs[b].iou })
+	trackMatched := make([]bool, len(tr.tracks))
+	detectionMatched := make([]bool, len(detections))
+	for _, p := range pairs {
+		if trackMatched[p.track] || detectionMatched[p.detection] {
+			continue
+		}
+		trackMatched[p.track], detectionMatched[p.detection] = true, true
+		tr.tracks[p.track].correct(detections[p.detection], imageBounds, t)
+	}
+
+	live := tr.tracks[:0]
+	for i, track := range tr.tracks {
+		if !trackMatched[i] {
+			track.misses++
+			if track.misses > tr.conf.MaxMisses {
+				continue
+			}
+		}
+		live = append(live, track)
+	}
+	tr.tracks = live
+	for j, det := range detections {
+		if !detectionMatched[j] {
+			tr.tracks = append(tr.tracks, newTrackState(tr.nextID, det, imageBounds, t))
+			tr.nextID++
+		}
+	}
+
+	var tracks []Track
+	for _, track := range tr.tracks {
+		if track.hits >= tr.conf.MinHits {
+			tracks = append(tracks, track.toTrack())
+		}
+	}
+	return tracks
+}
+
+func newTrackState(id int64, det Detection, imageBounds image.Rectangle, t time.Time) *trackState {
+	box := det.BoundingBox()
+	return &trackState{
+		id:          id,
+		cx:          newKalman1D(float64(box.Min.X+box.Max.X) / 2),
+		cy:          newKalman1D(float64(box.Min.Y+box.Max.Y) / 2),
+		w:           newKalman1D(float64(box.Dx())),
+		h:           newKalman1D(float64(box.Dy())),
+		label:       det.Label(),
+		score:       det.Score(),
+		imageBounds: imageBounds,
+		hits:        1,
+		firstSeen:   t,
+		lastSeen:    t,
+	}
+}
+
+func (ts *trackState) predict(dt float64) {
+	for _, k := range []*kalman1D{&ts.cx, &ts.cy, &ts.w, &ts.h} {
+		k.predict(dt)
+	}
+	// boxes can't shrink past nothing
+	ts.w.pos = math.Max(ts.w.pos, 1)
+	ts.h.pos = math.Max(ts.h.pos, 1)
+}
+
+func (ts *trackState) correct(det Detection, imageBounds image.Rectangle, t time.Time) {
+	box := det.BoundingBox()
+	ts.cx.correct(float64(box.Min.X+box.Max.X) / 2)
+	ts.cy.correct(float64(box.Min.Y+box.Max.Y) / 2)
+	ts.w.correct(float64(box.Dx()))
+	ts.h.correct(float64(box.Dy()))
+	ts.label = det.Label()
+	ts.score = det.Score()
+	ts.imageBounds = imageBounds
+	ts.hits++
+	ts.misses = 0
+	ts.lastSeen = t
+}
+
+func (ts *trackState) box() image.Rectangle {
+	return image.Rect(
+		int(math.Round(ts.cx.pos-ts.w.pos/2)),
+		int(math.Round(ts.cy.pos-ts.h.pos/2)),
+		int(math.Round(ts.cx.pos+ts.w.pos/2)),
+		int(math.Round(ts.cy.pos+ts.h.pos/2)),
+	)
+}
+
+func (ts *trackState) toTrack() Track {
+	var det Detection
+	if ts.imageBounds.Empty() {
+		det = NewDetectionWithoutImgBounds(ts.box(), ts.score, ts.label)
+	} else {
+		det = NewDetection(ts.imageBounds, ts.box(), ts.score, ts.label)
+	}
+	return Track{
+		ID:        ts.id,
+		Detection: det,
+		VelocityX: ts.cx.vel,
+		VelocityY: ts.cy.vel,
+		Hits:      ts.hits,
+		Misses:    ts.misses,
+		FirstSeen: ts.firstSeen,
+		LastSeen:  ts.lastSeen,
+	}
+}
+
+// IoU returns the intersection over union of two rectangles.
+func IoU(a, b image.Rectangle) float64 {
+	intersection := a.Intersect(b)
+	if intersection.Empty() {
+		return 0
+	}
+	i := float64(intersection.Dx() * intersection.Dy())
+	return i / (float64(a.Dx()*a.Dy()+b.Dx()*b.Dy()) - i)
+}
+
+// The noise of the tracker's Kalman filters. Process noise is how much the velocity of a box may
+// change, in pixels per second squared, and measurement noise is how far from the truth detected
+// boxes are, in pixels.
+const (
+	trackProcessNoise     = 200.
+	trackMeasurementNoise = 5.
+)
+
+// kalman1D is a constant velocity Kalman filter of a single coordinate.
+type kalman1D struct {
+	pos, vel float64
+	cov      [2][2]float64
+}
+
+func newKalman1D(pos float64) kalman1D {
+	// the initial velocity is unknown
+	return kalman1D{pos: pos, cov: [2][2]float64{{trackMeasurementNoise * trackMeasurementNoise, 0}, {0, 1e4}}}
+}
+
+func (k *kalman1D) predict(dt float64) {
+	if dt <= 0 {
+		return
+	}
+	k.pos += k.vel * dt
+	p := k.cov
+	q := trackProcessNoise * trackProcessNoise
+	k.cov = [2][2]float64{
+		{p[0][0] + dt*(p[1][0]+p[0][1]) + dt*dt*p[1][1] + q*dt*dt*dt*dt/4, p[0][1] + dt*p[1][1] + q*dt*dt*dt/2},
+		{p[1][0] + dt*p[1][1] + q*dt*dt*dt/2, p[1][1] + q*dt*dt},
+	}
+}
+
+func (k *kalman1D) correct(measured float64) {
+	p := k.cov
+	s := p[0][0] + trackMeasurementNoise*trackMeasurementNoise
+	gainPos, gainVel := p[0][0]/s, p[1][0]/s
+	residual := measured - k.pos
+	k.pos += gainPos * residual
+	k.vel += gainVel * residual
+	k.cov = [2][2]float64{
+		{(1 - gainPos) * p[0][0], (1 - gainPos) * p[0][1]},
+		{p[1][0] - gainVel*p[0][0], p[1][1] - gainVel*p[0][1]},
+	}
+}
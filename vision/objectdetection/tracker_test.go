@@ -0,0 +1,127 @@
+package objectdetection
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestIoU(t *testing.T) {
+	test.That(t, IoU(image.Rect(0, 0, 10, 10), image.Rect(0, 0, 10, 10)), test.ShouldEqual, 1)
+	test.That(t, IoU(image.Rect(0, 0, 10, 10), image.Rect(5, 0, 15, 10)), test.ShouldAlmostEqual, 50./150)
+	test.That(t, IoU(image.Rect(0, 0, 10, 10), image.Rect(20, 20, 30, 30)), test.ShouldEqual, 0)
+}
+
+func TestTracker(t *testing.T) {
+	bounds := image.Rect(0, 0, 640, 480)
+	tracker := NewTracker(DefaultTrackerConfig)
+	start := time.Now()
+	frame := func(i int) time.Time { return start.Add(time.Duration(i) * 100 * time.Millisecond) }
+
+	// two people walk in opposite directions, 20 pixels a frame, and a dog stays still
+	detectionsAt := func(i int) []Detection {
+		return []Detection{
+			NewDetection(bounds, image.Rect(100+20*i, 100, 150+20*i, 200), 0.9, "person"),
+			NewDetection(bounds, image.Rect(500-20*i, 120, 550-20*i, 220), 0.8, "person"),
+			NewDetection(bounds, image.Rect(300, 400, 340, 440), 0.7, "dog"),
+		}
+	}
+
+	// tracks aren't reported until they are seen twice
+	tracks := tracker.Update(detectionsAt(0), bounds, frame(0))
+	test.That(t, tracks, test.ShouldBeEmpty)
+
+	ids := map[string]int64{}
+	for i := 1; i < 8; i++ {
+		tracks = tracker.Update(detectionsAt(i), bounds, frame(i))
+		test.That(t, len(tracks), test.ShouldEqual, 3)
+		for _, track := range tracks {
+			test.That(t, track.Misses, test.ShouldEqual, 0)
+			test.That(t, track.Hits, test.ShouldEqual, i+1)
+			key := track.Detection.Label()
+			if key == "person" {
+				key += map[bool]string{true: "-right", false: "-left"}[track.VelocityX > 0]
+			}
+			if id, ok := ids[key]; ok {
+				test.That(t, track.ID, test.ShouldEqual, id)
+			} else {
+				ids[key] = track.ID
+			}
+		}
+	}
+	// the IDs are distinct and the velocities are learned
+	test.That(t, len(ids), test.ShouldEqual, 3)
+	for _, track := range tracks {
+		switch track.ID {
+		case ids["person-right"]:
+			test.That(t, track.VelocityX, test.ShouldAlmostEqual, 200, 20)
+		case ids["person-left"]:
+			test.That(t, track.VelocityX, test.ShouldAlmostEqual, -200, 20)
+		case ids["dog"]:
+			test.That(t, track.VelocityX, test.ShouldAlmostEqual, 0, 1)
+		}
+		test.That(t, track.FirstSeen, test.ShouldEqual, frame(0))
+		test.That(t, track.LastSeen, test.ShouldEqual, frame(7))
+		test.That(t, len(track.Detection.NormalizedBoundingBox()), test.ShouldEqual, 4)
+	}
+
+	// the right-walking person is missed for two frames, and is predicted to keep walking
+	for i := 8; i < 10; i++ {
+		tracks = tracker.Update(detectionsAt(i)[1:], bounds, frame(i))
+		test.That(t, len(tracks), test.ShouldEqual, 3)
+	}
+	for _, track := range tracks {
+		if track.ID == ids["person-right"] {
+			test.That(t, track.Misses, test.ShouldEqual, 2)
+			test.That(t, track.Detection.BoundingBox().Min.X, test.ShouldAlmostEqual, 100+20*9, 10)
+		}
+	}
+	// so it is found again where it walked to
+	tracks = tracker.Update(detectionsAt(10), bounds, frame(10))
+	for _, track := range tracks {
+		if track.Detection.Label() == "person" && track.VelocityX > 0 {
+			test.That(t, track.ID, test.ShouldEqual, ids["person-right"])
+			test.That(t, track.Misses, test.ShouldEqual, 0)
+		}
+	}
+
+	// objects that are gone for too long are dropped, and ones that reappear get new IDs
+	for i := 11; i < 11+DefaultTrackerConfig.MaxMisses+1; i++ {
+		tracks = tracker.Update(detectionsAt(i)[2:], bounds, frame(i))
+	}
+	test.That(t, len(tracks), test.ShouldEqual, 1)
+	test.That(t, tracks[0].ID, test.ShouldEqual, ids["dog"])
+	tracker.Update(detectionsAt(0)[:1], bounds, frame(20))
+	tracks = tracker.Update(detectionsAt(0)[:1], bounds, frame(21))
+	test.That(t, len(tracks), test.ShouldEqual, 2)
+	test.That(t, tracks[0].ID, test.ShouldEqual, ids["dog"])
+	test.That(t, tracks[0].Misses, test.ShouldEqual, 2)
+	test.That(t, tracks[1].Detection.Label(), test.ShouldEqual, "person")
+	test.That(t, tracks[1].ID, test.ShouldBeGreaterThan, ids["dog"])
+	test.That(t, tracks[1].ID, test.ShouldBeGreaterThan, ids["person-right"])
+	test.That(t, tracks[1].ID, test.ShouldBeGreaterThan, ids["person-left"])
+}
+
+func TestTrackerMatchLabels(t *testing.T) {
+	box := image.Rect(0, 0, 50, 50)
+	conf := DefaultTrackerConfig
+	conf.MinHits = 1
+	tracker := NewTracker(conf)
+	now := time.Now()
+	first := tracker.Update([]Detection{NewDetectionWithoutImgBounds(box, 0.9, "cat")}, image.Rectangle{}, now)
+	second := tracker.Update([]Detection{NewDetectionWithoutImgBounds(box, 0.9, "dog")}, image.Rectangle{}, now.Add(time.Second))
+	test.That(t, len(first), test.ShouldEqual, 1)
+	test.That(t, len(second), test.ShouldEqual, 2)
+	test.That(t, second[1].ID, test.ShouldNotEqual, first[0].ID)
+	test.That(t, second[1].Detection.NormalizedBoundingBox(), test.ShouldBeNil)
+
+	conf.MatchLabels = false
+	tracker = NewTracker(conf)
+	first = tracker.Update([]Detection{NewDetectionWithoutImgBounds(box, 0.9, "cat")}, image.Rectangle{}, now)
+	second = tracker.Update([]Detection{NewDetectionWithoutImgBounds(box, 0.9, "dog")}, image.Rectangle{}, now.Add(time.Second))
+	test.That(t, len(second), test.ShouldEqual, 1)
+	test.That(t, second[0].ID, test.ShouldEqual, first[0].ID)
+	test.That(t, second[0].Detection.Label(), test.ShouldEqual, "dog")
+}
@@ -31,6 +31,7 @@ import (
 	robotimpl "go.viam.com/rdk/robot/impl"
 	"go.viam.com/rdk/robot/web"
 	weboptions "go.viam.com/rdk/robot/web/options"
+	"go.viam.com/rdk/tracing"
 	rutils "go.viam.com/rdk/utils"
 )
 
@@ -52,7 +53,14 @@ type Arguments struct {
 	DisableMulticastDNS        bool   `flag:"disable-mdns,usage=disable server discovery through multicast DNS"`
 	DumpResourcesPath          string `flag:"dump-resources,usage=dump all resource registrations as json to the provided file path"`
 	EnableFTDC                 bool   `flag:"ftdc,default=true,usage=enable fulltime data capture for diagnostics"`
+	FTDCPrometheusURL          string `flag:"ftdc-prometheus-url,usage=push fulltime data capture to a prometheus remote-write endpoint"`
+	RTSPAddress                string `flag:"rtsp-address,usage=serve every camera as an unauthenticated RTSP stream at this address"`
+	QUICAddress                string `flag:"quic-address,usage=also serve gRPC over QUIC at this UDP address; requires a TLS certificate"`
 	OutputLogFile              string `flag:"log-file,usage=write logs to a file with log rotation"`
+	LogDir                     string `flag:"log-dir,usage=also write logs to a file per resource and per module in a directory"`
+	LogMaxSizeMB               int    `flag:"log-max-size-mb,default=100,usage=rotate the files of log-dir at this size"`
+	LogMaxAgeHours             int    `flag:"log-max-age-hours,default=24,usage=rotate the files of log-dir after this many hours"`
+	LogMaxBackups              int    `flag:"log-max-backups,default=5,usage=rotated files of log-dir kept per file"`
 }
 
 type robotServer struct {
@@ -60,6 +68,12 @@ type robotServer struct {
 	logger   logging.Logger
 	registry *logging.Registry
 	conn     rpc.ClientConn
+	// recentLogs keeps the most recent logs for diagnostics bundles.
+	recentLogs *logging.RecentLogsAppender
+	// logSinks sends logs to the log sinks of the config.
+	logSinks *logging.Sinks
+	// logFormat is the format of the logs written to stdout, the log file and the log directory.
+	logFormat *logging.LogFormatSwitch
 }
 
 func logViamEnvVariables(logger logging.Logger) {
@@ -127,15 +141,38 @@ func RunServer(ctx context.Context, args []string, _ logging.Logger) (err error)
 	// expect `InitLoggingSettings` will always put the logger into the right state without any
 	// observable side-effects.
 	logger.SetLevel(logging.INFO)
+	// The format of the logs is set by the machine config once it's read.
+	logFormat := logging.NewLogFormatSwitch()
 	if argsParsed.OutputLogFile != "" {
-		logWriter, closer := logging.NewFileAppender(argsParsed.OutputLogFile)
+		logFile := logging.NewFileWriter(argsParsed.OutputLogFile)
 		defer func() {
-			utils.UncheckedError(closer.Close())
+			utils.UncheckedError(logFile.Close())
 		}()
-		logger.AddAppender(logWriter)
+		logger.AddAppender(logFormat.NewAppender(logFile))
 	} else {
-		logger.AddAppender(logging.NewStdoutAppender())
+		logger.AddAppender(logFormat.NewAppender(os.Stdout))
+	}
+	recentLogs := logging.NewRecentLogsAppender(logging.DefaultRecentLogsCapacity)
+	logger.AddAppender(recentLogs)
+	if argsParsed.LogDir != "" {
+		logFiles, err := logging.NewRoutedFilesAppender(argsParsed.LogDir, logging.FileRotation{
+			MaxSizeMB:  argsParsed.LogMaxSizeMB,
+			MaxAge:     time.Duration(argsParsed.LogMaxAgeHours) * time.Hour,
+			MaxBackups: argsParsed.LogMaxBackups,
+		}, robotimpl.LogFilename, logFormat)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			utils.UncheckedError(logFiles.Close())
+		}()
+		logger.AddAppender(logFiles)
 	}
+	logSinks := logging.NewSinks()
+	defer func() {
+		utils.UncheckedError(logSinks.Close())
+	}()
+	logger.AddAppender(logSinks)
 
 	logging.RegisterEventLogger(logger)
 	logging.ReplaceGlobal(logger)
@@ -192,6 +229,14 @@ func RunServer(ctx context.Context, args []string, _ logging.Logger) (err error)
 		defer exporter.Stop()
 	}
 
+	stopTracing, err := tracing.Start(ctx, "viam-server")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		utils.UncheckedError(stopTracing(context.Background()))
+	}()
+
 	// the underlying connection in `appConn` can be nil. In this case, a background Goroutine is kicked off to reattempt dials in a
 	// serialized manner
 	if cfgFromDisk.Cloud != nil {
@@ -227,10 +272,13 @@ func RunServer(ctx context.Context, args []string, _ logging.Logger) (err error)
 	startupInfoLogged = true
 
 	server := robotServer{
-		logger:   logger,
-		args:     argsParsed,
-		registry: registry,
-		conn:     appConn,
+		logger:     logger,
+		args:       argsParsed,
+		registry:   registry,
+		conn:       appConn,
+		recentLogs: recentLogs,
+		logSinks:   logSinks,
+		logFormat:  logFormat,
 	}
 
 	// Run the server with remote logging enabled.
@@ -272,6 +320,8 @@ func (s *robotServer) createWebOptions(cfg *config.Config) (weboptions.Options,
 	options.Debug = s.args.Debug || cfg.Debug
 	options.PreferWebRTC = s.args.WebRTC
 	options.DisableMulticastDNS = s.args.DisableMulticastDNS
+	options.RTSPAddress = s.args.RTSPAddress
+	options.QUICAddress = s.args.QUICAddress
 	if cfg.Cloud != nil && s.args.AllowInsecureCreds {
 		options.SignalingDialOpts = append(options.SignalingDialOpts, rpc.WithAllowInsecureWithCredentialsDowngrade())
 	}
@@ -361,6 +411,7 @@ func (s *robotServer) configWatcher(ctx context.Context, currCfg *config.Config,
 			if !diff.LogEqual {
 				s.logger.Debug("Detected potential changes to log patterns; updating logger levels")
 				config.UpdateLoggerRegistryFromConfig(s.registry, processedConfig, s.logger)
+				s.updateLogOutputs(processedConfig)
 			}
 
 			r.Reconfigure(ctx, processedConfig)
@@ -470,6 +521,7 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 	//
 	// This functionality is tested in `TestLogPropagation` in `local_robot_test.go`.
 	config.UpdateLoggerRegistryFromConfig(s.registry, fullProcessedConfig, s.logger)
+	s.updateLogOutputs(fullProcessedConfig)
 
 	if fullProcessedConfig.Cloud != nil {
 		cloudRestartCheckerActive = make(chan struct{})
@@ -503,6 +555,8 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 		robotOptions = append(robotOptions, robotimpl.WithRevealSensitiveConfigDiffs())
 	}
 
+	robotOptions = append(robotOptions, robotimpl.WithRecentLogs(s.recentLogs))
+
 	shutdownCallbackOpt := robotimpl.WithShutdownCallback(func() {
 		logStackTraceAndCancel(cancel, s.logger)
 	})
@@ -510,6 +564,9 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 
 	if s.args.EnableFTDC {
 		robotOptions = append(robotOptions, robotimpl.WithFTDC())
+		if s.args.FTDCPrometheusURL != "" {
+			robotOptions = append(robotOptions, robotimpl.WithFTDCPrometheus(s.args.FTDCPrometheusURL))
+		}
 	}
 
 	// Create `minimalProcessedConfig`, a copy of `fullProcessedConfig`. Remove
@@ -633,3 +690,14 @@ func logStackTraceAndCancel(cancel context.CancelFunc, logger logging.Logger) {
 	logger.Infof("%s, %s", message, traces[:traceSize])
 	cancel()
 }
+
+// updateLogOutputs applies the log format and log sinks of the config. Sinks which fail to be
+// created are logged and left out.
+func (s *robotServer) updateLogOutputs(cfg *config.Config) {
+	if err := s.logFormat.Set(cfg.LogFormat); err != nil {
+		s.logger.Errorw("invalid log format; keeping the current format", "error", err)
+	}
+	if err := s.logSinks.Update(cfg.LogSinks); err != nil {
+		s.logger.Errorw("error creating log sinks", "error", err)
+	}
+}